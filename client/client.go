@@ -0,0 +1,146 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package client is the Go client of the Gogs API v1.
+//
+// Types and methods of endpoints are generated from the OpenAPI document
+// "openapi.yaml" in this directory, run `go generate ./client` from the root of
+// the repository after making changes to the document.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//go:generate go run ./internal/gen openapi.yaml generated.go
+
+// Client is a client of the Gogs API v1.
+type Client struct {
+	url        string // The external URL of the Gogs instance
+	baseURL    string // The base URL of API v1
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient returns a new client for the Gogs instance at the given URL (e.g.
+// https://try.gogs.io), using the token to authenticate requests. Requests are
+// sent anonymously when the token is empty.
+func NewClient(url, token string) *Client {
+	url = strings.TrimSuffix(url, "/")
+	return &Client{
+		url:        url,
+		baseURL:    url + "/api/v1",
+		token:      token,
+		httpClient: http.DefaultClient,
+	}
+}
+
+// SetHTTPClient replaces the default HTTP client with the given one.
+func (c *Client) SetHTTPClient(client *http.Client) {
+	c.httpClient = client
+}
+
+// ResponseError is the error returned by the server with a non-2xx status code.
+type ResponseError struct {
+	StatusCode int
+	Message    string
+}
+
+func (err *ResponseError) Error() string {
+	if err.Message == "" {
+		return fmt.Sprintf("%d %s", err.StatusCode, http.StatusText(err.StatusCode))
+	}
+	return fmt.Sprintf("%d %s: %s", err.StatusCode, http.StatusText(err.StatusCode), err.Message)
+}
+
+// NotFound returns true if the server responded with 404 status code.
+func (err *ResponseError) NotFound() bool {
+	return err.StatusCode == http.StatusNotFound
+}
+
+// IsNotFound returns true if the error is a ResponseError with 404 status code.
+func IsNotFound(err error) bool {
+	e, ok := err.(*ResponseError)
+	return ok && e.NotFound()
+}
+
+// do sends the request and decodes the response body into v. The body is
+// encoded as JSON when not nil. The response body is discarded when v is nil,
+// and is read as-is when v is a *string.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, v interface{}) error {
+	var r io.Reader
+	contentType := ""
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request body: %v", err)
+		}
+		r = bytes.NewReader(data)
+		contentType = "application/json"
+	}
+
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return c.doRaw(ctx, method, u, contentType, r, v)
+}
+
+// doRaw sends the request to the URL with given body of the content type, and
+// decodes the response body into v in the same way as do.
+func (c *Client) doRaw(ctx context.Context, method, url, contentType string, body io.Reader, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "token "+c.token)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		respErr := &ResponseError{StatusCode: resp.StatusCode}
+		var payload struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&payload) == nil {
+			respErr.Message = payload.Message
+		}
+		return respErr
+	}
+
+	switch v := v.(type) {
+	case nil:
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	case *string:
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		*v = string(data)
+		return nil
+	default:
+		err = json.NewDecoder(resp.Body).Decode(v)
+		if err != nil {
+			return fmt.Errorf("decode response body: %v", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return NewClient(srv.URL+"/", "abc123")
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("GetRepo", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "/api/v1/repos/alice/my%20repo", r.URL.EscapedPath())
+			assert.Equal(t, "token abc123", r.Header.Get("Authorization"))
+			_, _ = w.Write([]byte(`{"id":1,"name":"my repo","owner":{"id":2,"username":"alice"},"permissions":{"admin":true}}`))
+		})
+
+		repo, err := c.GetRepo(ctx, "alice", "my repo")
+		require.NoError(t, err)
+		want := &Repository{
+			ID:   1,
+			Name: "my repo",
+			Owner: &User{
+				ID:       2,
+				Username: "alice",
+			},
+			Permissions: &Permission{
+				Admin: true,
+			},
+		}
+		assert.Equal(t, want, repo)
+	})
+
+	t.Run("ListIssues", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v1/repos/alice/repo/issues", r.URL.Path)
+			assert.Equal(t, "page=2&state=closed", r.URL.RawQuery)
+			_, _ = w.Write([]byte(`[{"number":1},{"number":2}]`))
+		})
+
+		issues, err := c.ListIssues(ctx, "alice", "repo", "closed", 2)
+		require.NoError(t, err)
+		assert.Equal(t, []*Issue{{Number: 1}, {Number: 2}}, issues)
+	})
+
+	t.Run("CreateIssue", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+			var opt map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&opt))
+			assert.Equal(t, map[string]interface{}{"title": "Bug"}, opt)
+
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"number":3,"title":"Bug"}`))
+		})
+
+		issue, err := c.CreateIssue(ctx, "alice", "repo", CreateIssueOption{Title: "Bug"})
+		require.NoError(t, err)
+		assert.Equal(t, &Issue{Number: 3, Title: "Bug"}, issue)
+	})
+
+	t.Run("DeleteRepo", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodDelete, r.Method)
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		err := c.DeleteRepo(ctx, "alice", "repo")
+		require.NoError(t, err)
+	})
+
+	t.Run("RenderMarkdown", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("<p><strong>bold</strong></p>\n"))
+		})
+
+		html, err := c.RenderMarkdown(ctx, MarkdownOption{Text: "**bold**"})
+		require.NoError(t, err)
+		assert.Equal(t, "<p><strong>bold</strong></p>\n", html)
+	})
+
+	t.Run("UploadReleaseAttachment", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v1/repos/alice/repo/releases/7/attachments", r.URL.Path)
+
+			file, header, err := r.FormFile("attachment")
+			require.NoError(t, err)
+			defer func() { _ = file.Close() }()
+			data, err := io.ReadAll(file)
+			require.NoError(t, err)
+			assert.Equal(t, "app.tar.gz", header.Filename)
+			assert.Equal(t, "content", string(data))
+
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":1,"name":"app.tar.gz"}`))
+		})
+
+		attach, err := c.UploadReleaseAttachment(ctx, "alice", "repo", 7, "app.tar.gz", strings.NewReader("content"))
+		require.NoError(t, err)
+		assert.Equal(t, &Attachment{ID: 1, Name: "app.tar.gz"}, attach)
+	})
+
+	t.Run("device flow", func(t *testing.T) {
+		polls := 0
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "gogs-cli", r.PostForm.Get("client_id"))
+
+			switch r.URL.Path {
+			case "/login/device/code":
+				_, _ = w.Write([]byte(`{"device_code":"dc","user_code":"BCDF-GHJK","interval":1}`))
+			case "/login/oauth/access_token":
+				assert.Equal(t, "dc", r.PostForm.Get("device_code"))
+				polls++
+				if polls == 1 {
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"error":"authorization_pending"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"access_token":"token","token_type":"bearer"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		})
+
+		code, err := c.RequestDeviceCode(ctx, "gogs-cli")
+		require.NoError(t, err)
+		assert.Equal(t, "BCDF-GHJK", code.UserCode)
+
+		token, err := c.PollAccessToken(ctx, "gogs-cli", code)
+		require.NoError(t, err)
+		assert.Equal(t, "token", token)
+		assert.Equal(t, 2, polls)
+	})
+
+	t.Run("device flow denied", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"access_denied","error_description":"The authorization request was denied."}`))
+		})
+
+		_, err := c.PollAccessToken(ctx, "gogs-cli", &DeviceCode{DeviceCode: "dc", Interval: 1})
+		assert.EqualError(t, err, "device authorization failed: access_denied: The authorization request was denied.")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"message":"repository already exists"}`))
+		})
+
+		_, err := c.CreateRepo(ctx, CreateRepoOption{Name: "repo"})
+		assert.Equal(t, &ResponseError{StatusCode: http.StatusUnprocessableEntity, Message: "repository already exists"}, err)
+		assert.Equal(t, "422 Unprocessable Entity: repository already exists", err.Error())
+		assert.False(t, IsNotFound(err))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		_, err := c.GetUser(ctx, "bob")
+		assert.True(t, IsNotFound(err))
+		assert.Equal(t, "404 Not Found", err.Error())
+	})
+}
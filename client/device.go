@@ -0,0 +1,119 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DeviceCode is the response of a device authorization request, see
+// https://datatracker.ietf.org/doc/html/rfc8628#section-3.2.
+type DeviceCode struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// DeviceFlowError is the error returned by the server during the device
+// authorization flow, see https://datatracker.ietf.org/doc/html/rfc6749#section-5.2.
+type DeviceFlowError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (err *DeviceFlowError) Error() string {
+	if err.Description == "" {
+		return err.Code
+	}
+	return err.Code + ": " + err.Description
+}
+
+// postForm sends the form to the path relative to the external URL of the Gogs
+// instance and decodes the JSON response into v.
+func (c *Client) postForm(ctx context.Context, path string, form url.Values, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		flowErr := new(DeviceFlowError)
+		if json.NewDecoder(resp.Body).Decode(flowErr) != nil || flowErr.Code == "" {
+			return &ResponseError{StatusCode: resp.StatusCode}
+		}
+		return flowErr
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// RequestDeviceCode starts the device authorization flow for the client ID.
+func (c *Client) RequestDeviceCode(ctx context.Context, clientID string) (*DeviceCode, error) {
+	code := new(DeviceCode)
+	err := c.postForm(ctx, "/login/device/code", url.Values{"client_id": {clientID}}, code)
+	if err != nil {
+		return nil, err
+	}
+	return code, nil
+}
+
+// PollAccessToken polls the server until the user approved or denied the device
+// authorization, or the device code expired. It returns the access token once
+// the authorization is approved.
+func (c *Client) PollAccessToken(ctx context.Context, clientID string, code *DeviceCode) (string, error) {
+	interval := time.Duration(code.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	form := url.Values{
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+		"client_id":   {clientID},
+		"device_code": {code.DeviceCode},
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(interval):
+		}
+
+		var resp struct {
+			AccessToken string `json:"access_token"`
+		}
+		err := c.postForm(ctx, "/login/oauth/access_token", form, &resp)
+		if err == nil {
+			return resp.AccessToken, nil
+		}
+
+		flowErr, ok := err.(*DeviceFlowError)
+		if !ok {
+			return "", err
+		}
+		switch flowErr.Code {
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		default:
+			return "", fmt.Errorf("device authorization failed: %v", flowErr)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+// Code generated by gen; DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Attachment is generated from the schema "Attachment".
+type Attachment struct {
+	ID                 int64  `json:"id,omitempty"`
+	Uuid               string `json:"uuid,omitempty"`
+	Name               string `json:"name,omitempty"`
+	BrowserDownloadURL string `json:"browser_download_url,omitempty"`
+}
+
+// Branch is generated from the schema "Branch".
+type Branch struct {
+	Name   string         `json:"name,omitempty"`
+	Commit *PayloadCommit `json:"commit,omitempty"`
+}
+
+// CreateIssueOption is generated from the schema "CreateIssueOption".
+type CreateIssueOption struct {
+	Title     string  `json:"title"`
+	Body      string  `json:"body,omitempty"`
+	Assignee  string  `json:"assignee,omitempty"`
+	Milestone int64   `json:"milestone,omitempty"`
+	Labels    []int64 `json:"labels,omitempty"`
+	Closed    bool    `json:"closed,omitempty"`
+}
+
+// CreateRepoOption is generated from the schema "CreateRepoOption".
+type CreateRepoOption struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Private     bool   `json:"private,omitempty"`
+	AutoInit    bool   `json:"auto_init,omitempty"`
+	Gitignores  string `json:"gitignores,omitempty"`
+	License     string `json:"license,omitempty"`
+	Readme      string `json:"readme,omitempty"`
+}
+
+// Issue is generated from the schema "Issue".
+type Issue struct {
+	ID        int64      `json:"id,omitempty"`
+	Number    int64      `json:"number,omitempty"`
+	User      *User      `json:"user,omitempty"`
+	Title     string     `json:"title,omitempty"`
+	Body      string     `json:"body,omitempty"`
+	Labels    []*Label   `json:"labels,omitempty"`
+	Milestone *Milestone `json:"milestone,omitempty"`
+	Assignee  *User      `json:"assignee,omitempty"`
+	State     string     `json:"state,omitempty"`
+	Comments  int        `json:"comments,omitempty"`
+	CreatedAt time.Time  `json:"created_at,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at,omitempty"`
+}
+
+// Label is generated from the schema "Label".
+type Label struct {
+	ID    int64  `json:"id,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Color string `json:"color,omitempty"`
+	URL   string `json:"url,omitempty"`
+}
+
+// MarkdownOption is generated from the schema "MarkdownOption".
+type MarkdownOption struct {
+	Text    string `json:"text"`
+	Context string `json:"context,omitempty"`
+}
+
+// Milestone is generated from the schema "Milestone".
+type Milestone struct {
+	ID           int64      `json:"id,omitempty"`
+	Title        string     `json:"title,omitempty"`
+	Description  string     `json:"description,omitempty"`
+	State        string     `json:"state,omitempty"`
+	OpenIssues   int        `json:"open_issues,omitempty"`
+	ClosedIssues int        `json:"closed_issues,omitempty"`
+	ClosedAt     *time.Time `json:"closed_at,omitempty"`
+	DueOn        *time.Time `json:"due_on,omitempty"`
+}
+
+// PayloadCommit is generated from the schema "PayloadCommit".
+type PayloadCommit struct {
+	ID        string       `json:"id,omitempty"`
+	Message   string       `json:"message,omitempty"`
+	URL       string       `json:"url,omitempty"`
+	Author    *PayloadUser `json:"author,omitempty"`
+	Committer *PayloadUser `json:"committer,omitempty"`
+	Timestamp time.Time    `json:"timestamp,omitempty"`
+}
+
+// PayloadUser is generated from the schema "PayloadUser".
+type PayloadUser struct {
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// Permission is generated from the schema "Permission".
+type Permission struct {
+	Admin bool `json:"admin,omitempty"`
+	Push  bool `json:"push,omitempty"`
+	Pull  bool `json:"pull,omitempty"`
+}
+
+// Release is generated from the schema "Release".
+type Release struct {
+	ID              int64     `json:"id,omitempty"`
+	TagName         string    `json:"tag_name,omitempty"`
+	TargetCommitish string    `json:"target_commitish,omitempty"`
+	Name            string    `json:"name,omitempty"`
+	Body            string    `json:"body,omitempty"`
+	Draft           bool      `json:"draft,omitempty"`
+	Prerelease      bool      `json:"prerelease,omitempty"`
+	Author          *User     `json:"author,omitempty"`
+	CreatedAt       time.Time `json:"created_at,omitempty"`
+}
+
+// Repository is generated from the schema "Repository".
+type Repository struct {
+	ID              int64       `json:"id,omitempty"`
+	Owner           *User       `json:"owner,omitempty"`
+	Name            string      `json:"name,omitempty"`
+	FullName        string      `json:"full_name,omitempty"`
+	Description     string      `json:"description,omitempty"`
+	Private         bool        `json:"private,omitempty"`
+	Fork            bool        `json:"fork,omitempty"`
+	Parent          *Repository `json:"parent,omitempty"`
+	Empty           bool        `json:"empty,omitempty"`
+	Mirror          bool        `json:"mirror,omitempty"`
+	Size            int64       `json:"size,omitempty"`
+	HTMLURL         string      `json:"html_url,omitempty"`
+	SSHURL          string      `json:"ssh_url,omitempty"`
+	CloneURL        string      `json:"clone_url,omitempty"`
+	Website         string      `json:"website,omitempty"`
+	StarsCount      int         `json:"stars_count,omitempty"`
+	ForksCount      int         `json:"forks_count,omitempty"`
+	WatchersCount   int         `json:"watchers_count,omitempty"`
+	OpenIssuesCount int         `json:"open_issues_count,omitempty"`
+	DefaultBranch   string      `json:"default_branch,omitempty"`
+	CreatedAt       time.Time   `json:"created_at,omitempty"`
+	UpdatedAt       time.Time   `json:"updated_at,omitempty"`
+	Permissions     *Permission `json:"permissions,omitempty"`
+}
+
+// User is generated from the schema "User".
+type User struct {
+	ID        int64  `json:"id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	Login     string `json:"login,omitempty"`
+	FullName  string `json:"full_name,omitempty"`
+	Email     string `json:"email,omitempty"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+}
+
+// CreateIssue creates an issue in the repository.
+func (c *Client) CreateIssue(ctx context.Context, owner string, repo string, opt CreateIssueOption) (*Issue, error) {
+	v := new(Issue)
+	err := c.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/issues", url.PathEscape(owner), url.PathEscape(repo)), nil, opt, v)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// CreateRepo creates a repository for the authenticated user.
+func (c *Client) CreateRepo(ctx context.Context, opt CreateRepoOption) (*Repository, error) {
+	v := new(Repository)
+	err := c.do(ctx, http.MethodPost, "/user/repos", nil, opt, v)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// DeleteRepo deletes the repository with given owner and name.
+func (c *Client) DeleteRepo(ctx context.Context, owner string, repo string) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/repos/%s/%s", url.PathEscape(owner), url.PathEscape(repo)), nil, nil, nil)
+}
+
+// GetAuthenticatedUser returns the authenticated user.
+func (c *Client) GetAuthenticatedUser(ctx context.Context) (*User, error) {
+	v := new(User)
+	err := c.do(ctx, http.MethodGet, "/user", nil, nil, v)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// GetIssue returns the issue with given index in the repository.
+func (c *Client) GetIssue(ctx context.Context, owner string, repo string, index int64) (*Issue, error) {
+	v := new(Issue)
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/issues/%d", url.PathEscape(owner), url.PathEscape(repo), index), nil, nil, v)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// GetRepo returns the repository with given owner and name.
+func (c *Client) GetRepo(ctx context.Context, owner string, repo string) (*Repository, error) {
+	v := new(Repository)
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s", url.PathEscape(owner), url.PathEscape(repo)), nil, nil, v)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// GetUser returns the user with given username.
+func (c *Client) GetUser(ctx context.Context, username string) (*User, error) {
+	v := new(User)
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/users/%s", url.PathEscape(username)), nil, nil, v)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ListBranches returns all branches of the repository.
+func (c *Client) ListBranches(ctx context.Context, owner string, repo string) ([]*Branch, error) {
+	var v []*Branch
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/branches", url.PathEscape(owner), url.PathEscape(repo)), nil, nil, &v)
+	return v, err
+}
+
+// ListIssues returns issues of the repository in given state and page.
+func (c *Client) ListIssues(ctx context.Context, owner string, repo string, state string, page int) ([]*Issue, error) {
+	query := make(url.Values)
+	if state != "" {
+		query.Set("state", state)
+	}
+	if page != 0 {
+		query.Set("page", strconv.Itoa(page))
+	}
+	var v []*Issue
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/issues", url.PathEscape(owner), url.PathEscape(repo)), query, nil, &v)
+	return v, err
+}
+
+// ListMyRepos returns all repositories the authenticated user has access to.
+func (c *Client) ListMyRepos(ctx context.Context) ([]*Repository, error) {
+	var v []*Repository
+	err := c.do(ctx, http.MethodGet, "/user/repos", nil, nil, &v)
+	return v, err
+}
+
+// ListReleases returns all releases of the repository.
+func (c *Client) ListReleases(ctx context.Context, owner string, repo string) ([]*Release, error) {
+	var v []*Release
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/releases", url.PathEscape(owner), url.PathEscape(repo)), nil, nil, &v)
+	return v, err
+}
+
+// ListUserRepos returns repositories owned by the user with given username.
+func (c *Client) ListUserRepos(ctx context.Context, username string) ([]*Repository, error) {
+	var v []*Repository
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/users/%s/repos", url.PathEscape(username)), nil, nil, &v)
+	return v, err
+}
+
+// RenderMarkdown renders a Markdown document to HTML.
+func (c *Client) RenderMarkdown(ctx context.Context, opt MarkdownOption) (string, error) {
+	var v string
+	err := c.do(ctx, http.MethodPost, "/markdown", nil, opt, &v)
+	return v, err
+}
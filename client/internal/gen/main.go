@@ -0,0 +1,435 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Gen generates Go types and client methods of the package "gogs.io/gogs/client"
+// from the OpenAPI document.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+func main() {
+	if len(os.Args) != 3 {
+		log.Fatal("Usage: gen <openapi.yaml> <output.go>")
+	}
+
+	data, err := os.ReadFile(os.Args[1])
+	if err != nil {
+		log.Fatalf("Failed to read OpenAPI document: %v", err)
+	}
+
+	src, err := generate(data)
+	if err != nil {
+		log.Fatalf("Failed to generate: %v", err)
+	}
+
+	err = os.WriteFile(os.Args[2], src, 0644)
+	if err != nil {
+		log.Fatalf("Failed to write file: %v", err)
+	}
+}
+
+type document struct {
+	Paths      orderedMap `yaml:"paths"`
+	Components struct {
+		Parameters map[string]*parameter `yaml:"parameters"`
+		Schemas    orderedMap            `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+// orderedMap is a YAML mapping that preserves the order of its keys.
+type orderedMap struct {
+	node *yaml.Node
+}
+
+func (m *orderedMap) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return errors.Errorf("line %d: expect a mapping", node.Line)
+	}
+	m.node = node
+	return nil
+}
+
+// each calls fn for every key-value pair of the mapping in the order of
+// declaration.
+func (m *orderedMap) each(fn func(key string, value *yaml.Node) error) error {
+	if m.node == nil {
+		return nil
+	}
+	for i := 0; i+1 < len(m.node.Content); i += 2 {
+		err := fn(m.node.Content[i].Value, m.node.Content[i+1])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type schema struct {
+	Ref        string     `yaml:"$ref"`
+	Type       string     `yaml:"type"`
+	Format     string     `yaml:"format"`
+	Nullable   bool       `yaml:"nullable"`
+	Required   []string   `yaml:"required"`
+	Properties orderedMap `yaml:"properties"`
+	Items      *schema    `yaml:"items"`
+}
+
+type parameter struct {
+	Ref      string  `yaml:"$ref"`
+	Name     string  `yaml:"name"`
+	In       string  `yaml:"in"`
+	Required bool    `yaml:"required"`
+	Schema   *schema `yaml:"schema"`
+}
+
+type mediaType struct {
+	Schema *schema `yaml:"schema"`
+}
+
+type operation struct {
+	OperationID string       `yaml:"operationId"`
+	Summary     string       `yaml:"summary"`
+	Handwritten bool         `yaml:"x-handwritten"` // The method is implemented manually
+	Parameters  []*parameter `yaml:"parameters"`
+	RequestBody *struct {
+		Content map[string]*mediaType `yaml:"content"`
+	} `yaml:"requestBody"`
+	Responses map[string]*struct {
+		Content map[string]*mediaType `yaml:"content"`
+	} `yaml:"responses"`
+
+	method string
+	path   string
+}
+
+func generate(data []byte) ([]byte, error) {
+	var doc document
+	err := yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal")
+	}
+
+	var buf bytes.Buffer
+
+	err = doc.Components.Schemas.each(func(name string, value *yaml.Node) error {
+		var s schema
+		err := value.Decode(&s)
+		if err != nil {
+			return errors.Wrapf(err, "decode schema %q", name)
+		}
+		return writeType(&buf, name, &s)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []*operation
+	err = doc.Paths.each(func(path string, value *yaml.Node) error {
+		var methods orderedMap
+		err := value.Decode(&methods)
+		if err != nil {
+			return errors.Wrapf(err, "decode path %q", path)
+		}
+		return methods.each(func(method string, value *yaml.Node) error {
+			op := &operation{
+				method: strings.ToUpper(method),
+				path:   path,
+			}
+			err := value.Decode(op)
+			if err != nil {
+				return errors.Wrapf(err, "decode operation %s %s", method, path)
+			}
+			if op.OperationID == "" {
+				return errors.Errorf("operation %s %s has no operationId", method, path)
+			} else if op.Handwritten {
+				return nil
+			}
+			ops = append(ops, op)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].OperationID < ops[j].OperationID
+	})
+
+	for _, op := range ops {
+		err = writeOperation(&buf, &doc, op)
+		if err != nil {
+			return nil, errors.Wrapf(err, "operation %q", op.OperationID)
+		}
+	}
+
+	imports := []string{"context", "net/http"}
+	for _, pkg := range []string{"fmt", "net/url", "strconv", "time"} {
+		if bytes.Contains(buf.Bytes(), []byte(path.Base(pkg)+".")) {
+			imports = append(imports, pkg)
+		}
+	}
+	sort.Strings(imports)
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by gen; DO NOT EDIT.\n\npackage client\n\nimport (\n")
+	for _, pkg := range imports {
+		fmt.Fprintf(&out, "\t%q\n", pkg)
+	}
+	out.WriteString(")\n")
+	_, _ = buf.WriteTo(&out)
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, errors.Wrapf(err, "format source\n%s", out.String())
+	}
+	return src, nil
+}
+
+func writeType(buf *bytes.Buffer, name string, s *schema) error {
+	if s.Type != "object" {
+		return errors.Errorf("schema %q: only object is supported but got %q", name, s.Type)
+	}
+
+	required := make(map[string]bool, len(s.Required))
+	for _, r := range s.Required {
+		required[r] = true
+	}
+
+	fmt.Fprintf(buf, "\n// %s is generated from the schema %q.\ntype %s struct {\n", name, name, name)
+	err := s.Properties.each(func(prop string, value *yaml.Node) error {
+		var ps schema
+		err := value.Decode(&ps)
+		if err != nil {
+			return errors.Wrapf(err, "decode property %q", prop)
+		}
+
+		typ, err := goType(&ps)
+		if err != nil {
+			return errors.Wrapf(err, "property %q", prop)
+		}
+
+		tag := prop
+		if !required[prop] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(buf, "\t%s %s `json:%q`\n", goName(prop), typ, tag)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "schema %q", name)
+	}
+	buf.WriteString("}\n")
+	return nil
+}
+
+func writeOperation(buf *bytes.Buffer, doc *document, op *operation) error {
+	args := []string{"ctx context.Context"}
+	var pathArgs []string
+	var queries []*parameter
+	urlPath := op.path
+	for _, p := range op.Parameters {
+		if p.Ref != "" {
+			ref := doc.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+			if ref == nil {
+				return errors.Errorf("parameter %q not found", p.Ref)
+			}
+			p = ref
+		}
+
+		typ, err := goType(p.Schema)
+		if err != nil {
+			return errors.Wrapf(err, "parameter %q", p.Name)
+		}
+		args = append(args, lowerFirst(goName(p.Name))+" "+typ)
+
+		switch p.In {
+		case "path":
+			verb := "%s"
+			arg := "url.PathEscape(" + lowerFirst(goName(p.Name)) + ")"
+			if typ != "string" {
+				verb = "%d"
+				arg = lowerFirst(goName(p.Name))
+			}
+			urlPath = strings.Replace(urlPath, "{"+p.Name+"}", verb, 1)
+			pathArgs = append(pathArgs, arg)
+		case "query":
+			queries = append(queries, p)
+		default:
+			return errors.Errorf("parameter %q: unsupported location %q", p.Name, p.In)
+		}
+	}
+
+	body := "nil"
+	if op.RequestBody != nil {
+		mt := op.RequestBody.Content["application/json"]
+		if mt == nil || mt.Schema == nil {
+			return errors.New("request body must be application/json")
+		}
+		typ, err := goType(mt.Schema)
+		if err != nil {
+			return errors.Wrap(err, "request body")
+		}
+		args = append(args, "opt "+strings.TrimPrefix(typ, "*"))
+		body = "opt"
+	}
+
+	// Find the success response, there should be exactly one.
+	var result string
+	var codes []string
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) != 1 {
+		return errors.Errorf("expect exactly one success response but got %d", len(codes))
+	}
+	for _, mt := range op.Responses[codes[0]].Content {
+		typ, err := goType(mt.Schema)
+		if err != nil {
+			return errors.Wrap(err, "response")
+		}
+		result = typ
+	}
+
+	fmt.Fprintf(buf, "\n// %s %s\nfunc (c *Client) %s(%s) ", op.OperationID, op.Summary, op.OperationID, strings.Join(args, ", "))
+	if result == "" {
+		buf.WriteString("error {\n")
+	} else {
+		fmt.Fprintf(buf, "(%s, error) {\n", result)
+	}
+
+	query := "nil"
+	if len(queries) > 0 {
+		query = "query"
+		buf.WriteString("query := make(url.Values)\n")
+		for _, q := range queries {
+			name := lowerFirst(goName(q.Name))
+			switch q.Schema.Type {
+			case "string":
+				fmt.Fprintf(buf, "if %s != \"\" {\nquery.Set(%q, %s)\n}\n", name, q.Name, name)
+			case "integer":
+				format := "strconv.Itoa(%s)"
+				if q.Schema.Format == "int64" {
+					format = "strconv.FormatInt(%s, 10)"
+				}
+				fmt.Fprintf(buf, "if %s != 0 {\nquery.Set(%q, "+format+")\n}\n", name, q.Name, name)
+			case "boolean":
+				fmt.Fprintf(buf, "if %s {\nquery.Set(%q, \"true\")\n}\n", name, q.Name)
+			default:
+				return errors.Errorf("query parameter %q: unsupported type %q", q.Name, q.Schema.Type)
+			}
+		}
+	}
+
+	pathExpr := fmt.Sprintf("%q", urlPath)
+	if len(pathArgs) > 0 {
+		pathExpr = fmt.Sprintf("fmt.Sprintf(%q, %s)", urlPath, strings.Join(pathArgs, ", "))
+	}
+
+	switch {
+	case result == "":
+		fmt.Fprintf(buf, "return c.do(ctx, http.Method%s, %s, %s, %s, nil)\n", methodName(op.method), pathExpr, query, body)
+	case strings.HasPrefix(result, "*"):
+		fmt.Fprintf(buf, "v := new(%s)\nerr := c.do(ctx, http.Method%s, %s, %s, %s, v)\nif err != nil {\nreturn nil, err\n}\nreturn v, nil\n",
+			strings.TrimPrefix(result, "*"), methodName(op.method), pathExpr, query, body)
+	default:
+		fmt.Fprintf(buf, "var v %s\nerr := c.do(ctx, http.Method%s, %s, %s, %s, &v)\nreturn v, err\n",
+			result, methodName(op.method), pathExpr, query, body)
+	}
+	buf.WriteString("}\n")
+	return nil
+}
+
+// goType returns the Go type for the schema.
+func goType(s *schema) (string, error) {
+	if s == nil {
+		return "", errors.New("missing schema")
+	}
+
+	if s.Ref != "" {
+		return "*" + strings.TrimPrefix(s.Ref, "#/components/schemas/"), nil
+	}
+
+	var typ string
+	switch s.Type {
+	case "string":
+		typ = "string"
+		if s.Format == "date-time" {
+			typ = "time.Time"
+		}
+	case "integer":
+		typ = "int"
+		if s.Format == "int64" {
+			typ = "int64"
+		}
+	case "number":
+		typ = "float64"
+	case "boolean":
+		typ = "bool"
+	case "array":
+		elem, err := goType(s.Items)
+		if err != nil {
+			return "", errors.Wrap(err, "items")
+		}
+		return "[]" + elem, nil
+	default:
+		return "", errors.Errorf("unsupported type %q", s.Type)
+	}
+
+	if s.Nullable {
+		typ = "*" + typ
+	}
+	return typ, nil
+}
+
+var initialisms = map[string]string{
+	"api":  "API",
+	"html": "HTML",
+	"id":   "ID",
+	"ssh":  "SSH",
+	"url":  "URL",
+}
+
+// goName converts snake_case name to a exported Go identifier.
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		if s, ok := initialisms[part]; ok {
+			b.WriteString(s)
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// lowerFirst lowers the first initialism or letter of the exported Go identifier.
+func lowerFirst(name string) string {
+	for _, s := range initialisms {
+		if name == s {
+			return strings.ToLower(s)
+		}
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+func methodName(method string) string {
+	return method[:1] + strings.ToLower(method[1:])
+}
@@ -0,0 +1,42 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	data, err := os.ReadFile("../../openapi.yaml")
+	require.NoError(t, err)
+
+	got, err := generate(data)
+	require.NoError(t, err)
+
+	want, err := os.ReadFile("../../generated.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got), "generated.go is out of date, please run `go generate ./client`")
+}
+
+func TestGoName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "id", want: "ID"},
+		{name: "full_name", want: "FullName"},
+		{name: "html_url", want: "HTMLURL"},
+		{name: "open_issues_count", want: "OpenIssuesCount"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, goName(test.name))
+		})
+	}
+}
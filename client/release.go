@@ -0,0 +1,41 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+)
+
+// UploadReleaseAttachment uploads a file as an attachment of the release.
+func (c *Client) UploadReleaseAttachment(ctx context.Context, owner, repo string, id int64, name string, r io.Reader) (*Attachment, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("attachment", name)
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(part, r)
+	if err != nil {
+		return nil, err
+	}
+	err = w.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	u := c.baseURL + fmt.Sprintf("/repos/%s/%s/releases/%d/attachments", url.PathEscape(owner), url.PathEscape(repo), id)
+	v := new(Attachment)
+	err = c.doRaw(ctx, http.MethodPost, u, w.FormDataContentType(), &body, v)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
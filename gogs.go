@@ -36,6 +36,7 @@ func main() {
 		cmd.Import,
 		cmd.Backup,
 		cmd.Restore,
+		cmd.CLI,
 	}
 	if err := app.Run(os.Args); err != nil {
 		log.Fatal("Failed to start application: %v", err)
@@ -0,0 +1,168 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package antivirus scans uploaded files for viruses before they are stored.
+package antivirus
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/db"
+)
+
+// Kind is the kind of uploaded files.
+type Kind string
+
+const (
+	KindAttachment        Kind = "attachment"
+	KindReleaseAttachment Kind = "release_attachment"
+	KindLFSObject         Kind = "lfs_object"
+)
+
+// Enabled returns true if uploaded files of the kind should be scanned.
+func Enabled(kind Kind) bool {
+	if !conf.Antivirus.Enabled {
+		return false
+	}
+
+	switch kind {
+	case KindAttachment:
+		return conf.Antivirus.ScanAttachments
+	case KindReleaseAttachment:
+		return conf.Antivirus.ScanReleaseAttachments
+	case KindLFSObject:
+		return conf.Antivirus.ScanLFSObjects
+	default:
+		return false
+	}
+}
+
+var scans = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gogs_antivirus_scans_total",
+		Help: "Number of uploaded files scanned for viruses, the result is one of clean, infected and error.",
+	},
+	[]string{"kind", "result"},
+)
+
+// Collector returns the Prometheus collector of scans.
+func Collector() prometheus.Collector {
+	return scans
+}
+
+// ErrInfected is returned when an uploaded file is infected.
+type ErrInfected struct {
+	Signature string
+}
+
+func IsErrInfected(err error) bool {
+	_, ok := err.(ErrInfected)
+	return ok
+}
+
+func (err ErrInfected) Error() string {
+	return fmt.Sprintf("file is infected with %s", err.Signature)
+}
+
+// newScanner returns the scanner to use, it is a variable for testing.
+var newScanner = func() (Scanner, error) {
+	return NewClamd(conf.Antivirus.ClamdAddress, conf.Antivirus.Timeout)
+}
+
+// Scan scans content of the uploaded file with given name when scanning files
+// of the kind is enabled, the source describes where the file comes from (e.g.
+// "user alice"). The position of the reader is restored when the function
+// returns without errors. When the file is infected, the content is copied to
+// the quarantine directory, a system notice is created and ErrInfected is
+// returned.
+func Scan(ctx context.Context, kind Kind, name, source string, r io.ReadSeeker) error {
+	if !Enabled(kind) {
+		return nil
+	}
+
+	offset, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return errors.Wrap(err, "get offset")
+	}
+
+	result, err := scan(ctx, r)
+	if err != nil {
+		scans.WithLabelValues(string(kind), "error").Inc()
+		return errors.Wrap(err, "scan")
+	} else if !result.Infected {
+		scans.WithLabelValues(string(kind), "clean").Inc()
+		_, err = r.Seek(offset, io.SeekStart)
+		return err
+	}
+	scans.WithLabelValues(string(kind), "infected").Inc()
+
+	qpath, err := quarantine(kind, name, r)
+	if err != nil {
+		log.Error("Failed to quarantine infected file %q: %v", name, err)
+	}
+
+	desc := fmt.Sprintf("Infected %s %q from %s was rejected: %s (quarantined to %q)", kind, name, source, result.Signature, qpath)
+	log.Warn("%s", desc)
+	if err = db.CreateNotice(db.NOTICE_ANTIVIRUS, db.NoticeLevelWarn, desc); err != nil {
+		log.Error("Failed to create system notice: %v", err)
+	}
+	return ErrInfected{Signature: result.Signature}
+}
+
+func scan(ctx context.Context, r io.ReadSeeker) (*Result, error) {
+	_, err := r.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, errors.Wrap(err, "seek to start")
+	}
+
+	scanner, err := newScanner()
+	if err != nil {
+		return nil, errors.Wrap(err, "new scanner")
+	}
+	return scanner.Scan(ctx, r)
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// quarantine copies content of the infected file to the quarantine directory,
+// and returns the path of the copy.
+func quarantine(kind Kind, name string, r io.ReadSeeker) (string, error) {
+	_, err := r.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", errors.Wrap(err, "seek to start")
+	}
+
+	err = os.MkdirAll(conf.Antivirus.QuarantinePath, 0700)
+	if err != nil {
+		return "", errors.Wrap(err, "create directory")
+	}
+
+	// The copy is not executable and only readable by the owner.
+	fpath := filepath.Join(
+		conf.Antivirus.QuarantinePath,
+		fmt.Sprintf("%s-%s-%s", time.Now().UTC().Format("20060102T150405.000000000"), kind, unsafeNameChars.ReplaceAllString(filepath.Base(name), "_")),
+	)
+	w, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", errors.Wrap(err, "create file")
+	}
+	defer func() { _ = w.Close() }()
+
+	_, err = io.Copy(w, r)
+	if err != nil {
+		return "", errors.Wrap(err, "copy content")
+	}
+	return fpath, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package antivirus
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/conf"
+)
+
+func TestEnabled(t *testing.T) {
+	conf.SetMockAntivirus(t, conf.AntivirusOpts{
+		Enabled:         false,
+		ScanAttachments: true,
+	})
+	assert.False(t, Enabled(KindAttachment))
+
+	conf.SetMockAntivirus(t, conf.AntivirusOpts{
+		Enabled:         true,
+		ScanAttachments: true,
+		ScanLFSObjects:  false,
+	})
+	assert.True(t, Enabled(KindAttachment))
+	assert.False(t, Enabled(KindReleaseAttachment))
+	assert.False(t, Enabled(KindLFSObject))
+}
+
+type mockScanner struct{}
+
+func (mockScanner) Scan(_ context.Context, r io.Reader) (*Result, error) {
+	p, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(string(p), "EICAR") {
+		return &Result{Infected: true, Signature: "Eicar-Signature"}, nil
+	}
+	return &Result{}, nil
+}
+
+func TestScan(t *testing.T) {
+	quarantinePath := filepath.Join(t.TempDir(), "quarantine")
+	conf.SetMockAntivirus(t, conf.AntivirusOpts{
+		Enabled:         true,
+		QuarantinePath:  quarantinePath,
+		ScanAttachments: true,
+	})
+
+	before := newScanner
+	newScanner = func() (Scanner, error) { return mockScanner{}, nil }
+	t.Cleanup(func() { newScanner = before })
+
+	ctx := context.Background()
+
+	t.Run("clean", func(t *testing.T) {
+		r := strings.NewReader("hello world")
+		_, err := r.Seek(5, io.SeekStart)
+		require.NoError(t, err)
+
+		err = Scan(ctx, KindAttachment, "hello.txt", "user alice", r)
+		require.NoError(t, err)
+
+		// The position of the reader is restored
+		rest, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, " world", string(rest))
+	})
+
+	t.Run("infected", func(t *testing.T) {
+		r := strings.NewReader("EICAR")
+		err := Scan(ctx, KindAttachment, "../virus.exe", "user alice", r)
+		assert.Equal(t, ErrInfected{Signature: "Eicar-Signature"}, err)
+
+		entries, err := ioutil.ReadDir(quarantinePath)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.True(t, strings.HasSuffix(entries[0].Name(), "-attachment-virus.exe"), entries[0].Name())
+
+		p, err := os.ReadFile(filepath.Join(quarantinePath, entries[0].Name()))
+		require.NoError(t, err)
+		assert.Equal(t, "EICAR", string(p))
+	})
+
+	t.Run("disabled kind", func(t *testing.T) {
+		err := Scan(ctx, KindLFSObject, "virus.exe", "user alice", strings.NewReader("EICAR"))
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package antivirus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Result is the result of a scan.
+type Result struct {
+	Infected bool
+	// Signature is the name of the virus found, only set when infected.
+	Signature string
+}
+
+// Scanner is a virus scanner.
+type Scanner interface {
+	// Scan scans content read from the reader.
+	Scan(ctx context.Context, r io.Reader) (*Result, error)
+}
+
+var _ Scanner = (*Clamd)(nil)
+
+// Clamd is a scanner that sends content to the clamd daemon of ClamAV.
+type Clamd struct {
+	Network string
+	Address string
+	Timeout time.Duration
+}
+
+// NewClamd returns a new scanner with given address of the clamd daemon, which
+// is either "unix:///path/to/clamd.sock" or "tcp://host:port".
+func NewClamd(address string, timeout time.Duration) (*Clamd, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse address")
+	}
+
+	switch u.Scheme {
+	case "unix":
+		return &Clamd{Network: "unix", Address: u.Path, Timeout: timeout}, nil
+	case "tcp":
+		return &Clamd{Network: "tcp", Address: u.Host, Timeout: timeout}, nil
+	default:
+		return nil, errors.Errorf("unsupported scheme %q of clamd address", u.Scheme)
+	}
+}
+
+// clamdChunkSize is the size of each chunk sent to clamd, which must not exceed
+// the StreamMaxLength of clamd.
+const clamdChunkSize = 32 * 1024
+
+// Scan sends content read from the reader to clamd using the INSTREAM command.
+func (c *Clamd) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, c.Network, c.Address)
+	if err != nil {
+		return nil, errors.Wrap(err, "connect to clamd")
+	}
+	defer func() { _ = conn.Close() }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	w := bufio.NewWriter(conn)
+	_, err = w.WriteString("zINSTREAM\x00")
+	if err != nil {
+		return nil, errors.Wrap(err, "send command")
+	}
+
+	buf := make([]byte, clamdChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := w.Write(size); err != nil {
+				return nil, errors.Wrap(err, "send chunk size")
+			} else if _, err = w.Write(buf[:n]); err != nil {
+				return nil, errors.Wrap(err, "send chunk")
+			}
+		}
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Wrap(err, "read content")
+		}
+	}
+
+	// A zero-length chunk marks the end of the stream.
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err = w.Write(size); err != nil {
+		return nil, errors.Wrap(err, "send end of stream")
+	} else if err = w.Flush(); err != nil {
+		return nil, errors.Wrap(err, "flush")
+	}
+
+	reply, err := bufio.NewReader(conn).ReadBytes(0)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "read reply")
+	}
+	return parseClamdReply(string(bytes.TrimRight(reply, "\x00\n")))
+}
+
+// parseClamdReply parses the reply of the INSTREAM command, e.g. "stream: OK",
+// "stream: Eicar-Signature FOUND" or "INSTREAM size limit exceeded. ERROR".
+func parseClamdReply(reply string) (*Result, error) {
+	switch {
+	case strings.HasSuffix(reply, " FOUND"):
+		signature := strings.TrimSuffix(reply, " FOUND")
+		if i := strings.Index(signature, ": "); i >= 0 {
+			signature = signature[i+2:]
+		}
+		return &Result{Infected: true, Signature: signature}, nil
+	case strings.HasSuffix(reply, ": OK"):
+		return &Result{}, nil
+	default:
+		return nil, fmt.Errorf("unexpected reply from clamd: %q", reply)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package antivirus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClamd(t *testing.T) {
+	c, err := NewClamd("unix:///var/run/clamav/clamd.ctl", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, &Clamd{Network: "unix", Address: "/var/run/clamav/clamd.ctl", Timeout: time.Minute}, c)
+
+	c, err = NewClamd("tcp://localhost:3310", 0)
+	require.NoError(t, err)
+	assert.Equal(t, &Clamd{Network: "tcp", Address: "localhost:3310"}, c)
+
+	_, err = NewClamd("http://localhost:3310", 0)
+	assert.Error(t, err)
+}
+
+func Test_parseClamdReply(t *testing.T) {
+	tests := []struct {
+		reply   string
+		want    *Result
+		wantErr bool
+	}{
+		{reply: "stream: OK", want: &Result{}},
+		{reply: "stream: Win.Test.EICAR_HDB-1 FOUND", want: &Result{Infected: true, Signature: "Win.Test.EICAR_HDB-1"}},
+		{reply: "INSTREAM size limit exceeded. ERROR", wantErr: true},
+		{reply: "", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.reply, func(t *testing.T) {
+			got, err := parseClamdReply(test.reply)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+// serveClamd starts a fake clamd daemon that reports content containing
+// "EICAR" as infected, and returns its address.
+func serveClamd(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer func() { _ = conn.Close() }()
+
+				r := bufio.NewReader(conn)
+				cmd, err := r.ReadString(0)
+				if err != nil || cmd != "zINSTREAM\x00" {
+					_, _ = conn.Write([]byte("UNKNOWN COMMAND\x00"))
+					return
+				}
+
+				var content bytes.Buffer
+				size := make([]byte, 4)
+				for {
+					if _, err = io.ReadFull(r, size); err != nil {
+						return
+					}
+					n := binary.BigEndian.Uint32(size)
+					if n == 0 {
+						break
+					}
+					if _, err = io.CopyN(&content, r, int64(n)); err != nil {
+						return
+					}
+				}
+
+				if strings.Contains(content.String(), "EICAR") {
+					_, _ = conn.Write([]byte("stream: Eicar-Signature FOUND\x00"))
+				} else {
+					_, _ = conn.Write([]byte("stream: OK\x00"))
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestClamd_Scan(t *testing.T) {
+	c := &Clamd{
+		Network: "tcp",
+		Address: serveClamd(t),
+		Timeout: 10 * time.Second,
+	}
+	ctx := context.Background()
+
+	// Content larger than a chunk
+	got, err := c.Scan(ctx, strings.NewReader(strings.Repeat("clean", clamdChunkSize)))
+	require.NoError(t, err)
+	assert.Equal(t, &Result{}, got)
+
+	got, err = c.Scan(ctx, strings.NewReader("X5O!P%@AP[4\\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*"))
+	require.NoError(t, err)
+	assert.Equal(t, &Result{Infected: true, Signature: "Eicar-Signature"}, got)
+}
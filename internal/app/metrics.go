@@ -5,12 +5,18 @@
 package app
 
 import (
+	"context"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"gopkg.in/macaron.v1"
+	log "unknwon.dev/clog/v2"
 
 	"gogs.io/gogs/internal/authutil"
 	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/db"
 )
 
 func MetricsFilter() macaron.Handler {
@@ -31,3 +37,143 @@ func MetricsFilter() macaron.Handler {
 		}
 	}
 }
+
+var fetchesDesc = prometheus.NewDesc(
+	"gogs_git_fetches_total",
+	"Number of Git fetches (including clones) that served a packfile, the filter is empty for fetches without object filter.",
+	[]string{"protocol", "filter", "shallow"},
+	nil,
+)
+
+// fetchStatsCollector collects statistics of Git fetches from the database, so
+// that fetches served by all processes (e.g. "gogs serv" for SSH) are counted.
+type fetchStatsCollector struct{}
+
+// NewFetchStatsCollector returns a new Prometheus collector of statistics of
+// Git fetches.
+func NewFetchStatsCollector() prometheus.Collector {
+	return fetchStatsCollector{}
+}
+
+func (fetchStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- fetchesDesc
+}
+
+func (fetchStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	// The database is not yet initialized during installation.
+	if db.FetchStats == nil {
+		return
+	}
+
+	stats, err := db.FetchStats.List(context.Background())
+	if err != nil {
+		log.Error("Failed to list fetch statistics: %v", err)
+		return
+	}
+
+	for _, s := range stats {
+		ch <- prometheus.MustNewConstMetric(
+			fetchesDesc,
+			prometheus.CounterValue,
+			float64(s.Count),
+			s.Protocol, s.Filter, strconv.FormatBool(s.IsShallow),
+		)
+	}
+}
+
+var (
+	repoReviewPullRequestsDesc = prometheus.NewDesc(
+		"gogs_repo_review_pull_requests",
+		"Number of pull requests of the repository created in the rolling window, by state of created, reviewed or merged.",
+		[]string{"repo", "window", "state"},
+		nil,
+	)
+	repoReviewFirstReviewDesc = prometheus.NewDesc(
+		"gogs_repo_review_time_to_first_review_seconds",
+		"Quantiles of time from creation to the first review of pull requests of the repository created in the rolling window.",
+		[]string{"repo", "window", "quantile"},
+		nil,
+	)
+	repoReviewMergeDesc = prometheus.NewDesc(
+		"gogs_repo_review_time_to_merge_seconds",
+		"Quantiles of time from creation to merge of pull requests of the repository created in the rolling window.",
+		[]string{"repo", "window", "quantile"},
+		nil,
+	)
+	ownerReviewPullRequestsDesc = prometheus.NewDesc(
+		"gogs_owner_review_pull_requests",
+		"Number of pull requests of all repositories of the owner created in the rolling window, by state of created, reviewed or merged.",
+		[]string{"owner", "window", "state"},
+		nil,
+	)
+	ownerReviewFirstReviewDesc = prometheus.NewDesc(
+		"gogs_owner_review_time_to_first_review_seconds",
+		"Quantiles of time from creation to the first review of pull requests of all repositories of the owner created in the rolling window.",
+		[]string{"owner", "window", "quantile"},
+		nil,
+	)
+	ownerReviewMergeDesc = prometheus.NewDesc(
+		"gogs_owner_review_time_to_merge_seconds",
+		"Quantiles of time from creation to merge of pull requests of all repositories of the owner created in the rolling window.",
+		[]string{"owner", "window", "quantile"},
+		nil,
+	)
+)
+
+// reviewLatencyCollector collects review latency of pull requests per
+// repository and owner from the database.
+type reviewLatencyCollector struct{}
+
+// NewReviewLatencyCollector returns a new Prometheus collector of review
+// latency of pull requests.
+func NewReviewLatencyCollector() prometheus.Collector {
+	return reviewLatencyCollector{}
+}
+
+func (reviewLatencyCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- repoReviewPullRequestsDesc
+	ch <- repoReviewFirstReviewDesc
+	ch <- repoReviewMergeDesc
+	ch <- ownerReviewPullRequestsDesc
+	ch <- ownerReviewFirstReviewDesc
+	ch <- ownerReviewMergeDesc
+}
+
+// collectReviewLatencies sends metrics of the review latencies with given
+// descriptors and the label value of the repository or the owner.
+func collectReviewLatencies(ch chan<- prometheus.Metric, pullRequestsDesc, firstReviewDesc, mergeDesc *prometheus.Desc, label string, latencies []*db.ReviewLatency) {
+	for _, l := range latencies {
+		ch <- prometheus.MustNewConstMetric(pullRequestsDesc, prometheus.GaugeValue, float64(l.PullRequests), label, l.Window, "created")
+		ch <- prometheus.MustNewConstMetric(pullRequestsDesc, prometheus.GaugeValue, float64(l.Reviewed), label, l.Window, "reviewed")
+		ch <- prometheus.MustNewConstMetric(pullRequestsDesc, prometheus.GaugeValue, float64(l.Merged), label, l.Window, "merged")
+
+		if l.Reviewed > 0 {
+			ch <- prometheus.MustNewConstMetric(firstReviewDesc, prometheus.GaugeValue, l.MedianTimeToFirstReview.Seconds(), label, l.Window, "0.5")
+			ch <- prometheus.MustNewConstMetric(firstReviewDesc, prometheus.GaugeValue, l.P90TimeToFirstReview.Seconds(), label, l.Window, "0.9")
+		}
+		if l.Merged > 0 {
+			ch <- prometheus.MustNewConstMetric(mergeDesc, prometheus.GaugeValue, l.MedianTimeToMerge.Seconds(), label, l.Window, "0.5")
+			ch <- prometheus.MustNewConstMetric(mergeDesc, prometheus.GaugeValue, l.P90TimeToMerge.Seconds(), label, l.Window, "0.9")
+		}
+	}
+}
+
+func (reviewLatencyCollector) Collect(ch chan<- prometheus.Metric) {
+	// The database is not yet initialized during installation.
+	if db.Repos == nil {
+		return
+	}
+
+	repos, owners, err := db.GetAllReviewLatencies(time.Now())
+	if err != nil {
+		log.Error("Failed to get review latencies: %v", err)
+		return
+	}
+
+	for _, r := range repos {
+		collectReviewLatencies(ch, repoReviewPullRequestsDesc, repoReviewFirstReviewDesc, repoReviewMergeDesc, r.FullName, r.Latencies)
+	}
+	for _, o := range owners {
+		collectReviewLatencies(ch, ownerReviewPullRequestsDesc, ownerReviewFirstReviewDesc, ownerReviewMergeDesc, o.OwnerName, o.Latencies)
+	}
+}
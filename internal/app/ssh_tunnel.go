@@ -0,0 +1,73 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strings"
+
+	"gopkg.in/macaron.v1"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/ssh"
+)
+
+// SSHTunnelProtocol is the protocol name in the "Upgrade" header to tunnel SSH
+// connections through the web port.
+const SSHTunnelProtocol = "ssh"
+
+// bufferedConn is a net.Conn that reads data buffered by the HTTP server before
+// reading from the underlying connection.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+// SSHTunnel upgrades the HTTP connection to a raw SSH connection, which is then
+// served by the builtin SSH server with the same authentication and permission
+// checks.
+func SSHTunnel() macaron.Handler {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if conf.SSH.Disabled || !conf.SSH.EnableTunnel {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if !strings.EqualFold(r.Header.Get("Upgrade"), SSHTunnelProtocol) {
+			w.Header().Set("Upgrade", SSHTunnelProtocol)
+			w.WriteHeader(http.StatusUpgradeRequired)
+			return
+		}
+
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			log.Error("SSH tunnel: response writer does not support hijacking")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		conn, rw, err := hijacker.Hijack()
+		if err != nil {
+			log.Error("SSH tunnel: failed to hijack connection: %v", err)
+			return
+		}
+
+		_, err = conn.Write([]byte("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: " + SSHTunnelProtocol + "\r\n\r\n"))
+		if err != nil {
+			log.Error("SSH tunnel: failed to write response: %v", err)
+			_ = conn.Close()
+			return
+		}
+
+		log.Trace("SSH tunnel: Connection from %s", conn.RemoteAddr())
+		ssh.ServeTunnel(&bufferedConn{Conn: conn, r: rw.Reader})
+	}
+}
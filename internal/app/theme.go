@@ -0,0 +1,30 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"net/http"
+
+	"gopkg.in/macaron.v1"
+
+	"gogs.io/gogs/internal/theme"
+)
+
+// ThemeAsset serves assets of theme packs. URLs of stylesheets contain versions
+// of the files, so that browsers can cache them until files are changed.
+func ThemeAsset() macaron.Handler {
+	return func(c *macaron.Context) {
+		p, ok := theme.AssetPath(c.Params(":name"), c.Params("*"))
+		if !ok {
+			c.Status(http.StatusNotFound)
+			return
+		}
+
+		if c.Query("v") != "" {
+			c.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		}
+		http.ServeFile(c.Resp, c.Req.Request, p)
+	}
+}
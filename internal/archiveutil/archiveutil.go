@@ -0,0 +1,181 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package archiveutil provides utilities for extracting archives.
+package archiveutil
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrUnsupportedFormat is returned when the format of the archive is not
+	// supported.
+	ErrUnsupportedFormat = errors.New("unsupported archive format")
+	// ErrTooLarge is returned when the total size of extracted files exceeds the
+	// limit.
+	ErrTooLarge = errors.New("archive is too large")
+)
+
+// IsSupported returns true if the format of the archive with given file name
+// is supported, based on its extension.
+func IsSupported(name string) bool {
+	name = strings.ToLower(name)
+	for _, ext := range []string{".zip", ".tar", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// Extract extracts the archive with given file name from r of given size to
+// the directory dst. The format is detected by the extension of the name, see
+// IsSupported. Only regular files and directories are extracted, and anything
+// under a ".git" directory is skipped. It returns ErrTooLarge when the total
+// size of extracted files exceeds maxSize bytes.
+func Extract(name string, r io.ReaderAt, size int64, dst string, maxSize int64) error {
+	x := &extractor{
+		dst:       dst,
+		remaining: maxSize,
+	}
+
+	name = strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return x.zip(r, size)
+	case strings.HasSuffix(name, ".tar"):
+		return x.tar(io.NewSectionReader(r, 0, size))
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		gr, err := gzip.NewReader(io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return errors.Wrap(err, "new gzip reader")
+		}
+		defer func() { _ = gr.Close() }()
+		return x.tar(gr)
+	}
+	return ErrUnsupportedFormat
+}
+
+type extractor struct {
+	dst       string
+	remaining int64
+}
+
+// target returns the path in the destination directory for given name of an
+// entry, or an empty string if the entry should be skipped.
+func (x *extractor) target(name string) string {
+	// Cleaning as an absolute path makes sure the entry never escapes the
+	// destination directory.
+	name = strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(name, `\`, "/")), "/")
+	if name == "" {
+		return ""
+	}
+	for _, elem := range strings.Split(name, "/") {
+		if strings.EqualFold(elem, ".git") {
+			return ""
+		}
+	}
+	return filepath.Join(x.dst, filepath.FromSlash(name))
+}
+
+func (x *extractor) writeFile(target string, mode os.FileMode, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return errors.Wrap(err, "create parent directory")
+	}
+
+	perm := os.FileMode(0o644)
+	if mode&0o111 != 0 {
+		perm = 0o755
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return errors.Wrap(err, "create file")
+	}
+	defer func() { _ = f.Close() }()
+
+	n, err := io.CopyN(f, r, x.remaining+1)
+	if err != nil && err != io.EOF {
+		return errors.Wrap(err, "write file")
+	}
+	x.remaining -= n
+	if x.remaining < 0 {
+		return ErrTooLarge
+	}
+	return nil
+}
+
+func (x *extractor) zip(r io.ReaderAt, size int64) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return errors.Wrap(err, "new zip reader")
+	}
+
+	for _, f := range zr.File {
+		target := x.target(f.Name)
+		if target == "" {
+			continue
+		}
+
+		mode := f.Mode()
+		if mode.IsDir() {
+			if err = os.MkdirAll(target, os.ModePerm); err != nil {
+				return errors.Wrap(err, "create directory")
+			}
+			continue
+		} else if !mode.IsRegular() {
+			continue
+		}
+
+		err = func() error {
+			rc, err := f.Open()
+			if err != nil {
+				return errors.Wrapf(err, "open %q", f.Name)
+			}
+			defer func() { _ = rc.Close() }()
+			return x.writeFile(target, mode, rc)
+		}()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (x *extractor) tar(r io.Reader) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.Wrap(err, "read next entry")
+		}
+
+		target := x.target(hdr.Name)
+		if target == "" {
+			continue
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err = os.MkdirAll(target, os.ModePerm); err != nil {
+				return errors.Wrap(err, "create directory")
+			}
+		case tar.TypeReg:
+			if err = x.writeFile(target, hdr.FileInfo().Mode(), tr); err != nil {
+				return err
+			}
+		}
+	}
+}
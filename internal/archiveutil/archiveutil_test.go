@@ -0,0 +1,118 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package archiveutil
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFiles = []struct {
+	name    string
+	content string
+}{
+	{name: "README.md", content: "# Hello"},
+	{name: "src/main.go", content: "package main"},
+	{name: ".git/config", content: "[core]"},
+	{name: "../../escape.txt", content: "escaped"},
+}
+
+func newTestZip(t *testing.T) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range testFiles {
+		w, err := zw.Create(f.name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(f.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func newTestTarGz(t *testing.T) []byte {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, f := range testFiles {
+		err := tw.WriteHeader(&tar.Header{
+			Name:     f.name,
+			Mode:     0o644,
+			Size:     int64(len(f.content)),
+			Typeflag: tar.TypeReg,
+		})
+		require.NoError(t, err)
+		_, err = tw.Write([]byte(f.content))
+		require.NoError(t, err)
+	}
+	err := tw.WriteHeader(&tar.Header{
+		Name:     "link",
+		Linkname: "/etc/passwd",
+		Typeflag: tar.TypeSymlink,
+	})
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+func TestIsSupported(t *testing.T) {
+	assert.True(t, IsSupported("project.zip"))
+	assert.True(t, IsSupported("project.TAR"))
+	assert.True(t, IsSupported("project.tar.gz"))
+	assert.True(t, IsSupported("project.tgz"))
+	assert.False(t, IsSupported("project.rar"))
+}
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "project.zip", data: newTestZip(t)},
+		{name: "project.tar.gz", data: newTestTarGz(t)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dst := filepath.Join(t.TempDir(), "a", "b")
+			err := Extract(test.name, bytes.NewReader(test.data), int64(len(test.data)), dst, 1024)
+			require.NoError(t, err)
+
+			p, err := os.ReadFile(filepath.Join(dst, "README.md"))
+			require.NoError(t, err)
+			assert.Equal(t, "# Hello", string(p))
+
+			p, err = os.ReadFile(filepath.Join(dst, "src", "main.go"))
+			require.NoError(t, err)
+			assert.Equal(t, "package main", string(p))
+
+			// Entries escaping the destination are extracted inside of it.
+			assert.FileExists(t, filepath.Join(dst, "escape.txt"))
+			assert.NoFileExists(t, filepath.Join(dst, "..", "..", "escape.txt"))
+
+			assert.NoDirExists(t, filepath.Join(dst, ".git"))
+			assert.NoFileExists(t, filepath.Join(dst, "link"))
+		})
+	}
+
+	t.Run("too large", func(t *testing.T) {
+		data := newTestZip(t)
+		err := Extract("project.zip", bytes.NewReader(data), int64(len(data)), t.TempDir(), 10)
+		assert.Equal(t, ErrTooLarge, err)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		err := Extract("project.rar", bytes.NewReader(nil), 0, t.TempDir(), 10)
+		assert.Equal(t, ErrUnsupportedFormat, err)
+	})
+}
@@ -70,6 +70,9 @@ type ExternalAccount struct {
 	Website string
 	// Whether the user should be prompted as a site admin.
 	Admin bool
+	// Additional attributes of the account requested via AttributesProvider,
+	// keyed by attribute names.
+	Attributes map[string]string
 }
 
 // Provider defines an authenticate provider which provides ability to authentication against
@@ -88,3 +91,13 @@ type Provider interface {
 	// SkipTLSVerify returns true if the authenticate provider is configured to skip TLS verify.
 	SkipTLSVerify() bool
 }
+
+// AttributesProvider is an optional interface of authenticate providers that
+// are able to query additional attributes of external accounts, e.g. to sync
+// custom profile fields.
+type AttributesProvider interface {
+	// AuthenticateWithAttributes performs authentication like Authenticate and
+	// also queries given attributes of the external account. Missing attributes
+	// are not included in the returned account.
+	AuthenticateWithAttributes(login, password string, attributes []string) (*ExternalAccount, error)
+}
@@ -191,16 +191,17 @@ func bindUser(l *ldap.Conn, userDN, passwd string) error {
 }
 
 // searchEntry searches an LDAP source if an entry (name, passwd) is valid and in the specific filter.
-func (c *Config) searchEntry(name, passwd string, directBind bool) (string, string, string, string, bool, bool) {
+// Values of extra attributes of the entry are returned when requested.
+func (c *Config) searchEntry(name, passwd string, directBind bool, extraAttrs []string) (string, string, string, string, bool, map[string]string, bool) {
 	// See https://tools.ietf.org/search/rfc4513#section-5.1.2
 	if passwd == "" {
 		log.Trace("authentication failed for '%s' with empty password", name)
-		return "", "", "", "", false, false
+		return "", "", "", "", false, nil, false
 	}
 	l, err := dial(c)
 	if err != nil {
 		log.Error("LDAP connect failed for '%s': %v", c.Host, err)
-		return "", "", "", "", false, false
+		return "", "", "", "", false, nil, false
 	}
 	defer l.Close()
 
@@ -211,7 +212,7 @@ func (c *Config) searchEntry(name, passwd string, directBind bool) (string, stri
 		var ok bool
 		userDN, ok = c.sanitizedUserDN(name)
 		if !ok {
-			return "", "", "", "", false, false
+			return "", "", "", "", false, nil, false
 		}
 	} else {
 		log.Trace("LDAP will use BindDN")
@@ -219,7 +220,7 @@ func (c *Config) searchEntry(name, passwd string, directBind bool) (string, stri
 		var found bool
 		userDN, found = c.findUserDN(l, name)
 		if !found {
-			return "", "", "", "", false, false
+			return "", "", "", "", false, nil, false
 		}
 	}
 
@@ -227,13 +228,13 @@ func (c *Config) searchEntry(name, passwd string, directBind bool) (string, stri
 		// binds user (checking password) before looking-up attributes in user context
 		err = bindUser(l, userDN, passwd)
 		if err != nil {
-			return "", "", "", "", false, false
+			return "", "", "", "", false, nil, false
 		}
 	}
 
 	userFilter, ok := c.sanitizedUserQuery(name)
 	if !ok {
-		return "", "", "", "", false, false
+		return "", "", "", "", false, nil, false
 	}
 
 	log.Trace("Fetching attributes %q, %q, %q, %q, %q with user filter %q and user DN %q",
@@ -241,12 +242,12 @@ func (c *Config) searchEntry(name, passwd string, directBind bool) (string, stri
 
 	search := ldap.NewSearchRequest(
 		userDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false, userFilter,
-		[]string{c.AttributeUsername, c.AttributeName, c.AttributeSurname, c.AttributeMail, c.UserUID},
+		append([]string{c.AttributeUsername, c.AttributeName, c.AttributeSurname, c.AttributeMail, c.UserUID}, extraAttrs...),
 		nil)
 	sr, err := l.Search(search)
 	if err != nil {
 		log.Error("LDAP: User search failed: %v", err)
-		return "", "", "", "", false, false
+		return "", "", "", "", false, nil, false
 	} else if len(sr.Entries) < 1 {
 		if directBind {
 			log.Trace("LDAP: User filter inhibited user login")
@@ -254,7 +255,7 @@ func (c *Config) searchEntry(name, passwd string, directBind bool) (string, stri
 			log.Trace("LDAP: User search failed: 0 entries")
 		}
 
-		return "", "", "", "", false, false
+		return "", "", "", "", false, nil, false
 	}
 
 	username := sr.Entries[0].GetAttributeValue(c.AttributeUsername)
@@ -263,15 +264,25 @@ func (c *Config) searchEntry(name, passwd string, directBind bool) (string, stri
 	mail := sr.Entries[0].GetAttributeValue(c.AttributeMail)
 	uid := sr.Entries[0].GetAttributeValue(c.UserUID)
 
+	var attrs map[string]string
+	if len(extraAttrs) > 0 {
+		attrs = make(map[string]string, len(extraAttrs))
+		for _, attr := range extraAttrs {
+			if v := sr.Entries[0].GetAttributeValue(attr); v != "" {
+				attrs[attr] = v
+			}
+		}
+	}
+
 	// Check group membership
 	if c.GroupEnabled {
 		groupFilter, ok := c.sanitizedGroupFilter(c.GroupFilter)
 		if !ok {
-			return "", "", "", "", false, false
+			return "", "", "", "", false, nil, false
 		}
 		groupDN, ok := c.sanitizedGroupDN(c.GroupDN)
 		if !ok {
-			return "", "", "", "", false, false
+			return "", "", "", "", false, nil, false
 		}
 
 		log.Trace("LDAP: Fetching groups '%v' with filter '%s' and base '%s'", c.GroupMemberUID, groupFilter, groupDN)
@@ -283,10 +294,10 @@ func (c *Config) searchEntry(name, passwd string, directBind bool) (string, stri
 		srg, err := l.Search(groupSearch)
 		if err != nil {
 			log.Error("LDAP: Group search failed: %v", err)
-			return "", "", "", "", false, false
+			return "", "", "", "", false, nil, false
 		} else if len(srg.Entries) < 1 {
 			log.Trace("LDAP: Group search returned no entries")
-			return "", "", "", "", false, false
+			return "", "", "", "", false, nil, false
 		}
 
 		isMember := false
@@ -310,7 +321,7 @@ func (c *Config) searchEntry(name, passwd string, directBind bool) (string, stri
 
 		if !isMember {
 			log.Trace("LDAP: Group membership test failed [username: %s, group_member_uid: %s, user_uid: %s", username, c.GroupMemberUID, uid)
-			return "", "", "", "", false, false
+			return "", "", "", "", false, nil, false
 		}
 	}
 
@@ -336,9 +347,9 @@ func (c *Config) searchEntry(name, passwd string, directBind bool) (string, stri
 		// binds user (checking password) after looking-up attributes in BindDN context
 		err = bindUser(l, userDN, passwd)
 		if err != nil {
-			return "", "", "", "", false, false
+			return "", "", "", "", false, nil, false
 		}
 	}
 
-	return username, firstname, surname, mail, isAdmin, true
+	return username, firstname, surname, mail, isAdmin, attrs, true
 }
@@ -24,10 +24,18 @@ func NewProvider(directBind bool, cfg *Config) auth.Provider {
 	}
 }
 
+var _ auth.AttributesProvider = (*Provider)(nil)
+
 // Authenticate queries if login/password is valid against the LDAP directory pool,
 // and returns queried information when succeeded.
 func (p *Provider) Authenticate(login, password string) (*auth.ExternalAccount, error) {
-	username, fn, sn, email, isAdmin, succeed := p.config.searchEntry(login, password, p.directBind)
+	return p.AuthenticateWithAttributes(login, password, nil)
+}
+
+// AuthenticateWithAttributes is like Authenticate but also queries given
+// attributes of the LDAP entry.
+func (p *Provider) AuthenticateWithAttributes(login, password string, attributes []string) (*auth.ExternalAccount, error) {
+	username, fn, sn, email, isAdmin, attrs, succeed := p.config.searchEntry(login, password, p.directBind, attributes)
 	if !succeed {
 		return nil, auth.ErrBadCredentials{Args: map[string]interface{}{"login": login}}
 	}
@@ -53,11 +61,12 @@ func (p *Provider) Authenticate(login, password string) (*auth.ExternalAccount,
 	}
 
 	return &auth.ExternalAccount{
-		Login:    login,
-		Name:     username,
-		FullName: composeFullName(fn, sn, username),
-		Email:    email,
-		Admin:    isAdmin,
+		Login:      login,
+		Name:       username,
+		FullName:   composeFullName(fn, sn, username),
+		Email:      email,
+		Admin:      isAdmin,
+		Attributes: attrs,
 	}, nil
 }
 
@@ -22,6 +22,7 @@ import (
 
 	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/repoutil"
 )
 
 var Backup = cli.Command{
@@ -127,6 +128,9 @@ func runBackup(c *cli.Context) error {
 	if !c.Bool("exclude-repos") && !c.Bool("database-only") {
 		reposDump := filepath.Join(rootDir, "repositories.zip")
 		log.Info("Dumping repositories in %q", conf.Repository.Root)
+		if len(conf.Repository.ShardRoots) > 0 {
+			log.Warn("Repositories in shard roots %q are not included and need to be backed up separately", conf.Repository.ShardRoots)
+		}
 		if c.Bool("exclude-mirror-repos") {
 			repos, err := db.GetNonMirrorRepositories()
 			if err != nil {
@@ -138,6 +142,9 @@ func runBackup(c *cli.Context) error {
 			}
 			baseDir := filepath.Base(conf.Repository.Root)
 			for _, r := range repos {
+				if repoutil.ShardRoot(r.StorageShard) != conf.Repository.Root {
+					continue
+				}
 				name := r.FullName() + ".git"
 				if err := reposZip.AddDir(filepath.Join(baseDir, name), filepath.Join(conf.Repository.Root, name)); err != nil {
 					log.Fatal("Failed to add %q: %v", name, err)
@@ -0,0 +1,642 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+
+	"gogs.io/gogs/client"
+	"gogs.io/gogs/internal/app"
+)
+
+// cliClientID is the client ID used by the command-line client in the device
+// authorization flow.
+const cliClientID = "gogs-cli"
+
+var (
+	CLI = cli.Command{
+		Name:  "cli",
+		Usage: "Interact with a remote Gogs instance on command line",
+		Description: `Work with issues, pull requests and releases of a remote Gogs instance,
+authenticating via the device authorization flow with "gogs cli login".
+
+The instance is chosen by the "--host" flag, the GOGS_HOST environment variable,
+or the "origin" remote of the current Git repository. The repository is chosen
+by the "--repo" flag or the "origin" remote of the current Git repository.`,
+		Subcommands: []cli.Command{
+			subcmdCLILogin,
+			subcmdCLILogout,
+			{
+				Name:  "issue",
+				Usage: "Work with issues",
+				Subcommands: []cli.Command{
+					subcmdCLIIssueList,
+					subcmdCLIIssueCreate,
+				},
+			},
+			{
+				Name:  "pr",
+				Usage: "Work with pull requests",
+				Subcommands: []cli.Command{
+					subcmdCLIPRCheckout,
+					subcmdCLIPRCreate,
+				},
+			},
+			{
+				Name:  "release",
+				Usage: "Work with releases",
+				Subcommands: []cli.Command{
+					subcmdCLIReleaseUpload,
+				},
+			},
+			subcmdCLISSHTunnel,
+		},
+	}
+
+	subcmdCLILogin = cli.Command{
+		Name:   "login",
+		Usage:  "Authenticate with a Gogs instance",
+		Action: runCLILogin,
+		Flags: []cli.Flag{
+			stringFlag("host", "", "External URL of the Gogs instance, e.g. https://try.gogs.io"),
+		},
+	}
+
+	subcmdCLILogout = cli.Command{
+		Name:   "logout",
+		Usage:  "Remove the stored credentials of a Gogs instance",
+		Action: runCLILogout,
+		Flags: []cli.Flag{
+			stringFlag("host", "", "External URL of the Gogs instance"),
+		},
+	}
+
+	subcmdCLIIssueList = cli.Command{
+		Name:   "list",
+		Usage:  "List issues of the repository",
+		Action: runCLIIssueList,
+		Flags: append(cliRepoFlags(),
+			stringFlag("state", "open", "Issue state, either 'open' or 'closed'"),
+			intFlag("page", 1, "Page number of results"),
+		),
+	}
+
+	subcmdCLIIssueCreate = cli.Command{
+		Name:   "create",
+		Usage:  "Create an issue in the repository",
+		Action: runCLIIssueCreate,
+		Flags: append(cliRepoFlags(),
+			stringFlag("title, t", "", "Issue title"),
+			stringFlag("body, b", "", "Issue body"),
+		),
+	}
+
+	subcmdCLIPRCheckout = cli.Command{
+		Name:      "checkout",
+		Usage:     "Check out the head of a pull request to a local branch",
+		ArgsUsage: "<index>",
+		Action:    runCLIPRCheckout,
+		Flags: []cli.Flag{
+			stringFlag("remote", "origin", "Git remote of the base repository"),
+		},
+	}
+
+	subcmdCLIPRCreate = cli.Command{
+		Name:   "create",
+		Usage:  "Print the URL to create a pull request from the current branch",
+		Action: runCLIPRCreate,
+		Flags: append(cliRepoFlags(),
+			stringFlag("base", "", "Base branch, default to the default branch of the repository"),
+			stringFlag("head", "", "Head branch, default to the current branch"),
+		),
+	}
+
+	subcmdCLIReleaseUpload = cli.Command{
+		Name:      "upload",
+		Usage:     "Upload files as attachments of a release",
+		ArgsUsage: "<tag> <file>...",
+		Action:    runCLIReleaseUpload,
+		Flags:     cliRepoFlags(),
+	}
+
+	subcmdCLISSHTunnel = cli.Command{
+		Name:  "ssh-tunnel",
+		Usage: "Tunnel SSH connection through the web port of a Gogs instance",
+		Description: `Connect stdin and stdout to the SSH server of the instance through its web
+port, for use as the SSH ProxyCommand when the SSH port is blocked, e.g. in
+"~/.ssh/config":
+
+	Host try.gogs.io
+		ProxyCommand gogs cli ssh-tunnel --host https://%h`,
+		ArgsUsage: "[host]",
+		Action:    runCLISSHTunnel,
+		Flags: []cli.Flag{
+			stringFlag("host", "", "External URL of the Gogs instance"),
+		},
+	}
+)
+
+func cliRepoFlags() []cli.Flag {
+	return []cli.Flag{
+		stringFlag("host", "", "External URL of the Gogs instance"),
+		stringFlag("repo, R", "", "Repository in the format of '<owner>/<name>'"),
+		stringFlag("remote", "origin", "Git remote to infer the host and repository from"),
+	}
+}
+
+// cliConfig is the configuration of the command-line client, which is stored
+// as JSON in the user's configuration directory.
+type cliConfig struct {
+	// Hosts is the set of logged in instances, keyed by their external URLs.
+	Hosts map[string]*cliHost `json:"hosts"`
+}
+
+type cliHost struct {
+	Username string `json:"username"`
+	Token    string `json:"token"`
+}
+
+func cliConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", errors.Wrap(err, "get user config directory")
+	}
+	return filepath.Join(dir, "gogs", "cli.json"), nil
+}
+
+func loadCLIConfig() (*cliConfig, error) {
+	cfg := &cliConfig{
+		Hosts: make(map[string]*cliHost),
+	}
+
+	path, err := cliConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, errors.Wrap(err, "read config")
+	}
+
+	err = json.Unmarshal(data, cfg)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse config %q", path)
+	}
+	if cfg.Hosts == nil {
+		cfg.Hosts = make(map[string]*cliHost)
+	}
+	return cfg, nil
+}
+
+func (cfg *cliConfig) save() error {
+	path, err := cliConfigPath()
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return errors.Wrap(err, "create config directory")
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshal config")
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// normalizeHost returns the external URL without the trailing slash, and
+// defaults to use HTTPS when no scheme is given.
+func normalizeHost(host string) string {
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	return strings.TrimSuffix(host, "/")
+}
+
+// parseRemoteURL parses the hostname, owner and name of the repository from the
+// URL of a Git remote, supporting HTTP(S), SSH and SCP-like syntax.
+func parseRemoteURL(remote string) (hostname, owner, name string, err error) {
+	var path string
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return "", "", "", errors.Wrap(err, "parse URL")
+		}
+		hostname = u.Hostname()
+		path = u.Path
+	} else if i := strings.Index(remote, ":"); i > 0 {
+		// SCP-like syntax, e.g. git@try.gogs.io:unknwon/gogs.git
+		hostname = remote[:i]
+		if j := strings.Index(hostname, "@"); j >= 0 {
+			hostname = hostname[j+1:]
+		}
+		path = remote[i+1:]
+	} else {
+		return "", "", "", errors.Errorf("unrecognized remote URL %q", remote)
+	}
+
+	fields := strings.Split(strings.Trim(path, "/"), "/")
+	if len(fields) < 2 {
+		return "", "", "", errors.Errorf("no repository in remote URL %q", remote)
+	}
+	owner = fields[len(fields)-2]
+	name = strings.TrimSuffix(fields[len(fields)-1], ".git")
+	return hostname, owner, name, nil
+}
+
+func gitOutput(args ...string) (string, error) {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", errors.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", errors.Wrapf(err, "git %s", strings.Join(args, " "))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func gitRun(args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return errors.Wrapf(cmd.Run(), "git %s", strings.Join(args, " "))
+}
+
+// cliRepoContext is the context of a subcommand that works with a repository.
+type cliRepoContext struct {
+	host   string
+	client *client.Client
+	owner  string
+	name   string
+}
+
+// newCLIRepoContext resolves the instance and repository to work with, and
+// returns an authenticated client.
+func newCLIRepoContext(c *cli.Context) (*cliRepoContext, error) {
+	cfg, err := loadCLIConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var remoteHostname, owner, name string
+	if c.String("repo") != "" {
+		fields := strings.SplitN(c.String("repo"), "/", 2)
+		if len(fields) != 2 || fields[0] == "" || fields[1] == "" {
+			return nil, errors.Errorf("invalid repository %q, expect '<owner>/<name>'", c.String("repo"))
+		}
+		owner, name = fields[0], fields[1]
+	} else {
+		remote, err := gitOutput("remote", "get-url", c.String("remote"))
+		if err != nil {
+			return nil, errors.Wrap(err, "infer repository (use --repo to specify)")
+		}
+		remoteHostname, owner, name, err = parseRemoteURL(remote)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	host := c.String("host")
+	if host == "" {
+		host = os.Getenv("GOGS_HOST")
+	}
+	if host == "" {
+		for h := range cfg.Hosts {
+			u, err := url.Parse(h)
+			if err == nil && u.Hostname() == remoteHostname {
+				host = h
+				break
+			}
+		}
+	}
+	if host == "" && len(cfg.Hosts) == 1 {
+		for h := range cfg.Hosts {
+			host = h
+		}
+	}
+	if host == "" {
+		return nil, errors.New("cannot determine the Gogs instance, use --host to specify")
+	}
+	host = normalizeHost(host)
+
+	token := os.Getenv("GOGS_TOKEN")
+	if token == "" {
+		h, ok := cfg.Hosts[host]
+		if !ok {
+			return nil, errors.Errorf("not logged in to %s, run 'gogs cli login --host %s' first", host, host)
+		}
+		token = h.Token
+	}
+
+	return &cliRepoContext{
+		host:   host,
+		client: client.NewClient(host, token),
+		owner:  owner,
+		name:   name,
+	}, nil
+}
+
+func runCLILogin(c *cli.Context) error {
+	host := c.String("host")
+	if host == "" {
+		host = c.Args().First()
+	}
+	if host == "" {
+		return errors.New("the external URL of the Gogs instance is not specified, use --host to specify")
+	}
+	host = normalizeHost(host)
+
+	ctx := context.Background()
+	code, err := client.NewClient(host, "").RequestDeviceCode(ctx, cliClientID)
+	if err != nil {
+		return errors.Wrap(err, "request device code")
+	}
+
+	fmt.Printf("First copy your one-time code: %s\n", code.UserCode)
+	fmt.Printf("Then open the following URL in the browser to authorize:\n\n\t%s\n\n", code.VerificationURIComplete)
+	fmt.Println("Waiting for authorization...")
+
+	token, err := client.NewClient(host, "").PollAccessToken(ctx, cliClientID, code)
+	if err != nil {
+		return err
+	}
+
+	user, err := client.NewClient(host, token).GetAuthenticatedUser(ctx)
+	if err != nil {
+		return errors.Wrap(err, "get authenticated user")
+	}
+
+	cfg, err := loadCLIConfig()
+	if err != nil {
+		return err
+	}
+	cfg.Hosts[host] = &cliHost{
+		Username: user.Username,
+		Token:    token,
+	}
+	err = cfg.save()
+	if err != nil {
+		return errors.Wrap(err, "save config")
+	}
+
+	fmt.Printf("Logged in to %s as %s\n", host, user.Username)
+	return nil
+}
+
+func runCLILogout(c *cli.Context) error {
+	cfg, err := loadCLIConfig()
+	if err != nil {
+		return err
+	}
+
+	host := c.String("host")
+	if host == "" && len(cfg.Hosts) == 1 {
+		for h := range cfg.Hosts {
+			host = h
+		}
+	}
+	if host == "" {
+		return errors.New("the external URL of the Gogs instance is not specified, use --host to specify")
+	}
+	host = normalizeHost(host)
+
+	if _, ok := cfg.Hosts[host]; !ok {
+		return errors.Errorf("not logged in to %s", host)
+	}
+	delete(cfg.Hosts, host)
+	err = cfg.save()
+	if err != nil {
+		return errors.Wrap(err, "save config")
+	}
+
+	fmt.Printf("Logged out of %s\n", host)
+	return nil
+}
+
+func runCLIIssueList(c *cli.Context) error {
+	rc, err := newCLIRepoContext(c)
+	if err != nil {
+		return err
+	}
+
+	issues, err := rc.client.ListIssues(context.Background(), rc.owner, rc.name, c.String("state"), c.Int("page"))
+	if err != nil {
+		return errors.Wrap(err, "list issues")
+	}
+	if len(issues) == 0 {
+		fmt.Println("No issues found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, issue := range issues {
+		labels := make([]string, 0, len(issue.Labels))
+		for _, label := range issue.Labels {
+			labels = append(labels, label.Name)
+		}
+		_, _ = fmt.Fprintf(w, "#%d\t%s\t%s\t%s\n", issue.Number, issue.State, issue.Title, strings.Join(labels, ", "))
+	}
+	return w.Flush()
+}
+
+func runCLIIssueCreate(c *cli.Context) error {
+	if c.String("title") == "" {
+		return errors.New("issue title is not specified")
+	}
+
+	rc, err := newCLIRepoContext(c)
+	if err != nil {
+		return err
+	}
+
+	issue, err := rc.client.CreateIssue(context.Background(), rc.owner, rc.name, client.CreateIssueOption{
+		Title: c.String("title"),
+		Body:  c.String("body"),
+	})
+	if err != nil {
+		return errors.Wrap(err, "create issue")
+	}
+
+	fmt.Printf("Issue #%d has been created: %s/%s/%s/issues/%d\n", issue.Number, rc.host, rc.owner, rc.name, issue.Number)
+	return nil
+}
+
+func runCLIPRCheckout(c *cli.Context) error {
+	index, err := strconv.ParseInt(c.Args().First(), 10, 64)
+	if err != nil || index <= 0 {
+		return errors.Errorf("invalid pull request index %q", c.Args().First())
+	}
+
+	// Gogs keeps the head of every pull request in the base repository, so no
+	// API call is needed.
+	err = gitRun("fetch", c.String("remote"), fmt.Sprintf("refs/pull/%d/head", index))
+	if err != nil {
+		return err
+	}
+	return gitRun("checkout", "-B", fmt.Sprintf("pull/%d", index), "FETCH_HEAD")
+}
+
+func runCLIPRCreate(c *cli.Context) error {
+	rc, err := newCLIRepoContext(c)
+	if err != nil {
+		return err
+	}
+
+	head := c.String("head")
+	if head == "" {
+		head, err = gitOutput("rev-parse", "--abbrev-ref", "HEAD")
+		if err != nil {
+			return errors.Wrap(err, "get current branch (use --head to specify)")
+		}
+	}
+
+	base := c.String("base")
+	if base == "" {
+		repo, err := rc.client.GetRepo(context.Background(), rc.owner, rc.name)
+		if err != nil {
+			return errors.Wrap(err, "get repository")
+		}
+		base = repo.DefaultBranch
+	}
+	if base == head {
+		return errors.Errorf("head branch %q is the same as the base branch", head)
+	}
+
+	fmt.Printf("Open the following URL in the browser to create the pull request:\n\n\t%s/%s/%s/compare/%s...%s\n\n",
+		rc.host, rc.owner, rc.name, url.PathEscape(base), url.PathEscape(head))
+	return nil
+}
+
+func runCLIReleaseUpload(c *cli.Context) error {
+	if c.NArg() < 2 {
+		return errors.New("tag name and at least one file are required")
+	}
+	tag := c.Args().First()
+
+	rc, err := newCLIRepoContext(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	releases, err := rc.client.ListReleases(ctx, rc.owner, rc.name)
+	if err != nil {
+		return errors.Wrap(err, "list releases")
+	}
+	var release *client.Release
+	for _, r := range releases {
+		if r.TagName == tag {
+			release = r
+			break
+		}
+	}
+	if release == nil {
+		return errors.Errorf("release with tag %q does not exist", tag)
+	}
+
+	for _, name := range c.Args().Tail() {
+		f, err := os.Open(name)
+		if err != nil {
+			return errors.Wrap(err, "open file")
+		}
+
+		attach, err := rc.client.UploadReleaseAttachment(ctx, rc.owner, rc.name, release.ID, filepath.Base(name), f)
+		_ = f.Close()
+		if err != nil {
+			return errors.Wrapf(err, "upload %q", name)
+		}
+		fmt.Printf("Uploaded %s: %s\n", attach.Name, attach.BrowserDownloadURL)
+	}
+	return nil
+}
+
+func runCLISSHTunnel(c *cli.Context) error {
+	host := c.String("host")
+	if host == "" {
+		host = c.Args().First()
+	}
+	if host == "" {
+		host = os.Getenv("GOGS_HOST")
+	}
+	if host == "" {
+		return errors.New("the external URL of the Gogs instance is not specified, use --host to specify")
+	}
+
+	u, err := url.Parse(normalizeHost(host) + "/-/ssh")
+	if err != nil {
+		return errors.Wrap(err, "parse URL")
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		port := "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+		addr = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	var conn net.Conn
+	if u.Scheme == "https" {
+		conn, err = tls.Dial("tcp", addr, &tls.Config{ServerName: u.Hostname()})
+	} else {
+		conn, err = net.Dial("tcp", addr)
+	}
+	if err != nil {
+		return errors.Wrap(err, "dial")
+	}
+	defer func() { _ = conn.Close() }()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return errors.Wrap(err, "new request")
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", app.SSHTunnelProtocol)
+	if err = req.Write(conn); err != nil {
+		return errors.Wrap(err, "write request")
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return errors.Wrap(err, "read response")
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return errors.Errorf("SSH tunnel is not available: %s", resp.Status)
+	}
+
+	go func() {
+		_, _ = io.Copy(conn, os.Stdin)
+		_ = conn.Close()
+	}()
+	_, err = io.Copy(os.Stdout, br)
+	if err != nil && !errors.Is(err, net.ErrClosed) {
+		return errors.Wrap(err, "copy")
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ package cmd
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net/url"
@@ -70,67 +71,38 @@ func runHookPreReceive(c *cli.Context) error {
 
 	isWiki := strings.Contains(os.Getenv(db.ENV_REPO_CUSTOM_HOOKS_PATH), ".wiki.git/")
 
+	var refs []db.RefPush
 	buf := bytes.NewBuffer(nil)
 	scanner := bufio.NewScanner(os.Stdin)
 	for scanner.Scan() {
 		buf.Write(scanner.Bytes())
 		buf.WriteByte('\n')
 
-		if isWiki {
-			continue
-		}
-
 		fields := bytes.Fields(scanner.Bytes())
 		if len(fields) != 3 {
 			continue
 		}
-		oldCommitID := string(fields[0])
-		newCommitID := string(fields[1])
-		branchName := git.RefShortName(string(fields[2]))
+		refs = append(refs, db.RefPush{
+			RefName:     string(fields[2]),
+			OldCommitID: string(fields[0]),
+			NewCommitID: string(fields[1]),
+		})
+	}
 
-		// Branch protection
+	if !isWiki {
 		repoID := com.StrTo(os.Getenv(db.ENV_REPO_ID)).MustInt64()
-		protectBranch, err := db.GetProtectBranchOfRepoByName(repoID, branchName)
+		repo, err := db.GetRepositoryByID(repoID)
 		if err != nil {
-			if db.IsErrBranchNotExist(err) {
-				continue
-			}
-			fail("Internal error", "GetProtectBranchOfRepoByName [repo_id: %d, branch: %s]: %v", repoID, branchName, err)
-		}
-		if !protectBranch.Protected {
-			continue
+			fail("Internal error", "GetRepositoryByID [repo_id: %d]: %v", repoID, err)
 		}
 
-		// Whitelist users can bypass require pull request check
-		bypassRequirePullRequest := false
-
-		// Check if user is in whitelist when enabled
 		userID := com.StrTo(os.Getenv(db.ENV_AUTH_USER_ID)).MustInt64()
-		if protectBranch.EnableWhitelist {
-			if !db.IsUserInProtectBranchWhitelist(repoID, userID, branchName) {
-				fail(fmt.Sprintf("Branch '%s' is protected and you are not in the push whitelist", branchName), "")
-			}
-
-			bypassRequirePullRequest = true
-		}
-
-		// Check if branch allows direct push
-		if !bypassRequirePullRequest && protectBranch.RequirePullRequest {
-			fail(fmt.Sprintf("Branch '%s' is protected and commits must be merged through pull request", branchName), "")
-		}
-
-		// check and deletion
-		if newCommitID == git.EmptyID {
-			fail(fmt.Sprintf("Branch '%s' is protected from deletion", branchName), "")
-		}
-
-		// Check force push
-		output, err := git.NewCommand("rev-list", "--max-count=1", oldCommitID, "^"+newCommitID).
-			RunInDir(db.RepoPath(os.Getenv(db.ENV_REPO_OWNER_NAME), os.Getenv(db.ENV_REPO_NAME)))
+		err = db.CheckPush(context.Background(), repo, userID, refs)
 		if err != nil {
-			fail("Internal error", "Failed to detect force push: %v", err)
-		} else if len(output) > 0 {
-			fail(fmt.Sprintf("Branch '%s' is protected from force push", branchName), "")
+			if db.IsErrPushRejected(err) {
+				fail(err.Error(), "")
+			}
+			fail("Internal error", "Check push [repo_id: %d]: %v", repoID, err)
 		}
 	}
 
@@ -198,6 +170,7 @@ func runHookPostReceive(c *cli.Context) error {
 	// Post-receive hook does more than just gather Git information,
 	// so we need to setup additional services for email notifications.
 	email.NewContext()
+	email.UseQueue(db.QueuedEmails)
 
 	isWiki := strings.Contains(os.Getenv(db.ENV_REPO_CUSTOM_HOOKS_PATH), ".wiki.git/")
 
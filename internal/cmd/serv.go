@@ -7,6 +7,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -19,6 +20,8 @@ import (
 
 	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/gitutil"
+	"gogs.io/gogs/internal/repoutil"
 )
 
 const (
@@ -159,6 +162,7 @@ func runServ(c *cli.Context) error {
 	}
 	ownerName := strings.ToLower(repoFields[0])
 	repoName := strings.TrimSuffix(strings.ToLower(repoFields[1]), ".git")
+	isWiki := strings.HasSuffix(repoName, ".wiki")
 	repoName = strings.TrimSuffix(repoName, ".wiki")
 
 	owner, err := db.GetUserByName(ownerName)
@@ -178,6 +182,11 @@ func runServ(c *cli.Context) error {
 	}
 	repo.Owner = owner
 
+	// Only the wiki of tracker-only repositories is accessible through Git.
+	if repo.IsTrackerOnly && !isWiki {
+		fail(_ACCESS_DENIED_MESSAGE, "Repository is tracker-only: %s/%s", owner.Name, repoName)
+	}
+
 	requestMode, ok := allowedCommands[verb]
 	if !ok {
 		fail("Unknown git command", "Unknown git command '%s'", verb)
@@ -196,7 +205,7 @@ func runServ(c *cli.Context) error {
 		fail("Invalid key ID", "Invalid key ID '%s': %v", c.Args()[0], err)
 	}
 
-	if requestMode == db.AccessModeWrite || repo.IsPrivate {
+	if requestMode == db.AccessModeWrite || repo.IsPrivate || conf.Security.EnableTenantIsolation {
 		// Check deploy key or user key.
 		if key.IsDeployKey() {
 			if key.Mode < requestMode {
@@ -208,11 +217,14 @@ func runServ(c *cli.Context) error {
 			if err != nil {
 				fail("Internal error", "Failed to get user by key ID '%d': %v", key.ID, err)
 			}
+			if user.ProhibitLogin {
+				fail("User is prohibited from logging in", "User '%s' is prohibited from logging in", user.Name)
+			}
 
 			mode := db.Perms.AccessMode(context.Background(), user.ID, repo.ID,
 				db.AccessModeOptions{
 					OwnerID: repo.OwnerID,
-					Private: repo.IsPrivate,
+					Private: repo.IsPrivate || db.IsTenantIsolated(user, owner),
 				},
 			)
 			if mode < requestMode {
@@ -255,7 +267,11 @@ func runServ(c *cli.Context) error {
 
 	var gitCmd *exec.Cmd
 	verbs := strings.Split(verb, " ")
-	if len(verbs) == 2 {
+	isUploadPack := verb == "git-upload-pack" || verb == "git upload-pack"
+	if isUploadPack {
+		args := append(gitutil.UploadPackConfigArgs(conf.Git.EnablePartialClone), "upload-pack", repoFullName)
+		gitCmd = exec.Command("git", args...)
+	} else if len(verbs) == 2 {
 		gitCmd = exec.Command(verbs[0], verbs[1], repoFullName)
 	} else {
 		gitCmd = exec.Command(verb, repoFullName)
@@ -270,13 +286,61 @@ func runServ(c *cli.Context) error {
 			RepoPath:  repo.RepoPath(),
 		})...)
 	}
-	gitCmd.Dir = conf.Repository.Root
+	gitCmd.Dir = repoutil.ShardRoot(repo.StorageShard)
 	gitCmd.Stdout = os.Stdout
 	gitCmd.Stdin = os.Stdin
 	gitCmd.Stderr = os.Stderr
-	if err = gitCmd.Run(); err != nil {
+
+	// Inspect Git access of fetches and pushes to collect statistics.
+	var (
+		startedAt     = time.Now()
+		requestWriter io.Writer
+		record        func(opts db.GitAccessOptions)
+	)
+	switch {
+	case isUploadPack:
+		inspector := gitutil.NewUploadPackInspector()
+		requestWriter = inspector.RequestWriter()
+		gitCmd.Stdout = io.MultiWriter(os.Stdout, inspector.ResponseWriter())
+		record = func(opts db.GitAccessOptions) { db.RecordUploadPack(opts, inspector) }
+	case requestMode == db.AccessModeWrite:
+		inspector := gitutil.NewReceivePackInspector()
+		requestWriter = inspector.RequestWriter()
+		record = func(opts db.GitAccessOptions) { db.RecordReceivePack(opts, inspector) }
+	default:
+		if err = gitCmd.Run(); err != nil {
+			fail("Internal error", "Failed to execute git command: %v", err)
+		}
+		return nil
+	}
+
+	// The standard input is copied by ourselves because the client may not close
+	// it until the command exits.
+	gitCmd.Stdin = nil
+	stdin, err := gitCmd.StdinPipe()
+	if err != nil {
+		fail("Internal error", "Failed to get standard input pipe: %v", err)
+	}
+	if err = gitCmd.Start(); err != nil {
+		fail("Internal error", "Failed to execute git command: %v", err)
+	}
+	go func() {
+		_, _ = io.Copy(stdin, io.TeeReader(os.Stdin, requestWriter))
+		_ = stdin.Close()
+	}()
+	if err = gitCmd.Wait(); err != nil {
 		fail("Internal error", "Failed to execute git command: %v", err)
 	}
 
+	// The environment variable "SSH_CLIENT" is in the form of
+	// "<client IP> <client port> <server port>".
+	record(db.GitAccessOptions{
+		Protocol:   "ssh",
+		Doer:       user,
+		RemoteAddr: strings.Split(os.Getenv("SSH_CLIENT"), " ")[0],
+		RepoID:     repo.ID,
+		RepoName:   owner.Name + "/" + repo.Name,
+		StartedAt:  startedAt,
+	})
 	return nil
 }
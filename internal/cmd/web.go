@@ -7,7 +7,6 @@ package cmd
 import (
 	"crypto/tls"
 	"fmt"
-	"io"
 	"net"
 	"net/http"
 	"net/http/fcgi"
@@ -15,7 +14,6 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/go-macaron/binding"
 	"github.com/go-macaron/cache"
 	"github.com/go-macaron/captcha"
 	"github.com/go-macaron/csrf"
@@ -23,19 +21,23 @@ import (
 	"github.com/go-macaron/i18n"
 	"github.com/go-macaron/session"
 	"github.com/go-macaron/toolbox"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"github.com/unknwon/com"
 	"github.com/urfave/cli"
 	"gopkg.in/macaron.v1"
 	log "unknwon.dev/clog/v2"
 
 	embedConf "gogs.io/gogs/conf"
+	"gogs.io/gogs/internal/antivirus"
 	"gogs.io/gogs/internal/app"
 	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/context"
 	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/downloadlimit"
 	"gogs.io/gogs/internal/form"
 	"gogs.io/gogs/internal/osutil"
+	"gogs.io/gogs/internal/pages"
+	"gogs.io/gogs/internal/repoutil"
 	"gogs.io/gogs/internal/route"
 	"gogs.io/gogs/internal/route/admin"
 	apiv1 "gogs.io/gogs/internal/route/api/v1"
@@ -43,8 +45,13 @@ import (
 	"gogs.io/gogs/internal/route/lfs"
 	"gogs.io/gogs/internal/route/org"
 	"gogs.io/gogs/internal/route/repo"
+	"gogs.io/gogs/internal/route/scim"
+	"gogs.io/gogs/internal/route/snippet"
 	"gogs.io/gogs/internal/route/user"
+	"gogs.io/gogs/internal/selfcheck"
+	"gogs.io/gogs/internal/strutil"
 	"gogs.io/gogs/internal/template"
+	"gogs.io/gogs/internal/theme"
 	"gogs.io/gogs/public"
 	"gogs.io/gogs/templates"
 )
@@ -69,7 +76,15 @@ func newMacaron() *macaron.Macaron {
 	}
 	m.Use(macaron.Recovery())
 	if conf.Server.EnableGzip {
-		m.Use(gzip.Gziper())
+		gziper := gzip.Gziper()
+		m.Use(func(c *macaron.Context) {
+			// Compressed responses are buffered, which holds back server-sent
+			// events.
+			if c.Req.Header.Get("Accept") == "text/event-stream" {
+				return
+			}
+			_, _ = c.Invoke(gziper)
+		})
 	}
 	if conf.Server.Protocol == "fcgi" {
 		m.SetURLPrefix(conf.Server.Subpath)
@@ -112,16 +127,29 @@ func newMacaron() *macaron.Macaron {
 		},
 	))
 
+	// Templates of the default theme pack have higher precedence over custom
+	// templates.
+	customTemplateDirs := []string{filepath.Join(conf.CustomDir(), "templates")}
+	if dir := theme.TemplatesDir(conf.UI.DefaultTheme); dir != "" {
+		customTemplateDirs = append(customTemplateDirs, dir)
+	}
 	renderOpt := macaron.RenderOptions{
 		Directory:         filepath.Join(conf.WorkDir(), "templates"),
-		AppendDirectories: []string{filepath.Join(conf.CustomDir(), "templates")},
+		AppendDirectories: customTemplateDirs,
 		Funcs:             template.FuncMap(),
 		IndentJSON:        macaron.Env != macaron.PROD,
 	}
 	if !conf.Server.LoadAssetsFromDisk {
-		renderOpt.TemplateFileSystem = templates.NewTemplateFileSystem("", renderOpt.AppendDirectories[0])
+		// Templates are compiled on every rendering in development mode, reload
+		// custom templates as well so that changes take effect without restarting.
+		if macaron.Env == macaron.DEV {
+			renderOpt.TemplateFileSystem = templates.NewReloadingTemplateFileSystem("", customTemplateDirs...)
+		} else {
+			renderOpt.TemplateFileSystem = templates.NewTemplateFileSystem("", customTemplateDirs...)
+		}
 	}
 	m.Use(macaron.Renderer(renderOpt))
+	m.Use(context.BodyLimiter())
 
 	localeNames, err := embedConf.FileNames("locale")
 	if err != nil {
@@ -151,30 +179,54 @@ func newMacaron() *macaron.Macaron {
 	m.Use(captcha.Captchaer(captcha.Options{
 		SubURL: conf.Server.Subpath,
 	}))
-	m.Use(toolbox.Toolboxer(m, toolbox.Options{
-		HealthCheckFuncs: []*toolbox.HealthCheckFuncDesc{
-			{
-				Desc: "Database connection",
-				Func: db.Ping,
-			},
+	healthChecks := []*toolbox.HealthCheckFuncDesc{
+		{
+			Desc: "Database connection",
+			Func: db.Ping,
 		},
+	}
+	for _, root := range repoutil.Roots() {
+		root := root
+		healthChecks = append(healthChecks, &toolbox.HealthCheckFuncDesc{
+			Desc: fmt.Sprintf("Repository root %q", root),
+			Func: func() error {
+				return repoutil.CheckRoot(root, db.RepositoryShardCheckTimeout)
+			},
+		})
+	}
+	m.Use(toolbox.Toolboxer(m, toolbox.Options{
+		HealthCheckFuncs: healthChecks,
 	}))
 	return m
 }
 
 func runWeb(c *cli.Context) error {
 	err := route.GlobalInit(c.String("config"))
-	if err != nil {
+	if problems, ok := err.(selfcheck.Problems); ok {
+		for _, p := range problems {
+			log.Error("Self-check: %s: %s", p.Name, p.Detail)
+		}
+		serve(c, newSafeModeMacaron(problems))
+		return nil
+	} else if err != nil {
 		log.Fatal("Failed to initialize application: %v", err)
 	}
 
+	if conf.Prometheus.Enabled {
+		prometheus.MustRegister(app.NewFetchStatsCollector(), antivirus.Collector())
+		prometheus.MustRegister(downloadlimit.Collectors()...)
+		if conf.Prometheus.EnableReviewLatency {
+			prometheus.MustRegister(app.NewReviewLatencyCollector())
+		}
+	}
+
 	m := newMacaron()
 
 	reqSignIn := context.Toggle(&context.ToggleOptions{SignInRequired: true})
 	ignSignIn := context.Toggle(&context.ToggleOptions{SignInRequired: conf.Auth.RequireSigninView})
 	reqSignOut := context.Toggle(&context.ToggleOptions{SignOutRequired: true})
 
-	bindIgnErr := binding.BindIgnErr
+	bindIgnErr := context.BindIgnErr
 
 	m.SetAutoHead(true)
 
@@ -187,7 +239,23 @@ func runWeb(c *cli.Context) error {
 			m.Get("/repos", route.ExploreRepos)
 			m.Get("/users", route.ExploreUsers)
 			m.Get("/organizations", route.ExploreOrganizations)
-		}, ignSignIn)
+		}, ignSignIn, route.MustAllowExplore)
+		m.Group("/snippets", func() {
+			m.Get("", snippet.MustAllowList, snippet.List)
+			m.Combo("/new", reqSignIn).Get(snippet.New).
+				Post(bindIgnErr(form.Snippet{}), snippet.NewPost)
+			m.Group("/:name", func() {
+				m.Get("", snippet.View)
+				m.Get("/revisions", snippet.Revisions)
+				m.Get("/revisions/:sha", snippet.View)
+				m.Get("/raw/:filename", snippet.Raw)
+				m.Group("", func() {
+					m.Combo("/edit").Get(snippet.Edit).
+						Post(bindIgnErr(form.Snippet{}), snippet.EditPost)
+					m.Post("/delete", snippet.Delete)
+				}, reqSignIn, snippet.MustBeWritable)
+			}, snippet.Assignment)
+		}, ignSignIn, snippet.MustEnable)
 		m.Combo("/install", route.InstallInit).Get(route.Install).
 			Post(bindIgnErr(form.Install{}), route.InstallPost)
 		m.Get("/^:type(issues|pulls)$", reqSignIn, user.Issues)
@@ -205,22 +273,32 @@ func runWeb(c *cli.Context) error {
 			m.Post("/sign_up", bindIgnErr(form.Register{}), user.SignUpPost)
 			m.Get("/reset_password", user.ResetPasswd)
 			m.Post("/reset_password", user.ResetPasswdPost)
+			m.Combo("/recover").Get(user.AdminRecovery).Post(user.AdminRecoveryPost)
 		}, reqSignOut)
 
+		m.Get("/user/events", reqSignIn, user.Events)
+
 		m.Group("/user/settings", func() {
 			m.Get("", user.Settings)
 			m.Post("", bindIgnErr(form.UpdateProfile{}), user.SettingsPost)
 			m.Combo("/avatar").Get(user.SettingsAvatar).
-				Post(binding.MultipartForm(form.Avatar{}), user.SettingsAvatarPost)
+				Post(bindIgnErr(form.Avatar{}), user.SettingsAvatarPost)
 			m.Post("/avatar/delete", user.SettingsDeleteAvatar)
 			m.Combo("/email").Get(user.SettingsEmails).
 				Post(bindIgnErr(form.AddEmail{}), user.SettingsEmailPost)
 			m.Post("/email/delete", user.DeleteEmail)
+			m.Post("/email/privacy", bindIgnErr(form.UpdateEmailPrivacy{}), user.SettingsEmailPrivacyPost)
+			m.Combo("/notifications").Get(user.SettingsNotifications).
+				Post(user.SettingsNotificationsPost)
+			m.Post("/notifications/schedule", user.SettingsNotificationSchedulePost)
 			m.Get("/password", user.SettingsPassword)
 			m.Post("/password", bindIgnErr(form.ChangePassword{}), user.SettingsPasswordPost)
 			m.Combo("/ssh").Get(user.SettingsSSHKeys).
 				Post(bindIgnErr(form.AddSSHKey{}), user.SettingsSSHKeysPost)
 			m.Post("/ssh/delete", user.DeleteSSHKey)
+			m.Combo("/gpg").Get(user.SettingsGPGKeys).
+				Post(bindIgnErr(form.AddGPGKey{}), user.SettingsGPGKeysPost)
+			m.Post("/gpg/delete", user.DeleteGPGKey)
 			m.Group("/security", func() {
 				m.Get("", user.SettingsSecurity)
 				m.Combo("/two_factor_enable").Get(user.SettingsTwoFactorEnable).
@@ -252,33 +330,53 @@ func runWeb(c *cli.Context) error {
 			m.Get("/forget_password", user.ForgotPasswd)
 			m.Post("/forget_password", user.ForgotPasswdPost)
 			m.Post("/logout", user.SignOut)
+			m.Combo("/digests/unsubscribe").Get(user.DigestUnsubscribe).
+				Post(user.DigestUnsubscribePost)
 		})
+
+		m.Group("/login", func() {
+			m.Combo("/device", reqSignIn).Get(user.Device).Post(user.DevicePost)
+			m.Post("/device/code", user.DeviceCodePost)
+			m.Post("/oauth/access_token", user.AccessTokenPost)
+		}, user.MustEnableDeviceAuthorization)
 		// ***** END: User *****
 
 		reqAdmin := context.Toggle(&context.ToggleOptions{SignInRequired: true, AdminRequired: true})
+		reqSiteAdmin := context.AdminRoleRequired()
+		reqUsersAdmin := context.AdminRoleRequired(db.AdminRoleUserAdmin)
+		reqReposAdmin := context.AdminRoleRequired(db.AdminRoleRepoAdmin)
 
 		// ***** START: Admin *****
 		m.Group("/admin", func() {
+			// Operations check admin roles individually.
 			m.Combo("").Get(admin.Dashboard).Post(admin.Operation) // "/admin"
-			m.Get("/config", admin.Config)
-			m.Post("/config/test_mail", admin.SendTestMail)
-			m.Get("/monitor", admin.Monitor)
+			m.Group("", func() {
+				m.Get("/config", admin.Config)
+				m.Post("/config/test_mail", admin.SendTestMail)
+				m.Get("/monitor", admin.Monitor)
+			}, reqSiteAdmin)
 
 			m.Group("/users", func() {
 				m.Get("", admin.Users)
 				m.Combo("/new").Get(admin.NewUser).Post(bindIgnErr(form.AdminCrateUser{}), admin.NewUserPost)
 				m.Combo("/:userid").Get(admin.EditUser).Post(bindIgnErr(form.AdminEditUser{}), admin.EditUserPost)
 				m.Post("/:userid/delete", admin.DeleteUser)
-			})
+			}, reqUsersAdmin)
 
 			m.Group("/orgs", func() {
 				m.Get("", admin.Organizations)
-			})
+			}, reqUsersAdmin)
 
 			m.Group("/repos", func() {
 				m.Get("", admin.Repos)
 				m.Post("/delete", admin.DeleteRepo)
-			})
+				m.Combo("/bulk_edit").
+					Get(admin.BulkEditRepos).
+					Post(bindIgnErr(form.AdminBulkEditRepos{}), admin.BulkEditReposPost)
+				m.Get("/trash", admin.TrashedRepos)
+				m.Post("/trash/:id/restore", admin.RestoreTrashedRepo)
+				m.Post("/trash/:id/purge", admin.PurgeTrashedRepo)
+			}, reqReposAdmin)
 
 			m.Group("/auths", func() {
 				m.Get("", admin.Authentications)
@@ -286,50 +384,53 @@ func runWeb(c *cli.Context) error {
 				m.Combo("/:authid").Get(admin.EditAuthSource).
 					Post(bindIgnErr(form.Authentication{}), admin.EditAuthSourcePost)
 				m.Post("/:authid/delete", admin.DeleteAuthSource)
-			})
+			}, reqSiteAdmin)
+
+			m.Group("/templates", func() {
+				m.Combo("").Get(admin.Templates).Post(bindIgnErr(form.AdminRepoInitFile{}), admin.SaveTemplatePost)
+				m.Post("/delete", admin.DeleteTemplate)
+			}, reqReposAdmin)
+
+			m.Group("/profile_fields", func() {
+				m.Combo("").Get(admin.ProfileFields).Post(bindIgnErr(form.AdminProfileField{}), admin.NewProfileFieldPost)
+				m.Combo("/:id").Get(admin.EditProfileField).Post(bindIgnErr(form.AdminProfileField{}), admin.EditProfileFieldPost)
+				m.Post("/:id/delete", admin.DeleteProfileField)
+			}, reqUsersAdmin)
+
+			m.Group("/usage", func() {
+				m.Get("", admin.Usage)
+				m.Get("/export", admin.UsageExport)
+			}, reqReposAdmin)
 
 			m.Group("/notices", func() {
 				m.Get("", admin.Notices)
 				m.Post("/delete", admin.DeleteNotices)
-				m.Get("/empty", admin.EmptyNotices)
-			})
+				m.Post("/ack", admin.AckNotices)
+				m.Post("/empty", admin.EmptyNotices)
+			}, reqSiteAdmin)
+
+			m.Group("/reserved_names", func() {
+				m.Combo("").Get(admin.ReservedNames).Post(bindIgnErr(form.AdminReservedName{}), admin.NewReservedNamePost)
+				m.Post("/:id/delete", admin.DeleteReservedName)
+			}, reqSiteAdmin)
+
+			m.Group("/emails", func() {
+				m.Get("", admin.Emails)
+				m.Post("/:id/retry", admin.RetryEmail)
+				m.Post("/:id/discard", admin.DiscardEmail)
+			}, reqSiteAdmin)
 		}, reqAdmin)
 		// ***** END: Admin *****
 
 		m.Group("", func() {
+			m.Get("/:username", user.Profile)
 			m.Group("/:username", func() {
-				m.Get("", user.Profile)
 				m.Get("/followers", user.Followers)
 				m.Get("/following", user.Following)
 				m.Get("/stars", user.Stars)
 			}, context.InjectParamsUser())
 
-			m.Get("/attachments/:uuid", func(c *context.Context) {
-				attach, err := db.GetAttachmentByUUID(c.Params(":uuid"))
-				if err != nil {
-					c.NotFoundOrError(err, "get attachment by UUID")
-					return
-				} else if !com.IsFile(attach.LocalPath()) {
-					c.NotFound()
-					return
-				}
-
-				fr, err := os.Open(attach.LocalPath())
-				if err != nil {
-					c.Error(err, "open attachment file")
-					return
-				}
-				defer fr.Close()
-
-				c.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
-				c.Header().Set("Cache-Control", "public,max-age=86400")
-				c.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, attach.Name))
-
-				if _, err = io.Copy(c.Resp, fr); err != nil {
-					c.Error(err, "copy from file to response")
-					return
-				}
-			})
+			m.Get("/attachments/:uuid", repo.RedirectAttachment)
 			m.Post("/issues/attachments", repo.UploadIssueAttachment)
 			m.Post("/releases/attachments", repo.UploadReleaseAttachment)
 		}, ignSignIn)
@@ -344,6 +445,7 @@ func runWeb(c *cli.Context) error {
 
 		reqRepoAdmin := context.RequireRepoAdmin()
 		reqRepoWriter := context.RequireRepoWriter()
+		reqRepoTriager := context.RequireRepoTriager()
 
 		webhookRoutes := func() {
 			m.Group("", func() {
@@ -385,6 +487,13 @@ func runWeb(c *cli.Context) error {
 			m.Group("/:org", func() {
 				m.Get("/teams/:team", org.TeamMembers)
 				m.Get("/teams/:team/repositories", org.TeamRepositories)
+				m.Group("/teams/:team/discussions", func() {
+					m.Combo("").Get(org.TeamDiscussions).
+						Post(bindIgnErr(form.NewTeamDiscussion{}), org.NewTeamDiscussionPost)
+					m.Post("/delete", org.DeleteTeamDiscussion)
+					m.Combo("/:id").Get(org.TeamDiscussion).
+						Post(bindIgnErr(form.NewTeamDiscussionReply{}), org.TeamDiscussionReplyPost)
+				})
 				m.Route("/teams/:team/action/:action", "GET,POST", org.TeamsAction)
 				m.Route("/teams/:team/action/repo/:action", "GET,POST", org.TeamsRepoAction)
 			}, context.OrgAssignment(true, false, true))
@@ -399,9 +508,39 @@ func runWeb(c *cli.Context) error {
 				m.Group("/settings", func() {
 					m.Combo("").Get(org.Settings).
 						Post(bindIgnErr(form.UpdateOrgSetting{}), org.SettingsPost)
-					m.Post("/avatar", binding.MultipartForm(form.Avatar{}), org.SettingsAvatar)
+					m.Post("/avatar", bindIgnErr(form.Avatar{}), org.SettingsAvatar)
 					m.Post("/avatar/delete", org.SettingsDeleteAvatar)
+					m.Combo("/announcement").Get(org.SettingsAnnouncement).Post(org.SettingsAnnouncementPost)
 					m.Group("/hooks", webhookRoutes)
+					m.Get("/access", org.SettingsAccess)
+					m.Get("/access/export", org.SettingsAccessExport)
+					m.Get("/licenses", org.SettingsLicenses)
+					m.Get("/licenses/export", org.SettingsLicensesExport)
+					m.Get("/reviews", org.SettingsReviews)
+					m.Group("/domains", func() {
+						m.Combo("").Get(org.SettingsDomains).Post(org.SettingsDomainsPost)
+						m.Post("/restrict", org.SettingsDomainsRestrictPost)
+						m.Post("/:id/verify", org.SettingsDomainVerify)
+						m.Post("/delete", org.SettingsDomainDelete)
+					})
+					m.Group("/security", func() {
+						m.Combo("").Get(org.SettingsSecurity).Post(org.SettingsSecurityPost)
+						m.Post("/policy", org.SettingsSecurityPolicyPost)
+						m.Post("/exceptions/:id/:action(approve|reject)", org.SettingsSecurityExceptionReview)
+						m.Post("/exceptions/delete", org.SettingsSecurityExceptionDelete)
+					})
+					m.Group("/rulesets", func() {
+						m.Get("", org.SettingsRulesets)
+						m.Combo("/new").Get(org.NewRuleset).Post(bindIgnErr(form.OrgRuleset{}), org.NewRulesetPost)
+						m.Combo("/:id").Get(org.EditRuleset).Post(bindIgnErr(form.OrgRuleset{}), org.EditRulesetPost)
+						m.Post("/delete", org.DeleteRuleset)
+						m.Post("/default_branch_protection", org.SettingsDefaultBranchProtectionPost)
+						m.Post("/repo_naming", org.SettingsRepoNamingPost)
+					})
+					m.Group("/runners", func() {
+						m.Combo("").Get(org.SettingsRunners).Post(org.SettingsRunnersPost)
+						m.Post("/delete", org.SettingsRunnerDelete)
+					})
 					m.Route("/delete", "GET,POST", org.SettingsDelete)
 				})
 
@@ -425,7 +564,7 @@ func runWeb(c *cli.Context) error {
 				m.Combo("").Get(repo.Settings).
 					Post(bindIgnErr(form.RepoSetting{}), repo.SettingsPost)
 				m.Combo("/avatar").Get(repo.SettingsAvatar).
-					Post(binding.MultipartForm(form.Avatar{}), repo.SettingsAvatarPost)
+					Post(bindIgnErr(form.Avatar{}), repo.SettingsAvatarPost)
 				m.Post("/avatar/delete", repo.SettingsDeleteAvatar)
 				m.Group("/collaboration", func() {
 					m.Combo("").Get(repo.SettingsCollaboration).Post(repo.SettingsCollaborationPost)
@@ -464,15 +603,30 @@ func runWeb(c *cli.Context) error {
 						Post(bindIgnErr(form.AddSSHKey{}), repo.SettingsDeployKeysPost)
 					m.Post("/delete", repo.DeleteDeployKey)
 				})
+				m.Get("/security", repo.SettingsSecurity)
+				m.Post("/security/exceptions", repo.SettingsSecurityExceptionPost)
+				m.Get("/traffic", repo.SettingsTraffic)
+				m.Group("/pages", func() {
+					m.Combo("").Get(repo.SettingsPages).Post(repo.SettingsPagesPost)
+					m.Post("/verify", repo.SettingsPagesVerify)
+					m.Post("/delete", repo.SettingsPagesDelete)
+				}, repo.MustEnablePages)
 			}, func(c *context.Context) {
 				c.Data["PageIsSettings"] = true
+				c.Data["IsPagesEnabled"] = conf.Pages.Enabled
 			})
 		}, reqSignIn, context.RepoAssignment(), reqRepoAdmin, context.RepoRef())
 
 		m.Post("/:username/:reponame/action/:action", reqSignIn, context.RepoAssignment(), repo.Action)
+		m.Get("/:username/:reponame/events", ignSignIn, context.RepoAssignment(), repo.Events)
+		m.Get("/:username/:reponame/status.svg", ignSignIn, context.RepoAssignment(), repo.BranchStatusBadge)
+		m.Get("/:username/:reponame/search", ignSignIn, context.RepoAssignment(), repo.Search)
+		m.Get("/:username/:reponame/search/commits", ignSignIn, context.RepoAssignment(), repo.SearchCommitsPage)
+		m.Get("/:username/:reponame/pages/auth", reqSignIn, context.RepoAssignment(), repo.MustEnablePages, repo.PagesAuth)
 		m.Group("/:username/:reponame", func() {
 			m.Get("/issues", repo.RetrieveLabels, repo.Issues)
 			m.Get("/issues/:index", repo.ViewIssue)
+			m.Get("/issues/:index/attachments/:uuid", repo.IssueAttachment)
 			m.Get("/labels/", repo.RetrieveLabels, repo.Labels)
 			m.Get("/milestones", repo.Milestones)
 		}, ignSignIn, context.RepoAssignment(true))
@@ -482,11 +636,14 @@ func runWeb(c *cli.Context) error {
 			m.Group("/issues", func() {
 				m.Combo("/new", repo.MustEnableIssues).Get(context.RepoRef(), repo.NewIssue).
 					Post(bindIgnErr(form.NewIssue{}), repo.NewIssuePost)
+				m.Get("/duplicates", repo.MustEnableIssues, repo.IssueDuplicates)
 
 				m.Group("/:index", func() {
 					m.Post("/title", repo.UpdateIssueTitle)
 					m.Post("/content", repo.UpdateIssueContent)
+					m.Post("/attachments/:uuid/delete", repo.DeleteIssueAttachment)
 					m.Combo("/comments").Post(bindIgnErr(form.CreateComment{}), repo.NewComment)
+					m.Post("/reactions", repo.ToggleIssueReaction)
 				})
 			})
 			m.Group("/comments/:id", func() {
@@ -509,7 +666,7 @@ func runWeb(c *cli.Context) error {
 					m.Post("/label", repo.UpdateIssueLabel)
 					m.Post("/milestone", repo.UpdateIssueMilestone)
 					m.Post("/assignee", repo.UpdateIssueAssignee)
-				}, reqRepoWriter)
+				}, reqRepoTriager)
 			})
 			m.Group("/labels", func() {
 				m.Post("/new", bindIgnErr(form.CreateLabel{}), repo.NewLabel)
@@ -532,7 +689,7 @@ func runWeb(c *cli.Context) error {
 				m.Post("/delete", repo.DeleteRelease)
 				m.Get("/edit/*", repo.EditRelease)
 				m.Post("/edit/*", bindIgnErr(form.EditRelease{}), repo.EditReleasePost)
-			}, repo.MustBeNotBare, reqRepoWriter, func(c *context.Context) {
+			}, repo.MustBeNotBare, repo.MustEnableReleases, reqRepoWriter, func(c *context.Context) {
 				c.Data["PageIsViewFiles"] = true
 			})
 
@@ -575,7 +732,9 @@ func runWeb(c *cli.Context) error {
 
 		m.Group("/:username/:reponame", func() {
 			m.Group("", func() {
-				m.Get("/releases", repo.MustBeNotBare, repo.Releases)
+				m.Get("/releases", repo.MustBeNotBare, repo.MustEnableReleases, repo.Releases)
+				m.Get("/releases/tag/*", repo.MustBeNotBare, repo.MustEnableReleases, repo.ReleaseTag)
+				m.Get("/releases/:id/attachments/:uuid", repo.MustEnableReleases, repo.ReleaseAttachment)
 				m.Get("/pulls", repo.RetrieveLabels, repo.Pulls)
 				m.Get("/pulls/:index", repo.ViewPull)
 			}, context.RepoRef())
@@ -598,17 +757,19 @@ func runWeb(c *cli.Context) error {
 				}, reqSignIn, reqRepoWriter)
 			}, repo.MustEnableWiki, context.RepoRef())
 
-			m.Get("/archive/*", repo.MustBeNotBare, repo.Download)
+			m.Get("/archive/*", repo.MustBeNotBare, downloadlimit.Limit(downloadlimit.EndpointArchive), repo.Download)
 
 			m.Group("/pulls/:index", func() {
 				m.Get("/commits", context.RepoRef(), repo.ViewPullCommits)
 				m.Get("/files", context.RepoRef(), repo.ViewPullFiles)
 				m.Post("/merge", reqRepoWriter, repo.MergePullRequest)
+				m.Post("/merge_checklist", reqSignIn, repo.UpdateMergeChecklist)
+				m.Post("/merge_queue/remove", reqRepoWriter, repo.RemoveFromMergeQueue)
 			}, repo.MustAllowPulls)
 
 			m.Group("", func() {
 				m.Get("/src/*", repo.Home)
-				m.Get("/raw/*", repo.SingleDownload)
+				m.Get("/raw/*", downloadlimit.Limit(downloadlimit.EndpointRaw), repo.SingleDownload)
 				m.Get("/commits/*", repo.RefCommits)
 				m.Get("/commit/:sha([a-f0-9]{7,40})$", repo.Diff)
 				m.Get("/forks", repo.Forks)
@@ -655,6 +816,16 @@ func runWeb(c *cli.Context) error {
 		context.Contexter(),
 	)
 
+	// ***********************
+	// ----- SCIM routes -----
+	// ***********************
+
+	if conf.Auth.EnableSCIM {
+		m.Group("/scim/v2", func() {
+			scim.RegisterRoutes(m.Router)
+		})
+	}
+
 	// ***************************
 	// ----- HTTP Git routes -----
 	// ***************************
@@ -675,6 +846,8 @@ func runWeb(c *cli.Context) error {
 
 	m.Group("/-", func() {
 		m.Get("/metrics", app.MetricsFilter(), promhttp.Handler()) // "/-/metrics"
+		m.Get("/ssh", app.SSHTunnel())                             // "/-/ssh"
+		m.Get("/themes/:name/*", app.ThemeAsset())                 // "/-/themes/:name/*"
 
 		m.Group("/api", func() {
 			m.Post("/sanitize_ipynb", app.SanitizeIpynb()) // "/-/api/sanitize_ipynb"
@@ -695,6 +868,39 @@ func runWeb(c *cli.Context) error {
 
 	m.NotFound(route.NotFound)
 
+	var h http.Handler = m
+	if conf.Pages.Enabled {
+		h = pages.Handler(m)
+	}
+	serve(c, h)
+	return nil
+}
+
+// newSafeModeMacaron returns a minimal Macaron instance that only serves the
+// page describing problems found by the self-check.
+func newSafeModeMacaron(problems selfcheck.Problems) *macaron.Macaron {
+	token, err := strutil.RandomChars(32)
+	if err != nil {
+		log.Fatal("Failed to generate safe mode token: %v", err)
+	}
+
+	m := macaron.New()
+	if !conf.Server.DisableRouterLog {
+		m.Use(macaron.Logger())
+	}
+	m.Use(macaron.Recovery())
+	if conf.Server.Protocol == "fcgi" {
+		m.SetURLPrefix(conf.Server.Subpath)
+	}
+	m.Any("/*", route.SafeMode(problems, token))
+
+	log.Warn("Started in safe mode, visit %s?token=%s to see problems found by the self-check", conf.Server.ExternalURL, token)
+	return m
+}
+
+// serve starts the web server with given handler, and blocks until the server
+// exits.
+func serve(c *cli.Context, h http.Handler) {
 	// Flag for port number in case first time run conflict.
 	if c.IsSet("port") {
 		conf.Server.URL.Host = strings.Replace(conf.Server.URL.Host, ":"+conf.Server.URL.Port(), ":"+c.String("port"), 1)
@@ -710,9 +916,10 @@ func runWeb(c *cli.Context) error {
 	}
 	log.Info("Available on %s", conf.Server.ExternalURL)
 
+	var err error
 	switch conf.Server.Protocol {
 	case "http":
-		err = http.ListenAndServe(listenAddr, m)
+		err = http.ListenAndServe(listenAddr, h)
 
 	case "https":
 		tlsMinVersion := tls.VersionTLS12
@@ -740,12 +947,22 @@ func runWeb(c *cli.Context) error {
 					tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
 					tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
 				},
-			}, Handler: m,
+			}, Handler: h,
+		}
+		if conf.Pages.Enabled && conf.Pages.EnableACME {
+			cert, certErr := tls.LoadX509KeyPair(conf.Server.CertFile, conf.Server.KeyFile)
+			if certErr != nil {
+				log.Fatal("Failed to load certificate: %v", certErr)
+			}
+			server.TLSConfig.GetCertificate = pages.GetCertificate(&cert)
+			server.TLSConfig.NextProtos = pages.NextProtos
+			err = server.ListenAndServeTLS("", "")
+			break
 		}
 		err = server.ListenAndServeTLS(conf.Server.CertFile, conf.Server.KeyFile)
 
 	case "fcgi":
-		err = fcgi.Serve(nil, m)
+		err = fcgi.Serve(nil, h)
 
 	case "unix":
 		if osutil.IsExist(listenAddr) {
@@ -766,7 +983,7 @@ func runWeb(c *cli.Context) error {
 		if err = os.Chmod(listenAddr, conf.Server.UnixSocketMode); err != nil {
 			log.Fatal("Failed to change permission of Unix domain socket: %v", err)
 		}
-		err = http.Serve(listener, m)
+		err = http.Serve(listener, h)
 
 	default:
 		log.Fatal("Unexpected server protocol: %s", conf.Server.Protocol)
@@ -775,6 +992,4 @@ func runWeb(c *cli.Context) error {
 	if err != nil {
 		log.Fatal("Failed to start server: %v", err)
 	}
-
-	return nil
 }
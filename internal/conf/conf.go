@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -78,6 +79,10 @@ func Init(customConf string) error {
 		log.Warn("Custom config %q not found. Ignore this warning if you're running for the first time", customConf)
 	}
 
+	if err = applyEnvOverrides(File, os.Environ()); err != nil {
+		return errors.Wrap(err, "apply environment variables")
+	}
+
 	if err = File.Section(ini.DefaultSection).MapTo(&App); err != nil {
 		return errors.Wrap(err, "mapping default section")
 	}
@@ -169,7 +174,15 @@ func Init(customConf string) error {
 		return errors.Wrap(err, "mapping [repository] section")
 	}
 	Repository.Root = ensureAbs(Repository.Root)
+	for i := range Repository.ShardRoots {
+		Repository.ShardRoots[i] = ensureAbs(Repository.ShardRoots[i])
+	}
 	Repository.Upload.TempPath = ensureAbs(Repository.Upload.TempPath)
+	if Repository.Naming.NamePattern != "" {
+		if _, err = regexp.Compile(Repository.Naming.NamePattern); err != nil {
+			return errors.Wrap(err, "compile [repository.naming] NAME_PATTERN")
+		}
+	}
 
 	// *****************************
 	// ----- Database settings -----
@@ -223,6 +236,22 @@ func Init(customConf string) error {
 	if err = File.Section("auth").MapTo(&Auth); err != nil {
 		return errors.Wrap(err, "mapping [auth] section")
 	}
+	// Anonymous users do not belong to any tenant.
+	if Security.EnableTenantIsolation {
+		Auth.RequireSigninView = true
+	}
+
+	// *******************************
+	// ----- Federation settings -----
+	// *******************************
+
+	if err = File.Section("federation").MapTo(&Federation); err != nil {
+		return errors.Wrap(err, "mapping [federation] section")
+	}
+	Federation.CentralURL = strings.TrimSuffix(Federation.CentralURL, "/")
+	if (Federation.EnableIntrospection || Federation.CentralURL != "") && Federation.SharedKey == "" {
+		return errors.New("[federation] SHARED_KEY is required when federation is enabled")
+	}
 
 	// *************************
 	// ----- User settings -----
@@ -231,6 +260,19 @@ func Init(customConf string) error {
 	if err = File.Section("user").MapTo(&User); err != nil {
 		return errors.Wrap(err, "mapping [user] section")
 	}
+	if User.NoReplyAddress == "" {
+		User.NoReplyAddress = "noreply." + Server.Domain
+	}
+	for key, policy := range map[string]string{
+		"TEAM_MENTIONS": User.TeamMentions,
+		"ALL_MENTIONS":  User.AllMentions,
+	} {
+		switch policy {
+		case "members", "collaborators", "disabled":
+		default:
+			return errors.Errorf("[user] unsupported %s %q", key, policy)
+		}
+	}
 
 	// ****************************
 	// ----- Session settings -----
@@ -365,6 +407,8 @@ func Init(customConf string) error {
 		return errors.Wrap(err, "mapping [smartypants] section")
 	} else if err = File.Section("admin").MapTo(&Admin); err != nil {
 		return errors.Wrap(err, "mapping [admin] section")
+	} else if err = File.Section("reserved_names").MapTo(&ReservedNames); err != nil {
+		return errors.Wrap(err, "mapping [reserved_names] section")
 	} else if err = File.Section("cron").MapTo(&Cron); err != nil {
 		return errors.Wrap(err, "mapping [cron] section")
 	} else if err = File.Section("git").MapTo(&Git); err != nil {
@@ -375,10 +419,46 @@ func Init(customConf string) error {
 		return errors.Wrap(err, "mapping [ui] section")
 	} else if err = File.Section("prometheus").MapTo(&Prometheus); err != nil {
 		return errors.Wrap(err, "mapping [prometheus] section")
+	} else if err = File.Section("antivirus").MapTo(&Antivirus); err != nil {
+		return errors.Wrap(err, "mapping [antivirus] section")
+	} else if err = File.Section("pages").MapTo(&Pages); err != nil {
+		return errors.Wrap(err, "mapping [pages] section")
+	} else if err = File.Section("snippet").MapTo(&Snippet); err != nil {
+		return errors.Wrap(err, "mapping [snippet] section")
+	} else if err = File.Section("firehose").MapTo(&Firehose); err != nil {
+		return errors.Wrap(err, "mapping [firehose] section")
 	} else if err = File.Section("other").MapTo(&Other); err != nil {
 		return errors.Wrap(err, "mapping [other] section")
 	}
 
+	if API.DeleteConfirmationLiveMinutes <= 0 {
+		API.DeleteConfirmationLiveMinutes = 5
+	}
+
+	if Firehose.Enabled {
+		if Firehose.URL == "" {
+			return errors.New("[firehose] URL is required when firehose is enabled")
+		} else if Firehose.Format != "ndjson" && Firehose.Format != "kafka_rest" {
+			return errors.Errorf("[firehose] unsupported FORMAT %q", Firehose.Format)
+		}
+	}
+	if Firehose.Interval <= 0 {
+		Firehose.Interval = 10 * time.Second
+	}
+	if Firehose.BatchSize <= 0 {
+		Firehose.BatchSize = 100
+	}
+	if Firehose.Timeout <= 0 {
+		Firehose.Timeout = 15 * time.Second
+	}
+
+	Cron.CheckVulnerabilities.OSVPath = ensureAbs(Cron.CheckVulnerabilities.OSVPath)
+	Antivirus.QuarantinePath = ensureAbs(Antivirus.QuarantinePath)
+	Pages.Domain = strings.ToLower(strings.TrimSuffix(Pages.Domain, "."))
+	Pages.StoragePath = ensureAbs(Pages.StoragePath)
+	Pages.ACMECachePath = ensureAbs(Pages.ACMECachePath)
+	Snippet.RootPath = ensureAbs(Snippet.RootPath)
+
 	HasRobotsTxt = osutil.IsFile(filepath.Join(CustomDir(), "robots.txt"))
 	return nil
 }
@@ -0,0 +1,99 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package conf
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/ini.v1"
+)
+
+const (
+	// envPrefix is the prefix of environment variables that override
+	// configuration options, e.g. GOGS__DATABASE__PASSWORD.
+	envPrefix = "GOGS__"
+	// envFileSuffix is the suffix of environment variables that point to a file
+	// whose content is the value of the configuration option, e.g.
+	// GOGS__DATABASE__PASSWORD__FILE=/run/secrets/db-password.
+	envFileSuffix = "__FILE"
+)
+
+// envSectionReplacer decodes characters that are not allowed in names of
+// environment variables but in section names.
+var envSectionReplacer = strings.NewReplacer(
+	"_0X2E_", ".",
+	"_0X2D_", "-",
+)
+
+// parseEnvKey parses the section and key names from the name of an environment
+// variable in the form of "GOGS__<SECTION>__<KEY>[__FILE]". The section of
+// "DEFAULT" refers to the default section. It returns false if the name is not
+// in the form.
+func parseEnvKey(name string) (section, key string, isFile, ok bool) {
+	if !strings.HasPrefix(name, envPrefix) {
+		return "", "", false, false
+	}
+	name = strings.TrimPrefix(name, envPrefix)
+
+	if strings.HasSuffix(name, envFileSuffix) {
+		isFile = true
+		name = strings.TrimSuffix(name, envFileSuffix)
+	}
+
+	i := strings.Index(name, "__")
+	if i <= 0 || i+2 >= len(name) {
+		return "", "", false, false
+	}
+	section, key = name[:i], name[i+2:]
+	if strings.Contains(key, "__") {
+		return "", "", false, false
+	}
+
+	if section == ini.DefaultSection {
+		return ini.DefaultSection, key, isFile, true
+	}
+	return strings.ToLower(envSectionReplacer.Replace(section)), key, isFile, true
+}
+
+// applyEnvOverrides overrides configuration options of the file with the
+// environment variables in the form of "GOGS__<SECTION>__<KEY>=<value>", or
+// "GOGS__<SECTION>__<KEY>__FILE=<path>" to read the value from the file, e.g. a
+// mounted secret. The "." and "-" in section names are encoded as "_0X2E_" and
+// "_0X2D_" respectively.
+func applyEnvOverrides(file *ini.File, environ []string) error {
+	for _, env := range environ {
+		i := strings.Index(env, "=")
+		if i <= 0 {
+			continue
+		}
+		name, value := env[:i], env[i+1:]
+
+		section, key, isFile, ok := parseEnvKey(name)
+		if !ok {
+			continue
+		}
+
+		if isFile {
+			data, err := os.ReadFile(value)
+			if err != nil {
+				return errors.Wrapf(err, "read file of %q", name)
+			}
+			value = strings.TrimRight(string(data), "\r\n")
+		}
+
+		s := file.Section(section)
+		// Keys are case-sensitive, reuse the name of the existing key if any.
+		for _, k := range s.Keys() {
+			if strings.EqualFold(k.Name(), key) {
+				key = k.Name()
+				break
+			}
+		}
+		s.Key(key).SetValue(value)
+	}
+	return nil
+}
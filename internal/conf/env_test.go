@@ -0,0 +1,89 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+func TestParseEnvKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		wantSection string
+		wantKey     string
+		wantIsFile  bool
+		wantOK      bool
+	}{
+		{name: "GOGS__DATABASE__PASSWORD", wantSection: "database", wantKey: "PASSWORD", wantOK: true},
+		{name: "GOGS__DATABASE__PASSWORD__FILE", wantSection: "database", wantKey: "PASSWORD", wantIsFile: true, wantOK: true},
+		{name: "GOGS__DEFAULT__BRAND_NAME", wantSection: ini.DefaultSection, wantKey: "BRAND_NAME", wantOK: true},
+		{name: "GOGS__REPOSITORY_0X2E_EDITOR__LINE_WRAP_EXTENSIONS", wantSection: "repository.editor", wantKey: "LINE_WRAP_EXTENSIONS", wantOK: true},
+		{name: "GOGS__LOG_0X2E_GITEA_0X2D_LIKE__LEVEL", wantSection: "log.gitea-like", wantKey: "LEVEL", wantOK: true},
+
+		{name: "GOGS_DATABASE_PASSWORD"},
+		{name: "GOGS__DATABASE"},
+		{name: "GOGS__DATABASE__"},
+		{name: "GOGS____PASSWORD"},
+		{name: "GOGS__DATABASE__PASSWORD__EXTRA"},
+		{name: "HOME"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			section, key, isFile, ok := parseEnvKey(test.name)
+			assert.Equal(t, test.wantSection, section)
+			assert.Equal(t, test.wantKey, key)
+			assert.Equal(t, test.wantIsFile, isFile)
+			assert.Equal(t, test.wantOK, ok)
+		})
+	}
+}
+
+func TestApplyEnvOverrides(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "secret")
+	err := os.WriteFile(secret, []byte("s3cr3t\n"), 0600)
+	require.NoError(t, err)
+
+	file, err := ini.Load([]byte(`
+BRAND_NAME = Gogs
+
+[database]
+TYPE = sqlite
+PASSWORD =
+
+[ssh.minimum_key_sizes]
+ed25519 = 256
+`))
+	require.NoError(t, err)
+
+	err = applyEnvOverrides(file, []string{
+		"HOME=/home/git",
+		"GOGS__DEFAULT__BRAND_NAME=My Gogs",
+		"GOGS__DATABASE__TYPE=postgres",
+		"GOGS__DATABASE__PASSWORD__FILE=" + secret,
+		"GOGS__SSH_0X2E_MINIMUM_KEY_SIZES__ED25519=-1",
+		"GOGS__EMAIL__ENABLED=true",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "My Gogs", file.Section("").Key("BRAND_NAME").String())
+	assert.Equal(t, "postgres", file.Section("database").Key("TYPE").String())
+	assert.Equal(t, "s3cr3t", file.Section("database").Key("PASSWORD").String())
+	assert.Equal(t, "-1", file.Section("ssh.minimum_key_sizes").Key("ed25519").String())
+	assert.False(t, file.Section("ssh.minimum_key_sizes").HasKey("ED25519"))
+	assert.Equal(t, "true", file.Section("email").Key("ENABLED").String())
+
+	t.Run("missing file", func(t *testing.T) {
+		err := applyEnvOverrides(ini.Empty(), []string{
+			"GOGS__DATABASE__PASSWORD__FILE=" + filepath.Join(t.TempDir(), "404"),
+		})
+		assert.Error(t, err)
+	})
+}
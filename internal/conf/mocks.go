@@ -47,3 +47,19 @@ func SetMockUI(t *testing.T, opts UIOpts) {
 		UI = before
 	})
 }
+
+func SetMockAntivirus(t *testing.T, opts AntivirusOpts) {
+	before := Antivirus
+	Antivirus = opts
+	t.Cleanup(func() {
+		Antivirus = before
+	})
+}
+
+func SetMockPages(t *testing.T, opts PagesOpts) {
+	before := Pages
+	Pages = opts
+	t.Cleanup(func() {
+		Pages = before
+	})
+}
@@ -45,6 +45,7 @@ var (
 		EnableLoginStatusCookie bool
 		LoginStatusCookieName   string
 		LocalNetworkAllowlist   []string `delim:","`
+		EnableTenantIsolation   bool
 	}
 
 	// Email settings
@@ -83,11 +84,33 @@ var (
 		EnableReverseProxyAuthentication   bool
 		EnableReverseProxyAutoRegistration bool
 		ReverseProxyAuthenticationHeader   string
+
+		EnableDeviceAuthorization bool
+		DeviceCodeLives           int
+		DeviceCodePollInterval    int
+
+		EnableJobTokens  bool
+		JobTokenMaxLives int
+
+		EnableSCIM bool `ini:"ENABLE_SCIM"`
+	}
+
+	// Federation settings
+	Federation struct {
+		EnableIntrospection bool
+		CentralURL          string `ini:"CENTRAL_URL"`
+		SharedKey           string
+		CacheTTL            time.Duration `ini:"CACHE_TTL"`
 	}
 
 	// User settings
 	User struct {
 		EnableEmailNotification bool
+		NoReplyAddress          string
+		// Who can mention teams and use "@all" mentions to notify many users at
+		// once, either "members", "collaborators" or "disabled".
+		TeamMentions string
+		AllMentions  string
 	}
 
 	// Session settings
@@ -111,6 +134,11 @@ var (
 	// HTTP settings
 	HTTP struct {
 		AccessControlAllowOrigin string
+		// Maximum sizes of request bodies in MB, zero means unlimited.
+		MaxRequestBodySize     int64 `ini:"MAX_REQUEST_BODY_SIZE"`
+		MaxAPIBodySize         int64 `ini:"MAX_API_BODY_SIZE"`
+		MaxUploadBodySize      int64 `ini:"MAX_UPLOAD_BODY_SIZE"`
+		MaxReceivePackBodySize int64 `ini:"MAX_RECEIVE_PACK_BODY_SIZE"`
 	}
 
 	// Attachment settings
@@ -124,6 +152,8 @@ var (
 
 	// Release settings
 	Release struct {
+		AutoCreateForTags bool
+
 		Attachment struct {
 			Enabled      bool
 			AllowedTypes []string `delim:"|"`
@@ -154,7 +184,8 @@ var (
 
 	// Mirror settings
 	Mirror struct {
-		DefaultInterval int
+		DefaultInterval  int
+		EnableDriftCheck bool
 	}
 
 	// Webhook settings
@@ -186,6 +217,14 @@ var (
 		DisableRegularOrgCreation bool
 	}
 
+	// Reserved names settings
+	ReservedNames struct {
+		Usernames        []string `delim:","`
+		AllowedUsernames []string `delim:","`
+		RepoNames        []string `delim:","`
+		AllowedRepoNames []string `delim:","`
+	}
+
 	// Cron tasks
 	Cron struct {
 		UpdateMirror struct {
@@ -211,6 +250,44 @@ var (
 			Schedule   string
 			OlderThan  time.Duration
 		} `ini:"cron.repo_archive_cleanup"`
+		CheckVulnerabilities struct {
+			Enabled    bool
+			RunAtStart bool
+			Schedule   string
+			// The directory of the local copy of the OSV database.
+			OSVPath string `ini:"OSV_PATH"`
+		} `ini:"cron.check_vulnerabilities"`
+		GitAccessLogCleanup struct {
+			Enabled    bool
+			RunAtStart bool
+			Schedule   string
+			OlderThan  time.Duration
+		} `ini:"cron.git_access_log_cleanup"`
+		RepoTrashCleanup struct {
+			Enabled    bool
+			RunAtStart bool
+			Schedule   string
+			OlderThan  time.Duration
+		} `ini:"cron.repo_trash_cleanup"`
+		UsageReport struct {
+			Enabled    bool
+			RunAtStart bool
+			Schedule   string
+			// The time duration of bandwidth and activity covered by each report.
+			Period time.Duration
+			// Email addresses to send reports to, defaults to all site admins.
+			Recipients []string `delim:","`
+		} `ini:"cron.usage_report"`
+		ActivityDigests struct {
+			Enabled    bool
+			RunAtStart bool
+			Schedule   string
+		} `ini:"cron.activity_digests"`
+		AggregateContributions struct {
+			Enabled    bool
+			RunAtStart bool
+			Schedule   string
+		} `ini:"cron.aggregate_contributions"`
 	}
 
 	// Git settings
@@ -223,6 +300,7 @@ var (
 		MaxDiffLines         int      `ini:"MAX_GIT_DIFF_LINES"`
 		MaxDiffLineChars     int      `ini:"MAX_GIT_DIFF_LINE_CHARACTERS"`
 		GCArgs               []string `ini:"GC_ARGS" delim:" "`
+		EnablePartialClone   bool
 		Timeout              struct {
 			Migrate int
 			Mirror  int
@@ -231,11 +309,23 @@ var (
 			Diff    int
 			GC      int `ini:"GC"`
 		} `ini:"git.timeout"`
+		AccessLog struct {
+			Enabled        bool
+			SaveToDatabase bool
+		} `ini:"git.access_log"`
 	}
 
 	// API settings
 	API struct {
 		MaxResponseItems int
+		EnableJWT        bool          `ini:"ENABLE_JWT"`
+		JWTSecret        string        `ini:"JWT_SECRET"`
+		JWTTTL           time.Duration `ini:"JWT_TTL"`
+		// Whether deleting repositories requires a confirmation token issued
+		// by a prior request, or the full name of the repository in the
+		// header "X-Gogs-Confirm-Repo".
+		RequireDeleteConfirmation     bool
+		DeleteConfirmationLiveMinutes int
 	}
 
 	// Prometheus settings
@@ -244,6 +334,9 @@ var (
 		EnableBasicAuth   bool
 		BasicAuthUsername string
 		BasicAuthPassword string
+		// Whether to export review latency of pull requests per repository and
+		// owner, which is computed from the database on every scrape.
+		EnableReviewLatency bool
 	}
 
 	// Other settings
@@ -315,6 +408,7 @@ type SSHOpts struct {
 	ListenPort         int      `ini:"SSH_LISTEN_PORT"`
 	ServerCiphers      []string `ini:"SSH_SERVER_CIPHERS"`
 	ServerMACs         []string `ini:"SSH_SERVER_MACS"`
+	EnableTunnel       bool     `ini:"ENABLE_SSH_TUNNEL"`
 }
 
 // SSH settings
@@ -322,15 +416,20 @@ var SSH SSHOpts
 
 type RepositoryOpts struct {
 	Root                     string
+	ShardRoots               []string
 	ScriptType               string
 	ANSICharset              string `ini:"ANSI_CHARSET"`
 	ForcePrivate             bool
 	MaxCreationLimit         int
+	ProtectDefaultBranch     bool
 	PreferredLicenses        []string
 	DisableHTTPGit           bool `ini:"DISABLE_HTTP_GIT"`
 	EnableLocalPathMigration bool
 	EnableRawFileRenderMode  bool
 	CommitsFetchConcurrency  int
+	EnableTrafficAnalytics   bool
+	EnableTrash              bool
+	EnableContentSearch      bool
 
 	// Repository editor settings
 	Editor struct {
@@ -340,12 +439,33 @@ type RepositoryOpts struct {
 
 	// Repository upload settings
 	Upload struct {
-		Enabled      bool
-		TempPath     string
-		AllowedTypes []string `delim:"|"`
-		FileMaxSize  int64
-		MaxFiles     int
+		Enabled        bool
+		TempPath       string
+		AllowedTypes   []string `delim:"|"`
+		FileMaxSize    int64
+		MaxFiles       int
+		ArchiveMaxSize int64
 	} `ini:"repository.upload"`
+
+	// Repository push settings
+	Push struct {
+		MaxFileSize int64
+		MaxSize     int64
+	} `ini:"repository.push"`
+
+	// Repository download settings
+	Download struct {
+		MaxConcurrentPerClient int
+		MaxBandwidthPerClient  int64
+		MaxConcurrentArchives  int
+	} `ini:"repository.download"`
+
+	// Repository naming settings
+	Naming struct {
+		NamePattern     string
+		NamePatternHelp string
+		RequiredTopics  []string
+	} `ini:"repository.naming"`
 }
 
 // Repository settings
@@ -375,6 +495,77 @@ type LFSOpts struct {
 // LFS settings
 var LFS LFSOpts
 
+type AntivirusOpts struct {
+	Enabled bool
+	// The address of the clamd daemon, e.g. "unix:///var/run/clamav/clamd.ctl"
+	// or "tcp://localhost:3310".
+	ClamdAddress string
+	Timeout      time.Duration
+	// The directory to move infected files to.
+	QuarantinePath string
+
+	ScanAttachments        bool
+	ScanReleaseAttachments bool
+	ScanLFSObjects         bool `ini:"SCAN_LFS_OBJECTS"`
+}
+
+// Antivirus settings
+var Antivirus AntivirusOpts
+
+type PagesOpts struct {
+	Enabled bool
+	// The domain that sites are served under as subdomains of owners, e.g.
+	// "pages.example.com" serves sites at "<owner>.pages.example.com/<repo>".
+	Domain string
+	// The scheme of URLs of sites, either "http" or "https".
+	Protocol string
+	// The directory to store artifacts uploaded by CI.
+	StoragePath string
+	// The maximum size of an uploaded artifact in megabytes.
+	MaxArtifactSize int64
+	// Whether to obtain TLS certificates of verified custom domains from an ACME
+	// CA automatically, requires the server to be listening on HTTPS.
+	EnableACME    bool   `ini:"ENABLE_ACME"`
+	ACMEDirectory string `ini:"ACME_DIRECTORY"`
+	ACMEEmail     string `ini:"ACME_EMAIL"`
+	ACMECachePath string `ini:"ACME_CACHE_PATH"`
+}
+
+// Pages settings
+var Pages PagesOpts
+
+type SnippetOpts struct {
+	Enabled bool
+	// The directory to store Git repositories of snippets.
+	RootPath string
+	// The maximum number of files of a snippet.
+	MaxFiles int
+	// The maximum size of a file of a snippet in kilobytes.
+	MaxFileSize int64
+}
+
+// Snippet settings
+var Snippet SnippetOpts
+
+type FirehoseOpts struct {
+	Enabled bool
+	// The endpoint that batches of activity events are posted to.
+	URL string `ini:"URL"`
+	// The format of request bodies, either "ndjson" or "kafka_rest".
+	Format string
+	// The secret to sign request bodies with.
+	Secret string
+	// The interval of checking for new activity events.
+	Interval time.Duration
+	// The maximum number of events in a request.
+	BatchSize     int
+	Timeout       time.Duration
+	SkipTLSVerify bool `ini:"SKIP_TLS_VERIFY"`
+}
+
+// Firehose settings
+var Firehose FirehoseOpts
+
 type UIUserOpts struct {
 	RepoPagingNum     int
 	NewsFeedPagingNum int
@@ -387,6 +578,8 @@ type UIOpts struct {
 	FeedMaxCommitNum   int
 	ThemeColorMetaTag  string
 	MaxDisplayFileSize int64
+	DefaultTheme       string
+	AllowUserThemes    bool
 
 	Admin struct {
 		UserPagingNum   int
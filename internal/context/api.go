@@ -5,6 +5,7 @@
 package context
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	log "unknwon.dev/clog/v2"
 
 	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/db"
 	"gogs.io/gogs/internal/errutil"
 )
 
@@ -29,6 +31,13 @@ type APIContext struct {
 // FIXME: move this constant to github.com/gogs/go-gogs-client
 const DocURL = "https://github.com/gogs/docs-api"
 
+// IsTenantVisible returns true if the user or organization is visible to the
+// context user under tenant isolation, see db.IsTenantVisible. Users with admin
+// roles manage users and organizations of all tenants.
+func (c *APIContext) IsTenantVisible(u *db.User) bool {
+	return c.HasAdminRole(db.AllAdminRoles...) || db.IsTenantVisible(c.User, u)
+}
+
 // NoContent renders the 204 response.
 func (c *APIContext) NoContent() {
 	c.Status(http.StatusNoContent)
@@ -36,14 +45,46 @@ func (c *APIContext) NoContent() {
 
 // NotFound renders the 404 response.
 func (c *APIContext) NotFound() {
-	c.Status(http.StatusNotFound)
+	c.ErrorStatus(http.StatusNotFound, errors.New("not found"))
+}
+
+// statusErrorCodes contains error codes by status code for errors that do not
+// carry a code of their own.
+var statusErrorCodes = map[int]string{
+	http.StatusBadRequest:            "bad_request",
+	http.StatusUnauthorized:          "unauthorized",
+	http.StatusForbidden:             "forbidden",
+	http.StatusNotFound:              "not_found",
+	http.StatusConflict:              "conflict",
+	http.StatusRequestEntityTooLarge: "too_large",
+	http.StatusUnprocessableEntity:   "validation_failed",
+	http.StatusTooManyRequests:       "rate_limited",
+	http.StatusInternalServerError:   "internal_error",
 }
 
-// ErrorStatus renders error with given status code.
+// ErrorResponse is the body of error responses. The Code is stable and meant
+// for clients to branch on, while the Message is for humans and may change.
+type ErrorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	URL     string `json:"url"`
+}
+
+// ErrorStatus renders error with given status code. The error code is taken
+// from the error when it implements errutil.Coder, or derived from the status
+// code otherwise.
 func (c *APIContext) ErrorStatus(status int, err error) {
-	c.JSON(status, map[string]string{
-		"message": err.Error(),
-		"url":     DocURL,
+	code := errutil.Code(err)
+	if code == "" || (code == "not_found" && status != http.StatusNotFound) {
+		code = statusErrorCodes[status]
+	}
+	if code == "" {
+		code = "error"
+	}
+	c.JSON(status, ErrorResponse{
+		Code:    code,
+		Message: err.Error(),
+		URL:     DocURL,
 	})
 }
 
@@ -18,6 +18,8 @@ import (
 	"gogs.io/gogs/internal/auth"
 	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/federation"
+	"gogs.io/gogs/internal/jwtauth"
 	"gogs.io/gogs/internal/tool"
 )
 
@@ -43,6 +45,16 @@ func Toggle(options *ToggleOptions) macaron.Handler {
 			return
 		}
 
+		// Users required by admins to change the password cannot visit any other
+		// page before doing so.
+		if c.IsLogged && c.User.MustChangePassword && c.User.IsLocal() &&
+			!c.IsBasicAuth && !c.IsTokenAuth && !isAPIPath(c.Req.URL.Path) &&
+			c.Req.URL.Path != "/user/settings/password" && c.Req.URL.Path != "/user/logout" {
+			c.Flash.Info(c.Tr("auth.must_change_password"))
+			c.RedirectSubpath("/user/settings/password")
+			return
+		}
+
 		// Check non-logged users landing page.
 		if !c.IsLogged && c.Req.RequestURI == "/" && conf.Server.LandingURL != "/" {
 			c.RedirectSubpath(conf.Server.LandingURL)
@@ -66,8 +78,10 @@ func Toggle(options *ToggleOptions) macaron.Handler {
 			if !c.IsLogged {
 				// Restrict API calls with error message.
 				if isAPIPath(c.Req.URL.Path) {
-					c.JSON(http.StatusForbidden, map[string]string{
-						"message": "Only authenticated user is allowed to call APIs.",
+					c.JSON(http.StatusForbidden, ErrorResponse{
+						Code:    "unauthenticated",
+						Message: "Only authenticated user is allowed to call APIs.",
+						URL:     DocURL,
 					})
 					return
 				}
@@ -91,7 +105,7 @@ func Toggle(options *ToggleOptions) macaron.Handler {
 		}
 
 		if options.AdminRequired {
-			if !c.User.IsAdmin {
+			if !c.HasAdminRole(db.AllAdminRoles...) {
 				c.Status(http.StatusForbidden)
 				return
 			}
@@ -100,15 +114,33 @@ func Toggle(options *ToggleOptions) macaron.Handler {
 	}
 }
 
+// AdminRoleRequired makes sure the user is a site admin or has any of given
+// admin roles, only site admins are allowed when no role is given. Auditors are
+// also allowed for read-only requests.
+func AdminRoleRequired(roles ...db.AdminRole) macaron.Handler {
+	return func(c *Context) {
+		if c.HasAdminRole(roles...) {
+			return
+		}
+
+		readOnly := c.Req.Method == http.MethodGet || c.Req.Method == http.MethodHead
+		if readOnly && c.HasAdminRole(db.AdminRoleAuditor) {
+			return
+		}
+		c.Status(http.StatusForbidden)
+	}
+}
+
 func isAPIPath(url string) bool {
 	return strings.HasPrefix(url, "/api/")
 }
 
-// authenticatedUserID returns the ID of the authenticated user, along with a bool value
-// which indicates whether the user uses token authentication.
-func authenticatedUserID(c *macaron.Context, sess session.Store) (_ int64, isTokenAuth bool) {
+// authenticatedUserID returns the ID of the authenticated user, along with two bool values
+// which indicate whether the user uses token authentication and whether the token is a
+// JSON Web Token respectively.
+func authenticatedUserID(c *macaron.Context, sess session.Store) (_ int64, isTokenAuth, isJWTAuth bool) {
 	if !db.HasEngine {
-		return 0, false
+		return 0, false, false
 	}
 
 	// Check access token.
@@ -122,52 +154,81 @@ func authenticatedUserID(c *macaron.Context, sess session.Store) (_ int64, isTok
 			auHead := c.Req.Header.Get("Authorization")
 			if len(auHead) > 0 {
 				auths := strings.Fields(auHead)
-				if len(auths) == 2 && auths[0] == "token" {
+				if len(auths) == 2 && (auths[0] == "token" || strings.EqualFold(auths[0], "bearer")) {
 					tokenSHA = auths[1]
 				}
 			}
 		}
 
+		// JSON Web Tokens are validated by their signatures without database lookups.
+		if jwtauth.Enabled() && jwtauth.IsToken(tokenSHA) {
+			uid, err := jwtauth.Authenticate(tokenSHA)
+			if err != nil {
+				return 0, false, false
+			}
+			return uid, true, true
+		}
+
 		// Let's see if token is valid.
 		if len(tokenSHA) > 0 {
 			t, err := db.AccessTokens.GetBySHA1(c.Req.Context(), tokenSHA)
-			if err != nil {
+			if db.IsErrAccessTokenNotExist(err) && federation.Enabled() {
+				u, err := federation.Authenticate(c.Req.Context(), tokenSHA)
+				if err != nil {
+					if !federation.IsErrTokenInactive(err) {
+						log.Error("Failed to authenticate federated token: %v", err)
+					}
+					return 0, false, false
+				}
+				return u.ID, true, false
+			} else if err != nil {
 				if !db.IsErrAccessTokenNotExist(err) {
 					log.Error("GetAccessTokenBySHA: %v", err)
 				}
-				return 0, false
+				return 0, false, false
 			}
 			if err = db.AccessTokens.Touch(c.Req.Context(), t.ID); err != nil {
 				log.Error("Failed to touch access token: %v", err)
 			}
-			return t.UserID, true
+			return t.UserID, true, false
 		}
 	}
 
 	uid := sess.Get("uid")
 	if uid == nil {
-		return 0, false
+		return 0, false, false
 	}
 	if id, ok := uid.(int64); ok {
-		if _, err := db.GetUserByID(id); err != nil {
+		u, err := db.GetUserByID(id)
+		if err != nil {
 			if !db.IsErrUserNotExist(err) {
 				log.Error("Failed to get user by ID: %v", err)
 			}
-			return 0, false
+			return 0, false, false
+		}
+
+		// Sessions created before the session epoch of the user was increased,
+		// e.g. by resetting the password, are no longer valid.
+		epoch, _ := sess.Get("session_epoch").(int64)
+		if epoch != u.SessionEpoch {
+			_ = sess.Delete("uid")
+			_ = sess.Delete("uname")
+			return 0, false, false
 		}
-		return id, false
+		return id, false, false
 	}
-	return 0, false
+	return 0, false, false
 }
 
-// authenticatedUser returns the user object of the authenticated user, along with two bool values
-// which indicate whether the user uses HTTP Basic Authentication or token authentication respectively.
-func authenticatedUser(ctx *macaron.Context, sess session.Store) (_ *db.User, isBasicAuth, isTokenAuth bool) {
+// authenticatedUser returns the user object of the authenticated user, along with three bool values
+// which indicate whether the user uses HTTP Basic Authentication, token authentication or JSON Web
+// Token authentication respectively.
+func authenticatedUser(ctx *macaron.Context, sess session.Store) (_ *db.User, isBasicAuth, isTokenAuth, isJWTAuth bool) {
 	if !db.HasEngine {
-		return nil, false, false
+		return nil, false, false, false
 	}
 
-	uid, isTokenAuth := authenticatedUserID(ctx, sess)
+	uid, isTokenAuth, isJWTAuth := authenticatedUserID(ctx, sess)
 
 	if uid <= 0 {
 		if conf.Auth.EnableReverseProxyAuthentication {
@@ -177,7 +238,7 @@ func authenticatedUser(ctx *macaron.Context, sess session.Store) (_ *db.User, is
 				if err != nil {
 					if !db.IsErrUserNotExist(err) {
 						log.Error("Failed to get user by name: %v", err)
-						return nil, false, false
+						return nil, false, false, false
 					}
 
 					// Check if enabled auto-registration.
@@ -191,13 +252,13 @@ func authenticatedUser(ctx *macaron.Context, sess session.Store) (_ *db.User, is
 						if err = db.CreateUser(u); err != nil {
 							// FIXME: should I create a system notice?
 							log.Error("Failed to create user: %v", err)
-							return nil, false, false
+							return nil, false, false, false
 						} else {
-							return u, false, false
+							return u, false, false, false
 						}
 					}
 				}
-				return u, false, false
+				return u, false, false, false
 			}
 		}
 
@@ -213,19 +274,28 @@ func authenticatedUser(ctx *macaron.Context, sess session.Store) (_ *db.User, is
 					if !auth.IsErrBadCredentials(err) {
 						log.Error("Failed to authenticate user: %v", err)
 					}
-					return nil, false, false
+					return nil, false, false, false
+				}
+				if u.ProhibitLogin {
+					return nil, false, false, false
 				}
 
-				return u, true, false
+				return u, true, false, false
 			}
 		}
-		return nil, false, false
+		return nil, false, false, false
 	}
 
 	u, err := db.GetUserByID(uid)
 	if err != nil {
 		log.Error("GetUserByID: %v", err)
-		return nil, false, false
+		return nil, false, false, false
+	}
+
+	// Users prohibited from logging in with sessions are shown the prohibition
+	// page, but access tokens must not be accepted anymore.
+	if isTokenAuth && u.ProhibitLogin {
+		return nil, false, false, false
 	}
-	return u, false, isTokenAuth
+	return u, false, isTokenAuth, isJWTAuth
 }
@@ -0,0 +1,118 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-macaron/binding"
+	"gopkg.in/macaron.v1"
+
+	"gogs.io/gogs/internal/conf"
+)
+
+// requestBodyLimit returns the maximum size of the request body in MB, and
+// the kind of the request to be shown in errors. A zero or negative size means
+// unlimited.
+func requestBodyLimit(r *http.Request) (kind string, size int64) {
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/git-receive-pack"):
+		return "Git push", conf.HTTP.MaxReceivePackBodySize
+	case strings.HasSuffix(r.URL.Path, "/git-upload-pack"),
+		strings.Contains(r.URL.Path, "/info/lfs/"):
+		// Fetches and LFS objects are streamed and have their own limits.
+		return "", 0
+	case strings.HasPrefix(r.URL.Path, "/api/") && strings.HasSuffix(r.URL.Path, "/pages/artifact"):
+		return "Pages artifact", conf.Pages.MaxArtifactSize
+	case strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data"):
+		return "upload", conf.HTTP.MaxUploadBodySize
+	case strings.HasPrefix(r.URL.Path, "/api/"):
+		return "API request", conf.HTTP.MaxAPIBodySize
+	}
+	return "request", conf.HTTP.MaxRequestBodySize
+}
+
+// limitedBody is a request body capped by http.MaxBytesReader, which records
+// whether reading failed for exceeding the limit. Bodies without declared sizes
+// only fail mid-handler, where the error may be reported by other means.
+type limitedBody struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		b.exceeded = true
+	}
+	return n, err
+}
+
+// IsBodyTooLarge returns true if reading the body of the request failed for
+// exceeding its limit.
+func IsBodyTooLarge(r *http.Request) bool {
+	b, ok := r.Body.(*limitedBody)
+	return ok && b.exceeded
+}
+
+// WriteBodyTooLarge responds 413 for the request whose body exceeds its limit,
+// with an API error for API requests and a plain text message otherwise.
+func WriteBodyTooLarge(w http.ResponseWriter, r *http.Request) {
+	kind, size := requestBodyLimit(r)
+	msg := fmt.Sprintf("%s body exceeds the limit of %d MB", kind, size)
+	if strings.HasPrefix(r.URL.Path, "/api/") {
+		data, _ := json.Marshal(ErrorResponse{
+			Code:    statusErrorCodes[http.StatusRequestEntityTooLarge],
+			Message: msg,
+			URL:     DocURL,
+		})
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		_, _ = w.Write(data)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	_, _ = w.Write([]byte(msg))
+}
+
+// BodyLimiter limits sizes of request bodies by the kind of requests, and
+// responds 413 for requests declaring larger bodies. Bodies without declared
+// sizes fail to read beyond the limit, see IsBodyTooLarge.
+func BodyLimiter() macaron.Handler {
+	return func(c *macaron.Context) {
+		_, size := requestBodyLimit(c.Req.Request)
+		if size <= 0 {
+			return
+		}
+
+		limit := size << 20
+		if c.Req.ContentLength > limit {
+			WriteBodyTooLarge(c.Resp, c.Req.Request)
+			return
+		}
+		c.Req.Request.Body = &limitedBody{
+			ReadCloser: http.MaxBytesReader(c.Resp, c.Req.Request.Body, limit),
+		}
+	}
+}
+
+// BindIgnErr is like binding.BindIgnErr, but responds 413 when the request
+// body exceeds its limit while being read.
+func BindIgnErr(obj interface{}) macaron.Handler {
+	bindIgnErr := binding.BindIgnErr(obj)
+	return func(c *macaron.Context) {
+		_, _ = c.Invoke(bindIgnErr)
+		if IsBodyTooLarge(c.Req.Request) {
+			WriteBodyTooLarge(c.Resp, c.Req.Request)
+		}
+	}
+}
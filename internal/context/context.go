@@ -24,6 +24,7 @@ import (
 	"gogs.io/gogs/internal/form"
 	"gogs.io/gogs/internal/lazyregexp"
 	"gogs.io/gogs/internal/template"
+	"gogs.io/gogs/internal/theme"
 )
 
 // Context represents context of a request.
@@ -39,9 +40,64 @@ type Context struct {
 	IsLogged    bool
 	IsBasicAuth bool
 	IsTokenAuth bool
+	IsJWTAuth   bool // Whether the token is a JSON Web Token
 
 	Repo *Repository
 	Org  *Organization
+
+	adminRoles map[db.AdminRole]bool
+}
+
+// HasAdminRole returns true if the user is a site admin or has any of given
+// admin roles.
+func (c *Context) HasAdminRole(roles ...db.AdminRole) bool {
+	if !c.IsLogged {
+		return false
+	} else if c.User.IsAdmin {
+		return true
+	}
+
+	for _, role := range roles {
+		if c.adminRoles[role] {
+			return true
+		}
+	}
+	return false
+}
+
+// CanManageUser returns true if the user is allowed to manage the given user in
+// site administration. Site admins and users with admin roles can only be
+// managed by site admins.
+func (c *Context) CanManageUser(u *db.User) (bool, error) {
+	if c.User.IsAdmin {
+		return true, nil
+	}
+
+	privileged, err := db.HasAdminPrivileges(c.Req.Context(), u)
+	if err != nil {
+		return false, err
+	}
+	return !privileged, nil
+}
+
+// loadAdminRoles loads admin roles of the user, site admins have all roles.
+func (c *Context) loadAdminRoles() {
+	roles := db.AllAdminRoles
+	if !c.User.IsAdmin {
+		var err error
+		roles, err = db.AdminRoles.ListByUserID(c.Req.Context(), c.User.ID)
+		if err != nil {
+			log.Error("Failed to list admin roles of user %q: %v", c.User.Name, err)
+		}
+	}
+
+	c.adminRoles = make(map[db.AdminRole]bool, len(roles))
+	names := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		c.adminRoles[role] = true
+		names[string(role)] = true
+	}
+	c.Data["AdminRoles"] = names
 }
 
 // RawTitle sets the "Title" field in template data.
@@ -260,7 +316,7 @@ func Contexter() macaron.Handler {
 		}
 
 		// Get user from session or header when possible
-		c.User, c.IsBasicAuth, c.IsTokenAuth = authenticatedUser(c.Context, c.Session)
+		c.User, c.IsBasicAuth, c.IsTokenAuth, c.IsJWTAuth = authenticatedUser(c.Context, c.Session)
 
 		if c.User != nil {
 			c.IsLogged = true
@@ -269,9 +325,12 @@ func Contexter() macaron.Handler {
 			c.Data["LoggedUserID"] = c.User.ID
 			c.Data["LoggedUserName"] = c.User.Name
 			c.Data["IsAdmin"] = c.User.IsAdmin
+			c.Data["Theme"] = theme.Resolve(c.User.Theme)
+			c.loadAdminRoles()
 		} else {
 			c.Data["LoggedUserID"] = 0
 			c.Data["LoggedUserName"] = ""
+			c.Data["Theme"] = theme.Resolve("")
 		}
 
 		// If request sends files, parse them here otherwise the Query() can't be parsed and the CsrfToken will be invalid.
@@ -292,6 +351,8 @@ func Contexter() macaron.Handler {
 		log.Trace("CSRF Token: %v", c.Data["CSRFToken"])
 
 		c.Data["ShowRegistrationButton"] = !conf.Auth.DisableRegistration
+		c.Data["ShowExplore"] = !conf.Security.EnableTenantIsolation || (c.IsLogged && c.User.IsAdmin)
+		c.Data["ShowSnippets"] = conf.Snippet.Enabled
 		c.Data["ShowFooterBranding"] = conf.Other.ShowFooterBranding
 
 		c.renderNoticeBanner()
@@ -80,8 +80,18 @@ func HandleOrgAssignment(c *Context, args ...bool) {
 		// Fake data.
 		c.Data["SignedUser"] = &db.User{}
 	}
+
+	// Members are sent to enable two-factor authentication when the
+	// organization enforces it.
+	if c.Org.IsMember && !c.User.IsAdmin && org.IsBlockedByTwoFactorRequirement(c.User.ID) {
+		c.Flash.Error(c.Tr("org.two_factor_required", org.Name))
+		c.RedirectSubpath("/user/settings/security")
+		return
+	}
+
 	if (requireMember && !c.Org.IsMember) ||
-		(requireOwner && !c.Org.IsOwner) {
+		(requireOwner && !c.Org.IsOwner) ||
+		(!c.Org.IsMember && db.IsTenantIsolated(c.User, org)) {
 		c.NotFound()
 		return
 	}
@@ -13,11 +13,13 @@ import (
 	"github.com/editorconfig/editorconfig-core-go/v2"
 	"github.com/pkg/errors"
 	"gopkg.in/macaron.v1"
+	log "unknwon.dev/clog/v2"
 
 	"github.com/gogs/git-module"
 
 	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/gitutil"
 	"gogs.io/gogs/internal/repoutil"
 )
 
@@ -47,6 +49,9 @@ type Repository struct {
 	CloneLink    repoutil.CloneLink
 	CommitsCount int64
 	Mirror       *db.Mirror
+	// IsListedMaintainer indicates current user is listed in the MAINTAINERS
+	// file of repository, see LoadListedMaintainer.
+	IsListedMaintainer bool
 
 	PullRequest *PullRequest
 }
@@ -66,6 +71,27 @@ func (r *Repository) IsWriter() bool {
 	return r.AccessMode >= db.AccessModeWrite
 }
 
+// CanTriage returns true if current user can triage issues and pull requests,
+// i.e. has write or higher access, or is a listed maintainer of repository.
+func (r *Repository) CanTriage() bool {
+	return r.IsWriter() || r.IsListedMaintainer
+}
+
+// LoadListedMaintainer checks whether the user is listed in the MAINTAINERS
+// file of repository, which only matters when the user has read access.
+func (r *Repository) LoadListedMaintainer(u *db.User) {
+	if u == nil || r.IsWriter() || !r.HasAccess() {
+		return
+	}
+
+	isMaintainer, err := r.Repository.IsListedMaintainer(u)
+	if err != nil {
+		log.Error("Failed to check listed maintainer of repository %d: %v", r.Repository.ID, err)
+		return
+	}
+	r.IsListedMaintainer = isMaintainer
+}
+
 // HasAccess returns true if the current user has at least read access for this repository
 func (r *Repository) HasAccess() bool {
 	return r.AccessMode >= db.AccessModeRead
@@ -95,6 +121,35 @@ func (r *Repository) Editorconfig() (*editorconfig.Editorconfig, error) {
 	return editorconfig.Parse(bytes.NewReader(p))
 }
 
+// Mailmap returns the mailmap of the ".mailmap" file on the default branch.
+func (r *Repository) Mailmap() (*gitutil.Mailmap, error) {
+	commit, err := r.GitRepo.BranchCommit(r.Repository.DefaultBranch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "get commit of branch %q ", r.Repository.DefaultBranch)
+	}
+
+	entry, err := commit.TreeEntry(".mailmap")
+	if err != nil {
+		return nil, errors.Wrap(err, "get .mailmap")
+	}
+
+	p, err := entry.Blob().Bytes()
+	if err != nil {
+		return nil, errors.Wrap(err, "read .mailmap")
+	}
+	return gitutil.ParseMailmap(p), nil
+}
+
+// Codeowners returns the code owners of the first CODEOWNERS file found on the
+// default branch, see gitutil.CodeownersPaths for the lookup order.
+func (r *Repository) Codeowners() (*gitutil.Codeowners, error) {
+	commit, err := r.GitRepo.BranchCommit(r.Repository.DefaultBranch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "get commit of branch %q ", r.Repository.DefaultBranch)
+	}
+	return gitutil.CommitCodeowners(commit)
+}
+
 // MakeURL accepts a string or url.URL as argument and returns escaped URL prepended with repository URL.
 func (r *Repository) MakeURL(location interface{}) string {
 	switch location := location.(type) {
@@ -167,6 +222,10 @@ func RepoAssignment(pages ...bool) macaron.Handler {
 		c.Data["RepoLink"] = c.Repo.RepoLink
 		c.Data["RepoRelPath"] = c.Repo.Owner.Name + "/" + c.Repo.Repository.Name
 
+		// Repositories of other tenants are treated as private when tenant isolation
+		// is enabled.
+		isolated := db.IsTenantIsolated(c.User, owner)
+
 		// Admin has super access
 		if c.IsLogged && c.User.IsAdmin {
 			c.Repo.AccessMode = db.AccessModeOwner
@@ -174,7 +233,7 @@ func RepoAssignment(pages ...bool) macaron.Handler {
 			c.Repo.AccessMode = db.Perms.AccessMode(c.Req.Context(), c.UserID(), repo.ID,
 				db.AccessModeOptions{
 					OwnerID: repo.OwnerID,
-					Private: repo.IsPrivate,
+					Private: repo.IsPrivate || isolated,
 				},
 			)
 		}
@@ -185,7 +244,7 @@ func RepoAssignment(pages ...bool) macaron.Handler {
 			mode := db.Perms.AccessMode(c.Req.Context(), c.UserID(), repo.BaseRepo.ID,
 				db.AccessModeOptions{
 					OwnerID: repo.BaseRepo.OwnerID,
-					Private: repo.BaseRepo.IsPrivate,
+					Private: repo.BaseRepo.IsPrivate || db.IsTenantIsolated(c.User, repo.BaseRepo.MustOwner()),
 				},
 			)
 
@@ -198,6 +257,11 @@ func RepoAssignment(pages ...bool) macaron.Handler {
 
 		// Check access
 		if c.Repo.AccessMode == db.AccessModeNone {
+			if isolated {
+				c.NotFound()
+				return
+			}
+
 			// Redirect to any accessible page if not yet on it
 			if repo.IsPartialPublic() &&
 				(!(isIssuesPage || isWikiPage) ||
@@ -258,6 +322,10 @@ func RepoAssignment(pages ...bool) macaron.Handler {
 		c.Data["IsRepositoryOwner"] = c.Repo.IsOwner()
 		c.Data["IsRepositoryAdmin"] = c.Repo.IsAdmin()
 		c.Data["IsRepositoryWriter"] = c.Repo.IsWriter()
+		if c.IsLogged {
+			c.Repo.LoadListedMaintainer(c.User)
+		}
+		c.Data["IsRepositoryTriager"] = c.Repo.CanTriage()
 
 		c.Data["DisableSSH"] = conf.SSH.Disabled
 		c.Data["DisableHTTP"] = conf.Repository.DisableHTTPGit
@@ -454,6 +522,17 @@ func RequireRepoWriter() macaron.Handler {
 	}
 }
 
+// RequireRepoTriager requires current user can triage issues and pull requests
+// of repository.
+func RequireRepoTriager() macaron.Handler {
+	return func(c *Context) {
+		if !c.IsLogged || (!c.Repo.CanTriage() && !c.User.IsAdmin) {
+			c.NotFound()
+			return
+		}
+	}
+}
+
 // GitHookService checks if repository Git hooks service has been enabled.
 func GitHookService() macaron.Handler {
 	return func(c *Context) {
@@ -23,6 +23,9 @@ func InjectParamsUser() macaron.Handler {
 		if err != nil {
 			c.NotFoundOrError(err, "get user by name")
 			return
+		} else if !db.IsTenantVisible(c.User, user) {
+			c.NotFound()
+			return
 		}
 		c.Map(&ParamsUser{user})
 	}
@@ -66,6 +66,72 @@ func NewContext() {
 			go db.DeleteOldRepositoryArchives()
 		}
 	}
+	if conf.Cron.CheckVulnerabilities.Enabled {
+		entry, err = c.AddFunc("Check vulnerabilities", conf.Cron.CheckVulnerabilities.Schedule, db.CheckVulnerabilities)
+		if err != nil {
+			log.Fatal("Cron.(check vulnerabilities): %v", err)
+		}
+		if conf.Cron.CheckVulnerabilities.RunAtStart {
+			entry.Prev = time.Now()
+			entry.ExecTimes++
+			go db.CheckVulnerabilities()
+		}
+	}
+	if conf.Cron.GitAccessLogCleanup.Enabled {
+		entry, err = c.AddFunc("Git access log cleanup", conf.Cron.GitAccessLogCleanup.Schedule, db.DeleteOldGitAccessLogs)
+		if err != nil {
+			log.Fatal("Cron.(git access log cleanup): %v", err)
+		}
+		if conf.Cron.GitAccessLogCleanup.RunAtStart {
+			entry.Prev = time.Now()
+			entry.ExecTimes++
+			go db.DeleteOldGitAccessLogs()
+		}
+	}
+	if conf.Cron.RepoTrashCleanup.Enabled {
+		entry, err = c.AddFunc("Purge expired trashed repositories", conf.Cron.RepoTrashCleanup.Schedule, db.PurgeExpiredTrashedRepositories)
+		if err != nil {
+			log.Fatal("Cron.(purge expired trashed repositories): %v", err)
+		}
+		if conf.Cron.RepoTrashCleanup.RunAtStart {
+			entry.Prev = time.Now()
+			entry.ExecTimes++
+			go db.PurgeExpiredTrashedRepositories()
+		}
+	}
+	if conf.Cron.UsageReport.Enabled {
+		entry, err = c.AddFunc("Send usage report", conf.Cron.UsageReport.Schedule, db.SendUsageReport)
+		if err != nil {
+			log.Fatal("Cron.(send usage report): %v", err)
+		}
+		if conf.Cron.UsageReport.RunAtStart {
+			entry.Prev = time.Now()
+			entry.ExecTimes++
+			go db.SendUsageReport()
+		}
+	}
+	if conf.Cron.ActivityDigests.Enabled {
+		entry, err = c.AddFunc("Send activity digests", conf.Cron.ActivityDigests.Schedule, db.SendActivityDigests)
+		if err != nil {
+			log.Fatal("Cron.(send activity digests): %v", err)
+		}
+		if conf.Cron.ActivityDigests.RunAtStart {
+			entry.Prev = time.Now()
+			entry.ExecTimes++
+			go db.SendActivityDigests()
+		}
+	}
+	if conf.Cron.AggregateContributions.Enabled {
+		entry, err = c.AddFunc("Aggregate contributions", conf.Cron.AggregateContributions.Schedule, db.AggregateContributions)
+		if err != nil {
+			log.Fatal("Cron.(aggregate contributions): %v", err)
+		}
+		if conf.Cron.AggregateContributions.RunAtStart {
+			entry.Prev = time.Now()
+			entry.ExecTimes++
+			go db.AggregateContributions()
+		}
+	}
 	c.Start()
 }
 
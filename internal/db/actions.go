@@ -38,6 +38,10 @@ type ActionsStore interface {
 	// regular push also creates a new branch, then another action with type
 	// ActionCreateBranch is created.
 	CommitRepo(ctx context.Context, opts CommitRepoOptions) error
+	// ListAfter returns at most `limit` actions with IDs greater than `afterID`
+	// in ascending order. Only the copies of actors are returned, i.e. one action
+	// for each activity regardless of the number of watchers.
+	ListAfter(ctx context.Context, afterID int64, limit int) ([]*Action, error)
 	// ListByOrganization returns actions of the organization viewable by the actor.
 	// Results are paginated if `afterID` is given.
 	ListByOrganization(ctx context.Context, orgID, actorID, afterID int64) ([]*Action, error)
@@ -164,6 +168,16 @@ func (db *actions) ListByUser(ctx context.Context, userID, actorID, afterID int6
 	return actions, db.listByUser(ctx, userID, actorID, afterID, isProfile).Find(&actions).Error
 }
 
+func (db *actions) ListAfter(ctx context.Context, afterID int64, limit int) ([]*Action, error) {
+	actions := make([]*Action, 0, limit)
+	return actions, db.WithContext(ctx).
+		Where("id > ? AND user_id = act_user_id", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&actions).
+		Error
+}
+
 // notifyWatchers creates rows in action table for watchers who are able to see the action.
 func (db *actions) notifyWatchers(ctx context.Context, act *Action) error {
 	watches, err := NewWatchesStore(db.DB).ListByRepo(ctx, act.RepoID)
@@ -258,7 +272,7 @@ func (db *actions) MirrorSyncPush(ctx context.Context, opts MirrorSyncPushOption
 
 	apiCommits, err := opts.Commits.APIFormat(ctx,
 		NewUsersStore(db.DB),
-		repoutil.RepositoryPath(opts.Owner.Name, opts.Repo.Name),
+		repoutil.ShardRepositoryPath(opts.Repo.StorageShard, opts.Owner.Name, opts.Repo.Name),
 		repoutil.HTMLURL(opts.Owner.Name, opts.Repo.Name),
 	)
 	if err != nil {
@@ -592,7 +606,7 @@ func (db *actions) CommitRepo(ctx context.Context, opts CommitRepoOptions) error
 
 	commits, err := opts.Commits.APIFormat(ctx,
 		NewUsersStore(db.DB),
-		repoutil.RepositoryPath(opts.Owner.Name, opts.Repo.Name),
+		repoutil.ShardRepositoryPath(opts.Repo.StorageShard, opts.Owner.Name, opts.Repo.Name),
 		repoutil.HTMLURL(opts.Owner.Name, opts.Repo.Name),
 	)
 	if err != nil {
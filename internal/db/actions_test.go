@@ -89,6 +89,7 @@ func TestActions(t *testing.T) {
 		test func(*testing.T, *actions)
 	}{
 		{"CommitRepo", actionsCommitRepo},
+		{"ListAfter", actionsListAfter},
 		{"ListByOrganization", actionsListByOrganization},
 		{"ListByUser", actionsListByUser},
 		{"MergePullRequest", actionsMergePullRequest},
@@ -353,6 +354,43 @@ func actionsListByOrganization(t *testing.T, db *actions) {
 	}
 }
 
+func actionsListAfter(t *testing.T, db *actions) {
+	ctx := context.Background()
+
+	alice, err := NewUsersStore(db.DB).Create(ctx, "alice", "alice@example.com", CreateUserOptions{})
+	require.NoError(t, err)
+	bob, err := NewUsersStore(db.DB).Create(ctx, "bob", "bob@example.com", CreateUserOptions{})
+	require.NoError(t, err)
+	repo, err := NewReposStore(db.DB).Create(ctx,
+		alice.ID,
+		CreateRepoOptions{
+			Name: "example",
+		},
+	)
+	require.NoError(t, err)
+	err = db.WithContext(ctx).Create(&Watch{UserID: bob.ID, RepoID: repo.ID}).Error
+	require.NoError(t, err)
+
+	for _, name := range []string{"a", "b", "c"} {
+		err = db.RenameRepo(ctx, alice, alice, name, repo)
+		require.NoError(t, err)
+	}
+
+	got, err := db.ListAfter(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	for _, a := range got {
+		assert.Equal(t, alice.ID, a.UserID)
+	}
+	assert.Equal(t, "a", got[0].Content)
+	assert.Equal(t, "c", got[2].Content)
+
+	got, err = db.ListAfter(ctx, got[0].ID, 1)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "b", got[0].Content)
+}
+
 func actionsListByUser(t *testing.T, db *actions) {
 	if os.Getenv("GOGS_DATABASE_TYPE") != "postgres" {
 		t.Skip("Skipping testing with not using PostgreSQL")
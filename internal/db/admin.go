@@ -14,32 +14,93 @@ import (
 	log "unknwon.dev/clog/v2"
 	"xorm.io/xorm"
 
+	"gogs.io/gogs/internal/cryptoutil"
+	"gogs.io/gogs/internal/errutil"
 	"gogs.io/gogs/internal/tool"
 )
 
+// NoticeType is the module that created a notice.
 type NoticeType int
 
 const (
 	NOTICE_REPOSITORY NoticeType = iota + 1
+	NOTICE_ANTIVIRUS
+	NOTICE_MIRROR
+	NOTICE_SECURITY
 )
 
+// Source returns the name of the module that created notices of the type.
+func (t NoticeType) Source() string {
+	switch t {
+	case NOTICE_REPOSITORY:
+		return "repository"
+	case NOTICE_ANTIVIRUS:
+		return "antivirus"
+	case NOTICE_MIRROR:
+		return "mirror"
+	case NOTICE_SECURITY:
+		return "security"
+	default:
+		return "unknown"
+	}
+}
+
+// NoticeLevel is the severity of a notice.
+type NoticeLevel string
+
+const (
+	NoticeLevelInfo  NoticeLevel = "info"
+	NoticeLevelWarn  NoticeLevel = "warn"
+	NoticeLevelError NoticeLevel = "error"
+)
+
+// IsValid returns true if the level is known.
+func (l NoticeLevel) IsValid() bool {
+	switch l {
+	case NoticeLevelInfo, NoticeLevelWarn, NoticeLevelError:
+		return true
+	}
+	return false
+}
+
 // Notice represents a system notice for admin.
 type Notice struct {
-	ID          int64
-	Type        NoticeType
-	Description string    `xorm:"TEXT"`
+	ID    int64
+	Type  NoticeType
+	Level NoticeLevel `xorm:"VARCHAR(8) NOT NULL DEFAULT 'error'"`
+	// Digest identifies repeats of the notice, which are counted by Count
+	// instead of creating new notices until the notice is acknowledged.
+	Digest      string `xorm:"VARCHAR(40) INDEX"`
+	Description string `xorm:"TEXT"`
+	Count       int64  `xorm:"NOT NULL DEFAULT 1"`
+
+	// AckedByID is the ID of the admin who acknowledged the notice, 0 if the
+	// notice is not acknowledged.
+	AckedByID int64     `xorm:"NOT NULL DEFAULT 0"`
+	AckedBy   *User     `xorm:"-" json:"-"`
+	Acked     time.Time `xorm:"-" json:"-"`
+	AckedUnix int64
+
 	Created     time.Time `xorm:"-" json:"-"`
 	CreatedUnix int64
+	// Updated is the time of the last repeat of the notice.
+	Updated     time.Time `xorm:"-" json:"-"`
+	UpdatedUnix int64
 }
 
 func (n *Notice) BeforeInsert() {
 	n.CreatedUnix = time.Now().Unix()
+	n.UpdatedUnix = n.CreatedUnix
 }
 
 func (n *Notice) AfterSet(colName string, _ xorm.Cell) {
 	switch colName {
+	case "acked_unix":
+		n.Acked = time.Unix(n.AckedUnix, 0).Local()
 	case "created_unix":
 		n.Created = time.Unix(n.CreatedUnix, 0).Local()
+	case "updated_unix":
+		n.Updated = time.Unix(n.UpdatedUnix, 0).Local()
 	}
 }
 
@@ -48,24 +109,48 @@ func (n *Notice) TrStr() string {
 	return "admin.notices.type_" + com.ToStr(n.Type)
 }
 
-// CreateNotice creates new system notice.
-func CreateNotice(tp NoticeType, desc string) error {
+// IsAcked returns true if the notice has been acknowledged.
+func (n *Notice) IsAcked() bool {
+	return n.AckedByID > 0
+}
+
+// noticeDigest returns the digest of a notice for detecting repeats.
+func noticeDigest(tp NoticeType, level NoticeLevel, desc string) string {
+	return cryptoutil.SHA1(fmt.Sprintf("%d:%s:%s", tp, level, desc))
+}
+
+// CreateNotice creates new system notice. Repeats of an unacknowledged notice
+// with the same type, level and description increase its count instead.
+func CreateNotice(tp NoticeType, level NoticeLevel, desc string) error {
 	// Prevent panic if database connection is not available at this point
 	if x == nil {
 		return fmt.Errorf("could not save notice due database connection not being available: %d %s", tp, desc)
 	}
 
+	digest := noticeDigest(tp, level, desc)
+	result, err := x.Exec("UPDATE notice SET count = count + 1, updated_unix = ? WHERE digest = ? AND acked_by_id = 0",
+		time.Now().Unix(), digest)
+	if err != nil {
+		return fmt.Errorf("count repeat: %v", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n > 0 {
+		return nil
+	}
+
 	n := &Notice{
 		Type:        tp,
+		Level:       level,
+		Digest:      digest,
 		Description: desc,
+		Count:       1,
 	}
-	_, err := x.Insert(n)
+	_, err = x.Insert(n)
 	return err
 }
 
 // CreateRepositoryNotice creates new system notice with type NOTICE_REPOSITORY.
 func CreateRepositoryNotice(desc string) error {
-	return CreateNotice(NOTICE_REPOSITORY, desc)
+	return CreateNotice(NOTICE_REPOSITORY, NoticeLevelError, desc)
 }
 
 // RemoveAllWithNotice removes all directories in given path and
@@ -74,22 +159,106 @@ func RemoveAllWithNotice(title, path string) {
 	if err := os.RemoveAll(path); err != nil {
 		desc := fmt.Sprintf("%s [%s]: %v", title, path, err)
 		log.Warn(desc)
-		if err = CreateRepositoryNotice(desc); err != nil {
-			log.Error("CreateRepositoryNotice: %v", err)
+		if err = CreateNotice(NOTICE_REPOSITORY, NoticeLevelWarn, desc); err != nil {
+			log.Error("CreateNotice: %v", err)
 		}
 	}
 }
 
-// CountNotices returns number of notices.
-func CountNotices() int64 {
-	count, _ := x.Count(new(Notice))
+// NoticesOptions contains options to list notices.
+type NoticesOptions struct {
+	// Level filters notices by the level, notices of all levels are listed if
+	// empty.
+	Level NoticeLevel
+	// Unacked filters out acknowledged notices.
+	Unacked  bool
+	Page     int
+	PageSize int
+}
+
+func (opts NoticesOptions) where() *xorm.Session {
+	sess := x.Where("1 = 1")
+	if opts.Level != "" {
+		sess.And("level = ?", opts.Level)
+	}
+	if opts.Unacked {
+		sess.And("acked_by_id = 0")
+	}
+	return sess
+}
+
+// CountNotices returns number of notices matching the options.
+func CountNotices(opts NoticesOptions) int64 {
+	count, _ := opts.where().Count(new(Notice))
 	return count
 }
 
-// Notices returns number of notices in given page.
-func Notices(page, pageSize int) ([]*Notice, error) {
-	notices := make([]*Notice, 0, pageSize)
-	return notices, x.Limit(pageSize, (page-1)*pageSize).Desc("id").Find(&notices)
+// Notices returns notices matching the options in given page, with admins
+// who acknowledged them loaded.
+func Notices(opts NoticesOptions) ([]*Notice, error) {
+	notices := make([]*Notice, 0, opts.PageSize)
+	err := opts.where().Limit(opts.PageSize, (opts.Page-1)*opts.PageSize).Desc("id").Find(&notices)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range notices {
+		if !n.IsAcked() {
+			continue
+		}
+		n.AckedBy, err = GetUserByID(n.AckedByID)
+		if err != nil {
+			if !IsErrUserNotExist(err) {
+				return nil, fmt.Errorf("get user [id: %d]: %v", n.AckedByID, err)
+			}
+			n.AckedBy = NewGhostUser()
+		}
+	}
+	return notices, nil
+}
+
+// AckNotices marks notices with given IDs as acknowledged by the admin.
+// Notices that have been acknowledged are left unchanged.
+func AckNotices(ids []int64, adminID int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := x.In("id", ids).And("acked_by_id = 0").Cols("acked_by_id", "acked_unix").Update(&Notice{
+		AckedByID: adminID,
+		AckedUnix: time.Now().Unix(),
+	})
+	return err
+}
+
+// GetNoticeByID returns the notice with given ID.
+func GetNoticeByID(id int64) (*Notice, error) {
+	n := new(Notice)
+	has, err := x.ID(id).Get(n)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrNoticeNotExist{args: errutil.Args{"noticeID": id}}
+	}
+	return n, nil
+}
+
+var _ errutil.NotFound = (*ErrNoticeNotExist)(nil)
+
+type ErrNoticeNotExist struct {
+	args errutil.Args
+}
+
+func IsErrNoticeNotExist(err error) bool {
+	_, ok := err.(ErrNoticeNotExist)
+	return ok
+}
+
+func (err ErrNoticeNotExist) Error() string {
+	return fmt.Sprintf("notice does not exist: %v", err.args)
+}
+
+func (ErrNoticeNotExist) NotFound() bool {
+	return true
 }
 
 // DeleteNotice deletes a system notice by given ID.
@@ -0,0 +1,170 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	gouuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/cryptoutil"
+	"gogs.io/gogs/internal/errutil"
+)
+
+// AdminRecoveryTokensStore is the persistent interface for admin recovery
+// tokens.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type AdminRecoveryTokensStore interface {
+	// Create issues a new admin recovery token to the user, valid for the given
+	// duration. Unused tokens previously issued to the user are expired, so that
+	// only the latest one can be used. The raw token is only available via the
+	// Token field of the returned admin recovery token.
+	Create(ctx context.Context, userID int64, ttl time.Duration) (*AdminRecoveryToken, error)
+	// Use marks the unused and unexpired admin recovery token with given raw
+	// token as used from the IP address, and returns the token. Each token can
+	// only be used once. It returns ErrAdminRecoveryTokenNotExist when not found,
+	// used or expired.
+	Use(ctx context.Context, token, ip string) (*AdminRecoveryToken, error)
+}
+
+var AdminRecoveryTokens AdminRecoveryTokensStore
+
+// MaxAdminRecoveryTokenTTL is the maximum duration that an admin recovery token
+// can be valid for.
+const MaxAdminRecoveryTokenTTL = time.Hour
+
+// AdminRecoveryToken is a single-use token issued on the command line to sign in
+// as a site admin without password or two-factor authentication, for operators
+// who have been locked out. Only the hash of the token is stored, and tokens are
+// kept after being used for auditing.
+type AdminRecoveryToken struct {
+	ID        int64     `gorm:"primaryKey"`
+	UserID    int64     `gorm:"index;not null"`
+	SHA256    string    `gorm:"type:VARCHAR(64);unique;not null"`
+	IsUsed    bool      `gorm:"not null"`
+	UsedIP    string    `gorm:"type:VARCHAR(64);not null"`
+	UsedAt    time.Time `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null"`
+
+	// Token is the raw token, which is only set right after creation.
+	Token string `gorm:"-" json:"-"`
+}
+
+var _ AdminRecoveryTokensStore = (*adminRecoveryTokens)(nil)
+
+type adminRecoveryTokens struct {
+	*gorm.DB
+}
+
+// NewAdminRecoveryTokensStore returns a persistent interface for admin recovery
+// tokens with given database connection.
+func NewAdminRecoveryTokensStore(db *gorm.DB) AdminRecoveryTokensStore {
+	return &adminRecoveryTokens{DB: db}
+}
+
+func (db *adminRecoveryTokens) Create(ctx context.Context, userID int64, ttl time.Duration) (*AdminRecoveryToken, error) {
+	if ttl <= 0 || ttl > MaxAdminRecoveryTokenTTL {
+		return nil, errors.Errorf("TTL must be between 0 and %s", MaxAdminRecoveryTokenTTL)
+	}
+
+	now := db.NowFunc()
+	token := cryptoutil.SHA1(gouuid.NewV4().String())
+	t := &AdminRecoveryToken{
+		UserID:    userID,
+		SHA256:    cryptoutil.SHA256(token),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(new(AdminRecoveryToken)).
+			Where("user_id = ? AND is_used = ? AND expires_at > ?", userID, false, now).
+			Update("expires_at", now).
+			Error
+		if err != nil {
+			return errors.Wrap(err, "expire unused")
+		}
+		return tx.Create(t).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	t.Token = token
+	return t, nil
+}
+
+var _ errutil.NotFound = (*ErrAdminRecoveryTokenNotExist)(nil)
+
+type ErrAdminRecoveryTokenNotExist struct {
+	args errutil.Args
+}
+
+func IsErrAdminRecoveryTokenNotExist(err error) bool {
+	_, ok := err.(ErrAdminRecoveryTokenNotExist)
+	return ok
+}
+
+func (err ErrAdminRecoveryTokenNotExist) Error() string {
+	return fmt.Sprintf("admin recovery token does not exist: %v", err.args)
+}
+
+func (ErrAdminRecoveryTokenNotExist) NotFound() bool {
+	return true
+}
+
+func (db *adminRecoveryTokens) Use(ctx context.Context, token, ip string) (*AdminRecoveryToken, error) {
+	now := db.NowFunc()
+	sha256 := cryptoutil.SHA256(token)
+
+	// The condition of the update guarantees that concurrent requests cannot use
+	// the same token more than once.
+	result := db.WithContext(ctx).Model(new(AdminRecoveryToken)).
+		Where("sha256 = ? AND is_used = ? AND expires_at > ?", sha256, false, now).
+		Updates(map[string]interface{}{
+			"is_used": true,
+			"used_ip": ip,
+			"used_at": now,
+		})
+	if result.Error != nil {
+		return nil, errors.Wrap(result.Error, "mark as used")
+	} else if result.RowsAffected == 0 {
+		return nil, ErrAdminRecoveryTokenNotExist{args: errutil.Args{}}
+	}
+
+	t := new(AdminRecoveryToken)
+	return t, db.WithContext(ctx).Where("sha256 = ?", sha256).First(t).Error
+}
+
+// IssueAdminRecoveryURL issues an admin recovery token to the site admin with
+// given username, and returns the URL to sign in with the token. Issuing is
+// recorded as a system notice.
+func IssueAdminRecoveryURL(ctx context.Context, externalURL, username string, ttl time.Duration) (string, *AdminRecoveryToken, error) {
+	u, err := Users.GetByUsername(ctx, username)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "get user")
+	} else if !u.IsAdmin {
+		return "", nil, errors.Errorf("user %q is not a site admin", u.Name)
+	} else if !u.IsActive || u.ProhibitLogin {
+		return "", nil, errors.Errorf("user %q is not allowed to sign in", u.Name)
+	}
+
+	t, err := AdminRecoveryTokens.Create(ctx, u.ID, ttl)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "create token")
+	}
+
+	desc := fmt.Sprintf("Admin recovery sign-in URL for %q was issued on the command line, valid until %s.", u.Name, t.ExpiresAt.Format(time.RFC3339))
+	err = CreateNotice(NOTICE_SECURITY, NoticeLevelWarn, desc)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "create notice")
+	}
+	return externalURL + "user/recover?code=" + t.Token, t, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+func TestAdminRecoveryTokens(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(AdminRecoveryToken)}
+	db := &adminRecoveryTokens{
+		DB: dbtest.NewDB(t, "adminRecoveryTokens", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *adminRecoveryTokens)
+	}{
+		{"Create", adminRecoveryTokensCreate},
+		{"Use", adminRecoveryTokensUse},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func adminRecoveryTokensCreate(t *testing.T, db *adminRecoveryTokens) {
+	ctx := context.Background()
+
+	_, err := db.Create(ctx, 1, 0)
+	assert.Error(t, err)
+	_, err = db.Create(ctx, 1, MaxAdminRecoveryTokenTTL+time.Minute)
+	assert.Error(t, err)
+
+	first, err := db.Create(ctx, 1, 15*time.Minute)
+	require.NoError(t, err)
+	assert.Len(t, first.Token, 40)
+	assert.NotEqual(t, first.Token, first.SHA256)
+	assert.Equal(t, db.NowFunc().Add(15*time.Minute).Unix(), first.ExpiresAt.Unix())
+
+	other, err := db.Create(ctx, 2, 15*time.Minute)
+	require.NoError(t, err)
+
+	// Issuing a new token expires unused tokens of the same user.
+	second, err := db.Create(ctx, 1, 15*time.Minute)
+	require.NoError(t, err)
+
+	_, err = db.Use(ctx, first.Token, "127.0.0.1")
+	assert.True(t, IsErrAdminRecoveryTokenNotExist(err))
+	_, err = db.Use(ctx, second.Token, "127.0.0.1")
+	require.NoError(t, err)
+	_, err = db.Use(ctx, other.Token, "127.0.0.1")
+	require.NoError(t, err)
+}
+
+func adminRecoveryTokensUse(t *testing.T, db *adminRecoveryTokens) {
+	ctx := context.Background()
+
+	_, err := db.Use(ctx, "404", "127.0.0.1")
+	assert.True(t, IsErrAdminRecoveryTokenNotExist(err))
+
+	token, err := db.Create(ctx, 1, 15*time.Minute)
+	require.NoError(t, err)
+
+	got, err := db.Use(ctx, token.Token, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), got.UserID)
+	assert.True(t, got.IsUsed)
+	assert.Equal(t, "10.0.0.1", got.UsedIP)
+
+	// Each token can only be used once.
+	_, err = db.Use(ctx, token.Token, "10.0.0.1")
+	assert.True(t, IsErrAdminRecoveryTokenNotExist(err))
+
+	// Expired tokens cannot be used.
+	expired, err := db.Create(ctx, 2, 15*time.Minute)
+	require.NoError(t, err)
+	err = db.Model(new(AdminRecoveryToken)).
+		Where("id = ?", expired.ID).
+		Update("expires_at", db.NowFunc().Add(-time.Minute)).
+		Error
+	require.NoError(t, err)
+	_, err = db.Use(ctx, expired.Token, "10.0.0.1")
+	assert.True(t, IsErrAdminRecoveryTokenNotExist(err))
+}
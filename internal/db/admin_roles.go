@@ -0,0 +1,168 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"gogs.io/gogs/internal/errutil"
+)
+
+// AdminRolesStore is the persistent interface for admin roles assigned to
+// users, which delegate parts of site administration without granting full
+// control of the site.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type AdminRolesStore interface {
+	// Assign assigns the admin role to the user. It is a no-op when the role has
+	// already been assigned. It returns ErrInvalidAdminRole when the role is not
+	// one of known roles.
+	Assign(ctx context.Context, userID int64, role AdminRole) error
+	// ListByUserID returns admin roles assigned to the user, ordered by role.
+	ListByUserID(ctx context.Context, userID int64) ([]AdminRole, error)
+	// Revoke revokes the admin role from the user. It is a no-op when the role
+	// has not been assigned.
+	Revoke(ctx context.Context, userID int64, role AdminRole) error
+	// Set replaces admin roles of the user with given roles. It returns
+	// ErrInvalidAdminRole when any of roles is not one of known roles.
+	Set(ctx context.Context, userID int64, roles []AdminRole) error
+}
+
+var AdminRoles AdminRolesStore
+
+// AdminRole is a role of site administration.
+type AdminRole string
+
+const (
+	// AdminRoleUserAdmin manages users, organizations, teams and profile fields.
+	AdminRoleUserAdmin AdminRole = "user_admin"
+	// AdminRoleRepoAdmin manages repositories, repository templates and usage.
+	AdminRoleRepoAdmin AdminRole = "repo_admin"
+	// AdminRoleHooksAdmin resynchronizes Git hooks of all repositories, and
+	// grants the permission of editing Git hooks to users.
+	AdminRoleHooksAdmin AdminRole = "hooks_admin"
+	// AdminRoleAuditor has read-only access to the admin panel.
+	AdminRoleAuditor AdminRole = "auditor"
+)
+
+// AllAdminRoles is the list of all admin roles.
+var AllAdminRoles = []AdminRole{
+	AdminRoleUserAdmin,
+	AdminRoleRepoAdmin,
+	AdminRoleHooksAdmin,
+	AdminRoleAuditor,
+}
+
+// IsValid returns true if the role is one of known roles.
+func (r AdminRole) IsValid() bool {
+	for _, role := range AllAdminRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// AdminRoleAssignment is an admin role assigned to a user.
+type AdminRoleAssignment struct {
+	ID        int64     `gorm:"primaryKey"`
+	UserID    int64     `gorm:"uniqueIndex:admin_role_assignment_user_role_unique;not null"`
+	Role      AdminRole `gorm:"type:VARCHAR(20);uniqueIndex:admin_role_assignment_user_role_unique;not null"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// HasAdminPrivileges returns true if the user is a site admin or has any admin
+// role.
+func HasAdminPrivileges(ctx context.Context, u *User) (bool, error) {
+	if u.IsAdmin {
+		return true, nil
+	}
+
+	roles, err := AdminRoles.ListByUserID(ctx, u.ID)
+	if err != nil {
+		return false, err
+	}
+	return len(roles) > 0, nil
+}
+
+var _ AdminRolesStore = (*adminRoles)(nil)
+
+type adminRoles struct {
+	*gorm.DB
+}
+
+// NewAdminRolesStore returns a persistent interface for admin roles with given
+// database connection.
+func NewAdminRolesStore(db *gorm.DB) AdminRolesStore {
+	return &adminRoles{DB: db}
+}
+
+type ErrInvalidAdminRole struct {
+	args errutil.Args
+}
+
+func IsErrInvalidAdminRole(err error) bool {
+	_, ok := err.(ErrInvalidAdminRole)
+	return ok
+}
+
+func (err ErrInvalidAdminRole) Error() string {
+	return fmt.Sprintf("invalid admin role: %v", err.args)
+}
+
+func assignAdminRole(tx *gorm.DB, userID int64, role AdminRole) error {
+	if !role.IsValid() {
+		return ErrInvalidAdminRole{args: errutil.Args{"role": role}}
+	}
+
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&AdminRoleAssignment{
+			UserID: userID,
+			Role:   role,
+		}).
+		Error
+}
+
+func (db *adminRoles) Assign(ctx context.Context, userID int64, role AdminRole) error {
+	return assignAdminRole(db.WithContext(ctx), userID, role)
+}
+
+func (db *adminRoles) ListByUserID(ctx context.Context, userID int64) ([]AdminRole, error) {
+	var roles []AdminRole
+	return roles, db.WithContext(ctx).
+		Model(new(AdminRoleAssignment)).
+		Where("user_id = ?", userID).
+		Order("role ASC").
+		Pluck("role", &roles).
+		Error
+}
+
+func (db *adminRoles) Revoke(ctx context.Context, userID int64, role AdminRole) error {
+	return db.WithContext(ctx).
+		Where("user_id = ? AND role = ?", userID, role).
+		Delete(new(AdminRoleAssignment)).
+		Error
+}
+
+func (db *adminRoles) Set(ctx context.Context, userID int64, roles []AdminRole) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ?", userID).Delete(new(AdminRoleAssignment)).Error
+		if err != nil {
+			return err
+		}
+
+		for _, role := range roles {
+			if err = assignAdminRole(tx, userID, role); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
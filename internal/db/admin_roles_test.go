@@ -0,0 +1,114 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/errutil"
+)
+
+func TestAdminRoles(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(AdminRoleAssignment)}
+	db := &adminRoles{
+		DB: dbtest.NewDB(t, "adminRoles", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *adminRoles)
+	}{
+		{"Assign", adminRolesAssign},
+		{"Revoke", adminRolesRevoke},
+		{"Set", adminRolesSet},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func adminRolesAssign(t *testing.T, db *adminRoles) {
+	ctx := context.Background()
+
+	err := db.Assign(ctx, 1, AdminRoleUserAdmin)
+	require.NoError(t, err)
+	err = db.Assign(ctx, 1, AdminRoleAuditor)
+	require.NoError(t, err)
+
+	// Assigning the same role again is a no-op.
+	err = db.Assign(ctx, 1, AdminRoleUserAdmin)
+	require.NoError(t, err)
+
+	err = db.Assign(ctx, 1, "superuser")
+	wantErr := ErrInvalidAdminRole{args: errutil.Args{"role": AdminRole("superuser")}}
+	assert.Equal(t, wantErr, err)
+
+	roles, err := db.ListByUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []AdminRole{AdminRoleAuditor, AdminRoleUserAdmin}, roles)
+}
+
+func adminRolesRevoke(t *testing.T, db *adminRoles) {
+	ctx := context.Background()
+
+	err := db.Assign(ctx, 1, AdminRoleRepoAdmin)
+	require.NoError(t, err)
+	err = db.Assign(ctx, 2, AdminRoleRepoAdmin)
+	require.NoError(t, err)
+
+	err = db.Revoke(ctx, 1, AdminRoleRepoAdmin)
+	require.NoError(t, err)
+	// Revoking a role that is not assigned is a no-op.
+	err = db.Revoke(ctx, 1, AdminRoleHooksAdmin)
+	require.NoError(t, err)
+
+	roles, err := db.ListByUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, roles)
+
+	roles, err = db.ListByUserID(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []AdminRole{AdminRoleRepoAdmin}, roles)
+}
+
+func adminRolesSet(t *testing.T, db *adminRoles) {
+	ctx := context.Background()
+
+	err := db.Set(ctx, 1, []AdminRole{AdminRoleUserAdmin, AdminRoleHooksAdmin})
+	require.NoError(t, err)
+	err = db.Set(ctx, 1, []AdminRole{AdminRoleHooksAdmin, AdminRoleRepoAdmin})
+	require.NoError(t, err)
+
+	roles, err := db.ListByUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []AdminRole{AdminRoleHooksAdmin, AdminRoleRepoAdmin}, roles)
+
+	// Roles are left unchanged when any of new roles is invalid.
+	err = db.Set(ctx, 1, []AdminRole{AdminRoleAuditor, "superuser"})
+	wantErr := ErrInvalidAdminRole{args: errutil.Args{"role": AdminRole("superuser")}}
+	assert.Equal(t, wantErr, err)
+
+	roles, err = db.ListByUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []AdminRole{AdminRoleHooksAdmin, AdminRoleRepoAdmin}, roles)
+}
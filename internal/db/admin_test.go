@@ -0,0 +1,37 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoticeLevel_IsValid(t *testing.T) {
+	for _, level := range []NoticeLevel{NoticeLevelInfo, NoticeLevelWarn, NoticeLevelError} {
+		assert.True(t, level.IsValid(), level)
+	}
+	assert.False(t, NoticeLevel("").IsValid())
+	assert.False(t, NoticeLevel("fatal").IsValid())
+}
+
+func TestNoticeType_Source(t *testing.T) {
+	assert.Equal(t, "repository", NOTICE_REPOSITORY.Source())
+	assert.Equal(t, "antivirus", NOTICE_ANTIVIRUS.Source())
+	assert.Equal(t, "mirror", NOTICE_MIRROR.Source())
+	assert.Equal(t, "security", NOTICE_SECURITY.Source())
+	assert.Equal(t, "unknown", NoticeType(0).Source())
+}
+
+func TestNoticeDigest(t *testing.T) {
+	digest := noticeDigest(NOTICE_MIRROR, NoticeLevelWarn, "remote is inaccessible")
+	assert.Len(t, digest, 40)
+	assert.Equal(t, digest, noticeDigest(NOTICE_MIRROR, NoticeLevelWarn, "remote is inaccessible"))
+
+	// Notices of different levels or types are not merged.
+	assert.NotEqual(t, digest, noticeDigest(NOTICE_MIRROR, NoticeLevelError, "remote is inaccessible"))
+	assert.NotEqual(t, digest, noticeDigest(NOTICE_REPOSITORY, NoticeLevelWarn, "remote is inaccessible"))
+}
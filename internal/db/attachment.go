@@ -55,11 +55,23 @@ func (attach *Attachment) LocalPath() string {
 
 // NewAttachment creates a new attachment object.
 func NewAttachment(name string, buf []byte, file multipart.File) (_ *Attachment, err error) {
-	attach := &Attachment{
+	return newAttachment(&Attachment{
 		UUID: gouuid.NewV4().String(),
 		Name: name,
-	}
+	}, buf, file)
+}
+
+// NewReleaseAttachment creates a new attachment object that belongs to the
+// given release.
+func NewReleaseAttachment(releaseID int64, name string, buf []byte, file multipart.File) (*Attachment, error) {
+	return newAttachment(&Attachment{
+		UUID:      gouuid.NewV4().String(),
+		ReleaseID: releaseID,
+		Name:      name,
+	}, buf, file)
+}
 
+func newAttachment(attach *Attachment, buf []byte, file multipart.File) (_ *Attachment, err error) {
 	localPath := attach.LocalPath()
 	if err = os.MkdirAll(path.Dir(localPath), os.ModePerm); err != nil {
 		return nil, fmt.Errorf("MkdirAll: %v", err)
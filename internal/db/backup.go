@@ -20,7 +20,6 @@ import (
 	"xorm.io/core"
 	"xorm.io/xorm"
 
-	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/osutil"
 )
 
@@ -95,8 +94,88 @@ func dumpTable(ctx context.Context, db *gorm.DB, table interface{}, w io.Writer)
 		}
 
 		switch e := elem.(type) {
+		case *AdminRecoveryToken:
+			e.UsedAt = e.UsedAt.UTC()
+			e.ExpiresAt = e.ExpiresAt.UTC()
+			e.CreatedAt = e.CreatedAt.UTC()
+		case *AdminRoleAssignment:
+			e.CreatedAt = e.CreatedAt.UTC()
+		case *CommitMessage:
+			e.AuthoredAt = e.AuthoredAt.UTC()
+			e.CommittedAt = e.CommittedAt.UTC()
+		case *CommitStatus:
+			e.CreatedAt = e.CreatedAt.UTC()
+		case *Deployment:
+			e.CreatedAt = e.CreatedAt.UTC()
+			e.UpdatedAt = e.UpdatedAt.UTC()
+		case *DeploymentStatus:
+			e.CreatedAt = e.CreatedAt.UTC()
+		case *DeviceAuthorization:
+			e.PolledAt = e.PolledAt.UTC()
+			e.ExpiresAt = e.ExpiresAt.UTC()
+			e.CreatedAt = e.CreatedAt.UTC()
+		case *DigestSubscription:
+			e.LastSentAt = e.LastSentAt.UTC()
+			e.CreatedAt = e.CreatedAt.UTC()
+		case *FederatedIdentity:
+			e.CreatedAt = e.CreatedAt.UTC()
+		case *FetchStat:
+			e.UpdatedAt = e.UpdatedAt.UTC()
+		case *GitAccessLog:
+			e.CreatedAt = e.CreatedAt.UTC()
+		case *GPGKey:
+			e.CreatedAt = e.CreatedAt.UTC()
+		case *JobToken:
+			e.ExpiresAt = e.ExpiresAt.UTC()
+			e.CreatedAt = e.CreatedAt.UTC()
 		case *LFSObject:
 			e.CreatedAt = e.CreatedAt.UTC()
+		case *MergeChecklistCheck:
+			e.CreatedAt = e.CreatedAt.UTC()
+		case *MergeQueueEntry:
+			e.CreatedAt = e.CreatedAt.UTC()
+			e.UpdatedAt = e.UpdatedAt.UTC()
+		case *MirrorRefDrift:
+			e.CheckedAt = e.CheckedAt.UTC()
+		case *OrgDomain:
+			e.CreatedAt = e.CreatedAt.UTC()
+		case *OrgRuleset:
+			e.CreatedAt = e.CreatedAt.UTC()
+			e.UpdatedAt = e.UpdatedAt.UTC()
+		case *OrgSecurityPolicy:
+			e.UpdatedAt = e.UpdatedAt.UTC()
+		case *PagesSite:
+			e.CreatedAt = e.CreatedAt.UTC()
+			e.UpdatedAt = e.UpdatedAt.UTC()
+		case *PasswordResetToken:
+			e.UsedAt = e.UsedAt.UTC()
+			e.ExpiresAt = e.ExpiresAt.UTC()
+			e.CreatedAt = e.CreatedAt.UTC()
+		case *ProfileField:
+			e.CreatedAt = e.CreatedAt.UTC()
+		case *ProfileFieldValue:
+			e.UpdatedAt = e.UpdatedAt.UTC()
+		case *QueuedEmail:
+			e.NextAttemptAt = e.NextAttemptAt.UTC()
+			e.CreatedAt = e.CreatedAt.UTC()
+			e.UpdatedAt = e.UpdatedAt.UTC()
+		case *Reaction:
+			e.CreatedAt = e.CreatedAt.UTC()
+		case *ReservedName:
+			e.CreatedAt = e.CreatedAt.UTC()
+		case *Runner:
+			e.CreatedAt = e.CreatedAt.UTC()
+		case *SecurityAlert:
+			e.CreatedAt = e.CreatedAt.UTC()
+		case *SecurityPolicyException:
+			e.CreatedAt = e.CreatedAt.UTC()
+			e.UpdatedAt = e.UpdatedAt.UTC()
+		case *Snippet:
+			e.CreatedAt = e.CreatedAt.UTC()
+			e.UpdatedAt = e.UpdatedAt.UTC()
+		case *TeamDiscussion:
+			e.CreatedAt = e.CreatedAt.UTC()
+			e.UpdatedAt = e.UpdatedAt.UTC()
 		}
 
 		err = jsoniter.NewEncoder(w).Encode(elem)
@@ -128,9 +207,7 @@ func dumpLegacyTables(ctx context.Context, dirPath string, verbose bool) error {
 			return fmt.Errorf("create JSON file: %v", err)
 		}
 
-		if err = x.Context(ctx).Asc("id").Iterate(table, func(idx int, bean interface{}) (err error) {
-			return jsoniter.NewEncoder(f).Encode(bean)
-		}); err != nil {
+		if err = dumpLegacyTable(ctx, x, table, f); err != nil {
 			_ = f.Close()
 			return fmt.Errorf("dump table '%s': %v", tableName, err)
 		}
@@ -139,6 +216,17 @@ func dumpLegacyTables(ctx context.Context, dirPath string, verbose bool) error {
 	return nil
 }
 
+func dumpLegacyTable(ctx context.Context, e *xorm.Engine, table interface{}, w io.Writer) error {
+	// Not all tables have the "id" column, e.g. create_repo_options_legacy.
+	sess := e.Context(ctx)
+	for _, col := range e.TableInfo(table).PKColumns() {
+		sess = sess.Asc(col.Name)
+	}
+	return sess.Iterate(table, func(idx int, bean interface{}) (err error) {
+		return jsoniter.NewEncoder(w).Encode(bean)
+	})
+}
+
 // ImportDatabase imports data from backup archive in JSON Lines format.
 func ImportDatabase(ctx context.Context, db *gorm.DB, dirPath string, verbose bool) error {
 	err := importLegacyTables(ctx, dirPath, verbose)
@@ -201,7 +289,7 @@ func importTable(ctx context.Context, db *gorm.DB, table interface{}, r io.Reade
 		"lfs_object": true,
 	}
 
-	scanner := bufio.NewScanner(r)
+	scanner := newRowScanner(r)
 	for scanner.Scan() {
 		// PostgreSQL does not like the null characters (U+0000)
 		cleaned := bytes.ReplaceAll(scanner.Bytes(), []byte("\\u0000"), []byte(""))
@@ -218,8 +306,12 @@ func importTable(ctx context.Context, db *gorm.DB, table interface{}, r io.Reade
 		}
 	}
 
+	if err = scanner.Err(); err != nil {
+		return errors.Wrap(err, "read rows")
+	}
+
 	// PostgreSQL needs manually reset table sequence for auto increment keys
-	if conf.UsePostgreSQL && !skipResetIDSeq[rawTableName] {
+	if db.Dialector.Name() == "postgres" && !skipResetIDSeq[rawTableName] {
 		seqName := rawTableName + "_id_seq"
 		if err = db.WithContext(ctx).Exec(fmt.Sprintf(`SELECT setval('%s', COALESCE((SELECT MAX(id)+1 FROM "%s"), 1), false)`, seqName, rawTableName)).Error; err != nil {
 			return errors.Wrapf(err, "reset table %q.%q", rawTableName, seqName)
@@ -229,13 +321,6 @@ func importTable(ctx context.Context, db *gorm.DB, table interface{}, r io.Reade
 }
 
 func importLegacyTables(ctx context.Context, dirPath string, verbose bool) error {
-	snakeMapper := core.SnakeMapper{}
-
-	skipInsertProcessors := map[string]bool{
-		"mirror":    true,
-		"milestone": true,
-	}
-
 	// Purposely create a local variable to not modify global variable
 	legacyTables := append(legacyTables, new(Version))
 	for _, table := range legacyTables {
@@ -255,61 +340,92 @@ func importLegacyTables(ctx context.Context, dirPath string, verbose bool) error
 			log.Trace("Importing table %q...", tableName)
 		}
 
-		if err := x.DropTables(table); err != nil {
-			return fmt.Errorf("drop table %q: %v", tableName, err)
-		} else if err = x.Sync2(table); err != nil {
-			return fmt.Errorf("sync table %q: %v", tableName, err)
-		}
-
 		f, err := os.Open(tableFile)
 		if err != nil {
 			return fmt.Errorf("open JSON file: %v", err)
 		}
-		rawTableName := x.TableName(table)
-		_, isInsertProcessor := table.(xorm.BeforeInsertProcessor)
-		scanner := bufio.NewScanner(f)
-		for scanner.Scan() {
-			if err = jsoniter.Unmarshal(scanner.Bytes(), table); err != nil {
-				return fmt.Errorf("unmarshal to struct: %v", err)
-			}
+		err = importLegacyTable(ctx, x, table, f)
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("import table %q: %v", tableName, err)
+		}
+	}
+	return nil
+}
 
-			if _, err = x.Insert(table); err != nil {
-				return fmt.Errorf("insert strcut: %v", err)
-			}
+func importLegacyTable(ctx context.Context, e *xorm.Engine, table interface{}, r io.Reader) error {
+	skipInsertProcessors := map[string]bool{
+		"mirror":    true,
+		"milestone": true,
+	}
 
-			var meta struct {
-				ID             int64
-				CreatedUnix    int64
-				DeadlineUnix   int64
-				ClosedDateUnix int64
-			}
-			if err = jsoniter.Unmarshal(scanner.Bytes(), &meta); err != nil {
-				log.Error("Failed to unmarshal to map: %v", err)
-			}
+	if err := e.DropTables(table); err != nil {
+		return fmt.Errorf("drop table: %v", err)
+	} else if err = e.Sync2(table); err != nil {
+		return fmt.Errorf("sync table: %v", err)
+	}
 
-			// Reset created_unix back to the date save in archive because Insert method updates its value
-			if isInsertProcessor && !skipInsertProcessors[rawTableName] {
-				if _, err = x.Exec("UPDATE `"+rawTableName+"` SET created_unix=? WHERE id=?", meta.CreatedUnix, meta.ID); err != nil {
-					log.Error("Failed to reset '%s.created_unix': %v", rawTableName, err)
-				}
-			}
+	rawTableName := e.TableName(table)
+	_, isInsertProcessor := table.(xorm.BeforeInsertProcessor)
+	scanner := newRowScanner(r)
+	for scanner.Scan() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		bean := reflect.New(reflect.TypeOf(table).Elem()).Interface()
+		if err := jsoniter.Unmarshal(scanner.Bytes(), bean); err != nil {
+			return fmt.Errorf("unmarshal to struct: %v", err)
+		}
+
+		if _, err := e.Insert(bean); err != nil {
+			return fmt.Errorf("insert strcut: %v", err)
+		}
+
+		var meta struct {
+			ID             int64
+			CreatedUnix    int64
+			DeadlineUnix   int64
+			ClosedDateUnix int64
+		}
+		if err := jsoniter.Unmarshal(scanner.Bytes(), &meta); err != nil {
+			log.Error("Failed to unmarshal to map: %v", err)
+		}
 
-			switch rawTableName {
-			case "milestone":
-				if _, err = x.Exec("UPDATE `"+rawTableName+"` SET deadline_unix=?, closed_date_unix=? WHERE id=?", meta.DeadlineUnix, meta.ClosedDateUnix, meta.ID); err != nil {
-					log.Error("Failed to reset 'milestone.deadline_unix', 'milestone.closed_date_unix': %v", err)
-				}
+		// Reset created_unix back to the date save in archive because Insert method updates its value
+		if isInsertProcessor && !skipInsertProcessors[rawTableName] {
+			if _, err := e.Exec("UPDATE `"+rawTableName+"` SET created_unix=? WHERE id=?", meta.CreatedUnix, meta.ID); err != nil {
+				log.Error("Failed to reset '%s.created_unix': %v", rawTableName, err)
 			}
 		}
 
-		// PostgreSQL needs manually reset table sequence for auto increment keys
-		if conf.UsePostgreSQL {
-			rawTableName := snakeMapper.Obj2Table(tableName)
-			seqName := rawTableName + "_id_seq"
-			if _, err = x.Exec(fmt.Sprintf(`SELECT setval('%s', COALESCE((SELECT MAX(id)+1 FROM "%s"), 1), false);`, seqName, rawTableName)); err != nil {
-				return fmt.Errorf("reset table %q' sequence: %v", rawTableName, err)
+		switch rawTableName {
+		case "milestone":
+			if _, err := e.Exec("UPDATE `"+rawTableName+"` SET deadline_unix=?, closed_date_unix=? WHERE id=?", meta.DeadlineUnix, meta.ClosedDateUnix, meta.ID); err != nil {
+				log.Error("Failed to reset 'milestone.deadline_unix', 'milestone.closed_date_unix': %v", err)
 			}
 		}
 	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read rows: %v", err)
+	}
+
+	// PostgreSQL needs manually reset table sequence for auto increment keys
+	if e.Dialect().DBType() == core.POSTGRES && e.TableInfo(table).AutoIncrement != "" {
+		seqName := rawTableName + "_id_seq"
+		if _, err := e.Exec(fmt.Sprintf(`SELECT setval('%s', COALESCE((SELECT MAX(id)+1 FROM "%s"), 1), false);`, seqName, rawTableName)); err != nil {
+			return fmt.Errorf("reset table %q' sequence: %v", rawTableName, err)
+		}
+	}
 	return nil
 }
+
+// newRowScanner returns a scanner of rows in JSON Lines format, allowing rows
+// up to 64 MiB.
+func newRowScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	return scanner
+}
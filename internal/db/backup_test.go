@@ -31,8 +31,8 @@ func TestDumpAndImport(t *testing.T) {
 	}
 	t.Parallel()
 
-	if len(Tables) != 5 {
-		t.Fatalf("New table has added (want 5 got %d), please add new tests for the table and update this check", len(Tables))
+	if len(Tables) != 40 {
+		t.Fatalf("New table has added (want 40 got %d), please add new tests for the table and update this check", len(Tables))
 	}
 
 	db := dbtest.NewDB(t, "dumpAndImport", Tables...)
@@ -131,6 +131,202 @@ func setupDBToDump(t *testing.T, db *gorm.DB) {
 			CreatedUnix:  1588568886,
 		},
 
+		&AdminRecoveryToken{
+			UserID:    1,
+			SHA256:    "9a1c2e3f4b5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f7",
+			ExpiresAt: time.Unix(1588569786, 0).UTC(), // 15 minutes later
+			CreatedAt: time.Unix(1588568886, 0).UTC(),
+		},
+		&AdminRecoveryToken{
+			UserID:    1,
+			SHA256:    "1b2c3d4e5f60718293a4b5c6d7e8f9001b2c3d4e5f60718293a4b5c6d7e8f900",
+			IsUsed:    true,
+			UsedIP:    "10.0.0.1",
+			UsedAt:    time.Unix(1588568946, 0).UTC(), // 1 minute later
+			ExpiresAt: time.Unix(1588569786, 0).UTC(), // 15 minutes later
+			CreatedAt: time.Unix(1588568886, 0).UTC(),
+		},
+
+		&AdminRoleAssignment{
+			ID:        1,
+			UserID:    1,
+			Role:      AdminRoleAuditor,
+			CreatedAt: time.Unix(1588568886, 0).UTC(),
+		},
+		&AdminRoleAssignment{
+			ID:        2,
+			UserID:    2,
+			Role:      AdminRoleUserAdmin,
+			CreatedAt: time.Unix(1588568886, 0).UTC(),
+		},
+
+		&CommitMessage{
+			RepoID:         1,
+			SHA:            "0b5f2b9a6f1e4a3c9b0e2f0c5c1d7e8a0b5f2b9a",
+			AuthorName:     "alice",
+			AuthorEmail:    "alice@example.com",
+			AuthoredAt:     time.Unix(1588568886, 0).UTC(),
+			CommitterName:  "bob",
+			CommitterEmail: "bob@example.com",
+			CommittedAt:    time.Unix(1588569486, 0).UTC(),
+			Message:        "Fix the build\n\nThe build was broken.",
+		},
+
+		&CommitStatus{
+			RepoID:      1,
+			SHA:         "0b5f2b9a6f1e4a3c9b0e2f0c5c1d7e8a0b5f2b9a",
+			State:       CommitStatusPending,
+			TargetURL:   "https://ci.example.com/builds/1",
+			Description: "The build is running",
+			Context:     "ci/build",
+			CreatorID:   1,
+			CreatedAt:   time.Unix(1588568886, 0).UTC(),
+		},
+		&CommitStatus{
+			RepoID:      1,
+			SHA:         "0b5f2b9a6f1e4a3c9b0e2f0c5c1d7e8a0b5f2b9a",
+			State:       CommitStatusSuccess,
+			TargetURL:   "https://ci.example.com/builds/1",
+			Description: "The build succeeded",
+			Context:     "ci/build",
+			CreatorID:   1,
+			CreatedAt:   time.Unix(1588569486, 0).UTC(), // 10 minutes later
+		},
+
+		&Deployment{
+			RepoID:      1,
+			Environment: "staging",
+			Ref:         "main",
+			SHA:         "0b5f2b9a6f1e4a3c9b0e2f0c5c1d7e8a0b5f2b9a",
+			Description: "Deploy to staging",
+			State:       DeploymentSuccess,
+			CreatorID:   1,
+			CreatedAt:   time.Unix(1588568886, 0).UTC(),
+			UpdatedAt:   time.Unix(1588569486, 0).UTC(), // 10 minutes later
+		},
+		&Deployment{
+			RepoID:      1,
+			Environment: "production",
+			Ref:         "v1.0.0",
+			SHA:         "0b5f2b9a6f1e4a3c9b0e2f0c5c1d7e8a0b5f2b9a",
+			State:       DeploymentPending,
+			CreatorID:   1,
+			CreatedAt:   time.Unix(1588569486, 0).UTC(),
+			UpdatedAt:   time.Unix(1588569486, 0).UTC(),
+		},
+
+		&DeploymentStatus{
+			DeploymentID: 1,
+			State:        DeploymentInProgress,
+			TargetURL:    "https://cd.example.com/deploys/1",
+			CreatorID:    1,
+			CreatedAt:    time.Unix(1588568946, 0).UTC(), // 1 minute later
+		},
+		&DeploymentStatus{
+			DeploymentID: 1,
+			State:        DeploymentSuccess,
+			TargetURL:    "https://cd.example.com/deploys/1",
+			Description:  "Deployed to https://staging.example.com",
+			CreatorID:    1,
+			CreatedAt:    time.Unix(1588569486, 0).UTC(), // 10 minutes later
+		},
+
+		&DeviceAuthorization{
+			ClientID:         "git-credential",
+			DeviceCodeSHA256: cryptoutil.SHA256(cryptoutil.SHA1("0b5f2b9a-6f1e-4a3c-9b0e-2f0c5c1d7e8a")),
+			UserCode:         "BCDF-GHJK",
+			Status:           DeviceAuthorizationPending,
+			PolledAt:         time.Unix(1588568886, 0).UTC(),
+			ExpiresAt:        time.Unix(1588569786, 0).UTC(), // 15 minutes later
+			CreatedAt:        time.Unix(1588568886, 0).UTC(),
+		},
+		&DeviceAuthorization{
+			ClientID:         "gogs-cli",
+			DeviceCodeSHA256: cryptoutil.SHA256(cryptoutil.SHA1("6d1a3e0f-2c4b-4f8e-a7d9-3b5e8c0f1a2d")),
+			UserCode:         "LMNP-QRST",
+			UserID:           1,
+			Status:           DeviceAuthorizationApproved,
+			PolledAt:         time.Unix(1588568946, 0).UTC(), // 1 minute later
+			ExpiresAt:        time.Unix(1588569786, 0).UTC(), // 15 minutes later
+			CreatedAt:        time.Unix(1588568886, 0).UTC(),
+		},
+
+		&DigestSubscription{
+			UserID:     1,
+			Frequency:  DigestDaily,
+			LastSentAt: time.Unix(1588568886, 0).UTC(),
+			CreatedAt:  time.Unix(1588568886, 0).UTC(),
+		},
+		&DigestSubscription{
+			UserID:     2,
+			Frequency:  DigestWeekly,
+			LastSentAt: time.Unix(1588568946, 0).UTC(), // 1 minute later
+			CreatedAt:  time.Unix(1588568946, 0).UTC(),
+		},
+
+		&FederatedIdentity{
+			UserID:        1,
+			CentralUserID: 42,
+			CreatedAt:     time.Unix(1588568886, 0).UTC(),
+		},
+		&FederatedIdentity{
+			UserID:        2,
+			CentralUserID: 7,
+			CreatedAt:     time.Unix(1588568946, 0).UTC(), // 1 minute later
+		},
+
+		&FetchStat{
+			Protocol:  "http",
+			Filter:    "blob:none",
+			Count:     3,
+			UpdatedAt: time.Unix(1588568886, 0).UTC(),
+		},
+		&FetchStat{
+			Protocol:  "ssh",
+			IsShallow: true,
+			Count:     1,
+			UpdatedAt: time.Unix(1588568946, 0).UTC(), // 1 minute later
+		},
+
+		&GitAccessLog{
+			RepoID:    1,
+			UserID:    1,
+			UserName:  "alice",
+			Protocol:  "ssh",
+			Operation: GitAccessPush,
+			Bytes:     1024,
+			Duration:  1500 * time.Millisecond,
+			Summary:   "refs/heads/main",
+			CreatedAt: time.Unix(1588568886, 0).UTC(),
+		},
+		&GitAccessLog{
+			RepoID:    1,
+			Protocol:  "http",
+			Operation: GitAccessClone,
+			Bytes:     4096,
+			Duration:  200 * time.Millisecond,
+			Summary:   "want 1, have 0, filter blob:none",
+			CreatedAt: time.Unix(1588568946, 0).UTC(), // 1 minute later
+		},
+
+		&GPGKey{
+			OwnerID:     1,
+			KeyID:       "4AEE18F83AFDEB23",
+			Fingerprint: "5DE3E0509C47EA3CF04A42D34AEE18F83AFDEB23",
+			Emails:      "alice@example.com",
+			Content:     "-----BEGIN PGP PUBLIC KEY BLOCK-----\n\nxsBNBFmUaEEBCACzXx2nwNUQ\n-----END PGP PUBLIC KEY BLOCK-----\n",
+			CreatedAt:   time.Unix(1588568886, 0).UTC(),
+		},
+
+		&JobToken{
+			RunnerID:  1,
+			RepoID:    1,
+			Mode:      AccessModeRead,
+			SHA256:    cryptoutil.SHA256("7f3c1a9e5b2d4f6a8c0e1b3d5f7a9c2e4b6d8f0a"),
+			ExpiresAt: time.Unix(1588572486, 0).UTC(), // 1 hour later
+			CreatedAt: time.Unix(1588568886, 0).UTC(),
+		},
+
 		&LFSObject{
 			RepoID:    1,
 			OID:       "ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f",
@@ -165,6 +361,307 @@ func setupDBToDump(t *testing.T, db *gorm.DB) {
 			}),
 			CreatedUnix: 1588568886,
 		},
+
+		&MergeChecklistCheck{
+			PullRequestID: 1,
+			Item:          "Documentation is updated",
+			CheckerID:     1,
+			CreatedAt:     time.Unix(1588568886, 0).UTC(),
+		},
+
+		&MergeQueueEntry{
+			RepoID:              1,
+			BaseBranch:          "main",
+			PullRequestID:       1,
+			EnqueuerID:          1,
+			BaseCommitID:        "6d1a3e0f2c4b4f8ea7d93b5e8c0f1a2d6d1a3e0f",
+			HeadCommitID:        "a7c1f3e59b2d4e6f8a0c1d3e5f7a9b2ca7c1f3e5",
+			SpeculativeCommitID: "0b5f2b9a6f1e4a3c9b0e2f0c5c1d7e8a0b5f2b9a",
+			CreatedAt:           time.Unix(1588568886, 0).UTC(),
+			UpdatedAt:           time.Unix(1588568886, 0).UTC(),
+		},
+		&MergeQueueEntry{
+			RepoID:            1,
+			BaseBranch:        "main",
+			PullRequestID:     2,
+			EnqueuerID:        2,
+			CommitDescription: "Fix typos",
+			FailureReason:     MergeQueueFailureConflict,
+			CreatedAt:         time.Unix(1588568886, 0).UTC(),
+			UpdatedAt:         time.Unix(1588572486, 0).UTC(), // 1 hour later
+		},
+
+		&MirrorRefDrift{
+			RepoID:           1,
+			RefName:          "refs/heads/main",
+			UpstreamCommitID: "a7c1f3e59b2d4e6f8a0c1d3e5f7a9b2ca7c1f3e5",
+			LocalCommitID:    "6d1a3e0f2c4b4f8ea7d93b5e8c0f1a2d6d1a3e0f",
+			CheckedAt:        time.Unix(1588568886, 0).UTC(),
+		},
+		&MirrorRefDrift{
+			RepoID:           1,
+			RefName:          "refs/tags/v1.0",
+			UpstreamCommitID: "0b5f2b9a6f1e4a3c9b0e2f0c5c1d7e8a0b5f2b9a",
+			CheckedAt:        time.Unix(1588568886, 0).UTC(),
+		},
+
+		&OrgDomain{
+			OrgID:     1,
+			Domain:    "example.com",
+			Token:     cryptoutil.SHA1("a7c1f3e5-9b2d-4e6f-8a0c-1d3e5f7a9b2c"),
+			CreatedAt: time.Unix(1588568886, 0).UTC(),
+		},
+		&OrgDomain{
+			OrgID:      1,
+			Domain:     "gogs.io",
+			Token:      cryptoutil.SHA1("4e2a6c8f-0b1d-4f3e-9a5c-7e9b1d3f5a7c"),
+			IsVerified: true,
+			CreatedAt:  time.Unix(1588568886, 0).UTC(),
+		},
+
+		&OrgRuleset{
+			OrgID:              1,
+			Name:               "Protect main",
+			RepoPatterns:       "*, !sandbox-*",
+			BranchPatterns:     "main, release/*",
+			RequirePullRequest: true,
+			BlockForcePush:     true,
+			BlockDeletion:      true,
+			BlockedPaths:       "*.pem",
+			CreatedAt:          time.Unix(1588568886, 0).UTC(),
+			UpdatedAt:          time.Unix(1588568886, 0).UTC(),
+		},
+		&OrgSecurityPolicy{
+			OrgID:                1,
+			SecretScanning:       true,
+			VulnerabilityAlerts:  true,
+			RequireSignedCommits: true,
+			UpdatedAt:            time.Unix(1588568886, 0).UTC(),
+		},
+
+		&PagesSite{
+			RepoID:      1,
+			Source:      PagesSourceBranch,
+			Branch:      "gh-pages",
+			DomainToken: "f2c8a9b1e0d3c4b5a6978869504132e1d0c9b8a7",
+			CreatedAt:   time.Unix(1588568886, 0).UTC(),
+			UpdatedAt:   time.Unix(1588568886, 0).UTC(),
+		},
+		&PagesSite{
+			RepoID:           2,
+			Source:           PagesSourceArtifact,
+			Directory:        "public",
+			ArtifactSHA256:   "ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f",
+			CustomDomain:     "docs.example.com",
+			DomainToken:      "0a1b2c3d4e5f60718293a4b5c6d7e8f901234567",
+			IsDomainVerified: true,
+			CreatedAt:        time.Unix(1588568886, 0).UTC(),
+			UpdatedAt:        time.Unix(1588568946, 0).UTC(), // 1 minute later
+		},
+
+		&PasswordResetToken{
+			UserID:    1,
+			SHA256:    "5f0c4d6d4a0f63b8e3d0e7ec8ab79b1c4de8b6cf5b4d3e2a1f0e9d8c7b6a5948",
+			RequestIP: "127.0.0.1",
+			ExpiresAt: time.Unix(1588570686, 0).UTC(), // 30 minutes later
+			CreatedAt: time.Unix(1588568886, 0).UTC(),
+		},
+		&PasswordResetToken{
+			UserID:    2,
+			SHA256:    "0e3d7f1a2b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6",
+			RequestIP: "10.0.0.1",
+			IsUsed:    true,
+			UsedIP:    "10.0.0.2",
+			UsedAt:    time.Unix(1588568946, 0).UTC(), // 1 minute later
+			ExpiresAt: time.Unix(1588570686, 0).UTC(), // 30 minutes later
+			CreatedAt: time.Unix(1588568886, 0).UTC(),
+		},
+
+		&ProfileField{
+			Name:          "employee_id",
+			Label:         "Employee ID",
+			Scope:         ProfileFieldScopeUser,
+			LDAPAttribute: "employeeNumber",
+			CreatedAt:     time.Unix(1588568886, 0).UTC(),
+		},
+		&ProfileField{
+			Name:      "cost_center",
+			Label:     "Cost center",
+			Scope:     ProfileFieldScopeOrganization,
+			Editable:  true,
+			Position:  1,
+			CreatedAt: time.Unix(1588568886, 0).UTC(),
+		},
+
+		&ProfileFieldValue{
+			UserID:    1,
+			FieldID:   1,
+			Value:     "E-1024",
+			UpdatedAt: time.Unix(1588568886, 0).UTC(),
+		},
+		&ProfileFieldValue{
+			UserID:    2,
+			FieldID:   2,
+			Value:     "R&D",
+			UpdatedAt: time.Unix(1588568886, 0).UTC(),
+		},
+
+		&QueuedEmail{
+			Sender:        "Gogs <noreply@example.com>",
+			Recipients:    "alice@example.com",
+			Subject:       "[Gogs] Welcome",
+			Info:          "UID: 1, registration notification",
+			Body:          []byte("Subject: [Gogs] Welcome\r\n\r\nHello"),
+			Status:        QueuedEmailPending,
+			NextAttemptAt: time.Unix(1588568886, 0).UTC(),
+			CreatedAt:     time.Unix(1588568886, 0).UTC(),
+			UpdatedAt:     time.Unix(1588568886, 0).UTC(),
+		},
+		&QueuedEmail{
+			Sender:        "Gogs <noreply@example.com>",
+			Recipients:    "bob@example.com,carol@example.com",
+			Subject:       "[Gogs] New comment",
+			Info:          "Subject: New comment, issue comment",
+			Body:          []byte("Subject: [Gogs] New comment\r\n\r\nHello"),
+			Status:        QueuedEmailFailed,
+			Attempts:      8,
+			LastError:     "dial tcp: connection refused",
+			NextAttemptAt: time.Unix(1588568886, 0).UTC(),
+			CreatedAt:     time.Unix(1588568886, 0).UTC(),
+			UpdatedAt:     time.Unix(1588568886, 0).UTC(),
+		},
+
+		&Reaction{
+			IssueID:   1,
+			UserID:    1,
+			Content:   ReactionThumbsUp,
+			CreatedAt: time.Unix(1588568886, 0).UTC(),
+		},
+		&Reaction{
+			IssueID:   1,
+			UserID:    2,
+			Content:   "heart",
+			CreatedAt: time.Unix(1588568886, 0).UTC(),
+		},
+
+		&RepoDependency{
+			RepoID:       1,
+			ManifestPath: "go.mod",
+			Ecosystem:    "go",
+			PackageName:  "gogs.io/gogs",
+			Name:         "github.com/gogs/git-module",
+			Requirement:  "v1.7.0",
+		},
+		&RepoDependency{
+			RepoID:        2,
+			ManifestPath:  "web/package.json",
+			Ecosystem:     "npm",
+			Name:          "jest",
+			Requirement:   "^29.0.0",
+			IsDevelopment: true,
+		},
+
+		&RepoTraffic{
+			RepoID:         1,
+			Date:           "2020-05-04",
+			Views:          10,
+			UniqueVisitors: 3,
+			Clones:         2,
+			UniqueCloners:  1,
+		},
+		&RepoTrafficVisitor{
+			RepoID: 1,
+			Date:   "2020-05-04",
+			Kind:   RepoTrafficView,
+			Hash:   cryptoutil.SHA256("user:1"),
+		},
+
+		&ReservedName{
+			Kind:      ReservedNameKindUser,
+			Name:      "acme-*",
+			CreatedAt: time.Unix(1588568886, 0).UTC(),
+		},
+		&ReservedName{
+			Kind:      ReservedNameKindUser,
+			Name:      "help",
+			IsAllowed: true,
+			CreatedAt: time.Unix(1588568886, 0).UTC(),
+		},
+
+		&Runner{
+			OrgID:       1,
+			Name:        "build-01",
+			CreatedByID: 1,
+			SHA256:      cryptoutil.SHA256("2b4d6f8a0c1e3b5d7f9a1c3e5b7d9f0a2c4e6b8d"),
+			CreatedAt:   time.Unix(1588568886, 0).UTC(),
+		},
+
+		&SecurityAlert{
+			RepoID:          2,
+			VulnerabilityID: "GHSA-p6mc-m468-83gw",
+			Summary:         "Prototype Pollution in lodash",
+			ManifestPath:    "web/package.json",
+			Ecosystem:       "npm",
+			PackageName:     "lodash",
+			Version:         "4.17.15",
+			FixedVersion:    "4.17.19",
+			CreatedAt:       time.Unix(1588568886, 0).UTC(),
+		},
+		&SecurityPolicyException{
+			OrgID:       1,
+			RepoID:      2,
+			Feature:     SecurityFeatureSignedCommits,
+			Reason:      "Mirrors commits of an upstream project",
+			RequesterID: 2,
+			Status:      SecurityPolicyExceptionApproved,
+			ReviewerID:  1,
+			CreatedAt:   time.Unix(1588568886, 0).UTC(),
+			UpdatedAt:   time.Unix(1588568886, 0).UTC(),
+		},
+
+		&Snippet{
+			Name:       "kR3nB8pQ2xLm7vT1yZ4c",
+			OwnerID:    1,
+			Title:      "Deploy script",
+			Visibility: SnippetSecret,
+			CreatorID:  1,
+			CreatedAt:  time.Unix(1588568886, 0).UTC(),
+			UpdatedAt:  time.Unix(1588568886, 0).UTC(),
+		},
+
+		&TeamDiscussion{
+			OrgID:      1,
+			TeamID:     1,
+			PosterID:   2,
+			Title:      "Release plan",
+			Content:    "Let's discuss the release plan.",
+			NumReplies: 1,
+			CreatedAt:  time.Unix(1588568886, 0).UTC(),
+			UpdatedAt:  time.Unix(1588568887, 0).UTC(),
+		},
+		&TeamDiscussion{
+			OrgID:     1,
+			TeamID:    1,
+			ThreadID:  1,
+			PosterID:  3,
+			Content:   "Sounds good to me.",
+			CreatedAt: time.Unix(1588568887, 0).UTC(),
+			UpdatedAt: time.Unix(1588568887, 0).UTC(),
+		},
+
+		&UserContribution{
+			UserID:       1,
+			Date:         "2020-05-04",
+			Commits:      3,
+			PullRequests: 1,
+		},
+		&UserContribution{
+			UserID:    1,
+			Date:      "2020-05-04",
+			IsPrivate: true,
+			Issues:    2,
+			Reviews:   1,
+		},
 	}
 	for _, val := range vals {
 		err := db.Create(val).Error
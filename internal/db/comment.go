@@ -16,7 +16,7 @@ import (
 	api "github.com/gogs/go-gogs-client"
 
 	"gogs.io/gogs/internal/errutil"
-	"gogs.io/gogs/internal/markup"
+	"gogs.io/gogs/internal/events"
 )
 
 // CommentType defines whether a comment is just a simple comment, an action (like close) or a reference.
@@ -36,6 +36,9 @@ const (
 	COMMENT_TYPE_COMMENT_REF
 	// Reference from a pull request
 	COMMENT_TYPE_PULL_REF
+	// Force push to the head branch of a pull request, the commit IDs before and
+	// after the push are stored in Content and CommitSHA respectively.
+	COMMENT_TYPE_FORCE_PUSH
 )
 
 type CommentTag int
@@ -154,6 +157,11 @@ func CommentHashTag(id int64) string {
 }
 
 // HashTag returns unique hash tag for comment.
+// ContentRevision returns the revision token of the content of the comment.
+func (c *Comment) ContentRevision() string {
+	return ContentRevision(c.Content)
+}
+
 func (c *Comment) HashTag() string {
 	return CommentHashTag(c.ID)
 }
@@ -166,7 +174,11 @@ func (c *Comment) EventTag() string {
 // mailParticipants sends new comment emails to repository watchers
 // and mentioned people.
 func (cmt *Comment) mailParticipants(e Engine, opType ActionType, issue *Issue) (err error) {
-	mentions := markup.FindAllMentions(cmt.Content)
+	mentions, err := expandMentions(e, cmt.Poster, issue.Repo, cmt.Content)
+	if err != nil {
+		return fmt.Errorf("expandMentions: %v", err)
+	}
+
 	if err = updateIssueMentions(e, cmt.IssueID, mentions); err != nil {
 		return fmt.Errorf("UpdateIssueMentions [%d]: %v", cmt.IssueID, err)
 	}
@@ -288,6 +300,8 @@ func createComment(e *xorm.Session, opts *CreateCommentOptions) (_ *Comment, err
 			log.Error("MailParticipants: %v", err)
 		}
 	}
+	publishIssueEvent(events.TypeIssueComment, opts.Doer, opts.Issue)
+	notifyInvolvedUsers(e, opts.Doer, opts.Repo, opts.Issue)
 
 	return comment, comment.loadAttributes(e)
 }
@@ -334,6 +348,18 @@ func CreateComment(opts *CreateCommentOptions) (comment *Comment, err error) {
 	return comment, sess.Commit()
 }
 
+func (issue *Issue) sendCommentCreatedWebhook(doer *User, repo *Repository, comment *Comment) {
+	if err := PrepareWebhooks(repo, HOOK_EVENT_ISSUE_COMMENT, &api.IssueCommentPayload{
+		Action:     api.HOOK_ISSUE_COMMENT_CREATED,
+		Issue:      issue.APIFormat(),
+		Comment:    comment.APIFormat(),
+		Repository: repo.APIFormatLegacy(nil),
+		Sender:     doer.APIFormat(),
+	}); err != nil {
+		log.Error("PrepareWebhooks [comment_id: %d]: %v", comment.ID, err)
+	}
+}
+
 // CreateIssueComment creates a plain issue comment.
 func CreateIssueComment(doer *User, repo *Repository, issue *Issue, content string, attachments []string) (*Comment, error) {
 	comment, err := CreateComment(&CreateCommentOptions{
@@ -349,16 +375,7 @@ func CreateIssueComment(doer *User, repo *Repository, issue *Issue, content stri
 	}
 
 	comment.Issue = issue
-	if err = PrepareWebhooks(repo, HOOK_EVENT_ISSUE_COMMENT, &api.IssueCommentPayload{
-		Action:     api.HOOK_ISSUE_COMMENT_CREATED,
-		Issue:      issue.APIFormat(),
-		Comment:    comment.APIFormat(),
-		Repository: repo.APIFormatLegacy(nil),
-		Sender:     doer.APIFormat(),
-	}); err != nil {
-		log.Error("PrepareWebhooks [comment_id: %d]: %v", comment.ID, err)
-	}
-
+	issue.sendCommentCreatedWebhook(doer, repo, comment)
 	return comment, nil
 }
 
@@ -482,6 +499,7 @@ func UpdateComment(doer *User, c *Comment, oldContent string) (err error) {
 	if _, err = x.Id(c.ID).AllCols().Update(c); err != nil {
 		return err
 	}
+	publishIssueEvent(events.TypeIssueEdited, doer, c.Issue)
 
 	if err = c.Issue.LoadAttributes(); err != nil {
 		log.Error("Issue.LoadAttributes [issue_id: %d]: %v", c.IssueID, err)
@@ -537,6 +555,7 @@ func DeleteCommentByID(doer *User, id int64) error {
 	if err != nil {
 		log.Error("Failed to delete attachments by comment[%d]: %v", comment.ID, err)
 	}
+	publishIssueEvent(events.TypeIssueEdited, doer, comment.Issue)
 
 	if err = comment.Issue.LoadAttributes(); err != nil {
 		log.Error("Issue.LoadAttributes [issue_id: %d]: %v", comment.IssueID, err)
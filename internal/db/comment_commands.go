@@ -0,0 +1,397 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/errutil"
+)
+
+// CommentCommand is a quick action given by a line starting with a slash in
+// the content of a comment, e.g. "/label bug".
+type CommentCommand struct {
+	Name string
+	Args []string
+}
+
+func (cmd *CommentCommand) String() string {
+	if len(cmd.Args) == 0 {
+		return "/" + cmd.Name
+	}
+	return "/" + cmd.Name + " " + strings.Join(cmd.Args, " ")
+}
+
+// commentCommandNames is the set of names of supported commands. Lines
+// starting with a slash but not a supported command are kept as they are,
+// e.g. a line of file path.
+var commentCommandNames = map[string]bool{
+	"close":     true,
+	"reopen":    true,
+	"label":     true,
+	"unlabel":   true,
+	"assign":    true,
+	"unassign":  true,
+	"milestone": true,
+	"duplicate": true,
+}
+
+// splitCommandArgs splits arguments of a command by whitespace and commas,
+// where double quotes can be used to group words with whitespace, e.g. the
+// name of a label.
+func splitCommandArgs(s string) []string {
+	var args []string
+	var cur strings.Builder
+	inQuote := false
+	flush := func() {
+		if cur.Len() > 0 {
+			args = append(args, cur.String())
+			cur.Reset()
+		}
+	}
+	for _, r := range s {
+		switch {
+		case r == '"':
+			if inQuote {
+				flush()
+			}
+			inQuote = !inQuote
+		case !inQuote && (r == ',' || r == ' ' || r == '\t'):
+			flush()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	flush()
+	return args
+}
+
+// ParseCommentCommands extracts commands from the content of a comment, which
+// are lines starting with a slash followed by a supported command name. Lines
+// in fenced code blocks are never treated as commands. It returns the commands
+// in the order of appearance, and the content with lines of commands removed.
+func ParseCommentCommands(content string) ([]*CommentCommand, string) {
+	var cmds []*CommentCommand
+	lines := strings.Split(content, "\n")
+	kept := make([]string, 0, len(lines))
+	inCodeBlock := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCodeBlock = !inCodeBlock
+		}
+		if inCodeBlock || !strings.HasPrefix(trimmed, "/") {
+			kept = append(kept, line)
+			continue
+		}
+
+		fields := strings.SplitN(trimmed[1:], " ", 2)
+		name := strings.ToLower(fields[0])
+		if !commentCommandNames[name] {
+			kept = append(kept, line)
+			continue
+		}
+
+		cmd := &CommentCommand{Name: name}
+		if len(fields) == 2 {
+			cmd.Args = splitCommandArgs(fields[1])
+		}
+		cmds = append(cmds, cmd)
+	}
+	return cmds, strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+type ErrCommentCommandInvalid struct {
+	args errutil.Args
+}
+
+func IsErrCommentCommandInvalid(err error) bool {
+	_, ok := err.(ErrCommentCommandInvalid)
+	return ok
+}
+
+func (err ErrCommentCommandInvalid) Error() string {
+	return fmt.Sprintf("invalid command: %v", err.args)
+}
+
+type ErrCommentCommandNotPermitted struct {
+	args errutil.Args
+}
+
+func IsErrCommentCommandNotPermitted(err error) bool {
+	_, ok := err.(ErrCommentCommandNotPermitted)
+	return ok
+}
+
+func (err ErrCommentCommandNotPermitted) Error() string {
+	return fmt.Sprintf("command not permitted: %v", err.args)
+}
+
+// CommentActions is the resolved changes to an issue given by commands of a
+// comment.
+type CommentActions struct {
+	// IsClosed is the new status of the issue, nil means unchanged.
+	IsClosed     *bool
+	AddLabels    []*Label
+	RemoveLabels []*Label
+	// AssigneeID is the new assignee of the issue, nil means unchanged and 0
+	// means to remove the assignee.
+	AssigneeID *int64
+	// MilestoneID is the new milestone of the issue, nil means unchanged.
+	MilestoneID *int64
+	// DuplicateOf is the index of the issue that the issue duplicates, 0 means
+	// not a duplicate.
+	DuplicateOf int64
+}
+
+// IsEmpty returns true if there is no change.
+func (a *CommentActions) IsEmpty() bool {
+	return a.IsClosed == nil && len(a.AddLabels) == 0 && len(a.RemoveLabels) == 0 &&
+		a.AssigneeID == nil && a.MilestoneID == nil && a.DuplicateOf == 0
+}
+
+// ResolveCommentCommands checks permissions of the doer for the commands and
+// resolves them into changes to the issue. The doer must be able to triage
+// issues of the repository (canTriage), i.e. has write access or is a listed
+// maintainer, for all commands except closing and reopening the issue posted
+// by the doer. It returns ErrCommentCommandNotPermitted or
+// ErrCommentCommandInvalid if any of the commands cannot be applied, so that
+// either all or none of the commands take effect.
+func ResolveCommentCommands(doer *User, repo *Repository, issue *Issue, canTriage bool, cmds []*CommentCommand) (*CommentActions, error) {
+	actions := new(CommentActions)
+	invalid := func(cmd *CommentCommand, reason string) error {
+		return ErrCommentCommandInvalid{args: errutil.Args{"command": cmd.String(), "reason": reason}}
+	}
+	setStatus := func(isClosed bool) {
+		actions.IsClosed = &isClosed
+	}
+
+	for _, cmd := range cmds {
+		switch cmd.Name {
+		case "close", "reopen", "duplicate":
+			if !canTriage && !issue.IsPoster(doer.ID) {
+				return nil, ErrCommentCommandNotPermitted{args: errutil.Args{"command": cmd.String()}}
+			}
+		default:
+			if !canTriage {
+				return nil, ErrCommentCommandNotPermitted{args: errutil.Args{"command": cmd.String()}}
+			}
+		}
+
+		switch cmd.Name {
+		case "close":
+			if issue.IsPull && issue.PullRequest != nil && issue.PullRequest.HasMerged {
+				return nil, invalid(cmd, "pull request has been merged")
+			}
+			setStatus(true)
+
+		case "reopen":
+			if issue.IsPull && issue.PullRequest != nil {
+				pull := issue.PullRequest
+				if pull.HasMerged {
+					return nil, invalid(cmd, "pull request has been merged")
+				}
+				pr, err := GetUnmergedPullRequest(pull.HeadRepoID, pull.BaseRepoID, pull.HeadBranch, pull.BaseBranch)
+				if err != nil && !IsErrPullRequestNotExist(err) {
+					return nil, fmt.Errorf("get unmerged pull request: %v", err)
+				} else if pr != nil && pr.ID != pull.ID {
+					return nil, invalid(cmd, fmt.Sprintf("pull request #%d with the same branches is open", pr.Index))
+				}
+			}
+			setStatus(false)
+
+		case "label", "unlabel":
+			if len(cmd.Args) == 0 {
+				return nil, invalid(cmd, "no label")
+			}
+			for _, name := range cmd.Args {
+				label, err := GetLabelOfRepoByName(repo.ID, strings.TrimPrefix(name, "~"))
+				if err != nil {
+					if IsErrLabelNotExist(err) {
+						return nil, invalid(cmd, fmt.Sprintf("label %q does not exist", name))
+					}
+					return nil, fmt.Errorf("get label by name: %v", err)
+				}
+				if cmd.Name == "label" {
+					actions.AddLabels = append(actions.AddLabels, label)
+				} else {
+					actions.RemoveLabels = append(actions.RemoveLabels, label)
+				}
+			}
+
+		case "assign":
+			if len(cmd.Args) != 1 {
+				return nil, invalid(cmd, "exactly one user is required")
+			}
+			name := strings.TrimPrefix(cmd.Args[0], "@")
+			if name == "me" {
+				name = doer.Name
+			}
+			u, err := GetUserByName(name)
+			if err != nil {
+				if IsErrUserNotExist(err) {
+					return nil, invalid(cmd, fmt.Sprintf("user %q does not exist", name))
+				}
+				return nil, fmt.Errorf("get user by name: %v", err)
+			}
+			if _, err = repo.GetAssigneeByID(u.ID); err != nil {
+				return nil, invalid(cmd, fmt.Sprintf("user %q cannot be assigned", name))
+			}
+			actions.AssigneeID = &u.ID
+
+		case "unassign":
+			var none int64
+			actions.AssigneeID = &none
+
+		case "milestone":
+			name := strings.TrimPrefix(strings.Join(cmd.Args, " "), "%")
+			if name == "" {
+				return nil, invalid(cmd, "no milestone")
+			}
+			var id int64
+			if name != "none" {
+				m, err := GetMilestoneByRepoIDAndName(repo.ID, name)
+				if err != nil {
+					if IsErrMilestoneNotExist(err) {
+						return nil, invalid(cmd, fmt.Sprintf("milestone %q does not exist", name))
+					}
+					return nil, fmt.Errorf("get milestone by name: %v", err)
+				}
+				id = m.ID
+			}
+			actions.MilestoneID = &id
+
+		case "duplicate":
+			if len(cmd.Args) != 1 {
+				return nil, invalid(cmd, "exactly one issue is required")
+			}
+			index, err := strconv.ParseInt(strings.TrimPrefix(cmd.Args[0], "#"), 10, 64)
+			if err != nil || index == issue.Index {
+				return nil, invalid(cmd, fmt.Sprintf("%q is not another issue", cmd.Args[0]))
+			}
+			if _, err = GetRawIssueByIndex(repo.ID, index); err != nil {
+				if IsErrIssueNotExist(err) {
+					return nil, invalid(cmd, fmt.Sprintf("issue #%d does not exist", index))
+				}
+				return nil, fmt.Errorf("get issue by index: %v", err)
+			}
+			actions.DuplicateOf = index
+			setStatus(true)
+		}
+	}
+	return actions, nil
+}
+
+// CreateIssueCommentWithActions creates a plain issue comment with the content
+// and attachments, if any, and applies the actions to the issue in the same
+// transaction.
+func CreateIssueCommentWithActions(doer *User, repo *Repository, issue *Issue, content string, attachments []string, actions *CommentActions) (_ *Comment, err error) {
+	if actions.DuplicateOf > 0 {
+		if content != "" {
+			content += "\n\n"
+		}
+		content += fmt.Sprintf("Duplicate of #%d", actions.DuplicateOf)
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	var comment *Comment
+	if content != "" || len(attachments) > 0 {
+		comment, err = createComment(sess, &CreateCommentOptions{
+			Type:        COMMENT_TYPE_COMMENT,
+			Doer:        doer,
+			Repo:        repo,
+			Issue:       issue,
+			Content:     content,
+			Attachments: attachments,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create comment: %v", err)
+		}
+	}
+
+	labelsChanged := false
+	for _, label := range actions.AddLabels {
+		if !hasIssueLabel(sess, issue.ID, label.ID) {
+			if err = issue.addLabel(sess, label); err != nil {
+				return nil, fmt.Errorf("add label: %v", err)
+			}
+			labelsChanged = true
+		}
+	}
+	for _, label := range actions.RemoveLabels {
+		if hasIssueLabel(sess, issue.ID, label.ID) {
+			if err = deleteIssueLabel(sess, issue, label); err != nil {
+				return nil, fmt.Errorf("remove label: %v", err)
+			}
+			labelsChanged = true
+		}
+	}
+
+	assigneeChanged := actions.AssigneeID != nil && *actions.AssigneeID != issue.AssigneeID
+	if assigneeChanged {
+		issue.AssigneeID = *actions.AssigneeID
+		if err = updateIssueUserByAssignee(sess, issue); err != nil {
+			return nil, fmt.Errorf("update assignee: %v", err)
+		}
+	}
+
+	milestoneChanged := actions.MilestoneID != nil && *actions.MilestoneID != issue.MilestoneID
+	if milestoneChanged {
+		oldMilestoneID := issue.MilestoneID
+		issue.MilestoneID = *actions.MilestoneID
+		if err = changeMilestoneAssign(sess, issue, oldMilestoneID); err != nil {
+			return nil, fmt.Errorf("change milestone: %v", err)
+		}
+	}
+
+	statusChanged := actions.IsClosed != nil && *actions.IsClosed != issue.IsClosed
+	if statusChanged {
+		if err = issue.changeStatus(sess, doer, repo, *actions.IsClosed); err != nil {
+			return nil, fmt.Errorf("change status: %v", err)
+		}
+	}
+
+	if err = sess.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %v", err)
+	}
+
+	if comment != nil {
+		comment.Issue = issue
+		issue.sendCommentCreatedWebhook(doer, repo, comment)
+	}
+	if labelsChanged {
+		issue.sendLabelUpdatedWebhook(doer)
+	}
+	if assigneeChanged {
+		issue.Assignee, err = GetUserByID(issue.AssigneeID)
+		if err != nil && !IsErrUserNotExist(err) {
+			log.Error("Failed to get user by ID: %v", err)
+		}
+		issue.sendAssigneeWebhook(doer, issue.AssigneeID == 0)
+	}
+	if milestoneChanged {
+		issue.sendMilestoneWebhook(doer)
+	}
+	if statusChanged {
+		issue.sendStatusWebhook(doer, repo, issue.IsClosed)
+		if issue.IsPull && !issue.IsClosed {
+			if err = issue.PullRequest.UpdatePatch(); err != nil {
+				log.Error("Failed to update patch [pull_id: %d]: %v", issue.PullRequest.ID, err)
+			} else {
+				issue.PullRequest.AddToTaskQueue()
+			}
+		}
+	}
+	return comment, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommentCommands(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantCmds    []*CommentCommand
+		wantContent string
+	}{
+		{
+			name:        "no commands",
+			content:     "Looks good to me.",
+			wantContent: "Looks good to me.",
+		},
+		{
+			name:    "only commands",
+			content: "/close\n/label bug, \"help wanted\" ~urgent\n/assign @alice",
+			wantCmds: []*CommentCommand{
+				{Name: "close"},
+				{Name: "label", Args: []string{"bug", "help wanted", "~urgent"}},
+				{Name: "assign", Args: []string{"@alice"}},
+			},
+			wantContent: "",
+		},
+		{
+			name:    "commands mixed with text",
+			content: "Fixed in the latest release.\n\n  /Milestone v1.2\n/duplicate #45\nThanks!",
+			wantCmds: []*CommentCommand{
+				{Name: "milestone", Args: []string{"v1.2"}},
+				{Name: "duplicate", Args: []string{"#45"}},
+			},
+			wantContent: "Fixed in the latest release.\n\nThanks!",
+		},
+		{
+			name:        "unknown commands and paths are kept",
+			content:     "/etc/passwd\n/shrug",
+			wantContent: "/etc/passwd\n/shrug",
+		},
+		{
+			name:    "fenced code blocks are ignored",
+			content: "```\n/close\n```\n/reopen",
+			wantCmds: []*CommentCommand{
+				{Name: "reopen"},
+			},
+			wantContent: "```\n/close\n```",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cmds, content := ParseCommentCommands(test.content)
+			assert.Equal(t, test.wantCmds, cmds)
+			assert.Equal(t, test.wantContent, content)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CommitMessagesStore is the persistent interface for the index of commit
+// messages of repositories.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type CommitMessagesStore interface {
+	// Count returns the number of indexed commits of the repository.
+	Count(ctx context.Context, repoID int64) (int64, error)
+	// Index adds the commits to the index of the repository. Commits that are
+	// already indexed are skipped.
+	Index(ctx context.Context, repoID int64, commits []*CommitMessage) error
+	// Search returns a page of indexed commits of the repository that match
+	// given conditions, ordered by the newest committed, along with the total
+	// number of matched commits.
+	Search(ctx context.Context, repoID int64, opts SearchCommitMessagesOptions) ([]*CommitMessage, int64, error)
+}
+
+var CommitMessages CommitMessagesStore
+
+// CommitMessage is an indexed commit of a repository, which allows searching
+// commits without reading through the Git history.
+type CommitMessage struct {
+	ID             int64     `gorm:"primaryKey"`
+	RepoID         int64     `gorm:"uniqueIndex:commit_message_repo_sha_unique;not null"`
+	SHA            string    `gorm:"type:VARCHAR(40);uniqueIndex:commit_message_repo_sha_unique;not null"`
+	AuthorName     string    `gorm:"not null"`
+	AuthorEmail    string    `gorm:"not null"`
+	AuthoredAt     time.Time `gorm:"not null"`
+	CommitterName  string    `gorm:"not null"`
+	CommitterEmail string    `gorm:"not null"`
+	CommittedAt    time.Time `gorm:"index;not null"`
+	Message        string    `gorm:"type:TEXT;not null"`
+}
+
+// Summary returns the first line of the commit message.
+func (c *CommitMessage) Summary() string {
+	if i := strings.IndexByte(c.Message, '\n'); i >= 0 {
+		return c.Message[:i]
+	}
+	return c.Message
+}
+
+var _ CommitMessagesStore = (*commitMessages)(nil)
+
+type commitMessages struct {
+	*gorm.DB
+}
+
+// NewCommitMessagesStore returns a persistent interface for the index of
+// commit messages with given database connection.
+func NewCommitMessagesStore(db *gorm.DB) CommitMessagesStore {
+	return &commitMessages{DB: db}
+}
+
+func (db *commitMessages) Count(ctx context.Context, repoID int64) (int64, error) {
+	var count int64
+	return count, db.WithContext(ctx).Model(new(CommitMessage)).Where("repo_id = ?", repoID).Count(&count).Error
+}
+
+func (db *commitMessages) Index(ctx context.Context, repoID int64, commits []*CommitMessage) error {
+	if len(commits) == 0 {
+		return nil
+	}
+
+	for _, c := range commits {
+		c.ID = 0
+		c.RepoID = repoID
+	}
+	return db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(commits, 100).
+		Error
+}
+
+type SearchCommitMessagesOptions struct {
+	// Keyword is matched against commit messages case-insensitively.
+	Keyword string
+	// Author is matched against names and emails of authors
+	// case-insensitively.
+	Author string
+	// Committer is matched against names and emails of committers
+	// case-insensitively.
+	Committer string
+	// Since and Until limit the range of commit dates, zero values mean no
+	// limit.
+	Since time.Time
+	Until time.Time
+	// Page is the page number starting from 1.
+	Page     int
+	PageSize int
+}
+
+func (db *commitMessages) Search(ctx context.Context, repoID int64, opts SearchCommitMessagesOptions) ([]*CommitMessage, int64, error) {
+	if opts.Page <= 0 {
+		opts.Page = 1
+	}
+
+	query := db.WithContext(ctx).Model(new(CommitMessage)).Where("repo_id = ?", repoID)
+	if opts.Keyword != "" {
+		query = query.Where("LOWER(message) LIKE ?", "%"+strings.ToLower(opts.Keyword)+"%")
+	}
+	if opts.Author != "" {
+		author := "%" + strings.ToLower(opts.Author) + "%"
+		query = query.Where("LOWER(author_name) LIKE ? OR LOWER(author_email) LIKE ?", author, author)
+	}
+	if opts.Committer != "" {
+		committer := "%" + strings.ToLower(opts.Committer) + "%"
+		query = query.Where("LOWER(committer_name) LIKE ? OR LOWER(committer_email) LIKE ?", committer, committer)
+	}
+	if !opts.Since.IsZero() {
+		query = query.Where("committed_at >= ?", opts.Since)
+	}
+	if !opts.Until.IsZero() {
+		query = query.Where("committed_at <= ?", opts.Until)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	commits := make([]*CommitMessage, 0, opts.PageSize)
+	err := query.
+		Order("committed_at DESC").
+		Order("id DESC").
+		Limit(opts.PageSize).
+		Offset((opts.Page - 1) * opts.PageSize).
+		Find(&commits).
+		Error
+	return commits, count, err
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+func TestCommitMessages(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(CommitMessage)}
+	db := &commitMessages{
+		DB: dbtest.NewDB(t, "commitMessages", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *commitMessages)
+	}{
+		{"Index", commitMessagesIndex},
+		{"Search", commitMessagesSearch},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func newTestCommitMessage(sha, author, committer, message string, committedAt time.Time) *CommitMessage {
+	return &CommitMessage{
+		SHA:            sha,
+		AuthorName:     author,
+		AuthorEmail:    author + "@example.com",
+		AuthoredAt:     committedAt,
+		CommitterName:  committer,
+		CommitterEmail: committer + "@example.com",
+		CommittedAt:    committedAt,
+		Message:        message,
+	}
+}
+
+func commitMessagesIndex(t *testing.T, db *commitMessages) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	err := db.Index(ctx, 1, []*CommitMessage{
+		newTestCommitMessage("1111111111111111111111111111111111111111", "alice", "alice", "first", now),
+		newTestCommitMessage("2222222222222222222222222222222222222222", "alice", "alice", "second", now),
+	})
+	require.NoError(t, err)
+
+	// Indexing the same commits again is a no-op, and the same commits can be
+	// indexed for other repositories, e.g. forks.
+	err = db.Index(ctx, 1, []*CommitMessage{
+		newTestCommitMessage("2222222222222222222222222222222222222222", "alice", "alice", "second", now),
+		newTestCommitMessage("3333333333333333333333333333333333333333", "alice", "alice", "third", now),
+	})
+	require.NoError(t, err)
+	err = db.Index(ctx, 2, []*CommitMessage{
+		newTestCommitMessage("1111111111111111111111111111111111111111", "alice", "alice", "first", now),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Index(ctx, 3, nil))
+
+	count, err := db.Count(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	count, err = db.Count(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	count, err = db.Count(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func commitMessagesSearch(t *testing.T, db *commitMessages) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	err := db.Index(ctx, 1, []*CommitMessage{
+		newTestCommitMessage("1111111111111111111111111111111111111111", "alice", "alice", "Fix the login page", now.Add(-3*time.Hour)),
+		newTestCommitMessage("2222222222222222222222222222222222222222", "bob", "alice", "Add the signup page", now.Add(-2*time.Hour)),
+		newTestCommitMessage("3333333333333333333333333333333333333333", "bob", "bob", "fix typo", now.Add(-time.Hour)),
+	})
+	require.NoError(t, err)
+	err = db.Index(ctx, 2, []*CommitMessage{
+		newTestCommitMessage("4444444444444444444444444444444444444444", "alice", "alice", "Fix other repository", now),
+	})
+	require.NoError(t, err)
+
+	shas := func(commits []*CommitMessage) []string {
+		got := make([]string, len(commits))
+		for i := range commits {
+			got[i] = commits[i].SHA[:1]
+		}
+		return got
+	}
+
+	tests := []struct {
+		name      string
+		opts      SearchCommitMessagesOptions
+		wantSHAs  []string
+		wantCount int64
+	}{
+		{
+			name:      "all",
+			opts:      SearchCommitMessagesOptions{PageSize: 10},
+			wantSHAs:  []string{"3", "2", "1"},
+			wantCount: 3,
+		},
+		{
+			name:      "keyword",
+			opts:      SearchCommitMessagesOptions{Keyword: "FIX", PageSize: 10},
+			wantSHAs:  []string{"3", "1"},
+			wantCount: 2,
+		},
+		{
+			name:      "author by email",
+			opts:      SearchCommitMessagesOptions{Author: "bob@example", PageSize: 10},
+			wantSHAs:  []string{"3", "2"},
+			wantCount: 2,
+		},
+		{
+			name:      "committer",
+			opts:      SearchCommitMessagesOptions{Committer: "alice", PageSize: 10},
+			wantSHAs:  []string{"2", "1"},
+			wantCount: 2,
+		},
+		{
+			name:      "date range",
+			opts:      SearchCommitMessagesOptions{Since: now.Add(-150 * time.Minute), Until: now.Add(-30 * time.Minute), PageSize: 10},
+			wantSHAs:  []string{"3", "2"},
+			wantCount: 2,
+		},
+		{
+			name:      "paginate",
+			opts:      SearchCommitMessagesOptions{Page: 2, PageSize: 2},
+			wantSHAs:  []string{"1"},
+			wantCount: 3,
+		},
+		{
+			name:      "no match",
+			opts:      SearchCommitMessagesOptions{Keyword: "fix", Author: "carol", PageSize: 10},
+			wantSHAs:  []string{},
+			wantCount: 0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			commits, count, err := db.Search(ctx, 1, test.opts)
+			require.NoError(t, err)
+			assert.Equal(t, test.wantSHAs, shas(commits))
+			assert.Equal(t, test.wantCount, count)
+		})
+	}
+}
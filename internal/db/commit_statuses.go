@@ -0,0 +1,157 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/events"
+)
+
+// CommitStatusesStore is the persistent interface for statuses of commits.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type CommitStatusesStore interface {
+	// Create creates a new status for the commit with given SHA of the
+	// repository.
+	Create(ctx context.Context, repoID, creatorID int64, sha string, opts CreateCommitStatusOptions) (*CommitStatus, error)
+	// ListLatest returns the latest status of each context for the commit with
+	// given SHA of the repository, ordered by context.
+	ListLatest(ctx context.Context, repoID int64, sha string) ([]*CommitStatus, error)
+}
+
+var CommitStatuses CommitStatusesStore
+
+// CommitStatusState is the state of a commit status.
+type CommitStatusState string
+
+const (
+	CommitStatusPending CommitStatusState = "pending"
+	CommitStatusSuccess CommitStatusState = "success"
+	CommitStatusError   CommitStatusState = "error"
+	CommitStatusFailure CommitStatusState = "failure"
+)
+
+// IsValid returns true if the state is one of known states.
+func (s CommitStatusState) IsValid() bool {
+	switch s {
+	case CommitStatusPending, CommitStatusSuccess, CommitStatusError, CommitStatusFailure:
+		return true
+	}
+	return false
+}
+
+// IsFailed returns true if the state is either error or failure.
+func (s CommitStatusState) IsFailed() bool {
+	return s == CommitStatusError || s == CommitStatusFailure
+}
+
+// CommitStatus is a status of a commit reported by external systems, e.g. CI.
+// Each report creates a new status, and the latest one of the same context
+// takes effect.
+type CommitStatus struct {
+	ID          int64             `gorm:"primaryKey"`
+	RepoID      int64             `gorm:"index:commit_status_repo_sha;not null"`
+	SHA         string            `gorm:"type:VARCHAR(40);index:commit_status_repo_sha;not null"`
+	State       CommitStatusState `gorm:"type:VARCHAR(7);not null"`
+	TargetURL   string            `gorm:"type:TEXT"`
+	Description string
+	Context     string    `gorm:"not null"`
+	CreatorID   int64     `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"not null"`
+}
+
+// CombineCommitStatuses returns the combined state of the statuses, which is
+// failure if any of them has failed, pending if any of them is pending, and
+// success if all of them have succeeded. It returns an empty string when there
+// is no status.
+func CombineCommitStatuses(statuses []*CommitStatus) CommitStatusState {
+	if len(statuses) == 0 {
+		return ""
+	}
+
+	state := CommitStatusSuccess
+	for _, s := range statuses {
+		if s.State.IsFailed() {
+			return CommitStatusFailure
+		} else if s.State == CommitStatusPending {
+			state = CommitStatusPending
+		}
+	}
+	return state
+}
+
+var _ CommitStatusesStore = (*commitStatuses)(nil)
+
+type commitStatuses struct {
+	*gorm.DB
+}
+
+// NewCommitStatusesStore returns a persistent interface for statuses of
+// commits with given database connection.
+func NewCommitStatusesStore(db *gorm.DB) CommitStatusesStore {
+	return &commitStatuses{DB: db}
+}
+
+type CreateCommitStatusOptions struct {
+	State       CommitStatusState
+	TargetURL   string
+	Description string
+	// Context is the label to differentiate statuses from different systems,
+	// "default" is used when empty.
+	Context string
+}
+
+func (db *commitStatuses) Create(ctx context.Context, repoID, creatorID int64, sha string, opts CreateCommitStatusOptions) (*CommitStatus, error) {
+	if opts.Context == "" {
+		opts.Context = "default"
+	}
+
+	s := &CommitStatus{
+		RepoID:      repoID,
+		SHA:         sha,
+		State:       opts.State,
+		TargetURL:   opts.TargetURL,
+		Description: opts.Description,
+		Context:     opts.Context,
+		CreatorID:   creatorID,
+	}
+	if err := db.WithContext(ctx).Create(s).Error; err != nil {
+		return nil, err
+	}
+
+	events.Publish(events.RepoTopic(repoID), &events.Event{
+		Type:    events.TypeCommitStatus,
+		RepoID:  repoID,
+		SHA:     sha,
+		ActorID: creatorID,
+	})
+	return s, nil
+}
+
+func (db *commitStatuses) ListLatest(ctx context.Context, repoID int64, sha string) ([]*CommitStatus, error) {
+	var statuses []*CommitStatus
+	err := db.WithContext(ctx).
+		Where("repo_id = ? AND sha = ?", repoID, sha).
+		Order("context ASC").
+		Order("id DESC").
+		Find(&statuses).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make([]*CommitStatus, 0, len(statuses))
+	for _, s := range statuses {
+		if len(latest) > 0 && latest[len(latest)-1].Context == s.Context {
+			continue
+		}
+		latest = append(latest, s)
+	}
+	return latest, nil
+}
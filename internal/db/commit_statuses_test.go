@@ -0,0 +1,104 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+func TestCombineCommitStatuses(t *testing.T) {
+	assert.Equal(t, CommitStatusState(""), CombineCommitStatuses(nil))
+	assert.Equal(t, CommitStatusSuccess, CombineCommitStatuses([]*CommitStatus{
+		{State: CommitStatusSuccess},
+		{State: CommitStatusSuccess},
+	}))
+	assert.Equal(t, CommitStatusPending, CombineCommitStatuses([]*CommitStatus{
+		{State: CommitStatusSuccess},
+		{State: CommitStatusPending},
+	}))
+	assert.Equal(t, CommitStatusFailure, CombineCommitStatuses([]*CommitStatus{
+		{State: CommitStatusPending},
+		{State: CommitStatusError},
+	}))
+}
+
+func TestCommitStatuses(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(CommitStatus)}
+	db := &commitStatuses{
+		DB: dbtest.NewDB(t, "commitStatuses", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *commitStatuses)
+	}{
+		{"Create", commitStatusesCreate},
+		{"ListLatest", commitStatusesListLatest},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+const testCommitSHA = "0b5f2b9a6f1e4a3c9b0e2f0c5c1d7e8a0b5f2b9a"
+
+func commitStatusesCreate(t *testing.T, db *commitStatuses) {
+	ctx := context.Background()
+
+	s, err := db.Create(ctx, 1, 2, testCommitSHA, CreateCommitStatusOptions{
+		State:       CommitStatusPending,
+		TargetURL:   "https://ci.example.com/builds/1",
+		Description: "The build is running",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), s.RepoID)
+	assert.Equal(t, int64(2), s.CreatorID)
+	assert.Equal(t, "default", s.Context)
+	assert.False(t, s.CreatedAt.IsZero())
+}
+
+func commitStatusesListLatest(t *testing.T, db *commitStatuses) {
+	ctx := context.Background()
+
+	for _, opts := range []CreateCommitStatusOptions{
+		{State: CommitStatusPending, Context: "ci/test"},
+		{State: CommitStatusPending, Context: "ci/build"},
+		{State: CommitStatusFailure, Context: "ci/test"},
+		{State: CommitStatusSuccess, Context: "ci/build"},
+	} {
+		_, err := db.Create(ctx, 1, 1, testCommitSHA, opts)
+		require.NoError(t, err)
+	}
+	// Statuses of other repositories are not included.
+	_, err := db.Create(ctx, 2, 1, testCommitSHA, CreateCommitStatusOptions{State: CommitStatusPending, Context: "ci/lint"})
+	require.NoError(t, err)
+
+	statuses, err := db.ListLatest(ctx, 1, testCommitSHA)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "ci/build", statuses[0].Context)
+	assert.Equal(t, CommitStatusSuccess, statuses[0].State)
+	assert.Equal(t, "ci/test", statuses[1].Context)
+	assert.Equal(t, CommitStatusFailure, statuses[1].State)
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	log "unknwon.dev/clog/v2"
+	"xorm.io/core"
+	"xorm.io/xorm"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/dbutil"
+)
+
+// ErrConvertTargetNotEmpty is returned when the target database of a conversion
+// already has a Gogs schema.
+var ErrConvertTargetNotEmpty = errors.New("target database is not empty")
+
+// ConvertedTable is a table copied by ConvertDatabase.
+type ConvertedTable struct {
+	Name    string
+	NumRows int64
+}
+
+// ConvertDatabase copies all tables from the current database to the target
+// database, which is usually of another type. Tables are created with column
+// types of the target database, rows are streamed table by table without
+// intermediate files, and the number of rows of each table is verified after
+// copying. The target database must not have a Gogs schema yet.
+func ConvertDatabase(ctx context.Context, db *gorm.DB, target conf.DatabaseOpts, verbose bool) ([]*ConvertedTable, error) {
+	targetX, err := newEngine(target)
+	if err != nil {
+		return nil, errors.Wrap(err, "connect to target database")
+	}
+	defer func() { _ = targetX.Close() }()
+	if target.Type == "postgres" {
+		targetX.SetSchema(target.Schema)
+	}
+	targetX.SetMapper(core.GonicMapper{})
+
+	exists, err := targetX.IsTableExist(new(Version))
+	if err != nil {
+		return nil, errors.Wrap(err, "check version table")
+	} else if exists {
+		return nil, ErrConvertTargetNotEmpty
+	}
+
+	targetDB, err := dbutil.OpenDB(target, newGormConfig())
+	if err != nil {
+		return nil, errors.Wrap(err, "open target database")
+	}
+	if sqlDB, err := targetDB.DB(); err == nil {
+		defer func() { _ = sqlDB.Close() }()
+	}
+	if target.Type == "mysql" {
+		targetDB = targetDB.Set("gorm:table_options", "ENGINE=InnoDB").Session(&gorm.Session{})
+	}
+
+	var converted []*ConvertedTable
+	convert := func(table interface{}, convertFunc func(table interface{}) (int64, error)) error {
+		tableName := getTableType(table)
+		if verbose {
+			log.Trace("Converting table %q...", tableName)
+		}
+
+		numRows, err := convertFunc(table)
+		if err != nil {
+			return errors.Wrapf(err, "convert table %q", tableName)
+		}
+		converted = append(converted, &ConvertedTable{Name: tableName, NumRows: numRows})
+		return nil
+	}
+	convertLegacy := func(table interface{}) (int64, error) {
+		return convertLegacyTable(ctx, x, targetX, table)
+	}
+
+	for _, table := range legacyTables {
+		if err = convert(table, convertLegacy); err != nil {
+			return converted, err
+		}
+	}
+	for _, table := range Tables {
+		err = convert(table, func(table interface{}) (int64, error) {
+			return convertTable(ctx, db, targetDB, table)
+		})
+		if err != nil {
+			return converted, err
+		}
+	}
+
+	// The version table goes last so that an interrupted conversion can be
+	// retried.
+	if err = convert(new(Version), convertLegacy); err != nil {
+		return converted, err
+	}
+	return converted, nil
+}
+
+// streamTable pipes rows written by dump to load.
+func streamTable(dump func(w io.Writer) error, load func(r io.Reader) error) error {
+	pr, pw := io.Pipe()
+	dumpErr := make(chan error, 1)
+	go func() {
+		err := dump(pw)
+		_ = pw.CloseWithError(err)
+		dumpErr <- err
+	}()
+
+	err := load(pr)
+	// Unblock the dump when the load stopped early.
+	_ = pr.CloseWithError(errors.New("load stopped"))
+	if derr := <-dumpErr; derr != nil && err == nil {
+		return errors.Wrap(derr, "dump")
+	} else if err != nil {
+		return errors.Wrap(err, "load")
+	}
+	return nil
+}
+
+func convertLegacyTable(ctx context.Context, source, target *xorm.Engine, table interface{}) (int64, error) {
+	err := streamTable(
+		func(w io.Writer) error { return dumpLegacyTable(ctx, source, table, w) },
+		func(r io.Reader) error { return importLegacyTable(ctx, target, table, r) },
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	tableName := source.TableName(table)
+	want, err := source.Table(tableName).Count()
+	if err != nil {
+		return 0, errors.Wrap(err, "count source rows")
+	}
+	got, err := target.Table(tableName).Count()
+	if err != nil {
+		return 0, errors.Wrap(err, "count target rows")
+	} else if got != want {
+		return 0, errors.Errorf("verify: want %d rows but got %d", want, got)
+	}
+	return got, nil
+}
+
+func convertTable(ctx context.Context, source, target *gorm.DB, table interface{}) (int64, error) {
+	err := streamTable(
+		func(w io.Writer) error { return dumpTable(ctx, source, table, w) },
+		func(r io.Reader) error { return importTable(ctx, target, table, r) },
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	var want, got int64
+	if err = source.WithContext(ctx).Model(table).Count(&want).Error; err != nil {
+		return 0, errors.Wrap(err, "count source rows")
+	}
+	if err = target.WithContext(ctx).Model(table).Count(&got).Error; err != nil {
+		return 0, errors.Wrap(err, "count target rows")
+	} else if got != want {
+		return 0, errors.Errorf("verify: want %d rows but got %d", want, got)
+	}
+	return got, nil
+}
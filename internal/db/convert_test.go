@@ -0,0 +1,90 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+func TestConvertTable(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	ctx := context.Background()
+	source := dbtest.NewDB(t, "convertTableSource", new(RepoDependency))
+	target := dbtest.NewDB(t, "convertTableTarget", new(RepoDependency))
+
+	want := []*RepoDependency{
+		{ID: 1, RepoID: 1, Ecosystem: "go", Name: "github.com/pkg/errors", Requirement: "v0.9.1", ManifestPath: "go.mod"},
+		{ID: 3, RepoID: 2, Ecosystem: "npm", Name: "jquery", Requirement: "3.6.0", ManifestPath: "package.json"},
+	}
+	require.NoError(t, source.Create(want).Error)
+	// Existing rows of the target are replaced.
+	require.NoError(t, target.Create(&RepoDependency{ID: 9, RepoID: 9, Ecosystem: "go", Name: "stale", ManifestPath: "go.mod"}).Error)
+
+	numRows, err := convertTable(ctx, source, target, new(RepoDependency))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), numRows)
+
+	var got []*RepoDependency
+	require.NoError(t, target.Order("id ASC").Find(&got).Error)
+	assert.Equal(t, want, got)
+
+	// The sequence of IDs continues after copied rows.
+	next := &RepoDependency{RepoID: 3, Ecosystem: "go", Name: "gorm.io/gorm", ManifestPath: "go.mod"}
+	require.NoError(t, target.Create(next).Error)
+	assert.Equal(t, int64(4), next.ID)
+}
+
+func TestStreamTable(t *testing.T) {
+	t.Run("load stops early", func(t *testing.T) {
+		done := make(chan error, 1)
+		go func() {
+			done <- streamTable(
+				func(w io.Writer) error {
+					for {
+						if _, err := w.Write([]byte("{}\n")); err != nil {
+							return err
+						}
+					}
+				},
+				func(r io.Reader) error {
+					return errors.New("bad row")
+				},
+			)
+		}()
+
+		select {
+		case err := <-done:
+			assert.EqualError(t, err, "load: bad row")
+		case <-time.After(5 * time.Second):
+			t.Fatal("stream did not stop")
+		}
+	})
+
+	t.Run("dump fails", func(t *testing.T) {
+		err := streamTable(
+			func(w io.Writer) error {
+				return errors.New("connection lost")
+			},
+			func(r io.Reader) error {
+				_, err := io.ReadAll(r)
+				return err
+			},
+		)
+		assert.EqualError(t, err, "load: connection lost")
+	})
+}
@@ -5,6 +5,7 @@
 package db
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -41,8 +42,34 @@ func newLogWriter() (logger.Writer, error) {
 //
 // NOTE: Lines are sorted in alphabetical order, each letter in its own line.
 var Tables = []interface{}{
-	new(Access), new(AccessToken), new(Action),
+	new(Access), new(AccessToken), new(Action), new(AdminRecoveryToken), new(AdminRoleAssignment),
+	new(CommitMessage), new(CommitStatus),
+	new(Deployment), new(DeploymentStatus), new(DeviceAuthorization), new(DigestSubscription),
+	new(FederatedIdentity), new(FetchStat),
+	new(GitAccessLog), new(GPGKey),
+	new(JobToken),
 	new(LFSObject), new(LoginSource),
+	new(MergeChecklistCheck), new(MergeQueueEntry), new(MirrorRefDrift),
+	new(OrgDomain), new(OrgRuleset), new(OrgSecurityPolicy),
+	new(PagesSite), new(PasswordResetToken), new(ProfileField), new(ProfileFieldValue),
+	new(QueuedEmail), new(Reaction),
+	new(RepoDependency), new(RepoTraffic), new(RepoTrafficVisitor), new(ReservedName), new(Runner),
+	new(SecurityAlert), new(SecurityPolicyException), new(Snippet),
+	new(TeamDiscussion),
+	new(UserContribution),
+}
+
+// newGormConfig returns the GORM config for opening databases.
+func newGormConfig() *gorm.Config {
+	return &gorm.Config{
+		SkipDefaultTransaction: true,
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true,
+		},
+		NowFunc: func() time.Time {
+			return time.Now().UTC().Truncate(time.Microsecond)
+		},
+	}
 }
 
 // Init initializes the database with given logger.
@@ -58,18 +85,7 @@ func Init(w logger.Writer) (*gorm.DB, error) {
 		LogLevel:      level,
 	})
 
-	db, err := dbutil.OpenDB(
-		conf.Database,
-		&gorm.Config{
-			SkipDefaultTransaction: true,
-			NamingStrategy: schema.NamingStrategy{
-				SingularTable: true,
-			},
-			NowFunc: func() time.Time {
-				return time.Now().UTC().Truncate(time.Microsecond)
-			},
-		},
-	)
+	db, err := dbutil.OpenDB(conf.Database, newGormConfig())
 	if err != nil {
 		return nil, errors.Wrap(err, "open database")
 	}
@@ -120,13 +136,49 @@ func Init(w logger.Writer) (*gorm.DB, error) {
 	// Initialize stores, sorted in alphabetical order.
 	AccessTokens = &accessTokens{DB: db}
 	Actions = NewActionsStore(db)
+	AdminRecoveryTokens = NewAdminRecoveryTokensStore(db)
+	AdminRoles = NewAdminRolesStore(db)
+	CommitMessages = NewCommitMessagesStore(db)
+	CommitStatuses = NewCommitStatusesStore(db)
+	Deployments = NewDeploymentsStore(db)
+	DeviceAuthorizations = NewDeviceAuthorizationsStore(db)
+	Digests = NewDigestsStore(db)
+	FederatedIdentities = NewFederatedIdentitiesStore(db)
+	FetchStats = NewFetchStatsStore(db)
+	GitAccessLogs = NewGitAccessLogsStore(db)
+	GPGKeys = NewGPGKeysStore(db)
+	JobTokens = NewJobTokensStore(db)
 	LoginSources = &loginSources{DB: db, files: sourceFiles}
 	LFS = &lfs{DB: db}
+	MergeChecklistChecks = NewMergeChecklistChecksStore(db)
+	MergeQueueEntries = NewMergeQueueEntriesStore(db)
+	MirrorRefDrifts = NewMirrorRefDriftsStore(db)
+	OrgDomains = NewOrgDomainsStore(db)
+	OrgRulesets = NewOrgRulesetsStore(db)
+	OrgSecurityPolicies = NewOrgSecurityPoliciesStore(db)
+	PagesSites = NewPagesSitesStore(db)
+	PasswordResetTokens = NewPasswordResetTokensStore(db)
 	Perms = &perms{DB: db}
+	ProfileFields = NewProfileFieldsStore(db)
+	QueuedEmails = NewQueuedEmailsStore(db)
+	Reactions = NewReactionsStore(db)
+	RepoDependencies = NewRepoDependenciesStore(db)
+	RepoTraffics = NewRepoTrafficsStore(db)
 	Repos = NewReposStore(db)
+	ReservedNames = NewReservedNamesStore(db)
+	Runners = NewRunnersStore(db)
+	SecurityAlerts = NewSecurityAlertsStore(db)
+	SecurityPolicyExceptions = NewSecurityPolicyExceptionsStore(db)
+	Snippets = NewSnippetsStore(db)
+	TeamDiscussions = NewTeamDiscussionsStore(db)
 	TwoFactors = &twoFactors{DB: db}
+	UsageReports = NewUsageReportsStore(db)
+	UserContributions = NewUserContributionsStore(db)
 	Users = NewUsersStore(db)
 	Watches = NewWatchesStore(db)
 
+	if err = LoadReservedNames(context.Background()); err != nil {
+		return nil, errors.Wrap(err, "load reserved names")
+	}
 	return db, nil
 }
@@ -0,0 +1,356 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/errutil"
+)
+
+// DeploymentsStore is the persistent interface for deployments of
+// repositories.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type DeploymentsStore interface {
+	// Create creates a new deployment of the commit with given SHA of the
+	// repository to the environment, in pending state.
+	Create(ctx context.Context, repoID, creatorID int64, opts CreateDeploymentOptions) (*Deployment, error)
+	// CreateStatus creates a new status for the deployment, which also becomes
+	// the state of the deployment. The environment URL of the deployment is
+	// updated when the status has one.
+	CreateStatus(ctx context.Context, deploymentID, creatorID int64, opts CreateDeploymentStatusOptions) (*DeploymentStatus, error)
+	// DeactivatePullRequest marks all deployments of preview environments of the
+	// pull request with given index of the repository as inactive by creating
+	// an inactive status for each. It returns deployments that were not
+	// inactive.
+	DeactivatePullRequest(ctx context.Context, repoID, index, creatorID int64) ([]*Deployment, error)
+	// GetByID returns the deployment with given ID of the repository. It returns
+	// ErrDeploymentNotExist when not found.
+	GetByID(ctx context.Context, repoID, id int64) (*Deployment, error)
+	// HasSucceeded returns true if the commit with given SHA of the repository
+	// has been successfully deployed to the environment.
+	HasSucceeded(ctx context.Context, repoID int64, environment, sha string) (bool, error)
+	// List returns deployments of the repository matching the options, ordered
+	// by the most recently created first.
+	List(ctx context.Context, repoID int64, opts ListDeploymentsOptions) ([]*Deployment, error)
+	// ListEnvironments returns the latest deployment of each environment of the
+	// repository, ordered by environment.
+	ListEnvironments(ctx context.Context, repoID int64) ([]*Deployment, error)
+	// ListPullRequestEnvironments returns the latest deployment of each preview
+	// environment of the pull request with given index of the repository,
+	// ordered by environment.
+	ListPullRequestEnvironments(ctx context.Context, repoID, index int64) ([]*Deployment, error)
+	// ListStatuses returns all statuses of the deployment, ordered by the most
+	// recently created first.
+	ListStatuses(ctx context.Context, deploymentID int64) ([]*DeploymentStatus, error)
+}
+
+var Deployments DeploymentsStore
+
+// DeploymentState is the state of a deployment.
+type DeploymentState string
+
+const (
+	DeploymentPending    DeploymentState = "pending"
+	DeploymentInProgress DeploymentState = "in_progress"
+	DeploymentSuccess    DeploymentState = "success"
+	DeploymentFailure    DeploymentState = "failure"
+	DeploymentError      DeploymentState = "error"
+	DeploymentInactive   DeploymentState = "inactive"
+)
+
+// IsValid returns true if the state is one of known states.
+func (s DeploymentState) IsValid() bool {
+	switch s {
+	case DeploymentPending, DeploymentInProgress, DeploymentSuccess,
+		DeploymentFailure, DeploymentError, DeploymentInactive:
+		return true
+	}
+	return false
+}
+
+// Deployment is a deployment of a commit to an environment recorded by
+// external systems, e.g. CD. The state of a deployment is the state of its
+// latest status.
+type Deployment struct {
+	ID          int64           `gorm:"primaryKey"`
+	RepoID      int64           `gorm:"index:deployment_repo_environment;not null"`
+	Environment string          `gorm:"index:deployment_repo_environment;not null"`
+	Ref         string          `gorm:"not null"`
+	SHA         string          `gorm:"type:VARCHAR(40);not null"`
+	Description string          `gorm:"type:TEXT"`
+	State       DeploymentState `gorm:"type:VARCHAR(11);not null"`
+	CreatorID   int64           `gorm:"not null"`
+	CreatedAt   time.Time       `gorm:"not null"`
+	UpdatedAt   time.Time       `gorm:"not null"`
+
+	// PullRequestIndex is the index of the pull request that the environment is
+	// a preview of, or zero for other deployments.
+	PullRequestIndex int64 `gorm:"index;not null;default:0"`
+	// EnvironmentURL is the URL of the deployed environment reported by the
+	// latest status that has one.
+	EnvironmentURL string `gorm:"type:TEXT"`
+}
+
+// IsPreviewable returns true if the deployment has succeeded and the deployed
+// environment can be viewed over HTTP(S).
+func (d *Deployment) IsPreviewable() bool {
+	return d.State == DeploymentSuccess &&
+		(strings.HasPrefix(d.EnvironmentURL, "http://") || strings.HasPrefix(d.EnvironmentURL, "https://"))
+}
+
+// DeploymentStatus is a status of a deployment reported by external systems.
+type DeploymentStatus struct {
+	ID           int64           `gorm:"primaryKey"`
+	DeploymentID int64           `gorm:"index;not null"`
+	State        DeploymentState `gorm:"type:VARCHAR(11);not null"`
+	TargetURL    string          `gorm:"type:TEXT"`
+	Description  string          `gorm:"type:TEXT"`
+	CreatorID    int64           `gorm:"not null"`
+	CreatedAt    time.Time       `gorm:"not null"`
+
+	EnvironmentURL string `gorm:"type:TEXT"`
+}
+
+var _ DeploymentsStore = (*deployments)(nil)
+
+type deployments struct {
+	*gorm.DB
+}
+
+// NewDeploymentsStore returns a persistent interface for deployments of
+// repositories with given database connection.
+func NewDeploymentsStore(db *gorm.DB) DeploymentsStore {
+	return &deployments{DB: db}
+}
+
+type CreateDeploymentOptions struct {
+	Environment string
+	Ref         string
+	SHA         string
+	Description string
+	// PullRequestIndex is the index of the pull request when the deployment is
+	// a preview environment of it.
+	PullRequestIndex int64
+}
+
+func (db *deployments) Create(ctx context.Context, repoID, creatorID int64, opts CreateDeploymentOptions) (*Deployment, error) {
+	d := &Deployment{
+		RepoID:      repoID,
+		Environment: opts.Environment,
+		Ref:         opts.Ref,
+		SHA:         opts.SHA,
+		Description: opts.Description,
+		State:       DeploymentPending,
+		CreatorID:   creatorID,
+
+		PullRequestIndex: opts.PullRequestIndex,
+	}
+	return d, db.WithContext(ctx).Create(d).Error
+}
+
+type CreateDeploymentStatusOptions struct {
+	State       DeploymentState
+	TargetURL   string
+	Description string
+	// EnvironmentURL is the URL of the deployed environment.
+	EnvironmentURL string
+}
+
+func (db *deployments) CreateStatus(ctx context.Context, deploymentID, creatorID int64, opts CreateDeploymentStatusOptions) (*DeploymentStatus, error) {
+	s := &DeploymentStatus{
+		DeploymentID: deploymentID,
+		State:        opts.State,
+		TargetURL:    opts.TargetURL,
+		Description:  opts.Description,
+		CreatorID:    creatorID,
+
+		EnvironmentURL: opts.EnvironmentURL,
+	}
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(s).Error
+		if err != nil {
+			return errors.Wrap(err, "create status")
+		}
+
+		updates := map[string]interface{}{
+			"state":      s.State,
+			"updated_at": tx.NowFunc(),
+		}
+		if s.EnvironmentURL != "" {
+			updates["environment_url"] = s.EnvironmentURL
+		}
+		err = tx.Model(new(Deployment)).
+			Where("id = ?", deploymentID).
+			Updates(updates).
+			Error
+		if err != nil {
+			return errors.Wrap(err, "update deployment state")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (db *deployments) DeactivatePullRequest(ctx context.Context, repoID, index, creatorID int64) ([]*Deployment, error) {
+	var ds []*Deployment
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("repo_id = ? AND pull_request_index = ? AND state != ?", repoID, index, DeploymentInactive).
+			Order("id ASC").
+			Find(&ds).
+			Error
+		if err != nil {
+			return errors.Wrap(err, "list deployments")
+		} else if len(ds) == 0 {
+			return nil
+		}
+
+		statuses := make([]*DeploymentStatus, len(ds))
+		ids := make([]int64, len(ds))
+		for i, d := range ds {
+			statuses[i] = &DeploymentStatus{
+				DeploymentID: d.ID,
+				State:        DeploymentInactive,
+				Description:  "Pull request closed",
+				CreatorID:    creatorID,
+			}
+			ids[i] = d.ID
+		}
+		err = tx.Create(&statuses).Error
+		if err != nil {
+			return errors.Wrap(err, "create statuses")
+		}
+
+		now := tx.NowFunc()
+		err = tx.Model(new(Deployment)).
+			Where("id IN (?)", ids).
+			Updates(map[string]interface{}{
+				"state":      DeploymentInactive,
+				"updated_at": now,
+			}).
+			Error
+		if err != nil {
+			return errors.Wrap(err, "update deployment states")
+		}
+		for _, d := range ds {
+			d.State = DeploymentInactive
+			d.UpdatedAt = now
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
+var _ errutil.NotFound = (*ErrDeploymentNotExist)(nil)
+
+type ErrDeploymentNotExist struct {
+	args errutil.Args
+}
+
+func IsErrDeploymentNotExist(err error) bool {
+	_, ok := err.(ErrDeploymentNotExist)
+	return ok
+}
+
+func (err ErrDeploymentNotExist) Error() string {
+	return fmt.Sprintf("deployment does not exist: %v", err.args)
+}
+
+func (ErrDeploymentNotExist) NotFound() bool {
+	return true
+}
+
+func (db *deployments) GetByID(ctx context.Context, repoID, id int64) (*Deployment, error) {
+	d := new(Deployment)
+	err := db.WithContext(ctx).Where("id = ? AND repo_id = ?", id, repoID).First(d).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrDeploymentNotExist{args: errutil.Args{"repoID": repoID, "id": id}}
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+func (db *deployments) HasSucceeded(ctx context.Context, repoID int64, environment, sha string) (bool, error) {
+	var count int64
+	err := db.WithContext(ctx).
+		Model(new(Deployment)).
+		Where("repo_id = ? AND environment = ? AND sha = ? AND state = ?", repoID, environment, sha, DeploymentSuccess).
+		Count(&count).
+		Error
+	return count > 0, err
+}
+
+type ListDeploymentsOptions struct {
+	// Environment filters deployments by environment when not empty.
+	Environment string
+	// Ref filters deployments by ref when not empty.
+	Ref string
+	// SHA filters deployments by commit SHA when not empty.
+	SHA string
+	// PullRequestIndex filters deployments by the pull request that the
+	// environment is a preview of when positive.
+	PullRequestIndex int64
+}
+
+func (db *deployments) List(ctx context.Context, repoID int64, opts ListDeploymentsOptions) ([]*Deployment, error) {
+	tx := db.WithContext(ctx).Where("repo_id = ?", repoID)
+	if opts.Environment != "" {
+		tx = tx.Where("environment = ?", opts.Environment)
+	}
+	if opts.Ref != "" {
+		tx = tx.Where("ref = ?", opts.Ref)
+	}
+	if opts.SHA != "" {
+		tx = tx.Where("sha = ?", opts.SHA)
+	}
+	if opts.PullRequestIndex > 0 {
+		tx = tx.Where("pull_request_index = ?", opts.PullRequestIndex)
+	}
+
+	var ds []*Deployment
+	return ds, tx.Order("id DESC").Find(&ds).Error
+}
+
+func (db *deployments) ListEnvironments(ctx context.Context, repoID int64) ([]*Deployment, error) {
+	tx := db.WithContext(ctx)
+	latest := tx.Model(new(Deployment)).
+		Select("MAX(id)").
+		Where("repo_id = ?", repoID).
+		Group("environment")
+
+	var ds []*Deployment
+	return ds, tx.Where("id IN (?)", latest).Order("environment ASC").Find(&ds).Error
+}
+
+func (db *deployments) ListPullRequestEnvironments(ctx context.Context, repoID, index int64) ([]*Deployment, error) {
+	tx := db.WithContext(ctx)
+	latest := tx.Model(new(Deployment)).
+		Select("MAX(id)").
+		Where("repo_id = ? AND pull_request_index = ?", repoID, index).
+		Group("environment")
+
+	var ds []*Deployment
+	return ds, tx.Where("id IN (?)", latest).Order("environment ASC").Find(&ds).Error
+}
+
+func (db *deployments) ListStatuses(ctx context.Context, deploymentID int64) ([]*DeploymentStatus, error) {
+	var statuses []*DeploymentStatus
+	return statuses, db.WithContext(ctx).Where("deployment_id = ?", deploymentID).Order("id DESC").Find(&statuses).Error
+}
@@ -0,0 +1,274 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+func TestDeployments(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(Deployment), new(DeploymentStatus)}
+	db := &deployments{
+		DB: dbtest.NewDB(t, "deployments", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *deployments)
+	}{
+		{"Create", deploymentsCreate},
+		{"CreateStatus", deploymentsCreateStatus},
+		{"DeactivatePullRequest", deploymentsDeactivatePullRequest},
+		{"GetByID", deploymentsGetByID},
+		{"HasSucceeded", deploymentsHasSucceeded},
+		{"List", deploymentsList},
+		{"ListEnvironments", deploymentsListEnvironments},
+		{"ListPullRequestEnvironments", deploymentsListPullRequestEnvironments},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func deploymentsCreate(t *testing.T, db *deployments) {
+	ctx := context.Background()
+
+	d, err := db.Create(ctx, 1, 2, CreateDeploymentOptions{
+		Environment: "staging",
+		Ref:         "main",
+		SHA:         testCommitSHA,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), d.RepoID)
+	assert.Equal(t, int64(2), d.CreatorID)
+	assert.Equal(t, DeploymentPending, d.State)
+	assert.False(t, d.CreatedAt.IsZero())
+}
+
+func deploymentsCreateStatus(t *testing.T, db *deployments) {
+	ctx := context.Background()
+
+	d, err := db.Create(ctx, 1, 1, CreateDeploymentOptions{Environment: "staging", Ref: "main", SHA: testCommitSHA})
+	require.NoError(t, err)
+
+	for _, state := range []DeploymentState{DeploymentInProgress, DeploymentSuccess} {
+		_, err = db.CreateStatus(ctx, d.ID, 1, CreateDeploymentStatusOptions{State: state})
+		require.NoError(t, err)
+	}
+
+	// The state of the latest status becomes the state of the deployment.
+	d, err = db.GetByID(ctx, 1, d.ID)
+	require.NoError(t, err)
+	assert.Equal(t, DeploymentSuccess, d.State)
+
+	statuses, err := db.ListStatuses(ctx, d.ID)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, DeploymentSuccess, statuses[0].State)
+	assert.Equal(t, DeploymentInProgress, statuses[1].State)
+
+	// The environment URL is kept when the latest status does not have one.
+	_, err = db.CreateStatus(ctx, d.ID, 1, CreateDeploymentStatusOptions{State: DeploymentSuccess, EnvironmentURL: "https://pr-1.example.com"})
+	require.NoError(t, err)
+	_, err = db.CreateStatus(ctx, d.ID, 1, CreateDeploymentStatusOptions{State: DeploymentSuccess})
+	require.NoError(t, err)
+	d, err = db.GetByID(ctx, 1, d.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://pr-1.example.com", d.EnvironmentURL)
+}
+
+func deploymentsDeactivatePullRequest(t *testing.T, db *deployments) {
+	ctx := context.Background()
+
+	var ids []int64
+	for _, opts := range []CreateDeploymentOptions{
+		{Environment: "pr-1", Ref: "feature", SHA: testCommitSHA, PullRequestIndex: 1},
+		{Environment: "pr-1-docs", Ref: "feature", SHA: testCommitSHA, PullRequestIndex: 1},
+		{Environment: "pr-2", Ref: "fix", SHA: testCommitSHA, PullRequestIndex: 2},
+		{Environment: "staging", Ref: "main", SHA: testCommitSHA},
+	} {
+		d, err := db.Create(ctx, 1, 1, opts)
+		require.NoError(t, err)
+		ids = append(ids, d.ID)
+	}
+	_, err := db.CreateStatus(ctx, ids[1], 1, CreateDeploymentStatusOptions{State: DeploymentInactive})
+	require.NoError(t, err)
+
+	got, err := db.DeactivatePullRequest(ctx, 1, 1, 2)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, ids[0], got[0].ID)
+	assert.Equal(t, DeploymentInactive, got[0].State)
+
+	statuses, err := db.ListStatuses(ctx, ids[0])
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, DeploymentInactive, statuses[0].State)
+	assert.Equal(t, int64(2), statuses[0].CreatorID)
+
+	// Deployments of other pull requests are not deactivated.
+	for _, id := range ids[2:] {
+		d, err := db.GetByID(ctx, 1, id)
+		require.NoError(t, err)
+		assert.Equal(t, DeploymentPending, d.State)
+	}
+
+	// Deactivating again is a no-op.
+	got, err = db.DeactivatePullRequest(ctx, 1, 1, 2)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func deploymentsGetByID(t *testing.T, db *deployments) {
+	ctx := context.Background()
+
+	d, err := db.Create(ctx, 1, 1, CreateDeploymentOptions{Environment: "staging", Ref: "main", SHA: testCommitSHA})
+	require.NoError(t, err)
+
+	// Deployments of other repositories are not visible.
+	_, err = db.GetByID(ctx, 2, d.ID)
+	wantErr := ErrDeploymentNotExist{args: map[string]interface{}{"repoID": int64(2), "id": d.ID}}
+	assert.Equal(t, wantErr, err)
+
+	got, err := db.GetByID(ctx, 1, d.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "staging", got.Environment)
+}
+
+func deploymentsHasSucceeded(t *testing.T, db *deployments) {
+	ctx := context.Background()
+
+	d, err := db.Create(ctx, 1, 1, CreateDeploymentOptions{Environment: "staging", Ref: "main", SHA: testCommitSHA})
+	require.NoError(t, err)
+
+	got, err := db.HasSucceeded(ctx, 1, "staging", testCommitSHA)
+	require.NoError(t, err)
+	assert.False(t, got)
+
+	_, err = db.CreateStatus(ctx, d.ID, 1, CreateDeploymentStatusOptions{State: DeploymentSuccess})
+	require.NoError(t, err)
+
+	got, err = db.HasSucceeded(ctx, 1, "staging", testCommitSHA)
+	require.NoError(t, err)
+	assert.True(t, got)
+
+	got, err = db.HasSucceeded(ctx, 1, "production", testCommitSHA)
+	require.NoError(t, err)
+	assert.False(t, got)
+}
+
+func deploymentsList(t *testing.T, db *deployments) {
+	ctx := context.Background()
+
+	for _, opts := range []CreateDeploymentOptions{
+		{Environment: "staging", Ref: "main", SHA: testCommitSHA},
+		{Environment: "production", Ref: "v1.0.0", SHA: testCommitSHA},
+		{Environment: "staging", Ref: "develop", SHA: "1c6e3c0b7a2f5b4d0c1f3a1d6d2e8f9b1c6e3c0b"},
+	} {
+		_, err := db.Create(ctx, 1, 1, opts)
+		require.NoError(t, err)
+	}
+	// Deployments of other repositories are not included.
+	_, err := db.Create(ctx, 2, 1, CreateDeploymentOptions{Environment: "staging", Ref: "main", SHA: testCommitSHA})
+	require.NoError(t, err)
+
+	got, err := db.List(ctx, 1, ListDeploymentsOptions{})
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	assert.Equal(t, "develop", got[0].Ref)
+
+	got, err = db.List(ctx, 1, ListDeploymentsOptions{Environment: "staging", SHA: testCommitSHA})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "main", got[0].Ref)
+
+	got, err = db.List(ctx, 1, ListDeploymentsOptions{Ref: "v1.0.0"})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "production", got[0].Environment)
+}
+
+func deploymentsListEnvironments(t *testing.T, db *deployments) {
+	ctx := context.Background()
+
+	for _, opts := range []CreateDeploymentOptions{
+		{Environment: "staging", Ref: "main", SHA: testCommitSHA},
+		{Environment: "production", Ref: "v1.0.0", SHA: testCommitSHA},
+		{Environment: "staging", Ref: "develop", SHA: "1c6e3c0b7a2f5b4d0c1f3a1d6d2e8f9b1c6e3c0b"},
+	} {
+		_, err := db.Create(ctx, 1, 1, opts)
+		require.NoError(t, err)
+	}
+	_, err := db.Create(ctx, 2, 1, CreateDeploymentOptions{Environment: "qa", Ref: "main", SHA: testCommitSHA})
+	require.NoError(t, err)
+
+	got, err := db.ListEnvironments(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "production", got[0].Environment)
+	assert.Equal(t, "staging", got[1].Environment)
+	assert.Equal(t, "develop", got[1].Ref)
+}
+
+func deploymentsListPullRequestEnvironments(t *testing.T, db *deployments) {
+	ctx := context.Background()
+
+	for _, opts := range []CreateDeploymentOptions{
+		{Environment: "pr-1", Ref: "feature", SHA: testCommitSHA, PullRequestIndex: 1},
+		{Environment: "pr-1", Ref: "feature", SHA: "1c6e3c0b7a2f5b4d0c1f3a1d6d2e8f9b1c6e3c0b", PullRequestIndex: 1},
+		{Environment: "pr-2", Ref: "fix", SHA: testCommitSHA, PullRequestIndex: 2},
+		{Environment: "staging", Ref: "feature", SHA: testCommitSHA},
+	} {
+		_, err := db.Create(ctx, 1, 1, opts)
+		require.NoError(t, err)
+	}
+
+	got, err := db.ListPullRequestEnvironments(ctx, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "1c6e3c0b7a2f5b4d0c1f3a1d6d2e8f9b1c6e3c0b", got[0].SHA)
+
+	got, err = db.List(ctx, 1, ListDeploymentsOptions{PullRequestIndex: 2})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "pr-2", got[0].Environment)
+}
+
+func TestDeployment_IsPreviewable(t *testing.T) {
+	tests := []struct {
+		name       string
+		deployment *Deployment
+		want       bool
+	}{
+		{name: "success", deployment: &Deployment{State: DeploymentSuccess, EnvironmentURL: "https://pr-1.example.com"}, want: true},
+		{name: "no URL", deployment: &Deployment{State: DeploymentSuccess}, want: false},
+		{name: "pending", deployment: &Deployment{State: DeploymentPending, EnvironmentURL: "https://pr-1.example.com"}, want: false},
+		{name: "unsafe URL", deployment: &Deployment{State: DeploymentSuccess, EnvironmentURL: "javascript:alert(1)"}, want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.deployment.IsPreviewable())
+		})
+	}
+}
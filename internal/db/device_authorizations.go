@@ -0,0 +1,250 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	gouuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/cryptoutil"
+	"gogs.io/gogs/internal/errutil"
+)
+
+// DeviceAuthorizationsStore is the persistent interface for device
+// authorizations of the OAuth 2.0 Device Authorization Grant (RFC 8628).
+//
+// NOTE: All methods are sorted in alphabetical order.
+type DeviceAuthorizationsStore interface {
+	// Approve marks the pending device authorization with given user code as
+	// approved by the user. It returns ErrDeviceAuthorizationNotExist when not
+	// found or no longer pending.
+	Approve(ctx context.Context, userCode string, userID int64) error
+	// Create creates a new pending device authorization for given client that
+	// expires after given duration. The raw device code is only available in the
+	// returned object and never persisted.
+	Create(ctx context.Context, clientID string, lifetime time.Duration) (*DeviceAuthorization, error)
+	// DeleteByID deletes the device authorization by given ID. It returns
+	// ErrDeviceAuthorizationNotExist when not found, e.g. it has been deleted by
+	// a concurrent request.
+	DeleteByID(ctx context.Context, id int64) error
+	// DeleteExpired deletes all device authorizations that have been expired.
+	DeleteExpired(ctx context.Context) error
+	// Deny marks the pending device authorization with given user code as denied
+	// by the user. It returns ErrDeviceAuthorizationNotExist when not found or no
+	// longer pending.
+	Deny(ctx context.Context, userCode string, userID int64) error
+	// GetByDeviceCode returns the device authorization with given raw device
+	// code. It returns ErrDeviceAuthorizationNotExist when not found.
+	GetByDeviceCode(ctx context.Context, deviceCode string) (*DeviceAuthorization, error)
+	// GetByUserCode returns the pending device authorization with given user
+	// code. It returns ErrDeviceAuthorizationNotExist when not found, expired or
+	// no longer pending.
+	GetByUserCode(ctx context.Context, userCode string) (*DeviceAuthorization, error)
+	// Touch updates the last polled time of the given device authorization to
+	// the current time.
+	Touch(ctx context.Context, id int64) error
+}
+
+var DeviceAuthorizations DeviceAuthorizationsStore
+
+// DeviceAuthorizationStatus is the status of a device authorization.
+type DeviceAuthorizationStatus string
+
+const (
+	DeviceAuthorizationPending  DeviceAuthorizationStatus = "pending"
+	DeviceAuthorizationApproved DeviceAuthorizationStatus = "approved"
+	DeviceAuthorizationDenied   DeviceAuthorizationStatus = "denied"
+)
+
+// DeviceAuthorization is a pending or decided request from a device (e.g. a
+// Git credential helper or a CLI tool) to obtain an access token on behalf of
+// a user.
+type DeviceAuthorization struct {
+	ID       int64  `gorm:"primaryKey"`
+	ClientID string `gorm:"not null"`
+	// DeviceCode is the raw device code, only available right after creation.
+	DeviceCode       string                    `gorm:"-" json:"-"`
+	DeviceCodeSHA256 string                    `gorm:"column:device_code_sha256;type:VARCHAR(64);unique;not null"`
+	UserCode         string                    `gorm:"type:VARCHAR(9);unique;not null"`
+	UserID           int64                     `gorm:"index"` // The user who approved or denied the request.
+	Status           DeviceAuthorizationStatus `gorm:"not null"`
+	PolledAt         time.Time                 `gorm:"not null"`
+	ExpiresAt        time.Time                 `gorm:"not null"`
+	CreatedAt        time.Time                 `gorm:"not null"`
+}
+
+// IsExpired returns true if the device authorization has been expired at the
+// given time.
+func (a *DeviceAuthorization) IsExpired(now time.Time) bool {
+	return !now.Before(a.ExpiresAt)
+}
+
+var _ DeviceAuthorizationsStore = (*deviceAuthorizations)(nil)
+
+type deviceAuthorizations struct {
+	*gorm.DB
+}
+
+// NewDeviceAuthorizationsStore returns a persistent interface for device
+// authorizations with given database connection.
+func NewDeviceAuthorizationsStore(db *gorm.DB) DeviceAuthorizationsStore {
+	return &deviceAuthorizations{DB: db}
+}
+
+var _ errutil.NotFound = (*ErrDeviceAuthorizationNotExist)(nil)
+
+type ErrDeviceAuthorizationNotExist struct {
+	args errutil.Args
+}
+
+func IsErrDeviceAuthorizationNotExist(err error) bool {
+	_, ok := err.(ErrDeviceAuthorizationNotExist)
+	return ok
+}
+
+func (err ErrDeviceAuthorizationNotExist) Error() string {
+	return fmt.Sprintf("device authorization does not exist: %v", err.args)
+}
+
+func (ErrDeviceAuthorizationNotExist) NotFound() bool {
+	return true
+}
+
+func (db *deviceAuthorizations) decide(ctx context.Context, userCode string, userID int64, status DeviceAuthorizationStatus) error {
+	result := db.WithContext(ctx).
+		Model(new(DeviceAuthorization)).
+		Where("user_code = ? AND status = ? AND expires_at > ?", NormalizeUserCode(userCode), DeviceAuthorizationPending, db.NowFunc()).
+		Updates(map[string]interface{}{
+			"user_id": userID,
+			"status":  status,
+		})
+	if result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		return ErrDeviceAuthorizationNotExist{args: errutil.Args{"userCode": userCode}}
+	}
+	return nil
+}
+
+func (db *deviceAuthorizations) Approve(ctx context.Context, userCode string, userID int64) error {
+	return db.decide(ctx, userCode, userID, DeviceAuthorizationApproved)
+}
+
+// userCodeAlphabet excludes vowels and look-alike characters to avoid forming
+// words and to make user codes easy to type.
+const userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+// generateUserCode returns a random user code in the form of "XXXX-XXXX".
+func generateUserCode() (string, error) {
+	max := big.NewInt(int64(len(userCodeAlphabet)))
+	buf := make([]byte, 0, 9)
+	for i := 0; i < 8; i++ {
+		if i == 4 {
+			buf = append(buf, '-')
+		}
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		buf = append(buf, userCodeAlphabet[n.Int64()])
+	}
+	return string(buf), nil
+}
+
+// NormalizeUserCode returns the canonical form of a user code typed by a user,
+// i.e. upper cased and with the dash at the right place.
+func NormalizeUserCode(userCode string) string {
+	userCode = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(userCode))
+	if len(userCode) != 8 {
+		return userCode
+	}
+	return userCode[:4] + "-" + userCode[4:]
+}
+
+func (db *deviceAuthorizations) Create(ctx context.Context, clientID string, lifetime time.Duration) (*DeviceAuthorization, error) {
+	userCode, err := generateUserCode()
+	if err != nil {
+		return nil, errors.Wrap(err, "generate user code")
+	}
+
+	deviceCode := cryptoutil.SHA1(gouuid.NewV4().String())
+	now := db.NowFunc()
+	auth := &DeviceAuthorization{
+		ClientID:         clientID,
+		DeviceCodeSHA256: cryptoutil.SHA256(deviceCode),
+		UserCode:         userCode,
+		Status:           DeviceAuthorizationPending,
+		PolledAt:         now,
+		ExpiresAt:        now.Add(lifetime),
+	}
+	if err = db.WithContext(ctx).Create(auth).Error; err != nil {
+		return nil, err
+	}
+
+	// Set back the raw device code, for the sake of the caller.
+	auth.DeviceCode = deviceCode
+	return auth, nil
+}
+
+func (db *deviceAuthorizations) DeleteByID(ctx context.Context, id int64) error {
+	result := db.WithContext(ctx).Where("id = ?", id).Delete(new(DeviceAuthorization))
+	if result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		return ErrDeviceAuthorizationNotExist{args: errutil.Args{"id": id}}
+	}
+	return nil
+}
+
+func (db *deviceAuthorizations) DeleteExpired(ctx context.Context) error {
+	return db.WithContext(ctx).Where("expires_at <= ?", db.NowFunc()).Delete(new(DeviceAuthorization)).Error
+}
+
+func (db *deviceAuthorizations) Deny(ctx context.Context, userCode string, userID int64) error {
+	return db.decide(ctx, userCode, userID, DeviceAuthorizationDenied)
+}
+
+func (db *deviceAuthorizations) GetByDeviceCode(ctx context.Context, deviceCode string) (*DeviceAuthorization, error) {
+	auth := new(DeviceAuthorization)
+	err := db.WithContext(ctx).Where("device_code_sha256 = ?", cryptoutil.SHA256(deviceCode)).First(auth).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrDeviceAuthorizationNotExist{args: errutil.Args{"deviceCode": deviceCode}}
+		}
+		return nil, err
+	}
+	return auth, nil
+}
+
+func (db *deviceAuthorizations) GetByUserCode(ctx context.Context, userCode string) (*DeviceAuthorization, error) {
+	auth := new(DeviceAuthorization)
+	err := db.WithContext(ctx).
+		Where("user_code = ? AND status = ? AND expires_at > ?", NormalizeUserCode(userCode), DeviceAuthorizationPending, db.NowFunc()).
+		First(auth).
+		Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrDeviceAuthorizationNotExist{args: errutil.Args{"userCode": userCode}}
+		}
+		return nil, err
+	}
+	return auth, nil
+}
+
+func (db *deviceAuthorizations) Touch(ctx context.Context, id int64) error {
+	return db.WithContext(ctx).
+		Model(new(DeviceAuthorization)).
+		Where("id = ?", id).
+		UpdateColumn("polled_at", db.NowFunc()).
+		Error
+}
@@ -0,0 +1,198 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/errutil"
+)
+
+func TestNormalizeUserCode(t *testing.T) {
+	tests := []struct {
+		userCode string
+		want     string
+	}{
+		{userCode: "BCDF-GHJK", want: "BCDF-GHJK"},
+		{userCode: "bcdf-ghjk", want: "BCDF-GHJK"},
+		{userCode: "bcdfghjk", want: "BCDF-GHJK"},
+		{userCode: " bcdf ghjk ", want: "BCDF-GHJK"},
+		{userCode: "bcd", want: "BCD"},
+	}
+	for _, test := range tests {
+		t.Run(test.userCode, func(t *testing.T) {
+			assert.Equal(t, test.want, NormalizeUserCode(test.userCode))
+		})
+	}
+}
+
+func TestDeviceAuthorizations(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(DeviceAuthorization)}
+	db := &deviceAuthorizations{
+		DB: dbtest.NewDB(t, "deviceAuthorizations", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *deviceAuthorizations)
+	}{
+		{"Approve", deviceAuthorizationsApprove},
+		{"Create", deviceAuthorizationsCreate},
+		{"DeleteByID", deviceAuthorizationsDeleteByID},
+		{"DeleteExpired", deviceAuthorizationsDeleteExpired},
+		{"Deny", deviceAuthorizationsDeny},
+		{"GetByUserCode", deviceAuthorizationsGetByUserCode},
+		{"Touch", deviceAuthorizationsTouch},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func deviceAuthorizationsApprove(t *testing.T, db *deviceAuthorizations) {
+	ctx := context.Background()
+
+	auth, err := db.Create(ctx, "git-credential", 15*time.Minute)
+	require.NoError(t, err)
+
+	err = db.Approve(ctx, auth.UserCode, 1)
+	require.NoError(t, err)
+
+	got, err := db.GetByDeviceCode(ctx, auth.DeviceCode)
+	require.NoError(t, err)
+	assert.Equal(t, DeviceAuthorizationApproved, got.Status)
+	assert.Equal(t, int64(1), got.UserID)
+
+	// Approving again should fail because it is no longer pending
+	err = db.Approve(ctx, auth.UserCode, 1)
+	wantErr := ErrDeviceAuthorizationNotExist{args: errutil.Args{"userCode": auth.UserCode}}
+	assert.Equal(t, wantErr, err)
+}
+
+func deviceAuthorizationsCreate(t *testing.T, db *deviceAuthorizations) {
+	ctx := context.Background()
+
+	auth, err := db.Create(ctx, "git-credential", 15*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "git-credential", auth.ClientID)
+	assert.Equal(t, DeviceAuthorizationPending, auth.Status)
+	assert.Len(t, auth.UserCode, 9)
+	assert.Equal(t, 40, len(auth.DeviceCode), "device code length")
+
+	// Get it back by the raw device code
+	got, err := db.GetByDeviceCode(ctx, auth.DeviceCode)
+	require.NoError(t, err)
+	assert.Equal(t, auth.ID, got.ID)
+	assert.Equal(t, db.NowFunc().Add(15*time.Minute).Format(time.RFC3339), got.ExpiresAt.UTC().Format(time.RFC3339))
+
+	// The device code is never persisted in raw
+	_, err = db.GetByDeviceCode(ctx, auth.DeviceCodeSHA256)
+	wantErr := ErrDeviceAuthorizationNotExist{args: errutil.Args{"deviceCode": auth.DeviceCodeSHA256}}
+	assert.Equal(t, wantErr, err)
+}
+
+func deviceAuthorizationsDeleteByID(t *testing.T, db *deviceAuthorizations) {
+	ctx := context.Background()
+
+	auth, err := db.Create(ctx, "git-credential", 15*time.Minute)
+	require.NoError(t, err)
+
+	err = db.DeleteByID(ctx, auth.ID)
+	require.NoError(t, err)
+
+	_, err = db.GetByDeviceCode(ctx, auth.DeviceCode)
+	assert.True(t, IsErrDeviceAuthorizationNotExist(err), "%v", err)
+
+	// Deleting again fails, so that concurrent requests cannot both use it
+	err = db.DeleteByID(ctx, auth.ID)
+	wantErr := ErrDeviceAuthorizationNotExist{args: errutil.Args{"id": auth.ID}}
+	assert.Equal(t, wantErr, err)
+}
+
+func deviceAuthorizationsDeleteExpired(t *testing.T, db *deviceAuthorizations) {
+	ctx := context.Background()
+
+	expired, err := db.Create(ctx, "git-credential", -time.Minute)
+	require.NoError(t, err)
+	active, err := db.Create(ctx, "git-credential", 15*time.Minute)
+	require.NoError(t, err)
+
+	err = db.DeleteExpired(ctx)
+	require.NoError(t, err)
+
+	_, err = db.GetByDeviceCode(ctx, expired.DeviceCode)
+	wantErr := ErrDeviceAuthorizationNotExist{args: errutil.Args{"deviceCode": expired.DeviceCode}}
+	assert.Equal(t, wantErr, err)
+
+	_, err = db.GetByDeviceCode(ctx, active.DeviceCode)
+	require.NoError(t, err)
+}
+
+func deviceAuthorizationsDeny(t *testing.T, db *deviceAuthorizations) {
+	ctx := context.Background()
+
+	auth, err := db.Create(ctx, "git-credential", 15*time.Minute)
+	require.NoError(t, err)
+
+	err = db.Deny(ctx, auth.UserCode, 1)
+	require.NoError(t, err)
+
+	got, err := db.GetByDeviceCode(ctx, auth.DeviceCode)
+	require.NoError(t, err)
+	assert.Equal(t, DeviceAuthorizationDenied, got.Status)
+}
+
+func deviceAuthorizationsGetByUserCode(t *testing.T, db *deviceAuthorizations) {
+	ctx := context.Background()
+
+	auth, err := db.Create(ctx, "git-credential", 15*time.Minute)
+	require.NoError(t, err)
+
+	// User codes are case-insensitive
+	got, err := db.GetByUserCode(ctx, strings.ToLower(auth.UserCode))
+	require.NoError(t, err)
+	assert.Equal(t, auth.ID, got.ID)
+
+	// Expired user codes are not found
+	expired, err := db.Create(ctx, "git-credential", -time.Minute)
+	require.NoError(t, err)
+	_, err = db.GetByUserCode(ctx, expired.UserCode)
+	wantErr := ErrDeviceAuthorizationNotExist{args: errutil.Args{"userCode": expired.UserCode}}
+	assert.Equal(t, wantErr, err)
+}
+
+func deviceAuthorizationsTouch(t *testing.T, db *deviceAuthorizations) {
+	ctx := context.Background()
+
+	auth, err := db.Create(ctx, "git-credential", 15*time.Minute)
+	require.NoError(t, err)
+
+	err = db.Touch(ctx, auth.ID)
+	require.NoError(t, err)
+
+	got, err := db.GetByDeviceCode(ctx, auth.DeviceCode)
+	require.NoError(t, err)
+	assert.Equal(t, db.NowFunc().Format(time.RFC3339), got.PolledAt.UTC().Format(time.RFC3339))
+}
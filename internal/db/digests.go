@@ -0,0 +1,428 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/email"
+	"gogs.io/gogs/internal/errutil"
+)
+
+// DigestsStore is the persistent interface for activity digest subscriptions
+// of users.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type DigestsStore interface {
+	// ListActivities returns new issues, merged pull requests and published
+	// releases of repositories watched by the user between since and until,
+	// excluding activities done by the user, ordered by repository and time.
+	ListActivities(ctx context.Context, userID int64, since, until time.Time) ([]*DigestActivity, error)
+	// ListByUser returns all digest subscriptions of the user.
+	ListByUser(ctx context.Context, userID int64) ([]*DigestSubscription, error)
+	// ListDue returns subscriptions of the frequency that were last sent no later
+	// than one period before the given time.
+	ListDue(ctx context.Context, frequency DigestFrequency, before time.Time) ([]*DigestSubscription, error)
+	// MarkSent updates the last sent time of the subscription.
+	MarkSent(ctx context.Context, id int64, at time.Time) error
+	// Subscribe subscribes the user to digests of the frequency. It is a no-op
+	// when the user is already subscribed. It returns ErrInvalidDigestFrequency
+	// when the frequency is not one of known frequencies.
+	Subscribe(ctx context.Context, userID int64, frequency DigestFrequency) error
+	// Unsubscribe unsubscribes the user from digests of the frequency. It is a
+	// no-op when the user is not subscribed.
+	Unsubscribe(ctx context.Context, userID int64, frequency DigestFrequency) error
+}
+
+var Digests DigestsStore
+
+// DigestFrequency is how often an activity digest is sent.
+type DigestFrequency string
+
+const (
+	DigestDaily  DigestFrequency = "daily"
+	DigestWeekly DigestFrequency = "weekly"
+)
+
+// AllDigestFrequencies is the list of all digest frequencies.
+var AllDigestFrequencies = []DigestFrequency{DigestDaily, DigestWeekly}
+
+// IsValid returns true if the frequency is one of known frequencies.
+func (f DigestFrequency) IsValid() bool {
+	return f == DigestDaily || f == DigestWeekly
+}
+
+// Period returns the time duration covered by each digest of the frequency.
+func (f DigestFrequency) Period() time.Duration {
+	if f == DigestWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// DigestSubscription is a subscription of a user to activity digests.
+type DigestSubscription struct {
+	ID         int64           `gorm:"primaryKey"`
+	UserID     int64           `gorm:"uniqueIndex:digest_subscription_user_frequency_unique;not null"`
+	Frequency  DigestFrequency `gorm:"type:VARCHAR(10);uniqueIndex:digest_subscription_user_frequency_unique;not null"`
+	LastSentAt time.Time       `gorm:"not null"`
+	CreatedAt  time.Time       `gorm:"not null"`
+}
+
+// DigestActivityType is the type of an activity listed in digests.
+type DigestActivityType string
+
+const (
+	DigestActivityIssue   DigestActivityType = "issue"
+	DigestActivityMerge   DigestActivityType = "merge"
+	DigestActivityRelease DigestActivityType = "release"
+)
+
+// DigestActivity is an activity of a watched repository listed in digests.
+type DigestActivity struct {
+	Type      DigestActivityType
+	RepoID    int64
+	RepoName  string // The full name of the repository, e.g. "owner/repo".
+	ActorName string
+	// The issue or pull request index, or the tag name for releases.
+	Ref       string
+	Title     string
+	CreatedAt time.Time
+}
+
+var _ DigestsStore = (*digests)(nil)
+
+type digests struct {
+	*gorm.DB
+}
+
+// NewDigestsStore returns a persistent interface for activity digest
+// subscriptions with given database connection.
+func NewDigestsStore(db *gorm.DB) DigestsStore {
+	return &digests{DB: db}
+}
+
+type ErrInvalidDigestFrequency struct {
+	args errutil.Args
+}
+
+func IsErrInvalidDigestFrequency(err error) bool {
+	_, ok := err.(ErrInvalidDigestFrequency)
+	return ok
+}
+
+func (err ErrInvalidDigestFrequency) Error() string {
+	return fmt.Sprintf("invalid digest frequency: %v", err.args)
+}
+
+func (db *digests) ListActivities(ctx context.Context, userID int64, since, until time.Time) ([]*DigestActivity, error) {
+	/*
+		Equivalent SQL for PostgreSQL:
+
+		SELECT * FROM action
+		WHERE user_id = @userID
+			AND act_user_id <> @userID
+			AND op_type IN (@ActionCreateIssue, @ActionMergePullRequest)
+			AND created_unix >= @since AND created_unix < @until
+		ORDER BY repo_id ASC, created_unix ASC, id ASC
+	*/
+	var actions []*Action
+	err := db.WithContext(ctx).
+		Where("user_id = ? AND act_user_id <> ?", userID, userID).
+		Where("op_type IN ?", []ActionType{ActionCreateIssue, ActionMergePullRequest}).
+		Where("created_unix >= ? AND created_unix < ?", since.Unix(), until.Unix()).
+		Order("repo_id ASC, created_unix ASC, id ASC").
+		Find(&actions).
+		Error
+	if err != nil {
+		return nil, errors.Wrap(err, "list actions")
+	}
+
+	activities := make([]*DigestActivity, 0, len(actions))
+	for _, a := range actions {
+		activity := &DigestActivity{
+			Type:      DigestActivityIssue,
+			RepoID:    a.RepoID,
+			RepoName:  a.RepoUserName + "/" + a.RepoName,
+			ActorName: a.ActUserName,
+			CreatedAt: time.Unix(a.CreatedUnix, 0),
+		}
+		if a.OpType == ActionMergePullRequest {
+			activity.Type = DigestActivityMerge
+		}
+
+		// The content of both types of actions is in the form of "<index>|<title>".
+		fields := strings.SplitN(a.Content, "|", 2)
+		activity.Ref = fields[0]
+		if len(fields) == 2 {
+			activity.Title = fields[1]
+		}
+		activities = append(activities, activity)
+	}
+
+	/*
+		Equivalent SQL for PostgreSQL:
+
+		SELECT
+			release.repo_id,
+			repository.owner_id,
+			repository.name AS repo_name,
+			release.publisher_id,
+			release.tag_name,
+			release.title,
+			release.created_unix
+		FROM watch
+		JOIN release ON release.repo_id = watch.repo_id
+		JOIN repository ON repository.id = release.repo_id
+		WHERE watch.user_id = @userID
+			AND release.publisher_id <> @userID
+			AND release.is_draft = FALSE
+			AND release.created_unix >= @since AND release.created_unix < @until
+		ORDER BY release.repo_id ASC, release.created_unix ASC, release.id ASC
+	*/
+	var releases []struct {
+		RepoID      int64
+		OwnerID     int64
+		RepoName    string
+		PublisherID int64
+		TagName     string
+		Title       string
+		CreatedUnix int64
+	}
+	// NOTE: "release" is a reserved word in MySQL, thus the table is quoted and
+	// referenced by an alias.
+	err = db.WithContext(ctx).
+		Model(new(Watch)).
+		Select("r.repo_id, repository.owner_id, repository.name AS repo_name, r.publisher_id, r.tag_name, r.title, r.created_unix").
+		Joins("JOIN ? ON r.repo_id = watch.repo_id", clause.Table{Name: "release", Alias: "r"}).
+		Joins("JOIN repository ON repository.id = r.repo_id").
+		Where("watch.user_id = ? AND r.publisher_id <> ?", userID, userID).
+		Where("r.is_draft = ?", false).
+		Where("r.created_unix >= ? AND r.created_unix < ?", since.Unix(), until.Unix()).
+		Order("r.repo_id ASC, r.created_unix ASC, r.id ASC").
+		Scan(&releases).
+		Error
+	if err != nil {
+		return nil, errors.Wrap(err, "list releases")
+	}
+	if len(releases) == 0 {
+		return activities, nil
+	}
+
+	userIDs := make([]int64, 0, len(releases)*2)
+	for _, r := range releases {
+		userIDs = append(userIDs, r.OwnerID, r.PublisherID)
+	}
+	var users []*User
+	err = db.WithContext(ctx).Select("id, name").Where("id IN ?", userIDs).Find(&users).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "list owners and publishers")
+	}
+	names := make(map[int64]string, len(users))
+	for _, u := range users {
+		names[u.ID] = u.Name
+	}
+
+	for _, r := range releases {
+		activities = append(activities, &DigestActivity{
+			Type:      DigestActivityRelease,
+			RepoID:    r.RepoID,
+			RepoName:  names[r.OwnerID] + "/" + r.RepoName,
+			ActorName: names[r.PublisherID],
+			Ref:       r.TagName,
+			Title:     r.Title,
+			CreatedAt: time.Unix(r.CreatedUnix, 0),
+		})
+	}
+	return activities, nil
+}
+
+func (db *digests) ListByUser(ctx context.Context, userID int64) ([]*DigestSubscription, error) {
+	var subscriptions []*DigestSubscription
+	return subscriptions, db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("frequency ASC").
+		Find(&subscriptions).
+		Error
+}
+
+func (db *digests) ListDue(ctx context.Context, frequency DigestFrequency, before time.Time) ([]*DigestSubscription, error) {
+	var subscriptions []*DigestSubscription
+	return subscriptions, db.WithContext(ctx).
+		Where("frequency = ? AND last_sent_at <= ?", frequency, before.Add(-frequency.Period())).
+		Order("id ASC").
+		Find(&subscriptions).
+		Error
+}
+
+func (db *digests) MarkSent(ctx context.Context, id int64, at time.Time) error {
+	return db.WithContext(ctx).
+		Model(new(DigestSubscription)).
+		Where("id = ?", id).
+		Update("last_sent_at", at).
+		Error
+}
+
+func (db *digests) Subscribe(ctx context.Context, userID int64, frequency DigestFrequency) error {
+	if !frequency.IsValid() {
+		return ErrInvalidDigestFrequency{args: errutil.Args{"frequency": frequency}}
+	}
+
+	// Activities happened before the subscription are not included in the first
+	// digest.
+	return db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&DigestSubscription{
+			UserID:     userID,
+			Frequency:  frequency,
+			LastSentAt: db.NowFunc(),
+		}).
+		Error
+}
+
+func (db *digests) Unsubscribe(ctx context.Context, userID int64, frequency DigestFrequency) error {
+	return db.WithContext(ctx).
+		Where("user_id = ? AND frequency = ?", userID, frequency).
+		Delete(new(DigestSubscription)).
+		Error
+}
+
+// DigestUnsubscribeToken returns the token for unsubscribing the user from
+// digests of the frequency without signing in.
+func DigestUnsubscribeToken(userID int64, frequency DigestFrequency) string {
+	mac := hmac.New(sha256.New, []byte(conf.Security.SecretKey))
+	_, _ = mac.Write([]byte("digest-unsubscribe:" + strconv.FormatInt(userID, 10) + ":" + string(frequency)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyDigestUnsubscribeToken returns true if the token is valid for
+// unsubscribing the user from digests of the frequency.
+func VerifyDigestUnsubscribeToken(userID int64, frequency DigestFrequency, token string) bool {
+	return hmac.Equal([]byte(DigestUnsubscribeToken(userID, frequency)), []byte(token))
+}
+
+// SendActivityDigests sends activity digests to all users whose subscriptions
+// are due.
+func SendActivityDigests() {
+	if taskStatusTable.IsRunning(_SEND_ACTIVITY_DIGESTS) {
+		return
+	}
+	taskStatusTable.Start(_SEND_ACTIVITY_DIGESTS)
+	defer taskStatusTable.Stop(_SEND_ACTIVITY_DIGESTS)
+
+	log.Trace("Doing: SendActivityDigests")
+
+	if !conf.Email.Enabled {
+		log.Warn("Activity digests are not sent because email service is disabled")
+		return
+	}
+
+	ctx := context.Background()
+	now := time.Now()
+	for _, frequency := range AllDigestFrequencies {
+		subscriptions, err := Digests.ListDue(ctx, frequency, now)
+		if err != nil {
+			log.Error("Failed to list due %s digest subscriptions: %v", frequency, err)
+			continue
+		}
+
+		for _, s := range subscriptions {
+			if err = sendActivityDigest(ctx, s, now); err != nil {
+				log.Error("Failed to send %s digest to user [id: %d]: %v", frequency, s.UserID, err)
+			}
+
+			// Always move forward to not send the same activities again on failures.
+			if err = Digests.MarkSent(ctx, s.ID, now); err != nil {
+				log.Error("Failed to mark %s digest of user [id: %d] as sent: %v", frequency, s.UserID, err)
+			}
+		}
+	}
+}
+
+func sendActivityDigest(ctx context.Context, s *DigestSubscription, until time.Time) error {
+	u, err := Users.GetByID(ctx, s.UserID)
+	if err != nil {
+		return errors.Wrap(err, "get user")
+	}
+	if !u.IsActive || u.ProhibitLogin {
+		return nil
+	}
+
+	activities, err := Digests.ListActivities(ctx, u.ID, s.LastSentAt, until)
+	if err != nil {
+		return errors.Wrap(err, "list activities")
+	}
+
+	// The user may have lost access to some watched repositories.
+	canRead := make(map[int64]bool)
+	repos := make([]email.DigestRepository, 0, 5)
+	for _, a := range activities {
+		allowed, ok := canRead[a.RepoID]
+		if !ok {
+			repo, err := GetRepositoryByID(a.RepoID)
+			if err != nil {
+				if !IsErrRepoNotExist(err) {
+					return errors.Wrap(err, "get repository")
+				}
+			} else {
+				allowed = Perms.Authorize(ctx, u.ID, repo.ID, AccessModeRead,
+					AccessModeOptions{
+						OwnerID: repo.OwnerID,
+						Private: repo.IsPrivate,
+					},
+				)
+			}
+			canRead[a.RepoID] = allowed
+		}
+		if !allowed {
+			continue
+		}
+
+		if len(repos) == 0 || repos[len(repos)-1].Name != a.RepoName {
+			repos = append(repos, email.DigestRepository{
+				Name: a.RepoName,
+				Link: conf.Server.ExternalURL + a.RepoName,
+			})
+		}
+		r := &repos[len(repos)-1]
+		item := email.DigestItem{
+			ActorName: a.ActorName,
+			Ref:       a.Ref,
+			Title:     a.Title,
+		}
+		switch a.Type {
+		case DigestActivityIssue:
+			item.Link = r.Link + "/issues/" + a.Ref
+			r.Issues = append(r.Issues, item)
+		case DigestActivityMerge:
+			item.Link = r.Link + "/pulls/" + a.Ref
+			r.Merges = append(r.Merges, item)
+		case DigestActivityRelease:
+			item.Link = r.Link + "/releases/tag/" + a.Ref
+			r.Releases = append(r.Releases, item)
+		}
+	}
+	if len(repos) == 0 {
+		return nil
+	}
+
+	unsubscribeLink := fmt.Sprintf("%suser/digests/unsubscribe?user=%d&frequency=%s&token=%s",
+		conf.Server.ExternalURL, u.ID, s.Frequency, DigestUnsubscribeToken(u.ID, s.Frequency))
+	email.SendActivityDigestMail(NewMailerUser(u), string(s.Frequency), s.LastSentAt, until, repos, unsubscribeLink, u.notificationDeliverAt(until))
+	return nil
+}
@@ -0,0 +1,217 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/errutil"
+)
+
+func TestDigests(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(Action), new(DigestSubscription), new(Release), new(Repository), new(User), new(Watch)}
+	db := &digests{
+		DB: dbtest.NewDB(t, "digests", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *digests)
+	}{
+		{"ListActivities", digestsListActivities},
+		{"ListDue", digestsListDue},
+		{"MarkSent", digestsMarkSent},
+		{"Subscribe", digestsSubscribe},
+		{"Unsubscribe", digestsUnsubscribe},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func digestsListActivities(t *testing.T, db *digests) {
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	since := now.Add(-24 * time.Hour)
+	for _, v := range []interface{}{
+		&User{ID: 1, LowerName: "alice", Name: "alice"},
+		&User{ID: 2, LowerName: "bob", Name: "bob"},
+		&User{ID: 3, LowerName: "acme", Name: "acme", Type: UserOrganization},
+		&Repository{ID: 1, OwnerID: 3, LowerName: "repo1", Name: "repo1"},
+		&Repository{ID: 2, OwnerID: 3, LowerName: "repo2", Name: "repo2"},
+		&Watch{UserID: 1, RepoID: 1},
+		&Action{UserID: 1, ActUserID: 2, ActUserName: "bob", OpType: ActionCreateIssue, RepoID: 1, RepoUserName: "acme", RepoName: "repo1", Content: "1|Crash on start", CreatedUnix: now.Add(-2 * time.Hour).Unix()},
+		&Action{UserID: 1, ActUserID: 2, ActUserName: "bob", OpType: ActionMergePullRequest, RepoID: 1, RepoUserName: "acme", RepoName: "repo1", Content: "2|Fix crash on start", CreatedUnix: now.Add(-time.Hour).Unix()},
+		// Done by the user.
+		&Action{UserID: 1, ActUserID: 1, ActUserName: "alice", OpType: ActionCreateIssue, RepoID: 1, RepoUserName: "acme", RepoName: "repo1", Content: "3|Add docs", CreatedUnix: now.Add(-time.Hour).Unix()},
+		// Not included type.
+		&Action{UserID: 1, ActUserID: 2, ActUserName: "bob", OpType: ActionCommentIssue, RepoID: 1, RepoUserName: "acme", RepoName: "repo1", Content: "1|Me too", CreatedUnix: now.Add(-time.Hour).Unix()},
+		// Out of range.
+		&Action{UserID: 1, ActUserID: 2, ActUserName: "bob", OpType: ActionCreateIssue, RepoID: 1, RepoUserName: "acme", RepoName: "repo1", Content: "4|Old issue", CreatedUnix: now.Add(-48 * time.Hour).Unix()},
+		// Received by another user.
+		&Action{UserID: 2, ActUserID: 2, ActUserName: "bob", OpType: ActionCreateIssue, RepoID: 1, RepoUserName: "acme", RepoName: "repo1", Content: "1|Crash on start", CreatedUnix: now.Add(-2 * time.Hour).Unix()},
+		&Release{RepoID: 1, PublisherID: 2, TagName: "v1.0.0", LowerTagName: "v1.0.0", Title: "First release", CreatedUnix: now.Add(-30 * time.Minute).Unix()},
+		// Draft.
+		&Release{RepoID: 1, PublisherID: 2, TagName: "v1.1.0", LowerTagName: "v1.1.0", IsDraft: true, CreatedUnix: now.Add(-30 * time.Minute).Unix()},
+		// Not watched.
+		&Release{RepoID: 2, PublisherID: 2, TagName: "v2.0.0", LowerTagName: "v2.0.0", CreatedUnix: now.Add(-30 * time.Minute).Unix()},
+	} {
+		err := db.Create(v).Error
+		require.NoError(t, err)
+	}
+
+	got, err := db.ListActivities(ctx, 1, since, now)
+	require.NoError(t, err)
+
+	want := []*DigestActivity{
+		{
+			Type:      DigestActivityIssue,
+			RepoID:    1,
+			RepoName:  "acme/repo1",
+			ActorName: "bob",
+			Ref:       "1",
+			Title:     "Crash on start",
+			CreatedAt: now.Add(-2 * time.Hour),
+		},
+		{
+			Type:      DigestActivityMerge,
+			RepoID:    1,
+			RepoName:  "acme/repo1",
+			ActorName: "bob",
+			Ref:       "2",
+			Title:     "Fix crash on start",
+			CreatedAt: now.Add(-time.Hour),
+		},
+		{
+			Type:      DigestActivityRelease,
+			RepoID:    1,
+			RepoName:  "acme/repo1",
+			ActorName: "bob",
+			Ref:       "v1.0.0",
+			Title:     "First release",
+			CreatedAt: now.Add(-30 * time.Minute),
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func digestsListDue(t *testing.T, db *digests) {
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	for _, s := range []*DigestSubscription{
+		{UserID: 1, Frequency: DigestDaily, LastSentAt: now.Add(-25 * time.Hour)},
+		{UserID: 2, Frequency: DigestDaily, LastSentAt: now.Add(-time.Hour)},
+		{UserID: 1, Frequency: DigestWeekly, LastSentAt: now.Add(-25 * time.Hour)},
+		{UserID: 2, Frequency: DigestWeekly, LastSentAt: now.Add(-8 * 24 * time.Hour)},
+	} {
+		err := db.Create(s).Error
+		require.NoError(t, err)
+	}
+
+	got, err := db.ListDue(ctx, DigestDaily, now)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, int64(1), got[0].UserID)
+
+	got, err = db.ListDue(ctx, DigestWeekly, now)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, int64(2), got[0].UserID)
+}
+
+func digestsMarkSent(t *testing.T, db *digests) {
+	ctx := context.Background()
+
+	err := db.Subscribe(ctx, 1, DigestDaily)
+	require.NoError(t, err)
+
+	subscriptions, err := db.ListByUser(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 1)
+
+	at := time.Now().Add(time.Hour).Truncate(time.Second)
+	err = db.MarkSent(ctx, subscriptions[0].ID, at)
+	require.NoError(t, err)
+
+	subscriptions, err = db.ListByUser(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 1)
+	assert.Equal(t, at.Unix(), subscriptions[0].LastSentAt.Unix())
+}
+
+func digestsSubscribe(t *testing.T, db *digests) {
+	ctx := context.Background()
+
+	err := db.Subscribe(ctx, 1, DigestWeekly)
+	require.NoError(t, err)
+	err = db.Subscribe(ctx, 1, DigestDaily)
+	require.NoError(t, err)
+
+	// Subscribing to the same frequency again is a no-op.
+	err = db.Subscribe(ctx, 1, DigestDaily)
+	require.NoError(t, err)
+
+	err = db.Subscribe(ctx, 1, "monthly")
+	wantErr := ErrInvalidDigestFrequency{args: errutil.Args{"frequency": DigestFrequency("monthly")}}
+	assert.Equal(t, wantErr, err)
+
+	subscriptions, err := db.ListByUser(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 2)
+	assert.Equal(t, DigestDaily, subscriptions[0].Frequency)
+	assert.Equal(t, DigestWeekly, subscriptions[1].Frequency)
+}
+
+func digestsUnsubscribe(t *testing.T, db *digests) {
+	ctx := context.Background()
+
+	err := db.Subscribe(ctx, 1, DigestDaily)
+	require.NoError(t, err)
+	err = db.Subscribe(ctx, 2, DigestDaily)
+	require.NoError(t, err)
+
+	err = db.Unsubscribe(ctx, 1, DigestDaily)
+	require.NoError(t, err)
+	// Unsubscribing from a frequency that is not subscribed is a no-op.
+	err = db.Unsubscribe(ctx, 1, DigestWeekly)
+	require.NoError(t, err)
+
+	subscriptions, err := db.ListByUser(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, subscriptions)
+
+	subscriptions, err = db.ListByUser(ctx, 2)
+	require.NoError(t, err)
+	assert.Len(t, subscriptions, 1)
+}
+
+func TestDigestUnsubscribeToken(t *testing.T) {
+	token := DigestUnsubscribeToken(1, DigestDaily)
+	assert.True(t, VerifyDigestUnsubscribeToken(1, DigestDaily, token))
+	assert.False(t, VerifyDigestUnsubscribeToken(1, DigestWeekly, token))
+	assert.False(t, VerifyDigestUnsubscribeToken(2, DigestDaily, token))
+	assert.False(t, VerifyDigestUnsubscribeToken(1, DigestDaily, ""))
+}
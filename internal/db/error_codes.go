@@ -0,0 +1,107 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+// Stable, machine-readable codes of errors, which are returned by the API in
+// the "code" field of error responses. Codes must never change once released
+// so that clients can branch on them, add a new one instead.
+
+func (ErrAccessTokenAlreadyExist) ErrorCode() string     { return "access_token_already_exist" }
+func (ErrAccessTokenNotExist) ErrorCode() string         { return "access_token_not_exist" }
+func (ErrAdminRecoveryTokenNotExist) ErrorCode() string  { return "admin_recovery_token_not_exist" }
+func (ErrAttachmentNotExist) ErrorCode() string          { return "attachment_not_exist" }
+func (ErrBranchNotExist) ErrorCode() string              { return "branch_not_exist" }
+func (ErrCommentCommandInvalid) ErrorCode() string       { return "comment_command_invalid" }
+func (ErrCommentCommandNotPermitted) ErrorCode() string  { return "comment_command_not_permitted" }
+func (ErrCommentNotExist) ErrorCode() string             { return "comment_not_exist" }
+func (ErrDeployKeyAlreadyExist) ErrorCode() string       { return "deploy_key_already_exist" }
+func (ErrDeployKeyNameAlreadyUsed) ErrorCode() string    { return "deploy_key_name_already_used" }
+func (ErrDeployKeyNotExist) ErrorCode() string           { return "deploy_key_not_exist" }
+func (ErrDeploymentNotExist) ErrorCode() string          { return "deployment_not_exist" }
+func (ErrDeviceAuthorizationNotExist) ErrorCode() string { return "device_authorization_not_exist" }
+func (ErrEmailAlreadyUsed) ErrorCode() string            { return "email_already_used" }
+func (ErrFederatedUserNotAllowed) ErrorCode() string     { return "federated_user_not_allowed" }
+func (ErrGPGKeyAlreadyExist) ErrorCode() string          { return "gpg_key_already_exist" }
+func (ErrGPGKeyInvalid) ErrorCode() string               { return "gpg_key_invalid" }
+func (ErrHookTaskNotExist) ErrorCode() string            { return "hook_task_not_exist" }
+func (ErrInvalidAdminRole) ErrorCode() string            { return "invalid_admin_role" }
+func (ErrInvalidCloneAddr) ErrorCode() string            { return "invalid_clone_addr" }
+func (ErrInvalidDigestFrequency) ErrorCode() string      { return "invalid_digest_frequency" }
+func (ErrInvalidTagName) ErrorCode() string              { return "invalid_tag_name" }
+func (ErrIssueNotExist) ErrorCode() string               { return "issue_not_exist" }
+func (ErrJobTokenNotExist) ErrorCode() string            { return "job_token_not_exist" }
+func (ErrKeyAccessDenied) ErrorCode() string             { return "key_access_denied" }
+func (ErrKeyAlreadyExist) ErrorCode() string             { return "key_already_exist" }
+func (ErrKeyNameAlreadyUsed) ErrorCode() string          { return "key_name_already_used" }
+func (ErrKeyNotExist) ErrorCode() string                 { return "key_not_exist" }
+func (ErrKeyUnableVerify) ErrorCode() string             { return "key_unable_verify" }
+func (ErrLFSObjectNotExist) ErrorCode() string           { return "lfs_object_not_exist" }
+func (ErrLabelNotExist) ErrorCode() string               { return "label_not_exist" }
+func (ErrLastOrgOwner) ErrorCode() string                { return "last_org_owner" }
+func (ErrLoginSourceAlreadyExist) ErrorCode() string     { return "login_source_already_exist" }
+func (ErrLoginSourceInUse) ErrorCode() string            { return "login_source_in_use" }
+func (ErrLoginSourceMismatch) ErrorCode() string         { return "login_source_mismatch" }
+func (ErrLoginSourceNotExist) ErrorCode() string         { return "login_source_not_exist" }
+func (ErrMergeQueueEntryAlreadyExist) ErrorCode() string { return "merge_queue_entry_already_exist" }
+func (ErrMergeQueueEntryNotExist) ErrorCode() string     { return "merge_queue_entry_not_exist" }
+func (ErrMilestoneNotExist) ErrorCode() string           { return "milestone_not_exist" }
+func (ErrMirrorRefNotDrifted) ErrorCode() string         { return "mirror_ref_not_drifted" }
+func (ErrNameNotAllowed) ErrorCode() string              { return "name_reserved" }
+func (ErrNoticeNotExist) ErrorCode() string              { return "notice_not_exist" }
+func (ErrNotificationScheduleInvalid) ErrorCode() string { return "notification_schedule_invalid" }
+func (ErrOrgAnnouncementTooLong) ErrorCode() string      { return "org_announcement_too_long" }
+func (ErrOrgDomainAlreadyExist) ErrorCode() string       { return "org_domain_already_exist" }
+func (ErrOrgDomainNotExist) ErrorCode() string           { return "org_domain_not_exist" }
+func (ErrOrgMemberDomainNotVerified) ErrorCode() string  { return "org_member_domain_not_verified" }
+func (ErrOrgRulesetAlreadyExist) ErrorCode() string      { return "org_ruleset_already_exist" }
+func (ErrOrgRulesetNotExist) ErrorCode() string          { return "org_ruleset_not_exist" }
+func (ErrOrgTwoFactorNotEnabled) ErrorCode() string      { return "org_two_factor_not_enabled" }
+func (ErrPagesDomainAlreadyUsed) ErrorCode() string      { return "pages_domain_already_used" }
+func (ErrPagesSiteNotExist) ErrorCode() string           { return "pages_site_not_exist" }
+func (ErrParticipantNotExist) ErrorCode() string         { return "participant_not_exist" }
+func (ErrPasswordResetTokenNotExist) ErrorCode() string  { return "password_reset_token_not_exist" }
+func (ErrProfileFieldAlreadyExist) ErrorCode() string    { return "profile_field_already_exist" }
+func (ErrProfileFieldNotExist) ErrorCode() string        { return "profile_field_not_exist" }
+func (ErrPullRequestNotExist) ErrorCode() string         { return "pull_request_not_exist" }
+func (ErrPushRejected) ErrorCode() string                { return "push_rejected" }
+func (ErrQueuedEmailNotExist) ErrorCode() string         { return "queued_email_not_exist" }
+func (ErrReachLimitOfRepo) ErrorCode() string            { return "quota_exceeded" }
+func (ErrReactionInvalid) ErrorCode() string             { return "reaction_invalid" }
+func (ErrReleaseAlreadyExist) ErrorCode() string         { return "release_already_exist" }
+func (ErrReleaseNotExist) ErrorCode() string             { return "release_not_exist" }
+func (ErrRepoAlreadyExist) ErrorCode() string            { return "repo_already_exist" }
+func (ErrRepoFileAlreadyExist) ErrorCode() string        { return "repo_file_already_exist" }
+func (ErrRepoInitFileNotExist) ErrorCode() string        { return "repo_init_file_not_exist" }
+func (ErrRepoNamingConvention) ErrorCode() string        { return "repo_naming_convention" }
+func (ErrRepoNotEmpty) ErrorCode() string                { return "repo_not_empty" }
+func (ErrRepoNotExist) ErrorCode() string                { return "repo_not_exist" }
+func (ErrRepoTopicInvalid) ErrorCode() string            { return "repo_topic_invalid" }
+func (ErrReservedNameAlreadyExist) ErrorCode() string    { return "reserved_name_already_exist" }
+func (ErrRunnerAlreadyExist) ErrorCode() string          { return "runner_already_exist" }
+func (ErrRunnerNotExist) ErrorCode() string              { return "runner_not_exist" }
+func (ErrSecurityPolicyExceptionAlreadyExist) ErrorCode() string {
+	return "security_policy_exception_already_exist"
+}
+func (ErrSecurityPolicyExceptionNotExist) ErrorCode() string {
+	return "security_policy_exception_not_exist"
+}
+func (ErrSnippetInvalid) ErrorCode() string          { return "snippet_invalid" }
+func (ErrSnippetNotExist) ErrorCode() string         { return "snippet_not_exist" }
+func (ErrSnippetRevisionNotExist) ErrorCode() string { return "snippet_revision_not_exist" }
+func (ErrTeamAlreadyExist) ErrorCode() string        { return "team_already_exist" }
+func (ErrTeamDiscussionNotExist) ErrorCode() string  { return "team_discussion_not_exist" }
+func (ErrTeamNotExist) ErrorCode() string            { return "team_not_exist" }
+func (ErrTwoFactorNotFound) ErrorCode() string       { return "two_factor_not_found" }
+func (ErrTwoFactorRecoveryCodeNotFound) ErrorCode() string {
+	return "two_factor_recovery_code_not_found"
+}
+func (ErrUpdateTaskNotExist) ErrorCode() string { return "update_task_not_exist" }
+func (ErrUploadNotExist) ErrorCode() string     { return "upload_not_exist" }
+func (ErrUserAlreadyExist) ErrorCode() string   { return "user_already_exist" }
+func (ErrUserHasOrgs) ErrorCode() string        { return "user_has_orgs" }
+func (ErrUserNotExist) ErrorCode() string       { return "user_not_exist" }
+func (ErrUserOwnRepos) ErrorCode() string       { return "user_own_repos" }
+func (ErrWebhookNotExist) ErrorCode() string    { return "webhook_not_exist" }
+func (ErrWikiAlreadyExist) ErrorCode() string   { return "wiki_already_exist" }
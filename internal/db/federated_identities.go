@@ -0,0 +1,108 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/errutil"
+)
+
+// FederatedIdentitiesStore is the persistent interface for bindings of local
+// users to users of the central instance.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type FederatedIdentitiesStore interface {
+	// GetOrCreateUser returns the local user bound to the user with given ID on
+	// the central instance. When not bound yet, a new local user is created with
+	// given username, email and options, and bound to the central user. Existing
+	// local users are never bound implicitly. It returns
+	// ErrFederatedUserNotAllowed when the username is taken by a local user, or
+	// the bound user is a site admin or an organization.
+	GetOrCreateUser(ctx context.Context, centralUserID int64, username, email string, opts CreateUserOptions) (*User, error)
+}
+
+var FederatedIdentities FederatedIdentitiesStore
+
+// FederatedIdentity is the binding of a local user to a user of the central
+// instance, which authenticates the local user with access tokens issued by the
+// central instance.
+type FederatedIdentity struct {
+	ID            int64     `gorm:"primaryKey"`
+	UserID        int64     `gorm:"unique;not null"`
+	CentralUserID int64     `gorm:"unique;not null"`
+	CreatedAt     time.Time `gorm:"not null"`
+}
+
+var _ FederatedIdentitiesStore = (*federatedIdentities)(nil)
+
+type federatedIdentities struct {
+	*gorm.DB
+}
+
+// NewFederatedIdentitiesStore returns a persistent interface for bindings of
+// local users to users of the central instance with given database connection.
+func NewFederatedIdentitiesStore(db *gorm.DB) FederatedIdentitiesStore {
+	return &federatedIdentities{DB: db}
+}
+
+type ErrFederatedUserNotAllowed struct {
+	args errutil.Args
+}
+
+func IsErrFederatedUserNotAllowed(err error) bool {
+	_, ok := err.(ErrFederatedUserNotAllowed)
+	return ok
+}
+
+func (err ErrFederatedUserNotAllowed) Error() string {
+	return fmt.Sprintf("federated user is not allowed: %v", err.args)
+}
+
+func (db *federatedIdentities) GetOrCreateUser(ctx context.Context, centralUserID int64, username, email string, opts CreateUserOptions) (*User, error) {
+	if centralUserID <= 0 {
+		return nil, errors.Errorf("invalid central user ID %d", centralUserID)
+	}
+
+	var u *User
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		identity := new(FederatedIdentity)
+		err := tx.Where("central_user_id = ?", centralUserID).First(identity).Error
+		if err == nil {
+			u, err = NewUsersStore(tx).GetByID(ctx, identity.UserID)
+			return errors.Wrap(err, "get bound user")
+		} else if err != gorm.ErrRecordNotFound {
+			return errors.Wrap(err, "get identity")
+		}
+
+		u, err = NewUsersStore(tx).Create(ctx, username, email, opts)
+		if err != nil {
+			if IsErrUserAlreadyExist(err) {
+				return ErrFederatedUserNotAllowed{args: errutil.Args{"centralUserID": centralUserID, "reason": "username is taken by a local user"}}
+			}
+			return errors.Wrap(err, "create user")
+		}
+		return tx.Create(&FederatedIdentity{
+			UserID:        u.ID,
+			CentralUserID: centralUserID,
+			CreatedAt:     tx.NowFunc(),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Site admins must sign in locally, a compromised central instance must not
+	// be able to act as one.
+	if u.IsAdmin || u.IsOrganization() {
+		return nil, ErrFederatedUserNotAllowed{args: errutil.Args{"centralUserID": centralUserID, "reason": "bound user is a site admin or an organization"}}
+	}
+	return u, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+func TestFederatedIdentities(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(User), new(EmailAddress), new(FederatedIdentity)}
+	db := &federatedIdentities{
+		DB: dbtest.NewDB(t, "federatedIdentities", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *federatedIdentities)
+	}{
+		{"GetOrCreateUser", federatedIdentitiesGetOrCreateUser},
+		{"GetOrCreateUserExistingLocalUser", federatedIdentitiesGetOrCreateUserExistingLocalUser},
+		{"GetOrCreateUserExistingAdmin", federatedIdentitiesGetOrCreateUserExistingAdmin},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func federatedIdentitiesGetOrCreateUser(t *testing.T, db *federatedIdentities) {
+	ctx := context.Background()
+
+	_, err := db.GetOrCreateUser(ctx, 0, "alice", "alice@example.com", CreateUserOptions{})
+	assert.Error(t, err)
+
+	alice, err := db.GetOrCreateUser(ctx, 42, "alice", "alice@example.com", CreateUserOptions{FullName: "Alice"})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", alice.Name)
+	assert.Equal(t, "Alice", alice.FullName)
+
+	// The bound user is returned even after being renamed on either side.
+	err = db.Model(new(User)).Where("id = ?", alice.ID).Updates(map[string]interface{}{"name": "alice2", "lower_name": "alice2"}).Error
+	require.NoError(t, err)
+	got, err := db.GetOrCreateUser(ctx, 42, "alice", "alice@example.com", CreateUserOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, alice.ID, got.ID)
+	assert.Equal(t, "alice2", got.Name)
+
+	// The bound user loses access once promoted to a site admin.
+	err = db.Model(new(User)).Where("id = ?", alice.ID).Update("is_admin", true).Error
+	require.NoError(t, err)
+	_, err = db.GetOrCreateUser(ctx, 42, "alice", "alice@example.com", CreateUserOptions{})
+	assert.True(t, IsErrFederatedUserNotAllowed(err), "%v", err)
+}
+
+func federatedIdentitiesGetOrCreateUserExistingLocalUser(t *testing.T, db *federatedIdentities) {
+	ctx := context.Background()
+
+	bob, err := NewUsersStore(db.DB).Create(ctx, "bob", "bob@example.com", CreateUserOptions{})
+	require.NoError(t, err)
+
+	_, err = db.GetOrCreateUser(ctx, 7, "bob", "bob@central.example.com", CreateUserOptions{})
+	assert.True(t, IsErrFederatedUserNotAllowed(err), "%v", err)
+
+	// The local user is not bound by the attempt.
+	var count int64
+	err = db.Model(new(FederatedIdentity)).Where("user_id = ?", bob.ID).Count(&count).Error
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func federatedIdentitiesGetOrCreateUserExistingAdmin(t *testing.T, db *federatedIdentities) {
+	ctx := context.Background()
+
+	_, err := NewUsersStore(db.DB).Create(ctx, "root", "root@example.com", CreateUserOptions{Admin: true})
+	require.NoError(t, err)
+
+	_, err = db.GetOrCreateUser(ctx, 1, "root", "root@central.example.com", CreateUserOptions{})
+	assert.True(t, IsErrFederatedUserNotAllowed(err), "%v", err)
+
+	var count int64
+	err = db.Model(new(FederatedIdentity)).Count(&count).Error
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FetchStatsStore is the persistent interface for statistics of Git fetches.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type FetchStatsStore interface {
+	// Increment increments the number of fetches with given protocol, object
+	// filter kind and whether the fetch is shallow.
+	Increment(ctx context.Context, protocol, filter string, isShallow bool) error
+	// List returns all fetch statistics, ordered by protocol, filter and whether
+	// the fetch is shallow.
+	List(ctx context.Context) ([]*FetchStat, error)
+}
+
+var FetchStats FetchStatsStore
+
+// FetchStat is the number of Git fetches (including clones) that served a
+// packfile with the same protocol, object filter kind and shallowness.
+type FetchStat struct {
+	ID int64 `gorm:"primaryKey"`
+	// Protocol is the transport protocol of fetches, i.e. "http" or "ssh".
+	Protocol string `gorm:"type:VARCHAR(8);uniqueIndex:fetch_stat_protocol_filter_shallow_unique;not null"`
+	// Filter is the kind of the object filter of fetches (see
+	// gitutil.FilterKind), it is empty for fetches without filter.
+	Filter    string    `gorm:"type:VARCHAR(32);uniqueIndex:fetch_stat_protocol_filter_shallow_unique;not null"`
+	IsShallow bool      `gorm:"uniqueIndex:fetch_stat_protocol_filter_shallow_unique;not null;default:FALSE"`
+	Count     int64     `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+var _ FetchStatsStore = (*fetchStats)(nil)
+
+type fetchStats struct {
+	*gorm.DB
+}
+
+// NewFetchStatsStore returns a persistent interface for statistics of Git
+// fetches with given database connection.
+func NewFetchStatsStore(db *gorm.DB) FetchStatsStore {
+	return &fetchStats{DB: db}
+}
+
+func (db *fetchStats) increment(tx *gorm.DB, protocol, filter string, isShallow bool) (int64, error) {
+	result := tx.Model(new(FetchStat)).
+		Where("protocol = ? AND filter = ? AND is_shallow = ?", protocol, filter, isShallow).
+		Updates(map[string]interface{}{
+			"count":      gorm.Expr("count + 1"),
+			"updated_at": tx.NowFunc(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (db *fetchStats) Increment(ctx context.Context, protocol, filter string, isShallow bool) error {
+	tx := db.WithContext(ctx)
+	updated, err := db.increment(tx, protocol, filter, isShallow)
+	if err != nil || updated > 0 {
+		return err
+	}
+
+	err = tx.Create(&FetchStat{
+		Protocol:  protocol,
+		Filter:    filter,
+		IsShallow: isShallow,
+		Count:     1,
+	}).Error
+	if err == nil {
+		return nil
+	}
+
+	// The row may have been created by a concurrent fetch in the meantime.
+	updated, uerr := db.increment(tx, protocol, filter, isShallow)
+	if uerr != nil || updated == 0 {
+		return err
+	}
+	return nil
+}
+
+func (db *fetchStats) List(ctx context.Context) ([]*FetchStat, error) {
+	var stats []*FetchStat
+	return stats, db.WithContext(ctx).
+		Order("protocol ASC").
+		Order("filter ASC").
+		Order("is_shallow ASC").
+		Find(&stats).
+		Error
+}
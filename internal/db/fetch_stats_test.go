@@ -0,0 +1,87 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+func TestFetchStats(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(FetchStat)}
+	db := &fetchStats{
+		DB: dbtest.NewDB(t, "fetchStats", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *fetchStats)
+	}{
+		{"Increment", fetchStatsIncrement},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func fetchStatsIncrement(t *testing.T, db *fetchStats) {
+	ctx := context.Background()
+
+	for _, args := range []struct {
+		protocol  string
+		filter    string
+		isShallow bool
+	}{
+		{"ssh", "", false},
+		{"http", "blob:none", false},
+		{"http", "", true},
+		{"http", "blob:none", false},
+		{"http", "", false},
+		{"http", "blob:none", false},
+	} {
+		err := db.Increment(ctx, args.protocol, args.filter, args.isShallow)
+		require.NoError(t, err)
+	}
+
+	stats, err := db.List(ctx)
+	require.NoError(t, err)
+
+	type stat struct {
+		Protocol  string
+		Filter    string
+		IsShallow bool
+		Count     int64
+	}
+	var got []stat
+	for _, s := range stats {
+		assert.False(t, s.UpdatedAt.IsZero())
+		got = append(got, stat{s.Protocol, s.Filter, s.IsShallow, s.Count})
+	}
+	want := []stat{
+		{"http", "", false, 1},
+		{"http", "", true, 1},
+		{"http", "blob:none", false, 3},
+		{"ssh", "", false, 1},
+	}
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,366 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+)
+
+// FirehoseEvent is a normalized activity event published to the firehose. See
+// docs/admin/firehose.md for the schema.
+type FirehoseEvent struct {
+	// ID is unique and increases monotonically, which consumers use to drop
+	// events that are delivered more than once.
+	ID         int64               `json:"id"`
+	Type       string              `json:"type"`
+	CreatedAt  time.Time           `json:"created_at"`
+	Actor      *FirehoseActor      `json:"actor"`
+	Repository *FirehoseRepository `json:"repository"`
+	Ref        string              `json:"ref,omitempty"`
+	// Previous is the previous full name of a renamed or transferred repository.
+	Previous string            `json:"previous,omitempty"`
+	Issue    *FirehoseIssue    `json:"issue,omitempty"`
+	Comment  string            `json:"comment,omitempty"`
+	Commits  []*FirehoseCommit `json:"commits,omitempty"`
+}
+
+// FirehoseActor is the user who triggered an event.
+type FirehoseActor struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+}
+
+// FirehoseRepository is the repository of an event.
+type FirehoseRepository struct {
+	ID       int64  `json:"id"`
+	Owner    string `json:"owner"`
+	Name     string `json:"name"`
+	FullName string `json:"full_name"`
+	Private  bool   `json:"private"`
+}
+
+// FirehoseIssue is the issue or pull request of an event.
+type FirehoseIssue struct {
+	Number int64  `json:"number"`
+	Title  string `json:"title,omitempty"`
+	IsPull bool   `json:"is_pull"`
+}
+
+// FirehoseCommit is a pushed commit of an event.
+type FirehoseCommit struct {
+	SHA            string    `json:"sha"`
+	Message        string    `json:"message"`
+	AuthorName     string    `json:"author_name"`
+	AuthorEmail    string    `json:"author_email"`
+	CommitterName  string    `json:"committer_name"`
+	CommitterEmail string    `json:"committer_email"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+var firehoseEventTypes = map[ActionType]string{
+	ActionCreateRepo:        "repository.create",
+	ActionRenameRepo:        "repository.rename",
+	ActionStarRepo:          "repository.star",
+	ActionWatchRepo:         "repository.watch",
+	ActionCommitRepo:        "push",
+	ActionCreateIssue:       "issue.open",
+	ActionCreatePullRequest: "pull_request.open",
+	ActionTransferRepo:      "repository.transfer",
+	ActionPushTag:           "tag.create",
+	ActionCommentIssue:      "issue.comment",
+	ActionMergePullRequest:  "pull_request.merge",
+	ActionCloseIssue:        "issue.close",
+	ActionReopenIssue:       "issue.reopen",
+	ActionClosePullRequest:  "pull_request.close",
+	ActionReopenPullRequest: "pull_request.reopen",
+	ActionCreateBranch:      "branch.create",
+	ActionDeleteBranch:      "branch.delete",
+	ActionDeleteTag:         "tag.delete",
+	ActionForkRepo:          "repository.fork",
+	ActionMirrorSyncPush:    "mirror.push",
+	ActionMirrorSyncCreate:  "mirror.ref_create",
+	ActionMirrorSyncDelete:  "mirror.ref_delete",
+}
+
+// newFirehoseEvent normalizes the action to an event. Titles of issues and
+// whether comments are made in pull requests are not stored in actions, which
+// are filled by loadFirehoseIssue.
+func newFirehoseEvent(a *Action) *FirehoseEvent {
+	e := &FirehoseEvent{
+		ID:        a.ID,
+		Type:      firehoseEventTypes[a.OpType],
+		CreatedAt: time.Unix(a.CreatedUnix, 0).UTC(),
+		Actor: &FirehoseActor{
+			ID:    a.ActUserID,
+			Login: a.ActUserName,
+		},
+		Repository: &FirehoseRepository{
+			ID:       a.RepoID,
+			Owner:    a.RepoUserName,
+			Name:     a.RepoName,
+			FullName: path.Join(a.RepoUserName, a.RepoName),
+			Private:  a.IsPrivate,
+		},
+		Ref: a.RefName,
+	}
+	if e.Type == "" {
+		e.Type = "unknown"
+	}
+
+	switch a.OpType {
+	case ActionRenameRepo:
+		e.Previous = path.Join(a.RepoUserName, a.Content)
+
+	case ActionTransferRepo:
+		e.Previous = a.Content
+
+	case ActionCommitRepo, ActionMirrorSyncPush:
+		if a.Content == "" {
+			break
+		}
+		commits := new(PushCommits)
+		if err := json.Unmarshal([]byte(a.Content), commits); err != nil {
+			log.Error("Failed to unmarshal push commits of action [id: %d]: %v", a.ID, err)
+			break
+		}
+		e.Commits = make([]*FirehoseCommit, 0, len(commits.Commits))
+		for _, c := range commits.Commits {
+			e.Commits = append(e.Commits, &FirehoseCommit{
+				SHA:            c.Sha1,
+				Message:        c.Message,
+				AuthorName:     c.AuthorName,
+				AuthorEmail:    c.AuthorEmail,
+				CommitterName:  c.CommitterName,
+				CommitterEmail: c.CommitterEmail,
+				Timestamp:      c.Timestamp,
+			})
+		}
+
+	case ActionCreateIssue, ActionCommentIssue, ActionCloseIssue, ActionReopenIssue,
+		ActionCreatePullRequest, ActionMergePullRequest, ActionClosePullRequest, ActionReopenPullRequest:
+		infos := a.GetIssueInfos()
+		index, _ := strconv.ParseInt(infos[0], 10, 64)
+		e.Issue = &FirehoseIssue{
+			Number: index,
+			IsPull: a.OpType == ActionCreatePullRequest || a.OpType == ActionMergePullRequest ||
+				a.OpType == ActionClosePullRequest || a.OpType == ActionReopenPullRequest,
+		}
+		if len(infos) > 1 {
+			switch a.OpType {
+			case ActionCreateIssue, ActionCreatePullRequest, ActionMergePullRequest:
+				e.Issue.Title = infos[1]
+			default:
+				// The first line of the comment
+				e.Comment = infos[1]
+			}
+		}
+	}
+	return e
+}
+
+// loadFirehoseIssue fills the title of the issue of the event, and tells
+// comments made in pull requests apart.
+func loadFirehoseIssue(e *FirehoseEvent) {
+	if e.Issue == nil {
+		return
+	}
+
+	issue, err := GetIssueByIndex(e.Repository.ID, e.Issue.Number)
+	if err != nil {
+		if !IsErrIssueNotExist(err) {
+			log.Error("Failed to get issue of firehose event [id: %d]: %v", e.ID, err)
+		}
+		return
+	}
+	e.Issue.Title = issue.Title
+	e.Issue.IsPull = issue.IsPull
+	if e.Type == "issue.comment" && issue.IsPull {
+		e.Type = "pull_request.comment"
+	}
+}
+
+// encodeFirehoseEvents encodes events in the format and returns the request
+// body with its content type.
+func encodeFirehoseEvents(events []*FirehoseEvent, format string) ([]byte, string, error) {
+	switch format {
+	case "ndjson":
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		for _, e := range events {
+			if err := enc.Encode(e); err != nil {
+				return nil, "", err
+			}
+		}
+		return buf.Bytes(), "application/x-ndjson", nil
+
+	case "kafka_rest":
+		// Records are keyed by repository so that events of a repository go to the
+		// same partition and stay in order.
+		type record struct {
+			Key   string         `json:"key"`
+			Value *FirehoseEvent `json:"value"`
+		}
+		records := make([]*record, 0, len(events))
+		for _, e := range events {
+			records = append(records, &record{
+				Key:   strconv.FormatInt(e.Repository.ID, 10),
+				Value: e,
+			})
+		}
+		body, err := json.Marshal(map[string]interface{}{"records": records})
+		if err != nil {
+			return nil, "", err
+		}
+		return body, "application/vnd.kafka.json.v2+json", nil
+	}
+	return nil, "", errors.Errorf("unsupported format %q", format)
+}
+
+// postFirehoseEvents posts the request body to the firehose endpoint. The body
+// is signed with the secret if it is set.
+func postFirehoseEvents(ctx context.Context, client *http.Client, url, secret string, body []byte, contentType string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "new request")
+	}
+	req.Header.Set("Content-Type", contentType)
+	if secret != "" {
+		sig := hmac.New(sha256.New, []byte(secret))
+		_, _ = sig.Write(body)
+		req.Header.Set("X-Gogs-Signature", hex.EncodeToString(sig.Sum(nil)))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "do request")
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("unexpected status %d: %s", resp.StatusCode, respBody)
+	}
+	return nil
+}
+
+// firehoseCursorPath returns the path of the file that stores the ID of the
+// last delivered event.
+func firehoseCursorPath() string {
+	return filepath.Join(conf.Server.AppDataPath, "firehose", "cursor")
+}
+
+// readFirehoseCursor returns the ID of the last delivered event, or 0 if
+// nothing has been delivered.
+func readFirehoseCursor(name string) (int64, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return strconv.ParseInt(string(bytes.TrimSpace(data)), 10, 64)
+}
+
+// writeFirehoseCursor saves the ID of the last delivered event. The file is
+// replaced atomically so that a crash never leaves a partial cursor behind.
+func writeFirehoseCursor(name string, id int64) error {
+	if err := os.MkdirAll(filepath.Dir(name), os.ModePerm); err != nil {
+		return err
+	}
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatInt(id, 10)), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
+
+// deliverFirehose delivers the next batch of events after the cursor and
+// returns the number of events delivered. Events are delivered at least once,
+// the cursor only moves forward after the endpoint accepts the batch.
+func deliverFirehose(ctx context.Context, client *http.Client) (int, error) {
+	cursorPath := firehoseCursorPath()
+	cursor, err := readFirehoseCursor(cursorPath)
+	if err != nil {
+		return 0, errors.Wrap(err, "read cursor")
+	}
+
+	actions, err := Actions.ListAfter(ctx, cursor, conf.Firehose.BatchSize)
+	if err != nil {
+		return 0, errors.Wrap(err, "list actions")
+	} else if len(actions) == 0 {
+		return 0, nil
+	}
+
+	events := make([]*FirehoseEvent, 0, len(actions))
+	for _, a := range actions {
+		e := newFirehoseEvent(a)
+		loadFirehoseIssue(e)
+		events = append(events, e)
+	}
+
+	body, contentType, err := encodeFirehoseEvents(events, conf.Firehose.Format)
+	if err != nil {
+		return 0, errors.Wrap(err, "encode events")
+	}
+	err = postFirehoseEvents(ctx, client, conf.Firehose.URL, conf.Firehose.Secret, body, contentType)
+	if err != nil {
+		return 0, errors.Wrap(err, "post events")
+	}
+
+	err = writeFirehoseCursor(cursorPath, actions[len(actions)-1].ID)
+	if err != nil {
+		return 0, errors.Wrap(err, "write cursor")
+	}
+	return len(actions), nil
+}
+
+// runFirehose keeps delivering events, and checks for new events in every
+// interval once it has caught up or the delivery has failed.
+func runFirehose() {
+	client := &http.Client{
+		Timeout: conf.Firehose.Timeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: conf.Firehose.SkipTLSVerify},
+		},
+	}
+	for {
+		n, err := deliverFirehose(context.Background(), client)
+		if err != nil {
+			log.Error("Failed to deliver firehose events: %v", err)
+		}
+		if err != nil || n < conf.Firehose.BatchSize {
+			time.Sleep(conf.Firehose.Interval)
+		}
+	}
+}
+
+// InitFirehose starts publishing activity events to the firehose endpoint if
+// enabled.
+func InitFirehose() {
+	if !conf.Firehose.Enabled {
+		return
+	}
+	go runFirehose()
+}
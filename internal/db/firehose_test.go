@@ -0,0 +1,191 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFirehoseEvent(t *testing.T) {
+	base := Action{
+		ID:           10,
+		ActUserID:    1,
+		ActUserName:  "alice",
+		RepoID:       2,
+		RepoUserName: "alice",
+		RepoName:     "example",
+		IsPrivate:    true,
+		CreatedUnix:  1588568886,
+	}
+	newAction := func(opType ActionType, refName, content string) *Action {
+		a := base
+		a.OpType = opType
+		a.RefName = refName
+		a.Content = content
+		return &a
+	}
+
+	t.Run("common fields", func(t *testing.T) {
+		got := newFirehoseEvent(newAction(ActionCreateRepo, "", ""))
+		want := &FirehoseEvent{
+			ID:        10,
+			Type:      "repository.create",
+			CreatedAt: time.Unix(1588568886, 0).UTC(),
+			Actor: &FirehoseActor{
+				ID:    1,
+				Login: "alice",
+			},
+			Repository: &FirehoseRepository{
+				ID:       2,
+				Owner:    "alice",
+				Name:     "example",
+				FullName: "alice/example",
+				Private:  true,
+			},
+		}
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("rename and transfer", func(t *testing.T) {
+		got := newFirehoseEvent(newAction(ActionRenameRepo, "", "old"))
+		assert.Equal(t, "repository.rename", got.Type)
+		assert.Equal(t, "alice/old", got.Previous)
+
+		got = newFirehoseEvent(newAction(ActionTransferRepo, "", "bob/example"))
+		assert.Equal(t, "repository.transfer", got.Type)
+		assert.Equal(t, "bob/example", got.Previous)
+	})
+
+	t.Run("push", func(t *testing.T) {
+		content := `{"Len":1,"Commits":[{"Sha1":"085bb3bcb608e1e8451d4b2432f8ecbe6306e7e7","Message":"Initial commit","AuthorEmail":"alice@example.com","AuthorName":"alice","CommitterEmail":"alice@example.com","CommitterName":"alice","Timestamp":"2020-05-04T05:08:06Z"}],"CompareURL":""}`
+		got := newFirehoseEvent(newAction(ActionCommitRepo, "main", content))
+		assert.Equal(t, "push", got.Type)
+		assert.Equal(t, "main", got.Ref)
+		want := []*FirehoseCommit{
+			{
+				SHA:            "085bb3bcb608e1e8451d4b2432f8ecbe6306e7e7",
+				Message:        "Initial commit",
+				AuthorName:     "alice",
+				AuthorEmail:    "alice@example.com",
+				CommitterName:  "alice",
+				CommitterEmail: "alice@example.com",
+				Timestamp:      time.Unix(1588568886, 0).UTC(),
+			},
+		}
+		assert.Equal(t, want, got.Commits)
+	})
+
+	t.Run("issues and pull requests", func(t *testing.T) {
+		got := newFirehoseEvent(newAction(ActionCreatePullRequest, "", "3|Add README"))
+		assert.Equal(t, "pull_request.open", got.Type)
+		assert.Equal(t, &FirehoseIssue{Number: 3, Title: "Add README", IsPull: true}, got.Issue)
+		assert.Empty(t, got.Comment)
+
+		got = newFirehoseEvent(newAction(ActionCommentIssue, "", "4|Looks good to me"))
+		assert.Equal(t, "issue.comment", got.Type)
+		assert.Equal(t, &FirehoseIssue{Number: 4}, got.Issue)
+		assert.Equal(t, "Looks good to me", got.Comment)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		got := newFirehoseEvent(newAction(ActionType(100), "", ""))
+		assert.Equal(t, "unknown", got.Type)
+	})
+}
+
+func TestEncodeFirehoseEvents(t *testing.T) {
+	events := []*FirehoseEvent{
+		{ID: 1, Type: "repository.star", Repository: &FirehoseRepository{ID: 2}},
+		{ID: 3, Type: "repository.watch", Repository: &FirehoseRepository{ID: 4}},
+	}
+
+	t.Run("ndjson", func(t *testing.T) {
+		body, contentType, err := encodeFirehoseEvents(events, "ndjson")
+		require.NoError(t, err)
+		assert.Equal(t, "application/x-ndjson", contentType)
+		want := `{"id":1,"type":"repository.star","created_at":"0001-01-01T00:00:00Z","actor":null,"repository":{"id":2,"owner":"","name":"","full_name":"","private":false}}
+{"id":3,"type":"repository.watch","created_at":"0001-01-01T00:00:00Z","actor":null,"repository":{"id":4,"owner":"","name":"","full_name":"","private":false}}
+`
+		assert.Equal(t, want, string(body))
+	})
+
+	t.Run("kafka_rest", func(t *testing.T) {
+		body, contentType, err := encodeFirehoseEvents(events[:1], "kafka_rest")
+		require.NoError(t, err)
+		assert.Equal(t, "application/vnd.kafka.json.v2+json", contentType)
+		want := `{"records":[{"key":"2","value":{"id":1,"type":"repository.star","created_at":"0001-01-01T00:00:00Z","actor":null,"repository":{"id":2,"owner":"","name":"","full_name":"","private":false}}}]}`
+		assert.Equal(t, want, string(body))
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, _, err := encodeFirehoseEvents(events, "avro")
+		assert.Error(t, err)
+	})
+}
+
+func TestPostFirehoseEvents(t *testing.T) {
+	var gotBody []byte
+	var gotContentType, gotSignature string
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotContentType = r.Header.Get("Content-Type")
+		gotSignature = r.Header.Get("X-Gogs-Signature")
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	body := []byte(`{"id":1}` + "\n")
+
+	t.Run("signed", func(t *testing.T) {
+		err := postFirehoseEvents(ctx, srv.Client(), srv.URL, "secret", body, "application/x-ndjson")
+		require.NoError(t, err)
+		assert.Equal(t, body, gotBody)
+		assert.Equal(t, "application/x-ndjson", gotContentType)
+
+		sig := hmac.New(sha256.New, []byte("secret"))
+		_, _ = sig.Write(body)
+		assert.Equal(t, hex.EncodeToString(sig.Sum(nil)), gotSignature)
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		err := postFirehoseEvents(ctx, srv.Client(), srv.URL, "", body, "application/x-ndjson")
+		require.NoError(t, err)
+		assert.Empty(t, gotSignature)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		status = http.StatusServiceUnavailable
+		err := postFirehoseEvents(ctx, srv.Client(), srv.URL, "", body, "application/x-ndjson")
+		assert.Error(t, err)
+	})
+}
+
+func TestFirehoseCursor(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "firehose", "cursor")
+
+	got, err := readFirehoseCursor(name)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), got)
+
+	require.NoError(t, writeFirehoseCursor(name, 42))
+	require.NoError(t, writeFirehoseCursor(name, 100))
+	got, err = readFirehoseCursor(name)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), got)
+}
@@ -0,0 +1,259 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/gitutil"
+)
+
+// GitAccessLogsStore is the persistent interface for records of Git access to
+// repositories.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type GitAccessLogsStore interface {
+	// Create creates a new record of Git access.
+	Create(ctx context.Context, l *GitAccessLog) error
+	// DeleteOlderThan deletes records that are created before the given time.
+	DeleteOlderThan(ctx context.Context, t time.Time) error
+	// ListByRepo returns records of the repository that are created since the
+	// given time, ordered by creation time in descending order.
+	ListByRepo(ctx context.Context, repoID int64, since time.Time) ([]*GitAccessLog, error)
+}
+
+var GitAccessLogs GitAccessLogsStore
+
+// GitAccessOperation is the operation of a Git access.
+type GitAccessOperation string
+
+const (
+	GitAccessClone GitAccessOperation = "clone"
+	GitAccessFetch GitAccessOperation = "fetch"
+	GitAccessPush  GitAccessOperation = "push"
+)
+
+// GitAccessLog is a record of a clone, fetch or push of a repository.
+type GitAccessLog struct {
+	ID     int64 `gorm:"primaryKey"`
+	RepoID int64 `gorm:"index;not null"`
+	// UserID is the ID of the authenticated user, it is 0 for anonymous access.
+	UserID    int64
+	UserName  string
+	Protocol  string             `gorm:"type:VARCHAR(8);not null"`
+	Operation GitAccessOperation `gorm:"type:VARCHAR(8);not null"`
+	// Bytes is the number of bytes sent to the client for clones and fetches, or
+	// received from the client for pushes.
+	Bytes    int64         `gorm:"not null"`
+	Duration time.Duration `gorm:"not null"`
+	// Summary is the summary of wanted objects for clones and fetches, or of
+	// updated references for pushes.
+	Summary   string    `gorm:"type:TEXT"`
+	CreatedAt time.Time `gorm:"index;not null"`
+}
+
+var _ GitAccessLogsStore = (*gitAccessLogs)(nil)
+
+type gitAccessLogs struct {
+	*gorm.DB
+}
+
+// NewGitAccessLogsStore returns a persistent interface for records of Git
+// access to repositories with given database connection.
+func NewGitAccessLogsStore(db *gorm.DB) GitAccessLogsStore {
+	return &gitAccessLogs{DB: db}
+}
+
+func (db *gitAccessLogs) Create(ctx context.Context, l *GitAccessLog) error {
+	return db.WithContext(ctx).Create(l).Error
+}
+
+func (db *gitAccessLogs) DeleteOlderThan(ctx context.Context, t time.Time) error {
+	return db.WithContext(ctx).Where("created_at < ?", t).Delete(new(GitAccessLog)).Error
+}
+
+func (db *gitAccessLogs) ListByRepo(ctx context.Context, repoID int64, since time.Time) ([]*GitAccessLog, error) {
+	var logs []*GitAccessLog
+	return logs, db.WithContext(ctx).
+		Where("repo_id = ? AND created_at >= ?", repoID, since).
+		Order("created_at DESC").
+		Order("id DESC").
+		Find(&logs).
+		Error
+}
+
+// GitAccessOptions contains options of a Git access to a repository.
+type GitAccessOptions struct {
+	// Protocol is the transport protocol, i.e. "http" or "ssh".
+	Protocol string
+	// Doer is the authenticated user, it is nil for anonymous access.
+	Doer       *User
+	RemoteAddr string
+	UserAgent  string
+	RepoID     int64
+	RepoName   string
+	StartedAt  time.Time
+}
+
+// RecordUploadPack records the fetch inspected by the inspector if a packfile
+// has been sent. Failures are logged and not returned since the fetch itself
+// has already succeeded.
+func RecordUploadPack(opts GitAccessOptions, inspector *gitutil.UploadPackInspector) {
+	if !inspector.IsPackSent() {
+		return
+	}
+
+	err := FetchStats.Increment(context.Background(), opts.Protocol, inspector.Filter(), inspector.IsShallow())
+	if err != nil {
+		log.Error("Failed to record fetch [protocol: %s]: %v", opts.Protocol, err)
+	}
+
+	op := GitAccessFetch
+	if inspector.IsClone() {
+		op = GitAccessClone
+		RecordRepoTraffic(opts.RepoID, RepoTrafficClone, opts.Doer, opts.RemoteAddr, opts.UserAgent)
+	}
+	summary := fmt.Sprintf("want %d, have %d", inspector.Wants(), inspector.Haves())
+	if inspector.Filter() != "" {
+		summary += ", filter " + inspector.Filter()
+	}
+	if inspector.IsShallow() {
+		summary += ", shallow"
+	}
+	recordGitAccess(opts, op, inspector.BytesSent(), summary)
+}
+
+// RecordReceivePack records the push inspected by the inspector if any
+// reference is updated. Failures are logged and not returned since the push
+// itself has already succeeded.
+func RecordReceivePack(opts GitAccessOptions, inspector *gitutil.ReceivePackInspector) {
+	refs := inspector.Refs()
+	if len(refs) == 0 {
+		return
+	}
+	recordGitAccess(opts, GitAccessPush, inspector.BytesReceived(), summarizeRefs(refs))
+}
+
+// maxSummaryRefs is the maximum number of references to be listed in the
+// summary of a push.
+const maxSummaryRefs = 5
+
+func summarizeRefs(refs []string) string {
+	if len(refs) <= maxSummaryRefs {
+		return strings.Join(refs, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(refs[:maxSummaryRefs], ", "), len(refs)-maxSummaryRefs)
+}
+
+func recordGitAccess(opts GitAccessOptions, op GitAccessOperation, bytes int64, summary string) {
+	if !conf.Git.AccessLog.Enabled && !conf.Git.AccessLog.SaveToDatabase {
+		return
+	}
+
+	l := &GitAccessLog{
+		RepoID:    opts.RepoID,
+		Protocol:  opts.Protocol,
+		Operation: op,
+		Bytes:     bytes,
+		Duration:  time.Since(opts.StartedAt),
+		Summary:   summary,
+	}
+	userName := "<anonymous>"
+	if opts.Doer != nil {
+		l.UserID = opts.Doer.ID
+		l.UserName = opts.Doer.Name
+		userName = opts.Doer.Name
+	}
+
+	if conf.Git.AccessLog.Enabled {
+		log.Info("Git access [user: %s, repo: %s, protocol: %s, operation: %s, bytes: %d, duration: %s, summary: %s]",
+			userName, opts.RepoName, l.Protocol, l.Operation, l.Bytes, l.Duration, l.Summary)
+	}
+	if conf.Git.AccessLog.SaveToDatabase {
+		err := GitAccessLogs.Create(context.Background(), l)
+		if err != nil {
+			log.Error("Failed to save Git access log [repo_id: %d]: %v", opts.RepoID, err)
+		}
+	}
+}
+
+// DeleteOldGitAccessLogs deletes records of Git access that are older than the
+// configured duration.
+func DeleteOldGitAccessLogs() {
+	if taskStatusTable.IsRunning(_CLEAN_OLD_GIT_ACCESS_LOGS) {
+		return
+	}
+	taskStatusTable.Start(_CLEAN_OLD_GIT_ACCESS_LOGS)
+	defer taskStatusTable.Stop(_CLEAN_OLD_GIT_ACCESS_LOGS)
+
+	log.Trace("Doing: DeleteOldGitAccessLogs")
+
+	err := GitAccessLogs.DeleteOlderThan(context.Background(), time.Now().Add(-conf.Cron.GitAccessLogCleanup.OlderThan))
+	if err != nil {
+		log.Error("Failed to delete old Git access logs: %v", err)
+	}
+}
+
+// GitAccessDay is the Git access traffic of a repository in a day.
+type GitAccessDay struct {
+	Date    time.Time
+	Clones  int
+	Fetches int
+	Pushes  int
+	// UniqueCloners is the number of distinct users that cloned the repository,
+	// anonymous users are counted as one.
+	UniqueCloners int
+	BytesSent     int64
+	BytesReceived int64
+}
+
+// BuildGitAccessTraffic returns the daily traffic of given records for the given
+// number of days until the day of now, in the location of now. Days without
+// traffic are included.
+func BuildGitAccessTraffic(logs []*GitAccessLog, now time.Time, days int) []*GitAccessDay {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	traffic := make([]*GitAccessDay, days)
+	for i := range traffic {
+		traffic[i] = &GitAccessDay{Date: today.AddDate(0, 0, i-days+1)}
+	}
+
+	cloners := make([]map[int64]bool, days)
+	for _, l := range logs {
+		t := l.CreatedAt.In(now.Location())
+		date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, now.Location())
+		i := days - 1 - int(today.Sub(date).Hours()/24+0.5)
+		if i < 0 || i >= days {
+			continue
+		}
+
+		day := traffic[i]
+		switch l.Operation {
+		case GitAccessClone:
+			day.Clones++
+			if cloners[i] == nil {
+				cloners[i] = make(map[int64]bool)
+			}
+			if !cloners[i][l.UserID] {
+				cloners[i][l.UserID] = true
+				day.UniqueCloners++
+			}
+			day.BytesSent += l.Bytes
+		case GitAccessFetch:
+			day.Fetches++
+			day.BytesSent += l.Bytes
+		case GitAccessPush:
+			day.Pushes++
+			day.BytesReceived += l.Bytes
+		}
+	}
+	return traffic
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+func TestGitAccessLogs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(GitAccessLog)}
+	db := &gitAccessLogs{
+		DB: dbtest.NewDB(t, "gitAccessLogs", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *gitAccessLogs)
+	}{
+		{"ListByRepo", gitAccessLogsListByRepo},
+		{"DeleteOlderThan", gitAccessLogsDeleteOlderThan},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func gitAccessLogsListByRepo(t *testing.T, db *gitAccessLogs) {
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	for _, l := range []*GitAccessLog{
+		{RepoID: 1, Protocol: "http", Operation: GitAccessClone, CreatedAt: now.Add(-48 * time.Hour)},
+		{RepoID: 1, UserID: 1, UserName: "alice", Protocol: "ssh", Operation: GitAccessPush, Bytes: 1024, Duration: time.Second, Summary: "refs/heads/main", CreatedAt: now.Add(-time.Hour)},
+		{RepoID: 1, Protocol: "http", Operation: GitAccessFetch, CreatedAt: now},
+		{RepoID: 2, Protocol: "http", Operation: GitAccessClone, CreatedAt: now},
+	} {
+		err := db.Create(ctx, l)
+		require.NoError(t, err)
+	}
+
+	logs, err := db.ListByRepo(ctx, 1, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	assert.Equal(t, GitAccessFetch, logs[0].Operation)
+	assert.Equal(t, GitAccessPush, logs[1].Operation)
+	assert.Equal(t, "alice", logs[1].UserName)
+	assert.Equal(t, int64(1024), logs[1].Bytes)
+	assert.Equal(t, time.Second, logs[1].Duration)
+	assert.Equal(t, "refs/heads/main", logs[1].Summary)
+}
+
+func gitAccessLogsDeleteOlderThan(t *testing.T, db *gitAccessLogs) {
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	for _, l := range []*GitAccessLog{
+		{RepoID: 1, Protocol: "http", Operation: GitAccessClone, CreatedAt: now.Add(-48 * time.Hour)},
+		{RepoID: 1, Protocol: "http", Operation: GitAccessFetch, CreatedAt: now},
+	} {
+		err := db.Create(ctx, l)
+		require.NoError(t, err)
+	}
+
+	err := db.DeleteOlderThan(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+
+	logs, err := db.ListByRepo(ctx, 1, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, GitAccessFetch, logs[0].Operation)
+}
+
+func TestSummarizeRefs(t *testing.T) {
+	assert.Equal(t, "refs/heads/main", summarizeRefs([]string{"refs/heads/main"}))
+	assert.Equal(t,
+		"refs/tags/v1, refs/tags/v2, refs/tags/v3, refs/tags/v4, refs/tags/v5 and 2 more",
+		summarizeRefs([]string{"refs/tags/v1", "refs/tags/v2", "refs/tags/v3", "refs/tags/v4", "refs/tags/v5", "refs/tags/v6", "refs/tags/v7"}),
+	)
+}
+
+func TestBuildGitAccessTraffic(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*60*60)
+	now := time.Date(2026, 5, 10, 9, 0, 0, 0, loc)
+	logs := []*GitAccessLog{
+		{UserID: 1, Operation: GitAccessClone, Bytes: 100, CreatedAt: now},
+		{UserID: 1, Operation: GitAccessClone, Bytes: 100, CreatedAt: now.Add(-time.Hour)},
+		{Operation: GitAccessClone, Bytes: 100, CreatedAt: now.Add(-2 * time.Hour)},
+		{UserID: 2, Operation: GitAccessFetch, Bytes: 10, CreatedAt: now.Add(-3 * time.Hour)},
+		// 23:00 of the previous day in the location of now.
+		{UserID: 2, Operation: GitAccessPush, Bytes: 50, CreatedAt: time.Date(2026, 5, 9, 15, 0, 0, 0, time.UTC)},
+		// Out of range.
+		{UserID: 2, Operation: GitAccessClone, Bytes: 100, CreatedAt: now.AddDate(0, 0, -3)},
+	}
+
+	got := BuildGitAccessTraffic(logs, now, 3)
+	want := []*GitAccessDay{
+		{Date: time.Date(2026, 5, 8, 0, 0, 0, 0, loc)},
+		{Date: time.Date(2026, 5, 9, 0, 0, 0, 0, loc), Pushes: 1, BytesReceived: 50},
+		{Date: time.Date(2026, 5, 10, 0, 0, 0, 0, loc), Clones: 3, UniqueCloners: 2, Fetches: 1, BytesSent: 310},
+	}
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,213 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/errutil"
+	"gogs.io/gogs/internal/gitutil"
+)
+
+// GPGKeysStore is the persistent interface for GPG public keys of users.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type GPGKeysStore interface {
+	// Create parses the ASCII-armored GPG public key and adds it to the user.
+	// It returns ErrGPGKeyInvalid when the content is not a single public key,
+	// and ErrGPGKeyAlreadyExist when the user already has the key.
+	Create(ctx context.Context, ownerID int64, content string) (*GPGKey, error)
+	// DeleteByID deletes the GPG key with given ID of the user.
+	DeleteByID(ctx context.Context, ownerID, id int64) error
+	// List returns all GPG keys of the user in the order they were added.
+	List(ctx context.Context, ownerID int64) ([]*GPGKey, error)
+}
+
+var GPGKeys GPGKeysStore
+
+// GPGKey is a GPG public key of a user, which is published for others to
+// verify signatures made by the user.
+type GPGKey struct {
+	ID          int64  `gorm:"primaryKey"`
+	OwnerID     int64  `gorm:"uniqueIndex:gpg_key_owner_fingerprint_unique;not null"`
+	KeyID       string `gorm:"type:VARCHAR(16);not null"`
+	Fingerprint string `gorm:"type:VARCHAR(40);uniqueIndex:gpg_key_owner_fingerprint_unique;not null"`
+	// Emails are the email addresses of identities of the key, separated by
+	// commas.
+	Emails string `gorm:"type:TEXT;not null"`
+	// Content is the ASCII-armored public key, which never contains any private
+	// key material.
+	Content   string    `gorm:"type:TEXT;not null"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// HasEmail returns true if the email is one of identities of the key. Emails
+// are matched case-insensitively.
+func (k *GPGKey) HasEmail(email string) bool {
+	for _, e := range strings.Split(k.Emails, ",") {
+		if e != "" && strings.EqualFold(e, email) {
+			return true
+		}
+	}
+	return false
+}
+
+var _ GPGKeysStore = (*gpgKeys)(nil)
+
+type gpgKeys struct {
+	*gorm.DB
+}
+
+// NewGPGKeysStore returns a persistent interface for GPG keys with given
+// database connection.
+func NewGPGKeysStore(db *gorm.DB) GPGKeysStore {
+	return &gpgKeys{DB: db}
+}
+
+type ErrGPGKeyInvalid struct {
+	args errutil.Args
+}
+
+func IsErrGPGKeyInvalid(err error) bool {
+	_, ok := err.(ErrGPGKeyInvalid)
+	return ok
+}
+
+func (err ErrGPGKeyInvalid) Error() string {
+	return fmt.Sprintf("invalid GPG public key: %v", err.args)
+}
+
+type ErrGPGKeyAlreadyExist struct {
+	args errutil.Args
+}
+
+func IsErrGPGKeyAlreadyExist(err error) bool {
+	_, ok := err.(ErrGPGKeyAlreadyExist)
+	return ok
+}
+
+func (err ErrGPGKeyAlreadyExist) Error() string {
+	return fmt.Sprintf("GPG key already exists: %v", err.args)
+}
+
+// parseGPGKey parses the ASCII-armored content that must contain exactly one
+// GPG public key, and returns the key with the public part re-armored.
+func parseGPGKey(content string) (*GPGKey, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(content))
+	if err != nil {
+		return nil, ErrGPGKeyInvalid{args: errutil.Args{"reason": err.Error()}}
+	} else if len(entities) != 1 {
+		return nil, ErrGPGKeyInvalid{args: errutil.Args{"reason": fmt.Sprintf("expect exactly one key but got %d", len(entities))}}
+	}
+	e := entities[0]
+	if e.PrivateKey != nil {
+		return nil, ErrGPGKeyInvalid{args: errutil.Args{"reason": "private key is not accepted"}}
+	}
+
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "encode armor")
+	}
+	if err = e.Serialize(w); err != nil {
+		return nil, errors.Wrap(err, "serialize")
+	}
+	if err = w.Close(); err != nil {
+		return nil, errors.Wrap(err, "close armor")
+	}
+	buf.WriteString("\n")
+
+	emails := make([]string, 0, len(e.Identities))
+	for _, id := range e.Identities {
+		if id.UserId != nil && id.UserId.Email != "" {
+			emails = append(emails, id.UserId.Email)
+		}
+	}
+
+	return &GPGKey{
+		KeyID:       e.PrimaryKey.KeyIdString(),
+		Fingerprint: fmt.Sprintf("%X", e.PrimaryKey.Fingerprint),
+		Emails:      strings.Join(emails, ","),
+		Content:     buf.String(),
+	}, nil
+}
+
+func (db *gpgKeys) Create(ctx context.Context, ownerID int64, content string) (*GPGKey, error) {
+	key, err := parseGPGKey(content)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.WithContext(ctx).Where("owner_id = ? AND fingerprint = ?", ownerID, key.Fingerprint).First(new(GPGKey)).Error
+	if err == nil {
+		return nil, ErrGPGKeyAlreadyExist{args: errutil.Args{"ownerID": ownerID, "fingerprint": key.Fingerprint}}
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, errors.Wrap(err, "check existence")
+	}
+
+	key.OwnerID = ownerID
+	return key, db.WithContext(ctx).Create(key).Error
+}
+
+func (db *gpgKeys) DeleteByID(ctx context.Context, ownerID, id int64) error {
+	return db.WithContext(ctx).Where("id = ? AND owner_id = ?", id, ownerID).Delete(new(GPGKey)).Error
+}
+
+func (db *gpgKeys) List(ctx context.Context, ownerID int64) ([]*GPGKey, error) {
+	keys := make([]*GPGKey, 0, 2)
+	return keys, db.WithContext(ctx).Where("owner_id = ?", ownerID).Order("id ASC").Find(&keys).Error
+}
+
+// VerifyCommitSignature returns true if the commit is signed by a GPG key of
+// the user who owns the committer email, and the email is an identity of the
+// key.
+func VerifyCommitSignature(ctx context.Context, c *gitutil.SignedCommit) (bool, error) {
+	if len(c.Signature) == 0 || c.CommitterEmail == "" {
+		return false, nil
+	}
+
+	committer, err := Users.GetByEmail(ctx, c.CommitterEmail)
+	if err != nil {
+		if IsErrUserNotExist(err) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "get committer")
+	}
+
+	keys, err := GPGKeys.List(ctx, committer.ID)
+	if err != nil {
+		return false, errors.Wrap(err, "list GPG keys")
+	}
+	return verifyCommitSignatureWithKeys(c, keys), nil
+}
+
+// verifyCommitSignatureWithKeys returns true if the commit is signed by any of
+// the keys that have the committer email as an identity.
+func verifyCommitSignatureWithKeys(c *gitutil.SignedCommit, keys []*GPGKey) bool {
+	for _, key := range keys {
+		if !key.HasEmail(c.CommitterEmail) {
+			continue
+		}
+
+		keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key.Content))
+		if err != nil {
+			continue
+		}
+		_, err = openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(c.Payload), bytes.NewReader(c.Signature))
+		if err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/gitutil"
+)
+
+// newTestGPGKey generates a new GPG key and returns the entity along with its
+// ASCII-armored public key.
+func newTestGPGKey(t *testing.T, email string) (*openpgp.Entity, string) {
+	e, err := openpgp.NewEntity("Test", "", email, nil)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, e.Serialize(w))
+	require.NoError(t, w.Close())
+	return e, buf.String()
+}
+
+func TestGPGKeys(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(GPGKey)}
+	db := &gpgKeys{
+		DB: dbtest.NewDB(t, "gpgKeys", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *gpgKeys)
+	}{
+		{"Create", gpgKeysCreate},
+		{"DeleteByID", gpgKeysDeleteByID},
+		{"List", gpgKeysList},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func gpgKeysCreate(t *testing.T, db *gpgKeys) {
+	ctx := context.Background()
+
+	e, content := newTestGPGKey(t, "alice@example.com")
+	key, err := db.Create(ctx, 1, content)
+	require.NoError(t, err)
+	assert.Equal(t, e.PrimaryKey.KeyIdString(), key.KeyID)
+	assert.Equal(t, fmt.Sprintf("%X", e.PrimaryKey.Fingerprint), key.Fingerprint)
+	assert.Equal(t, "alice@example.com", key.Emails)
+	assert.Contains(t, key.Content, "BEGIN PGP PUBLIC KEY BLOCK")
+
+	// The same key cannot be added twice to the same user, but can be added to
+	// other users.
+	_, err = db.Create(ctx, 1, content)
+	assert.True(t, IsErrGPGKeyAlreadyExist(err))
+	_, err = db.Create(ctx, 2, content)
+	require.NoError(t, err)
+
+	_, err = db.Create(ctx, 1, "not a key")
+	assert.True(t, IsErrGPGKeyInvalid(err))
+
+	// Private keys are never accepted.
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, e.SerializePrivate(w, nil))
+	require.NoError(t, w.Close())
+	_, err = db.Create(ctx, 3, buf.String())
+	assert.True(t, IsErrGPGKeyInvalid(err))
+}
+
+func gpgKeysDeleteByID(t *testing.T, db *gpgKeys) {
+	ctx := context.Background()
+
+	_, content := newTestGPGKey(t, "alice@example.com")
+	key, err := db.Create(ctx, 1, content)
+	require.NoError(t, err)
+
+	// Only the owner can delete the key.
+	err = db.DeleteByID(ctx, 2, key.ID)
+	require.NoError(t, err)
+	keys, err := db.List(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+
+	err = db.DeleteByID(ctx, 1, key.ID)
+	require.NoError(t, err)
+	keys, err = db.List(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func gpgKeysList(t *testing.T, db *gpgKeys) {
+	ctx := context.Background()
+
+	_, content1 := newTestGPGKey(t, "alice@example.com")
+	key1, err := db.Create(ctx, 1, content1)
+	require.NoError(t, err)
+	_, content2 := newTestGPGKey(t, "alice@example.org")
+	key2, err := db.Create(ctx, 1, content2)
+	require.NoError(t, err)
+	_, err = db.Create(ctx, 2, content1)
+	require.NoError(t, err)
+
+	keys, err := db.List(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, key1.ID, keys[0].ID)
+	assert.Equal(t, key2.ID, keys[1].ID)
+}
+
+func TestVerifyCommitSignatureWithKeys(t *testing.T) {
+	signer, content := newTestGPGKey(t, "alice@example.com")
+	_, otherContent := newTestGPGKey(t, "alice@example.com")
+	keys := []*GPGKey{
+		{Emails: "alice@example.com", Content: otherContent},
+		{Emails: "bob@example.com,alice@example.com", Content: content},
+	}
+
+	payload := []byte("tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n\nInitial commit\n")
+	var sig bytes.Buffer
+	require.NoError(t, openpgp.ArmoredDetachSign(&sig, signer, bytes.NewReader(payload), nil))
+
+	c := &gitutil.SignedCommit{
+		CommitterEmail: "Alice@Example.com",
+		Payload:        payload,
+		Signature:      sig.Bytes(),
+	}
+	assert.True(t, verifyCommitSignatureWithKeys(c, keys))
+
+	// The committer email must be an identity of the key
+	c.CommitterEmail = "carol@example.com"
+	assert.False(t, verifyCommitSignatureWithKeys(c, keys))
+
+	// The payload must not be changed
+	c.CommitterEmail = "alice@example.com"
+	c.Payload = []byte("tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n\nChanged\n")
+	assert.False(t, verifyCommitSignatureWithKeys(c, keys))
+}
@@ -5,6 +5,8 @@
 package db
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
 	"fmt"
 	"strings"
 	"time"
@@ -18,6 +20,7 @@ import (
 	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/db/errors"
 	"gogs.io/gogs/internal/errutil"
+	"gogs.io/gogs/internal/events"
 	"gogs.io/gogs/internal/tool"
 )
 
@@ -27,34 +30,38 @@ var ErrMissingIssueNumber = errors.New("No issue number specified")
 type Issue struct {
 	ID              int64
 	RepoID          int64       `xorm:"INDEX UNIQUE(repo_index)"`
-	Repo            *Repository `xorm:"-" json:"-"`
+	Repo            *Repository `xorm:"-" gorm:"-" json:"-"`
 	Index           int64       `xorm:"UNIQUE(repo_index)"` // Index in one repository.
 	PosterID        int64
-	Poster          *User    `xorm:"-" json:"-"`
-	Title           string   `xorm:"name"`
-	Content         string   `xorm:"TEXT"`
-	RenderedContent string   `xorm:"-" json:"-"`
-	Labels          []*Label `xorm:"-" json:"-"`
+	Poster          *User    `xorm:"-" gorm:"-" json:"-"`
+	Title           string   `xorm:"name" gorm:"column:name"`
+	Content         string   `xorm:"TEXT" gorm:"type:TEXT"`
+	RenderedContent string   `xorm:"-" gorm:"-" json:"-"`
+	Labels          []*Label `xorm:"-" gorm:"-" json:"-"`
 	MilestoneID     int64
-	Milestone       *Milestone `xorm:"-" json:"-"`
+	Milestone       *Milestone `xorm:"-" gorm:"-" json:"-"`
 	Priority        int
 	AssigneeID      int64
-	Assignee        *User `xorm:"-" json:"-"`
+	Assignee        *User `xorm:"-" gorm:"-" json:"-"`
 	IsClosed        bool
-	IsRead          bool         `xorm:"-" json:"-"`
+	IsRead          bool         `xorm:"-" gorm:"-" json:"-"`
 	IsPull          bool         // Indicates whether is a pull request or not.
-	PullRequest     *PullRequest `xorm:"-" json:"-"`
+	PullRequest     *PullRequest `xorm:"-" gorm:"-" json:"-"`
 	NumComments     int
+	// NumReactions and NumThumbsUp are maintained by the reactions store for
+	// sorting issues.
+	NumReactions int `xorm:"NOT NULL DEFAULT 0"`
+	NumThumbsUp  int `xorm:"NOT NULL DEFAULT 0"`
 
-	Deadline     time.Time `xorm:"-" json:"-"`
+	Deadline     time.Time `xorm:"-" gorm:"-" json:"-"`
 	DeadlineUnix int64
-	Created      time.Time `xorm:"-" json:"-"`
+	Created      time.Time `xorm:"-" gorm:"-" json:"-"`
 	CreatedUnix  int64
-	Updated      time.Time `xorm:"-" json:"-"`
+	Updated      time.Time `xorm:"-" gorm:"-" json:"-"`
 	UpdatedUnix  int64
 
-	Attachments []*Attachment `xorm:"-" json:"-"`
-	Comments    []*Comment    `xorm:"-" json:"-"`
+	Attachments []*Attachment `xorm:"-" gorm:"-" json:"-"`
+	Comments    []*Comment    `xorm:"-" gorm:"-" json:"-"`
 }
 
 func (issue *Issue) BeforeInsert() {
@@ -455,22 +462,8 @@ func (issue *Issue) changeStatus(e *xorm.Session, doer *User, repo *Repository,
 	return nil
 }
 
-// ChangeStatus changes issue status to open or closed.
-func (issue *Issue) ChangeStatus(doer *User, repo *Repository, isClosed bool) (err error) {
-	sess := x.NewSession()
-	defer sess.Close()
-	if err = sess.Begin(); err != nil {
-		return err
-	}
-
-	if err = issue.changeStatus(sess, doer, repo, isClosed); err != nil {
-		return err
-	}
-
-	if err = sess.Commit(); err != nil {
-		return fmt.Errorf("Commit: %v", err)
-	}
-
+func (issue *Issue) sendStatusWebhook(doer *User, repo *Repository, isClosed bool) {
+	var err error
 	if issue.IsPull {
 		// Merge pull request calls issue.changeStatus so we need to handle separately.
 		issue.PullRequest.Issue = issue
@@ -486,6 +479,9 @@ func (issue *Issue) ChangeStatus(doer *User, repo *Repository, isClosed bool) (e
 			apiPullRequest.Action = api.HOOK_ISSUE_REOPENED
 		}
 		err = PrepareWebhooks(repo, HOOK_EVENT_PULL_REQUEST, apiPullRequest)
+		if isClosed {
+			tearDownPreviewDeployments(doer, repo, issue.Index)
+		}
 	} else {
 		apiIssues := &api.IssuesPayload{
 			Index:      issue.Index,
@@ -503,7 +499,25 @@ func (issue *Issue) ChangeStatus(doer *User, repo *Repository, isClosed bool) (e
 	if err != nil {
 		log.Error("PrepareWebhooks [is_pull: %v, is_closed: %v]: %v", issue.IsPull, isClosed, err)
 	}
+}
 
+// ChangeStatus changes issue status to open or closed.
+func (issue *Issue) ChangeStatus(doer *User, repo *Repository, isClosed bool) (err error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	if err = issue.changeStatus(sess, doer, repo, isClosed); err != nil {
+		return err
+	}
+
+	if err = sess.Commit(); err != nil {
+		return fmt.Errorf("Commit: %v", err)
+	}
+
+	issue.sendStatusWebhook(doer, repo, isClosed)
 	return nil
 }
 
@@ -549,12 +563,25 @@ func (issue *Issue) ChangeTitle(doer *User, title string) (err error) {
 	return nil
 }
 
+// ContentRevision returns the revision token of the content of an issue or a
+// comment, which is sent back by editors to detect conflicting edits.
+func ContentRevision(content string) string {
+	sum := sha1.Sum([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// ContentRevision returns the revision token of the content of the issue.
+func (issue *Issue) ContentRevision() string {
+	return ContentRevision(issue.Content)
+}
+
 func (issue *Issue) ChangeContent(doer *User, content string) (err error) {
 	oldContent := issue.Content
 	issue.Content = content
 	if err = UpdateIssueCols(issue, "content"); err != nil {
 		return fmt.Errorf("UpdateIssueCols: %v", err)
 	}
+	publishIssueEvent(events.TypeIssueEdited, doer, issue)
 
 	if issue.IsPull {
 		issue.PullRequest.Issue = issue
@@ -591,20 +618,8 @@ func (issue *Issue) ChangeContent(doer *User, content string) (err error) {
 	return nil
 }
 
-func (issue *Issue) ChangeAssignee(doer *User, assigneeID int64) (err error) {
-	issue.AssigneeID = assigneeID
-	if err = UpdateIssueUserByAssignee(issue); err != nil {
-		return fmt.Errorf("UpdateIssueUserByAssignee: %v", err)
-	}
-
-	issue.Assignee, err = GetUserByID(issue.AssigneeID)
-	if err != nil && !IsErrUserNotExist(err) {
-		log.Error("Failed to get user by ID: %v", err)
-		return nil
-	}
-
-	// Error not nil here means user does not exist, which is remove assignee.
-	isRemoveAssignee := err != nil
+func (issue *Issue) sendAssigneeWebhook(doer *User, isRemoveAssignee bool) {
+	var err error
 	if issue.IsPull {
 		issue.PullRequest.Issue = issue
 		apiPullRequest := &api.PullRequestPayload{
@@ -636,7 +651,22 @@ func (issue *Issue) ChangeAssignee(doer *User, assigneeID int64) (err error) {
 	if err != nil {
 		log.Error("PrepareWebhooks [is_pull: %v, remove_assignee: %v]: %v", issue.IsPull, isRemoveAssignee, err)
 	}
+}
+
+func (issue *Issue) ChangeAssignee(doer *User, assigneeID int64) (err error) {
+	issue.AssigneeID = assigneeID
+	if err = UpdateIssueUserByAssignee(issue); err != nil {
+		return fmt.Errorf("UpdateIssueUserByAssignee: %v", err)
+	}
+
+	issue.Assignee, err = GetUserByID(issue.AssigneeID)
+	if err != nil && !IsErrUserNotExist(err) {
+		log.Error("Failed to get user by ID: %v", err)
+		return nil
+	}
 
+	// Error not nil here means user does not exist, which is remove assignee.
+	issue.sendAssigneeWebhook(doer, err != nil)
 	return nil
 }
 
@@ -739,8 +769,18 @@ func newIssue(e *xorm.Session, opts NewIssueOptions) (err error) {
 	return opts.Issue.loadAttributes(e)
 }
 
-// NewIssue creates new issue with labels and attachments for repository.
+// NewIssue creates new issue with labels and attachments for repository. The
+// default assignee of the repository is used when the issue has no assignee.
 func NewIssue(repo *Repository, issue *Issue, labelIDs []int64, uuids []string) (err error) {
+	if issue.AssigneeID == 0 {
+		assignee, err := repo.DefaultAssignee()
+		if err != nil {
+			return fmt.Errorf("get default assignee: %v", err)
+		} else if assignee != nil {
+			issue.AssigneeID = assignee.ID
+		}
+	}
+
 	sess := x.NewSession()
 	defer sess.Close()
 	if err = sess.Begin(); err != nil {
@@ -937,6 +977,10 @@ func buildIssuesQuery(opts *IssuesOptions) *xorm.Session {
 		sess.Asc("issue.updated_unix")
 	case "mostcomment":
 		sess.Desc("issue.num_comments")
+	case "mostreactions":
+		sess.Desc("issue.num_reactions")
+	case "mostthumbsup":
+		sess.Desc("issue.num_thumbs_up")
 	case "leastcomment":
 		sess.Asc("issue.num_comments")
 	case "priority":
@@ -997,6 +1041,23 @@ func Issues(opts *IssuesOptions) ([]*Issue, error) {
 	return issues, nil
 }
 
+// SearchRepoIssues returns issues and/or pull requests of the repository whose
+// title or content contain the keyword, ordered by the most recently updated.
+// At most limit issues are returned.
+func SearchRepoIssues(repoID int64, keyword string, isPull bool, limit int) ([]*Issue, error) {
+	keyword = "%" + strings.ToLower(keyword) + "%"
+	issues := make([]*Issue, 0, limit)
+	err := x.Where("repo_id = ? AND is_pull = ?", repoID, isPull).
+		And("LOWER(name) LIKE ? OR LOWER(content) LIKE ?", keyword, keyword).
+		Desc("updated_unix").
+		Limit(limit).
+		Find(&issues)
+	if err != nil {
+		return nil, fmt.Errorf("find: %v", err)
+	}
+	return issues, nil
+}
+
 // GetParticipantsByIssueID returns all users who are participated in comments of an issue.
 func GetParticipantsByIssueID(issueID int64) ([]*User, error) {
 	userIDs := make([]int64, 0, 5)
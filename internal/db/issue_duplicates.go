@@ -0,0 +1,126 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// issueTitleStopWords are common words that do not help telling whether two
+// issues are about the same thing.
+var issueTitleStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "not": true,
+	"when": true, "from": true, "this": true, "that": true, "does": true,
+	"doesn": true, "can": true, "cannot": true, "into": true, "are": true,
+	"was": true, "should": true, "after": true, "before": true, "use": true,
+}
+
+// maxIssueTitleKeywords is the maximum number of keywords of a title to be
+// matched, which bounds the size of queries.
+const maxIssueTitleKeywords = 10
+
+// issueTitleKeywords returns distinct lower-cased words of the title in the
+// order of appearance, ignoring words shorter than three characters and stop
+// words.
+func issueTitleKeywords(title string) []string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	keywords := make([]string, 0, len(words))
+	seen := make(map[string]bool, len(words))
+	for _, w := range words {
+		if utf8.RuneCountInString(w) < 3 || issueTitleStopWords[w] || seen[w] {
+			continue
+		}
+		seen[w] = true
+		keywords = append(keywords, w)
+		if len(keywords) == maxIssueTitleKeywords {
+			break
+		}
+	}
+	return keywords
+}
+
+// issueTitleSimilarity returns the ratio of keywords that are found in the
+// title, from 0 to 1.
+func issueTitleSimilarity(keywords []string, title string) float64 {
+	if len(keywords) == 0 {
+		return 0
+	}
+
+	words := make(map[string]bool)
+	for _, w := range issueTitleKeywords(title) {
+		words[w] = true
+	}
+	matched := 0
+	for _, k := range keywords {
+		if words[k] {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(keywords))
+}
+
+// minDuplicateIssueSimilarity is the minimum similarity of titles for an issue
+// to be considered a likely duplicate.
+const minDuplicateIssueSimilarity = 0.5
+
+// SuggestDuplicateIssues returns at most limit issues of the repository whose
+// titles are similar to the given title, in both open and closed states,
+// ordered by the most similar and then the most recently updated.
+func SuggestDuplicateIssues(repoID int64, title string, limit int) ([]*Issue, error) {
+	keywords := issueTitleKeywords(title)
+	if len(keywords) == 0 {
+		return []*Issue{}, nil
+	}
+
+	conds := make([]string, len(keywords))
+	args := make([]interface{}, len(keywords))
+	for i, k := range keywords {
+		conds[i] = "LOWER(name) LIKE ?"
+		args[i] = "%" + k + "%"
+	}
+
+	// Candidates are only loosely matched by substrings, and are scored by
+	// whole words below.
+	candidates := make([]*Issue, 0, 100)
+	err := x.Where("repo_id = ? AND is_pull = ?", repoID, false).
+		And(strings.Join(conds, " OR "), args...).
+		Desc("updated_unix").
+		Limit(100).
+		Find(&candidates)
+	if err != nil {
+		return nil, fmt.Errorf("find candidates: %v", err)
+	}
+
+	type scoredIssue struct {
+		issue *Issue
+		score float64
+	}
+	scored := make([]scoredIssue, 0, len(candidates))
+	for _, issue := range candidates {
+		score := issueTitleSimilarity(keywords, issue.Title)
+		if score >= minDuplicateIssueSimilarity {
+			scored = append(scored, scoredIssue{issue: issue, score: score})
+		}
+	}
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].score > scored[j].score
+	})
+
+	if len(scored) > limit {
+		scored = scored[:limit]
+	}
+	issues := make([]*Issue, len(scored))
+	for i := range scored {
+		issues[i] = scored[i].issue
+	}
+	return issues, nil
+}
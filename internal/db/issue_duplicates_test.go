@@ -0,0 +1,48 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueTitleKeywords(t *testing.T) {
+	tests := []struct {
+		title string
+		want  []string
+	}{
+		{title: "", want: []string{}},
+		{title: "It is a bug", want: []string{"bug"}},
+		{title: "Login fails with LDAP: login page returns 500", want: []string{"login", "fails", "ldap", "page", "returns", "500"}},
+		{title: "The avatar doesn't show up", want: []string{"avatar", "show"}},
+		{title: "Überprüfung der Größe", want: []string{"überprüfung", "der", "größe"}},
+	}
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			assert.Equal(t, test.want, issueTitleKeywords(test.title))
+		})
+	}
+}
+
+func TestIssueTitleSimilarity(t *testing.T) {
+	keywords := issueTitleKeywords("Login page returns 500 with LDAP")
+
+	tests := []struct {
+		title string
+		want  float64
+	}{
+		{title: "LDAP login page returns 500", want: 1},
+		{title: "Login page returns errors", want: 0.6},
+		{title: "Logins are broken", want: 0},
+	}
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			assert.Equal(t, test.want, issueTitleSimilarity(keywords, test.title))
+		})
+	}
+	assert.Equal(t, float64(0), issueTitleSimilarity(nil, "anything"))
+}
@@ -5,7 +5,10 @@
 package db
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/unknwon/com"
 	log "unknwon.dev/clog/v2"
@@ -114,7 +117,7 @@ func mailIssueCommentToParticipants(issue *Issue, doer *User, mentions []string)
 		participants = append(participants, issue.Poster)
 	}
 
-	tos := make([]string, 0, len(watchers)) // List of email addresses
+	tos := make([]*User, 0, len(watchers))
 	names := make([]string, 0, len(watchers))
 	for i := range watchers {
 		if watchers[i].UserID == doer.ID {
@@ -129,7 +132,7 @@ func mailIssueCommentToParticipants(issue *Issue, doer *User, mentions []string)
 			continue
 		}
 
-		tos = append(tos, to.Email)
+		tos = append(tos, to)
 		names = append(names, to.Name)
 	}
 	for i := range participants {
@@ -139,35 +142,195 @@ func mailIssueCommentToParticipants(issue *Issue, doer *User, mentions []string)
 			continue
 		}
 
-		tos = append(tos, participants[i].Email)
+		tos = append(tos, participants[i])
 		names = append(names, participants[i].Name)
 	}
 	if issue.Assignee != nil && issue.Assignee.ID != doer.ID {
 		if !com.IsSliceContainsStr(names, issue.Assignee.Name) {
-			tos = append(tos, issue.Assignee.Email)
+			tos = append(tos, issue.Assignee)
 			names = append(names, issue.Assignee.Name)
 		}
 	}
-	email.SendIssueCommentMail(NewMailerIssue(issue), NewMailerRepo(issue.Repo), NewMailerUser(doer), tos)
+	// Notifications are deferred for users in their quiet hours.
+	now := time.Now()
+	for _, b := range batchNotificationRecipients(tos, now) {
+		email.SendIssueCommentMail(NewMailerIssue(issue), NewMailerRepo(issue.Repo), NewMailerUser(doer), b.Emails, b.DeliverAt)
+	}
 
 	// Mail mentioned people and exclude watchers.
 	names = append(names, doer.Name)
-	tos = make([]string, 0, len(mentions)) // list of user names.
+	tos = make([]*User, 0, len(mentions))
 	for i := range mentions {
 		if com.IsSliceContainsStr(names, mentions[i]) {
 			continue
 		}
 
-		tos = append(tos, mentions[i])
+		u, err := GetUserByName(mentions[i])
+		if err != nil || !u.IsMailable() {
+			continue
+		}
+		tos = append(tos, u)
+	}
+	for _, b := range batchNotificationRecipients(tos, now) {
+		email.SendIssueMentionMail(NewMailerIssue(issue), NewMailerRepo(issue.Repo), NewMailerUser(doer), b.Emails, b.DeliverAt)
 	}
-	email.SendIssueMentionMail(NewMailerIssue(issue), NewMailerRepo(issue.Repo), NewMailerUser(doer), GetUserEmailsByNames(tos))
 	return nil
 }
 
+// AllMention is the mention that notifies all users with write access to the
+// repository, i.e. "@all".
+const AllMention = "all"
+
+// mentionAllowed returns true if the doer is allowed to make mentions under the
+// policy (i.e. conf.User.TeamMentions or conf.User.AllMentions). Site admins are
+// always allowed unless such mentions are disabled.
+func mentionAllowed(policy string, doer *User, isMember, isCollaborator func() bool) bool {
+	switch policy {
+	case "disabled":
+		return false
+	case "members":
+		return doer.IsAdmin || isMember()
+	case "collaborators":
+		return doer.IsAdmin || isCollaborator()
+	}
+	return false
+}
+
+// isRepoCollaborator returns true if the user has write access to the
+// repository.
+func isRepoCollaborator(userID int64, repo *Repository) bool {
+	return Perms.Authorize(context.TODO(), userID, repo.ID, AccessModeWrite,
+		AccessModeOptions{
+			OwnerID: repo.OwnerID,
+			Private: repo.IsPrivate,
+		},
+	)
+}
+
+// expandMentions returns names of users mentioned in the content by the doer,
+// including members of mentioned teams and users notified by "@all" when the
+// doer is allowed to make such mentions.
+func expandMentions(e Engine, doer *User, repo *Repository, content string) ([]string, error) {
+	var hasAll bool
+	userMentions := markup.FindAllMentions(content)
+	mentions := make([]string, 0, len(userMentions))
+	for _, m := range userMentions {
+		if strings.EqualFold(m, AllMention) {
+			hasAll = true
+			continue
+		}
+		mentions = append(mentions, m)
+	}
+
+	teamMembers, err := expandTeamMentions(e, doer, repo, markup.FindAllTeamMentions(content))
+	if err != nil {
+		return nil, fmt.Errorf("expand team mentions: %v", err)
+	}
+	mentions = append(mentions, teamMembers...)
+
+	if hasAll {
+		allowed := mentionAllowed(conf.User.AllMentions, doer,
+			func() bool {
+				owner := repo.mustOwner(e)
+				if owner.IsOrganization() {
+					return owner.IsOrgMember(doer.ID)
+				}
+				return owner.ID == doer.ID
+			},
+			func() bool { return isRepoCollaborator(doer.ID, repo) },
+		)
+		if allowed {
+			writers, err := repo.getUsersWithAccesMode(e, AccessModeWrite)
+			if err != nil {
+				return nil, fmt.Errorf("get writers: %v", err)
+			}
+			for _, w := range writers {
+				if w.IsActive {
+					mentions = append(mentions, w.Name)
+				}
+			}
+		}
+	}
+
+	seen := make(map[string]bool, len(mentions))
+	names := mentions[:0]
+	for _, m := range mentions {
+		if seen[strings.ToLower(m)] {
+			continue
+		}
+		seen[strings.ToLower(m)] = true
+		names = append(names, m)
+	}
+	return names, nil
+}
+
+// expandTeamMentions returns names of members of mentioned teams (in the form
+// of "<org>/<team>") who have read access to the repository. Mentions of teams
+// that are not visible to the doer are ignored, i.e. the doer must be a site
+// admin, an owner of the organization or a member of the team, and so are
+// mentions that are not allowed by conf.User.TeamMentions.
+func expandTeamMentions(e Engine, doer *User, repo *Repository, teamMentions []string) ([]string, error) {
+	if len(teamMentions) == 0 || conf.User.TeamMentions == "disabled" {
+		return []string{}, nil
+	}
+
+	names := make([]string, 0, len(teamMentions))
+	for _, mention := range teamMentions {
+		fields := strings.SplitN(mention, "/", 2)
+		if len(fields) != 2 {
+			continue
+		}
+
+		org, err := GetUserByName(fields[0])
+		if err != nil {
+			if IsErrUserNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("get organization %q: %v", fields[0], err)
+		} else if !org.IsOrganization() {
+			continue
+		}
+
+		team, err := getTeamOfOrgByName(e, org.ID, fields[1])
+		if err != nil {
+			if IsErrTeamNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("get team %q: %v", mention, err)
+		}
+
+		if !doer.IsAdmin && !org.IsOwnedBy(doer.ID) && !isTeamMember(e, org.ID, team.ID, doer.ID) {
+			continue
+		}
+		allowed := mentionAllowed(conf.User.TeamMentions, doer,
+			func() bool { return org.IsOrgMember(doer.ID) },
+			func() bool { return isRepoCollaborator(doer.ID, repo) },
+		)
+		if !allowed {
+			continue
+		}
+
+		members, err := getTeamMembers(e, team.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get members of team %q: %v", mention, err)
+		}
+		for _, m := range members {
+			if m.IsActive && repo.HasAccess(m.ID) {
+				names = append(names, m.Name)
+			}
+		}
+	}
+	return names, nil
+}
+
 // MailParticipants sends new issue thread created emails to repository watchers
 // and mentioned people.
 func (issue *Issue) MailParticipants() (err error) {
-	mentions := markup.FindAllMentions(issue.Content)
+	mentions, err := expandMentions(x, issue.Poster, issue.Repo, issue.Content)
+	if err != nil {
+		return fmt.Errorf("expandMentions: %v", err)
+	}
+
 	if err = updateIssueMentions(x, issue.ID, mentions); err != nil {
 		return fmt.Errorf("UpdateIssueMentions [%d]: %v", issue.ID, err)
 	}
@@ -0,0 +1,40 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMentionAllowed(t *testing.T) {
+	yes := func() bool { return true }
+	no := func() bool { return false }
+
+	tests := []struct {
+		name           string
+		policy         string
+		isAdmin        bool
+		isMember       func() bool
+		isCollaborator func() bool
+		want           bool
+	}{
+		{name: "members: member", policy: "members", isMember: yes, isCollaborator: no, want: true},
+		{name: "members: not member", policy: "members", isMember: no, isCollaborator: yes, want: false},
+		{name: "collaborators: collaborator", policy: "collaborators", isMember: no, isCollaborator: yes, want: true},
+		{name: "collaborators: not collaborator", policy: "collaborators", isMember: yes, isCollaborator: no, want: false},
+		{name: "collaborators: site admin", policy: "collaborators", isAdmin: true, isMember: no, isCollaborator: no, want: true},
+		{name: "disabled: site admin", policy: "disabled", isAdmin: true, isMember: yes, isCollaborator: yes, want: false},
+		{name: "unknown policy", policy: "anyone", isMember: yes, isCollaborator: yes, want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doer := &User{IsAdmin: test.isAdmin}
+			got := mentionAllowed(test.policy, doer, test.isMember, test.isCollaborator)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
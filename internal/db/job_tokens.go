@@ -0,0 +1,135 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	gouuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/cryptoutil"
+	"gogs.io/gogs/internal/errutil"
+)
+
+// JobTokensStore is the persistent interface for job tokens issued to CI
+// runners.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type JobTokensStore interface {
+	// Create issues a new job token to the runner for accessing the repository
+	// with given mode until the token expires after the given duration. The raw
+	// token is only available via the Token field of the returned job token.
+	// Expired job tokens of all runners are deleted along the way.
+	Create(ctx context.Context, runnerID, repoID int64, mode AccessMode, ttl time.Duration) (*JobToken, error)
+	// DeleteByToken deletes the job token with given raw token that is issued to
+	// the runner. It returns ErrJobTokenNotExist when not found.
+	DeleteByToken(ctx context.Context, runnerID int64, token string) error
+	// GetByToken returns the unexpired job token with given raw token. It returns
+	// ErrJobTokenNotExist when not found or expired.
+	GetByToken(ctx context.Context, token string) (*JobToken, error)
+}
+
+var JobTokens JobTokensStore
+
+// JobToken is a short-lived token issued to a CI runner for the duration of a
+// job, which can only be used for Git operations over HTTP on a single
+// repository.
+type JobToken struct {
+	ID        int64      `gorm:"primaryKey"`
+	RunnerID  int64      `gorm:"index;not null"`
+	RepoID    int64      `gorm:"not null"`
+	Mode      AccessMode `gorm:"not null"`
+	SHA256    string     `gorm:"type:VARCHAR(64);unique;not null"`
+	ExpiresAt time.Time  `gorm:"index;not null"`
+	CreatedAt time.Time  `gorm:"not null"`
+
+	// Token is the raw token, which is only set right after creation.
+	Token string `gorm:"-" json:"-"`
+}
+
+var _ JobTokensStore = (*jobTokens)(nil)
+
+type jobTokens struct {
+	*gorm.DB
+}
+
+// NewJobTokensStore returns a persistent interface for job tokens issued to CI
+// runners with given database connection.
+func NewJobTokensStore(db *gorm.DB) JobTokensStore {
+	return &jobTokens{DB: db}
+}
+
+func (db *jobTokens) Create(ctx context.Context, runnerID, repoID int64, mode AccessMode, ttl time.Duration) (*JobToken, error) {
+	now := db.NowFunc()
+	err := db.WithContext(ctx).Where("expires_at <= ?", now).Delete(new(JobToken)).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "delete expired")
+	}
+
+	token := cryptoutil.SHA1(gouuid.NewV4().String())
+	t := &JobToken{
+		RunnerID:  runnerID,
+		RepoID:    repoID,
+		Mode:      mode,
+		SHA256:    cryptoutil.SHA256(token),
+		ExpiresAt: now.Add(ttl),
+	}
+	if err = db.WithContext(ctx).Create(t).Error; err != nil {
+		return nil, err
+	}
+
+	t.Token = token
+	return t, nil
+}
+
+var _ errutil.NotFound = (*ErrJobTokenNotExist)(nil)
+
+type ErrJobTokenNotExist struct {
+	args errutil.Args
+}
+
+func IsErrJobTokenNotExist(err error) bool {
+	_, ok := err.(ErrJobTokenNotExist)
+	return ok
+}
+
+func (err ErrJobTokenNotExist) Error() string {
+	return fmt.Sprintf("job token does not exist: %v", err.args)
+}
+
+func (ErrJobTokenNotExist) NotFound() bool {
+	return true
+}
+
+func (db *jobTokens) DeleteByToken(ctx context.Context, runnerID int64, token string) error {
+	result := db.WithContext(ctx).
+		Where("runner_id = ? AND sha256 = ?", runnerID, cryptoutil.SHA256(token)).
+		Delete(new(JobToken))
+	if result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		return ErrJobTokenNotExist{args: errutil.Args{"runnerID": runnerID}}
+	}
+	return nil
+}
+
+func (db *jobTokens) GetByToken(ctx context.Context, token string) (*JobToken, error) {
+	t := new(JobToken)
+	err := db.WithContext(ctx).
+		Where("sha256 = ? AND expires_at > ?", cryptoutil.SHA256(token), db.NowFunc()).
+		First(t).
+		Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrJobTokenNotExist{args: errutil.Args{}}
+		}
+		return nil, err
+	}
+	return t, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+func TestJobTokens(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(JobToken)}
+	db := &jobTokens{
+		DB: dbtest.NewDB(t, "jobTokens", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *jobTokens)
+	}{
+		{"Create", jobTokensCreate},
+		{"DeleteByToken", jobTokensDeleteByToken},
+		{"GetByToken", jobTokensGetByToken},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func jobTokensCreate(t *testing.T, db *jobTokens) {
+	ctx := context.Background()
+
+	_, err := db.Create(ctx, 1, 1, AccessModeRead, -time.Minute)
+	require.NoError(t, err)
+
+	token, err := db.Create(ctx, 1, 2, AccessModeWrite, time.Hour)
+	require.NoError(t, err)
+	assert.Len(t, token.Token, 40)
+	assert.Equal(t, AccessModeWrite, token.Mode)
+	assert.Equal(t, db.NowFunc().Add(time.Hour).Unix(), token.ExpiresAt.Unix())
+
+	// Expired job tokens are deleted.
+	var count int64
+	err = db.Model(new(JobToken)).Count(&count).Error
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func jobTokensDeleteByToken(t *testing.T, db *jobTokens) {
+	ctx := context.Background()
+
+	token, err := db.Create(ctx, 1, 1, AccessModeRead, time.Hour)
+	require.NoError(t, err)
+
+	// Only the runner that the job token is issued to can delete it.
+	err = db.DeleteByToken(ctx, 2, token.Token)
+	assert.True(t, IsErrJobTokenNotExist(err))
+
+	err = db.DeleteByToken(ctx, 1, token.Token)
+	require.NoError(t, err)
+
+	_, err = db.GetByToken(ctx, token.Token)
+	assert.True(t, IsErrJobTokenNotExist(err))
+}
+
+func jobTokensGetByToken(t *testing.T, db *jobTokens) {
+	ctx := context.Background()
+
+	_, err := db.GetByToken(ctx, "bad_token")
+	assert.True(t, IsErrJobTokenNotExist(err))
+
+	token, err := db.Create(ctx, 1, 1, AccessModeRead, time.Hour)
+	require.NoError(t, err)
+	got, err := db.GetByToken(ctx, token.Token)
+	require.NoError(t, err)
+	assert.Equal(t, token.ID, got.ID)
+	assert.Equal(t, int64(1), got.RepoID)
+
+	// Expired job tokens are not returned.
+	expired, err := db.Create(ctx, 1, 1, AccessModeRead, -time.Minute)
+	require.NoError(t, err)
+	_, err = db.GetByToken(ctx, expired.Token)
+	assert.True(t, IsErrJobTokenNotExist(err))
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/events"
+)
+
+// publishIssueEvent publishes the live event of the issue to viewers of the
+// repository.
+func publishIssueEvent(typ string, doer *User, issue *Issue) {
+	events.Publish(events.RepoTopic(issue.RepoID), &events.Event{
+		Type:       typ,
+		RepoID:     issue.RepoID,
+		IssueIndex: issue.Index,
+		ActorID:    doer.ID,
+	})
+}
+
+// involvedUserIDs returns IDs of users involved in the issue, i.e. the poster,
+// the assignee, mentioned users (including requested reviewers) and users who
+// have commented.
+func involvedUserIDs(e Engine, issue *Issue) ([]int64, error) {
+	seen := make(map[int64]bool)
+	var userIDs []int64
+	add := func(ids ...int64) {
+		for _, id := range ids {
+			if id > 0 && !seen[id] {
+				seen[id] = true
+				userIDs = append(userIDs, id)
+			}
+		}
+	}
+	add(issue.PosterID, issue.AssigneeID)
+
+	mentioned := make([]*IssueUser, 0, 5)
+	if err := e.Where("issue_id = ? AND is_mentioned = ?", issue.ID, true).Find(&mentioned); err != nil {
+		return nil, fmt.Errorf("list mentioned users: %v", err)
+	}
+	for _, iu := range mentioned {
+		add(iu.UID)
+	}
+
+	comments := make([]*Comment, 0, 10)
+	if err := e.Where("issue_id = ?", issue.ID).Find(&comments); err != nil {
+		return nil, fmt.Errorf("list comments: %v", err)
+	}
+	for _, c := range comments {
+		add(c.PosterID)
+	}
+	return userIDs, nil
+}
+
+// notifyInvolvedUsers publishes live notifications of new activity of the
+// issue to involved users except the doer.
+func notifyInvolvedUsers(e Engine, doer *User, repo *Repository, issue *Issue) {
+	userIDs, err := involvedUserIDs(e, issue)
+	if err != nil {
+		log.Error("Failed to get involved users [issue_id: %d]: %v", issue.ID, err)
+		return
+	}
+
+	path := "issues"
+	if issue.IsPull {
+		path = "pulls"
+	}
+	url := fmt.Sprintf("%s/%s/%d", repo.HTMLURL(), path, issue.Index)
+	for _, id := range userIDs {
+		if id == doer.ID {
+			continue
+		}
+		events.Publish(events.UserTopic(id), &events.Event{
+			Type:       events.TypeNotification,
+			RepoID:     repo.ID,
+			IssueIndex: issue.Index,
+			URL:        url,
+			ActorID:    doer.ID,
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	api "github.com/gogs/go-gogs-client"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// MergeChecklistChecksStore is the persistent interface for checked items of
+// merge checklists of pull requests.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type MergeChecklistChecksStore interface {
+	// ListByPullRequestID returns checked items of the merge checklist of the
+	// pull request with given ID.
+	ListByPullRequestID(ctx context.Context, pullRequestID int64) ([]*MergeChecklistCheck, error)
+	// Set marks the item of the merge checklist of the pull request with given
+	// ID as checked or unchecked by the user.
+	Set(ctx context.Context, pullRequestID int64, item string, checkerID int64, checked bool) error
+}
+
+var MergeChecklistChecks MergeChecklistChecksStore
+
+// MergeChecklistCheck is a checked item of the merge checklist of a pull
+// request. Items are identified by their text, so that changing an item in the
+// checklist of the repository requires it to be checked again.
+type MergeChecklistCheck struct {
+	ID            int64     `gorm:"primaryKey"`
+	PullRequestID int64     `gorm:"index;not null"`
+	Item          string    `gorm:"type:TEXT;not null"`
+	CheckerID     int64     `gorm:"not null"`
+	CreatedAt     time.Time `gorm:"not null"`
+}
+
+var _ MergeChecklistChecksStore = (*mergeChecklistChecks)(nil)
+
+type mergeChecklistChecks struct {
+	*gorm.DB
+}
+
+// NewMergeChecklistChecksStore returns a persistent interface for checked items
+// of merge checklists with given database connection.
+func NewMergeChecklistChecksStore(db *gorm.DB) MergeChecklistChecksStore {
+	return &mergeChecklistChecks{DB: db}
+}
+
+func (db *mergeChecklistChecks) ListByPullRequestID(ctx context.Context, pullRequestID int64) ([]*MergeChecklistCheck, error) {
+	var checks []*MergeChecklistCheck
+	return checks, db.WithContext(ctx).Where("pull_request_id = ?", pullRequestID).Order("id ASC").Find(&checks).Error
+}
+
+func (db *mergeChecklistChecks) Set(ctx context.Context, pullRequestID int64, item string, checkerID int64, checked bool) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("pull_request_id = ? AND item = ?", pullRequestID, item).Delete(new(MergeChecklistCheck)).Error
+		if err != nil {
+			return errors.Wrap(err, "delete")
+		} else if !checked {
+			return nil
+		}
+
+		return tx.Create(&MergeChecklistCheck{
+			PullRequestID: pullRequestID,
+			Item:          item,
+			CheckerID:     checkerID,
+		}).Error
+	})
+}
+
+var mergeChecklistItemPrefix = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+(?:\[[ xX]?\]\s+)?`)
+
+// ParseMergeChecklist returns items of a merge checklist in Markdown, one item
+// per line, e.g. "- [ ] Documentation is updated". List markers and checkboxes
+// are optional, and blank lines are skipped.
+func ParseMergeChecklist(s string) []string {
+	var items []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(mergeChecklistItemPrefix.ReplaceAllString(line, ""))
+		if line != "" {
+			items = append(items, line)
+		}
+	}
+	return items
+}
+
+// MergeChecklistItem is an item of the merge checklist of a pull request along
+// with its state.
+type MergeChecklistItem struct {
+	Text      string     `json:"text"`
+	Checked   bool       `json:"checked"`
+	CheckerID int64      `json:"-"`
+	CheckedBy *api.User  `json:"checked_by,omitempty"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+}
+
+// buildMergeChecklist returns states of items with given checks. Checks of
+// items that are no longer in the checklist are ignored.
+func buildMergeChecklist(items []string, checks []*MergeChecklistCheck) []*MergeChecklistItem {
+	byItem := make(map[string]*MergeChecklistCheck, len(checks))
+	for _, c := range checks {
+		byItem[c.Item] = c
+	}
+
+	list := make([]*MergeChecklistItem, len(items))
+	for i, item := range items {
+		list[i] = &MergeChecklistItem{Text: item}
+		if c := byItem[item]; c != nil {
+			createdAt := c.CreatedAt
+			list[i].Checked = true
+			list[i].CheckerID = c.CheckerID
+			list[i].CheckedAt = &createdAt
+		}
+	}
+	return list
+}
+
+// IsMergeChecklistComplete returns true if all items of the checklist are
+// checked.
+func IsMergeChecklistComplete(list []*MergeChecklistItem) bool {
+	for _, item := range list {
+		if !item.Checked {
+			return false
+		}
+	}
+	return true
+}
+
+// MergeChecklist returns items of the merge checklist of the base repository
+// along with their states for the pull request.
+func (pr *PullRequest) MergeChecklist(ctx context.Context) ([]*MergeChecklistItem, error) {
+	if pr.BaseRepo == nil {
+		if err := pr.LoadAttributes(); err != nil {
+			return nil, errors.Wrap(err, "load attributes")
+		}
+	}
+	items := ParseMergeChecklist(pr.BaseRepo.MergeChecklist)
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	checks, err := MergeChecklistChecks.ListByPullRequestID(ctx, pr.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "list checks")
+	}
+
+	list := buildMergeChecklist(items, checks)
+	for _, item := range list {
+		if !item.Checked {
+			continue
+		}
+		checker, err := Users.GetByID(ctx, item.CheckerID)
+		if err != nil {
+			if IsErrUserNotExist(err) {
+				continue
+			}
+			return nil, errors.Wrap(err, "get checker")
+		}
+		item.CheckedBy = checker.APIFormat()
+	}
+	return list, nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/gogs/go-gogs-client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+func TestMergeChecklistChecks(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(MergeChecklistCheck)}
+	db := &mergeChecklistChecks{
+		DB: dbtest.NewDB(t, "mergeChecklistChecks", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *mergeChecklistChecks)
+	}{
+		{"Set", mergeChecklistChecksSet},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func mergeChecklistChecksSet(t *testing.T, db *mergeChecklistChecks) {
+	ctx := context.Background()
+
+	err := db.Set(ctx, 1, "Tests are added", 1, true)
+	require.NoError(t, err)
+	err = db.Set(ctx, 1, "Documentation is updated", 1, true)
+	require.NoError(t, err)
+	err = db.Set(ctx, 2, "Tests are added", 1, true)
+	require.NoError(t, err)
+
+	// Checking again replaces the checker
+	err = db.Set(ctx, 1, "Tests are added", 2, true)
+	require.NoError(t, err)
+	err = db.Set(ctx, 1, "Documentation is updated", 2, false)
+	require.NoError(t, err)
+
+	checks, err := db.ListByPullRequestID(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+	assert.Equal(t, "Tests are added", checks[0].Item)
+	assert.Equal(t, int64(2), checks[0].CheckerID)
+
+	// Unchecking an unchecked item is a no-op
+	err = db.Set(ctx, 1, "Changelog is updated", 2, false)
+	require.NoError(t, err)
+
+	checks, err = db.ListByPullRequestID(ctx, 2)
+	require.NoError(t, err)
+	assert.Len(t, checks, 1)
+}
+
+func TestParseMergeChecklist(t *testing.T) {
+	assert.Empty(t, ParseMergeChecklist(" \n\n"))
+
+	got := ParseMergeChecklist(`- [ ] Tests are added
+* [x] Documentation is updated
+
+1. Changelog is updated
+  + [] Reviewed by **two** people
+No list marker
+`)
+	want := []string{
+		"Tests are added",
+		"Documentation is updated",
+		"Changelog is updated",
+		"Reviewed by **two** people",
+		"No list marker",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestBuildMergeChecklist(t *testing.T) {
+	checkedAt := time.Unix(1588568886, 0).UTC()
+	list := buildMergeChecklist(
+		[]string{"Tests are added", "Documentation is updated"},
+		[]*MergeChecklistCheck{
+			{Item: "Documentation is updated", CheckerID: 2, CreatedAt: checkedAt},
+			{Item: "Removed item", CheckerID: 1, CreatedAt: checkedAt},
+		},
+	)
+	want := []*MergeChecklistItem{
+		{Text: "Tests are added"},
+		{Text: "Documentation is updated", Checked: true, CheckerID: 2, CheckedAt: &checkedAt},
+	}
+	assert.Equal(t, want, list)
+	assert.False(t, IsMergeChecklistComplete(list))
+
+	list[0].Checked = true
+	assert.True(t, IsMergeChecklistComplete(list))
+	assert.True(t, IsMergeChecklistComplete(nil))
+}
+
+func TestMergedPullRequestPayload(t *testing.T) {
+	p := &MergedPullRequestPayload{
+		PullRequestPayload: &api.PullRequestPayload{
+			Action: api.HOOK_ISSUE_CLOSED,
+			Index:  1,
+		},
+		MergeChecklist: []*MergeChecklistItem{
+			{Text: "Tests are added", Checked: true, CheckedBy: &api.User{UserName: "alice"}},
+		},
+	}
+	data, err := p.JSONPayload()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"action": "closed"`)
+	assert.Contains(t, string(data), `"merge_checklist": [`)
+	assert.Contains(t, string(data), `"text": "Tests are added"`)
+	assert.NotContains(t, string(data), "CheckerID")
+}
@@ -0,0 +1,472 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/unknwon/com"
+	log "unknwon.dev/clog/v2"
+
+	"github.com/gogs/git-module"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/process"
+	"gogs.io/gogs/internal/sync"
+)
+
+// MergeQueueTaskQueue is the queue of merge queues to be processed, each
+// identified by "<repo ID>:<branch>".
+var MergeQueueTaskQueue = sync.NewUniqueQueue(1000)
+
+// AddMergeQueueTask adds a task to process the merge queue of the branch of the
+// repository.
+func AddMergeQueueTask(repoID int64, branch string) {
+	MergeQueueTaskQueue.Add(fmt.Sprintf("%d:%s", repoID, branch))
+}
+
+// ParseRequiredChecks splits a list of contexts of commit statuses separated by
+// commas or new lines.
+func ParseRequiredChecks(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r'
+	})
+	checks := make([]string, 0, len(fields))
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		if f != "" {
+			checks = append(checks, f)
+		}
+	}
+	return checks
+}
+
+// requiredChecksState returns the combined state of required checks based on
+// given latest statuses. Missing checks are considered as pending.
+func requiredChecksState(requiredChecks []string, statuses []*CommitStatus) CommitStatusState {
+	byContext := make(map[string]*CommitStatus, len(statuses))
+	for _, s := range statuses {
+		byContext[s.Context] = s
+	}
+
+	state := CommitStatusSuccess
+	for _, check := range requiredChecks {
+		s := byContext[check]
+		if s == nil {
+			state = CommitStatusPending
+		} else if s.State.IsFailed() {
+			return CommitStatusFailure
+		} else if s.State == CommitStatusPending {
+			state = CommitStatusPending
+		}
+	}
+	return state
+}
+
+// EnqueuePullRequest adds the pull request to the merge queue of its base
+// branch on behalf of the doer.
+func EnqueuePullRequest(doer *User, pr *PullRequest, commitDescription string) error {
+	ctx := context.TODO()
+
+	// Failed entries are replaced to retry from the end of the queue.
+	e, err := MergeQueueEntries.GetByPullRequestID(ctx, pr.ID)
+	if err == nil && e.IsFailed() {
+		if err = dequeueMergeQueueEntry(pr.BaseRepo, e); err != nil {
+			return fmt.Errorf("dequeue failed entry: %v", err)
+		}
+	}
+
+	if _, err = MergeQueueEntries.Create(ctx, pr, doer.ID, commitDescription); err != nil {
+		return err
+	}
+	go AddMergeQueueTask(pr.BaseRepoID, pr.BaseBranch)
+	return nil
+}
+
+// DequeuePullRequest removes the pull request from the merge queue of its base
+// branch.
+func DequeuePullRequest(pr *PullRequest) error {
+	e, err := MergeQueueEntries.GetByPullRequestID(context.TODO(), pr.ID)
+	if err != nil {
+		if IsErrMergeQueueEntryNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if err = dequeueMergeQueueEntry(pr.BaseRepo, e); err != nil {
+		return err
+	}
+	go AddMergeQueueTask(pr.BaseRepoID, pr.BaseBranch)
+	return nil
+}
+
+// dequeueMergeQueueEntry deletes the entry along with the reference of its
+// speculative merge commit.
+func dequeueMergeQueueEntry(repo *Repository, e *MergeQueueEntry) error {
+	if e.SpeculativeCommitID != "" {
+		_, err := git.NewCommand("update-ref", "-d", e.SpeculativeRefName()).RunInDir(repo.RepoPath())
+		if err != nil {
+			log.Error("Failed to delete reference %q of repository %d: %v", e.SpeculativeRefName(), repo.ID, err)
+		}
+	}
+	return MergeQueueEntries.DeleteByPullRequestID(context.TODO(), e.PullRequestID)
+}
+
+// MergeQueuePosition returns the 1-based position of the entry among active
+// entries in the merge queue, or 0 if the entry has failed.
+func MergeQueuePosition(e *MergeQueueEntry) (int, error) {
+	if e.IsFailed() {
+		return 0, nil
+	}
+
+	entries, err := MergeQueueEntries.List(context.TODO(), e.RepoID, e.BaseBranch)
+	if err != nil {
+		return 0, err
+	}
+	pos := 0
+	for _, entry := range entries {
+		if entry.IsFailed() {
+			continue
+		}
+		pos++
+		if entry.ID == e.ID {
+			break
+		}
+	}
+	return pos, nil
+}
+
+// speculativeWorkspace is a temporary clone of the base repository to create
+// speculative merge commits.
+type speculativeWorkspace struct {
+	repo *Repository
+	path string
+}
+
+func newSpeculativeWorkspace(repo *Repository) (*speculativeWorkspace, error) {
+	path := filepath.Join(conf.Server.AppDataPath, "tmp", "merge-queue", com.ToStr(time.Now().UnixNano()))
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	if _, stderr, err := process.ExecTimeout(5*time.Minute,
+		fmt.Sprintf("newSpeculativeWorkspace (git clone): %s", path),
+		"git", "clone", "--no-checkout", repo.RepoPath(), path); err != nil {
+		return nil, fmt.Errorf("git clone: %s", stderr)
+	}
+
+	// Make speculative merge commits of existing entries available.
+	if _, stderr, err := process.ExecDir(-1, path,
+		fmt.Sprintf("newSpeculativeWorkspace (git fetch): %s", path),
+		"git", "fetch", "origin", "+refs/merge-queue/*:refs/merge-queue/*"); err != nil {
+		return nil, fmt.Errorf("git fetch: %s", stderr)
+	}
+	return &speculativeWorkspace{repo: repo, path: path}, nil
+}
+
+func (w *speculativeWorkspace) exec(desc string, args ...string) (string, error) {
+	stdout, stderr, err := process.ExecDir(-1, w.path,
+		fmt.Sprintf("speculativeWorkspace (%s): %s", desc, w.path),
+		"git", args...)
+	if err != nil {
+		return "", fmt.Errorf("%s: %v - %s", desc, err, stderr)
+	}
+	return strings.TrimSpace(stdout), nil
+}
+
+// merge creates a speculative merge commit for the entry that merges the head
+// branch of the pull request into the base commit, and pushes it to the base
+// repository. It returns the IDs of the merged head commit and the speculative
+// merge commit, or ok=false if there are conflicts.
+func (w *speculativeWorkspace) merge(e *MergeQueueEntry, pr *PullRequest, enqueuer *User, baseCommitID string) (headCommitID, speculativeCommitID string, ok bool, err error) {
+	if _, err = w.exec("git fetch", "fetch", pr.HeadRepo.RepoPath(), git.RefsHeads+pr.HeadBranch); err != nil {
+		return "", "", false, err
+	}
+	if headCommitID, err = w.exec("git rev-parse", "rev-parse", "FETCH_HEAD"); err != nil {
+		return "", "", false, err
+	}
+	if _, err = w.exec("git checkout", "checkout", "--quiet", "--detach", baseCommitID); err != nil {
+		return "", "", false, err
+	}
+	if _, err = w.exec("git merge", "merge", "--no-ff", "--no-commit", headCommitID); err != nil {
+		_, _ = w.exec("git merge --abort", "merge", "--abort")
+		return headCommitID, "", false, nil
+	}
+
+	if err = pr.LoadIssue(); err != nil {
+		return "", "", false, fmt.Errorf("load issue: %v", err)
+	}
+	var commits []*git.Commit
+	if headGitRepo, err := git.Open(pr.HeadRepo.RepoPath()); err != nil {
+		log.Error("Failed to open head repository of pull request [%d]: %v", pr.ID, err)
+	} else if commits, err = headGitRepo.RevList([]string{pr.MergeBase + "..." + headCommitID}); err != nil {
+		log.Error("Failed to list commits of pull request [%d]: %v", pr.ID, err)
+	}
+
+	sig := enqueuer.NewGitSig()
+	if _, err = w.exec("git commit", "commit",
+		fmt.Sprintf("--author='%s <%s>'", sig.Name, sig.Email),
+		"-m", pr.mergeMessage(MERGE_STYLE_REGULAR, commits, e.CommitDescription)); err != nil {
+		return "", "", false, err
+	}
+	if speculativeCommitID, err = w.exec("git rev-parse", "rev-parse", "HEAD"); err != nil {
+		return "", "", false, err
+	}
+	if _, err = w.exec("git push", "push", "--force", "origin", "HEAD:"+e.SpeculativeRefName()); err != nil {
+		return "", "", false, err
+	}
+	return headCommitID, speculativeCommitID, true, nil
+}
+
+func (w *speculativeWorkspace) clean() {
+	if w == nil {
+		return
+	}
+	_ = os.RemoveAll(w.path)
+}
+
+// ProcessMergeQueue processes the merge queue of the branch of the repository.
+// It (re)creates speculative merge commits that are out of date, marks entries
+// as failed for conflicts or failed required checks, and merges the first
+// entry when its required checks have succeeded, until no more progress can be
+// made.
+func ProcessMergeQueue(repoID int64, branch string) error {
+	ctx := context.TODO()
+
+	entries, err := MergeQueueEntries.List(ctx, repoID, branch)
+	if err != nil {
+		return fmt.Errorf("list entries: %v", err)
+	} else if len(entries) == 0 {
+		return nil
+	}
+
+	repo, err := GetRepositoryByID(repoID)
+	if err != nil {
+		if IsErrRepoNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("get repository: %v", err)
+	}
+	baseGitRepo, err := git.Open(repo.RepoPath())
+	if err != nil {
+		return fmt.Errorf("open repository: %v", err)
+	}
+	requiredChecks := ParseRequiredChecks(repo.MergeQueueRequiredChecks)
+
+	var workspace *speculativeWorkspace
+	defer func() {
+		workspace.clean()
+	}()
+
+	for {
+		entries, err := MergeQueueEntries.List(ctx, repoID, branch)
+		if err != nil {
+			return fmt.Errorf("list entries: %v", err)
+		}
+
+		// Collect active entries, and drop entries of pull requests that are no
+		// longer open.
+		active := make([]*MergeQueueEntry, 0, len(entries))
+		prs := make([]*PullRequest, 0, len(entries))
+		for _, e := range entries {
+			if e.IsFailed() {
+				continue
+			}
+
+			pr, err := GetPullRequestByID(e.PullRequestID)
+			if err != nil && !IsErrPullRequestNotExist(err) {
+				return fmt.Errorf("get pull request: %v", err)
+			}
+			if err == nil {
+				err = pr.LoadAttributes()
+				if err != nil {
+					return fmt.Errorf("load attributes of pull request: %v", err)
+				}
+			}
+			if err != nil || pr.HasMerged || pr.Issue.IsClosed || pr.BaseBranch != branch {
+				if err = dequeueMergeQueueEntry(repo, e); err != nil {
+					return fmt.Errorf("dequeue entry: %v", err)
+				}
+				continue
+			}
+
+			active = append(active, e)
+			prs = append(prs, pr)
+		}
+		if len(active) == 0 {
+			return nil
+		}
+
+		baseCommitID, err := baseGitRepo.BranchCommitID(branch)
+		if err != nil {
+			return fmt.Errorf("get branch %q commit ID: %v", branch, err)
+		}
+
+		// Recreate speculative merge commits that are out of date, which
+		// invalidates all entries after them.
+		progressed := false
+		for i, e := range active {
+			pr := prs[i]
+			headGitRepo, err := git.Open(pr.HeadRepo.RepoPath())
+			if err != nil {
+				return fmt.Errorf("open head repository of pull request %d: %v", pr.ID, err)
+			}
+			headCommitID, err := headGitRepo.BranchCommitID(pr.HeadBranch)
+			if err != nil {
+				return fmt.Errorf("get head commit ID of pull request %d: %v", pr.ID, err)
+			}
+			if e.SpeculativeCommitID != "" && e.BaseCommitID == baseCommitID && e.HeadCommitID == headCommitID {
+				baseCommitID = e.SpeculativeCommitID
+				continue
+			}
+
+			if workspace == nil {
+				workspace, err = newSpeculativeWorkspace(repo)
+				if err != nil {
+					return fmt.Errorf("new speculative workspace: %v", err)
+				}
+			}
+
+			enqueuer, err := GetUserByID(e.EnqueuerID)
+			if err != nil {
+				if !IsErrUserNotExist(err) {
+					return fmt.Errorf("get enqueuer: %v", err)
+				}
+				enqueuer = NewGhostUser()
+			}
+
+			headCommitID, speculativeCommitID, ok, err := workspace.merge(e, pr, enqueuer, baseCommitID)
+			if err != nil {
+				return fmt.Errorf("create speculative merge commit for pull request %d: %v", pr.ID, err)
+			} else if !ok {
+				log.Trace("ProcessMergeQueue [repo_id: %d, branch: %s]: pull request %d has conflicts", repoID, branch, pr.ID)
+				if err = MergeQueueEntries.MarkFailed(ctx, e.ID, MergeQueueFailureConflict); err != nil {
+					return fmt.Errorf("mark entry as failed: %v", err)
+				}
+				progressed = true
+				break
+			}
+
+			err = MergeQueueEntries.UpdateSpeculativeCommit(ctx, e.ID, baseCommitID, headCommitID, speculativeCommitID)
+			if err != nil {
+				return fmt.Errorf("update speculative commit: %v", err)
+			}
+			e.BaseCommitID = baseCommitID
+			e.HeadCommitID = headCommitID
+			e.SpeculativeCommitID = speculativeCommitID
+			baseCommitID = speculativeCommitID
+		}
+		if progressed {
+			continue
+		}
+
+		// Mark entries with failed required checks as failed, and merge the first
+		// entry if all of its required checks have succeeded.
+		for i, e := range active {
+			state := CommitStatusSuccess
+			if len(requiredChecks) > 0 {
+				statuses, err := CommitStatuses.ListLatest(ctx, repoID, e.SpeculativeCommitID)
+				if err != nil {
+					return fmt.Errorf("list commit statuses: %v", err)
+				}
+				state = requiredChecksState(requiredChecks, statuses)
+			}
+
+			if state.IsFailed() {
+				log.Trace("ProcessMergeQueue [repo_id: %d, branch: %s]: pull request %d failed required checks", repoID, branch, e.PullRequestID)
+				if err = MergeQueueEntries.MarkFailed(ctx, e.ID, MergeQueueFailureChecks); err != nil {
+					return fmt.Errorf("mark entry as failed: %v", err)
+				}
+				progressed = true
+				break
+			}
+
+			if i > 0 || state != CommitStatusSuccess {
+				continue
+			}
+
+			if err = mergeMergeQueueEntry(repo, baseGitRepo, e, prs[i]); err != nil {
+				log.Error("Failed to merge pull request %d from merge queue: %v", e.PullRequestID, err)
+				if err = MergeQueueEntries.MarkFailed(ctx, e.ID, MergeQueueFailureMerge); err != nil {
+					return fmt.Errorf("mark entry as failed: %v", err)
+				}
+			}
+			progressed = true
+			break
+		}
+		if !progressed {
+			return nil
+		}
+	}
+}
+
+// mergeMergeQueueEntry merges the pull request of the entry by fast-forwarding
+// the base branch to the speculative merge commit, which is tested.
+func mergeMergeQueueEntry(repo *Repository, baseGitRepo *git.Repository, e *MergeQueueEntry, pr *PullRequest) error {
+	enqueuer, err := GetUserByID(e.EnqueuerID)
+	if err != nil {
+		if !IsErrUserNotExist(err) {
+			return fmt.Errorf("get enqueuer: %v", err)
+		}
+		enqueuer = NewGhostUser()
+	}
+
+	// The reference is only updated when the base branch still points to the
+	// base commit of the speculative merge commit.
+	_, err = git.NewCommand("update-ref", git.RefsHeads+pr.BaseBranch, e.SpeculativeCommitID, e.BaseCommitID).RunInDir(repo.RepoPath())
+	if err != nil {
+		return fmt.Errorf("fast-forward base branch: %v", err)
+	}
+
+	if err = dequeueMergeQueueEntry(repo, e); err != nil {
+		return fmt.Errorf("dequeue entry: %v", err)
+	}
+
+	headGitRepo, err := git.Open(pr.HeadRepo.RepoPath())
+	if err != nil {
+		return fmt.Errorf("open head repository: %v", err)
+	}
+	defer func() {
+		go HookQueue.Add(repo.ID)
+		go AddTestPullRequestTask(enqueuer, repo.ID, pr.BaseBranch, false)
+	}()
+	return pr.markMerged(enqueuer, baseGitRepo, headGitRepo, MERGE_STYLE_REGULAR, e.HeadCommitID)
+}
+
+// ProcessMergeQueues processes merge queues in the task queue, and those with
+// active entries on startup.
+func ProcessMergeQueues() {
+	entries, err := MergeQueueEntries.ListActive(context.Background())
+	if err != nil {
+		log.Error("Failed to list merge queue entries: %v", err)
+	}
+	for _, e := range entries {
+		go AddMergeQueueTask(e.RepoID, e.BaseBranch)
+	}
+
+	for key := range MergeQueueTaskQueue.Queue() {
+		log.Trace("ProcessMergeQueues [%s]: processing task", key)
+		MergeQueueTaskQueue.Remove(key)
+
+		i := strings.Index(key, ":")
+		if i < 0 {
+			continue
+		}
+		repoID := com.StrTo(key[:i]).MustInt64()
+		if err = ProcessMergeQueue(repoID, key[i+1:]); err != nil {
+			log.Error("Failed to process merge queue [repo_id: %d, branch: %s]: %v", repoID, key[i+1:], err)
+		}
+	}
+}
+
+func InitMergeQueues() {
+	go ProcessMergeQueues()
+}
@@ -0,0 +1,221 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/errutil"
+)
+
+// MergeQueueEntriesStore is the persistent interface for entries of merge
+// queues.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type MergeQueueEntriesStore interface {
+	// Create adds the pull request to the end of the merge queue of its base
+	// branch. It returns ErrMergeQueueEntryAlreadyExist when the pull request is
+	// already in the queue.
+	Create(ctx context.Context, pr *PullRequest, enqueuerID int64, commitDescription string) (*MergeQueueEntry, error)
+	// DeleteByPullRequestID deletes the entry of the pull request with given ID.
+	DeleteByPullRequestID(ctx context.Context, pullRequestID int64) error
+	// GetByPullRequestID returns the entry of the pull request with given ID. It
+	// returns ErrMergeQueueEntryNotExist when not found.
+	GetByPullRequestID(ctx context.Context, pullRequestID int64) (*MergeQueueEntry, error)
+	// List returns all entries in the merge queue of the branch of the
+	// repository, ordered by their positions in the queue.
+	List(ctx context.Context, repoID int64, branch string) ([]*MergeQueueEntry, error)
+	// ListActive returns all entries that have not failed in all merge queues.
+	ListActive(ctx context.Context) ([]*MergeQueueEntry, error)
+	// ListBySpeculativeCommit returns entries of the repository that are tested
+	// against the speculative merge commit with given SHA.
+	ListBySpeculativeCommit(ctx context.Context, repoID int64, sha string) ([]*MergeQueueEntry, error)
+	// MarkFailed marks the entry with given ID as failed with the reason, which
+	// stays in the queue for the record but is no longer tested or merged.
+	MarkFailed(ctx context.Context, id int64, reason MergeQueueFailureReason) error
+	// UpdateSpeculativeCommit updates the speculative merge commit of the entry
+	// with given ID along with commits it is created from.
+	UpdateSpeculativeCommit(ctx context.Context, id int64, baseCommitID, headCommitID, speculativeCommitID string) error
+}
+
+var MergeQueueEntries MergeQueueEntriesStore
+
+// MergeQueueEntry is a pull request waiting in the merge queue of its base
+// branch. Each entry is tested against a speculative merge commit that merges
+// the pull request into the speculative merge commit of the previous entry (or
+// the base branch for the first entry), and pull requests are merged in the
+// order of the queue once their speculative merge commits pass required
+// status checks. Pull requests are always merged with merge commits by
+// fast-forwarding the base branch to their speculative merge commits.
+type MergeQueueEntry struct {
+	ID                int64  `gorm:"primaryKey"`
+	RepoID            int64  `gorm:"index:merge_queue_entry_repo_branch;not null"`
+	BaseBranch        string `gorm:"index:merge_queue_entry_repo_branch;not null"`
+	PullRequestID     int64  `gorm:"uniqueIndex;not null"`
+	EnqueuerID        int64  `gorm:"not null"`
+	CommitDescription string `gorm:"type:TEXT"`
+	// BaseCommitID is the commit that the speculative merge commit is created
+	// on, i.e. the speculative merge commit of the previous entry or the head of
+	// the base branch.
+	BaseCommitID string `gorm:"type:VARCHAR(40)"`
+	// HeadCommitID is the head commit of the pull request when the speculative
+	// merge commit is created.
+	HeadCommitID        string `gorm:"type:VARCHAR(40)"`
+	SpeculativeCommitID string `gorm:"type:VARCHAR(40);index"`
+	// FailureReason is the reason why the entry has failed, and empty for
+	// entries that are still active.
+	FailureReason MergeQueueFailureReason
+	CreatedAt     time.Time `gorm:"not null"`
+	UpdatedAt     time.Time `gorm:"not null"`
+}
+
+// MergeQueueFailureReason is the reason why a merge queue entry has failed.
+type MergeQueueFailureReason string
+
+const (
+	MergeQueueFailureConflict MergeQueueFailureReason = "conflict"
+	MergeQueueFailureChecks   MergeQueueFailureReason = "checks"
+	MergeQueueFailureMerge    MergeQueueFailureReason = "merge"
+)
+
+// IsFailed returns true if the entry has failed.
+func (e *MergeQueueEntry) IsFailed() bool {
+	return e.FailureReason != ""
+}
+
+// SpeculativeRefName returns the name of the reference that points to the
+// speculative merge commit of the entry in the base repository.
+func (e *MergeQueueEntry) SpeculativeRefName() string {
+	return fmt.Sprintf("refs/merge-queue/%d", e.ID)
+}
+
+var _ MergeQueueEntriesStore = (*mergeQueueEntries)(nil)
+
+type mergeQueueEntries struct {
+	*gorm.DB
+}
+
+// NewMergeQueueEntriesStore returns a persistent interface for entries of merge
+// queues with given database connection.
+func NewMergeQueueEntriesStore(db *gorm.DB) MergeQueueEntriesStore {
+	return &mergeQueueEntries{DB: db}
+}
+
+type ErrMergeQueueEntryAlreadyExist struct {
+	args errutil.Args
+}
+
+func IsErrMergeQueueEntryAlreadyExist(err error) bool {
+	_, ok := err.(ErrMergeQueueEntryAlreadyExist)
+	return ok
+}
+
+func (err ErrMergeQueueEntryAlreadyExist) Error() string {
+	return fmt.Sprintf("merge queue entry already exists: %v", err.args)
+}
+
+func (db *mergeQueueEntries) Create(ctx context.Context, pr *PullRequest, enqueuerID int64, commitDescription string) (*MergeQueueEntry, error) {
+	_, err := db.GetByPullRequestID(ctx, pr.ID)
+	if err == nil {
+		return nil, ErrMergeQueueEntryAlreadyExist{args: errutil.Args{"pullRequestID": pr.ID}}
+	} else if !IsErrMergeQueueEntryNotExist(err) {
+		return nil, errors.Wrap(err, "check existence")
+	}
+
+	e := &MergeQueueEntry{
+		RepoID:            pr.BaseRepoID,
+		BaseBranch:        pr.BaseBranch,
+		PullRequestID:     pr.ID,
+		EnqueuerID:        enqueuerID,
+		CommitDescription: commitDescription,
+	}
+	return e, db.WithContext(ctx).Create(e).Error
+}
+
+func (db *mergeQueueEntries) DeleteByPullRequestID(ctx context.Context, pullRequestID int64) error {
+	return db.WithContext(ctx).Where("pull_request_id = ?", pullRequestID).Delete(new(MergeQueueEntry)).Error
+}
+
+var _ errutil.NotFound = (*ErrMergeQueueEntryNotExist)(nil)
+
+type ErrMergeQueueEntryNotExist struct {
+	args errutil.Args
+}
+
+func IsErrMergeQueueEntryNotExist(err error) bool {
+	_, ok := err.(ErrMergeQueueEntryNotExist)
+	return ok
+}
+
+func (err ErrMergeQueueEntryNotExist) Error() string {
+	return fmt.Sprintf("merge queue entry does not exist: %v", err.args)
+}
+
+func (ErrMergeQueueEntryNotExist) NotFound() bool {
+	return true
+}
+
+func (db *mergeQueueEntries) GetByPullRequestID(ctx context.Context, pullRequestID int64) (*MergeQueueEntry, error) {
+	e := new(MergeQueueEntry)
+	err := db.WithContext(ctx).Where("pull_request_id = ?", pullRequestID).First(e).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrMergeQueueEntryNotExist{args: errutil.Args{"pullRequestID": pullRequestID}}
+		}
+		return nil, err
+	}
+	return e, nil
+}
+
+func (db *mergeQueueEntries) List(ctx context.Context, repoID int64, branch string) ([]*MergeQueueEntry, error) {
+	var entries []*MergeQueueEntry
+	return entries, db.WithContext(ctx).
+		Where("repo_id = ? AND base_branch = ?", repoID, branch).
+		Order("id ASC").
+		Find(&entries).
+		Error
+}
+
+func (db *mergeQueueEntries) ListActive(ctx context.Context) ([]*MergeQueueEntry, error) {
+	var entries []*MergeQueueEntry
+	return entries, db.WithContext(ctx).
+		Where("failure_reason = ?", "").
+		Order("id ASC").
+		Find(&entries).
+		Error
+}
+
+func (db *mergeQueueEntries) ListBySpeculativeCommit(ctx context.Context, repoID int64, sha string) ([]*MergeQueueEntry, error) {
+	var entries []*MergeQueueEntry
+	return entries, db.WithContext(ctx).
+		Where("repo_id = ? AND speculative_commit_id = ?", repoID, sha).
+		Find(&entries).
+		Error
+}
+
+func (db *mergeQueueEntries) MarkFailed(ctx context.Context, id int64, reason MergeQueueFailureReason) error {
+	return db.WithContext(ctx).
+		Model(new(MergeQueueEntry)).
+		Where("id = ?", id).
+		UpdateColumn("failure_reason", reason).
+		Error
+}
+
+func (db *mergeQueueEntries) UpdateSpeculativeCommit(ctx context.Context, id int64, baseCommitID, headCommitID, speculativeCommitID string) error {
+	return db.WithContext(ctx).
+		Model(new(MergeQueueEntry)).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"base_commit_id":        baseCommitID,
+			"head_commit_id":        headCommitID,
+			"speculative_commit_id": speculativeCommitID,
+		}).
+		Error
+}
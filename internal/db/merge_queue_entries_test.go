@@ -0,0 +1,203 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/errutil"
+)
+
+func TestParseRequiredChecks(t *testing.T) {
+	assert.Empty(t, ParseRequiredChecks(" , \n"))
+	assert.Equal(t, []string{"ci/build", "ci/test", "lint"}, ParseRequiredChecks("ci/build, ci/test\r\n lint"))
+}
+
+func TestRequiredChecksState(t *testing.T) {
+	statuses := []*CommitStatus{
+		{Context: "ci/build", State: CommitStatusSuccess},
+		{Context: "ci/test", State: CommitStatusPending},
+		{Context: "lint", State: CommitStatusError},
+	}
+	assert.Equal(t, CommitStatusSuccess, requiredChecksState(nil, statuses))
+	assert.Equal(t, CommitStatusSuccess, requiredChecksState([]string{"ci/build"}, statuses))
+	assert.Equal(t, CommitStatusPending, requiredChecksState([]string{"ci/build", "ci/test"}, statuses))
+	assert.Equal(t, CommitStatusPending, requiredChecksState([]string{"ci/build", "ci/deploy"}, statuses))
+	assert.Equal(t, CommitStatusFailure, requiredChecksState([]string{"ci/test", "lint"}, statuses))
+}
+
+func TestMergeQueueEntries(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(MergeQueueEntry)}
+	db := &mergeQueueEntries{
+		DB: dbtest.NewDB(t, "mergeQueueEntries", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *mergeQueueEntries)
+	}{
+		{"Create", mergeQueueEntriesCreate},
+		{"DeleteByPullRequestID", mergeQueueEntriesDeleteByPullRequestID},
+		{"GetByPullRequestID", mergeQueueEntriesGetByPullRequestID},
+		{"List", mergeQueueEntriesList},
+		{"ListActive", mergeQueueEntriesListActive},
+		{"ListBySpeculativeCommit", mergeQueueEntriesListBySpeculativeCommit},
+		{"MarkFailed", mergeQueueEntriesMarkFailed},
+		{"UpdateSpeculativeCommit", mergeQueueEntriesUpdateSpeculativeCommit},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func mergeQueueEntriesCreate(t *testing.T, db *mergeQueueEntries) {
+	ctx := context.Background()
+
+	pr := &PullRequest{ID: 1, BaseRepoID: 1, BaseBranch: "main"}
+	e, err := db.Create(ctx, pr, 2, "Fix typos")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), e.RepoID)
+	assert.Equal(t, "main", e.BaseBranch)
+	assert.Equal(t, int64(2), e.EnqueuerID)
+	assert.False(t, e.IsFailed())
+	assert.Equal(t, "refs/merge-queue/1", e.SpeculativeRefName())
+
+	_, err = db.Create(ctx, pr, 2, "")
+	wantErr := ErrMergeQueueEntryAlreadyExist{args: errutil.Args{"pullRequestID": int64(1)}}
+	assert.Equal(t, wantErr, err)
+}
+
+func mergeQueueEntriesDeleteByPullRequestID(t *testing.T, db *mergeQueueEntries) {
+	ctx := context.Background()
+
+	_, err := db.Create(ctx, &PullRequest{ID: 1, BaseRepoID: 1, BaseBranch: "main"}, 1, "")
+	require.NoError(t, err)
+
+	err = db.DeleteByPullRequestID(ctx, 1)
+	require.NoError(t, err)
+
+	_, err = db.GetByPullRequestID(ctx, 1)
+	wantErr := ErrMergeQueueEntryNotExist{args: errutil.Args{"pullRequestID": int64(1)}}
+	assert.Equal(t, wantErr, err)
+}
+
+func mergeQueueEntriesGetByPullRequestID(t *testing.T, db *mergeQueueEntries) {
+	ctx := context.Background()
+
+	_, err := db.GetByPullRequestID(ctx, 1)
+	wantErr := ErrMergeQueueEntryNotExist{args: errutil.Args{"pullRequestID": int64(1)}}
+	assert.Equal(t, wantErr, err)
+
+	_, err = db.Create(ctx, &PullRequest{ID: 1, BaseRepoID: 1, BaseBranch: "main"}, 1, "")
+	require.NoError(t, err)
+
+	e, err := db.GetByPullRequestID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), e.PullRequestID)
+}
+
+func mergeQueueEntriesList(t *testing.T, db *mergeQueueEntries) {
+	ctx := context.Background()
+
+	for _, pr := range []*PullRequest{
+		{ID: 3, BaseRepoID: 1, BaseBranch: "main"},
+		{ID: 1, BaseRepoID: 1, BaseBranch: "main"},
+		{ID: 2, BaseRepoID: 1, BaseBranch: "develop"},
+		{ID: 4, BaseRepoID: 2, BaseBranch: "main"},
+	} {
+		_, err := db.Create(ctx, pr, 1, "")
+		require.NoError(t, err)
+	}
+
+	entries, err := db.List(ctx, 1, "main")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, int64(3), entries[0].PullRequestID)
+	assert.Equal(t, int64(1), entries[1].PullRequestID)
+}
+
+func mergeQueueEntriesListActive(t *testing.T, db *mergeQueueEntries) {
+	ctx := context.Background()
+
+	e1, err := db.Create(ctx, &PullRequest{ID: 1, BaseRepoID: 1, BaseBranch: "main"}, 1, "")
+	require.NoError(t, err)
+	_, err = db.Create(ctx, &PullRequest{ID: 2, BaseRepoID: 2, BaseBranch: "main"}, 1, "")
+	require.NoError(t, err)
+
+	err = db.MarkFailed(ctx, e1.ID, MergeQueueFailureConflict)
+	require.NoError(t, err)
+
+	entries, err := db.ListActive(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(2), entries[0].PullRequestID)
+}
+
+func mergeQueueEntriesListBySpeculativeCommit(t *testing.T, db *mergeQueueEntries) {
+	ctx := context.Background()
+
+	e, err := db.Create(ctx, &PullRequest{ID: 1, BaseRepoID: 1, BaseBranch: "main"}, 1, "")
+	require.NoError(t, err)
+	err = db.UpdateSpeculativeCommit(ctx, e.ID, "base", "head", testCommitSHA)
+	require.NoError(t, err)
+
+	entries, err := db.ListBySpeculativeCommit(ctx, 1, testCommitSHA)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, e.ID, entries[0].ID)
+
+	entries, err = db.ListBySpeculativeCommit(ctx, 2, testCommitSHA)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func mergeQueueEntriesMarkFailed(t *testing.T, db *mergeQueueEntries) {
+	ctx := context.Background()
+
+	e, err := db.Create(ctx, &PullRequest{ID: 1, BaseRepoID: 1, BaseBranch: "main"}, 1, "")
+	require.NoError(t, err)
+
+	err = db.MarkFailed(ctx, e.ID, MergeQueueFailureChecks)
+	require.NoError(t, err)
+
+	e, err = db.GetByPullRequestID(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, e.IsFailed())
+	assert.Equal(t, MergeQueueFailureChecks, e.FailureReason)
+}
+
+func mergeQueueEntriesUpdateSpeculativeCommit(t *testing.T, db *mergeQueueEntries) {
+	ctx := context.Background()
+
+	e, err := db.Create(ctx, &PullRequest{ID: 1, BaseRepoID: 1, BaseBranch: "main"}, 1, "")
+	require.NoError(t, err)
+
+	err = db.UpdateSpeculativeCommit(ctx, e.ID, "base", "head", testCommitSHA)
+	require.NoError(t, err)
+
+	e, err = db.GetByPullRequestID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "base", e.BaseCommitID)
+	assert.Equal(t, "head", e.HeadCommitID)
+	assert.Equal(t, testCommitSHA, e.SpeculativeCommitID)
+}
@@ -5,6 +5,8 @@
 package migrations
 
 import (
+	"fmt"
+
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
 	log "unknwon.dev/clog/v2"
@@ -58,6 +60,46 @@ var migrations = []Migration{
 	NewMigration("add index to action.user_id", addIndexToActionUserID),
 }
 
+// ErrUnsupportedVersion is returned when the version of the database schema is
+// older than the minimum version that can be migrated from automatically.
+type ErrUnsupportedVersion struct {
+	Current int64
+	Min     int64
+}
+
+func IsErrUnsupportedVersion(err error) bool {
+	_, ok := err.(ErrUnsupportedVersion)
+	return ok
+}
+
+func (err ErrUnsupportedVersion) Error() string {
+	return fmt.Sprintf("database schema version %d is older than the minimum supported version %d, "+
+		"please migrate the database using a previous release first (https://gogs.io/gogs/releases/tag/v0.12.0)",
+		err.Current, err.Min)
+}
+
+// CheckVersion returns ErrUnsupportedVersion if the version of the database
+// schema can't be migrated from automatically. It returns nil when the database
+// has no version record yet.
+func CheckVersion(db *gorm.DB) error {
+	if !db.Migrator().HasTable(new(Version)) {
+		return nil
+	}
+
+	var current Version
+	err := db.Where("id = ?", 1).First(&current).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "get the version record")
+	}
+
+	if minDBVersion > current.Version {
+		return ErrUnsupportedVersion{Current: current.Version, Min: minDBVersion}
+	}
+	return nil
+}
+
 // Migrate migrates the database schema and/or data to the current version.
 func Migrate(db *gorm.DB) error {
 	// NOTE: GORM has problem migrating tables that happen to have columns with the
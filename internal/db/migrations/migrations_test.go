@@ -0,0 +1,44 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+func TestCheckVersion(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	db := dbtest.NewDB(t, "checkVersion")
+
+	// No version table
+	err := CheckVersion(db)
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(new(Version))
+	require.NoError(t, err)
+
+	// No version record
+	err = CheckVersion(db)
+	require.NoError(t, err)
+
+	err = db.Create(&Version{ID: 1, Version: minDBVersion - 1}).Error
+	require.NoError(t, err)
+	err = CheckVersion(db)
+	assert.Equal(t, ErrUnsupportedVersion{Current: minDBVersion - 1, Min: minDBVersion}, err)
+
+	err = db.Model(new(Version)).Where("id = ?", 1).Update("version", minDBVersion+len(migrations)).Error
+	require.NoError(t, err)
+	err = CheckVersion(db)
+	assert.NoError(t, err)
+}
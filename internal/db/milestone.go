@@ -6,6 +6,7 @@ package db
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	log "unknwon.dev/clog/v2"
@@ -169,6 +170,19 @@ func GetMilestoneByRepoID(repoID, id int64) (*Milestone, error) {
 	return getMilestoneByRepoID(x, repoID, id)
 }
 
+// GetMilestoneByRepoIDAndName returns the milestone with given name in a
+// repository, the name is matched case-insensitively.
+func GetMilestoneByRepoIDAndName(repoID int64, name string) (*Milestone, error) {
+	m := new(Milestone)
+	has, err := x.Where("repo_id = ? AND LOWER(name) = ?", repoID, strings.ToLower(name)).Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrMilestoneNotExist{args: map[string]interface{}{"repoID": repoID, "name": name}}
+	}
+	return m, nil
+}
+
 // GetMilestonesByRepoID returns all milestones of a repository.
 func GetMilestonesByRepoID(repoID int64) ([]*Milestone, error) {
 	miles := make([]*Milestone, 0, 10)
@@ -330,22 +344,8 @@ func changeMilestoneAssign(e *xorm.Session, issue *Issue, oldMilestoneID int64)
 	return updateIssue(e, issue)
 }
 
-// ChangeMilestoneAssign changes assignment of milestone for issue.
-func ChangeMilestoneAssign(doer *User, issue *Issue, oldMilestoneID int64) (err error) {
-	sess := x.NewSession()
-	defer sess.Close()
-	if err = sess.Begin(); err != nil {
-		return err
-	}
-
-	if err = changeMilestoneAssign(sess, issue, oldMilestoneID); err != nil {
-		return err
-	}
-
-	if err = sess.Commit(); err != nil {
-		return fmt.Errorf("Commit: %v", err)
-	}
-
+func (issue *Issue) sendMilestoneWebhook(doer *User) {
+	var err error
 	var hookAction api.HookIssueAction
 	if issue.MilestoneID > 0 {
 		hookAction = api.HOOK_ISSUE_MILESTONED
@@ -378,7 +378,25 @@ func ChangeMilestoneAssign(doer *User, issue *Issue, oldMilestoneID int64) (err
 	if err != nil {
 		log.Error("PrepareWebhooks [is_pull: %v]: %v", issue.IsPull, err)
 	}
+}
+
+// ChangeMilestoneAssign changes assignment of milestone for issue.
+func ChangeMilestoneAssign(doer *User, issue *Issue, oldMilestoneID int64) (err error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	if err = changeMilestoneAssign(sess, issue, oldMilestoneID); err != nil {
+		return err
+	}
+
+	if err = sess.Commit(); err != nil {
+		return fmt.Errorf("Commit: %v", err)
+	}
 
+	issue.sendMilestoneWebhook(doer)
 	return nil
 }
 
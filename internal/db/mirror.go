@@ -40,6 +40,10 @@ type Mirror struct {
 	NextSync     time.Time `xorm:"-" json:"-"`
 	NextSyncUnix int64     `xorm:"next_update_unix"`
 
+	// Last time of checking drift of references from upstream
+	DriftChecked     time.Time `xorm:"-" json:"-"`
+	DriftCheckedUnix int64     `xorm:"NOT NULL DEFAULT 0"`
+
 	address string `xorm:"-"`
 }
 
@@ -64,6 +68,8 @@ func (m *Mirror) AfterSet(colName string, _ xorm.Cell) {
 		m.LastSync = time.Unix(m.LastSyncUnix, 0).Local()
 	case "next_update_unix":
 		m.NextSync = time.Unix(m.NextSyncUnix, 0).Local()
+	case "drift_checked_unix":
+		m.DriftChecked = time.Unix(m.DriftCheckedUnix, 0).Local()
 	}
 }
 
@@ -215,8 +221,8 @@ func (m *Mirror) runSync() ([]*mirrorSyncResult, bool) {
 	// good condition to prevent long blocking on URL resolution without syncing anything.
 	if !git.IsURLAccessible(time.Minute, m.RawAddress()) {
 		desc := fmt.Sprintf("Source URL of mirror repository '%s' is not accessible: %s", m.Repo.FullName(), m.MosaicsAddress())
-		if err := CreateRepositoryNotice(desc); err != nil {
-			log.Error("CreateRepositoryNotice: %v", err)
+		if err := CreateNotice(NOTICE_MIRROR, NoticeLevelWarn, desc); err != nil {
+			log.Error("CreateNotice: %v", err)
 		}
 		return nil, false
 	}
@@ -231,8 +237,8 @@ func (m *Mirror) runSync() ([]*mirrorSyncResult, bool) {
 	if err != nil {
 		desc := fmt.Sprintf("Failed to update mirror repository '%s': %s", repoPath, stderr)
 		log.Error(desc)
-		if err = CreateRepositoryNotice(desc); err != nil {
-			log.Error("CreateRepositoryNotice: %v", err)
+		if err = CreateNotice(NOTICE_MIRROR, NoticeLevelError, desc); err != nil {
+			log.Error("CreateNotice: %v", err)
 		}
 		return nil, false
 	}
@@ -241,6 +247,12 @@ func (m *Mirror) runSync() ([]*mirrorSyncResult, bool) {
 	if err := m.Repo.UpdateSize(); err != nil {
 		log.Error("UpdateSize [repo_id: %d]: %v", m.Repo.ID, err)
 	}
+	if err := m.Repo.DetectLicense(); err != nil {
+		log.Error("Failed to detect license [repo_id: %d]: %v", m.Repo.ID, err)
+	}
+	if err := m.Repo.UpdateDependencies(context.TODO()); err != nil {
+		log.Error("Failed to update dependencies [repo_id: %d]: %v", m.Repo.ID, err)
+	}
 
 	if m.Repo.HasWiki() {
 		// Even if wiki sync failed, we still want results from the main repository
@@ -249,8 +261,8 @@ func (m *Mirror) runSync() ([]*mirrorSyncResult, bool) {
 			"git", "remote", "update", "--prune"); err != nil {
 			desc := fmt.Sprintf("Failed to update mirror wiki repository '%s': %s", wikiPath, stderr)
 			log.Error(desc)
-			if err = CreateRepositoryNotice(desc); err != nil {
-				log.Error("CreateRepositoryNotice: %v", err)
+			if err = CreateNotice(NOTICE_MIRROR, NoticeLevelError, desc); err != nil {
+				log.Error("CreateNotice: %v", err)
 			}
 		}
 	}
@@ -285,7 +297,10 @@ func UpdateMirror(m *Mirror) error {
 
 func DeleteMirrorByRepoID(repoID int64) error {
 	_, err := x.Delete(&Mirror{RepoID: repoID})
-	return err
+	if err != nil {
+		return err
+	}
+	return MirrorRefDrifts.Replace(context.TODO(), repoID, nil)
 }
 
 // MirrorUpdate checks and updates mirror repositories.
@@ -304,6 +319,9 @@ func MirrorUpdate() {
 			log.Error("Disconnected mirror repository found: %d", m.ID)
 			return nil
 		}
+		if m.Repo.IsTrashed() {
+			return nil
+		}
 
 		MirrorQueue.Add(m.RepoID)
 		return nil
@@ -433,6 +451,13 @@ func SyncMirrors() {
 				log.Error("Failed to create action for mirror sync push [repo_id: %d]: %v", m.RepoID, err)
 				continue
 			}
+
+			if newCommitID != "" {
+				err = IndexCommitMessages(ctx, m.RepoID, m.Repo.RepoPath(), result.refName, oldCommitID, newCommitID)
+				if err != nil {
+					log.Error("Failed to index commit messages [repo_id: %d, ref: %s]: %v", m.RepoID, result.refName, err)
+				}
+			}
 		}
 
 		if _, err = x.Exec("UPDATE mirror SET updated_unix = ? WHERE repo_id = ?", time.Now().Unix(), m.RepoID); err != nil {
@@ -440,6 +465,15 @@ func SyncMirrors() {
 			continue
 		}
 
+		if conf.Mirror.EnableDriftCheck {
+			drifts, err := CheckMirrorDrift(ctx, m)
+			if err != nil {
+				log.Error("Failed to check drift of mirror [repo_id: %d]: %v", m.RepoID, err)
+			} else if len(drifts) > 0 {
+				log.Trace("SyncMirrors [repo_id: %d]: %d references drifted from upstream", m.RepoID, len(drifts))
+			}
+		}
+
 		// Get latest commit date and compare to current repository updated time,
 		// update if latest commit date is newer.
 		latestCommitTime, err := gitRepo.LatestCommitTime()
@@ -0,0 +1,200 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/errutil"
+	"gogs.io/gogs/internal/gitutil"
+)
+
+// MirrorRefDriftsStore is the persistent interface for references of mirrors
+// that have drifted from their upstream.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type MirrorRefDriftsStore interface {
+	// List returns all drifted references of the mirror repository, ordered by
+	// reference names.
+	List(ctx context.Context, repoID int64) ([]*MirrorRefDrift, error)
+	// Replace replaces all drifted references of the mirror repository with
+	// given ones.
+	Replace(ctx context.Context, repoID int64, drifts []*MirrorRefDrift) error
+}
+
+var MirrorRefDrifts MirrorRefDriftsStore
+
+// MirrorRefDrift is a branch or tag of a mirror repository that differs from
+// its upstream as of the last check.
+type MirrorRefDrift struct {
+	ID      int64  `gorm:"primaryKey"`
+	RepoID  int64  `gorm:"uniqueIndex:mirror_ref_drift_repo_ref_unique;not null"`
+	RefName string `gorm:"type:VARCHAR(255);uniqueIndex:mirror_ref_drift_repo_ref_unique;not null"`
+	// UpstreamCommitID is empty if the reference does not exist upstream.
+	UpstreamCommitID string `gorm:"type:VARCHAR(40)"`
+	// LocalCommitID is empty if the reference does not exist in the mirror.
+	LocalCommitID string    `gorm:"type:VARCHAR(40)"`
+	CheckedAt     time.Time `gorm:"not null"`
+}
+
+// Drift returns the drift of the reference in the form of Git utilities.
+func (d *MirrorRefDrift) Drift() *gitutil.RefDrift {
+	return &gitutil.RefDrift{
+		RefName:          d.RefName,
+		UpstreamCommitID: d.UpstreamCommitID,
+		LocalCommitID:    d.LocalCommitID,
+	}
+}
+
+// Kind returns the kind of the drift, see gitutil.RefDriftMissing,
+// gitutil.RefDriftExtra and gitutil.RefDriftDiverged.
+func (d *MirrorRefDrift) Kind() string {
+	return d.Drift().Kind()
+}
+
+var _ MirrorRefDriftsStore = (*mirrorRefDrifts)(nil)
+
+type mirrorRefDrifts struct {
+	*gorm.DB
+}
+
+// NewMirrorRefDriftsStore returns a persistent interface for drifted references
+// of mirrors with given database connection.
+func NewMirrorRefDriftsStore(db *gorm.DB) MirrorRefDriftsStore {
+	return &mirrorRefDrifts{DB: db}
+}
+
+func (db *mirrorRefDrifts) List(ctx context.Context, repoID int64) ([]*MirrorRefDrift, error) {
+	var drifts []*MirrorRefDrift
+	return drifts, db.WithContext(ctx).
+		Where("repo_id = ?", repoID).
+		Order("ref_name ASC").
+		Find(&drifts).
+		Error
+}
+
+func (db *mirrorRefDrifts) Replace(ctx context.Context, repoID int64, drifts []*MirrorRefDrift) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("repo_id = ?", repoID).Delete(new(MirrorRefDrift)).Error
+		if err != nil {
+			return err
+		}
+		if len(drifts) == 0 {
+			return nil
+		}
+
+		for _, d := range drifts {
+			d.ID = 0
+			d.RepoID = repoID
+		}
+		return tx.CreateInBatches(drifts, 100).Error
+	})
+}
+
+// filterRefDrifts returns drifts to be reported for a mirror. References that
+// only exist locally are expected when pruning is disabled, thus skipped.
+func filterRefDrifts(drifts []*gitutil.RefDrift, enablePrune bool, checkedAt time.Time) []*MirrorRefDrift {
+	filtered := make([]*MirrorRefDrift, 0, len(drifts))
+	for _, d := range drifts {
+		if !enablePrune && d.Kind() == gitutil.RefDriftExtra {
+			continue
+		}
+		filtered = append(filtered, &MirrorRefDrift{
+			RefName:          d.RefName,
+			UpstreamCommitID: d.UpstreamCommitID,
+			LocalCommitID:    d.LocalCommitID,
+			CheckedAt:        checkedAt,
+		})
+	}
+	return filtered
+}
+
+// CheckMirrorDrift compares branches and tags of the mirror with its upstream,
+// and records references that have drifted.
+func CheckMirrorDrift(ctx context.Context, m *Mirror) ([]*MirrorRefDrift, error) {
+	repoPath := m.Repo.RepoPath()
+	timeout := time.Duration(conf.Git.Timeout.Mirror) * time.Second
+
+	upstream, err := gitutil.ListRemoteRefs(repoPath, "origin", timeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "list upstream references")
+	}
+	local, err := gitutil.ListLocalRefs(repoPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "list local references")
+	}
+
+	checkedAt := time.Now()
+	drifts := filterRefDrifts(gitutil.CompareRefs(upstream, local), m.EnablePrune, checkedAt)
+	err = MirrorRefDrifts.Replace(ctx, m.RepoID, drifts)
+	if err != nil {
+		return nil, errors.Wrap(err, "replace drifted references")
+	}
+
+	_, err = x.Exec("UPDATE mirror SET drift_checked_unix = ? WHERE repo_id = ?", checkedAt.Unix(), m.RepoID)
+	if err != nil {
+		return nil, errors.Wrap(err, "update 'mirror.drift_checked_unix'")
+	}
+	m.DriftCheckedUnix = checkedAt.Unix()
+	m.DriftChecked = checkedAt
+	return drifts, nil
+}
+
+var _ errutil.NotFound = (*ErrMirrorRefNotDrifted)(nil)
+
+type ErrMirrorRefNotDrifted struct {
+	args errutil.Args
+}
+
+func IsErrMirrorRefNotDrifted(err error) bool {
+	_, ok := err.(ErrMirrorRefNotDrifted)
+	return ok
+}
+
+func (err ErrMirrorRefNotDrifted) Error() string {
+	return fmt.Sprintf("mirror reference has not drifted: %v", err.args)
+}
+
+func (ErrMirrorRefNotDrifted) NotFound() bool {
+	return true
+}
+
+// ResyncMirrorRefs forcibly re-syncs given drifted references of the mirror
+// from its upstream, then checks the drift again. References that exist
+// upstream are fetched, and others are deleted. It returns
+// ErrMirrorRefNotDrifted when any of the references has not drifted as of the
+// last check.
+func ResyncMirrorRefs(ctx context.Context, m *Mirror, refNames []string) ([]*MirrorRefDrift, error) {
+	drifts, err := MirrorRefDrifts.List(ctx, m.RepoID)
+	if err != nil {
+		return nil, errors.Wrap(err, "list drifted references")
+	}
+	driftsByName := make(map[string]*MirrorRefDrift, len(drifts))
+	for _, d := range drifts {
+		driftsByName[d.RefName] = d
+	}
+
+	toResync := make([]*gitutil.RefDrift, 0, len(refNames))
+	for _, refName := range refNames {
+		d, ok := driftsByName[refName]
+		if !ok {
+			return nil, ErrMirrorRefNotDrifted{args: errutil.Args{"repoID": m.RepoID, "refName": refName}}
+		}
+		toResync = append(toResync, d.Drift())
+	}
+
+	timeout := time.Duration(conf.Git.Timeout.Mirror) * time.Second
+	err = gitutil.ResyncRefs(m.Repo.RepoPath(), "origin", timeout, toResync)
+	if err != nil {
+		return nil, errors.Wrap(err, "re-sync references")
+	}
+	return CheckMirrorDrift(ctx, m)
+}
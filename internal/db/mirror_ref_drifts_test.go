@@ -0,0 +1,103 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/gitutil"
+)
+
+func TestMirrorRefDrifts(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(MirrorRefDrift)}
+	db := &mirrorRefDrifts{
+		DB: dbtest.NewDB(t, "mirrorRefDrifts", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *mirrorRefDrifts)
+	}{
+		{"Replace", mirrorRefDriftsReplace},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func mirrorRefDriftsReplace(t *testing.T, db *mirrorRefDrifts) {
+	ctx := context.Background()
+	checkedAt := time.Unix(1588568886, 0)
+
+	err := db.Replace(ctx, 1, []*MirrorRefDrift{
+		{RefName: "refs/tags/v1.0", UpstreamCommitID: "b", LocalCommitID: "c", CheckedAt: checkedAt},
+		{RefName: "refs/heads/feature", UpstreamCommitID: "a", CheckedAt: checkedAt},
+	})
+	require.NoError(t, err)
+	err = db.Replace(ctx, 2, []*MirrorRefDrift{
+		{RefName: "refs/heads/old", LocalCommitID: "d", CheckedAt: checkedAt},
+	})
+	require.NoError(t, err)
+
+	drifts, err := db.List(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, drifts, 2)
+	assert.Equal(t, "refs/heads/feature", drifts[0].RefName)
+	assert.Equal(t, gitutil.RefDriftMissing, drifts[0].Kind())
+	assert.Equal(t, "refs/tags/v1.0", drifts[1].RefName)
+	assert.Equal(t, gitutil.RefDriftDiverged, drifts[1].Kind())
+
+	// Replacing with nothing removes all drifts of the repository only.
+	err = db.Replace(ctx, 1, nil)
+	require.NoError(t, err)
+
+	drifts, err = db.List(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, drifts)
+
+	drifts, err = db.List(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, drifts, 1)
+	assert.Equal(t, gitutil.RefDriftExtra, drifts[0].Kind())
+}
+
+func TestFilterRefDrifts(t *testing.T) {
+	checkedAt := time.Unix(1588568886, 0)
+	drifts := []*gitutil.RefDrift{
+		{RefName: "refs/heads/feature", UpstreamCommitID: "a"},
+		{RefName: "refs/heads/old", LocalCommitID: "b"},
+	}
+
+	got := filterRefDrifts(drifts, true, checkedAt)
+	assert.Equal(t, []*MirrorRefDrift{
+		{RefName: "refs/heads/feature", UpstreamCommitID: "a", CheckedAt: checkedAt},
+		{RefName: "refs/heads/old", LocalCommitID: "b", CheckedAt: checkedAt},
+	}, got)
+
+	// Local-only references are expected without pruning
+	got = filterRefDrifts(drifts, false, checkedAt)
+	assert.Equal(t, []*MirrorRefDrift{
+		{RefName: "refs/heads/feature", UpstreamCommitID: "a", CheckedAt: checkedAt},
+	}, got)
+}
@@ -48,6 +48,14 @@ func SetMockPermsStore(t *testing.T, mock PermsStore) {
 	})
 }
 
+func setMockProfileFieldsStore(t *testing.T, mock ProfileFieldsStore) {
+	before := ProfileFields
+	ProfileFields = mock
+	t.Cleanup(func() {
+		ProfileFields = before
+	})
+}
+
 func SetMockReposStore(t *testing.T, mock ReposStore) {
 	before := Repos
 	Repos = mock
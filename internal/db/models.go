@@ -67,47 +67,58 @@ func init() {
 }
 
 func getEngine() (*xorm.Engine, error) {
+	switch conf.Database.Type {
+	case "mysql":
+		conf.UseMySQL = true
+	case "postgres":
+		conf.UsePostgreSQL = true
+	case "mssql":
+		conf.UseMSSQL = true
+	case "sqlite3":
+		conf.UseSQLite3 = true
+	}
+	return newEngine(conf.Database)
+}
+
+// newEngine returns a new XORM engine for the database of given options.
+func newEngine(opts conf.DatabaseOpts) (*xorm.Engine, error) {
 	Param := "?"
-	if strings.Contains(conf.Database.Name, Param) {
+	if strings.Contains(opts.Name, Param) {
 		Param = "&"
 	}
 
-	driver := conf.Database.Type
+	driver := opts.Type
 	connStr := ""
-	switch conf.Database.Type {
+	switch opts.Type {
 	case "mysql":
-		conf.UseMySQL = true
-		if conf.Database.Host[0] == '/' { // looks like a unix socket
+		if opts.Host[0] == '/' { // looks like a unix socket
 			connStr = fmt.Sprintf("%s:%s@unix(%s)/%s%scharset=utf8mb4&parseTime=true",
-				conf.Database.User, conf.Database.Password, conf.Database.Host, conf.Database.Name, Param)
+				opts.User, opts.Password, opts.Host, opts.Name, Param)
 		} else {
 			connStr = fmt.Sprintf("%s:%s@tcp(%s)/%s%scharset=utf8mb4&parseTime=true",
-				conf.Database.User, conf.Database.Password, conf.Database.Host, conf.Database.Name, Param)
+				opts.User, opts.Password, opts.Host, opts.Name, Param)
 		}
 		engineParams := map[string]string{"rowFormat": "DYNAMIC"}
-		return xorm.NewEngineWithParams(conf.Database.Type, connStr, engineParams)
+		return xorm.NewEngineWithParams(opts.Type, connStr, engineParams)
 
 	case "postgres":
-		conf.UsePostgreSQL = true
-		host, port := dbutil.ParsePostgreSQLHostPort(conf.Database.Host)
+		host, port := dbutil.ParsePostgreSQLHostPort(opts.Host)
 		connStr = fmt.Sprintf("user='%s' password='%s' host='%s' port='%s' dbname='%s' sslmode='%s' search_path='%s'",
-			conf.Database.User, conf.Database.Password, host, port, conf.Database.Name, conf.Database.SSLMode, conf.Database.Schema)
+			opts.User, opts.Password, host, port, opts.Name, opts.SSLMode, opts.Schema)
 		driver = "pgx"
 
 	case "mssql":
-		conf.UseMSSQL = true
-		host, port := dbutil.ParseMSSQLHostPort(conf.Database.Host)
-		connStr = fmt.Sprintf("server=%s; port=%s; database=%s; user id=%s; password=%s;", host, port, conf.Database.Name, conf.Database.User, conf.Database.Password)
+		host, port := dbutil.ParseMSSQLHostPort(opts.Host)
+		connStr = fmt.Sprintf("server=%s; port=%s; database=%s; user id=%s; password=%s;", host, port, opts.Name, opts.User, opts.Password)
 
 	case "sqlite3":
-		if err := os.MkdirAll(path.Dir(conf.Database.Path), os.ModePerm); err != nil {
+		if err := os.MkdirAll(path.Dir(opts.Path), os.ModePerm); err != nil {
 			return nil, fmt.Errorf("create directories: %v", err)
 		}
-		conf.UseSQLite3 = true
-		connStr = "file:" + conf.Database.Path + "?cache=shared&mode=rwc"
+		connStr = "file:" + opts.Path + "?cache=shared&mode=rwc"
 
 	default:
-		return nil, fmt.Errorf("unknown database type: %s", conf.Database.Type)
+		return nil, fmt.Errorf("unknown database type: %s", opts.Type)
 	}
 	return xorm.NewEngine(driver, connStr)
 }
@@ -187,6 +198,12 @@ func NewEngine() (err error) {
 		return err
 	}
 
+	// Check the version beforehand to report unsupported database schema as an
+	// error, instead of exiting in the middle of migrations.
+	if err = migrations.CheckVersion(db); err != nil {
+		return err
+	}
+
 	if err = migrations.Migrate(db); err != nil {
 		return fmt.Errorf("migrate: %v", err)
 	}
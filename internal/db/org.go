@@ -5,6 +5,7 @@
 package db
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -220,6 +221,10 @@ func DeleteOrganization(org *User) (err error) {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
 
+	if _, err = sess.Exec("DELETE FROM team_discussion WHERE org_id = ?", org.ID); err != nil {
+		return fmt.Errorf("delete team discussions: %v", err)
+	}
+
 	if err = deleteUser(sess, org); err != nil {
 		return fmt.Errorf("deleteUser: %v", err)
 	}
@@ -343,12 +348,45 @@ func ChangeOrgUserStatus(orgID, uid int64, public bool) error {
 	return err
 }
 
-// AddOrgUser adds new user to given organization.
+type ErrOrgMemberDomainNotVerified struct {
+	args errutil.Args
+}
+
+func IsErrOrgMemberDomainNotVerified(err error) bool {
+	_, ok := err.(ErrOrgMemberDomainNotVerified)
+	return ok
+}
+
+func (err ErrOrgMemberDomainNotVerified) Error() string {
+	return fmt.Sprintf("user does not have an email address under verified domains of the organization: %v", err.args)
+}
+
+// AddOrgUser adds new user to given organization. It returns
+// ErrOrgMemberDomainNotVerified if the organization restricts members to
+// verified domains and the user has no activated email address under them,
+// or ErrOrgTwoFactorNotEnabled if the organization requires two-factor
+// authentication and the user has not enabled it.
 func AddOrgUser(orgID, uid int64) error {
 	if IsOrganizationMember(orgID, uid) {
 		return nil
 	}
 
+	org, err := GetUserByID(orgID)
+	if err != nil {
+		return fmt.Errorf("get organization: %v", err)
+	}
+	if org.RestrictMembersToVerifiedDomains {
+		ok, err := OrgDomains.HasVerifiedEmail(context.TODO(), orgID, uid)
+		if err != nil {
+			return fmt.Errorf("check verified email: %v", err)
+		} else if !ok {
+			return ErrOrgMemberDomainNotVerified{args: errutil.Args{"orgID": orgID, "userID": uid}}
+		}
+	}
+	if org.RequireTwoFactor && !TwoFactors.IsUserEnabled(context.TODO(), uid) {
+		return ErrOrgTwoFactorNotEnabled{args: errutil.Args{"orgID": orgID, "userID": uid}}
+	}
+
 	sess := x.NewSession()
 	defer sess.Close()
 	if err := sess.Begin(); err != nil {
@@ -517,6 +555,7 @@ func (org *User) GetUserRepositories(userID int64, page, pageSize int) ([]*Repos
 		And(builder.Or(
 			builder.And(builder.Expr("is_private = ?", false), builder.Expr("is_unlisted = ?", false)),
 			builder.In("id", teamRepoIDs))).
+		And("trashed_unix = 0").
 		Desc("updated_unix").
 		Limit(pageSize, (page-1)*pageSize).
 		Find(&repos); err != nil {
@@ -527,6 +566,7 @@ func (org *User) GetUserRepositories(userID int64, page, pageSize int) ([]*Repos
 		And(builder.Or(
 			builder.Expr("is_private = ?", false),
 			builder.In("id", teamRepoIDs))).
+		And("trashed_unix = 0").
 		Count(new(Repository))
 	if err != nil {
 		return nil, 0, fmt.Errorf("count user repositories: %v", err)
@@ -560,6 +600,7 @@ func (org *User) GetUserMirrorRepositories(userID int64) ([]*Repository, error)
 		And("is_private = ?", false).
 		Or(builder.In("id", teamRepoIDs)).
 		And("is_mirror = ?", true). // Don't move up because it's an independent condition
+		And("trashed_unix = 0").
 		Desc("updated_unix").
 		Find(&repos); err != nil {
 		return nil, fmt.Errorf("get user repositories: %v", err)
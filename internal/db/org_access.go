@@ -0,0 +1,219 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// OrgAccessSourceType is the type of source that grants a user access to a
+// repository of an organization.
+type OrgAccessSourceType string
+
+const (
+	OrgAccessSourceOwnership     OrgAccessSourceType = "ownership"
+	OrgAccessSourceTeam          OrgAccessSourceType = "team"
+	OrgAccessSourceCollaboration OrgAccessSourceType = "collaboration"
+)
+
+// OrgAccessSource is a single source that grants a user access to a
+// repository of an organization.
+type OrgAccessSource struct {
+	Type OrgAccessSourceType
+	// Name is the name of the team when the type is OrgAccessSourceTeam or
+	// OrgAccessSourceOwnership, and empty otherwise.
+	Name string
+	Mode AccessMode
+}
+
+func (s OrgAccessSource) String() string {
+	if s.Name == "" {
+		return fmt.Sprintf("%s (%s)", s.Type, s.Mode)
+	}
+	return fmt.Sprintf("%s:%s (%s)", s.Type, s.Name, s.Mode)
+}
+
+// OrgAccessEntry is the effective access of a user to a repository of an
+// organization, along with all sources that grant the access.
+type OrgAccessEntry struct {
+	User    *User
+	Repo    *Repository
+	Mode    AccessMode
+	Sources []OrgAccessSource
+}
+
+// GetOrgAccessReport returns effective access of every user to every
+// repository of the organization, ordered by user name and then repository
+// name. Users who are not granted any access are not included.
+func GetOrgAccessReport(orgID int64) ([]*OrgAccessEntry, error) {
+	repos := make([]*Repository, 0, 10)
+	if err := x.Where("owner_id = ?", orgID).Find(&repos); err != nil {
+		return nil, fmt.Errorf("list repositories: %v", err)
+	}
+	if len(repos) == 0 {
+		return []*OrgAccessEntry{}, nil
+	}
+	repoIDs := make([]int64, len(repos))
+	for i := range repos {
+		repoIDs[i] = repos[i].ID
+	}
+
+	teams, err := GetTeamsByOrgID(orgID)
+	if err != nil {
+		return nil, fmt.Errorf("list teams: %v", err)
+	}
+
+	teamRepos := make([]*TeamRepo, 0, 10)
+	if err = x.Where("org_id = ?", orgID).Find(&teamRepos); err != nil {
+		return nil, fmt.Errorf("list team repositories: %v", err)
+	}
+
+	teamUsers := make([]*TeamUser, 0, 10)
+	if err = x.Where("org_id = ?", orgID).Find(&teamUsers); err != nil {
+		return nil, fmt.Errorf("list team members: %v", err)
+	}
+
+	collaborations := make([]*Collaboration, 0, 10)
+	if err = x.In("repo_id", repoIDs).Find(&collaborations); err != nil {
+		return nil, fmt.Errorf("list collaborations: %v", err)
+	}
+
+	userIDs := make([]int64, 0, len(teamUsers)+len(collaborations))
+	for _, tu := range teamUsers {
+		userIDs = append(userIDs, tu.UID)
+	}
+	for _, c := range collaborations {
+		userIDs = append(userIDs, c.UserID)
+	}
+	users := make([]*User, 0, len(userIDs))
+	if len(userIDs) > 0 {
+		if err = x.In("id", userIDs).Find(&users); err != nil {
+			return nil, fmt.Errorf("list users: %v", err)
+		}
+	}
+
+	return buildOrgAccessReport(repos, teams, teamRepos, teamUsers, collaborations, users), nil
+}
+
+// buildOrgAccessReport aggregates relations of an organization into effective
+// access per user per repository.
+func buildOrgAccessReport(
+	repos []*Repository,
+	teams []*Team,
+	teamRepos []*TeamRepo,
+	teamUsers []*TeamUser,
+	collaborations []*Collaboration,
+	users []*User,
+) []*OrgAccessEntry {
+	repoByID := make(map[int64]*Repository, len(repos))
+	for _, r := range repos {
+		repoByID[r.ID] = r
+	}
+	userByID := make(map[int64]*User, len(users))
+	for _, u := range users {
+		userByID[u.ID] = u
+	}
+	teamByID := make(map[int64]*Team, len(teams))
+	for _, t := range teams {
+		teamByID[t.ID] = t
+	}
+	reposByTeam := make(map[int64][]int64)
+	for _, tr := range teamRepos {
+		reposByTeam[tr.TeamID] = append(reposByTeam[tr.TeamID], tr.RepoID)
+	}
+
+	type key struct {
+		userID int64
+		repoID int64
+	}
+	entries := make(map[key]*OrgAccessEntry)
+	grant := func(userID, repoID int64, source OrgAccessSource) {
+		u, r := userByID[userID], repoByID[repoID]
+		if u == nil || r == nil {
+			return
+		}
+
+		k := key{userID: userID, repoID: repoID}
+		e := entries[k]
+		if e == nil {
+			e = &OrgAccessEntry{User: u, Repo: r}
+			entries[k] = e
+		}
+		if source.Mode > e.Mode {
+			e.Mode = source.Mode
+		}
+		e.Sources = append(e.Sources, source)
+	}
+
+	for _, tu := range teamUsers {
+		t := teamByID[tu.TeamID]
+		if t == nil {
+			continue
+		}
+
+		if t.IsOwnerTeam() {
+			// The owner team has owner access to all repositories regardless of
+			// team-repository relations.
+			for _, r := range repos {
+				grant(tu.UID, r.ID, OrgAccessSource{Type: OrgAccessSourceOwnership, Name: t.Name, Mode: AccessModeOwner})
+			}
+			continue
+		}
+
+		for _, repoID := range reposByTeam[t.ID] {
+			grant(tu.UID, repoID, OrgAccessSource{Type: OrgAccessSourceTeam, Name: t.Name, Mode: t.Authorize})
+		}
+	}
+
+	for _, c := range collaborations {
+		grant(c.UserID, c.RepoID, OrgAccessSource{Type: OrgAccessSourceCollaboration, Mode: c.Mode})
+	}
+
+	report := make([]*OrgAccessEntry, 0, len(entries))
+	for _, e := range entries {
+		report = append(report, e)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].User.LowerName != report[j].User.LowerName {
+			return report[i].User.LowerName < report[j].User.LowerName
+		}
+		return report[i].Repo.LowerName < report[j].Repo.LowerName
+	})
+	return report
+}
+
+// WriteOrgAccessReportCSV writes the access report in CSV format, with a header
+// row followed by one row per entry.
+func WriteOrgAccessReportCSV(w io.Writer, report []*OrgAccessEntry) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"user", "full_name", "email", "repository", "private", "access", "sources"})
+	if err != nil {
+		return err
+	}
+	for _, e := range report {
+		sources := make([]string, len(e.Sources))
+		for i := range e.Sources {
+			sources[i] = e.Sources[i].String()
+		}
+		err = cw.Write([]string{
+			e.User.Name,
+			e.User.FullName,
+			e.User.Email,
+			e.Repo.Name,
+			fmt.Sprintf("%t", e.Repo.IsPrivate),
+			e.Mode.String(),
+			strings.Join(sources, "; "),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildOrgAccessReport(t *testing.T) {
+	repos := []*Repository{
+		{ID: 1, LowerName: "web"},
+		{ID: 2, LowerName: "api"},
+	}
+	teams := []*Team{
+		{ID: 1, Name: OWNER_TEAM, Authorize: AccessModeOwner},
+		{ID: 2, Name: "Readers", Authorize: AccessModeRead},
+		{ID: 3, Name: "Empty", Authorize: AccessModeAdmin},
+	}
+	teamRepos := []*TeamRepo{
+		{TeamID: 2, RepoID: 2},
+	}
+	teamUsers := []*TeamUser{
+		{TeamID: 1, UID: 1},
+		{TeamID: 2, UID: 2},
+		{TeamID: 3, UID: 3},
+	}
+	collaborations := []*Collaboration{
+		{RepoID: 2, UserID: 2, Mode: AccessModeWrite},
+		{RepoID: 1, UserID: 4, Mode: AccessModeRead},
+		// Dangling relations are ignored.
+		{RepoID: 3, UserID: 4, Mode: AccessModeRead},
+		{RepoID: 1, UserID: 5, Mode: AccessModeRead},
+	}
+	alice := &User{ID: 1, LowerName: "alice"}
+	bob := &User{ID: 2, LowerName: "bob"}
+	cindy := &User{ID: 3, LowerName: "cindy"}
+	dan := &User{ID: 4, LowerName: "dan"}
+	users := []*User{dan, cindy, bob, alice}
+
+	got := buildOrgAccessReport(repos, teams, teamRepos, teamUsers, collaborations, users)
+	want := []*OrgAccessEntry{
+		{
+			User: alice, Repo: repos[1], Mode: AccessModeOwner,
+			Sources: []OrgAccessSource{{Type: OrgAccessSourceOwnership, Name: OWNER_TEAM, Mode: AccessModeOwner}},
+		},
+		{
+			User: alice, Repo: repos[0], Mode: AccessModeOwner,
+			Sources: []OrgAccessSource{{Type: OrgAccessSourceOwnership, Name: OWNER_TEAM, Mode: AccessModeOwner}},
+		},
+		{
+			User: bob, Repo: repos[1], Mode: AccessModeWrite,
+			Sources: []OrgAccessSource{
+				{Type: OrgAccessSourceTeam, Name: "Readers", Mode: AccessModeRead},
+				{Type: OrgAccessSourceCollaboration, Mode: AccessModeWrite},
+			},
+		},
+		{
+			User: dan, Repo: repos[0], Mode: AccessModeRead,
+			Sources: []OrgAccessSource{{Type: OrgAccessSourceCollaboration, Mode: AccessModeRead}},
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestOrgAccessSource_String(t *testing.T) {
+	assert.Equal(t, "team:Readers (read)", OrgAccessSource{Type: OrgAccessSourceTeam, Name: "Readers", Mode: AccessModeRead}.String())
+	assert.Equal(t, "collaboration (write)", OrgAccessSource{Type: OrgAccessSourceCollaboration, Mode: AccessModeWrite}.String())
+}
+
+func TestWriteOrgAccessReportCSV(t *testing.T) {
+	report := []*OrgAccessEntry{
+		{
+			User: &User{Name: "bob", FullName: "Bob, Jr.", Email: "bob@example.com"},
+			Repo: &Repository{Name: "api", IsPrivate: true},
+			Mode: AccessModeWrite,
+			Sources: []OrgAccessSource{
+				{Type: OrgAccessSourceTeam, Name: "Readers", Mode: AccessModeRead},
+				{Type: OrgAccessSourceCollaboration, Mode: AccessModeWrite},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := WriteOrgAccessReportCSV(&buf, report)
+	assert.NoError(t, err)
+
+	want := `user,full_name,email,repository,private,access,sources
+bob,"Bob, Jr.",bob@example.com,api,true,write,team:Readers (read); collaboration (write)
+`
+	assert.Equal(t, want, buf.String())
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gogs.io/gogs/internal/errutil"
+)
+
+// MaxOrgAnnouncementLength is the maximum number of characters of an
+// announcement of an organization.
+const MaxOrgAnnouncementLength = 4096
+
+// AnnouncementUpdated returns the time when the announcement of the
+// organization was last changed.
+func (org *User) AnnouncementUpdated() time.Time {
+	return time.Unix(org.AnnouncementUpdatedUnix, 0).Local()
+}
+
+type ErrOrgAnnouncementTooLong struct {
+	args errutil.Args
+}
+
+func IsErrOrgAnnouncementTooLong(err error) bool {
+	_, ok := err.(ErrOrgAnnouncementTooLong)
+	return ok
+}
+
+func (err ErrOrgAnnouncementTooLong) Error() string {
+	return fmt.Sprintf("announcement is too long: %v", err.args)
+}
+
+// SetOrgAnnouncement changes the announcement of the organization, an empty
+// content removes the announcement. It returns ErrOrgAnnouncementTooLong when
+// the content exceeds MaxOrgAnnouncementLength characters.
+func SetOrgAnnouncement(org *User, content string) error {
+	content = strings.TrimSpace(content)
+	if n := utf8.RuneCountInString(content); n > MaxOrgAnnouncementLength {
+		return ErrOrgAnnouncementTooLong{args: errutil.Args{"orgID": org.ID, "length": n}}
+	}
+	if content == org.Announcement {
+		return nil
+	}
+
+	org.Announcement = content
+	if content == "" {
+		org.AnnouncementUpdatedUnix = 0
+	} else {
+		org.AnnouncementUpdatedUnix = time.Now().Unix()
+	}
+	return UpdateUser(org)
+}
+
+// OrgsWithAnnouncement returns organizations in the list that have an
+// announcement, ordered by the most recently updated.
+func OrgsWithAnnouncement(orgs []*User) []*User {
+	announced := make([]*User, 0, len(orgs))
+	for _, org := range orgs {
+		if org.Announcement != "" {
+			announced = append(announced, org)
+		}
+	}
+	sort.SliceStable(announced, func(i, j int) bool {
+		return announced[i].AnnouncementUpdatedUnix > announced[j].AnnouncementUpdatedUnix
+	})
+	return announced
+}
@@ -0,0 +1,37 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrgsWithAnnouncement(t *testing.T) {
+	orgs := []*User{
+		{Name: "org1", Announcement: "Freeze", AnnouncementUpdatedUnix: 100},
+		{Name: "org2"},
+		{Name: "org3", Announcement: "Welcome", AnnouncementUpdatedUnix: 300},
+		{Name: "org4", Announcement: "Onboarding", AnnouncementUpdatedUnix: 200},
+	}
+
+	got := OrgsWithAnnouncement(orgs)
+	names := make([]string, len(got))
+	for i := range got {
+		names[i] = got[i].Name
+	}
+	assert.Equal(t, []string{"org3", "org4", "org1"}, names)
+
+	assert.Empty(t, OrgsWithAnnouncement(nil))
+}
+
+func TestSetOrgAnnouncement_TooLong(t *testing.T) {
+	org := &User{ID: 1}
+	err := SetOrgAnnouncement(org, strings.Repeat("界", MaxOrgAnnouncementLength+1))
+	assert.True(t, IsErrOrgAnnouncementTooLong(err))
+	assert.Empty(t, org.Announcement)
+}
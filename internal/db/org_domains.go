@@ -0,0 +1,222 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	gouuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/cryptoutil"
+	"gogs.io/gogs/internal/errutil"
+)
+
+// OrgDomainsStore is the persistent interface for domains of organizations.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type OrgDomainsStore interface {
+	// Create creates a new unverified domain for the organization with a random
+	// verification token. It returns ErrOrgDomainAlreadyExist when the domain
+	// already exists for the organization.
+	Create(ctx context.Context, orgID int64, domain string) (*OrgDomain, error)
+	// DeleteByID deletes the domain with given ID of the organization.
+	DeleteByID(ctx context.Context, orgID, id int64) error
+	// GetByID returns the domain with given ID of the organization. It returns
+	// ErrOrgDomainNotExist when not found.
+	GetByID(ctx context.Context, orgID, id int64) (*OrgDomain, error)
+	// HasVerifiedEmail returns true if the user has an activated email address
+	// under any of verified domains (including their subdomains) of the
+	// organization.
+	HasVerifiedEmail(ctx context.Context, orgID, userID int64) (bool, error)
+	// List returns all domains of the organization, ordered by domain name.
+	List(ctx context.Context, orgID int64) ([]*OrgDomain, error)
+	// MarkVerified marks the domain with given ID of the organization as
+	// verified.
+	MarkVerified(ctx context.Context, orgID, id int64) error
+}
+
+var OrgDomains OrgDomainsStore
+
+// OrgDomain is a domain claimed by an organization, which is verified via a DNS
+// TXT record.
+type OrgDomain struct {
+	ID         int64     `gorm:"primaryKey"`
+	OrgID      int64     `gorm:"uniqueIndex:org_domain_org_domain_unique;not null"`
+	Domain     string    `gorm:"uniqueIndex:org_domain_org_domain_unique;not null"`
+	Token      string    `gorm:"type:VARCHAR(40);not null"`
+	IsVerified bool      `gorm:"not null"`
+	CreatedAt  time.Time `gorm:"not null"`
+}
+
+// OrgDomainTXTRecordPrefix is the prefix of the subdomain for the DNS TXT
+// record to verify a domain.
+const OrgDomainTXTRecordPrefix = "_gogs-challenge."
+
+// TXTRecordName returns the name of the DNS TXT record to verify the domain.
+func (d *OrgDomain) TXTRecordName() string {
+	return OrgDomainTXTRecordPrefix + d.Domain
+}
+
+// TXTRecordValue returns the expected value of the DNS TXT record to verify
+// the domain.
+func (d *OrgDomain) TXTRecordValue() string {
+	return "gogs-verification=" + d.Token
+}
+
+// NormalizeDomain returns the canonical form of a domain, i.e. lower cased and
+// without the trailing dot.
+func NormalizeDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
+
+// emailUnderDomain returns true if the email address is under the domain or its
+// subdomains.
+func emailUnderDomain(email, domain string) bool {
+	i := strings.LastIndex(email, "@")
+	if i < 0 {
+		return false
+	}
+	host := strings.ToLower(email[i+1:])
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+var _ OrgDomainsStore = (*orgDomains)(nil)
+
+type orgDomains struct {
+	*gorm.DB
+}
+
+// NewOrgDomainsStore returns a persistent interface for domains of
+// organizations with given database connection.
+func NewOrgDomainsStore(db *gorm.DB) OrgDomainsStore {
+	return &orgDomains{DB: db}
+}
+
+type ErrOrgDomainAlreadyExist struct {
+	args errutil.Args
+}
+
+func IsErrOrgDomainAlreadyExist(err error) bool {
+	_, ok := err.(ErrOrgDomainAlreadyExist)
+	return ok
+}
+
+func (err ErrOrgDomainAlreadyExist) Error() string {
+	return fmt.Sprintf("organization domain already exists: %v", err.args)
+}
+
+func (db *orgDomains) Create(ctx context.Context, orgID int64, domain string) (*OrgDomain, error) {
+	domain = NormalizeDomain(domain)
+	err := db.WithContext(ctx).Where("org_id = ? AND domain = ?", orgID, domain).First(new(OrgDomain)).Error
+	if err == nil {
+		return nil, ErrOrgDomainAlreadyExist{args: errutil.Args{"orgID": orgID, "domain": domain}}
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, errors.Wrap(err, "check existence")
+	}
+
+	d := &OrgDomain{
+		OrgID:  orgID,
+		Domain: domain,
+		Token:  cryptoutil.SHA1(gouuid.NewV4().String()),
+	}
+	return d, db.WithContext(ctx).Create(d).Error
+}
+
+func (db *orgDomains) DeleteByID(ctx context.Context, orgID, id int64) error {
+	return db.WithContext(ctx).Where("id = ? AND org_id = ?", id, orgID).Delete(new(OrgDomain)).Error
+}
+
+var _ errutil.NotFound = (*ErrOrgDomainNotExist)(nil)
+
+type ErrOrgDomainNotExist struct {
+	args errutil.Args
+}
+
+func IsErrOrgDomainNotExist(err error) bool {
+	_, ok := err.(ErrOrgDomainNotExist)
+	return ok
+}
+
+func (err ErrOrgDomainNotExist) Error() string {
+	return fmt.Sprintf("organization domain does not exist: %v", err.args)
+}
+
+func (ErrOrgDomainNotExist) NotFound() bool {
+	return true
+}
+
+func (db *orgDomains) GetByID(ctx context.Context, orgID, id int64) (*OrgDomain, error) {
+	d := new(OrgDomain)
+	err := db.WithContext(ctx).Where("id = ? AND org_id = ?", id, orgID).First(d).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrOrgDomainNotExist{args: errutil.Args{"orgID": orgID, "id": id}}
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+func (db *orgDomains) HasVerifiedEmail(ctx context.Context, orgID, userID int64) (bool, error) {
+	var domains []string
+	err := db.WithContext(ctx).
+		Model(new(OrgDomain)).
+		Where("org_id = ? AND is_verified = ?", orgID, true).
+		Pluck("domain", &domains).
+		Error
+	if err != nil {
+		return false, errors.Wrap(err, "list verified domains")
+	} else if len(domains) == 0 {
+		return false, nil
+	}
+
+	var emails []string
+	err = db.WithContext(ctx).
+		Model(new(User)).
+		Where("id = ? AND is_active = ?", userID, true).
+		Pluck("email", &emails).
+		Error
+	if err != nil {
+		return false, errors.Wrap(err, "get primary email")
+	}
+
+	var secondaryEmails []string
+	err = db.WithContext(ctx).
+		Model(new(EmailAddress)).
+		Where("uid = ? AND is_activated = ?", userID, true).
+		Pluck("email", &secondaryEmails).
+		Error
+	if err != nil {
+		return false, errors.Wrap(err, "list activated email addresses")
+	}
+	emails = append(emails, secondaryEmails...)
+
+	for _, email := range emails {
+		for _, domain := range domains {
+			if emailUnderDomain(email, domain) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (db *orgDomains) List(ctx context.Context, orgID int64) ([]*OrgDomain, error) {
+	var domains []*OrgDomain
+	return domains, db.WithContext(ctx).Where("org_id = ?", orgID).Order("domain ASC").Find(&domains).Error
+}
+
+func (db *orgDomains) MarkVerified(ctx context.Context, orgID, id int64) error {
+	return db.WithContext(ctx).
+		Model(new(OrgDomain)).
+		Where("id = ? AND org_id = ?", id, orgID).
+		UpdateColumn("is_verified", true).
+		Error
+}
@@ -0,0 +1,197 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/errutil"
+)
+
+func TestEmailUnderDomain(t *testing.T) {
+	assert.True(t, emailUnderDomain("alice@example.com", "example.com"))
+	assert.True(t, emailUnderDomain("alice@EXAMPLE.com", "example.com"))
+	assert.True(t, emailUnderDomain("alice@eng.example.com", "example.com"))
+	assert.False(t, emailUnderDomain("alice@badexample.com", "example.com"))
+	assert.False(t, emailUnderDomain("alice@example.com.evil", "example.com"))
+	assert.False(t, emailUnderDomain("example.com", "example.com"))
+}
+
+func TestOrgDomains(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(OrgDomain), new(User), new(EmailAddress)}
+	db := &orgDomains{
+		DB: dbtest.NewDB(t, "orgDomains", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *orgDomains)
+	}{
+		{"Create", orgDomainsCreate},
+		{"DeleteByID", orgDomainsDeleteByID},
+		{"GetByID", orgDomainsGetByID},
+		{"HasVerifiedEmail", orgDomainsHasVerifiedEmail},
+		{"List", orgDomainsList},
+		{"MarkVerified", orgDomainsMarkVerified},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func orgDomainsCreate(t *testing.T, db *orgDomains) {
+	ctx := context.Background()
+
+	d, err := db.Create(ctx, 1, " Example.COM. ")
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", d.Domain)
+	assert.False(t, d.IsVerified)
+	assert.Len(t, d.Token, 40)
+	assert.Equal(t, "_gogs-challenge.example.com", d.TXTRecordName())
+	assert.Equal(t, "gogs-verification="+d.Token, d.TXTRecordValue())
+
+	_, err = db.Create(ctx, 1, "example.com")
+	wantErr := ErrOrgDomainAlreadyExist{args: errutil.Args{"orgID": int64(1), "domain": "example.com"}}
+	assert.Equal(t, wantErr, err)
+
+	// The same domain can be claimed by another organization.
+	_, err = db.Create(ctx, 2, "example.com")
+	require.NoError(t, err)
+}
+
+func orgDomainsDeleteByID(t *testing.T, db *orgDomains) {
+	ctx := context.Background()
+
+	d, err := db.Create(ctx, 1, "example.com")
+	require.NoError(t, err)
+
+	// Deleting with a wrong organization should be a no-op.
+	err = db.DeleteByID(ctx, 2, d.ID)
+	require.NoError(t, err)
+	_, err = db.GetByID(ctx, 1, d.ID)
+	require.NoError(t, err)
+
+	err = db.DeleteByID(ctx, 1, d.ID)
+	require.NoError(t, err)
+	_, err = db.GetByID(ctx, 1, d.ID)
+	wantErr := ErrOrgDomainNotExist{args: errutil.Args{"orgID": int64(1), "id": d.ID}}
+	assert.Equal(t, wantErr, err)
+}
+
+func orgDomainsGetByID(t *testing.T, db *orgDomains) {
+	ctx := context.Background()
+
+	d, err := db.Create(ctx, 1, "example.com")
+	require.NoError(t, err)
+
+	got, err := db.GetByID(ctx, 1, d.ID)
+	require.NoError(t, err)
+	assert.Equal(t, d.Token, got.Token)
+
+	_, err = db.GetByID(ctx, 2, d.ID)
+	wantErr := ErrOrgDomainNotExist{args: errutil.Args{"orgID": int64(2), "id": d.ID}}
+	assert.Equal(t, wantErr, err)
+}
+
+func orgDomainsHasVerifiedEmail(t *testing.T, db *orgDomains) {
+	ctx := context.Background()
+
+	users := NewUsersStore(db.DB)
+	alice, err := users.Create(ctx, "alice", "alice@example.com", CreateUserOptions{Activated: true})
+	require.NoError(t, err)
+	bob, err := users.Create(ctx, "bob", "bob@example.com", CreateUserOptions{})
+	require.NoError(t, err)
+	cindy, err := users.Create(ctx, "cindy", "cindy@gmail.com", CreateUserOptions{Activated: true})
+	require.NoError(t, err)
+
+	// TODO: Use UserEmails.Create to replace SQL hack when the method is available.
+	err = db.Exec(`INSERT INTO email_address (uid, email, is_activated) VALUES (?, ?, ?)`, cindy.ID, "cindy@eng.example.com", true).Error
+	require.NoError(t, err)
+
+	d, err := db.Create(ctx, 1, "example.com")
+	require.NoError(t, err)
+
+	// No verified domains yet.
+	got, err := db.HasVerifiedEmail(ctx, 1, alice.ID)
+	require.NoError(t, err)
+	assert.False(t, got)
+
+	err = db.MarkVerified(ctx, 1, d.ID)
+	require.NoError(t, err)
+
+	got, err = db.HasVerifiedEmail(ctx, 1, alice.ID)
+	require.NoError(t, err)
+	assert.True(t, got)
+
+	// Bob has not activated the primary email.
+	got, err = db.HasVerifiedEmail(ctx, 1, bob.ID)
+	require.NoError(t, err)
+	assert.False(t, got)
+
+	// Cindy has an activated secondary email under a subdomain.
+	got, err = db.HasVerifiedEmail(ctx, 1, cindy.ID)
+	require.NoError(t, err)
+	assert.True(t, got)
+
+	// Domains of other organizations are irrelevant.
+	got, err = db.HasVerifiedEmail(ctx, 2, alice.ID)
+	require.NoError(t, err)
+	assert.False(t, got)
+}
+
+func orgDomainsList(t *testing.T, db *orgDomains) {
+	ctx := context.Background()
+
+	_, err := db.Create(ctx, 1, "gogs.io")
+	require.NoError(t, err)
+	_, err = db.Create(ctx, 1, "example.com")
+	require.NoError(t, err)
+	_, err = db.Create(ctx, 2, "example.org")
+	require.NoError(t, err)
+
+	domains, err := db.List(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, domains, 2)
+	assert.Equal(t, "example.com", domains[0].Domain)
+	assert.Equal(t, "gogs.io", domains[1].Domain)
+}
+
+func orgDomainsMarkVerified(t *testing.T, db *orgDomains) {
+	ctx := context.Background()
+
+	d, err := db.Create(ctx, 1, "example.com")
+	require.NoError(t, err)
+
+	// Marking with a wrong organization should be a no-op.
+	err = db.MarkVerified(ctx, 2, d.ID)
+	require.NoError(t, err)
+	got, err := db.GetByID(ctx, 1, d.ID)
+	require.NoError(t, err)
+	assert.False(t, got.IsVerified)
+
+	err = db.MarkVerified(ctx, 1, d.ID)
+	require.NoError(t, err)
+	got, err = db.GetByID(ctx, 1, d.ID)
+	require.NoError(t, err)
+	assert.True(t, got.IsVerified)
+}
@@ -0,0 +1,299 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/gitutil"
+	"gogs.io/gogs/internal/tool"
+)
+
+// ReviewLoad is the number of review requests of pull requests created in each
+// week of a review load report.
+type ReviewLoad struct {
+	// Total is the number of review requests in all weeks.
+	Total int64
+	// Open is the number of review requests of pull requests that are still
+	// open.
+	Open int64
+	// Weekly is the number of review requests in each week, in the same order
+	// as OrgReviewLoadReport.Weeks.
+	Weekly []int64
+}
+
+// UserReviewLoad is the review load of a user.
+type UserReviewLoad struct {
+	User *User
+	ReviewLoad
+}
+
+// TeamReviewLoad is the review load of a team, where each pull request is
+// counted once if any member of the team is requested to review it.
+type TeamReviewLoad struct {
+	Team *Team
+	ReviewLoad
+}
+
+// OrgReviewLoadReport is the review load of users and teams of an organization
+// by pull requests of its repositories.
+type OrgReviewLoadReport struct {
+	// Weeks are the start times of weeks in the report in ascending order. Weeks
+	// start on Monday in UTC.
+	Weeks []time.Time
+	Users []*UserReviewLoad
+	Teams []*TeamReviewLoad
+}
+
+// reviewRequest is a pull request with requested reviewers.
+type reviewRequest struct {
+	ReviewerIDs string `xorm:"reviewer_ids"`
+	CreatedUnix int64  `xorm:"created_unix"`
+	IsClosed    bool   `xorm:"is_closed"`
+}
+
+// reviewLoadWeeks returns the start times of the weeks up to the week of now.
+func reviewLoadWeeks(now time.Time, weeks int) []time.Time {
+	now = now.UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	start = start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+
+	starts := make([]time.Time, weeks)
+	for i := range starts {
+		starts[i] = start.AddDate(0, 0, -7*(weeks-1-i))
+	}
+	return starts
+}
+
+// reviewLoadWeek returns the index of the week of given time, or -1 if it is
+// before the first week.
+func reviewLoadWeek(weeks []time.Time, t time.Time) int {
+	for i := len(weeks) - 1; i >= 0; i-- {
+		if !t.Before(weeks[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// buildReviewLoads counts review requests of each user and team. Teams are
+// given by IDs of their members.
+func buildReviewLoads(requests []*reviewRequest, weeks []time.Time, teamMembers map[int64][]int64) (users, teams map[int64]*ReviewLoad) {
+	users = make(map[int64]*ReviewLoad)
+	teams = make(map[int64]*ReviewLoad)
+	count := func(loads map[int64]*ReviewLoad, id int64, week int, open bool) {
+		load, ok := loads[id]
+		if !ok {
+			load = &ReviewLoad{Weekly: make([]int64, len(weeks))}
+			loads[id] = load
+		}
+		load.Total++
+		load.Weekly[week]++
+		if open {
+			load.Open++
+		}
+	}
+
+	for _, r := range requests {
+		week := reviewLoadWeek(weeks, time.Unix(r.CreatedUnix, 0))
+		if week < 0 || r.ReviewerIDs == "" {
+			continue
+		}
+
+		reviewers := make(map[int64]bool)
+		for _, id := range tool.StringsToInt64s(strings.Split(r.ReviewerIDs, ",")) {
+			if id <= 0 || reviewers[id] {
+				continue
+			}
+			reviewers[id] = true
+			count(users, id, week, !r.IsClosed)
+		}
+
+		for teamID, members := range teamMembers {
+			for _, id := range members {
+				if reviewers[id] {
+					count(teams, teamID, week, !r.IsClosed)
+					break
+				}
+			}
+		}
+	}
+	return users, teams
+}
+
+// GetOrgReviewLoadReport returns the review load of users and teams of the
+// organization by pull requests of its repositories created in the past weeks,
+// including the current week. Users and teams are ordered by the number of open
+// review requests and then the total number in descending order.
+func GetOrgReviewLoadReport(orgID int64, weeks int, now time.Time) (*OrgReviewLoadReport, error) {
+	report := &OrgReviewLoadReport{
+		Weeks: reviewLoadWeeks(now, weeks),
+	}
+
+	requests := make([]*reviewRequest, 0, 10)
+	err := x.Table("pull_request").
+		Select("pull_request.reviewer_ids, issue.created_unix, issue.is_closed").
+		Join("INNER", "issue", "issue.id = pull_request.issue_id").
+		Join("INNER", "repository", "repository.id = pull_request.base_repo_id").
+		Where("repository.owner_id = ?", orgID).
+		And("issue.created_unix >= ?", report.Weeks[0].Unix()).
+		Find(&requests)
+	if err != nil {
+		return nil, fmt.Errorf("list pull requests: %v", err)
+	}
+
+	teams, err := GetTeamsByOrgID(orgID)
+	if err != nil {
+		return nil, fmt.Errorf("list teams: %v", err)
+	}
+	teamUsers := make([]*TeamUser, 0, 10)
+	if err = x.Where("org_id = ?", orgID).Find(&teamUsers); err != nil {
+		return nil, fmt.Errorf("list team users: %v", err)
+	}
+	teamMembers := make(map[int64][]int64, len(teams))
+	for _, tu := range teamUsers {
+		teamMembers[tu.TeamID] = append(teamMembers[tu.TeamID], tu.UID)
+	}
+
+	userLoads, teamLoads := buildReviewLoads(requests, report.Weeks, teamMembers)
+
+	userIDs := make([]int64, 0, len(userLoads))
+	for id := range userLoads {
+		userIDs = append(userIDs, id)
+	}
+	users := make([]*User, 0, len(userIDs))
+	if len(userIDs) > 0 {
+		if err = x.In("id", userIDs).Find(&users); err != nil {
+			return nil, fmt.Errorf("list users: %v", err)
+		}
+	}
+	for _, u := range users {
+		report.Users = append(report.Users, &UserReviewLoad{User: u, ReviewLoad: *userLoads[u.ID]})
+	}
+	sort.Slice(report.Users, func(i, j int) bool {
+		a, b := report.Users[i], report.Users[j]
+		if a.Open != b.Open {
+			return a.Open > b.Open
+		} else if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.User.LowerName < b.User.LowerName
+	})
+
+	for _, t := range teams {
+		load, ok := teamLoads[t.ID]
+		if !ok {
+			load = &ReviewLoad{Weekly: make([]int64, len(report.Weeks))}
+		}
+		report.Teams = append(report.Teams, &TeamReviewLoad{Team: t, ReviewLoad: *load})
+	}
+	sort.SliceStable(report.Teams, func(i, j int) bool {
+		a, b := report.Teams[i], report.Teams[j]
+		if a.Open != b.Open {
+			return a.Open > b.Open
+		}
+		return a.Total > b.Total
+	})
+	return report, nil
+}
+
+// CodeownersCoverage is the fraction of files owned by code owners on the
+// default branch of a repository.
+type CodeownersCoverage struct {
+	Repo *Repository
+	// HasCodeowners is true if the repository has a CODEOWNERS file, see
+	// gitutil.CodeownersPaths for the lookup order.
+	HasCodeowners bool
+	NumFiles      int
+	NumOwnedFiles int
+}
+
+// Percent returns the percentage of owned files rounded down.
+func (c *CodeownersCoverage) Percent() int {
+	if c.NumFiles == 0 {
+		return 0
+	}
+	return c.NumOwnedFiles * 100 / c.NumFiles
+}
+
+// countOwnedFiles returns the number of files that have owners.
+func countOwnedFiles(codeowners *gitutil.Codeowners, files []string) int {
+	owned := 0
+	for _, f := range files {
+		if len(codeowners.Owners(f)) > 0 {
+			owned++
+		}
+	}
+	return owned
+}
+
+// loadCodeownersCoverage counts files on the default branch of the repository
+// and files owned by code owners.
+func loadCodeownersCoverage(repo *Repository) (*CodeownersCoverage, error) {
+	coverage := &CodeownersCoverage{Repo: repo}
+	if repo.IsBare {
+		return coverage, nil
+	}
+
+	gitRepo, err := git.Open(repo.RepoPath())
+	if err != nil {
+		return nil, fmt.Errorf("open repository: %v", err)
+	} else if !gitRepo.HasBranch(repo.DefaultBranch) {
+		return coverage, nil
+	}
+
+	commit, err := gitRepo.BranchCommit(repo.DefaultBranch)
+	if err != nil {
+		return nil, fmt.Errorf("get commit of default branch: %v", err)
+	}
+	files, err := gitutil.ListFiles(repo.RepoPath(), commit.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("list files: %v", err)
+	}
+	coverage.NumFiles = len(files)
+
+	codeowners, err := gitutil.CommitCodeowners(commit)
+	if err != nil {
+		if gitutil.IsErrRevisionNotExist(errors.Cause(err)) {
+			return coverage, nil
+		}
+		return nil, fmt.Errorf("get code owners: %v", err)
+	}
+	coverage.HasCodeowners = true
+	coverage.NumOwnedFiles = countOwnedFiles(codeowners, files)
+	return coverage, nil
+}
+
+// GetOrgCodeownersCoverage returns the coverage of code owners of repositories
+// of the organization, ordered by the percentage of owned files in ascending
+// order. Failures of individual repositories are logged and the repositories
+// are left out.
+func GetOrgCodeownersCoverage(orgID int64) ([]*CodeownersCoverage, error) {
+	repos := make([]*Repository, 0, 10)
+	if err := x.Where("owner_id = ?", orgID).Asc("lower_name").Find(&repos); err != nil {
+		return nil, fmt.Errorf("list repositories: %v", err)
+	}
+
+	coverages := make([]*CodeownersCoverage, 0, len(repos))
+	for _, repo := range repos {
+		coverage, err := loadCodeownersCoverage(repo)
+		if err != nil {
+			log.Error("Failed to load code owners coverage [repo_id: %d]: %v", repo.ID, err)
+			continue
+		}
+		coverages = append(coverages, coverage)
+	}
+	sort.SliceStable(coverages, func(i, j int) bool {
+		return coverages[i].Percent() < coverages[j].Percent()
+	})
+	return coverages, nil
+}
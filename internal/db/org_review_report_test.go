@@ -0,0 +1,85 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gogs.io/gogs/internal/gitutil"
+)
+
+func TestReviewLoadWeeks(t *testing.T) {
+	// Wednesday
+	now := time.Date(2026, 3, 11, 15, 4, 5, 0, time.UTC)
+	got := reviewLoadWeeks(now, 3)
+	want := []time.Time{
+		time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC),
+	}
+	assert.Equal(t, want, got)
+
+	// Sunday belongs to the week started on the previous Monday.
+	got = reviewLoadWeeks(time.Date(2026, 3, 15, 23, 0, 0, 0, time.UTC), 1)
+	assert.Equal(t, []time.Time{time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)}, got)
+}
+
+func TestBuildReviewLoads(t *testing.T) {
+	weeks := reviewLoadWeeks(time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC), 2)
+	lastWeek := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC).Unix()
+	thisWeek := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC).Unix()
+
+	requests := []*reviewRequest{
+		{ReviewerIDs: "1,2", CreatedUnix: lastWeek, IsClosed: true},
+		{ReviewerIDs: "1", CreatedUnix: thisWeek},
+		{ReviewerIDs: "2,3,2", CreatedUnix: thisWeek},
+		{ReviewerIDs: "", CreatedUnix: thisWeek},
+		// Before the first week
+		{ReviewerIDs: "1", CreatedUnix: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC).Unix()},
+	}
+	teamMembers := map[int64][]int64{
+		10: {1, 2},
+		20: {4},
+	}
+
+	users, teams := buildReviewLoads(requests, weeks, teamMembers)
+	assert.Equal(t,
+		map[int64]*ReviewLoad{
+			1: {Total: 2, Open: 1, Weekly: []int64{1, 1}},
+			2: {Total: 2, Open: 1, Weekly: []int64{1, 1}},
+			3: {Total: 1, Open: 1, Weekly: []int64{0, 1}},
+		},
+		users,
+	)
+	assert.Equal(t,
+		map[int64]*ReviewLoad{
+			10: {Total: 3, Open: 2, Weekly: []int64{1, 2}},
+		},
+		teams,
+	)
+}
+
+func TestCountOwnedFiles(t *testing.T) {
+	codeowners := gitutil.ParseCodeowners([]byte(`
+*.go     @alice
+/docs/   @bob
+/docs/internal/
+`))
+	files := []string{
+		"main.go",
+		"internal/db/db.go",
+		"docs/README.md",
+		"docs/internal/notes.md",
+		"Makefile",
+	}
+	assert.Equal(t, 3, countOwnedFiles(codeowners, files))
+
+	coverage := &CodeownersCoverage{NumFiles: len(files), NumOwnedFiles: 3}
+	assert.Equal(t, 60, coverage.Percent())
+	assert.Equal(t, 0, (&CodeownersCoverage{}).Percent())
+}
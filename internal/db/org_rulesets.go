@@ -0,0 +1,284 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/errutil"
+)
+
+// OrgRulesetsStore is the persistent interface for rulesets of organizations.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type OrgRulesetsStore interface {
+	// Create creates a new ruleset for the organization. It returns
+	// ErrOrgRulesetAlreadyExist when a ruleset with the same name already exists
+	// for the organization.
+	Create(ctx context.Context, orgID int64, opts OrgRulesetOptions) (*OrgRuleset, error)
+	// DeleteByID deletes the ruleset with given ID of the organization.
+	DeleteByID(ctx context.Context, orgID, id int64) error
+	// GetByID returns the ruleset with given ID of the organization. It returns
+	// ErrOrgRulesetNotExist when not found.
+	GetByID(ctx context.Context, orgID, id int64) (*OrgRuleset, error)
+	// List returns all rulesets of the organization, ordered by name.
+	List(ctx context.Context, orgID int64) ([]*OrgRuleset, error)
+	// Match returns rulesets of the organization that apply to given branch of
+	// the repository with given name, ordered by name.
+	Match(ctx context.Context, orgID int64, repoName, branch string) ([]*OrgRuleset, error)
+	// Update updates the ruleset with given ID of the organization. It returns
+	// ErrOrgRulesetAlreadyExist when another ruleset with the same name already
+	// exists for the organization.
+	Update(ctx context.Context, orgID, id int64, opts OrgRulesetOptions) error
+}
+
+var OrgRulesets OrgRulesetsStore
+
+// OrgRuleset is a set of rules defined by an organization, which applies to
+// branches matching the branch patterns of repositories matching the
+// repository patterns. Rules are enforced in addition to branch protection of
+// each repository.
+type OrgRuleset struct {
+	ID    int64  `gorm:"primaryKey"`
+	OrgID int64  `gorm:"uniqueIndex:org_ruleset_org_name_unique;not null"`
+	Name  string `gorm:"uniqueIndex:org_ruleset_org_name_unique;not null"`
+	// RepoPatterns is the list of glob patterns of repository names separated by
+	// commas, e.g. "*, !sandbox-*".
+	RepoPatterns string `gorm:"type:TEXT;not null"`
+	// BranchPatterns is the list of glob patterns of branch names separated by
+	// commas, e.g. "main, release/*".
+	BranchPatterns     string `gorm:"type:TEXT;not null"`
+	RequirePullRequest bool   `gorm:"not null"`
+	BlockForcePush     bool   `gorm:"not null"`
+	BlockDeletion      bool   `gorm:"not null"`
+	// BlockedPaths is the list of glob patterns of file paths separated by
+	// commas that pushes are not allowed to change.
+	BlockedPaths string    `gorm:"type:TEXT;not null"`
+	CreatedAt    time.Time `gorm:"not null"`
+	UpdatedAt    time.Time `gorm:"not null"`
+}
+
+// ParseRulesetPatterns splits a list of patterns separated by commas or
+// whitespace.
+func ParseRulesetPatterns(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+}
+
+// matchRulesetPatterns returns true if the name matches any of the patterns and
+// none of the negated patterns (prefixed with "!").
+func matchRulesetPatterns(patterns []string, name string) bool {
+	matched := false
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		ok, _ := path.Match(strings.TrimPrefix(pattern, "!"), name)
+		if !ok {
+			continue
+		}
+
+		if negated {
+			return false
+		}
+		matched = true
+	}
+	return matched
+}
+
+// MatchRepo returns true if the ruleset applies to the repository with given
+// name. Repository names are matched case-insensitively.
+func (r *OrgRuleset) MatchRepo(name string) bool {
+	return matchRulesetPatterns(ParseRulesetPatterns(strings.ToLower(r.RepoPatterns)), strings.ToLower(name))
+}
+
+// MatchBranch returns true if the ruleset applies to the branch with given
+// name.
+func (r *OrgRuleset) MatchBranch(name string) bool {
+	return matchRulesetPatterns(ParseRulesetPatterns(r.BranchPatterns), name)
+}
+
+// BlockedPath returns the first of given file paths that the ruleset does not
+// allow to change, or an empty string if there is none. A pattern matches a
+// path if it matches the full path or any of its parent directories, and
+// patterns without a slash also match names at any level, e.g. "*.pem" matches
+// "certs/server.pem".
+func (r *OrgRuleset) BlockedPath(paths ...string) string {
+	patterns := ParseRulesetPatterns(r.BlockedPaths)
+	if len(patterns) == 0 {
+		return ""
+	}
+
+	for _, p := range paths {
+		for _, pattern := range patterns {
+			pattern = strings.Trim(pattern, "/")
+			anyLevel := !strings.Contains(pattern, "/")
+			for dir := p; dir != "." && dir != "/" && dir != ""; dir = path.Dir(dir) {
+				if ok, _ := path.Match(pattern, dir); ok {
+					return p
+				} else if anyLevel {
+					if ok, _ = path.Match(pattern, path.Base(dir)); ok {
+						return p
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+var _ OrgRulesetsStore = (*orgRulesets)(nil)
+
+type orgRulesets struct {
+	*gorm.DB
+}
+
+// NewOrgRulesetsStore returns a persistent interface for rulesets of
+// organizations with given database connection.
+func NewOrgRulesetsStore(db *gorm.DB) OrgRulesetsStore {
+	return &orgRulesets{DB: db}
+}
+
+// OrgRulesetOptions contains options to create or update a ruleset. Lists of
+// patterns are normalized to be separated by ", ".
+type OrgRulesetOptions struct {
+	Name               string
+	RepoPatterns       string
+	BranchPatterns     string
+	RequirePullRequest bool
+	BlockForcePush     bool
+	BlockDeletion      bool
+	BlockedPaths       string
+}
+
+func normalizeRulesetPatterns(s string) string {
+	return strings.Join(ParseRulesetPatterns(s), ", ")
+}
+
+type ErrOrgRulesetAlreadyExist struct {
+	args errutil.Args
+}
+
+func IsErrOrgRulesetAlreadyExist(err error) bool {
+	_, ok := err.(ErrOrgRulesetAlreadyExist)
+	return ok
+}
+
+func (err ErrOrgRulesetAlreadyExist) Error() string {
+	return fmt.Sprintf("organization ruleset already exists: %v", err.args)
+}
+
+// checkNameAvailable returns ErrOrgRulesetAlreadyExist when a ruleset other than
+// the one with given ID has the name in the organization.
+func (db *orgRulesets) checkNameAvailable(ctx context.Context, orgID, id int64, name string) error {
+	err := db.WithContext(ctx).Where("org_id = ? AND name = ? AND id != ?", orgID, name, id).First(new(OrgRuleset)).Error
+	if err == nil {
+		return ErrOrgRulesetAlreadyExist{args: errutil.Args{"orgID": orgID, "name": name}}
+	} else if err != gorm.ErrRecordNotFound {
+		return errors.Wrap(err, "check existence")
+	}
+	return nil
+}
+
+func (db *orgRulesets) Create(ctx context.Context, orgID int64, opts OrgRulesetOptions) (*OrgRuleset, error) {
+	err := db.checkNameAvailable(ctx, orgID, 0, opts.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &OrgRuleset{
+		OrgID:              orgID,
+		Name:               opts.Name,
+		RepoPatterns:       normalizeRulesetPatterns(opts.RepoPatterns),
+		BranchPatterns:     normalizeRulesetPatterns(opts.BranchPatterns),
+		RequirePullRequest: opts.RequirePullRequest,
+		BlockForcePush:     opts.BlockForcePush,
+		BlockDeletion:      opts.BlockDeletion,
+		BlockedPaths:       normalizeRulesetPatterns(opts.BlockedPaths),
+	}
+	return r, db.WithContext(ctx).Create(r).Error
+}
+
+func (db *orgRulesets) DeleteByID(ctx context.Context, orgID, id int64) error {
+	return db.WithContext(ctx).Where("id = ? AND org_id = ?", id, orgID).Delete(new(OrgRuleset)).Error
+}
+
+var _ errutil.NotFound = (*ErrOrgRulesetNotExist)(nil)
+
+type ErrOrgRulesetNotExist struct {
+	args errutil.Args
+}
+
+func IsErrOrgRulesetNotExist(err error) bool {
+	_, ok := err.(ErrOrgRulesetNotExist)
+	return ok
+}
+
+func (err ErrOrgRulesetNotExist) Error() string {
+	return fmt.Sprintf("organization ruleset does not exist: %v", err.args)
+}
+
+func (ErrOrgRulesetNotExist) NotFound() bool {
+	return true
+}
+
+func (db *orgRulesets) GetByID(ctx context.Context, orgID, id int64) (*OrgRuleset, error) {
+	r := new(OrgRuleset)
+	err := db.WithContext(ctx).Where("id = ? AND org_id = ?", id, orgID).First(r).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrOrgRulesetNotExist{args: errutil.Args{"orgID": orgID, "id": id}}
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+func (db *orgRulesets) List(ctx context.Context, orgID int64) ([]*OrgRuleset, error) {
+	var rulesets []*OrgRuleset
+	return rulesets, db.WithContext(ctx).Where("org_id = ?", orgID).Order("name ASC").Find(&rulesets).Error
+}
+
+func (db *orgRulesets) Match(ctx context.Context, orgID int64, repoName, branch string) ([]*OrgRuleset, error) {
+	rulesets, err := db.List(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]*OrgRuleset, 0, len(rulesets))
+	for _, r := range rulesets {
+		if r.MatchRepo(repoName) && r.MatchBranch(branch) {
+			matched = append(matched, r)
+		}
+	}
+	return matched, nil
+}
+
+func (db *orgRulesets) Update(ctx context.Context, orgID, id int64, opts OrgRulesetOptions) error {
+	err := db.checkNameAvailable(ctx, orgID, id, opts.Name)
+	if err != nil {
+		return err
+	}
+
+	return db.WithContext(ctx).
+		Model(new(OrgRuleset)).
+		Where("id = ? AND org_id = ?", id, orgID).
+		Updates(map[string]interface{}{
+			"name":                 opts.Name,
+			"repo_patterns":        normalizeRulesetPatterns(opts.RepoPatterns),
+			"branch_patterns":      normalizeRulesetPatterns(opts.BranchPatterns),
+			"require_pull_request": opts.RequirePullRequest,
+			"block_force_push":     opts.BlockForcePush,
+			"block_deletion":       opts.BlockDeletion,
+			"blocked_paths":        normalizeRulesetPatterns(opts.BlockedPaths),
+		}).
+		Error
+}
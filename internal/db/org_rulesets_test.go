@@ -0,0 +1,241 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/errutil"
+)
+
+func TestOrgRuleset_MatchRepo(t *testing.T) {
+	r := &OrgRuleset{RepoPatterns: "*, !sandbox-*"}
+	assert.True(t, r.MatchRepo("api"))
+	assert.True(t, r.MatchRepo("Web"))
+	assert.False(t, r.MatchRepo("sandbox-alice"))
+	assert.False(t, r.MatchRepo("Sandbox-Bob"))
+
+	r = &OrgRuleset{RepoPatterns: "service-?, web"}
+	assert.True(t, r.MatchRepo("service-a"))
+	assert.True(t, r.MatchRepo("WEB"))
+	assert.False(t, r.MatchRepo("service-ab"))
+
+	// No patterns match nothing.
+	r = &OrgRuleset{}
+	assert.False(t, r.MatchRepo("api"))
+}
+
+func TestOrgRuleset_MatchBranch(t *testing.T) {
+	r := &OrgRuleset{BranchPatterns: "main, release/*"}
+	assert.True(t, r.MatchBranch("main"))
+	assert.True(t, r.MatchBranch("release/1.0"))
+	assert.False(t, r.MatchBranch("Main"))
+	assert.False(t, r.MatchBranch("release/1.0/hotfix"))
+	assert.False(t, r.MatchBranch("feature"))
+}
+
+func TestOrgRuleset_BlockedPath(t *testing.T) {
+	r := &OrgRuleset{BlockedPaths: "*.pem, secrets, .gogs/workflows/*"}
+	assert.Equal(t, "", r.BlockedPath("README.md", "src/main.go"))
+	assert.Equal(t, "certs/server.pem", r.BlockedPath("README.md", "certs/server.pem"))
+	assert.Equal(t, "secrets/token", r.BlockedPath("secrets/token"))
+	assert.Equal(t, "config/secrets/token", r.BlockedPath("config/secrets/token"))
+	assert.Equal(t, ".gogs/workflows/ci.yml", r.BlockedPath(".gogs/workflows/ci.yml"))
+	assert.Equal(t, "", r.BlockedPath("docs/.gogs/workflows/ci.yml"))
+
+	r = &OrgRuleset{}
+	assert.Equal(t, "", r.BlockedPath("certs/server.pem"))
+}
+
+func TestOrgRulesets(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(OrgRuleset)}
+	db := &orgRulesets{
+		DB: dbtest.NewDB(t, "orgRulesets", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *orgRulesets)
+	}{
+		{"Create", orgRulesetsCreate},
+		{"DeleteByID", orgRulesetsDeleteByID},
+		{"GetByID", orgRulesetsGetByID},
+		{"List", orgRulesetsList},
+		{"Match", orgRulesetsMatch},
+		{"Update", orgRulesetsUpdate},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func orgRulesetsCreate(t *testing.T, db *orgRulesets) {
+	ctx := context.Background()
+
+	r, err := db.Create(ctx, 1,
+		OrgRulesetOptions{
+			Name:               "Protect main",
+			RepoPatterns:       "*,!sandbox-*",
+			BranchPatterns:     "main\nrelease/*",
+			RequirePullRequest: true,
+			BlockedPaths:       " *.pem ",
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "*, !sandbox-*", r.RepoPatterns)
+	assert.Equal(t, "main, release/*", r.BranchPatterns)
+	assert.Equal(t, "*.pem", r.BlockedPaths)
+	assert.True(t, r.RequirePullRequest)
+	assert.False(t, r.BlockForcePush)
+
+	_, err = db.Create(ctx, 1, OrgRulesetOptions{Name: "Protect main"})
+	wantErr := ErrOrgRulesetAlreadyExist{args: errutil.Args{"orgID": int64(1), "name": "Protect main"}}
+	assert.Equal(t, wantErr, err)
+
+	// The same name can be used by another organization.
+	_, err = db.Create(ctx, 2, OrgRulesetOptions{Name: "Protect main"})
+	require.NoError(t, err)
+}
+
+func orgRulesetsDeleteByID(t *testing.T, db *orgRulesets) {
+	ctx := context.Background()
+
+	r, err := db.Create(ctx, 1, OrgRulesetOptions{Name: "Protect main"})
+	require.NoError(t, err)
+
+	// Deleting with a wrong organization should be a no-op.
+	err = db.DeleteByID(ctx, 2, r.ID)
+	require.NoError(t, err)
+	_, err = db.GetByID(ctx, 1, r.ID)
+	require.NoError(t, err)
+
+	err = db.DeleteByID(ctx, 1, r.ID)
+	require.NoError(t, err)
+	_, err = db.GetByID(ctx, 1, r.ID)
+	wantErr := ErrOrgRulesetNotExist{args: errutil.Args{"orgID": int64(1), "id": r.ID}}
+	assert.Equal(t, wantErr, err)
+}
+
+func orgRulesetsGetByID(t *testing.T, db *orgRulesets) {
+	ctx := context.Background()
+
+	r, err := db.Create(ctx, 1, OrgRulesetOptions{Name: "Protect main"})
+	require.NoError(t, err)
+
+	got, err := db.GetByID(ctx, 1, r.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Protect main", got.Name)
+
+	_, err = db.GetByID(ctx, 2, r.ID)
+	wantErr := ErrOrgRulesetNotExist{args: errutil.Args{"orgID": int64(2), "id": r.ID}}
+	assert.Equal(t, wantErr, err)
+}
+
+func orgRulesetsList(t *testing.T, db *orgRulesets) {
+	ctx := context.Background()
+
+	_, err := db.Create(ctx, 1, OrgRulesetOptions{Name: "Protect release"})
+	require.NoError(t, err)
+	_, err = db.Create(ctx, 1, OrgRulesetOptions{Name: "Protect main"})
+	require.NoError(t, err)
+	_, err = db.Create(ctx, 2, OrgRulesetOptions{Name: "Protect all"})
+	require.NoError(t, err)
+
+	rulesets, err := db.List(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, rulesets, 2)
+	assert.Equal(t, "Protect main", rulesets[0].Name)
+	assert.Equal(t, "Protect release", rulesets[1].Name)
+}
+
+func orgRulesetsMatch(t *testing.T, db *orgRulesets) {
+	ctx := context.Background()
+
+	_, err := db.Create(ctx, 1,
+		OrgRulesetOptions{
+			Name:           "Protect main",
+			RepoPatterns:   "*, !sandbox-*",
+			BranchPatterns: "main",
+		},
+	)
+	require.NoError(t, err)
+	_, err = db.Create(ctx, 1,
+		OrgRulesetOptions{
+			Name:           "Protect services",
+			RepoPatterns:   "service-*",
+			BranchPatterns: "main, release/*",
+		},
+	)
+	require.NoError(t, err)
+
+	rulesets, err := db.Match(ctx, 1, "service-a", "main")
+	require.NoError(t, err)
+	require.Len(t, rulesets, 2)
+	assert.Equal(t, "Protect main", rulesets[0].Name)
+	assert.Equal(t, "Protect services", rulesets[1].Name)
+
+	rulesets, err = db.Match(ctx, 1, "service-a", "release/1.0")
+	require.NoError(t, err)
+	require.Len(t, rulesets, 1)
+	assert.Equal(t, "Protect services", rulesets[0].Name)
+
+	rulesets, err = db.Match(ctx, 1, "sandbox-alice", "main")
+	require.NoError(t, err)
+	assert.Empty(t, rulesets)
+
+	// Rulesets of other organizations are irrelevant.
+	rulesets, err = db.Match(ctx, 2, "service-a", "main")
+	require.NoError(t, err)
+	assert.Empty(t, rulesets)
+}
+
+func orgRulesetsUpdate(t *testing.T, db *orgRulesets) {
+	ctx := context.Background()
+
+	r, err := db.Create(ctx, 1, OrgRulesetOptions{Name: "Protect main"})
+	require.NoError(t, err)
+	_, err = db.Create(ctx, 1, OrgRulesetOptions{Name: "Protect release"})
+	require.NoError(t, err)
+
+	err = db.Update(ctx, 1, r.ID, OrgRulesetOptions{Name: "Protect release"})
+	wantErr := ErrOrgRulesetAlreadyExist{args: errutil.Args{"orgID": int64(1), "name": "Protect release"}}
+	assert.Equal(t, wantErr, err)
+
+	// Keeping the same name is fine.
+	err = db.Update(ctx, 1, r.ID,
+		OrgRulesetOptions{
+			Name:           "Protect main",
+			RepoPatterns:   "*",
+			BranchPatterns: "main,master",
+			BlockDeletion:  true,
+		},
+	)
+	require.NoError(t, err)
+
+	got, err := db.GetByID(ctx, 1, r.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "*", got.RepoPatterns)
+	assert.Equal(t, "main, master", got.BranchPatterns)
+	assert.True(t, got.BlockDeletion)
+	assert.False(t, got.RequirePullRequest)
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// OrgSecurityPoliciesStore is the persistent interface for security policies of
+// organizations.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type OrgSecurityPoliciesStore interface {
+	// Get returns the security policy of the organization with given ID. It
+	// returns the default policy when the organization has never set one.
+	Get(ctx context.Context, orgID int64) (*OrgSecurityPolicy, error)
+	// Update creates or updates the security policy of the organization with
+	// given ID.
+	Update(ctx context.Context, orgID int64, opts OrgSecurityPolicyOptions) error
+}
+
+var OrgSecurityPolicies OrgSecurityPoliciesStore
+
+// SecurityFeature is a security feature that organizations enable or disable
+// for all of their repositories.
+type SecurityFeature string
+
+const (
+	// SecurityFeatureSecretScanning blocks pushes that introduce files
+	// containing secrets, e.g. private keys and access tokens.
+	SecurityFeatureSecretScanning SecurityFeature = "secret_scanning"
+	// SecurityFeatureVulnerabilityAlerts raises security alerts for
+	// dependencies with known vulnerabilities.
+	SecurityFeatureVulnerabilityAlerts SecurityFeature = "vulnerability_alerts"
+	// SecurityFeatureSignedCommits blocks pushes that introduce commits without
+	// a valid signature of a GPG key of the committer.
+	SecurityFeatureSignedCommits SecurityFeature = "signed_commits"
+)
+
+// SecurityFeatures is the list of all security features.
+var SecurityFeatures = []SecurityFeature{
+	SecurityFeatureSecretScanning,
+	SecurityFeatureVulnerabilityAlerts,
+	SecurityFeatureSignedCommits,
+}
+
+// IsValid returns true if the feature is one of known features.
+func (f SecurityFeature) IsValid() bool {
+	for _, feature := range SecurityFeatures {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// OrgSecurityPolicy is the policy of security features for all repositories
+// of an organization. Repositories are exempted from an enabled feature when an
+// exception is approved by owners of the organization.
+type OrgSecurityPolicy struct {
+	ID                   int64     `gorm:"primaryKey"`
+	OrgID                int64     `gorm:"uniqueIndex;not null"`
+	SecretScanning       bool      `gorm:"not null"`
+	VulnerabilityAlerts  bool      `gorm:"not null"`
+	RequireSignedCommits bool      `gorm:"not null"`
+	UpdatedAt            time.Time `gorm:"not null"`
+}
+
+// IsEnabled returns true if the feature is enabled by the policy.
+func (p *OrgSecurityPolicy) IsEnabled(feature SecurityFeature) bool {
+	switch feature {
+	case SecurityFeatureSecretScanning:
+		return p.SecretScanning
+	case SecurityFeatureVulnerabilityAlerts:
+		return p.VulnerabilityAlerts
+	case SecurityFeatureSignedCommits:
+		return p.RequireSignedCommits
+	}
+	return false
+}
+
+// defaultOrgSecurityPolicy returns the policy of organizations that have never
+// set one, which keeps the behavior of repositories owned by users.
+func defaultOrgSecurityPolicy(orgID int64) *OrgSecurityPolicy {
+	return &OrgSecurityPolicy{
+		OrgID:               orgID,
+		VulnerabilityAlerts: true,
+	}
+}
+
+var _ OrgSecurityPoliciesStore = (*orgSecurityPolicies)(nil)
+
+type orgSecurityPolicies struct {
+	*gorm.DB
+}
+
+// NewOrgSecurityPoliciesStore returns a persistent interface for security
+// policies of organizations with given database connection.
+func NewOrgSecurityPoliciesStore(db *gorm.DB) OrgSecurityPoliciesStore {
+	return &orgSecurityPolicies{DB: db}
+}
+
+func (db *orgSecurityPolicies) Get(ctx context.Context, orgID int64) (*OrgSecurityPolicy, error) {
+	p := new(OrgSecurityPolicy)
+	err := db.WithContext(ctx).Where("org_id = ?", orgID).First(p).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return defaultOrgSecurityPolicy(orgID), nil
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+// OrgSecurityPolicyOptions contains options to update a security policy.
+type OrgSecurityPolicyOptions struct {
+	SecretScanning       bool
+	VulnerabilityAlerts  bool
+	RequireSignedCommits bool
+}
+
+func (db *orgSecurityPolicies) Update(ctx context.Context, orgID int64, opts OrgSecurityPolicyOptions) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		p := new(OrgSecurityPolicy)
+		err := tx.Where("org_id = ?", orgID).First(p).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return errors.Wrap(err, "get")
+		}
+
+		p.OrgID = orgID
+		p.SecretScanning = opts.SecretScanning
+		p.VulnerabilityAlerts = opts.VulnerabilityAlerts
+		p.RequireSignedCommits = opts.RequireSignedCommits
+		return tx.Save(p).Error
+	})
+}
+
+// IsSecurityFeatureEnforced returns true if the feature is enforced for the
+// repository, i.e. enabled by the policy of the organization that owns the
+// repository and the repository is not exempted by an approved exception.
+// Repositories owned by users follow the default policy.
+func IsSecurityFeatureEnforced(ctx context.Context, repo *Repository, feature SecurityFeature) (bool, error) {
+	if err := repo.GetOwner(); err != nil {
+		return false, errors.Wrap(err, "get owner")
+	}
+	if !repo.Owner.IsOrganization() {
+		return defaultOrgSecurityPolicy(repo.OwnerID).IsEnabled(feature), nil
+	}
+
+	policy, err := OrgSecurityPolicies.Get(ctx, repo.OwnerID)
+	if err != nil {
+		return false, errors.Wrap(err, "get security policy")
+	} else if !policy.IsEnabled(feature) {
+		return false, nil
+	}
+
+	exempted, err := SecurityPolicyExceptions.IsExempted(ctx, repo.OwnerID, repo.ID, feature)
+	if err != nil {
+		return false, errors.Wrap(err, "check exception")
+	}
+	return !exempted, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+func TestOrgSecurityPolicies(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(OrgSecurityPolicy)}
+	db := &orgSecurityPolicies{
+		DB: dbtest.NewDB(t, "orgSecurityPolicies", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *orgSecurityPolicies)
+	}{
+		{"Get", orgSecurityPoliciesGet},
+		{"Update", orgSecurityPoliciesUpdate},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func orgSecurityPoliciesGet(t *testing.T, db *orgSecurityPolicies) {
+	ctx := context.Background()
+
+	// The default policy keeps vulnerability alerts enabled
+	p, err := db.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), p.OrgID)
+	assert.False(t, p.IsEnabled(SecurityFeatureSecretScanning))
+	assert.True(t, p.IsEnabled(SecurityFeatureVulnerabilityAlerts))
+	assert.False(t, p.IsEnabled(SecurityFeatureSignedCommits))
+}
+
+func orgSecurityPoliciesUpdate(t *testing.T, db *orgSecurityPolicies) {
+	ctx := context.Background()
+
+	err := db.Update(ctx, 1, OrgSecurityPolicyOptions{
+		SecretScanning:       true,
+		RequireSignedCommits: true,
+	})
+	require.NoError(t, err)
+
+	p, err := db.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, p.IsEnabled(SecurityFeatureSecretScanning))
+	assert.False(t, p.IsEnabled(SecurityFeatureVulnerabilityAlerts))
+	assert.True(t, p.IsEnabled(SecurityFeatureSignedCommits))
+
+	// Updating again does not create another policy
+	err = db.Update(ctx, 1, OrgSecurityPolicyOptions{VulnerabilityAlerts: true})
+	require.NoError(t, err)
+
+	var count int64
+	err = db.Model(new(OrgSecurityPolicy)).Count(&count).Error
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	p, err = db.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, p.IsEnabled(SecurityFeatureSecretScanning))
+	assert.True(t, p.IsEnabled(SecurityFeatureVulnerabilityAlerts))
+	assert.False(t, p.IsEnabled(SecurityFeatureSignedCommits))
+}
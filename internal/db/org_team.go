@@ -362,6 +362,19 @@ func GetTeamsByOrgID(orgID int64) ([]*Team, error) {
 	return getTeamsByOrgID(x, orgID)
 }
 
+// CountTeams returns number of teams of all organizations.
+func CountTeams() int64 {
+	count, _ := x.Count(new(Team))
+	return count
+}
+
+// ListTeamsWithOffset returns at most limit teams of all organizations after
+// skipping offset teams, ordered by ID.
+func ListTeamsWithOffset(offset, limit int) ([]*Team, error) {
+	teams := make([]*Team, 0, limit)
+	return teams, x.Limit(limit, offset).Asc("id").Find(&teams)
+}
+
 // UpdateTeam updates information of team.
 func UpdateTeam(t *Team, authChanged bool) (err error) {
 	if t.Name == "" {
@@ -438,6 +451,11 @@ func DeleteTeam(t *Team) error {
 		return err
 	}
 
+	// Delete team discussions.
+	if _, err = sess.Exec("DELETE FROM team_discussion WHERE team_id = ?", t.ID); err != nil {
+		return err
+	}
+
 	// Delete team.
 	if _, err = sess.ID(t.ID).Delete(new(Team)); err != nil {
 		return err
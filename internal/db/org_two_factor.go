@@ -0,0 +1,112 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gogs.io/gogs/internal/errutil"
+)
+
+// MaxTwoFactorGracePeriod is the maximum grace period for members of an
+// organization to enable two-factor authentication after it is required.
+const MaxTwoFactorGracePeriod = 30 * 24 * time.Hour
+
+// TwoFactorGraceUntil returns the time when the grace period for members of
+// the organization to enable two-factor authentication ends.
+func (org *User) TwoFactorGraceUntil() time.Time {
+	return time.Unix(org.TwoFactorGraceUntilUnix, 0).Local()
+}
+
+// IsTwoFactorEnforced returns true if the organization requires two-factor
+// authentication and the grace period has ended.
+func (org *User) IsTwoFactorEnforced() bool {
+	return org.RequireTwoFactor && time.Now().Unix() >= org.TwoFactorGraceUntilUnix
+}
+
+// IsBlockedByTwoFactorRequirement returns true if the user is blocked from
+// accessing resources of the organization because the organization enforces
+// two-factor authentication and the user has not enabled it.
+func (org *User) IsBlockedByTwoFactorRequirement(userID int64) bool {
+	return org.IsTwoFactorEnforced() && !TwoFactors.IsUserEnabled(context.TODO(), userID)
+}
+
+type ErrOrgTwoFactorNotEnabled struct {
+	args errutil.Args
+}
+
+func IsErrOrgTwoFactorNotEnabled(err error) bool {
+	_, ok := err.(ErrOrgTwoFactorNotEnabled)
+	return ok
+}
+
+func (err ErrOrgTwoFactorNotEnabled) Error() string {
+	return fmt.Sprintf("user has not enabled two-factor authentication required by the organization: %v", err.args)
+}
+
+// SetOrgTwoFactorRequirement changes whether the organization requires its
+// members to enable two-factor authentication. When the requirement is turned
+// on, members without two-factor authentication are blocked after the grace
+// period, and the doer must have enabled two-factor authentication to not be
+// locked out. It returns ErrOrgTwoFactorNotEnabled if the doer has not.
+// Changing only the grace period restarts it from now.
+func SetOrgTwoFactorRequirement(org, doer *User, require bool, gracePeriod time.Duration) error {
+	if require && !TwoFactors.IsUserEnabled(context.TODO(), doer.ID) {
+		return ErrOrgTwoFactorNotEnabled{args: errutil.Args{"orgID": org.ID, "userID": doer.ID}}
+	}
+
+	if gracePeriod < 0 {
+		gracePeriod = 0
+	} else if gracePeriod > MaxTwoFactorGracePeriod {
+		gracePeriod = MaxTwoFactorGracePeriod
+	}
+
+	org.RequireTwoFactor = require
+	if require {
+		org.TwoFactorGraceUntilUnix = time.Now().Add(gracePeriod).Unix()
+	} else {
+		org.TwoFactorGraceUntilUnix = 0
+	}
+	return UpdateUser(org)
+}
+
+// GetOrgMembersWithoutTwoFactor returns members of the organization who have
+// not enabled two-factor authentication.
+func GetOrgMembersWithoutTwoFactor(orgID int64) ([]*User, error) {
+	ous, err := getOrgUsersByOrgID(x, orgID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("get organization users: %v", err)
+	}
+	if len(ous) == 0 {
+		return []*User{}, nil
+	}
+	uids := make([]int64, len(ous))
+	for i := range ous {
+		uids[i] = ous[i].Uid
+	}
+
+	tfs := make([]*TwoFactor, 0, len(uids))
+	if err = x.In("user_id", uids).Find(&tfs); err != nil {
+		return nil, fmt.Errorf("list two-factor authentications: %v", err)
+	}
+	enabled := make(map[int64]bool, len(tfs))
+	for _, tf := range tfs {
+		enabled[tf.UserID] = true
+	}
+
+	missing := make([]int64, 0, len(uids))
+	for _, uid := range uids {
+		if !enabled[uid] {
+			missing = append(missing, uid)
+		}
+	}
+	users := make([]*User, 0, len(missing))
+	if len(missing) == 0 {
+		return users, nil
+	}
+	return users, x.In("id", missing).Asc("lower_name").Find(&users)
+}
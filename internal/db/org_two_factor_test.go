@@ -0,0 +1,46 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUser_IsTwoFactorEnforced(t *testing.T) {
+	tests := []struct {
+		name string
+		org  *User
+		want bool
+	}{
+		{
+			name: "not required",
+			org:  &User{},
+			want: false,
+		},
+		{
+			name: "in grace period",
+			org:  &User{RequireTwoFactor: true, TwoFactorGraceUntilUnix: time.Now().Add(time.Hour).Unix()},
+			want: false,
+		},
+		{
+			name: "grace period ended",
+			org:  &User{RequireTwoFactor: true, TwoFactorGraceUntilUnix: time.Now().Add(-time.Hour).Unix()},
+			want: true,
+		},
+		{
+			name: "without grace period",
+			org:  &User{RequireTwoFactor: true},
+			want: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.org.IsTwoFactorEnforced())
+		})
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	gouuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/cryptoutil"
+	"gogs.io/gogs/internal/errutil"
+)
+
+// PagesSitesStore is the persistent interface for static websites published
+// from repositories.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type PagesSitesStore interface {
+	// DeleteByRepoID deletes the site of the repository. It is a no-op when the
+	// repository has no site.
+	DeleteByRepoID(ctx context.Context, repoID int64) error
+	// GetByDomain returns the site with given verified custom domain. It returns
+	// ErrPagesSiteNotExist when not found.
+	GetByDomain(ctx context.Context, domain string) (*PagesSite, error)
+	// GetByRepoID returns the site of the repository. It returns
+	// ErrPagesSiteNotExist when not found.
+	GetByRepoID(ctx context.Context, repoID int64) (*PagesSite, error)
+	// MarkDomainVerified marks the custom domain of the site of the repository
+	// as verified.
+	MarkDomainVerified(ctx context.Context, repoID int64) error
+	// Save creates or updates the site of the repository with given options.
+	// The verification of the custom domain is reset with a new token when the
+	// domain is changed. It returns ErrPagesDomainAlreadyUsed when the custom
+	// domain is used by the site of another repository.
+	Save(ctx context.Context, repoID int64, opts SavePagesSiteOptions) (*PagesSite, error)
+	// SetArtifact updates the SHA256 checksum of the artifact uploaded for the
+	// site of the repository. It returns ErrPagesSiteNotExist when the
+	// repository has no site.
+	SetArtifact(ctx context.Context, repoID int64, sha256 string) error
+}
+
+var PagesSites PagesSitesStore
+
+// PagesSource is where the content of a site is published from.
+type PagesSource string
+
+const (
+	// PagesSourceBranch publishes the content of a branch.
+	PagesSourceBranch PagesSource = "branch"
+	// PagesSourceArtifact publishes the content of a ZIP archive uploaded by CI.
+	PagesSourceArtifact PagesSource = "artifact"
+)
+
+// IsValid returns true if the source is one of known sources.
+func (s PagesSource) IsValid() bool {
+	return s == PagesSourceBranch || s == PagesSourceArtifact
+}
+
+// PagesSite is a static website published from a repository.
+type PagesSite struct {
+	ID     int64       `gorm:"primaryKey"`
+	RepoID int64       `gorm:"uniqueIndex;not null"`
+	Source PagesSource `gorm:"type:VARCHAR(10);not null"`
+	// The branch to publish for the branch source.
+	Branch string `gorm:"not null"`
+	// The directory to publish relative to the root of the branch or the
+	// artifact, empty for the root.
+	Directory string `gorm:"not null"`
+	// The SHA256 checksum of the latest artifact uploaded for the artifact
+	// source, empty when none has been uploaded.
+	ArtifactSHA256 string `gorm:"column:artifact_sha256;type:VARCHAR(64);not null"`
+
+	CustomDomain     string `gorm:"index;not null"`
+	DomainToken      string `gorm:"type:VARCHAR(40);not null"`
+	IsDomainVerified bool   `gorm:"not null"`
+
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+// PagesArtifactPath returns the local path of the artifact uploaded for the
+// site of the repository.
+func PagesArtifactPath(repoID int64) string {
+	return filepath.Join(conf.Pages.StoragePath, strconv.FormatInt(repoID, 10)+".zip")
+}
+
+// TXTRecordName returns the name of the DNS TXT record to verify the custom
+// domain of the site.
+func (s *PagesSite) TXTRecordName() string {
+	return OrgDomainTXTRecordPrefix + s.CustomDomain
+}
+
+// TXTRecordValue returns the expected value of the DNS TXT record to verify
+// the custom domain of the site.
+func (s *PagesSite) TXTRecordValue() string {
+	return "gogs-pages-verification=" + s.DomainToken
+}
+
+var _ PagesSitesStore = (*pagesSites)(nil)
+
+type pagesSites struct {
+	*gorm.DB
+}
+
+// NewPagesSitesStore returns a persistent interface for static websites
+// published from repositories with given database connection.
+func NewPagesSitesStore(db *gorm.DB) PagesSitesStore {
+	return &pagesSites{DB: db}
+}
+
+func (db *pagesSites) DeleteByRepoID(ctx context.Context, repoID int64) error {
+	return db.WithContext(ctx).Where("repo_id = ?", repoID).Delete(new(PagesSite)).Error
+}
+
+var _ errutil.NotFound = (*ErrPagesSiteNotExist)(nil)
+
+type ErrPagesSiteNotExist struct {
+	args errutil.Args
+}
+
+func IsErrPagesSiteNotExist(err error) bool {
+	_, ok := err.(ErrPagesSiteNotExist)
+	return ok
+}
+
+func (err ErrPagesSiteNotExist) Error() string {
+	return fmt.Sprintf("pages site does not exist: %v", err.args)
+}
+
+func (ErrPagesSiteNotExist) NotFound() bool {
+	return true
+}
+
+func (db *pagesSites) GetByDomain(ctx context.Context, domain string) (*PagesSite, error) {
+	domain = NormalizeDomain(domain)
+	s := new(PagesSite)
+	err := db.WithContext(ctx).
+		Where("custom_domain = ? AND is_domain_verified = ?", domain, true).
+		First(s).
+		Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPagesSiteNotExist{args: errutil.Args{"domain": domain}}
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+func (db *pagesSites) GetByRepoID(ctx context.Context, repoID int64) (*PagesSite, error) {
+	s := new(PagesSite)
+	err := db.WithContext(ctx).Where("repo_id = ?", repoID).First(s).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPagesSiteNotExist{args: errutil.Args{"repoID": repoID}}
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+func (db *pagesSites) MarkDomainVerified(ctx context.Context, repoID int64) error {
+	return db.WithContext(ctx).
+		Model(new(PagesSite)).
+		Where("repo_id = ? AND custom_domain <> ?", repoID, "").
+		Update("is_domain_verified", true).
+		Error
+}
+
+// SavePagesSiteOptions contains options for saving a site.
+type SavePagesSiteOptions struct {
+	Source       PagesSource
+	Branch       string
+	Directory    string
+	CustomDomain string
+}
+
+type ErrPagesDomainAlreadyUsed struct {
+	args errutil.Args
+}
+
+func IsErrPagesDomainAlreadyUsed(err error) bool {
+	_, ok := err.(ErrPagesDomainAlreadyUsed)
+	return ok
+}
+
+func (err ErrPagesDomainAlreadyUsed) Error() string {
+	return fmt.Sprintf("pages domain is already used: %v", err.args)
+}
+
+func (db *pagesSites) Save(ctx context.Context, repoID int64, opts SavePagesSiteOptions) (*PagesSite, error) {
+	opts.CustomDomain = NormalizeDomain(opts.CustomDomain)
+
+	s := new(PagesSite)
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if opts.CustomDomain != "" {
+			err := tx.Where("custom_domain = ? AND repo_id <> ?", opts.CustomDomain, repoID).First(new(PagesSite)).Error
+			if err == nil {
+				return ErrPagesDomainAlreadyUsed{args: errutil.Args{"domain": opts.CustomDomain}}
+			} else if err != gorm.ErrRecordNotFound {
+				return errors.Wrap(err, "check domain")
+			}
+		}
+
+		err := tx.Where("repo_id = ?", repoID).First(s).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return errors.Wrap(err, "get site")
+		}
+		exists := err == nil
+
+		if !exists || s.CustomDomain != opts.CustomDomain {
+			s.DomainToken = cryptoutil.SHA1(gouuid.NewV4().String())
+			s.IsDomainVerified = false
+		}
+		s.RepoID = repoID
+		s.Source = opts.Source
+		s.Branch = opts.Branch
+		s.Directory = opts.Directory
+		s.CustomDomain = opts.CustomDomain
+		if exists {
+			return tx.Save(s).Error
+		}
+		return tx.Create(s).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (db *pagesSites) SetArtifact(ctx context.Context, repoID int64, sha256 string) error {
+	result := db.WithContext(ctx).
+		Model(new(PagesSite)).
+		Where("repo_id = ?", repoID).
+		Updates(map[string]interface{}{
+			"artifact_sha256": sha256,
+			"updated_at":      db.NowFunc(),
+		})
+	if result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		return ErrPagesSiteNotExist{args: errutil.Args{"repoID": repoID}}
+	}
+	return nil
+}
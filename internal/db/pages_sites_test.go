@@ -0,0 +1,139 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/errutil"
+)
+
+func TestPagesSites(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(PagesSite)}
+	db := &pagesSites{
+		DB: dbtest.NewDB(t, "pagesSites", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *pagesSites)
+	}{
+		{"DeleteByRepoID", pagesSitesDeleteByRepoID},
+		{"GetByDomain", pagesSitesGetByDomain},
+		{"Save", pagesSitesSave},
+		{"SetArtifact", pagesSitesSetArtifact},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func pagesSitesDeleteByRepoID(t *testing.T, db *pagesSites) {
+	ctx := context.Background()
+
+	_, err := db.Save(ctx, 1, SavePagesSiteOptions{Source: PagesSourceBranch, Branch: "gh-pages"})
+	require.NoError(t, err)
+
+	err = db.DeleteByRepoID(ctx, 1)
+	require.NoError(t, err)
+	// Deleting a site that does not exist is a no-op.
+	err = db.DeleteByRepoID(ctx, 1)
+	require.NoError(t, err)
+
+	_, err = db.GetByRepoID(ctx, 1)
+	wantErr := ErrPagesSiteNotExist{args: errutil.Args{"repoID": int64(1)}}
+	assert.Equal(t, wantErr, err)
+}
+
+func pagesSitesGetByDomain(t *testing.T, db *pagesSites) {
+	ctx := context.Background()
+
+	_, err := db.Save(ctx, 1, SavePagesSiteOptions{Source: PagesSourceBranch, Branch: "gh-pages", CustomDomain: "Docs.Example.com."})
+	require.NoError(t, err)
+
+	// Unverified domains are not served.
+	_, err = db.GetByDomain(ctx, "docs.example.com")
+	wantErr := ErrPagesSiteNotExist{args: errutil.Args{"domain": "docs.example.com"}}
+	assert.Equal(t, wantErr, err)
+
+	err = db.MarkDomainVerified(ctx, 1)
+	require.NoError(t, err)
+
+	site, err := db.GetByDomain(ctx, "DOCS.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), site.RepoID)
+	assert.Equal(t, "docs.example.com", site.CustomDomain)
+}
+
+func pagesSitesSave(t *testing.T, db *pagesSites) {
+	ctx := context.Background()
+
+	site, err := db.Save(ctx, 1, SavePagesSiteOptions{Source: PagesSourceBranch, Branch: "gh-pages", CustomDomain: "docs.example.com"})
+	require.NoError(t, err)
+	token := site.DomainToken
+	assert.NotEmpty(t, token)
+
+	err = db.MarkDomainVerified(ctx, 1)
+	require.NoError(t, err)
+
+	// The verification is kept when the domain is unchanged.
+	site, err = db.Save(ctx, 1, SavePagesSiteOptions{Source: PagesSourceArtifact, Directory: "public", CustomDomain: "docs.example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, token, site.DomainToken)
+	assert.True(t, site.IsDomainVerified)
+
+	got, err := db.GetByRepoID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, PagesSourceArtifact, got.Source)
+	assert.Equal(t, "public", got.Directory)
+	assert.True(t, got.IsDomainVerified)
+
+	// The verification is reset when the domain is changed.
+	site, err = db.Save(ctx, 1, SavePagesSiteOptions{Source: PagesSourceArtifact, CustomDomain: "www.example.com"})
+	require.NoError(t, err)
+	assert.NotEqual(t, token, site.DomainToken)
+	assert.False(t, site.IsDomainVerified)
+
+	// The domain is used by another site.
+	_, err = db.Save(ctx, 2, SavePagesSiteOptions{Source: PagesSourceBranch, Branch: "master", CustomDomain: "www.example.com"})
+	wantErr := ErrPagesDomainAlreadyUsed{args: errutil.Args{"domain": "www.example.com"}}
+	assert.Equal(t, wantErr, err)
+}
+
+func pagesSitesSetArtifact(t *testing.T, db *pagesSites) {
+	ctx := context.Background()
+
+	err := db.SetArtifact(ctx, 1, "ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f")
+	wantErr := ErrPagesSiteNotExist{args: errutil.Args{"repoID": int64(1)}}
+	assert.Equal(t, wantErr, err)
+
+	_, err = db.Save(ctx, 1, SavePagesSiteOptions{Source: PagesSourceArtifact})
+	require.NoError(t, err)
+
+	err = db.SetArtifact(ctx, 1, "ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f")
+	require.NoError(t, err)
+
+	site, err := db.GetByRepoID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f", site.ArtifactSHA256)
+}
@@ -0,0 +1,190 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	gouuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/cryptoutil"
+	"gogs.io/gogs/internal/errutil"
+)
+
+// PasswordResetTokensStore is the persistent interface for password reset
+// tokens.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type PasswordResetTokensStore interface {
+	// Create issues a new password reset token to the user, requested from the
+	// IP address and valid for the given duration. Unused tokens previously
+	// issued to the user are expired, so that only the latest one can be used.
+	// The raw token is only available via the Token field of the returned
+	// password reset token. Tokens older than the retention period of all users
+	// are deleted along the way.
+	Create(ctx context.Context, userID int64, ip string, ttl time.Duration) (*PasswordResetToken, error)
+	// DeleteByUserID deletes all password reset tokens of the user.
+	DeleteByUserID(ctx context.Context, userID int64) error
+	// GetByToken returns the unused and unexpired password reset token with
+	// given raw token. It returns ErrPasswordResetTokenNotExist when not found,
+	// used or expired.
+	GetByToken(ctx context.Context, token string) (*PasswordResetToken, error)
+	// ListByUserID returns password reset tokens of the user in reverse
+	// chronological order, up to the limit.
+	ListByUserID(ctx context.Context, userID int64, limit int) ([]*PasswordResetToken, error)
+	// Use marks the unused and unexpired password reset token with given raw
+	// token as used from the IP address, and returns the token. Each token can
+	// only be used once. It returns ErrPasswordResetTokenNotExist when not found,
+	// used or expired.
+	Use(ctx context.Context, token, ip string) (*PasswordResetToken, error)
+}
+
+var PasswordResetTokens PasswordResetTokensStore
+
+// passwordResetTokenRetention is how long password reset tokens are kept for
+// auditing after being issued.
+const passwordResetTokenRetention = 90 * 24 * time.Hour
+
+// PasswordResetToken is a single-use token sent to the user by email to reset
+// the password. Only the hash of the token is stored.
+type PasswordResetToken struct {
+	ID        int64     `gorm:"primaryKey"`
+	UserID    int64     `gorm:"index;not null"`
+	SHA256    string    `gorm:"type:VARCHAR(64);unique;not null"`
+	RequestIP string    `gorm:"type:VARCHAR(64);not null"`
+	IsUsed    bool      `gorm:"not null"`
+	UsedIP    string    `gorm:"type:VARCHAR(64);not null"`
+	UsedAt    time.Time `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null"`
+
+	// Token is the raw token, which is only set right after creation.
+	Token string `gorm:"-" json:"-"`
+}
+
+// IsExpired returns true if the token has expired.
+func (t *PasswordResetToken) IsExpired() bool {
+	return !time.Now().Before(t.ExpiresAt)
+}
+
+var _ PasswordResetTokensStore = (*passwordResetTokens)(nil)
+
+type passwordResetTokens struct {
+	*gorm.DB
+}
+
+// NewPasswordResetTokensStore returns a persistent interface for password
+// reset tokens with given database connection.
+func NewPasswordResetTokensStore(db *gorm.DB) PasswordResetTokensStore {
+	return &passwordResetTokens{DB: db}
+}
+
+func (db *passwordResetTokens) Create(ctx context.Context, userID int64, ip string, ttl time.Duration) (*PasswordResetToken, error) {
+	now := db.NowFunc()
+	token := cryptoutil.SHA1(gouuid.NewV4().String())
+	t := &PasswordResetToken{
+		UserID:    userID,
+		SHA256:    cryptoutil.SHA256(token),
+		RequestIP: ip,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("created_at < ?", now.Add(-passwordResetTokenRetention)).Delete(new(PasswordResetToken)).Error
+		if err != nil {
+			return errors.Wrap(err, "delete outdated")
+		}
+
+		err = tx.Model(new(PasswordResetToken)).
+			Where("user_id = ? AND is_used = ? AND expires_at > ?", userID, false, now).
+			Update("expires_at", now).
+			Error
+		if err != nil {
+			return errors.Wrap(err, "expire unused")
+		}
+		return tx.Create(t).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	t.Token = token
+	return t, nil
+}
+
+func (db *passwordResetTokens) DeleteByUserID(ctx context.Context, userID int64) error {
+	return db.WithContext(ctx).Where("user_id = ?", userID).Delete(new(PasswordResetToken)).Error
+}
+
+var _ errutil.NotFound = (*ErrPasswordResetTokenNotExist)(nil)
+
+type ErrPasswordResetTokenNotExist struct {
+	args errutil.Args
+}
+
+func IsErrPasswordResetTokenNotExist(err error) bool {
+	_, ok := err.(ErrPasswordResetTokenNotExist)
+	return ok
+}
+
+func (err ErrPasswordResetTokenNotExist) Error() string {
+	return fmt.Sprintf("password reset token does not exist: %v", err.args)
+}
+
+func (ErrPasswordResetTokenNotExist) NotFound() bool {
+	return true
+}
+
+func (db *passwordResetTokens) GetByToken(ctx context.Context, token string) (*PasswordResetToken, error) {
+	t := new(PasswordResetToken)
+	err := db.WithContext(ctx).
+		Where("sha256 = ? AND is_used = ? AND expires_at > ?", cryptoutil.SHA256(token), false, db.NowFunc()).
+		First(t).
+		Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrPasswordResetTokenNotExist{args: errutil.Args{}}
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+func (db *passwordResetTokens) ListByUserID(ctx context.Context, userID int64, limit int) ([]*PasswordResetToken, error) {
+	tokens := make([]*PasswordResetToken, 0, limit)
+	return tokens, db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&tokens).
+		Error
+}
+
+func (db *passwordResetTokens) Use(ctx context.Context, token, ip string) (*PasswordResetToken, error) {
+	now := db.NowFunc()
+	sha256 := cryptoutil.SHA256(token)
+
+	// The condition of the update guarantees that concurrent requests cannot use
+	// the same token more than once.
+	result := db.WithContext(ctx).Model(new(PasswordResetToken)).
+		Where("sha256 = ? AND is_used = ? AND expires_at > ?", sha256, false, now).
+		Updates(map[string]interface{}{
+			"is_used": true,
+			"used_ip": ip,
+			"used_at": now,
+		})
+	if result.Error != nil {
+		return nil, errors.Wrap(result.Error, "mark as used")
+	} else if result.RowsAffected == 0 {
+		return nil, ErrPasswordResetTokenNotExist{args: errutil.Args{}}
+	}
+
+	t := new(PasswordResetToken)
+	return t, db.WithContext(ctx).Where("sha256 = ?", sha256).First(t).Error
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+func TestPasswordResetTokens(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(PasswordResetToken)}
+	db := &passwordResetTokens{
+		DB: dbtest.NewDB(t, "passwordResetTokens", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *passwordResetTokens)
+	}{
+		{"Create", passwordResetTokensCreate},
+		{"DeleteByUserID", passwordResetTokensDeleteByUserID},
+		{"GetByToken", passwordResetTokensGetByToken},
+		{"ListByUserID", passwordResetTokensListByUserID},
+		{"Use", passwordResetTokensUse},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func passwordResetTokensCreate(t *testing.T, db *passwordResetTokens) {
+	ctx := context.Background()
+
+	first, err := db.Create(ctx, 1, "127.0.0.1", time.Hour)
+	require.NoError(t, err)
+	assert.Len(t, first.Token, 40)
+	assert.Equal(t, "127.0.0.1", first.RequestIP)
+	assert.Equal(t, db.NowFunc().Add(time.Hour).Unix(), first.ExpiresAt.Unix())
+
+	other, err := db.Create(ctx, 2, "127.0.0.1", time.Hour)
+	require.NoError(t, err)
+
+	// Issuing a new token expires unused tokens of the same user.
+	second, err := db.Create(ctx, 1, "127.0.0.2", time.Hour)
+	require.NoError(t, err)
+
+	_, err = db.GetByToken(ctx, first.Token)
+	assert.True(t, IsErrPasswordResetTokenNotExist(err))
+	_, err = db.GetByToken(ctx, second.Token)
+	require.NoError(t, err)
+	_, err = db.GetByToken(ctx, other.Token)
+	require.NoError(t, err)
+
+	// Tokens older than the retention period are deleted.
+	err = db.Model(new(PasswordResetToken)).
+		Where("id = ?", first.ID).
+		Update("created_at", db.NowFunc().Add(-passwordResetTokenRetention-time.Hour)).
+		Error
+	require.NoError(t, err)
+	_, err = db.Create(ctx, 2, "127.0.0.1", time.Hour)
+	require.NoError(t, err)
+
+	var count int64
+	err = db.Model(new(PasswordResetToken)).Count(&count).Error
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
+func passwordResetTokensDeleteByUserID(t *testing.T, db *passwordResetTokens) {
+	ctx := context.Background()
+
+	_, err := db.Create(ctx, 1, "127.0.0.1", time.Hour)
+	require.NoError(t, err)
+	other, err := db.Create(ctx, 2, "127.0.0.1", time.Hour)
+	require.NoError(t, err)
+
+	err = db.DeleteByUserID(ctx, 1)
+	require.NoError(t, err)
+
+	tokens, err := db.ListByUserID(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Empty(t, tokens)
+
+	// Tokens of other users are not affected.
+	_, err = db.GetByToken(ctx, other.Token)
+	require.NoError(t, err)
+}
+
+func passwordResetTokensGetByToken(t *testing.T, db *passwordResetTokens) {
+	ctx := context.Background()
+
+	_, err := db.GetByToken(ctx, "404")
+	assert.True(t, IsErrPasswordResetTokenNotExist(err))
+
+	token, err := db.Create(ctx, 1, "127.0.0.1", time.Hour)
+	require.NoError(t, err)
+
+	got, err := db.GetByToken(ctx, token.Token)
+	require.NoError(t, err)
+	assert.Equal(t, token.ID, got.ID)
+	assert.Equal(t, int64(1), got.UserID)
+
+	// Expired tokens cannot be retrieved.
+	expired, err := db.Create(ctx, 2, "127.0.0.1", -time.Minute)
+	require.NoError(t, err)
+	_, err = db.GetByToken(ctx, expired.Token)
+	assert.True(t, IsErrPasswordResetTokenNotExist(err))
+}
+
+func passwordResetTokensListByUserID(t *testing.T, db *passwordResetTokens) {
+	ctx := context.Background()
+
+	first, err := db.Create(ctx, 1, "127.0.0.1", time.Hour)
+	require.NoError(t, err)
+	second, err := db.Create(ctx, 1, "127.0.0.2", time.Hour)
+	require.NoError(t, err)
+	_, err = db.Create(ctx, 2, "127.0.0.1", time.Hour)
+	require.NoError(t, err)
+
+	tokens, err := db.ListByUserID(ctx, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	assert.Equal(t, second.ID, tokens[0].ID)
+	assert.Equal(t, first.ID, tokens[1].ID)
+
+	tokens, err = db.ListByUserID(ctx, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, second.ID, tokens[0].ID)
+}
+
+func passwordResetTokensUse(t *testing.T, db *passwordResetTokens) {
+	ctx := context.Background()
+
+	_, err := db.Use(ctx, "404", "127.0.0.1")
+	assert.True(t, IsErrPasswordResetTokenNotExist(err))
+
+	token, err := db.Create(ctx, 1, "127.0.0.1", time.Hour)
+	require.NoError(t, err)
+
+	used, err := db.Use(ctx, token.Token, "127.0.0.2")
+	require.NoError(t, err)
+	assert.True(t, used.IsUsed)
+	assert.Equal(t, "127.0.0.1", used.RequestIP)
+	assert.Equal(t, "127.0.0.2", used.UsedIP)
+
+	// Each token can only be used once.
+	_, err = db.Use(ctx, token.Token, "127.0.0.2")
+	assert.True(t, IsErrPasswordResetTokenNotExist(err))
+	_, err = db.GetByToken(ctx, token.Token)
+	assert.True(t, IsErrPasswordResetTokenNotExist(err))
+
+	// Expired tokens cannot be used.
+	expired, err := db.Create(ctx, 2, "127.0.0.1", -time.Minute)
+	require.NoError(t, err)
+	_, err = db.Use(ctx, expired.Token, "127.0.0.2")
+	assert.True(t, IsErrPasswordResetTokenNotExist(err))
+}
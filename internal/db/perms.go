@@ -114,9 +114,44 @@ func (db *perms) AccessMode(ctx context.Context, userID, repoID int64, opts Acce
 		}
 		return mode
 	}
+
+	// Access granted by an organization is withheld from users who have not
+	// enabled two-factor authentication required by the organization.
+	if db.isBlockedByTwoFactorRequirement(ctx, userID, opts.OwnerID) {
+		return mode
+	}
 	return access.Mode
 }
 
+// isBlockedByTwoFactorRequirement returns true if the owner is an organization
+// that enforces two-factor authentication and the user has not enabled it, or
+// when it cannot be determined.
+func (db *perms) isBlockedByTwoFactorRequirement(ctx context.Context, userID, ownerID int64) bool {
+	owner := new(User)
+	err := db.WithContext(ctx).
+		Select("type", "require_two_factor", "two_factor_grace_until_unix").
+		Where("id = ?", ownerID).
+		First(owner).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return false
+		}
+		log.Error("Failed to get owner [id: %d]: %v", ownerID, err)
+		return true
+	}
+	if !owner.IsOrganization() || !owner.IsTwoFactorEnforced() {
+		return false
+	}
+
+	var count int64
+	err = db.WithContext(ctx).Model(new(TwoFactor)).Where("user_id = ?", userID).Count(&count).Error
+	if err != nil {
+		log.Error("Failed to count two-factor authentications [user_id: %d]: %v", userID, err)
+		return true
+	}
+	return count == 0
+}
+
 func (db *perms) Authorize(ctx context.Context, userID, repoID int64, desired AccessMode, opts AccessModeOptions) bool {
 	return desired <= db.AccessMode(ctx, userID, repoID, opts)
 }
@@ -7,6 +7,7 @@ package db
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -20,7 +21,7 @@ func TestPerms(t *testing.T) {
 	}
 	t.Parallel()
 
-	tables := []interface{}{new(Access)}
+	tables := []interface{}{new(Access), new(User), new(TwoFactor)}
 	db := &perms{
 		DB: dbtest.NewDB(t, "perms", tables...),
 	}
@@ -32,6 +33,7 @@ func TestPerms(t *testing.T) {
 		{"AccessMode", permsAccessMode},
 		{"Authorize", permsAuthorize},
 		{"SetRepoPerms", permsSetRepoPerms},
+		{"TwoFactorRequirement", permsTwoFactorRequirement},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Cleanup(func() {
@@ -312,3 +314,49 @@ func permsSetRepoPerms(t *testing.T, db *perms) {
 	}
 	assert.Equal(t, wantAccesses, accesses)
 }
+
+func permsTwoFactorRequirement(t *testing.T, db *perms) {
+	ctx := context.Background()
+
+	org := &User{
+		ID:               90,
+		LowerName:        "org",
+		Name:             "org",
+		Email:            "org@example.com",
+		Type:             UserOrganization,
+		RequireTwoFactor: true,
+	}
+	err := db.Create(org).Error
+	require.NoError(t, err)
+	err = db.Create(&TwoFactor{UserID: 2, Secret: "secret"}).Error
+	require.NoError(t, err)
+
+	err = db.SetRepoPerms(ctx, 1,
+		map[int64]AccessMode{
+			1: AccessModeWrite,
+			2: AccessModeWrite,
+		},
+	)
+	require.NoError(t, err)
+	opts := AccessModeOptions{
+		OwnerID: org.ID,
+		Private: true,
+	}
+
+	t.Run("grace period", func(t *testing.T) {
+		err := db.Model(org).Update("two_factor_grace_until_unix", time.Now().Add(time.Hour).Unix()).Error
+		require.NoError(t, err)
+
+		assert.Equal(t, AccessModeWrite, db.AccessMode(ctx, 1, 1, opts))
+		assert.Equal(t, AccessModeWrite, db.AccessMode(ctx, 2, 1, opts))
+	})
+
+	t.Run("enforced", func(t *testing.T) {
+		err := db.Model(org).Update("two_factor_grace_until_unix", time.Now().Add(-time.Hour).Unix()).Error
+		require.NoError(t, err)
+
+		assert.Equal(t, AccessModeNone, db.AccessMode(ctx, 1, 1, opts))
+		assert.Equal(t, AccessModeRead, db.AccessMode(ctx, 1, 1, AccessModeOptions{OwnerID: org.ID}))
+		assert.Equal(t, AccessModeWrite, db.AccessMode(ctx, 2, 1, opts))
+	})
+}
@@ -0,0 +1,307 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"gogs.io/gogs/internal/errutil"
+)
+
+// ProfileFieldsStore is the persistent interface for custom profile fields of
+// users and metadata fields of organizations defined by admins.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type ProfileFieldsStore interface {
+	// Create creates a new profile field with given name. It returns
+	// ErrProfileFieldAlreadyExist when a field with same name already exists.
+	Create(ctx context.Context, name string, opts CreateProfileFieldOptions) (*ProfileField, error)
+	// DeleteByID deletes the profile field with given ID and all values of it.
+	DeleteByID(ctx context.Context, id int64) error
+	// GetByID returns the profile field with given ID. It returns
+	// ErrProfileFieldNotExist when not found.
+	GetByID(ctx context.Context, id int64) (*ProfileField, error)
+	// List returns profile fields of the scope, ordered by position and then ID.
+	// All profile fields are returned when the scope is empty.
+	List(ctx context.Context, scope ProfileFieldScope) ([]*ProfileField, error)
+	// ListValues returns values of profile fields of the user or organization,
+	// keyed by field IDs.
+	ListValues(ctx context.Context, userID int64) (map[int64]string, error)
+	// SetValues sets values of profile fields keyed by field IDs for the user or
+	// organization. Values of fields not in the map are left unchanged, and an
+	// empty value deletes the existing value.
+	SetValues(ctx context.Context, userID int64, values map[int64]string) error
+	// SyncLDAPAttributes sets values of profile fields of the user that are
+	// synced from LDAP attributes, keyed by attribute names. Fields whose
+	// attributes are missing are cleared.
+	SyncLDAPAttributes(ctx context.Context, userID int64, attrs map[string]string) error
+	// Update updates the profile field with given ID.
+	Update(ctx context.Context, id int64, opts UpdateProfileFieldOptions) error
+}
+
+var ProfileFields ProfileFieldsStore
+
+// ProfileFieldScope is the kind of accounts that a profile field applies to.
+type ProfileFieldScope string
+
+const (
+	ProfileFieldScopeUser         ProfileFieldScope = "user"
+	ProfileFieldScopeOrganization ProfileFieldScope = "organization"
+)
+
+// IsValid returns true if the scope is one of known scopes.
+func (s ProfileFieldScope) IsValid() bool {
+	return s == ProfileFieldScopeUser || s == ProfileFieldScopeOrganization
+}
+
+// ProfileField is a custom profile field of users, or a metadata field of
+// organizations, e.g. employee ID, department or Matrix handle.
+type ProfileField struct {
+	ID int64 `gorm:"primaryKey"`
+	// Name is the key of the field used in the API, e.g. "employee_id".
+	Name  string            `gorm:"unique;not null"`
+	Label string            `gorm:"not null"`
+	Scope ProfileFieldScope `gorm:"type:VARCHAR(12);not null"`
+	// Editable indicates whether users, or owners of organizations, are allowed
+	// to edit values of the field. Values can always be edited by admins.
+	Editable bool `gorm:"not null"`
+	// LDAPAttribute is the LDAP attribute that values of the field are synced
+	// from whenever users sign in via LDAP, empty for not synced.
+	LDAPAttribute string
+	Position      int       `gorm:"not null"`
+	CreatedAt     time.Time `gorm:"not null"`
+}
+
+// ProfileFieldValue is the value of a profile field of a user or an
+// organization.
+type ProfileFieldValue struct {
+	ID        int64     `gorm:"primaryKey"`
+	UserID    int64     `gorm:"uniqueIndex:profile_field_value_user_field_unique;not null"`
+	FieldID   int64     `gorm:"uniqueIndex:profile_field_value_user_field_unique;index;not null"`
+	Value     string    `gorm:"type:TEXT;not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+// ProfileFieldEntry is a profile field with its value of a user or an
+// organization.
+type ProfileFieldEntry struct {
+	*ProfileField
+	Value string
+}
+
+// ListProfileFieldEntries returns profile fields of the scope with values of
+// the user or organization, values of unset fields are empty.
+func ListProfileFieldEntries(ctx context.Context, userID int64, scope ProfileFieldScope) ([]*ProfileFieldEntry, error) {
+	fields, err := ProfileFields.List(ctx, scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "list fields")
+	} else if len(fields) == 0 {
+		return nil, nil
+	}
+
+	values, err := ProfileFields.ListValues(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "list values")
+	}
+
+	entries := make([]*ProfileFieldEntry, len(fields))
+	for i, f := range fields {
+		entries[i] = &ProfileFieldEntry{
+			ProfileField: f,
+			Value:        values[f.ID],
+		}
+	}
+	return entries, nil
+}
+
+var _ ProfileFieldsStore = (*profileFields)(nil)
+
+type profileFields struct {
+	*gorm.DB
+}
+
+// NewProfileFieldsStore returns a persistent interface for profile fields with
+// given database connection.
+func NewProfileFieldsStore(db *gorm.DB) ProfileFieldsStore {
+	return &profileFields{DB: db}
+}
+
+type CreateProfileFieldOptions struct {
+	Label         string
+	Scope         ProfileFieldScope
+	Editable      bool
+	LDAPAttribute string
+	Position      int
+}
+
+type ErrProfileFieldAlreadyExist struct {
+	args errutil.Args
+}
+
+func IsErrProfileFieldAlreadyExist(err error) bool {
+	_, ok := err.(ErrProfileFieldAlreadyExist)
+	return ok
+}
+
+func (err ErrProfileFieldAlreadyExist) Error() string {
+	return fmt.Sprintf("profile field already exists: %v", err.args)
+}
+
+func (db *profileFields) Create(ctx context.Context, name string, opts CreateProfileFieldOptions) (*ProfileField, error) {
+	err := db.WithContext(ctx).Where("name = ?", name).First(new(ProfileField)).Error
+	if err == nil {
+		return nil, ErrProfileFieldAlreadyExist{args: errutil.Args{"name": name}}
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	f := &ProfileField{
+		Name:          name,
+		Label:         opts.Label,
+		Scope:         opts.Scope,
+		Editable:      opts.Editable,
+		LDAPAttribute: opts.LDAPAttribute,
+		Position:      opts.Position,
+	}
+	return f, db.WithContext(ctx).Create(f).Error
+}
+
+func (db *profileFields) DeleteByID(ctx context.Context, id int64) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("field_id = ?", id).Delete(new(ProfileFieldValue)).Error
+		if err != nil {
+			return errors.Wrap(err, "delete values")
+		}
+		return tx.Where("id = ?", id).Delete(new(ProfileField)).Error
+	})
+}
+
+var _ errutil.NotFound = (*ErrProfileFieldNotExist)(nil)
+
+type ErrProfileFieldNotExist struct {
+	args errutil.Args
+}
+
+func IsErrProfileFieldNotExist(err error) bool {
+	_, ok := err.(ErrProfileFieldNotExist)
+	return ok
+}
+
+func (err ErrProfileFieldNotExist) Error() string {
+	return fmt.Sprintf("profile field does not exist: %v", err.args)
+}
+
+func (ErrProfileFieldNotExist) NotFound() bool {
+	return true
+}
+
+func (db *profileFields) GetByID(ctx context.Context, id int64) (*ProfileField, error) {
+	f := new(ProfileField)
+	err := db.WithContext(ctx).Where("id = ?", id).First(f).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrProfileFieldNotExist{args: errutil.Args{"id": id}}
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+func (db *profileFields) List(ctx context.Context, scope ProfileFieldScope) ([]*ProfileField, error) {
+	tx := db.WithContext(ctx)
+	if scope != "" {
+		tx = tx.Where("scope = ?", scope)
+	}
+
+	var fields []*ProfileField
+	return fields, tx.Order("position ASC").Order("id ASC").Find(&fields).Error
+}
+
+func (db *profileFields) ListValues(ctx context.Context, userID int64) (map[int64]string, error) {
+	var values []*ProfileFieldValue
+	err := db.WithContext(ctx).Where("user_id = ?", userID).Find(&values).Error
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[int64]string, len(values))
+	for _, v := range values {
+		m[v.FieldID] = v.Value
+	}
+	return m, nil
+}
+
+func setProfileFieldValues(tx *gorm.DB, userID int64, values map[int64]string) error {
+	for fieldID, value := range values {
+		if value == "" {
+			err := tx.Where("user_id = ? AND field_id = ?", userID, fieldID).Delete(new(ProfileFieldValue)).Error
+			if err != nil {
+				return errors.Wrapf(err, "delete value of field %d", fieldID)
+			}
+			continue
+		}
+
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "field_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+		}).Create(&ProfileFieldValue{
+			UserID:  userID,
+			FieldID: fieldID,
+			Value:   value,
+		}).Error
+		if err != nil {
+			return errors.Wrapf(err, "set value of field %d", fieldID)
+		}
+	}
+	return nil
+}
+
+func (db *profileFields) SetValues(ctx context.Context, userID int64, values map[int64]string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return setProfileFieldValues(tx, userID, values)
+	})
+}
+
+func (db *profileFields) SyncLDAPAttributes(ctx context.Context, userID int64, attrs map[string]string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var fields []*ProfileField
+		err := tx.Where("scope = ? AND ldap_attribute != ?", ProfileFieldScopeUser, "").Find(&fields).Error
+		if err != nil {
+			return errors.Wrap(err, "list fields")
+		}
+
+		values := make(map[int64]string, len(fields))
+		for _, f := range fields {
+			values[f.ID] = attrs[f.LDAPAttribute]
+		}
+		return setProfileFieldValues(tx, userID, values)
+	})
+}
+
+type UpdateProfileFieldOptions struct {
+	Label         string
+	Editable      bool
+	LDAPAttribute string
+	Position      int
+}
+
+func (db *profileFields) Update(ctx context.Context, id int64, opts UpdateProfileFieldOptions) error {
+	return db.WithContext(ctx).
+		Model(new(ProfileField)).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"label":          opts.Label,
+			"editable":       opts.Editable,
+			"ldap_attribute": opts.LDAPAttribute,
+			"position":       opts.Position,
+		}).
+		Error
+}
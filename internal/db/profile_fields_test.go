@@ -0,0 +1,184 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/errutil"
+)
+
+func TestProfileFields(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(ProfileField), new(ProfileFieldValue)}
+	db := &profileFields{
+		DB: dbtest.NewDB(t, "profileFields", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *profileFields)
+	}{
+		{"Create", profileFieldsCreate},
+		{"DeleteByID", profileFieldsDeleteByID},
+		{"List", profileFieldsList},
+		{"SetValues", profileFieldsSetValues},
+		{"SyncLDAPAttributes", profileFieldsSyncLDAPAttributes},
+		{"Update", profileFieldsUpdate},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func profileFieldsCreate(t *testing.T, db *profileFields) {
+	ctx := context.Background()
+
+	f, err := db.Create(ctx, "employee_id", CreateProfileFieldOptions{
+		Label: "Employee ID",
+		Scope: ProfileFieldScopeUser,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Employee ID", f.Label)
+	assert.False(t, f.CreatedAt.IsZero())
+
+	_, err = db.Create(ctx, "employee_id", CreateProfileFieldOptions{Scope: ProfileFieldScopeOrganization})
+	wantErr := ErrProfileFieldAlreadyExist{args: errutil.Args{"name": "employee_id"}}
+	assert.Equal(t, wantErr, err)
+}
+
+func profileFieldsDeleteByID(t *testing.T, db *profileFields) {
+	ctx := context.Background()
+
+	f, err := db.Create(ctx, "department", CreateProfileFieldOptions{Scope: ProfileFieldScopeUser})
+	require.NoError(t, err)
+	err = db.SetValues(ctx, 1, map[int64]string{f.ID: "Engineering"})
+	require.NoError(t, err)
+
+	err = db.DeleteByID(ctx, f.ID)
+	require.NoError(t, err)
+
+	_, err = db.GetByID(ctx, f.ID)
+	wantErr := ErrProfileFieldNotExist{args: errutil.Args{"id": f.ID}}
+	assert.Equal(t, wantErr, err)
+
+	// Values of the field are deleted as well.
+	values, err := db.ListValues(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, values)
+}
+
+func profileFieldsList(t *testing.T, db *profileFields) {
+	ctx := context.Background()
+
+	_, err := db.Create(ctx, "matrix", CreateProfileFieldOptions{Scope: ProfileFieldScopeUser, Position: 2})
+	require.NoError(t, err)
+	_, err = db.Create(ctx, "cost_center", CreateProfileFieldOptions{Scope: ProfileFieldScopeOrganization})
+	require.NoError(t, err)
+	_, err = db.Create(ctx, "department", CreateProfileFieldOptions{Scope: ProfileFieldScopeUser, Position: 1})
+	require.NoError(t, err)
+
+	names := func(fields []*ProfileField) []string {
+		names := make([]string, len(fields))
+		for i := range fields {
+			names[i] = fields[i].Name
+		}
+		return names
+	}
+
+	fields, err := db.List(ctx, ProfileFieldScopeUser)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"department", "matrix"}, names(fields))
+
+	fields, err = db.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cost_center", "department", "matrix"}, names(fields))
+}
+
+func profileFieldsSetValues(t *testing.T, db *profileFields) {
+	ctx := context.Background()
+
+	err := db.SetValues(ctx, 1, map[int64]string{1: "E-1024", 2: "Engineering"})
+	require.NoError(t, err)
+
+	// Existing values are updated, and empty values are deleted.
+	err = db.SetValues(ctx, 1, map[int64]string{1: "E-2048", 2: ""})
+	require.NoError(t, err)
+	err = db.SetValues(ctx, 2, map[int64]string{1: "E-4096"})
+	require.NoError(t, err)
+
+	values, err := db.ListValues(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]string{1: "E-2048"}, values)
+}
+
+func profileFieldsSyncLDAPAttributes(t *testing.T, db *profileFields) {
+	ctx := context.Background()
+
+	employeeID, err := db.Create(ctx, "employee_id", CreateProfileFieldOptions{
+		Scope:         ProfileFieldScopeUser,
+		LDAPAttribute: "employeeNumber",
+	})
+	require.NoError(t, err)
+	department, err := db.Create(ctx, "department", CreateProfileFieldOptions{
+		Scope:         ProfileFieldScopeUser,
+		LDAPAttribute: "departmentNumber",
+	})
+	require.NoError(t, err)
+	matrix, err := db.Create(ctx, "matrix", CreateProfileFieldOptions{Scope: ProfileFieldScopeUser, Editable: true})
+	require.NoError(t, err)
+
+	err = db.SetValues(ctx, 1, map[int64]string{department.ID: "Sales", matrix.ID: "@alice:matrix.org"})
+	require.NoError(t, err)
+
+	err = db.SyncLDAPAttributes(ctx, 1, map[string]string{"employeeNumber": "E-1024"})
+	require.NoError(t, err)
+
+	// Values of fields not synced from LDAP are kept.
+	values, err := db.ListValues(ctx, 1)
+	require.NoError(t, err)
+	want := map[int64]string{
+		employeeID.ID: "E-1024",
+		matrix.ID:     "@alice:matrix.org",
+	}
+	assert.Equal(t, want, values)
+}
+
+func profileFieldsUpdate(t *testing.T, db *profileFields) {
+	ctx := context.Background()
+
+	f, err := db.Create(ctx, "matrix", CreateProfileFieldOptions{Label: "Matrix", Scope: ProfileFieldScopeUser})
+	require.NoError(t, err)
+
+	err = db.Update(ctx, f.ID, UpdateProfileFieldOptions{
+		Label:    "Matrix handle",
+		Editable: true,
+		Position: 3,
+	})
+	require.NoError(t, err)
+
+	f, err = db.GetByID(ctx, f.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Matrix handle", f.Label)
+	assert.True(t, f.Editable)
+	assert.Equal(t, 3, f.Position)
+}
@@ -24,6 +24,7 @@ import (
 	"gogs.io/gogs/internal/osutil"
 	"gogs.io/gogs/internal/process"
 	"gogs.io/gogs/internal/sync"
+	"gogs.io/gogs/internal/tool"
 )
 
 var PullRequestQueue = sync.NewUniqueQueue(1000)
@@ -68,6 +69,10 @@ type PullRequest struct {
 	Merger         *User     `xorm:"-" json:"-"`
 	Merged         time.Time `xorm:"-" json:"-"`
 	MergedUnix     int64
+
+	// ReviewerIDs is the list of IDs of users requested to review the pull
+	// request separated by commas.
+	ReviewerIDs string `xorm:"TEXT"`
 }
 
 func (pr *PullRequest) BeforeUpdate() {
@@ -185,34 +190,66 @@ func (pr *PullRequest) CanAutoMerge() bool {
 	return pr.Status == PULL_REQUEST_STATUS_MERGEABLE
 }
 
+// MissingRequiredDeployments returns environments that the protected base
+// branch requires the head commit of the pull request to be successfully
+// deployed to, but which have not been.
+func (pr *PullRequest) MissingRequiredDeployments() ([]string, error) {
+	protectBranch, err := GetProtectBranchOfRepoByName(pr.BaseRepoID, pr.BaseBranch)
+	if err != nil {
+		if IsErrBranchNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get protect branch of repository by name: %v", err)
+	} else if !protectBranch.Protected {
+		return nil, nil
+	}
+
+	envs := protectBranch.RequiredDeploymentEnvironments()
+	if len(envs) == 0 {
+		return nil, nil
+	}
+
+	if pr.HeadRepo == nil {
+		return envs, nil
+	}
+	headGitRepo, err := git.Open(RepoPath(pr.HeadUserName, pr.HeadRepo.Name))
+	if err != nil {
+		return nil, fmt.Errorf("open repository: %v", err)
+	}
+	headCommitID, err := headGitRepo.BranchCommitID(pr.HeadBranch)
+	if err != nil {
+		return nil, fmt.Errorf("get head commit ID: %v", err)
+	}
+
+	var missing []string
+	for _, env := range envs {
+		ok, err := Deployments.HasSucceeded(context.TODO(), pr.BaseRepoID, env, headCommitID)
+		if err != nil {
+			return nil, fmt.Errorf("check successful deployment to %q: %v", env, err)
+		} else if !ok {
+			missing = append(missing, env)
+		}
+	}
+	return missing, nil
+}
+
 // MergeStyle represents the approach to merge commits into base branch.
 type MergeStyle string
 
 const (
 	MERGE_STYLE_REGULAR MergeStyle = "create_merge_commit"
 	MERGE_STYLE_REBASE  MergeStyle = "rebase_before_merging"
+	MERGE_STYLE_SQUASH  MergeStyle = "squash"
 )
 
 // Merge merges pull request to base repository.
 // FIXME: add repoWorkingPull make sure two merges does not happen at same time.
 func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository, mergeStyle MergeStyle, commitDescription string) (err error) {
-	ctx := context.TODO()
-
 	defer func() {
 		go HookQueue.Add(pr.BaseRepo.ID)
 		go AddTestPullRequestTask(doer, pr.BaseRepo.ID, pr.BaseBranch, false)
 	}()
 
-	sess := x.NewSession()
-	defer sess.Close()
-	if err = sess.Begin(); err != nil {
-		return err
-	}
-
-	if err = pr.Issue.changeStatus(sess, doer, pr.Issue.Repo, true); err != nil {
-		return fmt.Errorf("Issue.changeStatus: %v", err)
-	}
-
 	headRepoPath := RepoPath(pr.HeadUserName, pr.HeadRepo.Name)
 	headGitRepo, err := git.Open(headRepoPath)
 	if err != nil {
@@ -255,10 +292,21 @@ func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository, mergeStyle
 	remoteHeadBranch := "head_repo/" + pr.HeadBranch
 
 	// Check if merge style is allowed, reset to default style if not
-	if mergeStyle == MERGE_STYLE_REBASE && !pr.BaseRepo.PullsAllowRebase {
+	if (mergeStyle == MERGE_STYLE_REBASE && !pr.BaseRepo.PullsAllowRebase) ||
+		(mergeStyle == MERGE_STYLE_SQUASH && !pr.BaseRepo.PullsAllowSquash) {
 		mergeStyle = MERGE_STYLE_REGULAR
 	}
 
+	if err = pr.LoadIssue(); err != nil {
+		return fmt.Errorf("load issue: %v", err)
+	}
+	// Commits are only used to compose the commit message, so failing to list
+	// them should not prevent the merge.
+	commits, err := headGitRepo.RevList([]string{pr.MergeBase + "..." + git.RefsHeads + pr.HeadBranch})
+	if err != nil {
+		log.Error("Failed to list commits of pull request [%d]: %v", pr.ID, err)
+	}
+
 	switch mergeStyle {
 	case MERGE_STYLE_REGULAR: // Create merge commit
 
@@ -274,8 +322,29 @@ func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository, mergeStyle
 		if _, stderr, err = process.ExecDir(-1, tmpBasePath,
 			fmt.Sprintf("PullRequest.Merge (git merge): %s", tmpBasePath),
 			"git", "commit", fmt.Sprintf("--author='%s <%s>'", sig.Name, sig.Email),
-			"-m", fmt.Sprintf("Merge branch '%s' of %s/%s into %s", pr.HeadBranch, pr.HeadUserName, pr.HeadRepo.Name, pr.BaseBranch),
-			"-m", commitDescription); err != nil {
+			"-m", pr.mergeMessage(mergeStyle, commits, commitDescription)); err != nil {
+			return fmt.Errorf("git commit [%s]: %v - %s", tmpBasePath, err, stderr)
+		}
+
+	case MERGE_STYLE_SQUASH: // Squash commits into one
+
+		// Stage changes from head branch.
+		if _, stderr, err = process.ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git merge --squash): %s", tmpBasePath),
+			"git", "merge", "--squash", remoteHeadBranch); err != nil {
+			return fmt.Errorf("git merge --squash [%s]: %v - %s", tmpBasePath, err, stderr)
+		}
+
+		// The squashed commit is authored by the poster of the pull request.
+		author := pr.Issue.Poster
+		if author == nil {
+			author = doer
+		}
+		sig := author.NewGitSig()
+		if _, stderr, err = process.ExecDir(-1, tmpBasePath,
+			fmt.Sprintf("PullRequest.Merge (git commit): %s", tmpBasePath),
+			"git", "commit", fmt.Sprintf("--author='%s <%s>'", sig.Name, sig.Email),
+			"-m", pr.mergeMessage(mergeStyle, commits, commitDescription)); err != nil {
 			return fmt.Errorf("git commit [%s]: %v - %s", tmpBasePath, err, stderr)
 		}
 
@@ -321,11 +390,30 @@ func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository, mergeStyle
 		return fmt.Errorf("git push: %s", stderr)
 	}
 
-	pr.MergedCommitID, err = headGitRepo.BranchCommitID(pr.HeadBranch)
+	mergedCommitID, err := headGitRepo.BranchCommitID(pr.HeadBranch)
 	if err != nil {
 		return fmt.Errorf("get head branch %q commit ID: %v", pr.HeadBranch, err)
 	}
+	return pr.markMerged(doer, baseGitRepo, headGitRepo, mergeStyle, mergedCommitID)
+}
+
+// markMerged marks the pull request as merged after changes of the head commit
+// with given ID have been pushed to the base branch, and fires corresponding
+// actions and webhooks.
+func (pr *PullRequest) markMerged(doer *User, baseGitRepo, headGitRepo *git.Repository, mergeStyle MergeStyle, mergedCommitID string) (err error) {
+	ctx := context.TODO()
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
 
+	if err = pr.Issue.changeStatus(sess, doer, pr.Issue.Repo, true); err != nil {
+		return fmt.Errorf("Issue.changeStatus: %v", err)
+	}
+
+	pr.MergedCommitID = mergedCommitID
 	pr.HasMerged = true
 	pr.Merged = time.Now()
 	pr.MergerID = doer.ID
@@ -340,18 +428,26 @@ func (pr *PullRequest) Merge(doer *User, baseGitRepo *git.Repository, mergeStyle
 	if err = Actions.MergePullRequest(ctx, doer, pr.Issue.Repo.Owner, pr.Issue.Repo, pr.Issue); err != nil {
 		log.Error("Failed to create action for merge pull request, pull_request_id: %d, error: %v", pr.ID, err)
 	}
+	tearDownPreviewDeployments(doer, pr.Issue.Repo, pr.Index)
 
 	// Reload pull request information.
 	if err = pr.LoadAttributes(); err != nil {
 		log.Error("LoadAttributes: %v", err)
 		return nil
 	}
-	if err = PrepareWebhooks(pr.Issue.Repo, HOOK_EVENT_PULL_REQUEST, &api.PullRequestPayload{
-		Action:      api.HOOK_ISSUE_CLOSED,
-		Index:       pr.Index,
-		PullRequest: pr.APIFormat(),
-		Repository:  pr.Issue.Repo.APIFormatLegacy(nil),
-		Sender:      doer.APIFormat(),
+	checklist, err := pr.MergeChecklist(ctx)
+	if err != nil {
+		log.Error("Failed to get merge checklist of pull request %d: %v", pr.ID, err)
+	}
+	if err = PrepareWebhooks(pr.Issue.Repo, HOOK_EVENT_PULL_REQUEST, &MergedPullRequestPayload{
+		PullRequestPayload: &api.PullRequestPayload{
+			Action:      api.HOOK_ISSUE_CLOSED,
+			Index:       pr.Index,
+			PullRequest: pr.APIFormat(),
+			Repository:  pr.Issue.Repo.APIFormatLegacy(nil),
+			Sender:      doer.APIFormat(),
+		},
+		MergeChecklist: checklist,
 	}); err != nil {
 		log.Error("PrepareWebhooks: %v", err)
 		return nil
@@ -624,7 +720,7 @@ func (pr *PullRequest) UpdateCols(cols ...string) error {
 
 // UpdatePatch generates and saves a new patch.
 func (pr *PullRequest) UpdatePatch() (err error) {
-	headGitRepo, err := git.Open(pr.HeadRepo.RepoPath())
+	headGitRepo, err := git.Open(RepoPath(pr.HeadUserName, pr.HeadRepo.Name))
 	if err != nil {
 		return fmt.Errorf("open repository: %v", err)
 	}
@@ -775,6 +871,73 @@ func addHeadRepoTasks(prs []*PullRequest) {
 	}
 }
 
+// rerequestReviews requests reviews again from reviewers of the pull request
+// if its base branch is protected to do so on new commits.
+func (pr *PullRequest) rerequestReviews(doer *User) error {
+	if pr.ReviewerIDs == "" {
+		return nil
+	}
+
+	protectBranch, err := GetProtectBranchOfRepoByName(pr.BaseRepoID, pr.BaseBranch)
+	if err != nil {
+		if IsErrBranchNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("get protect branch of repository by name: %v", err)
+	} else if !protectBranch.Protected || !protectBranch.RerequestReviews {
+		return nil
+	}
+
+	userIDs := tool.StringsToInt64s(strings.Split(pr.ReviewerIDs, ","))
+	reviewers := make([]*User, 0, len(userIDs))
+	for _, userID := range userIDs {
+		u, err := GetUserByID(userID)
+		if err != nil {
+			if IsErrUserNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("get user by ID: %v", err)
+		}
+		reviewers = append(reviewers, u)
+	}
+	return pr.Issue.RequestReviews(doer, reviewers)
+}
+
+// createForcePushComments records the force push to the branch of the
+// repository in the timeline of every open pull request from the branch.
+func createForcePushComments(pusherID, repoID int64, branch, oldCommitID, newCommitID string) error {
+	prs, err := GetUnmergedPullRequestsByHeadInfo(repoID, branch)
+	if err != nil {
+		return fmt.Errorf("get unmerged pull requests by head info: %v", err)
+	} else if len(prs) == 0 {
+		return nil
+	}
+
+	pusher, err := GetUserByID(pusherID)
+	if err != nil {
+		return fmt.Errorf("get pusher: %v", err)
+	}
+
+	for _, pr := range prs {
+		if err = pr.LoadIssue(); err != nil {
+			return fmt.Errorf("load issue: %v", err)
+		}
+
+		_, err = CreateComment(&CreateCommentOptions{
+			Type:      COMMENT_TYPE_FORCE_PUSH,
+			Doer:      pusher,
+			Repo:      pr.Issue.Repo,
+			Issue:     pr.Issue,
+			CommitSHA: newCommitID,
+			Content:   oldCommitID,
+		})
+		if err != nil {
+			return fmt.Errorf("create comment [pull_id: %d]: %v", pr.ID, err)
+		}
+	}
+	return nil
+}
+
 // AddTestPullRequestTask adds new test tasks by given head/base repository and head/base branch,
 // and generate new patch for testing as needed.
 func AddTestPullRequestTask(doer *User, repoID int64, branch string, isSync bool) {
@@ -807,11 +970,17 @@ func AddTestPullRequestTask(doer *User, repoID int64, branch string, isSync bool
 					log.Error("PrepareWebhooks [pull_id: %v]: %v", pr.ID, err)
 					continue
 				}
+				if err = pr.rerequestReviews(doer); err != nil {
+					log.Error("Failed to request reviews again [pull_id: %d]: %v", pr.ID, err)
+				}
 			}
 		}
 	}
 
 	addHeadRepoTasks(prs)
+	for _, pr := range prs {
+		AddMergeQueueTask(pr.BaseRepoID, pr.BaseBranch)
+	}
 
 	log.Trace("AddTestPullRequestTask [base_repo_id: %d, base_branch: %s]: finding pull requests", repoID, branch)
 	prs, err = GetUnmergedPullRequestsByBaseInfo(repoID, branch)
@@ -822,6 +991,7 @@ func AddTestPullRequestTask(doer *User, repoID int64, branch string, isSync bool
 	for _, pr := range prs {
 		pr.AddToTaskQueue()
 	}
+	AddMergeQueueTask(repoID, branch)
 }
 
 func ChangeUsernameInPullRequests(oldUserName, newUserName string) error {
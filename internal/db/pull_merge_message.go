@@ -0,0 +1,126 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gogs/git-module"
+)
+
+// Default templates of merge and squash commit messages, used when the
+// repository does not define its own.
+const (
+	DefaultMergeMessageTemplate  = "Merge branch '${HEAD_BRANCH}' of ${HEAD_REPO} into ${BASE_BRANCH}"
+	DefaultSquashMessageTemplate = "${PR_TITLE} (#${PR_NUMBER})\n\n${COMMIT_MESSAGES}\n\n${CO_AUTHORS}"
+)
+
+// MergeMessageVars is the list of variables available in merge and squash
+// commit message templates.
+var MergeMessageVars = []string{
+	"PR_TITLE",
+	"PR_NUMBER",
+	"PR_DESCRIPTION",
+	"PR_AUTHOR",
+	"HEAD_BRANCH",
+	"HEAD_REPO",
+	"BASE_BRANCH",
+	"COMMIT_MESSAGES",
+	"CO_AUTHORS",
+}
+
+var mergeMessageVarPattern = regexp.MustCompile(`\$\{([A-Z_]+)\}`)
+
+// RenderMergeMessage replaces variables in the form of "${NAME}" in the
+// template with given values. Unknown variables are left as they are.
+func RenderMergeMessage(tpl string, vars map[string]string) string {
+	return mergeMessageVarPattern.ReplaceAllStringFunc(tpl, func(s string) string {
+		v, ok := vars[s[2:len(s)-1]]
+		if !ok {
+			return s
+		}
+		return v
+	})
+}
+
+// mergeMessageTemplate returns the template of commit messages for the merge
+// style of the repository.
+func (repo *Repository) mergeMessageTemplate(mergeStyle MergeStyle) string {
+	if mergeStyle == MERGE_STYLE_SQUASH {
+		if repo.SquashMessageTemplate != "" {
+			return repo.SquashMessageTemplate
+		}
+		return DefaultSquashMessageTemplate
+	}
+
+	if repo.MergeMessageTemplate != "" {
+		return repo.MergeMessageTemplate
+	}
+	return DefaultMergeMessageTemplate
+}
+
+// coAuthors returns "Co-authored-by" trailers of distinct authors of commits,
+// excluding the author with given email. Commits are in reverse chronological
+// order as returned by "git rev-list", and trailers are in chronological order.
+func coAuthors(commits []*git.Commit, excludeEmail string) []string {
+	seen := map[string]bool{
+		strings.ToLower(excludeEmail): true,
+	}
+	var trailers []string
+	for i := len(commits) - 1; i >= 0; i-- {
+		author := commits[i].Author
+		if author == nil || seen[strings.ToLower(author.Email)] {
+			continue
+		}
+		seen[strings.ToLower(author.Email)] = true
+		trailers = append(trailers, fmt.Sprintf("Co-authored-by: %s <%s>", author.Name, author.Email))
+	}
+	return trailers
+}
+
+// mergeMessageVars returns values of template variables for the pull request
+// with given commits to be merged. Authors of commits other than the poster of
+// the pull request are co-authors.
+func (pr *PullRequest) mergeMessageVars(commits []*git.Commit) map[string]string {
+	poster := pr.Issue.Poster
+	if poster == nil {
+		poster = NewGhostUser()
+	}
+
+	messages := make([]string, 0, len(commits))
+	for i := len(commits) - 1; i >= 0; i-- {
+		messages = append(messages, "* "+commits[i].Summary())
+	}
+
+	headRepo := pr.HeadUserName
+	if pr.HeadRepo != nil {
+		headRepo += "/" + pr.HeadRepo.Name
+	}
+	return map[string]string{
+		"PR_TITLE":        pr.Issue.Title,
+		"PR_NUMBER":       strconv.FormatInt(pr.Index, 10),
+		"PR_DESCRIPTION":  pr.Issue.Content,
+		"PR_AUTHOR":       poster.Name,
+		"HEAD_BRANCH":     pr.HeadBranch,
+		"HEAD_REPO":       headRepo,
+		"BASE_BRANCH":     pr.BaseBranch,
+		"COMMIT_MESSAGES": strings.Join(messages, "\n"),
+		"CO_AUTHORS":      strings.Join(coAuthors(commits, poster.Email), "\n"),
+	}
+}
+
+// mergeMessage returns the commit message to merge the pull request with
+// given commits in the merge style, by the template of the base repository.
+// The commit description is appended as a separate paragraph when not empty.
+func (pr *PullRequest) mergeMessage(mergeStyle MergeStyle, commits []*git.Commit, commitDescription string) string {
+	msg := RenderMergeMessage(pr.BaseRepo.mergeMessageTemplate(mergeStyle), pr.mergeMessageVars(commits))
+	if commitDescription != "" {
+		msg += "\n\n" + commitDescription
+	}
+	return strings.TrimSpace(msg)
+}
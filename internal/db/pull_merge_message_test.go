@@ -0,0 +1,90 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/gogs/git-module"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMergeMessage(t *testing.T) {
+	vars := map[string]string{
+		"PR_TITLE":  "Fix typo",
+		"PR_NUMBER": "42",
+	}
+	tests := []struct {
+		tpl  string
+		want string
+	}{
+		{tpl: "", want: ""},
+		{tpl: "${PR_TITLE} (#${PR_NUMBER})", want: "Fix typo (#42)"},
+		{tpl: "Costs $5 and ${PR_TITLE}", want: "Costs $5 and Fix typo"},
+		{tpl: "${UNKNOWN} ${pr_title}", want: "${UNKNOWN} ${pr_title}"},
+	}
+	for _, test := range tests {
+		t.Run(test.tpl, func(t *testing.T) {
+			assert.Equal(t, test.want, RenderMergeMessage(test.tpl, vars))
+		})
+	}
+}
+
+func TestCoAuthors(t *testing.T) {
+	// Commits are in reverse chronological order.
+	commits := []*git.Commit{
+		{Author: &git.Signature{Name: "Bob", Email: "bob@example.com"}},
+		{Author: &git.Signature{Name: "Alice", Email: "alice@example.com"}},
+		{Author: &git.Signature{Name: "Carol", Email: "carol@example.com"}},
+		{Author: &git.Signature{Name: "Bob", Email: "BOB@example.com"}},
+	}
+	got := coAuthors(commits, "Alice@example.com")
+	want := []string{
+		"Co-authored-by: Bob <BOB@example.com>",
+		"Co-authored-by: Carol <carol@example.com>",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestPullRequest_mergeMessage(t *testing.T) {
+	pr := &PullRequest{
+		Index:        7,
+		HeadUserName: "alice",
+		HeadRepo:     &Repository{Name: "fork"},
+		HeadBranch:   "feature",
+		BaseBranch:   "master",
+		BaseRepo:     &Repository{},
+		Issue: &Issue{
+			Title:  "Add feature",
+			Poster: &User{Name: "alice", Email: "alice@example.com"},
+		},
+	}
+	commits := []*git.Commit{
+		{Message: "Address review\n\nDetails", Author: &git.Signature{Name: "Bob", Email: "bob@example.com"}},
+		{Message: "Add feature", Author: &git.Signature{Name: "alice", Email: "alice@example.com"}},
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		assert.Equal(t,
+			"Merge branch 'feature' of alice/fork into master",
+			pr.mergeMessage(MERGE_STYLE_REGULAR, commits, ""),
+		)
+		assert.Equal(t,
+			"Add feature (#7)\n\n* Add feature\n* Address review\n\nCo-authored-by: Bob <bob@example.com>",
+			pr.mergeMessage(MERGE_STYLE_SQUASH, commits, ""),
+		)
+	})
+
+	t.Run("custom templates", func(t *testing.T) {
+		pr.BaseRepo.MergeMessageTemplate = "Merge #${PR_NUMBER} by ${PR_AUTHOR}"
+		pr.BaseRepo.SquashMessageTemplate = "${PR_TITLE}\n\n${CO_AUTHORS}"
+		assert.Equal(t,
+			"Merge #7 by alice\n\nCloses a ticket",
+			pr.mergeMessage(MERGE_STYLE_REGULAR, commits, "Closes a ticket"),
+		)
+		// Empty variables do not leave trailing blank lines.
+		assert.Equal(t, "Add feature", pr.mergeMessage(MERGE_STYLE_SQUASH, commits[1:], ""))
+	})
+}
@@ -0,0 +1,307 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+
+	"gogs.io/gogs/internal/gitutil"
+)
+
+// RefPush is an update of a reference to be checked before being applied.
+type RefPush struct {
+	// RefName is the full name of the reference, e.g. "refs/heads/main".
+	RefName string
+	// OldCommitID is git.EmptyID when the reference is created.
+	OldCommitID string
+	// NewCommitID is git.EmptyID when the reference is deleted.
+	NewCommitID string
+}
+
+type ErrPushRejected struct {
+	Reason string
+}
+
+func IsErrPushRejected(err error) bool {
+	_, ok := err.(ErrPushRejected)
+	return ok
+}
+
+func (err ErrPushRejected) Error() string {
+	return err.Reason
+}
+
+// secretScanMaxFileSize is the max size of files to be scanned for secrets.
+const secretScanMaxFileSize = 1 << 20
+
+// CheckPush checks updates of references of the repository by the pusher
+// against reserved references, email privacy of the pusher, rulesets of the
+// organization, branch protection of the repository, size limits and security
+// policies, before the updates are applied. Objects of new commits must exist
+// in the repository, while the references are not yet updated. It returns
+// ErrPushRejected with the reason when any of the checks fails.
+func CheckPush(ctx context.Context, repo *Repository, pusherID int64, refs []RefPush) error {
+	privateEmails, noReplyEmail, err := pusherPrivateEmails(ctx, pusherID)
+	if err != nil {
+		return errors.Wrap(err, "get private emails of pusher")
+	}
+
+	var newCommitIDs []string
+	for _, ref := range refs {
+		// Speculative merge commits of merge queues are managed by the server.
+		if strings.HasPrefix(ref.RefName, "refs/merge-queue/") {
+			return ErrPushRejected{Reason: fmt.Sprintf("References under refs/merge-queue/ are reserved: %s", ref.RefName)}
+		}
+
+		if ref.NewCommitID != git.EmptyID {
+			newCommitIDs = append(newCommitIDs, ref.NewCommitID)
+
+			if len(privateEmails) > 0 {
+				err = checkEmailExposure(repo, privateEmails, noReplyEmail, ref.NewCommitID)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		branchName := git.RefShortName(ref.RefName)
+
+		// Organization rulesets are enforced regardless of branch protection of
+		// the repository.
+		err = checkOrgRulesets(ctx, repo, branchName, ref.OldCommitID, ref.NewCommitID)
+		if err != nil {
+			return err
+		}
+
+		err = checkProtectBranch(repo, pusherID, branchName, ref.OldCommitID, ref.NewCommitID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Size limits apply to the push as a whole since objects can be shared
+	// between references.
+	err = checkPushSize(repo, newCommitIDs)
+	if err != nil {
+		return err
+	}
+	return checkSecurityPolicy(ctx, repo, newCommitIDs)
+}
+
+// checkProtectBranch rejects the update if it violates branch protection of
+// the repository.
+func checkProtectBranch(repo *Repository, pusherID int64, branchName, oldCommitID, newCommitID string) error {
+	protectBranch, err := GetProtectBranchOfRepoByName(repo.ID, branchName)
+	if err != nil {
+		if IsErrBranchNotExist(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "get protect branch %q", branchName)
+	}
+	if !protectBranch.Protected {
+		return nil
+	}
+
+	// Whitelist users can bypass require pull request check
+	bypassRequirePullRequest := false
+
+	// Check if user is in whitelist when enabled
+	if protectBranch.EnableWhitelist {
+		if !IsUserInProtectBranchWhitelist(repo.ID, pusherID, branchName) {
+			return ErrPushRejected{Reason: fmt.Sprintf("Branch '%s' is protected and you are not in the push whitelist", branchName)}
+		}
+
+		bypassRequirePullRequest = true
+	}
+
+	// Check if branch allows direct push
+	if !bypassRequirePullRequest && protectBranch.RequirePullRequest {
+		return ErrPushRejected{Reason: fmt.Sprintf("Branch '%s' is protected and commits must be merged through pull request", branchName)}
+	}
+
+	if newCommitID == git.EmptyID {
+		return ErrPushRejected{Reason: fmt.Sprintf("Branch '%s' is protected from deletion", branchName)}
+	}
+
+	if oldCommitID == git.EmptyID {
+		return nil
+	}
+	isForce, err := gitutil.IsForcePush(repo.RepoPath(), oldCommitID, newCommitID)
+	if err != nil {
+		return errors.Wrap(err, "detect force push")
+	} else if isForce {
+		return ErrPushRejected{Reason: fmt.Sprintf("Branch '%s' is protected from force push", branchName)}
+	}
+	return nil
+}
+
+// checkOrgRulesets rejects the update if it violates any of the rulesets of the
+// organization that apply to the branch of the repository.
+func checkOrgRulesets(ctx context.Context, repo *Repository, branchName, oldCommitID, newCommitID string) error {
+	rulesets, err := OrgRulesets.Match(ctx, repo.OwnerID, repo.Name, branchName)
+	if err != nil {
+		return errors.Wrapf(err, "match organization rulesets of branch %q", branchName)
+	}
+
+	var changedFiles []string
+	for _, r := range rulesets {
+		if r.RequirePullRequest {
+			return ErrPushRejected{Reason: fmt.Sprintf("Branch '%s' is protected by ruleset '%s' and commits must be merged through pull request", branchName, r.Name)}
+		}
+
+		if newCommitID == git.EmptyID {
+			if r.BlockDeletion {
+				return ErrPushRejected{Reason: fmt.Sprintf("Branch '%s' is protected from deletion by ruleset '%s'", branchName, r.Name)}
+			}
+			continue
+		}
+
+		if r.BlockForcePush && oldCommitID != git.EmptyID {
+			isForce, err := gitutil.IsForcePush(repo.RepoPath(), oldCommitID, newCommitID)
+			if err != nil {
+				return errors.Wrap(err, "detect force push")
+			} else if isForce {
+				return ErrPushRejected{Reason: fmt.Sprintf("Branch '%s' is protected from force push by ruleset '%s'", branchName, r.Name)}
+			}
+		}
+
+		if r.BlockedPaths == "" {
+			continue
+		}
+		if changedFiles == nil {
+			output, err := git.NewCommand("log", "--format=", "--name-only", newCommitID, "--not", "--all").
+				RunInDir(repo.RepoPath())
+			if err != nil {
+				return errors.Wrap(err, "list changed files")
+			}
+			changedFiles = []string{}
+			for _, line := range strings.Split(string(output), "\n") {
+				if line != "" {
+					changedFiles = append(changedFiles, line)
+				}
+			}
+		}
+		if p := r.BlockedPath(changedFiles...); p != "" {
+			return ErrPushRejected{Reason: fmt.Sprintf("Changes to '%s' are blocked by ruleset '%s'", p, r.Name)}
+		}
+	}
+	return nil
+}
+
+// checkPushSize rejects the push when files introduced by the new commits
+// exceed size limits of the repository.
+func checkPushSize(repo *Repository, newCommitIDs []string) error {
+	maxFileSize, maxSize := repo.PushSizeLimits()
+	if maxFileSize <= 0 && maxSize <= 0 {
+		return nil
+	}
+
+	blobs, err := gitutil.ListNewBlobs(repo.RepoPath(), newCommitIDs...)
+	if err != nil {
+		return errors.Wrap(err, "list new blobs")
+	}
+	if msg := CheckPushSize(blobs, maxFileSize, maxSize); msg != "" {
+		return ErrPushRejected{Reason: msg}
+	}
+	return nil
+}
+
+// checkSecurityPolicy rejects the push when the new commits violate security
+// features enforced by the organization that owns the repository.
+func checkSecurityPolicy(ctx context.Context, repo *Repository, newCommitIDs []string) error {
+	if len(newCommitIDs) == 0 {
+		return nil
+	}
+
+	enforced, err := IsSecurityFeatureEnforced(ctx, repo, SecurityFeatureSecretScanning)
+	if err != nil {
+		return errors.Wrap(err, "check secret scanning")
+	}
+	if enforced {
+		blobs, err := gitutil.ListNewBlobs(repo.RepoPath(), newCommitIDs...)
+		if err != nil {
+			return errors.Wrap(err, "list new blobs")
+		}
+		finding, err := gitutil.ScanBlobsForSecrets(repo.RepoPath(), blobs, secretScanMaxFileSize)
+		if err != nil {
+			return errors.Wrap(err, "scan secrets")
+		} else if finding != nil {
+			return ErrPushRejected{Reason: fmt.Sprintf("%s found in '%s' at line %d is blocked by secret scanning", finding.Rule, finding.Path, finding.Line)}
+		}
+	}
+
+	enforced, err = IsSecurityFeatureEnforced(ctx, repo, SecurityFeatureSignedCommits)
+	if err != nil {
+		return errors.Wrap(err, "check signed commits")
+	}
+	if enforced {
+		commits, err := gitutil.ListNewSignedCommits(repo.RepoPath(), newCommitIDs...)
+		if err != nil {
+			return errors.Wrap(err, "list new commits")
+		}
+		for _, c := range commits {
+			verified, err := VerifyCommitSignature(ctx, c)
+			if err != nil {
+				return errors.Wrapf(err, "verify signature of commit %s", c.ID)
+			} else if !verified {
+				return ErrPushRejected{Reason: fmt.Sprintf("Commit %s must be signed with a GPG key of the committer added to the account", c.ID[:7])}
+			}
+		}
+	}
+	return nil
+}
+
+// pusherPrivateEmails returns the set of lowercased email addresses and the
+// noreply email address of the pusher if the pusher blocks pushes that expose
+// their private email addresses.
+func pusherPrivateEmails(ctx context.Context, pusherID int64) (_ map[string]bool, noReplyEmail string, _ error) {
+	if pusherID <= 0 {
+		return nil, "", nil
+	}
+
+	pusher, err := Users.GetByID(ctx, pusherID)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "get pusher")
+	}
+	if !pusher.KeepEmailPrivate || !pusher.BlockEmailExposingPush {
+		return nil, "", nil
+	}
+
+	emails, err := GetEmailAddresses(pusher.ID)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "get email addresses")
+	}
+	privateEmails := make(map[string]bool, len(emails))
+	for _, email := range emails {
+		privateEmails[strings.ToLower(email.Email)] = true
+	}
+	return privateEmails, pusher.NoReplyEmail(), nil
+}
+
+// checkEmailExposure rejects the push if any new commit reachable from the
+// newCommitID is authored or committed with one of the private emails.
+func checkEmailExposure(repo *Repository, privateEmails map[string]bool, noReplyEmail, newCommitID string) error {
+	output, err := git.NewCommand("log", "--format=%H %ae %ce", newCommitID, "--not", "--all").
+		RunInDir(repo.RepoPath())
+	if err != nil {
+		return errors.Wrap(err, "list new commits")
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		if privateEmails[strings.ToLower(fields[1])] || privateEmails[strings.ToLower(fields[2])] {
+			return ErrPushRejected{Reason: fmt.Sprintf("Commit %s exposes your private email address, please use %q instead", fields[0][:7], noReplyEmail)}
+		}
+	}
+	return nil
+}
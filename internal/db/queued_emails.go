@@ -0,0 +1,249 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/email"
+	"gogs.io/gogs/internal/errutil"
+)
+
+// QueuedEmailsStore is the persistent interface for outgoing emails that are
+// pending delivery or failed.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type QueuedEmailsStore interface {
+	email.Queue
+
+	// Count returns the number of queued emails matching the options.
+	Count(ctx context.Context, opts ListQueuedEmailsOptions) (int64, error)
+	// DeleteByID discards the queued email with given ID.
+	DeleteByID(ctx context.Context, id int64) error
+	// GetByID returns the queued email with given ID. It returns
+	// ErrQueuedEmailNotExist when not found.
+	GetByID(ctx context.Context, id int64) (*QueuedEmail, error)
+	// List returns queued emails matching the options in given page, ordered by
+	// the most recently updated first.
+	List(ctx context.Context, opts ListQueuedEmailsOptions) ([]*QueuedEmail, error)
+	// Requeue resets the queued email with given ID to be pending and due for
+	// delivery right away, with the number of attempts reset.
+	Requeue(ctx context.Context, id int64) error
+}
+
+var QueuedEmails QueuedEmailsStore
+
+// QueuedEmailStatus is the delivery status of a queued email.
+type QueuedEmailStatus string
+
+const (
+	QueuedEmailPending QueuedEmailStatus = "pending"
+	QueuedEmailFailed  QueuedEmailStatus = "failed"
+)
+
+// IsValid returns true if the status is one of known statuses.
+func (s QueuedEmailStatus) IsValid() bool {
+	return s == QueuedEmailPending || s == QueuedEmailFailed
+}
+
+// QueuedEmail is an outgoing email that is pending delivery or has failed to
+// be delivered. Emails are removed once delivered.
+type QueuedEmail struct {
+	ID     int64  `gorm:"primaryKey"`
+	Sender string `gorm:"not null"`
+	// Recipients is the comma-separated list of recipients.
+	Recipients string `gorm:"type:TEXT;not null"`
+	Subject    string `gorm:"type:TEXT"`
+	Info       string `gorm:"type:TEXT"`
+	// Body is the raw message including headers.
+	Body          []byte            `gorm:"not null"`
+	Status        QueuedEmailStatus `gorm:"type:VARCHAR(7);index:queued_email_status_next_attempt;not null"`
+	Attempts      int               `gorm:"not null;default:0"`
+	LastError     string            `gorm:"type:TEXT"`
+	NextAttemptAt time.Time         `gorm:"index:queued_email_status_next_attempt;not null"`
+	CreatedAt     time.Time         `gorm:"not null"`
+	UpdatedAt     time.Time         `gorm:"not null"`
+}
+
+// RecipientList returns the list of recipients of the email.
+func (e *QueuedEmail) RecipientList() []string {
+	if e.Recipients == "" {
+		return nil
+	}
+	return strings.Split(e.Recipients, ",")
+}
+
+var _ QueuedEmailsStore = (*queuedEmails)(nil)
+
+type queuedEmails struct {
+	*gorm.DB
+}
+
+// NewQueuedEmailsStore returns a persistent interface for outgoing emails with
+// given database connection.
+func NewQueuedEmailsStore(db *gorm.DB) QueuedEmailsStore {
+	return &queuedEmails{DB: db}
+}
+
+type ListQueuedEmailsOptions struct {
+	// Status filters emails by status when not empty.
+	Status QueuedEmailStatus
+	// Page is the 1-based page number, all emails are listed if zero.
+	Page     int
+	PageSize int
+}
+
+func (opts ListQueuedEmailsOptions) apply(tx *gorm.DB) *gorm.DB {
+	if opts.Status != "" {
+		tx = tx.Where("status = ?", opts.Status)
+	}
+	return tx
+}
+
+func (db *queuedEmails) Count(ctx context.Context, opts ListQueuedEmailsOptions) (int64, error) {
+	var count int64
+	return count, opts.apply(db.WithContext(ctx).Model(new(QueuedEmail))).Count(&count).Error
+}
+
+func (db *queuedEmails) DeleteByID(ctx context.Context, id int64) error {
+	return db.WithContext(ctx).Where("id = ?", id).Delete(new(QueuedEmail)).Error
+}
+
+func (db *queuedEmails) Enqueue(ctx context.Context, msg *email.QueuedMessage) error {
+	e := &QueuedEmail{
+		Sender:        msg.From,
+		Recipients:    strings.Join(msg.To, ","),
+		Subject:       msg.Subject,
+		Info:          msg.Info,
+		Body:          msg.Body,
+		Status:        QueuedEmailPending,
+		NextAttemptAt: msg.NextAttempt.UTC(),
+	}
+	err := db.WithContext(ctx).Create(e).Error
+	if err != nil {
+		return err
+	}
+	msg.ID = e.ID
+	return nil
+}
+
+var _ errutil.NotFound = (*ErrQueuedEmailNotExist)(nil)
+
+type ErrQueuedEmailNotExist struct {
+	args errutil.Args
+}
+
+func IsErrQueuedEmailNotExist(err error) bool {
+	_, ok := err.(ErrQueuedEmailNotExist)
+	return ok
+}
+
+func (err ErrQueuedEmailNotExist) Error() string {
+	return fmt.Sprintf("queued email does not exist: %v", err.args)
+}
+
+func (ErrQueuedEmailNotExist) NotFound() bool {
+	return true
+}
+
+func (db *queuedEmails) GetByID(ctx context.Context, id int64) (*QueuedEmail, error) {
+	e := new(QueuedEmail)
+	err := db.WithContext(ctx).Where("id = ?", id).First(e).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrQueuedEmailNotExist{args: errutil.Args{"id": id}}
+		}
+		return nil, err
+	}
+	return e, nil
+}
+
+func (db *queuedEmails) List(ctx context.Context, opts ListQueuedEmailsOptions) ([]*QueuedEmail, error) {
+	tx := opts.apply(db.WithContext(ctx))
+	if opts.Page > 0 {
+		tx = tx.Limit(opts.PageSize).Offset((opts.Page - 1) * opts.PageSize)
+	}
+
+	var emails []*QueuedEmail
+	return emails, tx.Order("updated_at DESC").Order("id DESC").Find(&emails).Error
+}
+
+func (db *queuedEmails) ListDue(ctx context.Context, limit int) ([]*email.QueuedMessage, error) {
+	var emails []*QueuedEmail
+	err := db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", QueuedEmailPending, db.NowFunc()).
+		Order("next_attempt_at ASC").
+		Order("id ASC").
+		Limit(limit).
+		Find(&emails).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]*email.QueuedMessage, len(emails))
+	for i, e := range emails {
+		msgs[i] = &email.QueuedMessage{
+			ID:          e.ID,
+			From:        e.Sender,
+			To:          e.RecipientList(),
+			Subject:     e.Subject,
+			Info:        e.Info,
+			Body:        e.Body,
+			Attempts:    e.Attempts,
+			NextAttempt: e.NextAttemptAt,
+		}
+	}
+	return msgs, nil
+}
+
+func (db *queuedEmails) MarkFailed(ctx context.Context, id int64, lastErr string) error {
+	return db.WithContext(ctx).
+		Model(new(QueuedEmail)).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     QueuedEmailFailed,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": lastErr,
+			"updated_at": db.NowFunc(),
+		}).
+		Error
+}
+
+func (db *queuedEmails) MarkRetry(ctx context.Context, id int64, lastErr string, retryAt time.Time) error {
+	return db.WithContext(ctx).
+		Model(new(QueuedEmail)).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"last_error":      lastErr,
+			"next_attempt_at": retryAt.UTC(),
+			"updated_at":      db.NowFunc(),
+		}).
+		Error
+}
+
+func (db *queuedEmails) MarkSent(ctx context.Context, id int64) error {
+	return db.DeleteByID(ctx, id)
+}
+
+func (db *queuedEmails) Requeue(ctx context.Context, id int64) error {
+	now := db.NowFunc()
+	return db.WithContext(ctx).
+		Model(new(QueuedEmail)).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          QueuedEmailPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+			"updated_at":      now,
+		}).
+		Error
+}
@@ -0,0 +1,205 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/email"
+)
+
+func TestQueuedEmails(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(QueuedEmail)}
+	db := &queuedEmails{
+		DB: dbtest.NewDB(t, "queuedEmails", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *queuedEmails)
+	}{
+		{"Enqueue", queuedEmailsEnqueue},
+		{"GetByID", queuedEmailsGetByID},
+		{"List", queuedEmailsList},
+		{"ListDue", queuedEmailsListDue},
+		{"MarkFailed", queuedEmailsMarkFailed},
+		{"MarkRetry", queuedEmailsMarkRetry},
+		{"MarkSent", queuedEmailsMarkSent},
+		{"Requeue", queuedEmailsRequeue},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func enqueueTestEmail(t *testing.T, db *queuedEmails, nextAttempt time.Time, to ...string) *email.QueuedMessage {
+	msg := &email.QueuedMessage{
+		From:        "Gogs <noreply@example.com>",
+		To:          to,
+		Subject:     "[Gogs] Test",
+		Info:        "test",
+		Body:        []byte("Subject: [Gogs] Test\r\n\r\nHello"),
+		NextAttempt: nextAttempt,
+	}
+	require.NoError(t, db.Enqueue(context.Background(), msg))
+	return msg
+}
+
+func queuedEmailsEnqueue(t *testing.T, db *queuedEmails) {
+	ctx := context.Background()
+
+	msg := enqueueTestEmail(t, db, time.Now(), "alice@example.com", "bob@example.com")
+	assert.NotZero(t, msg.ID)
+
+	got, err := db.GetByID(ctx, msg.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Gogs <noreply@example.com>", got.Sender)
+	assert.Equal(t, []string{"alice@example.com", "bob@example.com"}, got.RecipientList())
+	assert.Equal(t, "[Gogs] Test", got.Subject)
+	assert.Equal(t, msg.Body, got.Body)
+	assert.Equal(t, QueuedEmailPending, got.Status)
+	assert.Zero(t, got.Attempts)
+}
+
+func queuedEmailsGetByID(t *testing.T, db *queuedEmails) {
+	_, err := db.GetByID(context.Background(), 404)
+	wantErr := ErrQueuedEmailNotExist{args: map[string]interface{}{"id": int64(404)}}
+	assert.Equal(t, wantErr, err)
+}
+
+func queuedEmailsList(t *testing.T, db *queuedEmails) {
+	ctx := context.Background()
+
+	msg1 := enqueueTestEmail(t, db, time.Now(), "alice@example.com")
+	msg2 := enqueueTestEmail(t, db, time.Now(), "bob@example.com")
+	msg3 := enqueueTestEmail(t, db, time.Now(), "carol@example.com")
+	require.NoError(t, db.MarkFailed(ctx, msg2.ID, "connection refused"))
+
+	count, err := db.Count(ctx, ListQueuedEmailsOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	count, err = db.Count(ctx, ListQueuedEmailsOptions{Status: QueuedEmailFailed})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	emails, err := db.List(ctx, ListQueuedEmailsOptions{Status: QueuedEmailPending})
+	require.NoError(t, err)
+	require.Len(t, emails, 2)
+	assert.Equal(t, msg3.ID, emails[0].ID)
+	assert.Equal(t, msg1.ID, emails[1].ID)
+
+	emails, err = db.List(ctx, ListQueuedEmailsOptions{Status: QueuedEmailPending, Page: 2, PageSize: 1})
+	require.NoError(t, err)
+	require.Len(t, emails, 1)
+	assert.Equal(t, msg1.ID, emails[0].ID)
+}
+
+func queuedEmailsListDue(t *testing.T, db *queuedEmails) {
+	ctx := context.Background()
+
+	now := time.Now()
+	msg1 := enqueueTestEmail(t, db, now.Add(-time.Minute), "alice@example.com")
+	msg2 := enqueueTestEmail(t, db, now.Add(-time.Hour), "bob@example.com", "carol@example.com")
+	enqueueTestEmail(t, db, now.Add(time.Hour), "dave@example.com")
+	msg4 := enqueueTestEmail(t, db, now.Add(-time.Hour), "erin@example.com")
+	require.NoError(t, db.MarkFailed(ctx, msg4.ID, "connection refused"))
+
+	due, err := db.ListDue(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, msg2.ID, due[0].ID)
+	assert.Equal(t, []string{"bob@example.com", "carol@example.com"}, due[0].To)
+	assert.Equal(t, msg2.Body, due[0].Body)
+	assert.Equal(t, msg1.ID, due[1].ID)
+
+	due, err = db.ListDue(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, msg2.ID, due[0].ID)
+}
+
+func queuedEmailsMarkFailed(t *testing.T, db *queuedEmails) {
+	ctx := context.Background()
+
+	msg := enqueueTestEmail(t, db, time.Now(), "alice@example.com")
+	require.NoError(t, db.MarkFailed(ctx, msg.ID, "connection refused"))
+
+	got, err := db.GetByID(ctx, msg.ID)
+	require.NoError(t, err)
+	assert.Equal(t, QueuedEmailFailed, got.Status)
+	assert.Equal(t, 1, got.Attempts)
+	assert.Equal(t, "connection refused", got.LastError)
+}
+
+func queuedEmailsMarkRetry(t *testing.T, db *queuedEmails) {
+	ctx := context.Background()
+
+	msg := enqueueTestEmail(t, db, time.Now(), "alice@example.com")
+	retryAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	require.NoError(t, db.MarkRetry(ctx, msg.ID, "connection refused", retryAt))
+
+	got, err := db.GetByID(ctx, msg.ID)
+	require.NoError(t, err)
+	assert.Equal(t, QueuedEmailPending, got.Status)
+	assert.Equal(t, 1, got.Attempts)
+	assert.Equal(t, "connection refused", got.LastError)
+	assert.Equal(t, retryAt, got.NextAttemptAt.UTC())
+
+	// Not due until the next attempt.
+	due, err := db.ListDue(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
+
+func queuedEmailsMarkSent(t *testing.T, db *queuedEmails) {
+	ctx := context.Background()
+
+	msg := enqueueTestEmail(t, db, time.Now(), "alice@example.com")
+	require.NoError(t, db.MarkSent(ctx, msg.ID))
+
+	_, err := db.GetByID(ctx, msg.ID)
+	assert.True(t, IsErrQueuedEmailNotExist(err))
+}
+
+func queuedEmailsRequeue(t *testing.T, db *queuedEmails) {
+	ctx := context.Background()
+
+	msg := enqueueTestEmail(t, db, time.Now(), "alice@example.com")
+	require.NoError(t, db.MarkRetry(ctx, msg.ID, "connection refused", time.Now().Add(time.Hour)))
+	require.NoError(t, db.MarkFailed(ctx, msg.ID, "connection refused"))
+
+	require.NoError(t, db.Requeue(ctx, msg.ID))
+	got, err := db.GetByID(ctx, msg.ID)
+	require.NoError(t, err)
+	assert.Equal(t, QueuedEmailPending, got.Status)
+	assert.Zero(t, got.Attempts)
+	// The last error is kept for reference.
+	assert.Equal(t, "connection refused", got.LastError)
+
+	due, err := db.ListDue(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, msg.ID, due[0].ID)
+}
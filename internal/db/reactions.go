@@ -0,0 +1,177 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"gogs.io/gogs/internal/errutil"
+)
+
+// ReactionsStore is the persistent interface for reactions of users to issues
+// and pull requests.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type ReactionsStore interface {
+	// Create adds the reaction of the user to the issue, and increases counters
+	// of the issue accordingly. It returns ErrReactionInvalid when the content is
+	// not one of ReactionContents. Adding an existing reaction is a no-op.
+	Create(ctx context.Context, issueID, userID int64, content string) error
+	// Delete removes the reaction of the user from the issue, and decreases
+	// counters of the issue accordingly. Removing a nonexistent reaction is a
+	// no-op.
+	Delete(ctx context.Context, issueID, userID int64, content string) error
+	// List returns all reactions to the issue, ordered by the earliest added.
+	List(ctx context.Context, issueID int64) ([]*Reaction, error)
+}
+
+var Reactions ReactionsStore
+
+// Reaction is a reaction of a user to an issue or a pull request.
+type Reaction struct {
+	ID        int64     `gorm:"primaryKey"`
+	IssueID   int64     `gorm:"uniqueIndex:reaction_issue_user_content_unique;not null"`
+	UserID    int64     `gorm:"uniqueIndex:reaction_issue_user_content_unique;not null"`
+	Content   string    `gorm:"type:VARCHAR(20);uniqueIndex:reaction_issue_user_content_unique;not null"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+// ReactionThumbsUp is the content of the reaction that is counted separately
+// for prioritizing issues.
+const ReactionThumbsUp = "thumbs_up"
+
+// ReactionContents is the list of supported contents of reactions in the order
+// of display.
+var ReactionContents = []string{ReactionThumbsUp, "thumbs_down", "laugh", "hooray", "confused", "heart"}
+
+var reactionEmojis = map[string]string{
+	ReactionThumbsUp: "👍",
+	"thumbs_down":    "👎",
+	"laugh":          "😄",
+	"hooray":         "🎉",
+	"confused":       "😕",
+	"heart":          "❤️",
+}
+
+// IsValidReactionContent returns true if the content is one of
+// ReactionContents.
+func IsValidReactionContent(content string) bool {
+	_, ok := reactionEmojis[content]
+	return ok
+}
+
+type ErrReactionInvalid struct {
+	args errutil.Args
+}
+
+func IsErrReactionInvalid(err error) bool {
+	_, ok := err.(ErrReactionInvalid)
+	return ok
+}
+
+func (err ErrReactionInvalid) Error() string {
+	return fmt.Sprintf("reaction is invalid: %v", err.args)
+}
+
+// ReactionSummary is the number of reactions of a content to an issue.
+type ReactionSummary struct {
+	Content string
+	Emoji   string
+	Count   int
+	// Reacted indicates whether the viewer has given the reaction.
+	Reacted bool
+}
+
+// SummarizeReactions returns summaries of reactions for all of
+// ReactionContents, where Reacted is set for reactions given by the viewer.
+func SummarizeReactions(reactions []*Reaction, viewerID int64) []*ReactionSummary {
+	summaries := make([]*ReactionSummary, len(ReactionContents))
+	byContent := make(map[string]*ReactionSummary, len(ReactionContents))
+	for i, content := range ReactionContents {
+		summaries[i] = &ReactionSummary{
+			Content: content,
+			Emoji:   reactionEmojis[content],
+		}
+		byContent[content] = summaries[i]
+	}
+
+	for _, r := range reactions {
+		s, ok := byContent[r.Content]
+		if !ok {
+			continue
+		}
+		s.Count++
+		if viewerID > 0 && r.UserID == viewerID {
+			s.Reacted = true
+		}
+	}
+	return summaries
+}
+
+var _ ReactionsStore = (*reactions)(nil)
+
+type reactions struct {
+	*gorm.DB
+}
+
+// NewReactionsStore returns a persistent interface for reactions with given
+// database connection.
+func NewReactionsStore(db *gorm.DB) ReactionsStore {
+	return &reactions{DB: db}
+}
+
+// updateIssueReactionCounters changes counters of reactions of the issue by
+// delta.
+func updateIssueReactionCounters(tx *gorm.DB, issueID int64, content string, delta int) error {
+	updates := map[string]interface{}{
+		"num_reactions": gorm.Expr("num_reactions + ?", delta),
+	}
+	if content == ReactionThumbsUp {
+		updates["num_thumbs_up"] = gorm.Expr("num_thumbs_up + ?", delta)
+	}
+	return tx.Model(new(Issue)).Where("id = ?", issueID).UpdateColumns(updates).Error
+}
+
+func (db *reactions) Create(ctx context.Context, issueID, userID int64, content string) error {
+	if !IsValidReactionContent(content) {
+		return ErrReactionInvalid{args: errutil.Args{"content": content}}
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&Reaction{
+			IssueID: issueID,
+			UserID:  userID,
+			Content: content,
+		})
+		if result.Error != nil {
+			return result.Error
+		} else if result.RowsAffected == 0 {
+			return nil
+		}
+		return updateIssueReactionCounters(tx, issueID, content, 1)
+	})
+}
+
+func (db *reactions) Delete(ctx context.Context, issueID, userID int64, content string) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("issue_id = ? AND user_id = ? AND content = ?", issueID, userID, content).Delete(new(Reaction))
+		if result.Error != nil {
+			return result.Error
+		} else if result.RowsAffected == 0 {
+			return nil
+		}
+		return updateIssueReactionCounters(tx, issueID, content, -1)
+	})
+}
+
+func (db *reactions) List(ctx context.Context, issueID int64) ([]*Reaction, error) {
+	reactions := make([]*Reaction, 0, 10)
+	return reactions, db.WithContext(ctx).Where("issue_id = ?", issueID).Order("id ASC").Find(&reactions).Error
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+func TestReactions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(Issue), new(Reaction)}
+	db := &reactions{
+		DB: dbtest.NewDB(t, "reactions", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *reactions)
+	}{
+		{"Create", reactionsCreate},
+		{"Delete", reactionsDelete},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func createTestIssue(t *testing.T, db *gorm.DB) *Issue {
+	t.Helper()
+
+	issue := &Issue{RepoID: 1, Index: 1, Title: "Test"}
+	require.NoError(t, db.Create(issue).Error)
+	return issue
+}
+
+func assertIssueReactionCounters(t *testing.T, db *gorm.DB, issueID int64, wantReactions, wantThumbsUp int) {
+	t.Helper()
+
+	issue := new(Issue)
+	require.NoError(t, db.Where("id = ?", issueID).First(issue).Error)
+	assert.Equal(t, wantReactions, issue.NumReactions)
+	assert.Equal(t, wantThumbsUp, issue.NumThumbsUp)
+}
+
+func reactionsCreate(t *testing.T, db *reactions) {
+	ctx := context.Background()
+	issue := createTestIssue(t, db.DB)
+
+	err := db.Create(ctx, issue.ID, 1, "rocket")
+	assert.True(t, IsErrReactionInvalid(err), "want ErrReactionInvalid but got %v", err)
+
+	require.NoError(t, db.Create(ctx, issue.ID, 1, ReactionThumbsUp))
+	require.NoError(t, db.Create(ctx, issue.ID, 2, ReactionThumbsUp))
+	require.NoError(t, db.Create(ctx, issue.ID, 1, "heart"))
+	// Adding the same reaction again is a no-op.
+	require.NoError(t, db.Create(ctx, issue.ID, 1, ReactionThumbsUp))
+	assertIssueReactionCounters(t, db.DB, issue.ID, 3, 2)
+
+	reactions, err := db.List(ctx, issue.ID)
+	require.NoError(t, err)
+	require.Len(t, reactions, 3)
+
+	summaries := SummarizeReactions(reactions, 2)
+	require.Len(t, summaries, len(ReactionContents))
+	assert.Equal(t, &ReactionSummary{Content: ReactionThumbsUp, Emoji: "👍", Count: 2, Reacted: true}, summaries[0])
+	assert.Equal(t, &ReactionSummary{Content: "heart", Emoji: "❤️", Count: 1, Reacted: false}, summaries[5])
+}
+
+func reactionsDelete(t *testing.T, db *reactions) {
+	ctx := context.Background()
+	issue := createTestIssue(t, db.DB)
+
+	require.NoError(t, db.Create(ctx, issue.ID, 1, ReactionThumbsUp))
+	require.NoError(t, db.Create(ctx, issue.ID, 1, "laugh"))
+
+	require.NoError(t, db.Delete(ctx, issue.ID, 1, ReactionThumbsUp))
+	// Removing a nonexistent reaction is a no-op.
+	require.NoError(t, db.Delete(ctx, issue.ID, 1, ReactionThumbsUp))
+	require.NoError(t, db.Delete(ctx, issue.ID, 2, "laugh"))
+	assertIssueReactionCounters(t, db.DB, issue.ID, 1, 0)
+
+	reactions, err := db.List(ctx, issue.ID)
+	require.NoError(t, err)
+	require.Len(t, reactions, 1)
+	assert.Equal(t, "laugh", reactions[0].Content)
+}
@@ -0,0 +1,268 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/repoutil"
+)
+
+// recountCounter is a denormalized counter column to be recomputed.
+type recountCounter struct {
+	// Name is the unique name of the counter, e.g. "repository.num_stars".
+	Name   string
+	Table  string
+	Column string
+	// Where restricts rows of the table that have the counter, e.g. only
+	// organizations.
+	Where string
+	Args  []interface{}
+	// Count returns the actual values of the counter of rows with given IDs.
+	// Rows that are missing from the result are left unchanged.
+	Count func(ctx context.Context, db *gorm.DB, ids []int64) (map[int64]int64, error)
+}
+
+// countRowsBy returns a count function that counts rows of the table grouped by
+// the key column, with an optional condition of rows to count.
+func countRowsBy(table, key, where string, args ...interface{}) func(context.Context, *gorm.DB, []int64) (map[int64]int64, error) {
+	return func(ctx context.Context, db *gorm.DB, ids []int64) (map[int64]int64, error) {
+		q := db.WithContext(ctx).
+			Table(table).
+			Select(key+" AS ref_id, COUNT(*) AS num").
+			Where(key+" IN ?", ids)
+		if where != "" {
+			q = q.Where(where, args...)
+		}
+
+		var rows []struct {
+			RefID int64
+			Num   int64
+		}
+		err := q.Group(key).Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+
+		counts := make(map[int64]int64, len(ids))
+		for _, id := range ids {
+			counts[id] = 0
+		}
+		for _, row := range rows {
+			counts[row.RefID] = row.Num
+		}
+		return counts, nil
+	}
+}
+
+// countRepoSizes returns the sizes of Git objects of repositories on disk.
+// Repositories that failed to count are skipped.
+func countRepoSizes(ctx context.Context, db *gorm.DB, ids []int64) (map[int64]int64, error) {
+	var repos []*Repository
+	err := db.WithContext(ctx).Select("id", "owner_id", "lower_name", "storage_shard").Where("id IN ?", ids).Find(&repos).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "list repositories")
+	}
+
+	ownerIDs := make([]int64, 0, len(repos))
+	for _, repo := range repos {
+		ownerIDs = append(ownerIDs, repo.OwnerID)
+	}
+	var owners []*User
+	err = db.WithContext(ctx).Select("id", "lower_name").Where("id IN ?", ownerIDs).Find(&owners).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "list owners")
+	}
+	ownerNames := make(map[int64]string, len(owners))
+	for _, owner := range owners {
+		ownerNames[owner.ID] = owner.LowerName
+	}
+
+	sizes := make(map[int64]int64, len(repos))
+	for _, repo := range repos {
+		ownerName, ok := ownerNames[repo.OwnerID]
+		if !ok {
+			continue
+		}
+		countObject, err := git.CountObjects(repoutil.ShardRepositoryPath(repo.StorageShard, ownerName, repo.LowerName))
+		if err != nil {
+			log.Error("Failed to count objects of repository [id: %d]: %v", repo.ID, err)
+			continue
+		}
+		sizes[repo.ID] = countObject.Size + countObject.SizePack
+	}
+	return sizes, nil
+}
+
+var recountCounters = []*recountCounter{
+	{Name: "repository.num_watches", Table: "repository", Column: "num_watches", Count: countRowsBy("watch", "repo_id", "")},
+	{Name: "repository.num_stars", Table: "repository", Column: "num_stars", Count: countRowsBy("star", "repo_id", "")},
+	{Name: "repository.num_forks", Table: "repository", Column: "num_forks", Count: countRowsBy("repository", "fork_id", "is_fork = ?", true)},
+	{Name: "repository.num_issues", Table: "repository", Column: "num_issues", Count: countRowsBy("issue", "repo_id", "is_pull = ?", false)},
+	{Name: "repository.num_closed_issues", Table: "repository", Column: "num_closed_issues", Count: countRowsBy("issue", "repo_id", "is_pull = ? AND is_closed = ?", false, true)},
+	{Name: "repository.num_pulls", Table: "repository", Column: "num_pulls", Count: countRowsBy("issue", "repo_id", "is_pull = ?", true)},
+	{Name: "repository.num_closed_pulls", Table: "repository", Column: "num_closed_pulls", Count: countRowsBy("issue", "repo_id", "is_pull = ? AND is_closed = ?", true, true)},
+	{Name: "repository.num_milestones", Table: "repository", Column: "num_milestones", Count: countRowsBy("milestone", "repo_id", "")},
+	{Name: "repository.num_closed_milestones", Table: "repository", Column: "num_closed_milestones", Count: countRowsBy("milestone", "repo_id", "is_closed = ?", true)},
+	{Name: "repository.size", Table: "repository", Column: "size", Count: countRepoSizes},
+	{Name: "user.num_repos", Table: "user", Column: "num_repos", Count: countRowsBy("repository", "owner_id", "")},
+	{Name: "user.num_stars", Table: "user", Column: "num_stars", Where: "type = ?", Args: []interface{}{UserIndividual}, Count: countRowsBy("star", "uid", "")},
+	{Name: "user.num_followers", Table: "user", Column: "num_followers", Where: "type = ?", Args: []interface{}{UserIndividual}, Count: countRowsBy("follow", "follow_id", "")},
+	{Name: "user.num_following", Table: "user", Column: "num_following", Where: "type = ?", Args: []interface{}{UserIndividual}, Count: countRowsBy("follow", "user_id", "")},
+	{Name: "organization.num_members", Table: "user", Column: "num_members", Where: "type = ?", Args: []interface{}{UserOrganization}, Count: countRowsBy("org_user", "org_id", "")},
+	{Name: "organization.num_teams", Table: "user", Column: "num_teams", Where: "type = ?", Args: []interface{}{UserOrganization}, Count: countRowsBy("team", "org_id", "")},
+}
+
+// RecountCounterNames returns names of all counters that can be recounted.
+func RecountCounterNames() []string {
+	names := make([]string, 0, len(recountCounters))
+	for _, c := range recountCounters {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// RecountProgress is the progress of recounting a counter.
+type RecountProgress struct {
+	Counter string
+	// Checked is the number of rows that have been checked so far.
+	Checked int64
+	// Total is the number of rows to check.
+	Total int64
+	// Fixed is the number of rows whose counter had drifted so far.
+	Fixed int64
+}
+
+// RecountOptions contains options to recount counters.
+type RecountOptions struct {
+	// Counters is the list of names of counters to recount, all counters are
+	// recounted when empty.
+	Counters []string
+	// BatchSize is the number of rows to check in each batch.
+	BatchSize int
+	// Throttle is the time to pause between batches to limit the load on the
+	// database and the disk.
+	Throttle time.Duration
+	// DryRun only reports drifted counters without fixing them.
+	DryRun bool
+	// Progress is called after each batch.
+	Progress func(RecountProgress)
+}
+
+// RecountCounters recomputes denormalized counters, e.g. numbers of stars of
+// repositories and members of organizations, in batches of rows. It fixes
+// counters that have drifted from actual values, e.g. after crashes or editing
+// the database by hand.
+func RecountCounters(ctx context.Context, db *gorm.DB, opts RecountOptions) error {
+	counters := recountCounters
+	if len(opts.Counters) > 0 {
+		counters = make([]*recountCounter, 0, len(opts.Counters))
+	loop:
+		for _, name := range opts.Counters {
+			for _, c := range recountCounters {
+				if c.Name == name {
+					counters = append(counters, c)
+					continue loop
+				}
+			}
+			return errors.Errorf("unknown counter %q", name)
+		}
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+
+	for _, c := range counters {
+		err := recount(ctx, db, c, opts)
+		if err != nil {
+			return errors.Wrapf(err, "recount %q", c.Name)
+		}
+	}
+	return nil
+}
+
+func recount(ctx context.Context, db *gorm.DB, c *recountCounter, opts RecountOptions) error {
+	scope := func() *gorm.DB {
+		q := db.WithContext(ctx).Table(c.Table)
+		if c.Where != "" {
+			q = q.Where(c.Where, c.Args...)
+		}
+		return q
+	}
+
+	progress := RecountProgress{Counter: c.Name}
+	err := scope().Count(&progress.Total).Error
+	if err != nil {
+		return errors.Wrap(err, "count rows")
+	}
+
+	var lastID int64
+	for {
+		var rows []struct {
+			ID    int64
+			Value int64
+		}
+		err = scope().
+			Select("id, "+c.Column+" AS value").
+			Where("id > ?", lastID).
+			Order("id ASC").
+			Limit(opts.BatchSize).
+			Scan(&rows).
+			Error
+		if err != nil {
+			return errors.Wrap(err, "list rows")
+		} else if len(rows) == 0 {
+			return nil
+		}
+
+		ids := make([]int64, 0, len(rows))
+		for _, row := range rows {
+			ids = append(ids, row.ID)
+		}
+		counts, err := c.Count(ctx, db, ids)
+		if err != nil {
+			return errors.Wrap(err, "count")
+		}
+
+		for _, row := range rows {
+			count, ok := counts[row.ID]
+			if !ok || count == row.Value {
+				continue
+			}
+
+			progress.Fixed++
+			if opts.DryRun {
+				continue
+			}
+			err = db.WithContext(ctx).Table(c.Table).Where("id = ?", row.ID).Update(c.Column, count).Error
+			if err != nil {
+				return errors.Wrapf(err, "update row [id: %d]", row.ID)
+			}
+		}
+
+		progress.Checked += int64(len(rows))
+		if opts.Progress != nil {
+			opts.Progress(progress)
+		}
+
+		lastID = ids[len(ids)-1]
+		if len(rows) < opts.BatchSize {
+			return nil
+		}
+		if opts.Throttle > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(opts.Throttle):
+			}
+		}
+	}
+}
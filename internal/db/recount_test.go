@@ -0,0 +1,104 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+func TestRecountCounters(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	ctx := context.Background()
+	tables := []interface{}{new(User), new(EmailAddress), new(Repository), new(Star), new(Follow)}
+	db := dbtest.NewDB(t, "recountCounters", tables...)
+
+	alice, err := NewUsersStore(db).Create(ctx, "alice", "alice@example.com", CreateUserOptions{})
+	require.NoError(t, err)
+	bob, err := NewUsersStore(db).Create(ctx, "bob", "bob@example.com", CreateUserOptions{})
+	require.NoError(t, err)
+	repo1, err := NewReposStore(db).Create(ctx, alice.ID, CreateRepoOptions{Name: "repo1"})
+	require.NoError(t, err)
+	repo2, err := NewReposStore(db).Create(ctx, alice.ID, CreateRepoOptions{Name: "repo2"})
+	require.NoError(t, err)
+
+	err = db.Create([]*Star{
+		{UID: alice.ID, RepoID: repo1.ID},
+		{UID: bob.ID, RepoID: repo1.ID},
+		{UID: bob.ID, RepoID: repo2.ID},
+	}).Error
+	require.NoError(t, err)
+	err = db.Create(&Follow{UserID: bob.ID, FollowID: alice.ID}).Error
+	require.NoError(t, err)
+
+	// Make counters drift
+	err = db.Model(new(Repository)).Where("id = ?", repo1.ID).Update("num_stars", 5).Error
+	require.NoError(t, err)
+	err = db.Model(new(User)).Where("id = ?", bob.ID).Update("num_stars", 1).Error
+	require.NoError(t, err)
+
+	getCounters := func(t *testing.T) (repo1Stars, repo2Stars, aliceStars, bobStars, aliceFollowers int) {
+		r1, err := NewReposStore(db).GetByName(ctx, alice.ID, repo1.Name)
+		require.NoError(t, err)
+		r2, err := NewReposStore(db).GetByName(ctx, alice.ID, repo2.Name)
+		require.NoError(t, err)
+		a, err := NewUsersStore(db).GetByID(ctx, alice.ID)
+		require.NoError(t, err)
+		b, err := NewUsersStore(db).GetByID(ctx, bob.ID)
+		require.NoError(t, err)
+		return r1.NumStars, r2.NumStars, a.NumStars, b.NumStars, a.NumFollowers
+	}
+	counters := []string{"repository.num_stars", "user.num_stars", "user.num_followers"}
+
+	t.Run("unknown counter", func(t *testing.T) {
+		err := RecountCounters(ctx, db, RecountOptions{Counters: []string{"repository.num_bananas"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		var progress []RecountProgress
+		err := RecountCounters(ctx, db,
+			RecountOptions{
+				Counters:  counters,
+				BatchSize: 1,
+				DryRun:    true,
+				Progress: func(p RecountProgress) {
+					progress = append(progress, p)
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		want := []RecountProgress{
+			{Counter: "repository.num_stars", Checked: 1, Total: 2, Fixed: 1},
+			{Counter: "repository.num_stars", Checked: 2, Total: 2, Fixed: 2},
+			{Counter: "user.num_stars", Checked: 1, Total: 2, Fixed: 1},
+			{Counter: "user.num_stars", Checked: 2, Total: 2, Fixed: 2},
+			{Counter: "user.num_followers", Checked: 1, Total: 2, Fixed: 1},
+			{Counter: "user.num_followers", Checked: 2, Total: 2, Fixed: 1},
+		}
+		assert.Equal(t, want, progress)
+
+		repo1Stars, repo2Stars, aliceStars, bobStars, aliceFollowers := getCounters(t)
+		assert.Equal(t, []int{5, 0, 0, 1, 0}, []int{repo1Stars, repo2Stars, aliceStars, bobStars, aliceFollowers})
+	})
+
+	t.Run("fix", func(t *testing.T) {
+		err := RecountCounters(ctx, db, RecountOptions{Counters: counters})
+		require.NoError(t, err)
+
+		repo1Stars, repo2Stars, aliceStars, bobStars, aliceFollowers := getCounters(t)
+		assert.Equal(t, []int{2, 1, 1, 2, 1}, []int{repo1Stars, repo2Stars, aliceStars, bobStars, aliceFollowers})
+	})
+}
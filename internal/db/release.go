@@ -17,6 +17,7 @@ import (
 	api "github.com/gogs/go-gogs-client"
 
 	"gogs.io/gogs/internal/errutil"
+	"gogs.io/gogs/internal/gitutil"
 	"gogs.io/gogs/internal/process"
 )
 
@@ -34,6 +35,7 @@ type Release struct {
 	Sha1             string `xorm:"VARCHAR(40)"`
 	NumCommits       int64
 	NumCommitsBehind int64  `xorm:"-" json:"-"`
+	TagMessage       string `xorm:"-" json:"-"` // Message of the annotated tag for tags without releases
 	Note             string `xorm:"TEXT"`
 	IsDraft          bool   `xorm:"NOT NULL DEFAULT false"`
 	IsPrerelease     bool
@@ -206,6 +208,38 @@ func NewRelease(gitRepo *git.Repository, r *Release, uuids []string) error {
 	return nil
 }
 
+// NewReleaseForTag creates a published release for the existing tag on behalf
+// of the publisher, which uses the message of the annotated tag as the note. It
+// does nothing if a release of the tag already exists.
+func NewReleaseForTag(gitRepo *git.Repository, repo *Repository, publisherID int64, tagName string) error {
+	isExist, err := IsReleaseExist(repo.ID, tagName)
+	if err != nil {
+		return err
+	} else if isExist {
+		return nil
+	}
+
+	tag, err := gitRepo.Tag(tagName)
+	if err != nil {
+		return fmt.Errorf("get tag: %v", err)
+	}
+
+	var note string
+	if tag.Type() == git.ObjectTag {
+		note, _ = gitutil.SplitTagSignature(tag.Message())
+		note = strings.TrimSpace(note)
+	}
+
+	return NewRelease(gitRepo, &Release{
+		RepoID:      repo.ID,
+		PublisherID: publisherID,
+		TagName:     tagName,
+		Target:      repo.DefaultBranch,
+		Title:       tagName,
+		Note:        note,
+	}, nil)
+}
+
 var _ errutil.NotFound = (*ErrReleaseNotExist)(nil)
 
 type ErrReleaseNotExist struct {
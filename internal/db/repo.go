@@ -11,6 +11,7 @@ import (
 	"image"
 	_ "image/jpeg"
 	"image/png"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
@@ -18,6 +19,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/nfnt/resize"
@@ -50,27 +52,72 @@ const REPO_AVATAR_URL_PREFIX = "repo-avatars"
 var repoWorkingPool = sync.NewExclusivePool()
 
 var (
-	Gitignores, Licenses, Readmes, LabelTemplates []string
-
 	// Maximum items per page in forks, watchers and stars of a repo
 	ItemsPerPage = 40
 )
 
+// repoConfigFiles contains names of template files that can be used to
+// initialize repositories.
+type repoConfigFiles struct {
+	gitignores, licenses, readmes, labelTemplates []string
+}
+
+// repoConfig holds the loaded *repoConfigFiles, which is replaced as a whole
+// on reload and must not be modified once stored.
+var repoConfig atomic.Value
+
+func loadedRepoConfig() *repoConfigFiles {
+	files, _ := repoConfig.Load().(*repoConfigFiles)
+	if files == nil {
+		return &repoConfigFiles{}
+	}
+	return files
+}
+
+// Gitignores returns names of all available gitignore templates.
+func Gitignores() []string {
+	return loadedRepoConfig().gitignores
+}
+
+// Licenses returns names of all available license templates, with preferred
+// licenses first.
+func Licenses() []string {
+	return loadedRepoConfig().licenses
+}
+
+// Readmes returns names of all available README templates.
+func Readmes() []string {
+	return loadedRepoConfig().readmes
+}
+
+// LabelTemplates returns names of all available label templates.
+func LabelTemplates() []string {
+	return loadedRepoConfig().labelTemplates
+}
+
 func LoadRepoConfig() {
+	if err := loadRepoConfig(); err != nil {
+		log.Fatal("Failed to load repository config: %v", err)
+	}
+}
+
+// loadRepoConfig loads names of built-in and custom template files, and
+// replaces the loaded ones.
+func loadRepoConfig() error {
 	// Load .gitignore and license files and readme templates.
 	types := []string{"gitignore", "license", "readme", "label"}
 	typeFiles := make([][]string, 4)
 	for i, t := range types {
 		files, err := embedConf.FileNames(t)
 		if err != nil {
-			log.Fatal("Failed to get %q files: %v", t, err)
+			return errors.Wrapf(err, "get %q files", t)
 		}
 
 		customPath := filepath.Join(conf.CustomDir(), "conf", t)
 		if com.IsDir(customPath) {
 			customFiles, err := com.StatDir(customPath)
 			if err != nil {
-				log.Fatal("Failed to get custom %s files: %v", t, err)
+				return errors.Wrapf(err, "get custom %q files", t)
 			}
 
 			for _, f := range customFiles {
@@ -79,31 +126,31 @@ func LoadRepoConfig() {
 				}
 			}
 		}
+		sort.Strings(files)
 		typeFiles[i] = files
 	}
 
-	Gitignores = typeFiles[0]
-	Licenses = typeFiles[1]
-	Readmes = typeFiles[2]
-	LabelTemplates = typeFiles[3]
-	sort.Strings(Gitignores)
-	sort.Strings(Licenses)
-	sort.Strings(Readmes)
-	sort.Strings(LabelTemplates)
-
 	// Filter out invalid names and promote preferred licenses.
-	sortedLicenses := make([]string, 0, len(Licenses))
+	licenses := typeFiles[1]
+	sortedLicenses := make([]string, 0, len(licenses))
 	for _, name := range conf.Repository.PreferredLicenses {
-		if com.IsSliceContainsStr(Licenses, name) {
+		if com.IsSliceContainsStr(licenses, name) {
 			sortedLicenses = append(sortedLicenses, name)
 		}
 	}
-	for _, name := range Licenses {
+	for _, name := range licenses {
 		if !com.IsSliceContainsStr(conf.Repository.PreferredLicenses, name) {
 			sortedLicenses = append(sortedLicenses, name)
 		}
 	}
-	Licenses = sortedLicenses
+
+	repoConfig.Store(&repoConfigFiles{
+		gitignores:     typeFiles[0],
+		licenses:       sortedLicenses,
+		readmes:        typeFiles[2],
+		labelTemplates: typeFiles[3],
+	})
+	return nil
 }
 
 func NewRepoContext() {
@@ -162,6 +209,12 @@ type Repository struct {
 	DefaultBranch   string
 	Size            int64 `xorm:"NOT NULL DEFAULT 0" gorm:"not null;default:0"`
 	UseCustomAvatar bool
+	// LicenseSPDX is the SPDX identifier of the license detected from the file
+	// at LicensePath in the root directory of the default branch.
+	LicenseSPDX string
+	LicensePath string
+	// Topics is the comma-separated list of topics of the repository.
+	Topics string `xorm:"TEXT" gorm:"type:TEXT"`
 
 	// Counters
 	NumWatches          int
@@ -186,6 +239,9 @@ type Repository struct {
 
 	IsMirror bool
 	*Mirror  `xorm:"-" gorm:"-" json:"-"`
+	// IsTrackerOnly indicates the repository only has issues and wiki, for code
+	// that lives elsewhere. Git access and code pages are disabled.
+	IsTrackerOnly bool `xorm:"NOT NULL DEFAULT false" gorm:"not null;default:FALSE"`
 
 	// Advanced settings
 	EnableWiki            bool `xorm:"NOT NULL DEFAULT true" gorm:"not null;default:TRUE"`
@@ -202,6 +258,41 @@ type Repository struct {
 	EnablePulls           bool              `xorm:"NOT NULL DEFAULT true" gorm:"not null;default:TRUE"`
 	PullsIgnoreWhitespace bool              `xorm:"NOT NULL DEFAULT false" gorm:"not null;default:FALSE"`
 	PullsAllowRebase      bool              `xorm:"NOT NULL DEFAULT false" gorm:"not null;default:FALSE"`
+	PullsAllowSquash      bool              `xorm:"NOT NULL DEFAULT false" gorm:"not null;default:FALSE"`
+	// MergeMessageTemplate and SquashMessageTemplate are templates of commit
+	// messages to merge pull requests, see RenderMergeMessage. The defaults are
+	// used when empty.
+	MergeMessageTemplate  string `xorm:"TEXT" gorm:"type:TEXT"`
+	SquashMessageTemplate string `xorm:"TEXT" gorm:"type:TEXT"`
+	// DefaultAssignees and DefaultReviewers are lists of users and teams (in the
+	// form of "<org>/<team>") separated by commas, see ParseParticipants.
+	DefaultAssignees string `xorm:"TEXT" gorm:"type:TEXT"`
+	DefaultReviewers string `xorm:"TEXT" gorm:"type:TEXT"`
+	EnableMergeQueue bool   `xorm:"NOT NULL DEFAULT false" gorm:"not null;default:FALSE"`
+	// MergeQueueRequiredChecks is the list of contexts of commit statuses
+	// separated by commas that must succeed before merging from the merge queue.
+	MergeQueueRequiredChecks string `xorm:"TEXT" gorm:"type:TEXT"`
+	// MergeChecklist is the checklist in Markdown that must be checked off on
+	// pull requests before merging, see ParseMergeChecklist.
+	MergeChecklist string `xorm:"TEXT" gorm:"type:TEXT"`
+	EnableReleases bool   `xorm:"NOT NULL DEFAULT true" gorm:"not null;default:TRUE"`
+	// Whether users listed in the MAINTAINERS file on the default branch can
+	// triage issues and pull requests, see ListedMaintainers.
+	EnableMaintainersFile bool `xorm:"NOT NULL DEFAULT false" gorm:"not null;default:FALSE"`
+	// MaxPushFileSize and MaxPushSize are limits in MB on the size of each file
+	// and the total size of files introduced by a push, 0 means using limits of
+	// the instance.
+	MaxPushFileSize int64 `xorm:"NOT NULL DEFAULT 0" gorm:"not null;default:0"`
+	MaxPushSize     int64 `xorm:"NOT NULL DEFAULT 0" gorm:"not null;default:0"`
+	// StorageShard is the repository shard that stores the repository, see
+	// repoutil.ShardRoot.
+	StorageShard int `xorm:"NOT NULL DEFAULT 0" gorm:"not null;default:0"`
+	// TrashedUnix is the time when the repository was moved to the trash, 0 if
+	// it is not in the trash. See TrashRepository for details.
+	TrashedUnix int64     `xorm:"NOT NULL DEFAULT 0" gorm:"not null;default:0"`
+	TrashedByID int64     `xorm:"NOT NULL DEFAULT 0" gorm:"not null;default:0"`
+	Trashed     time.Time `xorm:"-" gorm:"-" json:"-"`
+	TrashedBy   *User     `xorm:"-" gorm:"-" json:"-"`
 
 	IsFork   bool `xorm:"NOT NULL DEFAULT false" gorm:"not null;default:FALSE"`
 	ForkID   int64
@@ -239,6 +330,8 @@ func (repo *Repository) AfterSet(colName string, _ xorm.Cell) {
 		repo.Created = time.Unix(repo.CreatedUnix, 0).Local()
 	case "updated_unix":
 		repo.Updated = time.Unix(repo.UpdatedUnix, 0)
+	case "trashed_unix":
+		repo.Trashed = time.Unix(repo.TrashedUnix, 0).Local()
 	}
 }
 
@@ -539,7 +632,7 @@ func (repo *Repository) GetMirror() (err error) {
 }
 
 func (repo *Repository) repoPath(e Engine) string {
-	return RepoPath(repo.mustOwner(e).Name, repo.Name)
+	return repoutil.ShardRepositoryPath(repo.StorageShard, repo.mustOwner(e).Name, repo.Name)
 }
 
 // Deprecated: Use repoutil.RepositoryPath instead.
@@ -579,12 +672,12 @@ func (repo *Repository) IsOwnedBy(userID int64) bool {
 
 // CanBeForked returns true if repository meets the requirements of being forked.
 func (repo *Repository) CanBeForked() bool {
-	return !repo.IsBare
+	return !repo.IsBare && !repo.IsTrackerOnly
 }
 
 // CanEnablePulls returns true if repository meets the requirements of accepting pulls.
 func (repo *Repository) CanEnablePulls() bool {
-	return !repo.IsMirror && !repo.IsBare
+	return !repo.IsMirror && !repo.IsBare && !repo.IsTrackerOnly
 }
 
 // AllowPulls returns true if repository meets the requirements of accepting pulls and has them enabled.
@@ -592,8 +685,50 @@ func (repo *Repository) AllowsPulls() bool {
 	return repo.CanEnablePulls() && repo.EnablePulls
 }
 
+// IsBranchRequirePullRequest returns true if the branch requires pull request
+// by either branch protection of the repository or rulesets of the
+// organization.
 func (repo *Repository) IsBranchRequirePullRequest(name string) bool {
-	return IsBranchOfRepoRequirePullRequest(repo.ID, name)
+	if IsBranchOfRepoRequirePullRequest(repo.ID, name) {
+		return true
+	}
+
+	rulesets, err := OrgRulesets.Match(context.TODO(), repo.OwnerID, repo.Name, name)
+	if err != nil {
+		log.Error("Failed to match organization rulesets [repo_id: %d, branch: %s]: %v", repo.ID, name, err)
+		return false
+	}
+	for _, r := range rulesets {
+		if r.RequirePullRequest {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBranchDeletionBlocked returns true if the branch is protected from deletion
+// by either branch protection of the repository or rulesets of the
+// organization.
+func (repo *Repository) IsBranchDeletionBlocked(name string) (bool, error) {
+	protectBranch, err := GetProtectBranchOfRepoByName(repo.ID, name)
+	if err != nil {
+		if !IsErrBranchNotExist(err) {
+			return false, errors.Wrap(err, "get protect branch")
+		}
+	} else if protectBranch.Protected {
+		return true, nil
+	}
+
+	rulesets, err := OrgRulesets.Match(context.TODO(), repo.OwnerID, repo.Name, name)
+	if err != nil {
+		return false, errors.Wrap(err, "match organization rulesets")
+	}
+	for _, r := range rulesets {
+		if r.BlockDeletion {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 // CanEnableEditor returns true if repository meets the requirements of web editor.
@@ -733,6 +868,7 @@ type MigrateRepoOptions struct {
 	IsUnlisted  bool
 	IsMirror    bool
 	RemoteAddr  string
+	Topics      []string
 }
 
 /*
@@ -762,13 +898,14 @@ func MigrateRepository(doer, owner *User, opts MigrateRepoOptions) (*Repository,
 		IsPrivate:   opts.IsPrivate,
 		IsUnlisted:  opts.IsUnlisted,
 		IsMirror:    opts.IsMirror,
+		Topics:      opts.Topics,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	repoPath := RepoPath(owner.Name, opts.Name)
-	wikiPath := WikiPath(owner.Name, opts.Name)
+	repoPath := repo.RepoPath()
+	wikiPath := repo.WikiPath()
 
 	if owner.IsOrganization() {
 		t, err := owner.GetOwnerTeam()
@@ -824,11 +961,23 @@ func MigrateRepository(doer, owner *User, opts MigrateRepoOptions) (*Repository,
 		if err != nil {
 			return repo, fmt.Errorf("get HEAD branch: %v", err)
 		}
-		repo.DefaultBranch = git.RefShortName(refspec)
+		defaultBranch := git.RefShortName(refspec)
+		if !opts.IsMirror && defaultBranch != repo.DefaultBranch {
+			if err = moveDefaultBranchProtection(repo, defaultBranch); err != nil {
+				return repo, fmt.Errorf("move default branch protection: %v", err)
+			}
+		}
+		repo.DefaultBranch = defaultBranch
 
 		if err = repo.UpdateSize(); err != nil {
 			log.Error("UpdateSize [repo_id: %d]: %v", repo.ID, err)
 		}
+		if err = repo.loadLicense(); err != nil {
+			log.Error("Failed to detect license [repo_id: %d]: %v", repo.ID, err)
+		}
+		if err = repo.UpdateDependencies(context.TODO()); err != nil {
+			log.Error("Failed to update dependencies [repo_id: %d]: %v", repo.ID, err)
+		}
 	}
 
 	if opts.IsMirror {
@@ -938,6 +1087,17 @@ type CreateRepoOptionsLegacy struct {
 	IsUnlisted  bool
 	IsMirror    bool
 	AutoInit    bool
+	// IsTrackerOnly creates a repository without code, see
+	// Repository.IsTrackerOnly. Options to initialize files are ignored.
+	IsTrackerOnly bool `xorm:"-"`
+
+	// InitialContentPath is the path of a directory whose files become the
+	// initial commit instead of the auto-initialized files, e.g. an extracted
+	// archive or a directory on the server.
+	InitialContentPath string `xorm:"-"`
+	// Topics are the initial topics, which are checked against naming
+	// conventions along with the name.
+	Topics []string `xorm:"-"`
 }
 
 func getRepoInitFile(tp, name string) ([]byte, error) {
@@ -951,14 +1111,7 @@ func getRepoInitFile(tp, name string) ([]byte, error) {
 	return embedConf.Files.ReadFile(relPath)
 }
 
-func prepareRepoCommit(repo *Repository, tmpDir, repoPath string, opts CreateRepoOptionsLegacy) error {
-	// Clone to temporary path and do the init commit.
-	_, stderr, err := process.Exec(
-		fmt.Sprintf("initRepository(git clone): %s", repoPath), "git", "clone", repoPath, tmpDir)
-	if err != nil {
-		return fmt.Errorf("git clone: %v - %s", err, stderr)
-	}
-
+func prepareRepoCommit(repo *Repository, tmpDir string, opts CreateRepoOptionsLegacy) error {
 	// README
 	data, err := getRepoInitFile("readme", opts.Readme)
 	if err != nil {
@@ -979,20 +1132,15 @@ func prepareRepoCommit(repo *Repository, tmpDir, repoPath string, opts CreateRep
 
 	// .gitignore
 	if len(opts.Gitignores) > 0 {
-		var buf bytes.Buffer
-		names := strings.Split(opts.Gitignores, ",")
-		for _, name := range names {
-			data, err = getRepoInitFile("gitignore", name)
-			if err != nil {
-				return fmt.Errorf("getRepoInitFile[%s]: %v", name, err)
-			}
-			buf.WriteString("# ---> " + name + "\n")
-			buf.Write(data)
-			buf.WriteString("\n")
+		data, err = mergeGitignores(strings.Split(opts.Gitignores, ","), func(name string) ([]byte, error) {
+			return getRepoInitFile("gitignore", name)
+		})
+		if err != nil {
+			return fmt.Errorf("merge gitignores: %v", err)
 		}
 
-		if buf.Len() > 0 {
-			if err = ioutil.WriteFile(filepath.Join(tmpDir, ".gitignore"), buf.Bytes(), 0644); err != nil {
+		if len(data) > 0 {
+			if err = ioutil.WriteFile(filepath.Join(tmpDir, ".gitignore"), data, 0644); err != nil {
 				return fmt.Errorf("write .gitignore: %v", err)
 			}
 		}
@@ -1013,6 +1161,79 @@ func prepareRepoCommit(repo *Repository, tmpDir, repoPath string, opts CreateRep
 	return nil
 }
 
+// copyInitialContent copies regular files and directories in srcDir to dstDir,
+// skipping any ".git" directory and symbolic links.
+func copyInitialContent(srcDir, dstDir string) error {
+	return filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		} else if relPath == "." {
+			return nil
+		}
+
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(filepath.Join(dstDir, relPath), os.ModePerm)
+		} else if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = src.Close() }()
+
+		dst, err := os.OpenFile(filepath.Join(dstDir, relPath), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+		defer func() { _ = dst.Close() }()
+
+		_, err = io.Copy(dst, src)
+		return err
+	})
+}
+
+// autoInitRepository pushes the initial commit with the initial content or
+// chosen setup files to the empty repository on behalf of the doer.
+func autoInitRepository(repoPath string, doer *User, repo *Repository, opts CreateRepoOptionsLegacy) (err error) {
+	tmpDir := filepath.Join(os.TempDir(), "gogs-"+repo.Name+"-"+com.ToStr(time.Now().Nanosecond()))
+	if err = os.MkdirAll(tmpDir, os.ModePerm); err != nil {
+		return err
+	}
+	defer RemoveAllWithNotice("Delete repository for auto-initialization", tmpDir)
+
+	// Clone to temporary path and do the init commit.
+	var stderr string
+	_, stderr, err = process.Exec(
+		fmt.Sprintf("initRepository(git clone): %s", repoPath), "git", "clone", repoPath, tmpDir)
+	if err != nil {
+		return fmt.Errorf("git clone: %v - %s", err, stderr)
+	}
+
+	if opts.InitialContentPath != "" {
+		if err = copyInitialContent(opts.InitialContentPath, tmpDir); err != nil {
+			return fmt.Errorf("copy initial content: %v", err)
+		}
+	} else if err = prepareRepoCommit(repo, tmpDir, opts); err != nil {
+		return fmt.Errorf("prepareRepoCommit: %v", err)
+	}
+
+	// Apply changes and commit.
+	if err = initRepoCommit(tmpDir, doer.NewGitSig()); err != nil {
+		return fmt.Errorf("initRepoCommit: %v", err)
+	}
+	return nil
+}
+
 // initRepository performs initial commit with chosen setup files on behave of doer.
 func initRepository(e Engine, repoPath string, doer *User, repo *Repository, opts CreateRepoOptionsLegacy) (err error) {
 	// Somehow the directory could exist.
@@ -1027,23 +1248,11 @@ func initRepository(e Engine, repoPath string, doer *User, repo *Repository, opt
 		return fmt.Errorf("createDelegateHooks: %v", err)
 	}
 
-	tmpDir := filepath.Join(os.TempDir(), "gogs-"+repo.Name+"-"+com.ToStr(time.Now().Nanosecond()))
-
 	// Initialize repository according to user's choice.
-	if opts.AutoInit {
-		if err = os.MkdirAll(tmpDir, os.ModePerm); err != nil {
+	if opts.AutoInit || opts.InitialContentPath != "" {
+		if err = autoInitRepository(repoPath, doer, repo, opts); err != nil {
 			return err
 		}
-		defer RemoveAllWithNotice("Delete repository for auto-initialization", tmpDir)
-
-		if err = prepareRepoCommit(repo, tmpDir, repoPath, opts); err != nil {
-			return fmt.Errorf("prepareRepoCommit: %v", err)
-		}
-
-		// Apply changes and commit.
-		if err = initRepoCommit(tmpDir, doer.NewGitSig()); err != nil {
-			return fmt.Errorf("initRepoCommit: %v", err)
-		}
 	}
 
 	// Re-fetch the repository from database before updating it (else it would
@@ -1052,11 +1261,16 @@ func initRepository(e Engine, repoPath string, doer *User, repo *Repository, opt
 		return fmt.Errorf("getRepositoryByID: %v", err)
 	}
 
-	if !opts.AutoInit {
+	if !opts.AutoInit && opts.InitialContentPath == "" {
 		repo.IsBare = true
 	}
 
 	repo.DefaultBranch = "master"
+	if !repo.IsBare {
+		if err = repo.loadLicense(); err != nil {
+			log.Error("Failed to detect license [repo_id: %d]: %v", repo.ID, err)
+		}
+	}
 	if err = updateRepository(e, repo, false); err != nil {
 		return fmt.Errorf("updateRepository: %v", err)
 	}
@@ -1064,6 +1278,8 @@ func initRepository(e Engine, repoPath string, doer *User, repo *Repository, opt
 	return nil
 }
 
+// Built-in reserved names and patterns of repositories, which can be changed by
+// configuration and admins except protected ones, see reservedNamesOf.
 var (
 	reservedRepoNames    = []string{".", ".."}
 	reservedRepoPatterns = []string{"*.git", "*.wiki"}
@@ -1071,7 +1287,8 @@ var (
 
 // isRepoNameAllowed return an error if given name is a reserved name or pattern for repositories.
 func isRepoNameAllowed(name string) error {
-	return isNameAllowed(reservedRepoNames, reservedRepoPatterns, name)
+	names, patterns := reservedNamesOf(ReservedNameKindRepo)
+	return isNameAllowed(names, patterns, name)
 }
 
 func createRepository(e *xorm.Session, doer, owner *User, repo *Repository) (err error) {
@@ -1086,6 +1303,7 @@ func createRepository(e *xorm.Session, doer, owner *User, repo *Repository) (err
 		return ErrRepoAlreadyExist{args: errutil.Args{"ownerID": owner.ID, "name": repo.Name}}
 	}
 
+	repo.StorageShard = repoutil.AssignShard(owner.Name)
 	if _, err = e.Insert(repo); err != nil {
 		return err
 	}
@@ -1162,17 +1380,33 @@ func CreateRepository(doer, owner *User, opts CreateRepoOptionsLegacy) (_ *Repos
 		return nil, ErrReachLimitOfRepo{Limit: owner.RepoCreationNum()}
 	}
 
+	topics, err := NormalizeRepoTopics(opts.Topics)
+	if err != nil {
+		return nil, err
+	} else if err = checkRepoNamingConventions(RepoNamingConventions(owner), opts.Name, topics); err != nil {
+		return nil, err
+	}
+
 	repo := &Repository{
-		OwnerID:      owner.ID,
-		Owner:        owner,
-		Name:         opts.Name,
-		LowerName:    strings.ToLower(opts.Name),
-		Description:  opts.Description,
-		IsPrivate:    opts.IsPrivate,
-		IsUnlisted:   opts.IsUnlisted,
-		EnableWiki:   true,
-		EnableIssues: true,
-		EnablePulls:  true,
+		OwnerID:        owner.ID,
+		Owner:          owner,
+		Name:           opts.Name,
+		LowerName:      strings.ToLower(opts.Name),
+		Description:    opts.Description,
+		IsPrivate:      opts.IsPrivate,
+		IsUnlisted:     opts.IsUnlisted,
+		EnableWiki:     true,
+		EnableIssues:   true,
+		EnablePulls:    true,
+		EnableReleases: true,
+		Topics:         strings.Join(topics, ","),
+	}
+	if opts.IsTrackerOnly {
+		repo.IsTrackerOnly = true
+		repo.EnablePulls = false
+		repo.EnableReleases = false
+		opts.AutoInit = false
+		opts.InitialContentPath = ""
 	}
 
 	sess := x.NewSession()
@@ -1187,10 +1421,12 @@ func CreateRepository(doer, owner *User, opts CreateRepoOptionsLegacy) (_ *Repos
 
 	// No need for init mirror.
 	if !opts.IsMirror {
-		repoPath := RepoPath(owner.Name, repo.Name)
+		repoPath := repo.repoPath(sess)
 		if err = initRepository(sess, repoPath, doer, repo, opts); err != nil {
 			RemoveAllWithNotice("Delete repository for initialization failure", repoPath)
 			return nil, fmt.Errorf("initRepository: %v", err)
+		} else if err = protectDefaultBranch(sess, repo, owner); err != nil {
+			return nil, fmt.Errorf("protect default branch: %v", err)
 		}
 
 		_, stderr, err := process.ExecDir(-1,
@@ -1205,7 +1441,7 @@ func CreateRepository(doer, owner *User, opts CreateRepoOptionsLegacy) (_ *Repos
 }
 
 func countRepositories(userID int64, private bool) int64 {
-	sess := x.Where("id > 0")
+	sess := x.Where("id > 0").And("trashed_unix = 0")
 
 	if userID > 0 {
 		sess.And("owner_id = ?", userID)
@@ -1237,7 +1473,7 @@ func CountUserRepositories(userID int64, private bool) int64 {
 
 func Repositories(page, pageSize int) (_ []*Repository, err error) {
 	repos := make([]*Repository, 0, pageSize)
-	return repos, x.Limit(pageSize, (page-1)*pageSize).Asc("id").Find(&repos)
+	return repos, x.Where("trashed_unix = 0").Limit(pageSize, (page-1)*pageSize).Asc("id").Find(&repos)
 }
 
 // RepositoriesWithUsers returns number of repos in given page.
@@ -1284,11 +1520,13 @@ func FilterRepositoryWithIssues(repoIDs []int64) ([]int64, error) {
 	return repoIDs, nil
 }
 
-// RepoPath returns repository path by given user and repository name.
+// RepoPath returns repository path by given user and repository name. The
+// repository shard is looked up from the database when multiple shards are
+// configured.
 //
-// Deprecated: Use repoutil.RepositoryPath instead.
+// Deprecated: Use Repository.RepoPath instead.
 func RepoPath(userName, repoName string) string {
-	return filepath.Join(UserPath(userName), strings.ToLower(repoName)+".git")
+	return repoutil.ShardRepositoryPath(repoStorageShard(userName, repoName), userName, repoName)
 }
 
 // TransferOwnership transfers all corresponding setting from old user to new one.
@@ -1414,21 +1652,25 @@ func TransferOwnership(doer *User, newOwnerName string, repo *Repository) error
 		return fmt.Errorf("transferRepoAction: %v", err)
 	}
 
-	// Rename remote repository to new path and delete local copy.
-	if err = os.MkdirAll(UserPath(newOwner.Name), os.ModePerm); err != nil {
+	// Rename remote repository to new path and delete local copy. The repository
+	// stays in the same repository shard.
+	if err = os.MkdirAll(repoutil.ShardUserPath(repo.StorageShard, newOwner.Name), os.ModePerm); err != nil {
 		return err
 	}
-	if err = os.Rename(RepoPath(owner.Name, repo.Name), RepoPath(newOwner.Name, repo.Name)); err != nil {
+	if err = os.Rename(
+		repoutil.ShardRepositoryPath(repo.StorageShard, owner.Name, repo.Name),
+		repoutil.ShardRepositoryPath(repo.StorageShard, newOwner.Name, repo.Name),
+	); err != nil {
 		return fmt.Errorf("rename repository directory: %v", err)
 	}
 
 	deleteRepoLocalCopy(repo)
 
 	// Rename remote wiki repository to new path and delete local copy.
-	wikiPath := WikiPath(owner.Name, repo.Name)
+	wikiPath := shardWikiPath(repo.StorageShard, owner.Name, repo.Name)
 	if com.IsExist(wikiPath) {
 		RemoveAllWithNotice("Delete repository wiki local copy", repo.LocalWikiPath())
-		if err = os.Rename(wikiPath, WikiPath(newOwner.Name, repo.Name)); err != nil {
+		if err = os.Rename(wikiPath, shardWikiPath(repo.StorageShard, newOwner.Name, repo.Name)); err != nil {
 			return fmt.Errorf("rename repository wiki: %v", err)
 		}
 	}
@@ -1463,13 +1705,13 @@ func ChangeRepositoryName(u *User, oldRepoName, newRepoName string) (err error)
 	}
 
 	// Change repository directory name
-	if err = os.Rename(repo.RepoPath(), RepoPath(u.Name, newRepoName)); err != nil {
+	if err = os.Rename(repo.RepoPath(), repoutil.ShardRepositoryPath(repo.StorageShard, u.Name, newRepoName)); err != nil {
 		return fmt.Errorf("rename repository directory: %v", err)
 	}
 
 	wikiPath := repo.WikiPath()
 	if com.IsExist(wikiPath) {
-		if err = os.Rename(wikiPath, WikiPath(u.Name, newRepoName)); err != nil {
+		if err = os.Rename(wikiPath, shardWikiPath(repo.StorageShard, u.Name, newRepoName)); err != nil {
 			return fmt.Errorf("rename repository wiki: %v", err)
 		}
 		RemoveAllWithNotice("Delete repository wiki local copy", repo.LocalWikiPath())
@@ -1627,10 +1869,33 @@ func DeleteRepository(ownerID, repoID int64) error {
 		&Webhook{RepoID: repoID},
 		&HookTask{RepoID: repoID},
 		&LFSObject{RepoID: repoID},
+		&RepoDependency{RepoID: repoID},
+		&MirrorRefDrift{RepoID: repoID},
+		&SecurityAlert{RepoID: repoID},
+		&SecurityPolicyException{RepoID: repoID},
+		&GitAccessLog{RepoID: repoID},
+		&RepoTraffic{RepoID: repoID},
+		&RepoTrafficVisitor{RepoID: repoID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
 
+	if _, err = sess.Exec("DELETE FROM deployment_status WHERE deployment_id IN (SELECT id FROM deployment WHERE repo_id = ?)", repoID); err != nil {
+		return fmt.Errorf("delete deployment statuses: %v", err)
+	} else if _, err = sess.Exec("DELETE FROM deployment WHERE repo_id = ?", repoID); err != nil {
+		return fmt.Errorf("delete deployments: %v", err)
+	}
+	if _, err = sess.Exec("DELETE FROM pages_site WHERE repo_id = ?", repoID); err != nil {
+		return fmt.Errorf("delete pages site: %v", err)
+	}
+	if _, err = sess.Exec("DELETE FROM commit_message WHERE repo_id = ?", repoID); err != nil {
+		return fmt.Errorf("delete commit messages: %v", err)
+	}
+
+	if _, err = sess.Exec("DELETE FROM reaction WHERE issue_id IN (SELECT id FROM issue WHERE repo_id = ?)", repoID); err != nil {
+		return fmt.Errorf("delete reactions: %v", err)
+	}
+
 	// Delete comments and attachments.
 	issues := make([]*Issue, 0, 25)
 	attachmentPaths := make([]string, 0, len(issues))
@@ -1659,14 +1924,17 @@ func DeleteRepository(ownerID, repoID int64) error {
 		return err
 	}
 
-	if repo.IsFork {
-		if _, err = sess.Exec("UPDATE `repository` SET num_forks=num_forks-1 WHERE id=?", repo.ForkID); err != nil {
-			return fmt.Errorf("decrease fork count: %v", err)
+	// Counts have been decreased when the repository was moved to the trash.
+	if !repo.IsTrashed() {
+		if repo.IsFork {
+			if _, err = sess.Exec("UPDATE `repository` SET num_forks=num_forks-1 WHERE id=?", repo.ForkID); err != nil {
+				return fmt.Errorf("decrease fork count: %v", err)
+			}
 		}
-	}
 
-	if _, err = sess.Exec("UPDATE `user` SET num_repos=num_repos-1 WHERE id=?", ownerID); err != nil {
-		return err
+		if _, err = sess.Exec("UPDATE `user` SET num_repos=num_repos-1 WHERE id=?", ownerID); err != nil {
+			return err
+		}
 	}
 
 	if err = sess.Commit(); err != nil {
@@ -1674,17 +1942,26 @@ func DeleteRepository(ownerID, repoID int64) error {
 	}
 
 	// Remove repository files.
-	repoPath := repo.RepoPath()
-	RemoveAllWithNotice("Delete repository files", repoPath)
-
-	repo.DeleteWiki()
+	if repo.IsTrashed() {
+		RemoveAllWithNotice("Delete repository files", repo.TrashPath())
+		RemoveAllWithNotice("Delete repository wiki", repo.TrashWikiPath())
+		RemoveAllWithNotice("Delete repository wiki", repo.LocalWikiPath())
+	} else {
+		RemoveAllWithNotice("Delete repository files", repo.RepoPath())
+		repo.DeleteWiki()
+	}
 
 	// Remove attachment files.
 	for i := range attachmentPaths {
 		RemoveAllWithNotice("Delete attachment", attachmentPaths[i])
 	}
 
-	if repo.NumForks > 0 {
+	if osutil.IsFile(PagesArtifactPath(repoID)) {
+		RemoveAllWithNotice("Delete pages artifact", PagesArtifactPath(repoID))
+	}
+
+	// Forks in the trash are not counted but need to be reset as well.
+	if repo.NumForks > 0 || conf.Repository.EnableTrash {
 		if _, err = x.Exec("UPDATE `repository` SET fork_id=0,is_fork=? WHERE fork_id=?", false, repo.ID); err != nil {
 			log.Error("reset 'fork_id' and 'is_fork': %v", err)
 		}
@@ -1751,7 +2028,7 @@ type UserRepoOptions struct {
 
 // GetUserRepositories returns a list of repositories of given user.
 func GetUserRepositories(opts *UserRepoOptions) ([]*Repository, error) {
-	sess := x.Where("owner_id=?", opts.UserID).Desc("updated_unix")
+	sess := x.Where("owner_id=?", opts.UserID).And("trashed_unix = 0").Desc("updated_unix")
 	if !opts.Private {
 		sess.And("is_private=?", false)
 		sess.And("is_unlisted=?", false)
@@ -1769,13 +2046,13 @@ func GetUserRepositories(opts *UserRepoOptions) ([]*Repository, error) {
 // GetUserRepositories returns a list of mirror repositories of given user.
 func GetUserMirrorRepositories(userID int64) ([]*Repository, error) {
 	repos := make([]*Repository, 0, 10)
-	return repos, x.Where("owner_id = ?", userID).And("is_mirror = ?", true).Find(&repos)
+	return repos, x.Where("owner_id = ?", userID).And("is_mirror = ?", true).And("trashed_unix = 0").Find(&repos)
 }
 
 // GetRecentUpdatedRepositories returns the list of repositories that are recently updated.
 func GetRecentUpdatedRepositories(page, pageSize int) (repos []*Repository, err error) {
 	return repos, x.Limit(pageSize, (page-1)*pageSize).
-		Where("is_private=?", false).Limit(pageSize).Desc("updated_unix").Find(&repos)
+		Where("is_private=?", false).And("trashed_unix = 0").Limit(pageSize).Desc("updated_unix").Find(&repos)
 }
 
 // GetUserAndCollaborativeRepositories returns list of repositories the user owns and collaborates.
@@ -1784,12 +2061,13 @@ func GetUserAndCollaborativeRepositories(userID int64) ([]*Repository, error) {
 	if err := x.Alias("repo").
 		Join("INNER", "collaboration", "collaboration.repo_id = repo.id").
 		Where("collaboration.user_id = ?", userID).
+		And("repo.trashed_unix = 0").
 		Find(&repos); err != nil {
 		return nil, fmt.Errorf("select collaborative repositories: %v", err)
 	}
 
 	ownRepos := make([]*Repository, 0, 10)
-	if err := x.Where("owner_id = ?", userID).Find(&ownRepos); err != nil {
+	if err := x.Where("owner_id = ?", userID).And("trashed_unix = 0").Find(&ownRepos); err != nil {
 		return nil, fmt.Errorf("select own repositories: %v", err)
 	}
 
@@ -1797,7 +2075,7 @@ func GetUserAndCollaborativeRepositories(userID int64) ([]*Repository, error) {
 }
 
 func getRepositoryCount(_ Engine, u *User) (int64, error) {
-	return x.Count(&Repository{OwnerID: u.ID})
+	return x.Where("trashed_unix = 0").Count(&Repository{OwnerID: u.ID})
 }
 
 // GetRepositoryCount returns the total number of repositories of user.
@@ -1813,6 +2091,11 @@ type SearchRepoOptions struct {
 	Private  bool // Include private repositories in results
 	Page     int
 	PageSize int // Can be smaller than or equal to setting.ExplorePagingNum
+
+	// When set, public repositories are only included if their owners are in the
+	// Tenant. Repositories that opts.UserID has access to are not affected.
+	IsolateTenant bool
+	Tenant        string
 }
 
 // SearchRepositoryByName takes keyword and part of repository name to search,
@@ -1823,16 +2106,24 @@ func SearchRepositoryByName(opts *SearchRepoOptions) (repos []*Repository, count
 	}
 
 	repos = make([]*Repository, 0, opts.PageSize)
-	sess := x.Alias("repo")
+	sess := x.Alias("repo").Where("repo.trashed_unix = 0")
+
+	publicCond := "((repo.is_private = ? AND repo.is_unlisted = ?) OR (repo.is_private = ? AND (repo.allow_public_wiki = ? OR repo.allow_public_issues = ?)))"
+	publicArgs := []interface{}{false, false, true, true, true}
+	if opts.IsolateTenant {
+		publicCond = "(" + publicCond + " AND repo.owner_id IN (SELECT id FROM `user` WHERE tenant = ?))"
+		publicArgs = append(publicArgs, opts.Tenant)
+	}
+
 	// Attempt to find repositories that opts.UserID has access to,
 	// this does not include other people's private repositories even if opts.UserID is an admin.
 	if !opts.Private && opts.UserID > 0 {
 		sess.Join("LEFT", "access", "access.repo_id = repo.id").
-			Where("repo.owner_id = ? OR access.user_id = ? OR (repo.is_private = ? AND repo.is_unlisted = ?) OR (repo.is_private = ? AND (repo.allow_public_wiki = ? OR repo.allow_public_issues = ?))", opts.UserID, opts.UserID, false, false, true, true, true)
+			Where("repo.owner_id = ? OR access.user_id = ? OR "+publicCond, append([]interface{}{opts.UserID, opts.UserID}, publicArgs...)...)
 	} else {
 		// Only return public repositories if opts.Private is not set
 		if !opts.Private {
-			sess.And("(repo.is_private = ? AND repo.is_unlisted = ?) OR (repo.is_private = ? AND (repo.allow_public_wiki = ? OR repo.allow_public_issues = ?))", false, false, true, true, true)
+			sess.And(publicCond, publicArgs...)
 		}
 	}
 	if len(opts.Keyword) > 0 {
@@ -1865,7 +2156,7 @@ func DeleteOldRepositoryArchives() {
 
 	formats := []string{"zip", "targz"}
 	oldestTime := time.Now().Add(-conf.Cron.RepoArchiveCleanup.OlderThan)
-	if err := x.Where("id > 0").Iterate(new(Repository),
+	if err := x.Where("id > 0 AND trashed_unix = 0").Iterate(new(Repository),
 		func(idx int, bean interface{}) error {
 			repo := bean.(*Repository)
 			basePath := filepath.Join(repo.RepoPath(), "archives")
@@ -1897,8 +2188,8 @@ func DeleteOldRepositoryArchives() {
 					if err = os.Remove(archivePath); err != nil {
 						desc := fmt.Sprintf("Failed to health delete archive '%s': %v", archivePath, err)
 						log.Warn(desc)
-						if err = CreateRepositoryNotice(desc); err != nil {
-							log.Error("CreateRepositoryNotice: %v", err)
+						if err = CreateNotice(NOTICE_REPOSITORY, NoticeLevelWarn, desc); err != nil {
+							log.Error("CreateNotice: %v", err)
 						}
 					}
 				}
@@ -1918,7 +2209,7 @@ func DeleteRepositoryArchives() error {
 	taskStatusTable.Start(_CLEAN_OLD_ARCHIVES)
 	defer taskStatusTable.Stop(_CLEAN_OLD_ARCHIVES)
 
-	return x.Where("id > 0").Iterate(new(Repository),
+	return x.Where("id > 0 AND trashed_unix = 0").Iterate(new(Repository),
 		func(idx int, bean interface{}) error {
 			repo := bean.(*Repository)
 			return os.RemoveAll(filepath.Join(repo.RepoPath(), "archives"))
@@ -1927,7 +2218,7 @@ func DeleteRepositoryArchives() error {
 
 func gatherMissingRepoRecords() ([]*Repository, error) {
 	repos := make([]*Repository, 0, 10)
-	if err := x.Where("id > 0").Iterate(new(Repository),
+	if err := x.Where("id > 0 AND trashed_unix = 0").Iterate(new(Repository),
 		func(idx int, bean interface{}) error {
 			repo := bean.(*Repository)
 			if !com.IsDir(repo.RepoPath()) {
@@ -1989,7 +2280,7 @@ func ReinitMissingRepositories() error {
 // SyncRepositoryHooks rewrites all repositories' pre-receive, update and post-receive hooks
 // to make sure the binary and custom conf path are up-to-date.
 func SyncRepositoryHooks() error {
-	return x.Where("id > 0").Iterate(new(Repository),
+	return x.Where("id > 0 AND trashed_unix = 0").Iterate(new(Repository),
 		func(idx int, bean interface{}) error {
 			repo := bean.(*Repository)
 			if err := createDelegateHooks(repo.RepoPath()); err != nil {
@@ -2007,10 +2298,15 @@ func SyncRepositoryHooks() error {
 var taskStatusTable = sync.NewStatusTable()
 
 const (
-	_MIRROR_UPDATE      = "mirror_update"
-	_GIT_FSCK           = "git_fsck"
-	_CHECK_REPO_STATS   = "check_repos_stats"
-	_CLEAN_OLD_ARCHIVES = "clean_old_archives"
+	_MIRROR_UPDATE             = "mirror_update"
+	_GIT_FSCK                  = "git_fsck"
+	_CHECK_REPO_STATS          = "check_repos_stats"
+	_CLEAN_OLD_ARCHIVES        = "clean_old_archives"
+	_CHECK_VULNERABILITIES     = "check_vulnerabilities"
+	_CLEAN_OLD_GIT_ACCESS_LOGS = "clean_old_git_access_logs"
+	_SEND_USAGE_REPORT         = "send_usage_report"
+	_SEND_ACTIVITY_DIGESTS     = "send_activity_digests"
+	_AGGREGATE_CONTRIBUTIONS   = "aggregate_contributions"
 )
 
 // GitFsck calls 'git fsck' to check repository health.
@@ -2023,7 +2319,7 @@ func GitFsck() {
 
 	log.Trace("Doing: GitFsck")
 
-	if err := x.Where("id>0").Iterate(new(Repository),
+	if err := x.Where("id > 0 AND trashed_unix = 0").Iterate(new(Repository),
 		func(idx int, bean interface{}) error {
 			repo := bean.(*Repository)
 			repoPath := repo.RepoPath()
@@ -2048,7 +2344,7 @@ func GitFsck() {
 
 func GitGcRepos() error {
 	args := append([]string{"gc"}, conf.Git.GCArgs...)
-	return x.Where("id > 0").Iterate(new(Repository),
+	return x.Where("id > 0 AND trashed_unix = 0").Iterate(new(Repository),
 		func(idx int, bean interface{}) error {
 			repo := bean.(*Repository)
 			if err := repo.GetOwner(); err != nil {
@@ -2495,6 +2791,8 @@ func ForkRepository(doer, owner *User, baseRepo *Repository, name, desc string)
 		return nil, err
 	} else if _, err = sess.Exec("UPDATE `repository` SET num_forks=num_forks+1 WHERE id=?", baseRepo.ID); err != nil {
 		return nil, err
+	} else if err = protectDefaultBranch(sess, repo, owner); err != nil {
+		return nil, fmt.Errorf("protect default branch: %v", err)
 	}
 
 	repoPath := repo.repoPath(sess)
@@ -2525,6 +2823,9 @@ func ForkRepository(doer, owner *User, baseRepo *Repository, name, desc string)
 	if err = repo.UpdateSize(); err != nil {
 		log.Error("UpdateSize [repo_id: %d]: %v", repo.ID, err)
 	}
+	if err = repo.DetectLicense(); err != nil {
+		log.Error("Failed to detect license [repo_id: %d]: %v", repo.ID, err)
+	}
 	if err = PrepareWebhooks(baseRepo, HOOK_EVENT_FORK, &api.ForkPayload{
 		Forkee: repo.APIFormatLegacy(nil),
 		Repo:   baseRepo.APIFormatLegacy(nil),
@@ -12,6 +12,7 @@ import (
 	"github.com/gogs/git-module"
 	"github.com/unknwon/com"
 
+	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/errutil"
 	"gogs.io/gogs/internal/tool"
 )
@@ -110,6 +111,23 @@ type ProtectBranch struct {
 	EnableWhitelist    bool
 	WhitelistUserIDs   string `xorm:"TEXT"`
 	WhitelistTeamIDs   string `xorm:"TEXT"`
+	RerequestReviews   bool   `xorm:"NOT NULL DEFAULT false"`
+	// RequiredDeployments is a comma-separated list of environments that the
+	// head commit of pull requests must be successfully deployed to before
+	// merging.
+	RequiredDeployments string `xorm:"TEXT"`
+}
+
+// RequiredDeploymentEnvironments returns the list of environments that the head
+// commit of pull requests must be successfully deployed to before merging.
+func (protectBranch *ProtectBranch) RequiredDeploymentEnvironments() []string {
+	var envs []string
+	for _, env := range strings.Split(protectBranch.RequiredDeployments, ",") {
+		if env = strings.TrimSpace(env); env != "" {
+			envs = append(envs, env)
+		}
+	}
+	return envs
 }
 
 // GetProtectBranchOfRepoByName returns *ProtectBranch by branch name in given repository.
@@ -158,6 +176,31 @@ func UpdateProtectBranch(protectBranch *ProtectBranch) (err error) {
 	return sess.Commit()
 }
 
+// protectDefaultBranch protects the default branch of the new repository from
+// force pushes and deletion when it is required for all new repositories or by
+// the owner organization.
+func protectDefaultBranch(e Engine, repo *Repository, owner *User) error {
+	if repo.IsTrackerOnly ||
+		(!conf.Repository.ProtectDefaultBranch && !owner.ProtectDefaultBranch) {
+		return nil
+	}
+
+	_, err := e.Insert(&ProtectBranch{
+		RepoID:    repo.ID,
+		Name:      repo.DefaultBranch,
+		Protected: true,
+	})
+	return err
+}
+
+// moveDefaultBranchProtection moves the protection of the default branch of the
+// repository to the new default branch, which is used when the default branch
+// is only known after the repository is created, e.g. migrations.
+func moveDefaultBranchProtection(repo *Repository, defaultBranch string) error {
+	_, err := x.Where("repo_id = ? AND name = ?", repo.ID, repo.DefaultBranch).Cols("name").Update(&ProtectBranch{Name: defaultBranch})
+	return err
+}
+
 // UpdateOrgProtectBranch saves branch protection options of organizational repository.
 // If ID is 0, it creates a new record. Otherwise, updates existing record.
 // This function also performs check if whitelist user and team's IDs have been changed
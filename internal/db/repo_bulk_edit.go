@@ -0,0 +1,199 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// BulkRepoFilter selects repositories to apply settings changes in bulk.
+type BulkRepoFilter struct {
+	// Owner is the name of the owner, repositories of all owners are selected
+	// when empty.
+	Owner string
+	// NamePattern is a case-insensitive glob pattern of repository names (see
+	// path.Match), all repositories are selected when empty.
+	NamePattern string
+}
+
+// Validate returns an error if the name pattern is malformed.
+func (f BulkRepoFilter) Validate() error {
+	if _, err := path.Match(strings.ToLower(f.NamePattern), ""); err != nil {
+		return fmt.Errorf("invalid name pattern %q: %v", f.NamePattern, err)
+	}
+	return nil
+}
+
+func (f BulkRepoFilter) match(repo *Repository) bool {
+	if f.NamePattern == "" {
+		return true
+	}
+	matched, _ := path.Match(strings.ToLower(f.NamePattern), repo.LowerName)
+	return matched
+}
+
+// Names of repository settings that can be changed in bulk.
+const (
+	BulkRepoSettingWiki    = "wiki"
+	BulkRepoSettingIssues  = "issues"
+	BulkRepoSettingPulls   = "pulls"
+	BulkRepoSettingPrivate = "private"
+	BulkRepoSettingWebhook = "webhook"
+)
+
+// BulkRepoChanges is a change of settings to apply to repositories in bulk.
+// Settings of nil fields are left unchanged.
+type BulkRepoChanges struct {
+	EnableWiki   *bool
+	EnableIssues *bool
+	EnablePulls  *bool
+	// Private is not applied to forks, whose visibility always follows their
+	// base repositories.
+	Private *bool
+	// Webhook is added to repositories that do not have a webhook with the same
+	// payload URL. Its repository ID is ignored.
+	Webhook *Webhook
+}
+
+// IsEmpty returns true if nothing is to be changed.
+func (changes BulkRepoChanges) IsEmpty() bool {
+	return changes.EnableWiki == nil &&
+		changes.EnableIssues == nil &&
+		changes.EnablePulls == nil &&
+		changes.Private == nil &&
+		changes.Webhook == nil
+}
+
+// settingsDiff returns names of settings stored in the repository that would
+// be changed.
+func (changes BulkRepoChanges) settingsDiff(repo *Repository) []string {
+	var diff []string
+	if changes.EnableWiki != nil && *changes.EnableWiki != repo.EnableWiki {
+		diff = append(diff, BulkRepoSettingWiki)
+	}
+	if changes.EnableIssues != nil && *changes.EnableIssues != repo.EnableIssues {
+		diff = append(diff, BulkRepoSettingIssues)
+	}
+	if changes.EnablePulls != nil && *changes.EnablePulls != repo.EnablePulls {
+		diff = append(diff, BulkRepoSettingPulls)
+	}
+	if changes.Private != nil && *changes.Private != repo.IsPrivate && !repo.IsFork {
+		diff = append(diff, BulkRepoSettingPrivate)
+	}
+	return diff
+}
+
+// BulkRepoEdit is the settings change of a repository selected by a bulk edit.
+type BulkRepoEdit struct {
+	Repo *Repository
+	// Settings contains names of settings that are changed, empty when the
+	// repository is already up-to-date.
+	Settings []string
+}
+
+func hasWebhookURL(repoID int64, url string) (bool, error) {
+	count, err := x.Where("repo_id = ? AND url = ?", repoID, url).Count(new(Webhook))
+	return count > 0, err
+}
+
+// PreviewBulkRepoChanges returns repositories selected by the filter along with
+// settings that would be changed, ordered by owner and name. Repositories in
+// the trash are never selected.
+func PreviewBulkRepoChanges(filter BulkRepoFilter, changes BulkRepoChanges) ([]*BulkRepoEdit, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	sess := x.Where("trashed_unix = 0")
+	if filter.Owner != "" {
+		owner, err := GetUserByName(filter.Owner)
+		if err != nil {
+			return nil, err
+		}
+		sess.And("owner_id = ?", owner.ID)
+	}
+
+	repos := make([]*Repository, 0, 10)
+	if err := sess.Asc("owner_id", "lower_name").Find(&repos); err != nil {
+		return nil, fmt.Errorf("find repositories: %v", err)
+	}
+
+	edits := make([]*BulkRepoEdit, 0, len(repos))
+	for _, repo := range repos {
+		if !filter.match(repo) {
+			continue
+		}
+		if err := repo.GetOwner(); err != nil {
+			return nil, fmt.Errorf("get owner of repository [%d]: %v", repo.ID, err)
+		}
+
+		edit := &BulkRepoEdit{
+			Repo:     repo,
+			Settings: changes.settingsDiff(repo),
+		}
+		if changes.Webhook != nil {
+			has, err := hasWebhookURL(repo.ID, changes.Webhook.URL)
+			if err != nil {
+				return nil, fmt.Errorf("check webhook of repository [%d]: %v", repo.ID, err)
+			} else if !has {
+				edit.Settings = append(edit.Settings, BulkRepoSettingWebhook)
+			}
+		}
+		edits = append(edits, edit)
+	}
+	return edits, nil
+}
+
+// ApplyBulkRepoChanges applies the change of settings to repositories selected
+// by the filter, and returns the edits as PreviewBulkRepoChanges does. It stops
+// at the first repository that fails to be changed.
+func ApplyBulkRepoChanges(doer *User, filter BulkRepoFilter, changes BulkRepoChanges) ([]*BulkRepoEdit, error) {
+	edits, err := PreviewBulkRepoChanges(filter, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, edit := range edits {
+		repo := edit.Repo
+		var updated, visibilityChanged bool
+		for _, setting := range edit.Settings {
+			switch setting {
+			case BulkRepoSettingWiki:
+				repo.EnableWiki = *changes.EnableWiki
+			case BulkRepoSettingIssues:
+				repo.EnableIssues = *changes.EnableIssues
+			case BulkRepoSettingPulls:
+				repo.EnablePulls = *changes.EnablePulls
+			case BulkRepoSettingPrivate:
+				repo.IsPrivate = *changes.Private
+				visibilityChanged = true
+			case BulkRepoSettingWebhook:
+				w := *changes.Webhook
+				w.ID = 0
+				w.RepoID = repo.ID
+				w.OrgID = 0
+				if err = w.UpdateEvent(); err != nil {
+					return nil, fmt.Errorf("update webhook event: %v", err)
+				} else if err = CreateWebhook(&w); err != nil {
+					return nil, fmt.Errorf("create webhook for repository %q: %v", repo.FullName(), err)
+				}
+				continue
+			}
+			updated = true
+		}
+
+		if updated {
+			if err = UpdateRepository(repo, visibilityChanged); err != nil {
+				return nil, fmt.Errorf("update repository %q: %v", repo.FullName(), err)
+			}
+			if visibilityChanged {
+				PrepareVisibilityWebhook(doer, repo)
+			}
+		}
+	}
+	return edits, nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkRepoFilter(t *testing.T) {
+	assert.NoError(t, BulkRepoFilter{}.Validate())
+	assert.NoError(t, BulkRepoFilter{NamePattern: "infra-*"}.Validate())
+	assert.Error(t, BulkRepoFilter{NamePattern: "infra-["}.Validate())
+
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "", name: "anything", want: true},
+		{pattern: "infra-*", name: "infra-dns", want: true},
+		{pattern: "Infra-*", name: "infra-dns", want: true},
+		{pattern: "infra-*", name: "website", want: false},
+		{pattern: "*-[ab]", name: "service-a", want: true},
+		{pattern: "*-[ab]", name: "service-c", want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.pattern+"/"+test.name, func(t *testing.T) {
+			f := BulkRepoFilter{NamePattern: test.pattern}
+			assert.Equal(t, test.want, f.match(&Repository{LowerName: test.name}))
+		})
+	}
+}
+
+func TestBulkRepoChanges_settingsDiff(t *testing.T) {
+	enable, disable := true, false
+
+	assert.True(t, BulkRepoChanges{}.IsEmpty())
+	assert.False(t, BulkRepoChanges{Webhook: &Webhook{}}.IsEmpty())
+
+	repo := &Repository{
+		EnableWiki:   true,
+		EnableIssues: true,
+		EnablePulls:  false,
+	}
+	assert.Empty(t, BulkRepoChanges{}.settingsDiff(repo))
+
+	changes := BulkRepoChanges{
+		EnableWiki:   &disable,
+		EnableIssues: &enable,
+		EnablePulls:  &enable,
+		Private:      &enable,
+	}
+	assert.Equal(t, []string{BulkRepoSettingWiki, BulkRepoSettingPulls, BulkRepoSettingPrivate}, changes.settingsDiff(repo))
+
+	// Visibility of forks follows their base repositories.
+	repo.IsFork = true
+	assert.Equal(t, []string{BulkRepoSettingWiki, BulkRepoSettingPulls}, changes.settingsDiff(repo))
+}
@@ -0,0 +1,42 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"strings"
+
+	"github.com/unknwon/com"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/tool"
+)
+
+func (repo *Repository) deleteConfirmationData(doer *User) string {
+	return "delete:" + com.ToStr(doer.ID) + ":" + com.ToStr(repo.ID) + ":" + repo.FullName() + ":" + doer.Passwd + doer.Rands
+}
+
+// GenerateDeleteConfirmationCode generates a time limit code for the doer to
+// confirm deleting the repository. The code is invalidated when the repository
+// is renamed or transferred, or the doer changes password.
+func (repo *Repository) GenerateDeleteConfirmationCode(doer *User) string {
+	return tool.CreateTimeLimitCode(repo.deleteConfirmationData(doer), conf.API.DeleteConfirmationLiveMinutes, nil)
+}
+
+// VerifyDeleteConfirmationCode returns true if the code is generated for the
+// doer to confirm deleting the repository and has not expired.
+func (repo *Repository) VerifyDeleteConfirmationCode(doer *User, code string) bool {
+	if len(code) != tool.TIME_LIMIT_CODE_LENGTH {
+		return false
+	}
+	return tool.VerifyTimeLimitCode(repo.deleteConfirmationData(doer), conf.API.DeleteConfirmationLiveMinutes, code)
+}
+
+// IsDeleteConfirmationName returns true if the name is the full name of the
+// repository, which confirms deleting the repository. Names are matched case
+// insensitively.
+func (repo *Repository) IsDeleteConfirmationName(name string) bool {
+	name = strings.TrimSpace(name)
+	return name != "" && strings.EqualFold(name, repo.FullName())
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gogs.io/gogs/internal/conf"
+)
+
+func TestRepository_DeleteConfirmation(t *testing.T) {
+	before := conf.API.DeleteConfirmationLiveMinutes
+	t.Cleanup(func() {
+		conf.API.DeleteConfirmationLiveMinutes = before
+	})
+	conf.API.DeleteConfirmationLiveMinutes = 5
+
+	owner := &User{ID: 1, Name: "alice", Passwd: "secret", Rands: "rands"}
+	repo := &Repository{ID: 1, Name: "example", Owner: owner}
+	doer := &User{ID: 2, Name: "bob", Passwd: "secret", Rands: "rands"}
+
+	code := repo.GenerateDeleteConfirmationCode(doer)
+	assert.True(t, repo.VerifyDeleteConfirmationCode(doer, code))
+	assert.False(t, repo.VerifyDeleteConfirmationCode(owner, code))
+	assert.False(t, repo.VerifyDeleteConfirmationCode(doer, code[:len(code)-1]))
+	assert.False(t, repo.VerifyDeleteConfirmationCode(doer, ""))
+
+	// Renaming the repository invalidates the code
+	renamed := &Repository{ID: 1, Name: "renamed", Owner: owner}
+	assert.False(t, renamed.VerifyDeleteConfirmationCode(doer, code))
+
+	assert.True(t, repo.IsDeleteConfirmationName("alice/example"))
+	assert.True(t, repo.IsDeleteConfirmationName(" Alice/Example "))
+	assert.False(t, repo.IsDeleteConfirmationName("example"))
+	assert.False(t, repo.IsDeleteConfirmationName(""))
+}
@@ -0,0 +1,246 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/gitutil"
+	"gogs.io/gogs/internal/manifest"
+)
+
+// RepoDependenciesStore is the persistent interface for dependencies of
+// repositories parsed from their manifests.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type RepoDependenciesStore interface {
+	// ListByRepo returns all dependencies of the repository, ordered by manifest
+	// path and name.
+	ListByRepo(ctx context.Context, repoID int64) ([]*RepoDependency, error)
+	// ListDependents returns dependencies on the package with given ecosystem
+	// and name from all repositories, ordered by repository ID and manifest path.
+	ListDependents(ctx context.Context, ecosystem manifest.Ecosystem, name string) ([]*RepoDependency, error)
+	// ListRepoIDs returns IDs of all repositories that have dependencies, in
+	// ascending order.
+	ListRepoIDs(ctx context.Context) ([]int64, error)
+	// Replace replaces all dependencies of the repository with given ones.
+	Replace(ctx context.Context, repoID int64, deps []*RepoDependency) error
+}
+
+var RepoDependencies RepoDependenciesStore
+
+// RepoDependency is a package that a manifest in the default branch of a
+// repository depends on.
+type RepoDependency struct {
+	ID           int64              `gorm:"primaryKey"`
+	RepoID       int64              `gorm:"index;not null"`
+	ManifestPath string             `gorm:"not null"`
+	Ecosystem    manifest.Ecosystem `gorm:"type:VARCHAR(16);index:repo_dependency_ecosystem_name;not null"`
+	// PackageName is the name of the package declared by the manifest, it may be
+	// empty.
+	PackageName   string
+	Name          string `gorm:"index:repo_dependency_ecosystem_name;not null"`
+	Requirement   string
+	IsDevelopment bool `gorm:"not null;default:FALSE"`
+}
+
+var _ RepoDependenciesStore = (*repoDependencies)(nil)
+
+type repoDependencies struct {
+	*gorm.DB
+}
+
+// NewRepoDependenciesStore returns a persistent interface for dependencies of
+// repositories with given database connection.
+func NewRepoDependenciesStore(db *gorm.DB) RepoDependenciesStore {
+	return &repoDependencies{DB: db}
+}
+
+func (db *repoDependencies) ListByRepo(ctx context.Context, repoID int64) ([]*RepoDependency, error) {
+	var deps []*RepoDependency
+	return deps, db.WithContext(ctx).
+		Where("repo_id = ?", repoID).
+		Order("manifest_path ASC").
+		Order("name ASC").
+		Find(&deps).
+		Error
+}
+
+func (db *repoDependencies) ListDependents(ctx context.Context, ecosystem manifest.Ecosystem, name string) ([]*RepoDependency, error) {
+	var deps []*RepoDependency
+	return deps, db.WithContext(ctx).
+		Where("ecosystem = ? AND name = ?", ecosystem, name).
+		Order("repo_id ASC").
+		Order("manifest_path ASC").
+		Find(&deps).
+		Error
+}
+
+func (db *repoDependencies) ListRepoIDs(ctx context.Context) ([]int64, error) {
+	var repoIDs []int64
+	return repoIDs, db.WithContext(ctx).
+		Model(new(RepoDependency)).
+		Distinct("repo_id").
+		Order("repo_id ASC").
+		Pluck("repo_id", &repoIDs).
+		Error
+}
+
+func (db *repoDependencies) Replace(ctx context.Context, repoID int64, deps []*RepoDependency) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("repo_id = ?", repoID).Delete(new(RepoDependency)).Error
+		if err != nil {
+			return err
+		}
+		if len(deps) == 0 {
+			return nil
+		}
+
+		for _, dep := range deps {
+			dep.ID = 0
+			dep.RepoID = repoID
+		}
+		return tx.CreateInBatches(deps, 100).Error
+	})
+}
+
+// RepoPackage is a package declared by a manifest of a repository.
+type RepoPackage struct {
+	Ecosystem manifest.Ecosystem
+	Name      string
+}
+
+// DeclaredPackages returns distinct packages declared by manifests of the given
+// dependencies, in the order of their first appearance.
+func DeclaredPackages(deps []*RepoDependency) []RepoPackage {
+	var pkgs []RepoPackage
+	seen := make(map[RepoPackage]bool)
+	for _, dep := range deps {
+		if dep.PackageName == "" {
+			continue
+		}
+		pkg := RepoPackage{Ecosystem: dep.Ecosystem, Name: dep.PackageName}
+		if seen[pkg] {
+			continue
+		}
+		seen[pkg] = true
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs
+}
+
+// maxManifestSize is the maximum size of a manifest to be parsed.
+const maxManifestSize = 1 << 20
+
+// UpdateDependencies parses all manifests in the default branch of the
+// repository and replaces its stored dependencies. Manifests that cannot be
+// parsed are skipped.
+func (repo *Repository) UpdateDependencies(ctx context.Context) error {
+	gitRepo, err := git.Open(repo.RepoPath())
+	if err != nil {
+		return errors.Wrap(err, "open repository")
+	}
+
+	var deps []*RepoDependency
+	if gitRepo.HasBranch(repo.DefaultBranch) {
+		commit, err := gitRepo.BranchCommit(repo.DefaultBranch)
+		if err != nil {
+			return errors.Wrap(err, "get commit of default branch")
+		}
+
+		files, err := gitutil.ListFiles(repo.RepoPath(), commit.ID.String())
+		if err != nil {
+			return errors.Wrap(err, "list files")
+		}
+		for _, file := range files {
+			if !manifest.IsManifest(file) {
+				continue
+			}
+
+			entry, err := commit.TreeEntry(file)
+			if err != nil {
+				return errors.Wrapf(err, "get tree entry %q", file)
+			} else if !entry.IsBlob() || entry.Size() > maxManifestSize {
+				continue
+			}
+			data, err := entry.Blob().Bytes()
+			if err != nil {
+				return errors.Wrapf(err, "read %q", file)
+			}
+
+			m, err := manifest.Parse(file, data)
+			if err != nil {
+				log.Trace("Failed to parse manifest %q [repo_id: %d]: %v", file, repo.ID, err)
+				continue
+			}
+			for _, dep := range m.Dependencies {
+				deps = append(deps, &RepoDependency{
+					ManifestPath:  file,
+					Ecosystem:     m.Ecosystem,
+					PackageName:   m.PackageName,
+					Name:          dep.Name,
+					Requirement:   dep.Requirement,
+					IsDevelopment: dep.IsDevelopment,
+				})
+			}
+		}
+	}
+
+	return RepoDependencies.Replace(ctx, repo.ID, deps)
+}
+
+// RepoDependent is a repository that depends on a package.
+type RepoDependent struct {
+	Repo       *Repository
+	Dependency *RepoDependency
+}
+
+// ListAccessibleDependents returns dependencies on the package with given
+// ecosystem and name from repositories that the given user has read access to.
+// Only public repositories are returned when the user is nil.
+func ListAccessibleDependents(ctx context.Context, doer *User, ecosystem manifest.Ecosystem, name string) ([]*RepoDependent, error) {
+	deps, err := RepoDependencies.ListDependents(ctx, ecosystem, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "list dependents")
+	}
+
+	var doerID int64
+	if doer != nil {
+		doerID = doer.ID
+	}
+
+	var (
+		dependents []*RepoDependent
+		repos      = make(map[int64]*Repository)
+	)
+	for _, dep := range deps {
+		repo, ok := repos[dep.RepoID]
+		if !ok {
+			repo, err = GetRepositoryByID(dep.RepoID)
+			if err != nil {
+				if !IsErrRepoNotExist(err) {
+					return nil, errors.Wrapf(err, "get repository by ID %d", dep.RepoID)
+				}
+				repo = nil
+			} else if (doer == nil || !doer.IsAdmin) && !repo.HasAccess(doerID) {
+				repo = nil
+			}
+			repos[dep.RepoID] = repo
+		}
+		if repo == nil {
+			continue
+		}
+		dependents = append(dependents, &RepoDependent{
+			Repo:       repo,
+			Dependency: dep,
+		})
+	}
+	return dependents, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/manifest"
+)
+
+func TestRepoDependencies(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(RepoDependency)}
+	db := &repoDependencies{
+		DB: dbtest.NewDB(t, "repoDependencies", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *repoDependencies)
+	}{
+		{"Replace", repoDependenciesReplace},
+		{"ListDependents", repoDependenciesListDependents},
+		{"ListRepoIDs", repoDependenciesListRepoIDs},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func repoDependenciesReplace(t *testing.T, db *repoDependencies) {
+	ctx := context.Background()
+
+	err := db.Replace(ctx, 1, []*RepoDependency{
+		{ManifestPath: "web/package.json", Ecosystem: manifest.EcosystemNPM, Name: "react", Requirement: "^18.2.0"},
+		{ManifestPath: "go.mod", Ecosystem: manifest.EcosystemGo, PackageName: "example.com/app", Name: "github.com/pkg/errors", Requirement: "v0.9.1"},
+	})
+	require.NoError(t, err)
+	err = db.Replace(ctx, 2, []*RepoDependency{
+		{ManifestPath: "go.mod", Ecosystem: manifest.EcosystemGo, Name: "github.com/pkg/errors", Requirement: "v0.8.0"},
+	})
+	require.NoError(t, err)
+
+	deps, err := db.ListByRepo(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, deps, 2)
+	assert.Equal(t, "go.mod", deps[0].ManifestPath)
+	assert.Equal(t, "web/package.json", deps[1].ManifestPath)
+	assert.Equal(t, []RepoPackage{{Ecosystem: manifest.EcosystemGo, Name: "example.com/app"}}, DeclaredPackages(deps))
+
+	// Replacing with nothing removes all dependencies of the repository only.
+	err = db.Replace(ctx, 1, nil)
+	require.NoError(t, err)
+	deps, err = db.ListByRepo(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, deps)
+
+	deps, err = db.ListByRepo(ctx, 2)
+	require.NoError(t, err)
+	assert.Len(t, deps, 1)
+}
+
+func repoDependenciesListDependents(t *testing.T, db *repoDependencies) {
+	ctx := context.Background()
+
+	for repoID, requirement := range map[int64]string{3: "v0.9.1", 1: "v0.8.0"} {
+		err := db.Replace(ctx, repoID, []*RepoDependency{
+			{ManifestPath: "go.mod", Ecosystem: manifest.EcosystemGo, Name: "github.com/pkg/errors", Requirement: requirement},
+			{ManifestPath: "go.mod", Ecosystem: manifest.EcosystemGo, Name: "github.com/gogs/git-module", Requirement: "v1.7.0"},
+		})
+		require.NoError(t, err)
+	}
+	err := db.Replace(ctx, 2, []*RepoDependency{
+		{ManifestPath: "requirements.txt", Ecosystem: manifest.EcosystemPip, Name: "github.com/pkg/errors"},
+	})
+	require.NoError(t, err)
+
+	deps, err := db.ListDependents(ctx, manifest.EcosystemGo, "github.com/pkg/errors")
+	require.NoError(t, err)
+	require.Len(t, deps, 2)
+	assert.Equal(t, int64(1), deps[0].RepoID)
+	assert.Equal(t, "v0.8.0", deps[0].Requirement)
+	assert.Equal(t, int64(3), deps[1].RepoID)
+	assert.Equal(t, "v0.9.1", deps[1].Requirement)
+
+	deps, err = db.ListDependents(ctx, manifest.EcosystemNPM, "github.com/pkg/errors")
+	require.NoError(t, err)
+	assert.Empty(t, deps)
+}
+
+func repoDependenciesListRepoIDs(t *testing.T, db *repoDependencies) {
+	ctx := context.Background()
+
+	for _, repoID := range []int64{3, 1} {
+		err := db.Replace(ctx, repoID, []*RepoDependency{
+			{ManifestPath: "go.mod", Ecosystem: manifest.EcosystemGo, Name: "github.com/pkg/errors", Requirement: "v0.9.1"},
+			{ManifestPath: "go.mod", Ecosystem: manifest.EcosystemGo, Name: "github.com/gogs/git-module", Requirement: "v1.7.0"},
+		})
+		require.NoError(t, err)
+	}
+
+	repoIDs, err := db.ListRepoIDs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 3}, repoIDs)
+}
@@ -0,0 +1,253 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/errutil"
+	"gogs.io/gogs/internal/osutil"
+)
+
+// The types of template files that can be used to initialize a repository.
+const (
+	RepoInitFileGitignore = "gitignore"
+	RepoInitFileLicense   = "license"
+	RepoInitFileReadme    = "readme"
+)
+
+// RepoInitFileTypes is the list of template file types that can be managed.
+var RepoInitFileTypes = []string{RepoInitFileGitignore, RepoInitFileLicense, RepoInitFileReadme}
+
+// RepoInitFileNames returns the names of all available template files of the
+// given type, or nil if the type is unknown.
+func RepoInitFileNames(tp string) []string {
+	switch tp {
+	case RepoInitFileGitignore:
+		return Gitignores()
+	case RepoInitFileLicense:
+		return Licenses()
+	case RepoInitFileReadme:
+		return Readmes()
+	}
+	return nil
+}
+
+var repoInitFileNamePattern = regexp.MustCompile(`^[\w.+-]+( [\w.+-]+)*$`)
+
+// IsValidRepoInitFileName returns true if the given name can be used as the
+// name of a custom template file.
+func IsValidRepoInitFileName(name string) bool {
+	return len(name) <= 100 &&
+		!strings.HasPrefix(name, ".") &&
+		repoInitFileNamePattern.MatchString(name)
+}
+
+func customRepoInitFilePath(tp, name string) string {
+	return filepath.Join(conf.CustomDir(), "conf", tp, name)
+}
+
+// IsCustomRepoInitFile returns true if the template file of the given type and
+// name is provided by the custom directory.
+func IsCustomRepoInitFile(tp, name string) bool {
+	return IsValidRepoInitFileName(name) && osutil.IsFile(customRepoInitFilePath(tp, name))
+}
+
+var _ errutil.NotFound = (*ErrRepoInitFileNotExist)(nil)
+
+type ErrRepoInitFileNotExist struct {
+	args errutil.Args
+}
+
+func IsErrRepoInitFileNotExist(err error) bool {
+	_, ok := err.(ErrRepoInitFileNotExist)
+	return ok
+}
+
+func (err ErrRepoInitFileNotExist) Error() string {
+	return fmt.Sprintf("repository template file does not exist: %v", err.args)
+}
+
+func (ErrRepoInitFileNotExist) NotFound() bool {
+	return true
+}
+
+// GetRepoInitFile returns the content of the template file of the given type
+// and name. It returns ErrRepoInitFileNotExist when not found.
+func GetRepoInitFile(tp, name string) ([]byte, error) {
+	found := false
+	for _, n := range RepoInitFileNames(tp) {
+		if n == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, ErrRepoInitFileNotExist{args: errutil.Args{"type": tp, "name": name}}
+	}
+	return getRepoInitFile(tp, name)
+}
+
+// SaveCustomRepoInitFile creates or overwrites the custom template file of the
+// given type and name, and reloads the catalog of template files.
+func SaveCustomRepoInitFile(tp, name string, data []byte) error {
+	if RepoInitFileNames(tp) == nil {
+		return errors.Errorf("unknown template type %q", tp)
+	} else if !IsValidRepoInitFileName(name) {
+		return ErrNameNotAllowed{args: errutil.Args{"name": name}}
+	}
+
+	fpath := customRepoInitFilePath(tp, name)
+	if err := os.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
+		return errors.Wrap(err, "create directory")
+	}
+	if err := os.WriteFile(fpath, data, 0o644); err != nil {
+		return errors.Wrap(err, "write file")
+	}
+	return errors.Wrap(loadRepoConfig(), "reload")
+}
+
+// DeleteCustomRepoInitFile deletes the custom template file of the given type
+// and name, and reloads the catalog of template files. A built-in template file
+// with the same name becomes available again after the deletion.
+func DeleteCustomRepoInitFile(tp, name string) error {
+	if !IsCustomRepoInitFile(tp, name) {
+		return ErrRepoInitFileNotExist{args: errutil.Args{"type": tp, "name": name}}
+	}
+
+	if err := os.Remove(customRepoInitFilePath(tp, name)); err != nil {
+		return errors.Wrap(err, "remove file")
+	}
+	return errors.Wrap(loadRepoConfig(), "reload")
+}
+
+// mergeGitignores merges the gitignore templates of given names into a single
+// file. Each template is preceded by a comment line with its name, duplicated
+// names are only included once and rules already included by a previous
+// template are omitted.
+func mergeGitignores(names []string, load func(name string) ([]byte, error)) ([]byte, error) {
+	var buf bytes.Buffer
+	seenNames := make(map[string]bool, len(names))
+	seenRules := make(map[string]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seenNames[name] {
+			continue
+		}
+		seenNames[name] = true
+
+		data, err := load(name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "load %q", name)
+		}
+
+		buf.WriteString("# ---> " + name + "\n")
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			line := scanner.Text()
+			rule := strings.TrimSpace(line)
+			if rule != "" && !strings.HasPrefix(rule, "#") {
+				if seenRules[rule] {
+					continue
+				}
+				seenRules[rule] = true
+			}
+			buf.WriteString(line + "\n")
+		}
+		if err = scanner.Err(); err != nil {
+			return nil, errors.Wrapf(err, "read %q", name)
+		}
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// validateRepoInitFiles returns ErrRepoInitFileNotExist if any of the README,
+// license and gitignore templates chosen by options is not one of the names of
+// its type.
+func validateRepoInitFiles(opts CreateRepoOptionsLegacy, names func(tp string) []string) error {
+	check := func(tp, name string) error {
+		for _, n := range names(tp) {
+			if n == name {
+				return nil
+			}
+		}
+		return ErrRepoInitFileNotExist{args: errutil.Args{"type": tp, "name": name}}
+	}
+
+	if err := check(RepoInitFileReadme, opts.Readme); err != nil {
+		return err
+	}
+	if opts.License != "" {
+		if err := check(RepoInitFileLicense, opts.License); err != nil {
+			return err
+		}
+	}
+	for _, name := range strings.Split(opts.Gitignores, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if err := check(RepoInitFileGitignore, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type ErrRepoNotEmpty struct {
+	args errutil.Args
+}
+
+func IsErrRepoNotEmpty(err error) bool {
+	_, ok := err.(ErrRepoNotEmpty)
+	return ok
+}
+
+func (err ErrRepoNotEmpty) Error() string {
+	return fmt.Sprintf("repository is not empty: %v", err.args)
+}
+
+// InitializeRepository creates the initial commit of the empty repository with
+// the README, license and gitignore templates chosen by options on behalf of
+// the doer, the same as auto-initialization when creating a repository. The
+// "Default" README is used when none is chosen. It returns ErrRepoNotEmpty when
+// the repository already has commits or does not host code.
+func InitializeRepository(doer *User, repo *Repository, opts CreateRepoOptionsLegacy) error {
+	if !repo.IsBare || repo.IsMirror || repo.IsTrackerOnly {
+		return ErrRepoNotEmpty{args: errutil.Args{"repoID": repo.ID}}
+	}
+
+	if opts.Readme == "" {
+		opts.Readme = "Default"
+	}
+	if err := validateRepoInitFiles(opts, RepoInitFileNames); err != nil {
+		return err
+	}
+
+	if err := autoInitRepository(repo.RepoPath(), doer, repo, opts); err != nil {
+		return errors.Wrap(err, "auto-initialize")
+	}
+
+	repo.IsBare = false
+	if repo.DefaultBranch == "" {
+		repo.DefaultBranch = "master"
+	}
+	if err := repo.loadLicense(); err != nil {
+		log.Error("Failed to detect license [repo_id: %d]: %v", repo.ID, err)
+	}
+	return UpdateRepository(repo, false)
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidRepoInitFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "Go", want: true},
+		{name: "Apache License 2.0", want: true},
+		{name: " Go ", want: false},
+		{name: "GPL-3.0+", want: true},
+		{name: "Visual_Studio.Code", want: true},
+		{name: "", want: false},
+		{name: ".hidden", want: false},
+		{name: "..", want: false},
+		{name: "../Go", want: false},
+		{name: "a/b", want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, IsValidRepoInitFileName(test.name))
+		})
+	}
+}
+
+func TestMergeGitignores(t *testing.T) {
+	files := map[string]string{
+		"Go":    "# Binaries\n*.exe\n*.test\n\nvendor/\n",
+		"Node":  "# Dependencies\nnode_modules/\n\n# Binaries\n*.exe\n",
+		"macOS": ".DS_Store",
+	}
+	load := func(name string) ([]byte, error) {
+		data, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("%q not found", name)
+		}
+		return []byte(data), nil
+	}
+
+	got, err := mergeGitignores([]string{"Go", "Node", " Go", "macOS", ""}, load)
+	require.NoError(t, err)
+
+	want := `# ---> Go
+# Binaries
+*.exe
+*.test
+
+vendor/
+
+# ---> Node
+# Dependencies
+node_modules/
+
+# Binaries
+
+# ---> macOS
+.DS_Store
+
+`
+	assert.Equal(t, want, string(got))
+
+	_, err = mergeGitignores([]string{"Rust"}, load)
+	assert.Error(t, err)
+}
+
+func TestValidateRepoInitFiles(t *testing.T) {
+	files := map[string][]string{
+		RepoInitFileGitignore: {"Go", "Node"},
+		RepoInitFileLicense:   {"MIT License"},
+		RepoInitFileReadme:    {"Default"},
+	}
+	names := func(tp string) []string { return files[tp] }
+
+	tests := []struct {
+		name    string
+		opts    CreateRepoOptionsLegacy
+		wantErr bool
+	}{
+		{name: "readme only", opts: CreateRepoOptionsLegacy{Readme: "Default"}},
+		{name: "all", opts: CreateRepoOptionsLegacy{Readme: "Default", License: "MIT License", Gitignores: "Go, Node,"}},
+		{name: "unknown readme", opts: CreateRepoOptionsLegacy{Readme: "Fancy"}, wantErr: true},
+		{name: "unknown license", opts: CreateRepoOptionsLegacy{Readme: "Default", License: "WTFPL"}, wantErr: true},
+		{name: "unknown gitignore", opts: CreateRepoOptionsLegacy{Readme: "Default", Gitignores: "Go,Rust"}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateRepoInitFiles(test.opts, names)
+			if test.wantErr {
+				assert.True(t, IsErrRepoInitFileNotExist(err), "got %v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
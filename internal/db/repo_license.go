@@ -0,0 +1,199 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/gogs/git-module"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/licenseutil"
+)
+
+// LicenseNoAssertion is the SPDX identifier used when a license file exists but
+// its license cannot be detected.
+const LicenseNoAssertion = "NOASSERTION"
+
+// maxLicenseFileSize is the maximum size of a license file to be read for
+// detection.
+const maxLicenseFileSize = 1 << 20
+
+// detectLicense returns the SPDX identifier and path of the license file in the
+// root directory of the given commit. It returns empty strings when there is no
+// license file.
+func detectLicense(commit *git.Commit) (spdx, path string, err error) {
+	entries, err := commit.Entries()
+	if err != nil {
+		return "", "", fmt.Errorf("list entries: %v", err)
+	}
+
+	var (
+		file     *git.TreeEntry
+		priority int
+	)
+	for _, e := range entries {
+		if !e.IsBlob() {
+			continue
+		}
+		p := licenseutil.CandidatePriority(e.Name())
+		if p < 0 || (file != nil && p >= priority) {
+			continue
+		}
+		file = e
+		priority = p
+	}
+	if file == nil {
+		return "", "", nil
+	} else if file.Size() > maxLicenseFileSize {
+		return LicenseNoAssertion, file.Name(), nil
+	}
+
+	data, err := file.Blob().Bytes()
+	if err != nil {
+		return "", "", fmt.Errorf("read %q: %v", file.Name(), err)
+	}
+
+	license := licenseutil.Detect(data)
+	if license == nil {
+		return LicenseNoAssertion, file.Name(), nil
+	}
+	return license.SPDX, file.Name(), nil
+}
+
+// loadLicense detects the license of the repository from the license file in
+// the root directory of the default branch, and sets LicenseSPDX and
+// LicensePath accordingly.
+func (repo *Repository) loadLicense() error {
+	gitRepo, err := git.Open(repo.RepoPath())
+	if err != nil {
+		return fmt.Errorf("open repository: %v", err)
+	}
+
+	repo.LicenseSPDX, repo.LicensePath = "", ""
+	if !gitRepo.HasBranch(repo.DefaultBranch) {
+		return nil
+	}
+
+	commit, err := gitRepo.BranchCommit(repo.DefaultBranch)
+	if err != nil {
+		return fmt.Errorf("get commit of default branch: %v", err)
+	}
+	repo.LicenseSPDX, repo.LicensePath, err = detectLicense(commit)
+	return err
+}
+
+// DetectLicense detects the license of the repository from the license file in
+// the root directory of the default branch, and saves the result when changed.
+func (repo *Repository) DetectLicense() error {
+	spdx, path := repo.LicenseSPDX, repo.LicensePath
+	if err := repo.loadLicense(); err != nil {
+		return err
+	} else if repo.LicenseSPDX == spdx && repo.LicensePath == path {
+		return nil
+	}
+
+	if _, err := x.Id(repo.ID).Cols("license_spdx", "license_path").Update(repo); err != nil {
+		return fmt.Errorf("update license: %v", err)
+	}
+	return nil
+}
+
+// DetectRepositoryLicenses detects and saves licenses of all repositories.
+// Failures of individual repositories are logged and do not stop detection of
+// other repositories.
+func DetectRepositoryLicenses() error {
+	repos := make([]*Repository, 0, 10)
+	if err := x.Where("is_bare = ?", false).Find(&repos); err != nil {
+		return fmt.Errorf("list repositories: %v", err)
+	}
+
+	for _, repo := range repos {
+		if err := repo.DetectLicense(); err != nil {
+			log.Error("Failed to detect license [repo_id: %d]: %v", repo.ID, err)
+		}
+	}
+	return nil
+}
+
+// LicenseName returns the human readable name of the detected license.
+func (repo *Repository) LicenseName() string {
+	return licenseutil.Name(repo.LicenseSPDX)
+}
+
+// OrgLicenseEntry is a license used by repositories of an organization.
+type OrgLicenseEntry struct {
+	// SPDX is the SPDX identifier of the license, empty for repositories without
+	// a license file, or LicenseNoAssertion for repositories with a license file
+	// that cannot be detected.
+	SPDX  string
+	Name  string
+	Repos []*Repository
+}
+
+// GetOrgLicenseReport returns the licenses used by repositories of the
+// organization, ordered by the number of repositories in descending order.
+func GetOrgLicenseReport(orgID int64) ([]*OrgLicenseEntry, error) {
+	repos := make([]*Repository, 0, 10)
+	if err := x.Where("owner_id = ?", orgID).Asc("lower_name").Find(&repos); err != nil {
+		return nil, fmt.Errorf("list repositories: %v", err)
+	}
+	return buildOrgLicenseReport(repos), nil
+}
+
+func buildOrgLicenseReport(repos []*Repository) []*OrgLicenseEntry {
+	entries := make(map[string]*OrgLicenseEntry)
+	for _, repo := range repos {
+		e, ok := entries[repo.LicenseSPDX]
+		if !ok {
+			e = &OrgLicenseEntry{
+				SPDX: repo.LicenseSPDX,
+				Name: licenseutil.Name(repo.LicenseSPDX),
+			}
+			entries[repo.LicenseSPDX] = e
+		}
+		e.Repos = append(e.Repos, repo)
+	}
+
+	report := make([]*OrgLicenseEntry, 0, len(entries))
+	for _, e := range entries {
+		report = append(report, e)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if len(report[i].Repos) != len(report[j].Repos) {
+			return len(report[i].Repos) > len(report[j].Repos)
+		}
+		return report[i].SPDX < report[j].SPDX
+	})
+	return report
+}
+
+// WriteOrgLicenseReportCSV writes the license report in CSV format with one
+// row per repository.
+func WriteOrgLicenseReportCSV(w io.Writer, report []*OrgLicenseEntry) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"repository", "private", "license", "path"})
+	if err != nil {
+		return err
+	}
+	for _, e := range report {
+		for _, repo := range e.Repos {
+			err = cw.Write([]string{
+				repo.Name,
+				fmt.Sprintf("%t", repo.IsPrivate),
+				e.SPDX,
+				repo.LicensePath,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gogs/git-module"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	embedConf "gogs.io/gogs/conf"
+)
+
+func TestDetectLicense(t *testing.T) {
+	repoPath := t.TempDir()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=alice", "GIT_AUTHOR_EMAIL=alice@example.com",
+			"GIT_COMMITTER_NAME=alice", "GIT_COMMITTER_EMAIL=alice@example.com",
+		)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		return strings.TrimSpace(string(output))
+	}
+	commit := func(t *testing.T) *git.Commit {
+		run("add", "-A")
+		run("commit", "--allow-empty", "-m", "update")
+		gitRepo, err := git.Open(repoPath)
+		require.NoError(t, err)
+		c, err := gitRepo.CatFileCommit(run("rev-parse", "HEAD"))
+		require.NoError(t, err)
+		return c
+	}
+	writeFile := func(t *testing.T, name string, data []byte) {
+		require.NoError(t, os.WriteFile(filepath.Join(repoPath, name), data, 0o644))
+	}
+	run("init")
+
+	spdx, path, err := detectLicense(commit(t))
+	require.NoError(t, err)
+	assert.Empty(t, spdx)
+	assert.Empty(t, path)
+
+	writeFile(t, "COPYING", []byte("Do whatever you want."))
+	spdx, path, err = detectLicense(commit(t))
+	require.NoError(t, err)
+	assert.Equal(t, LicenseNoAssertion, spdx)
+	assert.Equal(t, "COPYING", path)
+
+	mit, err := embedConf.Files.ReadFile("license/MIT License")
+	require.NoError(t, err)
+	writeFile(t, "LICENSE.md", bytes.Replace(mit, []byte("<year> <copyright holders>"), []byte("2026 Alice"), 1))
+	spdx, path, err = detectLicense(commit(t))
+	require.NoError(t, err)
+	assert.Equal(t, "MIT", spdx)
+	assert.Equal(t, "LICENSE.md", path)
+}
+
+func TestBuildOrgLicenseReport(t *testing.T) {
+	repos := []*Repository{
+		{ID: 1, Name: "api", LicenseSPDX: "MIT", LicensePath: "LICENSE"},
+		{ID: 2, Name: "docs"},
+		{ID: 3, Name: "infra", LicenseSPDX: LicenseNoAssertion, LicensePath: "COPYING", IsPrivate: true},
+		{ID: 4, Name: "web", LicenseSPDX: "MIT", LicensePath: "LICENSE.md"},
+	}
+	report := buildOrgLicenseReport(repos)
+
+	require.Len(t, report, 3)
+	assert.Equal(t, "MIT", report[0].SPDX)
+	assert.Equal(t, "MIT License", report[0].Name)
+	assert.Equal(t, []*Repository{repos[0], repos[3]}, report[0].Repos)
+	assert.Equal(t, "", report[1].SPDX)
+	assert.Equal(t, []*Repository{repos[1]}, report[1].Repos)
+	assert.Equal(t, LicenseNoAssertion, report[2].SPDX)
+	assert.Equal(t, []*Repository{repos[2]}, report[2].Repos)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteOrgLicenseReportCSV(&buf, report))
+	want := `repository,private,license,path
+api,false,MIT,LICENSE
+web,false,MIT,LICENSE.md
+docs,false,,
+infra,true,NOASSERTION,COPYING
+`
+	assert.Equal(t, want, buf.String())
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+
+	"gogs.io/gogs/internal/gitutil"
+)
+
+type maintainersCacheEntry struct {
+	commitID string
+	names    []string
+}
+
+// maintainersCache caches user names listed in MAINTAINERS files by repository
+// IDs, which are valid as long as the default branch is not changed.
+var maintainersCache = struct {
+	sync.Mutex
+	entries map[int64]maintainersCacheEntry
+}{
+	entries: make(map[int64]maintainersCacheEntry),
+}
+
+// ListedMaintainers returns lower-cased user names listed in the MAINTAINERS
+// file on the default branch, see gitutil.MaintainersPaths for the lookup
+// order. It returns nil if the file does not exist.
+func (repo *Repository) ListedMaintainers() ([]string, error) {
+	gitRepo, err := git.Open(repo.RepoPath())
+	if err != nil {
+		return nil, errors.Wrap(err, "open repository")
+	}
+	commit, err := gitRepo.BranchCommit(repo.DefaultBranch)
+	if err != nil {
+		if gitutil.IsErrRevisionNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "get commit of branch %q", repo.DefaultBranch)
+	}
+	commitID := commit.ID.String()
+
+	maintainersCache.Lock()
+	entry, ok := maintainersCache.entries[repo.ID]
+	maintainersCache.Unlock()
+	if ok && entry.commitID == commitID {
+		return entry.names, nil
+	}
+
+	names, err := gitutil.CommitMaintainers(commit)
+	if err != nil {
+		if !gitutil.IsErrRevisionNotExist(errors.Cause(err)) {
+			return nil, err
+		}
+		names = nil
+	}
+
+	maintainersCache.Lock()
+	maintainersCache.entries[repo.ID] = maintainersCacheEntry{
+		commitID: commitID,
+		names:    names,
+	}
+	maintainersCache.Unlock()
+	return names, nil
+}
+
+// IsListedMaintainer returns true if the repository enables the MAINTAINERS
+// file and the user is listed in it, which grants the user permission to
+// triage issues and pull requests.
+func (repo *Repository) IsListedMaintainer(u *User) (bool, error) {
+	if !repo.EnableMaintainersFile || u == nil || repo.IsBare || repo.IsTrackerOnly {
+		return false, nil
+	}
+
+	names, err := repo.ListedMaintainers()
+	if err != nil {
+		return false, err
+	}
+	for _, name := range names {
+		if name == strings.ToLower(u.Name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
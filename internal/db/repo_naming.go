@@ -0,0 +1,146 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"gogs.io/gogs/internal/conf"
+)
+
+// RepoNamingConvention is a convention that names and topics of new
+// repositories must follow.
+type RepoNamingConvention struct {
+	// NamePattern is the regular expression that the whole name must match,
+	// empty means any name.
+	NamePattern string
+	// NamePatternHelp describes NamePattern to users.
+	NamePatternHelp string
+	// RequiredTopics are topics that must be set. Each can be a pattern with "*"
+	// to require any matching topic.
+	RequiredTopics []string
+}
+
+// IsEmpty returns true if the convention does not require anything.
+func (nc *RepoNamingConvention) IsEmpty() bool {
+	return nc.NamePattern == "" && len(nc.RequiredTopics) == 0
+}
+
+// ParseRequiredRepoTopics returns distinct lower-cased required topics in the
+// comma-separated list, ignoring empty ones.
+func ParseRequiredRepoTopics(list string) []string {
+	var topics []string
+	seen := make(map[string]bool)
+	for _, topic := range strings.Split(list, ",") {
+		topic = strings.ToLower(strings.TrimSpace(topic))
+		if topic == "" || seen[topic] {
+			continue
+		}
+		seen[topic] = true
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+// ValidateRepoNamingConvention returns an error if the name pattern is not a
+// valid regular expression or any of required topics is malformed.
+func ValidateRepoNamingConvention(nc *RepoNamingConvention) error {
+	if nc.NamePattern != "" {
+		if _, err := regexp.Compile(nc.NamePattern); err != nil {
+			return err
+		}
+	}
+	for _, topic := range nc.RequiredTopics {
+		if _, err := path.Match(topic, ""); err != nil {
+			return fmt.Errorf("required topic %q: %v", topic, err)
+		}
+	}
+	return nil
+}
+
+// RepoNamingConventions returns naming conventions that new repositories of
+// the owner must follow, which are the ones of the instance followed by the
+// ones of the organization. Empty conventions are omitted.
+func RepoNamingConventions(owner *User) []*RepoNamingConvention {
+	var conventions []*RepoNamingConvention
+	site := &RepoNamingConvention{
+		NamePattern:     conf.Repository.Naming.NamePattern,
+		NamePatternHelp: conf.Repository.Naming.NamePatternHelp,
+		RequiredTopics:  ParseRequiredRepoTopics(strings.Join(conf.Repository.Naming.RequiredTopics, ",")),
+	}
+	if !site.IsEmpty() {
+		conventions = append(conventions, site)
+	}
+	if owner.IsOrganization() {
+		org := &RepoNamingConvention{
+			NamePattern:     owner.RepoNamePattern,
+			NamePatternHelp: owner.RepoNamePatternHelp,
+			RequiredTopics:  ParseRequiredRepoTopics(owner.RequiredRepoTopics),
+		}
+		if !org.IsEmpty() {
+			conventions = append(conventions, org)
+		}
+	}
+	return conventions
+}
+
+type ErrRepoNamingConvention struct {
+	reason string
+}
+
+func IsErrRepoNamingConvention(err error) bool {
+	_, ok := err.(ErrRepoNamingConvention)
+	return ok
+}
+
+// Reason returns the description of the violated convention to users.
+func (err ErrRepoNamingConvention) Reason() string {
+	return err.reason
+}
+
+func (err ErrRepoNamingConvention) Error() string {
+	return "repository does not follow naming conventions: " + err.reason
+}
+
+// checkRepoNamingConventions returns ErrRepoNamingConvention if the name or
+// normalized topics violate any of naming conventions.
+func checkRepoNamingConventions(conventions []*RepoNamingConvention, name string, topics []string) error {
+	for _, nc := range conventions {
+		if nc.NamePattern != "" {
+			pattern, err := regexp.Compile("^(?:" + nc.NamePattern + ")$")
+			if err != nil {
+				return fmt.Errorf("compile name pattern %q: %v", nc.NamePattern, err)
+			}
+			if !pattern.MatchString(name) {
+				reason := nc.NamePatternHelp
+				if reason == "" {
+					reason = fmt.Sprintf("name must match the pattern %q", nc.NamePattern)
+				}
+				return ErrRepoNamingConvention{reason: reason}
+			}
+		}
+
+		var missing []string
+		for _, required := range nc.RequiredTopics {
+			found := false
+			for _, topic := range topics {
+				if ok, _ := path.Match(required, topic); ok {
+					found = true
+					break
+				}
+			}
+			if !found {
+				missing = append(missing, fmt.Sprintf("%q", required))
+			}
+		}
+		if len(missing) > 0 {
+			return ErrRepoNamingConvention{reason: "topics must include " + strings.Join(missing, ", ")}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gogs.io/gogs/internal/conf"
+)
+
+func TestParseRequiredRepoTopics(t *testing.T) {
+	assert.Nil(t, ParseRequiredRepoTopics(" , "))
+	assert.Equal(t, []string{"service", "team-*"}, ParseRequiredRepoTopics("Service, team-*,,service"))
+}
+
+func TestValidateRepoNamingConvention(t *testing.T) {
+	assert.NoError(t, ValidateRepoNamingConvention(&RepoNamingConvention{
+		NamePattern:    "[a-z][a-z0-9-]*",
+		RequiredTopics: []string{"team-*"},
+	}))
+	assert.Error(t, ValidateRepoNamingConvention(&RepoNamingConvention{NamePattern: "[a-z"}))
+	assert.Error(t, ValidateRepoNamingConvention(&RepoNamingConvention{RequiredTopics: []string{"team-["}}))
+}
+
+func TestRepoNamingConventions(t *testing.T) {
+	conf.SetMockRepository(t, conf.RepositoryOpts{})
+	org := &User{Type: UserOrganization, RepoNamePattern: "svc-.+"}
+	assert.Nil(t, RepoNamingConventions(&User{RepoNamePattern: "svc-.+"}))
+	assert.Equal(t, []*RepoNamingConvention{{NamePattern: "svc-.+"}}, RepoNamingConventions(org))
+
+	opts := conf.RepositoryOpts{}
+	opts.Naming.RequiredTopics = []string{"Service"}
+	conf.SetMockRepository(t, opts)
+	assert.Equal(t,
+		[]*RepoNamingConvention{
+			{RequiredTopics: []string{"service"}},
+			{NamePattern: "svc-.+"},
+		},
+		RepoNamingConventions(org),
+	)
+}
+
+func TestCheckRepoNamingConventions(t *testing.T) {
+	conventions := []*RepoNamingConvention{
+		{NamePattern: "[a-z][a-z0-9-]*"},
+		{
+			NamePattern:     "payments-.+",
+			NamePatternHelp: "names must start with \"payments-\"",
+			RequiredTopics:  []string{"service", "team-*"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		topics     []string
+		wantReason string
+	}{
+		{name: "payments-api", topics: []string{"service", "team-payments"}},
+		{name: "Payments-api", wantReason: `name must match the pattern "[a-z][a-z0-9-]*"`},
+		{name: "billing", wantReason: `names must start with "payments-"`},
+		{name: "payments-api", topics: []string{"team-payments"}, wantReason: `topics must include "service"`},
+		{name: "payments-api", wantReason: `topics must include "service", "team-*"`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkRepoNamingConventions(conventions, test.name, test.topics)
+			if test.wantReason == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, ErrRepoNamingConvention{reason: test.wantReason}, err)
+		})
+	}
+}
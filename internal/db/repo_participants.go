@@ -0,0 +1,221 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/email"
+	"gogs.io/gogs/internal/errutil"
+)
+
+// ParseParticipants splits a list of users and teams (in the form of
+// "<org>/<team>") separated by commas or whitespace. The optional "@" prefix of
+// each entry is stripped.
+func ParseParticipants(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+	participants := make([]string, 0, len(fields))
+	for _, f := range fields {
+		f = strings.TrimPrefix(f, "@")
+		if f != "" {
+			participants = append(participants, f)
+		}
+	}
+	return participants
+}
+
+type ErrParticipantNotExist struct {
+	args errutil.Args
+}
+
+func IsErrParticipantNotExist(err error) bool {
+	_, ok := err.(ErrParticipantNotExist)
+	return ok
+}
+
+func (err ErrParticipantNotExist) Error() string {
+	return fmt.Sprintf("participant does not exist: %v", err.args)
+}
+
+// Name returns the name of the participant that does not exist.
+func (err ErrParticipantNotExist) Name() string {
+	return err.args["name"].(string)
+}
+
+// resolveParticipant returns users of the participant, which is either a user
+// or a team of the organization that owns the repository. It returns
+// ErrParticipantNotExist when the participant does not exist.
+func (repo *Repository) resolveParticipant(name string) ([]*User, error) {
+	notExist := ErrParticipantNotExist{args: errutil.Args{"name": name}}
+
+	if i := strings.Index(name, "/"); i >= 0 {
+		if err := repo.GetOwner(); err != nil {
+			return nil, fmt.Errorf("get owner: %v", err)
+		} else if !repo.Owner.IsOrganization() || !strings.EqualFold(repo.Owner.Name, name[:i]) {
+			return nil, notExist
+		}
+
+		team, err := GetTeamOfOrgByName(repo.OwnerID, name[i+1:])
+		if err != nil {
+			if IsErrTeamNotExist(err) {
+				return nil, notExist
+			}
+			return nil, fmt.Errorf("get team: %v", err)
+		}
+		return GetTeamMembers(team.ID)
+	}
+
+	u, err := GetUserByName(name)
+	if err != nil {
+		if IsErrUserNotExist(err) {
+			return nil, notExist
+		}
+		return nil, fmt.Errorf("get user: %v", err)
+	} else if u.IsOrganization() {
+		return nil, notExist
+	}
+	return []*User{u}, nil
+}
+
+// ValidateParticipants returns ErrParticipantNotExist for the first participant
+// that is neither a user nor a team of the organization that owns the
+// repository.
+func (repo *Repository) ValidateParticipants(names []string) error {
+	for _, name := range names {
+		if _, err := repo.resolveParticipant(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eligibleParticipants returns active users of each participant who have at
+// least given access mode to the repository, grouped by participants in the
+// same order. Participants that no longer exist are skipped.
+func (repo *Repository) eligibleParticipants(names []string, mode AccessMode) ([][]*User, error) {
+	groups := make([][]*User, 0, len(names))
+	for _, name := range names {
+		users, err := repo.resolveParticipant(name)
+		if err != nil {
+			if IsErrParticipantNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		eligible := make([]*User, 0, len(users))
+		for _, u := range users {
+			if !u.IsActive {
+				continue
+			}
+
+			if !Perms.Authorize(context.TODO(), u.ID, repo.ID, mode,
+				AccessModeOptions{
+					OwnerID: repo.OwnerID,
+					Private: repo.IsPrivate,
+				},
+			) {
+				continue
+			}
+			eligible = append(eligible, u)
+		}
+		if len(eligible) > 0 {
+			groups = append(groups, eligible)
+		}
+	}
+	return groups, nil
+}
+
+// DefaultAssignee returns the assignee for new issues based on the default
+// assignees of the repository, or nil if there is none. The first participant
+// that has eligible users is used, and the member with the fewest open issues
+// assigned in the repository is picked for teams.
+func (repo *Repository) DefaultAssignee() (*User, error) {
+	groups, err := repo.eligibleParticipants(ParseParticipants(repo.DefaultAssignees), AccessModeWrite)
+	if err != nil {
+		return nil, err
+	} else if len(groups) == 0 {
+		return nil, nil
+	}
+
+	var (
+		assignee *User
+		minCount int64 = -1
+	)
+	for _, u := range groups[0] {
+		count, err := x.Where("repo_id = ? AND assignee_id = ? AND is_closed = ?", repo.ID, u.ID, false).Count(new(Issue))
+		if err != nil {
+			return nil, fmt.Errorf("count assigned issues: %v", err)
+		}
+		if minCount < 0 || count < minCount {
+			assignee = u
+			minCount = count
+		}
+	}
+	return assignee, nil
+}
+
+// PullRequestReviewers returns reviewers for a new pull request. Owners of
+// changed files defined by CODEOWNERS take precedence, and the default
+// reviewers of the repository are used only when there are none. Reviewers are
+// deduplicated and must have read access to the repository.
+func (repo *Repository) PullRequestReviewers(codeowners []string) ([]*User, error) {
+	groups, err := repo.eligibleParticipants(codeowners, AccessModeRead)
+	if err != nil {
+		return nil, fmt.Errorf("resolve code owners: %v", err)
+	}
+	if len(groups) == 0 {
+		groups, err = repo.eligibleParticipants(ParseParticipants(repo.DefaultReviewers), AccessModeRead)
+		if err != nil {
+			return nil, fmt.Errorf("resolve default reviewers: %v", err)
+		}
+	}
+
+	var reviewers []*User
+	seen := make(map[int64]bool)
+	for _, users := range groups {
+		for _, u := range users {
+			if !seen[u.ID] {
+				seen[u.ID] = true
+				reviewers = append(reviewers, u)
+			}
+		}
+	}
+	return reviewers, nil
+}
+
+// RequestReviews marks reviewers as mentioned in the pull request so it shows
+// up in their dashboards, and sends review request emails to them.
+func (issue *Issue) RequestReviews(doer *User, reviewers []*User) error {
+	ids := make([]int64, 0, len(reviewers))
+	tos := make([]*User, 0, len(reviewers))
+	for _, u := range reviewers {
+		if u.ID == doer.ID {
+			continue
+		}
+		ids = append(ids, u.ID)
+		tos = append(tos, u)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := updateIssueUsersByMentions(x, issue.ID, ids); err != nil {
+		return fmt.Errorf("update issue users by mentions: %v", err)
+	}
+
+	if conf.User.EnableEmailNotification {
+		for _, b := range batchNotificationRecipients(tos, time.Now()) {
+			email.SendIssueReviewRequestMail(NewMailerIssue(issue), NewMailerRepo(issue.Repo), NewMailerUser(doer), b.Emails, b.DeliverAt)
+		}
+	}
+	return nil
+}
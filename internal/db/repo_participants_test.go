@@ -0,0 +1,29 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseParticipants(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{input: "", want: []string{}},
+		{input: " , ", want: []string{}},
+		{input: "alice", want: []string{"alice"}},
+		{input: "@alice, bob\n@org/team", want: []string{"alice", "bob", "org/team"}},
+		{input: "alice,,@ ,bob", want: []string{"alice", "bob"}},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			assert.Equal(t, test.want, ParseParticipants(test.input))
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+	"path"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/gitutil"
+	"gogs.io/gogs/internal/tool"
+)
+
+// lowerLimit returns the lower one of two limits, where 0 means no limit.
+func lowerLimit(a, b int64) int64 {
+	if a <= 0 {
+		return b
+	} else if b <= 0 || a < b {
+		return a
+	}
+	return b
+}
+
+// PushSizeLimits returns the effective limits in bytes on the size of each file
+// and the total size of files introduced by a push to the repository, 0 means
+// no limit. Limits of the repository only take effect when they are lower than
+// limits of the instance.
+func (repo *Repository) PushSizeLimits() (maxFileSize, maxSize int64) {
+	maxFileSize = lowerLimit(conf.Repository.Push.MaxFileSize, repo.MaxPushFileSize)
+	maxSize = lowerLimit(conf.Repository.Push.MaxSize, repo.MaxPushSize)
+	return maxFileSize * 1024 * 1024, maxSize * 1024 * 1024
+}
+
+// CheckPushSize checks blobs introduced by a push against given limits in
+// bytes, where 0 means no limit. It returns a message for the pusher that
+// explains the violation and suggests using Git LFS, or an empty string when
+// the push is within the limits.
+func CheckPushSize(blobs []*gitutil.BlobInfo, maxFileSize, maxSize int64) string {
+	var total int64
+	for _, b := range blobs {
+		if maxFileSize > 0 && b.Size > maxFileSize {
+			pattern := b.Path
+			if ext := path.Ext(b.Path); ext != "" {
+				pattern = "*" + ext
+			}
+			return fmt.Sprintf("File '%s' is %s, which exceeds the file size limit of %s. "+
+				"Consider tracking large files with Git LFS, e.g. `git lfs track %q`, "+
+				"and rewriting commits that added the file with `git lfs migrate import --include=%q`.",
+				b.Path, tool.FileSize(b.Size), tool.FileSize(maxFileSize), pattern, pattern)
+		}
+		total += b.Size
+	}
+
+	if maxSize > 0 && total > maxSize {
+		return fmt.Sprintf("Push introduces %s of files, which exceeds the push size limit of %s. "+
+			"Consider tracking large files with Git LFS, or pushing fewer commits at a time.",
+			tool.FileSize(total), tool.FileSize(maxSize))
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/gitutil"
+)
+
+func TestRepository_PushSizeLimits(t *testing.T) {
+	before := conf.Repository.Push
+	t.Cleanup(func() {
+		conf.Repository.Push = before
+	})
+	conf.Repository.Push.MaxFileSize = 100
+	conf.Repository.Push.MaxSize = 0
+
+	tests := []struct {
+		name            string
+		repo            *Repository
+		wantMaxFileSize int64
+		wantMaxSize     int64
+	}{
+		{
+			name:            "instance limits",
+			repo:            &Repository{},
+			wantMaxFileSize: 100 << 20,
+			wantMaxSize:     0,
+		},
+		{
+			name:            "lower repository limits",
+			repo:            &Repository{MaxPushFileSize: 10, MaxPushSize: 500},
+			wantMaxFileSize: 10 << 20,
+			wantMaxSize:     500 << 20,
+		},
+		{
+			name:            "higher repository limits",
+			repo:            &Repository{MaxPushFileSize: 200},
+			wantMaxFileSize: 100 << 20,
+			wantMaxSize:     0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			maxFileSize, maxSize := test.repo.PushSizeLimits()
+			assert.Equal(t, test.wantMaxFileSize, maxFileSize)
+			assert.Equal(t, test.wantMaxSize, maxSize)
+		})
+	}
+}
+
+func TestCheckPushSize(t *testing.T) {
+	blobs := []*gitutil.BlobInfo{
+		{Path: "main.go", Size: 1 << 10},
+		{Path: "assets/video.mp4", Size: 150 << 20},
+		{Path: "Makefile", Size: 60 << 20},
+	}
+
+	assert.Empty(t, CheckPushSize(blobs, 0, 0))
+	assert.Empty(t, CheckPushSize(blobs, 200<<20, 300<<20))
+	assert.Equal(t,
+		"File 'assets/video.mp4' is 150 MB, which exceeds the file size limit of 100 MB. "+
+			"Consider tracking large files with Git LFS, e.g. `git lfs track \"*.mp4\"`, "+
+			"and rewriting commits that added the file with `git lfs migrate import --include=\"*.mp4\"`.",
+		CheckPushSize(blobs, 100<<20, 0),
+	)
+	assert.Contains(t, CheckPushSize(blobs[2:], 50<<20, 0), "`git lfs track \"Makefile\"`")
+	assert.Equal(t,
+		"Push introduces 210 MB of files, which exceeds the push size limit of 200 MB. "+
+			"Consider tracking large files with Git LFS, or pushing fewer commits at a time.",
+		CheckPushSize(blobs, 0, 200<<20),
+	)
+}
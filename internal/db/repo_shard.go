@@ -0,0 +1,99 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/repoutil"
+)
+
+// repoStorageShard returns the repository shard of the repository with given
+// owner and repository name. It returns the shard assigned to new repositories
+// of the owner if the repository does not exist yet.
+func repoStorageShard(ownerName, repoName string) int {
+	if len(conf.Repository.ShardRoots) == 0 {
+		return 0
+	}
+
+	owner := new(User)
+	has, err := x.Where("lower_name = ?", strings.ToLower(ownerName)).Cols("id").Get(owner)
+	if err != nil {
+		log.Error("Failed to get owner %q of repository: %v", ownerName, err)
+		return 0
+	} else if !has {
+		return repoutil.AssignShard(ownerName)
+	}
+
+	repo := new(Repository)
+	has, err = x.Where("owner_id = ? AND lower_name = ?", owner.ID, strings.ToLower(repoName)).Cols("storage_shard").Get(repo)
+	if err != nil {
+		log.Error("Failed to get storage shard of repository %s/%s: %v", ownerName, repoName, err)
+		return 0
+	} else if !has {
+		return repoutil.AssignShard(ownerName)
+	}
+	return repo.StorageShard
+}
+
+// RepositoryShardCheckTimeout is the time to wait for the root path of a
+// repository shard to respond in health checks.
+const RepositoryShardCheckTimeout = 5 * time.Second
+
+// RepositoryShard is the state of a repository shard.
+type RepositoryShard struct {
+	Shard    int
+	Root     string
+	NumRepos int64
+	// Err is the error of the health check, nil if the root path is healthy.
+	Err error
+}
+
+// GetRepositoryShards returns the state of all configured repository shards.
+// Root paths are checked concurrently so that a hanging mount does not delay
+// checks of others.
+func GetRepositoryShards() ([]*RepositoryShard, error) {
+	var counts []*struct {
+		StorageShard int
+		Count        int64
+	}
+	err := x.Table("repository").
+		Select("storage_shard, COUNT(*) AS count").
+		GroupBy("storage_shard").
+		Find(&counts)
+	if err != nil {
+		return nil, fmt.Errorf("count repositories: %v", err)
+	}
+
+	roots := repoutil.Roots()
+	shards := make([]*RepositoryShard, len(roots))
+	done := make(chan struct{}, len(roots))
+	for i, root := range roots {
+		shard := &RepositoryShard{Shard: i, Root: root}
+		shards[i] = shard
+		go func() {
+			shard.Err = repoutil.CheckRoot(shard.Root, RepositoryShardCheckTimeout)
+			done <- struct{}{}
+		}()
+	}
+	for range roots {
+		<-done
+	}
+
+	for _, c := range counts {
+		// Repositories of removed shards are looked up in the primary root.
+		shard := c.StorageShard
+		if shard < 0 || shard >= len(shards) {
+			shard = 0
+		}
+		shards[shard].NumRepos += c.Count
+	}
+	return shards, nil
+}
@@ -1,9 +1,12 @@
 package db
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"gogs.io/gogs/internal/markup"
 )
@@ -52,3 +55,27 @@ func TestRepository_ComposeMetas(t *testing.T) {
 		assert.Equal(t, "https://someurl.com/{user}/{repo}/{issue}", metas["format"])
 	})
 }
+
+func TestCopyInitialContent(t *testing.T) {
+	srcDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "src"), os.ModePerm))
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, ".git"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "README.md"), []byte("# Hello"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "src", "build.sh"), []byte("#!/bin/sh"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, ".git", "config"), []byte("[core]"), 0o644))
+	require.NoError(t, os.Symlink("/etc/passwd", filepath.Join(srcDir, "link")))
+
+	dstDir := t.TempDir()
+	require.NoError(t, copyInitialContent(srcDir, dstDir))
+
+	p, err := os.ReadFile(filepath.Join(dstDir, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Hello", string(p))
+
+	fi, err := os.Stat(filepath.Join(dstDir, "src", "build.sh"))
+	require.NoError(t, err)
+	assert.NotZero(t, fi.Mode().Perm()&0o100)
+
+	assert.NoDirExists(t, filepath.Join(dstDir, ".git"))
+	assert.NoFileExists(t, filepath.Join(dstDir, "link"))
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/errutil"
+)
+
+// MaxRepoTopics is the maximum number of topics of a repository.
+const MaxRepoTopics = 20
+
+var repoTopicPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,34}$`)
+
+// TopicList returns topics of the repository.
+func (repo *Repository) TopicList() []string {
+	if repo.Topics == "" {
+		return []string{}
+	}
+	return strings.Split(repo.Topics, ",")
+}
+
+type ErrRepoTopicInvalid struct {
+	args errutil.Args
+}
+
+func IsErrRepoTopicInvalid(err error) bool {
+	_, ok := err.(ErrRepoTopicInvalid)
+	return ok
+}
+
+func (err ErrRepoTopicInvalid) Error() string {
+	return fmt.Sprintf("repository topic is invalid: %v", err.args)
+}
+
+// NormalizeRepoTopics returns distinct lower-cased topics in the order of
+// appearance, ignoring empty ones. It returns ErrRepoTopicInvalid when any of
+// the topics does not consist of at most 35 letters, numbers and hyphens
+// starting with a letter or number, or there are more than MaxRepoTopics
+// topics.
+func NormalizeRepoTopics(topics []string) ([]string, error) {
+	normalized := make([]string, 0, len(topics))
+	seen := make(map[string]bool, len(topics))
+	for _, topic := range topics {
+		topic = strings.ToLower(strings.TrimSpace(topic))
+		if topic == "" || seen[topic] {
+			continue
+		} else if !repoTopicPattern.MatchString(topic) {
+			return nil, ErrRepoTopicInvalid{args: errutil.Args{"topic": topic}}
+		}
+		seen[topic] = true
+		normalized = append(normalized, topic)
+	}
+	if len(normalized) > MaxRepoTopics {
+		return nil, ErrRepoTopicInvalid{args: errutil.Args{"count": len(normalized)}}
+	}
+	return normalized, nil
+}
+
+// SetRepoTopics replaces topics of the repository, and sends repository
+// webhooks when the topics are changed.
+func SetRepoTopics(doer *User, repo *Repository, topics []string) error {
+	topics, err := NormalizeRepoTopics(topics)
+	if err != nil {
+		return err
+	}
+
+	oldTopics := repo.TopicList()
+	repo.Topics = strings.Join(topics, ",")
+	if repo.Topics == strings.Join(oldTopics, ",") {
+		return nil
+	}
+	if _, err = x.ID(repo.ID).Cols("topics").Update(repo); err != nil {
+		return fmt.Errorf("update topics: %v", err)
+	}
+
+	if err = PrepareWebhooks(repo, HOOK_EVENT_REPOSITORY, &RepositoryPayload{
+		Action: RepositoryActionTopicsUpdated,
+		Changes: &RepositoryChangesPayload{
+			Topics: &RepositoryTopicsChange{
+				From: oldTopics,
+				To:   topics,
+			},
+		},
+		Repository: repo.APIFormatLegacy(nil),
+		Sender:     doer.APIFormat(),
+	}); err != nil {
+		log.Error("PrepareWebhooks [repo_id: %d]: %v", repo.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeRepoTopics(t *testing.T) {
+	tooMany := make([]string, MaxRepoTopics+1)
+	for i := range tooMany {
+		tooMany[i] = "topic-" + strconv.Itoa(i)
+	}
+
+	tests := []struct {
+		name    string
+		topics  []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty", topics: nil, want: []string{}},
+		{name: "normalized", topics: []string{" Go ", "git", "", "GO", "developer-portal"}, want: []string{"go", "git", "developer-portal"}},
+		{name: "leading hyphen", topics: []string{"-go"}, wantErr: true},
+		{name: "space", topics: []string{"dev tools"}, wantErr: true},
+		{name: "too long", topics: []string{"a123456789012345678901234567890123456"}, wantErr: true},
+		{name: "too many", topics: tooMany, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := NormalizeRepoTopics(test.topics)
+			if test.wantErr {
+				assert.True(t, IsErrRepoTopicInvalid(err), "want ErrRepoTopicInvalid but got %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestRepository_TopicList(t *testing.T) {
+	assert.Equal(t, []string{}, (&Repository{}).TopicList())
+	assert.Equal(t, []string{"go", "git"}, (&Repository{Topics: "go,git"}).TopicList())
+}
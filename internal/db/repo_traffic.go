@@ -0,0 +1,218 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/cryptoutil"
+)
+
+// RepoTrafficsStore is the persistent interface for daily traffic of
+// repositories.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type RepoTrafficsStore interface {
+	// DeleteVisitorsBefore deletes identities of visitors that are recorded for
+	// days before the given date.
+	DeleteVisitorsBefore(ctx context.Context, date string) error
+	// Increment increments the number of views or clones of the repository on
+	// the given date. The number of unique visitors or cloners is also
+	// incremented when the visitor is not yet recorded for the date. The visitor
+	// is not counted as unique when the hash is empty.
+	Increment(ctx context.Context, repoID int64, date string, kind RepoTrafficKind, visitorHash string) error
+	// ListByRepo returns daily traffic of the repository since the given date,
+	// ordered by date.
+	ListByRepo(ctx context.Context, repoID int64, since string) ([]*RepoTraffic, error)
+}
+
+var RepoTraffics RepoTrafficsStore
+
+// RepoTrafficKind is the kind of traffic of a repository.
+type RepoTrafficKind string
+
+const (
+	RepoTrafficView  RepoTrafficKind = "view"
+	RepoTrafficClone RepoTrafficKind = "clone"
+)
+
+// RepoTrafficDateFormat is the format of dates of traffic, which are in UTC.
+const RepoTrafficDateFormat = "2006-01-02"
+
+// RepoTraffic is the traffic of a repository in a day.
+type RepoTraffic struct {
+	ID     int64 `gorm:"primaryKey"`
+	RepoID int64 `gorm:"uniqueIndex:repo_traffic_repo_date_unique;not null"`
+	// Date is the day of the traffic in UTC, in the format of
+	// RepoTrafficDateFormat.
+	Date           string `gorm:"type:VARCHAR(10);uniqueIndex:repo_traffic_repo_date_unique;not null"`
+	Views          int64  `gorm:"not null"`
+	UniqueVisitors int64  `gorm:"not null"`
+	Clones         int64  `gorm:"not null"`
+	UniqueCloners  int64  `gorm:"not null"`
+}
+
+// RepoTrafficVisitor is a visitor of a repository on a day, which is used to
+// count unique visitors and cloners. Visitors are identified by salted hashes
+// that are only valid for the day, and are deleted after the day ends.
+type RepoTrafficVisitor struct {
+	ID     int64           `gorm:"primaryKey"`
+	RepoID int64           `gorm:"uniqueIndex:repo_traffic_visitor_unique;not null"`
+	Date   string          `gorm:"type:VARCHAR(10);uniqueIndex:repo_traffic_visitor_unique;index;not null"`
+	Kind   RepoTrafficKind `gorm:"type:VARCHAR(8);uniqueIndex:repo_traffic_visitor_unique;not null"`
+	Hash   string          `gorm:"type:VARCHAR(64);uniqueIndex:repo_traffic_visitor_unique;not null"`
+}
+
+var _ RepoTrafficsStore = (*repoTraffics)(nil)
+
+type repoTraffics struct {
+	*gorm.DB
+}
+
+// NewRepoTrafficsStore returns a persistent interface for daily traffic of
+// repositories with given database connection.
+func NewRepoTrafficsStore(db *gorm.DB) RepoTrafficsStore {
+	return &repoTraffics{DB: db}
+}
+
+func (db *repoTraffics) DeleteVisitorsBefore(ctx context.Context, date string) error {
+	return db.WithContext(ctx).Where("date < ?", date).Delete(new(RepoTrafficVisitor)).Error
+}
+
+func (db *repoTraffics) Increment(ctx context.Context, repoID int64, date string, kind RepoTrafficKind, visitorHash string) error {
+	total, unique := "views", "unique_visitors"
+	if kind == RepoTrafficClone {
+		total, unique = "clones", "unique_cloners"
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var isUnique int64
+		if visitorHash != "" {
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&RepoTrafficVisitor{
+				RepoID: repoID,
+				Date:   date,
+				Kind:   kind,
+				Hash:   visitorHash,
+			})
+			if result.Error != nil {
+				return result.Error
+			}
+			isUnique = result.RowsAffected
+		}
+
+		increment := func() (int64, error) {
+			result := tx.Model(new(RepoTraffic)).
+				Where("repo_id = ? AND date = ?", repoID, date).
+				Updates(map[string]interface{}{
+					total:  gorm.Expr(total + " + 1"),
+					unique: gorm.Expr(unique+" + ?", isUnique),
+				})
+			return result.RowsAffected, result.Error
+		}
+		updated, err := increment()
+		if err != nil || updated > 0 {
+			return err
+		}
+
+		// Create the row of the day, and increment again in case it has been
+		// created by a concurrent request in the meantime.
+		err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&RepoTraffic{
+			RepoID: repoID,
+			Date:   date,
+		}).Error
+		if err != nil {
+			return err
+		}
+		_, err = increment()
+		return err
+	})
+}
+
+func (db *repoTraffics) ListByRepo(ctx context.Context, repoID int64, since string) ([]*RepoTraffic, error) {
+	var traffics []*RepoTraffic
+	return traffics, db.WithContext(ctx).
+		Where("repo_id = ? AND date >= ?", repoID, since).
+		Order("date ASC").
+		Find(&traffics).
+		Error
+}
+
+var repoTrafficVisitorsCleanup = struct {
+	sync.Mutex
+	date string
+}{}
+
+// RecordRepoTraffic records a view or clone of the repository by the given user
+// or, for anonymous users, the remote address and user agent. Only salted
+// hashes of them are stored for the day to count unique visitors. Failures are
+// logged and not returned since traffic is not essential.
+func RecordRepoTraffic(repoID int64, kind RepoTrafficKind, doer *User, remoteAddr, userAgent string) {
+	if !conf.Repository.EnableTrafficAnalytics {
+		return
+	}
+
+	ctx := context.Background()
+	date := time.Now().UTC().Format(RepoTrafficDateFormat)
+
+	// Delete visitors of previous days once a day.
+	repoTrafficVisitorsCleanup.Lock()
+	if repoTrafficVisitorsCleanup.date != date {
+		repoTrafficVisitorsCleanup.date = date
+		err := RepoTraffics.DeleteVisitorsBefore(ctx, date)
+		if err != nil {
+			log.Error("Failed to delete visitors of repository traffic: %v", err)
+		}
+	}
+	repoTrafficVisitorsCleanup.Unlock()
+
+	var visitor string
+	if doer != nil {
+		visitor = fmt.Sprintf("user:%d", doer.ID)
+	} else if remoteAddr != "" {
+		if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+			remoteAddr = host
+		}
+		visitor = "addr:" + remoteAddr + "\x00" + userAgent
+	}
+
+	var hash string
+	if visitor != "" {
+		hash = cryptoutil.SHA256(conf.Security.SecretKey + "\x00" + date + "\x00" + visitor)
+	}
+	err := RepoTraffics.Increment(ctx, repoID, date, kind, hash)
+	if err != nil {
+		log.Error("Failed to record repository traffic [repo_id: %d, kind: %s]: %v", repoID, kind, err)
+	}
+}
+
+// FillRepoTraffic returns daily traffic for the given number of days until the
+// day of now in UTC, days without traffic are filled with zero counts.
+func FillRepoTraffic(traffics []*RepoTraffic, now time.Time, days int) []*RepoTraffic {
+	byDate := make(map[string]*RepoTraffic, len(traffics))
+	for _, t := range traffics {
+		byDate[t.Date] = t
+	}
+
+	today := now.UTC()
+	filled := make([]*RepoTraffic, days)
+	for i := range filled {
+		date := today.AddDate(0, 0, i-days+1).Format(RepoTrafficDateFormat)
+		t, ok := byDate[date]
+		if !ok {
+			t = &RepoTraffic{Date: date}
+		}
+		filled[i] = t
+	}
+	return filled
+}
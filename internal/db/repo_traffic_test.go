@@ -0,0 +1,126 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+func TestRepoTraffics(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(RepoTraffic), new(RepoTrafficVisitor)}
+	db := &repoTraffics{
+		DB: dbtest.NewDB(t, "repoTraffics", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *repoTraffics)
+	}{
+		{"Increment", repoTrafficsIncrement},
+		{"DeleteVisitorsBefore", repoTrafficsDeleteVisitorsBefore},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func repoTrafficsIncrement(t *testing.T, db *repoTraffics) {
+	ctx := context.Background()
+
+	for _, args := range []struct {
+		repoID int64
+		date   string
+		kind   RepoTrafficKind
+		hash   string
+	}{
+		{1, "2026-05-09", RepoTrafficView, "alice"},
+		{1, "2026-05-10", RepoTrafficView, "alice"},
+		{1, "2026-05-10", RepoTrafficView, "alice"},
+		{1, "2026-05-10", RepoTrafficView, "bob"},
+		{1, "2026-05-10", RepoTrafficView, ""},
+		{1, "2026-05-10", RepoTrafficClone, "alice"},
+		{1, "2026-05-10", RepoTrafficClone, "alice"},
+		{2, "2026-05-10", RepoTrafficView, "alice"},
+	} {
+		err := db.Increment(ctx, args.repoID, args.date, args.kind, args.hash)
+		require.NoError(t, err)
+	}
+
+	traffics, err := db.ListByRepo(ctx, 1, "2026-05-01")
+	require.NoError(t, err)
+	for _, traffic := range traffics {
+		traffic.ID = 0
+	}
+	want := []*RepoTraffic{
+		{RepoID: 1, Date: "2026-05-09", Views: 1, UniqueVisitors: 1},
+		{RepoID: 1, Date: "2026-05-10", Views: 4, UniqueVisitors: 2, Clones: 2, UniqueCloners: 1},
+	}
+	assert.Equal(t, want, traffics)
+
+	traffics, err = db.ListByRepo(ctx, 1, "2026-05-10")
+	require.NoError(t, err)
+	assert.Len(t, traffics, 1)
+}
+
+func repoTrafficsDeleteVisitorsBefore(t *testing.T, db *repoTraffics) {
+	ctx := context.Background()
+
+	err := db.Increment(ctx, 1, "2026-05-09", RepoTrafficView, "alice")
+	require.NoError(t, err)
+	err = db.Increment(ctx, 1, "2026-05-10", RepoTrafficView, "alice")
+	require.NoError(t, err)
+
+	err = db.DeleteVisitorsBefore(ctx, "2026-05-10")
+	require.NoError(t, err)
+
+	var visitors []*RepoTrafficVisitor
+	err = db.Find(&visitors).Error
+	require.NoError(t, err)
+	require.Len(t, visitors, 1)
+	assert.Equal(t, "2026-05-10", visitors[0].Date)
+
+	// Counts of days are kept.
+	traffics, err := db.ListByRepo(ctx, 1, "")
+	require.NoError(t, err)
+	assert.Len(t, traffics, 2)
+}
+
+func TestFillRepoTraffic(t *testing.T) {
+	now := time.Date(2026, 5, 10, 1, 0, 0, 0, time.FixedZone("UTC+8", 8*60*60))
+	got := FillRepoTraffic(
+		[]*RepoTraffic{
+			{RepoID: 1, Date: "2026-05-08", Views: 1},
+			{RepoID: 1, Date: "2026-05-09", Views: 2},
+		},
+		now,
+		3,
+	)
+	want := []*RepoTraffic{
+		{Date: "2026-05-07"},
+		{RepoID: 1, Date: "2026-05-08", Views: 1},
+		{RepoID: 1, Date: "2026-05-09", Views: 2},
+	}
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,287 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/unknwon/com"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/errutil"
+	"gogs.io/gogs/internal/repoutil"
+)
+
+const _PURGE_TRASHED_REPOS = "purge_trashed_repos"
+
+// trashDir is the directory in each repository shard to keep Git data of
+// trashed repositories. The "@" is not allowed in user names, so it never
+// conflicts with user directories.
+const trashDir = "@trash"
+
+// trashedLowerName returns the lower name of a trashed repository. The "@" is
+// not allowed in repository names, so the original name is freed up for new
+// repositories and the trashed one is never matched by name.
+func trashedLowerName(name string, id int64) string {
+	return fmt.Sprintf("%s@trash-%d", strings.ToLower(name), id)
+}
+
+// IsTrashed returns true if the repository is in the trash.
+func (repo *Repository) IsTrashed() bool {
+	return repo.TrashedUnix > 0
+}
+
+// TrashPath returns the path of Git data of the repository while it is in the
+// trash.
+func (repo *Repository) TrashPath() string {
+	return filepath.Join(repoutil.ShardRoot(repo.StorageShard), trashDir, com.ToStr(repo.ID)+".git")
+}
+
+// TrashWikiPath returns the path of wiki Git data of the repository while it is
+// in the trash.
+func (repo *Repository) TrashWikiPath() string {
+	return filepath.Join(repoutil.ShardRoot(repo.StorageShard), trashDir, com.ToStr(repo.ID)+".wiki.git")
+}
+
+// RemoveRepository deletes the repository by moving it to the trash when
+// "[repository] ENABLE_TRASH" is on, or deletes it permanently otherwise.
+func RemoveRepository(doer *User, ownerID, repoID int64) error {
+	if !conf.Repository.EnableTrash {
+		return DeleteRepository(ownerID, repoID)
+	}
+
+	repo := &Repository{ID: repoID, OwnerID: ownerID}
+	has, err := x.Get(repo)
+	if err != nil {
+		return err
+	} else if !has {
+		return ErrRepoNotExist{args: map[string]interface{}{"ownerID": ownerID, "repoID": repoID}}
+	}
+	return TrashRepository(doer, repo)
+}
+
+// moveRepoData moves Git data of the repository and its wiki. Moved paths are
+// moved back when it fails halfway.
+func moveRepoData(repoPath, wikiPath, newRepoPath, newWikiPath string) error {
+	if err := os.MkdirAll(filepath.Dir(newRepoPath), os.ModePerm); err != nil {
+		return fmt.Errorf("create parent directory: %v", err)
+	}
+	if err := os.Rename(repoPath, newRepoPath); err != nil {
+		return fmt.Errorf("move repository: %v", err)
+	}
+	if com.IsDir(wikiPath) {
+		if err := os.Rename(wikiPath, newWikiPath); err != nil {
+			if err2 := os.Rename(newRepoPath, repoPath); err2 != nil {
+				log.Error("Failed to move back repository %q: %v", newRepoPath, err2)
+			}
+			return fmt.Errorf("move wiki: %v", err)
+		}
+	}
+	return nil
+}
+
+// TrashRepository moves the repository to the trash, where it is invisible to
+// everyone and its name is freed up. Its Git data is moved to the trash
+// directory of its shard until it is restored or purged.
+func TrashRepository(doer *User, repo *Repository) (err error) {
+	if repo.IsTrashed() {
+		return nil
+	}
+
+	repoPath := repo.RepoPath()
+	wikiPath := repo.WikiPath()
+	if err = moveRepoData(repoPath, wikiPath, repo.TrashPath(), repo.TrashWikiPath()); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if err2 := moveRepoData(repo.TrashPath(), repo.TrashWikiPath(), repoPath, wikiPath); err2 != nil {
+				log.Error("Failed to move back trashed repository [%d]: %v", repo.ID, err2)
+			}
+		}
+	}()
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	trashedUnix := time.Now().Unix()
+	if _, err = sess.Exec("UPDATE `repository` SET lower_name = ?, trashed_unix = ?, trashed_by_id = ? WHERE id = ?",
+		trashedLowerName(repo.Name, repo.ID), trashedUnix, doer.ID, repo.ID); err != nil {
+		return fmt.Errorf("update repository: %v", err)
+	}
+	if repo.IsFork {
+		if _, err = sess.Exec("UPDATE `repository` SET num_forks = num_forks - 1 WHERE id = ?", repo.ForkID); err != nil {
+			return fmt.Errorf("decrease fork count: %v", err)
+		}
+	}
+	if _, err = sess.Exec("UPDATE `user` SET num_repos = num_repos - 1 WHERE id = ?", repo.OwnerID); err != nil {
+		return fmt.Errorf("decrease repository count: %v", err)
+	}
+	if err = sess.Commit(); err != nil {
+		return err
+	}
+
+	repo.LowerName = trashedLowerName(repo.Name, repo.ID)
+	repo.TrashedUnix = trashedUnix
+	repo.TrashedByID = doer.ID
+	repo.Trashed = time.Unix(trashedUnix, 0).Local()
+	return nil
+}
+
+// RestoreRepository moves the repository out of the trash. It returns
+// ErrRepoAlreadyExist when the owner has created another repository with the
+// same name in the meantime.
+func RestoreRepository(repo *Repository) (err error) {
+	if !repo.IsTrashed() {
+		return nil
+	}
+
+	owner, err := GetUserByID(repo.OwnerID)
+	if err != nil {
+		return fmt.Errorf("get owner: %v", err)
+	}
+
+	has, err := x.Get(&Repository{OwnerID: owner.ID, LowerName: strings.ToLower(repo.Name)})
+	if err != nil {
+		return err
+	} else if has || com.IsExist(repo.RepoPath()) {
+		return ErrRepoAlreadyExist{args: errutil.Args{"ownerID": owner.ID, "name": repo.Name}}
+	}
+
+	repoPath := repo.RepoPath()
+	wikiPath := repo.WikiPath()
+	if err = moveRepoData(repo.TrashPath(), repo.TrashWikiPath(), repoPath, wikiPath); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if err2 := moveRepoData(repoPath, wikiPath, repo.TrashPath(), repo.TrashWikiPath()); err2 != nil {
+				log.Error("Failed to move back restored repository [%d]: %v", repo.ID, err2)
+			}
+		}
+	}()
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err = sess.Exec("UPDATE `repository` SET lower_name = ?, trashed_unix = 0, trashed_by_id = 0 WHERE id = ?",
+		strings.ToLower(repo.Name), repo.ID); err != nil {
+		return fmt.Errorf("update repository: %v", err)
+	}
+	if repo.IsFork {
+		if _, err = sess.Exec("UPDATE `repository` SET num_forks = num_forks + 1 WHERE id = ?", repo.ForkID); err != nil {
+			return fmt.Errorf("increase fork count: %v", err)
+		}
+	}
+	if _, err = sess.Exec("UPDATE `user` SET num_repos = num_repos + 1 WHERE id = ?", repo.OwnerID); err != nil {
+		return fmt.Errorf("increase repository count: %v", err)
+	}
+	if err = sess.Commit(); err != nil {
+		return err
+	}
+
+	repo.LowerName = strings.ToLower(repo.Name)
+	repo.TrashedUnix = 0
+	repo.TrashedByID = 0
+	repo.Trashed = time.Time{}
+	return nil
+}
+
+// GetTrashedRepositoryByID returns the trashed repository with given ID.
+func GetTrashedRepositoryByID(id int64) (*Repository, error) {
+	repo := new(Repository)
+	has, err := x.Where("id = ? AND trashed_unix > 0", id).Get(repo)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrRepoNotExist{args: map[string]interface{}{"repoID": id}}
+	}
+	return repo, nil
+}
+
+// CountTrashedRepositories returns the number of repositories in the trash.
+func CountTrashedRepositories() int64 {
+	count, err := x.Where("trashed_unix > 0").Count(new(Repository))
+	if err != nil {
+		log.Error("CountTrashedRepositories: %v", err)
+	}
+	return count
+}
+
+// TrashedRepositories returns repositories in the trash in given page, the most
+// recently trashed first. Owners and users who trashed them are loaded.
+func TrashedRepositories(page, pageSize int) ([]*Repository, error) {
+	repos := make([]*Repository, 0, pageSize)
+	if err := x.Where("trashed_unix > 0").Limit(pageSize, (page-1)*pageSize).Desc("trashed_unix").Find(&repos); err != nil {
+		return nil, err
+	}
+
+	for _, repo := range repos {
+		if err := repo.GetOwner(); err != nil {
+			return nil, fmt.Errorf("get owner of repository [%d]: %v", repo.ID, err)
+		}
+		doer, err := GetUserByID(repo.TrashedByID)
+		if err != nil && !IsErrUserNotExist(err) {
+			return nil, fmt.Errorf("get user [%d]: %v", repo.TrashedByID, err)
+		}
+		repo.TrashedBy = doer
+	}
+	return repos, nil
+}
+
+// purgeTrashedRepositories permanently deletes trashed repositories owned by
+// given user.
+func purgeTrashedRepositories(ownerID int64) error {
+	repos := make([]*Repository, 0, 10)
+	if err := x.Where("owner_id = ? AND trashed_unix > 0", ownerID).Find(&repos); err != nil {
+		return err
+	}
+	for _, repo := range repos {
+		if err := DeleteRepository(repo.OwnerID, repo.ID); err != nil {
+			return fmt.Errorf("delete repository [%d]: %v", repo.ID, err)
+		}
+	}
+	return nil
+}
+
+// PurgeExpiredTrashedRepositories permanently deletes repositories that have
+// been in the trash for longer than the retention window.
+func PurgeExpiredTrashedRepositories() {
+	if taskStatusTable.IsRunning(_PURGE_TRASHED_REPOS) {
+		return
+	}
+	taskStatusTable.Start(_PURGE_TRASHED_REPOS)
+	defer taskStatusTable.Stop(_PURGE_TRASHED_REPOS)
+
+	log.Trace("Doing: PurgeExpiredTrashedRepositories")
+
+	repos := make([]*Repository, 0, 10)
+	olderThan := time.Now().Add(-conf.Cron.RepoTrashCleanup.OlderThan).Unix()
+	if err := x.Where("trashed_unix > 0 AND trashed_unix <= ?", olderThan).Find(&repos); err != nil {
+		log.Error("PurgeExpiredTrashedRepositories: %v", err)
+		return
+	}
+
+	for _, repo := range repos {
+		if err := DeleteRepository(repo.OwnerID, repo.ID); err != nil {
+			desc := fmt.Sprintf("Failed to purge trashed repository [%d]: %v", repo.ID, err)
+			log.Warn(desc)
+			if err = CreateRepositoryNotice(desc); err != nil {
+				log.Error("CreateRepositoryNotice: %v", err)
+			}
+		}
+	}
+}
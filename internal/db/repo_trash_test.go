@@ -0,0 +1,65 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/osutil"
+)
+
+func TestTrashedLowerName(t *testing.T) {
+	assert.Equal(t, "myrepo@trash-12", trashedLowerName("MyRepo", 12))
+	assert.NotEqual(t, trashedLowerName("repo", 1), trashedLowerName("repo", 2))
+}
+
+func TestMoveRepoData(t *testing.T) {
+	root := t.TempDir()
+	repoPath := filepath.Join(root, "alice", "repo.git")
+	wikiPath := filepath.Join(root, "alice", "repo.wiki.git")
+	trashPath := filepath.Join(root, trashDir, "1.git")
+	trashWikiPath := filepath.Join(root, trashDir, "1.wiki.git")
+
+	t.Run("without wiki", func(t *testing.T) {
+		require.NoError(t, os.MkdirAll(repoPath, os.ModePerm))
+
+		require.NoError(t, moveRepoData(repoPath, wikiPath, trashPath, trashWikiPath))
+		assert.False(t, osutil.IsExist(repoPath))
+		assert.True(t, osutil.IsDir(trashPath))
+		assert.False(t, osutil.IsExist(trashWikiPath))
+
+		require.NoError(t, moveRepoData(trashPath, trashWikiPath, repoPath, wikiPath))
+		assert.True(t, osutil.IsDir(repoPath))
+		assert.False(t, osutil.IsExist(trashPath))
+	})
+
+	t.Run("with wiki", func(t *testing.T) {
+		require.NoError(t, os.MkdirAll(wikiPath, os.ModePerm))
+
+		require.NoError(t, moveRepoData(repoPath, wikiPath, trashPath, trashWikiPath))
+		assert.True(t, osutil.IsDir(trashPath))
+		assert.True(t, osutil.IsDir(trashWikiPath))
+		assert.False(t, osutil.IsExist(wikiPath))
+	})
+
+	t.Run("moved back on failure", func(t *testing.T) {
+		require.NoError(t, os.RemoveAll(root))
+		require.NoError(t, os.MkdirAll(repoPath, os.ModePerm))
+		require.NoError(t, os.MkdirAll(wikiPath, os.ModePerm))
+		// The destination of the wiki is occupied by a non-empty directory.
+		require.NoError(t, os.MkdirAll(filepath.Join(trashWikiPath, "HEAD"), os.ModePerm))
+
+		err := moveRepoData(repoPath, wikiPath, trashPath, trashWikiPath)
+		assert.Error(t, err)
+		assert.True(t, osutil.IsDir(repoPath))
+		assert.True(t, osutil.IsDir(wikiPath))
+		assert.False(t, osutil.IsExist(trashPath))
+	})
+}
@@ -0,0 +1,244 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/errutil"
+)
+
+// ReservedNamesStore is the persistent interface for reserved names managed by
+// admins.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type ReservedNamesStore interface {
+	// Create reserves or allows the name of given kind. It returns
+	// ErrReservedNameAlreadyExist when the name of the kind already exists.
+	Create(ctx context.Context, kind ReservedNameKind, name string, allowed bool) (*ReservedName, error)
+	// DeleteByID deletes the reserved name with given ID.
+	DeleteByID(ctx context.Context, id int64) error
+	// List returns all reserved names, ordered by kind and then name.
+	List(ctx context.Context) ([]*ReservedName, error)
+}
+
+var ReservedNames ReservedNamesStore
+
+// ReservedNameKind is the kind of names that a reserved name applies to.
+type ReservedNameKind string
+
+const (
+	// ReservedNameKindUser applies to names of users and organizations.
+	ReservedNameKindUser ReservedNameKind = "user"
+	ReservedNameKindRepo ReservedNameKind = "repo"
+)
+
+// IsValid returns true if the kind is one of known kinds.
+func (k ReservedNameKind) IsValid() bool {
+	return k == ReservedNameKindUser || k == ReservedNameKindRepo
+}
+
+// ReservedName is a name or pattern that is reserved, or allowed to remove a
+// built-in or configured reserved name, by admins.
+type ReservedName struct {
+	ID   int64            `gorm:"primaryKey"`
+	Kind ReservedNameKind `gorm:"type:VARCHAR(8);uniqueIndex:reserved_name_kind_name;not null"`
+	// Name is either a name or a pattern with a placeholder "*" at the start or
+	// the end, in lower case.
+	Name string `gorm:"uniqueIndex:reserved_name_kind_name;not null"`
+	// IsAllowed indicates the name is allowed rather than reserved.
+	IsAllowed bool      `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+var _ ReservedNamesStore = (*reservedNames)(nil)
+
+type reservedNames struct {
+	*gorm.DB
+}
+
+// NewReservedNamesStore returns a persistent interface for reserved names with
+// given database connection.
+func NewReservedNamesStore(db *gorm.DB) ReservedNamesStore {
+	return &reservedNames{DB: db}
+}
+
+type ErrReservedNameAlreadyExist struct {
+	args errutil.Args
+}
+
+func IsErrReservedNameAlreadyExist(err error) bool {
+	_, ok := err.(ErrReservedNameAlreadyExist)
+	return ok
+}
+
+func (err ErrReservedNameAlreadyExist) Error() string {
+	return fmt.Sprintf("reserved name already exists: %v", err.args)
+}
+
+func (db *reservedNames) Create(ctx context.Context, kind ReservedNameKind, name string, allowed bool) (*ReservedName, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	err := db.WithContext(ctx).Where("kind = ? AND name = ?", kind, name).First(new(ReservedName)).Error
+	if err == nil {
+		return nil, ErrReservedNameAlreadyExist{args: errutil.Args{"kind": kind, "name": name}}
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, errors.Wrap(err, "check existence")
+	}
+
+	rn := &ReservedName{
+		Kind:      kind,
+		Name:      name,
+		IsAllowed: allowed,
+	}
+	return rn, db.WithContext(ctx).Create(rn).Error
+}
+
+func (db *reservedNames) DeleteByID(ctx context.Context, id int64) error {
+	return db.WithContext(ctx).Where("id = ?", id).Delete(new(ReservedName)).Error
+}
+
+func (db *reservedNames) List(ctx context.Context) ([]*ReservedName, error) {
+	var names []*ReservedName
+	return names, db.WithContext(ctx).Order("kind ASC, name ASC").Find(&names).Error
+}
+
+// IsValidReservedName returns true if the name can be reserved, i.e. a
+// non-empty name, or a pattern with a single placeholder "*" at either the
+// start or the end.
+func IsValidReservedName(name string) bool {
+	name = strings.TrimSpace(name)
+	switch strings.Count(name, "*") {
+	case 0:
+		return name != "" && !strings.ContainsAny(name, ", ")
+	case 1:
+		return len(name) > 1 &&
+			(strings.HasPrefix(name, "*") || strings.HasSuffix(name, "*")) &&
+			!strings.ContainsAny(name, ", ")
+	}
+	return false
+}
+
+// Reserved names and patterns that cannot be allowed because routes or files
+// depend on them.
+var (
+	protectedReservedUsernames = []string{"-", ".", "..", "api", "assets", "css", "img", "js", "less", "plugins", "avatar", "user", "org", "admin", "explore", "snippets", "install", "*.keys", "*.gpg"}
+	protectedReservedRepoNames = []string{".", "..", "*.git", "*.wiki"}
+)
+
+// reservedNamesCache is the cache of reserved names managed by admins, see
+// LoadReservedNames.
+var reservedNamesCache struct {
+	sync.RWMutex
+	list []*ReservedName
+}
+
+// LoadReservedNames loads reserved names managed by admins into the cache, and
+// must be called after they are changed.
+func LoadReservedNames(ctx context.Context) error {
+	list, err := ReservedNames.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	reservedNamesCache.Lock()
+	reservedNamesCache.list = list
+	reservedNamesCache.Unlock()
+	return nil
+}
+
+// mergeReservedNames returns reserved names and patterns that start with
+// built-in ones, then changed by additions and removals in configuration, and
+// then by admins. Protected names are always reserved. Invalid names are
+// ignored.
+func mergeReservedNames(builtin, protected, added, allowed []string, entries []*ReservedName) (names, patterns []string) {
+	var list []string
+	index := make(map[string]int)
+	reserve := func(name string) {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !IsValidReservedName(name) {
+			return
+		} else if _, ok := index[name]; ok {
+			return
+		}
+		index[name] = len(list)
+		list = append(list, name)
+	}
+	allow := func(name string) {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if i, ok := index[name]; ok {
+			list[i] = ""
+			delete(index, name)
+		}
+	}
+
+	for _, name := range builtin {
+		reserve(name)
+	}
+	for _, name := range added {
+		reserve(name)
+	}
+	for _, name := range allowed {
+		allow(name)
+	}
+	for _, e := range entries {
+		if e.IsAllowed {
+			allow(e.Name)
+		} else {
+			reserve(e.Name)
+		}
+	}
+	for _, name := range protected {
+		reserve(name)
+	}
+
+	for _, name := range list {
+		if name == "" {
+			continue
+		} else if strings.Contains(name, "*") {
+			patterns = append(patterns, name)
+		} else {
+			names = append(names, name)
+		}
+	}
+	return names, patterns
+}
+
+// reservedNamesOf returns effective reserved names and patterns of the kind.
+func reservedNamesOf(kind ReservedNameKind) (names, patterns []string) {
+	reservedNamesCache.RLock()
+	var entries []*ReservedName
+	for _, e := range reservedNamesCache.list {
+		if e.Kind == kind {
+			entries = append(entries, e)
+		}
+	}
+	reservedNamesCache.RUnlock()
+
+	switch kind {
+	case ReservedNameKindUser:
+		builtin := append(append([]string{}, reservedUsernames...), reservedUserPatterns...)
+		return mergeReservedNames(builtin, protectedReservedUsernames,
+			conf.ReservedNames.Usernames, conf.ReservedNames.AllowedUsernames, entries)
+	case ReservedNameKindRepo:
+		builtin := append(append([]string{}, reservedRepoNames...), reservedRepoPatterns...)
+		return mergeReservedNames(builtin, protectedReservedRepoNames,
+			conf.ReservedNames.RepoNames, conf.ReservedNames.AllowedRepoNames, entries)
+	}
+	return nil, nil
+}
+
+// ListReservedNames returns effective reserved names and patterns of the kind.
+func ListReservedNames(kind ReservedNameKind) []string {
+	names, patterns := reservedNamesOf(kind)
+	return append(names, patterns...)
+}
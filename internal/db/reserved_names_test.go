@@ -0,0 +1,139 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/errutil"
+)
+
+func TestReservedNames(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(ReservedName)}
+	db := &reservedNames{
+		DB: dbtest.NewDB(t, "reservedNames", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *reservedNames)
+	}{
+		{"Create", reservedNamesCreate},
+		{"DeleteByID", reservedNamesDeleteByID},
+		{"List", reservedNamesList},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func reservedNamesCreate(t *testing.T, db *reservedNames) {
+	ctx := context.Background()
+
+	rn, err := db.Create(ctx, ReservedNameKindUser, " ACME ", false)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", rn.Name)
+	assert.False(t, rn.IsAllowed)
+
+	// The same name of another kind is a different one
+	_, err = db.Create(ctx, ReservedNameKindRepo, "acme", true)
+	require.NoError(t, err)
+
+	_, err = db.Create(ctx, ReservedNameKindUser, "acme", true)
+	wantErr := ErrReservedNameAlreadyExist{args: errutil.Args{"kind": ReservedNameKindUser, "name": "acme"}}
+	assert.Equal(t, wantErr, err)
+}
+
+func reservedNamesDeleteByID(t *testing.T, db *reservedNames) {
+	ctx := context.Background()
+
+	rn, err := db.Create(ctx, ReservedNameKindUser, "acme", false)
+	require.NoError(t, err)
+
+	err = db.DeleteByID(ctx, rn.ID)
+	require.NoError(t, err)
+
+	list, err := db.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func reservedNamesList(t *testing.T, db *reservedNames) {
+	ctx := context.Background()
+
+	_, err := db.Create(ctx, ReservedNameKindUser, "help", true)
+	require.NoError(t, err)
+	_, err = db.Create(ctx, ReservedNameKindRepo, "*-internal", false)
+	require.NoError(t, err)
+	_, err = db.Create(ctx, ReservedNameKindUser, "acme", false)
+	require.NoError(t, err)
+
+	list, err := db.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+
+	got := make([]string, len(list))
+	for i := range list {
+		got[i] = string(list[i].Kind) + ":" + list[i].Name
+	}
+	assert.Equal(t, []string{"repo:*-internal", "user:acme", "user:help"}, got)
+}
+
+func TestIsValidReservedName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "acme", want: true},
+		{name: "*-bot", want: true},
+		{name: "acme-*", want: true},
+		{name: "", want: false},
+		{name: "*", want: false},
+		{name: "*acme*", want: false},
+		{name: "ac*me", want: false},
+		{name: "acme inc", want: false},
+		{name: "a,b", want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, IsValidReservedName(test.name))
+		})
+	}
+}
+
+func TestMergeReservedNames(t *testing.T) {
+	names, patterns := mergeReservedNames(
+		[]string{"help", "new", "explore", "*.keys"},
+		[]string{"explore", "*.keys"},
+		[]string{"Acme", "*-bot", "help", "*"},
+		[]string{"new", "explore"},
+		[]*ReservedName{
+			{Name: "help", IsAllowed: true},
+			{Name: "*.keys", IsAllowed: true},
+			{Name: "new"},
+			{Name: "widget"},
+		},
+	)
+	assert.Equal(t, []string{"acme", "new", "widget", "explore"}, names)
+	assert.Equal(t, []string{"*-bot", "*.keys"}, patterns)
+}
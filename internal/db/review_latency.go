@@ -0,0 +1,233 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ReviewLatencyWindow is a rolling window over which review latency of pull
+// requests is computed.
+type ReviewLatencyWindow struct {
+	Name     string
+	Duration time.Duration
+}
+
+// ReviewLatencyWindows is the list of rolling windows of review latency, from
+// the shortest to the longest.
+var ReviewLatencyWindows = []ReviewLatencyWindow{
+	{Name: "7d", Duration: 7 * 24 * time.Hour},
+	{Name: "30d", Duration: 30 * 24 * time.Hour},
+	{Name: "90d", Duration: 90 * 24 * time.Hour},
+}
+
+// ReviewLatency is the review latency of pull requests created in a rolling
+// window. The first review of a pull request is the first comment by a user
+// other than its poster. Durations are zero when there is no pull request
+// reviewed or merged.
+type ReviewLatency struct {
+	Window       string
+	PullRequests int64
+	Reviewed     int64
+	Merged       int64
+
+	MedianTimeToFirstReview time.Duration
+	P90TimeToFirstReview    time.Duration
+	MedianTimeToMerge       time.Duration
+	P90TimeToMerge          time.Duration
+}
+
+// reviewLatencySample is a pull request with times of its first review and
+// merge, which are zero if not happened.
+type reviewLatencySample struct {
+	RepoID          int64 `xorm:"repo_id"`
+	CreatedUnix     int64 `xorm:"created_unix"`
+	FirstReviewUnix int64 `xorm:"first_review_unix"`
+	HasMerged       bool  `xorm:"has_merged"`
+	MergedUnix      int64 `xorm:"merged_unix"`
+}
+
+// durationPercentile returns the p-th percentile of sorted durations by the
+// nearest-rank method.
+func durationPercentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p*float64(len(sorted))+0.999999) - 1
+	if rank < 0 {
+		rank = 0
+	} else if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// buildReviewLatencies computes review latency of the samples for each of
+// ReviewLatencyWindows ending at now.
+func buildReviewLatencies(samples []*reviewLatencySample, now time.Time) []*ReviewLatency {
+	latencies := make([]*ReviewLatency, len(ReviewLatencyWindows))
+	for i, w := range ReviewLatencyWindows {
+		since := now.Add(-w.Duration).Unix()
+		latency := &ReviewLatency{Window: w.Name}
+
+		var reviews, merges []time.Duration
+		for _, s := range samples {
+			if s.CreatedUnix < since {
+				continue
+			}
+			latency.PullRequests++
+
+			if s.FirstReviewUnix > 0 {
+				latency.Reviewed++
+				reviews = append(reviews, time.Duration(s.FirstReviewUnix-s.CreatedUnix)*time.Second)
+			}
+			if s.HasMerged {
+				latency.Merged++
+				merges = append(merges, time.Duration(s.MergedUnix-s.CreatedUnix)*time.Second)
+			}
+		}
+
+		sort.Slice(reviews, func(i, j int) bool { return reviews[i] < reviews[j] })
+		sort.Slice(merges, func(i, j int) bool { return merges[i] < merges[j] })
+		latency.MedianTimeToFirstReview = durationPercentile(reviews, 0.5)
+		latency.P90TimeToFirstReview = durationPercentile(reviews, 0.9)
+		latency.MedianTimeToMerge = durationPercentile(merges, 0.5)
+		latency.P90TimeToMerge = durationPercentile(merges, 0.9)
+		latencies[i] = latency
+	}
+	return latencies
+}
+
+// listReviewLatencySamples returns pull requests created since the longest of
+// ReviewLatencyWindows, optionally limited to the repository or the owner of
+// repositories when the ID is positive.
+func listReviewLatencySamples(repoID, ownerID int64, now time.Time) ([]*reviewLatencySample, error) {
+	since := now.Add(-ReviewLatencyWindows[len(ReviewLatencyWindows)-1].Duration).Unix()
+	sess := x.Table("issue").
+		Select(fmt.Sprintf(`issue.repo_id, issue.created_unix, pull_request.has_merged, pull_request.merged_unix,
+			COALESCE((SELECT MIN(comment.created_unix) FROM comment
+				WHERE comment.issue_id = issue.id AND comment.poster_id != issue.poster_id AND comment.type = %d), 0) AS first_review_unix`,
+			COMMENT_TYPE_COMMENT)).
+		Join("INNER", "pull_request", "pull_request.issue_id = issue.id").
+		Where("issue.is_pull = ?", true).
+		And("issue.created_unix >= ?", since)
+	if repoID > 0 {
+		sess.And("issue.repo_id = ?", repoID)
+	}
+	if ownerID > 0 {
+		sess.Join("INNER", "repository", "repository.id = issue.repo_id").And("repository.owner_id = ?", ownerID)
+	}
+
+	samples := make([]*reviewLatencySample, 0, 10)
+	if err := sess.Find(&samples); err != nil {
+		return nil, fmt.Errorf("list pull requests: %v", err)
+	}
+	return samples, nil
+}
+
+// GetRepoReviewLatencies returns review latency of pull requests of the
+// repository for each of ReviewLatencyWindows ending at now.
+func GetRepoReviewLatencies(repoID int64, now time.Time) ([]*ReviewLatency, error) {
+	samples, err := listReviewLatencySamples(repoID, 0, now)
+	if err != nil {
+		return nil, err
+	}
+	return buildReviewLatencies(samples, now), nil
+}
+
+// GetOwnerReviewLatencies returns review latency of pull requests of all
+// repositories of the owner (e.g. an organization) for each of
+// ReviewLatencyWindows ending at now.
+func GetOwnerReviewLatencies(ownerID int64, now time.Time) ([]*ReviewLatency, error) {
+	samples, err := listReviewLatencySamples(0, ownerID, now)
+	if err != nil {
+		return nil, err
+	}
+	return buildReviewLatencies(samples, now), nil
+}
+
+// RepoReviewLatencies is review latency of a repository.
+type RepoReviewLatencies struct {
+	RepoID    int64
+	OwnerID   int64
+	FullName  string
+	Latencies []*ReviewLatency
+}
+
+// OwnerReviewLatencies is review latency of all repositories of an owner.
+type OwnerReviewLatencies struct {
+	OwnerID   int64
+	OwnerName string
+	Latencies []*ReviewLatency
+}
+
+// GetAllReviewLatencies returns review latency of all repositories and owners
+// that have pull requests created in the longest of ReviewLatencyWindows.
+func GetAllReviewLatencies(now time.Time) ([]*RepoReviewLatencies, []*OwnerReviewLatencies, error) {
+	samples, err := listReviewLatencySamples(0, 0, now)
+	if err != nil {
+		return nil, nil, err
+	} else if len(samples) == 0 {
+		return nil, nil, nil
+	}
+
+	byRepo := make(map[int64][]*reviewLatencySample)
+	for _, s := range samples {
+		byRepo[s.RepoID] = append(byRepo[s.RepoID], s)
+	}
+	repoIDs := make([]int64, 0, len(byRepo))
+	for id := range byRepo {
+		repoIDs = append(repoIDs, id)
+	}
+	repos := make([]*Repository, 0, len(repoIDs))
+	if err = x.Cols("id", "owner_id", "name").In("id", repoIDs).Find(&repos); err != nil {
+		return nil, nil, fmt.Errorf("list repositories: %v", err)
+	}
+
+	byOwner := make(map[int64][]*reviewLatencySample)
+	ownerIDs := make([]int64, 0, len(repos))
+	for _, repo := range repos {
+		if _, ok := byOwner[repo.OwnerID]; !ok {
+			ownerIDs = append(ownerIDs, repo.OwnerID)
+		}
+		byOwner[repo.OwnerID] = append(byOwner[repo.OwnerID], byRepo[repo.ID]...)
+	}
+	owners := make([]*User, 0, len(ownerIDs))
+	if err = x.Cols("id", "name").In("id", ownerIDs).Find(&owners); err != nil {
+		return nil, nil, fmt.Errorf("list owners: %v", err)
+	}
+	ownerNames := make(map[int64]string, len(owners))
+	for _, u := range owners {
+		ownerNames[u.ID] = u.Name
+	}
+
+	repoLatencies := make([]*RepoReviewLatencies, 0, len(repos))
+	for _, repo := range repos {
+		ownerName, ok := ownerNames[repo.OwnerID]
+		if !ok {
+			continue
+		}
+		repoLatencies = append(repoLatencies, &RepoReviewLatencies{
+			RepoID:    repo.ID,
+			OwnerID:   repo.OwnerID,
+			FullName:  ownerName + "/" + repo.Name,
+			Latencies: buildReviewLatencies(byRepo[repo.ID], now),
+		})
+	}
+	sort.Slice(repoLatencies, func(i, j int) bool { return repoLatencies[i].FullName < repoLatencies[j].FullName })
+
+	ownerLatencies := make([]*OwnerReviewLatencies, 0, len(owners))
+	for _, u := range owners {
+		ownerLatencies = append(ownerLatencies, &OwnerReviewLatencies{
+			OwnerID:   u.ID,
+			OwnerName: u.Name,
+			Latencies: buildReviewLatencies(byOwner[u.ID], now),
+		})
+	}
+	sort.Slice(ownerLatencies, func(i, j int) bool { return ownerLatencies[i].OwnerName < ownerLatencies[j].OwnerName })
+	return repoLatencies, ownerLatencies, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDurationPercentile(t *testing.T) {
+	durations := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	tests := []struct {
+		name      string
+		durations []time.Duration
+		p         float64
+		want      time.Duration
+	}{
+		{name: "empty", durations: nil, p: 0.5, want: 0},
+		{name: "single", durations: []time.Duration{7}, p: 0.9, want: 7},
+		{name: "median", durations: durations, p: 0.5, want: 5},
+		{name: "p90", durations: durations, p: 0.9, want: 9},
+		{name: "max", durations: durations, p: 1, want: 10},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, durationPercentile(test.durations, test.p))
+		})
+	}
+}
+
+func TestBuildReviewLatencies(t *testing.T) {
+	now := time.Unix(100*24*3600, 0)
+	daysAgo := func(days float64) int64 {
+		return now.Add(-time.Duration(days * float64(24*time.Hour))).Unix()
+	}
+
+	samples := []*reviewLatencySample{
+		// Created 2 days ago, reviewed after 1 hour and merged after 1 day.
+		{CreatedUnix: daysAgo(2), FirstReviewUnix: daysAgo(2) + 3600, HasMerged: true, MergedUnix: daysAgo(1)},
+		// Created 3 days ago, reviewed after 3 hours and not merged.
+		{CreatedUnix: daysAgo(3), FirstReviewUnix: daysAgo(3) + 3*3600},
+		// Created 20 days ago, never reviewed and merged after 2 days.
+		{CreatedUnix: daysAgo(20), HasMerged: true, MergedUnix: daysAgo(18)},
+		// Created 60 days ago, reviewed after 10 hours.
+		{CreatedUnix: daysAgo(60), FirstReviewUnix: daysAgo(60) + 10*3600},
+	}
+
+	got := buildReviewLatencies(samples, now)
+	want := []*ReviewLatency{
+		{
+			Window:                  "7d",
+			PullRequests:            2,
+			Reviewed:                2,
+			Merged:                  1,
+			MedianTimeToFirstReview: time.Hour,
+			P90TimeToFirstReview:    3 * time.Hour,
+			MedianTimeToMerge:       24 * time.Hour,
+			P90TimeToMerge:          24 * time.Hour,
+		},
+		{
+			Window:                  "30d",
+			PullRequests:            3,
+			Reviewed:                2,
+			Merged:                  2,
+			MedianTimeToFirstReview: time.Hour,
+			P90TimeToFirstReview:    3 * time.Hour,
+			MedianTimeToMerge:       24 * time.Hour,
+			P90TimeToMerge:          48 * time.Hour,
+		},
+		{
+			Window:                  "90d",
+			PullRequests:            4,
+			Reviewed:                3,
+			Merged:                  2,
+			MedianTimeToFirstReview: 3 * time.Hour,
+			P90TimeToFirstReview:    10 * time.Hour,
+			MedianTimeToMerge:       24 * time.Hour,
+			P90TimeToMerge:          48 * time.Hour,
+		},
+	}
+	assert.Equal(t, want, got)
+
+	t.Run("no samples", func(t *testing.T) {
+		got := buildReviewLatencies(nil, now)
+		assert.Len(t, got, len(ReviewLatencyWindows))
+		for _, latency := range got {
+			assert.Zero(t, latency.PullRequests)
+			assert.Zero(t, latency.MedianTimeToFirstReview)
+		}
+	})
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	gouuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/cryptoutil"
+	"gogs.io/gogs/internal/errutil"
+)
+
+// RunnersStore is the persistent interface for CI runners of organizations.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type RunnersStore interface {
+	// Create registers a new runner for the organization with a random
+	// credential, which is only available via the Token field of the returned
+	// runner. It returns ErrRunnerAlreadyExist when a runner with the same name
+	// already exists for the organization.
+	Create(ctx context.Context, orgID, createdByID int64, name string) (*Runner, error)
+	// DeleteByID deletes the runner with given ID of the organization, along with
+	// all job tokens issued to it.
+	DeleteByID(ctx context.Context, orgID, id int64) error
+	// GetByID returns the runner with given ID. It returns ErrRunnerNotExist
+	// when not found.
+	GetByID(ctx context.Context, id int64) (*Runner, error)
+	// GetByToken returns the runner with given credential. It returns
+	// ErrRunnerNotExist when not found.
+	GetByToken(ctx context.Context, token string) (*Runner, error)
+	// List returns all runners of the organization, ordered by name.
+	List(ctx context.Context, orgID int64) ([]*Runner, error)
+}
+
+var Runners RunnersStore
+
+// Runner is a CI runner registered by an organization. Runners authenticate
+// with their credentials to exchange for short-lived job tokens to access
+// repositories of the organization.
+type Runner struct {
+	ID    int64  `gorm:"primaryKey"`
+	OrgID int64  `gorm:"uniqueIndex:runner_org_name_unique;not null"`
+	Name  string `gorm:"uniqueIndex:runner_org_name_unique;not null"`
+	// CreatedByID is the ID of the user who registered the runner. Job tokens
+	// never have more access than the user, and pushes with job tokens are made
+	// on behalf of the user.
+	CreatedByID int64     `gorm:"not null"`
+	SHA256      string    `gorm:"type:VARCHAR(64);unique;not null"`
+	CreatedAt   time.Time `gorm:"not null"`
+
+	// Token is the raw credential, which is only set right after creation.
+	Token string `gorm:"-" json:"-"`
+}
+
+var _ RunnersStore = (*runners)(nil)
+
+type runners struct {
+	*gorm.DB
+}
+
+// NewRunnersStore returns a persistent interface for CI runners of
+// organizations with given database connection.
+func NewRunnersStore(db *gorm.DB) RunnersStore {
+	return &runners{DB: db}
+}
+
+type ErrRunnerAlreadyExist struct {
+	args errutil.Args
+}
+
+func IsErrRunnerAlreadyExist(err error) bool {
+	_, ok := err.(ErrRunnerAlreadyExist)
+	return ok
+}
+
+func (err ErrRunnerAlreadyExist) Error() string {
+	return fmt.Sprintf("runner already exists: %v", err.args)
+}
+
+func (db *runners) Create(ctx context.Context, orgID, createdByID int64, name string) (*Runner, error) {
+	err := db.WithContext(ctx).Where("org_id = ? AND name = ?", orgID, name).First(new(Runner)).Error
+	if err == nil {
+		return nil, ErrRunnerAlreadyExist{args: errutil.Args{"orgID": orgID, "name": name}}
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, errors.Wrap(err, "check existence")
+	}
+
+	token := cryptoutil.SHA1(gouuid.NewV4().String())
+	r := &Runner{
+		OrgID:       orgID,
+		Name:        name,
+		CreatedByID: createdByID,
+		SHA256:      cryptoutil.SHA256(token),
+	}
+	if err = db.WithContext(ctx).Create(r).Error; err != nil {
+		return nil, err
+	}
+
+	r.Token = token
+	return r, nil
+}
+
+func (db *runners) DeleteByID(ctx context.Context, orgID, id int64) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND org_id = ?", id, orgID).Delete(new(Runner))
+		if result.Error != nil {
+			return errors.Wrap(result.Error, "delete runner")
+		} else if result.RowsAffected == 0 {
+			return nil
+		}
+
+		err := tx.Where("runner_id = ?", id).Delete(new(JobToken)).Error
+		if err != nil {
+			return errors.Wrap(err, "delete job tokens")
+		}
+		return nil
+	})
+}
+
+var _ errutil.NotFound = (*ErrRunnerNotExist)(nil)
+
+type ErrRunnerNotExist struct {
+	args errutil.Args
+}
+
+func IsErrRunnerNotExist(err error) bool {
+	_, ok := err.(ErrRunnerNotExist)
+	return ok
+}
+
+func (err ErrRunnerNotExist) Error() string {
+	return fmt.Sprintf("runner does not exist: %v", err.args)
+}
+
+func (ErrRunnerNotExist) NotFound() bool {
+	return true
+}
+
+func (db *runners) GetByID(ctx context.Context, id int64) (*Runner, error) {
+	r := new(Runner)
+	err := db.WithContext(ctx).Where("id = ?", id).First(r).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrRunnerNotExist{args: errutil.Args{"id": id}}
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+func (db *runners) GetByToken(ctx context.Context, token string) (*Runner, error) {
+	// No need to waste a query for an empty token.
+	if token == "" {
+		return nil, ErrRunnerNotExist{args: errutil.Args{}}
+	}
+
+	r := new(Runner)
+	err := db.WithContext(ctx).Where("sha256 = ?", cryptoutil.SHA256(token)).First(r).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrRunnerNotExist{args: errutil.Args{}}
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+func (db *runners) List(ctx context.Context, orgID int64) ([]*Runner, error) {
+	var rs []*Runner
+	return rs, db.WithContext(ctx).Where("org_id = ?", orgID).Order("name ASC").Find(&rs).Error
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+func TestRunners(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(Runner), new(JobToken)}
+	db := &runners{
+		DB: dbtest.NewDB(t, "runners", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *runners)
+	}{
+		{"Create", runnersCreate},
+		{"DeleteByID", runnersDeleteByID},
+		{"GetByToken", runnersGetByToken},
+		{"List", runnersList},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func runnersCreate(t *testing.T, db *runners) {
+	ctx := context.Background()
+
+	r, err := db.Create(ctx, 1, 2, "build-01")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), r.OrgID)
+	assert.Equal(t, int64(2), r.CreatedByID)
+	assert.Len(t, r.Token, 40)
+	assert.NotEqual(t, r.Token, r.SHA256)
+
+	_, err = db.Create(ctx, 1, 2, "build-01")
+	assert.True(t, IsErrRunnerAlreadyExist(err))
+
+	// Same name in a different organization is fine.
+	_, err = db.Create(ctx, 2, 2, "build-01")
+	require.NoError(t, err)
+}
+
+func runnersDeleteByID(t *testing.T, db *runners) {
+	ctx := context.Background()
+
+	r, err := db.Create(ctx, 1, 2, "build-01")
+	require.NoError(t, err)
+	jobTokens := NewJobTokensStore(db.DB)
+	_, err = jobTokens.Create(ctx, r.ID, 1, AccessModeRead, time.Hour)
+	require.NoError(t, err)
+
+	// Runner of another organization cannot be deleted.
+	err = db.DeleteByID(ctx, 2, r.ID)
+	require.NoError(t, err)
+	_, err = db.GetByID(ctx, r.ID)
+	require.NoError(t, err)
+
+	err = db.DeleteByID(ctx, 1, r.ID)
+	require.NoError(t, err)
+	_, err = db.GetByID(ctx, r.ID)
+	assert.True(t, IsErrRunnerNotExist(err))
+
+	var count int64
+	err = db.Model(new(JobToken)).Where("runner_id = ?", r.ID).Count(&count).Error
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func runnersGetByToken(t *testing.T, db *runners) {
+	ctx := context.Background()
+
+	_, err := db.GetByToken(ctx, "")
+	assert.True(t, IsErrRunnerNotExist(err))
+	_, err = db.GetByToken(ctx, "bad_token")
+	assert.True(t, IsErrRunnerNotExist(err))
+
+	r, err := db.Create(ctx, 1, 2, "build-01")
+	require.NoError(t, err)
+
+	got, err := db.GetByToken(ctx, r.Token)
+	require.NoError(t, err)
+	assert.Equal(t, r.ID, got.ID)
+	assert.Empty(t, got.Token)
+}
+
+func runnersList(t *testing.T, db *runners) {
+	ctx := context.Background()
+
+	for _, name := range []string{"build-02", "build-01"} {
+		_, err := db.Create(ctx, 1, 2, name)
+		require.NoError(t, err)
+	}
+	_, err := db.Create(ctx, 2, 2, "build-03")
+	require.NoError(t, err)
+
+	rs, err := db.List(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, rs, 2)
+	assert.Equal(t, "build-01", rs[0].Name)
+	assert.Equal(t, "build-02", rs[1].Name)
+}
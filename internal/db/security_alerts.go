@@ -0,0 +1,297 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/email"
+	"gogs.io/gogs/internal/manifest"
+	"gogs.io/gogs/internal/osv"
+)
+
+// SecurityAlertsStore is the persistent interface for security alerts of
+// repositories.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type SecurityAlertsStore interface {
+	// CountByRepo returns the number of security alerts of the repository.
+	CountByRepo(ctx context.Context, repoID int64) (int64, error)
+	// ListByRepo returns all security alerts of the repository, ordered by
+	// package name and vulnerability ID.
+	ListByRepo(ctx context.Context, repoID int64) ([]*SecurityAlert, error)
+	// ListRepoIDs returns IDs of all repositories that have security alerts, in
+	// ascending order.
+	ListRepoIDs(ctx context.Context) ([]int64, error)
+	// Sync replaces security alerts of the repository with given ones. Existing
+	// alerts that are still present are kept as-is, and it returns alerts that
+	// are newly created.
+	Sync(ctx context.Context, repoID int64, alerts []*SecurityAlert) ([]*SecurityAlert, error)
+}
+
+var SecurityAlerts SecurityAlertsStore
+
+// SecurityAlert is a known vulnerability that affects a dependency of a
+// repository.
+type SecurityAlert struct {
+	ID              int64              `gorm:"primaryKey"`
+	RepoID          int64              `gorm:"index;not null"`
+	VulnerabilityID string             `gorm:"not null"`
+	Summary         string             `gorm:"type:TEXT"`
+	ManifestPath    string             `gorm:"not null"`
+	Ecosystem       manifest.Ecosystem `gorm:"type:VARCHAR(16);not null"`
+	PackageName     string             `gorm:"not null"`
+	Version         string             `gorm:"not null"`
+	// FixedVersion is the lowest version that fixes the vulnerability, it is
+	// empty when no fix is known.
+	FixedVersion string
+	CreatedAt    time.Time `gorm:"not null"`
+}
+
+// key returns the string that identifies the alert within a repository.
+func (a *SecurityAlert) key() string {
+	return a.VulnerabilityID + "\x00" + a.ManifestPath + "\x00" + a.PackageName + "\x00" + a.Version
+}
+
+var _ SecurityAlertsStore = (*securityAlerts)(nil)
+
+type securityAlerts struct {
+	*gorm.DB
+}
+
+// NewSecurityAlertsStore returns a persistent interface for security alerts of
+// repositories with given database connection.
+func NewSecurityAlertsStore(db *gorm.DB) SecurityAlertsStore {
+	return &securityAlerts{DB: db}
+}
+
+func (db *securityAlerts) CountByRepo(ctx context.Context, repoID int64) (int64, error) {
+	var count int64
+	return count, db.WithContext(ctx).Model(new(SecurityAlert)).Where("repo_id = ?", repoID).Count(&count).Error
+}
+
+func (db *securityAlerts) ListByRepo(ctx context.Context, repoID int64) ([]*SecurityAlert, error) {
+	var alerts []*SecurityAlert
+	return alerts, db.WithContext(ctx).
+		Where("repo_id = ?", repoID).
+		Order("package_name ASC").
+		Order("vulnerability_id ASC").
+		Order("manifest_path ASC").
+		Find(&alerts).
+		Error
+}
+
+func (db *securityAlerts) ListRepoIDs(ctx context.Context) ([]int64, error) {
+	var repoIDs []int64
+	return repoIDs, db.WithContext(ctx).
+		Model(new(SecurityAlert)).
+		Distinct("repo_id").
+		Order("repo_id ASC").
+		Pluck("repo_id", &repoIDs).
+		Error
+}
+
+func (db *securityAlerts) Sync(ctx context.Context, repoID int64, alerts []*SecurityAlert) ([]*SecurityAlert, error) {
+	var created []*SecurityAlert
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing []*SecurityAlert
+		err := tx.Where("repo_id = ?", repoID).Find(&existing).Error
+		if err != nil {
+			return errors.Wrap(err, "list existing alerts")
+		}
+
+		wanted := make(map[string]bool, len(alerts))
+		for _, a := range alerts {
+			wanted[a.key()] = true
+		}
+		found := make(map[string]bool, len(existing))
+		var staleIDs []int64
+		for _, a := range existing {
+			if wanted[a.key()] {
+				found[a.key()] = true
+			} else {
+				staleIDs = append(staleIDs, a.ID)
+			}
+		}
+		if len(staleIDs) > 0 {
+			err = tx.Where("id IN ?", staleIDs).Delete(new(SecurityAlert)).Error
+			if err != nil {
+				return errors.Wrap(err, "delete stale alerts")
+			}
+		}
+
+		for _, a := range alerts {
+			if found[a.key()] {
+				continue
+			}
+			found[a.key()] = true
+
+			a.ID = 0
+			a.RepoID = repoID
+			created = append(created, a)
+		}
+		if len(created) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(created, 100).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// matchSecurityAlerts returns security alerts for dependencies with pinned
+// versions that are affected by known vulnerabilities.
+func matchSecurityAlerts(vulndb *osv.Database, deps []*RepoDependency) []*SecurityAlert {
+	var alerts []*SecurityAlert
+	for _, dep := range deps {
+		version := manifest.PinnedVersion(dep.Ecosystem, dep.Requirement)
+		if version == "" {
+			continue
+		}
+
+		for _, m := range vulndb.Query(dep.Ecosystem, dep.Name, version) {
+			alerts = append(alerts, &SecurityAlert{
+				VulnerabilityID: m.Vulnerability.ID,
+				Summary:         m.Vulnerability.Summary,
+				ManifestPath:    dep.ManifestPath,
+				Ecosystem:       dep.Ecosystem,
+				PackageName:     dep.Name,
+				Version:         version,
+				FixedVersion:    m.FixedVersion,
+			})
+		}
+	}
+	return alerts
+}
+
+// CheckVulnerabilities matches dependencies of all repositories against the
+// local copy of the OSV database, and sends notifications to administrators
+// of repositories and site admins about newly raised security alerts.
+func CheckVulnerabilities() {
+	if taskStatusTable.IsRunning(_CHECK_VULNERABILITIES) {
+		return
+	}
+	taskStatusTable.Start(_CHECK_VULNERABILITIES)
+	defer taskStatusTable.Stop(_CHECK_VULNERABILITIES)
+
+	log.Trace("Doing: CheckVulnerabilities")
+
+	if err := checkVulnerabilities(context.Background(), conf.Cron.CheckVulnerabilities.OSVPath); err != nil {
+		log.Error("Failed to check vulnerabilities: %v", err)
+	}
+}
+
+func checkVulnerabilities(ctx context.Context, osvPath string) error {
+	vulndb, err := osv.Load(osvPath)
+	if err != nil {
+		return errors.Wrap(err, "load OSV database")
+	}
+	log.Trace("Loaded %d vulnerabilities from %q", vulndb.Len(), osvPath)
+
+	// Repositories that no longer have dependencies may still have alerts to be
+	// cleared.
+	repoIDs, err := RepoDependencies.ListRepoIDs(ctx)
+	if err != nil {
+		return errors.Wrap(err, "list repositories with dependencies")
+	}
+	alertRepoIDs, err := SecurityAlerts.ListRepoIDs(ctx)
+	if err != nil {
+		return errors.Wrap(err, "list repositories with security alerts")
+	}
+	seen := make(map[int64]bool, len(repoIDs))
+	for _, id := range repoIDs {
+		seen[id] = true
+	}
+	for _, id := range alertRepoIDs {
+		if !seen[id] {
+			repoIDs = append(repoIDs, id)
+		}
+	}
+
+	for _, repoID := range repoIDs {
+		repo, err := GetRepositoryByID(repoID)
+		if err != nil {
+			if IsErrRepoNotExist(err) {
+				continue
+			}
+			return errors.Wrapf(err, "get repository by ID %d", repoID)
+		}
+
+		// Alerts are cleared for repositories that vulnerability alerts are
+		// disabled by the security policy of the organization.
+		var alerts []*SecurityAlert
+		enforced, err := IsSecurityFeatureEnforced(ctx, repo, SecurityFeatureVulnerabilityAlerts)
+		if err != nil {
+			return errors.Wrapf(err, "check security policy of repository %d", repoID)
+		} else if enforced {
+			deps, err := RepoDependencies.ListByRepo(ctx, repoID)
+			if err != nil {
+				return errors.Wrapf(err, "list dependencies of repository %d", repoID)
+			}
+			alerts = matchSecurityAlerts(vulndb, deps)
+		}
+
+		created, err := SecurityAlerts.Sync(ctx, repoID, alerts)
+		if err != nil {
+			return errors.Wrapf(err, "sync security alerts of repository %d", repoID)
+		} else if len(created) == 0 {
+			continue
+		}
+
+		if err = mailSecurityAlerts(repo, created); err != nil {
+			log.Error("Failed to send security alert notifications [repo_id: %d]: %v", repoID, err)
+		}
+	}
+	return nil
+}
+
+// mailSecurityAlerts sends notifications about new security alerts to users
+// with admin access to the repository and site admins.
+func mailSecurityAlerts(repo *Repository, alerts []*SecurityAlert) error {
+	if !conf.Email.Enabled {
+		return nil
+	}
+
+	users, err := repo.getUsersWithAccesMode(x, AccessModeAdmin)
+	if err != nil {
+		return errors.Wrap(err, "get users with admin access")
+	}
+	admins := make([]*User, 0, 5)
+	if err = x.Where("is_admin = ?", true).And("is_active = ?", true).Find(&admins); err != nil {
+		return errors.Wrap(err, "list site admins")
+	}
+
+	seen := make(map[int64]bool)
+	tos := make([]string, 0, len(users)+len(admins))
+	for _, u := range append(users, admins...) {
+		if seen[u.ID] || u.IsOrganization() || u.Email == "" {
+			continue
+		}
+		seen[u.ID] = true
+		tos = append(tos, u.Email)
+	}
+
+	descs := make([]string, len(alerts))
+	for i, a := range alerts {
+		descs[i] = fmt.Sprintf("%s: %s@%s (%s)", a.VulnerabilityID, a.PackageName, a.Version, a.ManifestPath)
+		if a.FixedVersion != "" {
+			descs[i] += fmt.Sprintf(", fixed in %s", a.FixedVersion)
+		}
+		if a.Summary != "" {
+			descs[i] += " - " + a.Summary
+		}
+	}
+	email.SendSecurityAlertMail(NewMailerRepo(repo), tos, descs, repo.HTMLURL()+"/settings/security")
+	return nil
+}
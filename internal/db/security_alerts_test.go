@@ -0,0 +1,136 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/manifest"
+	"gogs.io/gogs/internal/osv"
+)
+
+func TestSecurityAlerts(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(SecurityAlert)}
+	db := &securityAlerts{
+		DB: dbtest.NewDB(t, "securityAlerts", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *securityAlerts)
+	}{
+		{"Sync", securityAlertsSync},
+		{"ListRepoIDs", securityAlertsListRepoIDs},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func securityAlertsSync(t *testing.T, db *securityAlerts) {
+	ctx := context.Background()
+
+	lodash := func() *SecurityAlert {
+		return &SecurityAlert{VulnerabilityID: "GHSA-p6mc-m468-83gw", ManifestPath: "package.json", Ecosystem: manifest.EcosystemNPM, PackageName: "lodash", Version: "4.17.15"}
+	}
+	gin := func() *SecurityAlert {
+		return &SecurityAlert{VulnerabilityID: "GO-2020-0001", ManifestPath: "go.mod", Ecosystem: manifest.EcosystemGo, PackageName: "github.com/gin-gonic/gin", Version: "v1.5.0"}
+	}
+
+	created, err := db.Sync(ctx, 1, []*SecurityAlert{lodash()})
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	firstID := created[0].ID
+
+	// Existing alerts are kept and only new ones are returned.
+	created, err = db.Sync(ctx, 1, []*SecurityAlert{lodash(), gin(), gin()})
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	assert.Equal(t, "GO-2020-0001", created[0].VulnerabilityID)
+
+	alerts, err := db.ListByRepo(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, alerts, 2)
+	assert.Equal(t, "github.com/gin-gonic/gin", alerts[0].PackageName)
+	assert.Equal(t, firstID, alerts[1].ID)
+
+	// Alerts that are no longer present are removed.
+	created, err = db.Sync(ctx, 1, []*SecurityAlert{gin()})
+	require.NoError(t, err)
+	assert.Empty(t, created)
+
+	count, err := db.CountByRepo(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func securityAlertsListRepoIDs(t *testing.T, db *securityAlerts) {
+	ctx := context.Background()
+
+	for _, repoID := range []int64{3, 1} {
+		_, err := db.Sync(ctx, repoID, []*SecurityAlert{
+			{VulnerabilityID: "GO-2020-0001", ManifestPath: "go.mod", Ecosystem: manifest.EcosystemGo, PackageName: "github.com/gin-gonic/gin", Version: "v1.5.0"},
+			{VulnerabilityID: "GO-2020-0002", ManifestPath: "go.mod", Ecosystem: manifest.EcosystemGo, PackageName: "github.com/gin-gonic/gin", Version: "v1.5.0"},
+		})
+		require.NoError(t, err)
+	}
+
+	repoIDs, err := db.ListRepoIDs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 3}, repoIDs)
+}
+
+func TestMatchSecurityAlerts(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "GHSA-p6mc-m468-83gw.json"), []byte(`{
+  "id": "GHSA-p6mc-m468-83gw",
+  "summary": "Prototype Pollution in lodash",
+  "affected": [{
+    "package": {"ecosystem": "npm", "name": "lodash"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "3.7.0"}, {"fixed": "4.17.19"}]}]
+  }]
+}`), 0644)
+	require.NoError(t, err)
+	vulndb, err := osv.Load(dir)
+	require.NoError(t, err)
+
+	got := matchSecurityAlerts(vulndb, []*RepoDependency{
+		{ManifestPath: "package.json", Ecosystem: manifest.EcosystemNPM, Name: "lodash", Requirement: "4.17.15"},
+		{ManifestPath: "web/package.json", Ecosystem: manifest.EcosystemNPM, Name: "lodash", Requirement: "^4.17.15"},
+		{ManifestPath: "docs/package.json", Ecosystem: manifest.EcosystemNPM, Name: "lodash", Requirement: "4.17.21"},
+	})
+	want := []*SecurityAlert{
+		{
+			VulnerabilityID: "GHSA-p6mc-m468-83gw",
+			Summary:         "Prototype Pollution in lodash",
+			ManifestPath:    "package.json",
+			Ecosystem:       manifest.EcosystemNPM,
+			PackageName:     "lodash",
+			Version:         "4.17.15",
+			FixedVersion:    "4.17.19",
+		},
+	}
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,236 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/errutil"
+)
+
+// SecurityPolicyExceptionsStore is the persistent interface for exceptions of
+// repositories to security policies of organizations.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type SecurityPolicyExceptionsStore interface {
+	// Create requests an exception of the repository to the feature of the
+	// security policy of the organization. A rejected request is replaced by
+	// the new one. It returns ErrSecurityPolicyExceptionAlreadyExist when a
+	// request for the feature of the repository is pending or approved.
+	Create(ctx context.Context, orgID, repoID, requesterID int64, feature SecurityFeature, reason string) (*SecurityPolicyException, error)
+	// DeleteByID deletes the exception with given ID of the organization.
+	DeleteByID(ctx context.Context, orgID, id int64) error
+	// IsExempted returns true if the repository has an approved exception of the
+	// organization to the feature. Exceptions of organizations that previously
+	// owned the repository do not count.
+	IsExempted(ctx context.Context, orgID, repoID int64, feature SecurityFeature) (bool, error)
+	// List returns all exceptions of repositories of the organization, with
+	// pending ones first and then the most recent ones.
+	List(ctx context.Context, orgID int64) ([]*SecurityPolicyException, error)
+	// ListByRepo returns all exceptions of the repository, ordered by feature.
+	ListByRepo(ctx context.Context, repoID int64) ([]*SecurityPolicyException, error)
+	// Review approves or rejects the pending exception with given ID of the
+	// organization by the reviewer. It returns ErrSecurityPolicyExceptionNotExist
+	// when no such pending exception is found.
+	Review(ctx context.Context, orgID, id, reviewerID int64, approved bool) error
+}
+
+var SecurityPolicyExceptions SecurityPolicyExceptionsStore
+
+// SecurityPolicyExceptionStatus is the review status of an exception.
+type SecurityPolicyExceptionStatus string
+
+const (
+	SecurityPolicyExceptionPending  SecurityPolicyExceptionStatus = "pending"
+	SecurityPolicyExceptionApproved SecurityPolicyExceptionStatus = "approved"
+	SecurityPolicyExceptionRejected SecurityPolicyExceptionStatus = "rejected"
+)
+
+// SecurityPolicyException is a request of a repository to be exempted from a
+// security feature enabled by the policy of the organization that owns the
+// repository, which takes effect once approved by owners of the organization.
+type SecurityPolicyException struct {
+	ID          int64                         `gorm:"primaryKey"`
+	OrgID       int64                         `gorm:"index;not null"`
+	RepoID      int64                         `gorm:"uniqueIndex:security_policy_exception_repo_feature_unique;not null"`
+	Feature     SecurityFeature               `gorm:"type:VARCHAR(32);uniqueIndex:security_policy_exception_repo_feature_unique;not null"`
+	Reason      string                        `gorm:"type:TEXT;not null"`
+	RequesterID int64                         `gorm:"not null"`
+	Status      SecurityPolicyExceptionStatus `gorm:"type:VARCHAR(16);not null"`
+	ReviewerID  int64                         `gorm:"not null"`
+	CreatedAt   time.Time                     `gorm:"not null"`
+	UpdatedAt   time.Time                     `gorm:"not null"`
+
+	Repo      *Repository `gorm:"-" json:"-"`
+	Requester *User       `gorm:"-" json:"-"`
+	Reviewer  *User       `gorm:"-" json:"-"`
+}
+
+// IsPending returns true if the exception has not been reviewed.
+func (e *SecurityPolicyException) IsPending() bool {
+	return e.Status == SecurityPolicyExceptionPending
+}
+
+// IsApproved returns true if the exception has been approved.
+func (e *SecurityPolicyException) IsApproved() bool {
+	return e.Status == SecurityPolicyExceptionApproved
+}
+
+// LoadAttributes loads the repository, the requester and the reviewer of the
+// exception. Deleted users are left as nil.
+func (e *SecurityPolicyException) LoadAttributes(ctx context.Context) (err error) {
+	if e.Repo == nil {
+		e.Repo, err = GetRepositoryByID(e.RepoID)
+		if err != nil {
+			return errors.Wrap(err, "get repository")
+		}
+	}
+	if e.Requester == nil {
+		e.Requester, err = Users.GetByID(ctx, e.RequesterID)
+		if err != nil && !IsErrUserNotExist(err) {
+			return errors.Wrap(err, "get requester")
+		}
+	}
+	if e.Reviewer == nil && e.ReviewerID > 0 {
+		e.Reviewer, err = Users.GetByID(ctx, e.ReviewerID)
+		if err != nil && !IsErrUserNotExist(err) {
+			return errors.Wrap(err, "get reviewer")
+		}
+	}
+	return nil
+}
+
+var _ SecurityPolicyExceptionsStore = (*securityPolicyExceptions)(nil)
+
+type securityPolicyExceptions struct {
+	*gorm.DB
+}
+
+// NewSecurityPolicyExceptionsStore returns a persistent interface for
+// exceptions to security policies with given database connection.
+func NewSecurityPolicyExceptionsStore(db *gorm.DB) SecurityPolicyExceptionsStore {
+	return &securityPolicyExceptions{DB: db}
+}
+
+type ErrSecurityPolicyExceptionAlreadyExist struct {
+	args errutil.Args
+}
+
+func IsErrSecurityPolicyExceptionAlreadyExist(err error) bool {
+	_, ok := err.(ErrSecurityPolicyExceptionAlreadyExist)
+	return ok
+}
+
+func (err ErrSecurityPolicyExceptionAlreadyExist) Error() string {
+	return fmt.Sprintf("security policy exception already exists: %v", err.args)
+}
+
+func (db *securityPolicyExceptions) Create(ctx context.Context, orgID, repoID, requesterID int64, feature SecurityFeature, reason string) (*SecurityPolicyException, error) {
+	e := new(SecurityPolicyException)
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("repo_id = ? AND feature = ?", repoID, feature).First(e).Error
+		if err == nil {
+			if e.Status != SecurityPolicyExceptionRejected {
+				return ErrSecurityPolicyExceptionAlreadyExist{args: errutil.Args{"repoID": repoID, "feature": feature}}
+			}
+		} else if err != gorm.ErrRecordNotFound {
+			return errors.Wrap(err, "check existence")
+		}
+
+		e.OrgID = orgID
+		e.RepoID = repoID
+		e.Feature = feature
+		e.Reason = reason
+		e.RequesterID = requesterID
+		e.Status = SecurityPolicyExceptionPending
+		e.ReviewerID = 0
+		if e.ID > 0 {
+			e.CreatedAt = tx.NowFunc()
+		}
+		return tx.Save(e).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (db *securityPolicyExceptions) DeleteByID(ctx context.Context, orgID, id int64) error {
+	return db.WithContext(ctx).Where("id = ? AND org_id = ?", id, orgID).Delete(new(SecurityPolicyException)).Error
+}
+
+func (db *securityPolicyExceptions) IsExempted(ctx context.Context, orgID, repoID int64, feature SecurityFeature) (bool, error) {
+	var count int64
+	err := db.WithContext(ctx).
+		Model(new(SecurityPolicyException)).
+		Where("org_id = ? AND repo_id = ? AND feature = ? AND status = ?", orgID, repoID, feature, SecurityPolicyExceptionApproved).
+		Count(&count).
+		Error
+	return count > 0, err
+}
+
+func (db *securityPolicyExceptions) List(ctx context.Context, orgID int64) ([]*SecurityPolicyException, error) {
+	var exceptions []*SecurityPolicyException
+	err := db.WithContext(ctx).Where("org_id = ?", orgID).Order("updated_at DESC, id DESC").Find(&exceptions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(exceptions, func(i, j int) bool {
+		return exceptions[i].IsPending() && !exceptions[j].IsPending()
+	})
+	return exceptions, nil
+}
+
+func (db *securityPolicyExceptions) ListByRepo(ctx context.Context, repoID int64) ([]*SecurityPolicyException, error) {
+	var exceptions []*SecurityPolicyException
+	return exceptions, db.WithContext(ctx).Where("repo_id = ?", repoID).Order("feature ASC").Find(&exceptions).Error
+}
+
+var _ errutil.NotFound = (*ErrSecurityPolicyExceptionNotExist)(nil)
+
+type ErrSecurityPolicyExceptionNotExist struct {
+	args errutil.Args
+}
+
+func IsErrSecurityPolicyExceptionNotExist(err error) bool {
+	_, ok := err.(ErrSecurityPolicyExceptionNotExist)
+	return ok
+}
+
+func (err ErrSecurityPolicyExceptionNotExist) Error() string {
+	return fmt.Sprintf("security policy exception does not exist: %v", err.args)
+}
+
+func (ErrSecurityPolicyExceptionNotExist) NotFound() bool {
+	return true
+}
+
+func (db *securityPolicyExceptions) Review(ctx context.Context, orgID, id, reviewerID int64, approved bool) error {
+	status := SecurityPolicyExceptionRejected
+	if approved {
+		status = SecurityPolicyExceptionApproved
+	}
+
+	result := db.WithContext(ctx).
+		Model(new(SecurityPolicyException)).
+		Where("id = ? AND org_id = ? AND status = ?", id, orgID, SecurityPolicyExceptionPending).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"reviewer_id": reviewerID,
+		})
+	if result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		return ErrSecurityPolicyExceptionNotExist{args: errutil.Args{"orgID": orgID, "id": id}}
+	}
+	return nil
+}
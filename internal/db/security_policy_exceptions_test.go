@@ -0,0 +1,151 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/errutil"
+)
+
+func TestSecurityPolicyExceptions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(SecurityPolicyException)}
+	db := &securityPolicyExceptions{
+		DB: dbtest.NewDB(t, "securityPolicyExceptions", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *securityPolicyExceptions)
+	}{
+		{"Create", securityPolicyExceptionsCreate},
+		{"DeleteByID", securityPolicyExceptionsDeleteByID},
+		{"List", securityPolicyExceptionsList},
+		{"Review", securityPolicyExceptionsReview},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func securityPolicyExceptionsCreate(t *testing.T, db *securityPolicyExceptions) {
+	ctx := context.Background()
+
+	e, err := db.Create(ctx, 1, 2, 3, SecurityFeatureSignedCommits, "Mirrors upstream commits")
+	require.NoError(t, err)
+	assert.True(t, e.IsPending())
+
+	_, err = db.Create(ctx, 1, 2, 3, SecurityFeatureSignedCommits, "Again")
+	wantErr := ErrSecurityPolicyExceptionAlreadyExist{args: errutil.Args{"repoID": int64(2), "feature": SecurityFeatureSignedCommits}}
+	assert.Equal(t, wantErr, err)
+
+	// A rejected request is replaced by the new one
+	err = db.Review(ctx, 1, e.ID, 4, false)
+	require.NoError(t, err)
+	renewed, err := db.Create(ctx, 1, 2, 5, SecurityFeatureSignedCommits, "Still mirrors upstream commits")
+	require.NoError(t, err)
+	assert.Equal(t, e.ID, renewed.ID)
+
+	list, err := db.ListByRepo(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.True(t, list[0].IsPending())
+	assert.Equal(t, int64(5), list[0].RequesterID)
+	assert.Equal(t, int64(0), list[0].ReviewerID)
+	assert.Equal(t, "Still mirrors upstream commits", list[0].Reason)
+}
+
+func securityPolicyExceptionsDeleteByID(t *testing.T, db *securityPolicyExceptions) {
+	ctx := context.Background()
+
+	e, err := db.Create(ctx, 1, 2, 3, SecurityFeatureSecretScanning, "Test fixtures")
+	require.NoError(t, err)
+
+	// Deleting with another organization is a no-op
+	err = db.DeleteByID(ctx, 2, e.ID)
+	require.NoError(t, err)
+	list, err := db.ListByRepo(ctx, 2)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	err = db.DeleteByID(ctx, 1, e.ID)
+	require.NoError(t, err)
+	list, err = db.ListByRepo(ctx, 2)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func securityPolicyExceptionsList(t *testing.T, db *securityPolicyExceptions) {
+	ctx := context.Background()
+
+	e1, err := db.Create(ctx, 1, 2, 3, SecurityFeatureSecretScanning, "Test fixtures")
+	require.NoError(t, err)
+	_, err = db.Create(ctx, 1, 3, 3, SecurityFeatureSignedCommits, "Mirrors upstream commits")
+	require.NoError(t, err)
+	_, err = db.Create(ctx, 2, 4, 3, SecurityFeatureSignedCommits, "Another organization")
+	require.NoError(t, err)
+
+	err = db.Review(ctx, 1, e1.ID, 4, true)
+	require.NoError(t, err)
+
+	// Pending ones come first
+	list, err := db.List(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, int64(3), list[0].RepoID)
+	assert.Equal(t, int64(2), list[1].RepoID)
+}
+
+func securityPolicyExceptionsReview(t *testing.T, db *securityPolicyExceptions) {
+	ctx := context.Background()
+
+	e, err := db.Create(ctx, 1, 2, 3, SecurityFeatureSignedCommits, "Mirrors upstream commits")
+	require.NoError(t, err)
+
+	exempted, err := db.IsExempted(ctx, 1, 2, SecurityFeatureSignedCommits)
+	require.NoError(t, err)
+	assert.False(t, exempted)
+
+	err = db.Review(ctx, 2, e.ID, 4, true)
+	wantErr := ErrSecurityPolicyExceptionNotExist{args: errutil.Args{"orgID": int64(2), "id": e.ID}}
+	assert.Equal(t, wantErr, err)
+
+	err = db.Review(ctx, 1, e.ID, 4, true)
+	require.NoError(t, err)
+
+	exempted, err = db.IsExempted(ctx, 1, 2, SecurityFeatureSignedCommits)
+	require.NoError(t, err)
+	assert.True(t, exempted)
+
+	// Exceptions only apply to the feature and the organization
+	exempted, err = db.IsExempted(ctx, 1, 2, SecurityFeatureSecretScanning)
+	require.NoError(t, err)
+	assert.False(t, exempted)
+	exempted, err = db.IsExempted(ctx, 2, 2, SecurityFeatureSignedCommits)
+	require.NoError(t, err)
+	assert.False(t, exempted)
+
+	// Reviewed exceptions cannot be reviewed again
+	err = db.Review(ctx, 1, e.ID, 4, false)
+	assert.True(t, IsErrSecurityPolicyExceptionNotExist(err))
+}
@@ -0,0 +1,310 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+	"github.com/unknwon/com"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/errutil"
+	"gogs.io/gogs/internal/osutil"
+	"gogs.io/gogs/internal/sync"
+)
+
+var snippetWorkingPool = sync.NewExclusivePool()
+
+// snippetBranch is the branch that files of snippets are committed to.
+const snippetBranch = "master"
+
+// SnippetFile is a file of a snippet.
+type SnippetFile struct {
+	Name    string
+	Content []byte
+}
+
+var (
+	snippetFileNamePattern = regexp.MustCompile(`^[\w.-]{1,255}$`)
+	snippetRevisionPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+)
+
+// ValidateSnippetFiles returns ErrSnippetInvalid when there is no file, more
+// files than allowed, a file with an invalid or duplicated name, or a file
+// that is empty or too large.
+func ValidateSnippetFiles(files []*SnippetFile) error {
+	if len(files) == 0 {
+		return ErrSnippetInvalid{args: errutil.Args{"files": "no file"}}
+	} else if len(files) > conf.Snippet.MaxFiles {
+		return ErrSnippetInvalid{args: errutil.Args{"files": fmt.Sprintf("more than %d files", conf.Snippet.MaxFiles)}}
+	}
+
+	names := make(map[string]bool, len(files))
+	for _, f := range files {
+		switch {
+		case !snippetFileNamePattern.MatchString(f.Name) ||
+			strings.Trim(f.Name, ".") == "" ||
+			isRepositoryGitPath(f.Name):
+			return ErrSnippetInvalid{args: errutil.Args{"name": f.Name}}
+		case names[strings.ToLower(f.Name)]:
+			return ErrSnippetInvalid{args: errutil.Args{"name": f.Name, "reason": "duplicated"}}
+		case len(f.Content) == 0:
+			return ErrSnippetInvalid{args: errutil.Args{"name": f.Name, "reason": "empty"}}
+		case int64(len(f.Content)) > conf.Snippet.MaxFileSize*1024:
+			return ErrSnippetInvalid{args: errutil.Args{"name": f.Name, "reason": "too large"}}
+		}
+		names[strings.ToLower(f.Name)] = true
+	}
+	return nil
+}
+
+// IsValidSnippetRevision returns true if the revision looks like a full or
+// abbreviated commit SHA.
+func IsValidSnippetRevision(revision string) bool {
+	return snippetRevisionPattern.MatchString(revision)
+}
+
+// RepoPath returns the path of the Git repository of the snippet.
+func (s *Snippet) RepoPath() string {
+	return filepath.Join(conf.Snippet.RootPath, com.ToStr(s.ID)+".git")
+}
+
+func (s *Snippet) localCopyPath() string {
+	return filepath.Join(conf.Server.AppDataPath, "tmp", "local-snippet", com.ToStr(s.ID))
+}
+
+// Files returns files of the snippet at the revision, or the latest revision
+// when empty, sorted by name. It returns an empty list when nothing has been
+// committed.
+func (s *Snippet) Files(revision string) ([]*SnippetFile, error) {
+	if !osutil.IsDir(s.RepoPath()) {
+		return []*SnippetFile{}, nil
+	}
+
+	gitRepo, err := git.Open(s.RepoPath())
+	if err != nil {
+		return nil, errors.Wrap(err, "open repository")
+	}
+
+	var commit *git.Commit
+	if revision == "" {
+		if !gitRepo.HasBranch(snippetBranch) {
+			return []*SnippetFile{}, nil
+		}
+		commit, err = gitRepo.BranchCommit(snippetBranch)
+	} else {
+		// Only existing commits are allowed, the type check fails for unknown
+		// objects.
+		if !IsValidSnippetRevision(revision) {
+			return nil, ErrSnippetRevisionNotExist{args: errutil.Args{"revision": revision}}
+		} else if typ, err := gitRepo.CatFileType(revision); err != nil || typ != git.ObjectCommit {
+			return nil, ErrSnippetRevisionNotExist{args: errutil.Args{"revision": revision}}
+		}
+		commit, err = gitRepo.CatFileCommit(revision)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "get commit")
+	}
+
+	entries, err := commit.Entries()
+	if err != nil {
+		return nil, errors.Wrap(err, "list entries")
+	}
+
+	files := make([]*SnippetFile, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsBlob() {
+			continue
+		}
+		p, err := e.Blob().Bytes()
+		if err != nil {
+			return nil, errors.Wrapf(err, "read blob %q", e.Name())
+		}
+		files = append(files, &SnippetFile{
+			Name:    e.Name(),
+			Content: p,
+		})
+	}
+	return files, nil
+}
+
+var _ errutil.NotFound = (*ErrSnippetRevisionNotExist)(nil)
+
+type ErrSnippetRevisionNotExist struct {
+	args errutil.Args
+}
+
+func IsErrSnippetRevisionNotExist(err error) bool {
+	_, ok := err.(ErrSnippetRevisionNotExist)
+	return ok
+}
+
+func (err ErrSnippetRevisionNotExist) Error() string {
+	return fmt.Sprintf("snippet revision does not exist: %v", err.args)
+}
+
+func (ErrSnippetRevisionNotExist) NotFound() bool {
+	return true
+}
+
+// Revisions returns commits of the snippet, the most recent first.
+func (s *Snippet) Revisions() ([]*git.Commit, error) {
+	if !osutil.IsDir(s.RepoPath()) {
+		return []*git.Commit{}, nil
+	}
+
+	gitRepo, err := git.Open(s.RepoPath())
+	if err != nil {
+		return nil, errors.Wrap(err, "open repository")
+	} else if !gitRepo.HasBranch(snippetBranch) {
+		return []*git.Commit{}, nil
+	}
+	return gitRepo.Log(snippetBranch)
+}
+
+// snippetFilesEqual returns true if both lists of files have the same names
+// and contents regardless of the order.
+func snippetFilesEqual(a, b []*SnippetFile) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	contents := make(map[string][]byte, len(a))
+	for _, f := range a {
+		contents[f.Name] = f.Content
+	}
+	for _, f := range b {
+		content, ok := contents[f.Name]
+		if !ok || !bytes.Equal(content, f.Content) {
+			return false
+		}
+	}
+	return true
+}
+
+// commitFiles replaces all files of the snippet with given files in a new
+// commit by the doer. It does nothing when files are not changed.
+func (s *Snippet) commitFiles(doer *User, files []*SnippetFile, message string) (err error) {
+	snippetWorkingPool.CheckIn(com.ToStr(s.ID))
+	defer snippetWorkingPool.CheckOut(com.ToStr(s.ID))
+
+	current, err := s.Files("")
+	if err != nil {
+		return errors.Wrap(err, "get current files")
+	} else if snippetFilesEqual(current, files) {
+		return nil
+	}
+
+	repoPath := s.RepoPath()
+	if !osutil.IsDir(repoPath) {
+		if err = git.Init(repoPath, git.InitOptions{Bare: true}); err != nil {
+			return errors.Wrap(err, "init repository")
+		}
+	}
+
+	// Files of snippets are small, so a fresh local copy is used for every
+	// change instead of keeping one up-to-date.
+	localPath := s.localCopyPath()
+	if err = os.RemoveAll(localPath); err != nil {
+		return errors.Wrap(err, "remove local copy")
+	}
+	defer func() {
+		if err := os.RemoveAll(localPath); err != nil {
+			log.Error("Failed to remove local copy of snippet [id: %d]: %v", s.ID, err)
+		}
+	}()
+	if err = git.Clone(repoPath, localPath, git.CloneOptions{
+		Timeout: time.Duration(conf.Git.Timeout.Clone) * time.Second,
+	}); err != nil {
+		return errors.Wrap(err, "clone")
+	}
+
+	for _, f := range current {
+		if err = os.Remove(filepath.Join(localPath, f.Name)); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "remove %q", f.Name)
+		}
+	}
+	for _, f := range files {
+		if err = ioutil.WriteFile(filepath.Join(localPath, f.Name), f.Content, 0666); err != nil {
+			return errors.Wrapf(err, "write %q", f.Name)
+		}
+	}
+
+	if err = git.Add(localPath, git.AddOptions{All: true}); err != nil {
+		return errors.Wrap(err, "add all changes")
+	} else if err = git.CreateCommit(localPath, doer.NewGitSig(), message); err != nil {
+		return errors.Wrap(err, "commit changes")
+	} else if err = git.Push(localPath, "origin", "HEAD:refs/heads/"+snippetBranch); err != nil {
+		return errors.Wrap(err, "push")
+	}
+	return nil
+}
+
+// CreateSnippet creates a new snippet owned by the owner with files committed
+// by the doer.
+func CreateSnippet(ctx context.Context, doer, owner *User, opts CreateSnippetOptions, files []*SnippetFile) (*Snippet, error) {
+	if err := ValidateSnippetFiles(files); err != nil {
+		return nil, err
+	}
+
+	s, err := Snippets.Create(ctx, owner.ID, doer.ID, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "create snippet")
+	}
+
+	if err = s.commitFiles(doer, files, "Create snippet"); err != nil {
+		if err := DeleteSnippet(ctx, s); err != nil {
+			log.Error("Failed to delete snippet [id: %d]: %v", s.ID, err)
+		}
+		return nil, errors.Wrap(err, "commit files")
+	}
+	return s, nil
+}
+
+// UpdateSnippet updates the title and visibility of the snippet, and replaces
+// its files in a new commit by the doer when files are given.
+func UpdateSnippet(ctx context.Context, doer *User, s *Snippet, opts UpdateSnippetOptions, files []*SnippetFile) error {
+	if files != nil {
+		if err := ValidateSnippetFiles(files); err != nil {
+			return err
+		}
+	}
+
+	if err := Snippets.Update(ctx, s.ID, opts); err != nil {
+		return errors.Wrap(err, "update snippet")
+	}
+	s.Title = opts.Title
+	s.Visibility = opts.Visibility
+
+	if files != nil {
+		if err := s.commitFiles(doer, files, "Update snippet"); err != nil {
+			return errors.Wrap(err, "commit files")
+		}
+	}
+	return nil
+}
+
+// DeleteSnippet deletes the snippet and its Git repository.
+func DeleteSnippet(ctx context.Context, s *Snippet) error {
+	if err := Snippets.DeleteByID(ctx, s.ID); err != nil {
+		return errors.Wrap(err, "delete snippet")
+	}
+
+	if err := os.RemoveAll(s.RepoPath()); err != nil {
+		return errors.Wrap(err, "remove repository")
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/conf"
+)
+
+func setMockSnippetConf(t *testing.T) {
+	t.Helper()
+
+	oldSnippet := conf.Snippet
+	oldAppDataPath := conf.Server.AppDataPath
+	conf.Snippet.RootPath = t.TempDir()
+	conf.Snippet.MaxFiles = 2
+	conf.Snippet.MaxFileSize = 1
+	conf.Server.AppDataPath = t.TempDir()
+	t.Cleanup(func() {
+		conf.Snippet = oldSnippet
+		conf.Server.AppDataPath = oldAppDataPath
+	})
+}
+
+func TestValidateSnippetFiles(t *testing.T) {
+	setMockSnippetConf(t)
+
+	content := []byte("echo hello")
+	tests := []struct {
+		name    string
+		files   []*SnippetFile
+		wantErr bool
+	}{
+		{
+			name:  "valid",
+			files: []*SnippetFile{{Name: "deploy.sh", Content: content}, {Name: ".env_example", Content: content}},
+		},
+		{
+			name:    "no file",
+			files:   nil,
+			wantErr: true,
+		},
+		{
+			name:    "too many files",
+			files:   []*SnippetFile{{Name: "a", Content: content}, {Name: "b", Content: content}, {Name: "c", Content: content}},
+			wantErr: true,
+		},
+		{
+			name:    "path",
+			files:   []*SnippetFile{{Name: "dir/deploy.sh", Content: content}},
+			wantErr: true,
+		},
+		{
+			name:    "dots",
+			files:   []*SnippetFile{{Name: "..", Content: content}},
+			wantErr: true,
+		},
+		{
+			name:    "git directory",
+			files:   []*SnippetFile{{Name: ".git", Content: content}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicated",
+			files:   []*SnippetFile{{Name: "README", Content: content}, {Name: "readme", Content: content}},
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			files:   []*SnippetFile{{Name: "empty.txt"}},
+			wantErr: true,
+		},
+		{
+			name:    "too large",
+			files:   []*SnippetFile{{Name: "large.txt", Content: []byte(strings.Repeat("a", 1025))}},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateSnippetFiles(test.files)
+			if test.wantErr {
+				assert.True(t, IsErrSnippetInvalid(err), "%v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSnippet_commitFiles(t *testing.T) {
+	setMockSnippetConf(t)
+
+	doer := &User{Name: "alice", Email: "alice@example.com"}
+	s := &Snippet{ID: 1}
+
+	files, err := s.Files("")
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	v1 := []*SnippetFile{
+		{Name: "main.go", Content: []byte("package main\n")},
+		{Name: "README.md", Content: []byte("# Hello\n")},
+	}
+	require.NoError(t, s.commitFiles(doer, v1, "Create snippet"))
+
+	v2 := []*SnippetFile{
+		{Name: "main.go", Content: []byte("package main\n\nfunc main() {}\n")},
+	}
+	require.NoError(t, s.commitFiles(doer, v2, "Update snippet"))
+	// Committing the same files again is a no-op.
+	require.NoError(t, s.commitFiles(doer, v2, "Update snippet"))
+
+	revisions, err := s.Revisions()
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, "Update snippet", revisions[0].Summary())
+	assert.Equal(t, "alice", revisions[0].Author.Name)
+
+	files, err = s.Files("")
+	require.NoError(t, err)
+	assert.Equal(t, v2, files)
+
+	files, err = s.Files(revisions[1].ID.String())
+	require.NoError(t, err)
+	assert.True(t, snippetFilesEqual(v1, files))
+
+	_, err = s.Files("0000000000000000000000000000000000000000")
+	assert.True(t, IsErrSnippetRevisionNotExist(err), "%v", err)
+	_, err = s.Files("--all")
+	assert.True(t, IsErrSnippetRevisionNotExist(err), "%v", err)
+}
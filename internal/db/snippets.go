@@ -0,0 +1,239 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/errutil"
+	"gogs.io/gogs/internal/strutil"
+)
+
+// SnippetsStore is the persistent interface for snippets.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type SnippetsStore interface {
+	// Create creates a new snippet owned by the user or organization with given
+	// ID, and a random name.
+	Create(ctx context.Context, ownerID, creatorID int64, opts CreateSnippetOptions) (*Snippet, error)
+	// DeleteByID deletes the snippet with given ID.
+	DeleteByID(ctx context.Context, id int64) error
+	// GetByName returns the snippet with given name. It returns
+	// ErrSnippetNotExist when not found.
+	GetByName(ctx context.Context, name string) (*Snippet, error)
+	// List returns snippets matching the options and the total count, ordered by
+	// the most recently updated first.
+	List(ctx context.Context, opts ListSnippetsOptions) ([]*Snippet, int64, error)
+	// Update updates the title and visibility of the snippet with given ID, and
+	// its updated time.
+	Update(ctx context.Context, id int64, opts UpdateSnippetOptions) error
+}
+
+var Snippets SnippetsStore
+
+// SnippetVisibility is the visibility of a snippet.
+type SnippetVisibility string
+
+const (
+	// SnippetPublic snippets are listed publicly.
+	SnippetPublic SnippetVisibility = "public"
+	// SnippetSecret snippets are not listed, but accessible by anyone with the
+	// link.
+	SnippetSecret SnippetVisibility = "secret"
+	// SnippetPrivate snippets are only accessible by the owner, members of the
+	// owner organization and site admins.
+	SnippetPrivate SnippetVisibility = "private"
+)
+
+// IsValid returns true if the visibility is one of known visibilities.
+func (v SnippetVisibility) IsValid() bool {
+	switch v {
+	case SnippetPublic, SnippetSecret, SnippetPrivate:
+		return true
+	}
+	return false
+}
+
+// Snippet is a single or multi-file paste owned by a user or an organization.
+// Files of a snippet are stored in its own Git repository so that every change
+// is versioned.
+type Snippet struct {
+	ID int64 `gorm:"primaryKey"`
+	// Name is the random identifier of the snippet in URLs, which is hard to
+	// guess for secret snippets.
+	Name       string            `gorm:"type:VARCHAR(20);uniqueIndex;not null"`
+	OwnerID    int64             `gorm:"index;not null"`
+	Title      string            `gorm:"not null"`
+	Visibility SnippetVisibility `gorm:"type:VARCHAR(7);not null"`
+	CreatorID  int64             `gorm:"not null"`
+	CreatedAt  time.Time         `gorm:"not null"`
+	UpdatedAt  time.Time         `gorm:"not null"`
+}
+
+// CanWriteSnippets returns true if the user is allowed to create, edit and
+// delete snippets owned by the user or organization with given ID, and to view
+// their private snippets.
+func CanWriteSnippets(ownerID int64, user *User) bool {
+	if user == nil {
+		return false
+	}
+	return user.ID == ownerID || user.IsAdmin || IsOrganizationMember(ownerID, user.ID)
+}
+
+// IsReadableBy returns true if the user is allowed to view the snippet. The
+// user is nil for anonymous visitors.
+func (s *Snippet) IsReadableBy(user *User) bool {
+	return s.Visibility != SnippetPrivate || CanWriteSnippets(s.OwnerID, user)
+}
+
+var _ SnippetsStore = (*snippets)(nil)
+
+type snippets struct {
+	*gorm.DB
+}
+
+// NewSnippetsStore returns a persistent interface for snippets with given
+// database connection.
+func NewSnippetsStore(db *gorm.DB) SnippetsStore {
+	return &snippets{DB: db}
+}
+
+type CreateSnippetOptions struct {
+	Title      string
+	Visibility SnippetVisibility
+}
+
+type ErrSnippetInvalid struct {
+	args errutil.Args
+}
+
+func IsErrSnippetInvalid(err error) bool {
+	_, ok := err.(ErrSnippetInvalid)
+	return ok
+}
+
+func (err ErrSnippetInvalid) Error() string {
+	return fmt.Sprintf("snippet is invalid: %v", err.args)
+}
+
+func (db *snippets) Create(ctx context.Context, ownerID, creatorID int64, opts CreateSnippetOptions) (*Snippet, error) {
+	if !opts.Visibility.IsValid() {
+		return nil, ErrSnippetInvalid{args: errutil.Args{"visibility": opts.Visibility}}
+	}
+
+	name, err := strutil.RandomChars(20)
+	if err != nil {
+		return nil, errors.Wrap(err, "generate name")
+	}
+
+	s := &Snippet{
+		Name:       name,
+		OwnerID:    ownerID,
+		Title:      opts.Title,
+		Visibility: opts.Visibility,
+		CreatorID:  creatorID,
+	}
+	return s, db.WithContext(ctx).Create(s).Error
+}
+
+func (db *snippets) DeleteByID(ctx context.Context, id int64) error {
+	return db.WithContext(ctx).Where("id = ?", id).Delete(new(Snippet)).Error
+}
+
+var _ errutil.NotFound = (*ErrSnippetNotExist)(nil)
+
+type ErrSnippetNotExist struct {
+	args errutil.Args
+}
+
+func IsErrSnippetNotExist(err error) bool {
+	_, ok := err.(ErrSnippetNotExist)
+	return ok
+}
+
+func (err ErrSnippetNotExist) Error() string {
+	return fmt.Sprintf("snippet does not exist: %v", err.args)
+}
+
+func (ErrSnippetNotExist) NotFound() bool {
+	return true
+}
+
+func (db *snippets) GetByName(ctx context.Context, name string) (*Snippet, error) {
+	s := new(Snippet)
+	err := db.WithContext(ctx).Where("name = ?", name).First(s).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrSnippetNotExist{args: errutil.Args{"name": name}}
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+type ListSnippetsOptions struct {
+	// OwnerID filters snippets by the owner when positive.
+	OwnerID int64
+	// Visibilities filters snippets by visibility when not empty.
+	Visibilities []SnippetVisibility
+	// Page is the page number starting from 1.
+	Page     int
+	PageSize int
+}
+
+func (db *snippets) List(ctx context.Context, opts ListSnippetsOptions) ([]*Snippet, int64, error) {
+	if opts.Page <= 0 {
+		opts.Page = 1
+	}
+
+	query := db.WithContext(ctx).Model(new(Snippet))
+	if opts.OwnerID > 0 {
+		query = query.Where("owner_id = ?", opts.OwnerID)
+	}
+	if len(opts.Visibilities) > 0 {
+		query = query.Where("visibility IN (?)", opts.Visibilities)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	snippets := make([]*Snippet, 0, opts.PageSize)
+	err := query.
+		Order("updated_at DESC").
+		Order("id DESC").
+		Limit(opts.PageSize).
+		Offset((opts.Page - 1) * opts.PageSize).
+		Find(&snippets).
+		Error
+	return snippets, count, err
+}
+
+type UpdateSnippetOptions struct {
+	Title      string
+	Visibility SnippetVisibility
+}
+
+func (db *snippets) Update(ctx context.Context, id int64, opts UpdateSnippetOptions) error {
+	if !opts.Visibility.IsValid() {
+		return ErrSnippetInvalid{args: errutil.Args{"visibility": opts.Visibility}}
+	}
+
+	return db.WithContext(ctx).
+		Model(new(Snippet)).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"title":      opts.Title,
+			"visibility": opts.Visibility,
+			"updated_at": db.NowFunc(),
+		}).
+		Error
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/errutil"
+)
+
+func TestSnippets(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(Snippet)}
+	db := &snippets{
+		DB: dbtest.NewDB(t, "snippets", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *snippets)
+	}{
+		{"Create", snippetsCreate},
+		{"DeleteByID", snippetsDeleteByID},
+		{"GetByName", snippetsGetByName},
+		{"List", snippetsList},
+		{"Update", snippetsUpdate},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func snippetsCreate(t *testing.T, db *snippets) {
+	ctx := context.Background()
+
+	s1, err := db.Create(ctx, 1, 2, CreateSnippetOptions{Title: "Deploy script", Visibility: SnippetSecret})
+	require.NoError(t, err)
+	assert.Len(t, s1.Name, 20)
+	assert.Equal(t, int64(1), s1.OwnerID)
+	assert.Equal(t, int64(2), s1.CreatorID)
+	assert.False(t, s1.CreatedAt.IsZero())
+
+	s2, err := db.Create(ctx, 1, 2, CreateSnippetOptions{Visibility: SnippetPublic})
+	require.NoError(t, err)
+	assert.NotEqual(t, s1.Name, s2.Name)
+
+	_, err = db.Create(ctx, 1, 2, CreateSnippetOptions{Visibility: "internal"})
+	wantErr := ErrSnippetInvalid{args: errutil.Args{"visibility": SnippetVisibility("internal")}}
+	assert.Equal(t, wantErr, err)
+}
+
+func snippetsDeleteByID(t *testing.T, db *snippets) {
+	ctx := context.Background()
+
+	s, err := db.Create(ctx, 1, 1, CreateSnippetOptions{Visibility: SnippetPublic})
+	require.NoError(t, err)
+
+	require.NoError(t, db.DeleteByID(ctx, s.ID))
+	_, err = db.GetByName(ctx, s.Name)
+	assert.True(t, IsErrSnippetNotExist(err))
+}
+
+func snippetsGetByName(t *testing.T, db *snippets) {
+	ctx := context.Background()
+
+	_, err := db.GetByName(ctx, "404")
+	wantErr := ErrSnippetNotExist{args: errutil.Args{"name": "404"}}
+	assert.Equal(t, wantErr, err)
+
+	s, err := db.Create(ctx, 1, 1, CreateSnippetOptions{Title: "Hello", Visibility: SnippetPrivate})
+	require.NoError(t, err)
+
+	got, err := db.GetByName(ctx, s.Name)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", got.Title)
+	assert.Equal(t, SnippetPrivate, got.Visibility)
+}
+
+func snippetsList(t *testing.T, db *snippets) {
+	ctx := context.Background()
+
+	for _, opts := range []struct {
+		ownerID    int64
+		visibility SnippetVisibility
+	}{
+		{1, SnippetPublic},
+		{1, SnippetSecret},
+		{1, SnippetPrivate},
+		{2, SnippetPublic},
+	} {
+		_, err := db.Create(ctx, opts.ownerID, opts.ownerID, CreateSnippetOptions{Visibility: opts.visibility})
+		require.NoError(t, err)
+	}
+
+	got, count, err := db.List(ctx, ListSnippetsOptions{Visibilities: []SnippetVisibility{SnippetPublic}, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	require.Len(t, got, 2)
+	assert.Equal(t, int64(2), got[0].OwnerID)
+
+	got, count, err = db.List(ctx, ListSnippetsOptions{OwnerID: 1, PageSize: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	require.Len(t, got, 2)
+	assert.Equal(t, SnippetPrivate, got[0].Visibility)
+
+	got, _, err = db.List(ctx, ListSnippetsOptions{OwnerID: 1, Page: 2, PageSize: 2})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, SnippetPublic, got[0].Visibility)
+}
+
+func snippetsUpdate(t *testing.T, db *snippets) {
+	ctx := context.Background()
+
+	s, err := db.Create(ctx, 1, 1, CreateSnippetOptions{Title: "Old", Visibility: SnippetPublic})
+	require.NoError(t, err)
+
+	err = db.Update(ctx, s.ID, UpdateSnippetOptions{Title: "New", Visibility: SnippetSecret})
+	require.NoError(t, err)
+
+	got, err := db.GetByName(ctx, s.Name)
+	require.NoError(t, err)
+	assert.Equal(t, "New", got.Title)
+	assert.Equal(t, SnippetSecret, got.Visibility)
+
+	err = db.Update(ctx, s.ID, UpdateSnippetOptions{Title: "New", Visibility: ""})
+	assert.True(t, IsErrSnippetInvalid(err))
+}
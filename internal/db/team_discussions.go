@@ -0,0 +1,230 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"gogs.io/gogs/internal/errutil"
+)
+
+// TeamDiscussionsStore is the persistent interface for discussions of teams.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type TeamDiscussionsStore interface {
+	// CountThreads returns the number of discussion threads of the team.
+	CountThreads(ctx context.Context, teamID int64) (int64, error)
+	// CreateReply creates a new reply to the discussion thread with given ID of
+	// the team. It returns ErrTeamDiscussionNotExist when the thread does not
+	// exist.
+	CreateReply(ctx context.Context, teamID, threadID, posterID int64, content string) (*TeamDiscussion, error)
+	// CreateThread creates a new discussion thread for the team.
+	CreateThread(ctx context.Context, teamID, orgID, posterID int64, title, content string) (*TeamDiscussion, error)
+	// DeleteByID deletes the discussion with given ID of the team. Replies are
+	// deleted along with the thread.
+	DeleteByID(ctx context.Context, teamID, id int64) error
+	// GetByID returns the discussion with given ID of the team. It returns
+	// ErrTeamDiscussionNotExist when not found.
+	GetByID(ctx context.Context, teamID, id int64) (*TeamDiscussion, error)
+	// ListReplies returns all replies to the discussion thread with given ID of
+	// the team, ordered by the creation time.
+	ListReplies(ctx context.Context, teamID, threadID int64) ([]*TeamDiscussion, error)
+	// ListThreads returns discussion threads of the team in given page, ordered
+	// by the latest activity.
+	ListThreads(ctx context.Context, teamID int64, page, pageSize int) ([]*TeamDiscussion, error)
+}
+
+var TeamDiscussions TeamDiscussionsStore
+
+// TeamDiscussion is either a discussion thread of a team or a reply to the
+// thread.
+type TeamDiscussion struct {
+	ID    int64 `gorm:"primaryKey"`
+	OrgID int64 `gorm:"index;not null"`
+	// TeamID is the ID of the team the discussion belongs to.
+	TeamID int64 `gorm:"index;not null"`
+	// ThreadID is the ID of the thread for replies, and 0 for threads.
+	ThreadID   int64     `gorm:"index;not null"`
+	PosterID   int64     `gorm:"not null"`
+	Poster     *User     `gorm:"-" json:"-"`
+	Title      string    `gorm:"not null"` // Empty for replies.
+	Content    string    `gorm:"type:TEXT;not null"`
+	NumReplies int       `gorm:"not null"`
+	CreatedAt  time.Time `gorm:"not null"`
+	// UpdatedAt is the time of the latest activity, i.e. the time of the latest
+	// reply for threads.
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+// IsThread returns true if the discussion is a thread rather than a reply.
+func (d *TeamDiscussion) IsThread() bool {
+	return d.ThreadID == 0
+}
+
+// LoadPoster loads the poster of the discussion. The poster is set to a ghost
+// user when the account no longer exists.
+func (d *TeamDiscussion) LoadPoster(ctx context.Context) error {
+	if d.Poster != nil {
+		return nil
+	}
+
+	var err error
+	d.Poster, err = Users.GetByID(ctx, d.PosterID)
+	if err != nil {
+		if !IsErrUserNotExist(err) {
+			return errors.Wrap(err, "get poster")
+		}
+		d.PosterID = -1
+		d.Poster = NewGhostUser()
+	}
+	return nil
+}
+
+var _ TeamDiscussionsStore = (*teamDiscussions)(nil)
+
+type teamDiscussions struct {
+	*gorm.DB
+}
+
+// NewTeamDiscussionsStore returns a persistent interface for discussions of
+// teams with given database connection.
+func NewTeamDiscussionsStore(db *gorm.DB) TeamDiscussionsStore {
+	return &teamDiscussions{DB: db}
+}
+
+func (db *teamDiscussions) CountThreads(ctx context.Context, teamID int64) (int64, error) {
+	var count int64
+	return count, db.WithContext(ctx).
+		Model(new(TeamDiscussion)).
+		Where("team_id = ? AND thread_id = ?", teamID, 0).
+		Count(&count).
+		Error
+}
+
+func (db *teamDiscussions) CreateReply(ctx context.Context, teamID, threadID, posterID int64, content string) (*TeamDiscussion, error) {
+	thread, err := db.GetByID(ctx, teamID, threadID)
+	if err != nil {
+		return nil, err
+	} else if !thread.IsThread() {
+		return nil, ErrTeamDiscussionNotExist{args: errutil.Args{"teamID": teamID, "id": threadID}}
+	}
+
+	reply := &TeamDiscussion{
+		OrgID:    thread.OrgID,
+		TeamID:   teamID,
+		ThreadID: threadID,
+		PosterID: posterID,
+		Content:  content,
+	}
+	return reply, db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(reply).Error
+		if err != nil {
+			return errors.Wrap(err, "create reply")
+		}
+
+		return tx.Model(new(TeamDiscussion)).
+			Where("id = ?", threadID).
+			UpdateColumns(map[string]interface{}{
+				"num_replies": gorm.Expr("num_replies + 1"),
+				"updated_at":  reply.CreatedAt,
+			}).
+			Error
+	})
+}
+
+func (db *teamDiscussions) CreateThread(ctx context.Context, teamID, orgID, posterID int64, title, content string) (*TeamDiscussion, error) {
+	thread := &TeamDiscussion{
+		OrgID:    orgID,
+		TeamID:   teamID,
+		PosterID: posterID,
+		Title:    title,
+		Content:  content,
+	}
+	return thread, db.WithContext(ctx).Create(thread).Error
+}
+
+func (db *teamDiscussions) DeleteByID(ctx context.Context, teamID, id int64) error {
+	d, err := db.GetByID(ctx, teamID, id)
+	if err != nil {
+		if IsErrTeamDiscussionNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if d.IsThread() {
+			err := tx.Where("team_id = ? AND thread_id = ?", teamID, d.ID).Delete(new(TeamDiscussion)).Error
+			if err != nil {
+				return errors.Wrap(err, "delete replies")
+			}
+		} else {
+			err := tx.Model(new(TeamDiscussion)).
+				Where("id = ?", d.ThreadID).
+				UpdateColumn("num_replies", gorm.Expr("num_replies - 1")).
+				Error
+			if err != nil {
+				return errors.Wrap(err, "decrease number of replies")
+			}
+		}
+		return tx.Delete(d).Error
+	})
+}
+
+var _ errutil.NotFound = (*ErrTeamDiscussionNotExist)(nil)
+
+type ErrTeamDiscussionNotExist struct {
+	args errutil.Args
+}
+
+func IsErrTeamDiscussionNotExist(err error) bool {
+	_, ok := err.(ErrTeamDiscussionNotExist)
+	return ok
+}
+
+func (err ErrTeamDiscussionNotExist) Error() string {
+	return fmt.Sprintf("team discussion does not exist: %v", err.args)
+}
+
+func (ErrTeamDiscussionNotExist) NotFound() bool {
+	return true
+}
+
+func (db *teamDiscussions) GetByID(ctx context.Context, teamID, id int64) (*TeamDiscussion, error) {
+	d := new(TeamDiscussion)
+	err := db.WithContext(ctx).Where("id = ? AND team_id = ?", id, teamID).First(d).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrTeamDiscussionNotExist{args: errutil.Args{"teamID": teamID, "id": id}}
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+func (db *teamDiscussions) ListReplies(ctx context.Context, teamID, threadID int64) ([]*TeamDiscussion, error) {
+	var replies []*TeamDiscussion
+	return replies, db.WithContext(ctx).
+		Where("team_id = ? AND thread_id = ?", teamID, threadID).
+		Order("id ASC").
+		Find(&replies).
+		Error
+}
+
+func (db *teamDiscussions) ListThreads(ctx context.Context, teamID int64, page, pageSize int) ([]*TeamDiscussion, error) {
+	var threads []*TeamDiscussion
+	return threads, db.WithContext(ctx).
+		Where("team_id = ? AND thread_id = ?", teamID, 0).
+		Order("updated_at DESC").
+		Order("id DESC").
+		Limit(pageSize).Offset((page - 1) * pageSize).
+		Find(&threads).
+		Error
+}
@@ -0,0 +1,195 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+	"gogs.io/gogs/internal/errutil"
+)
+
+func TestTeamDiscussions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(TeamDiscussion)}
+	db := &teamDiscussions{
+		DB: dbtest.NewDB(t, "teamDiscussions", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *teamDiscussions)
+	}{
+		{"CountThreads", teamDiscussionsCountThreads},
+		{"CreateReply", teamDiscussionsCreateReply},
+		{"CreateThread", teamDiscussionsCreateThread},
+		{"DeleteByID", teamDiscussionsDeleteByID},
+		{"GetByID", teamDiscussionsGetByID},
+		{"ListReplies", teamDiscussionsListReplies},
+		{"ListThreads", teamDiscussionsListThreads},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func teamDiscussionsCountThreads(t *testing.T, db *teamDiscussions) {
+	ctx := context.Background()
+
+	thread, err := db.CreateThread(ctx, 1, 1, 1, "title", "content")
+	require.NoError(t, err)
+	_, err = db.CreateReply(ctx, 1, thread.ID, 2, "reply")
+	require.NoError(t, err)
+	_, err = db.CreateThread(ctx, 2, 1, 1, "title", "content")
+	require.NoError(t, err)
+
+	count, err := db.CountThreads(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func teamDiscussionsCreateReply(t *testing.T, db *teamDiscussions) {
+	ctx := context.Background()
+
+	thread, err := db.CreateThread(ctx, 1, 1, 1, "title", "content")
+	require.NoError(t, err)
+
+	reply, err := db.CreateReply(ctx, 1, thread.ID, 2, "reply")
+	require.NoError(t, err)
+	assert.False(t, reply.IsThread())
+	assert.Equal(t, thread.OrgID, reply.OrgID)
+
+	got, err := db.GetByID(ctx, 1, thread.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.NumReplies)
+	assert.Equal(t, reply.CreatedAt.Unix(), got.UpdatedAt.Unix())
+
+	// Replying to a thread of another team is not allowed.
+	_, err = db.CreateReply(ctx, 2, thread.ID, 2, "reply")
+	wantErr := ErrTeamDiscussionNotExist{args: errutil.Args{"teamID": int64(2), "id": thread.ID}}
+	assert.Equal(t, wantErr, err)
+
+	// Replying to a reply is not allowed.
+	_, err = db.CreateReply(ctx, 1, reply.ID, 2, "reply")
+	wantErr = ErrTeamDiscussionNotExist{args: errutil.Args{"teamID": int64(1), "id": reply.ID}}
+	assert.Equal(t, wantErr, err)
+}
+
+func teamDiscussionsCreateThread(t *testing.T, db *teamDiscussions) {
+	ctx := context.Background()
+
+	thread, err := db.CreateThread(ctx, 1, 2, 3, "title", "content")
+	require.NoError(t, err)
+	assert.True(t, thread.IsThread())
+
+	got, err := db.GetByID(ctx, 1, thread.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), got.OrgID)
+	assert.Equal(t, int64(3), got.PosterID)
+	assert.Equal(t, "title", got.Title)
+	assert.Equal(t, "content", got.Content)
+}
+
+func teamDiscussionsDeleteByID(t *testing.T, db *teamDiscussions) {
+	ctx := context.Background()
+
+	thread, err := db.CreateThread(ctx, 1, 1, 1, "title", "content")
+	require.NoError(t, err)
+	reply1, err := db.CreateReply(ctx, 1, thread.ID, 2, "reply 1")
+	require.NoError(t, err)
+	reply2, err := db.CreateReply(ctx, 1, thread.ID, 2, "reply 2")
+	require.NoError(t, err)
+
+	// Deleting a reply decreases the number of replies of the thread.
+	err = db.DeleteByID(ctx, 1, reply1.ID)
+	require.NoError(t, err)
+	got, err := db.GetByID(ctx, 1, thread.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.NumReplies)
+
+	// Deleting a thread deletes its replies.
+	err = db.DeleteByID(ctx, 1, thread.ID)
+	require.NoError(t, err)
+	_, err = db.GetByID(ctx, 1, reply2.ID)
+	assert.True(t, IsErrTeamDiscussionNotExist(err))
+
+	// Deleting a non-existent discussion should be a no-op.
+	err = db.DeleteByID(ctx, 1, thread.ID)
+	require.NoError(t, err)
+}
+
+func teamDiscussionsGetByID(t *testing.T, db *teamDiscussions) {
+	ctx := context.Background()
+
+	thread, err := db.CreateThread(ctx, 1, 1, 1, "title", "content")
+	require.NoError(t, err)
+
+	_, err = db.GetByID(ctx, 2, thread.ID)
+	wantErr := ErrTeamDiscussionNotExist{args: errutil.Args{"teamID": int64(2), "id": thread.ID}}
+	assert.Equal(t, wantErr, err)
+}
+
+func teamDiscussionsListReplies(t *testing.T, db *teamDiscussions) {
+	ctx := context.Background()
+
+	thread, err := db.CreateThread(ctx, 1, 1, 1, "title", "content")
+	require.NoError(t, err)
+	_, err = db.CreateReply(ctx, 1, thread.ID, 2, "reply 1")
+	require.NoError(t, err)
+	_, err = db.CreateReply(ctx, 1, thread.ID, 3, "reply 2")
+	require.NoError(t, err)
+
+	replies, err := db.ListReplies(ctx, 1, thread.ID)
+	require.NoError(t, err)
+	require.Len(t, replies, 2)
+	assert.Equal(t, "reply 1", replies[0].Content)
+	assert.Equal(t, "reply 2", replies[1].Content)
+}
+
+func teamDiscussionsListThreads(t *testing.T, db *teamDiscussions) {
+	ctx := context.Background()
+
+	thread1, err := db.CreateThread(ctx, 1, 1, 1, "thread 1", "content")
+	require.NoError(t, err)
+	thread2, err := db.CreateThread(ctx, 1, 1, 1, "thread 2", "content")
+	require.NoError(t, err)
+	_, err = db.CreateThread(ctx, 2, 1, 1, "thread 3", "content")
+	require.NoError(t, err)
+
+	// Bump the first thread to be the latest active one.
+	err = db.Model(new(TeamDiscussion)).
+		Where("id = ?", thread1.ID).
+		UpdateColumn("updated_at", thread2.UpdatedAt.Add(time.Minute)).
+		Error
+	require.NoError(t, err)
+
+	threads, err := db.ListThreads(ctx, 1, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, threads, 2)
+	assert.Equal(t, "thread 1", threads[0].Title)
+	assert.Equal(t, "thread 2", threads[1].Title)
+
+	threads, err = db.ListThreads(ctx, 1, 2, 1)
+	require.NoError(t, err)
+	require.Len(t, threads, 1)
+	assert.Equal(t, "thread 2", threads[0].Title)
+}
@@ -12,6 +12,9 @@ import (
 
 	"github.com/gogs/git-module"
 	"github.com/pkg/errors"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/gitutil"
 )
 
 // CommitToPushCommit transforms a git.Commit to PushCommit type.
@@ -101,6 +104,13 @@ func PushUpdate(opts PushUpdateOptions) (err error) {
 		if err != nil {
 			return errors.Wrap(err, "create action for push tag")
 		}
+
+		if conf.Release.AutoCreateForTags && repo.EnableReleases && !isDelRef {
+			tagName := strings.TrimPrefix(opts.FullRefspec, git.RefsTags)
+			if err = NewReleaseForTag(gitRepo, repo, opts.PusherID, tagName); err != nil {
+				return errors.Wrap(err, "create release for tag")
+			}
+		}
 		return nil
 	}
 
@@ -141,5 +151,77 @@ func PushUpdate(opts PushUpdateOptions) (err error) {
 	if err != nil {
 		return errors.Wrap(err, "create action for commit push")
 	}
+
+	if !isDelRef {
+		branch := git.RefShortName(opts.FullRefspec)
+		if err = IndexCommitMessages(ctx, repo.ID, repoPath, branch, opts.OldCommitID, opts.NewCommitID); err != nil {
+			return errors.Wrap(err, "index commit messages")
+		}
+	}
+
+	if !isNewRef && !isDelRef {
+		isForce, err := gitutil.IsForcePush(repoPath, opts.OldCommitID, opts.NewCommitID)
+		if err != nil {
+			return errors.Wrap(err, "detect force push")
+		}
+		if isForce {
+			branch := git.RefShortName(opts.FullRefspec)
+			if err = createForcePushComments(opts.PusherID, repo.ID, branch, opts.OldCommitID, opts.NewCommitID); err != nil {
+				return errors.Wrap(err, "create force push comments")
+			}
+		}
+	}
+
+	if git.RefShortName(opts.FullRefspec) == repo.DefaultBranch {
+		if err = repo.DetectLicense(); err != nil {
+			return errors.Wrap(err, "detect license")
+		}
+		if err = repo.UpdateDependencies(ctx); err != nil {
+			return errors.Wrap(err, "update dependencies")
+		}
+	}
 	return nil
 }
+
+// IndexCommitMessages adds commits pushed to the branch of the repository in
+// given path to the index of commit messages. All branches are indexed when
+// the repository has not been indexed yet, e.g. repositories created before the
+// index was introduced.
+func IndexCommitMessages(ctx context.Context, repoID int64, repoPath, branch, oldCommitID, newCommitID string) error {
+	count, err := CommitMessages.Count(ctx, repoID)
+	if err != nil {
+		return errors.Wrap(err, "count indexed commits")
+	}
+
+	var revs []string
+	switch {
+	case count == 0:
+		revs = []string{"--branches"}
+	case oldCommitID == "" || strings.HasPrefix(oldCommitID, git.EmptyID):
+		// Commits of a new branch that are reachable from other branches have
+		// already been indexed.
+		revs = []string{newCommitID, "--not", "--exclude=" + branch, "--branches"}
+	default:
+		revs = []string{newCommitID, "^" + oldCommitID}
+	}
+
+	entries, err := gitutil.Log(repoPath, revs...)
+	if err != nil {
+		return errors.Wrap(err, "list commits")
+	}
+
+	commits := make([]*CommitMessage, len(entries))
+	for i, e := range entries {
+		commits[i] = &CommitMessage{
+			SHA:            e.CommitID,
+			AuthorName:     e.AuthorName,
+			AuthorEmail:    e.AuthorEmail,
+			AuthoredAt:     e.AuthoredAt,
+			CommitterName:  e.CommitterName,
+			CommitterEmail: e.CommitterEmail,
+			CommittedAt:    e.CommittedAt,
+			Message:        e.Message,
+		}
+	}
+	return CommitMessages.Index(ctx, repoID, commits)
+}
@@ -0,0 +1,341 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/email"
+	"gogs.io/gogs/internal/tool"
+)
+
+// UsageReportsStore is the persistent interface for usage reports of the
+// instance.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type UsageReportsStore interface {
+	// ListByOwner returns usage of all owners that have repositories, with
+	// bandwidth and activity since the given time. Sizes of attachments are not
+	// included since they are only known to the file system.
+	ListByOwner(ctx context.Context, since time.Time) ([]*OwnerUsage, error)
+}
+
+var UsageReports UsageReportsStore
+
+// OwnerUsage is the usage of the instance by a user or an organization and
+// their repositories.
+type OwnerUsage struct {
+	OwnerID        int64
+	OwnerName      string
+	IsOrganization bool
+	NumRepos       int64
+	// GitSize is the size of Git repositories in bytes.
+	GitSize        int64
+	LFSSize        int64
+	AttachmentSize int64
+	// BytesSent is the number of bytes sent to clients by clones and fetches,
+	// which is only known when Git access logs are saved to the database.
+	BytesSent int64
+	// BytesReceived is the number of bytes received from clients by pushes,
+	// which is only known when Git access logs are saved to the database.
+	BytesReceived int64
+	// NumActions is the number of activities happened in repositories.
+	NumActions int64
+}
+
+// DiskUsage returns the total disk usage in bytes.
+func (u *OwnerUsage) DiskUsage() int64 {
+	return u.GitSize + u.LFSSize + u.AttachmentSize
+}
+
+var _ UsageReportsStore = (*usageReports)(nil)
+
+type usageReports struct {
+	*gorm.DB
+}
+
+// NewUsageReportsStore returns a persistent interface for usage reports of the
+// instance with given database connection.
+func NewUsageReportsStore(db *gorm.DB) UsageReportsStore {
+	return &usageReports{DB: db}
+}
+
+func (db *usageReports) ListByOwner(ctx context.Context, since time.Time) ([]*OwnerUsage, error) {
+	tx := db.WithContext(ctx)
+
+	var usages []*OwnerUsage
+	err := tx.Model(new(Repository)).
+		Select("owner_id, COUNT(*) AS num_repos, SUM(size) AS git_size").
+		Group("owner_id").
+		Scan(&usages).
+		Error
+	if err != nil {
+		return nil, errors.Wrap(err, "sum repository sizes")
+	}
+	if len(usages) == 0 {
+		return usages, nil
+	}
+
+	byOwner := make(map[int64]*OwnerUsage, len(usages))
+	ownerIDs := make([]int64, 0, len(usages))
+	for _, u := range usages {
+		byOwner[u.OwnerID] = u
+		ownerIDs = append(ownerIDs, u.OwnerID)
+	}
+
+	var owners []*User
+	err = tx.Select("id, name, type").Where("id IN ?", ownerIDs).Find(&owners).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "list owners")
+	}
+	for _, owner := range owners {
+		byOwner[owner.ID].OwnerName = owner.Name
+		byOwner[owner.ID].IsOrganization = owner.IsOrganization()
+	}
+
+	var lfsSizes []*OwnerUsage
+	err = tx.Model(new(LFSObject)).
+		Select("repository.owner_id, SUM(lfs_object.size) AS lfs_size").
+		Joins("JOIN repository ON repository.id = lfs_object.repo_id").
+		Group("repository.owner_id").
+		Scan(&lfsSizes).
+		Error
+	if err != nil {
+		return nil, errors.Wrap(err, "sum LFS object sizes")
+	}
+	for _, s := range lfsSizes {
+		if u := byOwner[s.OwnerID]; u != nil {
+			u.LFSSize = s.LFSSize
+		}
+	}
+
+	var bandwidths []*OwnerUsage
+	err = tx.Model(new(GitAccessLog)).
+		Select(`repository.owner_id,
+SUM(CASE WHEN git_access_log.operation = ? THEN 0 ELSE git_access_log.bytes END) AS bytes_sent,
+SUM(CASE WHEN git_access_log.operation = ? THEN git_access_log.bytes ELSE 0 END) AS bytes_received`,
+			GitAccessPush, GitAccessPush,
+		).
+		Joins("JOIN repository ON repository.id = git_access_log.repo_id").
+		Where("git_access_log.created_at >= ?", since).
+		Group("repository.owner_id").
+		Scan(&bandwidths).
+		Error
+	if err != nil {
+		return nil, errors.Wrap(err, "sum Git access bytes")
+	}
+	for _, b := range bandwidths {
+		if u := byOwner[b.OwnerID]; u != nil {
+			u.BytesSent = b.BytesSent
+			u.BytesReceived = b.BytesReceived
+		}
+	}
+
+	// Actions are fanned out to watchers, only the copy of the actor is counted
+	// to have one per activity.
+	var activities []*OwnerUsage
+	err = tx.Model(new(Action)).
+		Select("repository.owner_id, COUNT(*) AS num_actions").
+		Joins("JOIN repository ON repository.id = action.repo_id").
+		Where("action.user_id = action.act_user_id AND action.created_unix >= ?", since.Unix()).
+		Group("repository.owner_id").
+		Scan(&activities).
+		Error
+	if err != nil {
+		return nil, errors.Wrap(err, "count actions")
+	}
+	for _, a := range activities {
+		if u := byOwner[a.OwnerID]; u != nil {
+			u.NumActions = a.NumActions
+		}
+	}
+
+	return usages, nil
+}
+
+// attachmentSizesByOwner returns total sizes of attachments of issues and
+// releases grouped by owners of repositories. Attachments that are missing in
+// the file system are ignored.
+func attachmentSizesByOwner() (map[int64]int64, error) {
+	type attachmentOwner struct {
+		UUID    string `xorm:"uuid"`
+		OwnerID int64
+	}
+
+	var owners []*attachmentOwner
+	for _, join := range []struct {
+		table, cond string
+	}{
+		{"issue", "issue.id = attachment.issue_id"},
+		{"release", "release.id = attachment.release_id"},
+	} {
+		err := x.Table("attachment").
+			Select("attachment.uuid, repository.owner_id").
+			Join("INNER", join.table, join.cond).
+			Join("INNER", "repository", "repository.id = "+join.table+".repo_id").
+			Find(&owners)
+		if err != nil {
+			return nil, errors.Wrapf(err, "list attachments of %s", join.table)
+		}
+	}
+
+	sizes := make(map[int64]int64)
+	for _, o := range owners {
+		fi, err := os.Stat(AttachmentLocalPath(o.UUID))
+		if err != nil {
+			continue
+		}
+		sizes[o.OwnerID] += fi.Size()
+	}
+	return sizes, nil
+}
+
+// GetUsageReport returns usage of all owners that have repositories, with
+// bandwidth and activity since the given time. Owners are sorted by disk usage
+// in descending order.
+func GetUsageReport(since time.Time) ([]*OwnerUsage, error) {
+	usages, err := UsageReports.ListByOwner(context.Background(), since)
+	if err != nil {
+		return nil, err
+	}
+
+	attachmentSizes, err := attachmentSizesByOwner()
+	if err != nil {
+		return nil, errors.Wrap(err, "sum attachment sizes")
+	}
+	for _, u := range usages {
+		u.AttachmentSize = attachmentSizes[u.OwnerID]
+	}
+
+	sortOwnerUsages(usages)
+	return usages, nil
+}
+
+func sortOwnerUsages(usages []*OwnerUsage) {
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].DiskUsage() != usages[j].DiskUsage() {
+			return usages[i].DiskUsage() > usages[j].DiskUsage()
+		}
+		return usages[i].OwnerName < usages[j].OwnerName
+	})
+}
+
+// WriteUsageReportCSV writes the usage report in CSV format to w.
+func WriteUsageReportCSV(w io.Writer, usages []*OwnerUsage) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{
+		"owner", "type", "repositories",
+		"git_bytes", "lfs_bytes", "attachment_bytes", "disk_bytes",
+		"bytes_sent", "bytes_received", "activities",
+	})
+	if err != nil {
+		return err
+	}
+
+	itoa := func(i int64) string { return strconv.FormatInt(i, 10) }
+	for _, u := range usages {
+		tp := "user"
+		if u.IsOrganization {
+			tp = "organization"
+		}
+		err = cw.Write([]string{
+			u.OwnerName,
+			tp,
+			itoa(u.NumRepos),
+			itoa(u.GitSize),
+			itoa(u.LFSSize),
+			itoa(u.AttachmentSize),
+			itoa(u.DiskUsage()),
+			itoa(u.BytesSent),
+			itoa(u.BytesReceived),
+			itoa(u.NumActions),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// maxUsageReportMailOwners is the maximum number of owners to be listed in the
+// body of usage report emails, the full report is attached as a CSV file.
+const maxUsageReportMailOwners = 10
+
+// SendUsageReport sends the usage report of the configured period to the
+// configured recipients, or to all site admins if not configured.
+func SendUsageReport() {
+	if taskStatusTable.IsRunning(_SEND_USAGE_REPORT) {
+		return
+	}
+	taskStatusTable.Start(_SEND_USAGE_REPORT)
+	defer taskStatusTable.Stop(_SEND_USAGE_REPORT)
+
+	log.Trace("Doing: SendUsageReport")
+
+	if !conf.Email.Enabled {
+		log.Warn("Usage report is not sent because email service is disabled")
+		return
+	}
+
+	tos := conf.Cron.UsageReport.Recipients
+	if len(tos) == 0 {
+		admins := make([]*User, 0, 5)
+		err := x.Where("is_admin = ?", true).And("is_active = ?", true).Find(&admins)
+		if err != nil {
+			log.Error("Failed to list site admins: %v", err)
+			return
+		}
+		for _, admin := range admins {
+			tos = append(tos, admin.Email)
+		}
+	}
+	if len(tos) == 0 {
+		return
+	}
+
+	until := time.Now()
+	since := until.Add(-conf.Cron.UsageReport.Period)
+	usages, err := GetUsageReport(since)
+	if err != nil {
+		log.Error("Failed to get usage report: %v", err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err = WriteUsageReportCSV(&buf, usages); err != nil {
+		log.Error("Failed to write usage report: %v", err)
+		return
+	}
+
+	top := usages
+	if len(top) > maxUsageReportMailOwners {
+		top = top[:maxUsageReportMailOwners]
+	}
+	owners := make([]email.UsageReportOwner, len(top))
+	for i, u := range top {
+		owners[i] = email.UsageReportOwner{
+			Name:          u.OwnerName,
+			NumRepos:      u.NumRepos,
+			DiskUsage:     tool.FileSize(u.DiskUsage()),
+			BytesSent:     tool.FileSize(u.BytesSent),
+			BytesReceived: tool.FileSize(u.BytesReceived),
+			NumActions:    u.NumActions,
+		}
+	}
+	email.SendUsageReportMail(tos, since, until, owners, buf.Bytes(), conf.Server.ExternalURL+"admin/usage")
+}
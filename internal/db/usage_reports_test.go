@@ -0,0 +1,123 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+func TestUsageReports(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(Action), new(GitAccessLog), new(LFSObject), new(Repository), new(User)}
+	db := &usageReports{
+		DB: dbtest.NewDB(t, "usageReports", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *usageReports)
+	}{
+		{"ListByOwner", usageReportsListByOwner},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func usageReportsListByOwner(t *testing.T, db *usageReports) {
+	ctx := context.Background()
+
+	usages, err := db.ListByOwner(ctx, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, usages)
+
+	now := time.Now().Truncate(time.Second)
+	for _, v := range []interface{}{
+		&User{ID: 1, LowerName: "alice", Name: "alice"},
+		&User{ID: 2, LowerName: "acme", Name: "acme", Type: UserOrganization},
+		&User{ID: 3, LowerName: "bob", Name: "bob"},
+		&Repository{ID: 1, OwnerID: 1, LowerName: "repo1", Name: "repo1", Size: 100},
+		&Repository{ID: 2, OwnerID: 2, LowerName: "repo2", Name: "repo2", Size: 200},
+		&Repository{ID: 3, OwnerID: 2, LowerName: "repo3", Name: "repo3", Size: 300},
+		&LFSObject{RepoID: 2, OID: "ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f", Size: 1000, Storage: "local"},
+		&LFSObject{RepoID: 3, OID: "ef797c8118f02dfb649607dd5d3f8c7623048c9c063d532cc95c5ed7a898a64f", Size: 2000, Storage: "local"},
+		&GitAccessLog{RepoID: 2, Protocol: "http", Operation: GitAccessClone, Bytes: 10, CreatedAt: now},
+		&GitAccessLog{RepoID: 3, Protocol: "ssh", Operation: GitAccessFetch, Bytes: 20, CreatedAt: now},
+		&GitAccessLog{RepoID: 3, Protocol: "ssh", Operation: GitAccessPush, Bytes: 30, CreatedAt: now},
+		// Out of range.
+		&GitAccessLog{RepoID: 2, Protocol: "http", Operation: GitAccessClone, Bytes: 40, CreatedAt: now.Add(-48 * time.Hour)},
+		// The actor and a watcher of an activity.
+		&Action{UserID: 3, ActUserID: 3, RepoID: 2, CreatedUnix: now.Unix()},
+		&Action{UserID: 1, ActUserID: 3, RepoID: 2, CreatedUnix: now.Unix()},
+		&Action{UserID: 1, ActUserID: 1, RepoID: 1, CreatedUnix: now.Unix()},
+		// Out of range.
+		&Action{UserID: 1, ActUserID: 1, RepoID: 1, CreatedUnix: now.Add(-48 * time.Hour).Unix()},
+	} {
+		err = db.Create(v).Error
+		require.NoError(t, err)
+	}
+
+	usages, err = db.ListByOwner(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	sortOwnerUsages(usages)
+	want := []*OwnerUsage{
+		{
+			OwnerID:        2,
+			OwnerName:      "acme",
+			IsOrganization: true,
+			NumRepos:       2,
+			GitSize:        500,
+			LFSSize:        3000,
+			BytesSent:      30,
+			BytesReceived:  30,
+			NumActions:     1,
+		},
+		{
+			OwnerID:    1,
+			OwnerName:  "alice",
+			NumRepos:   1,
+			GitSize:    100,
+			NumActions: 1,
+		},
+	}
+	assert.Equal(t, want, usages)
+}
+
+func TestWriteUsageReportCSV(t *testing.T) {
+	usages := []*OwnerUsage{
+		{OwnerName: "acme", IsOrganization: true, NumRepos: 2, GitSize: 500, LFSSize: 3000, AttachmentSize: 10, BytesSent: 30, BytesReceived: 20, NumActions: 1},
+		{OwnerName: "alice", NumRepos: 1, GitSize: 100},
+	}
+
+	var buf bytes.Buffer
+	err := WriteUsageReportCSV(&buf, usages)
+	require.NoError(t, err)
+
+	want := `owner,type,repositories,git_bytes,lfs_bytes,attachment_bytes,disk_bytes,bytes_sent,bytes_received,activities
+acme,organization,2,500,3000,10,3510,30,20,1
+alice,user,1,100,0,0,100,0,0,0
+`
+	assert.Equal(t, want, buf.String())
+}
@@ -33,6 +33,8 @@ import (
 	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/db/errors"
 	"gogs.io/gogs/internal/errutil"
+	"gogs.io/gogs/internal/gitutil"
+	"gogs.io/gogs/internal/repoutil"
 	"gogs.io/gogs/internal/strutil"
 	"gogs.io/gogs/internal/tool"
 )
@@ -74,6 +76,12 @@ type User struct {
 
 	// Remember visibility choice for convenience, true for private
 	LastRepoVisibility bool
+	// Whether to use the noreply email address for commits created on the web
+	// and to hide the email address from others.
+	KeepEmailPrivate bool
+	// Whether to reject pushes containing commits that expose the private email
+	// addresses, only effective when KeepEmailPrivate is true.
+	BlockEmailExposingPush bool
 	// Maximum repository creation limit, -1 means use global default
 	MaxRepoCreation int `xorm:"NOT NULL DEFAULT -1" gorm:"not null;default:-1"`
 
@@ -83,6 +91,13 @@ type User struct {
 	AllowGitHook     bool
 	AllowImportLocal bool // Allow migrate repository by local path
 	ProhibitLogin    bool
+	// Whether the user must change the password at the next sign in, set by
+	// admins.
+	MustChangePassword bool
+
+	// SessionEpoch is increased to invalidate all existing sessions of the user,
+	// e.g. after the password is reset.
+	SessionEpoch int64 `xorm:"NOT NULL DEFAULT 0" gorm:"not null;default:0"`
 
 	// Avatar
 	Avatar          string `xorm:"VARCHAR(2048) NOT NULL" gorm:"type:VARCHAR(2048);not null"`
@@ -95,12 +110,56 @@ type User struct {
 	NumStars     int
 	NumRepos     int
 
+	// Tenant is the label set by admins to group users and organizations when
+	// tenant isolation is enabled.
+	Tenant string `xorm:"INDEX" gorm:"index"`
+
 	// For organization
 	Description string
 	NumTeams    int
 	NumMembers  int
 	Teams       []*Team `xorm:"-" gorm:"-" json:"-"`
 	Members     []*User `xorm:"-" gorm:"-" json:"-"`
+
+	// Whether to only allow users with activated email addresses under verified
+	// domains of the organization to become members.
+	RestrictMembersToVerifiedDomains bool
+	// Whether to require members of the organization to enable two-factor
+	// authentication. Members without it are blocked from accessing resources
+	// of the organization once the grace period ends.
+	RequireTwoFactor        bool
+	TwoFactorGraceUntilUnix int64
+	// Announcement is the Markdown content shown to members of the organization
+	// on their dashboards, e.g. freeze windows and onboarding links.
+	Announcement            string `xorm:"TEXT" gorm:"type:TEXT"`
+	AnnouncementUpdatedUnix int64
+	// Whether to protect the default branch of new repositories of the
+	// organization from force pushes and deletion.
+	ProtectDefaultBranch bool
+	// Naming conventions of new repositories of the organization, in addition
+	// to ones of the instance, see RepoNamingConvention.
+	RepoNamePattern     string
+	RepoNamePatternHelp string
+	RequiredRepoTopics  string
+
+	// Theme is the preferred theme of the web interface, empty means to use the
+	// default theme of the instance.
+	Theme string
+
+	// Timezone is the IANA time zone name of the user, empty means the time
+	// zone of the server.
+	Timezone string
+	// Non-urgent email notifications are deferred during quiet hours, from
+	// QuietHoursStart to QuietHoursEnd in the time zone of the user, and during
+	// weekends if QuietWeekends is true.
+	EnableQuietHours bool
+	QuietHoursStart  int
+	QuietHoursEnd    int
+	QuietWeekends    bool
+
+	// ContributionsVisibility is who can view the contributions calendar of the
+	// user, empty means public.
+	ContributionsVisibility string
 }
 
 func (u *User) BeforeInsert() {
@@ -312,11 +371,26 @@ func (u *User) GetFollowing(page int) ([]*User, error) {
 	return users, sess.Find(&users)
 }
 
+// NoReplyEmail returns the noreply email address of the user.
+func (u *User) NoReplyEmail() string {
+	return u.LowerName + "@" + conf.User.NoReplyAddress
+}
+
+// CommitEmail returns the email address to be used for commits created on
+// behalf of the user, which is the noreply email address if the user keeps the
+// email address private.
+func (u *User) CommitEmail() string {
+	if u.KeepEmailPrivate {
+		return u.NoReplyEmail()
+	}
+	return u.Email
+}
+
 // NewGitSig generates and returns the signature of given user.
 func (u *User) NewGitSig() *git.Signature {
 	return &git.Signature{
 		Name:  u.DisplayName(),
-		Email: u.Email,
+		Email: u.CommitEmail(),
 		When:  time.Now(),
 	}
 }
@@ -393,6 +467,31 @@ func (u *User) IsOrganization() bool {
 	return u.Type == UserOrganization
 }
 
+// IsTenantIsolated returns true if the actor and the owner are isolated from each
+// other by tenant isolation, i.e. the actor is only allowed to see the owner and
+// its repositories with explicitly granted access. The actor is nil for
+// anonymous users.
+func IsTenantIsolated(actor, owner *User) bool {
+	if !conf.Security.EnableTenantIsolation {
+		return false
+	} else if actor == nil {
+		return true
+	} else if actor.IsAdmin || actor.ID == owner.ID {
+		return false
+	}
+	return actor.Tenant != owner.Tenant
+}
+
+// IsTenantVisible returns true if the actor is allowed to see the profile of the
+// user or organization under tenant isolation. Members of an organization can
+// always see it regardless of tenants.
+func IsTenantVisible(actor, u *User) bool {
+	if !IsTenantIsolated(actor, u) {
+		return true
+	}
+	return actor != nil && u.IsOrganization() && u.IsOrgMember(actor.ID)
+}
+
 // IsUserOrgOwner returns true if user is in the owner team of given organization.
 func (u *User) IsUserOrgOwner(orgId int64) bool {
 	return IsOrganizationOwner(orgId, u.ID)
@@ -500,9 +599,12 @@ func NewGhostUser() *User {
 	}
 }
 
+// Built-in reserved names and patterns of users and organizations, which can be
+// changed by configuration and admins except protected ones, see
+// reservedNamesOf.
 var (
-	reservedUsernames    = []string{"-", "explore", "create", "assets", "css", "img", "js", "less", "plugins", "debug", "raw", "install", "api", "avatar", "user", "org", "help", "stars", "issues", "pulls", "commits", "repo", "template", "admin", "new", ".", ".."}
-	reservedUserPatterns = []string{"*.keys"}
+	reservedUsernames    = []string{"-", "explore", "snippets", "create", "assets", "css", "img", "js", "less", "plugins", "debug", "raw", "install", "api", "avatar", "user", "login", "org", "help", "stars", "issues", "pulls", "commits", "repo", "template", "admin", "new", "all", ".", ".."}
+	reservedUserPatterns = []string{"*.keys", "*.gpg"}
 )
 
 type ErrNameNotAllowed struct {
@@ -559,7 +661,8 @@ func isNameAllowed(names, patterns []string, name string) error {
 
 // isUsernameAllowed return an error if given name is a reserved name or pattern for users.
 func isUsernameAllowed(name string) error {
-	return isNameAllowed(reservedUsernames, reservedUserPatterns, name)
+	names, patterns := reservedNamesOf(ReservedNameKindUser)
+	return isNameAllowed(names, patterns, name)
 }
 
 // CreateUser creates record of a new user.
@@ -627,6 +730,13 @@ func ListUsers(page, pageSize int) ([]*User, error) {
 	return users, x.Limit(pageSize, (page-1)*pageSize).Where("type=0").Asc("id").Find(&users)
 }
 
+// ListUsersWithOffset returns at most limit users after skipping offset users,
+// ordered by ID.
+func ListUsersWithOffset(offset, limit int) ([]*User, error) {
+	users := make([]*User, 0, limit)
+	return users, x.Limit(limit, offset).Where("type=0").Asc("id").Find(&users)
+}
+
 // parseUserFromCode returns user by username encoded in code.
 // It returns nil if code or username is invalid.
 func parseUserFromCode(code string) (user *User) {
@@ -710,13 +820,18 @@ func ChangeUserName(u *User, newUserName string) (err error) {
 		return fmt.Errorf("delete repository and wiki local copy: %v", err)
 	}
 
-	// Rename or create user base directory
-	baseDir := UserPath(u.Name)
-	newBaseDir := UserPath(newUserName)
-	if com.IsExist(baseDir) {
-		return os.Rename(baseDir, newBaseDir)
+	// Rename user base directories in all repository shards, and create the one
+	// in the primary root.
+	for shard := range repoutil.Roots() {
+		baseDir := repoutil.ShardUserPath(shard, u.Name)
+		if !com.IsExist(baseDir) {
+			continue
+		}
+		if err = os.Rename(baseDir, repoutil.ShardUserPath(shard, newUserName)); err != nil {
+			return err
+		}
 	}
-	return os.MkdirAll(newBaseDir, os.ModePerm)
+	return os.MkdirAll(UserPath(newUserName), os.ModePerm)
 }
 
 func updateUser(e Engine, u *User) error {
@@ -750,6 +865,41 @@ func UpdateUser(u *User) error {
 	return updateUser(x, u)
 }
 
+// ResetUserPassword sets a new password for the user, clears the requirement
+// to change the password and invalidates all existing sessions of the user.
+func ResetUserPassword(u *User, passwd string) (err error) {
+	if u.Rands, err = GetUserSalt(); err != nil {
+		return fmt.Errorf("get user salt: %v", err)
+	}
+	if u.Salt, err = GetUserSalt(); err != nil {
+		return fmt.Errorf("get user salt: %v", err)
+	}
+	u.Passwd = passwd
+	u.EncodePassword()
+	u.MustChangePassword = false
+	u.SessionEpoch++
+	return UpdateUser(u)
+}
+
+// DeactivateUser prohibits the user from signing in and revokes all access
+// tokens of the user, so that credentials issued before can no longer be used
+// for the web, the API or Git operations.
+func DeactivateUser(u *User) (err error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	u.ProhibitLogin = true
+	if _, err = sess.ID(u.ID).Cols("prohibit_login").Update(u); err != nil {
+		return fmt.Errorf("update user: %v", err)
+	} else if _, err = sess.Delete(&AccessToken{UserID: u.ID}); err != nil {
+		return fmt.Errorf("delete access tokens: %v", err)
+	}
+	return sess.Commit()
+}
+
 // deleteBeans deletes all given beans, beans should contain delete conditions.
 func deleteBeans(e Engine, beans ...interface{}) (err error) {
 	for i := range beans {
@@ -851,6 +1001,33 @@ func deleteUser(e *xorm.Session, u *User) error {
 		return fmt.Errorf("clear assignee: %v", err)
 	}
 
+	if _, err = e.Exec("DELETE FROM profile_field_value WHERE user_id = ?", u.ID); err != nil {
+		return fmt.Errorf("delete profile field values: %v", err)
+	}
+	if _, err = e.Exec("DELETE FROM admin_role_assignment WHERE user_id = ?", u.ID); err != nil {
+		return fmt.Errorf("delete admin role assignments: %v", err)
+	}
+	if _, err = e.Exec("DELETE FROM digest_subscription WHERE user_id = ?", u.ID); err != nil {
+		return fmt.Errorf("delete digest subscriptions: %v", err)
+	}
+	if _, err = e.Exec("DELETE FROM password_reset_token WHERE user_id = ?", u.ID); err != nil {
+		return fmt.Errorf("delete password reset tokens: %v", err)
+	}
+	if _, err = e.Exec("DELETE FROM federated_identity WHERE user_id = ?", u.ID); err != nil {
+		return fmt.Errorf("delete federated identity: %v", err)
+	}
+	if _, err = e.Exec("DELETE FROM gpg_key WHERE owner_id = ?", u.ID); err != nil {
+		return fmt.Errorf("delete GPG keys: %v", err)
+	}
+
+	snippetIDs := make([]int64, 0, 10)
+	if err = e.Table("snippet").Cols("id").Where("owner_id = ?", u.ID).Find(&snippetIDs); err != nil {
+		return fmt.Errorf("list snippets: %v", err)
+	}
+	if _, err = e.Exec("DELETE FROM snippet WHERE owner_id = ?", u.ID); err != nil {
+		return fmt.Errorf("delete snippets: %v", err)
+	}
+
 	if _, err = e.ID(u.ID).Delete(new(User)); err != nil {
 		return fmt.Errorf("Delete: %v", err)
 	}
@@ -859,8 +1036,13 @@ func deleteUser(e *xorm.Session, u *User) error {
 	// Note: There are something just cannot be roll back,
 	//	so just keep error logs of those operations.
 
-	_ = os.RemoveAll(UserPath(u.Name))
+	for shard := range repoutil.Roots() {
+		_ = os.RemoveAll(repoutil.ShardUserPath(shard, u.Name))
+	}
 	_ = os.Remove(u.CustomAvatarPath())
+	for _, id := range snippetIDs {
+		_ = os.RemoveAll((&Snippet{ID: id}).RepoPath())
+	}
 
 	return nil
 }
@@ -868,6 +1050,18 @@ func deleteUser(e *xorm.Session, u *User) error {
 // DeleteUser completely and permanently deletes everything of a user,
 // but issues/comments/pulls will be kept and shown as someone has been deleted.
 func DeleteUser(u *User) (err error) {
+	// Repositories in the trash do not prevent the user from being deleted, and
+	// are purged along with the user.
+	count, err := GetRepositoryCount(u)
+	if err != nil {
+		return fmt.Errorf("GetRepositoryCount: %v", err)
+	} else if count > 0 {
+		return ErrUserOwnRepos{UID: u.ID}
+	}
+	if err = purgeTrashedRepositories(u.ID); err != nil {
+		return fmt.Errorf("purge trashed repositories: %v", err)
+	}
+
 	sess := x.NewSession()
 	defer sess.Close()
 	if err = sess.Begin(); err != nil {
@@ -1004,6 +1198,16 @@ func GetUserIDsByNames(names []string) []int64 {
 type UserCommit struct {
 	User *User
 	*git.Commit
+	// CoAuthors is the list of co-authors declared by "Co-authored-by" trailers
+	// of the commit message.
+	CoAuthors []*CommitCoAuthor
+}
+
+// CommitCoAuthor is a co-author of a commit, and the corresponding user if the
+// e-mail matches one.
+type CommitCoAuthor struct {
+	User *User
+	*git.Signature
 }
 
 // ValidateCommitWithEmail checks if author's e-mail of commit is corresponding to a user.
@@ -1015,22 +1219,48 @@ func ValidateCommitWithEmail(c *git.Commit) *User {
 	return u
 }
 
-// ValidateCommitsWithEmails checks if authors' e-mails of commits are corresponding to users.
+// getUserByEmailCached returns the user by given e-mail, and caches the result
+// in emails including nonexistence.
+func getUserByEmailCached(emails map[string]*User, email string) *User {
+	if u, ok := emails[email]; ok {
+		return u
+	}
+	u, _ := GetUserByEmail(email)
+	emails[email] = u
+	return u
+}
+
+func validateCoAuthorsWithEmails(emails map[string]*User, c *git.Commit) []*CommitCoAuthor {
+	sigs := gitutil.CommitCoAuthors(c)
+	if len(sigs) == 0 {
+		return nil
+	}
+
+	coAuthors := make([]*CommitCoAuthor, len(sigs))
+	for i := range sigs {
+		coAuthors[i] = &CommitCoAuthor{
+			User:      getUserByEmailCached(emails, sigs[i].Email),
+			Signature: sigs[i],
+		}
+	}
+	return coAuthors
+}
+
+// ValidateCoAuthorsWithEmails checks if e-mails of co-authors of the commit
+// are corresponding to users.
+func ValidateCoAuthorsWithEmails(c *git.Commit) []*CommitCoAuthor {
+	return validateCoAuthorsWithEmails(make(map[string]*User), c)
+}
+
+// ValidateCommitsWithEmails checks if authors' and co-authors' e-mails of commits are corresponding to users.
 func ValidateCommitsWithEmails(oldCommits []*git.Commit) []*UserCommit {
 	emails := make(map[string]*User)
 	newCommits := make([]*UserCommit, len(oldCommits))
 	for i := range oldCommits {
-		var u *User
-		if v, ok := emails[oldCommits[i].Author.Email]; !ok {
-			u, _ = GetUserByEmail(oldCommits[i].Author.Email)
-			emails[oldCommits[i].Author.Email] = u
-		} else {
-			u = v
-		}
-
 		newCommits[i] = &UserCommit{
-			User:   u,
-			Commit: oldCommits[i],
+			User:      getUserByEmailCached(emails, oldCommits[i].Author.Email),
+			Commit:    oldCommits[i],
+			CoAuthors: validateCoAuthorsWithEmails(emails, oldCommits[i]),
 		}
 	}
 	return newCommits
@@ -1044,6 +1274,12 @@ func GetUserByEmail(email string) (*User, error) {
 	}
 
 	email = strings.ToLower(email)
+
+	// Noreply email addresses are mapped to users by their usernames.
+	if name := strings.TrimSuffix(email, "@"+strings.ToLower(conf.User.NoReplyAddress)); name != email {
+		return GetUserByName(name)
+	}
+
 	// First try to find the user by primary email
 	user := &User{Email: email}
 	has, err := x.Get(user)
@@ -1073,6 +1309,10 @@ type SearchUserOptions struct {
 	OrderBy  string
 	Page     int
 	PageSize int // Can be smaller than or equal to setting.UI.ExplorePagingNum
+
+	// When set, only users in the Tenant are included.
+	IsolateTenant bool
+	Tenant        string
 }
 
 // SearchUserByName takes keyword and part of user name to search,
@@ -1096,6 +1336,9 @@ func SearchUserByName(opts *SearchUserOptions) (users []*User, _ int64, _ error)
 	sess := x.Where("LOWER(lower_name) LIKE ?", searchQuery).
 		Or("LOWER(full_name) LIKE ?", searchQuery).
 		And("type = ?", opts.Type)
+	if opts.IsolateTenant {
+		sess.And("tenant = ?", opts.Tenant)
+	}
 
 	countSess := *sess
 	count, err := countSess.Count(new(User))
@@ -1208,7 +1451,7 @@ func (u *User) GetRepositoryAccesses() (map[*Repository]AccessMode, error) {
 // GetAccessibleRepositories finds repositories which the user has access but does not own.
 // If limit is smaller than 1 means returns all found results.
 func (user *User) GetAccessibleRepositories(limit int) (repos []*Repository, _ error) {
-	sess := x.Where("owner_id !=? ", user.ID).Desc("updated_unix")
+	sess := x.Where("owner_id !=? ", user.ID).And("repository.trashed_unix = 0").Desc("updated_unix")
 	if limit > 0 {
 		sess.Limit(limit)
 		repos = make([]*Repository, 0, limit)
@@ -0,0 +1,383 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	log "unknwon.dev/clog/v2"
+)
+
+// UserContributionsStore is the persistent interface for daily contributions
+// of users.
+//
+// NOTE: All methods are sorted in alphabetical order.
+type UserContributionsStore interface {
+	// LatestDate returns the latest date of contributions, or empty if there is
+	// none.
+	LatestDate(ctx context.Context) (string, error)
+	// ListByUser returns daily contributions of the user since the given date,
+	// ordered by date. Contributions to private repositories are only included
+	// when includePrivate is true.
+	ListByUser(ctx context.Context, userID int64, since string, includePrivate bool) ([]*UserContribution, error)
+	// ReplaceSince replaces contributions of all users on and after the given
+	// date.
+	ReplaceSince(ctx context.Context, since string, contributions []*UserContribution) error
+}
+
+var UserContributions UserContributionsStore
+
+// UserContributionDateFormat is the format of dates of contributions, which
+// are in UTC.
+const UserContributionDateFormat = "2006-01-02"
+
+// UserContribution is the number of contributions of a user in a day, to
+// either public or private repositories.
+type UserContribution struct {
+	ID     int64 `gorm:"primaryKey"`
+	UserID int64 `gorm:"uniqueIndex:user_contribution_unique;not null"`
+	// Date is the day of contributions in UTC, in the format of
+	// UserContributionDateFormat.
+	Date         string `gorm:"type:VARCHAR(10);uniqueIndex:user_contribution_unique;index;not null"`
+	IsPrivate    bool   `gorm:"uniqueIndex:user_contribution_unique;not null"`
+	Commits      int64  `gorm:"not null"`
+	Issues       int64  `gorm:"not null"`
+	PullRequests int64  `gorm:"not null"`
+	// Reviews are comments on pull requests of other users.
+	Reviews int64 `gorm:"not null"`
+}
+
+// Total returns the total number of contributions.
+func (c *UserContribution) Total() int64 {
+	return c.Commits + c.Issues + c.PullRequests + c.Reviews
+}
+
+var _ UserContributionsStore = (*userContributions)(nil)
+
+type userContributions struct {
+	*gorm.DB
+}
+
+// NewUserContributionsStore returns a persistent interface for daily
+// contributions of users with given database connection.
+func NewUserContributionsStore(db *gorm.DB) UserContributionsStore {
+	return &userContributions{DB: db}
+}
+
+func (db *userContributions) LatestDate(ctx context.Context) (string, error) {
+	var dates []string
+	err := db.WithContext(ctx).Model(new(UserContribution)).Order("date DESC").Limit(1).Pluck("date", &dates).Error
+	if err != nil || len(dates) == 0 {
+		return "", err
+	}
+	return dates[0], nil
+}
+
+func (db *userContributions) ListByUser(ctx context.Context, userID int64, since string, includePrivate bool) ([]*UserContribution, error) {
+	var contributions []*UserContribution
+	q := db.WithContext(ctx).Where("user_id = ? AND date >= ?", userID, since)
+	if !includePrivate {
+		q = q.Where("is_private = ?", false)
+	}
+	err := q.Order("date ASC").Find(&contributions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Merge contributions to public and private repositories of the same day.
+	merged := contributions[:0]
+	for _, c := range contributions {
+		if n := len(merged); n > 0 && merged[n-1].Date == c.Date {
+			last := merged[n-1]
+			last.Commits += c.Commits
+			last.Issues += c.Issues
+			last.PullRequests += c.PullRequests
+			last.Reviews += c.Reviews
+			continue
+		}
+		c.IsPrivate = false
+		merged = append(merged, c)
+	}
+	return merged, nil
+}
+
+func (db *userContributions) ReplaceSince(ctx context.Context, since string, contributions []*UserContribution) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("date >= ?", since).Delete(new(UserContribution)).Error
+		if err != nil {
+			return errors.Wrap(err, "delete")
+		}
+		if len(contributions) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(contributions, 100).Error
+	})
+}
+
+// Visibility of the contributions calendar of a user.
+const (
+	ContributionsVisibilityPublic    = "public"
+	ContributionsVisibilityFollowers = "followers"
+	ContributionsVisibilityPrivate   = "private"
+)
+
+// IsValidContributionsVisibility returns true if the visibility is valid,
+// empty is the same as public.
+func IsValidContributionsVisibility(visibility string) bool {
+	switch visibility {
+	case "", ContributionsVisibilityPublic, ContributionsVisibilityFollowers, ContributionsVisibilityPrivate:
+		return true
+	}
+	return false
+}
+
+// CanViewContributions returns true if the viewer is allowed to view the
+// contributions calendar of the user. The viewer is nil for anonymous users.
+func (u *User) CanViewContributions(viewer *User) bool {
+	if viewer != nil && (viewer.ID == u.ID || viewer.IsAdmin) {
+		return true
+	}
+
+	switch u.ContributionsVisibility {
+	case ContributionsVisibilityFollowers:
+		return viewer != nil && IsFollowing(viewer.ID, u.ID)
+	case ContributionsVisibilityPrivate:
+		return false
+	default:
+		return true
+	}
+}
+
+// ListContributionsForViewer returns daily contributions of the user since the
+// given date to be shown to the viewer. Contributions to private repositories
+// are only included for the user and site admins.
+func (u *User) ListContributionsForViewer(ctx context.Context, viewer *User, since string) ([]*UserContribution, error) {
+	includePrivate := viewer != nil && (viewer.ID == u.ID || viewer.IsAdmin)
+	return UserContributions.ListByUser(ctx, u.ID, since, includePrivate)
+}
+
+// ContributionCalendarWeeks is the number of weeks shown in contributions
+// calendars, which covers a year.
+const ContributionCalendarWeeks = 53
+
+// ContributionDay is a day of a contributions calendar. Level is from 0 to 4,
+// relative to the busiest day of the calendar.
+type ContributionDay struct {
+	Date  string
+	Count int64
+	Level int
+}
+
+// ContributionCalendar is a calendar of contributions of a user, with weeks
+// starting on Sundays. Days after today are nil.
+type ContributionCalendar struct {
+	Weeks [][]*ContributionDay
+	Total int64
+}
+
+// ContributionCalendarSince returns the first date shown in the contributions
+// calendar ending today.
+func ContributionCalendarSince(today time.Time) string {
+	today = today.UTC()
+	start := today.AddDate(0, 0, -int(today.Weekday())-(ContributionCalendarWeeks-1)*7)
+	return start.Format(UserContributionDateFormat)
+}
+
+// BuildContributionCalendar builds the contributions calendar ending today from
+// daily contributions.
+func BuildContributionCalendar(contributions []*UserContribution, today time.Time) *ContributionCalendar {
+	counts := make(map[string]int64, len(contributions))
+	var max int64
+	calendar := &ContributionCalendar{}
+	for _, c := range contributions {
+		counts[c.Date] += c.Total()
+		calendar.Total += c.Total()
+		if counts[c.Date] > max {
+			max = counts[c.Date]
+		}
+	}
+
+	today = today.UTC()
+	todayDate := today.Format(UserContributionDateFormat)
+	start, _ := time.Parse(UserContributionDateFormat, ContributionCalendarSince(today))
+	calendar.Weeks = make([][]*ContributionDay, ContributionCalendarWeeks)
+	for w := range calendar.Weeks {
+		week := make([]*ContributionDay, 7)
+		for d := range week {
+			date := start.AddDate(0, 0, w*7+d).Format(UserContributionDateFormat)
+			if date > todayDate {
+				break
+			}
+
+			day := &ContributionDay{
+				Date:  date,
+				Count: counts[date],
+			}
+			if day.Count > 0 {
+				day.Level = int((day.Count*4 + max - 1) / max)
+			}
+			week[d] = day
+		}
+		calendar.Weeks[w] = week
+	}
+	return calendar
+}
+
+// contributionKey identifies contributions of a user in a day.
+type contributionKey struct {
+	UserID    int64
+	Date      string
+	IsPrivate bool
+}
+
+// contributionCounter counts contributions of users by day.
+type contributionCounter map[contributionKey]*UserContribution
+
+// get returns the contributions of the user on the day of the given Unix
+// timestamp.
+func (c contributionCounter) get(userID, unix int64, isPrivate bool) *UserContribution {
+	key := contributionKey{
+		UserID:    userID,
+		Date:      time.Unix(unix, 0).UTC().Format(UserContributionDateFormat),
+		IsPrivate: isPrivate,
+	}
+	contribution, ok := c[key]
+	if !ok {
+		contribution = &UserContribution{
+			UserID:    key.UserID,
+			Date:      key.Date,
+			IsPrivate: key.IsPrivate,
+		}
+		c[key] = contribution
+	}
+	return contribution
+}
+
+// list returns contributions ordered by user, date and privacy.
+func (c contributionCounter) list() []*UserContribution {
+	contributions := make([]*UserContribution, 0, len(c))
+	for _, contribution := range c {
+		contributions = append(contributions, contribution)
+	}
+	sort.Slice(contributions, func(i, j int) bool {
+		a, b := contributions[i], contributions[j]
+		if a.UserID != b.UserID {
+			return a.UserID < b.UserID
+		} else if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		return !a.IsPrivate && b.IsPrivate
+	})
+	return contributions
+}
+
+// contributionSample is an issue, pull request or review by a user.
+type contributionSample struct {
+	PosterID    int64 `xorm:"poster_id"`
+	IsPull      bool  `xorm:"is_pull"`
+	IsPrivate   bool  `xorm:"is_private"`
+	CreatedUnix int64 `xorm:"created_unix"`
+}
+
+// countContributions counts commits pushed, issues and pull requests created,
+// and pull requests reviewed by users since the given Unix timestamp.
+func countContributions(since int64) (contributionCounter, error) {
+	counter := make(contributionCounter)
+
+	err := x.Where("op_type = ? AND user_id = act_user_id AND created_unix >= ?", ActionCommitRepo, since).
+		Cols("act_user_id", "is_private", "content", "created_unix").
+		Iterate(new(Action), func(_ int, bean interface{}) error {
+			action := bean.(*Action)
+			var commits PushCommits
+			if err := jsoniter.Unmarshal([]byte(action.Content), &commits); err != nil {
+				log.Trace("Failed to unmarshal commits of action %d: %v", action.ID, err)
+				return nil
+			}
+			counter.get(action.ActUserID, action.CreatedUnix, action.IsPrivate).Commits += int64(commits.Len)
+			return nil
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "count commits")
+	}
+
+	var issues []*contributionSample
+	err = x.Table("issue").
+		Select("issue.poster_id, issue.is_pull, issue.created_unix, repository.is_private").
+		Join("INNER", "repository", "repository.id = issue.repo_id").
+		Where("issue.created_unix >= ?", since).
+		Find(&issues)
+	if err != nil {
+		return nil, errors.Wrap(err, "list issues")
+	}
+	for _, s := range issues {
+		c := counter.get(s.PosterID, s.CreatedUnix, s.IsPrivate)
+		if s.IsPull {
+			c.PullRequests++
+		} else {
+			c.Issues++
+		}
+	}
+
+	var reviews []*contributionSample
+	err = x.Table("comment").
+		Select("comment.poster_id, comment.created_unix, repository.is_private").
+		Join("INNER", "issue", "issue.id = comment.issue_id").
+		Join("INNER", "repository", "repository.id = issue.repo_id").
+		Where("comment.type = ? AND comment.created_unix >= ?", COMMENT_TYPE_COMMENT, since).
+		And("issue.is_pull = ? AND comment.poster_id != issue.poster_id", true).
+		Find(&reviews)
+	if err != nil {
+		return nil, errors.Wrap(err, "list reviews")
+	}
+	for _, s := range reviews {
+		counter.get(s.PosterID, s.CreatedUnix, s.IsPrivate).Reviews++
+	}
+	return counter, nil
+}
+
+// AggregateContributions recomputes daily contributions of all users since the
+// day before the latest aggregated day, or for the whole contributions
+// calendar on the first run.
+func AggregateContributions() {
+	if taskStatusTable.IsRunning(_AGGREGATE_CONTRIBUTIONS) {
+		return
+	}
+	taskStatusTable.Start(_AGGREGATE_CONTRIBUTIONS)
+	defer taskStatusTable.Stop(_AGGREGATE_CONTRIBUTIONS)
+
+	log.Trace("Doing: AggregateContributions")
+
+	ctx := context.Background()
+	latest, err := UserContributions.LatestDate(ctx)
+	if err != nil {
+		log.Error("Failed to get latest date of contributions: %v", err)
+		return
+	}
+
+	// Recompute the day before the latest one as well in case there were
+	// contributions around midnight after the last run.
+	since, err := time.Parse(UserContributionDateFormat, latest)
+	if err == nil {
+		since = since.AddDate(0, 0, -1)
+	} else {
+		since, _ = time.Parse(UserContributionDateFormat, ContributionCalendarSince(time.Now()))
+	}
+
+	counter, err := countContributions(since.Unix())
+	if err != nil {
+		log.Error("Failed to count contributions: %v", err)
+		return
+	}
+
+	err = UserContributions.ReplaceSince(ctx, since.Format(UserContributionDateFormat), counter.list())
+	if err != nil {
+		log.Error("Failed to replace contributions: %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/dbtest"
+)
+
+func TestUserContributions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+
+	tables := []interface{}{new(UserContribution)}
+	db := &userContributions{
+		DB: dbtest.NewDB(t, "userContributions", tables...),
+	}
+
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, *userContributions)
+	}{
+		{"ReplaceSince", userContributionsReplaceSince},
+		{"ListByUser", userContributionsListByUser},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				err := clearTables(t, db.DB, tables...)
+				require.NoError(t, err)
+			})
+			tc.test(t, db)
+		})
+		if t.Failed() {
+			break
+		}
+	}
+}
+
+func userContributionsReplaceSince(t *testing.T, db *userContributions) {
+	ctx := context.Background()
+
+	latest, err := db.LatestDate(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, latest)
+
+	err = db.ReplaceSince(ctx, "2026-05-01", []*UserContribution{
+		{UserID: 1, Date: "2026-05-01", Commits: 1},
+		{UserID: 1, Date: "2026-05-02", Commits: 2},
+	})
+	require.NoError(t, err)
+
+	err = db.ReplaceSince(ctx, "2026-05-02", []*UserContribution{
+		{UserID: 1, Date: "2026-05-02", Commits: 3},
+		{UserID: 1, Date: "2026-05-03", Issues: 1},
+	})
+	require.NoError(t, err)
+
+	latest, err = db.LatestDate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-05-03", latest)
+
+	got, err := db.ListByUser(ctx, 1, "2026-05-01", true)
+	require.NoError(t, err)
+	for _, c := range got {
+		c.ID = 0
+	}
+	want := []*UserContribution{
+		{UserID: 1, Date: "2026-05-01", Commits: 1},
+		{UserID: 1, Date: "2026-05-02", Commits: 3},
+		{UserID: 1, Date: "2026-05-03", Issues: 1},
+	}
+	assert.Equal(t, want, got)
+}
+
+func userContributionsListByUser(t *testing.T, db *userContributions) {
+	ctx := context.Background()
+
+	err := db.ReplaceSince(ctx, "2026-05-01", []*UserContribution{
+		{UserID: 1, Date: "2026-04-30", Commits: 1},
+		{UserID: 1, Date: "2026-05-01", Commits: 2, Reviews: 1},
+		{UserID: 1, Date: "2026-05-01", IsPrivate: true, Commits: 3, PullRequests: 1},
+		{UserID: 1, Date: "2026-05-02", IsPrivate: true, Issues: 1},
+		{UserID: 2, Date: "2026-05-01", Commits: 5},
+	})
+	require.NoError(t, err)
+
+	got, err := db.ListByUser(ctx, 1, "2026-05-01", false)
+	require.NoError(t, err)
+	for _, c := range got {
+		c.ID = 0
+	}
+	want := []*UserContribution{
+		{UserID: 1, Date: "2026-05-01", Commits: 2, Reviews: 1},
+	}
+	assert.Equal(t, want, got)
+
+	got, err = db.ListByUser(ctx, 1, "2026-05-01", true)
+	require.NoError(t, err)
+	for _, c := range got {
+		c.ID = 0
+	}
+	want = []*UserContribution{
+		{UserID: 1, Date: "2026-05-01", Commits: 5, PullRequests: 1, Reviews: 1},
+		{UserID: 1, Date: "2026-05-02", Issues: 1},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestBuildContributionCalendar(t *testing.T) {
+	// 2026-05-06 is a Wednesday.
+	today := time.Date(2026, time.May, 6, 15, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2025-05-04", ContributionCalendarSince(today))
+
+	calendar := BuildContributionCalendar([]*UserContribution{
+		{Date: "2025-05-04", Commits: 1},
+		{Date: "2026-05-04", Commits: 2, Reviews: 2},
+		{Date: "2026-05-06", Issues: 2},
+	}, today)
+	assert.Equal(t, int64(7), calendar.Total)
+	require.Len(t, calendar.Weeks, ContributionCalendarWeeks)
+
+	first := calendar.Weeks[0][0]
+	assert.Equal(t, &ContributionDay{Date: "2025-05-04", Count: 1, Level: 1}, first)
+
+	last := calendar.Weeks[ContributionCalendarWeeks-1]
+	assert.Equal(t, &ContributionDay{Date: "2026-05-03"}, last[0])
+	assert.Equal(t, &ContributionDay{Date: "2026-05-04", Count: 4, Level: 4}, last[1])
+	assert.Equal(t, &ContributionDay{Date: "2026-05-06", Count: 2, Level: 2}, last[3])
+	assert.Nil(t, last[4])
+	assert.Nil(t, last[6])
+}
+
+func TestContributionCounter(t *testing.T) {
+	counter := make(contributionCounter)
+	// 1777852800 is 2026-05-04 00:00:00 UTC.
+	counter.get(2, 1777852800, false).Commits += 2
+	counter.get(1, 1777852800+3600, true).Issues++
+	counter.get(1, 1777852800-1, false).Reviews++
+	counter.get(1, 1777852800+7200, false).PullRequests++
+	counter.get(1, 1777852800+7200, false).PullRequests++
+
+	want := []*UserContribution{
+		{UserID: 1, Date: "2026-05-03", Reviews: 1},
+		{UserID: 1, Date: "2026-05-04", PullRequests: 2},
+		{UserID: 1, Date: "2026-05-04", IsPrivate: true, Issues: 1},
+		{UserID: 2, Date: "2026-05-04", Commits: 2},
+	}
+	assert.Equal(t, want, counter.list())
+}
@@ -0,0 +1,358 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/email"
+	"gogs.io/gogs/internal/errutil"
+	"gogs.io/gogs/internal/strutil"
+)
+
+// ImportUserFields maps fields of imported users to columns of a CSV file or
+// attributes of entries of an LDIF file. Empty fields are not imported.
+type ImportUserFields struct {
+	Name     string
+	Email    string
+	FullName string
+	Password string
+	Admin    string
+}
+
+var (
+	// DefaultCSVImportUserFields is the column header of fields of users in CSV
+	// files.
+	DefaultCSVImportUserFields = ImportUserFields{
+		Name:     "name",
+		Email:    "email",
+		FullName: "full_name",
+		Password: "password",
+		Admin:    "admin",
+	}
+	// DefaultLDIFImportUserFields is the attributes of fields of users in LDIF
+	// files, e.g. exported from an LDAP directory with ldapsearch.
+	DefaultLDIFImportUserFields = ImportUserFields{
+		Name:     "uid",
+		Email:    "mail",
+		FullName: "cn",
+	}
+)
+
+// ParseImportUserFields overrides the default fields with a comma-separated
+// list of mappings, e.g. "name=login,email=mail".
+func ParseImportUserFields(defaults ImportUserFields, mappings string) (ImportUserFields, error) {
+	fields := defaults
+	for _, mapping := range strings.Split(mappings, ",") {
+		mapping = strings.TrimSpace(mapping)
+		if mapping == "" {
+			continue
+		}
+
+		i := strings.Index(mapping, "=")
+		if i < 0 {
+			return fields, errors.Errorf("invalid field mapping %q", mapping)
+		}
+		field, column := strings.TrimSpace(mapping[:i]), strings.TrimSpace(mapping[i+1:])
+		switch field {
+		case "name":
+			fields.Name = column
+		case "email":
+			fields.Email = column
+		case "full_name":
+			fields.FullName = column
+		case "password":
+			fields.Password = column
+		case "admin":
+			fields.Admin = column
+		default:
+			return fields, errors.Errorf("unknown field %q", field)
+		}
+	}
+
+	if fields.Name == "" || fields.Email == "" {
+		return fields, errors.New("name and email fields are required")
+	}
+	return fields, nil
+}
+
+// ImportUserRow is a user to import, read from a row of a CSV file or an entry
+// of an LDIF file. Line is the line number where the row starts.
+type ImportUserRow struct {
+	Line     int
+	Name     string
+	Email    string
+	FullName string
+	Password string
+	Admin    bool
+}
+
+// newImportUserRow maps values by column or attribute to a row.
+func newImportUserRow(line int, fields ImportUserFields, values map[string]string) *ImportUserRow {
+	row := &ImportUserRow{
+		Line:     line,
+		Name:     strings.TrimSpace(values[fields.Name]),
+		Email:    strings.TrimSpace(values[fields.Email]),
+		FullName: strings.TrimSpace(values[fields.FullName]),
+		Password: values[fields.Password],
+	}
+	if fields.Admin != "" {
+		row.Admin, _ = strconv.ParseBool(strings.TrimSpace(values[fields.Admin]))
+	}
+	return row
+}
+
+// ReadImportUsersCSV reads users to import from a CSV file with a header row.
+func ReadImportUsersCSV(r io.Reader, fields ImportUserFields) ([]*ImportUserRow, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "read header")
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	columns := make(map[string]bool, len(header))
+	for _, column := range header {
+		columns[column] = true
+	}
+	for _, column := range []string{fields.Name, fields.Email} {
+		if !columns[column] {
+			return nil, errors.Errorf("missing column %q", column)
+		}
+	}
+
+	var rows []*ImportUserRow
+	// The header is on the first line, and fields spanning multiple lines are
+	// not taken into account for line numbers.
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Wrap(err, "read row")
+		}
+
+		values := make(map[string]string, len(header))
+		for i, column := range header {
+			if i < len(record) {
+				values[column] = record[i]
+			}
+		}
+		rows = append(rows, newImportUserRow(line, fields, values))
+	}
+	return rows, nil
+}
+
+// ReadImportUsersLDIF reads users to import from entries of an LDIF file.
+// Entries without the name attribute, such as organizational units, are
+// skipped.
+func ReadImportUsersLDIF(r io.Reader, fields ImportUserFields) ([]*ImportUserRow, error) {
+	var rows []*ImportUserRow
+	var values map[string]string
+	var entryLine int
+	flush := func() {
+		if values != nil && values[fields.Name] != "" {
+			rows = append(rows, newImportUserRow(entryLine, fields, values))
+		}
+		values = nil
+	}
+
+	// Lines starting with a space continue the previous line.
+	var lines []string
+	var lineNumbers []int
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(line, " ") && len(lines) > 0 && lines[len(lines)-1] != "" {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+		lineNumbers = append(lineNumbers, n)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "read")
+	}
+
+	for i, line := range lines {
+		if line == "" {
+			flush()
+			continue
+		} else if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "version:") {
+			continue
+		}
+
+		j := strings.Index(line, ":")
+		if j < 0 {
+			return nil, errors.Errorf("invalid line %d", lineNumbers[i])
+		}
+		attr, value := line[:j], line[j+1:]
+		if strings.HasPrefix(value, ":") {
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value[1:]))
+			if err != nil {
+				return nil, errors.Errorf("invalid base64 value on line %d", lineNumbers[i])
+			}
+			value = string(decoded)
+		}
+
+		if values == nil {
+			values = make(map[string]string)
+			entryLine = lineNumbers[i]
+		}
+		// Only the first value of multi-valued attributes is used.
+		if _, ok := values[attr]; !ok {
+			values[attr] = strings.TrimSpace(value)
+		}
+	}
+	flush()
+	return rows, nil
+}
+
+// ImportUsersOptions contains options to import users.
+type ImportUsersOptions struct {
+	// RandomPassword generates passwords for rows without passwords, which are
+	// returned in results.
+	RandomPassword bool
+	// MustChangePassword forces imported users to change their passwords after
+	// signing in.
+	MustChangePassword bool
+	// SendResetEmail sends emails to imported users to set their passwords.
+	SendResetEmail bool
+	// RemoteAddr is the address of the admin who imports users, recorded with
+	// password reset tokens.
+	RemoteAddr string
+	// DryRun only validates rows without creating users.
+	DryRun bool
+}
+
+// ImportUserResult is the result of importing a row. User is nil when the row
+// failed or in dry run.
+type ImportUserResult struct {
+	Line int
+	Name string
+	User *User
+	// Password is the generated password of the user, if any.
+	Password string
+	Err      error
+}
+
+// validateImportUserRow returns an error if the user of the row cannot be
+// created, taking users of previous rows into account.
+func validateImportUserRow(ctx context.Context, row *ImportUserRow, names, emails map[string]bool) error {
+	if row.Name == "" {
+		return errors.New("name is required")
+	} else if row.Email == "" {
+		return errors.New("email is required")
+	} else if !strings.Contains(row.Email, "@") {
+		return errors.Errorf("invalid email %q", row.Email)
+	}
+
+	lowerName, lowerEmail := strings.ToLower(row.Name), strings.ToLower(row.Email)
+	if names[lowerName] {
+		return errors.Errorf("duplicate name %q", row.Name)
+	} else if emails[lowerEmail] {
+		return errors.Errorf("duplicate email %q", row.Email)
+	}
+	names[lowerName] = true
+	emails[lowerEmail] = true
+
+	if err := isUsernameAllowed(row.Name); err != nil {
+		return err
+	}
+	if _, err := Users.GetByUsername(ctx, row.Name); err == nil {
+		return ErrUserAlreadyExist{args: errutil.Args{"name": row.Name}}
+	} else if !IsErrUserNotExist(err) {
+		return err
+	}
+	if _, err := Users.GetByEmail(ctx, row.Email); err == nil {
+		return ErrEmailAlreadyUsed{args: errutil.Args{"email": row.Email}}
+	} else if !IsErrUserNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// ImportUsers creates users of rows, one by one so that a failed row does not
+// stop others, and returns results of rows in the same order. It returns an
+// error only when the import cannot be started at all.
+func ImportUsers(ctx context.Context, rows []*ImportUserRow, opts ImportUsersOptions) ([]*ImportUserResult, error) {
+	if opts.SendResetEmail && !conf.Email.Enabled {
+		return nil, errors.New("email service is not enabled")
+	}
+
+	names := make(map[string]bool, len(rows))
+	emails := make(map[string]bool, len(rows))
+	results := make([]*ImportUserResult, 0, len(rows))
+	for _, row := range rows {
+		result := &ImportUserResult{
+			Line: row.Line,
+			Name: row.Name,
+		}
+		results = append(results, result)
+
+		result.Err = validateImportUserRow(ctx, row, names, emails)
+		if result.Err != nil {
+			continue
+		}
+
+		password := row.Password
+		if password == "" {
+			if !opts.RandomPassword && !opts.SendResetEmail {
+				result.Err = errors.New("password is required")
+				continue
+			}
+
+			// Users who set their passwords by email do not need to know the
+			// generated one.
+			password, result.Err = strutil.RandomChars(16)
+			if result.Err != nil {
+				continue
+			}
+			if opts.RandomPassword {
+				result.Password = password
+			}
+		}
+		if opts.DryRun {
+			continue
+		}
+
+		result.User, result.Err = Users.Create(ctx, row.Name, row.Email, CreateUserOptions{
+			FullName:           row.FullName,
+			Password:           password,
+			Activated:          true,
+			Admin:              row.Admin,
+			MustChangePassword: opts.MustChangePassword,
+		})
+		if result.Err != nil {
+			result.Password = ""
+			continue
+		}
+
+		if opts.SendResetEmail {
+			ttl := time.Duration(conf.Auth.ResetPasswordCodeLives) * time.Minute
+			token, err := PasswordResetTokens.Create(ctx, result.User.ID, opts.RemoteAddr, ttl)
+			if err != nil {
+				result.Err = fmt.Errorf("user is created but failed to send email: %v", err)
+				continue
+			}
+			email.SendSetPasswordMail(NewMailerUser(result.User), token.Token)
+		}
+	}
+	return results, nil
+}
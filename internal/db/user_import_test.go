@@ -0,0 +1,79 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImportUserFields(t *testing.T) {
+	got, err := ParseImportUserFields(DefaultCSVImportUserFields, "name=login, full_name=Display Name,admin=")
+	require.NoError(t, err)
+	want := ImportUserFields{
+		Name:     "login",
+		Email:    "email",
+		FullName: "Display Name",
+		Password: "password",
+	}
+	assert.Equal(t, want, got)
+
+	_, err = ParseImportUserFields(DefaultCSVImportUserFields, "login")
+	assert.Error(t, err)
+	_, err = ParseImportUserFields(DefaultCSVImportUserFields, "nickname=nick")
+	assert.Error(t, err)
+	_, err = ParseImportUserFields(DefaultCSVImportUserFields, "email=")
+	assert.Error(t, err)
+}
+
+func TestReadImportUsersCSV(t *testing.T) {
+	input := `name, email, full_name, admin, department
+alice,alice@example.com,Alice Smith,true,Sales
+bob,Bob@Example.com,,,Sales
+`
+	got, err := ReadImportUsersCSV(strings.NewReader(input), DefaultCSVImportUserFields)
+	require.NoError(t, err)
+	want := []*ImportUserRow{
+		{Line: 2, Name: "alice", Email: "alice@example.com", FullName: "Alice Smith", Admin: true},
+		{Line: 3, Name: "bob", Email: "Bob@Example.com"},
+	}
+	assert.Equal(t, want, got)
+
+	_, err = ReadImportUsersCSV(strings.NewReader("login,email\nalice,alice@example.com\n"), DefaultCSVImportUserFields)
+	assert.EqualError(t, err, `missing column "name"`)
+}
+
+func TestReadImportUsersLDIF(t *testing.T) {
+	input := `version: 1
+
+# Sales
+dn: ou=sales,dc=example,dc=com
+objectClass: organizationalUnit
+ou: sales
+
+dn: uid=alice,ou=sales,dc=example,dc=com
+objectClass: inetOrgPerson
+uid: alice
+cn: Alice Smith
+mail: alice@example.com
+mail: alice.smith@example.com
+
+dn: uid=bob,ou=sales,dc=example,dc=com
+uid: bob
+cn:: Qm9iIErDuHJnZW5zZW4=
+mail: bob@exa
+ mple.com
+`
+	got, err := ReadImportUsersLDIF(strings.NewReader(input), DefaultLDIFImportUserFields)
+	require.NoError(t, err)
+	want := []*ImportUserRow{
+		{Line: 8, Name: "alice", Email: "alice@example.com", FullName: "Alice Smith"},
+		{Line: 15, Name: "bob", Email: "bob@example.com", FullName: "Bob Jørgensen"},
+	}
+	assert.Equal(t, want, got)
+}
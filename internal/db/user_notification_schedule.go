@@ -0,0 +1,159 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gogs.io/gogs/internal/errutil"
+)
+
+// TimeLocation returns the time zone of the user, or the time zone of the server
+// when not set or unknown.
+func (u *User) TimeLocation() *time.Location {
+	if u.Timezone == "" {
+		return time.Local
+	}
+
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// isQuietHour returns true if the hour of a day falls in quiet hours of the
+// user. Quiet hours span midnight when they end before they start, and are
+// disabled when they start and end at the same hour.
+func (u *User) isQuietHour(hour int) bool {
+	if !u.EnableQuietHours || u.QuietHoursStart == u.QuietHoursEnd {
+		return false
+	}
+
+	if u.QuietHoursStart < u.QuietHoursEnd {
+		return hour >= u.QuietHoursStart && hour < u.QuietHoursEnd
+	}
+	return hour >= u.QuietHoursStart || hour < u.QuietHoursEnd
+}
+
+// NextNotificationTime returns the time when non-urgent notifications to the
+// user are due, which is now when the user is not in quiet hours or weekends,
+// and otherwise the start of the next active window in the time zone of the
+// user.
+func (u *User) NextNotificationTime(now time.Time) time.Time {
+	t := now.In(u.TimeLocation())
+	// Every step moves to the end of a quiet window, there are at most two
+	// weekend days and a quiet hours window in between.
+	for i := 0; i < 8; i++ {
+		year, month, day := t.Date()
+		switch {
+		case u.QuietWeekends && (t.Weekday() == time.Saturday || t.Weekday() == time.Sunday):
+			t = time.Date(year, month, day+1, 0, 0, 0, 0, t.Location())
+		case u.isQuietHour(t.Hour()):
+			end := time.Date(year, month, day, u.QuietHoursEnd, 0, 0, 0, t.Location())
+			if !end.After(t) {
+				end = time.Date(year, month, day+1, u.QuietHoursEnd, 0, 0, 0, t.Location())
+			}
+			t = end
+		default:
+			if t.Equal(now) {
+				return now
+			}
+			return t
+		}
+	}
+	return t
+}
+
+// notificationDeliverAt returns the time to defer non-urgent notifications to
+// the user until, or zero when they are due now.
+func (u *User) notificationDeliverAt(now time.Time) time.Time {
+	if next := u.NextNotificationTime(now); next.After(now) {
+		return next
+	}
+	return time.Time{}
+}
+
+// notificationBatch is a list of email addresses of users whose notifications
+// are due at the same time.
+type notificationBatch struct {
+	DeliverAt time.Time
+	Emails    []string
+}
+
+// batchNotificationRecipients groups email addresses of users by the time
+// their non-urgent notifications are due, ordered by the time. The time is
+// zero for users who are not in quiet hours or weekends.
+func batchNotificationRecipients(users []*User, now time.Time) []*notificationBatch {
+	batches := make(map[int64]*notificationBatch)
+	for _, u := range users {
+		deliverAt := u.notificationDeliverAt(now)
+		var key int64
+		if !deliverAt.IsZero() {
+			key = deliverAt.Unix()
+		}
+		b, ok := batches[key]
+		if !ok {
+			b = &notificationBatch{DeliverAt: deliverAt}
+			batches[key] = b
+		}
+		b.Emails = append(b.Emails, u.Email)
+	}
+
+	list := make([]*notificationBatch, 0, len(batches))
+	for _, b := range batches {
+		list = append(list, b)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].DeliverAt.Before(list[j].DeliverAt) })
+	return list
+}
+
+// NotificationScheduleOptions contains options to update the notification
+// schedule of a user.
+type NotificationScheduleOptions struct {
+	Timezone         string
+	EnableQuietHours bool
+	QuietHoursStart  int
+	QuietHoursEnd    int
+	QuietWeekends    bool
+}
+
+type ErrNotificationScheduleInvalid struct {
+	args errutil.Args
+}
+
+func IsErrNotificationScheduleInvalid(err error) bool {
+	_, ok := err.(ErrNotificationScheduleInvalid)
+	return ok
+}
+
+func (err ErrNotificationScheduleInvalid) Error() string {
+	return fmt.Sprintf("notification schedule is invalid: %v", err.args)
+}
+
+// UpdateNotificationSchedule updates the time zone and quiet hours of the
+// user. It returns ErrNotificationScheduleInvalid when the time zone is unknown
+// or hours are out of range.
+func UpdateNotificationSchedule(u *User, opts NotificationScheduleOptions) error {
+	if opts.Timezone != "" {
+		if _, err := time.LoadLocation(opts.Timezone); err != nil {
+			return ErrNotificationScheduleInvalid{args: errutil.Args{"timezone": opts.Timezone}}
+		}
+	}
+	for _, hour := range []int{opts.QuietHoursStart, opts.QuietHoursEnd} {
+		if hour < 0 || hour > 23 {
+			return ErrNotificationScheduleInvalid{args: errutil.Args{"hour": hour}}
+		}
+	}
+
+	u.Timezone = opts.Timezone
+	u.EnableQuietHours = opts.EnableQuietHours
+	u.QuietHoursStart = opts.QuietHoursStart
+	u.QuietHoursEnd = opts.QuietHoursEnd
+	u.QuietWeekends = opts.QuietWeekends
+	return UpdateUser(u)
+}
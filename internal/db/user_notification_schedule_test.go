@@ -0,0 +1,130 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUser_NextNotificationTime(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	at := func(day, hour, min int) time.Time {
+		// 2026-01-05 is a Monday.
+		return time.Date(2026, time.January, day, hour, min, 0, 0, loc)
+	}
+
+	nightOwl := &User{
+		Timezone:         "Asia/Tokyo",
+		EnableQuietHours: true,
+		QuietHoursStart:  22,
+		QuietHoursEnd:    8,
+		QuietWeekends:    true,
+	}
+	tests := []struct {
+		name string
+		user *User
+		now  time.Time
+		want time.Time
+	}{
+		{
+			name: "no schedule",
+			user: &User{Timezone: "Asia/Tokyo"},
+			now:  at(10, 23, 0),
+			want: at(10, 23, 0),
+		},
+		{
+			name: "active hours",
+			user: nightOwl,
+			now:  at(5, 12, 30),
+			want: at(5, 12, 30),
+		},
+		{
+			name: "quiet hours before midnight",
+			user: nightOwl,
+			now:  at(5, 23, 15),
+			want: at(6, 8, 0),
+		},
+		{
+			name: "quiet hours after midnight",
+			user: nightOwl,
+			now:  at(6, 3, 0),
+			want: at(6, 8, 0),
+		},
+		{
+			name: "quiet hours into weekend",
+			user: nightOwl,
+			now:  at(9, 22, 0),
+			want: at(12, 8, 0),
+		},
+		{
+			name: "weekend",
+			user: nightOwl,
+			now:  at(11, 14, 0),
+			want: at(12, 8, 0),
+		},
+		{
+			name: "weekend without quiet hours",
+			user: &User{Timezone: "Asia/Tokyo", QuietWeekends: true},
+			now:  at(10, 9, 0),
+			want: at(12, 0, 0),
+		},
+		{
+			name: "quiet hours within a day",
+			user: &User{Timezone: "Asia/Tokyo", EnableQuietHours: true, QuietHoursStart: 12, QuietHoursEnd: 14},
+			now:  at(7, 13, 59),
+			want: at(7, 14, 0),
+		},
+		{
+			name: "same start and end",
+			user: &User{Timezone: "Asia/Tokyo", EnableQuietHours: true, QuietHoursStart: 9, QuietHoursEnd: 9},
+			now:  at(7, 9, 0),
+			want: at(7, 9, 0),
+		},
+		{
+			name: "time zone of the user",
+			user: nightOwl,
+			// 14:00 UTC is 23:00 in Tokyo.
+			now:  time.Date(2026, time.January, 5, 14, 0, 0, 0, time.UTC),
+			want: at(6, 8, 0),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.user.NextNotificationTime(test.now)
+			assert.True(t, test.want.Equal(got), "want %s but got %s", test.want, got)
+		})
+	}
+}
+
+func TestBatchNotificationRecipients(t *testing.T) {
+	now := time.Date(2026, time.January, 5, 23, 0, 0, 0, time.UTC)
+	quiet := func(email, timezone string) *User {
+		return &User{
+			Email:            email,
+			Timezone:         timezone,
+			EnableQuietHours: true,
+			QuietHoursStart:  22,
+			QuietHoursEnd:    7,
+		}
+	}
+	users := []*User{
+		{Email: "alice@example.com"},
+		quiet("bob@example.com", "UTC"),
+		quiet("cindy@example.com", "Asia/Tokyo"),
+		quiet("dan@example.com", "Etc/UTC"),
+	}
+
+	got := batchNotificationRecipients(users, now)
+	require.Len(t, got, 2)
+	assert.True(t, got[0].DeliverAt.IsZero())
+	assert.Equal(t, []string{"alice@example.com", "cindy@example.com"}, got[0].Emails)
+	assert.True(t, got[1].DeliverAt.Equal(time.Date(2026, time.January, 6, 7, 0, 0, 0, time.UTC)))
+	assert.Equal(t, []string{"bob@example.com", "dan@example.com"}, got[1].Emails)
+}
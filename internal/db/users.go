@@ -15,6 +15,7 @@ import (
 	"gorm.io/gorm"
 
 	"gogs.io/gogs/internal/auth"
+	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/cryptoutil"
 	"gogs.io/gogs/internal/errutil"
 )
@@ -145,31 +146,73 @@ func (db *users) Authenticate(ctx context.Context, login, password string, login
 		return nil, errors.Errorf("login source %d is not activated", source.ID)
 	}
 
-	extAccount, err := source.Provider.Authenticate(login, password)
+	// Query attributes that profile fields are synced from when supported by the
+	// provider.
+	var syncAttrs []string
+	attrsProvider, ok := source.Provider.(auth.AttributesProvider)
+	if ok {
+		syncAttrs, err = profileFieldLDAPAttributes(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "get LDAP attributes of profile fields")
+		}
+	}
+
+	var extAccount *auth.ExternalAccount
+	if len(syncAttrs) > 0 {
+		extAccount, err = attrsProvider.AuthenticateWithAttributes(login, password, syncAttrs)
+	} else {
+		extAccount, err = source.Provider.Authenticate(login, password)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	if !createNewUser {
-		return user, nil
+	if createNewUser {
+		// Validate username make sure it satisfies requirement.
+		if binding.AlphaDashDotPattern.MatchString(extAccount.Name) {
+			return nil, fmt.Errorf("invalid pattern for attribute 'username' [%s]: must be valid alpha or numeric or dash(-_) or dot characters", extAccount.Name)
+		}
+
+		user, err = db.Create(ctx, extAccount.Name, extAccount.Email,
+			CreateUserOptions{
+				FullName:    extAccount.FullName,
+				LoginSource: authSourceID,
+				LoginName:   extAccount.Login,
+				Location:    extAccount.Location,
+				Website:     extAccount.Website,
+				Activated:   true,
+				Admin:       extAccount.Admin,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// Validate username make sure it satisfies requirement.
-	if binding.AlphaDashDotPattern.MatchString(extAccount.Name) {
-		return nil, fmt.Errorf("invalid pattern for attribute 'username' [%s]: must be valid alpha or numeric or dash(-_) or dot characters", extAccount.Name)
+	if len(syncAttrs) > 0 {
+		err = ProfileFields.SyncLDAPAttributes(ctx, user.ID, extAccount.Attributes)
+		if err != nil {
+			return nil, errors.Wrap(err, "sync profile fields")
+		}
 	}
+	return user, nil
+}
 
-	return db.Create(ctx, extAccount.Name, extAccount.Email,
-		CreateUserOptions{
-			FullName:    extAccount.FullName,
-			LoginSource: authSourceID,
-			LoginName:   extAccount.Login,
-			Location:    extAccount.Location,
-			Website:     extAccount.Website,
-			Activated:   true,
-			Admin:       extAccount.Admin,
-		},
-	)
+// profileFieldLDAPAttributes returns LDAP attributes that values of profile
+// fields of users are synced from.
+func profileFieldLDAPAttributes(ctx context.Context) ([]string, error) {
+	fields, err := ProfileFields.List(ctx, ProfileFieldScopeUser)
+	if err != nil {
+		return nil, err
+	}
+
+	var attrs []string
+	for _, f := range fields {
+		if f.LDAPAttribute != "" {
+			attrs = append(attrs, f.LDAPAttribute)
+		}
+	}
+	return attrs, nil
 }
 
 type CreateUserOptions struct {
@@ -181,6 +224,9 @@ type CreateUserOptions struct {
 	Website     string
 	Activated   bool
 	Admin       bool
+	// MustChangePassword forces the user to change the password after signing
+	// in.
+	MustChangePassword bool
 }
 
 type ErrUserAlreadyExist struct {
@@ -238,20 +284,21 @@ func (db *users) Create(ctx context.Context, username, email string, opts Create
 	}
 
 	user := &User{
-		LowerName:       strings.ToLower(username),
-		Name:            username,
-		FullName:        opts.FullName,
-		Email:           email,
-		Passwd:          opts.Password,
-		LoginSource:     opts.LoginSource,
-		LoginName:       opts.LoginName,
-		Location:        opts.Location,
-		Website:         opts.Website,
-		MaxRepoCreation: -1,
-		IsActive:        opts.Activated,
-		IsAdmin:         opts.Admin,
-		Avatar:          cryptoutil.MD5(email),
-		AvatarEmail:     email,
+		LowerName:          strings.ToLower(username),
+		Name:               username,
+		FullName:           opts.FullName,
+		Email:              email,
+		Passwd:             opts.Password,
+		LoginSource:        opts.LoginSource,
+		LoginName:          opts.LoginName,
+		Location:           opts.Location,
+		Website:            opts.Website,
+		MaxRepoCreation:    -1,
+		IsActive:           opts.Activated,
+		IsAdmin:            opts.Admin,
+		MustChangePassword: opts.MustChangePassword,
+		Avatar:             cryptoutil.MD5(email),
+		AvatarEmail:        email,
 	}
 
 	user.Rands, err = GetUserSalt()
@@ -293,6 +340,20 @@ func (db *users) GetByEmail(ctx context.Context, email string) (*User, error) {
 		return nil, ErrUserNotExist{args: errutil.Args{"email": email}}
 	}
 
+	// Noreply email addresses are mapped to users by their usernames.
+	if name := strings.TrimSuffix(email, "@"+strings.ToLower(conf.User.NoReplyAddress)); name != email {
+		user, err := db.GetByUsername(ctx, name)
+		if err != nil {
+			if IsErrUserNotExist(err) {
+				return nil, ErrUserNotExist{args: errutil.Args{"email": email}}
+			}
+			return nil, err
+		} else if user.IsOrganization() {
+			return nil, ErrUserNotExist{args: errutil.Args{"email": email}}
+		}
+		return user, nil
+	}
+
 	// First try to find the user by primary email
 	user := new(User)
 	err := db.WithContext(ctx).
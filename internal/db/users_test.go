@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"gogs.io/gogs/internal/auth"
+	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/dbtest"
 	"gogs.io/gogs/internal/errutil"
 )
@@ -24,7 +25,7 @@ func TestUsers(t *testing.T) {
 	}
 	t.Parallel()
 
-	tables := []interface{}{new(User), new(EmailAddress)}
+	tables := []interface{}{new(User), new(EmailAddress), new(ProfileField), new(ProfileFieldValue)}
 	db := &users{
 		DB: dbtest.NewDB(t, "users", tables...),
 	}
@@ -147,6 +148,58 @@ func usersAuthenticate(t *testing.T, db *users) {
 		require.NoError(t, err)
 		assert.Equal(t, "cindy@example.com", user.Email)
 	})
+
+	t.Run("sync profile fields via login source", func(t *testing.T) {
+		profileFields := &profileFields{DB: db.DB}
+		setMockProfileFieldsStore(t, profileFields)
+
+		field, err := profileFields.Create(ctx, "employee_id", CreateProfileFieldOptions{
+			Scope:         ProfileFieldScopeUser,
+			LDAPAttribute: "employeeNumber",
+		})
+		require.NoError(t, err)
+
+		mockLoginSources := NewMockLoginSourcesStore()
+		mockLoginSources.GetByIDFunc.SetDefaultHook(func(ctx context.Context, id int64) (*LoginSource, error) {
+			s := &LoginSource{
+				IsActived: true,
+				Provider: &mockAttributesProvider{
+					MockProvider: NewMockProvider(),
+					account: &auth.ExternalAccount{
+						Name:  "dan",
+						Email: "dan@example.com",
+					},
+					attrs: map[string]string{"employeeNumber": "E-1024"},
+				},
+			}
+			return s, nil
+		})
+		setMockLoginSourcesStore(t, mockLoginSources)
+
+		user, err := db.Authenticate(ctx, "dan", password, 1)
+		require.NoError(t, err)
+
+		values, err := profileFields.ListValues(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, map[int64]string{field.ID: "E-1024"}, values)
+	})
+}
+
+type mockAttributesProvider struct {
+	*MockProvider
+	account *auth.ExternalAccount
+	attrs   map[string]string
+}
+
+func (p *mockAttributesProvider) AuthenticateWithAttributes(_, _ string, attributes []string) (*auth.ExternalAccount, error) {
+	account := *p.account
+	account.Attributes = make(map[string]string, len(attributes))
+	for _, attr := range attributes {
+		if v, ok := p.attrs[attr]; ok {
+			account.Attributes[attr] = v
+		}
+	}
+	return &account, nil
 }
 
 func usersCreate(t *testing.T, db *users) {
@@ -244,6 +297,25 @@ func usersGetByEmail(t *testing.T, db *users) {
 		require.NoError(t, err)
 		assert.Equal(t, bob.Name, user.Name)
 	})
+
+	t.Run("by noreply email", func(t *testing.T) {
+		before := conf.User.NoReplyAddress
+		conf.User.NoReplyAddress = "noreply.example.com"
+		t.Cleanup(func() {
+			conf.User.NoReplyAddress = before
+		})
+
+		cindy, err := db.Create(ctx, "cindy", "cindy@example.com", CreateUserOptions{})
+		require.NoError(t, err)
+
+		user, err := db.GetByEmail(ctx, "Cindy@noreply.example.com")
+		require.NoError(t, err)
+		assert.Equal(t, cindy.Name, user.Name)
+
+		_, err = db.GetByEmail(ctx, "gogs@noreply.example.com")
+		wantErr := ErrUserNotExist{args: errutil.Args{"email": "gogs@noreply.example.com"}}
+		assert.Equal(t, wantErr, err)
+	})
 }
 
 func usersGetByID(t *testing.T, db *users) {
@@ -275,3 +347,42 @@ func usersGetByUsername(t *testing.T, db *users) {
 	wantErr := ErrUserNotExist{args: errutil.Args{"name": "bad_username"}}
 	assert.Equal(t, wantErr, err)
 }
+
+func TestIsTenantIsolated(t *testing.T) {
+	before := conf.Security.EnableTenantIsolation
+	t.Cleanup(func() {
+		conf.Security.EnableTenantIsolation = before
+	})
+
+	alice := &User{ID: 1, Tenant: "acme"}
+	bob := &User{ID: 2, Tenant: "acme"}
+	cindy := &User{ID: 3, Tenant: "initech"}
+	admin := &User{ID: 4, IsAdmin: true}
+
+	conf.Security.EnableTenantIsolation = false
+	assert.False(t, IsTenantIsolated(nil, alice))
+	assert.False(t, IsTenantIsolated(cindy, alice))
+
+	conf.Security.EnableTenantIsolation = true
+	assert.True(t, IsTenantIsolated(nil, alice))
+	assert.True(t, IsTenantIsolated(cindy, alice))
+	assert.False(t, IsTenantIsolated(alice, alice))
+	assert.False(t, IsTenantIsolated(bob, alice))
+	assert.False(t, IsTenantIsolated(admin, cindy))
+}
+
+func TestUser_CommitEmail(t *testing.T) {
+	before := conf.User.NoReplyAddress
+	conf.User.NoReplyAddress = "noreply.example.com"
+	t.Cleanup(func() {
+		conf.User.NoReplyAddress = before
+	})
+
+	u := &User{LowerName: "alice", Email: "alice@example.com"}
+	assert.Equal(t, "alice@example.com", u.CommitEmail())
+	assert.Equal(t, "alice@example.com", u.NewGitSig().Email)
+
+	u.KeepEmailPrivate = true
+	assert.Equal(t, "alice@noreply.example.com", u.CommitEmail())
+	assert.Equal(t, "alice@noreply.example.com", u.NewGitSig().Email)
+}
@@ -74,6 +74,11 @@ type HookEvents struct {
 	PullRequest  bool `json:"pull_request"`
 	IssueComment bool `json:"issue_comment"`
 	Release      bool `json:"release"`
+	Star         bool `json:"star"`
+	Watch        bool `json:"watch"`
+	Repository   bool `json:"repository"`
+	Deployment   bool `json:"deployment"`
+	Wiki         bool `json:"wiki"`
 }
 
 // HookEvent represents events that will delivery hook.
@@ -207,13 +212,43 @@ func (w *Webhook) HasReleaseEvent() bool {
 		(w.ChooseEvents && w.HookEvents.Release)
 }
 
+// HasStarEvent returns true if hook enabled star event.
+func (w *Webhook) HasStarEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Star)
+}
+
+// HasWatchEvent returns true if hook enabled watch event.
+func (w *Webhook) HasWatchEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Watch)
+}
+
+// HasRepositoryEvent returns true if hook enabled repository event.
+func (w *Webhook) HasRepositoryEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Repository)
+}
+
+// HasDeploymentEvent returns true if hook enabled deployment event.
+func (w *Webhook) HasDeploymentEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Deployment)
+}
+
+// HasWikiEvent returns true if hook enabled wiki event.
+func (w *Webhook) HasWikiEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Wiki)
+}
+
 type eventChecker struct {
 	checker func() bool
 	typ     HookEventType
 }
 
 func (w *Webhook) EventsArray() []string {
-	events := make([]string, 0, 8)
+	events := make([]string, 0, 13)
 	eventCheckers := []eventChecker{
 		{w.HasCreateEvent, HOOK_EVENT_CREATE},
 		{w.HasDeleteEvent, HOOK_EVENT_DELETE},
@@ -223,6 +258,11 @@ func (w *Webhook) EventsArray() []string {
 		{w.HasPullRequestEvent, HOOK_EVENT_PULL_REQUEST},
 		{w.HasIssueCommentEvent, HOOK_EVENT_ISSUE_COMMENT},
 		{w.HasReleaseEvent, HOOK_EVENT_RELEASE},
+		{w.HasStarEvent, HOOK_EVENT_STAR},
+		{w.HasWatchEvent, HOOK_EVENT_WATCH},
+		{w.HasRepositoryEvent, HOOK_EVENT_REPOSITORY},
+		{w.HasDeploymentEvent, HOOK_EVENT_DEPLOYMENT},
+		{w.HasWikiEvent, HOOK_EVENT_WIKI},
 	}
 	for _, c := range eventCheckers {
 		if c.checker() {
@@ -417,6 +457,11 @@ const (
 	HOOK_EVENT_PULL_REQUEST  HookEventType = "pull_request"
 	HOOK_EVENT_ISSUE_COMMENT HookEventType = "issue_comment"
 	HOOK_EVENT_RELEASE       HookEventType = "release"
+	HOOK_EVENT_STAR          HookEventType = "star"
+	HOOK_EVENT_WATCH         HookEventType = "watch"
+	HOOK_EVENT_REPOSITORY    HookEventType = "repository"
+	HOOK_EVENT_DEPLOYMENT    HookEventType = "deployment"
+	HOOK_EVENT_WIKI          HookEventType = "wiki"
 )
 
 // HookRequest represents hook task request information.
@@ -567,6 +612,12 @@ func prepareHookTasks(e Engine, repo *Repository, event HookEventType, p api.Pay
 		return nil
 	}
 
+	// Chat services only convert payloads provided by the API client.
+	chatPayloader := p
+	if mp, ok := p.(*MergedPullRequestPayload); ok {
+		chatPayloader = mp.PullRequestPayload
+	}
+
 	var payloader api.Payloader
 	for _, w := range webhooks {
 		switch event {
@@ -602,22 +653,42 @@ func prepareHookTasks(e Engine, repo *Repository, event HookEventType, p api.Pay
 			if !w.HasReleaseEvent() {
 				continue
 			}
+		case HOOK_EVENT_STAR:
+			if !w.HasStarEvent() {
+				continue
+			}
+		case HOOK_EVENT_WATCH:
+			if !w.HasWatchEvent() {
+				continue
+			}
+		case HOOK_EVENT_REPOSITORY:
+			if !w.HasRepositoryEvent() {
+				continue
+			}
+		case HOOK_EVENT_DEPLOYMENT:
+			if !w.HasDeploymentEvent() {
+				continue
+			}
+		case HOOK_EVENT_WIKI:
+			if !w.HasWikiEvent() {
+				continue
+			}
 		}
 
 		// Use separate objects so modifications won't be made on payload on non-Gogs type hooks.
 		switch w.HookTaskType {
 		case SLACK:
-			payloader, err = GetSlackPayload(p, event, w.Meta)
+			payloader, err = GetSlackPayload(chatPayloader, event, w.Meta)
 			if err != nil {
 				return fmt.Errorf("GetSlackPayload: %v", err)
 			}
 		case DISCORD:
-			payloader, err = GetDiscordPayload(p, event, w.Meta)
+			payloader, err = GetDiscordPayload(chatPayloader, event, w.Meta)
 			if err != nil {
 				return fmt.Errorf("GetDiscordPayload: %v", err)
 			}
 		case DINGTALK:
-			payloader, err = GetDingtalkPayload(p, event)
+			payloader, err = GetDingtalkPayload(chatPayloader, event)
 			if err != nil {
 				return fmt.Errorf("GetDingtalkPayload: %v", err)
 			}
@@ -0,0 +1,95 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	api "github.com/gogs/go-gogs-client"
+	jsoniter "github.com/json-iterator/go"
+	log "unknwon.dev/clog/v2"
+)
+
+const (
+	DeploymentActionCreated  = "created"
+	DeploymentActionTornDown = "torn_down"
+)
+
+// DeploymentHookInfo is the deployment in payloads of deployment events.
+type DeploymentHookInfo struct {
+	ID             int64     `json:"id"`
+	Environment    string    `json:"environment"`
+	EnvironmentURL string    `json:"environment_url"`
+	Ref            string    `json:"ref"`
+	SHA            string    `json:"sha"`
+	Description    string    `json:"description"`
+	State          string    `json:"state"`
+	PullRequest    int64     `json:"pull_request,omitempty"`
+	Created        time.Time `json:"created_at"`
+	Updated        time.Time `json:"updated_at"`
+}
+
+type DeploymentPayload struct {
+	// Action is either "created" or "torn_down".
+	Action     string              `json:"action"`
+	Deployment *DeploymentHookInfo `json:"deployment"`
+	Repository *api.Repository     `json:"repository"`
+	Sender     *api.User           `json:"sender"`
+}
+
+func (p *DeploymentPayload) JSONPayload() ([]byte, error) {
+	return jsoniter.MarshalIndent(p, "", "  ")
+}
+
+func (p *DeploymentPayload) describe() string {
+	if p.Action == DeploymentActionTornDown {
+		return fmt.Sprintf("preview environment %q of pull request #%d torn down by %s",
+			p.Deployment.Environment, p.Deployment.PullRequest, p.Sender.UserName)
+	}
+	return fmt.Sprintf("deployment to %q created by %s", p.Deployment.Environment, p.Sender.UserName)
+}
+
+func (p *DeploymentPayload) repo() *api.Repository { return p.Repository }
+func (p *DeploymentPayload) sender() *api.User     { return p.Sender }
+
+// PrepareDeploymentWebhook sends deployment webhooks about the action on the
+// deployment of the repository.
+func PrepareDeploymentWebhook(doer *User, repo *Repository, d *Deployment, action string) {
+	if err := PrepareWebhooks(repo, HOOK_EVENT_DEPLOYMENT, &DeploymentPayload{
+		Action: action,
+		Deployment: &DeploymentHookInfo{
+			ID:             d.ID,
+			Environment:    d.Environment,
+			EnvironmentURL: d.EnvironmentURL,
+			Ref:            d.Ref,
+			SHA:            d.SHA,
+			Description:    d.Description,
+			State:          string(d.State),
+			PullRequest:    d.PullRequestIndex,
+			Created:        d.CreatedAt,
+			Updated:        d.UpdatedAt,
+		},
+		Repository: repo.APIFormatLegacy(nil),
+		Sender:     doer.APIFormat(),
+	}); err != nil {
+		log.Error("PrepareWebhooks [repo_id: %d]: %v", repo.ID, err)
+	}
+}
+
+// tearDownPreviewDeployments marks preview environments of the closed pull
+// request as inactive, and sends deployment webhooks so that CI/CD can tear
+// them down.
+func tearDownPreviewDeployments(doer *User, repo *Repository, index int64) {
+	ds, err := Deployments.DeactivatePullRequest(context.Background(), repo.ID, index, doer.ID)
+	if err != nil {
+		log.Error("Failed to deactivate deployments of pull request [repo_id: %d, index: %d]: %v", repo.ID, index, err)
+		return
+	}
+	for _, d := range ds {
+		PrepareDeploymentWebhook(doer, repo, d, DeploymentActionTornDown)
+	}
+}
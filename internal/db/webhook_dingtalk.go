@@ -77,6 +77,8 @@ func GetDingtalkPayload(p api.Payloader, event HookEventType) (payload *Dingtalk
 		payload = getDingtalkPullRequestPayload(p.(*api.PullRequestPayload))
 	case HOOK_EVENT_RELEASE:
 		payload = getDingtalkReleasePayload(p.(*api.ReleasePayload))
+	case HOOK_EVENT_STAR, HOOK_EVENT_WATCH, HOOK_EVENT_REPOSITORY, HOOK_EVENT_DEPLOYMENT, HOOK_EVENT_WIKI:
+		payload = getDingtalkRepositoryEventPayload(p.(repositoryEventPayload))
 	default:
 		return nil, errors.Errorf("unexpected event %q", event)
 	}
@@ -125,6 +127,18 @@ func getDingtalkForkPayload(p *api.ForkPayload) *DingtalkPayload {
 	}
 }
 
+func getDingtalkRepositoryEventPayload(p repositoryEventPayload) *DingtalkPayload {
+	actionCard := NewDingtalkActionCard("View Repo", p.repo().HTMLURL)
+	actionCard.Text += "# Repository Event"
+	actionCard.Text += "\n- Repo: **" + MarkdownLinkFormatter(p.repo().HTMLURL, p.repo().FullName) + "**"
+	actionCard.Text += "\n- Event: Repository " + p.describe()
+
+	return &DingtalkPayload{
+		MsgType:    "actionCard",
+		ActionCard: actionCard,
+	}
+}
+
 func getDingtalkPushPayload(p *api.PushPayload) *DingtalkPayload {
 	refName := git.RefShortName(p.Ref)
 
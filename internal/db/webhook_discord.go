@@ -122,6 +122,20 @@ func getDiscordForkPayload(p *api.ForkPayload) *DiscordPayload {
 	}
 }
 
+func getDiscordRepositoryEventPayload(p repositoryEventPayload) *DiscordPayload {
+	repoLink := DiscordLinkFormatter(p.repo().HTMLURL, p.repo().FullName)
+	return &DiscordPayload{
+		Embeds: []*DiscordEmbedObject{{
+			Description: fmt.Sprintf("[%s] Repository %s", repoLink, p.describe()),
+			URL:         p.repo().HTMLURL,
+			Author: &DiscordEmbedAuthorObject{
+				Name:    p.sender().UserName,
+				IconURL: p.sender().AvatarUrl,
+			},
+		}},
+	}
+}
+
 func getDiscordPushPayload(p *api.PushPayload, slack *SlackMeta) *DiscordPayload {
 	// n new commits
 	var (
@@ -394,6 +408,8 @@ func GetDiscordPayload(p api.Payloader, event HookEventType, meta string) (paylo
 		payload = getDiscordPullRequestPayload(p.(*api.PullRequestPayload), slack)
 	case HOOK_EVENT_RELEASE:
 		payload = getDiscordReleasePayload(p.(*api.ReleasePayload))
+	case HOOK_EVENT_STAR, HOOK_EVENT_WATCH, HOOK_EVENT_REPOSITORY, HOOK_EVENT_DEPLOYMENT, HOOK_EVENT_WIKI:
+		payload = getDiscordRepositoryEventPayload(p.(repositoryEventPayload))
 	default:
 		return nil, errors.Errorf("unexpected event %q", event)
 	}
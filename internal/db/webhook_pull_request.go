@@ -0,0 +1,22 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	api "github.com/gogs/go-gogs-client"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// MergedPullRequestPayload is the payload of pull request events of merged pull
+// requests, which extends the payload provided by the API client with states
+// of the merge checklist.
+type MergedPullRequestPayload struct {
+	*api.PullRequestPayload
+	MergeChecklist []*MergeChecklistItem `json:"merge_checklist,omitempty"`
+}
+
+func (p *MergedPullRequestPayload) JSONPayload() ([]byte, error) {
+	return jsoniter.MarshalIndent(p, "", "  ")
+}
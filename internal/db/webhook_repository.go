@@ -0,0 +1,175 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"strings"
+
+	api "github.com/gogs/go-gogs-client"
+	jsoniter "github.com/json-iterator/go"
+	log "unknwon.dev/clog/v2"
+)
+
+// Payloads of star, watch and repository events, which are not provided by the
+// API client.
+
+type StarPayload struct {
+	// Action is either "created" or "deleted".
+	Action     string          `json:"action"`
+	Repository *api.Repository `json:"repository"`
+	Sender     *api.User       `json:"sender"`
+}
+
+func (p *StarPayload) JSONPayload() ([]byte, error) {
+	return jsoniter.MarshalIndent(p, "", "  ")
+}
+
+func (p *StarPayload) describe() string {
+	if p.Action == "deleted" {
+		return "unstarred by " + p.Sender.UserName
+	}
+	return "starred by " + p.Sender.UserName
+}
+
+type WatchPayload struct {
+	// Action is either "started" or "stopped".
+	Action     string          `json:"action"`
+	Repository *api.Repository `json:"repository"`
+	Sender     *api.User       `json:"sender"`
+}
+
+func (p *WatchPayload) JSONPayload() ([]byte, error) {
+	return jsoniter.MarshalIndent(p, "", "  ")
+}
+
+func (p *WatchPayload) describe() string {
+	if p.Action == "stopped" {
+		return "unwatched by " + p.Sender.UserName
+	}
+	return "watched by " + p.Sender.UserName
+}
+
+const (
+	RepositoryActionTopicsUpdated = "topics_updated"
+	RepositoryActionPublicized    = "publicized"
+	RepositoryActionPrivatized    = "privatized"
+)
+
+type RepositoryTopicsChange struct {
+	From []string `json:"from"`
+	To   []string `json:"to"`
+}
+
+type RepositoryChangesPayload struct {
+	Topics *RepositoryTopicsChange `json:"topics,omitempty"`
+}
+
+type RepositoryPayload struct {
+	// Action is one of "topics_updated", "publicized" and "privatized".
+	Action     string                    `json:"action"`
+	Changes    *RepositoryChangesPayload `json:"changes,omitempty"`
+	Repository *api.Repository           `json:"repository"`
+	Sender     *api.User                 `json:"sender"`
+}
+
+func (p *RepositoryPayload) JSONPayload() ([]byte, error) {
+	return jsoniter.MarshalIndent(p, "", "  ")
+}
+
+func (p *RepositoryPayload) describe() string {
+	switch p.Action {
+	case RepositoryActionTopicsUpdated:
+		topics := "none"
+		if p.Changes != nil && p.Changes.Topics != nil && len(p.Changes.Topics.To) > 0 {
+			topics = strings.Join(p.Changes.Topics.To, ", ")
+		}
+		return "topics changed to " + topics + " by " + p.Sender.UserName
+	case RepositoryActionPublicized:
+		return "made public by " + p.Sender.UserName
+	case RepositoryActionPrivatized:
+		return "made private by " + p.Sender.UserName
+	}
+	return p.Action + " by " + p.Sender.UserName
+}
+
+// repositoryEventPayload is implemented by payloads of star, watch, repository,
+// deployment and wiki events to be converted for chat services.
+type repositoryEventPayload interface {
+	api.Payloader
+	describe() string
+	repo() *api.Repository
+	sender() *api.User
+}
+
+func (p *StarPayload) repo() *api.Repository       { return p.Repository }
+func (p *StarPayload) sender() *api.User           { return p.Sender }
+func (p *WatchPayload) repo() *api.Repository      { return p.Repository }
+func (p *WatchPayload) sender() *api.User          { return p.Sender }
+func (p *RepositoryPayload) repo() *api.Repository { return p.Repository }
+func (p *RepositoryPayload) sender() *api.User     { return p.Sender }
+
+// StarRepository stars or unstars the repository for the doer, and sends star
+// webhooks when the state is changed.
+func StarRepository(doer *User, repo *Repository, star bool) error {
+	if IsStaring(doer.ID, repo.ID) == star {
+		return nil
+	}
+	if err := StarRepo(doer.ID, repo.ID, star); err != nil {
+		return err
+	}
+
+	action := "created"
+	if !star {
+		action = "deleted"
+	}
+	if err := PrepareWebhooks(repo, HOOK_EVENT_STAR, &StarPayload{
+		Action:     action,
+		Repository: repo.APIFormatLegacy(nil),
+		Sender:     doer.APIFormat(),
+	}); err != nil {
+		log.Error("PrepareWebhooks [repo_id: %d]: %v", repo.ID, err)
+	}
+	return nil
+}
+
+// WatchRepository watches or unwatches the repository for the doer, and sends
+// watch webhooks when the state is changed.
+func WatchRepository(doer *User, repo *Repository, watch bool) error {
+	if IsWatching(doer.ID, repo.ID) == watch {
+		return nil
+	}
+	if err := WatchRepo(doer.ID, repo.ID, watch); err != nil {
+		return err
+	}
+
+	action := "started"
+	if !watch {
+		action = "stopped"
+	}
+	if err := PrepareWebhooks(repo, HOOK_EVENT_WATCH, &WatchPayload{
+		Action:     action,
+		Repository: repo.APIFormatLegacy(nil),
+		Sender:     doer.APIFormat(),
+	}); err != nil {
+		log.Error("PrepareWebhooks [repo_id: %d]: %v", repo.ID, err)
+	}
+	return nil
+}
+
+// PrepareVisibilityWebhook sends repository webhooks about the repository
+// being made public or private according to its current visibility.
+func PrepareVisibilityWebhook(doer *User, repo *Repository) {
+	action := RepositoryActionPublicized
+	if repo.IsPrivate {
+		action = RepositoryActionPrivatized
+	}
+	if err := PrepareWebhooks(repo, HOOK_EVENT_REPOSITORY, &RepositoryPayload{
+		Action:     action,
+		Repository: repo.APIFormatLegacy(nil),
+		Sender:     doer.APIFormat(),
+	}); err != nil {
+		log.Error("PrepareWebhooks [repo_id: %d]: %v", repo.ID, err)
+	}
+}
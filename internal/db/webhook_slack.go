@@ -102,6 +102,13 @@ func getSlackForkPayload(p *api.ForkPayload) *SlackPayload {
 	}
 }
 
+func getSlackRepositoryEventPayload(p repositoryEventPayload) *SlackPayload {
+	repoLink := SlackLinkFormatter(p.repo().HTMLURL, p.repo().FullName)
+	return &SlackPayload{
+		Text: fmt.Sprintf("[%s] Repository %s", repoLink, p.describe()),
+	}
+}
+
 func getSlackPushPayload(p *api.PushPayload, slack *SlackMeta) *SlackPayload {
 	// n new commits
 	var (
@@ -311,6 +318,8 @@ func GetSlackPayload(p api.Payloader, event HookEventType, meta string) (payload
 		payload = getSlackPullRequestPayload(p.(*api.PullRequestPayload), slack)
 	case HOOK_EVENT_RELEASE:
 		payload = getSlackReleasePayload(p.(*api.ReleasePayload))
+	case HOOK_EVENT_STAR, HOOK_EVENT_WATCH, HOOK_EVENT_REPOSITORY, HOOK_EVENT_DEPLOYMENT, HOOK_EVENT_WIKI:
+		payload = getSlackRepositoryEventPayload(p.(repositoryEventPayload))
 	default:
 		return nil, errors.Errorf("unexpected event %q", event)
 	}
@@ -0,0 +1,83 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"fmt"
+
+	api "github.com/gogs/go-gogs-client"
+	jsoniter "github.com/json-iterator/go"
+	log "unknwon.dev/clog/v2"
+)
+
+const (
+	WikiActionCreated = "created"
+	WikiActionEdited  = "edited"
+	WikiActionDeleted = "deleted"
+)
+
+// WikiPageHookInfo is the wiki page in payloads of wiki events.
+type WikiPageHookInfo struct {
+	Title string `json:"title"`
+	// HTMLURL is empty for deleted pages.
+	HTMLURL string `json:"html_url,omitempty"`
+	Message string `json:"message"`
+}
+
+type WikiChangesPayload struct {
+	Title *api.ChangesFromPayload `json:"title,omitempty"`
+}
+
+type WikiPayload struct {
+	// Action is one of "created", "edited" and "deleted".
+	Action     string              `json:"action"`
+	Page       *WikiPageHookInfo   `json:"page"`
+	Changes    *WikiChangesPayload `json:"changes,omitempty"`
+	Repository *api.Repository     `json:"repository"`
+	Sender     *api.User           `json:"sender"`
+}
+
+func (p *WikiPayload) JSONPayload() ([]byte, error) {
+	return jsoniter.MarshalIndent(p, "", "  ")
+}
+
+func (p *WikiPayload) describe() string {
+	if p.Changes != nil && p.Changes.Title != nil {
+		return fmt.Sprintf("wiki page %q renamed to %q by %s", p.Changes.Title.From, p.Page.Title, p.Sender.UserName)
+	}
+	return fmt.Sprintf("wiki page %q %s by %s", p.Page.Title, p.Action, p.Sender.UserName)
+}
+
+func (p *WikiPayload) repo() *api.Repository { return p.Repository }
+func (p *WikiPayload) sender() *api.User     { return p.Sender }
+
+// prepareWikiWebhook sends wiki webhooks about the action on the wiki page of
+// the repository. The old title is only set for renamed pages.
+func prepareWikiWebhook(doer *User, repo *Repository, action, oldTitle, title, message string) {
+	page := &WikiPageHookInfo{
+		Title:   title,
+		Message: message,
+	}
+	if action != WikiActionDeleted {
+		page.HTMLURL = repo.HTMLURL() + "/wiki/" + ToWikiPageURL(title)
+	}
+
+	var changes *WikiChangesPayload
+	if oldTitle != "" && oldTitle != title {
+		changes = &WikiChangesPayload{
+			Title: &api.ChangesFromPayload{From: oldTitle},
+		}
+	}
+
+	if err := PrepareWebhooks(repo, HOOK_EVENT_WIKI, &WikiPayload{
+		Action:     action,
+		Page:       page,
+		Changes:    changes,
+		Repository: repo.APIFormatLegacy(nil),
+		Sender:     doer.APIFormat(),
+	}); err != nil {
+		log.Error("PrepareWebhooks [repo_id: %d]: %v", repo.ID, err)
+	}
+}
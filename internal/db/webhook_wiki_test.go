@@ -0,0 +1,49 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"testing"
+
+	api "github.com/gogs/go-gogs-client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWikiPayload(t *testing.T) {
+	sender := &api.User{UserName: "alice"}
+
+	p := &WikiPayload{
+		Action: WikiActionEdited,
+		Page: &WikiPageHookInfo{
+			Title:   "Install",
+			HTMLURL: "https://gogs.example.com/alice/app/wiki/Install",
+			Message: "Update page 'Install'",
+		},
+		Changes: &WikiChangesPayload{
+			Title: &api.ChangesFromPayload{From: "Setup"},
+		},
+		Repository: &api.Repository{FullName: "alice/app"},
+		Sender:     sender,
+	}
+	assert.Equal(t, `wiki page "Setup" renamed to "Install" by alice`, p.describe())
+
+	data, err := p.JSONPayload()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"action": "edited"`)
+	assert.Contains(t, string(data), `"from": "Setup"`)
+
+	p = &WikiPayload{
+		Action: WikiActionDeleted,
+		Page:   &WikiPageHookInfo{Title: "Install"},
+		Sender: sender,
+	}
+	assert.Equal(t, `wiki page "Install" deleted by alice`, p.describe())
+
+	data, err = p.JSONPayload()
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "html_url")
+	assert.NotContains(t, string(data), "changes")
+}
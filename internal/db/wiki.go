@@ -46,11 +46,15 @@ func (repo *Repository) WikiCloneLink() (cl *repoutil.CloneLink) {
 
 // WikiPath returns wiki data path by given user and repository name.
 func WikiPath(userName, repoName string) string {
-	return filepath.Join(UserPath(userName), strings.ToLower(repoName)+".wiki.git")
+	return shardWikiPath(repoStorageShard(userName, repoName), userName, repoName)
+}
+
+func shardWikiPath(shard int, userName, repoName string) string {
+	return filepath.Join(repoutil.ShardUserPath(shard, userName), strings.ToLower(repoName)+".wiki.git")
 }
 
 func (repo *Repository) WikiPath() string {
-	return WikiPath(repo.MustOwner().Name, repo.Name)
+	return shardWikiPath(repo.StorageShard, repo.MustOwner().Name, repo.Name)
 }
 
 // HasWiki returns true if repository has wiki.
@@ -136,6 +140,11 @@ func (repo *Repository) updateWikiPage(doer *User, oldTitle, title, content, mes
 		return fmt.Errorf("push: %v", err)
 	}
 
+	action := WikiActionEdited
+	if isNew {
+		action = WikiActionCreated
+	}
+	prepareWikiWebhook(doer, repo, action, oldTitle, title, message)
 	return nil
 }
 
@@ -172,5 +181,6 @@ func (repo *Repository) DeleteWikiPage(doer *User, title string) (err error) {
 		return fmt.Errorf("push: %v", err)
 	}
 
+	prepareWikiWebhook(doer, repo, WikiActionDeleted, "", title, message)
 	return nil
 }
@@ -0,0 +1,161 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package downloadlimit limits concurrency and bandwidth of repository archive
+// and raw file downloads, which are expensive enough to take down an instance
+// when abused.
+package downloadlimit
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/macaron.v1"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+)
+
+// Endpoint is the kind of download endpoints.
+type Endpoint string
+
+const (
+	EndpointArchive Endpoint = "archive"
+	EndpointRaw     Endpoint = "raw"
+)
+
+// retryAfter is the number of seconds suggested to clients to retry after
+// their requests are rejected.
+const retryAfter = 10
+
+var (
+	rejected = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gogs_download_rejected_total",
+			Help: "Number of archive and raw file downloads rejected by limits, the limit is one of client_concurrency and archive_concurrency.",
+		},
+		[]string{"endpoint", "limit"},
+	)
+	inProgress = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gogs_downloads_in_progress",
+			Help: "Number of archive and raw file downloads in progress.",
+		},
+		[]string{"endpoint"},
+	)
+	archivesInProgress = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "gogs_download_archives_generating",
+			Help: "Number of repository archives being generated.",
+		},
+	)
+	throttled = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gogs_download_throttled_seconds_total",
+			Help: "Time spent waiting by archive and raw file downloads due to bandwidth limits.",
+		},
+		[]string{"endpoint"},
+	)
+)
+
+// Collectors returns the Prometheus collectors of downloads.
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{rejected, inProgress, archivesInProgress, throttled}
+}
+
+var (
+	limitersOnce sync.Once
+	downloads    *Limiter
+	archives     *Limiter
+)
+
+func initLimiters() {
+	limitersOnce.Do(func() {
+		opts := conf.Repository.Download
+		downloads = NewLimiter(opts.MaxConcurrentPerClient, opts.MaxBandwidthPerClient*1024)
+		// All archives share the same client so that the limit is global.
+		archives = NewLimiter(opts.MaxConcurrentArchives, 0)
+	})
+}
+
+// clientKey returns the key to identify the client of the request. Clients
+// authenticated with access tokens are identified by the user, so that
+// automations running on many hosts share the same limits.
+func clientKey(c *context.Context) string {
+	if c.IsTokenAuth && c.IsLogged {
+		return "user:" + strconv.FormatInt(c.User.ID, 10)
+	}
+	return "ip:" + c.RemoteAddr()
+}
+
+func tooManyRequests(c *context.Context, msg string) {
+	c.Resp.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	c.PlainText(http.StatusTooManyRequests, msg)
+}
+
+// responseWriter writes the response body through a bandwidth limited writer.
+type responseWriter struct {
+	macaron.ResponseWriter
+	w io.Writer
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	return w.w.Write(p)
+}
+
+// Limit returns a middleware that limits the number of concurrent downloads
+// and the bandwidth of each client for the endpoint.
+func Limit(endpoint Endpoint) macaron.Handler {
+	return func(c *context.Context) {
+		initLimiters()
+
+		key := clientKey(c)
+		d, ok := downloads.Acquire(key)
+		if !ok {
+			rejected.WithLabelValues(string(endpoint), "client_concurrency").Inc()
+			log.Trace("Download rejected for too many concurrent downloads: %s %s", key, c.Req.URL.Path)
+			tooManyRequests(c, "Too many concurrent downloads, please retry later.")
+			return
+		}
+		defer d.Release()
+
+		gauge := inProgress.WithLabelValues(string(endpoint))
+		gauge.Inc()
+		defer gauge.Dec()
+
+		if conf.Repository.Download.MaxBandwidthPerClient > 0 {
+			w := d.Writer(c.Resp, func(wait time.Duration) {
+				throttled.WithLabelValues(string(endpoint)).Add(wait.Seconds())
+			})
+			c.Resp = &responseWriter{ResponseWriter: c.Resp, w: w}
+		}
+		c.Next()
+	}
+}
+
+// AcquireArchive reserves a slot to generate an archive. The release must be
+// called once the archive is generated. It writes a 429 response and returns
+// false when too many archives are being generated.
+func AcquireArchive(c *context.Context) (release func(), ok bool) {
+	initLimiters()
+
+	d, ok := archives.Acquire("")
+	if !ok {
+		rejected.WithLabelValues(string(EndpointArchive), "archive_concurrency").Inc()
+		log.Trace("Archive generation rejected for too many concurrent archives: %s", c.Req.URL.Path)
+		tooManyRequests(c, "Too many archives are being generated, please retry later.")
+		return nil, false
+	}
+
+	archivesInProgress.Inc()
+	return func() {
+		archivesInProgress.Dec()
+		d.Release()
+	}, true
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package downloadlimit
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// Limiter limits the number of concurrent downloads and the bandwidth of each
+// client identified by a key.
+type Limiter struct {
+	maxConcurrent  int
+	bytesPerSecond int64
+
+	mu      sync.Mutex
+	clients map[string]*client
+}
+
+type client struct {
+	active int
+	// bucket is shared by all downloads of the client, nil when the bandwidth
+	// is not limited.
+	bucket *bucket
+}
+
+// NewLimiter returns a new limiter allowing at most maxConcurrent downloads and
+// bytesPerSecond bandwidth for each client, 0 means no limit.
+func NewLimiter(maxConcurrent int, bytesPerSecond int64) *Limiter {
+	return &Limiter{
+		maxConcurrent:  maxConcurrent,
+		bytesPerSecond: bytesPerSecond,
+		clients:        make(map[string]*client),
+	}
+}
+
+// Download is a download in progress acquired from a Limiter.
+type Download struct {
+	l      *Limiter
+	key    string
+	bucket *bucket
+	once   sync.Once
+}
+
+// Acquire starts a new download of the client with given key. It returns false
+// if the client has reached the maximum number of concurrent downloads.
+func (l *Limiter) Acquire(key string) (*Download, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	c := l.clients[key]
+	if c == nil {
+		c = new(client)
+		if l.bytesPerSecond > 0 {
+			c.bucket = newBucket(l.bytesPerSecond, time.Now())
+		}
+		l.clients[key] = c
+	} else if l.maxConcurrent > 0 && c.active >= l.maxConcurrent {
+		return nil, false
+	}
+	c.active++
+	return &Download{l: l, key: key, bucket: c.bucket}, true
+}
+
+// Active returns the number of downloads in progress of the client with given
+// key.
+func (l *Limiter) Active(key string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if c := l.clients[key]; c != nil {
+		return c.active
+	}
+	return 0
+}
+
+// Release ends the download. It is safe to call more than once.
+func (d *Download) Release() {
+	d.once.Do(func() {
+		d.l.mu.Lock()
+		defer d.l.mu.Unlock()
+
+		c := d.l.clients[d.key]
+		if c == nil {
+			return
+		}
+		c.active--
+		if c.active <= 0 {
+			delete(d.l.clients, d.key)
+		}
+	})
+}
+
+// Writer returns a writer to w that is limited to the bandwidth of the client,
+// or w itself when the bandwidth is not limited. The onWait is called with the
+// duration of each wait, it may be nil.
+func (d *Download) Writer(w io.Writer, onWait func(time.Duration)) io.Writer {
+	if d.bucket == nil {
+		return w
+	}
+	return &writer{w: w, bucket: d.bucket, onWait: onWait}
+}
+
+type writer struct {
+	w      io.Writer
+	bucket *bucket
+	onWait func(time.Duration)
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		chunk := p
+		if int64(len(chunk)) > w.bucket.burst {
+			chunk = chunk[:w.bucket.burst]
+		}
+
+		if wait := w.bucket.reserve(time.Now(), len(chunk)); wait > 0 {
+			if w.onWait != nil {
+				w.onWait(wait)
+			}
+			time.Sleep(wait)
+		}
+
+		n, err := w.w.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// bucket is a token bucket of bytes shared by concurrent writers.
+type bucket struct {
+	rate  int64
+	burst int64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// newBucket returns a new full bucket refilled by rate bytes per second, which
+// holds at most one second worth of bytes.
+func newBucket(rate int64, now time.Time) *bucket {
+	return &bucket{
+		rate:   rate,
+		burst:  rate,
+		tokens: float64(rate),
+		last:   now,
+	}
+}
+
+// reserve takes n bytes from the bucket and returns how long to wait before
+// writing them. Bytes are taken even when the bucket does not have enough, so
+// that concurrent writers wait in turns.
+func (b *bucket) reserve(now time.Time, n int) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if now.After(b.last) {
+		b.tokens += now.Sub(b.last).Seconds() * float64(b.rate)
+		if b.tokens > float64(b.burst) {
+			b.tokens = float64(b.burst)
+		}
+		b.last = now
+	}
+
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / float64(b.rate) * float64(time.Second))
+}
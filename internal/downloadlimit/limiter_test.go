@@ -0,0 +1,102 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package downloadlimit
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_Acquire(t *testing.T) {
+	l := NewLimiter(2, 0)
+
+	d1, ok := l.Acquire("alice")
+	require.True(t, ok)
+	d2, ok := l.Acquire("alice")
+	require.True(t, ok)
+	_, ok = l.Acquire("alice")
+	assert.False(t, ok)
+	assert.Equal(t, 2, l.Active("alice"))
+
+	// Limits are per client.
+	d3, ok := l.Acquire("bob")
+	require.True(t, ok)
+
+	d1.Release()
+	// Releasing again does not free another slot.
+	d1.Release()
+	assert.Equal(t, 1, l.Active("alice"))
+	d4, ok := l.Acquire("alice")
+	require.True(t, ok)
+	_, ok = l.Acquire("alice")
+	assert.False(t, ok)
+
+	d2.Release()
+	d3.Release()
+	d4.Release()
+	assert.Empty(t, l.clients)
+}
+
+func TestLimiter_Unlimited(t *testing.T) {
+	l := NewLimiter(0, 0)
+	for i := 0; i < 100; i++ {
+		_, ok := l.Acquire("alice")
+		require.True(t, ok)
+	}
+	assert.Equal(t, 100, l.Active("alice"))
+
+	d, _ := l.Acquire("alice")
+	var buf bytes.Buffer
+	assert.Equal(t, &buf, d.Writer(&buf, nil))
+}
+
+func TestBucket_Reserve(t *testing.T) {
+	now := time.Now()
+	b := newBucket(1000, now)
+
+	// The bucket starts full.
+	assert.Equal(t, time.Duration(0), b.reserve(now, 600))
+	assert.Equal(t, time.Duration(0), b.reserve(now, 400))
+	assert.Equal(t, 500*time.Millisecond, b.reserve(now, 500))
+
+	// Concurrent writers wait in turns.
+	assert.Equal(t, time.Second, b.reserve(now, 500))
+
+	// Refilled over time.
+	now = now.Add(2 * time.Second)
+	assert.Equal(t, time.Duration(0), b.reserve(now, 1000))
+
+	// Never holds more than one second worth of bytes.
+	now = now.Add(time.Hour)
+	assert.Equal(t, 500*time.Millisecond, b.reserve(now, 1500))
+}
+
+func TestDownload_Writer(t *testing.T) {
+	l := NewLimiter(0, 1000)
+	d, ok := l.Acquire("alice")
+	require.True(t, ok)
+	defer d.Release()
+
+	var waited time.Duration
+	var buf bytes.Buffer
+	w := d.Writer(&buf, func(wait time.Duration) {
+		waited += wait
+	})
+
+	data := bytes.Repeat([]byte("a"), 1500)
+	start := time.Now()
+	n, err := w.Write(data)
+	require.NoError(t, err)
+	assert.Equal(t, len(data), n)
+	assert.Equal(t, data, buf.Bytes())
+
+	// The first second worth of bytes is written right away.
+	assert.InDelta(t, 500*time.Millisecond, waited, float64(50*time.Millisecond))
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(400*time.Millisecond))
+}
@@ -7,6 +7,7 @@ package email
 import (
 	"fmt"
 	"html/template"
+	"io"
 	"path/filepath"
 	"sync"
 	"time"
@@ -25,11 +26,17 @@ const (
 	MAIL_AUTH_ACTIVATE_EMAIL  = "auth/activate_email"
 	MAIL_AUTH_RESET_PASSWORD  = "auth/reset_passwd"
 	MAIL_AUTH_REGISTER_NOTIFY = "auth/register_notify"
+	MAIL_AUTH_SET_PASSWORD    = "auth/set_passwd"
 
 	MAIL_ISSUE_COMMENT = "issue/comment"
 	MAIL_ISSUE_MENTION = "issue/mention"
+	MAIL_ISSUE_REVIEW  = "issue/review_request"
 
-	MAIL_NOTIFY_COLLABORATOR = "notify/collaborator"
+	MAIL_NOTIFY_ACTIVITY_DIGEST = "notify/activity_digest"
+	MAIL_NOTIFY_COLLABORATOR    = "notify/collaborator"
+	MAIL_NOTIFY_ORG_TWO_FACTOR  = "notify/org_two_factor"
+	MAIL_NOTIFY_SECURITY_ALERT  = "notify/security_alert"
+	MAIL_NOTIFY_USAGE_REPORT    = "notify/usage_report"
 )
 
 var (
@@ -106,7 +113,7 @@ func SendUserMail(_ *macaron.Context, u User, tpl, code, subject, info string) {
 	data := map[string]interface{}{
 		"Username":          u.DisplayName(),
 		"ActiveCodeLives":   conf.Auth.ActivateCodeLives / 60,
-		"ResetPwdCodeLives": conf.Auth.ResetPasswordCodeLives / 60,
+		"ResetPwdCodeLives": conf.Auth.ResetPasswordCodeLives,
 		"Code":              code,
 	}
 	body, err := render(tpl, data)
@@ -125,8 +132,16 @@ func SendActivateAccountMail(c *macaron.Context, u User) {
 	SendUserMail(c, u, MAIL_AUTH_ACTIVATE, u.GenerateActivateCode(), c.Tr("mail.activate_account"), "activate account")
 }
 
-func SendResetPasswordMail(c *macaron.Context, u User) {
-	SendUserMail(c, u, MAIL_AUTH_RESET_PASSWORD, u.GenerateActivateCode(), c.Tr("mail.reset_password"), "reset password")
+// SendResetPasswordMail sends the password reset code to the user.
+func SendResetPasswordMail(c *macaron.Context, u User, code string) {
+	SendUserMail(c, u, MAIL_AUTH_RESET_PASSWORD, code, c.Tr("mail.reset_password"), "reset password")
+}
+
+// SendSetPasswordMail sends the password reset code to the user whose account
+// was created by an admin, so that the user sets their own password.
+func SendSetPasswordMail(u User, code string) {
+	subject := fmt.Sprintf("Your account on %s has been created", conf.App.BrandName)
+	SendUserMail(nil, u, MAIL_AUTH_SET_PASSWORD, code, subject, "set password")
 }
 
 // SendActivateAccountMail sends confirmation email.
@@ -187,6 +202,142 @@ func SendCollaboratorMail(u, doer User, repo Repository) {
 	Send(msg)
 }
 
+// SendOrgTwoFactorRequiredMail sends mail notification to members of the
+// organization who have not enabled two-factor authentication that the
+// organization requires it from the deadline.
+func SendOrgTwoFactorRequiredMail(tos []string, orgName string, deadline time.Time, link string) {
+	if len(tos) == 0 {
+		return
+	}
+
+	subject := fmt.Sprintf("%s requires two-factor authentication", orgName)
+	data := map[string]interface{}{
+		"Subject":  subject,
+		"OrgName":  orgName,
+		"Deadline": deadline.Format(time.RFC1123),
+		"Link":     link,
+	}
+	body, err := render(MAIL_NOTIFY_ORG_TWO_FACTOR, data)
+	if err != nil {
+		log.Error("HTMLString: %v", err)
+		return
+	}
+
+	msg := NewMessage(tos, subject, body)
+	msg.Info = fmt.Sprintf("Subject: %s, organization two-factor requirement", subject)
+
+	Send(msg)
+}
+
+// SendSecurityAlertMail sends mail notification about new security alerts of
+// the repository to target receivers.
+func SendSecurityAlertMail(repo Repository, tos, alerts []string, link string) {
+	if len(tos) == 0 {
+		return
+	}
+
+	subject := fmt.Sprintf("[%s] Vulnerable dependencies detected", repo.FullName())
+	data := map[string]interface{}{
+		"Subject":  subject,
+		"RepoName": repo.FullName(),
+		"Alerts":   alerts,
+		"Link":     link,
+	}
+	body, err := render(MAIL_NOTIFY_SECURITY_ALERT, data)
+	if err != nil {
+		log.Error("HTMLString: %v", err)
+		return
+	}
+
+	msg := NewMessage(tos, subject, body)
+	msg.Info = fmt.Sprintf("Subject: %s, security alert", subject)
+
+	Send(msg)
+}
+
+// UsageReportOwner is the usage of an owner to be listed in usage report emails.
+type UsageReportOwner struct {
+	Name          string
+	NumRepos      int64
+	DiskUsage     string
+	BytesSent     string
+	BytesReceived string
+	NumActions    int64
+}
+
+// SendUsageReportMail sends the usage report of the instance between since and
+// until, with the full report attached as a CSV file.
+func SendUsageReportMail(tos []string, since, until time.Time, owners []UsageReportOwner, csv []byte, link string) {
+	subject := fmt.Sprintf("Usage report from %s to %s", since.Format("2006-01-02"), until.Format("2006-01-02"))
+	data := map[string]interface{}{
+		"Subject": subject,
+		"Owners":  owners,
+		"Link":    link,
+	}
+	body, err := render(MAIL_NOTIFY_USAGE_REPORT, data)
+	if err != nil {
+		log.Error("HTMLString: %v", err)
+		return
+	}
+
+	msg := NewMessage(tos, subject, body)
+	msg.Attach(
+		fmt.Sprintf("usage-%s.csv", until.Format("20060102")),
+		gomail.SetHeader(map[string][]string{"Content-Type": {"text/csv; charset=utf-8"}}),
+		gomail.SetCopyFunc(func(w io.Writer) error {
+			_, err := w.Write(csv)
+			return err
+		}),
+	)
+	msg.Info = fmt.Sprintf("Subject: %s, usage report", subject)
+
+	Send(msg)
+}
+
+// DigestItem is an issue, a merged pull request or a release to be listed in
+// activity digest emails.
+type DigestItem struct {
+	ActorName string
+	Ref       string
+	Title     string
+	Link      string
+}
+
+// DigestRepository is a repository with its activities to be listed in
+// activity digest emails.
+type DigestRepository struct {
+	Name     string
+	Link     string
+	Issues   []DigestItem
+	Merges   []DigestItem
+	Releases []DigestItem
+}
+
+// SendActivityDigestMail sends the activity digest of watched repositories
+// between since and until to the user, delivery is deferred until deliverAt
+// when not zero.
+func SendActivityDigestMail(u User, frequency string, since, until time.Time, repos []DigestRepository, unsubscribeLink string, deliverAt time.Time) {
+	subject := fmt.Sprintf("Your %s digest from %s to %s", frequency, since.Format("2006-01-02"), until.Format("2006-01-02"))
+	data := map[string]interface{}{
+		"Subject":         subject,
+		"Username":        u.DisplayName(),
+		"Repos":           repos,
+		"UnsubscribeLink": unsubscribeLink,
+	}
+	body, err := render(MAIL_NOTIFY_ACTIVITY_DIGEST, data)
+	if err != nil {
+		log.Error("HTMLString: %v", err)
+		return
+	}
+
+	msg := NewMessage([]string{u.Email()}, subject, body)
+	msg.SetHeader("List-Unsubscribe", "<"+unsubscribeLink+">")
+	msg.Info = fmt.Sprintf("UID: %d, %s activity digest", u.ID(), frequency)
+	msg.DeliverAt = deliverAt
+
+	Send(msg)
+}
+
 func composeTplData(subject, body, link string) map[string]interface{} {
 	data := make(map[string]interface{}, 10)
 	data["Subject"] = subject
@@ -195,7 +346,7 @@ func composeTplData(subject, body, link string) map[string]interface{} {
 	return data
 }
 
-func composeIssueMessage(issue Issue, repo Repository, doer User, tplName string, tos []string, info string) *Message {
+func composeIssueMessage(issue Issue, repo Repository, doer User, tplName string, tos []string, info string, deliverAt time.Time) *Message {
 	subject := issue.MailSubject()
 	body := string(markup.Markdown([]byte(issue.Content()), repo.HTMLURL(), repo.ComposeMetas()))
 	data := composeTplData(subject, body, issue.HTMLURL())
@@ -207,22 +358,35 @@ func composeIssueMessage(issue Issue, repo Repository, doer User, tplName string
 	from := gomail.NewMessage().FormatAddress(conf.Email.FromEmail, doer.DisplayName())
 	msg := NewMessageFrom(tos, from, subject, content)
 	msg.Info = fmt.Sprintf("Subject: %s, %s", subject, info)
+	msg.DeliverAt = deliverAt
 	return msg
 }
 
-// SendIssueCommentMail composes and sends issue comment emails to target receivers.
-func SendIssueCommentMail(issue Issue, repo Repository, doer User, tos []string) {
+// SendIssueCommentMail composes and sends issue comment emails to target
+// receivers, delivery is deferred until deliverAt when not zero.
+func SendIssueCommentMail(issue Issue, repo Repository, doer User, tos []string, deliverAt time.Time) {
 	if len(tos) == 0 {
 		return
 	}
 
-	Send(composeIssueMessage(issue, repo, doer, MAIL_ISSUE_COMMENT, tos, "issue comment"))
+	Send(composeIssueMessage(issue, repo, doer, MAIL_ISSUE_COMMENT, tos, "issue comment", deliverAt))
+}
+
+// SendIssueMentionMail composes and sends issue mention emails to target
+// receivers, delivery is deferred until deliverAt when not zero.
+func SendIssueMentionMail(issue Issue, repo Repository, doer User, tos []string, deliverAt time.Time) {
+	if len(tos) == 0 {
+		return
+	}
+	Send(composeIssueMessage(issue, repo, doer, MAIL_ISSUE_MENTION, tos, "issue mention", deliverAt))
 }
 
-// SendIssueMentionMail composes and sends issue mention emails to target receivers.
-func SendIssueMentionMail(issue Issue, repo Repository, doer User, tos []string) {
+// SendIssueReviewRequestMail composes and sends pull request review request
+// emails to target receivers, delivery is deferred until deliverAt when not
+// zero.
+func SendIssueReviewRequestMail(issue Issue, repo Repository, doer User, tos []string, deliverAt time.Time) {
 	if len(tos) == 0 {
 		return
 	}
-	Send(composeIssueMessage(issue, repo, doer, MAIL_ISSUE_MENTION, tos, "issue mention"))
+	Send(composeIssueMessage(issue, repo, doer, MAIL_ISSUE_REVIEW, tos, "review request", deliverAt))
 }
@@ -24,6 +24,9 @@ import (
 type Message struct {
 	Info string // Message information for log purpose.
 	*gomail.Message
+	// DeliverAt defers delivery of the message until the time when not zero. It
+	// only takes effect when a queue is in use (see UseQueue).
+	DeliverAt   time.Time
 	confirmChan chan struct{}
 }
 
@@ -231,7 +234,18 @@ func NewContext() {
 // Send puts new message object into mail queue.
 // It returns without confirmation (mail processed asynchronously) in normal cases,
 // but waits/blocks under hook mode to make sure mail has been sent.
+//
+// Messages are persisted when a queue is in use (see UseQueue), and fall back to
+// the in-memory queue when they cannot be persisted.
 func Send(msg *Message) {
+	if queue != nil {
+		err := enqueue(msg)
+		if err == nil {
+			return
+		}
+		log.Error("Failed to persist e-mail %s: %s - %v", msg.GetHeader("To"), msg.Info, err)
+	}
+
 	mailQueue <- msg
 
 	if conf.HookMode {
@@ -0,0 +1,214 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package email
+
+import (
+	"bytes"
+	"context"
+	"net/mail"
+	"time"
+
+	"github.com/pkg/errors"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+)
+
+const (
+	// MaxAttempts is the number of delivery attempts of a queued message before
+	// it is marked as failed.
+	MaxAttempts = 8
+	// retryBackoff is the delay before the second delivery attempt, which is
+	// doubled after each failed attempt.
+	retryBackoff = time.Minute
+	// queuePollInterval is the interval to check for messages due for delivery.
+	queuePollInterval = time.Minute
+	// queueBatchSize is the maximum number of messages to deliver in a batch.
+	queueBatchSize = 100
+)
+
+// QueuedMessage is an outgoing message persisted in the Queue.
+type QueuedMessage struct {
+	ID      int64
+	From    string
+	To      []string
+	Subject string
+	Info    string
+	// Body is the raw message including headers.
+	Body     []byte
+	Attempts int
+	// NextAttempt is the time when the message is due for delivery.
+	NextAttempt time.Time
+}
+
+// Queue is the persistent storage of outgoing messages, so that messages are
+// not lost when delivery fails or the server restarts.
+type Queue interface {
+	// Enqueue persists a new message and sets its ID.
+	Enqueue(ctx context.Context, msg *QueuedMessage) error
+	// ListDue returns at most limit pending messages that are due for delivery,
+	// ordered by the time they are due.
+	ListDue(ctx context.Context, limit int) ([]*QueuedMessage, error)
+	// MarkFailed marks the message as failed with the last error, it is no
+	// longer retried.
+	MarkFailed(ctx context.Context, id int64, lastErr string) error
+	// MarkRetry records a failed delivery attempt of the message with the last
+	// error and schedules the next attempt at retryAt.
+	MarkRetry(ctx context.Context, id int64, lastErr string, retryAt time.Time) error
+	// MarkSent removes the delivered message from the queue.
+	MarkSent(ctx context.Context, id int64) error
+}
+
+var (
+	queue     Queue
+	queueWake = make(chan struct{}, 1)
+)
+
+// UseQueue makes the mail service persist outgoing messages in the queue and
+// retry failed deliveries with backoff. Only the web server delivers retries,
+// hooks attempt delivery of their own messages once and leave failures to the
+// web server.
+func UseQueue(q Queue) {
+	if !conf.Email.Enabled || queue != nil {
+		return
+	}
+
+	queue = q
+	if !conf.HookMode {
+		go processQueue()
+	}
+}
+
+// WakeQueue triggers delivery of messages that are due, e.g. after an admin
+// requeued failed messages.
+func WakeQueue() {
+	select {
+	case queueWake <- struct{}{}:
+	default:
+	}
+}
+
+// retryDelay returns the delay before the next attempt after given number of
+// failed attempts.
+func retryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return retryBackoff << uint(attempts-1)
+}
+
+// enqueue persists the message to the queue. In hook mode, it attempts the
+// delivery right away.
+func enqueue(msg *Message) error {
+	var body bytes.Buffer
+	if _, err := msg.WriteTo(&body); err != nil {
+		return errors.Wrap(err, "encode message")
+	}
+
+	from := msg.GetHeader("From")
+	if len(from) == 0 {
+		return errors.New("no sender")
+	}
+	qm := &QueuedMessage{
+		From:        from[0],
+		To:          msg.GetHeader("To"),
+		Info:        msg.Info,
+		Body:        body.Bytes(),
+		NextAttempt: time.Now(),
+	}
+	if subject := msg.GetHeader("Subject"); len(subject) > 0 {
+		qm.Subject = subject[0]
+	}
+	deferred := msg.DeliverAt.After(qm.NextAttempt)
+	if deferred {
+		qm.NextAttempt = msg.DeliverAt
+	} else if conf.HookMode {
+		// The web server would pick up the message while the hook is delivering it.
+		qm.NextAttempt = qm.NextAttempt.Add(retryDelay(1))
+	}
+
+	if err := queue.Enqueue(context.Background(), qm); err != nil {
+		return errors.Wrap(err, "enqueue")
+	}
+
+	if deferred {
+		log.Trace("E-mail %d deferred until %s: %s", qm.ID, qm.NextAttempt.Format(time.RFC3339), qm.Info)
+	} else if conf.HookMode {
+		deliver(qm)
+	} else {
+		WakeQueue()
+	}
+	return nil
+}
+
+// deliver sends the queued message and records the result in the queue.
+func deliver(msg *QueuedMessage) {
+	ctx := context.Background()
+	log.Trace("Delivering queued e-mail %d %s: %s", msg.ID, msg.To, msg.Info)
+
+	err := sendRaw(msg)
+	if err == nil {
+		log.Trace("Queued e-mail %d sent %s: %s", msg.ID, msg.To, msg.Info)
+		if err = queue.MarkSent(ctx, msg.ID); err != nil {
+			log.Error("Failed to mark queued e-mail %d as sent: %v", msg.ID, err)
+		}
+		return
+	}
+
+	attempts := msg.Attempts + 1
+	if attempts >= MaxAttempts {
+		log.Error("Failed to send queued e-mail %d %s after %d attempts, giving up: %s - %v", msg.ID, msg.To, attempts, msg.Info, err)
+		err = queue.MarkFailed(ctx, msg.ID, err.Error())
+	} else {
+		retryAt := time.Now().Add(retryDelay(attempts))
+		log.Warn("Failed to send queued e-mail %d %s, retrying at %s: %s - %v", msg.ID, msg.To, retryAt.Format(time.RFC3339), msg.Info, err)
+		err = queue.MarkRetry(ctx, msg.ID, err.Error(), retryAt)
+	}
+	if err != nil {
+		log.Error("Failed to record delivery attempt of queued e-mail %d: %v", msg.ID, err)
+	}
+}
+
+// sendRaw sends the raw body of the queued message to its recipients.
+func sendRaw(msg *QueuedMessage) error {
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return errors.Wrapf(err, "parse sender %q", msg.From)
+	}
+
+	to := make([]string, 0, len(msg.To))
+	for _, addr := range msg.To {
+		a, err := mail.ParseAddress(addr)
+		if err != nil {
+			return errors.Wrapf(err, "parse recipient %q", addr)
+		}
+		to = append(to, a.Address)
+	}
+	return (&Sender{}).Send(from.Address, to, bytes.NewReader(msg.Body))
+}
+
+func processQueue() {
+	ticker := time.NewTicker(queuePollInterval)
+	defer ticker.Stop()
+
+	for {
+		msgs, err := queue.ListDue(context.Background(), queueBatchSize)
+		if err != nil {
+			log.Error("Failed to list queued e-mails: %v", err)
+		}
+		for _, msg := range msgs {
+			deliver(msg)
+		}
+
+		// Deliver the next batch right away when the queue has more messages.
+		if len(msgs) == queueBatchSize {
+			continue
+		}
+		select {
+		case <-queueWake:
+		case <-ticker.C:
+		}
+	}
+}
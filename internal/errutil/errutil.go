@@ -4,6 +4,10 @@
 
 package errutil
 
+import (
+	"errors"
+)
+
 // NotFound represents a not found error.
 type NotFound interface {
 	NotFound() bool
@@ -17,3 +21,22 @@ func IsNotFound(err error) bool {
 
 // Args is a map of key-value pairs to provide additional context of an error.
 type Args map[string]interface{}
+
+// Coder represents an error with a stable, machine-readable code.
+type Coder interface {
+	ErrorCode() string
+}
+
+// Code returns the machine-readable code of the error, which is the code of
+// the first error in the chain that implements Coder, "not_found" for other
+// not found errors, or empty if none applies.
+func Code(err error) string {
+	var c Coder
+	if errors.As(err, &c) {
+		return c.ErrorCode()
+	}
+	if IsNotFound(err) {
+		return "not_found"
+	}
+	return ""
+}
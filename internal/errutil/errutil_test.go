@@ -6,6 +6,7 @@ package errutil
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -51,3 +52,47 @@ func TestIsNotFound(t *testing.T) {
 		})
 	}
 }
+
+type codeError struct{}
+
+func (codeError) Error() string {
+	return "coded"
+}
+
+func (codeError) ErrorCode() string {
+	return "coded_error"
+}
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		expCode string
+	}{
+		{
+			name:    "error does not implement Coder",
+			err:     errors.New("a simple error"),
+			expCode: "",
+		},
+		{
+			name:    "error implements Coder",
+			err:     codeError{},
+			expCode: "coded_error",
+		},
+		{
+			name:    "wrapped error implements Coder",
+			err:     fmt.Errorf("wrapped: %w", codeError{}),
+			expCode: "coded_error",
+		},
+		{
+			name:    "not found error",
+			err:     notFoundError{val: true},
+			expCode: "not_found",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expCode, Code(test.err))
+		})
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package events fans out live events of repositories and users to
+// subscribers, e.g. browsers connected via server-sent events.
+package events
+
+import (
+	"strconv"
+	"sync"
+)
+
+// Types of events.
+const (
+	// TypeIssueComment is published when a comment is created in an issue or a
+	// pull request, including comments of closing and reopening.
+	TypeIssueComment = "issue.comment"
+	// TypeIssueEdited is published when the content of an issue or a comment
+	// is changed.
+	TypeIssueEdited = "issue.edited"
+	// TypeCommitStatus is published when a commit status is created.
+	TypeCommitStatus = "commit.status"
+	// TypeNotification is published to users involved in an issue or a pull
+	// request when there is new activity.
+	TypeNotification = "notification"
+)
+
+// Event is a live event. Events only carry identifiers, subscribers fetch what
+// has changed through the usual access-checked pages and APIs.
+type Event struct {
+	Type       string `json:"type"`
+	RepoID     int64  `json:"repo_id,omitempty"`
+	IssueIndex int64  `json:"issue_index,omitempty"`
+	SHA        string `json:"sha,omitempty"`
+	// URL is the link of what the event is about, e.g. the issue.
+	URL string `json:"url,omitempty"`
+	// ActorID is the ID of the user who triggered the event.
+	ActorID int64 `json:"actor_id,omitempty"`
+}
+
+// RepoTopic returns the topic of events of the repository.
+func RepoTopic(repoID int64) string {
+	return "repo:" + strconv.FormatInt(repoID, 10)
+}
+
+// UserTopic returns the topic of events addressed to the user.
+func UserTopic(userID int64) string {
+	return "user:" + strconv.FormatInt(userID, 10)
+}
+
+// subscriptionBufferSize is the number of events buffered for a subscriber.
+// Events are dropped for subscribers that fall behind.
+const subscriptionBufferSize = 32
+
+// Subscription receives events of subscribed topics until it is closed.
+type Subscription struct {
+	// C is the channel that receives events, which is closed when the
+	// subscription is closed.
+	C <-chan *Event
+
+	c      chan *Event
+	hub    *Hub
+	topics []string
+}
+
+// Close unsubscribes from all topics and closes the channel. It is safe to
+// call multiple times.
+func (s *Subscription) Close() {
+	s.hub.unsubscribe(s)
+}
+
+// Hub fans out published events to subscribers of their topics.
+type Hub struct {
+	mu     sync.RWMutex
+	topics map[string]map[*Subscription]struct{}
+}
+
+// NewHub returns a new hub without subscribers.
+func NewHub() *Hub {
+	return &Hub{
+		topics: make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe returns a new subscription of events of given topics.
+func (h *Hub) Subscribe(topics ...string) *Subscription {
+	c := make(chan *Event, subscriptionBufferSize)
+	s := &Subscription{
+		C:      c,
+		c:      c,
+		hub:    h,
+		topics: topics,
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, topic := range topics {
+		subs, ok := h.topics[topic]
+		if !ok {
+			subs = make(map[*Subscription]struct{})
+			h.topics[topic] = subs
+		}
+		subs[s] = struct{}{}
+	}
+	return s
+}
+
+func (h *Hub) unsubscribe(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subscribed := false
+	for _, topic := range s.topics {
+		subs := h.topics[topic]
+		if _, ok := subs[s]; !ok {
+			continue
+		}
+		subscribed = true
+		delete(subs, s)
+		if len(subs) == 0 {
+			delete(h.topics, topic)
+		}
+	}
+	if subscribed {
+		close(s.c)
+	}
+}
+
+// Publish sends the event to subscribers of the topic without blocking. The
+// event is dropped for subscribers whose buffer is full.
+func (h *Hub) Publish(topic string, e *Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for s := range h.topics[topic] {
+		select {
+		case s.c <- e:
+		default:
+		}
+	}
+}
+
+// NumSubscribers returns the number of subscribers of the topic.
+func (h *Hub) NumSubscribers(topic string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.topics[topic])
+}
+
+var defaultHub = NewHub()
+
+// Subscribe returns a new subscription of events of given topics from the
+// default hub.
+func Subscribe(topics ...string) *Subscription {
+	return defaultHub.Subscribe(topics...)
+}
+
+// Publish sends the event to subscribers of the topic of the default hub.
+func Publish(topic string, e *Event) {
+	defaultHub.Publish(topic, e)
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHub(t *testing.T) {
+	h := NewHub()
+	repo := h.Subscribe(RepoTopic(1))
+	both := h.Subscribe(RepoTopic(1), UserTopic(2))
+	assert.Equal(t, 2, h.NumSubscribers(RepoTopic(1)))
+	assert.Equal(t, 1, h.NumSubscribers(UserTopic(2)))
+
+	comment := &Event{Type: TypeIssueComment, RepoID: 1, IssueIndex: 3}
+	h.Publish(RepoTopic(1), comment)
+	notification := &Event{Type: TypeNotification, RepoID: 1, IssueIndex: 3}
+	h.Publish(UserTopic(2), notification)
+	// No subscriber
+	h.Publish(RepoTopic(2), &Event{Type: TypeIssueComment, RepoID: 2})
+
+	assert.Equal(t, comment, <-repo.C)
+	assert.Equal(t, comment, <-both.C)
+	assert.Equal(t, notification, <-both.C)
+	assert.Len(t, repo.C, 0)
+	assert.Len(t, both.C, 0)
+
+	t.Run("close", func(t *testing.T) {
+		repo.Close()
+		repo.Close()
+		_, ok := <-repo.C
+		assert.False(t, ok)
+		assert.Equal(t, 1, h.NumSubscribers(RepoTopic(1)))
+
+		both.Close()
+		assert.Equal(t, 0, h.NumSubscribers(RepoTopic(1)))
+		assert.Equal(t, 0, h.NumSubscribers(UserTopic(2)))
+		assert.Empty(t, h.topics)
+	})
+
+	t.Run("drop events of slow subscribers", func(t *testing.T) {
+		s := h.Subscribe(RepoTopic(1))
+		defer s.Close()
+		for i := 0; i < subscriptionBufferSize+10; i++ {
+			h.Publish(RepoTopic(1), comment)
+		}
+		assert.Len(t, s.C, subscriptionBufferSize)
+	})
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	log "unknwon.dev/clog/v2"
+)
+
+// StreamOptions contains options of serving a stream of events.
+type StreamOptions struct {
+	// HeartbeatInterval is the interval of sending comments to keep the
+	// connection alive through proxies.
+	HeartbeatInterval time.Duration
+	// MaxLifetime is the maximum duration of the stream. Clients reconnect
+	// automatically once the stream ends, which checks the access again.
+	MaxLifetime time.Duration
+}
+
+// DefaultStreamOptions are the options of streams served to browsers.
+var DefaultStreamOptions = StreamOptions{
+	HeartbeatInterval: 30 * time.Second,
+	MaxLifetime:       30 * time.Minute,
+}
+
+// ServeStream writes events received by the subscription to the response as
+// server-sent events, until the request is canceled or the stream reaches its
+// maximum lifetime.
+func ServeStream(w http.ResponseWriter, r *http.Request, sub *Subscription, opts StreamOptions) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Disable response buffering of nginx.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprint(w, "retry: 5000\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(opts.HeartbeatInterval)
+	defer heartbeat.Stop()
+	lifetime := time.NewTimer(opts.MaxLifetime)
+	defer lifetime.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-lifetime.C:
+			return
+		case <-heartbeat.C:
+			_, _ = fmt.Fprint(w, ": heartbeat\n\n")
+		case e, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				log.Error("Failed to encode event: %v", err)
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package events
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeStream(t *testing.T) {
+	h := NewHub()
+	sub := h.Subscribe(RepoTopic(1))
+	defer sub.Close()
+
+	h.Publish(RepoTopic(1), &Event{Type: TypeIssueComment, RepoID: 1, IssueIndex: 2, ActorID: 3})
+	h.Publish(RepoTopic(1), &Event{Type: TypeCommitStatus, RepoID: 1, SHA: "2dcc1fc"})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/events", nil)
+	ServeStream(w, r, sub, StreamOptions{
+		HeartbeatInterval: time.Hour,
+		MaxLifetime:       100 * time.Millisecond,
+	})
+
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	want := "retry: 5000\n\n" +
+		"event: issue.comment\ndata: {\"type\":\"issue.comment\",\"repo_id\":1,\"issue_index\":2,\"actor_id\":3}\n\n" +
+		"event: commit.status\ndata: {\"type\":\"commit.status\",\"repo_id\":1,\"sha\":\"2dcc1fc\"}\n\n"
+	assert.Equal(t, want, w.Body.String())
+	assert.True(t, w.Flushed)
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package federation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/errutil"
+	"gogs.io/gogs/internal/strutil"
+)
+
+var _ errutil.NotFound = (*ErrTokenInactive)(nil)
+
+type ErrTokenInactive struct{}
+
+func IsErrTokenInactive(err error) bool {
+	_, ok := err.(ErrTokenInactive)
+	return ok
+}
+
+func (ErrTokenInactive) Error() string {
+	return "token is not active on the central instance"
+}
+
+func (ErrTokenInactive) NotFound() bool {
+	return true
+}
+
+// Enabled returns true if the instance is configured to trust access tokens
+// issued by a central instance.
+func Enabled() bool {
+	return conf.Federation.CentralURL != ""
+}
+
+type cacheEntry struct {
+	userID    int64 // Zero for inactive tokens.
+	expiresAt time.Time
+}
+
+var (
+	cacheMu sync.Mutex
+	cache   = make(map[string]cacheEntry)
+
+	httpClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+// Authenticate returns the local user of the first active token among given
+// tokens, which is introspected with the central instance and the result is
+// cached for conf.Federation.CacheTTL. The local user is created with the same
+// username on its first access and bound to the central user, see
+// db.FederatedIdentitiesStore. Tokens of central users who cannot be bound are
+// treated as inactive. Empty tokens are ignored, and it returns
+// ErrTokenInactive if none of tokens is active.
+func Authenticate(ctx context.Context, tokens ...string) (*db.User, error) {
+	for _, token := range tokens {
+		if token == "" {
+			continue
+		}
+
+		userID, err := authenticate(ctx, token)
+		if err != nil {
+			return nil, err
+		} else if userID > 0 {
+			u, err := db.Users.GetByID(ctx, userID)
+			if err != nil {
+				return nil, err
+			} else if u.IsAdmin {
+				// The user may have been promoted since the token was cached.
+				return nil, ErrTokenInactive{}
+			}
+			return u, nil
+		}
+	}
+	return nil, ErrTokenInactive{}
+}
+
+// authenticate returns the ID of the local user of the token, or zero if the
+// token is not active.
+func authenticate(ctx context.Context, token string) (int64, error) {
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:])
+
+	cacheMu.Lock()
+	entry, ok := cache[key]
+	cacheMu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.userID, nil
+	}
+
+	result, err := Introspect(ctx, httpClient, conf.Federation.CentralURL, conf.Federation.SharedKey, token, time.Now())
+	if err != nil {
+		return 0, errors.Wrap(err, "introspect")
+	}
+
+	var userID int64
+	if result.Active {
+		u, err := localUser(ctx, result)
+		if err == nil {
+			userID = u.ID
+		} else if db.IsErrFederatedUserNotAllowed(err) {
+			log.Warn("Federated user %q is not allowed: %v", result.Username, err)
+		} else {
+			return 0, errors.Wrap(err, "get local user")
+		}
+	}
+
+	cacheMu.Lock()
+	// Purge expired entries while we are here to keep the cache bounded.
+	now := time.Now()
+	for k, v := range cache {
+		if now.After(v.expiresAt) {
+			delete(cache, k)
+		}
+	}
+	cache[key] = cacheEntry{
+		userID:    userID,
+		expiresAt: now.Add(conf.Federation.CacheTTL),
+	}
+	cacheMu.Unlock()
+	return userID, nil
+}
+
+// localUser returns the local user bound to the user of the introspection
+// result, and creates one with the same username if not bound yet.
+func localUser(ctx context.Context, result *Introspection) (*db.User, error) {
+	if result.UserID <= 0 {
+		return nil, errors.New("central instance did not return the user ID")
+	}
+
+	// The local user authenticates via the central instance, a random password
+	// makes sure it cannot sign in locally with password.
+	password, err := strutil.RandomChars(32)
+	if err != nil {
+		return nil, errors.Wrap(err, "generate password")
+	}
+	return db.FederatedIdentities.GetOrCreateUser(ctx, result.UserID, result.Username, result.Email,
+		db.CreateUserOptions{
+			FullName:  result.FullName,
+			Password:  password,
+			Activated: true,
+		},
+	)
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package federation implements trusting access tokens issued by a central Gogs
+// instance, which allows satellite instances to share one identity store.
+package federation
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// IntrospectPath is the path of the token introspection endpoint relative to
+	// the URL of the central instance.
+	IntrospectPath = "/api/v1/federation/introspect"
+	// SignatureHeader is the HTTP header that carries the signature of the
+	// request or response body.
+	SignatureHeader = "X-Gogs-Signature"
+
+	// MaxClockSkew is the maximum difference allowed between the timestamp of an
+	// introspection request and the clock of the central instance.
+	MaxClockSkew = 5 * time.Minute
+)
+
+// Sign returns the hex-encoded HMAC-SHA256 signature of the body using the key.
+func Sign(key string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify returns true if the signature is valid for the body using the key.
+func Verify(key string, body []byte, signature string) bool {
+	want, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), want)
+}
+
+// Introspection is the result of introspecting an access token.
+type Introspection struct {
+	// Active indicates whether the token is valid and its owner is allowed to
+	// sign in. Other user fields are empty when it is false.
+	Active bool `json:"active"`
+	// UserID is the ID of the user on the central instance, which binds the
+	// user to its local user.
+	UserID   int64  `json:"user_id,omitempty"`
+	Username string `json:"username,omitempty"`
+	Email    string `json:"email,omitempty"`
+	FullName string `json:"full_name,omitempty"`
+	// Timestamp is the echo of the timestamp of the request to prevent replays
+	// of responses.
+	Timestamp int64 `json:"timestamp"`
+}
+
+// IntrospectRequest is the request to introspect an access token.
+type IntrospectRequest struct {
+	Token string `json:"token"`
+	// Timestamp is the Unix time of the request to prevent replays of requests.
+	Timestamp int64 `json:"timestamp"`
+}
+
+// ParseIntrospectRequest verifies the signature and the timestamp of the request
+// body and returns the parsed request.
+func ParseIntrospectRequest(key string, body []byte, signature string, now time.Time) (*IntrospectRequest, error) {
+	if !Verify(key, body, signature) {
+		return nil, errors.New("invalid signature")
+	}
+
+	var req IntrospectRequest
+	err := json.Unmarshal(body, &req)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode body")
+	}
+
+	skew := now.Sub(time.Unix(req.Timestamp, 0))
+	if skew > MaxClockSkew || skew < -MaxClockSkew {
+		return nil, errors.Errorf("timestamp is out of range: %d", req.Timestamp)
+	} else if req.Token == "" {
+		return nil, errors.New("empty token")
+	}
+	return &req, nil
+}
+
+// Introspect asks the central instance at the URL whether the token is active.
+func Introspect(ctx context.Context, client *http.Client, centralURL, key, token string, now time.Time) (*Introspection, error) {
+	timestamp := now.Unix()
+	body, err := json.Marshal(IntrospectRequest{
+		Token:     token,
+		Timestamp: timestamp,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "encode request")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, centralURL+IntrospectPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "new request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(key, body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "do request")
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, errors.Wrap(err, "read response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status %d: %s", resp.StatusCode, respBody)
+	}
+	if !Verify(key, respBody, resp.Header.Get(SignatureHeader)) {
+		return nil, errors.New("invalid response signature")
+	}
+
+	var result Introspection
+	err = json.Unmarshal(respBody, &result)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode response")
+	}
+	if result.Timestamp != timestamp {
+		return nil, errors.Errorf("mismatched response timestamp: want %d but got %d", timestamp, result.Timestamp)
+	}
+	return &result, nil
+}
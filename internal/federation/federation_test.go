@@ -0,0 +1,146 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"token":"abc"}`)
+	signature := Sign("key", body)
+	assert.True(t, Verify("key", body, signature))
+	assert.False(t, Verify("other-key", body, signature))
+	assert.False(t, Verify("key", []byte(`{"token":"xyz"}`), signature))
+	assert.False(t, Verify("key", body, "not-hex"))
+}
+
+func TestParseIntrospectRequest(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	sign := func(req IntrospectRequest) ([]byte, string) {
+		body, err := json.Marshal(req)
+		require.NoError(t, err)
+		return body, Sign("key", body)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		body, signature := sign(IntrospectRequest{Token: "abc", Timestamp: now.Unix() - 60})
+		got, err := ParseIntrospectRequest("key", body, signature, now)
+		require.NoError(t, err)
+		assert.Equal(t, &IntrospectRequest{Token: "abc", Timestamp: now.Unix() - 60}, got)
+	})
+
+	t.Run("bad signature", func(t *testing.T) {
+		body, _ := sign(IntrospectRequest{Token: "abc", Timestamp: now.Unix()})
+		_, err := ParseIntrospectRequest("key", body, Sign("other-key", body), now)
+		assert.Error(t, err)
+	})
+
+	t.Run("stale timestamp", func(t *testing.T) {
+		body, signature := sign(IntrospectRequest{Token: "abc", Timestamp: now.Add(-MaxClockSkew - time.Second).Unix()})
+		_, err := ParseIntrospectRequest("key", body, signature, now)
+		assert.Error(t, err)
+	})
+
+	t.Run("empty token", func(t *testing.T) {
+		body, signature := sign(IntrospectRequest{Timestamp: now.Unix()})
+		_, err := ParseIntrospectRequest("key", body, signature, now)
+		assert.Error(t, err)
+	})
+}
+
+func TestIntrospect(t *testing.T) {
+	now := time.Now()
+	newServer := func(key string, mutate func(*Introspection)) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, IntrospectPath, r.URL.Path)
+
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			req, err := ParseIntrospectRequest("key", body, r.Header.Get(SignatureHeader), now)
+			if err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			result := Introspection{
+				Active:    req.Token == "valid",
+				Timestamp: req.Timestamp,
+			}
+			if result.Active {
+				result.UserID = 1
+				result.Username = "alice"
+				result.Email = "alice@example.com"
+			}
+			if mutate != nil {
+				mutate(&result)
+			}
+			data, err := json.Marshal(result)
+			require.NoError(t, err)
+			w.Header().Set(SignatureHeader, Sign(key, data))
+			_, _ = w.Write(data)
+		}))
+	}
+
+	t.Run("active", func(t *testing.T) {
+		srv := newServer("key", nil)
+		defer srv.Close()
+
+		got, err := Introspect(context.Background(), srv.Client(), srv.URL, "key", "valid", now)
+		require.NoError(t, err)
+		want := &Introspection{
+			Active:    true,
+			UserID:    1,
+			Username:  "alice",
+			Email:     "alice@example.com",
+			Timestamp: now.Unix(),
+		}
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("inactive", func(t *testing.T) {
+		srv := newServer("key", nil)
+		defer srv.Close()
+
+		got, err := Introspect(context.Background(), srv.Client(), srv.URL, "key", "invalid", now)
+		require.NoError(t, err)
+		assert.False(t, got.Active)
+	})
+
+	t.Run("bad request signature", func(t *testing.T) {
+		srv := newServer("key", nil)
+		defer srv.Close()
+
+		_, err := Introspect(context.Background(), srv.Client(), srv.URL, "other-key", "valid", now)
+		assert.Error(t, err)
+	})
+
+	t.Run("bad response signature", func(t *testing.T) {
+		srv := newServer("other-key", nil)
+		defer srv.Close()
+
+		_, err := Introspect(context.Background(), srv.Client(), srv.URL, "key", "valid", now)
+		assert.Error(t, err)
+	})
+
+	t.Run("replayed response", func(t *testing.T) {
+		srv := newServer("key", func(result *Introspection) {
+			result.Timestamp--
+		})
+		defer srv.Close()
+
+		_, err := Introspect(context.Background(), srv.Client(), srv.URL, "key", "valid", now)
+		assert.Error(t, err)
+	})
+}
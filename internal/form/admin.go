@@ -23,21 +23,74 @@ func (f *AdminCrateUser) Validate(ctx *macaron.Context, errs binding.Errors) bin
 }
 
 type AdminEditUser struct {
-	LoginType        string `binding:"Required"`
-	LoginName        string
-	FullName         string `binding:"MaxSize(100)"`
-	Email            string `binding:"Required;Email;MaxSize(254)"`
-	Password         string `binding:"MaxSize(255)"`
-	Website          string `binding:"MaxSize(50)"`
-	Location         string `binding:"MaxSize(50)"`
-	MaxRepoCreation  int
-	Active           bool
-	Admin            bool
-	AllowGitHook     bool
-	AllowImportLocal bool
-	ProhibitLogin    bool
+	LoginType          string `binding:"Required"`
+	LoginName          string
+	FullName           string `binding:"MaxSize(100)"`
+	Email              string `binding:"Required;Email;MaxSize(254)"`
+	Password           string `binding:"MaxSize(255)"`
+	Website            string `binding:"MaxSize(50)"`
+	Location           string `binding:"MaxSize(50)"`
+	MaxRepoCreation    int
+	Tenant             string `binding:"MaxSize(50)"`
+	Active             bool
+	Admin              bool
+	AllowGitHook       bool
+	AllowImportLocal   bool
+	ProhibitLogin      bool
+	MustChangePassword bool
+	AdminRoles         []string
 }
 
 func (f *AdminEditUser) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
+
+type AdminRepoInitFile struct {
+	Type    string `binding:"Required;In(gitignore,license,readme)"`
+	Name    string `binding:"Required;MaxSize(100)" locale:"admin.templates.name"`
+	Content string `binding:"Required" locale:"admin.templates.content"`
+}
+
+func (f *AdminRepoInitFile) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+type AdminProfileField struct {
+	Name          string `binding:"Required;AlphaDashDot;MaxSize(50)" locale:"admin.profile_fields.name"`
+	Label         string `binding:"Required;MaxSize(100)" locale:"admin.profile_fields.label"`
+	Scope         string `binding:"Required;In(user,organization)"`
+	Editable      bool
+	LDAPAttribute string `form:"ldap_attribute" binding:"MaxSize(100)" locale:"admin.profile_fields.ldap_attribute"`
+	Position      int
+}
+
+func (f *AdminProfileField) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+type AdminReservedName struct {
+	Kind   string `binding:"Required;In(user,repo)"`
+	Name   string `binding:"Required;MaxSize(255)" locale:"admin.reserved_names.name"`
+	Action string `binding:"Required;In(reserve,allow)"`
+}
+
+func (f *AdminReservedName) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+type AdminBulkEditRepos struct {
+	Owner              string `binding:"MaxSize(255)" locale:"admin.repos.owner"`
+	NamePattern        string `binding:"MaxSize(255)" locale:"admin.repos.bulk_edit.name_pattern"`
+	Wiki               string `binding:"OmitEmpty;In(enable,disable)"`
+	Issues             string `binding:"OmitEmpty;In(enable,disable)"`
+	Pulls              string `binding:"OmitEmpty;In(enable,disable)"`
+	Visibility         string `binding:"OmitEmpty;In(private,public)"`
+	WebhookURL         string `form:"webhook_url" binding:"Url;MaxSize(2048)" locale:"repo.settings.payload_url"`
+	WebhookContentType string `form:"webhook_content_type" binding:"OmitEmpty;In(json,form)"`
+	WebhookSecret      string `form:"webhook_secret"`
+	Apply              bool
+}
+
+func (f *AdminBulkEditRepos) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
@@ -24,6 +24,7 @@ type UpdateOrgSetting struct {
 	Website         string `binding:"Url;MaxSize(100)"`
 	Location        string `binding:"MaxSize(50)"`
 	MaxRepoCreation int
+	Tenant          string `binding:"MaxSize(50)"`
 }
 
 func (f *UpdateOrgSetting) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
@@ -39,3 +40,34 @@ type CreateTeam struct {
 func (f *CreateTeam) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
+
+type NewTeamDiscussion struct {
+	Title   string `binding:"Required;MaxSize(255)"`
+	Content string `binding:"Required"`
+}
+
+func (f *NewTeamDiscussion) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+type NewTeamDiscussionReply struct {
+	Content string `binding:"Required"`
+}
+
+func (f *NewTeamDiscussionReply) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+type OrgRuleset struct {
+	Name               string `binding:"Required;MaxSize(100)" locale:"org.settings.rulesets.name"`
+	RepoPatterns       string `binding:"Required;MaxSize(2048)" locale:"org.settings.rulesets.repo_patterns"`
+	BranchPatterns     string `binding:"Required;MaxSize(2048)" locale:"org.settings.rulesets.branch_patterns"`
+	RequirePullRequest bool
+	BlockForcePush     bool
+	BlockDeletion      bool
+	BlockedPaths       string `binding:"MaxSize(2048)" locale:"org.settings.rulesets.blocked_paths"`
+}
+
+func (f *OrgRuleset) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
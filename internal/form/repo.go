@@ -5,6 +5,7 @@
 package form
 
 import (
+	"mime/multipart"
 	"net/url"
 	"strings"
 
@@ -29,12 +30,20 @@ type CreateRepo struct {
 	RepoName        string `binding:"Required;AlphaDashDot;MaxSize(100)"`
 	Private         bool
 	Unlisted        bool
+	TrackerOnly     bool
 	Description     string `binding:"MaxSize(512)"`
 	AutoInit        bool
 	SaveDefaultRepo bool
 	Gitignores      string
 	License         string
 	Readme          string
+	Topics          string
+	// Archive is the uploaded zip or tar archive whose files become the initial
+	// commit of the repository.
+	Archive *multipart.FileHeader
+	// LocalPath is the path of a directory on the server whose files become the
+	// initial commit of the repository, only available to site admins.
+	LocalPath string
 }
 
 func (f *CreateRepo) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
@@ -51,6 +60,8 @@ type MigrateRepo struct {
 	Private      bool   `json:"private"`
 	Unlisted     bool   `json:"unlisted"`
 	Description  string `json:"description" binding:"MaxSize(512)"`
+	// Topics is the comma-separated list of topics.
+	Topics string `json:"topics"`
 }
 
 func (f *MigrateRepo) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
@@ -98,6 +109,7 @@ type RepoSetting struct {
 	RepoName      string `binding:"Required;AlphaDashDot;MaxSize(100)"`
 	Description   string `binding:"MaxSize(512)"`
 	Website       string `binding:"Url;MaxSize(100)"`
+	Topics        string
 	Branch        string
 	Interval      int
 	MirrorAddress string
@@ -106,19 +118,31 @@ type RepoSetting struct {
 	EnablePrune   bool
 
 	// Advanced settings
-	EnableWiki            bool
-	AllowPublicWiki       bool
-	EnableExternalWiki    bool
-	ExternalWikiURL       string
-	EnableIssues          bool
-	AllowPublicIssues     bool
-	EnableExternalTracker bool
-	ExternalTrackerURL    string
-	TrackerURLFormat      string
-	TrackerIssueStyle     string
-	EnablePulls           bool
-	PullsIgnoreWhitespace bool
-	PullsAllowRebase      bool
+	EnableWiki               bool
+	AllowPublicWiki          bool
+	EnableExternalWiki       bool
+	ExternalWikiURL          string
+	EnableIssues             bool
+	AllowPublicIssues        bool
+	EnableExternalTracker    bool
+	ExternalTrackerURL       string
+	TrackerURLFormat         string
+	TrackerIssueStyle        string
+	EnablePulls              bool
+	PullsIgnoreWhitespace    bool
+	PullsAllowRebase         bool
+	PullsAllowSquash         bool
+	MergeMessageTemplate     string `binding:"MaxSize(4096)"`
+	SquashMessageTemplate    string `binding:"MaxSize(4096)"`
+	DefaultAssignees         string `binding:"MaxSize(1024)"`
+	DefaultReviewers         string `binding:"MaxSize(1024)"`
+	EnableMergeQueue         bool
+	MergeQueueRequiredChecks string `binding:"MaxSize(1024)"`
+	MergeChecklist           string `binding:"MaxSize(4096)"`
+	EnableReleases           bool
+	MaxPushFileSize          int64
+	MaxPushSize              int64
+	EnableMaintainersFile    bool
 }
 
 func (f *RepoSetting) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
@@ -133,11 +157,13 @@ func (f *RepoSetting) Validate(ctx *macaron.Context, errs binding.Errors) bindin
 //         \/             \/     \/     \/     \/
 
 type ProtectBranch struct {
-	Protected          bool
-	RequirePullRequest bool
-	EnableWhitelist    bool
-	WhitelistUsers     string
-	WhitelistTeams     string
+	Protected           bool
+	RequirePullRequest  bool
+	EnableWhitelist     bool
+	WhitelistUsers      string
+	WhitelistTeams      string
+	RerequestReviews    bool
+	RequiredDeployments string
 }
 
 func (f *ProtectBranch) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
@@ -161,6 +187,11 @@ type Webhook struct {
 	IssueComment bool
 	PullRequest  bool
 	Release      bool
+	Star         bool
+	Watch        bool
+	Repository   bool
+	Deployment   bool
+	Wiki         bool
 	Active       bool
 }
 
@@ -235,6 +266,8 @@ type NewIssue struct {
 	AssigneeID  int64
 	Content     string
 	Files       []string
+	// Template is the file name of the issue form that the issue is created with.
+	Template string
 }
 
 func (f *NewIssue) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
@@ -0,0 +1,24 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package form
+
+import (
+	"github.com/go-macaron/binding"
+	"gopkg.in/macaron.v1"
+)
+
+type Snippet struct {
+	// OwnerID is only used when creating a snippet.
+	OwnerID    int64
+	Title      string `binding:"MaxSize(255)"`
+	Visibility string `binding:"Required;In(public,secret,private)"`
+	// FileName and Content are names and contents of files in the same order.
+	FileName []string
+	Content  []string
+}
+
+func (f *Snippet) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
@@ -98,6 +98,9 @@ type UpdateProfile struct {
 	Email    string `binding:"Required;Email;MaxSize(254)"`
 	Website  string `binding:"Url;MaxSize(100)"`
 	Location string `binding:"MaxSize(50)"`
+	Theme    string
+
+	ContributionsVisibility string
 }
 
 func (f *UpdateProfile) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
@@ -128,6 +131,15 @@ func (f *AddEmail) Validate(ctx *macaron.Context, errs binding.Errors) binding.E
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+type UpdateEmailPrivacy struct {
+	KeepEmailPrivate       bool
+	BlockEmailExposingPush bool
+}
+
+func (f *UpdateEmailPrivacy) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 type ChangePassword struct {
 	OldPassword string `binding:"Required;MinSize(1);MaxSize(255)"`
 	Password    string `binding:"Required;MaxSize(255)"`
@@ -147,6 +159,14 @@ func (f *AddSSHKey) Validate(ctx *macaron.Context, errs binding.Errors) binding.
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+type AddGPGKey struct {
+	Content string `binding:"Required"`
+}
+
+func (f *AddGPGKey) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 type NewAccessToken struct {
 	Name string `binding:"Required"`
 }
@@ -0,0 +1,141 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+)
+
+// BlobInfo contains the ID, path and size of a blob.
+type BlobInfo struct {
+	ID string
+	// Path is the first path found for the blob in the tree, the same blob may
+	// exist in other paths.
+	Path string
+	Size int64
+}
+
+// ListNewBlobs returns blobs that are reachable from given commits but not from
+// any existing reference of the repository in given path, i.e. blobs to be
+// introduced by pushing the commits.
+func ListNewBlobs(repoPath string, commitIDs ...string) ([]*BlobInfo, error) {
+	if len(commitIDs) == 0 {
+		return []*BlobInfo{}, nil
+	}
+
+	args := append([]string{"rev-list", "--objects"}, commitIDs...)
+	args = append(args, "--not", "--all")
+	output, err := git.NewCommand(args...).RunInDir(repoPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "list objects")
+	}
+
+	// Commits are listed without paths, and the root tree is listed with an
+	// empty path.
+	var (
+		ids   []string
+		paths = make(map[string]string)
+	)
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 || fields[1] == "" {
+			continue
+		}
+		if _, ok := paths[fields[0]]; ok {
+			continue
+		}
+		ids = append(ids, fields[0])
+		paths[fields[0]] = fields[1]
+	}
+	if len(ids) == 0 {
+		return []*BlobInfo{}, nil
+	}
+
+	stdin := strings.NewReader(strings.Join(ids, "\n") + "\n")
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	err = git.NewCommand("cat-file", "--batch-check=%(objectname) %(objecttype) %(objectsize)").
+		RunInDirWithOptions(repoPath, git.RunInDirOptions{
+			Stdin:  stdin,
+			Stdout: stdout,
+			Stderr: stderr,
+		})
+	if err != nil {
+		return nil, errors.Wrapf(err, "check objects: %s", stderr)
+	}
+
+	blobs := make([]*BlobInfo, 0, len(ids))
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || fields[1] != "blob" {
+			continue
+		}
+
+		size, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse size of %q", fields[0])
+		}
+		blobs = append(blobs, &BlobInfo{
+			ID:   fields[0],
+			Path: paths[fields[0]],
+			Size: size,
+		})
+	}
+	return blobs, nil
+}
+
+// readObjects reads objects with given IDs of the repository in given path,
+// and calls the function with the ID, type and content of each object until it
+// returns false. Missing objects are skipped.
+func readObjects(repoPath string, ids []string, fn func(id, typ string, content []byte) bool) error {
+	stdin := strings.NewReader(strings.Join(ids, "\n") + "\n")
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	err := git.NewCommand("cat-file", "--batch").
+		RunInDirWithOptions(repoPath, git.RunInDirOptions{
+			Stdin:  stdin,
+			Stdout: stdout,
+			Stderr: stderr,
+		})
+	if err != nil {
+		return errors.Wrapf(err, "read objects: %s", stderr)
+	}
+
+	// Each object is printed as "<id> <type> <size>\n<content>\n", and each
+	// missing object as "<id> missing\n".
+	r := bufio.NewReader(stdout)
+	for {
+		header, err := r.ReadString('\n')
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.Wrap(err, "read header")
+		}
+
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "parse size of %q", fields[0])
+		}
+
+		content := make([]byte, size+1) // Including the trailing newline
+		if _, err = io.ReadFull(r, content); err != nil {
+			return errors.Wrapf(err, "read content of %q", fields[0])
+		}
+		if !fn(fields[0], fields[1], content[:size]) {
+			return nil
+		}
+	}
+}
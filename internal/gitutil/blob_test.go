@@ -0,0 +1,65 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListNewBlobs(t *testing.T) {
+	repoPath := t.TempDir()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=alice", "GIT_AUTHOR_EMAIL=alice@example.com",
+			"GIT_COMMITTER_NAME=alice", "GIT_COMMITTER_EMAIL=alice@example.com",
+		)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		return strings.TrimSpace(string(output))
+	}
+
+	run("init", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "README.md"), []byte("# Hello"), 0o644))
+	run("add", "-A")
+	run("commit", "-m", "initial")
+	base := run("rev-parse", "HEAD")
+
+	blobs, err := ListNewBlobs(repoPath)
+	require.NoError(t, err)
+	assert.Empty(t, blobs)
+
+	// Commits that are not referenced yet, as in the pre-receive hook.
+	require.NoError(t, os.MkdirAll(filepath.Join(repoPath, "assets"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "assets", "video.mp4"), make([]byte, 2048), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "assets", "copy.mp4"), make([]byte, 2048), 0o644))
+	run("add", "-A")
+	run("commit", "-m", "add video")
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "main.go"), []byte("package main"), 0o644))
+	run("add", "-A")
+	run("commit", "-m", "add main.go")
+	head := run("rev-parse", "HEAD")
+	run("update-ref", "refs/heads/main", base)
+
+	blobs, err = ListNewBlobs(repoPath, head)
+	require.NoError(t, err)
+	want := []*BlobInfo{
+		{ID: run("rev-parse", head+":main.go"), Path: "main.go", Size: 12},
+		{ID: run("rev-parse", head+":assets/copy.mp4"), Path: "assets/copy.mp4", Size: 2048},
+	}
+	assert.ElementsMatch(t, want, blobs)
+
+	blobs, err = ListNewBlobs(repoPath, base)
+	require.NoError(t, err)
+	assert.Empty(t, blobs)
+}
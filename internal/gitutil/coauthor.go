@@ -0,0 +1,65 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"bufio"
+	"strings"
+
+	"github.com/gogs/git-module"
+)
+
+const coAuthoredByTrailer = "co-authored-by:"
+
+// ParseCoAuthors returns co-authors declared by "Co-authored-by: Name <email>"
+// trailers in the commit message. The trailer key is case-insensitive, and
+// co-authors with duplicated email addresses are only returned once.
+func ParseCoAuthors(message string) []*git.Signature {
+	var coAuthors []*git.Signature
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(message))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) < len(coAuthoredByTrailer) || !strings.EqualFold(line[:len(coAuthoredByTrailer)], coAuthoredByTrailer) {
+			continue
+		}
+		line = line[len(coAuthoredByTrailer):]
+
+		start := strings.Index(line, "<")
+		end := strings.LastIndex(line, ">")
+		if start < 0 || end < start {
+			continue
+		}
+		name := strings.TrimSpace(line[:start])
+		email := strings.TrimSpace(line[start+1 : end])
+		if name == "" || email == "" || seen[strings.ToLower(email)] {
+			continue
+		}
+		seen[strings.ToLower(email)] = true
+
+		coAuthors = append(coAuthors, &git.Signature{
+			Name:  name,
+			Email: email,
+		})
+	}
+	return coAuthors
+}
+
+// CommitCoAuthors returns co-authors of the commit excluding its author, with
+// the author time of the commit.
+func CommitCoAuthors(c *git.Commit) []*git.Signature {
+	coAuthors := ParseCoAuthors(c.Message)
+	filtered := coAuthors[:0]
+	for _, sig := range coAuthors {
+		if c.Author != nil {
+			if strings.EqualFold(sig.Email, c.Author.Email) {
+				continue
+			}
+			sig.When = c.Author.When
+		}
+		filtered = append(filtered, sig)
+	}
+	return filtered
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogs/git-module"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCoAuthors(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []*git.Signature
+	}{
+		{
+			name:    "no trailers",
+			message: "Fix typos\n\nSome details.",
+			want:    nil,
+		},
+		{
+			name: "trailers",
+			message: `Fix typos
+
+Co-authored-by: Alice <alice@example.com>
+co-authored-by:Bob Smith <bob@example.com>
+Co-Authored-By: Alice Again <ALICE@example.com>
+Co-authored-by: <noname@example.com>
+Co-authored-by: Malformed
+Signed-off-by: Carol <carol@example.com>`,
+			want: []*git.Signature{
+				{Name: "Alice", Email: "alice@example.com"},
+				{Name: "Bob Smith", Email: "bob@example.com"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, ParseCoAuthors(test.message))
+		})
+	}
+}
+
+func TestCommitCoAuthors(t *testing.T) {
+	when := time.Unix(1588568886, 0)
+	c := &git.Commit{
+		Author: &git.Signature{Name: "Alice", Email: "alice@example.com", When: when},
+		Message: `Fix typos
+
+Co-authored-by: Alice <Alice@example.com>
+Co-authored-by: Bob <bob@example.com>`,
+	}
+	want := []*git.Signature{
+		{Name: "Bob", Email: "bob@example.com", When: when},
+	}
+	assert.Equal(t, want, CommitCoAuthors(c))
+}
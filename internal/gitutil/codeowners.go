@@ -0,0 +1,162 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+)
+
+// CodeownersPaths is the list of paths that a CODEOWNERS file is looked up in
+// order.
+var CodeownersPaths = []string{"CODEOWNERS", ".gogs/CODEOWNERS", "docs/CODEOWNERS"}
+
+type codeownersRule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// Codeowners maps file paths to their owners, see
+// https://docs.github.com/en/repositories/managing-your-repositorys-settings-and-features/customizing-your-repository/about-code-owners
+// for the format. Owners are user names or teams in the form of "<org>/<team>",
+// without the "@" prefix. Email addresses are not supported.
+type Codeowners struct {
+	rules []codeownersRule
+}
+
+// codeownersPatternToRegexp converts a gitignore-style pattern to a regular
+// expression that matches the path itself and everything under it.
+func codeownersPatternToRegexp(pattern string) (*regexp.Regexp, error) {
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	var buf strings.Builder
+	if anchored {
+		buf.WriteString("^")
+	} else {
+		buf.WriteString("(^|/)")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				// "**/" matches zero or more directories.
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					buf.WriteString("(.*/)?")
+				} else {
+					buf.WriteString(".*")
+				}
+			} else {
+				buf.WriteString("[^/]*")
+			}
+		case '?':
+			buf.WriteString("[^/]")
+		default:
+			buf.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	buf.WriteString("(/|$)")
+	return regexp.Compile(buf.String())
+}
+
+// ParseCodeowners parses the content of a CODEOWNERS file. Malformed lines are
+// ignored.
+func ParseCodeowners(data []byte) *Codeowners {
+	c := new(Codeowners)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		re, err := codeownersPatternToRegexp(fields[0])
+		if err != nil {
+			continue
+		}
+
+		owners := make([]string, 0, len(fields)-1)
+		for _, owner := range fields[1:] {
+			if !strings.HasPrefix(owner, "@") {
+				continue // Email addresses
+			}
+			owners = append(owners, owner[1:])
+		}
+
+		// A rule without owners is still meaningful, which unsets owners of
+		// matched paths by previous rules.
+		c.rules = append(c.rules, codeownersRule{
+			pattern: re,
+			owners:  owners,
+		})
+	}
+	return c
+}
+
+// Owners returns owners of given paths in the order of their appearance, with
+// duplicates removed. For each path, the last matching rule wins.
+func (c *Codeowners) Owners(paths ...string) []string {
+	if c == nil {
+		return nil
+	}
+
+	var owners []string
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		path = strings.TrimPrefix(path, "/")
+		for i := len(c.rules) - 1; i >= 0; i-- {
+			if !c.rules[i].pattern.MatchString(path) {
+				continue
+			}
+
+			for _, owner := range c.rules[i].owners {
+				key := strings.ToLower(owner)
+				if !seen[key] {
+					seen[key] = true
+					owners = append(owners, owner)
+				}
+			}
+			break
+		}
+	}
+	return owners
+}
+
+// CommitCodeowners returns the code owners of the first CODEOWNERS file found
+// in the commit, see CodeownersPaths for the lookup order.
+func CommitCodeowners(commit *git.Commit) (*Codeowners, error) {
+	var (
+		entry *git.TreeEntry
+		err   error
+	)
+	for _, p := range CodeownersPaths {
+		entry, err = commit.TreeEntry(p)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "get CODEOWNERS")
+	}
+
+	p, err := entry.Blob().Bytes()
+	if err != nil {
+		return nil, errors.Wrap(err, "read CODEOWNERS")
+	}
+	return ParseCodeowners(p), nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodeowners_Owners(t *testing.T) {
+	c := ParseCodeowners([]byte(`
+# Default owners
+*                   @alice
+
+*.go                @bob @gogs/backend  # trailing comment
+/docs/              @gogs/docs
+apps/               @cindy
+/internal/**/db/    @dan
+templates/*.tmpl    @erin
+vendor/             
+README.md           eve@example.com
+`))
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "Makefile", want: []string{"alice"}},
+		{path: "main.go", want: []string{"bob", "gogs/backend"}},
+		{path: "internal/route/web.go", want: []string{"bob", "gogs/backend"}},
+		{path: "docs/README.md", want: []string{}},
+		{path: "docs/dev/schema.md", want: []string{"gogs/docs"}},
+		{path: "sub/docs/dev/schema.md", want: []string{"alice"}},
+		{path: "apps/web/index.js", want: []string{"cindy"}},
+		{path: "nested/apps/web/index.js", want: []string{"cindy"}},
+		{path: "internal/db/users.go", want: []string{"dan"}},
+		{path: "internal/foo/bar/db/users.go", want: []string{"dan"}},
+		{path: "templates/home.tmpl", want: []string{"erin"}},
+		{path: "templates/user/home.tmpl", want: []string{"alice"}},
+		{path: "vendor/lib.c", want: []string{}},
+		{path: "README.md", want: []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			got := c.Owners(test.path)
+			if len(test.want) == 0 {
+				assert.Empty(t, got)
+				return
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+
+	// Owners of multiple paths are deduplicated.
+	assert.Equal(t, []string{"bob", "gogs/backend", "alice"}, c.Owners("main.go", "Makefile", "cmd/gogs.go"))
+
+	// Nil codeowners has no owners.
+	var empty *Codeowners
+	assert.Empty(t, empty.Owners("main.go"))
+}
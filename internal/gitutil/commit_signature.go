@@ -0,0 +1,99 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+)
+
+// SignedCommit is a commit with its signature split from the payload that is
+// signed.
+type SignedCommit struct {
+	ID             string
+	CommitterEmail string
+	// Payload is the commit object without the signature.
+	Payload []byte
+	// Signature is the ASCII-armored signature, which is empty if the commit is
+	// not signed.
+	Signature []byte
+}
+
+// ParseSignedCommit parses the raw commit object with given ID. The signature
+// is stored in the "gpgsig" header, whose continuation lines start with a
+// space.
+func ParseSignedCommit(id string, data []byte) *SignedCommit {
+	c := &SignedCommit{ID: id}
+
+	var payload, signature bytes.Buffer
+	inHeaders := true
+	inSignature := false
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		if !inHeaders {
+			payload.Write(line)
+			continue
+		}
+
+		switch {
+		case inSignature && bytes.HasPrefix(line, []byte(" ")):
+			signature.Write(line[1:])
+			continue
+		case bytes.HasPrefix(line, []byte("gpgsig ")):
+			inSignature = true
+			signature.Write(bytes.TrimPrefix(line, []byte("gpgsig ")))
+			continue
+		case bytes.Equal(line, []byte("\n")):
+			inHeaders = false
+		case bytes.HasPrefix(line, []byte("committer ")):
+			s := string(line)
+			if i := strings.LastIndex(s, "<"); i > -1 {
+				if j := strings.Index(s[i:], ">"); j > -1 {
+					c.CommitterEmail = s[i+1 : i+j]
+				}
+			}
+		}
+		inSignature = false
+		payload.Write(line)
+	}
+
+	c.Payload = payload.Bytes()
+	c.Signature = signature.Bytes()
+	return c
+}
+
+// ListNewSignedCommits returns commits that are reachable from given commits
+// but not from any existing reference of the repository in given path, i.e.
+// commits to be introduced by pushing the commits.
+func ListNewSignedCommits(repoPath string, commitIDs ...string) ([]*SignedCommit, error) {
+	if len(commitIDs) == 0 {
+		return []*SignedCommit{}, nil
+	}
+
+	args := append([]string{"rev-list"}, commitIDs...)
+	args = append(args, "--not", "--all")
+	output, err := git.NewCommand(args...).RunInDir(repoPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "list commits")
+	}
+	ids := strings.Fields(string(output))
+	if len(ids) == 0 {
+		return []*SignedCommit{}, nil
+	}
+
+	commits := make([]*SignedCommit, 0, len(ids))
+	err = readObjects(repoPath, ids, func(id, typ string, content []byte) bool {
+		if typ == "commit" {
+			commits = append(commits, ParseSignedCommit(id, content))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return commits, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSignedCommit(t *testing.T) {
+	data := `tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904
+author alice <alice@example.com> 1588568886 +0000
+committer Bob <Bob@Example.com> 1588568886 +0000
+gpgsig -----BEGIN PGP SIGNATURE-----
+ 
+ iQEzBAABCAAdFiEE
+ -----END PGP SIGNATURE-----
+
+Initial commit
+`
+	got := ParseSignedCommit("abc", []byte(data))
+	assert.Equal(t, "abc", got.ID)
+	assert.Equal(t, "Bob@Example.com", got.CommitterEmail)
+	assert.Equal(t, `tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904
+author alice <alice@example.com> 1588568886 +0000
+committer Bob <Bob@Example.com> 1588568886 +0000
+
+Initial commit
+`, string(got.Payload))
+	assert.Equal(t, `-----BEGIN PGP SIGNATURE-----
+
+iQEzBAABCAAdFiEE
+-----END PGP SIGNATURE-----
+`, string(got.Signature))
+
+	// Unsigned commits keep the payload as is
+	data = `tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904
+author alice <alice@example.com> 1588568886 +0000
+committer alice <alice@example.com> 1588568886 +0000
+
+Initial commit
+`
+	got = ParseSignedCommit("abc", []byte(data))
+	assert.Equal(t, data, string(got.Payload))
+	assert.Empty(t, got.Signature)
+}
+
+func TestListNewSignedCommits(t *testing.T) {
+	repoPath := t.TempDir()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=alice", "GIT_AUTHOR_EMAIL=alice@example.com",
+			"GIT_COMMITTER_NAME=alice", "GIT_COMMITTER_EMAIL=alice@example.com",
+		)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		return strings.TrimSpace(string(output))
+	}
+
+	run("init", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "README.md"), []byte("# Hello"), 0o644))
+	run("add", "-A")
+	run("commit", "-m", "initial")
+	base := run("rev-parse", "HEAD")
+	run("commit", "--allow-empty", "-m", "second")
+	head := run("rev-parse", "HEAD")
+	run("update-ref", "refs/heads/main", base)
+
+	commits, err := ListNewSignedCommits(repoPath, head)
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	assert.Equal(t, head, commits[0].ID)
+	assert.Equal(t, "alice@example.com", commits[0].CommitterEmail)
+	assert.Empty(t, commits[0].Signature)
+
+	commits, err = ListNewSignedCommits(repoPath, base)
+	require.NoError(t, err)
+	assert.Empty(t, commits)
+}
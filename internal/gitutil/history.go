@@ -0,0 +1,92 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+)
+
+// FileHistoryEntry is a commit in the history of a file.
+type FileHistoryEntry struct {
+	CommitID string
+	// Path is the path of the file in the commit.
+	Path string
+	// OldPath is the path of the file before the commit, only set when the file
+	// was renamed in the commit.
+	OldPath string
+}
+
+// FileHistoryOptions contains optional arguments for listing history of a
+// file.
+type FileHistoryOptions struct {
+	// Follow indicates whether to continue listing history beyond renames.
+	Follow bool
+	// Skip is the number of commits to skip from the newest.
+	Skip int
+	// MaxCount is the maximum number of commits to return, 0 means no limit.
+	MaxCount int
+}
+
+// FileHistory returns commits that changed the file with given path, starting
+// from the revision in the repository in given path, ordered from the newest.
+func FileHistory(repoPath, rev, path string, opts FileHistoryOptions) ([]*FileHistoryEntry, error) {
+	if rev == "" || strings.HasPrefix(rev, "-") {
+		return nil, errors.Errorf("invalid revision %q", rev)
+	}
+
+	// Each commit is prefixed by a NUL character to split them reliably.
+	args := []string{"log", "--format=format:%x00%H", "--name-status", "-M"}
+	if opts.Follow {
+		args = append(args, "--follow")
+	}
+	if opts.Skip > 0 {
+		args = append(args, "--skip="+strconv.Itoa(opts.Skip))
+	}
+	if opts.MaxCount > 0 {
+		args = append(args, "--max-count="+strconv.Itoa(opts.MaxCount))
+	}
+	args = append(args, rev, "--", path)
+	output, err := git.NewCommand(args...).RunInDir(repoPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "log")
+	}
+	return parseFileHistory(string(output), path), nil
+}
+
+// parseFileHistory parses the output of "git log --name-status" in the format
+// of FileHistory. The path is the path of the file in the newest commit, and is
+// tracked across renames for commits without any changed file listed, e.g.
+// merge commits.
+func parseFileHistory(output, path string) []*FileHistoryEntry {
+	var entries []*FileHistoryEntry
+	for _, chunk := range strings.Split(output, "\x00") {
+		lines := strings.Split(strings.TrimSpace(chunk), "\n")
+		if lines[0] == "" {
+			continue
+		}
+
+		entry := &FileHistoryEntry{
+			CommitID: lines[0],
+			Path:     path,
+		}
+		for _, line := range lines[1:] {
+			fields := strings.Split(line, "\t")
+			switch {
+			case len(fields) == 3 && strings.HasPrefix(fields[0], "R"):
+				entry.OldPath = fields[1]
+				entry.Path = fields[2]
+				path = fields[1]
+			case len(fields) == 2:
+				entry.Path = fields[1]
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
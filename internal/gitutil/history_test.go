@@ -0,0 +1,84 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileHistory(t *testing.T) {
+	repoPath := t.TempDir()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=alice", "GIT_AUTHOR_EMAIL=alice@example.com",
+			"GIT_COMMITTER_NAME=alice", "GIT_COMMITTER_EMAIL=alice@example.com",
+		)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		return strings.TrimSpace(string(output))
+	}
+	commit := func(name, content, message string) string {
+		require.NoError(t, os.WriteFile(filepath.Join(repoPath, name), []byte(content), 0o644))
+		run("add", "-A")
+		run("commit", "-m", message)
+		return run("rev-parse", "HEAD")
+	}
+
+	run("init", "-b", "main")
+	content := "line 1\nline 2\nline 3\nline 4\nline 5\n"
+	first := commit("a.txt", content, "add a.txt")
+	second := commit("a.txt", content+"line 6\n", "update a.txt")
+	commit("other.txt", "other", "add other.txt")
+	run("mv", "a.txt", "b.txt")
+	run("commit", "-m", "rename a.txt to b.txt")
+	renamed := run("rev-parse", "HEAD")
+	last := commit("b.txt", content+"line 6\nline 7\n", "update b.txt")
+
+	t.Run("follow renames", func(t *testing.T) {
+		entries, err := FileHistory(repoPath, "main", "b.txt", FileHistoryOptions{Follow: true})
+		require.NoError(t, err)
+		want := []*FileHistoryEntry{
+			{CommitID: last, Path: "b.txt"},
+			{CommitID: renamed, Path: "b.txt", OldPath: "a.txt"},
+			{CommitID: second, Path: "a.txt"},
+			{CommitID: first, Path: "a.txt"},
+		}
+		assert.Equal(t, want, entries)
+	})
+
+	t.Run("not follow renames", func(t *testing.T) {
+		entries, err := FileHistory(repoPath, "main", "b.txt", FileHistoryOptions{})
+		require.NoError(t, err)
+		want := []*FileHistoryEntry{
+			{CommitID: last, Path: "b.txt"},
+			{CommitID: renamed, Path: "b.txt"},
+		}
+		assert.Equal(t, want, entries)
+	})
+
+	t.Run("paginate", func(t *testing.T) {
+		entries, err := FileHistory(repoPath, "main", "b.txt", FileHistoryOptions{Follow: true, Skip: 1, MaxCount: 2})
+		require.NoError(t, err)
+		want := []*FileHistoryEntry{
+			{CommitID: renamed, Path: "b.txt", OldPath: "a.txt"},
+			{CommitID: second, Path: "a.txt"},
+		}
+		assert.Equal(t, want, entries)
+	})
+
+	t.Run("invalid revision", func(t *testing.T) {
+		_, err := FileHistory(repoPath, "--output=/tmp/x", "b.txt", FileHistoryOptions{})
+		assert.Error(t, err)
+	})
+}
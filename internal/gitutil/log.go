@@ -0,0 +1,81 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+)
+
+// LogEntry is a commit listed by Log.
+type LogEntry struct {
+	CommitID       string
+	AuthorName     string
+	AuthorEmail    string
+	AuthoredAt     time.Time
+	CommitterName  string
+	CommitterEmail string
+	CommittedAt    time.Time
+	Message        string
+}
+
+// Log returns commits reachable from the revisions in the repository in given
+// path, ordered from the newest. The revisions are passed to "git log" as-is,
+// thus may also contain exclusions like "^<rev>" or "--not --branches", and
+// must never come from user input.
+func Log(repoPath string, revs ...string) ([]*LogEntry, error) {
+	if len(revs) == 0 {
+		return nil, errors.New("no revision")
+	}
+
+	// Commits are separated by NUL characters, and fields of each commit are
+	// separated by unit separators.
+	args := []string{"log", "-z", "--format=format:%H%x1f%an%x1f%ae%x1f%at%x1f%cn%x1f%ce%x1f%ct%x1f%B"}
+	args = append(args, revs...)
+	args = append(args, "--")
+	output, err := git.NewCommand(args...).RunInDir(repoPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "log")
+	}
+	return parseLog(string(output))
+}
+
+// parseLog parses the output of "git log" in the format of Log.
+func parseLog(output string) ([]*LogEntry, error) {
+	var entries []*LogEntry
+	for _, chunk := range strings.Split(output, "\x00") {
+		if chunk == "" {
+			continue
+		}
+
+		fields := strings.SplitN(chunk, "\x1f", 8)
+		if len(fields) != 8 {
+			return nil, errors.Errorf("malformed commit %q", chunk)
+		}
+		authoredAt, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse author date")
+		}
+		committedAt, err := strconv.ParseInt(fields[6], 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse committer date")
+		}
+		entries = append(entries, &LogEntry{
+			CommitID:       fields[0],
+			AuthorName:     fields[1],
+			AuthorEmail:    fields[2],
+			AuthoredAt:     time.Unix(authoredAt, 0).UTC(),
+			CommitterName:  fields[4],
+			CommitterEmail: fields[5],
+			CommittedAt:    time.Unix(committedAt, 0).UTC(),
+			Message:        strings.TrimRight(fields[7], "\n"),
+		})
+	}
+	return entries, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog(t *testing.T) {
+	repoPath := t.TempDir()
+	run := func(env []string, args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		cmd.Env = append(cmd.Environ(), env...)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		return strings.TrimSpace(string(output))
+	}
+	commit := func(name, message string, env ...string) string {
+		env = append([]string{
+			"GIT_AUTHOR_NAME=alice", "GIT_AUTHOR_EMAIL=alice@example.com", "GIT_AUTHOR_DATE=2026-01-01T00:00:00Z",
+			"GIT_COMMITTER_NAME=bob", "GIT_COMMITTER_EMAIL=bob@example.com", "GIT_COMMITTER_DATE=2026-01-02T00:00:00Z",
+		}, env...)
+		require.NoError(t, os.WriteFile(filepath.Join(repoPath, name), []byte(name), 0o644))
+		run(env, "add", "-A")
+		run(env, "commit", "-m", message)
+		return run(nil, "rev-parse", "HEAD")
+	}
+
+	run(nil, "init", "-b", "main")
+	first := commit("a.txt", "Add a.txt\n\nWith a body.")
+	second := commit("b.txt", "Add b.txt", "GIT_AUTHOR_NAME=carol", "GIT_AUTHOR_EMAIL=carol@example.com")
+	run(nil, "checkout", "-b", "feature")
+	third := commit("c.txt", "Add c.txt")
+
+	t.Run("all", func(t *testing.T) {
+		entries, err := Log(repoPath, "feature")
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		assert.Equal(t, []string{third, second, first}, []string{entries[0].CommitID, entries[1].CommitID, entries[2].CommitID})
+
+		want := &LogEntry{
+			CommitID:       first,
+			AuthorName:     "alice",
+			AuthorEmail:    "alice@example.com",
+			AuthoredAt:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			CommitterName:  "bob",
+			CommitterEmail: "bob@example.com",
+			CommittedAt:    time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+			Message:        "Add a.txt\n\nWith a body.",
+		}
+		assert.Equal(t, want, entries[2])
+		assert.Equal(t, "carol", entries[1].AuthorName)
+	})
+
+	t.Run("exclude", func(t *testing.T) {
+		entries, err := Log(repoPath, "feature", "^"+first)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, third, entries[0].CommitID)
+		assert.Equal(t, second, entries[1].CommitID)
+
+		entries, err = Log(repoPath, "feature", "--not", "--exclude=feature", "--branches")
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, third, entries[0].CommitID)
+	})
+
+	t.Run("no revision", func(t *testing.T) {
+		_, err := Log(repoPath)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+
+	"github.com/gogs/git-module"
+)
+
+type mailmapEntry struct {
+	properName  string
+	properEmail string
+	commitName  string
+	commitEmail string
+}
+
+// Mailmap maps names and email addresses of commit authors and committers to
+// the canonical ones, see https://git-scm.com/docs/gitmailmap for the format.
+type Mailmap struct {
+	entries []mailmapEntry
+}
+
+// ParseMailmap parses the content of a ".mailmap" file. Malformed lines are
+// ignored.
+func ParseMailmap(data []byte) *Mailmap {
+	m := new(Mailmap)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+
+		// Each line has at most two "Name <email>" pairs, where the first is the
+		// proper one and the second is the one to be matched in commits.
+		var names, emails []string
+		for len(emails) < 2 {
+			start := strings.Index(line, "<")
+			if start < 0 {
+				break
+			}
+			end := strings.Index(line[start:], ">")
+			if end < 0 {
+				break
+			}
+			names = append(names, strings.TrimSpace(line[:start]))
+			emails = append(emails, strings.TrimSpace(line[start+1:start+end]))
+			line = line[start+end+1:]
+		}
+
+		var entry mailmapEntry
+		switch len(emails) {
+		case 1:
+			// Proper Name <commit@email>
+			entry.properName = names[0]
+			entry.commitEmail = emails[0]
+		case 2:
+			// [Proper Name] <proper@email> [Commit Name] <commit@email>
+			entry.properName = names[0]
+			entry.properEmail = emails[0]
+			entry.commitName = names[1]
+			entry.commitEmail = emails[1]
+		default:
+			continue
+		}
+		if entry.commitEmail == "" || (entry.properName == "" && entry.properEmail == "") {
+			continue
+		}
+		m.entries = append(m.entries, entry)
+	}
+	return m
+}
+
+// Map returns the canonical name and email address for given ones. Entries
+// that match both the name and the email address take precedence over those
+// only match the email address, and later entries win over earlier ones.
+func (m *Mailmap) Map(name, email string) (string, string) {
+	if m == nil {
+		return name, email
+	}
+
+	var matched *mailmapEntry
+	for i := range m.entries {
+		e := &m.entries[i]
+		if !strings.EqualFold(e.commitEmail, email) {
+			continue
+		}
+
+		if e.commitName == "" {
+			if matched == nil || matched.commitName == "" {
+				matched = e
+			}
+		} else if strings.EqualFold(e.commitName, name) {
+			matched = e
+		}
+	}
+	if matched == nil {
+		return name, email
+	}
+
+	if matched.properName != "" {
+		name = matched.properName
+	}
+	if matched.properEmail != "" {
+		email = matched.properEmail
+	}
+	return name, email
+}
+
+func (m *Mailmap) mapSignature(sig *git.Signature) *git.Signature {
+	if sig == nil {
+		return nil
+	}
+
+	name, email := m.Map(sig.Name, sig.Email)
+	if name == sig.Name && email == sig.Email {
+		return sig
+	}
+	return &git.Signature{
+		Name:  name,
+		Email: email,
+		When:  sig.When,
+	}
+}
+
+// MapCommits replaces authors and committers of commits with the canonical
+// ones. Signatures are replaced rather than modified in place because they may
+// be shared with other commits.
+func (m *Mailmap) MapCommits(commits ...*git.Commit) {
+	if m == nil || len(m.entries) == 0 {
+		return
+	}
+
+	for _, c := range commits {
+		c.Author = m.mapSignature(c.Author)
+		c.Committer = m.mapSignature(c.Committer)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogs/git-module"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMailmap_Map(t *testing.T) {
+	m := ParseMailmap([]byte(`
+# Comments and malformed lines are ignored
+Not an entry
+<only@email.com>
+
+Joe Developer <joe@example.com>
+<jane@example.com> <jane@laptop.(none)>
+Other Author <other@author.xx> <nick2@company.xx>
+Other Author <other@author.xx> nick1 <bugs@company.xx>
+Santa Claus <santa.claus@northpole.xx> <me@company.xx> # trailing comment
+`))
+
+	tests := []struct {
+		name      string
+		email     string
+		wantName  string
+		wantEmail string
+	}{
+		{
+			name: "Joe", email: "joe@example.com",
+			wantName: "Joe Developer", wantEmail: "joe@example.com",
+		},
+		{
+			name: "Jane", email: "JANE@laptop.(none)",
+			wantName: "Jane", wantEmail: "jane@example.com",
+		},
+		{
+			name: "nick2", email: "nick2@company.xx",
+			wantName: "Other Author", wantEmail: "other@author.xx",
+		},
+		{
+			name: "nick1", email: "bugs@company.xx",
+			wantName: "Other Author", wantEmail: "other@author.xx",
+		},
+		{
+			name: "nick3", email: "bugs@company.xx",
+			wantName: "nick3", wantEmail: "bugs@company.xx",
+		},
+		{
+			name: "Santa", email: "me@company.xx",
+			wantName: "Santa Claus", wantEmail: "santa.claus@northpole.xx",
+		},
+		{
+			name: "Nobody", email: "nobody@example.com",
+			wantName: "Nobody", wantEmail: "nobody@example.com",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotName, gotEmail := m.Map(test.name, test.email)
+			assert.Equal(t, test.wantName, gotName)
+			assert.Equal(t, test.wantEmail, gotEmail)
+		})
+	}
+}
+
+func TestMailmap_MapCommits(t *testing.T) {
+	now := time.Now()
+	sig := &git.Signature{Name: "jane", Email: "jane@laptop", When: now}
+	commits := []*git.Commit{
+		{Author: sig, Committer: sig},
+		{Author: &git.Signature{Name: "joe", Email: "joe@example.com", When: now}, Committer: sig},
+	}
+
+	m := ParseMailmap([]byte(`Jane Doe <jane@example.com> <jane@laptop>`))
+	m.MapCommits(commits...)
+
+	want := &git.Signature{Name: "Jane Doe", Email: "jane@example.com", When: now}
+	assert.Equal(t, want, commits[0].Author)
+	assert.Equal(t, want, commits[0].Committer)
+	assert.Equal(t, &git.Signature{Name: "joe", Email: "joe@example.com", When: now}, commits[1].Author)
+	assert.Equal(t, want, commits[1].Committer)
+
+	// The original signature should not be modified.
+	assert.Equal(t, "jane@laptop", sig.Email)
+
+	// Nil mailmap is a no-op.
+	var empty *Mailmap
+	empty.MapCommits(commits...)
+	name, email := empty.Map("a", "b")
+	assert.Equal(t, "a", name)
+	assert.Equal(t, "b", email)
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+)
+
+// MaintainersPaths is the list of paths that a MAINTAINERS file is looked up in
+// order.
+var MaintainersPaths = []string{"MAINTAINERS", ".gogs/MAINTAINERS", "docs/MAINTAINERS"}
+
+// ParseMaintainers returns distinct lower-cased user names listed in the
+// content of a MAINTAINERS file. Each line lists one maintainer, either as a
+// mention like "Jane Doe <jane@example.com> @jane" or as the user name alone
+// like "jane". Text after "#" is a comment, and lines without a user name are
+// ignored.
+func ParseMaintainers(data []byte) []string {
+	var names []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+
+		name := ""
+		for _, field := range fields {
+			if strings.HasPrefix(field, "@") {
+				name = strings.TrimPrefix(field, "@")
+				break
+			}
+		}
+		if name == "" && len(fields) == 1 && !strings.ContainsAny(fields[0], "@<>") {
+			name = fields[0]
+		}
+
+		name = strings.ToLower(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// CommitMaintainers returns user names listed in the first MAINTAINERS file
+// found in the commit, see MaintainersPaths for the lookup order.
+func CommitMaintainers(commit *git.Commit) ([]string, error) {
+	var (
+		entry *git.TreeEntry
+		err   error
+	)
+	for _, p := range MaintainersPaths {
+		entry, err = commit.TreeEntry(p)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "get MAINTAINERS")
+	}
+
+	p, err := entry.Blob().Bytes()
+	if err != nil {
+		return nil, errors.Wrap(err, "read MAINTAINERS")
+	}
+	return ParseMaintainers(p), nil
+}
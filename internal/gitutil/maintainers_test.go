@@ -0,0 +1,27 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMaintainers(t *testing.T) {
+	got := ParseMaintainers([]byte(`
+# Maintainers of the project
+
+Jane Doe <jane@example.com> @Jane
+@bob # Release manager
+cindy
+Dan <dan@example.com>
+Eve Smith
+@jane
+`))
+	assert.Equal(t, []string{"jane", "bob", "cindy"}, got)
+
+	assert.Nil(t, ParseMaintainers([]byte("# Nobody yet\n")))
+}
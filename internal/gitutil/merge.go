@@ -0,0 +1,68 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// MergeTextLabels are the labels of each side in conflict markers of MergeText.
+type MergeTextLabels struct {
+	Ours   string
+	Base   string
+	Theirs string
+}
+
+// MergeText merges changes from base to theirs into ours line by line, as
+// what "git merge-file" does. It returns true when there is no conflict,
+// otherwise the merged text contains conflict markers with given labels.
+func MergeText(base, ours, theirs string, labels MergeTextLabels) (merged string, clean bool, err error) {
+	dir, err := os.MkdirTemp("", "gogs-merge-text-*")
+	if err != nil {
+		return "", false, errors.Wrap(err, "create temporary directory")
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"ours", ours},
+		{"base", base},
+		{"theirs", theirs},
+	}
+	for _, f := range files {
+		err = os.WriteFile(filepath.Join(dir, f.name), []byte(f.content), 0o600)
+		if err != nil {
+			return "", false, errors.Wrapf(err, "write %q", f.name)
+		}
+	}
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	cmd := exec.Command("git", "merge-file", "--stdout",
+		"-L", labels.Ours, "-L", labels.Base, "-L", labels.Theirs,
+		"ours", "base", "theirs",
+	)
+	cmd.Dir = dir
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err = cmd.Run()
+	if err != nil {
+		// A positive exit status is the number of conflicts, and a negative one
+		// (i.e. 255 on most platforms) indicates an error.
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok || exitErr.ExitCode() <= 0 || exitErr.ExitCode() >= 128 {
+			return "", false, errors.Wrapf(err, "merge file: %s", stderr)
+		}
+		return stdout.String(), false, nil
+	}
+	return stdout.String(), true, nil
+}
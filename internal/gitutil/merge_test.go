@@ -0,0 +1,36 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeText(t *testing.T) {
+	labels := MergeTextLabels{Ours: "yours", Base: "original", Theirs: "theirs"}
+	base := "Steps:\n1. Open\n2. Click\n3. Crash\n"
+
+	t.Run("clean", func(t *testing.T) {
+		ours := "Steps:\n1. Open the app\n2. Click\n3. Crash\n"
+		theirs := "Steps:\n1. Open\n2. Click\n3. Crash\n\nVersion: 0.13\n"
+		merged, clean, err := MergeText(base, ours, theirs, labels)
+		require.NoError(t, err)
+		assert.True(t, clean)
+		assert.Equal(t, "Steps:\n1. Open the app\n2. Click\n3. Crash\n\nVersion: 0.13\n", merged)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		ours := "Steps:\n1. Open\n2. Click twice\n3. Crash\n"
+		theirs := "Steps:\n1. Open\n2. Double click\n3. Crash\n"
+		merged, clean, err := MergeText(base, ours, theirs, labels)
+		require.NoError(t, err)
+		assert.False(t, clean)
+		want := "Steps:\n1. Open\n<<<<<<< yours\n2. Click twice\n=======\n2. Double click\n>>>>>>> theirs\n3. Crash\n"
+		assert.Equal(t, want, merged)
+	})
+}
@@ -0,0 +1,20 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+)
+
+// IsForcePush returns true if the oldCommitID is not an ancestor of the
+// newCommitID in the repository, i.e. the push rewrote the history.
+func IsForcePush(repoPath, oldCommitID, newCommitID string) (bool, error) {
+	output, err := git.NewCommand("rev-list", "--max-count=1", oldCommitID, "^"+newCommitID).RunInDir(repoPath)
+	if err != nil {
+		return false, errors.Wrap(err, "list commits")
+	}
+	return len(output) > 0, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsForcePush(t *testing.T) {
+	repoPath := t.TempDir()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=alice", "GIT_AUTHOR_EMAIL=alice@example.com",
+			"GIT_COMMITTER_NAME=alice", "GIT_COMMITTER_EMAIL=alice@example.com",
+		)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		return strings.TrimSpace(string(output))
+	}
+
+	run("init")
+	run("commit", "--allow-empty", "-m", "first")
+	first := run("rev-parse", "HEAD")
+	run("commit", "--allow-empty", "-m", "second")
+	second := run("rev-parse", "HEAD")
+	run("commit", "--amend", "--allow-empty", "-m", "second amended")
+	amended := run("rev-parse", "HEAD")
+
+	tests := []struct {
+		name        string
+		oldCommitID string
+		newCommitID string
+		want        bool
+	}{
+		{
+			name:        "fast-forward",
+			oldCommitID: first,
+			newCommitID: second,
+			want:        false,
+		},
+		{
+			name:        "amended",
+			oldCommitID: second,
+			newCommitID: amended,
+			want:        true,
+		},
+		{
+			name:        "reset",
+			oldCommitID: second,
+			newCommitID: first,
+			want:        true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := IsForcePush(repoPath, test.oldCommitID, test.newCommitID)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+)
+
+// ReceivePackInspector inspects the request stream of a receive-pack session to
+// find out which references the client pushes.
+type ReceivePackInspector struct {
+	mu       sync.Mutex
+	refs     []string
+	received int64
+
+	request *pktLineScanner
+}
+
+// NewReceivePackInspector returns a new inspector of a receive-pack session.
+func NewReceivePackInspector() *ReceivePackInspector {
+	i := &ReceivePackInspector{}
+	i.request = &pktLineScanner{handle: i.handleRequest}
+	return i
+}
+
+// RequestWriter returns the writer to be written with the request stream.
+func (i *ReceivePackInspector) RequestWriter() io.Writer {
+	return writerFunc(func(p []byte) (int, error) {
+		i.mu.Lock()
+		i.received += int64(len(p))
+		i.mu.Unlock()
+		return i.request.Write(p)
+	})
+}
+
+func (i *ReceivePackInspector) handleRequest(payload []byte, raw bool) bool {
+	// The list of commands ends with a flush packet.
+	if raw || payload == nil {
+		return false
+	}
+
+	// The first command is followed by capabilities after a NUL byte.
+	if j := bytes.IndexByte(payload, 0); j >= 0 {
+		payload = payload[:j]
+	}
+	fields := strings.Fields(string(payload))
+	if len(fields) != 3 {
+		// Skip "shallow" lines and anything else that is not a command.
+		return true
+	}
+
+	i.mu.Lock()
+	i.refs = append(i.refs, fields[2])
+	i.mu.Unlock()
+	return true
+}
+
+// Refs returns full names of references that the client updates, creates or
+// deletes, in the order of commands sent by the client.
+func (i *ReceivePackInspector) Refs() []string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.refs
+}
+
+// BytesReceived returns the number of bytes written to the request stream.
+func (i *ReceivePackInspector) BytesReceived() int64 {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.received
+}
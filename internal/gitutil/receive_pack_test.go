@@ -0,0 +1,69 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceivePackInspector(t *testing.T) {
+	dir := t.TempDir()
+	run := func(dir string, args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=alice", "GIT_AUTHOR_EMAIL=alice@example.com",
+			"GIT_COMMITTER_NAME=alice", "GIT_COMMITTER_EMAIL=alice@example.com",
+		)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		return strings.TrimSpace(string(output))
+	}
+
+	remotePath := filepath.Join(dir, "remote.git")
+	run(dir, "init", "--bare", remotePath)
+
+	repoPath := filepath.Join(dir, "repo")
+	require.NoError(t, os.MkdirAll(repoPath, os.ModePerm))
+	run(repoPath, "init", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "README.md"), []byte("# Hello"), 0o644))
+	run(repoPath, "add", "-A")
+	run(repoPath, "commit", "-m", "initial")
+	run(repoPath, "tag", "v1.0.0")
+	run(repoPath, "push", "file://"+remotePath, "main", "v1.0.0")
+
+	// The wrapper records the request stream of receive-pack.
+	wrapper := filepath.Join(dir, "receive-pack.sh")
+	script := fmt.Sprintf("#!/bin/sh\ntee %q | git receive-pack \"$@\"\n", filepath.Join(dir, "request"))
+	require.NoError(t, os.WriteFile(wrapper, []byte(script), 0o755))
+
+	run(repoPath, "commit", "--allow-empty", "-m", "second")
+	run(repoPath, "push", "--receive-pack="+wrapper, "file://"+remotePath, "main", "main:develop", ":refs/tags/v1.0.0")
+
+	request, err := os.ReadFile(filepath.Join(dir, "request"))
+	require.NoError(t, err)
+
+	inspector := NewReceivePackInspector()
+	// Write in small chunks to exercise buffering of partial pkt-lines.
+	for data := request; len(data) > 0; {
+		n := 7
+		if n > len(data) {
+			n = len(data)
+		}
+		_, _ = inspector.RequestWriter().Write(data[:n])
+		data = data[n:]
+	}
+
+	assert.ElementsMatch(t, []string{"refs/heads/main", "refs/heads/develop", "refs/tags/v1.0.0"}, inspector.Refs())
+	assert.EqualValues(t, len(request), inspector.BytesReceived())
+}
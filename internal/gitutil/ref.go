@@ -0,0 +1,74 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+)
+
+// RefUpdate is an update of a reference in a repository.
+type RefUpdate struct {
+	// The full name of the reference, e.g. "refs/heads/main".
+	Ref string
+	// The new object ID of the reference, empty to delete the reference.
+	NewID string
+	// The expected current object ID of the reference, empty to require the
+	// reference to not exist.
+	OldID string
+}
+
+// ErrRefsChanged is returned by UpdateRefs when any of the references does not
+// have the expected current object ID.
+var ErrRefsChanged = errors.New("references have been changed")
+
+// IsErrRefsChanged returns true if the error is ErrRefsChanged.
+func IsErrRefsChanged(err error) bool {
+	return err == ErrRefsChanged
+}
+
+// IsValidRefName returns true if the given full name of a reference is
+// well-formed.
+func IsValidRefName(ref string) bool {
+	if strings.ContainsAny(ref, " \t\r\n") {
+		return false
+	}
+	_, err := git.NewCommand("check-ref-format", ref).Run()
+	return err == nil
+}
+
+// UpdateRefs updates references of the repository atomically, i.e. either all
+// of them are updated or none of them is. It returns ErrRefsChanged when any
+// of the references does not have the expected current object ID.
+func UpdateRefs(repoPath string, updates ...RefUpdate) error {
+	var stdin bytes.Buffer
+	for _, u := range updates {
+		switch {
+		case u.OldID == "":
+			_, _ = fmt.Fprintf(&stdin, "create %s %s\n", u.Ref, u.NewID)
+		case u.NewID == "":
+			_, _ = fmt.Fprintf(&stdin, "delete %s %s\n", u.Ref, u.OldID)
+		default:
+			_, _ = fmt.Fprintf(&stdin, "update %s %s %s\n", u.Ref, u.NewID, u.OldID)
+		}
+	}
+
+	var stderr bytes.Buffer
+	err := git.NewCommand("update-ref", "--stdin").RunInDirWithOptions(repoPath, git.RunInDirOptions{
+		Stdin:  &stdin,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		if strings.Contains(stderr.String(), "cannot lock ref") {
+			return ErrRefsChanged
+		}
+		return errors.Wrapf(err, "update refs: %s", strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+)
+
+// Kinds of drift of a reference.
+const (
+	// RefDriftMissing is a reference that exists upstream but not locally.
+	RefDriftMissing = "missing"
+	// RefDriftExtra is a reference that exists locally but not upstream.
+	RefDriftExtra = "extra"
+	// RefDriftDiverged is a reference that points to different objects upstream
+	// and locally.
+	RefDriftDiverged = "diverged"
+)
+
+// RefDrift is a branch or tag of a mirror that differs from its upstream.
+type RefDrift struct {
+	RefName string
+	// UpstreamCommitID is empty if the reference does not exist upstream.
+	UpstreamCommitID string
+	// LocalCommitID is empty if the reference does not exist locally.
+	LocalCommitID string
+}
+
+// Kind returns the kind of the drift.
+func (d *RefDrift) Kind() string {
+	switch {
+	case d.LocalCommitID == "":
+		return RefDriftMissing
+	case d.UpstreamCommitID == "":
+		return RefDriftExtra
+	}
+	return RefDriftDiverged
+}
+
+// isDriftCheckedRef returns true if drift of the reference is checked, i.e. it
+// is a branch or a tag.
+func isDriftCheckedRef(refName string) bool {
+	return strings.HasPrefix(refName, git.RefsHeads) || strings.HasPrefix(refName, git.RefsTags)
+}
+
+// parseRefs parses the output of "git ls-remote" or "git for-each-ref" in the
+// form of "<object ID> <reference>" per line, and returns references of
+// branches and tags mapped to their object IDs. Peeled tags are skipped.
+func parseRefs(output []byte) map[string]string {
+	refs := make(map[string]string)
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		id, refName := fields[0], fields[1]
+		if strings.HasSuffix(refName, "^{}") || !isDriftCheckedRef(refName) {
+			continue
+		}
+		refs[refName] = id
+	}
+	return refs
+}
+
+// CompareRefs returns references that differ between upstream and local,
+// sorted by reference names.
+func CompareRefs(upstream, local map[string]string) []*RefDrift {
+	drifts := make([]*RefDrift, 0)
+	for refName, upstreamID := range upstream {
+		if local[refName] != upstreamID {
+			drifts = append(drifts, &RefDrift{
+				RefName:          refName,
+				UpstreamCommitID: upstreamID,
+				LocalCommitID:    local[refName],
+			})
+		}
+	}
+	for refName, localID := range local {
+		if _, ok := upstream[refName]; !ok {
+			drifts = append(drifts, &RefDrift{
+				RefName:       refName,
+				LocalCommitID: localID,
+			})
+		}
+	}
+	sort.Slice(drifts, func(i, j int) bool {
+		return drifts[i].RefName < drifts[j].RefName
+	})
+	return drifts
+}
+
+// ListRemoteRefs returns branches and tags of the remote of the repository in
+// given path, mapped to their object IDs.
+func ListRemoteRefs(repoPath, remote string, timeout time.Duration) (map[string]string, error) {
+	output, err := git.NewCommand("ls-remote", "--heads", "--tags", remote).RunInDirWithTimeout(timeout, repoPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "list remote references")
+	}
+	return parseRefs(output), nil
+}
+
+// ListLocalRefs returns branches and tags of the repository in given path,
+// mapped to their object IDs.
+func ListLocalRefs(repoPath string) (map[string]string, error) {
+	output, err := git.NewCommand("for-each-ref", "--format=%(objectname) %(refname)", git.RefsHeads, git.RefsTags).RunInDir(repoPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "list local references")
+	}
+	return parseRefs(output), nil
+}
+
+// ResyncRefs forcibly updates the drifted references of the repository in given
+// path to match the remote, i.e. references are fetched from the remote, or
+// deleted if they do not exist upstream.
+func ResyncRefs(repoPath, remote string, timeout time.Duration, drifts []*RefDrift) error {
+	refspecs := make([]string, 0, len(drifts))
+	for _, d := range drifts {
+		if !isDriftCheckedRef(d.RefName) {
+			return errors.Errorf("invalid reference %q", d.RefName)
+		}
+
+		if d.Kind() == RefDriftExtra {
+			_, err := git.NewCommand("update-ref", "-d", d.RefName).RunInDir(repoPath)
+			if err != nil {
+				return errors.Wrapf(err, "delete reference %q", d.RefName)
+			}
+			continue
+		}
+		refspecs = append(refspecs, "+"+d.RefName+":"+d.RefName)
+	}
+	if len(refspecs) == 0 {
+		return nil
+	}
+
+	args := append([]string{"fetch", "--force", "--no-tags", remote}, refspecs...)
+	_, err := git.NewCommand(args...).RunInDirWithTimeout(timeout, repoPath)
+	if err != nil {
+		return errors.Wrap(err, "fetch references")
+	}
+	return nil
+}
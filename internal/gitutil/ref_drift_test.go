@@ -0,0 +1,108 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRefs(t *testing.T) {
+	output := `1111111111111111111111111111111111111111	HEAD
+2222222222222222222222222222222222222222	refs/heads/main
+3333333333333333333333333333333333333333	refs/tags/v1.0
+4444444444444444444444444444444444444444	refs/tags/v1.0^{}
+5555555555555555555555555555555555555555	refs/pull/1/head
+`
+	got := parseRefs([]byte(output))
+	want := map[string]string{
+		"refs/heads/main": "2222222222222222222222222222222222222222",
+		"refs/tags/v1.0":  "3333333333333333333333333333333333333333",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestCompareRefs(t *testing.T) {
+	upstream := map[string]string{
+		"refs/heads/main":    "a",
+		"refs/heads/feature": "b",
+		"refs/tags/v1.0":     "c",
+	}
+	local := map[string]string{
+		"refs/heads/main": "a",
+		"refs/heads/old":  "d",
+		"refs/tags/v1.0":  "e",
+	}
+	got := CompareRefs(upstream, local)
+	want := []*RefDrift{
+		{RefName: "refs/heads/feature", UpstreamCommitID: "b"},
+		{RefName: "refs/heads/old", LocalCommitID: "d"},
+		{RefName: "refs/tags/v1.0", UpstreamCommitID: "c", LocalCommitID: "e"},
+	}
+	assert.Equal(t, want, got)
+	assert.Equal(t, RefDriftMissing, got[0].Kind())
+	assert.Equal(t, RefDriftExtra, got[1].Kind())
+	assert.Equal(t, RefDriftDiverged, got[2].Kind())
+
+	assert.Empty(t, CompareRefs(upstream, upstream))
+}
+
+func TestResyncRefs(t *testing.T) {
+	upstreamPath := t.TempDir()
+	mirrorPath := t.TempDir()
+	run := func(dir string, args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=alice", "GIT_AUTHOR_EMAIL=alice@example.com",
+			"GIT_COMMITTER_NAME=alice", "GIT_COMMITTER_EMAIL=alice@example.com",
+		)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		return strings.TrimSpace(string(output))
+	}
+
+	run(upstreamPath, "init", "-b", "main")
+	run(upstreamPath, "commit", "--allow-empty", "-m", "initial")
+	run(upstreamPath, "tag", "-a", "v1.0", "-m", "v1.0")
+	run(mirrorPath, "clone", "--mirror", upstreamPath, ".")
+
+	// Make the mirror drift from upstream
+	run(upstreamPath, "commit", "--allow-empty", "-m", "second")
+	run(upstreamPath, "branch", "feature")
+	run(mirrorPath, "update-ref", "refs/heads/old", "refs/heads/main")
+
+	upstream, err := ListRemoteRefs(mirrorPath, "origin", time.Minute)
+	require.NoError(t, err)
+	local, err := ListLocalRefs(mirrorPath)
+	require.NoError(t, err)
+	assert.Len(t, upstream, 3)
+	assert.Len(t, local, 3)
+
+	drifts := CompareRefs(upstream, local)
+	var names []string
+	for _, d := range drifts {
+		names = append(names, d.RefName+":"+d.Kind())
+	}
+	assert.Equal(t, []string{"refs/heads/feature:missing", "refs/heads/main:diverged", "refs/heads/old:extra"}, names)
+
+	// Only re-sync the missing and the extra references
+	err = ResyncRefs(mirrorPath, "origin", time.Minute, []*RefDrift{drifts[0], drifts[2]})
+	require.NoError(t, err)
+
+	local, err = ListLocalRefs(mirrorPath)
+	require.NoError(t, err)
+	drifts = CompareRefs(upstream, local)
+	require.Len(t, drifts, 1)
+	assert.Equal(t, "refs/heads/main", drifts[0].RefName)
+
+	err = ResyncRefs(mirrorPath, "origin", time.Minute, []*RefDrift{{RefName: "HEAD", UpstreamCommitID: "a"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidRefName(t *testing.T) {
+	assert.True(t, IsValidRefName("refs/heads/main"))
+	assert.True(t, IsValidRefName("refs/tags/v1.0.0"))
+	assert.False(t, IsValidRefName("refs/heads/a..b"))
+	assert.False(t, IsValidRefName("refs/heads/main refs/heads/dev"))
+	assert.False(t, IsValidRefName("refs/heads/main\nupdate"))
+}
+
+func TestUpdateRefs(t *testing.T) {
+	repoPath := t.TempDir()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=alice", "GIT_AUTHOR_EMAIL=alice@example.com",
+			"GIT_COMMITTER_NAME=alice", "GIT_COMMITTER_EMAIL=alice@example.com",
+		)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		return strings.TrimSpace(string(output))
+	}
+
+	run("init")
+	run("commit", "--allow-empty", "-m", "first")
+	first := run("rev-parse", "HEAD")
+	run("commit", "--allow-empty", "-m", "second")
+	second := run("rev-parse", "HEAD")
+	run("branch", "dev", first)
+	run("branch", "old", first)
+
+	// Nothing is updated when any of the references has been changed.
+	err := UpdateRefs(repoPath,
+		RefUpdate{Ref: "refs/tags/v1.0.0", NewID: second},
+		RefUpdate{Ref: "refs/heads/dev", NewID: second, OldID: second},
+	)
+	assert.True(t, IsErrRefsChanged(err), "%v", err)
+	assert.Equal(t, first, run("rev-parse", "refs/heads/dev"))
+	assert.Empty(t, run("tag", "--list"))
+
+	err = UpdateRefs(repoPath,
+		RefUpdate{Ref: "refs/tags/v1.0.0", NewID: second},
+		RefUpdate{Ref: "refs/heads/dev", NewID: second, OldID: first},
+		RefUpdate{Ref: "refs/heads/old", OldID: first},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, second, run("rev-parse", "refs/tags/v1.0.0"))
+	assert.Equal(t, second, run("rev-parse", "refs/heads/dev"))
+	assert.NotContains(t, run("branch", "--list"), "old")
+}
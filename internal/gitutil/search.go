@@ -0,0 +1,94 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"bytes"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+)
+
+// SearchFileNames returns paths of files in the tree of the revision in the
+// repository in given path, which contain the keyword case-insensitively. At
+// most limit paths are returned.
+func SearchFileNames(repoPath, rev, keyword string, limit int) ([]string, error) {
+	if rev == "" || strings.HasPrefix(rev, "-") {
+		return nil, errors.Errorf("invalid revision %q", rev)
+	}
+
+	output, err := git.NewCommand("ls-tree", "-r", "-z", "--name-only", rev).RunInDir(repoPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "list tree")
+	}
+
+	keyword = strings.ToLower(keyword)
+	paths := make([]string, 0, limit)
+	for _, path := range strings.Split(string(output), "\x00") {
+		if path == "" || !strings.Contains(strings.ToLower(path), keyword) {
+			continue
+		}
+		paths = append(paths, path)
+		if len(paths) >= limit {
+			break
+		}
+	}
+	return paths, nil
+}
+
+// GrepResult is a line of a file that matches the keyword.
+type GrepResult struct {
+	Path    string
+	Line    int
+	Content string
+}
+
+// Grep searches lines of text files in the tree of the revision in the
+// repository in given path, which contain the keyword case-insensitively. At
+// most limit lines are returned.
+func Grep(repoPath, rev, keyword string, limit int, timeout time.Duration) ([]*GrepResult, error) {
+	if rev == "" || strings.HasPrefix(rev, "-") {
+		return nil, errors.Errorf("invalid revision %q", rev)
+	}
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	err := git.NewCommand("grep", "-I", "-i", "-n", "-F", "--null", "-e", keyword, rev, "--").
+		RunInDirPipelineWithTimeout(timeout, stdout, stderr, repoPath)
+	if err != nil {
+		// Git exits with status 1 when nothing is matched.
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok || exitErr.ExitCode() != 1 || stderr.Len() > 0 {
+			return nil, errors.Wrapf(err, "grep: %s", stderr.String())
+		}
+		return []*GrepResult{}, nil
+	}
+
+	// Each line is in the form of "<rev>:<path>\0<line>\0<content>".
+	results := make([]*GrepResult, 0, limit)
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		fields := strings.SplitN(line, "\x00", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		n, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		results = append(results, &GrepResult{
+			Path:    strings.TrimPrefix(fields[0], rev+":"),
+			Line:    n,
+			Content: fields[2],
+		})
+		if len(results) >= limit {
+			break
+		}
+	}
+	return results, nil
+}
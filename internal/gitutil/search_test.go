@@ -0,0 +1,74 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	repoPath := t.TempDir()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=alice", "GIT_AUTHOR_EMAIL=alice@example.com",
+			"GIT_COMMITTER_NAME=alice", "GIT_COMMITTER_EMAIL=alice@example.com",
+		)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		return strings.TrimSpace(string(output))
+	}
+
+	run("init", "-b", "main")
+	require.NoError(t, os.MkdirAll(filepath.Join(repoPath, "docs"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "README.md"), []byte("# Hello\nSee the docs.\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "docs", "Install.md"), []byte("Install with care.\nhello world\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "logo.png"), []byte("hello\x00binary"), 0o644))
+	run("add", "-A")
+	run("commit", "-m", "initial")
+
+	t.Run("SearchFileNames", func(t *testing.T) {
+		paths, err := SearchFileNames(repoPath, "main", "INSTALL", 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"docs/Install.md"}, paths)
+
+		paths, err = SearchFileNames(repoPath, "main", ".md", 1)
+		require.NoError(t, err)
+		assert.Len(t, paths, 1)
+
+		_, err = SearchFileNames(repoPath, "--help", ".md", 1)
+		assert.Error(t, err)
+	})
+
+	t.Run("Grep", func(t *testing.T) {
+		results, err := Grep(repoPath, "main", "hello", 10, time.Minute)
+		require.NoError(t, err)
+		want := []*GrepResult{
+			{Path: "README.md", Line: 1, Content: "# Hello"},
+			{Path: "docs/Install.md", Line: 2, Content: "hello world"},
+		}
+		assert.Equal(t, want, results)
+
+		results, err = Grep(repoPath, "main", "hello", 1, time.Minute)
+		require.NoError(t, err)
+		assert.Len(t, results, 1)
+
+		results, err = Grep(repoPath, "main", "nothing", 10, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+
+		_, err = Grep(repoPath, "404", "hello", 10, time.Minute)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"bytes"
+	"regexp"
+)
+
+// SecretRule is a rule to detect a kind of secrets in file content.
+type SecretRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// SecretRules are rules of well-known secrets, which are specific enough to
+// block pushes without many false positives.
+var SecretRules = []*SecretRule{
+	{Name: "Private key", Pattern: regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )?PRIVATE KEY(?: BLOCK)?-----`)},
+	{Name: "AWS access key ID", Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{Name: "GitHub token", Pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9_]{82})\b`)},
+	{Name: "GitLab personal access token", Pattern: regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20}\b`)},
+	{Name: "Slack token", Pattern: regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{Name: "Stripe secret key", Pattern: regexp.MustCompile(`\b[rs]k_live_[A-Za-z0-9]{24,}\b`)},
+	{Name: "Google API key", Pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+}
+
+// SecretFinding is a secret found in a file.
+type SecretFinding struct {
+	Path string
+	Line int
+	Rule string
+}
+
+// FindSecret returns the line number (starting from 1) and the name of the
+// rule of the first secret found in the content. It returns 0 and an empty
+// string if there is none, or the content is binary.
+func FindSecret(content []byte) (line int, rule string) {
+	if isBinary(content) {
+		return 0, ""
+	}
+
+	for i, l := range bytes.Split(content, []byte("\n")) {
+		for _, r := range SecretRules {
+			if r.Pattern.Match(l) {
+				return i + 1, r.Name
+			}
+		}
+	}
+	return 0, ""
+}
+
+// isBinary returns true if the content looks binary, i.e. contains a NUL byte
+// in the first 8000 bytes like Git does.
+func isBinary(content []byte) bool {
+	if len(content) > 8000 {
+		content = content[:8000]
+	}
+	return bytes.IndexByte(content, 0) >= 0
+}
+
+// ScanBlobsForSecrets returns the first secret found in given blobs of the
+// repository in given path, or nil if there is none. Blobs larger than the max
+// size are skipped.
+func ScanBlobsForSecrets(repoPath string, blobs []*BlobInfo, maxSize int64) (*SecretFinding, error) {
+	paths := make(map[string]string, len(blobs))
+	ids := make([]string, 0, len(blobs))
+	for _, b := range blobs {
+		if b.Size > maxSize {
+			continue
+		}
+		paths[b.ID] = b.Path
+		ids = append(ids, b.ID)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var finding *SecretFinding
+	err := readObjects(repoPath, ids, func(id, _ string, content []byte) bool {
+		if line, rule := FindSecret(content); rule != "" {
+			finding = &SecretFinding{
+				Path: paths[id],
+				Line: line,
+				Rule: rule,
+			}
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return finding, nil
+}
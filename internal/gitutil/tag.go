@@ -5,6 +5,9 @@
 package gitutil
 
 import (
+	"strings"
+
+	"github.com/gogs/git-module"
 	"github.com/pkg/errors"
 )
 
@@ -93,3 +96,47 @@ func (module) ListTagsAfter(repoPath, after string, limit int) (*TagsPage, error
 		HasNext:       true,
 	}, nil
 }
+
+// TagSignatureStatus is the status of the signature of an annotated tag.
+type TagSignatureStatus string
+
+const (
+	TagSignatureUnsigned   TagSignatureStatus = "unsigned"
+	TagSignatureUnverified TagSignatureStatus = "unverified"
+	TagSignatureVerified   TagSignatureStatus = "verified"
+)
+
+var tagSignatureHeaders = []string{
+	"-----BEGIN PGP SIGNATURE-----",
+	"-----BEGIN SSH SIGNATURE-----",
+}
+
+// SplitTagSignature splits the message of an annotated tag into the message
+// itself and the signature appended by "git tag -s". The signature is empty if
+// the tag is not signed.
+func SplitTagSignature(message string) (string, string) {
+	for _, header := range tagSignatureHeaders {
+		if strings.HasPrefix(message, header) {
+			return "", message
+		}
+		if i := strings.Index(message, "\n"+header); i >= 0 {
+			return message[:i+1], message[i+1:]
+		}
+	}
+	return message, ""
+}
+
+// VerifyTag returns the signature status of the tag with given name in the
+// repository. Signatures are verified against keys known to the server, thus
+// valid signatures of unknown keys are considered unverified.
+func VerifyTag(repoPath, name, signature string) TagSignatureStatus {
+	if signature == "" {
+		return TagSignatureUnsigned
+	}
+
+	_, err := git.NewCommand("verify-tag", "--", name).RunInDir(repoPath)
+	if err != nil {
+		return TagSignatureUnverified
+	}
+	return TagSignatureVerified
+}
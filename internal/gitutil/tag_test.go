@@ -108,3 +108,41 @@ func TestModuler_ListTagsAfter(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitTagSignature(t *testing.T) {
+	tests := []struct {
+		name          string
+		message       string
+		wantMessage   string
+		wantSignature string
+	}{
+		{
+			name:        "unsigned",
+			message:     "Release v1.0.0\n",
+			wantMessage: "Release v1.0.0\n",
+		},
+		{
+			name:          "PGP signature",
+			message:       "Release v1.0.0\n-----BEGIN PGP SIGNATURE-----\n\nabc\n-----END PGP SIGNATURE-----\n",
+			wantMessage:   "Release v1.0.0\n",
+			wantSignature: "-----BEGIN PGP SIGNATURE-----\n\nabc\n-----END PGP SIGNATURE-----\n",
+		},
+		{
+			name:          "SSH signature without message",
+			message:       "-----BEGIN SSH SIGNATURE-----\nabc\n-----END SSH SIGNATURE-----\n",
+			wantSignature: "-----BEGIN SSH SIGNATURE-----\nabc\n-----END SSH SIGNATURE-----\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			message, signature := SplitTagSignature(test.message)
+			assert.Equal(t, test.wantMessage, message)
+			assert.Equal(t, test.wantSignature, signature)
+		})
+	}
+}
+
+func TestVerifyTag(t *testing.T) {
+	assert.Equal(t, TagSignatureUnsigned, VerifyTag(t.TempDir(), "v1.0.0", ""))
+	assert.Equal(t, TagSignatureUnverified, VerifyTag(t.TempDir(), "v1.0.0", "-----BEGIN PGP SIGNATURE-----"))
+}
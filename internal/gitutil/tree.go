@@ -0,0 +1,33 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"bytes"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+)
+
+// ListFiles returns paths of all files in the tree of given revision of the
+// repository in given path, including files in subdirectories.
+func ListFiles(repoPath, rev string) ([]string, error) {
+	output, err := git.NewCommand("ls-tree", "-r", "-z", "--name-only", rev).RunInDir(repoPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "list tree")
+	}
+
+	output = bytes.TrimSuffix(output, []byte{0})
+	if len(output) == 0 {
+		return []string{}, nil
+	}
+
+	fields := bytes.Split(output, []byte{0})
+	files := make([]string, len(fields))
+	for i := range fields {
+		files[i] = string(fields[i])
+	}
+	return files, nil
+}
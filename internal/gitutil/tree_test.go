@@ -0,0 +1,50 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListFiles(t *testing.T) {
+	repoPath := t.TempDir()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=alice", "GIT_AUTHOR_EMAIL=alice@example.com",
+			"GIT_COMMITTER_NAME=alice", "GIT_COMMITTER_EMAIL=alice@example.com",
+		)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		return strings.TrimSpace(string(output))
+	}
+
+	run("init")
+	run("commit", "--allow-empty", "-m", "empty")
+	files, err := ListFiles(repoPath, "HEAD")
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(repoPath, "web", "src"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "go.mod"), []byte("module example.com"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "web", "src", "app name.js"), []byte(""), 0o644))
+	run("add", "-A")
+	run("commit", "-m", "files")
+
+	files, err = ListFiles(repoPath, "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go.mod", "web/src/app name.js"}, files)
+
+	_, err = ListFiles(repoPath, "404")
+	assert.Error(t, err)
+}
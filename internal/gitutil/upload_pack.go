@@ -0,0 +1,250 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// UploadPackConfigArgs returns the arguments to be put before the "upload-pack"
+// subcommand of Git to configure partial clone support.
+func UploadPackConfigArgs(allowFilter bool) []string {
+	if !allowFilter {
+		return []string{"-c", "uploadpack.allowFilter=false"}
+	}
+	// Fetching objects that are missing from a partial clone requires asking for
+	// them by object ID.
+	return []string{
+		"-c", "uploadpack.allowFilter=true",
+		"-c", "uploadpack.allowAnySHA1InWant=true",
+	}
+}
+
+// FilterKind returns the kind of the object filter spec of partial clones,
+// e.g. "blob:none", "blob:limit" or "tree".
+func FilterKind(spec string) string {
+	spec = strings.TrimSpace(spec)
+	switch {
+	case spec == "":
+		return ""
+	case spec == "blob:none":
+		return spec
+	case strings.HasPrefix(spec, "blob:limit="):
+		return "blob:limit"
+	}
+
+	if i := strings.IndexAny(spec, ":="); i > 0 {
+		spec = spec[:i]
+	}
+	switch spec {
+	case "tree", "sparse", "object", "combine":
+		return spec
+	}
+	return "other"
+}
+
+// pktLineScanner splits a stream of pkt-lines into payloads. Special packets
+// (i.e. flush, delimiter and response end) are reported as nil payloads.
+// Scanning stops once the handler returns false, or the stream is not in the
+// pkt-line format.
+type pktLineScanner struct {
+	buf     []byte
+	stopped bool
+	// handle is called with each payload, and the raw remaining data when the
+	// stream is not in the pkt-line format.
+	handle func(payload []byte, raw bool) bool
+}
+
+func (s *pktLineScanner) Write(p []byte) (int, error) {
+	if s.stopped {
+		return len(p), nil
+	}
+
+	s.buf = append(s.buf, p...)
+	for len(s.buf) >= 4 {
+		n, err := strconv.ParseUint(string(s.buf[:4]), 16, 16)
+		if err != nil || (n > 2 && n < 4) {
+			s.handle(s.buf, true)
+			s.stop()
+			break
+		}
+
+		if n <= 2 {
+			s.buf = s.buf[4:]
+			if !s.handle(nil, false) {
+				s.stop()
+				break
+			}
+			continue
+		}
+		if len(s.buf) < int(n) {
+			break
+		}
+
+		payload := s.buf[4:n]
+		s.buf = s.buf[n:]
+		if !s.handle(payload, false) {
+			s.stop()
+			break
+		}
+	}
+	return len(p), nil
+}
+
+func (s *pktLineScanner) stop() {
+	s.stopped = true
+	s.buf = nil
+}
+
+// writerFunc is an adapter to allow the use of an ordinary function as an
+// io.Writer.
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
+// UploadPackInspector inspects the request and response streams of an
+// upload-pack session to find out how the client fetches. It supports protocol
+// version 0, 1 and 2.
+type UploadPackInspector struct {
+	mu       sync.Mutex
+	filter   string
+	shallow  bool
+	wants    int
+	haves    int
+	packSent bool
+	sent     int64
+
+	request  *pktLineScanner
+	response *pktLineScanner
+}
+
+// NewUploadPackInspector returns a new inspector of an upload-pack session.
+func NewUploadPackInspector() *UploadPackInspector {
+	i := &UploadPackInspector{}
+	i.request = &pktLineScanner{handle: i.handleRequest}
+	i.response = &pktLineScanner{handle: i.handleResponse}
+	return i
+}
+
+// RequestWriter returns the writer to be written with the request stream.
+func (i *UploadPackInspector) RequestWriter() io.Writer {
+	return i.request
+}
+
+// ResponseWriter returns the writer to be written with the response stream.
+func (i *UploadPackInspector) ResponseWriter() io.Writer {
+	return writerFunc(func(p []byte) (int, error) {
+		i.mu.Lock()
+		i.sent += int64(len(p))
+		i.mu.Unlock()
+		return i.response.Write(p)
+	})
+}
+
+func (i *UploadPackInspector) handleRequest(payload []byte, raw bool) bool {
+	if raw {
+		return false
+	}
+
+	line := string(bytes.TrimSuffix(payload, []byte("\n")))
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	switch {
+	case strings.HasPrefix(line, "filter "):
+		if i.filter == "" {
+			i.filter = FilterKind(strings.TrimPrefix(line, "filter "))
+		}
+	case strings.HasPrefix(line, "deepen"):
+		i.shallow = true
+	case strings.HasPrefix(line, "want ") || strings.HasPrefix(line, "want-ref "):
+		i.wants++
+	case strings.HasPrefix(line, "have "):
+		i.haves++
+	case line == "done":
+		return false
+	}
+	return true
+}
+
+func (i *UploadPackInspector) handleResponse(payload []byte, raw bool) bool {
+	var sent bool
+	switch {
+	case raw:
+		// The packfile is sent without side-band after negotiation.
+		sent = bytes.HasPrefix(payload, []byte("PACK"))
+	case bytes.Equal(payload, []byte("packfile\n")):
+		// The packfile section of protocol version 2.
+		sent = true
+	case bytes.HasPrefix(payload, []byte("\x01PACK")):
+		// The packfile is sent with side-band.
+		sent = true
+	}
+	if !sent {
+		return true
+	}
+
+	i.mu.Lock()
+	i.packSent = true
+	i.mu.Unlock()
+	return false
+}
+
+// Filter returns the kind of the object filter requested by the client, or an
+// empty string if no filter is requested.
+func (i *UploadPackInspector) Filter() string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.filter
+}
+
+// IsShallow returns true if the client requested a shallow fetch.
+func (i *UploadPackInspector) IsShallow() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.shallow
+}
+
+// Wants returns the number of objects and references wanted by the client.
+func (i *UploadPackInspector) Wants() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.wants
+}
+
+// Haves returns the number of objects that the client told it has.
+func (i *UploadPackInspector) Haves() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.haves
+}
+
+// IsClone returns true if the client has no objects of the repository, i.e. it
+// did not tell any object it has.
+func (i *UploadPackInspector) IsClone() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.haves == 0
+}
+
+// BytesSent returns the number of bytes written to the response stream.
+func (i *UploadPackInspector) BytesSent() int64 {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.sent
+}
+
+// IsPackSent returns true if a packfile has been sent to the client, i.e. the
+// session is a fetch rather than only listing references or negotiating.
+func (i *UploadPackInspector) IsPackSent() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.packSent
+}
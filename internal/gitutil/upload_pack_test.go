@@ -0,0 +1,140 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package gitutil
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterKind(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{spec: "", want: ""},
+		{spec: "blob:none", want: "blob:none"},
+		{spec: "blob:limit=1m", want: "blob:limit"},
+		{spec: "tree:0", want: "tree"},
+		{spec: "sparse:oid=main:.sparse", want: "sparse"},
+		{spec: "combine:blob%3Anone+tree%3A0", want: "combine"},
+		{spec: "unknown", want: "other"},
+	}
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			assert.Equal(t, test.want, FilterKind(test.spec))
+		})
+	}
+}
+
+func TestUploadPackInspector(t *testing.T) {
+	dir := t.TempDir()
+	run := func(dir string, args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=alice", "GIT_AUTHOR_EMAIL=alice@example.com",
+			"GIT_COMMITTER_NAME=alice", "GIT_COMMITTER_EMAIL=alice@example.com",
+		)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		return strings.TrimSpace(string(output))
+	}
+
+	repoPath := filepath.Join(dir, "repo")
+	require.NoError(t, os.MkdirAll(repoPath, os.ModePerm))
+	run(repoPath, "init")
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, "README.md"), []byte("# Hello"), 0o644))
+	run(repoPath, "add", "-A")
+	run(repoPath, "commit", "-m", "initial")
+	run(repoPath, "commit", "--allow-empty", "-m", "second")
+	// Lazy fetches of missing objects after a partial clone do not go through the
+	// wrapper, so the repository itself needs to allow them.
+	run(repoPath, "config", "uploadpack.allowFilter", "true")
+	run(repoPath, "config", "uploadpack.allowAnySHA1InWant", "true")
+
+	// The wrapper records the request and response streams of upload-pack.
+	wrapper := filepath.Join(dir, "upload-pack.sh")
+	script := fmt.Sprintf("#!/bin/sh\ntee %q | git %s upload-pack \"$@\" | tee %q\n",
+		filepath.Join(dir, "request"), strings.Join(UploadPackConfigArgs(true), " "), filepath.Join(dir, "response"))
+	require.NoError(t, os.WriteFile(wrapper, []byte(script), 0o755))
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantFilter  string
+		wantShallow bool
+	}{
+		{
+			name: "protocol v0",
+			args: []string{"-c", "protocol.version=0", "clone"},
+		},
+		{
+			name:       "protocol v0 with filter",
+			args:       []string{"-c", "protocol.version=0", "clone", "--filter=blob:none"},
+			wantFilter: "blob:none",
+		},
+		{
+			name:        "protocol v2 shallow",
+			args:        []string{"-c", "protocol.version=2", "clone", "--depth=1"},
+			wantShallow: true,
+		},
+		{
+			name:       "protocol v2 with filter",
+			args:       []string{"-c", "protocol.version=2", "clone", "--filter=blob:limit=1k"},
+			wantFilter: "blob:limit",
+		},
+	}
+	for i, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			args := append(test.args, "--upload-pack="+wrapper, "file://"+repoPath, filepath.Join(dir, fmt.Sprintf("clone%d", i)))
+			run(dir, args...)
+
+			request, err := os.ReadFile(filepath.Join(dir, "request"))
+			require.NoError(t, err)
+			response, err := os.ReadFile(filepath.Join(dir, "response"))
+			require.NoError(t, err)
+
+			inspector := NewUploadPackInspector()
+			// Write in small chunks to exercise buffering of partial pkt-lines.
+			for _, stream := range []struct {
+				data []byte
+				w    func([]byte) (int, error)
+			}{
+				{request, inspector.RequestWriter().Write},
+				{response, inspector.ResponseWriter().Write},
+			} {
+				for len(stream.data) > 0 {
+					n := 7
+					if n > len(stream.data) {
+						n = len(stream.data)
+					}
+					_, _ = stream.w(stream.data[:n])
+					stream.data = stream.data[n:]
+				}
+			}
+
+			assert.Equal(t, test.wantFilter, inspector.Filter())
+			assert.Equal(t, test.wantShallow, inspector.IsShallow())
+			assert.True(t, inspector.IsPackSent())
+			assert.True(t, inspector.IsClone())
+			assert.Positive(t, inspector.Wants())
+			assert.Zero(t, inspector.Haves())
+			assert.EqualValues(t, len(response), inspector.BytesSent())
+		})
+	}
+
+	// Listing references only does not send a packfile.
+	inspector := NewUploadPackInspector()
+	_, _ = inspector.ResponseWriter().Write([]byte("003f0123456789012345678901234567890123456789 refs/heads/main\n0000"))
+	assert.False(t, inspector.IsPackSent())
+}
@@ -0,0 +1,315 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package issueform parses issue forms, which are issue templates defined in
+// YAML with structured fields, validates submitted values and renders them as
+// Markdown.
+package issueform
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Dirs is the list of directories in a repository where issue forms are
+// looked up, in the order of priority.
+var Dirs = []string{
+	".gogs/ISSUE_TEMPLATE",
+	".github/ISSUE_TEMPLATE",
+}
+
+// IsForm returns true if the file name is of an issue form.
+func IsForm(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".yml" || ext == ".yaml"
+}
+
+// ElementType is the type of an element in the body of an issue form.
+type ElementType string
+
+const (
+	ElementMarkdown   ElementType = "markdown"
+	ElementTextarea   ElementType = "textarea"
+	ElementInput      ElementType = "input"
+	ElementDropdown   ElementType = "dropdown"
+	ElementCheckboxes ElementType = "checkboxes"
+)
+
+// Option is an option of a dropdown or checkboxes element. Options of
+// dropdowns are written as plain strings.
+type Option struct {
+	Label string `yaml:"label"`
+	// Required indicates whether the checkbox must be checked.
+	Required bool `yaml:"required"`
+}
+
+func (o *Option) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		o.Label = value.Value
+		return nil
+	}
+
+	type plain Option
+	return value.Decode((*plain)(o))
+}
+
+// Attributes are the attributes of an element, which ones are used depends on
+// the type of the element.
+type Attributes struct {
+	Label       string `yaml:"label"`
+	Description string `yaml:"description"`
+	Placeholder string `yaml:"placeholder"`
+	// Value is the content of a markdown element, or the default value of a
+	// textarea or input element.
+	Value string `yaml:"value"`
+	// Render is the language to render the value of a textarea element as a
+	// code block.
+	Render   string    `yaml:"render"`
+	Multiple bool      `yaml:"multiple"`
+	Options  []*Option `yaml:"options"`
+}
+
+// Validations are the validations of the value of an element.
+type Validations struct {
+	Required bool `yaml:"required"`
+}
+
+// Element is an element in the body of an issue form.
+type Element struct {
+	Type        ElementType `yaml:"type"`
+	ID          string      `yaml:"id"`
+	Attributes  Attributes  `yaml:"attributes"`
+	Validations Validations `yaml:"validations"`
+}
+
+// IsField returns true if the element takes a value, i.e. it is not a
+// markdown element.
+func (e *Element) IsField() bool {
+	return e.Type != ElementMarkdown
+}
+
+// Form is an issue form.
+type Form struct {
+	// Filename is the name of the file that defines the form.
+	Filename    string     `yaml:"-"`
+	Name        string     `yaml:"name"`
+	Description string     `yaml:"description"`
+	Title       string     `yaml:"title"`
+	Labels      []string   `yaml:"labels"`
+	Body        []*Element `yaml:"body"`
+}
+
+var idPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Parse parses and checks the issue form defined in the file with given name
+// and content.
+func Parse(filename string, data []byte) (*Form, error) {
+	f := &Form{
+		Filename: filename,
+	}
+	if err := yaml.Unmarshal(data, f); err != nil {
+		return nil, errors.Wrap(err, "unmarshal")
+	}
+
+	if strings.TrimSpace(f.Name) == "" {
+		return nil, errors.New("name is required")
+	}
+	if len(f.Body) == 0 {
+		return nil, errors.New("body is required")
+	}
+
+	ids := make(map[string]bool, len(f.Body))
+	for i, e := range f.Body {
+		if e == nil {
+			return nil, errors.Errorf("body[%d]: element is empty", i)
+		}
+		switch e.Type {
+		case ElementMarkdown:
+			if e.Attributes.Value == "" {
+				return nil, errors.Errorf("body[%d]: value is required", i)
+			}
+			continue
+		case ElementTextarea, ElementInput:
+		case ElementDropdown, ElementCheckboxes:
+			if len(e.Attributes.Options) == 0 {
+				return nil, errors.Errorf("body[%d]: options are required", i)
+			}
+			for j, opt := range e.Attributes.Options {
+				if opt == nil || strings.TrimSpace(opt.Label) == "" {
+					return nil, errors.Errorf("body[%d]: options[%d]: label is required", i, j)
+				}
+			}
+		default:
+			return nil, errors.Errorf("body[%d]: unsupported type %q", i, e.Type)
+		}
+
+		if strings.TrimSpace(e.Attributes.Label) == "" {
+			return nil, errors.Errorf("body[%d]: label is required", i)
+		}
+		if e.ID == "" {
+			e.ID = "field-" + strconv.Itoa(i)
+		} else if !idPattern.MatchString(e.ID) {
+			return nil, errors.Errorf("body[%d]: invalid id %q", i, e.ID)
+		}
+		if ids[e.ID] {
+			return nil, errors.Errorf("body[%d]: duplicated id %q", i, e.ID)
+		}
+		ids[e.ID] = true
+	}
+	return f, nil
+}
+
+// FieldError is an error of the submitted value of an element.
+type FieldError struct {
+	ID    string
+	Label string
+	// Reason is one of "required" and "invalid".
+	Reason string
+}
+
+func (err *FieldError) Error() string {
+	return fmt.Sprintf("field %q is %s", err.Label, err.Reason)
+}
+
+// selectedOptions returns the options selected by the values, which are
+// indexes of options.
+func selectedOptions(e *Element, values []string) ([]*Option, bool) {
+	selected := make([]*Option, 0, len(values))
+	seen := make(map[int]bool, len(values))
+	for _, v := range values {
+		i, err := strconv.Atoi(v)
+		if err != nil || i < 0 || i >= len(e.Attributes.Options) {
+			return nil, false
+		}
+		if seen[i] {
+			continue
+		}
+		seen[i] = true
+		selected = append(selected, e.Attributes.Options[i])
+	}
+	return selected, true
+}
+
+// Validate checks the values submitted for the form, which are keyed by IDs
+// of elements. Values of dropdown and checkboxes elements are indexes of
+// selected options.
+func (f *Form) Validate(values map[string][]string) []*FieldError {
+	var errs []*FieldError
+	for _, e := range f.Body {
+		if !e.IsField() {
+			continue
+		}
+
+		fail := func(reason string) {
+			errs = append(errs, &FieldError{ID: e.ID, Label: e.Attributes.Label, Reason: reason})
+		}
+		vals := values[e.ID]
+		switch e.Type {
+		case ElementTextarea, ElementInput:
+			if len(vals) > 1 {
+				fail("invalid")
+			} else if e.Validations.Required && (len(vals) == 0 || strings.TrimSpace(vals[0]) == "") {
+				fail("required")
+			}
+
+		case ElementDropdown:
+			selected, ok := selectedOptions(e, vals)
+			if !ok || (!e.Attributes.Multiple && len(selected) > 1) {
+				fail("invalid")
+			} else if e.Validations.Required && len(selected) == 0 {
+				fail("required")
+			}
+
+		case ElementCheckboxes:
+			selected, ok := selectedOptions(e, vals)
+			if !ok {
+				fail("invalid")
+				continue
+			}
+			checked := make(map[*Option]bool, len(selected))
+			for _, opt := range selected {
+				checked[opt] = true
+			}
+			for _, opt := range e.Attributes.Options {
+				if opt.Required && !checked[opt] {
+					fail("required")
+					break
+				}
+			}
+		}
+	}
+	return errs
+}
+
+const noResponse = "_No response_"
+
+// Render renders the values submitted for the form as Markdown, with a
+// section for each element that takes a value. Values should be validated
+// beforehand.
+func (f *Form) Render(values map[string][]string) string {
+	var b strings.Builder
+	for _, e := range f.Body {
+		if !e.IsField() {
+			continue
+		}
+
+		b.WriteString("### ")
+		b.WriteString(strings.TrimSpace(e.Attributes.Label))
+		b.WriteString("\n\n")
+
+		vals := values[e.ID]
+		switch e.Type {
+		case ElementTextarea, ElementInput:
+			var v string
+			if len(vals) > 0 {
+				v = strings.TrimSpace(vals[0])
+			}
+			if v == "" {
+				b.WriteString(noResponse)
+			} else if e.Type == ElementTextarea && e.Attributes.Render != "" {
+				b.WriteString("```" + e.Attributes.Render + "\n" + v + "\n```")
+			} else {
+				b.WriteString(v)
+			}
+
+		case ElementDropdown:
+			selected, _ := selectedOptions(e, vals)
+			if len(selected) == 0 {
+				b.WriteString(noResponse)
+				break
+			}
+			labels := make([]string, len(selected))
+			for i := range selected {
+				labels[i] = selected[i].Label
+			}
+			b.WriteString(strings.Join(labels, ", "))
+
+		case ElementCheckboxes:
+			selected, _ := selectedOptions(e, vals)
+			checked := make(map[*Option]bool, len(selected))
+			for _, opt := range selected {
+				checked[opt] = true
+			}
+			for i, opt := range e.Attributes.Options {
+				if i > 0 {
+					b.WriteString("\n")
+				}
+				if checked[opt] {
+					b.WriteString("- [x] ")
+				} else {
+					b.WriteString("- [ ] ")
+				}
+				b.WriteString(opt.Label)
+			}
+		}
+		b.WriteString("\n\n")
+	}
+	return strings.TrimSpace(b.String())
+}
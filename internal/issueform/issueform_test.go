@@ -0,0 +1,205 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issueform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bugReport = `name: Bug report
+description: Report something that is broken
+title: "[Bug]: "
+labels: [bug]
+body:
+  - type: markdown
+    attributes:
+      value: Thanks for taking the time to fill out this report!
+  - type: input
+    id: version
+    attributes:
+      label: Version
+  - type: textarea
+    id: what-happened
+    attributes:
+      label: What happened?
+      placeholder: Tell us what you see
+    validations:
+      required: true
+  - type: textarea
+    id: logs
+    attributes:
+      label: Logs
+      render: shell
+  - type: dropdown
+    id: databases
+    attributes:
+      label: Databases
+      multiple: true
+      options:
+        - PostgreSQL
+        - MySQL
+        - SQLite
+  - type: checkboxes
+    attributes:
+      label: Code of Conduct
+      options:
+        - label: I agree to follow the Code of Conduct
+          required: true
+        - label: I searched existing issues
+`
+
+func TestIsForm(t *testing.T) {
+	assert.True(t, IsForm("bug.yml"))
+	assert.True(t, IsForm("feature.YAML"))
+	assert.False(t, IsForm("bug.md"))
+	assert.False(t, IsForm("config"))
+}
+
+func TestParse(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		f, err := Parse("bug.yml", []byte(bugReport))
+		require.NoError(t, err)
+
+		assert.Equal(t, "bug.yml", f.Filename)
+		assert.Equal(t, "Bug report", f.Name)
+		assert.Equal(t, "[Bug]: ", f.Title)
+		assert.Equal(t, []string{"bug"}, f.Labels)
+		require.Len(t, f.Body, 6)
+		assert.False(t, f.Body[0].IsField())
+		assert.Equal(t, []*Option{{Label: "PostgreSQL"}, {Label: "MySQL"}, {Label: "SQLite"}}, f.Body[4].Attributes.Options)
+		assert.Equal(t, "field-5", f.Body[5].ID)
+		assert.True(t, f.Body[5].Attributes.Options[0].Required)
+	})
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "no name",
+			content: "body:\n  - type: input\n    attributes:\n      label: Version\n",
+			wantErr: "name is required",
+		},
+		{
+			name:    "no body",
+			content: "name: Bug\n",
+			wantErr: "body is required",
+		},
+		{
+			name:    "unsupported type",
+			content: "name: Bug\nbody:\n  - type: slider\n    attributes:\n      label: Level\n",
+			wantErr: `body[0]: unsupported type "slider"`,
+		},
+		{
+			name:    "no label",
+			content: "name: Bug\nbody:\n  - type: input\n",
+			wantErr: "body[0]: label is required",
+		},
+		{
+			name:    "no options",
+			content: "name: Bug\nbody:\n  - type: dropdown\n    attributes:\n      label: OS\n",
+			wantErr: "body[0]: options are required",
+		},
+		{
+			name:    "invalid id",
+			content: "name: Bug\nbody:\n  - type: input\n    id: my version\n    attributes:\n      label: Version\n",
+			wantErr: `body[0]: invalid id "my version"`,
+		},
+		{
+			name:    "duplicated id",
+			content: "name: Bug\nbody:\n  - type: input\n    id: v\n    attributes:\n      label: A\n  - type: input\n    id: v\n    attributes:\n      label: B\n",
+			wantErr: `body[1]: duplicated id "v"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse("bug.yml", []byte(test.content))
+			require.Error(t, err)
+			assert.Equal(t, test.wantErr, err.Error())
+		})
+	}
+}
+
+func TestForm_Validate(t *testing.T) {
+	f, err := Parse("bug.yml", []byte(bugReport))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		values map[string][]string
+		want   []*FieldError
+	}{
+		{
+			name: "valid",
+			values: map[string][]string{
+				"what-happened": {"It crashed"},
+				"databases":     {"0", "2"},
+				"field-5":       {"0"},
+			},
+		},
+		{
+			name: "missing required values",
+			values: map[string][]string{
+				"what-happened": {"  "},
+				"field-5":       {"1"},
+			},
+			want: []*FieldError{
+				{ID: "what-happened", Label: "What happened?", Reason: "required"},
+				{ID: "field-5", Label: "Code of Conduct", Reason: "required"},
+			},
+		},
+		{
+			name: "invalid options",
+			values: map[string][]string{
+				"what-happened": {"It crashed"},
+				"databases":     {"3"},
+				"field-5":       {"0", "x"},
+			},
+			want: []*FieldError{
+				{ID: "databases", Label: "Databases", Reason: "invalid"},
+				{ID: "field-5", Label: "Code of Conduct", Reason: "invalid"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, f.Validate(test.values))
+		})
+	}
+
+	t.Run("single choice dropdown", func(t *testing.T) {
+		f.Body[4].Attributes.Multiple = false
+		defer func() { f.Body[4].Attributes.Multiple = true }()
+
+		got := f.Validate(map[string][]string{
+			"what-happened": {"It crashed"},
+			"databases":     {"0", "1"},
+			"field-5":       {"0"},
+		})
+		assert.Equal(t, []*FieldError{{ID: "databases", Label: "Databases", Reason: "invalid"}}, got)
+	})
+}
+
+func TestForm_Render(t *testing.T) {
+	f, err := Parse("bug.yml", []byte(bugReport))
+	require.NoError(t, err)
+
+	got := f.Render(map[string][]string{
+		"what-happened": {"It crashed\n"},
+		"logs":          {"panic: oops"},
+		"databases":     {"2", "0"},
+		"field-5":       {"0"},
+	})
+	want := "### Version\n\n_No response_\n\n" +
+		"### What happened?\n\nIt crashed\n\n" +
+		"### Logs\n\n```shell\npanic: oops\n```\n\n" +
+		"### Databases\n\nSQLite, PostgreSQL\n\n" +
+		"### Code of Conduct\n\n- [x] I agree to follow the Code of Conduct\n- [ ] I searched existing issues"
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package jwtauth
+
+import (
+	"crypto/sha256"
+	"time"
+
+	"gogs.io/gogs/internal/conf"
+)
+
+// Enabled returns true if the instance is configured to issue and accept
+// tokens.
+func Enabled() bool {
+	return conf.API.EnableJWT
+}
+
+// key returns the key to sign tokens, which is derived from the secret key of
+// the instance unless configured explicitly.
+func key() []byte {
+	if conf.API.JWTSecret != "" {
+		return []byte(conf.API.JWTSecret)
+	}
+	sum := sha256.Sum256([]byte("jwt:" + conf.Security.SecretKey))
+	return sum[:]
+}
+
+// Issue returns a token issued to the user that expires after
+// conf.API.JWTTTL, along with its expiration time.
+func Issue(userID int64) (token string, expiresAt time.Time, err error) {
+	claims := NewClaims(userID, time.Now(), conf.API.JWTTTL)
+	token, err = Sign(key(), claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, time.Unix(claims.ExpiresAt, 0), nil
+}
+
+// Authenticate returns the ID of the user that the token is issued to if the
+// token is valid.
+func Authenticate(token string) (int64, error) {
+	claims, err := Parse(key(), token, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return claims.UserID(), nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package jwtauth
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndParse(t *testing.T) {
+	key := []byte("key")
+	now := time.Unix(1700000000, 0)
+	token, err := Sign(key, NewClaims(42, now, 15*time.Minute))
+	require.NoError(t, err)
+	assert.True(t, IsToken(token))
+	assert.False(t, IsToken("3a8b5e6f1d2c4b7a9e0f1a2b3c4d5e6f7a8b9c0d"))
+
+	t.Run("valid", func(t *testing.T) {
+		claims, err := Parse(key, token, now.Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), claims.UserID())
+		assert.Equal(t, now.Unix(), claims.IssuedAt)
+		assert.Equal(t, now.Add(15*time.Minute).Unix(), claims.ExpiresAt)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		_, err := Parse(key, token, now.Add(15*time.Minute))
+		assert.EqualError(t, err, "token is expired")
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		_, err := Parse([]byte("other-key"), token, now)
+		assert.EqualError(t, err, "invalid signature")
+	})
+
+	t.Run("tampered claims", func(t *testing.T) {
+		parts := strings.Split(token, ".")
+		parts[1] = base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"gogs","sub":"1","iat":1700000000,"exp":1800000000}`))
+		_, err := Parse(key, strings.Join(parts, "."), now)
+		assert.EqualError(t, err, "invalid signature")
+	})
+
+	t.Run("unsupported header", func(t *testing.T) {
+		parts := strings.Split(token, ".")
+		parts[0] = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+		_, err := Parse(key, strings.Join(parts[:2], ".")+".", now)
+		assert.EqualError(t, err, "unsupported header")
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := Parse(key, "abc", now)
+		assert.EqualError(t, err, "malformed token")
+	})
+}
@@ -99,6 +99,10 @@ func (r *Regexp) ReplaceAll(src, repl []byte) []byte {
 	return r.Regexp().ReplaceAll(src, repl)
 }
 
+func (r *Regexp) ReplaceAllFunc(src []byte, repl func([]byte) []byte) []byte {
+	return r.Regexp().ReplaceAllFunc(src, repl)
+}
+
 // New creates a new lazy regexp, delaying the compiling work until it is first
 // needed. If the code is being run as part of tests, the regexp compiling will
 // happen immediately.
@@ -0,0 +1,208 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package licenseutil
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	log "unknwon.dev/clog/v2"
+
+	embedConf "gogs.io/gogs/conf"
+)
+
+// License is a license identified by its SPDX identifier.
+type License struct {
+	// The SPDX identifier, e.g. "MIT".
+	SPDX string
+	// The human readable name, e.g. "MIT License".
+	Name string
+}
+
+// knownLicenses maps the names of built-in license templates to their SPDX
+// identifiers.
+var knownLicenses = map[string]string{
+	"Abstyles License":                        "Abstyles",
+	"Academic Free License v1.1":              "AFL-1.1",
+	"Academic Free License v1.2":              "AFL-1.2",
+	"Academic Free License v2.0":              "AFL-2.0",
+	"Academic Free License v2.1":              "AFL-2.1",
+	"Academic Free License v3.0":              "AFL-3.0",
+	"Affero General Public License v1.0":      "AGPL-1.0",
+	"Apache License 1.0":                      "Apache-1.0",
+	"Apache License 1.1":                      "Apache-1.1",
+	"Apache License 2.0":                      "Apache-2.0",
+	"Artistic License 1.0":                    "Artistic-1.0",
+	"Artistic License 2.0":                    "Artistic-2.0",
+	"BSD 2-clause License":                    "BSD-2-Clause",
+	"BSD 3-clause License":                    "BSD-3-Clause",
+	"BSD 4-clause License":                    "BSD-4-Clause",
+	"Creative Commons CC0 1.0 Universal":      "CC0-1.0",
+	"Eclipse Public License 1.0":              "EPL-1.0",
+	"Educational Community License v1.0":      "ECL-1.0",
+	"Educational Community License v2.0":      "ECL-2.0",
+	"GNU Affero General Public License v3.0":  "AGPL-3.0",
+	"GNU Free Documentation License v1.1":     "GFDL-1.1",
+	"GNU Free Documentation License v1.2":     "GFDL-1.2",
+	"GNU Free Documentation License v1.3":     "GFDL-1.3",
+	"GNU General Public License v1.0":         "GPL-1.0",
+	"GNU General Public License v2.0":         "GPL-2.0",
+	"GNU General Public License v3.0":         "GPL-3.0",
+	"GNU Lesser General Public License v2.1":  "LGPL-2.1",
+	"GNU Lesser General Public License v3.0":  "LGPL-3.0",
+	"GNU Library General Public License v2.0": "LGPL-2.0",
+	"ISC license":                             "ISC",
+	"MIT License":                             "MIT",
+	"Mozilla Public License 1.0":              "MPL-1.0",
+	"Mozilla Public License 1.1":              "MPL-1.1",
+	"Mozilla Public License 2.0":              "MPL-2.0",
+}
+
+// Name returns the human readable name of the license with given SPDX
+// identifier. It returns the identifier itself when the license is unknown.
+func Name(spdx string) string {
+	for name, id := range knownLicenses {
+		if strings.EqualFold(id, spdx) {
+			return name
+		}
+	}
+	return spdx
+}
+
+type template struct {
+	License
+	bigrams map[string]struct{}
+}
+
+var (
+	templatesOnce sync.Once
+	templates     []*template
+)
+
+func loadTemplates() []*template {
+	templatesOnce.Do(func() {
+		for name, spdx := range knownLicenses {
+			data, err := embedConf.Files.ReadFile(path.Join("license", name))
+			if err != nil {
+				log.Error("Failed to read license template %q: %v", name, err)
+				continue
+			}
+			templates = append(templates, &template{
+				License: License{SPDX: spdx, Name: name},
+				bigrams: bigrams(data),
+			})
+		}
+		sort.Slice(templates, func(i, j int) bool {
+			return templates[i].SPDX < templates[j].SPDX
+		})
+	})
+	return templates
+}
+
+var nonWordRunes = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+
+// bigrams returns the set of consecutive word pairs of the normalized text.
+// Lines mentioning copyright are skipped as they contain years and names that
+// vary between copies of the same license.
+func bigrams(data []byte) map[string]struct{} {
+	var words []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		line := strings.ToLower(scanner.Text())
+		if strings.HasPrefix(strings.TrimLeftFunc(line, func(r rune) bool { return !unicode.IsLetter(r) }), "copyright") {
+			continue
+		}
+		words = append(words, strings.Fields(nonWordRunes.ReplaceAllString(line, " "))...)
+	}
+
+	set := make(map[string]struct{}, len(words))
+	for i := 1; i < len(words); i++ {
+		set[words[i-1]+" "+words[i]] = struct{}{}
+	}
+	return set
+}
+
+// similarity returns the Sørensen–Dice coefficient of two sets.
+func similarity(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	common := 0
+	for k := range a {
+		if _, ok := b[k]; ok {
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(a)+len(b))
+}
+
+// minSimilarity is the minimum similarity for the content of a file to be
+// considered as a copy of a license template.
+const minSimilarity = 0.8
+
+var spdxIdentifierPattern = regexp.MustCompile(`(?m)SPDX-License-Identifier:\s*([\w.+-]+)`)
+
+// Detect returns the license of the given content of a license file. It uses
+// the SPDX identifier tag when present, otherwise it returns the built-in
+// license template that is most similar to the content. It returns nil when no
+// license can be detected.
+func Detect(content []byte) *License {
+	if m := spdxIdentifierPattern.FindSubmatch(content); m != nil {
+		spdx := string(m[1])
+		return &License{SPDX: spdx, Name: Name(spdx)}
+	}
+
+	set := bigrams(content)
+	var (
+		best      *template
+		bestScore float64
+	)
+	for _, t := range loadTemplates() {
+		score := similarity(set, t.bigrams)
+		if score > bestScore {
+			best = t
+			bestScore = score
+		}
+	}
+	if best == nil || bestScore < minSimilarity {
+		return nil
+	}
+
+	license := best.License
+	return &license
+}
+
+// candidateFilenames is the list of filenames (in lower case and without
+// extension) that are considered as license files in priority order.
+var candidateFilenames = []string{"license", "licence", "copying"}
+
+// CandidatePriority returns the priority of the given filename to be the license
+// file of a repository, the smaller the higher priority. It returns -1 when the
+// file is not a license file.
+func CandidatePriority(name string) int {
+	name = strings.ToLower(name)
+	ext := path.Ext(name)
+	switch ext {
+	case "", ".md", ".markdown", ".txt", ".rst":
+	default:
+		return -1
+	}
+
+	name = strings.TrimSuffix(name, ext)
+	for i, candidate := range candidateFilenames {
+		if name == candidate {
+			return i
+		}
+	}
+	return -1
+}
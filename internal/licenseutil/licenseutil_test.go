@@ -0,0 +1,80 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package licenseutil
+
+import (
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	embedConf "gogs.io/gogs/conf"
+)
+
+func TestDetect(t *testing.T) {
+	readTemplate := func(t *testing.T, name string) string {
+		data, err := embedConf.Files.ReadFile(path.Join("license", name))
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("SPDX identifier", func(t *testing.T) {
+		got := Detect([]byte("// SPDX-License-Identifier: Apache-2.0\n"))
+		assert.Equal(t, &License{SPDX: "Apache-2.0", Name: "Apache License 2.0"}, got)
+
+		got = Detect([]byte("SPDX-License-Identifier: BlueOak-1.0.0"))
+		assert.Equal(t, &License{SPDX: "BlueOak-1.0.0", Name: "BlueOak-1.0.0"}, got)
+	})
+
+	t.Run("exact templates", func(t *testing.T) {
+		for name, spdx := range knownLicenses {
+			t.Run(spdx, func(t *testing.T) {
+				got := Detect([]byte(readTemplate(t, name)))
+				require.NotNil(t, got)
+				assert.Equal(t, spdx, got.SPDX)
+			})
+		}
+	})
+
+	t.Run("filled in copyright and reflowed text", func(t *testing.T) {
+		content := readTemplate(t, "MIT License")
+		content = strings.Replace(content, "Copyright (c) <year> <copyright holders>", "Copyright (c) 2026 The Gogs Authors", 1)
+		content = strings.ReplaceAll(content, ", ", ",\n")
+		got := Detect([]byte(content))
+		assert.Equal(t, &License{SPDX: "MIT", Name: "MIT License"}, got)
+
+		content = readTemplate(t, "BSD 3-clause License")
+		content = strings.Replace(content, "<year> <owner>", "2026 Alice", 1)
+		got = Detect([]byte(content))
+		assert.Equal(t, &License{SPDX: "BSD-3-Clause", Name: "BSD 3-clause License"}, got)
+	})
+
+	t.Run("not a license", func(t *testing.T) {
+		assert.Nil(t, Detect([]byte("All rights reserved. Do not copy.")))
+		assert.Nil(t, Detect(nil))
+	})
+}
+
+func TestCandidatePriority(t *testing.T) {
+	tests := []struct {
+		name string
+		want int
+	}{
+		{name: "LICENSE", want: 0},
+		{name: "license.md", want: 0},
+		{name: "LICENCE.txt", want: 1},
+		{name: "COPYING", want: 2},
+		{name: "LICENSE.go", want: -1},
+		{name: "README.md", want: -1},
+		{name: "license-check", want: -1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, CandidatePriority(test.name))
+		})
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package manifest parses dependency manifests of package managers.
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Ecosystem is the package ecosystem of a manifest.
+type Ecosystem string
+
+const (
+	EcosystemGo  Ecosystem = "go"
+	EcosystemNPM Ecosystem = "npm"
+	EcosystemPip Ecosystem = "pip"
+)
+
+// Dependency is a package that a manifest depends on.
+type Dependency struct {
+	Name string
+	// Requirement is the version or the version constraint of the dependency as
+	// written in the manifest, it may be empty.
+	Requirement string
+	// IsDevelopment indicates whether the dependency is only needed for
+	// development.
+	IsDevelopment bool
+}
+
+// Manifest is a parsed dependency manifest.
+type Manifest struct {
+	Ecosystem Ecosystem
+	// PackageName is the name of the package declared by the manifest, it may be
+	// empty when the manifest does not declare one.
+	PackageName  string
+	Dependencies []*Dependency
+}
+
+var filenames = map[string]Ecosystem{
+	"go.mod":           EcosystemGo,
+	"package.json":     EcosystemNPM,
+	"requirements.txt": EcosystemPip,
+}
+
+// IsManifest returns true if the file at the given path is a supported
+// manifest. Files under vendor and node_modules directories are not considered
+// manifests because they belong to dependencies.
+func IsManifest(p string) bool {
+	if _, ok := filenames[path.Base(p)]; !ok {
+		return false
+	}
+	for _, dir := range strings.Split(path.Dir(p), "/") {
+		if dir == "vendor" || dir == "node_modules" {
+			return false
+		}
+	}
+	return true
+}
+
+// Parse parses the content of the manifest at the given path. Dependencies are
+// sorted by name.
+func Parse(p string, content []byte) (*Manifest, error) {
+	var m *Manifest
+	var err error
+	switch filenames[path.Base(p)] {
+	case EcosystemGo:
+		m, err = parseGoMod(content)
+	case EcosystemNPM:
+		m, err = parsePackageJSON(content)
+	case EcosystemPip:
+		m, err = parseRequirementsTxt(content)
+	default:
+		return nil, errors.Errorf("unsupported manifest %q", p)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(m.Dependencies, func(i, j int) bool {
+		return m.Dependencies[i].Name < m.Dependencies[j].Name
+	})
+	return m, nil
+}
+
+func parseGoMod(content []byte) (*Manifest, error) {
+	m := &Manifest{Ecosystem: EcosystemGo}
+	inRequire := false
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if inRequire {
+			if fields[0] == ")" {
+				inRequire = false
+			} else if len(fields) >= 2 {
+				m.Dependencies = append(m.Dependencies, &Dependency{
+					Name:        unquote(fields[0]),
+					Requirement: fields[1],
+				})
+			}
+			continue
+		}
+
+		switch fields[0] {
+		case "module":
+			if len(fields) >= 2 {
+				m.PackageName = unquote(fields[1])
+			}
+		case "require":
+			if len(fields) >= 2 && fields[1] == "(" {
+				inRequire = true
+			} else if len(fields) >= 3 {
+				m.Dependencies = append(m.Dependencies, &Dependency{
+					Name:        unquote(fields[1]),
+					Requirement: fields[2],
+				})
+			}
+		}
+	}
+	return m, errors.Wrap(scanner.Err(), "read go.mod")
+}
+
+func unquote(s string) string {
+	return strings.Trim(s, "\"`")
+}
+
+func parsePackageJSON(content []byte) (*Manifest, error) {
+	var pkg struct {
+		Name            string            `json:"name"`
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil, errors.Wrap(err, "unmarshal package.json")
+	}
+
+	m := &Manifest{
+		Ecosystem:   EcosystemNPM,
+		PackageName: pkg.Name,
+	}
+	for name, requirement := range pkg.Dependencies {
+		m.Dependencies = append(m.Dependencies, &Dependency{
+			Name:        name,
+			Requirement: requirement,
+		})
+	}
+	for name, requirement := range pkg.DevDependencies {
+		if _, ok := pkg.Dependencies[name]; ok {
+			continue
+		}
+		m.Dependencies = append(m.Dependencies, &Dependency{
+			Name:          name,
+			Requirement:   requirement,
+			IsDevelopment: true,
+		})
+	}
+	return m, nil
+}
+
+// requirementPattern matches a requirement specifier of pip, e.g.
+// "requests[security]>=2.8.1,<3 ; python_version < '3.8'".
+var requirementPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*([^;#]*)`)
+
+func parseRequirementsTxt(content []byte) (*Manifest, error) {
+	m := &Manifest{Ecosystem: EcosystemPip}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		// Skip comments, options (e.g. "-r other.txt", "-e .") and direct
+		// references to archives or version control systems.
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") || strings.Contains(line, "://") {
+			continue
+		}
+
+		match := requirementPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		m.Dependencies = append(m.Dependencies, &Dependency{
+			// Package names of pip are case-insensitive and treat runs of "-", "_"
+			// and "." as equal, see PEP 503.
+			Name:        NormalizePipName(match[1]),
+			Requirement: strings.TrimSpace(match[2]),
+		})
+	}
+	return m, errors.Wrap(scanner.Err(), "read requirements.txt")
+}
+
+var pipNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// NormalizePipName returns the normalized form of a package name of pip, which
+// is lower cased and has runs of "-", "_" and "." replaced by a single "-".
+func NormalizePipName(name string) string {
+	return pipNameSeparators.ReplaceAllString(strings.ToLower(name), "-")
+}
+
+var (
+	npmExactVersionPattern = regexp.MustCompile(`^=?v?(\d+\.\d+\.\d+(?:[-+][\w.+-]*)?)$`)
+	pipExactVersionPattern = regexp.MustCompile(`^===?\s*([\w.+!-]+)$`)
+)
+
+// PinnedVersion returns the exact version that the requirement of a dependency
+// pins to. It returns an empty string when the requirement allows a range of
+// versions, e.g. "^1.2.0" or ">=2.0".
+func PinnedVersion(ecosystem Ecosystem, requirement string) string {
+	requirement = strings.TrimSpace(requirement)
+	switch ecosystem {
+	case EcosystemGo:
+		return requirement
+	case EcosystemNPM:
+		if m := npmExactVersionPattern.FindStringSubmatch(requirement); m != nil {
+			return m[1]
+		}
+	case EcosystemPip:
+		if m := pipExactVersionPattern.FindStringSubmatch(requirement); m != nil && !strings.Contains(m[1], "*") {
+			return m[1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsManifest(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "go.mod", want: true},
+		{path: "web/package.json", want: true},
+		{path: "docs/requirements.txt", want: true},
+		{path: "go.sum", want: false},
+		{path: "vendor/github.com/pkg/errors/go.mod", want: false},
+		{path: "web/node_modules/react/package.json", want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			assert.Equal(t, test.want, IsManifest(test.path))
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    *Manifest
+	}{
+		{
+			name: "go.mod",
+			path: "go.mod",
+			content: `module gogs.io/gogs
+
+go 1.21
+
+require github.com/pkg/errors v0.9.1
+
+require (
+	// Comments are ignored.
+	github.com/gogs/git-module v1.7.0
+	golang.org/x/crypto v0.14.0 // indirect
+)
+
+replace github.com/pkg/errors => ../errors
+`,
+			want: &Manifest{
+				Ecosystem:   EcosystemGo,
+				PackageName: "gogs.io/gogs",
+				Dependencies: []*Dependency{
+					{Name: "github.com/gogs/git-module", Requirement: "v1.7.0"},
+					{Name: "github.com/pkg/errors", Requirement: "v0.9.1"},
+					{Name: "golang.org/x/crypto", Requirement: "v0.14.0"},
+				},
+			},
+		},
+		{
+			name: "package.json",
+			path: "web/package.json",
+			content: `{
+  "name": "@gogs/web",
+  "dependencies": {"react": "^18.2.0", "lodash": "4.17.21"},
+  "devDependencies": {"jest": "^29.0.0", "lodash": "4.17.21"}
+}`,
+			want: &Manifest{
+				Ecosystem:   EcosystemNPM,
+				PackageName: "@gogs/web",
+				Dependencies: []*Dependency{
+					{Name: "jest", Requirement: "^29.0.0", IsDevelopment: true},
+					{Name: "lodash", Requirement: "4.17.21"},
+					{Name: "react", Requirement: "^18.2.0"},
+				},
+			},
+		},
+		{
+			name: "requirements.txt",
+			path: "requirements.txt",
+			content: `# Runtime
+-r base.txt
+Django==4.2.1
+requests[security] >= 2.8.1, < 3 ; python_version < "3.8"
+zope.interface
+git+https://github.com/gogs/example.git#egg=example
+`,
+			want: &Manifest{
+				Ecosystem: EcosystemPip,
+				Dependencies: []*Dependency{
+					{Name: "django", Requirement: "==4.2.1"},
+					{Name: "requests", Requirement: ">= 2.8.1, < 3"},
+					{Name: "zope-interface", Requirement: ""},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Parse(test.path, []byte(test.content))
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+
+	_, err := Parse("package.json", []byte("{"))
+	assert.Error(t, err)
+
+	_, err = Parse("Cargo.toml", nil)
+	assert.Error(t, err)
+}
+
+func TestPinnedVersion(t *testing.T) {
+	tests := []struct {
+		ecosystem   Ecosystem
+		requirement string
+		want        string
+	}{
+		{ecosystem: EcosystemGo, requirement: "v1.7.0", want: "v1.7.0"},
+		{ecosystem: EcosystemNPM, requirement: "4.17.15", want: "4.17.15"},
+		{ecosystem: EcosystemNPM, requirement: "=v1.0.0-beta.1", want: "1.0.0-beta.1"},
+		{ecosystem: EcosystemNPM, requirement: "^18.2.0", want: ""},
+		{ecosystem: EcosystemNPM, requirement: "1.x", want: ""},
+		{ecosystem: EcosystemPip, requirement: "==2.8.1", want: "2.8.1"},
+		{ecosystem: EcosystemPip, requirement: "== 1.0.0rc1", want: "1.0.0rc1"},
+		{ecosystem: EcosystemPip, requirement: "==2.*", want: ""},
+		{ecosystem: EcosystemPip, requirement: ">= 2.8.1, < 3", want: ""},
+		{ecosystem: EcosystemPip, requirement: "", want: ""},
+	}
+	for _, test := range tests {
+		t.Run(string(test.ecosystem)+" "+test.requirement, func(t *testing.T) {
+			assert.Equal(t, test.want, PinnedVersion(test.ecosystem, test.requirement))
+		})
+	}
+}
@@ -34,8 +34,9 @@ const (
 )
 
 var (
-	// MentionPattern matches string that mentions someone, e.g. @Unknwon
-	MentionPattern = lazyregexp.New(`(\s|^|\W)@[0-9a-zA-Z-_\.]+`)
+	// MentionPattern matches string that mentions someone or a team of an
+	// organization, e.g. @Unknwon or @gogs/core
+	MentionPattern = lazyregexp.New(`(\s|^|\W)@[0-9a-zA-Z-_\.]+(/[0-9a-zA-Z-_\.]+)?`)
 
 	// CommitPattern matches link to certain commit with or without trailing hash,
 	// e.g. https://try.gogs.io/gogs/gogs/commit/d8a994ef243349f321568f9e36d5c3f444b99cae#diff-2
@@ -57,14 +58,31 @@ var (
 	Sha1CurrentPattern = lazyregexp.New(`\b[0-9a-f]{7,40}\b`)
 )
 
+// findAllMentions matches mention patterns in given content and returns a list
+// of found mentions without @ prefix. Team mentions are returned if teams is
+// true, otherwise user mentions are returned.
+func findAllMentions(content string, teams bool) []string {
+	matches := MentionPattern.FindAllString(content, -1)
+	mentions := make([]string, 0, len(matches))
+	for _, m := range matches {
+		m = m[strings.Index(m, "@")+1:] // Strip @ character
+		if strings.Contains(m, "/") == teams {
+			mentions = append(mentions, m)
+		}
+	}
+	return mentions
+}
+
 // FindAllMentions matches mention patterns in given content
 // and returns a list of found user names without @ prefix.
 func FindAllMentions(content string) []string {
-	mentions := MentionPattern.FindAllString(content, -1)
-	for i := range mentions {
-		mentions[i] = mentions[i][strings.Index(mentions[i], "@")+1:] // Strip @ character
-	}
-	return mentions
+	return findAllMentions(content, false)
+}
+
+// FindAllTeamMentions matches team mention patterns in given content and
+// returns a list of found teams in the form of "<org>/<team>" without @ prefix.
+func FindAllTeamMentions(content string) []string {
+	return findAllMentions(content, true)
 }
 
 // cutoutVerbosePrefix cutouts URL prefix including sub-path to
@@ -152,11 +170,20 @@ func RenderSha1CurrentPattern(rawBytes []byte, urlPrefix string) []byte {
 
 // RenderSpecialLink renders mentions, indexes and SHA1 strings to corresponding links.
 func RenderSpecialLink(rawBytes []byte, urlPrefix string, metas map[string]string) []byte {
-	ms := MentionPattern.FindAll(rawBytes, -1)
-	for _, m := range ms {
-		m = m[bytes.Index(m, []byte("@")):]
-		rawBytes = bytes.ReplaceAll(rawBytes, m, []byte(fmt.Sprintf(`<a href="%s/%s">%s</a>`, conf.Server.Subpath, m[1:], m)))
-	}
+	rawBytes = MentionPattern.ReplaceAllFunc(rawBytes, func(m []byte) []byte {
+		i := bytes.Index(m, []byte("@"))
+		prefix, mention := m[:i], m[i+1:]
+		// "@all" notifies users with write access instead of linking to a user.
+		if bytes.EqualFold(mention, []byte("all")) {
+			return m
+		}
+
+		link := conf.Server.Subpath + "/" + string(mention)
+		if j := bytes.IndexByte(mention, '/'); j >= 0 {
+			link = fmt.Sprintf("%s/org/%s/teams/%s", conf.Server.Subpath, mention[:j], bytes.ToLower(mention[j+1:]))
+		}
+		return []byte(fmt.Sprintf(`%s<a href="%s">@%s</a>`, prefix, link, mention))
+	})
 
 	rawBytes = RenderIssueIndexPattern(rawBytes, urlPrefix, metas)
 	rawBytes = RenderCrossReferenceIssueIndexPattern(rawBytes, urlPrefix, metas)
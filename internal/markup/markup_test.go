@@ -44,6 +44,7 @@ func Test_FindAllMentions(t *testing.T) {
 		{input: "@unknwon what do you think?", expMatches: []string{"unknwon"}},
 		{input: "Hi @unknwon, sounds good to me", expMatches: []string{"unknwon"}},
 		{input: "cc/ @unknwon @eddycjy", expMatches: []string{"unknwon", "eddycjy"}},
+		{input: "cc/ @unknwon @gogs/core", expMatches: []string{"unknwon"}},
 	}
 	for _, test := range tests {
 		t.Run("", func(t *testing.T) {
@@ -52,6 +53,39 @@ func Test_FindAllMentions(t *testing.T) {
 	}
 }
 
+func Test_FindAllTeamMentions(t *testing.T) {
+	tests := []struct {
+		input      string
+		expMatches []string
+	}{
+		{input: "@gogs/core, what do you think?", expMatches: []string{"gogs/core"}},
+		{input: "cc/ @unknwon @gogs/core @gogs/docs", expMatches: []string{"gogs/core", "gogs/docs"}},
+		{input: "@unknwon what do you think?", expMatches: []string{}},
+	}
+	for _, test := range tests {
+		t.Run("", func(t *testing.T) {
+			assert.Equal(t, test.expMatches, FindAllTeamMentions(test.input))
+		})
+	}
+}
+
+func Test_RenderSpecialLink_Mentions(t *testing.T) {
+	tests := []struct {
+		input  string
+		expVal string
+	}{
+		{input: "@unknwon, hi", expVal: `<a href="/unknwon">@unknwon</a>, hi`},
+		{input: "cc/ @gogs/Core @gogs", expVal: `cc/ <a href="/org/gogs/teams/core">@gogs/Core</a> <a href="/gogs">@gogs</a>`},
+		{input: "email me at alice@example.com", expVal: "email me at alice@example.com"},
+		{input: "@all please review", expVal: "@all please review"},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			assert.Equal(t, test.expVal, string(RenderSpecialLink([]byte(test.input), "/prefix", nil)))
+		})
+	}
+}
+
 func Test_RenderIssueIndexPattern(t *testing.T) {
 	urlPrefix := "/prefix"
 	t.Run("render to internal issue tracker", func(t *testing.T) {
@@ -0,0 +1,311 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package osv matches packages against a local copy of the OSV database, see
+// https://ossf.github.io/osv-schema/.
+package osv
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+
+	"gogs.io/gogs/internal/manifest"
+)
+
+// Vulnerability is an entry of the OSV database. Only fields that are needed
+// for matching are decoded.
+type Vulnerability struct {
+	ID        string      `json:"id"`
+	Summary   string      `json:"summary"`
+	Details   string      `json:"details"`
+	Aliases   []string    `json:"aliases"`
+	Withdrawn string      `json:"withdrawn"`
+	Affected  []*Affected `json:"affected"`
+}
+
+// Affected is a package affected by a vulnerability.
+type Affected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges   []*Range `json:"ranges"`
+	Versions []string `json:"versions"`
+}
+
+// Range is a range of affected versions, described by a list of events.
+type Range struct {
+	Type   string   `json:"type"`
+	Events []*Event `json:"events"`
+}
+
+// Event is a version at which a package starts or stops being affected.
+type Event struct {
+	Introduced   string `json:"introduced"`
+	Fixed        string `json:"fixed"`
+	LastAffected string `json:"last_affected"`
+	Limit        string `json:"limit"`
+}
+
+// ecosystems maps ecosystems of manifests to their names in the OSV database.
+var ecosystems = map[manifest.Ecosystem]string{
+	manifest.EcosystemGo:  "Go",
+	manifest.EcosystemNPM: "npm",
+	manifest.EcosystemPip: "PyPI",
+}
+
+type packageKey struct {
+	ecosystem string
+	name      string
+}
+
+func newPackageKey(ecosystem, name string) packageKey {
+	if ecosystem == ecosystems[manifest.EcosystemPip] {
+		name = manifest.NormalizePipName(name)
+	}
+	return packageKey{ecosystem: ecosystem, name: name}
+}
+
+// Database is an in-memory index of vulnerabilities by affected packages.
+type Database struct {
+	vulns map[packageKey][]*Vulnerability
+}
+
+// Load reads all vulnerabilities from JSON files in the given directory and its
+// subdirectories. Zip archives, e.g. "all.zip" exported per ecosystem by OSV,
+// are also read. Withdrawn vulnerabilities are ignored.
+func Load(dir string) (*Database, error) {
+	db := &Database{
+		vulns: make(map[packageKey][]*Vulnerability),
+	}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json":
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			return db.add(f, path)
+		case ".zip":
+			return db.addZip(path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func (db *Database) addZip(path string) error {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return errors.Wrapf(err, "open %q", path)
+	}
+	defer func() { _ = r.Close() }()
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || strings.ToLower(filepath.Ext(f.Name)) != ".json" {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return errors.Wrapf(err, "open %q in %q", f.Name, path)
+		}
+		err = db.add(rc, path+"/"+f.Name)
+		_ = rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *Database) add(r io.Reader, name string) error {
+	var v Vulnerability
+	if err := json.NewDecoder(r).Decode(&v); err != nil {
+		return errors.Wrapf(err, "decode %q", name)
+	} else if v.ID == "" || v.Withdrawn != "" {
+		return nil
+	}
+
+	seen := make(map[packageKey]bool)
+	for _, a := range v.Affected {
+		key := newPackageKey(a.Package.Ecosystem, a.Package.Name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		db.vulns[key] = append(db.vulns[key], &v)
+	}
+	return nil
+}
+
+// Len returns the number of vulnerabilities in the database.
+func (db *Database) Len() int {
+	ids := make(map[string]struct{})
+	for _, vulns := range db.vulns {
+		for _, v := range vulns {
+			ids[v.ID] = struct{}{}
+		}
+	}
+	return len(ids)
+}
+
+// Match is a vulnerability that affects a version of a package.
+type Match struct {
+	Vulnerability *Vulnerability
+	// FixedVersion is the lowest version that fixes the vulnerability, it is
+	// empty when no fix is known.
+	FixedVersion string
+}
+
+// Query returns vulnerabilities that affect the given version of the package,
+// ordered by ID.
+func (db *Database) Query(ecosystem manifest.Ecosystem, name, version string) []*Match {
+	osvEcosystem, ok := ecosystems[ecosystem]
+	if !ok {
+		return nil
+	}
+
+	var matches []*Match
+	for _, v := range db.vulns[newPackageKey(osvEcosystem, name)] {
+		for _, a := range v.Affected {
+			if newPackageKey(a.Package.Ecosystem, a.Package.Name) != newPackageKey(osvEcosystem, name) {
+				continue
+			}
+
+			if affected, fixed := a.affects(version); affected {
+				matches = append(matches, &Match{
+					Vulnerability: v,
+					FixedVersion:  fixed,
+				})
+				break
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Vulnerability.ID < matches[j].Vulnerability.ID
+	})
+	return matches
+}
+
+// affects returns true if the given version is affected, along with the
+// lowest version that fixes it.
+func (a *Affected) affects(version string) (affected bool, fixed string) {
+	for _, v := range a.Versions {
+		if v == version || strings.TrimPrefix(v, "v") == strings.TrimPrefix(version, "v") {
+			affected = true
+			break
+		}
+	}
+
+	ver, err := semver.NewVersion(version)
+	if err != nil {
+		return affected, ""
+	}
+
+	for _, r := range a.Ranges {
+		if r.Type != "SEMVER" && r.Type != "ECOSYSTEM" {
+			continue
+		}
+
+		inRange, rangeFixed := r.contains(ver)
+		if !inRange {
+			continue
+		}
+		affected = true
+		if rangeFixed != "" && (fixed == "" || compare(rangeFixed, fixed) < 0) {
+			fixed = rangeFixed
+		}
+	}
+	return affected, fixed
+}
+
+// contains returns true if the version is within the range, along with the
+// first version after it that fixes the range.
+func (r *Range) contains(ver *semver.Version) (contains bool, fixed string) {
+	type event struct {
+		version *semver.Version
+		raw     string
+		kind    string
+	}
+	var events []event
+	for _, e := range r.Events {
+		kind, raw := "introduced", e.Introduced
+		switch {
+		case e.Fixed != "":
+			kind, raw = "fixed", e.Fixed
+		case e.LastAffected != "":
+			kind, raw = "last_affected", e.LastAffected
+		case e.Limit != "":
+			kind, raw = "limit", e.Limit
+		}
+		if raw == "0" {
+			raw = "0.0.0"
+		}
+
+		v, err := semver.NewVersion(raw)
+		if err != nil {
+			continue
+		}
+		events = append(events, event{version: v, raw: raw, kind: kind})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].version.LessThan(events[j].version)
+	})
+
+	for _, e := range events {
+		cmp := ver.Compare(e.version)
+		switch e.kind {
+		case "introduced":
+			if cmp >= 0 {
+				contains = true
+			}
+		case "fixed":
+			if cmp >= 0 {
+				contains = false
+			} else if contains {
+				return true, e.raw
+			}
+		case "last_affected":
+			if cmp > 0 {
+				contains = false
+			}
+		case "limit":
+			if cmp >= 0 {
+				return false, ""
+			}
+		}
+	}
+	return contains, ""
+}
+
+// compare compares two versions, it returns 0 when either cannot be parsed.
+func compare(a, b string) int {
+	va, err := semver.NewVersion(a)
+	if err != nil {
+		return 0
+	}
+	vb, err := semver.NewVersion(b)
+	if err != nil {
+		return 0
+	}
+	return va.Compare(vb)
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package osv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/manifest"
+)
+
+func TestDatabase(t *testing.T) {
+	db, err := Load("testdata")
+	require.NoError(t, err)
+	assert.Equal(t, 3, db.Len())
+
+	tests := []struct {
+		name      string
+		ecosystem manifest.Ecosystem
+		pkg       string
+		version   string
+		wantID    string
+		wantFixed string
+	}{
+		{name: "before introduced", ecosystem: manifest.EcosystemNPM, pkg: "lodash", version: "3.6.0"},
+		{name: "introduced", ecosystem: manifest.EcosystemNPM, pkg: "lodash", version: "4.17.15", wantID: "GHSA-p6mc-m468-83gw", wantFixed: "4.17.19"},
+		{name: "fixed", ecosystem: manifest.EcosystemNPM, pkg: "lodash", version: "4.17.19"},
+		{name: "wrong ecosystem", ecosystem: manifest.EcosystemPip, pkg: "lodash", version: "4.17.15"},
+
+		{name: "from zero", ecosystem: manifest.EcosystemGo, pkg: "github.com/gin-gonic/gin", version: "v1.5.0", wantID: "GO-2020-0001", wantFixed: "1.6.0"},
+		{name: "between ranges", ecosystem: manifest.EcosystemGo, pkg: "github.com/gin-gonic/gin", version: "v1.6.3"},
+		{name: "last affected", ecosystem: manifest.EcosystemGo, pkg: "github.com/gin-gonic/gin", version: "v1.7.2", wantID: "GO-2020-0001"},
+		{name: "after last affected", ecosystem: manifest.EcosystemGo, pkg: "github.com/gin-gonic/gin", version: "v1.7.3"},
+
+		{name: "normalized name", ecosystem: manifest.EcosystemPip, pkg: "django", version: "3.2.4", wantID: "PYSEC-2021-1", wantFixed: "3.2.5"},
+		{name: "listed version", ecosystem: manifest.EcosystemPip, pkg: "django", version: "3.1", wantID: "PYSEC-2021-1", wantFixed: "3.1.13"},
+		{name: "unparsable version", ecosystem: manifest.EcosystemPip, pkg: "django", version: "3.2.4rc1"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matches := db.Query(test.ecosystem, test.pkg, test.version)
+			if test.wantID == "" {
+				assert.Empty(t, matches)
+				return
+			}
+
+			require.Len(t, matches, 1)
+			assert.Equal(t, test.wantID, matches[0].Vulnerability.ID)
+			assert.Equal(t, test.wantFixed, matches[0].FixedVersion)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pages
+
+import (
+	"context"
+	"crypto/tls"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/db"
+)
+
+// NextProtos is the list of supported application protocols of the TLS server,
+// including the protocol of the TLS-ALPN-01 challenge of ACME.
+var NextProtos = []string{"h2", "http/1.1", acme.ALPNProto}
+
+// GetCertificate returns a function that returns TLS certificates of verified
+// custom domains obtained from the ACME CA, and the fallback certificate for
+// all other hosts.
+func GetCertificate(fallback *tls.Certificate) func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	m := &autocert.Manager{
+		Prompt: autocert.AcceptTOS,
+		Cache:  autocert.DirCache(conf.Pages.ACMECachePath),
+		Client: &acme.Client{DirectoryURL: conf.Pages.ACMEDirectory},
+		Email:  conf.Pages.ACMEEmail,
+		HostPolicy: func(ctx context.Context, host string) error {
+			if !isCustomDomain(ctx, host) {
+				return errors.Errorf("host %q is not a verified custom domain", host)
+			}
+			return nil
+		},
+	}
+
+	mainHost := hostname(conf.Server.URL.Host)
+	return func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		host := hostname(hello.ServerName)
+		if host == "" || host == mainHost {
+			return fallback, nil
+		} else if _, ok := ownerFromHost(host); ok || host == conf.Pages.Domain {
+			return fallback, nil
+		}
+
+		if isCustomDomain(hello.Context(), host) {
+			return m.GetCertificate(hello)
+		}
+		return fallback, nil
+	}
+}
+
+func isCustomDomain(ctx context.Context, host string) bool {
+	_, err := db.PagesSites.GetByDomain(ctx, host)
+	return err == nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pages
+
+import (
+	"archive/zip"
+	"io"
+	"io/fs"
+	"path"
+	"time"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/errutil"
+	"gogs.io/gogs/internal/gitutil"
+)
+
+// content is the content of a site. Names are slash-separated and relative to
+// the root of the site, empty for the root.
+type content interface {
+	// stat returns whether the file or directory exists, and whether it is a
+	// directory.
+	stat(name string) (exists, isDir bool, err error)
+	// read returns the content of the file.
+	read(name string) ([]byte, error)
+	// modTime returns the time when the content was last modified.
+	modTime() time.Time
+}
+
+var _ content = (*gitContent)(nil)
+
+// gitContent is the content of a directory of a Git commit.
+type gitContent struct {
+	commit *git.Commit
+	dir    string
+}
+
+func (c *gitContent) stat(name string) (bool, bool, error) {
+	entry, err := c.commit.TreeEntry(path.Join(c.dir, name))
+	if err != nil {
+		// Looking up a path under a file fails with errors other than not found,
+		// which is also a path that does not exist to visitors.
+		if !errutil.IsNotFound(gitutil.NewError(err)) {
+			log.Trace("Failed to get tree entry %q: %v", path.Join(c.dir, name), err)
+		}
+		return false, false, nil
+	}
+	return true, entry.IsTree(), nil
+}
+
+func (c *gitContent) read(name string) ([]byte, error) {
+	blob, err := c.commit.Blob(path.Join(c.dir, name))
+	if err != nil {
+		return nil, err
+	}
+	return blob.Bytes()
+}
+
+func (c *gitContent) modTime() time.Time {
+	return c.commit.Committer.When
+}
+
+var _ content = (*zipContent)(nil)
+
+// zipContent is the content of a directory of a ZIP archive.
+type zipContent struct {
+	r        *zip.Reader
+	dir      string
+	modified time.Time
+}
+
+// fsName returns the name in the archive, which is "." for the root.
+func (c *zipContent) fsName(name string) string {
+	return path.Clean(path.Join(".", c.dir, name))
+}
+
+func (c *zipContent) stat(name string) (bool, bool, error) {
+	fi, err := fs.Stat(c.r, c.fsName(name))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	return true, fi.IsDir(), nil
+}
+
+func (c *zipContent) read(name string) ([]byte, error) {
+	f, err := c.r.Open(c.fsName(name))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return io.ReadAll(f)
+}
+
+func (c *zipContent) modTime() time.Time {
+	return c.modified
+}
+
+// errNotFound is returned when no file is found for the path.
+var errNotFound = errors.New("not found")
+
+// resolve returns the name of the file to serve for the request path. Requests
+// to directories are served with the "index.html" of the directory. It returns
+// true to redirect when the request path of a directory misses the trailing
+// slash, so that relative links resolve within the directory.
+func resolve(c content, p string) (name string, redirect bool, err error) {
+	name = cleanPath(p)
+	exists, isDir, err := c.stat(name)
+	if err != nil {
+		return "", false, err
+	} else if !exists {
+		return "", false, errNotFound
+	}
+
+	if isDir {
+		if name != "" && p[len(p)-1] != '/' {
+			return "", true, nil
+		}
+
+		name = path.Join(name, "index.html")
+		exists, isDir, err = c.stat(name)
+		if err != nil {
+			return "", false, err
+		} else if !exists || isDir {
+			return "", false, errNotFound
+		}
+	}
+	return name, false, nil
+}
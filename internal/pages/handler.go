@@ -0,0 +1,279 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pages
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gogs/git-module"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/errutil"
+	"gogs.io/gogs/internal/repoutil"
+)
+
+// Handler returns a handler that serves sites for requests to subdomains of the
+// pages domain and to verified custom domains, and passes other requests to
+// next.
+func Handler(next http.Handler) http.Handler {
+	mainHost := hostname(conf.Server.URL.Host)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := hostname(r.Host)
+		if host == mainHost {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if owner, ok := ownerFromHost(host); ok {
+			serveOwnerHost(w, r, owner)
+			return
+		} else if host == conf.Pages.Domain {
+			http.NotFound(w, r)
+			return
+		}
+
+		site, err := db.PagesSites.GetByDomain(r.Context(), host)
+		if err != nil {
+			if !db.IsErrPagesSiteNotExist(err) {
+				serverError(w, err, "get site by domain")
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		repo, err := db.GetRepositoryByID(site.RepoID)
+		if err != nil {
+			serverError(w, err, "get repository")
+			return
+		}
+		owner, err := db.Users.GetByID(r.Context(), repo.OwnerID)
+		if err != nil {
+			serverError(w, err, "get owner")
+			return
+		}
+		serveSite(w, r, owner, repo, site, "/", strings.TrimPrefix(r.URL.Path, "/"))
+	})
+}
+
+func serverError(w http.ResponseWriter, err error, msg string) {
+	log.Error("pages: %s: %v", msg, err)
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+// serveOwnerHost serves sites under the subdomain of the owner, i.e.
+// "<owner>.<pages-domain>/<repo>/<path>".
+func serveOwnerHost(w http.ResponseWriter, r *http.Request, ownerName string) {
+	fields := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	if fields[0] == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	owner, err := db.Users.GetByUsername(r.Context(), ownerName)
+	if err != nil {
+		if errutil.IsNotFound(err) {
+			http.NotFound(w, r)
+		} else {
+			serverError(w, err, "get owner")
+		}
+		return
+	}
+	repo, err := db.Repos.GetByName(r.Context(), owner.ID, fields[0])
+	if err != nil {
+		if errutil.IsNotFound(err) {
+			http.NotFound(w, r)
+		} else {
+			serverError(w, err, "get repository")
+		}
+		return
+	}
+	site, err := db.PagesSites.GetByRepoID(r.Context(), repo.ID)
+	if err != nil {
+		if errutil.IsNotFound(err) {
+			http.NotFound(w, r)
+		} else {
+			serverError(w, err, "get site")
+		}
+		return
+	}
+
+	base := "/" + fields[0] + "/"
+	if len(fields) == 1 {
+		http.Redirect(w, r, base, http.StatusMovedPermanently)
+		return
+	}
+
+	// Sites with a verified custom domain are only served under the domain.
+	if site.CustomDomain != "" && site.IsDomainVerified {
+		target := SiteURL(owner.Name, repo.Name, site) + fields[1]
+		if r.URL.RawQuery != "" {
+			target += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+		return
+	}
+
+	serveSite(w, r, owner, repo, site, base, fields[1])
+}
+
+// serveSite serves the file at the path relative to the base of the site.
+func serveSite(w http.ResponseWriter, r *http.Request, owner *db.User, repo *db.Repository, site *db.PagesSite, base, p string) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if p == authPath {
+		completeAuth(w, r, repo, base)
+		return
+	}
+
+	// Anonymous visitors are isolated from every tenant under tenant isolation.
+	if (repo.IsPrivate || db.IsTenantIsolated(nil, owner)) && !isAuthorized(r, owner, repo) {
+		// Visitors are signed in via the main site, which redirects back with a
+		// short-lived token once the access to the repository is confirmed.
+		target := repoutil.HTMLURL(owner.Name, repo.Name) + "/pages/auth?path=" + url.QueryEscape("/"+p)
+		http.Redirect(w, r, target, http.StatusFound)
+		return
+	}
+
+	var c content
+	switch site.Source {
+	case db.PagesSourceBranch:
+		gitRepo, err := git.Open(repoutil.ShardRepositoryPath(repo.StorageShard, owner.Name, repo.Name))
+		if err != nil {
+			serverError(w, err, "open repository")
+			return
+		}
+		commit, err := gitRepo.BranchCommit(site.Branch)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		c = &gitContent{commit: commit, dir: site.Directory}
+
+	case db.PagesSourceArtifact:
+		if site.ArtifactSHA256 == "" {
+			http.NotFound(w, r)
+			return
+		}
+		zr, err := zip.OpenReader(db.PagesArtifactPath(repo.ID))
+		if err != nil {
+			serverError(w, err, "open artifact")
+			return
+		}
+		defer func() { _ = zr.Close() }()
+		c = &zipContent{r: &zr.Reader, dir: site.Directory, modified: site.UpdatedAt}
+
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if repo.IsPrivate {
+		w.Header().Set("Cache-Control", "private, no-cache")
+	} else {
+		w.Header().Set("Cache-Control", "public, max-age=60")
+	}
+
+	name, redirect, err := resolve(c, p)
+	if redirect {
+		target := base + cleanPath(p) + "/"
+		if r.URL.RawQuery != "" {
+			target += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+		return
+	} else if err == errNotFound {
+		serveNotFound(w, r, c)
+		return
+	} else if err != nil {
+		serverError(w, err, "resolve path")
+		return
+	}
+
+	data, err := c.read(name)
+	if err != nil {
+		serverError(w, err, "read file")
+		return
+	}
+	http.ServeContent(w, r, name, c.modTime(), bytes.NewReader(data))
+}
+
+// serveNotFound responds 404 with the "404.html" at the root of the site if
+// exists.
+func serveNotFound(w http.ResponseWriter, r *http.Request, c content) {
+	if exists, isDir, err := c.stat("404.html"); err == nil && exists && !isDir {
+		if data, err := c.read("404.html"); err == nil {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write(data)
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// isAuthorized returns true if the visitor has a valid session of a user who
+// has read access to the repository.
+func isAuthorized(r *http.Request, owner *db.User, repo *db.Repository) bool {
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return false
+	}
+
+	userID, ok := parseToken("session", cookie.Value, repo.ID, time.Now())
+	if !ok {
+		return false
+	}
+
+	// The access is checked on every request to reflect revoked permissions and
+	// changed tenants.
+	user, err := db.Users.GetByID(r.Context(), userID)
+	if err != nil {
+		if !db.IsErrUserNotExist(err) {
+			log.Error("pages: get user: %v", err)
+		}
+		return false
+	}
+	return db.Perms.Authorize(r.Context(), userID, repo.ID, db.AccessModeRead,
+		db.AccessModeOptions{
+			OwnerID: owner.ID,
+			Private: repo.IsPrivate || db.IsTenantIsolated(user, owner),
+		},
+	)
+}
+
+// completeAuth exchanges the short-lived token from the main site for a
+// session of the site, then redirects to the requested path.
+func completeAuth(w http.ResponseWriter, r *http.Request, repo *db.Repository, base string) {
+	userID, ok := parseToken("auth", r.URL.Query().Get("token"), repo.ID, time.Now())
+	if !ok {
+		http.Error(w, "Invalid or expired token, please try again.", http.StatusForbidden)
+		return
+	}
+
+	expires := time.Now().Add(sessionLifetime)
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    newToken("session", repo.ID, userID, expires),
+		Path:     base,
+		Expires:  expires,
+		Secure:   conf.Pages.Protocol == "https",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, base+cleanPath(r.URL.Query().Get("path")), http.StatusFound)
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package pages serves static websites published from repositories, either
+// from a branch or from a ZIP archive uploaded by CI.
+package pages
+
+import (
+	"archive/zip"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/db"
+)
+
+const (
+	// authPath is the path relative to the base of a site that completes signing
+	// in to the site of a private repository.
+	authPath = "__gogs_pages_auth"
+	// sessionCookieName is the name of the cookie of signed in visitors of sites
+	// of private repositories.
+	sessionCookieName = "gogs_pages_session"
+
+	authTokenLifetime = time.Minute
+	sessionLifetime   = 24 * time.Hour
+)
+
+// hostname returns the lower cased host without the port.
+func hostname(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// ownerFromHost returns the name of the owner of the host if it is a subdomain
+// of the pages domain, e.g. "alice" for "alice.pages.example.com".
+func ownerFromHost(host string) (string, bool) {
+	if conf.Pages.Domain == "" {
+		return "", false
+	}
+
+	owner := strings.TrimSuffix(host, "."+conf.Pages.Domain)
+	if owner == host || owner == "" || strings.Contains(owner, ".") {
+		return "", false
+	}
+	return owner, true
+}
+
+// SiteURL returns the URL of the site of the repository with a trailing slash.
+// Sites with a verified custom domain are served at the root of the domain.
+func SiteURL(ownerName, repoName string, site *db.PagesSite) string {
+	if site.CustomDomain != "" && site.IsDomainVerified {
+		return conf.Pages.Protocol + "://" + site.CustomDomain + "/"
+	}
+	return conf.Pages.Protocol + "://" + strings.ToLower(ownerName) + "." + conf.Pages.Domain + "/" + repoName + "/"
+}
+
+// cleanPath returns the slash-separated path relative to the root of a site,
+// empty for the root. It never goes above the root.
+func cleanPath(p string) string {
+	return strings.TrimPrefix(path.Clean("/"+p), "/")
+}
+
+// sign returns the signature of fields for the purpose.
+func sign(purpose string, fields ...string) string {
+	mac := hmac.New(sha256.New, []byte(conf.Security.SecretKey))
+	_, _ = mac.Write([]byte("pages-" + purpose))
+	for _, f := range fields {
+		_, _ = mac.Write([]byte{0})
+		_, _ = mac.Write([]byte(f))
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newToken returns a token of the user for the site of the repository for the
+// purpose, which is valid until expires.
+func newToken(purpose string, repoID, userID int64, expires time.Time) string {
+	fields := []string{
+		strconv.FormatInt(repoID, 10),
+		strconv.FormatInt(userID, 10),
+		strconv.FormatInt(expires.Unix(), 10),
+	}
+	return strings.Join(fields, ".") + "." + sign(purpose, fields...)
+}
+
+// parseToken returns the ID of the user of the token for the purpose. It
+// returns false if the token is invalid, expired, or for another repository.
+func parseToken(purpose, token string, repoID int64, now time.Time) (int64, bool) {
+	fields := strings.Split(token, ".")
+	if len(fields) != 4 {
+		return 0, false
+	}
+
+	mac := sign(purpose, fields[:3]...)
+	if !hmac.Equal([]byte(mac), []byte(fields[3])) {
+		return 0, false
+	}
+
+	tokenRepoID, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil || tokenRepoID != repoID {
+		return 0, false
+	}
+	userID, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil || userID <= 0 {
+		return 0, false
+	}
+	expires, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil || now.Unix() >= expires {
+		return 0, false
+	}
+	return userID, true
+}
+
+// AuthURL returns the URL on the site to sign the user in to the site of the
+// private repository, then redirect to the path within the site. The URL is
+// only valid for a short time.
+func AuthURL(siteURL string, repoID, userID int64, p string) string {
+	token := newToken("auth", repoID, userID, time.Now().Add(authTokenLifetime))
+	return siteURL + authPath + "?token=" + url.QueryEscape(token) + "&path=" + url.QueryEscape("/"+cleanPath(p))
+}
+
+var (
+	// ErrArtifactTooLarge is returned when the artifact exceeds the maximum size.
+	ErrArtifactTooLarge = errors.New("artifact is too large")
+	// ErrInvalidArtifact is returned when the artifact is not a ZIP archive.
+	ErrInvalidArtifact = errors.New("artifact is not a valid ZIP archive")
+)
+
+// SaveArtifact saves the ZIP archive read from r as the artifact of the site
+// of the repository, and returns its SHA256 checksum. The previous artifact is
+// replaced only when the new one is valid.
+func SaveArtifact(repoID int64, r io.Reader) (string, error) {
+	err := os.MkdirAll(conf.Pages.StoragePath, os.ModePerm)
+	if err != nil {
+		return "", errors.Wrap(err, "create storage directory")
+	}
+
+	f, err := os.CreateTemp(conf.Pages.StoragePath, "artifact-*.zip")
+	if err != nil {
+		return "", errors.Wrap(err, "create temporary file")
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	maxSize := conf.Pages.MaxArtifactSize * 1024 * 1024
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(f, hash), io.LimitReader(r, maxSize+1))
+	if err != nil {
+		_ = f.Close()
+		return "", errors.Wrap(err, "write temporary file")
+	}
+	if err = f.Close(); err != nil {
+		return "", errors.Wrap(err, "close temporary file")
+	}
+	if written > maxSize {
+		return "", ErrArtifactTooLarge
+	}
+
+	zr, err := zip.OpenReader(f.Name())
+	if err != nil {
+		return "", ErrInvalidArtifact
+	}
+	_ = zr.Close()
+
+	if err = os.Rename(f.Name(), db.PagesArtifactPath(repoID)); err != nil {
+		return "", errors.Wrap(err, "rename temporary file")
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
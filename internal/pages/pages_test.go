@@ -0,0 +1,236 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pages
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/db"
+)
+
+func TestOwnerFromHost(t *testing.T) {
+	conf.SetMockPages(t, conf.PagesOpts{Domain: "pages.example.com"})
+
+	tests := []struct {
+		host      string
+		wantOwner string
+		wantOK    bool
+	}{
+		{host: "alice.pages.example.com", wantOwner: "alice", wantOK: true},
+		{host: "pages.example.com"},
+		{host: "a.b.pages.example.com"},
+		{host: "alice.example.com"},
+		{host: "docs.example.org"},
+	}
+	for _, test := range tests {
+		t.Run(test.host, func(t *testing.T) {
+			owner, ok := ownerFromHost(test.host)
+			assert.Equal(t, test.wantOwner, owner)
+			assert.Equal(t, test.wantOK, ok)
+		})
+	}
+}
+
+func TestSiteURL(t *testing.T) {
+	conf.SetMockPages(t, conf.PagesOpts{Domain: "pages.example.com", Protocol: "https"})
+
+	got := SiteURL("Alice", "docs", &db.PagesSite{CustomDomain: "docs.example.org"})
+	assert.Equal(t, "https://alice.pages.example.com/docs/", got)
+
+	got = SiteURL("Alice", "docs", &db.PagesSite{CustomDomain: "docs.example.org", IsDomainVerified: true})
+	assert.Equal(t, "https://docs.example.org/", got)
+}
+
+func TestCleanPath(t *testing.T) {
+	tests := map[string]string{
+		"":                  "",
+		"/":                 "",
+		"index.html":        "index.html",
+		"/a/b/":             "a/b",
+		"../../etc/passwd":  "etc/passwd",
+		"a/../../b/./c.txt": "b/c.txt",
+	}
+	for p, want := range tests {
+		assert.Equal(t, want, cleanPath(p), p)
+	}
+}
+
+func TestParseToken(t *testing.T) {
+	now := time.Now()
+	token := newToken("auth", 1, 2, now.Add(time.Minute))
+
+	userID, ok := parseToken("auth", token, 1, now)
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), userID)
+
+	t.Run("expired", func(t *testing.T) {
+		_, ok := parseToken("auth", token, 1, now.Add(time.Minute))
+		assert.False(t, ok)
+	})
+	t.Run("another repository", func(t *testing.T) {
+		_, ok := parseToken("auth", token, 3, now)
+		assert.False(t, ok)
+	})
+	t.Run("another purpose", func(t *testing.T) {
+		_, ok := parseToken("session", token, 1, now)
+		assert.False(t, ok)
+	})
+	t.Run("tampered", func(t *testing.T) {
+		_, ok := parseToken("auth", strings.Replace(token, "1.2.", "1.3.", 1), 1, now)
+		assert.False(t, ok)
+	})
+}
+
+// usersStore is a db.UsersStore that only looks up users by ID.
+type usersStore struct {
+	db.UsersStore
+	users []*db.User
+}
+
+func (s *usersStore) GetByID(_ context.Context, id int64) (*db.User, error) {
+	for _, u := range s.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, db.ErrUserNotExist{}
+}
+
+// permsStore is a db.PermsStore that grants read access to public repositories
+// only.
+type permsStore struct {
+	db.PermsStore
+}
+
+func (permsStore) Authorize(_ context.Context, _, _ int64, desired db.AccessMode, opts db.AccessModeOptions) bool {
+	return desired <= db.AccessModeRead && !opts.Private
+}
+
+func TestIsAuthorized(t *testing.T) {
+	before := conf.Security.EnableTenantIsolation
+	conf.Security.EnableTenantIsolation = true
+	t.Cleanup(func() {
+		conf.Security.EnableTenantIsolation = before
+	})
+
+	var (
+		owner = &db.User{ID: 1, Name: "acme", Tenant: "acme", Type: db.UserOrganization}
+		alice = &db.User{ID: 2, Name: "alice", Tenant: "acme"}
+		bob   = &db.User{ID: 3, Name: "bob", Tenant: "globex"}
+		admin = &db.User{ID: 4, Name: "admin", Tenant: "globex", IsAdmin: true}
+		repo  = &db.Repository{ID: 10, OwnerID: owner.ID}
+	)
+	db.SetMockUsersStore(t, &usersStore{users: []*db.User{owner, alice, bob, admin}})
+	db.SetMockPermsStore(t, permsStore{})
+
+	tests := []struct {
+		name   string
+		userID int64
+		want   bool
+	}{
+		{name: "user of the same tenant", userID: alice.ID, want: true},
+		{name: "user of another tenant", userID: bob.ID, want: false},
+		{name: "site admin of another tenant", userID: admin.ID, want: true},
+		{name: "deleted user", userID: 99, want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, "/", nil)
+			require.NoError(t, err)
+			r.AddCookie(&http.Cookie{
+				Name:  sessionCookieName,
+				Value: newToken("session", repo.ID, test.userID, time.Now().Add(time.Minute)),
+			})
+			assert.Equal(t, test.want, isAuthorized(r, owner, repo))
+		})
+	}
+}
+
+func newZip(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestResolve(t *testing.T) {
+	data := newZip(t, map[string]string{
+		"public/index.html":      "home",
+		"public/docs/index.html": "docs",
+		"public/docs/a.html":     "a",
+		"public/assets/app.css":  "css",
+	})
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	c := &zipContent{r: r, dir: "public"}
+
+	tests := []struct {
+		path         string
+		wantName     string
+		wantRedirect bool
+		wantErr      error
+	}{
+		{path: "", wantName: "index.html"},
+		{path: "docs/", wantName: "docs/index.html"},
+		{path: "docs", wantRedirect: true},
+		{path: "docs/a.html", wantName: "docs/a.html"},
+		{path: "../index.html", wantName: "index.html"},
+		{path: "assets/", wantErr: errNotFound},
+		{path: "missing.html", wantErr: errNotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			name, redirect, err := resolve(c, test.path)
+			assert.Equal(t, test.wantErr, err)
+			assert.Equal(t, test.wantRedirect, redirect)
+			assert.Equal(t, test.wantName, name)
+		})
+	}
+}
+
+func TestSaveArtifact(t *testing.T) {
+	conf.SetMockPages(t, conf.PagesOpts{StoragePath: t.TempDir(), MaxArtifactSize: 1})
+
+	data := newZip(t, map[string]string{"index.html": "home"})
+	sha256, err := SaveArtifact(1, bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Len(t, sha256, 64)
+
+	got, err := os.ReadFile(db.PagesArtifactPath(1))
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := SaveArtifact(1, strings.NewReader("not a zip"))
+		assert.Equal(t, ErrInvalidArtifact, err)
+
+		// The previous artifact is kept.
+		got, err := os.ReadFile(db.PagesArtifactPath(1))
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		_, err := SaveArtifact(1, bytes.NewReader(make([]byte, 1024*1024+1)))
+		assert.Equal(t, ErrArtifactTooLarge, err)
+	})
+}
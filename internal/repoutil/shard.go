@@ -0,0 +1,91 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repoutil
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gogs.io/gogs/internal/conf"
+)
+
+// Roots returns root paths of all repository shards, where the index is the
+// shard number. Shard 0 is "[repository] ROOT".
+func Roots() []string {
+	roots := make([]string, 0, 1+len(conf.Repository.ShardRoots))
+	roots = append(roots, conf.Repository.Root)
+	return append(roots, conf.Repository.ShardRoots...)
+}
+
+// ShardRoot returns the root path of the repository shard. It falls back to
+// "[repository] ROOT" when the shard is no longer configured.
+func ShardRoot(shard int) string {
+	if shard <= 0 || shard > len(conf.Repository.ShardRoots) {
+		return conf.Repository.Root
+	}
+	return conf.Repository.ShardRoots[shard-1]
+}
+
+// AssignShard returns the shard for new repositories of the owner, which is
+// chosen by the hash of the owner name so that repositories of the same owner
+// are stored together.
+func AssignShard(owner string) int {
+	n := 1 + len(conf.Repository.ShardRoots)
+	if n == 1 {
+		return 0
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(strings.ToLower(owner)))
+	return int(h.Sum32() % uint32(n))
+}
+
+// ShardUserPath returns the absolute path for storing user repositories in
+// the repository shard.
+func ShardUserPath(shard int, user string) string {
+	return filepath.Join(ShardRoot(shard), strings.ToLower(user))
+}
+
+// ShardRepositoryPath returns the absolute path using given user and
+// repository name in the repository shard.
+func ShardRepositoryPath(shard int, owner, repo string) string {
+	return filepath.Join(ShardUserPath(shard, owner), strings.ToLower(repo)+".git")
+}
+
+// CheckRoot checks the root path of a repository shard is a directory and is
+// writable. Stale network mounts tend to hang instead of failing, so it gives
+// up after the timeout.
+func CheckRoot(root string, timeout time.Duration) error {
+	errc := make(chan error, 1)
+	go func() {
+		fi, err := os.Stat(root)
+		if err != nil {
+			errc <- err
+			return
+		} else if !fi.IsDir() {
+			errc <- fmt.Errorf("%q is not a directory", root)
+			return
+		}
+
+		f, err := os.CreateTemp(root, ".healthcheck-")
+		if err != nil {
+			errc <- err
+			return
+		}
+		_ = f.Close()
+		errc <- os.Remove(f.Name())
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("%q did not respond within %s", root, timeout)
+	}
+}
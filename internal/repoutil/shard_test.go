@@ -0,0 +1,88 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repoutil
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gogs.io/gogs/internal/conf"
+)
+
+func TestShardRoot(t *testing.T) {
+	conf.SetMockRepository(t,
+		conf.RepositoryOpts{
+			Root:       "/mnt/a",
+			ShardRoots: []string{"/mnt/b", "/mnt/c"},
+		},
+	)
+
+	assert.Equal(t, []string{"/mnt/a", "/mnt/b", "/mnt/c"}, Roots())
+	assert.Equal(t, "/mnt/a", ShardRoot(0))
+	assert.Equal(t, "/mnt/c", ShardRoot(2))
+	// Removed shards fall back to the primary root.
+	assert.Equal(t, "/mnt/a", ShardRoot(3))
+	assert.Equal(t, "/mnt/a", ShardRoot(-1))
+}
+
+func TestAssignShard(t *testing.T) {
+	conf.SetMockRepository(t,
+		conf.RepositoryOpts{
+			Root: "/mnt/a",
+		},
+	)
+	assert.Equal(t, 0, AssignShard("alice"))
+
+	conf.SetMockRepository(t,
+		conf.RepositoryOpts{
+			Root:       "/mnt/a",
+			ShardRoots: []string{"/mnt/b", "/mnt/c"},
+		},
+	)
+	for _, owner := range []string{"alice", "bob", "carol", "dave", "erin", "frank", "grace", "heidi"} {
+		shard := AssignShard(owner)
+		assert.True(t, shard >= 0 && shard < 3, owner)
+		assert.Equal(t, shard, AssignShard(owner), owner)
+	}
+	assert.Equal(t, AssignShard("alice"), AssignShard("Alice"))
+}
+
+func TestShardRepositoryPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping testing on Windows")
+		return
+	}
+
+	conf.SetMockRepository(t,
+		conf.RepositoryOpts{
+			Root:       "/mnt/a",
+			ShardRoots: []string{"/mnt/b"},
+		},
+	)
+
+	assert.Equal(t, "/mnt/b/alice", ShardUserPath(1, "Alice"))
+	assert.Equal(t, "/mnt/b/alice/example.git", ShardRepositoryPath(1, "Alice", "Example"))
+	assert.Equal(t, "/mnt/a/alice/example.git", ShardRepositoryPath(0, "Alice", "Example"))
+}
+
+func TestCheckRoot(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, CheckRoot(root, time.Second))
+
+	entries, err := os.ReadDir(root)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	assert.Error(t, CheckRoot(filepath.Join(root, "missing"), time.Second))
+
+	file := filepath.Join(root, "file")
+	assert.NoError(t, os.WriteFile(file, nil, 0o644))
+	assert.Error(t, CheckRoot(file, time.Second))
+}
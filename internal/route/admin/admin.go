@@ -6,6 +6,7 @@ package admin
 
 import (
 	"fmt"
+	"net/http"
 	"runtime"
 	"strings"
 	"time"
@@ -137,12 +138,33 @@ const (
 	SyncSSHAuthorizedKey
 	SyncRepositoryHooks
 	ReinitMissingRepository
+	DetectRepoLicenses
+	CheckVulnerabilities
 )
 
+// operationRoles is the admin role required to run each operation.
+var operationRoles = map[AdminOperation]db.AdminRole{
+	CleanInactivateUser:     db.AdminRoleUserAdmin,
+	CleanRepoArchives:       db.AdminRoleRepoAdmin,
+	CleanMissingRepos:       db.AdminRoleRepoAdmin,
+	GitGCRepos:              db.AdminRoleRepoAdmin,
+	SyncSSHAuthorizedKey:    db.AdminRoleUserAdmin,
+	SyncRepositoryHooks:     db.AdminRoleHooksAdmin,
+	ReinitMissingRepository: db.AdminRoleRepoAdmin,
+	DetectRepoLicenses:      db.AdminRoleRepoAdmin,
+	CheckVulnerabilities:    db.AdminRoleRepoAdmin,
+}
+
 func Operation(c *context.Context) {
+	op := AdminOperation(c.QueryInt("op"))
+	if !c.HasAdminRole(operationRoles[op]) {
+		c.Status(http.StatusForbidden)
+		return
+	}
+
 	var err error
 	var success string
-	switch AdminOperation(c.QueryInt("op")) {
+	switch op {
 	case CleanInactivateUser:
 		success = c.Tr("admin.dashboard.delete_inactivate_accounts_success")
 		err = db.DeleteInactivateUsers()
@@ -164,6 +186,12 @@ func Operation(c *context.Context) {
 	case ReinitMissingRepository:
 		success = c.Tr("admin.dashboard.reinit_missing_repos_success")
 		err = db.ReinitMissingRepositories()
+	case DetectRepoLicenses:
+		success = c.Tr("admin.dashboard.detect_repo_licenses_success")
+		err = db.DetectRepositoryLicenses()
+	case CheckVulnerabilities:
+		success = c.Tr("admin.dashboard.check_vulnerabilities_success")
+		go db.CheckVulnerabilities()
 	}
 
 	if err != nil {
@@ -195,10 +223,19 @@ func Config(c *context.Context) {
 	c.Data["Server"] = conf.Server
 	c.Data["SSH"] = conf.SSH
 	c.Data["Repository"] = conf.Repository
+	if len(conf.Repository.ShardRoots) > 0 {
+		shards, err := db.GetRepositoryShards()
+		if err != nil {
+			c.Error(err, "get repository shards")
+			return
+		}
+		c.Data["RepositoryShards"] = shards
+	}
 	c.Data["Database"] = conf.Database
 	c.Data["Security"] = conf.Security
 	c.Data["Email"] = conf.Email
 	c.Data["Auth"] = conf.Auth
+	c.Data["Federation"] = conf.Federation
 	c.Data["User"] = conf.User
 	c.Data["Session"] = conf.Session
 	c.Data["Cache"] = conf.Cache
@@ -0,0 +1,90 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"github.com/unknwon/paginater"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/email"
+)
+
+const (
+	EMAILS = "admin/emails"
+)
+
+func Emails(c *context.Context) {
+	c.Title("admin.emails")
+	c.PageIs("Admin")
+	c.PageIs("AdminEmails")
+
+	opts := db.ListQueuedEmailsOptions{
+		Status:   db.QueuedEmailFailed,
+		PageSize: conf.UI.Admin.NoticePagingNum,
+	}
+	if status := db.QueuedEmailStatus(c.Query("status")); status.IsValid() {
+		opts.Status = status
+	}
+	c.Data["Status"] = string(opts.Status)
+	c.Data["MaxAttempts"] = email.MaxAttempts
+
+	total, err := db.QueuedEmails.Count(c.Req.Context(), opts)
+	if err != nil {
+		c.Error(err, "count queued emails")
+		return
+	}
+	opts.Page = c.QueryInt("page")
+	if opts.Page <= 1 {
+		opts.Page = 1
+	}
+	c.Data["Page"] = paginater.New(int(total), opts.PageSize, opts.Page, 5)
+
+	emails, err := db.QueuedEmails.List(c.Req.Context(), opts)
+	if err != nil {
+		c.Error(err, "list queued emails")
+		return
+	}
+	c.Data["Emails"] = emails
+	c.Data["Total"] = total
+	c.Success(EMAILS)
+}
+
+func RetryEmail(c *context.Context) {
+	e, err := db.QueuedEmails.GetByID(c.Req.Context(), c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get queued email by ID")
+		return
+	}
+
+	if err = db.QueuedEmails.Requeue(c.Req.Context(), e.ID); err != nil {
+		c.Error(err, "requeue email")
+		return
+	}
+	email.WakeQueue()
+
+	log.Trace("Queued email %d requeued by admin (%s)", e.ID, c.User.Name)
+	c.Flash.Success(c.Tr("admin.emails.retry_success", e.Subject))
+	c.RedirectSubpath("/admin/emails?status=" + string(e.Status))
+}
+
+func DiscardEmail(c *context.Context) {
+	e, err := db.QueuedEmails.GetByID(c.Req.Context(), c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get queued email by ID")
+		return
+	}
+
+	if err = db.QueuedEmails.DeleteByID(c.Req.Context(), e.ID); err != nil {
+		c.Error(err, "delete queued email")
+		return
+	}
+
+	log.Trace("Queued email %d discarded by admin (%s)", e.ID, c.User.Name)
+	c.Flash.Success(c.Tr("admin.emails.discard_success", e.Subject))
+	c.RedirectSubpath("/admin/emails?status=" + string(e.Status))
+}
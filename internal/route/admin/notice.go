@@ -5,6 +5,7 @@
 package admin
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/unknwon/com"
@@ -25,14 +26,28 @@ func Notices(c *context.Context) {
 	c.Data["PageIsAdmin"] = true
 	c.Data["PageIsAdminNotices"] = true
 
-	total := db.CountNotices()
-	page := c.QueryInt("page")
-	if page <= 1 {
-		page = 1
+	opts := db.NoticesOptions{
+		Unacked:  c.Query("state") != "all",
+		PageSize: conf.UI.Admin.NoticePagingNum,
 	}
-	c.Data["Page"] = paginater.New(int(total), conf.UI.Admin.NoticePagingNum, page, 5)
+	if level := db.NoticeLevel(c.Query("level")); level.IsValid() {
+		opts.Level = level
+	}
+	c.Data["Level"] = string(opts.Level)
+	c.Data["State"] = "unacked"
+	if !opts.Unacked {
+		c.Data["State"] = "all"
+	}
+	c.Data["Query"] = fmt.Sprintf("level=%s&state=%s", opts.Level, c.Data["State"])
+
+	total := db.CountNotices(opts)
+	opts.Page = c.QueryInt("page")
+	if opts.Page <= 1 {
+		opts.Page = 1
+	}
+	c.Data["Page"] = paginater.New(int(total), opts.PageSize, opts.Page, 5)
 
-	notices, err := db.Notices(page, conf.UI.Admin.NoticePagingNum)
+	notices, err := db.Notices(opts)
 	if err != nil {
 		c.Error(err, "list notices")
 		return
@@ -43,7 +58,7 @@ func Notices(c *context.Context) {
 	c.Success(NOTICES)
 }
 
-func DeleteNotices(c *context.Context) {
+func parseNoticeIDs(c *context.Context) []int64 {
 	strs := c.QueryStrings("ids[]")
 	ids := make([]int64, 0, len(strs))
 	for i := range strs {
@@ -52,8 +67,11 @@ func DeleteNotices(c *context.Context) {
 			ids = append(ids, id)
 		}
 	}
+	return ids
+}
 
-	if err := db.DeleteNoticesByIDs(ids); err != nil {
+func DeleteNotices(c *context.Context) {
+	if err := db.DeleteNoticesByIDs(parseNoticeIDs(c)); err != nil {
 		c.Flash.Error("DeleteNoticesByIDs: " + err.Error())
 		c.Status(http.StatusInternalServerError)
 	} else {
@@ -62,6 +80,16 @@ func DeleteNotices(c *context.Context) {
 	}
 }
 
+func AckNotices(c *context.Context) {
+	if err := db.AckNotices(parseNoticeIDs(c), c.User.ID); err != nil {
+		c.Flash.Error("AckNotices: " + err.Error())
+		c.Status(http.StatusInternalServerError)
+	} else {
+		c.Flash.Success(c.Tr("admin.notices.ack_success"))
+		c.Status(http.StatusOK)
+	}
+}
+
 func EmptyNotices(c *context.Context) {
 	if err := db.DeleteNotices(0, 0); err != nil {
 		c.Error(err, "delete notices")
@@ -0,0 +1,137 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"strings"
+
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/form"
+)
+
+const (
+	PROFILE_FIELDS     = "admin/profile_field/list"
+	PROFILE_FIELD_EDIT = "admin/profile_field/edit"
+)
+
+func prepareProfileFields(c *context.Context) {
+	c.Title("admin.profile_fields")
+	c.PageIs("Admin")
+	c.PageIs("AdminProfileFields")
+
+	fields, err := db.ProfileFields.List(c.Req.Context(), "")
+	if err != nil {
+		c.Error(err, "list profile fields")
+		return
+	}
+	c.Data["Fields"] = fields
+	c.Data["Total"] = len(fields)
+}
+
+func ProfileFields(c *context.Context) {
+	prepareProfileFields(c)
+	if c.Written() {
+		return
+	}
+	c.Success(PROFILE_FIELDS)
+}
+
+func NewProfileFieldPost(c *context.Context, f form.AdminProfileField) {
+	prepareProfileFields(c)
+	if c.Written() {
+		return
+	} else if c.HasError() {
+		c.Success(PROFILE_FIELDS)
+		return
+	}
+
+	_, err := db.ProfileFields.Create(c.Req.Context(), f.Name, db.CreateProfileFieldOptions{
+		Label:         f.Label,
+		Scope:         db.ProfileFieldScope(f.Scope),
+		Editable:      f.Editable,
+		LDAPAttribute: strings.TrimSpace(f.LDAPAttribute),
+		Position:      f.Position,
+	})
+	if err != nil {
+		if db.IsErrProfileFieldAlreadyExist(err) {
+			c.FormErr("Name")
+			c.RenderWithErr(c.Tr("admin.profile_fields.name_been_taken", f.Name), PROFILE_FIELDS, &f)
+		} else {
+			c.Error(err, "create profile field")
+		}
+		return
+	}
+
+	log.Trace("Profile field created by admin (%s): %s", c.User.Name, f.Name)
+	c.Flash.Success(c.Tr("admin.profile_fields.new_success", f.Name))
+	c.RedirectSubpath("/admin/profile_fields")
+}
+
+func EditProfileField(c *context.Context) {
+	c.Title("admin.profile_fields.edit")
+	c.PageIs("Admin")
+	c.PageIs("AdminProfileFields")
+
+	field, err := db.ProfileFields.GetByID(c.Req.Context(), c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get profile field by ID")
+		return
+	}
+	c.Data["Field"] = field
+	c.Success(PROFILE_FIELD_EDIT)
+}
+
+func EditProfileFieldPost(c *context.Context, f form.AdminProfileField) {
+	c.Title("admin.profile_fields.edit")
+	c.PageIs("Admin")
+	c.PageIs("AdminProfileFields")
+
+	field, err := db.ProfileFields.GetByID(c.Req.Context(), c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get profile field by ID")
+		return
+	}
+	c.Data["Field"] = field
+
+	if c.HasError() {
+		c.Success(PROFILE_FIELD_EDIT)
+		return
+	}
+
+	err = db.ProfileFields.Update(c.Req.Context(), field.ID, db.UpdateProfileFieldOptions{
+		Label:         f.Label,
+		Editable:      f.Editable,
+		LDAPAttribute: strings.TrimSpace(f.LDAPAttribute),
+		Position:      f.Position,
+	})
+	if err != nil {
+		c.Error(err, "update profile field")
+		return
+	}
+
+	log.Trace("Profile field updated by admin (%s): %s", c.User.Name, field.Name)
+	c.Flash.Success(c.Tr("admin.profile_fields.update_success", field.Name))
+	c.RedirectSubpath("/admin/profile_fields")
+}
+
+func DeleteProfileField(c *context.Context) {
+	field, err := db.ProfileFields.GetByID(c.Req.Context(), c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get profile field by ID")
+		return
+	}
+
+	if err = db.ProfileFields.DeleteByID(c.Req.Context(), field.ID); err != nil {
+		c.Error(err, "delete profile field")
+		return
+	}
+
+	log.Trace("Profile field deleted by admin (%s): %s", c.User.Name, field.Name)
+	c.Flash.Success(c.Tr("admin.profile_fields.deletion_success", field.Name))
+	c.RedirectSubpath("/admin/profile_fields")
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/url"
+
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/form"
+	"gogs.io/gogs/internal/netutil"
+)
+
+const (
+	REPOS_BULK_EDIT = "admin/repo/bulk_edit"
+)
+
+// toggle returns the setting value of given option, which is one of "enable",
+// "disable" or empty for unchanged.
+func toggle(option, enable string) *bool {
+	if option == "" {
+		return nil
+	}
+	v := option == enable
+	return &v
+}
+
+func toBulkRepoChanges(f form.AdminBulkEditRepos) db.BulkRepoChanges {
+	changes := db.BulkRepoChanges{
+		EnableWiki:   toggle(f.Wiki, "enable"),
+		EnableIssues: toggle(f.Issues, "enable"),
+		EnablePulls:  toggle(f.Pulls, "enable"),
+		Private:      toggle(f.Visibility, "private"),
+	}
+	if f.WebhookURL != "" {
+		contentType := db.JSON
+		if db.ToHookContentType(f.WebhookContentType) == db.FORM {
+			contentType = db.FORM
+		}
+		changes.Webhook = &db.Webhook{
+			URL:          f.WebhookURL,
+			ContentType:  contentType,
+			Secret:       f.WebhookSecret,
+			HookEvent:    &db.HookEvent{PushOnly: true},
+			IsActive:     true,
+			HookTaskType: db.GOGS,
+		}
+	}
+	return changes
+}
+
+func BulkEditRepos(c *context.Context) {
+	c.Title("admin.repos.bulk_edit")
+	c.PageIs("Admin")
+	c.PageIs("AdminRepositories")
+	form.Assign(form.AdminBulkEditRepos{}, c.Data)
+	c.Success(REPOS_BULK_EDIT)
+}
+
+func BulkEditReposPost(c *context.Context, f form.AdminBulkEditRepos) {
+	c.Title("admin.repos.bulk_edit")
+	c.PageIs("Admin")
+	c.PageIs("AdminRepositories")
+
+	if c.HasError() {
+		c.Success(REPOS_BULK_EDIT)
+		return
+	}
+
+	changes := toBulkRepoChanges(f)
+	if changes.IsEmpty() {
+		c.RenderWithErr(c.Tr("admin.repos.bulk_edit.no_changes"), REPOS_BULK_EDIT, &f)
+		return
+	}
+
+	// 🚨 SECURITY: Local addresses must not be allowed to prevent SSRF, the same
+	// as adding webhooks to individual repositories.
+	if changes.Webhook != nil {
+		payloadURL, err := url.Parse(changes.Webhook.URL)
+		if err != nil || netutil.IsBlockedLocalHostname(payloadURL.Hostname(), conf.Security.LocalNetworkAllowlist) {
+			c.FormErr("WebhookURL")
+			c.RenderWithErr(c.Tr("repo.settings.webhook.url_resolved_to_blocked_local_address"), REPOS_BULK_EDIT, &f)
+			return
+		}
+	}
+
+	filter := db.BulkRepoFilter{
+		Owner:       f.Owner,
+		NamePattern: f.NamePattern,
+	}
+	if err := filter.Validate(); err != nil {
+		c.FormErr("NamePattern")
+		c.RenderWithErr(c.Tr("admin.repos.bulk_edit.invalid_name_pattern"), REPOS_BULK_EDIT, &f)
+		return
+	}
+
+	var edits []*db.BulkRepoEdit
+	var err error
+	if f.Apply {
+		edits, err = db.ApplyBulkRepoChanges(c.User, filter, changes)
+	} else {
+		edits, err = db.PreviewBulkRepoChanges(filter, changes)
+	}
+	if err != nil {
+		if db.IsErrUserNotExist(err) {
+			c.FormErr("Owner")
+			c.RenderWithErr(c.Tr("form.user_not_exist"), REPOS_BULK_EDIT, &f)
+		} else {
+			c.Error(err, "bulk edit repositories")
+		}
+		return
+	}
+
+	changed := 0
+	for _, edit := range edits {
+		if len(edit.Settings) > 0 {
+			changed++
+		}
+	}
+
+	if f.Apply {
+		log.Trace("Repositories bulk edited by admin (%s): %d changed", c.User.Name, changed)
+		c.Flash.Success(c.Tr("admin.repos.bulk_edit.apply_success", changed))
+		c.RedirectSubpath("/admin/repos/bulk_edit")
+		return
+	}
+
+	c.Data["Previewed"] = true
+	c.Data["Edits"] = edits
+	c.Data["NumChanged"] = changed
+	c.Success(REPOS_BULK_EDIT)
+}
@@ -14,7 +14,8 @@ import (
 )
 
 const (
-	REPOS = "admin/repo/list"
+	REPOS         = "admin/repo/list"
+	TRASHED_REPOS = "admin/repo/trash"
 )
 
 func Repos(c *context.Context) {
@@ -74,7 +75,7 @@ func DeleteRepo(c *context.Context) {
 		return
 	}
 
-	if err := db.DeleteRepository(repo.MustOwner().ID, repo.ID); err != nil {
+	if err := db.RemoveRepository(c.User, repo.MustOwner().ID, repo.ID); err != nil {
 		c.Error(err, "delete repository")
 		return
 	}
@@ -85,3 +86,65 @@ func DeleteRepo(c *context.Context) {
 		"redirect": conf.Server.Subpath + "/admin/repos?page=" + c.Query("page"),
 	})
 }
+
+func TrashedRepos(c *context.Context) {
+	c.Title("admin.repos.trash")
+	c.PageIs("Admin")
+	c.PageIs("AdminTrashedRepositories")
+
+	page := c.QueryInt("page")
+	if page <= 0 {
+		page = 1
+	}
+
+	total := db.CountTrashedRepositories()
+	repos, err := db.TrashedRepositories(page, conf.UI.Admin.RepoPagingNum)
+	if err != nil {
+		c.Error(err, "list trashed repositories")
+		return
+	}
+	c.Data["Repos"] = repos
+	c.Data["Total"] = total
+	c.Data["Page"] = paginater.New(int(total), conf.UI.Admin.RepoPagingNum, page, 5)
+	c.Data["RetentionDays"] = int(conf.Cron.RepoTrashCleanup.OlderThan.Hours() / 24)
+	c.Success(TRASHED_REPOS)
+}
+
+func RestoreTrashedRepo(c *context.Context) {
+	repo, err := db.GetTrashedRepositoryByID(c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get trashed repository by ID")
+		return
+	}
+
+	if err = db.RestoreRepository(repo); err != nil {
+		if db.IsErrRepoAlreadyExist(err) {
+			c.Flash.Error(c.Tr("admin.repos.trash.restore_name_taken", repo.MustOwner().Name, repo.Name))
+			c.RedirectSubpath("/admin/repos/trash")
+			return
+		}
+		c.Error(err, "restore repository")
+		return
+	}
+	log.Trace("Repository restored from trash by admin (%s): %s/%s", c.User.Name, repo.MustOwner().Name, repo.Name)
+
+	c.Flash.Success(c.Tr("admin.repos.trash.restore_success", repo.MustOwner().Name, repo.Name))
+	c.RedirectSubpath("/admin/repos/trash")
+}
+
+func PurgeTrashedRepo(c *context.Context) {
+	repo, err := db.GetTrashedRepositoryByID(c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get trashed repository by ID")
+		return
+	}
+
+	if err = db.DeleteRepository(repo.OwnerID, repo.ID); err != nil {
+		c.Error(err, "delete repository")
+		return
+	}
+	log.Trace("Trashed repository purged by admin (%s): %s/%s", c.User.Name, repo.MustOwner().Name, repo.Name)
+
+	c.Flash.Success(c.Tr("admin.repos.trash.purge_success", repo.MustOwner().Name, repo.Name))
+	c.RedirectSubpath("/admin/repos/trash")
+}
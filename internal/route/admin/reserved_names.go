@@ -0,0 +1,93 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/form"
+)
+
+const (
+	RESERVED_NAMES = "admin/reserved_names"
+)
+
+func prepareReservedNames(c *context.Context) {
+	c.Title("admin.reserved_names")
+	c.PageIs("Admin")
+	c.PageIs("AdminReservedNames")
+
+	entries, err := db.ReservedNames.List(c.Req.Context())
+	if err != nil {
+		c.Error(err, "list reserved names")
+		return
+	}
+	c.Data["Entries"] = entries
+	c.Data["Total"] = len(entries)
+	c.Data["ReservedUsernames"] = db.ListReservedNames(db.ReservedNameKindUser)
+	c.Data["ReservedRepoNames"] = db.ListReservedNames(db.ReservedNameKindRepo)
+}
+
+func ReservedNames(c *context.Context) {
+	prepareReservedNames(c)
+	if c.Written() {
+		return
+	}
+	c.Success(RESERVED_NAMES)
+}
+
+func NewReservedNamePost(c *context.Context, f form.AdminReservedName) {
+	prepareReservedNames(c)
+	if c.Written() {
+		return
+	} else if c.HasError() {
+		c.Success(RESERVED_NAMES)
+		return
+	}
+
+	if !db.IsValidReservedName(f.Name) {
+		c.FormErr("Name")
+		c.RenderWithErr(c.Tr("admin.reserved_names.invalid_name"), RESERVED_NAMES, &f)
+		return
+	}
+
+	allowed := f.Action == "allow"
+	_, err := db.ReservedNames.Create(c.Req.Context(), db.ReservedNameKind(f.Kind), f.Name, allowed)
+	if err != nil {
+		if db.IsErrReservedNameAlreadyExist(err) {
+			c.FormErr("Name")
+			c.RenderWithErr(c.Tr("admin.reserved_names.name_been_taken", f.Name), RESERVED_NAMES, &f)
+		} else {
+			c.Error(err, "create reserved name")
+		}
+		return
+	}
+	if err = db.LoadReservedNames(c.Req.Context()); err != nil {
+		c.Error(err, "load reserved names")
+		return
+	}
+
+	log.Trace("Reserved name of %s (allowed: %v) created by admin (%s): %s", f.Kind, allowed, c.User.Name, f.Name)
+	c.Flash.Success(c.Tr("admin.reserved_names.new_success", f.Name))
+	c.RedirectSubpath("/admin/reserved_names")
+}
+
+func DeleteReservedName(c *context.Context) {
+	id := c.ParamsInt64(":id")
+	if err := db.ReservedNames.DeleteByID(c.Req.Context(), id); err != nil {
+		c.Error(err, "delete reserved name")
+		return
+	}
+	if err := db.LoadReservedNames(c.Req.Context()); err != nil {
+		c.Error(err, "load reserved names")
+		return
+	}
+
+	log.Trace("Reserved name [%d] deleted by admin (%s)", id, c.User.Name)
+	c.Flash.Success(c.Tr("admin.reserved_names.deletion_success"))
+	c.RedirectSubpath("/admin/reserved_names")
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/url"
+
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/form"
+)
+
+const (
+	TEMPLATES = "admin/templates"
+)
+
+type repoInitFile struct {
+	Name     string
+	IsCustom bool
+}
+
+func prepareTemplates(c *context.Context, tp string) {
+	c.Title("admin.templates")
+	c.Data["PageIsAdmin"] = true
+	c.Data["PageIsAdminTemplates"] = true
+
+	if db.RepoInitFileNames(tp) == nil {
+		tp = db.RepoInitFileGitignore
+	}
+	c.Data["Type"] = tp
+	c.Data["Types"] = db.RepoInitFileTypes
+
+	names := db.RepoInitFileNames(tp)
+	files := make([]*repoInitFile, len(names))
+	for i, name := range names {
+		files[i] = &repoInitFile{
+			Name:     name,
+			IsCustom: db.IsCustomRepoInitFile(tp, name),
+		}
+	}
+	c.Data["Files"] = files
+	c.Data["Total"] = len(files)
+}
+
+func Templates(c *context.Context) {
+	prepareTemplates(c, c.Query("type"))
+	c.Success(TEMPLATES)
+}
+
+func templatesLink(tp string) string {
+	return conf.Server.Subpath + "/admin/templates?type=" + url.QueryEscape(tp)
+}
+
+func SaveTemplatePost(c *context.Context, f form.AdminRepoInitFile) {
+	prepareTemplates(c, f.Type)
+	if c.HasError() {
+		c.Success(TEMPLATES)
+		return
+	}
+
+	if !db.IsValidRepoInitFileName(f.Name) {
+		c.Data["Err_Name"] = true
+		c.RenderWithErr(c.Tr("admin.templates.invalid_name"), TEMPLATES, &f)
+		return
+	}
+
+	if err := db.SaveCustomRepoInitFile(f.Type, f.Name, []byte(f.Content)); err != nil {
+		c.Error(err, "save custom template")
+		return
+	}
+
+	log.Trace("Custom %s template saved by admin (%s): %s", f.Type, c.User.Name, f.Name)
+	c.Flash.Success(c.Tr("admin.templates.save_success", f.Name))
+	c.Redirect(templatesLink(f.Type))
+}
+
+func DeleteTemplate(c *context.Context) {
+	tp := c.Query("type")
+	name := c.Query("name")
+	if err := db.DeleteCustomRepoInitFile(tp, name); err != nil {
+		c.NotFoundOrError(err, "delete custom template")
+		return
+	}
+
+	log.Trace("Custom %s template deleted by admin (%s): %s", tp, c.User.Name, name)
+	c.Flash.Success(c.Tr("admin.templates.delete_success", name))
+	c.Redirect(templatesLink(tp))
+}
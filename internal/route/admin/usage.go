@@ -0,0 +1,79 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"time"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+const (
+	USAGE = "admin/usage"
+)
+
+// usagePeriods are the numbers of days that the usage report can cover.
+var usagePeriods = []int{7, 30, 90}
+
+func usageDays(c *context.Context) int {
+	days := c.QueryInt("days")
+	for _, d := range usagePeriods {
+		if d == days {
+			return days
+		}
+	}
+	return usagePeriods[1]
+}
+
+func Usage(c *context.Context) {
+	c.Title("admin.usage")
+	c.Data["PageIsAdmin"] = true
+	c.Data["PageIsAdminUsage"] = true
+
+	days := usageDays(c)
+	report, err := db.GetUsageReport(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		c.Error(err, "get usage report")
+		return
+	}
+
+	var total db.OwnerUsage
+	for _, u := range report {
+		total.NumRepos += u.NumRepos
+		total.GitSize += u.GitSize
+		total.LFSSize += u.LFSSize
+		total.AttachmentSize += u.AttachmentSize
+		total.BytesSent += u.BytesSent
+		total.BytesReceived += u.BytesReceived
+		total.NumActions += u.NumActions
+	}
+
+	c.Data["UsageDays"] = days
+	c.Data["UsagePeriods"] = usagePeriods
+	c.Data["UsageReport"] = report
+	c.Data["UsageTotal"] = total
+	c.Data["IsSavingGitAccessLogs"] = conf.Git.AccessLog.SaveToDatabase
+	c.Success(USAGE)
+}
+
+func UsageExport(c *context.Context) {
+	days := usageDays(c)
+	report, err := db.GetUsageReport(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		c.Error(err, "get usage report")
+		return
+	}
+
+	filename := fmt.Sprintf("usage-%dd-%s.csv", days, time.Now().Format("20060102"))
+	c.Resp.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	c.Resp.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	if err = db.WriteUsageReportCSV(c.Resp, report); err != nil {
+		c.Error(err, "write usage report")
+		return
+	}
+}
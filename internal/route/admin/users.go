@@ -5,6 +5,7 @@
 package admin
 
 import (
+	"net/http"
 	"strings"
 
 	"github.com/unknwon/com"
@@ -16,6 +17,7 @@ import (
 	"gogs.io/gogs/internal/email"
 	"gogs.io/gogs/internal/form"
 	"gogs.io/gogs/internal/route"
+	"gogs.io/gogs/internal/route/user"
 )
 
 const (
@@ -143,16 +145,63 @@ func prepareUserInfo(c *context.Context) *db.User {
 	}
 	c.Data["Sources"] = sources
 
+	canManage, err := c.CanManageUser(u)
+	if err != nil {
+		c.Error(err, "check if user can be managed")
+		return nil
+	}
+	// Auditors have read-only access.
+	c.Data["CanManageUser"] = canManage && c.HasAdminRole(db.AdminRoleUserAdmin)
+	c.Data["CanEditGitHook"] = c.HasAdminRole(db.AdminRoleHooksAdmin)
+
+	roles, err := db.AdminRoles.ListByUserID(c.Req.Context(), u.ID)
+	if err != nil {
+		c.Error(err, "list admin roles")
+		return nil
+	}
+	userRoles := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		userRoles[string(role)] = true
+	}
+	c.Data["UserAdminRoles"] = userRoles
+	c.Data["AllAdminRoles"] = db.AllAdminRoles
+
+	resetTokens, err := db.PasswordResetTokens.ListByUserID(c.Req.Context(), u.ID, 10)
+	if err != nil {
+		c.Error(err, "list password reset tokens")
+		return nil
+	}
+	c.Data["PasswordResetTokens"] = resetTokens
+
 	return u
 }
 
+// canManageUser returns true if the signed-in user is allowed to manage the
+// user, and responds 403 otherwise.
+func canManageUser(c *context.Context, u *db.User) bool {
+	ok, err := c.CanManageUser(u)
+	if err != nil {
+		c.Error(err, "check if user can be managed")
+		return false
+	} else if !ok {
+		c.Status(http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
 func EditUser(c *context.Context) {
 	c.Data["Title"] = c.Tr("admin.users.edit_account")
 	c.Data["PageIsAdmin"] = true
 	c.Data["PageIsAdminUsers"] = true
 	c.Data["EnableLocalPathMigration"] = conf.Repository.EnableLocalPathMigration
 
-	prepareUserInfo(c)
+	u := prepareUserInfo(c)
+	if c.Written() {
+		return
+	}
+	c.Data["EditAllProfileFields"] = true
+	user.PrepareProfileFields(c, u.ID, db.ProfileFieldScopeUser)
 	if c.Written() {
 		return
 	}
@@ -167,6 +216,11 @@ func EditUserPost(c *context.Context, f form.AdminEditUser) {
 	c.Data["EnableLocalPathMigration"] = conf.Repository.EnableLocalPathMigration
 
 	u := prepareUserInfo(c)
+	if c.Written() || !canManageUser(c, u) {
+		return
+	}
+	c.Data["EditAllProfileFields"] = true
+	profileFields := user.PrepareProfileFields(c, u.ID, db.ProfileFieldScopeUser)
 	if c.Written() {
 		return
 	}
@@ -193,6 +247,8 @@ func EditUserPost(c *context.Context, f form.AdminEditUser) {
 			return
 		}
 		u.EncodePassword()
+		// Sessions signed in with the old password are no longer valid.
+		u.SessionEpoch++
 	}
 
 	u.LoginName = f.LoginName
@@ -201,11 +257,22 @@ func EditUserPost(c *context.Context, f form.AdminEditUser) {
 	u.Website = f.Website
 	u.Location = f.Location
 	u.MaxRepoCreation = f.MaxRepoCreation
+	u.Tenant = f.Tenant
 	u.IsActive = f.Active
-	u.IsAdmin = f.Admin
-	u.AllowGitHook = f.AllowGitHook
-	u.AllowImportLocal = f.AllowImportLocal
+	// Git hooks are executed on the server, thus only hooks admins are allowed
+	// to grant the permission of editing them.
+	if c.HasAdminRole(db.AdminRoleHooksAdmin) {
+		u.AllowGitHook = f.AllowGitHook
+	}
+	// Importing from local paths gives access to files on the server, thus only
+	// site admins are allowed to grant the permission.
+	if c.User.IsAdmin {
+		u.IsAdmin = f.Admin
+		u.AllowImportLocal = f.AllowImportLocal
+	}
+	deactivated := !u.ProhibitLogin && f.ProhibitLogin
 	u.ProhibitLogin = f.ProhibitLogin
+	u.MustChangePassword = f.MustChangePassword
 
 	if err := db.UpdateUser(u); err != nil {
 		if db.IsErrEmailAlreadyUsed(err) {
@@ -216,6 +283,35 @@ func EditUserPost(c *context.Context, f form.AdminEditUser) {
 		}
 		return
 	}
+
+	if deactivated {
+		if err := db.DeactivateUser(u); err != nil {
+			c.Error(err, "deactivate user")
+			return
+		}
+	}
+
+	if c.User.IsAdmin {
+		roles := make([]db.AdminRole, 0, len(f.AdminRoles))
+		for _, role := range f.AdminRoles {
+			roles = append(roles, db.AdminRole(role))
+		}
+		if err := db.AdminRoles.Set(c.Req.Context(), u.ID, roles); err != nil {
+			if db.IsErrInvalidAdminRole(err) {
+				c.Flash.Error(err.Error())
+				c.Redirect(conf.Server.Subpath + "/admin/users/" + c.Params(":userid"))
+			} else {
+				c.Error(err, "set admin roles")
+			}
+			return
+		}
+	}
+
+	err := db.ProfileFields.SetValues(c.Req.Context(), u.ID, user.ParseProfileFieldValues(c, profileFields, true))
+	if err != nil {
+		c.Error(err, "set profile field values")
+		return
+	}
 	log.Trace("Account profile updated by admin (%s): %s", c.User.Name, u.Name)
 
 	c.Flash.Success(c.Tr("admin.users.update_profile_success"))
@@ -229,6 +325,10 @@ func DeleteUser(c *context.Context) {
 		return
 	}
 
+	if !canManageUser(c, u) {
+		return
+	}
+
 	if err = db.DeleteUser(u); err != nil {
 		switch {
 		case db.IsErrUserOwnRepos(err):
@@ -0,0 +1,105 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/email"
+	"gogs.io/gogs/internal/route/api/v1/convert"
+)
+
+type queuedEmail struct {
+	ID          int64     `json:"id"`
+	Status      string    `json:"status"`
+	Sender      string    `json:"sender"`
+	Recipients  []string  `json:"recipients"`
+	Subject     string    `json:"subject"`
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"last_error"`
+	NextAttempt time.Time `json:"next_attempt_at"`
+	Created     time.Time `json:"created_at"`
+	Updated     time.Time `json:"updated_at"`
+}
+
+func toQueuedEmail(e *db.QueuedEmail) *queuedEmail {
+	return &queuedEmail{
+		ID:          e.ID,
+		Status:      string(e.Status),
+		Sender:      e.Sender,
+		Recipients:  e.RecipientList(),
+		Subject:     e.Subject,
+		Attempts:    e.Attempts,
+		LastError:   e.LastError,
+		NextAttempt: e.NextAttemptAt,
+		Created:     e.CreatedAt,
+		Updated:     e.UpdatedAt,
+	}
+}
+
+// ListQueuedEmails returns outgoing emails in the queue, optionally filtered by
+// status.
+func ListQueuedEmails(c *context.APIContext) {
+	opts := db.ListQueuedEmailsOptions{
+		Page:     c.QueryInt("page"),
+		PageSize: convert.ToCorrectPageSize(c.QueryInt("limit")),
+	}
+	if status := c.Query("status"); status != "" {
+		opts.Status = db.QueuedEmailStatus(status)
+		if !opts.Status.IsValid() {
+			c.ErrorStatus(http.StatusUnprocessableEntity, errors.Errorf("invalid status %q", status))
+			return
+		}
+	}
+	if opts.Page <= 1 {
+		opts.Page = 1
+	}
+
+	emails, err := db.QueuedEmails.List(c.Req.Context(), opts)
+	if err != nil {
+		c.Error(err, "list queued emails")
+		return
+	}
+
+	apiEmails := make([]*queuedEmail, len(emails))
+	for i := range emails {
+		apiEmails[i] = toQueuedEmail(emails[i])
+	}
+	c.JSONSuccess(apiEmails)
+}
+
+func RetryQueuedEmail(c *context.APIContext) {
+	e, err := db.QueuedEmails.GetByID(c.Req.Context(), c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get queued email by ID")
+		return
+	}
+
+	if err = db.QueuedEmails.Requeue(c.Req.Context(), e.ID); err != nil {
+		c.Error(err, "requeue email")
+		return
+	}
+	email.WakeQueue()
+	c.NoContent()
+}
+
+func DiscardQueuedEmail(c *context.APIContext) {
+	e, err := db.QueuedEmails.GetByID(c.Req.Context(), c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get queued email by ID")
+		return
+	}
+
+	if err = db.QueuedEmails.DeleteByID(c.Req.Context(), e.ID); err != nil {
+		c.Error(err, "delete queued email")
+		return
+	}
+	c.NoContent()
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/route/api/v1/convert"
+)
+
+type notice struct {
+	ID          int64      `json:"id"`
+	Level       string     `json:"level"`
+	Source      string     `json:"source"`
+	Description string     `json:"description"`
+	Count       int64      `json:"count"`
+	Created     time.Time  `json:"created_at"`
+	Updated     time.Time  `json:"updated_at"`
+	AckedBy     string     `json:"acked_by,omitempty"`
+	Acked       *time.Time `json:"acked_at,omitempty"`
+}
+
+func toNotice(n *db.Notice) *notice {
+	apiNotice := &notice{
+		ID:          n.ID,
+		Level:       string(n.Level),
+		Source:      n.Type.Source(),
+		Description: n.Description,
+		Count:       n.Count,
+		Created:     n.Created,
+		Updated:     n.Updated,
+	}
+	if n.IsAcked() {
+		apiNotice.Acked = &n.Acked
+		if n.AckedBy != nil {
+			apiNotice.AckedBy = n.AckedBy.Name
+		}
+	}
+	return apiNotice
+}
+
+// ListNotices returns system notices, optionally filtered by level and
+// acknowledgement state. Only unacknowledged notices are listed unless the
+// state is "all".
+func ListNotices(c *context.APIContext) {
+	opts := db.NoticesOptions{
+		Unacked:  c.Query("state") != "all",
+		Page:     c.QueryInt("page"),
+		PageSize: convert.ToCorrectPageSize(c.QueryInt("limit")),
+	}
+	if level := c.Query("level"); level != "" {
+		opts.Level = db.NoticeLevel(level)
+		if !opts.Level.IsValid() {
+			c.ErrorStatus(http.StatusUnprocessableEntity, errors.Errorf("invalid level %q", level))
+			return
+		}
+	}
+	if opts.Page <= 1 {
+		opts.Page = 1
+	}
+
+	notices, err := db.Notices(opts)
+	if err != nil {
+		c.Error(err, "list notices")
+		return
+	}
+
+	apiNotices := make([]*notice, len(notices))
+	for i := range notices {
+		apiNotices[i] = toNotice(notices[i])
+	}
+	c.JSONSuccess(apiNotices)
+}
+
+func AckNotice(c *context.APIContext) {
+	n, err := db.GetNoticeByID(c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get notice by ID")
+		return
+	}
+
+	if err = db.AckNotices([]int64{n.ID}, c.User.ID); err != nil {
+		c.Error(err, "acknowledge notice")
+		return
+	}
+	c.NoContent()
+}
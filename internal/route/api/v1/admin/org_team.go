@@ -40,7 +40,11 @@ func AddTeamMember(c *context.APIContext) {
 		return
 	}
 	if err := c.Org.Team.AddMember(u.ID); err != nil {
-		c.Error(err, "add member")
+		if db.IsErrOrgMemberDomainNotVerified(err) || db.IsErrOrgTwoFactorNotEnabled(err) {
+			c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		} else {
+			c.Error(err, "add member")
+		}
 		return
 	}
 
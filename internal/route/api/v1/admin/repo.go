@@ -5,14 +5,12 @@
 package admin
 
 import (
-	api "github.com/gogs/go-gogs-client"
-
 	"gogs.io/gogs/internal/context"
 	"gogs.io/gogs/internal/route/api/v1/repo"
 	"gogs.io/gogs/internal/route/api/v1/user"
 )
 
-func CreateRepo(c *context.APIContext, form api.CreateRepoOption) {
+func CreateRepo(c *context.APIContext, form repo.CreateRepoOption) {
 	owner := user.GetUserByParams(c)
 	if c.Written() {
 		return
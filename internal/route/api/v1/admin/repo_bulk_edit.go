@@ -0,0 +1,120 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/netutil"
+)
+
+type BulkEditWebhookOption struct {
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Secret      string `json:"secret"`
+}
+
+type BulkEditReposOption struct {
+	Owner        string                 `json:"owner"`
+	NamePattern  string                 `json:"name_pattern"`
+	EnableWiki   *bool                  `json:"enable_wiki"`
+	EnableIssues *bool                  `json:"enable_issues"`
+	EnablePulls  *bool                  `json:"enable_pulls"`
+	Private      *bool                  `json:"private"`
+	AddWebhook   *BulkEditWebhookOption `json:"add_webhook"`
+	// DryRun only returns repositories that would be changed.
+	DryRun bool `json:"dry_run"`
+}
+
+type bulkRepoEdit struct {
+	ID       int64    `json:"id"`
+	FullName string   `json:"full_name"`
+	Changes  []string `json:"changes"`
+}
+
+// BulkEditRepos applies a change of settings to repositories selected by
+// owner and name pattern, or previews the change with dry run.
+func BulkEditRepos(c *context.APIContext, form BulkEditReposOption) {
+	changes := db.BulkRepoChanges{
+		EnableWiki:   form.EnableWiki,
+		EnableIssues: form.EnableIssues,
+		EnablePulls:  form.EnablePulls,
+		Private:      form.Private,
+	}
+	if form.AddWebhook != nil {
+		// 🚨 SECURITY: Local addresses must not be allowed to prevent SSRF, the
+		// same as adding webhooks to individual repositories.
+		payloadURL, err := url.Parse(form.AddWebhook.URL)
+		if err != nil || payloadURL.Hostname() == "" || netutil.IsBlockedLocalHostname(payloadURL.Hostname(), conf.Security.LocalNetworkAllowlist) {
+			c.ErrorStatus(http.StatusUnprocessableEntity, errors.New("webhook payload URL is not allowed"))
+			return
+		}
+
+		contentType := db.JSON
+		if db.ToHookContentType(form.AddWebhook.ContentType) == db.FORM {
+			contentType = db.FORM
+		}
+		changes.Webhook = &db.Webhook{
+			URL:          form.AddWebhook.URL,
+			ContentType:  contentType,
+			Secret:       form.AddWebhook.Secret,
+			HookEvent:    &db.HookEvent{PushOnly: true},
+			IsActive:     true,
+			HookTaskType: db.GOGS,
+		}
+	}
+	if changes.IsEmpty() {
+		c.ErrorStatus(http.StatusUnprocessableEntity, errors.New("no changes to apply"))
+		return
+	}
+
+	filter := db.BulkRepoFilter{
+		Owner:       form.Owner,
+		NamePattern: form.NamePattern,
+	}
+	if err := filter.Validate(); err != nil {
+		c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var edits []*db.BulkRepoEdit
+	var err error
+	if form.DryRun {
+		edits, err = db.PreviewBulkRepoChanges(filter, changes)
+	} else {
+		edits, err = db.ApplyBulkRepoChanges(c.User, filter, changes)
+	}
+	if err != nil {
+		if db.IsErrUserNotExist(err) {
+			c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		} else {
+			c.Error(err, "bulk edit repositories")
+		}
+		return
+	}
+
+	apiEdits := make([]*bulkRepoEdit, 0, len(edits))
+	for _, edit := range edits {
+		if len(edit.Settings) == 0 {
+			continue
+		}
+		apiEdits = append(apiEdits, &bulkRepoEdit{
+			ID:       edit.Repo.ID,
+			FullName: edit.Repo.FullName(),
+			Changes:  edit.Settings,
+		})
+	}
+	if !form.DryRun {
+		log.Trace("Repositories bulk edited by admin (%s): %d changed", c.User.Name, len(apiEdits))
+	}
+	c.JSONSuccess(apiEdits)
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/route/api/v1/convert"
+)
+
+type trashedRepository struct {
+	ID        int64     `json:"id"`
+	Owner     string    `json:"owner"`
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	TrashedBy string    `json:"trashed_by"`
+	Trashed   time.Time `json:"trashed_at"`
+}
+
+// ListTrashedRepositories returns repositories in the trash, the most recently
+// trashed first.
+func ListTrashedRepositories(c *context.APIContext) {
+	page := c.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+
+	repos, err := db.TrashedRepositories(page, convert.ToCorrectPageSize(c.QueryInt("limit")))
+	if err != nil {
+		c.Error(err, "list trashed repositories")
+		return
+	}
+
+	apiRepos := make([]*trashedRepository, len(repos))
+	for i, repo := range repos {
+		apiRepos[i] = &trashedRepository{
+			ID:      repo.ID,
+			Owner:   repo.Owner.Name,
+			Name:    repo.Name,
+			Size:    repo.Size,
+			Trashed: repo.Trashed,
+		}
+		if repo.TrashedBy != nil {
+			apiRepos[i].TrashedBy = repo.TrashedBy.Name
+		}
+	}
+	c.JSONSuccess(apiRepos)
+}
+
+func RestoreTrashedRepository(c *context.APIContext) {
+	repo, err := db.GetTrashedRepositoryByID(c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get trashed repository by ID")
+		return
+	}
+
+	if err = db.RestoreRepository(repo); err != nil {
+		if db.IsErrRepoAlreadyExist(err) {
+			c.ErrorStatus(http.StatusConflict, err)
+		} else {
+			c.Error(err, "restore repository")
+		}
+		return
+	}
+	c.NoContent()
+}
+
+func PurgeTrashedRepository(c *context.APIContext) {
+	repo, err := db.GetTrashedRepositoryByID(c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get trashed repository by ID")
+		return
+	}
+
+	if err = db.DeleteRepository(repo.OwnerID, repo.ID); err != nil {
+		c.Error(err, "delete repository")
+		return
+	}
+	c.NoContent()
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	api "github.com/gogs/go-gogs-client"
+	"github.com/pkg/errors"
 	log "unknwon.dev/clog/v2"
 
 	"gogs.io/gogs/internal/conf"
@@ -70,9 +71,40 @@ func CreateUser(c *context.APIContext, form api.CreateUserOption) {
 	c.JSON(http.StatusCreated, u.APIFormat())
 }
 
+// canManageUser returns true if the context user is allowed to manage the
+// user, and responds 403 otherwise.
+func canManageUser(c *context.APIContext, u *db.User) bool {
+	ok, err := c.CanManageUser(u)
+	if err != nil {
+		c.Error(err, "check if user can be managed")
+		return false
+	} else if !ok {
+		c.ErrorStatus(http.StatusForbidden, errors.New("only site admins can manage site admins and users with admin roles"))
+		return false
+	}
+	return true
+}
+
 func EditUser(c *context.APIContext, form api.EditUserOption) {
 	u := user.GetUserByParams(c)
-	if c.Written() {
+	if c.Written() || !canManageUser(c, u) {
+		return
+	}
+
+	if form.Admin != nil && *form.Admin != u.IsAdmin && !c.User.IsAdmin {
+		c.ErrorStatus(http.StatusForbidden, errors.New("only site admins can grant or revoke site admin permissions"))
+		return
+	}
+	// Importing from local paths gives access to files on the server, thus only
+	// site admins are allowed to grant the permission.
+	if form.AllowImportLocal != nil && *form.AllowImportLocal != u.AllowImportLocal && !c.User.IsAdmin {
+		c.ErrorStatus(http.StatusForbidden, errors.New("only site admins can grant or revoke permissions to import local repositories"))
+		return
+	}
+	// Git hooks are executed on the server, thus only hooks admins are allowed
+	// to grant the permission of editing them.
+	if form.AllowGitHook != nil && *form.AllowGitHook != u.AllowGitHook && !c.HasAdminRole(db.AdminRoleHooksAdmin) {
+		c.ErrorStatus(http.StatusForbidden, errors.New("only hooks admins can grant or revoke permissions to create Git hooks"))
 		return
 	}
 
@@ -127,7 +159,7 @@ func EditUser(c *context.APIContext, form api.EditUserOption) {
 
 func DeleteUser(c *context.APIContext) {
 	u := user.GetUserByParams(c)
-	if c.Written() {
+	if c.Written() || !canManageUser(c, u) {
 		return
 	}
 
@@ -147,8 +179,76 @@ func DeleteUser(c *context.APIContext) {
 
 func CreatePublicKey(c *context.APIContext, form api.CreateKeyOption) {
 	u := user.GetUserByParams(c)
-	if c.Written() {
+	if c.Written() || !canManageUser(c, u) {
 		return
 	}
 	user.CreateUserPublicKey(c, form, u.ID)
 }
+
+func EditUserProfileFields(c *context.APIContext) {
+	u := user.GetUserByParams(c)
+	if c.Written() {
+		return
+	}
+	user.EditProfileFieldValues(c, u, true)
+}
+
+func ListUserAdminRoles(c *context.APIContext) {
+	u := user.GetUserByParams(c)
+	if c.Written() {
+		return
+	}
+
+	roles, err := db.AdminRoles.ListByUserID(c.Req.Context(), u.ID)
+	if err != nil {
+		c.Error(err, "list admin roles")
+		return
+	}
+
+	names := make([]string, len(roles))
+	for i := range roles {
+		names[i] = string(roles[i])
+	}
+	c.JSONSuccess(names)
+}
+
+func AssignUserAdminRole(c *context.APIContext) {
+	u := user.GetUserByParams(c)
+	if c.Written() {
+		return
+	}
+
+	role := db.AdminRole(c.Params(":role"))
+	if err := db.AdminRoles.Assign(c.Req.Context(), u.ID, role); err != nil {
+		if db.IsErrInvalidAdminRole(err) {
+			c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		} else {
+			c.Error(err, "assign admin role")
+		}
+		return
+	}
+	log.Trace("Admin role assigned by admin %q: %s -> %s", c.User.Name, role, u.Name)
+
+	c.NoContent()
+}
+
+func RevokeUserAdminRole(c *context.APIContext) {
+	u := user.GetUserByParams(c)
+	if c.Written() {
+		return
+	}
+
+	role := db.AdminRole(c.Params(":role"))
+	if !role.IsValid() {
+		c.ErrorStatus(http.StatusUnprocessableEntity, errors.Errorf("invalid admin role %q", role))
+		return
+	}
+
+	if err := db.AdminRoles.Revoke(c.Req.Context(), u.ID, role); err != nil {
+		c.Error(err, "revoke admin role")
+		return
+	}
+	log.Trace("Admin role revoked by admin %q: %s -> %s", c.User.Name, role, u.Name)
+
+	c.NoContent()
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/errutil"
+)
+
+type ImportUserOption struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	FullName string `json:"full_name"`
+	Password string `json:"password"`
+	Admin    bool   `json:"admin"`
+}
+
+type ImportUsersOption struct {
+	// Users are given either as a list, or as the content of a CSV or LDIF file
+	// with fields mapped by Map, e.g. "name=login,email=mail".
+	Users []*ImportUserOption `json:"users"`
+	CSV   string              `json:"csv"`
+	LDIF  string              `json:"ldif"`
+	Map   string              `json:"map"`
+
+	RandomPassword     bool `json:"random_password"`
+	MustChangePassword bool `json:"must_change_password"`
+	SendResetEmail     bool `json:"send_reset_email"`
+	// DryRun only validates users without creating them.
+	DryRun bool `json:"dry_run"`
+}
+
+type importUserResult struct {
+	// Line is the line number of the CSV or LDIF file, or the index of the list
+	// starting from 1.
+	Line     int    `json:"line"`
+	Username string `json:"username"`
+	ID       int64  `json:"id,omitempty"`
+	Password string `json:"password,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Code     string `json:"code,omitempty"`
+}
+
+func importUserRows(form ImportUsersOption) ([]*db.ImportUserRow, error) {
+	var read func(io.Reader, db.ImportUserFields) ([]*db.ImportUserRow, error)
+	var input string
+	defaults := db.DefaultCSVImportUserFields
+	switch {
+	case len(form.Users) > 0 && (form.CSV != "" || form.LDIF != ""),
+		form.CSV != "" && form.LDIF != "":
+		return nil, errors.New("only one of users, CSV and LDIF can be given")
+	case form.CSV != "":
+		read, input = db.ReadImportUsersCSV, form.CSV
+	case form.LDIF != "":
+		read, input = db.ReadImportUsersLDIF, form.LDIF
+		defaults = db.DefaultLDIFImportUserFields
+	default:
+		rows := make([]*db.ImportUserRow, len(form.Users))
+		for i, u := range form.Users {
+			rows[i] = &db.ImportUserRow{
+				Line:     i + 1,
+				Name:     u.Username,
+				Email:    u.Email,
+				FullName: u.FullName,
+				Password: u.Password,
+				Admin:    u.Admin,
+			}
+		}
+		return rows, nil
+	}
+
+	fields, err := db.ParseImportUserFields(defaults, form.Map)
+	if err != nil {
+		return nil, err
+	}
+	return read(strings.NewReader(input), fields)
+}
+
+// ImportUsers creates users in bulk from a list, or a CSV or LDIF file, and
+// reports the result of every row.
+func ImportUsers(c *context.APIContext, form ImportUsersOption) {
+	rows, err := importUserRows(form)
+	if err != nil {
+		c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		return
+	} else if len(rows) == 0 {
+		c.ErrorStatus(http.StatusUnprocessableEntity, errors.New("no users to import"))
+		return
+	}
+
+	// 🚨 SECURITY: Only site admins can create site admins.
+	if !c.User.IsAdmin {
+		for _, row := range rows {
+			if row.Admin {
+				c.ErrorStatus(http.StatusForbidden, errors.New("only site admins can create site admins"))
+				return
+			}
+		}
+	}
+
+	results, err := db.ImportUsers(c.Req.Context(), rows, db.ImportUsersOptions{
+		RandomPassword:     form.RandomPassword,
+		MustChangePassword: form.MustChangePassword,
+		SendResetEmail:     form.SendResetEmail,
+		RemoteAddr:         c.RemoteAddr(),
+		DryRun:             form.DryRun,
+	})
+	if err != nil {
+		c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var imported int
+	apiResults := make([]*importUserResult, len(results))
+	for i, r := range results {
+		apiResults[i] = &importUserResult{
+			Line:     r.Line,
+			Username: r.Name,
+			Password: r.Password,
+		}
+		if r.Err != nil {
+			apiResults[i].Error = r.Err.Error()
+			apiResults[i].Code = errutil.Code(r.Err)
+			continue
+		}
+		if r.User != nil {
+			apiResults[i].ID = r.User.ID
+			imported++
+		}
+	}
+	if !form.DryRun {
+		log.Trace("Users imported by admin %q: %d of %d", c.User.Name, imported, len(results))
+	}
+	c.JSONSuccess(apiResults)
+}
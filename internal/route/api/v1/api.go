@@ -15,11 +15,13 @@ import (
 
 	"gogs.io/gogs/internal/context"
 	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/downloadlimit"
 	"gogs.io/gogs/internal/form"
 	"gogs.io/gogs/internal/route/api/v1/admin"
 	"gogs.io/gogs/internal/route/api/v1/misc"
 	"gogs.io/gogs/internal/route/api/v1/org"
 	"gogs.io/gogs/internal/route/api/v1/repo"
+	"gogs.io/gogs/internal/route/api/v1/snippet"
 	"gogs.io/gogs/internal/route/api/v1/user"
 )
 
@@ -60,7 +62,7 @@ func repoAssignment() macaron.Handler {
 			c.Repo.AccessMode = db.Perms.AccessMode(c.Req.Context(), c.UserID(), repo.ID,
 				db.AccessModeOptions{
 					OwnerID: repo.OwnerID,
-					Private: repo.IsPrivate,
+					Private: repo.IsPrivate || db.IsTenantIsolated(c.User, owner),
 				},
 			)
 		}
@@ -71,6 +73,9 @@ func repoAssignment() macaron.Handler {
 		}
 
 		c.Repo.Repository = repo
+		if c.IsLogged {
+			c.Repo.LoadListedMaintainer(c.User)
+		}
 	}
 }
 
@@ -91,10 +96,21 @@ func orgAssignment(args ...bool) macaron.Handler {
 
 		var err error
 		if assignOrg {
-			c.Org.Organization, err = db.GetUserByName(c.Params(":orgname"))
+			c.Org.Organization, err = db.Users.GetByUsername(c.Req.Context(), c.Params(":orgname"))
 			if err != nil {
 				c.NotFoundOrError(err, "get organization by name")
 				return
+			} else if !c.IsTenantVisible(c.Org.Organization) {
+				c.NotFound()
+				return
+			}
+
+			// Members who have not enabled two-factor authentication are blocked
+			// when the organization enforces it.
+			org := c.Org.Organization
+			if c.IsLogged && !c.User.IsAdmin && org.IsOrgMember(c.User.ID) && org.IsBlockedByTwoFactorRequirement(c.User.ID) {
+				c.Status(http.StatusForbidden)
+				return
 			}
 		}
 
@@ -128,10 +144,10 @@ func reqBasicAuth() macaron.Handler {
 	}
 }
 
-// reqAdmin makes sure the context user is a site admin.
+// reqAdmin makes sure the context user is a site admin or has any admin role.
 func reqAdmin() macaron.Handler {
 	return func(c *context.Context) {
-		if !c.IsLogged || !c.User.IsAdmin {
+		if !c.HasAdminRole(db.AllAdminRoles...) {
 			c.Status(http.StatusForbidden)
 			return
 		}
@@ -165,11 +181,42 @@ func mustEnableIssues(c *context.APIContext) {
 	}
 }
 
+func mustEnableReleases(c *context.APIContext) {
+	if !c.Repo.Repository.EnableReleases {
+		c.NotFound()
+		return
+	}
+}
+
+// bind is like binding.Bind, but responds 413 when the request body exceeds its
+// limit while being read.
+func bind(obj interface{}) macaron.Handler {
+	bindIgnErr := context.BindIgnErr(obj)
+	return func(c *macaron.Context) {
+		_, _ = c.Invoke(bindIgnErr)
+		if c.Written() {
+			return
+		}
+
+		_, _ = c.Invoke(func(errs binding.Errors) {
+			if len(errs) == 0 {
+				return
+			}
+
+			status := http.StatusUnprocessableEntity
+			if errs.Has(binding.ERR_DESERIALIZATION) {
+				status = http.StatusBadRequest
+			} else if errs.Has(binding.ERR_CONTENT_TYPE) {
+				status = http.StatusUnsupportedMediaType
+			}
+			c.JSON(status, errs)
+		})
+	}
+}
+
 // RegisterRoutes registers all route in API v1 to the web application.
 // FIXME: custom form error response
 func RegisterRoutes(m *macaron.Macaron) {
-	bind := binding.Bind
-
 	m.Group("/v1", func() {
 		// Handle preflight OPTIONS request
 		m.Options("/*", func() {})
@@ -177,6 +224,15 @@ func RegisterRoutes(m *macaron.Macaron) {
 		// Miscellaneous
 		m.Post("/markdown", bind(api.MarkdownOption{}), misc.Markdown)
 		m.Post("/markdown/raw", misc.MarkdownRaw)
+		m.Post("/federation/introspect", misc.IntrospectToken)
+		m.Post("/token", reqToken(), misc.ExchangeToken)
+		m.Get("/gitignore/templates", misc.ListRepoInitFiles(db.RepoInitFileGitignore))
+		m.Get("/gitignore/templates/:name", misc.GetRepoInitFile(db.RepoInitFileGitignore))
+		m.Get("/licenses", misc.ListRepoInitFiles(db.RepoInitFileLicense))
+		m.Get("/licenses/:name", misc.GetRepoInitFile(db.RepoInitFileLicense))
+		m.Get("/readmes", misc.ListRepoInitFiles(db.RepoInitFileReadme))
+		m.Get("/readmes/:name", misc.GetRepoInitFile(db.RepoInitFileReadme))
+		m.Get("/dependents", repo.ListPackageDependents)
 
 		// Users
 		m.Group("/users", func() {
@@ -184,6 +240,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 
 			m.Group("/:username", func() {
 				m.Get("", user.GetInfo)
+				m.Get("/contributions", user.ListContributions)
 
 				m.Group("/tokens", func() {
 					m.Combo("").
@@ -196,6 +253,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 		m.Group("/users", func() {
 			m.Group("/:username", func() {
 				m.Get("/keys", user.ListPublicKeys)
+				m.Get("/profile_fields", user.ListProfileFields)
 
 				m.Get("/followers", user.ListFollowers)
 				m.Group("/following", func() {
@@ -212,6 +270,10 @@ func RegisterRoutes(m *macaron.Macaron) {
 				Post(bind(api.CreateEmailOption{}), user.AddEmail).
 				Delete(bind(api.CreateEmailOption{}), user.DeleteEmail)
 
+			m.Combo("/profile_fields").
+				Get(user.ListMyProfileFields).
+				Patch(user.EditMyProfileFields)
+
 			m.Get("/followers", user.ListMyFollowers)
 			m.Group("/following", func() {
 				m.Get("", user.ListMyFollowing)
@@ -238,14 +300,14 @@ func RegisterRoutes(m *macaron.Macaron) {
 		m.Get("/orgs/:org/repos", reqToken(), repo.ListOrgRepositories)
 		m.Combo("/user/repos", reqToken()).
 			Get(repo.ListMyRepos).
-			Post(bind(api.CreateRepoOption{}), repo.Create)
-		m.Post("/org/:org/repos", reqToken(), bind(api.CreateRepoOption{}), repo.CreateOrgRepo)
+			Post(bind(repo.CreateRepoOption{}), repo.Create)
+		m.Post("/org/:org/repos", reqToken(), bind(repo.CreateRepoOption{}), repo.CreateOrgRepo)
 
 		m.Group("/repos", func() {
 			m.Get("/search", repo.Search)
 
 			m.Get("/:username/:reponame", repoAssignment(), repo.Get)
-			m.Get("/:username/:reponame/releases", repoAssignment(), repo.Releases)
+			m.Get("/:username/:reponame/releases", repoAssignment(), mustEnableReleases, repo.Releases)
 		})
 
 		m.Group("/repos", func() {
@@ -253,6 +315,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Delete("/:username/:reponame", repoAssignment(), repo.Delete)
 
 			m.Group("/:username/:reponame", func() {
+				m.Patch("", reqRepoAdmin(), bind(repo.EditRepoOption{}), repo.Edit)
+				m.Post("/init", reqToken(), reqRepoWriter(), bind(repo.InitRepoOption{}), repo.Initialize)
 				m.Group("/hooks", func() {
 					m.Combo("").
 						Get(repo.ListHooks).
@@ -270,17 +334,23 @@ func RegisterRoutes(m *macaron.Macaron) {
 						Delete(repo.DeleteCollaborator)
 				}, reqRepoAdmin())
 
-				m.Get("/raw/*", context.RepoRef(), repo.GetRawFile)
+				m.Get("/raw/*", context.RepoRef(), downloadlimit.Limit(downloadlimit.EndpointRaw), repo.GetRawFile)
+				m.Get("/license", repo.GetLicense)
+				m.Get("/dependencies", repo.ListDependencies)
+				m.Get("/dependents", repo.ListDependents)
+				m.Get("/traffic", reqRepoAdmin(), repo.GetTraffic)
 				m.Group("/contents", func() {
 					m.Get("", repo.GetContents)
 					m.Get("/*", repo.GetContents)
 				})
-				m.Get("/archive/*", repo.GetArchive)
+				m.Get("/archive/*", downloadlimit.Limit(downloadlimit.EndpointArchive), repo.GetArchive)
 				m.Group("/git/trees", func() {
 					m.Get("/:sha", repo.GetRepoGitTree)
 				})
+				m.Post("/git/refs", reqRepoWriter(), bind(repo.UpdateRefsOption{}), repo.UpdateRefs)
 				m.Get("/forks", repo.ListForks)
 				m.Get("/tags", repo.ListTags)
+				m.Get("/tags/*", repo.GetTag)
 				m.Group("/branches", func() {
 					m.Get("", repo.ListBranches)
 					m.Get("/*", repo.GetBranch)
@@ -290,6 +360,37 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Get("", repo.GetAllCommits)
 					m.Get("/*", repo.GetReferenceSHA)
 				})
+				m.Get("/status", repo.GetBranchStatus)
+				m.Get("/search", repo.SearchRepository)
+				m.Get("/search/commits", repo.SearchCommits)
+				m.Get("/topics", repo.ListTopics)
+				m.Get("/metrics/review-latency", repo.GetReviewLatency)
+				m.Put("/topics", reqToken(), reqRepoAdmin(), bind(repo.TopicsOption{}), repo.ReplaceTopics)
+				m.Combo("/star", reqToken()).
+					Get(repo.IsStarring).
+					Put(repo.Star).
+					Delete(repo.Unstar)
+				m.Combo("/subscription", reqToken()).
+					Get(repo.IsWatching).
+					Put(repo.Watch).
+					Delete(repo.Unwatch)
+				m.Combo("/statuses/:sha").
+					Get(repo.ListStatuses).
+					Post(reqRepoWriter(), bind(repo.CreateStatusOption{}), repo.CreateStatus)
+				m.Group("/deployments", func() {
+					m.Combo("").
+						Get(repo.ListDeployments).
+						Post(reqRepoWriter(), bind(repo.CreateDeploymentOption{}), repo.CreateDeployment)
+					m.Get("/:id", repo.GetDeployment)
+					m.Combo("/:id/statuses").
+						Get(repo.ListDeploymentStatuses).
+						Post(reqRepoWriter(), bind(repo.CreateDeploymentStatusOption{}), repo.CreateDeploymentStatus)
+				})
+				m.Get("/environments", repo.ListEnvironments)
+				m.Group("/pages", func() {
+					m.Get("", repo.GetPagesSite)
+					m.Put("/artifact", reqRepoWriter(), repo.UploadPagesArtifact)
+				})
 
 				m.Group("/keys", func() {
 					m.Combo("").
@@ -330,6 +431,12 @@ func RegisterRoutes(m *macaron.Macaron) {
 								Delete(repo.ClearIssueLabels)
 							m.Delete("/:id", repo.DeleteIssueLabel)
 						}, reqRepoWriter())
+
+						m.Get("/reactions", repo.ListIssueReactions)
+						m.Group("/reactions", func() {
+							m.Post("", bind(repo.CreateReactionOption{}), repo.CreateIssueReaction)
+							m.Delete("/:content", repo.DeleteIssueReaction)
+						}, reqToken())
 					})
 				}, mustEnableIssues)
 
@@ -358,6 +465,12 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Patch("/issue-tracker", reqRepoWriter(), bind(api.EditIssueTrackerOption{}), repo.IssueTracker)
 				m.Patch("/wiki", reqRepoWriter(), bind(api.EditWikiOption{}), repo.Wiki)
 				m.Post("/mirror-sync", reqRepoWriter(), repo.MirrorSync)
+				m.Group("/mirror-drift", func() {
+					m.Get("", repo.GetMirrorDrift)
+					m.Post("/check", repo.CheckMirrorDrift)
+					m.Post("/resync", bind(repo.ResyncMirrorRefsOption{}), repo.ResyncMirrorRefs)
+				}, reqRepoWriter())
+				m.Post("/releases/:id/attachments", reqRepoWriter(), mustEnableReleases, repo.UploadReleaseAttachment)
 				m.Get("/editorconfig/:filename", context.RepoRef(), repo.GetEditorconfig)
 			}, repoAssignment())
 		}, reqToken())
@@ -375,35 +488,96 @@ func RegisterRoutes(m *macaron.Macaron) {
 				Get(org.Get).
 				Patch(bind(api.EditOrgOption{}), org.Edit)
 			m.Get("/teams", org.ListTeams)
+			m.Get("/access", reqToken(), org.GetAccessReport)
+			m.Get("/licenses", reqToken(), org.GetLicenseReport)
+			m.Get("/metrics/review-latency", reqToken(), org.GetReviewLatency)
+			m.Combo("/profile_fields", reqToken()).
+				Get(org.ListProfileFields).
+				Patch(org.EditProfileFields)
+			m.Combo("/announcement", reqToken()).
+				Get(org.GetAnnouncement).
+				Put(bind(org.EditAnnouncementOption{}), org.EditAnnouncement).
+				Delete(org.DeleteAnnouncement)
 		}, orgAssignment(true))
 
+		m.Group("/snippets", func() {
+			m.Combo("").
+				Get(snippet.List).
+				Post(reqToken(), bind(snippet.CreateSnippetOption{}), snippet.Create)
+			m.Group("/:name", func() {
+				m.Combo("").
+					Get(snippet.Get).
+					Patch(reqToken(), bind(snippet.EditSnippetOption{}), snippet.Edit).
+					Delete(reqToken(), snippet.Delete)
+				m.Get("/revisions", snippet.ListRevisions)
+				m.Get("/raw/:filename", snippet.GetRawFile)
+			}, snippet.Assignment())
+		}, snippet.MustEnable)
+
+		m.Group("/runner/job_tokens", func() {
+			m.Combo("").
+				Post(bind(org.CreateJobTokenOption{}), org.CreateJobToken).
+				Delete(bind(org.DeleteJobTokenOption{}), org.DeleteJobToken)
+		}, org.RunnerAssignment())
+
 		m.Group("/admin", func() {
+			reqSiteAdmin := context.AdminRoleRequired()
+			reqUsersAdmin := context.AdminRoleRequired(db.AdminRoleUserAdmin)
+			reqReposAdmin := context.AdminRoleRequired(db.AdminRoleRepoAdmin)
+
 			m.Group("/users", func() {
-				m.Post("", bind(api.CreateUserOption{}), admin.CreateUser)
+				m.Post("", reqUsersAdmin, bind(api.CreateUserOption{}), admin.CreateUser)
+				m.Post("/import", reqUsersAdmin, bind(admin.ImportUsersOption{}), admin.ImportUsers)
 
 				m.Group("/:username", func() {
-					m.Combo("").
+					m.Combo("", reqUsersAdmin).
 						Patch(bind(api.EditUserOption{}), admin.EditUser).
 						Delete(admin.DeleteUser)
-					m.Post("/keys", bind(api.CreateKeyOption{}), admin.CreatePublicKey)
-					m.Post("/orgs", bind(api.CreateOrgOption{}), admin.CreateOrg)
-					m.Post("/repos", bind(api.CreateRepoOption{}), admin.CreateRepo)
+					m.Post("/keys", reqUsersAdmin, bind(api.CreateKeyOption{}), admin.CreatePublicKey)
+					m.Patch("/profile_fields", reqUsersAdmin, admin.EditUserProfileFields)
+					m.Post("/orgs", reqUsersAdmin, bind(api.CreateOrgOption{}), admin.CreateOrg)
+					m.Post("/repos", reqReposAdmin, bind(repo.CreateRepoOption{}), admin.CreateRepo)
+
+					m.Group("/roles", func() {
+						m.Get("", admin.ListUserAdminRoles)
+						m.Combo("/:role").
+							Put(admin.AssignUserAdminRole).
+							Delete(admin.RevokeUserAdminRole)
+					}, reqSiteAdmin)
 				})
 			})
 
+			m.Post("/repos/bulk_edit", reqReposAdmin, bind(admin.BulkEditReposOption{}), admin.BulkEditRepos)
+			m.Group("/repos/trash", func() {
+				m.Get("", admin.ListTrashedRepositories)
+				m.Delete("/:id", admin.PurgeTrashedRepository)
+				m.Post("/:id/restore", admin.RestoreTrashedRepository)
+			}, reqReposAdmin)
+
+			m.Group("/emails", func() {
+				m.Get("", admin.ListQueuedEmails)
+				m.Delete("/:id", admin.DiscardQueuedEmail)
+				m.Post("/:id/retry", admin.RetryQueuedEmail)
+			}, reqSiteAdmin)
+
+			m.Group("/notices", func() {
+				m.Get("", admin.ListNotices)
+				m.Post("/:id/ack", admin.AckNotice)
+			}, reqSiteAdmin)
+
 			m.Group("/orgs/:orgname", func() {
 				m.Group("/teams", func() {
 					m.Post("", orgAssignment(true), bind(api.CreateTeamOption{}), admin.CreateTeam)
 				})
-			})
+			}, reqUsersAdmin)
 
 			m.Group("/teams", func() {
 				m.Group("/:teamid", func() {
-					m.Get("/members", admin.ListTeamMembers)
-					m.Combo("/members/:username").
+					m.Get("/members", reqUsersAdmin, admin.ListTeamMembers)
+					m.Combo("/members/:username", reqUsersAdmin).
 						Put(admin.AddTeamMember).
 						Delete(admin.RemoveTeamMember)
-					m.Combo("/repos/:reponame").
+					m.Combo("/repos/:reponame", reqReposAdmin).
 						Put(admin.AddTeamRepository).
 						Delete(admin.RemoveTeamRepository)
 				}, orgAssignment(false, true))
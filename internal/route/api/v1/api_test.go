@@ -0,0 +1,162 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	gocontext "context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	api "github.com/gogs/go-gogs-client"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/macaron.v1"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/route/api/v1/repo"
+	"gogs.io/gogs/internal/route/api/v1/user"
+)
+
+// usersStore is a db.UsersStore that only looks up users by username.
+type usersStore struct {
+	db.UsersStore
+	users []*db.User
+}
+
+func (s *usersStore) GetByUsername(_ gocontext.Context, username string) (*db.User, error) {
+	for _, u := range s.users {
+		if u.LowerName == strings.ToLower(username) {
+			return u, nil
+		}
+	}
+	return nil, db.ErrUserNotExist{}
+}
+
+func TestTenantIsolation(t *testing.T) {
+	before := conf.Security.EnableTenantIsolation
+	conf.Security.EnableTenantIsolation = true
+	t.Cleanup(func() {
+		conf.Security.EnableTenantIsolation = before
+	})
+
+	var (
+		alice = &db.User{ID: 1, Name: "alice", LowerName: "alice", Tenant: "acme"}
+		carol = &db.User{ID: 2, Name: "carol", LowerName: "carol", Tenant: "acme"}
+		bob   = &db.User{ID: 3, Name: "bob", LowerName: "bob", Tenant: "globex"}
+		admin = &db.User{ID: 4, Name: "admin", LowerName: "admin", Tenant: "globex", IsAdmin: true}
+		org   = &db.User{ID: 5, Name: "acme", LowerName: "acme", Tenant: "acme", Type: db.UserOrganization}
+	)
+	db.SetMockUsersStore(t, &usersStore{users: []*db.User{alice, carol, bob, admin, org}})
+
+	// withActor injects the API context of the actor, nil for anonymous.
+	withActor := func(actor *db.User) macaron.Handler {
+		return func(c *macaron.Context) {
+			c.Map(&context.APIContext{
+				Context: &context.Context{
+					Context:  c,
+					User:     actor,
+					IsLogged: actor != nil,
+				},
+			})
+		}
+	}
+	newServer := func(actor *db.User) *macaron.Macaron {
+		m := macaron.New()
+		m.Use(macaron.Renderer())
+		m.Use(withActor(actor))
+		m.Get("/orgs/:orgname", orgAssignment(true), func(c *context.APIContext) {
+			c.JSONSuccess(c.Org.Organization.Name)
+		})
+		m.Get("/users/:username/keys", func(c *context.APIContext) {
+			u := user.GetUserByParams(c)
+			if c.Written() {
+				return
+			}
+			c.JSONSuccess(u.Name)
+		})
+		m.Get("/users/:username/repos", repo.ListUserRepositories)
+		m.Get("/orgs/:org/repos", repo.ListOrgRepositories)
+		return m
+	}
+
+	tests := []struct {
+		name          string
+		actor         *db.User
+		path          string
+		expStatusCode int
+	}{
+		{name: "user of the same tenant", actor: carol, path: "/users/alice/keys", expStatusCode: http.StatusOK},
+		{name: "user of another tenant", actor: bob, path: "/users/alice/keys", expStatusCode: http.StatusNotFound},
+		{name: "anonymous user", actor: nil, path: "/users/alice/keys", expStatusCode: http.StatusNotFound},
+		{name: "site admin of another tenant", actor: admin, path: "/users/alice/keys", expStatusCode: http.StatusOK},
+
+		{name: "repositories of user of another tenant", actor: bob, path: "/users/alice/repos", expStatusCode: http.StatusNotFound},
+		{name: "repositories of organization as anonymous", actor: nil, path: "/orgs/acme/repos", expStatusCode: http.StatusNotFound},
+
+		{name: "organization as anonymous", actor: nil, path: "/orgs/acme", expStatusCode: http.StatusNotFound},
+		{name: "organization as site admin of another tenant", actor: admin, path: "/orgs/acme", expStatusCode: http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, test.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			newServer(test.actor).ServeHTTP(rr, r)
+			assert.Equal(t, test.expStatusCode, rr.Code)
+			if test.expStatusCode == http.StatusNotFound {
+				assert.Contains(t, rr.Body.String(), `"code":"not_found"`)
+			}
+		})
+	}
+}
+
+func TestBindBodyTooLarge(t *testing.T) {
+	before := conf.HTTP.MaxAPIBodySize
+	conf.HTTP.MaxAPIBodySize = 1
+	t.Cleanup(func() {
+		conf.HTTP.MaxAPIBodySize = before
+	})
+
+	m := macaron.New()
+	m.Use(macaron.Renderer())
+	m.Use(context.BodyLimiter())
+	m.Post("/api/v1/markdown", bind(api.MarkdownOption{}), func(c *macaron.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name          string
+		body          string
+		expStatusCode int
+	}{
+		{name: "within the limit", body: `{"text":"hello"}`, expStatusCode: http.StatusOK},
+		{name: "exceeds the limit", body: `{"text":"` + strings.Repeat("a", 2<<20) + `"}`, expStatusCode: http.StatusRequestEntityTooLarge},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Hide the size of the body as chunked requests do.
+			r, err := http.NewRequest(http.MethodPost, "/api/v1/markdown", io.MultiReader(strings.NewReader(test.body)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.Header.Set("Content-Type", "application/json")
+			r.ContentLength = -1
+
+			rr := httptest.NewRecorder()
+			m.ServeHTTP(rr, r)
+			assert.Equal(t, test.expStatusCode, rr.Code)
+			if test.expStatusCode == http.StatusRequestEntityTooLarge {
+				assert.Contains(t, rr.Body.String(), "API request body exceeds the limit of 1 MB")
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	api "github.com/gogs/go-gogs-client"
 
 	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/gitutil"
 )
 
 func ToEmail(email *db.EmailAddress) *api.Email {
@@ -31,15 +32,54 @@ func ToBranch(b *db.Branch, c *git.Commit) *api.Branch {
 }
 
 type Tag struct {
-	Name   string             `json:"name"`
-	Commit *api.PayloadCommit `json:"commit"`
+	Name string `json:"name"`
+	// ID is the ID of the tag object for annotated tags, or the ID of the commit
+	// for lightweight tags.
+	ID        string             `json:"id"`
+	Type      string             `json:"type"`
+	Message   string             `json:"message,omitempty"`
+	Tagger    *api.PayloadUser   `json:"tagger,omitempty"`
+	Signature *TagSignature      `json:"signature,omitempty"`
+	Commit    *api.PayloadCommit `json:"commit"`
 }
 
-func ToTag(b *db.Tag, c *git.Commit) *Tag {
-	return &Tag{
-		Name:   b.Name,
+type TagSignature struct {
+	Status    string `json:"status"`
+	Signature string `json:"signature"`
+}
+
+func ToTag(repoPath, name string, t *git.Tag, c *git.Commit) *Tag {
+	tag := &Tag{
+		Name:   name,
+		ID:     t.ID().String(),
+		Type:   string(t.Type()),
 		Commit: ToCommit(c),
 	}
+	if t.Type() != git.ObjectTag {
+		return tag
+	}
+
+	message, signature := gitutil.SplitTagSignature(t.Message())
+	tag.Message = message
+	if tagger := t.Tagger(); tagger != nil {
+		taggerUsername := ""
+		u, err := db.GetUserByEmail(tagger.Email)
+		if err == nil {
+			taggerUsername = u.Name
+		}
+		tag.Tagger = &api.PayloadUser{
+			Name:     tagger.Name,
+			Email:    tagger.Email,
+			UserName: taggerUsername,
+		}
+	}
+	if signature != "" {
+		tag.Signature = &TagSignature{
+			Status:    string(gitutil.VerifyTag(repoPath, name, signature)),
+			Signature: signature,
+		}
+	}
+	return tag
 }
 
 func ToCommit(c *git.Commit) *api.PayloadCommit {
@@ -137,3 +177,33 @@ func ToTeam(team *db.Team) *api.Team {
 		Permission:  team.Authorize.String(),
 	}
 }
+
+type ReviewLatency struct {
+	Window       string `json:"window"`
+	PullRequests int64  `json:"pull_requests"`
+	Reviewed     int64  `json:"reviewed"`
+	Merged       int64  `json:"merged"`
+	// Durations are in seconds, and zero when there is no pull request reviewed
+	// or merged.
+	MedianTimeToFirstReview int64 `json:"median_time_to_first_review"`
+	P90TimeToFirstReview    int64 `json:"p90_time_to_first_review"`
+	MedianTimeToMerge       int64 `json:"median_time_to_merge"`
+	P90TimeToMerge          int64 `json:"p90_time_to_merge"`
+}
+
+func ToReviewLatencies(latencies []*db.ReviewLatency) []*ReviewLatency {
+	apiLatencies := make([]*ReviewLatency, len(latencies))
+	for i, l := range latencies {
+		apiLatencies[i] = &ReviewLatency{
+			Window:                  l.Window,
+			PullRequests:            l.PullRequests,
+			Reviewed:                l.Reviewed,
+			Merged:                  l.Merged,
+			MedianTimeToFirstReview: int64(l.MedianTimeToFirstReview.Seconds()),
+			P90TimeToFirstReview:    int64(l.P90TimeToFirstReview.Seconds()),
+			MedianTimeToMerge:       int64(l.MedianTimeToMerge.Seconds()),
+			P90TimeToMerge:          int64(l.P90TimeToMerge.Seconds()),
+		}
+	}
+	return apiLatencies
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/federation"
+)
+
+// IntrospectToken tells satellite instances whether an access token is active,
+// along with the basic information of its owner.
+func IntrospectToken(c *context.APIContext) {
+	if !conf.Federation.EnableIntrospection {
+		c.NotFound()
+		return
+	}
+
+	body, err := c.Req.Body().Bytes()
+	if err != nil {
+		c.Error(err, "read body")
+		return
+	}
+	req, err := federation.ParseIntrospectRequest(conf.Federation.SharedKey, body, c.Req.Header.Get(federation.SignatureHeader), time.Now())
+	if err != nil {
+		c.ErrorStatus(http.StatusUnauthorized, err)
+		return
+	}
+
+	result := federation.Introspection{
+		Timestamp: req.Timestamp,
+	}
+	token, err := db.AccessTokens.GetBySHA1(c.Req.Context(), req.Token)
+	if err == nil {
+		u, err := db.Users.GetByID(c.Req.Context(), token.UserID)
+		if err != nil {
+			c.Error(err, "get user by ID")
+			return
+		}
+		if u.IsActive && !u.ProhibitLogin {
+			result.Active = true
+			result.UserID = u.ID
+			result.Username = u.Name
+			result.Email = u.Email
+			result.FullName = u.FullName
+		}
+	} else if !db.IsErrAccessTokenNotExist(err) {
+		c.Error(err, "get access token by SHA1")
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		c.Error(err, "encode response")
+		return
+	}
+	c.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	c.Header().Set(federation.SignatureHeader, federation.Sign(conf.Federation.SharedKey, data))
+	_, _ = c.Write(data)
+}
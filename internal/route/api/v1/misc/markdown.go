@@ -5,26 +5,78 @@
 package misc
 
 import (
+	"strings"
+
 	api "github.com/gogs/go-gogs-client"
 
 	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
 	"gogs.io/gogs/internal/markup"
 )
 
+// markdownContext returns the URL prefix and metas to render markdown with. A
+// context in the form of "<owner>/<repo>" renders with the repository like the
+// web UI does for issues and comments, i.e. issue references, mentions and
+// relative links are linked within the repository. Other contexts are used as
+// the URL prefix as is. It returns false if a response has been written.
+func markdownContext(c *context.APIContext, renderContext string) (urlPrefix string, metas map[string]string, ok bool) {
+	fields := strings.Split(renderContext, "/")
+	if len(fields) != 2 || fields[0] == "" || fields[1] == "" || strings.Contains(renderContext, ":") {
+		return renderContext, nil, true
+	}
+
+	owner, err := db.Users.GetByUsername(c.Req.Context(), fields[0])
+	if err != nil {
+		c.NotFoundOrError(err, "get owner")
+		return "", nil, false
+	}
+	repo, err := db.Repos.GetByName(c.Req.Context(), owner.ID, fields[1])
+	if err != nil {
+		c.NotFoundOrError(err, "get repository")
+		return "", nil, false
+	}
+	repo.Owner = owner
+
+	if !c.IsTokenAuth || !c.User.IsAdmin {
+		mode := db.Perms.AccessMode(c.Req.Context(), c.UserID(), repo.ID,
+			db.AccessModeOptions{
+				OwnerID: repo.OwnerID,
+				Private: repo.IsPrivate || db.IsTenantIsolated(c.User, owner),
+			},
+		)
+		if mode < db.AccessModeRead {
+			c.NotFound()
+			return "", nil, false
+		}
+	}
+	return repo.Link(), repo.ComposeMetas(), true
+}
+
 func Markdown(c *context.APIContext, form api.MarkdownOption) {
 	if form.Text == "" {
 		_, _ = c.Write([]byte(""))
 		return
 	}
 
-	_, _ = c.Write(markup.Markdown([]byte(form.Text), form.Context, nil))
+	urlPrefix, metas, ok := markdownContext(c, form.Context)
+	if !ok {
+		return
+	}
+	_, _ = c.Write(markup.Markdown([]byte(form.Text), urlPrefix, metas))
 }
 
+// MarkdownRaw renders the request body as markdown, with an optional context
+// given by the "context" query parameter like Markdown.
 func MarkdownRaw(c *context.APIContext) {
 	body, err := c.Req.Body().Bytes()
 	if err != nil {
 		c.Error(err, "read body")
 		return
 	}
-	_, _ = c.Write(markup.SanitizeBytes(markup.RawMarkdown(body, "")))
+
+	urlPrefix, metas, ok := markdownContext(c, c.Query("context"))
+	if !ok {
+		return
+	}
+	_, _ = c.Write(markup.Markdown(body, urlPrefix, metas))
 }
@@ -0,0 +1,47 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+type repoInitFile struct {
+	Name     string `json:"name"`
+	Source   string `json:"source"`
+	IsCustom bool   `json:"is_custom"`
+}
+
+// ListRepoInitFiles returns a handler that lists names of all available
+// template files of the given type.
+func ListRepoInitFiles(tp string) func(c *context.APIContext) {
+	return func(c *context.APIContext) {
+		names := db.RepoInitFileNames(tp)
+		if names == nil {
+			names = []string{}
+		}
+		c.JSONSuccess(names)
+	}
+}
+
+// GetRepoInitFile returns a handler that responds with the content of the
+// template file of the given type and the name in the URL.
+func GetRepoInitFile(tp string) func(c *context.APIContext) {
+	return func(c *context.APIContext) {
+		name := c.Params(":name")
+		data, err := db.GetRepoInitFile(tp, name)
+		if err != nil {
+			c.NotFoundOrError(err, "get template file")
+			return
+		}
+
+		c.JSONSuccess(&repoInitFile{
+			Name:     name,
+			Source:   string(data),
+			IsCustom: db.IsCustomRepoInitFile(tp, name),
+		})
+	}
+}
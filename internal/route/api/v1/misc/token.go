@@ -0,0 +1,46 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/jwtauth"
+)
+
+// JWT is a short-lived JSON Web Token exchanged from a personal access token.
+type JWT struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ExchangeToken issues a short-lived JSON Web Token to the user authenticated
+// with a personal access token.
+func ExchangeToken(c *context.APIContext) {
+	if !jwtauth.Enabled() {
+		c.NotFound()
+		return
+	}
+
+	// Otherwise a leaked JSON Web Token could be renewed forever.
+	if c.IsJWTAuth {
+		c.ErrorStatus(http.StatusForbidden, errors.New("JSON Web Tokens cannot be exchanged for new ones"))
+		return
+	}
+
+	token, expiresAt, err := jwtauth.Issue(c.User.ID)
+	if err != nil {
+		c.Error(err, "issue token")
+		return
+	}
+	c.JSON(http.StatusCreated, &JWT{
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+// GetAccessReport returns effective access of every user to every repository
+// of the organization. The report is returned in CSV format when the query
+// parameter "format" is "csv".
+func GetAccessReport(c *context.APIContext) {
+	org := c.Org.Organization
+	if !c.User.IsAdmin && !org.IsOwnedBy(c.User.ID) {
+		c.Status(http.StatusForbidden)
+		return
+	}
+
+	report, err := db.GetOrgAccessReport(org.ID)
+	if err != nil {
+		c.Error(err, "get access report")
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		c.Resp.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		if err = db.WriteOrgAccessReportCSV(c.Resp, report); err != nil {
+			c.Error(err, "write access report")
+		}
+		return
+	}
+
+	type accessSource struct {
+		Type       string `json:"type"`
+		Name       string `json:"name,omitempty"`
+		Permission string `json:"permission"`
+	}
+	type accessEntry struct {
+		Username   string          `json:"username"`
+		Repository string          `json:"repository"`
+		Private    bool            `json:"private"`
+		Permission string          `json:"permission"`
+		Sources    []*accessSource `json:"sources"`
+	}
+
+	entries := make([]*accessEntry, len(report))
+	for i, e := range report {
+		sources := make([]*accessSource, len(e.Sources))
+		for j, s := range e.Sources {
+			sources[j] = &accessSource{
+				Type:       string(s.Type),
+				Name:       s.Name,
+				Permission: s.Mode.String(),
+			}
+		}
+		entries[i] = &accessEntry{
+			Username:   e.User.Name,
+			Repository: e.Repo.Name,
+			Private:    e.Repo.IsPrivate,
+			Permission: e.Mode.String(),
+			Sources:    sources,
+		}
+	}
+	c.JSONSuccess(entries)
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+	"time"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+type EditAnnouncementOption struct {
+	// Content is the Markdown content of the announcement, an empty content
+	// removes the announcement.
+	Content string `json:"content"`
+}
+
+type announcement struct {
+	Content string     `json:"content"`
+	Updated *time.Time `json:"updated_at"`
+}
+
+func toAnnouncement(org *db.User) *announcement {
+	a := &announcement{
+		Content: org.Announcement,
+	}
+	if org.Announcement != "" {
+		updated := org.AnnouncementUpdated()
+		a.Updated = &updated
+	}
+	return a
+}
+
+// GetAnnouncement returns the announcement of the organization to its members.
+func GetAnnouncement(c *context.APIContext) {
+	org := c.Org.Organization
+	if !c.User.IsAdmin && !org.IsOrgMember(c.User.ID) {
+		c.NotFound()
+		return
+	}
+	c.JSONSuccess(toAnnouncement(org))
+}
+
+// EditAnnouncement changes the announcement of the organization.
+func EditAnnouncement(c *context.APIContext, form EditAnnouncementOption) {
+	setAnnouncement(c, form.Content)
+}
+
+// DeleteAnnouncement removes the announcement of the organization.
+func DeleteAnnouncement(c *context.APIContext) {
+	setAnnouncement(c, "")
+}
+
+func setAnnouncement(c *context.APIContext, content string) {
+	org := c.Org.Organization
+	if !c.User.IsAdmin && !org.IsOwnedBy(c.User.ID) {
+		c.Status(http.StatusForbidden)
+		return
+	}
+
+	if err := db.SetOrgAnnouncement(org, content); err != nil {
+		if db.IsErrOrgAnnouncementTooLong(err) {
+			c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		} else {
+			c.Error(err, "set announcement")
+		}
+		return
+	}
+
+	if content == "" {
+		c.NoContent()
+		return
+	}
+	c.JSONSuccess(toAnnouncement(org))
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+// GetLicenseReport returns the licenses used by repositories of the
+// organization. The report is returned in CSV format when the query parameter
+// "format" is "csv".
+func GetLicenseReport(c *context.APIContext) {
+	org := c.Org.Organization
+	if !c.User.IsAdmin && !org.IsOwnedBy(c.User.ID) {
+		c.Status(http.StatusForbidden)
+		return
+	}
+
+	report, err := db.GetOrgLicenseReport(org.ID)
+	if err != nil {
+		c.Error(err, "get license report")
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		c.Resp.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		if err = db.WriteOrgLicenseReportCSV(c.Resp, report); err != nil {
+			c.Error(err, "write license report")
+		}
+		return
+	}
+
+	type licenseRepo struct {
+		Name    string `json:"name"`
+		Private bool   `json:"private"`
+		Path    string `json:"path"`
+	}
+	type licenseEntry struct {
+		SPDXID       string         `json:"spdx_id"`
+		Name         string         `json:"name"`
+		Count        int            `json:"count"`
+		Repositories []*licenseRepo `json:"repositories"`
+	}
+
+	entries := make([]*licenseEntry, len(report))
+	for i, e := range report {
+		repos := make([]*licenseRepo, len(e.Repos))
+		for j, repo := range e.Repos {
+			repos[j] = &licenseRepo{
+				Name:    repo.Name,
+				Private: repo.IsPrivate,
+				Path:    repo.LicensePath,
+			}
+		}
+		entries[i] = &licenseEntry{
+			SPDXID:       e.SPDX,
+			Name:         e.Name,
+			Count:        len(e.Repos),
+			Repositories: repos,
+		}
+	}
+	c.JSONSuccess(entries)
+}
@@ -93,3 +93,16 @@ func Edit(c *context.APIContext, form api.EditOrgOption) {
 
 	c.JSONSuccess(convert.ToOrganization(org))
 }
+
+func ListProfileFields(c *context.APIContext) {
+	user.ListProfileFieldValues(c, c.Org.Organization)
+}
+
+func EditProfileFields(c *context.APIContext) {
+	org := c.Org.Organization
+	if !org.IsOwnedBy(c.User.ID) {
+		c.Status(http.StatusForbidden)
+		return
+	}
+	user.EditProfileFieldValues(c, org, c.User.IsAdmin)
+}
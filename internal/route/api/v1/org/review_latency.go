@@ -0,0 +1,33 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+	"time"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/route/api/v1/convert"
+)
+
+// GetReviewLatency returns median and 90th percentile time to first review and
+// time to merge of pull requests of all repositories of the organization over
+// rolling windows. Only members of the organization are allowed because private
+// repositories are included.
+func GetReviewLatency(c *context.APIContext) {
+	org := c.Org.Organization
+	if !c.User.IsAdmin && !org.IsOrgMember(c.User.ID) {
+		c.Status(http.StatusForbidden)
+		return
+	}
+
+	latencies, err := db.GetOwnerReviewLatencies(org.ID, time.Now())
+	if err != nil {
+		c.Error(err, "get review latencies")
+		return
+	}
+	c.JSONSuccess(convert.ToReviewLatencies(latencies))
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/macaron.v1"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+// RunnerAssignment authenticates the CI runner with its credential in the
+// "Authorization: runner <credential>" header.
+func RunnerAssignment() macaron.Handler {
+	return func(c *context.APIContext) {
+		if !conf.Auth.EnableJobTokens {
+			c.NotFound()
+			return
+		}
+
+		fields := strings.Fields(c.Req.Header.Get("Authorization"))
+		if len(fields) != 2 || !strings.EqualFold(fields[0], "runner") {
+			c.ErrorStatus(http.StatusUnauthorized, errors.New("runner credential is required"))
+			return
+		}
+
+		runner, err := db.Runners.GetByToken(c.Req.Context(), fields[1])
+		if err != nil {
+			if db.IsErrRunnerNotExist(err) {
+				c.ErrorStatus(http.StatusUnauthorized, errors.New("invalid runner credential"))
+			} else {
+				c.Error(err, "get runner by token")
+			}
+			return
+		}
+		c.Map(runner)
+	}
+}
+
+type CreateJobTokenOption struct {
+	// Repository is the full name of the repository, i.e. "owner/name".
+	Repository string `json:"repository" binding:"Required"`
+	// Permission is either "read" (default) or "write".
+	Permission string `json:"permission"`
+	// ExpiresIn is the valid duration in seconds, which defaults to and is
+	// capped at the configured maximum.
+	ExpiresIn int64 `json:"expires_in"`
+}
+
+type jobToken struct {
+	Token      string    `json:"token"`
+	Repository string    `json:"repository"`
+	Permission string    `json:"permission"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// CreateJobToken exchanges the credential of the runner for a job token of a
+// repository of the organization that registered the runner.
+func CreateJobToken(c *context.APIContext, runner *db.Runner, form CreateJobTokenOption) {
+	mode := db.AccessModeRead
+	switch form.Permission {
+	case "", "read":
+	case "write":
+		mode = db.AccessModeWrite
+	default:
+		c.ErrorStatus(http.StatusUnprocessableEntity, errors.Errorf("invalid permission %q", form.Permission))
+		return
+	}
+
+	names := strings.SplitN(form.Repository, "/", 2)
+	if len(names) != 2 {
+		c.ErrorStatus(http.StatusUnprocessableEntity, errors.Errorf("invalid repository %q", form.Repository))
+		return
+	}
+	owner, err := db.Users.GetByUsername(c.Req.Context(), names[0])
+	if err != nil {
+		c.NotFoundOrError(err, "get owner")
+		return
+	} else if owner.ID != runner.OrgID {
+		c.NotFound()
+		return
+	}
+	repo, err := db.Repos.GetByName(c.Req.Context(), owner.ID, names[1])
+	if err != nil {
+		c.NotFoundOrError(err, "get repository")
+		return
+	}
+
+	// Job tokens never have more access than the user who registered the runner.
+	creator, err := db.Users.GetByID(c.Req.Context(), runner.CreatedByID)
+	if err != nil && !db.IsErrUserNotExist(err) {
+		c.Error(err, "get user who registered the runner")
+		return
+	}
+	if creator == nil || !creator.IsActive || creator.ProhibitLogin ||
+		!db.Perms.Authorize(c.Req.Context(), creator.ID, repo.ID, mode,
+			db.AccessModeOptions{
+				OwnerID: repo.OwnerID,
+				Private: repo.IsPrivate || db.IsTenantIsolated(creator, owner),
+			},
+		) {
+		c.ErrorStatus(http.StatusForbidden, errors.Errorf("runner does not have %s access to the repository", mode))
+		return
+	}
+
+	ttl := time.Duration(conf.Auth.JobTokenMaxLives) * time.Minute
+	if expiresIn := time.Duration(form.ExpiresIn) * time.Second; expiresIn > 0 && expiresIn < ttl {
+		ttl = expiresIn
+	}
+	t, err := db.JobTokens.Create(c.Req.Context(), runner.ID, repo.ID, mode, ttl)
+	if err != nil {
+		c.Error(err, "create job token")
+		return
+	}
+
+	log.Trace("Job token issued to runner %q of organization %q for repository %q", runner.Name, owner.Name, repo.Name)
+	c.JSON(http.StatusCreated, &jobToken{
+		Token:      t.Token,
+		Repository: owner.Name + "/" + repo.Name,
+		Permission: mode.String(),
+		ExpiresAt:  t.ExpiresAt,
+	})
+}
+
+type DeleteJobTokenOption struct {
+	Token string `json:"token" binding:"Required"`
+}
+
+// DeleteJobToken revokes a job token issued to the runner, e.g. when the job
+// has finished before the token expires.
+func DeleteJobToken(c *context.APIContext, runner *db.Runner, form DeleteJobTokenOption) {
+	err := db.JobTokens.DeleteByToken(c.Req.Context(), runner.ID, form.Token)
+	if err != nil {
+		c.NotFoundOrError(err, "delete job token")
+		return
+	}
+	c.NoContent()
+}
@@ -18,13 +18,24 @@ import (
 	"gogs.io/gogs/internal/gitutil"
 )
 
-// GetAllCommits returns a slice of commits starting from HEAD.
+// GetAllCommits returns a slice of commits starting from the revision given by
+// the "sha" query parameter, or HEAD. When the "path" query parameter is given,
+// only commits that changed the file are returned, following renames of the
+// file when the "follow" query parameter is set.
 func GetAllCommits(c *context.APIContext) {
 	// Get pagesize, set default if it is not specified.
 	pageSize := c.QueryInt("pageSize")
 	if pageSize == 0 {
 		pageSize = 30
 	}
+	page := c.QueryInt("page")
+	if page < 1 {
+		page = 1
+	}
+	rev := c.Query("sha")
+	if rev == "" {
+		rev = "HEAD"
+	}
 
 	gitRepo, err := git.Open(c.Repo.Repository.RepoPath())
 	if err != nil {
@@ -32,11 +43,17 @@ func GetAllCommits(c *context.APIContext) {
 		return
 	}
 
+	if path := c.Query("path"); path != "" {
+		getFileCommits(c, gitRepo, rev, path, page, pageSize)
+		return
+	}
+
 	// The response object returned as JSON
-	result := make([]*api.Commit, 0, pageSize)
-	commits, err := gitRepo.Log("HEAD", git.LogOptions{MaxCount: pageSize})
+	result := make([]*apiCommit, 0, pageSize)
+	commits, err := gitRepo.Log(rev, git.LogOptions{MaxCount: pageSize, Skip: (page - 1) * pageSize})
 	if err != nil {
-		c.Error(err, "git log")
+		c.NotFoundOrError(gitutil.NewError(err), "git log")
+		return
 	}
 
 	for _, commit := range commits {
@@ -51,6 +68,54 @@ func GetAllCommits(c *context.APIContext) {
 	c.JSONSuccess(result)
 }
 
+// apiFileCommit is the API commit in the history of a file, with the path of the
+// file in the commit.
+type apiFileCommit struct {
+	*apiCommit
+	Path string `json:"path"`
+	// PreviousPath is the path of the file before the commit, only set when the
+	// file was renamed in the commit.
+	PreviousPath string `json:"previous_path,omitempty"`
+}
+
+// getFileCommits responds with a page of commits that changed the file.
+func getFileCommits(c *context.APIContext, gitRepo *git.Repository, rev, path string, page, pageSize int) {
+	commit, err := gitRepo.CatFileCommit(rev)
+	if err != nil {
+		c.NotFoundOrError(gitutil.NewError(err), "get commit")
+		return
+	}
+	entries, err := gitutil.FileHistory(gitRepo.Path(), commit.ID.String(), path, gitutil.FileHistoryOptions{
+		Follow:   c.QueryBool("follow"),
+		Skip:     (page - 1) * pageSize,
+		MaxCount: pageSize,
+	})
+	if err != nil {
+		c.Error(err, "get file history")
+		return
+	}
+
+	result := make([]*apiFileCommit, 0, len(entries))
+	for _, entry := range entries {
+		commit, err := gitRepo.CatFileCommit(entry.CommitID)
+		if err != nil {
+			c.Error(err, "get commit")
+			return
+		}
+		apiCommit, err := gitCommitToAPICommit(commit, c)
+		if err != nil {
+			c.Error(err, "convert git commit to api commit")
+			return
+		}
+		result = append(result, &apiFileCommit{
+			apiCommit:    apiCommit,
+			Path:         entry.Path,
+			PreviousPath: entry.OldPath,
+		})
+	}
+	c.JSONSuccess(result)
+}
+
 // GetSingleCommit will return a single Commit object based on the specified SHA.
 func GetSingleCommit(c *context.APIContext) {
 	if strings.Contains(c.Req.Header.Get("Accept"), api.MediaApplicationSHA) {
@@ -116,8 +181,20 @@ func GetReferenceSHA(c *context.APIContext) {
 	c.PlainText(http.StatusOK, sha)
 }
 
+// apiCommit is the API commit with co-authors declared by "Co-authored-by"
+// trailers of the commit message.
+type apiCommit struct {
+	*api.Commit
+	CoAuthors []*apiCoAuthor `json:"co_authors"`
+}
+
+type apiCoAuthor struct {
+	*api.CommitUser
+	User *api.User `json:"user"`
+}
+
 // gitCommitToApiCommit is a helper function to convert git commit object to API commit.
-func gitCommitToAPICommit(commit *git.Commit, c *context.APIContext) (*api.Commit, error) {
+func gitCommitToAPICommit(commit *git.Commit, c *context.APIContext) (*apiCommit, error) {
 	// Retrieve author and committer information
 	var apiAuthor, apiCommitter *api.User
 	author, err := db.GetUserByEmail(commit.Author.Email)
@@ -149,32 +226,50 @@ func gitCommitToAPICommit(commit *git.Commit, c *context.APIContext) (*api.Commi
 		}
 	}
 
-	return &api.Commit{
-		CommitMeta: &api.CommitMeta{
-			URL: conf.Server.ExternalURL + c.Link[1:],
-			SHA: commit.ID.String(),
-		},
-		HTMLURL: c.Repo.Repository.HTMLURL() + "/commits/" + commit.ID.String(),
-		RepoCommit: &api.RepoCommit{
-			URL: conf.Server.ExternalURL + c.Link[1:],
-			Author: &api.CommitUser{
-				Name:  commit.Author.Name,
-				Email: commit.Author.Email,
-				Date:  commit.Author.When.Format(time.RFC3339),
+	coAuthors := db.ValidateCoAuthorsWithEmails(commit)
+	apiCoAuthors := make([]*apiCoAuthor, len(coAuthors))
+	for i, coAuthor := range coAuthors {
+		apiCoAuthors[i] = &apiCoAuthor{
+			CommitUser: &api.CommitUser{
+				Name:  coAuthor.Name,
+				Email: coAuthor.Email,
+				Date:  coAuthor.When.Format(time.RFC3339),
 			},
-			Committer: &api.CommitUser{
-				Name:  commit.Committer.Name,
-				Email: commit.Committer.Email,
-				Date:  commit.Committer.When.Format(time.RFC3339),
-			},
-			Message: commit.Summary(),
-			Tree: &api.CommitMeta{
-				URL: c.BaseURL + "/repos/" + c.Repo.Repository.FullName() + "/tree/" + commit.ID.String(),
+		}
+		if coAuthor.User != nil {
+			apiCoAuthors[i].User = coAuthor.User.APIFormat()
+		}
+	}
+
+	return &apiCommit{
+		Commit: &api.Commit{
+			CommitMeta: &api.CommitMeta{
+				URL: conf.Server.ExternalURL + c.Link[1:],
 				SHA: commit.ID.String(),
 			},
+			HTMLURL: c.Repo.Repository.HTMLURL() + "/commits/" + commit.ID.String(),
+			RepoCommit: &api.RepoCommit{
+				URL: conf.Server.ExternalURL + c.Link[1:],
+				Author: &api.CommitUser{
+					Name:  commit.Author.Name,
+					Email: commit.Author.Email,
+					Date:  commit.Author.When.Format(time.RFC3339),
+				},
+				Committer: &api.CommitUser{
+					Name:  commit.Committer.Name,
+					Email: commit.Committer.Email,
+					Date:  commit.Committer.When.Format(time.RFC3339),
+				},
+				Message: commit.Summary(),
+				Tree: &api.CommitMeta{
+					URL: c.BaseURL + "/repos/" + c.Repo.Repository.FullName() + "/tree/" + commit.ID.String(),
+					SHA: commit.ID.String(),
+				},
+			},
+			Author:    apiAuthor,
+			Committer: apiCommitter,
+			Parents:   apiParents,
 		},
-		Author:    apiAuthor,
-		Committer: apiCommitter,
-		Parents:   apiParents,
+		CoAuthors: apiCoAuthors,
 	}, nil
 }
@@ -0,0 +1,118 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/manifest"
+)
+
+type dependency struct {
+	ManifestPath  string `json:"manifest_path"`
+	Ecosystem     string `json:"ecosystem"`
+	Name          string `json:"name"`
+	Requirement   string `json:"requirement"`
+	IsDevelopment bool   `json:"is_development"`
+}
+
+// ListDependencies returns dependencies parsed from manifests in the default
+// branch of the repository.
+func ListDependencies(c *context.APIContext) {
+	deps, err := db.RepoDependencies.ListByRepo(c.Req.Context(), c.Repo.Repository.ID)
+	if err != nil {
+		c.Error(err, "list dependencies")
+		return
+	}
+
+	apiDeps := make([]*dependency, len(deps))
+	for i, dep := range deps {
+		apiDeps[i] = &dependency{
+			ManifestPath:  dep.ManifestPath,
+			Ecosystem:     string(dep.Ecosystem),
+			Name:          dep.Name,
+			Requirement:   dep.Requirement,
+			IsDevelopment: dep.IsDevelopment,
+		}
+	}
+	c.JSONSuccess(apiDeps)
+}
+
+type dependent struct {
+	Repository    string `json:"repository"`
+	Private       bool   `json:"private"`
+	ManifestPath  string `json:"manifest_path"`
+	Ecosystem     string `json:"ecosystem"`
+	Name          string `json:"name"`
+	Requirement   string `json:"requirement"`
+	IsDevelopment bool   `json:"is_development"`
+}
+
+// toDependents converts dependents to their API format.
+func toDependents(dependents []*db.RepoDependent) []*dependent {
+	apiDependents := make([]*dependent, len(dependents))
+	for i, d := range dependents {
+		apiDependents[i] = &dependent{
+			Repository:    d.Repo.FullName(),
+			Private:       d.Repo.IsPrivate,
+			ManifestPath:  d.Dependency.ManifestPath,
+			Ecosystem:     string(d.Dependency.Ecosystem),
+			Name:          d.Dependency.Name,
+			Requirement:   d.Dependency.Requirement,
+			IsDevelopment: d.Dependency.IsDevelopment,
+		}
+	}
+	return apiDependents
+}
+
+// ListDependents returns repositories within this instance that depend on
+// packages declared by manifests of the repository. Only repositories that the
+// context user has read access to are returned.
+func ListDependents(c *context.APIContext) {
+	deps, err := db.RepoDependencies.ListByRepo(c.Req.Context(), c.Repo.Repository.ID)
+	if err != nil {
+		c.Error(err, "list dependencies")
+		return
+	}
+
+	dependents := make([]*db.RepoDependent, 0)
+	for _, pkg := range db.DeclaredPackages(deps) {
+		ds, err := db.ListAccessibleDependents(c.Req.Context(), c.User, pkg.Ecosystem, pkg.Name)
+		if err != nil {
+			c.Error(err, "list accessible dependents")
+			return
+		}
+		for _, d := range ds {
+			if d.Repo.ID != c.Repo.Repository.ID {
+				dependents = append(dependents, d)
+			}
+		}
+	}
+	c.JSONSuccess(toDependents(dependents))
+}
+
+// ListPackageDependents returns repositories within this instance that depend
+// on the package with given ecosystem and name, for consumption by security
+// tooling. Only repositories that the context user has read access to are
+// returned.
+func ListPackageDependents(c *context.APIContext) {
+	ecosystem := manifest.Ecosystem(c.Query("ecosystem"))
+	name := c.Query("name")
+	if ecosystem == "" || name == "" {
+		c.ErrorStatus(http.StatusUnprocessableEntity, errors.New("both ecosystem and name are required"))
+		return
+	}
+
+	dependents, err := db.ListAccessibleDependents(c.Req.Context(), c.User, ecosystem, name)
+	if err != nil {
+		c.Error(err, "list accessible dependents")
+		return
+	}
+	c.JSONSuccess(toDependents(dependents))
+}
@@ -0,0 +1,241 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+type CreateDeploymentOption struct {
+	// Ref is the branch, tag or commit SHA to be deployed.
+	Ref         string `json:"ref" binding:"Required"`
+	Environment string `json:"environment" binding:"Required;MaxSize(255)"`
+	Description string `json:"description"`
+	// PullRequest is the index of the open pull request when the environment is
+	// a preview of it, which is torn down when the pull request is closed.
+	PullRequest int64 `json:"pull_request"`
+}
+
+type deployment struct {
+	ID          int64     `json:"id"`
+	Environment string    `json:"environment"`
+	Ref         string    `json:"ref"`
+	SHA         string    `json:"sha"`
+	Description string    `json:"description"`
+	State       string    `json:"state"`
+	CreatorID   int64     `json:"creator_id"`
+	Created     time.Time `json:"created_at"`
+	Updated     time.Time `json:"updated_at"`
+
+	EnvironmentURL string `json:"environment_url"`
+	PullRequest    int64  `json:"pull_request,omitempty"`
+}
+
+func toDeployment(d *db.Deployment) *deployment {
+	return &deployment{
+		ID:          d.ID,
+		Environment: d.Environment,
+		Ref:         d.Ref,
+		SHA:         d.SHA,
+		Description: d.Description,
+		State:       string(d.State),
+		CreatorID:   d.CreatorID,
+		Created:     d.CreatedAt,
+		Updated:     d.UpdatedAt,
+
+		EnvironmentURL: d.EnvironmentURL,
+		PullRequest:    d.PullRequestIndex,
+	}
+}
+
+func toDeployments(ds []*db.Deployment) []*deployment {
+	apiDeployments := make([]*deployment, len(ds))
+	for i := range ds {
+		apiDeployments[i] = toDeployment(ds[i])
+	}
+	return apiDeployments
+}
+
+// ListDeployments returns deployments of the repository, optionally filtered
+// by environment, ref, commit SHA and pull request.
+func ListDeployments(c *context.APIContext) {
+	ds, err := db.Deployments.List(c.Req.Context(), c.Repo.Repository.ID, db.ListDeploymentsOptions{
+		Environment: c.Query("environment"),
+		Ref:         c.Query("ref"),
+		SHA:         c.Query("sha"),
+
+		PullRequestIndex: c.QueryInt64("pull_request"),
+	})
+	if err != nil {
+		c.Error(err, "list deployments")
+		return
+	}
+	c.JSONSuccess(toDeployments(ds))
+}
+
+// CreateDeployment records a new deployment of the commit that the ref points
+// to, in pending state.
+func CreateDeployment(c *context.APIContext, form CreateDeploymentOption) {
+	if strings.HasPrefix(form.Ref, "-") {
+		c.ErrorStatus(http.StatusUnprocessableEntity, errors.Errorf("invalid ref %q", form.Ref))
+		return
+	}
+
+	if form.PullRequest != 0 {
+		issue, err := db.GetIssueByIndex(c.Repo.Repository.ID, form.PullRequest)
+		if err != nil && !db.IsErrIssueNotExist(err) {
+			c.Error(err, "get pull request")
+			return
+		} else if err != nil || !issue.IsPull || issue.IsClosed {
+			c.ErrorStatus(http.StatusUnprocessableEntity, errors.Errorf("pull request #%d does not exist or is closed", form.PullRequest))
+			return
+		}
+	}
+
+	sha, ok := resolveRevision(c, form.Ref)
+	if !ok {
+		return
+	}
+
+	d, err := db.Deployments.Create(c.Req.Context(), c.Repo.Repository.ID, c.User.ID, db.CreateDeploymentOptions{
+		Environment: form.Environment,
+		Ref:         form.Ref,
+		SHA:         sha,
+		Description: form.Description,
+
+		PullRequestIndex: form.PullRequest,
+	})
+	if err != nil {
+		c.Error(err, "create deployment")
+		return
+	}
+	db.PrepareDeploymentWebhook(c.User, c.Repo.Repository, d, db.DeploymentActionCreated)
+	c.JSON(http.StatusCreated, toDeployment(d))
+}
+
+// GetDeployment returns the deployment with the ":id" parameter.
+func GetDeployment(c *context.APIContext) {
+	d, err := db.Deployments.GetByID(c.Req.Context(), c.Repo.Repository.ID, c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get deployment")
+		return
+	}
+	c.JSONSuccess(toDeployment(d))
+}
+
+type CreateDeploymentStatusOption struct {
+	State       string `json:"state" binding:"Required"`
+	TargetURL   string `json:"target_url"`
+	Description string `json:"description"`
+	// EnvironmentURL is the URL of the deployed environment, which is shown as
+	// "View deployment" on the pull request for successful preview deployments.
+	EnvironmentURL string `json:"environment_url" binding:"Url"`
+}
+
+type deploymentStatus struct {
+	ID          int64     `json:"id"`
+	State       string    `json:"state"`
+	TargetURL   string    `json:"target_url"`
+	Description string    `json:"description"`
+	CreatorID   int64     `json:"creator_id"`
+	Created     time.Time `json:"created_at"`
+
+	EnvironmentURL string `json:"environment_url"`
+}
+
+func toDeploymentStatus(s *db.DeploymentStatus) *deploymentStatus {
+	return &deploymentStatus{
+		ID:          s.ID,
+		State:       string(s.State),
+		TargetURL:   s.TargetURL,
+		Description: s.Description,
+		CreatorID:   s.CreatorID,
+		Created:     s.CreatedAt,
+
+		EnvironmentURL: s.EnvironmentURL,
+	}
+}
+
+// ListDeploymentStatuses returns all statuses of the deployment, the most
+// recent first.
+func ListDeploymentStatuses(c *context.APIContext) {
+	d, err := db.Deployments.GetByID(c.Req.Context(), c.Repo.Repository.ID, c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get deployment")
+		return
+	}
+
+	statuses, err := db.Deployments.ListStatuses(c.Req.Context(), d.ID)
+	if err != nil {
+		c.Error(err, "list deployment statuses")
+		return
+	}
+
+	apiStatuses := make([]*deploymentStatus, len(statuses))
+	for i := range statuses {
+		apiStatuses[i] = toDeploymentStatus(statuses[i])
+	}
+	c.JSONSuccess(apiStatuses)
+}
+
+// CreateDeploymentStatus creates a new status for the deployment, which also
+// becomes the state of the deployment.
+func CreateDeploymentStatus(c *context.APIContext, form CreateDeploymentStatusOption) {
+	state := db.DeploymentState(form.State)
+	if !state.IsValid() {
+		c.ErrorStatus(http.StatusUnprocessableEntity, errors.Errorf("invalid state %q", form.State))
+		return
+	}
+
+	d, err := db.Deployments.GetByID(c.Req.Context(), c.Repo.Repository.ID, c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get deployment")
+		return
+	}
+
+	s, err := db.Deployments.CreateStatus(c.Req.Context(), d.ID, c.User.ID, db.CreateDeploymentStatusOptions{
+		State:       state,
+		TargetURL:   form.TargetURL,
+		Description: form.Description,
+
+		EnvironmentURL: form.EnvironmentURL,
+	})
+	if err != nil {
+		c.Error(err, "create deployment status")
+		return
+	}
+	c.JSON(http.StatusCreated, toDeploymentStatus(s))
+}
+
+type environment struct {
+	Name             string      `json:"name"`
+	LatestDeployment *deployment `json:"latest_deployment"`
+}
+
+// ListEnvironments returns all environments that the repository has been
+// deployed to, with the latest deployment of each.
+func ListEnvironments(c *context.APIContext) {
+	ds, err := db.Deployments.ListEnvironments(c.Req.Context(), c.Repo.Repository.ID)
+	if err != nil {
+		c.Error(err, "list environments")
+		return
+	}
+
+	envs := make([]*environment, len(ds))
+	for i := range ds {
+		envs[i] = &environment{
+			Name:             ds[i].Environment,
+			LatestDeployment: toDeployment(ds[i]),
+		}
+	}
+	c.JSONSuccess(envs)
+}
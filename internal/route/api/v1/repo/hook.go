@@ -69,6 +69,11 @@ func CreateHook(c *context.APIContext, form api.CreateHookOption) {
 				IssueComment: com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_ISSUE_COMMENT)),
 				PullRequest:  com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_PULL_REQUEST)),
 				Release:      com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_RELEASE)),
+				Star:         com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_STAR)),
+				Watch:        com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_WATCH)),
+				Repository:   com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_REPOSITORY)),
+				Deployment:   com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_DEPLOYMENT)),
+				Wiki:         com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_WIKI)),
 			},
 		},
 		IsActive:     form.Active,
@@ -156,6 +161,11 @@ func EditHook(c *context.APIContext, form api.EditHookOption) {
 	w.IssueComment = com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_ISSUE_COMMENT))
 	w.PullRequest = com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_PULL_REQUEST))
 	w.Release = com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_RELEASE))
+	w.Star = com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_STAR))
+	w.Watch = com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_WATCH))
+	w.Repository = com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_REPOSITORY))
+	w.Deployment = com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_DEPLOYMENT))
+	w.Wiki = com.IsSliceContainsStr(form.Events, string(db.HOOK_EVENT_WIKI))
 	if err = w.UpdateEvent(); err != nil {
 		c.Errorf(err, "update event")
 		return
@@ -0,0 +1,54 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	api "github.com/gogs/go-gogs-client"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+type InitRepoOption struct {
+	// Readme is the name of the README template, "Default" when empty.
+	Readme string `json:"readme"`
+	// License is the name of the license template, no license when empty.
+	License string `json:"license"`
+	// Gitignores is the list of names of gitignore templates separated by
+	// commas, no .gitignore when empty.
+	Gitignores string `json:"gitignores"`
+}
+
+// Initialize creates the initial commit of the empty repository with chosen
+// templates, the same as auto-initialization when creating a repository on the
+// web.
+func Initialize(c *context.APIContext, opt InitRepoOption) {
+	repo := c.Repo.Repository
+	err := db.InitializeRepository(c.User, repo, db.CreateRepoOptionsLegacy{
+		Readme:     opt.Readme,
+		License:    opt.License,
+		Gitignores: opt.Gitignores,
+	})
+	if err != nil {
+		if db.IsErrRepoNotEmpty(err) {
+			c.ErrorStatus(http.StatusConflict, err)
+		} else if db.IsErrRepoInitFileNotExist(err) {
+			c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		} else {
+			c.Error(err, "initialize repository")
+		}
+		return
+	}
+	log.Trace("Repository initialized by %q: %s/%s", c.User.Name, c.Repo.Owner.Name, repo.Name)
+
+	c.JSON(http.StatusCreated, repo.APIFormatLegacy(&api.Permission{
+		Admin: c.Repo.IsAdmin(),
+		Push:  c.Repo.IsWriter(),
+		Pull:  true,
+	}))
+}
@@ -58,6 +58,7 @@ func ListIssues(c *context.APIContext) {
 		RepoID:   c.Repo.Repository.ID,
 		Page:     c.QueryInt("page"),
 		IsClosed: api.StateType(c.Query("state")) == api.STATE_CLOSED,
+		SortType: c.Query("sort"),
 	}
 
 	listIssues(c, &opts)
@@ -81,7 +82,7 @@ func CreateIssue(c *context.APIContext, form api.CreateIssueOption) {
 		Content:  form.Body,
 	}
 
-	if c.Repo.IsWriter() {
+	if c.Repo.CanTriage() {
 		if len(form.Assignee) > 0 {
 			assignee, err := db.GetUserByName(form.Assignee)
 			if err != nil {
@@ -128,19 +129,23 @@ func EditIssue(c *context.APIContext, form api.EditIssueOption) {
 		return
 	}
 
-	if !issue.IsPoster(c.User.ID) && !c.Repo.IsWriter() {
+	if !issue.IsPoster(c.User.ID) && !c.Repo.CanTriage() {
 		c.Status(http.StatusForbidden)
 		return
 	}
 
-	if len(form.Title) > 0 {
-		issue.Title = form.Title
-	}
-	if form.Body != nil {
-		issue.Content = *form.Body
+	// Listed maintainers are able to triage the issue but not to change its
+	// content posted by others.
+	if issue.IsPoster(c.User.ID) || c.Repo.IsWriter() {
+		if len(form.Title) > 0 {
+			issue.Title = form.Title
+		}
+		if form.Body != nil {
+			issue.Content = *form.Body
+		}
 	}
 
-	if c.Repo.IsWriter() && form.Assignee != nil &&
+	if c.Repo.CanTriage() && form.Assignee != nil &&
 		(issue.Assignee == nil || issue.Assignee.LowerName != strings.ToLower(*form.Assignee)) {
 		if *form.Assignee == "" {
 			issue.AssigneeID = 0
@@ -162,7 +167,7 @@ func EditIssue(c *context.APIContext, form api.EditIssueOption) {
 			return
 		}
 	}
-	if c.Repo.IsWriter() && form.Milestone != nil &&
+	if c.Repo.CanTriage() && form.Milestone != nil &&
 		issue.MilestoneID != *form.Milestone {
 		oldMilestoneID := issue.MilestoneID
 		issue.MilestoneID = *form.Milestone
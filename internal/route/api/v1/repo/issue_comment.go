@@ -75,12 +75,41 @@ func CreateIssueComment(c *context.APIContext, form api.CreateIssueCommentOption
 		return
 	}
 
-	comment, err := db.CreateIssueComment(c.User, c.Repo.Repository, issue, form.Body, nil)
+	cmds, content := db.ParseCommentCommands(form.Body)
+	if len(cmds) == 0 {
+		comment, err := db.CreateIssueComment(c.User, c.Repo.Repository, issue, form.Body, nil)
+		if err != nil {
+			c.Error(err, "create issue comment")
+			return
+		}
+
+		c.JSON(http.StatusCreated, comment.APIFormat())
+		return
+	}
+
+	actions, err := db.ResolveCommentCommands(c.User, c.Repo.Repository, issue, c.Repo.CanTriage(), cmds)
+	if err != nil {
+		if db.IsErrCommentCommandNotPermitted(err) {
+			c.ErrorStatus(http.StatusForbidden, err)
+		} else if db.IsErrCommentCommandInvalid(err) {
+			c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		} else {
+			c.Error(err, "resolve comment commands")
+		}
+		return
+	}
+
+	comment, err := db.CreateIssueCommentWithActions(c.User, c.Repo.Repository, issue, content, nil, actions)
 	if err != nil {
-		c.Error(err, "create issue comment")
+		c.Error(err, "create issue comment with actions")
 		return
 	}
 
+	// The comment is omitted when it consists of only commands.
+	if comment == nil {
+		c.NoContent()
+		return
+	}
 	c.JSON(http.StatusCreated, comment.APIFormat())
 }
 
@@ -0,0 +1,25 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"gogs.io/gogs/internal/context"
+)
+
+// GetLicense returns the license detected from the license file in the root
+// directory of the default branch of the repository.
+func GetLicense(c *context.APIContext) {
+	repo := c.Repo.Repository
+	if repo.LicenseSPDX == "" {
+		c.NotFound()
+		return
+	}
+
+	c.JSONSuccess(map[string]string{
+		"spdx_id": repo.LicenseSPDX,
+		"name":    repo.LicenseName(),
+		"path":    repo.LicensePath,
+	})
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-macaron/binding"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+type mirrorRefDrift struct {
+	Ref              string    `json:"ref"`
+	Kind             string    `json:"kind"`
+	UpstreamCommitID string    `json:"upstream_commit_id"`
+	LocalCommitID    string    `json:"local_commit_id"`
+	CheckedAt        time.Time `json:"checked_at"`
+}
+
+type mirrorDrift struct {
+	// CheckedAt is nil if the drift has never been checked.
+	CheckedAt *time.Time        `json:"checked_at"`
+	Refs      []*mirrorRefDrift `json:"refs"`
+}
+
+// toMirrorDrift converts drifted references of the mirror to their API format.
+func toMirrorDrift(m *db.Mirror, drifts []*db.MirrorRefDrift) *mirrorDrift {
+	apiDrift := &mirrorDrift{
+		Refs: make([]*mirrorRefDrift, len(drifts)),
+	}
+	if m.DriftCheckedUnix > 0 {
+		checkedAt := m.DriftChecked
+		apiDrift.CheckedAt = &checkedAt
+	}
+	for i, d := range drifts {
+		apiDrift.Refs[i] = &mirrorRefDrift{
+			Ref:              d.RefName,
+			Kind:             d.Kind(),
+			UpstreamCommitID: d.UpstreamCommitID,
+			LocalCommitID:    d.LocalCommitID,
+			CheckedAt:        d.CheckedAt,
+		}
+	}
+	return apiDrift
+}
+
+// getMirror returns the mirror of the context repository. It returns nil if a
+// response has been written.
+func getMirror(c *context.APIContext) *db.Mirror {
+	repo := c.Repo.Repository
+	if !repo.IsMirror || !conf.Mirror.EnableDriftCheck {
+		c.NotFound()
+		return nil
+	}
+
+	m, err := db.GetMirrorByRepoID(repo.ID)
+	if err != nil {
+		c.Error(err, "get mirror")
+		return nil
+	}
+	return m
+}
+
+// GetMirrorDrift returns references of the mirror that have drifted from its
+// upstream as of the last check.
+func GetMirrorDrift(c *context.APIContext) {
+	m := getMirror(c)
+	if m == nil {
+		return
+	}
+
+	drifts, err := db.MirrorRefDrifts.List(c.Req.Context(), m.RepoID)
+	if err != nil {
+		c.Error(err, "list drifted references")
+		return
+	}
+	c.JSONSuccess(toMirrorDrift(m, drifts))
+}
+
+// CheckMirrorDrift compares references of the mirror with its upstream now,
+// and returns references that have drifted.
+func CheckMirrorDrift(c *context.APIContext) {
+	m := getMirror(c)
+	if m == nil {
+		return
+	}
+
+	drifts, err := db.CheckMirrorDrift(c.Req.Context(), m)
+	if err != nil {
+		c.Error(err, "check drift")
+		return
+	}
+	c.JSONSuccess(toMirrorDrift(m, drifts))
+}
+
+type ResyncMirrorRefsOption struct {
+	Refs []string `json:"refs"`
+}
+
+// ResyncMirrorRefs forcibly re-syncs given drifted references of the mirror
+// from its upstream, and returns references that are still drifted.
+func ResyncMirrorRefs(c *context.APIContext, opt ResyncMirrorRefsOption) {
+	if len(opt.Refs) == 0 {
+		var errs binding.Errors
+		errs.Add([]string{"refs"}, binding.ERR_REQUIRED, "At least one reference is required")
+		c.JSON(http.StatusUnprocessableEntity, errs)
+		return
+	}
+
+	m := getMirror(c)
+	if m == nil {
+		return
+	}
+
+	drifts, err := db.ResyncMirrorRefs(c.Req.Context(), m, opt.Refs)
+	if err != nil {
+		if db.IsErrMirrorRefNotDrifted(err) {
+			c.ErrorStatus(http.StatusUnprocessableEntity, err)
+			return
+		}
+		c.Error(err, "re-sync references")
+		return
+	}
+	c.JSONSuccess(toMirrorDrift(m, drifts))
+}
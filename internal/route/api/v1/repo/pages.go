@@ -0,0 +1,98 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/pages"
+)
+
+type pagesSite struct {
+	URL            string `json:"url"`
+	Source         string `json:"source"`
+	Branch         string `json:"branch"`
+	Directory      string `json:"directory"`
+	ArtifactSHA256 string `json:"artifact_sha256"`
+	CustomDomain   string `json:"custom_domain"`
+	DomainVerified bool   `json:"domain_verified"`
+}
+
+func toPagesSite(c *context.APIContext, site *db.PagesSite) *pagesSite {
+	return &pagesSite{
+		URL:            pages.SiteURL(c.Repo.Owner.Name, c.Repo.Repository.Name, site),
+		Source:         string(site.Source),
+		Branch:         site.Branch,
+		Directory:      site.Directory,
+		ArtifactSHA256: site.ArtifactSHA256,
+		CustomDomain:   site.CustomDomain,
+		DomainVerified: site.IsDomainVerified,
+	}
+}
+
+func mustEnablePages(c *context.APIContext) bool {
+	if !conf.Pages.Enabled {
+		c.NotFound()
+		return false
+	}
+	return true
+}
+
+// GetPagesSite returns the pages site of the repository.
+func GetPagesSite(c *context.APIContext) {
+	if !mustEnablePages(c) {
+		return
+	}
+
+	site, err := db.PagesSites.GetByRepoID(c.Req.Context(), c.Repo.Repository.ID)
+	if err != nil {
+		c.NotFoundOrError(err, "get pages site")
+		return
+	}
+	c.JSONSuccess(toPagesSite(c, site))
+}
+
+// UploadPagesArtifact replaces the artifact of the pages site of the
+// repository with the ZIP archive in the request body. The site must be
+// published from an artifact.
+func UploadPagesArtifact(c *context.APIContext) {
+	if !mustEnablePages(c) {
+		return
+	}
+
+	site, err := db.PagesSites.GetByRepoID(c.Req.Context(), c.Repo.Repository.ID)
+	if err != nil {
+		c.NotFoundOrError(err, "get pages site")
+		return
+	} else if site.Source != db.PagesSourceArtifact {
+		c.ErrorStatus(http.StatusUnprocessableEntity, errors.New("pages site is not published from an artifact"))
+		return
+	}
+
+	sha256, err := pages.SaveArtifact(site.RepoID, c.Req.Request.Body)
+	if err != nil {
+		switch err {
+		case pages.ErrArtifactTooLarge:
+			c.ErrorStatus(http.StatusRequestEntityTooLarge, err)
+		case pages.ErrInvalidArtifact:
+			c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		default:
+			c.Error(err, "save artifact")
+		}
+		return
+	}
+
+	if err = db.PagesSites.SetArtifact(c.Req.Context(), site.RepoID, sha256); err != nil {
+		c.Error(err, "set artifact")
+		return
+	}
+	site.ArtifactSHA256 = sha256
+	c.JSONSuccess(toPagesSite(c, site))
+}
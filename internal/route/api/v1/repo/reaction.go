@@ -0,0 +1,100 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"time"
+
+	api "github.com/gogs/go-gogs-client"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+type CreateReactionOption struct {
+	// Content is one of "thumbs_up", "thumbs_down", "laugh", "hooray",
+	// "confused" and "heart".
+	Content string `json:"content" binding:"Required"`
+}
+
+type reaction struct {
+	User    *api.User `json:"user"`
+	Content string    `json:"content"`
+	Created time.Time `json:"created_at"`
+}
+
+// ListIssueReactions returns all reactions to the issue.
+func ListIssueReactions(c *context.APIContext) {
+	issue, err := db.GetIssueByIndex(c.Repo.Repository.ID, c.ParamsInt64(":index"))
+	if err != nil {
+		c.NotFoundOrError(err, "get issue by index")
+		return
+	}
+
+	reactions, err := db.Reactions.List(c.Req.Context(), issue.ID)
+	if err != nil {
+		c.Error(err, "list reactions")
+		return
+	}
+
+	users := make(map[int64]*db.User)
+	apiReactions := make([]*reaction, 0, len(reactions))
+	for _, r := range reactions {
+		u, ok := users[r.UserID]
+		if !ok {
+			u, err = db.GetUserByID(r.UserID)
+			if err != nil {
+				if db.IsErrUserNotExist(err) {
+					continue
+				}
+				c.Error(err, "get user by ID")
+				return
+			}
+			users[r.UserID] = u
+		}
+		apiReactions = append(apiReactions, &reaction{
+			User:    u.APIFormat(),
+			Content: r.Content,
+			Created: r.CreatedAt,
+		})
+	}
+	c.JSONSuccess(apiReactions)
+}
+
+// CreateIssueReaction adds a reaction of the user to the issue.
+func CreateIssueReaction(c *context.APIContext, form CreateReactionOption) {
+	issue, err := db.GetIssueByIndex(c.Repo.Repository.ID, c.ParamsInt64(":index"))
+	if err != nil {
+		c.NotFoundOrError(err, "get issue by index")
+		return
+	}
+
+	err = db.Reactions.Create(c.Req.Context(), issue.ID, c.User.ID, form.Content)
+	if err != nil {
+		if db.IsErrReactionInvalid(err) {
+			c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		} else {
+			c.Error(err, "create reaction")
+		}
+		return
+	}
+	c.Status(http.StatusCreated)
+}
+
+// DeleteIssueReaction removes a reaction of the user from the issue.
+func DeleteIssueReaction(c *context.APIContext) {
+	issue, err := db.GetIssueByIndex(c.Repo.Repository.ID, c.ParamsInt64(":index"))
+	if err != nil {
+		c.NotFoundOrError(err, "get issue by index")
+		return
+	}
+
+	if err = db.Reactions.Delete(c.Req.Context(), issue.ID, c.User.ID, c.Params(":content")); err != nil {
+		c.Error(err, "delete reaction")
+		return
+	}
+	c.NoContent()
+}
@@ -0,0 +1,209 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-macaron/binding"
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/gitutil"
+)
+
+type RefUpdateOption struct {
+	// The full name of the reference, e.g. "refs/heads/main".
+	Ref string `json:"ref"`
+	// The new SHA of the reference, empty to delete the reference.
+	SHA string `json:"sha"`
+	// The expected current SHA of the reference, empty to create the reference.
+	OldSHA string `json:"old_sha"`
+}
+
+type UpdateRefsOption struct {
+	Updates []RefUpdateOption `json:"updates"`
+}
+
+type refUpdate struct {
+	Ref    string `json:"ref"`
+	SHA    string `json:"sha"`
+	OldSHA string `json:"old_sha"`
+}
+
+// validateRefUpdates checks the given updates and resolves their new SHAs. It
+// returns field-level errors for any invalid value.
+func validateRefUpdates(gitRepo *git.Repository, opts []RefUpdateOption) ([]gitutil.RefUpdate, binding.Errors) {
+	var errs binding.Errors
+	if len(opts) == 0 {
+		errs.Add([]string{"updates"}, binding.ERR_REQUIRED, "At least one update is required")
+		return nil, errs
+	}
+
+	updates := make([]gitutil.RefUpdate, len(opts))
+	seen := make(map[string]bool, len(opts))
+	for i, opt := range opts {
+		field := func(name string) []string {
+			return []string{fmt.Sprintf("updates[%d].%s", i, name)}
+		}
+
+		if !strings.HasPrefix(opt.Ref, git.RefsHeads) && !strings.HasPrefix(opt.Ref, git.RefsTags) {
+			errs.Add(field("ref"), binding.ERR_IN, "Reference must be a branch or a tag")
+			continue
+		} else if !gitutil.IsValidRefName(opt.Ref) {
+			errs.Add(field("ref"), "RefNameError", "Reference name is not valid")
+			continue
+		} else if seen[opt.Ref] {
+			errs.Add(field("ref"), "DuplicateError", "Reference is updated more than once")
+			continue
+		}
+		seen[opt.Ref] = true
+
+		if opt.SHA == "" && opt.OldSHA == "" {
+			errs.Add(field("sha"), binding.ERR_REQUIRED, "Either new or old SHA is required")
+			continue
+		}
+
+		updates[i] = gitutil.RefUpdate{
+			Ref:   opt.Ref,
+			OldID: opt.OldSHA,
+		}
+		if opt.SHA == "" {
+			continue
+		}
+
+		// Branches must point to commits, while tags may point to tag objects.
+		if strings.HasPrefix(opt.Ref, git.RefsHeads) {
+			commit, err := gitRepo.CatFileCommit(opt.SHA)
+			if err != nil {
+				errs.Add(field("sha"), "CommitNotExistError", "Commit does not exist")
+				continue
+			}
+			updates[i].NewID = commit.ID.String()
+		} else {
+			id, err := gitRepo.RevParse(opt.SHA)
+			if err != nil {
+				errs.Add(field("sha"), "ObjectNotExistError", "Object does not exist")
+				continue
+			}
+			updates[i].NewID = id
+		}
+	}
+	return updates, errs
+}
+
+// toRefPushes converts updates of references to be checked as pushes, which
+// are subject to the same rules as pushes over Git.
+func toRefPushes(updates []gitutil.RefUpdate) []db.RefPush {
+	refs := make([]db.RefPush, len(updates))
+	for i, u := range updates {
+		refs[i] = db.RefPush{
+			RefName:     u.Ref,
+			OldCommitID: u.OldID,
+			NewCommitID: u.NewID,
+		}
+		if refs[i].OldCommitID == "" {
+			refs[i].OldCommitID = git.EmptyID
+		}
+		if refs[i].NewCommitID == "" {
+			refs[i].NewCommitID = git.EmptyID
+		}
+	}
+	return refs
+}
+
+// UpdateRefs updates multiple references of the repository atomically, either
+// all of them are updated or none of them is.
+func UpdateRefs(c *context.APIContext, opt UpdateRefsOption) {
+	repo := c.Repo.Repository
+	if repo.IsMirror {
+		c.ErrorStatus(http.StatusForbidden, errors.New("Mirror repository is read-only."))
+		return
+	}
+
+	gitRepo, err := git.Open(repo.RepoPath())
+	if err != nil {
+		c.Error(err, "open repository")
+		return
+	}
+
+	updates, errs := validateRefUpdates(gitRepo, opt.Updates)
+	if errs.Len() > 0 {
+		c.JSON(http.StatusUnprocessableEntity, errs)
+		return
+	}
+
+	for _, u := range updates {
+		currentID, err := gitRepo.ShowRefVerify(u.Ref)
+		if err != nil && err != git.ErrReferenceNotExist {
+			c.Error(err, "get reference")
+			return
+		}
+		if currentID != u.OldID {
+			c.ErrorStatus(http.StatusConflict, errors.Errorf("Reference %q is at %q but expected %q.", u.Ref, currentID, u.OldID))
+			return
+		}
+	}
+
+	err = db.CheckPush(c.Req.Context(), repo, c.User.ID, toRefPushes(updates))
+	if err != nil {
+		if db.IsErrPushRejected(err) {
+			c.ErrorStatus(http.StatusForbidden, err)
+		} else {
+			c.Error(err, "check push")
+		}
+		return
+	}
+
+	if err = gitutil.UpdateRefs(repo.RepoPath(), updates...); err != nil {
+		if gitutil.IsErrRefsChanged(err) {
+			c.ErrorStatus(http.StatusConflict, err)
+		} else {
+			c.Error(err, "update references")
+		}
+		return
+	}
+
+	results := make([]*refUpdate, len(updates))
+	for i, u := range updates {
+		results[i] = &refUpdate{
+			Ref:    u.Ref,
+			SHA:    u.NewID,
+			OldSHA: u.OldID,
+		}
+
+		oldID, newID := u.OldID, u.NewID
+		if oldID == "" {
+			oldID = git.EmptyID
+		}
+		if newID == "" {
+			newID = git.EmptyID
+		}
+		err = db.PushUpdate(db.PushUpdateOptions{
+			OldCommitID:  oldID,
+			NewCommitID:  newID,
+			FullRefspec:  u.Ref,
+			PusherID:     c.User.ID,
+			PusherName:   c.User.Name,
+			RepoUserName: c.Repo.Owner.Name,
+			RepoName:     repo.Name,
+		})
+		if err != nil {
+			log.Error("Failed to update after pushing %q [repo_id: %d]: %v", u.Ref, repo.ID, err)
+		}
+
+		if strings.HasPrefix(u.Ref, git.RefsHeads) && u.NewID != "" {
+			go db.AddTestPullRequestTask(c.User, repo.ID, strings.TrimPrefix(u.Ref, git.RefsHeads), true)
+		}
+	}
+	go db.HookQueue.Add(repo.ID)
+
+	c.JSONSuccess(results)
+}
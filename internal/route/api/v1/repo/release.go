@@ -0,0 +1,91 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/antivirus"
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+// UploadReleaseAttachment uploads the file of the multipart form field
+// "attachment" as an attachment of the release.
+func UploadReleaseAttachment(c *context.APIContext) {
+	if !conf.Release.Attachment.Enabled {
+		c.NotFound()
+		return
+	}
+
+	release, err := db.GetReleaseByID(c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get release by ID")
+		return
+	} else if release.RepoID != c.Repo.Repository.ID {
+		c.NotFound()
+		return
+	}
+
+	file, header, err := c.Req.FormFile("attachment")
+	if err != nil {
+		c.ErrorStatus(http.StatusBadRequest, errors.Wrap(err, "get file"))
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	if header.Size > conf.Release.Attachment.MaxSize<<20 {
+		c.ErrorStatus(http.StatusRequestEntityTooLarge, fmt.Errorf("file size exceeds the limit of %d MB", conf.Release.Attachment.MaxSize))
+		return
+	}
+
+	buf := make([]byte, 1024)
+	n, _ := file.Read(buf)
+	buf = buf[:n]
+	fileType := http.DetectContentType(buf)
+
+	allowed := false
+	for _, t := range conf.Release.Attachment.AllowedTypes {
+		t := strings.TrimSpace(t)
+		if t == "*/*" || t == fileType {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		c.ErrorStatus(http.StatusUnprocessableEntity, fmt.Errorf("file type %q is not allowed", fileType))
+		return
+	}
+
+	err = antivirus.Scan(c.Req.Context(), antivirus.KindReleaseAttachment, header.Filename, "user "+c.User.Name, file)
+	if err != nil {
+		if antivirus.IsErrInfected(err) {
+			c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		} else {
+			c.Error(err, "scan file")
+		}
+		return
+	}
+
+	attach, err := db.NewReleaseAttachment(release.ID, header.Filename, buf, file)
+	if err != nil {
+		c.Error(err, "new release attachment")
+		return
+	}
+
+	log.Trace("New attachment uploaded to release [id: %d]: %s", release.ID, attach.UUID)
+	c.JSON(http.StatusCreated, map[string]interface{}{
+		"id":                   attach.ID,
+		"uuid":                 attach.UUID,
+		"name":                 attach.Name,
+		"browser_download_url": fmt.Sprintf("%s/releases/%d/attachments/%s", c.Repo.Repository.HTMLURL(), release.ID, attach.UUID),
+	})
+}
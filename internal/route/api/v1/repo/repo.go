@@ -6,8 +6,14 @@ package repo
 
 import (
 	"net/http"
+	"net/url"
 	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/go-macaron/binding"
+	"github.com/gogs/git-module"
 	api "github.com/gogs/go-gogs-client"
 	"github.com/pkg/errors"
 	log "unknwon.dev/clog/v2"
@@ -47,6 +53,19 @@ func Search(c *context.APIContext) {
 		}
 	}
 
+	if conf.Security.EnableTenantIsolation && !opts.Private && !(c.IsLogged && c.User.IsAdmin) {
+		if !c.IsLogged {
+			c.JSONSuccess(map[string]interface{}{
+				"ok":   true,
+				"data": []*api.Repository{},
+			})
+			return
+		}
+		opts.UserID = c.User.ID
+		opts.IsolateTenant = true
+		opts.Tenant = c.User.Tenant
+	}
+
 	repos, count, err := db.SearchRepositoryByName(opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, map[string]interface{}{
@@ -77,10 +96,13 @@ func Search(c *context.APIContext) {
 }
 
 func listUserRepositories(c *context.APIContext, username string) {
-	user, err := db.GetUserByName(username)
+	user, err := db.Users.GetByUsername(c.Req.Context(), username)
 	if err != nil {
 		c.NotFoundOrError(err, "get user by name")
 		return
+	} else if !c.IsTenantVisible(user) {
+		c.NotFound()
+		return
 	}
 
 	// Only list public repositories if user requests someone else's repository list,
@@ -153,7 +175,14 @@ func ListOrgRepositories(c *context.APIContext) {
 	listUserRepositories(c, c.Params(":org"))
 }
 
-func CreateUserRepo(c *context.APIContext, owner *db.User, opt api.CreateRepoOption) {
+// CreateRepoOption extends options provided by the API client to create a
+// repository with its initial topics.
+type CreateRepoOption struct {
+	api.CreateRepoOption
+	Topics []string `json:"topics"`
+}
+
+func CreateUserRepo(c *context.APIContext, owner *db.User, opt CreateRepoOption) {
 	repo, err := db.CreateRepository(c.User, owner, db.CreateRepoOptionsLegacy{
 		Name:        opt.Name,
 		Description: opt.Description,
@@ -162,10 +191,13 @@ func CreateUserRepo(c *context.APIContext, owner *db.User, opt api.CreateRepoOpt
 		Readme:      opt.Readme,
 		IsPrivate:   opt.Private,
 		AutoInit:    opt.AutoInit,
+		Topics:      opt.Topics,
 	})
 	if err != nil {
 		if db.IsErrRepoAlreadyExist(err) ||
-			db.IsErrNameNotAllowed(err) {
+			db.IsErrNameNotAllowed(err) ||
+			db.IsErrRepoTopicInvalid(err) ||
+			db.IsErrRepoNamingConvention(err) {
 			c.ErrorStatus(http.StatusUnprocessableEntity, err)
 		} else {
 			if repo != nil {
@@ -181,7 +213,7 @@ func CreateUserRepo(c *context.APIContext, owner *db.User, opt api.CreateRepoOpt
 	c.JSON(201, repo.APIFormatLegacy(&api.Permission{Admin: true, Push: true, Pull: true}))
 }
 
-func Create(c *context.APIContext, opt api.CreateRepoOption) {
+func Create(c *context.APIContext, opt CreateRepoOption) {
 	// Shouldn't reach this condition, but just in case.
 	if c.User.IsOrganization() {
 		c.ErrorStatus(http.StatusUnprocessableEntity, errors.New("Not allowed to create repository for organization."))
@@ -190,7 +222,7 @@ func Create(c *context.APIContext, opt api.CreateRepoOption) {
 	CreateUserRepo(c, c.User, opt)
 }
 
-func CreateOrgRepo(c *context.APIContext, opt api.CreateRepoOption) {
+func CreateOrgRepo(c *context.APIContext, opt CreateRepoOption) {
 	org, err := db.GetOrgByName(c.Params(":org"))
 	if err != nil {
 		c.NotFoundOrError(err, "get organization by name")
@@ -265,6 +297,7 @@ func Migrate(c *context.APIContext, f form.MigrateRepo) {
 		IsPrivate:   f.Private || conf.Repository.ForcePrivate,
 		IsMirror:    f.Mirror,
 		RemoteAddr:  remoteAddr,
+		Topics:      strings.Split(f.Topics, ","),
 	})
 	if err != nil {
 		if repo != nil {
@@ -273,7 +306,9 @@ func Migrate(c *context.APIContext, f form.MigrateRepo) {
 			}
 		}
 
-		if db.IsErrReachLimitOfRepo(err) {
+		if db.IsErrReachLimitOfRepo(err) ||
+			db.IsErrRepoTopicInvalid(err) ||
+			db.IsErrRepoNamingConvention(err) {
 			c.ErrorStatus(http.StatusUnprocessableEntity, err)
 		} else {
 			c.Error(errors.New(db.HandleMirrorCredentials(err.Error(), true)), "migrate repository")
@@ -319,6 +354,128 @@ func Get(c *context.APIContext) {
 	}))
 }
 
+// EditRepoOption contains the repository settings that can be changed via
+// the API. Fields left as nil are not changed.
+type EditRepoOption struct {
+	Description    *string `json:"description"`
+	Website        *string `json:"website"`
+	DefaultBranch  *string `json:"default_branch"`
+	EnableIssues   *bool   `json:"enable_issues"`
+	EnableWiki     *bool   `json:"enable_wiki"`
+	EnablePulls    *bool   `json:"enable_pulls"`
+	EnableReleases *bool   `json:"enable_releases"`
+	// Templates of merge and squash commit messages, empty to use the default.
+	MergeMessageTemplate  *string `json:"merge_message_template"`
+	SquashMessageTemplate *string `json:"squash_message_template"`
+}
+
+// validate checks the given options against the repository and returns
+// field-level errors for any invalid value.
+func (opt EditRepoOption) validate(repo *db.Repository, gitRepo *git.Repository) binding.Errors {
+	var errs binding.Errors
+	if opt.Description != nil && utf8.RuneCountInString(*opt.Description) > 512 {
+		errs.Add([]string{"description"}, binding.ERR_MAX_SIZE, "Description must be at most 512 characters")
+	}
+	if opt.Website != nil && *opt.Website != "" {
+		if len(*opt.Website) > 100 {
+			errs.Add([]string{"website"}, binding.ERR_MAX_SIZE, "Website must be at most 100 characters")
+		} else if u, err := url.Parse(*opt.Website); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs.Add([]string{"website"}, binding.ERR_URL, "Website must be a valid HTTP or HTTPS URL")
+		}
+	}
+	if opt.DefaultBranch != nil {
+		if *opt.DefaultBranch == "" {
+			errs.Add([]string{"default_branch"}, binding.ERR_REQUIRED, "Default branch cannot be empty")
+		} else if gitRepo == nil || !gitRepo.HasBranch(*opt.DefaultBranch) {
+			errs.Add([]string{"default_branch"}, "BranchNotExistError", "Default branch does not exist")
+		}
+	}
+	if opt.MergeMessageTemplate != nil && len(*opt.MergeMessageTemplate) > 4096 {
+		errs.Add([]string{"merge_message_template"}, binding.ERR_MAX_SIZE, "Merge message template must be at most 4096 characters")
+	}
+	if opt.SquashMessageTemplate != nil && len(*opt.SquashMessageTemplate) > 4096 {
+		errs.Add([]string{"squash_message_template"}, binding.ERR_MAX_SIZE, "Squash message template must be at most 4096 characters")
+	}
+	if opt.EnablePulls != nil && *opt.EnablePulls && !repo.CanEnablePulls() {
+		errs.Add([]string{"enable_pulls"}, "NotAllowedError", "Pull requests cannot be enabled for mirrors, empty or tracker-only repositories")
+	}
+	if opt.EnableReleases != nil && *opt.EnableReleases && repo.IsTrackerOnly {
+		errs.Add([]string{"enable_releases"}, "NotAllowedError", "Releases cannot be enabled for tracker-only repositories")
+	}
+	return errs
+}
+
+func Edit(c *context.APIContext, opt EditRepoOption) {
+	_, repo := parseOwnerAndRepo(c)
+	if c.Written() {
+		return
+	}
+
+	var gitRepo *git.Repository
+	if opt.DefaultBranch != nil && !repo.IsBare {
+		var err error
+		gitRepo, err = git.Open(repo.RepoPath())
+		if err != nil {
+			c.Error(err, "open repository")
+			return
+		}
+	}
+
+	if errs := opt.validate(repo, gitRepo); errs.Len() > 0 {
+		c.JSON(http.StatusUnprocessableEntity, errs)
+		return
+	}
+
+	if opt.Description != nil {
+		repo.Description = *opt.Description
+	}
+	if opt.Website != nil {
+		repo.Website = *opt.Website
+	}
+	if opt.EnableIssues != nil {
+		repo.EnableIssues = *opt.EnableIssues
+	}
+	if opt.EnableWiki != nil {
+		repo.EnableWiki = *opt.EnableWiki
+		if !repo.EnableWiki {
+			repo.AllowPublicWiki = false
+		}
+	}
+	if opt.EnablePulls != nil {
+		repo.EnablePulls = *opt.EnablePulls
+	}
+	if opt.EnableReleases != nil {
+		repo.EnableReleases = *opt.EnableReleases
+	}
+	if opt.MergeMessageTemplate != nil {
+		repo.MergeMessageTemplate = strings.TrimSpace(*opt.MergeMessageTemplate)
+	}
+	if opt.SquashMessageTemplate != nil {
+		repo.SquashMessageTemplate = strings.TrimSpace(*opt.SquashMessageTemplate)
+	}
+	if opt.DefaultBranch != nil && repo.DefaultBranch != *opt.DefaultBranch {
+		_, err := gitRepo.SymbolicRef(git.SymbolicRefOptions{
+			Ref: git.RefsHeads + *opt.DefaultBranch,
+		})
+		if err != nil {
+			c.Error(err, "set default branch")
+			return
+		}
+		repo.DefaultBranch = *opt.DefaultBranch
+	}
+
+	if err := db.UpdateRepository(repo, false); err != nil {
+		c.Error(err, "update repository")
+		return
+	}
+
+	c.JSONSuccess(repo.APIFormatLegacy(&api.Permission{
+		Admin: c.Repo.IsAdmin(),
+		Push:  c.Repo.IsWriter(),
+		Pull:  true,
+	}))
+}
+
 func Delete(c *context.APIContext) {
 	owner, repo := parseOwnerAndRepo(c)
 	if c.Written() {
@@ -330,7 +487,12 @@ func Delete(c *context.APIContext) {
 		return
 	}
 
-	if err := db.DeleteRepository(owner.ID, repo.ID); err != nil {
+	repo.Owner = owner
+	if conf.API.RequireDeleteConfirmation && !confirmDelete(c, repo) {
+		return
+	}
+
+	if err := db.RemoveRepository(c.User, owner.ID, repo.ID); err != nil {
 		c.Error(err, "delete repository")
 		return
 	}
@@ -339,6 +501,45 @@ func Delete(c *context.APIContext) {
 	c.NoContent()
 }
 
+// DeleteConfirmation is the response of deleting a repository without
+// confirmation.
+type DeleteConfirmation struct {
+	Message   string    `json:"message"`
+	Token     string    `json:"confirmation_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// confirmDelete returns true if deleting the repository is confirmed by either
+// a confirmation token issued by a prior request or the full name of the
+// repository. Otherwise, it responds a new confirmation token and returns
+// false.
+func confirmDelete(c *context.APIContext, repo *db.Repository) bool {
+	if name := c.Req.Header.Get("X-Gogs-Confirm-Repo"); name != "" {
+		if !repo.IsDeleteConfirmationName(name) {
+			c.ErrorStatus(http.StatusUnprocessableEntity, errors.New("Confirmation name does not match the full name of the repository."))
+			return false
+		}
+		return true
+	}
+
+	if token := c.Req.Header.Get("X-Gogs-Confirm-Token"); token != "" {
+		if !repo.VerifyDeleteConfirmationCode(c.User, token) {
+			c.ErrorStatus(http.StatusUnprocessableEntity, errors.New("Confirmation token is invalid or has expired."))
+			return false
+		}
+		return true
+	}
+
+	// Time limit codes start at the beginning of the current minute.
+	lives := time.Duration(conf.API.DeleteConfirmationLiveMinutes) * time.Minute
+	c.JSON(http.StatusPreconditionRequired, &DeleteConfirmation{
+		Message:   `Send the confirmation token in the header "X-Gogs-Confirm-Token" to delete the repository.`,
+		Token:     repo.GenerateDeleteConfirmationCode(c.User),
+		ExpiresAt: time.Now().Truncate(time.Minute).Add(lives),
+	})
+	return false
+}
+
 func ListForks(c *context.APIContext) {
 	forks, err := c.Repo.Repository.GetForks()
 	if err != nil {
@@ -0,0 +1,24 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"time"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/route/api/v1/convert"
+)
+
+// GetReviewLatency returns median and 90th percentile time to first review and
+// time to merge of pull requests of the repository over rolling windows.
+func GetReviewLatency(c *context.APIContext) {
+	latencies, err := db.GetRepoReviewLatencies(c.Repo.Repository.ID, time.Now())
+	if err != nil {
+		c.Error(err, "get review latencies")
+		return
+	}
+	c.JSONSuccess(convert.ToReviewLatencies(latencies))
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/route/repo"
+)
+
+type searchFileContent struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line"`
+	Content string `json:"content"`
+}
+
+type searchIssue struct {
+	Number  int64     `json:"number"`
+	Title   string    `json:"title"`
+	State   string    `json:"state"`
+	HTMLURL string    `json:"html_url"`
+	Updated time.Time `json:"updated_at"`
+}
+
+type searchCommit struct {
+	SHA     string    `json:"sha"`
+	Message string    `json:"message"`
+	Author  string    `json:"author"`
+	Date    time.Time `json:"date"`
+	HTMLURL string    `json:"html_url"`
+}
+
+type searchResults struct {
+	Keyword      string               `json:"keyword"`
+	Branch       string               `json:"branch"`
+	FileNames    []string             `json:"file_names"`
+	FileContents []*searchFileContent `json:"file_contents"`
+	Issues       []*searchIssue       `json:"issues"`
+	Pulls        []*searchIssue       `json:"pulls"`
+	Commits      []*searchCommit      `json:"commits"`
+}
+
+func toSearchIssues(repository *db.Repository, issues []*db.Issue) []*searchIssue {
+	apiIssues := make([]*searchIssue, len(issues))
+	for i, issue := range issues {
+		apiIssues[i] = &searchIssue{
+			Number:  issue.Index,
+			Title:   issue.Title,
+			State:   string(issue.State()),
+			Updated: issue.Updated,
+		}
+		if issue.IsPull {
+			apiIssues[i].HTMLURL = repository.HTMLURL() + "/pulls/" + strconv.FormatInt(issue.Index, 10)
+		} else {
+			apiIssues[i].HTMLURL = repository.HTMLURL() + "/issues/" + strconv.FormatInt(issue.Index, 10)
+		}
+	}
+	return apiIssues
+}
+
+// SearchRepository searches file names, file contents (when enabled), issues,
+// pull requests and commit messages of the repository with the keyword given
+// by the "q" query parameter, and returns results grouped by their kinds.
+func SearchRepository(c *context.APIContext) {
+	keyword := c.Query("q")
+	if keyword == "" {
+		c.ErrorStatus(http.StatusUnprocessableEntity, errors.New("empty keyword"))
+		return
+	}
+
+	results, err := repo.SearchRepository(c.Context, keyword)
+	if err != nil {
+		c.NotFoundOrError(err, "search repository")
+		return
+	}
+
+	repository := c.Repo.Repository
+	apiResults := &searchResults{
+		Keyword:      results.Keyword,
+		Branch:       results.Branch,
+		FileNames:    results.FileNames,
+		FileContents: make([]*searchFileContent, len(results.FileContents)),
+		Issues:       toSearchIssues(repository, results.Issues),
+		Pulls:        toSearchIssues(repository, results.Pulls),
+		Commits:      make([]*searchCommit, len(results.Commits)),
+	}
+	if apiResults.FileNames == nil {
+		apiResults.FileNames = []string{}
+	}
+	for i, result := range results.FileContents {
+		apiResults.FileContents[i] = &searchFileContent{
+			Path:    result.Path,
+			Line:    result.Line,
+			Content: result.Content,
+		}
+	}
+	for i, commit := range results.Commits {
+		apiResults.Commits[i] = &searchCommit{
+			SHA:     commit.ID.String(),
+			Message: commit.Message,
+			Author:  commit.Author.Name,
+			Date:    commit.Author.When,
+			HTMLURL: repository.HTMLURL() + "/commit/" + commit.ID.String(),
+		}
+	}
+	c.JSONSuccess(apiResults)
+}
+
+type indexedCommit struct {
+	SHA            string    `json:"sha"`
+	Message        string    `json:"message"`
+	AuthorName     string    `json:"author_name"`
+	AuthorEmail    string    `json:"author_email"`
+	AuthoredAt     time.Time `json:"authored_at"`
+	CommitterName  string    `json:"committer_name"`
+	CommitterEmail string    `json:"committer_email"`
+	CommittedAt    time.Time `json:"committed_at"`
+	HTMLURL        string    `json:"html_url"`
+}
+
+// SearchCommits searches indexed commits of the repository by the "q",
+// "author", "committer", "since" and "until" query parameters, and returns a
+// page of matched commits given by the "page" and "limit" query parameters.
+func SearchCommits(c *context.APIContext) {
+	opts, err := repo.ParseSearchCommitsOptions(c.Context)
+	if err != nil {
+		c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		return
+	}
+	opts.Page = c.QueryInt("page")
+	if opts.Page <= 0 {
+		opts.Page = 1
+	}
+	opts.PageSize = c.QueryInt("limit")
+	if opts.PageSize <= 0 || opts.PageSize > 50 {
+		opts.PageSize = 30
+	}
+
+	commits, count, err := repo.SearchIndexedCommits(c.Context, opts)
+	if err != nil {
+		c.Error(err, "search indexed commits")
+		return
+	}
+
+	repository := c.Repo.Repository
+	apiCommits := make([]*indexedCommit, len(commits))
+	for i, commit := range commits {
+		apiCommits[i] = &indexedCommit{
+			SHA:            commit.SHA,
+			Message:        commit.Message,
+			AuthorName:     commit.AuthorName,
+			AuthorEmail:    commit.AuthorEmail,
+			AuthoredAt:     commit.AuthoredAt,
+			CommitterName:  commit.CommitterName,
+			CommitterEmail: commit.CommitterEmail,
+			CommittedAt:    commit.CommittedAt,
+			HTMLURL:        repository.HTMLURL() + "/commit/" + commit.SHA,
+		}
+	}
+	c.JSONSuccess(map[string]interface{}{
+		"total_count": count,
+		"commits":     apiCommits,
+	})
+}
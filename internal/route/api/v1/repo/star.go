@@ -0,0 +1,66 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+// IsStarring responds with 204 if the user has starred the repository, or
+// 404 otherwise.
+func IsStarring(c *context.APIContext) {
+	if !db.IsStaring(c.User.ID, c.Repo.Repository.ID) {
+		c.NotFound()
+		return
+	}
+	c.NoContent()
+}
+
+// Star stars the repository for the user.
+func Star(c *context.APIContext) {
+	if err := db.StarRepository(c.User, c.Repo.Repository, true); err != nil {
+		c.Error(err, "star repository")
+		return
+	}
+	c.NoContent()
+}
+
+// Unstar unstars the repository for the user.
+func Unstar(c *context.APIContext) {
+	if err := db.StarRepository(c.User, c.Repo.Repository, false); err != nil {
+		c.Error(err, "unstar repository")
+		return
+	}
+	c.NoContent()
+}
+
+// IsWatching responds with 204 if the user is watching the repository, or 404
+// otherwise.
+func IsWatching(c *context.APIContext) {
+	if !db.IsWatching(c.User.ID, c.Repo.Repository.ID) {
+		c.NotFound()
+		return
+	}
+	c.NoContent()
+}
+
+// Watch watches the repository for the user.
+func Watch(c *context.APIContext) {
+	if err := db.WatchRepository(c.User, c.Repo.Repository, true); err != nil {
+		c.Error(err, "watch repository")
+		return
+	}
+	c.NoContent()
+}
+
+// Unwatch unwatches the repository for the user.
+func Unwatch(c *context.APIContext) {
+	if err := db.WatchRepository(c.User, c.Repo.Repository, false); err != nil {
+		c.Error(err, "unwatch repository")
+		return
+	}
+	c.NoContent()
+}
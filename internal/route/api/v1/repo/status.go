@@ -0,0 +1,162 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gogs/git-module"
+	"github.com/pkg/errors"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/gitutil"
+	"gogs.io/gogs/internal/route/repo"
+)
+
+type CreateStatusOption struct {
+	State       string `json:"state" binding:"Required"`
+	TargetURL   string `json:"target_url"`
+	Description string `json:"description"`
+	Context     string `json:"context"`
+}
+
+type commitStatus struct {
+	ID          int64     `json:"id"`
+	State       string    `json:"state"`
+	TargetURL   string    `json:"target_url"`
+	Description string    `json:"description"`
+	Context     string    `json:"context"`
+	CreatorID   int64     `json:"creator_id"`
+	Created     time.Time `json:"created_at"`
+}
+
+func toCommitStatus(s *db.CommitStatus) *commitStatus {
+	return &commitStatus{
+		ID:          s.ID,
+		State:       string(s.State),
+		TargetURL:   s.TargetURL,
+		Description: s.Description,
+		Context:     s.Context,
+		CreatorID:   s.CreatorID,
+		Created:     s.CreatedAt,
+	}
+}
+
+// resolveCommitSHA returns the full SHA of the commit identified by the ":sha"
+// parameter.
+func resolveCommitSHA(c *context.APIContext) (string, bool) {
+	return resolveRevision(c, c.Params(":sha"))
+}
+
+// resolveRevision returns the full SHA of the commit identified by the
+// revision, e.g. a branch, a tag or a commit SHA.
+func resolveRevision(c *context.APIContext, rev string) (string, bool) {
+	gitRepo, err := git.Open(c.Repo.Repository.RepoPath())
+	if err != nil {
+		c.Error(err, "open repository")
+		return "", false
+	}
+	commit, err := gitRepo.CatFileCommit(rev)
+	if err != nil {
+		c.NotFoundOrError(gitutil.NewError(err), "get commit")
+		return "", false
+	}
+	return commit.ID.String(), true
+}
+
+// ListStatuses returns the latest status of each context for the commit.
+func ListStatuses(c *context.APIContext) {
+	sha, ok := resolveCommitSHA(c)
+	if !ok {
+		return
+	}
+
+	statuses, err := db.CommitStatuses.ListLatest(c.Req.Context(), c.Repo.Repository.ID, sha)
+	if err != nil {
+		c.Error(err, "list commit statuses")
+		return
+	}
+
+	apiStatuses := make([]*commitStatus, len(statuses))
+	for i := range statuses {
+		apiStatuses[i] = toCommitStatus(statuses[i])
+	}
+	c.JSONSuccess(apiStatuses)
+}
+
+// CreateStatus creates a new status for the commit, and triggers processing of
+// merge queues when the commit is a speculative merge commit.
+func CreateStatus(c *context.APIContext, form CreateStatusOption) {
+	state := db.CommitStatusState(form.State)
+	if !state.IsValid() {
+		c.ErrorStatus(http.StatusUnprocessableEntity, errors.Errorf("invalid state %q", form.State))
+		return
+	}
+
+	sha, ok := resolveCommitSHA(c)
+	if !ok {
+		return
+	}
+
+	s, err := db.CommitStatuses.Create(c.Req.Context(), c.Repo.Repository.ID, c.User.ID, sha, db.CreateCommitStatusOptions{
+		State:       state,
+		TargetURL:   form.TargetURL,
+		Description: form.Description,
+		Context:     form.Context,
+	})
+	if err != nil {
+		c.Error(err, "create commit status")
+		return
+	}
+
+	entries, err := db.MergeQueueEntries.ListBySpeculativeCommit(c.Req.Context(), c.Repo.Repository.ID, sha)
+	if err != nil {
+		log.Error("Failed to list merge queue entries by speculative commit %q: %v", sha, err)
+	}
+	for _, e := range entries {
+		go db.AddMergeQueueTask(e.RepoID, e.BaseBranch)
+	}
+
+	c.JSON(http.StatusCreated, toCommitStatus(s))
+}
+
+type branchStatus struct {
+	Branch string `json:"branch"`
+	SHA    string `json:"sha"`
+	// State is "unknown" when the commit has no status.
+	State      string          `json:"state"`
+	TotalCount int             `json:"total_count"`
+	Statuses   []*commitStatus `json:"statuses"`
+}
+
+// GetBranchStatus returns the combined status of the latest commit on the
+// branch given by the "branch" query parameter, or the default branch of the
+// repository.
+func GetBranchStatus(c *context.APIContext) {
+	status, err := repo.GetBranchStatus(c.Context, c.Query("branch"))
+	if err != nil {
+		c.NotFoundOrError(err, "get branch status")
+		return
+	}
+
+	state := string(status.State)
+	if state == "" {
+		state = "unknown"
+	}
+	apiStatuses := make([]*commitStatus, len(status.Statuses))
+	for i := range status.Statuses {
+		apiStatuses[i] = toCommitStatus(status.Statuses[i])
+	}
+	c.JSONSuccess(&branchStatus{
+		Branch:     status.Branch,
+		SHA:        status.SHA,
+		State:      state,
+		TotalCount: len(apiStatuses),
+		Statuses:   apiStatuses,
+	})
+}
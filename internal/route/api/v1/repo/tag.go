@@ -5,7 +5,10 @@
 package repo
 
 import (
+	"github.com/gogs/git-module"
+
 	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/gitutil"
 	"gogs.io/gogs/internal/route/api/v1/convert"
 )
 
@@ -16,15 +19,47 @@ func ListTags(c *context.APIContext) {
 		return
 	}
 
+	gitRepo, err := git.Open(c.Repo.Repository.RepoPath())
+	if err != nil {
+		c.Error(err, "open repository")
+		return
+	}
+
 	apiTags := make([]*convert.Tag, len(tags))
 	for i := range tags {
-		commit, err := tags[i].GetCommit()
+		apiTags[i], err = toAPITag(gitRepo, tags[i].Name)
 		if err != nil {
-			c.Error(err, "get commit")
+			c.Error(err, "convert tag")
 			return
 		}
-		apiTags[i] = convert.ToTag(tags[i], commit)
 	}
 
 	c.JSONSuccess(&apiTags)
 }
+
+func GetTag(c *context.APIContext) {
+	gitRepo, err := git.Open(c.Repo.Repository.RepoPath())
+	if err != nil {
+		c.Error(err, "open repository")
+		return
+	}
+
+	apiTag, err := toAPITag(gitRepo, c.Params("*"))
+	if err != nil {
+		c.NotFoundOrError(gitutil.NewError(err), "convert tag")
+		return
+	}
+	c.JSONSuccess(apiTag)
+}
+
+func toAPITag(gitRepo *git.Repository, name string) (*convert.Tag, error) {
+	tag, err := gitRepo.Tag(name)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := tag.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return convert.ToTag(gitRepo.Path(), name, tag, commit), nil
+}
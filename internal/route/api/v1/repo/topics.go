@@ -0,0 +1,35 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+type TopicsOption struct {
+	Topics []string `json:"topics"`
+}
+
+// ListTopics returns topics of the repository.
+func ListTopics(c *context.APIContext) {
+	c.JSONSuccess(&TopicsOption{Topics: c.Repo.Repository.TopicList()})
+}
+
+// ReplaceTopics replaces topics of the repository.
+func ReplaceTopics(c *context.APIContext, form TopicsOption) {
+	repo := c.Repo.Repository
+	if err := db.SetRepoTopics(c.User, repo, form.Topics); err != nil {
+		if db.IsErrRepoTopicInvalid(err) {
+			c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		} else {
+			c.Error(err, "set repository topics")
+		}
+		return
+	}
+	c.JSONSuccess(&TopicsOption{Topics: repo.TopicList()})
+}
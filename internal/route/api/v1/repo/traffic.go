@@ -0,0 +1,68 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+type trafficDay struct {
+	Date           string `json:"date"`
+	Views          int64  `json:"views"`
+	UniqueVisitors int64  `json:"unique_visitors"`
+	Clones         int64  `json:"clones"`
+	UniqueCloners  int64  `json:"unique_cloners"`
+}
+
+type traffic struct {
+	Views  int64         `json:"views"`
+	Clones int64         `json:"clones"`
+	Days   []*trafficDay `json:"days"`
+}
+
+// maxTrafficDays is the maximum number of days of traffic that can be requested.
+const maxTrafficDays = 90
+
+// GetTraffic returns daily views and clones of the repository in the past days
+// given by the query parameter "days", which defaults to 14. Dates are in UTC.
+func GetTraffic(c *context.APIContext) {
+	days := c.QueryInt("days")
+	if days == 0 {
+		days = 14
+	} else if days < 0 || days > maxTrafficDays {
+		c.ErrorStatus(http.StatusUnprocessableEntity, errors.Errorf("days must be between 1 and %d", maxTrafficDays))
+		return
+	}
+
+	now := time.Now()
+	since := now.UTC().AddDate(0, 0, 1-days).Format(db.RepoTrafficDateFormat)
+	traffics, err := db.RepoTraffics.ListByRepo(c.Req.Context(), c.Repo.Repository.ID, since)
+	if err != nil {
+		c.Error(err, "list repository traffic")
+		return
+	}
+
+	apiTraffic := &traffic{
+		Days: make([]*trafficDay, 0, days),
+	}
+	for _, t := range db.FillRepoTraffic(traffics, now, days) {
+		apiTraffic.Views += t.Views
+		apiTraffic.Clones += t.Clones
+		apiTraffic.Days = append(apiTraffic.Days, &trafficDay{
+			Date:           t.Date,
+			Views:          t.Views,
+			UniqueVisitors: t.UniqueVisitors,
+			Clones:         t.Clones,
+			UniqueCloners:  t.UniqueCloners,
+		})
+	}
+	c.JSONSuccess(apiTraffic)
+}
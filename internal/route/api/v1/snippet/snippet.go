@@ -0,0 +1,332 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package snippet
+
+import (
+	"net/http"
+	"time"
+
+	api "github.com/gogs/go-gogs-client"
+	"github.com/pkg/errors"
+	"gopkg.in/macaron.v1"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/tool"
+)
+
+type snippetFile struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+type snippet struct {
+	Name       string         `json:"name"`
+	Title      string         `json:"title"`
+	Visibility string         `json:"visibility"`
+	Owner      *api.User      `json:"owner"`
+	HTMLURL    string         `json:"html_url"`
+	Created    time.Time      `json:"created_at"`
+	Updated    time.Time      `json:"updated_at"`
+	Files      []*snippetFile `json:"files,omitempty"`
+}
+
+func toSnippet(s *db.Snippet, owner *db.User, files []*db.SnippetFile) *snippet {
+	apiSnippet := &snippet{
+		Name:       s.Name,
+		Title:      s.Title,
+		Visibility: string(s.Visibility),
+		Owner:      owner.APIFormat(),
+		HTMLURL:    conf.Server.ExternalURL + "snippets/" + s.Name,
+		Created:    s.CreatedAt,
+		Updated:    s.UpdatedAt,
+	}
+	for _, f := range files {
+		apiSnippet.Files = append(apiSnippet.Files, &snippetFile{
+			Name:    f.Name,
+			Content: string(f.Content),
+		})
+	}
+	return apiSnippet
+}
+
+func toSnippetFiles(files []*snippetFile) []*db.SnippetFile {
+	dbFiles := make([]*db.SnippetFile, len(files))
+	for i, f := range files {
+		dbFiles[i] = &db.SnippetFile{
+			Name:    f.Name,
+			Content: []byte(f.Content),
+		}
+	}
+	return dbFiles
+}
+
+// MustEnable responds 404 when snippets are disabled.
+func MustEnable(c *context.APIContext) {
+	if !conf.Snippet.Enabled {
+		c.NotFound()
+		return
+	}
+}
+
+// Assignment loads the snippet from the URL and responds 404 when the user is
+// not allowed to view it.
+func Assignment() macaron.Handler {
+	return func(c *context.APIContext) {
+		s, err := db.Snippets.GetByName(c.Req.Context(), c.Params(":name"))
+		if err != nil {
+			c.NotFoundOrError(err, "get snippet by name")
+			return
+		} else if !s.IsReadableBy(c.User) {
+			c.NotFound()
+			return
+		}
+
+		owner, err := db.GetUserByID(s.OwnerID)
+		if err != nil {
+			c.NotFoundOrError(err, "get owner")
+			return
+		}
+		c.Map(s)
+		c.Data["SnippetOwner"] = owner
+	}
+}
+
+// List returns public snippets, or snippets of the owner given by the "owner"
+// query parameter including secret and private ones to those who can write
+// snippets of the owner.
+func List(c *context.APIContext) {
+	opts := db.ListSnippetsOptions{
+		Visibilities: []db.SnippetVisibility{db.SnippetPublic},
+		Page:         c.QueryInt("page"),
+		PageSize:     conf.UI.ExplorePagingNum,
+	}
+	if ownerName := c.Query("owner"); ownerName != "" {
+		owner, err := db.GetUserByName(ownerName)
+		if err != nil {
+			c.NotFoundOrError(err, "get owner")
+			return
+		}
+		opts.OwnerID = owner.ID
+		if db.CanWriteSnippets(owner.ID, c.User) {
+			opts.Visibilities = nil
+		}
+	}
+
+	if conf.Security.EnableTenantIsolation && !(c.IsLogged && c.User.IsAdmin) &&
+		(opts.OwnerID == 0 || !db.CanWriteSnippets(opts.OwnerID, c.User)) {
+		c.JSONSuccess([]*snippet{})
+		return
+	}
+
+	snippets, count, err := db.Snippets.List(c.Req.Context(), opts)
+	if err != nil {
+		c.Error(err, "list snippets")
+		return
+	}
+
+	owners := make(map[int64]*db.User)
+	apiSnippets := make([]*snippet, len(snippets))
+	for i, s := range snippets {
+		owner, ok := owners[s.OwnerID]
+		if !ok {
+			owner, err = db.GetUserByID(s.OwnerID)
+			if err != nil {
+				c.Error(err, "get owner")
+				return
+			}
+			owners[s.OwnerID] = owner
+		}
+		apiSnippets[i] = toSnippet(s, owner, nil)
+	}
+
+	c.SetLinkHeader(int(count), opts.PageSize)
+	c.JSONSuccess(apiSnippets)
+}
+
+type CreateSnippetOption struct {
+	// Owner is the name of the user or organization to own the snippet, the
+	// authenticated user when empty.
+	Owner      string         `json:"owner"`
+	Title      string         `json:"title" binding:"MaxSize(255)"`
+	Visibility string         `json:"visibility" binding:"Required;In(public,secret,private)"`
+	Files      []*snippetFile `json:"files"`
+}
+
+// Create creates a new snippet owned by the authenticated user or an
+// organization the user belongs to.
+func Create(c *context.APIContext, form CreateSnippetOption) {
+	owner := c.User
+	if form.Owner != "" && form.Owner != c.User.Name {
+		var err error
+		owner, err = db.GetUserByName(form.Owner)
+		if err != nil {
+			if db.IsErrUserNotExist(err) {
+				c.ErrorStatus(http.StatusUnprocessableEntity, err)
+			} else {
+				c.Error(err, "get owner")
+			}
+			return
+		} else if !db.CanWriteSnippets(owner.ID, c.User) {
+			c.ErrorStatus(http.StatusForbidden, errors.New("not allowed to create snippets for the owner"))
+			return
+		}
+	}
+
+	files := toSnippetFiles(form.Files)
+	s, err := db.CreateSnippet(c.Req.Context(), c.User, owner, db.CreateSnippetOptions{
+		Title:      form.Title,
+		Visibility: db.SnippetVisibility(form.Visibility),
+	}, files)
+	if err != nil {
+		if db.IsErrSnippetInvalid(err) {
+			c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		} else {
+			c.Error(err, "create snippet")
+		}
+		return
+	}
+
+	log.Trace("Snippet created by %q: %s", c.User.Name, s.Name)
+	c.JSON(http.StatusCreated, toSnippet(s, owner, files))
+}
+
+// Get returns the snippet with files of the revision given by the "revision"
+// query parameter, or the latest revision.
+func Get(c *context.APIContext, s *db.Snippet) {
+	files, err := s.Files(c.Query("revision"))
+	if err != nil {
+		c.NotFoundOrError(err, "get files")
+		return
+	}
+	c.JSONSuccess(toSnippet(s, c.Data["SnippetOwner"].(*db.User), files))
+}
+
+type EditSnippetOption struct {
+	Title      *string `json:"title"`
+	Visibility string  `json:"visibility" binding:"OmitEmpty;In(public,secret,private)"`
+	// Files replace all files of the snippet in a new revision when given.
+	Files []*snippetFile `json:"files"`
+}
+
+// Edit updates the snippet, and replaces its files when given.
+func Edit(c *context.APIContext, s *db.Snippet, form EditSnippetOption) {
+	if !db.CanWriteSnippets(s.OwnerID, c.User) {
+		c.Status(http.StatusForbidden)
+		return
+	}
+
+	opts := db.UpdateSnippetOptions{
+		Title:      s.Title,
+		Visibility: s.Visibility,
+	}
+	if form.Title != nil {
+		if len(*form.Title) > 255 {
+			c.ErrorStatus(http.StatusUnprocessableEntity, errors.New("title is too long"))
+			return
+		}
+		opts.Title = *form.Title
+	}
+	if form.Visibility != "" {
+		opts.Visibility = db.SnippetVisibility(form.Visibility)
+	}
+
+	var files []*db.SnippetFile
+	if form.Files != nil {
+		files = toSnippetFiles(form.Files)
+	}
+	if err := db.UpdateSnippet(c.Req.Context(), c.User, s, opts, files); err != nil {
+		if db.IsErrSnippetInvalid(err) {
+			c.ErrorStatus(http.StatusUnprocessableEntity, err)
+		} else {
+			c.Error(err, "update snippet")
+		}
+		return
+	}
+
+	files, err := s.Files("")
+	if err != nil {
+		c.Error(err, "get files")
+		return
+	}
+
+	log.Trace("Snippet updated by %q: %s", c.User.Name, s.Name)
+	c.JSONSuccess(toSnippet(s, c.Data["SnippetOwner"].(*db.User), files))
+}
+
+// Delete deletes the snippet and all of its revisions.
+func Delete(c *context.APIContext, s *db.Snippet) {
+	if !db.CanWriteSnippets(s.OwnerID, c.User) {
+		c.Status(http.StatusForbidden)
+		return
+	}
+
+	if err := db.DeleteSnippet(c.Req.Context(), s); err != nil {
+		c.Error(err, "delete snippet")
+		return
+	}
+
+	log.Trace("Snippet deleted by %q: %s", c.User.Name, s.Name)
+	c.NoContent()
+}
+
+type revision struct {
+	SHA     string    `json:"sha"`
+	Message string    `json:"message"`
+	Author  string    `json:"author"`
+	Created time.Time `json:"created_at"`
+}
+
+// ListRevisions returns revisions of the snippet, the most recent first.
+func ListRevisions(c *context.APIContext, s *db.Snippet) {
+	commits, err := s.Revisions()
+	if err != nil {
+		c.Error(err, "list revisions")
+		return
+	}
+
+	revisions := make([]*revision, len(commits))
+	for i, commit := range commits {
+		revisions[i] = &revision{
+			SHA:     commit.ID.String(),
+			Message: commit.Message,
+			Author:  commit.Author.Name,
+			Created: commit.Author.When,
+		}
+	}
+	c.JSONSuccess(revisions)
+}
+
+// GetRawFile returns the raw content of the file of the snippet at the
+// revision given by the "revision" query parameter, or the latest revision.
+func GetRawFile(c *context.APIContext, s *db.Snippet) {
+	files, err := s.Files(c.Query("revision"))
+	if err != nil {
+		c.NotFoundOrError(err, "get files")
+		return
+	}
+
+	name := c.Params(":filename")
+	for _, f := range files {
+		if f.Name != name {
+			continue
+		}
+
+		if tool.IsTextFile(f.Content) {
+			c.Resp.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		} else {
+			c.Resp.Header().Set("Content-Disposition", "attachment; filename=\""+f.Name+"\"")
+			c.Resp.Header().Set("Content-Transfer-Encoding", "binary")
+		}
+		c.Resp.WriteHeader(http.StatusOK)
+		if _, err = c.Resp.Write(f.Content); err != nil {
+			log.Error("Failed to write raw file of snippet: %v", err)
+		}
+		return
+	}
+	c.NotFound()
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+type contribution struct {
+	Date         string `json:"date"`
+	Commits      int64  `json:"commits"`
+	Issues       int64  `json:"issues"`
+	PullRequests int64  `json:"pull_requests"`
+	Reviews      int64  `json:"reviews"`
+	Total        int64  `json:"total"`
+}
+
+// ListContributions returns daily contributions of the user since the date
+// given by the "since" query parameter, or the first day of the contributions
+// calendar. Days without contributions are omitted.
+func ListContributions(c *context.APIContext) {
+	u := GetUserByParams(c)
+	if c.Written() {
+		return
+	}
+	if u.IsOrganization() {
+		c.NotFound()
+		return
+	} else if !u.CanViewContributions(c.User) {
+		c.ErrorStatus(http.StatusForbidden, errors.New("contributions of the user are not visible"))
+		return
+	}
+
+	since := c.Query("since")
+	if since == "" {
+		since = db.ContributionCalendarSince(time.Now())
+	} else if _, err := time.Parse(db.UserContributionDateFormat, since); err != nil {
+		c.ErrorStatus(http.StatusUnprocessableEntity, errors.New("since must be a date in the format of YYYY-MM-DD"))
+		return
+	}
+
+	contributions, err := u.ListContributionsForViewer(c.Req.Context(), c.User, since)
+	if err != nil {
+		c.Error(err, "list contributions")
+		return
+	}
+
+	apiContributions := make([]*contribution, len(contributions))
+	for i, contrib := range contributions {
+		apiContributions[i] = &contribution{
+			Date:         contrib.Date,
+			Commits:      contrib.Commits,
+			Issues:       contrib.Issues,
+			PullRequests: contrib.PullRequests,
+			Reviews:      contrib.Reviews,
+			Total:        contrib.Total(),
+		}
+	}
+	c.JSONSuccess(apiContributions)
+}
@@ -18,10 +18,13 @@ import (
 )
 
 func GetUserByParamsName(c *context.APIContext, name string) *db.User {
-	user, err := db.GetUserByName(c.Params(name))
+	user, err := db.Users.GetByUsername(c.Req.Context(), c.Params(name))
 	if err != nil {
 		c.NotFoundOrError(err, "get user by name")
 		return nil
+	} else if !c.IsTenantVisible(user) {
+		c.NotFound()
+		return nil
 	}
 	return user
 }
@@ -0,0 +1,96 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+func profileFieldScope(u *db.User) db.ProfileFieldScope {
+	if u.IsOrganization() {
+		return db.ProfileFieldScopeOrganization
+	}
+	return db.ProfileFieldScopeUser
+}
+
+// ListProfileFieldValues responds values of profile fields of the user or
+// organization, keyed by field names.
+func ListProfileFieldValues(c *context.APIContext, u *db.User) {
+	entries, err := db.ListProfileFieldEntries(c.Req.Context(), u.ID, profileFieldScope(u))
+	if err != nil {
+		c.Error(err, "list profile field entries")
+		return
+	}
+
+	values := make(map[string]string, len(entries))
+	for _, e := range entries {
+		values[e.Name] = e.Value
+	}
+	c.JSONSuccess(values)
+}
+
+// EditProfileFieldValues sets values of profile fields of the user or
+// organization from the request body keyed by field names, and responds the
+// updated values. Fields that are not editable are rejected unless all is
+// true.
+func EditProfileFieldValues(c *context.APIContext, u *db.User, all bool) {
+	var form map[string]string
+	if err := jsoniter.NewDecoder(c.Req.Request.Body).Decode(&form); err != nil {
+		c.ErrorStatus(http.StatusUnprocessableEntity, errors.Wrap(err, "decode request body"))
+		return
+	}
+
+	entries, err := db.ListProfileFieldEntries(c.Req.Context(), u.ID, profileFieldScope(u))
+	if err != nil {
+		c.Error(err, "list profile field entries")
+		return
+	}
+	byName := make(map[string]*db.ProfileFieldEntry, len(entries))
+	for _, e := range entries {
+		byName[e.Name] = e
+	}
+
+	values := make(map[int64]string, len(form))
+	for name, value := range form {
+		e, ok := byName[name]
+		if !ok {
+			c.ErrorStatus(http.StatusUnprocessableEntity, errors.Errorf("profile field %q does not exist", name))
+			return
+		} else if !all && !e.Editable {
+			c.ErrorStatus(http.StatusUnprocessableEntity, errors.Errorf("profile field %q is not editable", name))
+			return
+		}
+		values[e.ID] = strings.TrimSpace(value)
+	}
+
+	if err = db.ProfileFields.SetValues(c.Req.Context(), u.ID, values); err != nil {
+		c.Error(err, "set profile field values")
+		return
+	}
+	ListProfileFieldValues(c, u)
+}
+
+func ListProfileFields(c *context.APIContext) {
+	u := GetUserByParams(c)
+	if c.Written() {
+		return
+	}
+	ListProfileFieldValues(c, u)
+}
+
+func ListMyProfileFields(c *context.APIContext) {
+	ListProfileFieldValues(c, c.User)
+}
+
+func EditMyProfileFields(c *context.APIContext) {
+	EditProfileFieldValues(c, c.User, false)
+}
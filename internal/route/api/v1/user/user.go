@@ -11,6 +11,7 @@ import (
 
 	api "github.com/gogs/go-gogs-client"
 
+	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/context"
 	"gogs.io/gogs/internal/db"
 	"gogs.io/gogs/internal/markup"
@@ -26,6 +27,18 @@ func Search(c *context.APIContext) {
 		opts.PageSize = 10
 	}
 
+	if conf.Security.EnableTenantIsolation && !(c.IsLogged && c.User.IsAdmin) {
+		if !c.IsLogged {
+			c.JSONSuccess(map[string]interface{}{
+				"ok":   true,
+				"data": []*api.User{},
+			})
+			return
+		}
+		opts.IsolateTenant = true
+		opts.Tenant = c.User.Tenant
+	}
+
 	users, _, err := db.SearchUserByName(opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, map[string]interface{}{
@@ -59,11 +72,16 @@ func GetInfo(c *context.APIContext) {
 	if err != nil {
 		c.NotFoundOrError(err, "get user by name")
 		return
+	} else if !db.IsTenantVisible(c.User, u) {
+		c.NotFound()
+		return
 	}
 
 	// Hide user e-mail when API caller isn't signed in.
 	if !c.IsLogged {
 		u.Email = ""
+	} else if u.KeepEmailPrivate && c.User.ID != u.ID && !c.User.IsAdmin {
+		u.Email = u.NoReplyEmail()
 	}
 	c.JSONSuccess(u.APIFormat())
 }
@@ -17,7 +17,7 @@ func TemplatePreview(c *context.Context) {
 	c.Data["AppURL"] = conf.Server.ExternalURL
 	c.Data["Code"] = "2014031910370000009fff6782aadb2162b4a997acb69d4400888e0b9274657374"
 	c.Data["ActiveCodeLives"] = conf.Auth.ActivateCodeLives / 60
-	c.Data["ResetPwdCodeLives"] = conf.Auth.ResetPasswordCodeLives / 60
+	c.Data["ResetPwdCodeLives"] = conf.Auth.ResetPasswordCodeLives
 	c.Data["CurDbValue"] = ""
 
 	c.Success(c.Params("*"))
@@ -47,6 +47,15 @@ func Home(c *context.Context) {
 	c.Success(HOME)
 }
 
+// MustAllowExplore responds 404 when tenant isolation is enabled and the user
+// is not an admin.
+func MustAllowExplore(c *context.Context) {
+	if conf.Security.EnableTenantIsolation && !(c.IsLogged && c.User.IsAdmin) {
+		c.NotFound()
+		return
+	}
+}
+
 func ExploreRepos(c *context.Context) {
 	c.Data["Title"] = c.Tr("explore")
 	c.Data["PageIsExplore"] = true
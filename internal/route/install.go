@@ -22,10 +22,12 @@ import (
 	"gogs.io/gogs/internal/context"
 	"gogs.io/gogs/internal/cron"
 	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/db/migrations"
 	"gogs.io/gogs/internal/email"
 	"gogs.io/gogs/internal/form"
 	"gogs.io/gogs/internal/markup"
 	"gogs.io/gogs/internal/osutil"
+	"gogs.io/gogs/internal/selfcheck"
 	"gogs.io/gogs/internal/ssh"
 	"gogs.io/gogs/internal/strutil"
 	"gogs.io/gogs/internal/template/highlight"
@@ -68,12 +70,26 @@ func GlobalInit(customConf string) error {
 
 	email.NewContext()
 
+	// Problems found by the self-check are reported to the caller instead of
+	// exiting, so that the web server is able to boot in safe mode.
+	var problems selfcheck.Problems
 	if conf.Security.InstallLock {
 		highlight.NewContext()
 		markup.NewSanitizer()
+
+		problems = selfcheck.Run()
 		if err := db.NewEngine(); err != nil {
-			log.Fatal("Failed to initialize ORM engine: %v", err)
+			name := "database"
+			if migrations.IsErrUnsupportedVersion(err) {
+				name = "database_schema"
+			}
+			problems = append(problems, selfcheck.Problem{
+				Name:   name,
+				Detail: "Failed to initialize ORM engine: " + err.Error(),
+			})
 		}
+	}
+	if conf.Security.InstallLock && len(problems) == 0 {
 		db.HasEngine = true
 
 		db.LoadRepoConfig()
@@ -81,9 +97,12 @@ func GlobalInit(customConf string) error {
 
 		// Booting long running goroutines.
 		cron.NewContext()
+		email.UseQueue(db.QueuedEmails)
 		db.InitSyncMirrors()
 		db.InitDeliverHooks()
 		db.InitTestPullRequests()
+		db.InitMergeQueues()
+		db.InitFirehose()
 	}
 	if conf.HasMinWinSvc {
 		log.Info("Builtin Windows Service is supported")
@@ -93,7 +112,9 @@ func GlobalInit(customConf string) error {
 	}
 	checkRunMode()
 
-	if !conf.Security.InstallLock {
+	if len(problems) > 0 {
+		return problems
+	} else if !conf.Security.InstallLock {
 		return nil
 	}
 
@@ -6,14 +6,18 @@ package lfs
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"strconv"
 
+	"github.com/pkg/errors"
 	"gopkg.in/macaron.v1"
 	log "unknwon.dev/clog/v2"
 
+	"gogs.io/gogs/internal/antivirus"
 	"gogs.io/gogs/internal/db"
 	"gogs.io/gogs/internal/lfsutil"
 	"gogs.io/gogs/internal/strutil"
@@ -92,8 +96,38 @@ func (h *basicHandler) serveUpload(c *macaron.Context, repo *db.Repository, oid
 		return
 	}
 
+	body := c.Req.Request.Body
+	if antivirus.Enabled(antivirus.KindLFSObject) {
+		// The request body has to be spooled because it can only be read once.
+		f, err := spoolBody(body)
+		if err != nil {
+			internalServerError(c.Resp)
+			log.Error("Failed to spool object [repo_id: %d, oid: %s]: %v", repo.ID, oid, err)
+			return
+		}
+		defer func() {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}()
+
+		source := fmt.Sprintf("repository [id: %d, name: %s]", repo.ID, repo.Name)
+		err = antivirus.Scan(c.Req.Context(), antivirus.KindLFSObject, string(oid), source, f)
+		if err != nil {
+			if antivirus.IsErrInfected(err) {
+				responseJSON(c.Resp, http.StatusUnprocessableEntity, responseError{
+					Message: err.Error(),
+				})
+			} else {
+				internalServerError(c.Resp)
+				log.Error("Failed to scan object [repo_id: %d, oid: %s]: %v", repo.ID, oid, err)
+			}
+			return
+		}
+		body = f
+	}
+
 	s := h.DefaultStorager()
-	written, err := s.Upload(oid, c.Req.Request.Body)
+	written, err := s.Upload(oid, body)
 	if err != nil {
 		if err == lfsutil.ErrInvalidOID {
 			responseJSON(c.Resp, http.StatusBadRequest, responseError{
@@ -120,6 +154,32 @@ func (h *basicHandler) serveUpload(c *macaron.Context, repo *db.Repository, oid
 	log.Trace("[LFS] Object created %q", oid)
 }
 
+// spoolBody copies the request body to a temporary file, which is positioned at
+// the start. It is caller's responsibility to close and remove the file.
+func spoolBody(body io.ReadCloser) (*os.File, error) {
+	defer func() { _ = body.Close() }()
+
+	f, err := ioutil.TempFile("", "gogs-lfs-")
+	if err != nil {
+		return nil, errors.Wrap(err, "create temporary file")
+	}
+
+	_, err = io.Copy(f, body)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return nil, errors.Wrap(err, "copy body")
+	}
+
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return nil, errors.Wrap(err, "seek to start")
+	}
+	return f, nil
+}
+
 // POST /{owner}/{repo}.git/info/lfs/object/basic/verify
 func (*basicHandler) serveVerify(c *macaron.Context, repo *db.Repository) {
 	var request basicVerifyRequest
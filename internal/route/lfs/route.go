@@ -15,6 +15,7 @@ import (
 	"gogs.io/gogs/internal/authutil"
 	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/federation"
 	"gogs.io/gogs/internal/lfsutil"
 )
 
@@ -42,7 +43,8 @@ func RegisterRoutes(r *macaron.Router) {
 }
 
 // authenticate tries to authenticate user via HTTP Basic Auth. It first tries to authenticate
-// as plain username and password, then use username as access token if previous step failed.
+// as plain username and password, then use username or password as access token if previous
+// step failed.
 func authenticate() macaron.Handler {
 	askCredentials := func(w http.ResponseWriter) {
 		w.Header().Set("Lfs-Authenticate", `Basic realm="Git LFS"`)
@@ -70,10 +72,25 @@ func authenticate() macaron.Handler {
 			return
 		}
 
-		// If username and password authentication failed, try again using username as an access token.
+		// If username and password authentication failed, try again using username
+		// as an access token, then password as an access token.
 		if auth.IsErrBadCredentials(err) {
 			token, err := db.AccessTokens.GetBySHA1(c.Req.Context(), username)
-			if err != nil {
+			if db.IsErrAccessTokenNotExist(err) && password != "" {
+				token, err = db.AccessTokens.GetBySHA1(c.Req.Context(), password)
+			}
+			if db.IsErrAccessTokenNotExist(err) && federation.Enabled() {
+				user, err = federation.Authenticate(c.Req.Context(), username, password)
+				if err != nil {
+					if federation.IsErrTokenInactive(err) {
+						askCredentials(c.Resp)
+					} else {
+						internalServerError(c.Resp)
+						log.Error("Failed to authenticate federated token: %v", err)
+					}
+					return
+				}
+			} else if err != nil {
 				if db.IsErrAccessTokenNotExist(err) {
 					askCredentials(c.Resp)
 				} else {
@@ -81,18 +98,19 @@ func authenticate() macaron.Handler {
 					log.Error("Failed to get access token [sha: %s]: %v", username, err)
 				}
 				return
-			}
-			if err = db.AccessTokens.Touch(c.Req.Context(), token.ID); err != nil {
-				log.Error("Failed to touch access token: %v", err)
-			}
+			} else {
+				if err = db.AccessTokens.Touch(c.Req.Context(), token.ID); err != nil {
+					log.Error("Failed to touch access token: %v", err)
+				}
 
-			user, err = db.Users.GetByID(c.Req.Context(), token.UserID)
-			if err != nil {
-				// Once we found the token, we're supposed to find its related user,
-				// thus any error is unexpected.
-				internalServerError(c.Resp)
-				log.Error("Failed to get user [id: %d]: %v", token.UserID, err)
-				return
+				user, err = db.Users.GetByID(c.Req.Context(), token.UserID)
+				if err != nil {
+					// Once we found the token, we're supposed to find its related user,
+					// thus any error is unexpected.
+					internalServerError(c.Resp)
+					log.Error("Failed to get user [id: %d]: %v", token.UserID, err)
+					return
+				}
 			}
 		}
 
@@ -133,7 +151,7 @@ func authorize(mode db.AccessMode) macaron.Handler {
 		if !db.Perms.Authorize(c.Req.Context(), actor.ID, repo.ID, mode,
 			db.AccessModeOptions{
 				OwnerID: repo.OwnerID,
-				Private: repo.IsPrivate,
+				Private: repo.IsPrivate || db.IsTenantIsolated(actor, owner),
 			},
 		) {
 			c.Status(http.StatusNotFound)
@@ -0,0 +1,44 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"fmt"
+	"time"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+const SETTINGS_ACCESS = "org/settings/access"
+
+func SettingsAccess(c *context.Context) {
+	c.Title("org.settings.access")
+	c.PageIs("SettingsAccess")
+
+	report, err := db.GetOrgAccessReport(c.Org.Organization.ID)
+	if err != nil {
+		c.Error(err, "get access report")
+		return
+	}
+	c.Data["AccessReport"] = report
+	c.Success(SETTINGS_ACCESS)
+}
+
+func SettingsAccessExport(c *context.Context) {
+	report, err := db.GetOrgAccessReport(c.Org.Organization.ID)
+	if err != nil {
+		c.Error(err, "get access report")
+		return
+	}
+
+	filename := fmt.Sprintf("%s-access-%s.csv", c.Org.Organization.LowerName, time.Now().Format("20060102"))
+	c.Resp.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	c.Resp.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	if err = db.WriteOrgAccessReportCSV(c.Resp, report); err != nil {
+		c.Error(err, "write access report")
+		return
+	}
+}
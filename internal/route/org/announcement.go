@@ -0,0 +1,38 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+const SETTINGS_ANNOUNCEMENT = "org/settings/announcement"
+
+func SettingsAnnouncement(c *context.Context) {
+	c.Title("org.settings.announcement")
+	c.PageIs("SettingsAnnouncement")
+	c.Data["MaxAnnouncementLength"] = db.MaxOrgAnnouncementLength
+	c.Success(SETTINGS_ANNOUNCEMENT)
+}
+
+func SettingsAnnouncementPost(c *context.Context) {
+	org := c.Org.Organization
+	if err := db.SetOrgAnnouncement(org, c.Query("announcement")); err != nil {
+		if db.IsErrOrgAnnouncementTooLong(err) {
+			c.Flash.Error(c.Tr("org.settings.announcement.too_long", db.MaxOrgAnnouncementLength))
+			c.Redirect(c.Org.OrgLink + "/settings/announcement")
+		} else {
+			c.Error(err, "set announcement")
+		}
+		return
+	}
+
+	log.Trace("Announcement of organization %q updated by %q", org.Name, c.User.Name)
+	c.Flash.Success(c.Tr("org.settings.update_setting_success"))
+	c.Redirect(c.Org.OrgLink + "/settings/announcement")
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"fmt"
+
+	"github.com/unknwon/paginater"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/form"
+	"gogs.io/gogs/internal/markup"
+)
+
+const (
+	TEAM_DISCUSSIONS = "org/team/discussions"
+	TEAM_DISCUSSION  = "org/team/discussion"
+)
+
+func teamDiscussionsLink(c *context.Context) string {
+	return c.Org.OrgLink + "/teams/" + c.Org.Team.LowerName + "/discussions"
+}
+
+// renderTeamDiscussions loads posters and renders contents of discussions.
+func renderTeamDiscussions(c *context.Context, discussions ...*db.TeamDiscussion) bool {
+	for _, d := range discussions {
+		if err := d.LoadPoster(c.Req.Context()); err != nil {
+			c.Error(err, "load poster")
+			return false
+		}
+		d.Content = string(markup.Markdown(d.Content, teamDiscussionsLink(c), nil))
+	}
+	return true
+}
+
+func TeamDiscussions(c *context.Context) {
+	c.Data["Title"] = c.Org.Team.Name
+	c.Data["PageIsOrgTeams"] = true
+	c.Data["PageIsTeamDiscussions"] = true
+
+	page := c.QueryInt("page")
+	if page <= 0 {
+		page = 1
+	}
+
+	count, err := db.TeamDiscussions.CountThreads(c.Req.Context(), c.Org.Team.ID)
+	if err != nil {
+		c.Error(err, "count threads")
+		return
+	}
+	c.Data["Page"] = paginater.New(int(count), conf.UI.IssuePagingNum, page, 5)
+
+	threads, err := db.TeamDiscussions.ListThreads(c.Req.Context(), c.Org.Team.ID, page, conf.UI.IssuePagingNum)
+	if err != nil {
+		c.Error(err, "list threads")
+		return
+	}
+	for _, t := range threads {
+		if err = t.LoadPoster(c.Req.Context()); err != nil {
+			c.Error(err, "load poster")
+			return
+		}
+	}
+	c.Data["Threads"] = threads
+
+	c.Success(TEAM_DISCUSSIONS)
+}
+
+func NewTeamDiscussionPost(c *context.Context, f form.NewTeamDiscussion) {
+	if c.HasError() {
+		c.Flash.Error(c.Data["ErrorMsg"].(string))
+		c.Redirect(teamDiscussionsLink(c))
+		return
+	}
+
+	thread, err := db.TeamDiscussions.CreateThread(c.Req.Context(), c.Org.Team.ID, c.Org.Organization.ID, c.User.ID, f.Title, f.Content)
+	if err != nil {
+		c.Error(err, "create thread")
+		return
+	}
+
+	log.Trace("Team discussion created by %q: %s/%s#%d", c.User.Name, c.Org.Organization.Name, c.Org.Team.LowerName, thread.ID)
+	c.Redirect(fmt.Sprintf("%s/%d", teamDiscussionsLink(c), thread.ID))
+}
+
+func TeamDiscussion(c *context.Context) {
+	thread, err := db.TeamDiscussions.GetByID(c.Req.Context(), c.Org.Team.ID, c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get thread")
+		return
+	} else if !thread.IsThread() {
+		c.NotFound()
+		return
+	}
+
+	replies, err := db.TeamDiscussions.ListReplies(c.Req.Context(), c.Org.Team.ID, thread.ID)
+	if err != nil {
+		c.Error(err, "list replies")
+		return
+	}
+	discussions := append([]*db.TeamDiscussion{thread}, replies...)
+	if !renderTeamDiscussions(c, discussions...) {
+		return
+	}
+
+	c.Data["Title"] = thread.Title
+	c.Data["PageIsOrgTeams"] = true
+	c.Data["PageIsTeamDiscussions"] = true
+	c.Data["Thread"] = thread
+	c.Data["Discussions"] = discussions
+	c.Success(TEAM_DISCUSSION)
+}
+
+func TeamDiscussionReplyPost(c *context.Context, f form.NewTeamDiscussionReply) {
+	threadLink := fmt.Sprintf("%s/%d", teamDiscussionsLink(c), c.ParamsInt64(":id"))
+	if c.HasError() {
+		c.Flash.Error(c.Data["ErrorMsg"].(string))
+		c.Redirect(threadLink)
+		return
+	}
+
+	reply, err := db.TeamDiscussions.CreateReply(c.Req.Context(), c.Org.Team.ID, c.ParamsInt64(":id"), c.User.ID, f.Content)
+	if err != nil {
+		c.NotFoundOrError(err, "create reply")
+		return
+	}
+
+	c.Redirect(fmt.Sprintf("%s#discussion-%d", threadLink, reply.ID))
+}
+
+func DeleteTeamDiscussion(c *context.Context) {
+	d, err := db.TeamDiscussions.GetByID(c.Req.Context(), c.Org.Team.ID, c.QueryInt64("id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get discussion")
+		return
+	} else if d.PosterID != c.User.ID && !c.Org.IsOwner {
+		c.NotFound()
+		return
+	}
+
+	if err = db.TeamDiscussions.DeleteByID(c.Req.Context(), c.Org.Team.ID, d.ID); err != nil {
+		c.Flash.Error("DeleteByID: " + err.Error())
+	} else {
+		c.Flash.Success(c.Tr("org.teams.discussions.deletion_success"))
+	}
+
+	redirect := teamDiscussionsLink(c)
+	if !d.IsThread() {
+		redirect = fmt.Sprintf("%s/%d", redirect, d.ThreadID)
+	}
+	c.JSONSuccess(map[string]interface{}{
+		"redirect": redirect,
+	})
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net"
+
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/lazyregexp"
+)
+
+const SETTINGS_DOMAINS = "org/settings/domains"
+
+var domainPattern = lazyregexp.New(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+func SettingsDomains(c *context.Context) {
+	c.Title("org.settings.domains")
+	c.PageIs("SettingsDomains")
+
+	domains, err := db.OrgDomains.List(c.Req.Context(), c.Org.Organization.ID)
+	if err != nil {
+		c.Error(err, "list domains")
+		return
+	}
+	c.Data["Domains"] = domains
+	c.Success(SETTINGS_DOMAINS)
+}
+
+func SettingsDomainsPost(c *context.Context) {
+	domain := db.NormalizeDomain(c.Query("domain"))
+	if len(domain) > 253 || !domainPattern.MatchString(domain) {
+		c.Flash.Error(c.Tr("org.settings.domains.invalid"))
+		c.Redirect(c.Org.OrgLink + "/settings/domains")
+		return
+	}
+
+	_, err := db.OrgDomains.Create(c.Req.Context(), c.Org.Organization.ID, domain)
+	if err != nil {
+		if db.IsErrOrgDomainAlreadyExist(err) {
+			c.Flash.Error(c.Tr("org.settings.domains.already_exist"))
+			c.Redirect(c.Org.OrgLink + "/settings/domains")
+		} else {
+			c.Error(err, "create domain")
+		}
+		return
+	}
+
+	log.Trace("Domain added to organization %q: %s", c.Org.Organization.Name, domain)
+	c.Flash.Success(c.Tr("org.settings.domains.add_success", domain))
+	c.Redirect(c.Org.OrgLink + "/settings/domains")
+}
+
+func SettingsDomainVerify(c *context.Context) {
+	d, err := db.OrgDomains.GetByID(c.Req.Context(), c.Org.Organization.ID, c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get domain")
+		return
+	}
+
+	verified := false
+	records, err := net.DefaultResolver.LookupTXT(c.Req.Context(), d.TXTRecordName())
+	if err != nil {
+		log.Trace("Failed to look up TXT records of %q: %v", d.TXTRecordName(), err)
+	}
+	for _, record := range records {
+		if record == d.TXTRecordValue() {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		c.Flash.Error(c.Tr("org.settings.domains.verify_failed", d.Domain))
+		c.Redirect(c.Org.OrgLink + "/settings/domains")
+		return
+	}
+
+	if err = db.OrgDomains.MarkVerified(c.Req.Context(), c.Org.Organization.ID, d.ID); err != nil {
+		c.Error(err, "mark domain as verified")
+		return
+	}
+
+	log.Trace("Domain of organization %q verified: %s", c.Org.Organization.Name, d.Domain)
+	c.Flash.Success(c.Tr("org.settings.domains.verify_success", d.Domain))
+	c.Redirect(c.Org.OrgLink + "/settings/domains")
+}
+
+func SettingsDomainDelete(c *context.Context) {
+	if err := db.OrgDomains.DeleteByID(c.Req.Context(), c.Org.Organization.ID, c.QueryInt64("id")); err != nil {
+		c.Flash.Error("DeleteByID: " + err.Error())
+	} else {
+		c.Flash.Success(c.Tr("org.settings.domains.deletion_success"))
+	}
+
+	c.JSONSuccess(map[string]interface{}{
+		"redirect": c.Org.OrgLink + "/settings/domains",
+	})
+}
+
+func SettingsDomainsRestrictPost(c *context.Context) {
+	org := c.Org.Organization
+	org.RestrictMembersToVerifiedDomains = c.Query("restrict_members") == "on"
+	if err := db.UpdateUser(org); err != nil {
+		c.Error(err, "update user")
+		return
+	}
+
+	c.Flash.Success(c.Tr("org.settings.update_setting_success"))
+	c.Redirect(c.Org.OrgLink + "/settings/domains")
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"fmt"
+	"time"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+const SETTINGS_LICENSES = "org/settings/licenses"
+
+func SettingsLicenses(c *context.Context) {
+	c.Title("org.settings.licenses")
+	c.PageIs("SettingsLicenses")
+
+	report, err := db.GetOrgLicenseReport(c.Org.Organization.ID)
+	if err != nil {
+		c.Error(err, "get license report")
+		return
+	}
+	c.Data["LicenseReport"] = report
+	c.Data["LicenseNoAssertion"] = db.LicenseNoAssertion
+	c.Success(SETTINGS_LICENSES)
+}
+
+func SettingsLicensesExport(c *context.Context) {
+	report, err := db.GetOrgLicenseReport(c.Org.Organization.ID)
+	if err != nil {
+		c.Error(err, "get license report")
+		return
+	}
+
+	filename := fmt.Sprintf("%s-licenses-%s.csv", c.Org.Organization.LowerName, time.Now().Format("20060102"))
+	c.Resp.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	c.Resp.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	if err = db.WriteOrgLicenseReportCSV(c.Resp, report); err != nil {
+		c.Error(err, "write license report")
+		return
+	}
+}
@@ -109,7 +109,15 @@ func Invitation(c *context.Context) {
 		}
 
 		if err = org.AddMember(u.ID); err != nil {
-			c.Error(err, "add member")
+			if db.IsErrOrgMemberDomainNotVerified(err) {
+				c.Flash.Error(c.Tr("org.members.domain_not_verified"))
+				c.Redirect(c.Org.OrgLink + "/invitations/new")
+			} else if db.IsErrOrgTwoFactorNotEnabled(err) {
+				c.Flash.Error(c.Tr("org.members.two_factor_not_enabled"))
+				c.Redirect(c.Org.OrgLink + "/invitations/new")
+			} else {
+				c.Error(err, "add member")
+			}
 			return
 		}
 
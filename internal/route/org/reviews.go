@@ -0,0 +1,48 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"time"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+const SETTINGS_REVIEWS = "org/settings/reviews"
+
+const (
+	defaultReviewLoadWeeks = 8
+	maxReviewLoadWeeks     = 26
+)
+
+func SettingsReviews(c *context.Context) {
+	c.Title("org.settings.reviews")
+	c.PageIs("SettingsReviews")
+
+	weeks := c.QueryInt("weeks")
+	if weeks <= 0 {
+		weeks = defaultReviewLoadWeeks
+	} else if weeks > maxReviewLoadWeeks {
+		weeks = maxReviewLoadWeeks
+	}
+	c.Data["Weeks"] = weeks
+	c.Data["WeekOptions"] = []int{4, 8, 13, maxReviewLoadWeeks}
+
+	report, err := db.GetOrgReviewLoadReport(c.Org.Organization.ID, weeks, time.Now())
+	if err != nil {
+		c.Error(err, "get review load report")
+		return
+	}
+	c.Data["ReviewLoad"] = report
+
+	coverages, err := db.GetOrgCodeownersCoverage(c.Org.Organization.ID)
+	if err != nil {
+		c.Error(err, "get code owners coverage")
+		return
+	}
+	c.Data["CodeownersCoverages"] = coverages
+	c.Success(SETTINGS_REVIEWS)
+}
@@ -0,0 +1,221 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"path"
+	"strings"
+
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/form"
+)
+
+const (
+	SETTINGS_RULESETS    = "org/settings/rulesets"
+	SETTINGS_RULESET_NEW = "org/settings/ruleset_new"
+)
+
+func SettingsRulesets(c *context.Context) {
+	c.Title("org.settings.rulesets")
+	c.PageIs("SettingsRulesets")
+
+	rulesets, err := db.OrgRulesets.List(c.Req.Context(), c.Org.Organization.ID)
+	if err != nil {
+		c.Error(err, "list rulesets")
+		return
+	}
+	c.Data["Rulesets"] = rulesets
+	c.Data["ProtectDefaultBranchGlobally"] = conf.Repository.ProtectDefaultBranch
+	c.Data["SiteRepoNamePattern"] = conf.Repository.Naming.NamePattern
+	c.Data["SiteRequiredRepoTopics"] = conf.Repository.Naming.RequiredTopics
+	c.Success(SETTINGS_RULESETS)
+}
+
+// SettingsDefaultBranchProtectionPost updates whether to protect the default
+// branch of new repositories of the organization.
+func SettingsDefaultBranchProtectionPost(c *context.Context) {
+	org := c.Org.Organization
+	org.ProtectDefaultBranch = c.Query("protect_default_branch") == "on"
+	if err := db.UpdateUser(org); err != nil {
+		c.Error(err, "update user")
+		return
+	}
+
+	log.Trace("Default branch protection of organization %q changed by %q: %v", org.Name, c.User.Name, org.ProtectDefaultBranch)
+	c.Flash.Success(c.Tr("org.settings.update_setting_success"))
+	c.Redirect(c.Org.OrgLink + "/settings/rulesets")
+}
+
+// SettingsRepoNamingPost updates naming conventions of new repositories of the
+// organization.
+func SettingsRepoNamingPost(c *context.Context) {
+	org := c.Org.Organization
+	nc := &db.RepoNamingConvention{
+		NamePattern:     strings.TrimSpace(c.Query("repo_name_pattern")),
+		NamePatternHelp: strings.TrimSpace(c.Query("repo_name_pattern_help")),
+		RequiredTopics:  db.ParseRequiredRepoTopics(c.Query("required_repo_topics")),
+	}
+	if err := db.ValidateRepoNamingConvention(nc); err != nil {
+		c.Flash.Error(c.Tr("org.settings.rulesets.repo_naming_invalid", err))
+		c.Redirect(c.Org.OrgLink + "/settings/rulesets")
+		return
+	}
+
+	org.RepoNamePattern = nc.NamePattern
+	org.RepoNamePatternHelp = nc.NamePatternHelp
+	org.RequiredRepoTopics = strings.Join(nc.RequiredTopics, ",")
+	if err := db.UpdateUser(org); err != nil {
+		c.Error(err, "update user")
+		return
+	}
+
+	log.Trace("Repository naming conventions of organization %q changed by %q", org.Name, c.User.Name)
+	c.Flash.Success(c.Tr("org.settings.update_setting_success"))
+	c.Redirect(c.Org.OrgLink + "/settings/rulesets")
+}
+
+func NewRuleset(c *context.Context) {
+	c.Title("org.settings.rulesets.new")
+	c.PageIs("SettingsRulesets")
+	c.Data["PageIsNewRuleset"] = true
+
+	form.Assign(form.OrgRuleset{
+		RepoPatterns:       "*",
+		BranchPatterns:     "main, master",
+		RequirePullRequest: true,
+		BlockForcePush:     true,
+		BlockDeletion:      true,
+	}, c.Data)
+	c.Success(SETTINGS_RULESET_NEW)
+}
+
+// validRulesetPatterns returns false if any of patterns is malformed.
+func validRulesetPatterns(lists ...string) bool {
+	for _, list := range lists {
+		for _, pattern := range db.ParseRulesetPatterns(list) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func rulesetOptions(f form.OrgRuleset) db.OrgRulesetOptions {
+	return db.OrgRulesetOptions{
+		Name:               f.Name,
+		RepoPatterns:       f.RepoPatterns,
+		BranchPatterns:     f.BranchPatterns,
+		RequirePullRequest: f.RequirePullRequest,
+		BlockForcePush:     f.BlockForcePush,
+		BlockDeletion:      f.BlockDeletion,
+		BlockedPaths:       f.BlockedPaths,
+	}
+}
+
+func NewRulesetPost(c *context.Context, f form.OrgRuleset) {
+	c.Title("org.settings.rulesets.new")
+	c.PageIs("SettingsRulesets")
+	c.Data["PageIsNewRuleset"] = true
+
+	if c.HasError() {
+		c.Success(SETTINGS_RULESET_NEW)
+		return
+	}
+	if !validRulesetPatterns(f.RepoPatterns, f.BranchPatterns, f.BlockedPaths) {
+		c.RenderWithErr(c.Tr("org.settings.rulesets.invalid_patterns"), SETTINGS_RULESET_NEW, &f)
+		return
+	}
+
+	r, err := db.OrgRulesets.Create(c.Req.Context(), c.Org.Organization.ID, rulesetOptions(f))
+	if err != nil {
+		if db.IsErrOrgRulesetAlreadyExist(err) {
+			c.FormErr("Name")
+			c.RenderWithErr(c.Tr("org.settings.rulesets.name_been_taken"), SETTINGS_RULESET_NEW, &f)
+		} else {
+			c.Error(err, "create ruleset")
+		}
+		return
+	}
+
+	log.Trace("Ruleset created for organization %q: %s", c.Org.Organization.Name, r.Name)
+	c.Flash.Success(c.Tr("org.settings.rulesets.add_success", r.Name))
+	c.Redirect(c.Org.OrgLink + "/settings/rulesets")
+}
+
+func EditRuleset(c *context.Context) {
+	c.Title("org.settings.rulesets.edit")
+	c.PageIs("SettingsRulesets")
+
+	r, err := db.OrgRulesets.GetByID(c.Req.Context(), c.Org.Organization.ID, c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get ruleset")
+		return
+	}
+	c.Data["Ruleset"] = r
+
+	form.Assign(form.OrgRuleset{
+		Name:               r.Name,
+		RepoPatterns:       r.RepoPatterns,
+		BranchPatterns:     r.BranchPatterns,
+		RequirePullRequest: r.RequirePullRequest,
+		BlockForcePush:     r.BlockForcePush,
+		BlockDeletion:      r.BlockDeletion,
+		BlockedPaths:       r.BlockedPaths,
+	}, c.Data)
+	c.Success(SETTINGS_RULESET_NEW)
+}
+
+func EditRulesetPost(c *context.Context, f form.OrgRuleset) {
+	c.Title("org.settings.rulesets.edit")
+	c.PageIs("SettingsRulesets")
+
+	r, err := db.OrgRulesets.GetByID(c.Req.Context(), c.Org.Organization.ID, c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get ruleset")
+		return
+	}
+	c.Data["Ruleset"] = r
+
+	if c.HasError() {
+		c.Success(SETTINGS_RULESET_NEW)
+		return
+	}
+	if !validRulesetPatterns(f.RepoPatterns, f.BranchPatterns, f.BlockedPaths) {
+		c.RenderWithErr(c.Tr("org.settings.rulesets.invalid_patterns"), SETTINGS_RULESET_NEW, &f)
+		return
+	}
+
+	err = db.OrgRulesets.Update(c.Req.Context(), c.Org.Organization.ID, r.ID, rulesetOptions(f))
+	if err != nil {
+		if db.IsErrOrgRulesetAlreadyExist(err) {
+			c.FormErr("Name")
+			c.RenderWithErr(c.Tr("org.settings.rulesets.name_been_taken"), SETTINGS_RULESET_NEW, &f)
+		} else {
+			c.Error(err, "update ruleset")
+		}
+		return
+	}
+
+	log.Trace("Ruleset of organization %q updated: %s", c.Org.Organization.Name, f.Name)
+	c.Flash.Success(c.Tr("org.settings.rulesets.update_success", f.Name))
+	c.Redirect(c.Org.OrgLink + "/settings/rulesets")
+}
+
+func DeleteRuleset(c *context.Context) {
+	if err := db.OrgRulesets.DeleteByID(c.Req.Context(), c.Org.Organization.ID, c.QueryInt64("id")); err != nil {
+		c.Flash.Error("DeleteByID: " + err.Error())
+	} else {
+		c.Flash.Success(c.Tr("org.settings.rulesets.deletion_success"))
+	}
+
+	c.JSONSuccess(map[string]interface{}{
+		"redirect": c.Org.OrgLink + "/settings/rulesets",
+	})
+}
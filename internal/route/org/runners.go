@@ -0,0 +1,67 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+const SETTINGS_RUNNERS = "org/settings/runners"
+
+func SettingsRunners(c *context.Context) {
+	c.Title("org.settings.runners")
+	c.PageIs("SettingsRunners")
+
+	runners, err := db.Runners.List(c.Req.Context(), c.Org.Organization.ID)
+	if err != nil {
+		c.Error(err, "list runners")
+		return
+	}
+	c.Data["Runners"] = runners
+	c.Data["IsJobTokensEnabled"] = conf.Auth.EnableJobTokens
+	c.Data["JobTokenMaxLives"] = conf.Auth.JobTokenMaxLives
+	c.Success(SETTINGS_RUNNERS)
+}
+
+func SettingsRunnersPost(c *context.Context) {
+	name := c.Query("name")
+	if name == "" || len(name) > 255 {
+		c.Flash.Error(c.Tr("org.settings.runners.invalid_name"))
+		c.Redirect(c.Org.OrgLink + "/settings/runners")
+		return
+	}
+
+	r, err := db.Runners.Create(c.Req.Context(), c.Org.Organization.ID, c.User.ID, name)
+	if err != nil {
+		if db.IsErrRunnerAlreadyExist(err) {
+			c.Flash.Error(c.Tr("org.settings.runners.already_exist"))
+			c.Redirect(c.Org.OrgLink + "/settings/runners")
+		} else {
+			c.Error(err, "create runner")
+		}
+		return
+	}
+
+	log.Trace("Runner registered for organization %q: %s", c.Org.Organization.Name, name)
+	c.Flash.Success(c.Tr("org.settings.runners.add_success", name))
+	c.Flash.Info(r.Token)
+	c.Redirect(c.Org.OrgLink + "/settings/runners")
+}
+
+func SettingsRunnerDelete(c *context.Context) {
+	if err := db.Runners.DeleteByID(c.Req.Context(), c.Org.Organization.ID, c.QueryInt64("id")); err != nil {
+		c.Flash.Error("DeleteByID: " + err.Error())
+	} else {
+		c.Flash.Success(c.Tr("org.settings.runners.deletion_success"))
+	}
+
+	c.JSONSuccess(map[string]interface{}{
+		"redirect": c.Org.OrgLink + "/settings/runners",
+	})
+}
@@ -0,0 +1,139 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"time"
+
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/email"
+)
+
+const SETTINGS_SECURITY = "org/settings/security"
+
+func SettingsSecurity(c *context.Context) {
+	c.Title("org.settings.security")
+	c.PageIs("SettingsSecurity")
+
+	members, err := db.GetOrgMembersWithoutTwoFactor(c.Org.Organization.ID)
+	if err != nil {
+		c.Error(err, "get members without two-factor authentication")
+		return
+	}
+	c.Data["MembersWithoutTwoFactor"] = members
+	c.Data["MaxGracePeriodDays"] = int(db.MaxTwoFactorGracePeriod / (24 * time.Hour))
+
+	policy, err := db.OrgSecurityPolicies.Get(c.Req.Context(), c.Org.Organization.ID)
+	if err != nil {
+		c.Error(err, "get security policy")
+		return
+	}
+	c.Data["SecurityPolicy"] = policy
+
+	exceptions, err := db.SecurityPolicyExceptions.List(c.Req.Context(), c.Org.Organization.ID)
+	if err != nil {
+		c.Error(err, "list security policy exceptions")
+		return
+	}
+	for _, e := range exceptions {
+		if err = e.LoadAttributes(c.Req.Context()); err != nil {
+			c.Error(err, "load attributes of security policy exception")
+			return
+		}
+	}
+	c.Data["SecurityPolicyExceptions"] = exceptions
+	c.Success(SETTINGS_SECURITY)
+}
+
+func SettingsSecurityPost(c *context.Context) {
+	org := c.Org.Organization
+	wasRequired := org.RequireTwoFactor
+	require := c.Query("require_two_factor") == "on"
+	gracePeriod := time.Duration(c.QueryInt("grace_period_days")) * 24 * time.Hour
+	if err := db.SetOrgTwoFactorRequirement(org, c.User, require, gracePeriod); err != nil {
+		if db.IsErrOrgTwoFactorNotEnabled(err) {
+			c.Flash.Error(c.Tr("org.settings.security.doer_two_factor_not_enabled"))
+			c.Redirect(c.Org.OrgLink + "/settings/security")
+		} else {
+			c.Error(err, "set two-factor requirement")
+		}
+		return
+	}
+
+	if require && conf.Email.Enabled {
+		members, err := db.GetOrgMembersWithoutTwoFactor(org.ID)
+		if err != nil {
+			c.Error(err, "get members without two-factor authentication")
+			return
+		}
+		tos := make([]string, 0, len(members))
+		for _, u := range members {
+			tos = append(tos, u.Email)
+		}
+		email.SendOrgTwoFactorRequiredMail(tos, org.Name, org.TwoFactorGraceUntil(), conf.Server.ExternalURL+"user/settings/security")
+	}
+
+	if wasRequired != require {
+		log.Trace("Two-factor requirement of organization %q changed by %q: %v", org.Name, c.User.Name, require)
+	}
+	c.Flash.Success(c.Tr("org.settings.update_setting_success"))
+	c.Redirect(c.Org.OrgLink + "/settings/security")
+}
+
+func SettingsSecurityPolicyPost(c *context.Context) {
+	org := c.Org.Organization
+	err := db.OrgSecurityPolicies.Update(c.Req.Context(), org.ID, db.OrgSecurityPolicyOptions{
+		SecretScanning:       c.Query("secret_scanning") == "on",
+		VulnerabilityAlerts:  c.Query("vulnerability_alerts") == "on",
+		RequireSignedCommits: c.Query("require_signed_commits") == "on",
+	})
+	if err != nil {
+		c.Error(err, "update security policy")
+		return
+	}
+
+	log.Trace("Security policy of organization %q updated by %q", org.Name, c.User.Name)
+	c.Flash.Success(c.Tr("org.settings.update_setting_success"))
+	c.Redirect(c.Org.OrgLink + "/settings/security")
+}
+
+func SettingsSecurityExceptionReview(c *context.Context) {
+	org := c.Org.Organization
+	approved := c.Params(":action") == "approve"
+	err := db.SecurityPolicyExceptions.Review(c.Req.Context(), org.ID, c.ParamsInt64(":id"), c.User.ID, approved)
+	if err != nil {
+		if db.IsErrSecurityPolicyExceptionNotExist(err) {
+			c.Flash.Error(c.Tr("org.settings.security.exception_not_pending"))
+			c.Redirect(c.Org.OrgLink + "/settings/security")
+		} else {
+			c.Error(err, "review security policy exception")
+		}
+		return
+	}
+
+	log.Trace("Security policy exception %d of organization %q reviewed by %q: %v", c.ParamsInt64(":id"), org.Name, c.User.Name, approved)
+	if approved {
+		c.Flash.Success(c.Tr("org.settings.security.exception_approved"))
+	} else {
+		c.Flash.Success(c.Tr("org.settings.security.exception_rejected"))
+	}
+	c.Redirect(c.Org.OrgLink + "/settings/security")
+}
+
+func SettingsSecurityExceptionDelete(c *context.Context) {
+	if err := db.SecurityPolicyExceptions.DeleteByID(c.Req.Context(), c.Org.Organization.ID, c.QueryInt64("id")); err != nil {
+		c.Flash.Error("DeleteByID: " + err.Error())
+	} else {
+		c.Flash.Success(c.Tr("org.settings.security.exception_deletion_success"))
+	}
+
+	c.JSONSuccess(map[string]interface{}{
+		"redirect": c.Org.OrgLink + "/settings/security",
+	})
+}
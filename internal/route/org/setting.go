@@ -25,12 +25,22 @@ const (
 func Settings(c *context.Context) {
 	c.Title("org.settings")
 	c.Data["PageIsSettingsOptions"] = true
+	c.Data["EditAllProfileFields"] = c.User.IsAdmin
+	user.PrepareProfileFields(c, c.Org.Organization.ID, db.ProfileFieldScopeOrganization)
+	if c.Written() {
+		return
+	}
 	c.Success(SETTINGS_OPTIONS)
 }
 
 func SettingsPost(c *context.Context, f form.UpdateOrgSetting) {
 	c.Title("org.settings")
 	c.Data["PageIsSettingsOptions"] = true
+	c.Data["EditAllProfileFields"] = c.User.IsAdmin
+	profileFields := user.PrepareProfileFields(c, c.Org.Organization.ID, db.ProfileFieldScopeOrganization)
+	if c.Written() {
+		return
+	}
 
 	if c.HasError() {
 		c.Success(SETTINGS_OPTIONS)
@@ -69,6 +79,7 @@ func SettingsPost(c *context.Context, f form.UpdateOrgSetting) {
 
 	if c.User.IsAdmin {
 		org.MaxRepoCreation = f.MaxRepoCreation
+		org.Tenant = f.Tenant
 	}
 
 	org.FullName = f.FullName
@@ -79,6 +90,12 @@ func SettingsPost(c *context.Context, f form.UpdateOrgSetting) {
 		c.Error(err, "update user")
 		return
 	}
+
+	err := db.ProfileFields.SetValues(c.Req.Context(), org.ID, user.ParseProfileFieldValues(c, profileFields, c.User.IsAdmin))
+	if err != nil {
+		c.Error(err, "set profile field values")
+		return
+	}
 	log.Trace("Organization setting updated: %s", org.Name)
 	c.Flash.Success(c.Tr("org.settings.update_setting_success"))
 	c.Redirect(c.Org.OrgLink + "/settings")
@@ -89,6 +89,10 @@ func TeamsAction(c *context.Context) {
 	if err != nil {
 		if db.IsErrLastOrgOwner(err) {
 			c.Flash.Error(c.Tr("form.last_org_owner"))
+		} else if db.IsErrOrgMemberDomainNotVerified(err) {
+			c.Flash.Error(c.Tr("org.members.domain_not_verified"))
+		} else if db.IsErrOrgTwoFactorNotEnabled(err) {
+			c.Flash.Error(c.Tr("org.members.two_factor_not_enabled"))
 		} else {
 			log.Error("Action(%s): %v", c.Params(":action"), err)
 			c.JSONSuccess(map[string]interface{}{
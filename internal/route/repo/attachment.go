@@ -0,0 +1,220 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/tool"
+)
+
+// serveAttachment writes the file of the attachment to the response. Images and
+// PDF documents are displayed inline, and other files are downloaded.
+func serveAttachment(c *context.Context, attach *db.Attachment) {
+	fr, err := os.Open(attach.LocalPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			c.NotFound()
+		} else {
+			c.Error(err, "open attachment file")
+		}
+		return
+	}
+	defer func() { _ = fr.Close() }()
+
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(fr, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		c.Error(err, "read attachment file")
+		return
+	}
+	buf = buf[:n]
+
+	disposition := "attachment"
+	if tool.IsImageFile(buf) || tool.IsPDFFile(buf) {
+		disposition = "inline"
+		c.Header().Set("Content-Type", http.DetectContentType(buf))
+	} else {
+		c.Header().Set("Content-Type", "application/octet-stream")
+	}
+
+	// Attachments of private repositories must not be cached by shared caches.
+	cacheControl := "public,max-age=86400"
+	if c.Repo.Repository.IsPrivate {
+		cacheControl = "private,max-age=86400"
+	}
+
+	c.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+	c.Header().Set("X-Content-Type-Options", "nosniff")
+	c.Header().Set("Cache-Control", cacheControl)
+	c.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attach.Name}))
+
+	if _, err = c.Resp.Write(buf); err == nil {
+		_, err = io.Copy(c.Resp, fr)
+	}
+	if err != nil {
+		log.Error("Failed to copy attachment %q to response: %v", attach.UUID, err)
+	}
+}
+
+// getIssueAttachment returns the issue of the request and the attachment that
+// belongs to the issue or one of its comments.
+func getIssueAttachment(c *context.Context) (*db.Issue, *db.Attachment) {
+	issue, err := db.GetIssueByIndex(c.Repo.Repository.ID, c.ParamsInt64(":index"))
+	if err != nil {
+		c.NotFoundOrError(err, "get issue by index")
+		return nil, nil
+	}
+
+	attach, err := db.GetAttachmentByUUID(c.Params(":uuid"))
+	if err != nil {
+		c.NotFoundOrError(err, "get attachment by UUID")
+		return nil, nil
+	} else if attach.IssueID != issue.ID {
+		c.NotFound()
+		return nil, nil
+	}
+	return issue, attach
+}
+
+// GET /{owner}/{repo}/issues/{index}/attachments/{uuid}
+func IssueAttachment(c *context.Context) {
+	issue, attach := getIssueAttachment(c)
+	if c.Written() {
+		return
+	}
+
+	if issue.IsPull {
+		MustAllowPulls(c)
+	} else {
+		MustEnableIssues(c)
+	}
+	if c.Written() {
+		return
+	}
+
+	serveAttachment(c, attach)
+}
+
+// POST /{owner}/{repo}/issues/{index}/attachments/{uuid}/delete
+func DeleteIssueAttachment(c *context.Context) {
+	issue, attach := getIssueAttachment(c)
+	if c.Written() {
+		return
+	}
+
+	// Same permissions as editing content of the issue or the comment.
+	if attach.CommentID > 0 {
+		comment, err := db.GetCommentByID(attach.CommentID)
+		if err != nil {
+			c.NotFoundOrError(err, "get comment by ID")
+			return
+		}
+
+		if c.UserID() != comment.PosterID && !c.Repo.IsAdmin() {
+			c.Status(http.StatusForbidden)
+			return
+		}
+	} else if c.UserID() != issue.PosterID && !c.Repo.IsWriter() {
+		c.Status(http.StatusForbidden)
+		return
+	}
+
+	if err := db.DeleteAttachment(attach, true); err != nil {
+		c.Error(err, "delete attachment")
+		return
+	}
+
+	log.Trace("Attachment deleted: %s", attach.UUID)
+	c.Status(http.StatusOK)
+}
+
+// GET /{owner}/{repo}/releases/{id}/attachments/{uuid}
+func ReleaseAttachment(c *context.Context) {
+	release, err := db.GetReleaseByID(c.ParamsInt64(":id"))
+	if err != nil {
+		c.NotFoundOrError(err, "get release by ID")
+		return
+	} else if release.RepoID != c.Repo.Repository.ID || (release.IsDraft && !c.Repo.IsWriter()) {
+		c.NotFound()
+		return
+	}
+
+	attach, err := db.GetAttachmentByUUID(c.Params(":uuid"))
+	if err != nil {
+		c.NotFoundOrError(err, "get attachment by UUID")
+		return
+	} else if attach.ReleaseID != release.ID {
+		c.NotFound()
+		return
+	}
+
+	serveAttachment(c, attach)
+}
+
+// GET /attachments/{uuid}
+//
+// RedirectAttachment redirects the legacy URL of an attachment to the URL tied
+// to the owning issue or release when the current user has read access to the
+// repository, attachments that are not attached to anything yet are not served.
+func RedirectAttachment(c *context.Context) {
+	attach, err := db.GetAttachmentByUUID(c.Params(":uuid"))
+	if err != nil {
+		c.NotFoundOrError(err, "get attachment by UUID")
+		return
+	}
+
+	var (
+		repo *db.Repository
+		link string
+	)
+	switch {
+	case attach.IssueID > 0:
+		issue, err := db.GetIssueByID(attach.IssueID)
+		if err != nil {
+			c.NotFoundOrError(err, "get issue by ID")
+			return
+		}
+		repo = issue.Repo
+		link = fmt.Sprintf("%s/issues/%d/attachments/%s", repo.Link(), issue.Index, attach.UUID)
+
+	case attach.ReleaseID > 0:
+		release, err := db.GetReleaseByID(attach.ReleaseID)
+		if err != nil {
+			c.NotFoundOrError(err, "get release by ID")
+			return
+		}
+		repo = release.Repo
+		link = fmt.Sprintf("%s/releases/%d/attachments/%s", repo.Link(), release.ID, attach.UUID)
+
+	default:
+		c.NotFound()
+		return
+	}
+
+	// Do not reveal the location of attachments to users without access.
+	if !c.IsLogged || !c.User.IsAdmin {
+		canRead := db.Perms.Authorize(c.Req.Context(), c.UserID(), repo.ID, db.AccessModeRead,
+			db.AccessModeOptions{
+				OwnerID: repo.OwnerID,
+				Private: repo.IsPrivate || db.IsTenantIsolated(c.User, repo.MustOwner()),
+			},
+		)
+		if !canRead {
+			c.NotFound()
+			return
+		}
+	}
+
+	c.Redirect(link, http.StatusMovedPermanently)
+}
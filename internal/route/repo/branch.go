@@ -122,6 +122,13 @@ func DeleteBranchPost(c *context.Context) {
 	if !c.Repo.GitRepo.HasBranch(branchName) {
 		return
 	}
+	if blocked, err := c.Repo.Repository.IsBranchDeletionBlocked(branchName); err != nil {
+		log.Error("Failed to check if deletion of branch %q is blocked: %v", branchName, err)
+		return
+	} else if blocked {
+		c.Flash.Error(c.Tr("repo.branches.deletion_blocked", branchName))
+		return
+	}
 	if len(commitID) > 0 {
 		branchCommitID, err := c.Repo.GitRepo.BranchCommitID(branchName)
 		if err != nil {
@@ -0,0 +1,135 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+
+	"github.com/gogs/git-module"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/gitutil"
+)
+
+// branchStatusCacheTTL is the number of seconds to cache combined statuses of
+// branches, so that frequent polling by scripts and badges does not hit the
+// repository and the database every time.
+const branchStatusCacheTTL = 10
+
+// BranchStatus is the combined status of the latest commit on a branch.
+type BranchStatus struct {
+	Branch string
+	SHA    string
+	// State is empty when the commit has no status.
+	State    db.CommitStatusState
+	Statuses []*db.CommitStatus
+}
+
+// GetBranchStatus returns the combined status of the latest commit on the
+// branch of the current repository, which is cached briefly. The default
+// branch is used when the branch is empty.
+func GetBranchStatus(c *context.Context, branch string) (*BranchStatus, error) {
+	repo := c.Repo.Repository
+	if branch == "" {
+		branch = repo.DefaultBranch
+	}
+
+	key := fmt.Sprintf("branch_status_%d_%s", repo.ID, branch)
+	if v, ok := c.Cache.Get(key).(string); ok {
+		status := new(BranchStatus)
+		if err := json.Unmarshal([]byte(v), status); err == nil {
+			return status, nil
+		}
+	}
+
+	gitRepo, err := git.Open(repo.RepoPath())
+	if err != nil {
+		return nil, fmt.Errorf("open repository: %v", err)
+	}
+	commit, err := gitRepo.BranchCommit(branch)
+	if err != nil {
+		return nil, gitutil.NewError(err)
+	}
+
+	statuses, err := db.CommitStatuses.ListLatest(c.Req.Context(), repo.ID, commit.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("list commit statuses: %v", err)
+	}
+	status := &BranchStatus{
+		Branch:   branch,
+		SHA:      commit.ID.String(),
+		State:    db.CombineCommitStatuses(statuses),
+		Statuses: statuses,
+	}
+
+	p, err := json.Marshal(status)
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %v", err)
+	}
+	if err = c.Cache.Put(key, string(p), branchStatusCacheTTL); err != nil {
+		log.Error("Failed to put cache key %q: %v", key, err)
+	}
+	return status, nil
+}
+
+var branchStatusBadgeColors = map[db.CommitStatusState]string{
+	db.CommitStatusPending: "#dfb317",
+	db.CommitStatusSuccess: "#4c1",
+	db.CommitStatusFailure: "#e05d44",
+}
+
+// renderBranchStatusBadge renders the SVG badge with the label and the
+// combined state.
+func renderBranchStatusBadge(label string, state db.CommitStatusState) string {
+	value := string(state)
+	if value == "" {
+		value = "unknown"
+	}
+	color, ok := branchStatusBadgeColors[state]
+	if !ok {
+		color = "#9f9f9f"
+	}
+
+	// The width of text is estimated for the font size of 11px.
+	labelWidth := 6*len(label) + 10
+	valueWidth := 6*len(value) + 10
+	width := labelWidth + valueWidth
+	label = html.EscapeString(label)
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[2]s: %[5]s">`+
+		`<title>%[2]s: %[5]s</title>`+
+		`<rect width="%[3]d" height="20" fill="#555"/>`+
+		`<rect x="%[3]d" width="%[4]d" height="20" fill="%[6]s"/>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%[7]d" y="14">%[2]s</text>`+
+		`<text x="%[8]d" y="14">%[5]s</text>`+
+		`</g></svg>`,
+		width, label, labelWidth, valueWidth, value, color, labelWidth/2, labelWidth+valueWidth/2,
+	)
+}
+
+// BranchStatusBadge serves the SVG badge of the combined status of the latest
+// commit on the branch given by the "branch" query parameter, or the default
+// branch of the repository.
+func BranchStatusBadge(c *context.Context) {
+	status, err := GetBranchStatus(c, c.Query("branch"))
+	if err != nil {
+		c.NotFoundOrError(err, "get branch status")
+		return
+	}
+
+	label := c.Query("label")
+	if label == "" {
+		label = status.Branch
+	}
+	c.Resp.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	c.Resp.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", branchStatusCacheTTL))
+	c.Resp.WriteHeader(http.StatusOK)
+	_, _ = c.Resp.Write([]byte(renderBranchStatusBadge(label, status.State)))
+}
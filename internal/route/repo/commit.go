@@ -53,13 +53,20 @@ func renderCommits(c *context.Context, filename string) {
 		pageSize = conf.UI.User.CommitsPagingNum
 	}
 
-	commits, err := c.Repo.Commit.CommitsByPage(page, pageSize, git.CommitsByPageOptions{Path: filename})
+	var commits []*git.Commit
+	var err error
+	if filename == "" {
+		commits, err = c.Repo.Commit.CommitsByPage(page, pageSize, git.CommitsByPageOptions{})
+	} else {
+		commits, err = fileHistoryCommits(c, filename, page, pageSize)
+	}
 	if err != nil {
 		c.Error(err, "paging commits")
 		return
 	}
 
 	commits = RenderIssueLinks(commits, c.Repo.RepoLink)
+	mapCommitAuthors(c, commits...)
 	c.Data["Commits"] = db.ValidateCommitsWithEmails(commits)
 
 	if page > 1 {
@@ -77,6 +84,35 @@ func renderCommits(c *context.Context, filename string) {
 	c.Success(COMMITS)
 }
 
+// fileHistoryCommits returns a page of commits that changed the file, following
+// renames of the file. Commits that renamed the file are annotated with the
+// previous path.
+func fileHistoryCommits(c *context.Context, filename string, page, pageSize int) ([]*git.Commit, error) {
+	entries, err := gitutil.FileHistory(c.Repo.GitRepo.Path(), c.Repo.Commit.ID.String(), filename, gitutil.FileHistoryOptions{
+		Follow:   true,
+		Skip:     (page - 1) * pageSize,
+		MaxCount: pageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]*git.Commit, 0, len(entries))
+	renames := make(map[string]string)
+	for _, entry := range entries {
+		commit, err := c.Repo.GitRepo.CatFileCommit(entry.CommitID)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, commit)
+		if entry.OldPath != "" {
+			renames[entry.CommitID] = entry.OldPath
+		}
+	}
+	c.Data["FileRenames"] = renames
+	return commits, nil
+}
+
 func Commits(c *context.Context) {
 	renderCommits(c, "")
 }
@@ -97,6 +133,7 @@ func SearchCommits(c *context.Context) {
 	}
 
 	commits = RenderIssueLinks(commits, c.Repo.RepoLink)
+	mapCommitAuthors(c, commits...)
 	c.Data["Commits"] = db.ValidateCommitsWithEmails(commits)
 
 	c.Data["Keyword"] = keyword
@@ -156,7 +193,9 @@ func Diff(c *context.Context) {
 	c.Data["IsImageFile"] = commit.IsImageFile
 	c.Data["IsImageFileByIndex"] = commit.IsImageFileByIndex
 	c.Data["Commit"] = commit
+	mapCommitAuthors(c, commit)
 	c.Data["Author"] = db.ValidateCommitWithEmail(commit)
+	c.Data["CoAuthors"] = db.ValidateCoAuthorsWithEmails(commit)
 	c.Data["Diff"] = diff
 	c.Data["Parents"] = parents
 	c.Data["DiffNotAvailable"] = diff.NumFiles() == 0
@@ -210,6 +249,7 @@ func CompareDiff(c *context.Context) {
 
 	c.Data["IsSplitStyle"] = c.Query("style") == "split"
 	c.Data["CommitRepoLink"] = c.Repo.RepoLink
+	mapCommitAuthors(c, commits...)
 	c.Data["Commits"] = db.ValidateCommitsWithEmails(commits)
 	c.Data["CommitsCount"] = len(commits)
 	c.Data["BeforeCommitID"] = beforeCommitID
@@ -0,0 +1,23 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/events"
+)
+
+// Events streams live events of the repository as server-sent events, along
+// with notifications of the signed in user.
+func Events(c *context.Context) {
+	topics := []string{events.RepoTopic(c.Repo.Repository.ID)}
+	if c.IsLogged {
+		topics = append(topics, events.UserTopic(c.User.ID))
+	}
+
+	sub := events.Subscribe(topics...)
+	defer sub.Close()
+	events.ServeStream(c.Resp, c.Req.Request, sub, events.DefaultStreamOptions)
+}
@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/exec"
@@ -22,9 +23,13 @@ import (
 
 	"gogs.io/gogs/internal/auth"
 	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
 	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/federation"
+	"gogs.io/gogs/internal/gitutil"
 	"gogs.io/gogs/internal/lazyregexp"
 	"gogs.io/gogs/internal/pathutil"
+	"gogs.io/gogs/internal/repoutil"
 	"gogs.io/gogs/internal/tool"
 )
 
@@ -34,6 +39,8 @@ type HTTPContext struct {
 	OwnerSalt string
 	RepoID    int64
 	RepoName  string
+	// RepoShard is the repository shard that stores the repository.
+	RepoShard int
 	AuthUser  *db.User
 }
 
@@ -59,6 +66,7 @@ func HTTPContexter() macaron.Handler {
 
 		ownerName := c.Params(":username")
 		repoName := strings.TrimSuffix(c.Params(":reponame"), ".git")
+		isWiki := strings.HasSuffix(repoName, ".wiki")
 		repoName = strings.TrimSuffix(repoName, ".wiki")
 
 		isPull := c.Query("service") == "git-upload-pack" ||
@@ -87,6 +95,12 @@ func HTTPContexter() macaron.Handler {
 			return
 		}
 
+		// Only the wiki of tracker-only repositories is accessible through Git.
+		if repo.IsTrackerOnly && !isWiki {
+			c.Status(http.StatusNotFound)
+			return
+		}
+
 		// Authentication is not required for pulling from public repositories.
 		if isPull && !repo.IsPrivate && !conf.Auth.RequireSigninView {
 			c.Map(&HTTPContext{
@@ -130,29 +144,78 @@ func HTTPContexter() macaron.Handler {
 			return
 		}
 
-		// If username and password combination failed, try again using username as a token.
-		if authUser == nil {
-			token, err := db.AccessTokens.GetBySHA1(c.Req.Context(), authUsername)
+		// Job tokens of CI runners are passed as password with any username.
+		var jobToken *db.JobToken
+		if authUser == nil && authPassword != "" && conf.Auth.EnableJobTokens {
+			jobToken, err = db.JobTokens.GetByToken(c.Req.Context(), authPassword)
+			if err != nil && !db.IsErrJobTokenNotExist(err) {
+				c.Status(http.StatusInternalServerError)
+				log.Error("Failed to get job token: %v", err)
+				return
+			}
+		}
+
+		if jobToken != nil {
+			// Job tokens are only valid for the repository and the access mode they are
+			// issued for, and act on behalf of the user who registered the runner.
+			if jobToken.RepoID != repo.ID || (!isPull && jobToken.Mode < db.AccessModeWrite) {
+				askCredentials(c, http.StatusForbidden, "Job token permission denied")
+				return
+			}
+
+			runner, err := db.Runners.GetByID(c.Req.Context(), jobToken.RunnerID)
+			if err == nil {
+				authUser, err = db.Users.GetByID(c.Req.Context(), runner.CreatedByID)
+			}
 			if err != nil {
-				if db.IsErrAccessTokenNotExist(err) {
+				if db.IsErrRunnerNotExist(err) || db.IsErrUserNotExist(err) {
 					askCredentials(c, http.StatusUnauthorized, "")
 				} else {
 					c.Status(http.StatusInternalServerError)
-					log.Error("Failed to get access token [sha: %s]: %v", authUsername, err)
+					log.Error("Failed to get user of job token [runner_id: %d]: %v", jobToken.RunnerID, err)
 				}
 				return
 			}
-			if err = db.AccessTokens.Touch(c.Req.Context(), token.ID); err != nil {
-				log.Error("Failed to touch access token: %v", err)
+		} else if authUser == nil {
+			// If username and password combination failed, try again using username as
+			// a token, then password as a token (e.g. those cached by Git credential
+			// helpers after the device authorization flow).
+			token, err := db.AccessTokens.GetBySHA1(c.Req.Context(), authUsername)
+			if db.IsErrAccessTokenNotExist(err) && authPassword != "" {
+				token, err = db.AccessTokens.GetBySHA1(c.Req.Context(), authPassword)
 			}
-
-			authUser, err = db.Users.GetByID(c.Req.Context(), token.UserID)
-			if err != nil {
-				// Once we found token, we're supposed to find its related user,
-				// thus any error is unexpected.
-				c.Status(http.StatusInternalServerError)
-				log.Error("Failed to get user [id: %d]: %v", token.UserID, err)
+			if db.IsErrAccessTokenNotExist(err) && federation.Enabled() {
+				authUser, err = federation.Authenticate(c.Req.Context(), authUsername, authPassword)
+				if err != nil {
+					if federation.IsErrTokenInactive(err) {
+						askCredentials(c, http.StatusUnauthorized, "")
+					} else {
+						c.Status(http.StatusInternalServerError)
+						log.Error("Failed to authenticate federated token: %v", err)
+					}
+					return
+				}
+			} else if err != nil {
+				if db.IsErrAccessTokenNotExist(err) {
+					askCredentials(c, http.StatusUnauthorized, "")
+				} else {
+					c.Status(http.StatusInternalServerError)
+					log.Error("Failed to get access token [sha: %s]: %v", authUsername, err)
+				}
 				return
+			} else {
+				if err = db.AccessTokens.Touch(c.Req.Context(), token.ID); err != nil {
+					log.Error("Failed to touch access token: %v", err)
+				}
+
+				authUser, err = db.Users.GetByID(c.Req.Context(), token.UserID)
+				if err != nil {
+					// Once we found token, we're supposed to find its related user,
+					// thus any error is unexpected.
+					c.Status(http.StatusInternalServerError)
+					log.Error("Failed to get user [id: %d]: %v", token.UserID, err)
+					return
+				}
 			}
 		} else if authUser.IsEnabledTwoFactor() {
 			askCredentials(c, http.StatusUnauthorized, `User with two-factor authentication enabled cannot perform HTTP/HTTPS operations via plain username and password
@@ -160,6 +223,11 @@ Please create and use personal access token on user settings page`)
 			return
 		}
 
+		if authUser.ProhibitLogin {
+			askCredentials(c, http.StatusForbidden, "User is prohibited from logging in")
+			return
+		}
+
 		log.Trace("[Git] Authenticated user: %s", authUser.Name)
 
 		mode := db.AccessModeWrite
@@ -169,7 +237,7 @@ Please create and use personal access token on user settings page`)
 		if !db.Perms.Authorize(c.Req.Context(), authUser.ID, repo.ID, mode,
 			db.AccessModeOptions{
 				OwnerID: repo.OwnerID,
-				Private: repo.IsPrivate,
+				Private: repo.IsPrivate || db.IsTenantIsolated(authUser, owner),
 			},
 		) {
 			askCredentials(c, http.StatusForbidden, "User permission denied")
@@ -187,6 +255,7 @@ Please create and use personal access token on user settings page`)
 			OwnerSalt: owner.Salt,
 			RepoID:    repo.ID,
 			RepoName:  repoName,
+			RepoShard: repo.StorageShard,
 			AuthUser:  authUser,
 		})
 	}
@@ -198,11 +267,12 @@ type serviceHandler struct {
 	dir  string
 	file string
 
-	authUser  *db.User
-	ownerName string
-	ownerSalt string
-	repoID    int64
-	repoName  string
+	authUser   *db.User
+	remoteAddr string
+	ownerName  string
+	ownerSalt  string
+	repoID     int64
+	repoName   string
 }
 
 func (h *serviceHandler) setHeaderNoCache() {
@@ -257,9 +327,22 @@ func serviceRPC(h serviceHandler, service string) {
 		}
 	}
 
-	var stderr bytes.Buffer
-	cmd := exec.Command("git", service, "--stateless-rpc", h.dir)
-	if service == "receive-pack" {
+	var (
+		stderr           bytes.Buffer
+		uploadInspector  *gitutil.UploadPackInspector
+		receiveInspector *gitutil.ReceivePackInspector
+		startedAt        = time.Now()
+	)
+	cmd := exec.Command("git", serviceArgs(service, "--stateless-rpc", h.dir)...)
+	switch service {
+	case "upload-pack":
+		uploadInspector = gitutil.NewUploadPackInspector()
+		cmd.Stdout = io.MultiWriter(h.w, uploadInspector.ResponseWriter())
+		cmd.Stdin = io.TeeReader(reqBody, uploadInspector.RequestWriter())
+	case "receive-pack":
+		receiveInspector = gitutil.NewReceivePackInspector()
+		cmd.Stdout = h.w
+		cmd.Stdin = io.TeeReader(reqBody, receiveInspector.RequestWriter())
 		cmd.Env = append(os.Environ(), db.ComposeHookEnvs(db.ComposeHookEnvsOptions{
 			AuthUser:  h.authUser,
 			OwnerName: h.ownerName,
@@ -270,14 +353,32 @@ func serviceRPC(h serviceHandler, service string) {
 		})...)
 	}
 	cmd.Dir = h.dir
-	cmd.Stdout = h.w
 	cmd.Stderr = &stderr
-	cmd.Stdin = reqBody
 	if err = cmd.Run(); err != nil {
+		// Pushes without declared sizes only exceed the limit while being piped.
+		if context.IsBodyTooLarge(h.r) {
+			context.WriteBodyTooLarge(h.w, h.r)
+			return
+		}
 		log.Error("HTTP.serviceRPC: fail to serve RPC '%s': %v - %s", service, err, stderr.String())
 		h.w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+
+	opts := db.GitAccessOptions{
+		Protocol:   "http",
+		Doer:       h.authUser,
+		RemoteAddr: h.remoteAddr,
+		UserAgent:  h.r.UserAgent(),
+		RepoID:     h.repoID,
+		RepoName:   h.ownerName + "/" + h.repoName,
+		StartedAt:  startedAt,
+	}
+	if uploadInspector != nil {
+		db.RecordUploadPack(opts, uploadInspector)
+	} else {
+		db.RecordReceivePack(opts, receiveInspector)
+	}
 }
 
 func serviceUploadPack(h serviceHandler) {
@@ -288,6 +389,15 @@ func serviceReceivePack(h serviceHandler) {
 	serviceRPC(h, "receive-pack")
 }
 
+// serviceArgs returns the arguments of Git to run the service with given
+// arguments.
+func serviceArgs(service string, args ...string) []string {
+	if service != "upload-pack" {
+		return append([]string{service}, args...)
+	}
+	return append(append(gitutil.UploadPackConfigArgs(conf.Git.EnablePartialClone), service), args...)
+}
+
 func getServiceType(r *http.Request) string {
 	serviceType := r.FormValue("service")
 	if !strings.HasPrefix(serviceType, "git-") {
@@ -328,7 +438,7 @@ func getInfoRefs(h serviceHandler) {
 		return
 	}
 
-	refs := gitCommand(h.dir, service, "--stateless-rpc", "--advertise-refs", ".")
+	refs := gitCommand(h.dir, serviceArgs(service, "--stateless-rpc", "--advertise-refs", ".")...)
 	h.w.Header().Set("Content-Type", fmt.Sprintf("application/x-git-%s-advertisement", service))
 	h.w.WriteHeader(http.StatusOK)
 	_, _ = h.w.Write(packetWrite("# service=git-" + service + "\n"))
@@ -379,12 +489,12 @@ var routes = []struct {
 	{lazyregexp.New("(.*?)/objects/pack/pack-[0-9a-f]{40}\\.idx$"), "GET", getIdxFile},
 }
 
-func getGitRepoPath(dir string) (string, error) {
+func getGitRepoPath(root, dir string) (string, error) {
 	if !strings.HasSuffix(dir, ".git") {
 		dir += ".git"
 	}
 
-	filename := filepath.Join(conf.Repository.Root, dir)
+	filename := filepath.Join(root, dir)
 	if _, err := os.Stat(filename); os.IsNotExist(err) {
 		return "", err
 	}
@@ -420,7 +530,7 @@ func HTTP(c *HTTPContext) {
 		}
 
 		file := strings.TrimPrefix(reqPath, cleaned)
-		dir, err := getGitRepoPath(cleaned)
+		dir, err := getGitRepoPath(repoutil.ShardRoot(c.RepoShard), cleaned)
 		if err != nil {
 			log.Warn("HTTP.getGitRepoPath: %v", err)
 			c.Error(http.StatusNotFound)
@@ -433,11 +543,12 @@ func HTTP(c *HTTPContext) {
 			dir:  dir,
 			file: file,
 
-			authUser:  c.AuthUser,
-			ownerName: c.OwnerName,
-			ownerSalt: c.OwnerSalt,
-			repoID:    c.RepoID,
-			repoName:  c.RepoName,
+			authUser:   c.AuthUser,
+			remoteAddr: c.RemoteAddr(),
+			ownerName:  c.OwnerName,
+			ownerSalt:  c.OwnerSalt,
+			repoID:     c.RepoID,
+			repoName:   c.RepoName,
 		})
 		return
 	}
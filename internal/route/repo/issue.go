@@ -15,11 +15,14 @@ import (
 	"github.com/unknwon/paginater"
 	log "unknwon.dev/clog/v2"
 
+	"gogs.io/gogs/internal/antivirus"
 	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/context"
 	"gogs.io/gogs/internal/db"
 	"gogs.io/gogs/internal/db/errors"
 	"gogs.io/gogs/internal/form"
+	"gogs.io/gogs/internal/gitutil"
+	"gogs.io/gogs/internal/issueform"
 	"gogs.io/gogs/internal/markup"
 	"gogs.io/gogs/internal/tool"
 )
@@ -281,7 +284,7 @@ func RetrieveRepoMilestonesAndAssignees(c *context.Context, repo *db.Repository)
 }
 
 func RetrieveRepoMetas(c *context.Context, repo *db.Repository) []*db.Label {
-	if !c.Repo.IsWriter() {
+	if !c.Repo.CanTriage() {
 		return nil
 	}
 
@@ -337,6 +340,32 @@ func NewIssue(c *context.Context) {
 	c.Data["RequireSimpleMDE"] = true
 	c.Data["title"] = c.Query("title")
 	c.Data["content"] = c.Query("content")
+
+	if forms := issueForms(c); len(forms) > 0 {
+		if filename := c.Query("template"); filename != "" {
+			var f *issueform.Form
+			for i := range forms {
+				if forms[i].Filename == filename {
+					f = forms[i]
+					break
+				}
+			}
+			if f == nil {
+				c.NotFound()
+				return
+			}
+
+			if c.Query("title") == "" {
+				c.Data["title"] = f.Title
+			}
+			setIssueForm(c, f, nil, nil)
+		} else if !c.QueryBool("blank") {
+			c.Data["IssueForms"] = forms
+			c.Success(ISSUE_CHOOSE)
+			return
+		}
+	}
+
 	setTemplateIfExists(c, ISSUE_TEMPLATE_KEY, IssueTemplateCandidates)
 	renderAttachmentSettings(c)
 
@@ -359,7 +388,7 @@ func ValidateRepoMetas(c *context.Context, f form.NewIssue) ([]int64, int64, int
 		return nil, 0, 0
 	}
 
-	if !c.Repo.IsWriter() {
+	if !c.Repo.CanTriage() {
 		return nil, 0, 0
 	}
 
@@ -402,6 +431,27 @@ func ValidateRepoMetas(c *context.Context, f form.NewIssue) ([]int64, int64, int
 	return labelIDs, milestoneID, assigneeID
 }
 
+// IssueDuplicates returns existing issues whose titles are similar to the
+// title of a new issue, which is called by the new issue form as the user types.
+func IssueDuplicates(c *context.Context) {
+	issues, err := db.SuggestDuplicateIssues(c.Repo.Repository.ID, c.Query("title"), 5)
+	if err != nil {
+		c.Error(err, "suggest duplicate issues")
+		return
+	}
+
+	results := make([]map[string]interface{}, len(issues))
+	for i, issue := range issues {
+		results[i] = map[string]interface{}{
+			"index":     issue.Index,
+			"title":     issue.Title,
+			"is_closed": issue.IsClosed,
+			"url":       fmt.Sprintf("%s/issues/%d", c.Repo.RepoLink, issue.Index),
+		}
+	}
+	c.JSONSuccess(results)
+}
+
 func NewIssuePost(c *context.Context, f form.NewIssue) {
 	c.Data["Title"] = c.Tr("repo.issues.new")
 	c.Data["PageIsIssueList"] = true
@@ -414,11 +464,43 @@ func NewIssuePost(c *context.Context, f form.NewIssue) {
 		return
 	}
 
+	var issueForm *issueform.Form
+	var issueFormErrs []*issueform.FieldError
+	var issueFormVals map[string][]string
+	if f.Template != "" {
+		issueForm = issueFormByFilename(c, f.Template)
+		if issueForm == nil {
+			c.NotFound()
+			return
+		}
+		issueFormVals = issueFormValues(c, issueForm)
+		issueFormErrs = issueForm.Validate(issueFormVals)
+		setIssueForm(c, issueForm, issueFormVals, issueFormErrs)
+	}
+
 	if c.HasError() {
 		c.Success(ISSUE_NEW)
 		return
 	}
 
+	if issueForm != nil {
+		if len(issueFormErrs) > 0 {
+			err := issueFormErrs[0]
+			c.RenderWithErr(c.Tr("repo.issues.new.form_field_"+err.Reason, err.Label), ISSUE_NEW, &f)
+			return
+		}
+
+		// The issue form is defined by maintainers of the repository, so its
+		// labels are applied regardless of the permission of the poster.
+		formLabelIDs, err := issueFormLabelIDs(c.Repo.Repository.ID, issueForm)
+		if err != nil {
+			c.Error(err, "get labels of issue form")
+			return
+		}
+		labelIDs = append(labelIDs, formLabelIDs...)
+		f.Content = issueForm.Render(issueFormVals)
+	}
+
 	var attachments []string
 	if conf.Attachment.Enabled {
 		attachments = f.Files
@@ -442,7 +524,7 @@ func NewIssuePost(c *context.Context, f form.NewIssue) {
 	c.RawRedirect(c.Repo.MakeURL(fmt.Sprintf("issues/%d", issue.Index)))
 }
 
-func uploadAttachment(c *context.Context, allowedTypes []string) {
+func uploadAttachment(c *context.Context, kind antivirus.Kind, allowedTypes []string) {
 	file, header, err := c.Req.FormFile("file")
 	if err != nil {
 		c.Error(err, "get file")
@@ -471,6 +553,16 @@ func uploadAttachment(c *context.Context, allowedTypes []string) {
 		return
 	}
 
+	err = antivirus.Scan(c.Req.Context(), kind, header.Filename, "user "+c.User.Name, file)
+	if err != nil {
+		if antivirus.IsErrInfected(err) {
+			c.PlainText(http.StatusBadRequest, err.Error())
+		} else {
+			c.Error(err, "scan file")
+		}
+		return
+	}
+
 	attach, err := db.NewAttachment(header.Filename, buf, file)
 	if err != nil {
 		c.Error(err, "new attachment")
@@ -489,7 +581,7 @@ func UploadIssueAttachment(c *context.Context) {
 		return
 	}
 
-	uploadAttachment(c, conf.Attachment.AllowedTypes)
+	uploadAttachment(c, antivirus.KindAttachment, conf.Attachment.AllowedTypes)
 }
 
 func viewIssue(c *context.Context, isPullList bool) {
@@ -573,7 +665,7 @@ func viewIssue(c *context.Context, isPullList bool) {
 	c.Data["Labels"] = labels
 
 	// Check milestone and assignee.
-	if c.Repo.IsWriter() {
+	if c.Repo.CanTriage() {
 		RetrieveRepoMilestonesAndAssignees(c, repo)
 		if c.Written() {
 			return
@@ -635,15 +727,10 @@ func viewIssue(c *context.Context, isPullList bool) {
 
 	if issue.IsPull && issue.PullRequest.HasMerged {
 		pull := issue.PullRequest
-		branchProtected := false
-		protectBranch, err := db.GetProtectBranchOfRepoByName(pull.BaseRepoID, pull.HeadBranch)
+		branchProtected, err := c.Repo.Repository.IsBranchDeletionBlocked(pull.HeadBranch)
 		if err != nil {
-			if !db.IsErrBranchNotExist(err) {
-				c.Error(err, "get protect branch of repository by name")
-				return
-			}
-		} else {
-			branchProtected = protectBranch.Protected
+			c.Error(err, "check if branch deletion is blocked")
+			return
 		}
 
 		c.Data["IsPullBranchDeletable"] = pull.BaseRepoID == pull.HeadRepoID &&
@@ -656,10 +743,66 @@ func viewIssue(c *context.Context, isPullList bool) {
 		})
 	}
 
+	if issue.IsPull && !issue.PullRequest.HasMerged && !issue.IsClosed {
+		entry, err := db.MergeQueueEntries.GetByPullRequestID(c.Req.Context(), issue.PullRequest.ID)
+		if err != nil && !db.IsErrMergeQueueEntryNotExist(err) {
+			c.Error(err, "get merge queue entry")
+			return
+		} else if err == nil {
+			position, err := db.MergeQueuePosition(entry)
+			if err != nil {
+				c.Error(err, "get merge queue position")
+				return
+			}
+			c.Data["MergeQueueEntry"] = entry
+			c.Data["MergeQueuePosition"] = position
+		}
+
+		missing, err := issue.PullRequest.MissingRequiredDeployments()
+		if err != nil {
+			c.Error(err, "get missing required deployments")
+			return
+		}
+		c.Data["MissingDeployments"] = strings.Join(missing, ", ")
+	}
+
+	if issue.IsPull {
+		checklist, err := issue.PullRequest.MergeChecklist(c.Req.Context())
+		if err != nil {
+			c.Error(err, "get merge checklist")
+			return
+		}
+		c.Data["MergeChecklist"] = checklist
+		c.Data["MergeChecklistIncomplete"] = !db.IsMergeChecklistComplete(checklist)
+		c.Data["CanCheckMergeChecklist"] = canCheckMergeChecklist(c, issue)
+	}
+
+	if issue.IsPull && !issue.IsClosed {
+		ds, err := db.Deployments.ListPullRequestEnvironments(c.Req.Context(), c.Repo.Repository.ID, issue.Index)
+		if err != nil {
+			c.Error(err, "list pull request environments")
+			return
+		}
+		previews := make([]*db.Deployment, 0, len(ds))
+		for _, d := range ds {
+			if d.IsPreviewable() {
+				previews = append(previews, d)
+			}
+		}
+		c.Data["PreviewDeployments"] = previews
+	}
+
+	reactions, err := db.Reactions.List(c.Req.Context(), issue.ID)
+	if err != nil {
+		c.Error(err, "list reactions")
+		return
+	}
+	c.Data["Reactions"] = db.SummarizeReactions(reactions, c.UserID())
+
 	c.Data["Participants"] = participants
 	c.Data["NumParticipants"] = len(participants)
 	c.Data["Issue"] = issue
-	c.Data["IsIssueOwner"] = c.Repo.IsWriter() || (c.IsLogged && issue.IsPoster(c.User.ID))
+	c.Data["IsIssueOwner"] = c.Repo.CanTriage() || (c.IsLogged && issue.IsPoster(c.User.ID))
 	c.Data["SignInLink"] = conf.Server.Subpath + "/user/login?redirect_to=" + c.Data["Link"].(string)
 	c.Success(ISSUE_VIEW)
 }
@@ -688,6 +831,50 @@ func getActionIssue(c *context.Context) *db.Issue {
 	return issue
 }
 
+// ToggleIssueReaction adds the reaction of the user to the issue, or removes
+// it when the user has given the reaction.
+func ToggleIssueReaction(c *context.Context) {
+	issue := getActionIssue(c)
+	if c.Written() {
+		return
+	}
+
+	content := c.Query("content")
+	if !db.IsValidReactionContent(content) {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+
+	reactions, err := db.Reactions.List(c.Req.Context(), issue.ID)
+	if err != nil {
+		c.Error(err, "list reactions")
+		return
+	}
+	reacted := false
+	for _, r := range reactions {
+		if r.UserID == c.User.ID && r.Content == content {
+			reacted = true
+			break
+		}
+	}
+
+	if reacted {
+		err = db.Reactions.Delete(c.Req.Context(), issue.ID, c.User.ID, content)
+	} else {
+		err = db.Reactions.Create(c.Req.Context(), issue.ID, c.User.ID, content)
+	}
+	if err != nil {
+		c.Error(err, "toggle reaction")
+		return
+	}
+
+	typeName := "issues"
+	if issue.IsPull {
+		typeName = "pulls"
+	}
+	c.RawRedirect(c.Repo.MakeURL(fmt.Sprintf("%s/%d", typeName, issue.Index)))
+}
+
 func UpdateIssueTitle(c *context.Context) {
 	issue := getActionIssue(c)
 	if c.Written() {
@@ -726,6 +913,10 @@ func UpdateIssueContent(c *context.Context) {
 		return
 	}
 
+	if respondContentConflict(c, issue.Content) {
+		return
+	}
+
 	content := c.Query("content")
 	if err := issue.ChangeContent(c.User, content); err != nil {
 		c.Error(err, "change content")
@@ -733,10 +924,51 @@ func UpdateIssueContent(c *context.Context) {
 	}
 
 	c.JSONSuccess(map[string]string{
-		"content": string(markup.Markdown(issue.Content, c.Query("context"), c.Repo.Repository.ComposeMetas())),
+		"content":  string(markup.Markdown(issue.Content, c.Query("context"), c.Repo.Repository.ComposeMetas())),
+		"revision": issue.ContentRevision(),
 	})
 }
 
+// respondContentConflict responds with status 409 and returns true if the
+// current content has been changed by others since the revision that the
+// editor started from, which is given by the "revision" of the request.
+// Requests without a revision always overwrite the current content.
+//
+// The response contains the current content along with its revision, and the
+// result of merging the submitted content with the current content when the
+// "original" content that the editor started from is also given. The merged
+// content contains conflict markers unless "clean" is true.
+func respondContentConflict(c *context.Context, current string) bool {
+	revision := c.Query("revision")
+	currentRevision := db.ContentRevision(current)
+	if revision == "" || revision == currentRevision {
+		return false
+	}
+
+	resp := map[string]interface{}{
+		"conflict": true,
+		"revision": currentRevision,
+		"content":  current,
+		"rendered": string(markup.Markdown(current, c.Query("context"), c.Repo.Repository.ComposeMetas())),
+	}
+	original := c.Query("original")
+	if db.ContentRevision(original) == revision {
+		merged, clean, err := gitutil.MergeText(original, c.Query("content"), current, gitutil.MergeTextLabels{
+			Ours:   c.Tr("repo.issues.edit_conflict_yours"),
+			Base:   c.Tr("repo.issues.edit_conflict_original"),
+			Theirs: c.Tr("repo.issues.edit_conflict_theirs"),
+		})
+		if err != nil {
+			log.Error("Failed to merge content: %v", err)
+		} else {
+			resp["merged"] = merged
+			resp["clean"] = clean
+		}
+	}
+	c.JSON(http.StatusConflict, resp)
+	return true
+}
+
 func UpdateIssueLabel(c *context.Context) {
 	issue := getActionIssue(c)
 	if c.Written() {
@@ -842,11 +1074,29 @@ func NewComment(c *context.Context, f form.CreateComment) {
 		return
 	}
 
+	// Quick actions are checked before anything is written, so that the
+	// comment is not posted when any of them cannot be applied.
+	cmds, content := db.ParseCommentCommands(f.Content)
+	var actions *db.CommentActions
+	if len(cmds) > 0 {
+		var err error
+		actions, err = db.ResolveCommentCommands(c.User, c.Repo.Repository, issue, c.Repo.CanTriage(), cmds)
+		if err != nil {
+			if db.IsErrCommentCommandInvalid(err) || db.IsErrCommentCommandNotPermitted(err) {
+				c.Flash.Error(c.Tr("repo.issues.comment_commands_failed", err.Error()))
+				c.RawRedirect(c.Repo.MakeURL(fmt.Sprintf("issues/%d", issue.Index)))
+			} else {
+				c.Error(err, "resolve comment commands")
+			}
+			return
+		}
+	}
+
 	var err error
 	var comment *db.Comment
 	defer func() {
 		// Check if issue admin/poster changes the status of issue.
-		if (c.Repo.IsWriter() || (c.IsLogged && issue.IsPoster(c.User.ID))) &&
+		if (c.Repo.CanTriage() || (c.IsLogged && issue.IsPoster(c.User.ID))) &&
 			(f.Status == "reopen" || f.Status == "close") &&
 			!(issue.IsPull && issue.PullRequest.HasMerged) {
 
@@ -902,6 +1152,16 @@ func NewComment(c *context.Context, f form.CreateComment) {
 		c.RawRedirect(c.Repo.MakeURL(location))
 	}()
 
+	if actions != nil {
+		comment, err = db.CreateIssueCommentWithActions(c.User, c.Repo.Repository, issue, content, attachments, actions)
+		if err != nil {
+			c.Error(err, "create issue comment with actions")
+			return
+		}
+		log.Trace("Quick actions applied: %d/%d", c.Repo.Repository.ID, issue.ID)
+		return
+	}
+
 	// Fix #321: Allow empty comments, as long as we have attachments.
 	if f.Content == "" && len(attachments) == 0 {
 		return
@@ -939,13 +1199,17 @@ func UpdateCommentContent(c *context.Context) {
 		})
 		return
 	}
+	if respondContentConflict(c, oldContent) {
+		return
+	}
 	if err = db.UpdateComment(c.User, comment, oldContent); err != nil {
 		c.Error(err, "update comment")
 		return
 	}
 
 	c.JSONSuccess(map[string]string{
-		"content": string(markup.Markdown(comment.Content, c.Query("context"), c.Repo.Repository.ComposeMetas())),
+		"content":  string(markup.Markdown(comment.Content, c.Query("context"), c.Repo.Repository.ComposeMetas())),
+		"revision": comment.ContentRevision(),
 	})
 }
 
@@ -977,7 +1241,7 @@ func Labels(c *context.Context) {
 	c.Data["PageIsIssueList"] = true
 	c.Data["PageIsLabels"] = true
 	c.Data["RequireMinicolors"] = true
-	c.Data["LabelTemplates"] = db.LabelTemplates
+	c.Data["LabelTemplates"] = db.LabelTemplates()
 	c.Success(LABELS)
 }
 
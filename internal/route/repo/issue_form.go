@@ -0,0 +1,167 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/issueform"
+	"gogs.io/gogs/internal/markup"
+)
+
+const (
+	ISSUE_CHOOSE = "repo/issue/choose"
+)
+
+// issueForms returns issue forms in the default branch of the repository, from
+// the first directory that has any. Invalid forms are skipped.
+func issueForms(c *context.Context) []*issueform.Form {
+	if c.Repo.Commit == nil {
+		var err error
+		c.Repo.Commit, err = c.Repo.GitRepo.BranchCommit(c.Repo.Repository.DefaultBranch)
+		if err != nil {
+			return nil
+		}
+	}
+
+	for _, dir := range issueform.Dirs {
+		tree, err := c.Repo.Commit.Subtree(dir)
+		if err != nil {
+			continue
+		}
+		entries, err := tree.Entries()
+		if err != nil {
+			continue
+		}
+
+		var forms []*issueform.Form
+		for _, entry := range entries {
+			if !entry.IsBlob() || !issueform.IsForm(entry.Name()) {
+				continue
+			}
+			p, err := entry.Blob().Bytes()
+			if err != nil {
+				log.Error("Failed to read issue form %q of repository [%d]: %v", entry.Name(), c.Repo.Repository.ID, err)
+				continue
+			}
+			f, err := issueform.Parse(entry.Name(), p)
+			if err != nil {
+				log.Trace("Invalid issue form %q of repository [%d]: %v", entry.Name(), c.Repo.Repository.ID, err)
+				continue
+			}
+			forms = append(forms, f)
+		}
+		if len(forms) > 0 {
+			sort.Slice(forms, func(i, j int) bool {
+				return forms[i].Filename < forms[j].Filename
+			})
+			return forms
+		}
+	}
+	return nil
+}
+
+// issueFormByFilename returns the issue form with given file name, or nil if
+// it does not exist.
+func issueFormByFilename(c *context.Context, filename string) *issueform.Form {
+	for _, f := range issueForms(c) {
+		if f.Filename == filename {
+			return f
+		}
+	}
+	return nil
+}
+
+// issueFormField is an element of an issue form with its submitted value to
+// be rendered in the new issue page.
+type issueFormField struct {
+	*issueform.Element
+	Name            string
+	Value           string
+	Selected        map[int]bool
+	RenderedContent string
+	HasError        bool
+}
+
+// issueFormValues returns submitted values of fields of the issue form,
+// keyed by IDs of elements.
+func issueFormValues(c *context.Context, f *issueform.Form) map[string][]string {
+	values := make(map[string][]string, len(f.Body))
+	for _, e := range f.Body {
+		if e.IsField() {
+			values[e.ID] = c.QueryStrings("form_" + e.ID)
+		}
+	}
+	return values
+}
+
+// setIssueForm sets the issue form with submitted values and errors to be
+// rendered in the new issue page. Values are nil when the form is not
+// submitted yet.
+func setIssueForm(c *context.Context, f *issueform.Form, values map[string][]string, errs []*issueform.FieldError) {
+	hasError := make(map[string]bool, len(errs))
+	for _, err := range errs {
+		hasError[err.ID] = true
+	}
+
+	fields := make([]*issueFormField, len(f.Body))
+	for i, e := range f.Body {
+		field := &issueFormField{
+			Element:  e,
+			Name:     "form_" + e.ID,
+			Selected: make(map[int]bool),
+			HasError: hasError[e.ID],
+		}
+		switch {
+		case !e.IsField():
+			field.RenderedContent = string(markup.Markdown(e.Attributes.Value, c.Repo.RepoLink, c.Repo.Repository.ComposeMetas()))
+		case values == nil:
+			field.Value = e.Attributes.Value
+		default:
+			for _, v := range values[e.ID] {
+				field.Value = v
+				for j := range e.Attributes.Options {
+					if v == strconv.Itoa(j) {
+						field.Selected[j] = true
+					}
+				}
+			}
+		}
+		fields[i] = field
+	}
+
+	c.Data["IssueForm"] = f
+	c.Data["IssueFormFields"] = fields
+}
+
+// issueFormLabelIDs returns IDs of labels in the repository that are named by
+// the issue form.
+func issueFormLabelIDs(repoID int64, f *issueform.Form) ([]int64, error) {
+	if len(f.Labels) == 0 {
+		return nil, nil
+	}
+
+	labels, err := db.GetLabelsByRepoID(repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	for _, name := range f.Labels {
+		for _, label := range labels {
+			if strings.EqualFold(label.Name, strings.TrimSpace(name)) {
+				ids = append(ids, label.ID)
+				break
+			}
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net"
+	"os"
+	"path"
+	"strings"
+
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/lazyregexp"
+	"gogs.io/gogs/internal/pages"
+)
+
+const SETTINGS_PAGES = "repo/settings/pages"
+
+var pagesDomainPattern = lazyregexp.New(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+func MustEnablePages(c *context.Context) {
+	if !conf.Pages.Enabled {
+		c.NotFound()
+		return
+	}
+}
+
+func SettingsPages(c *context.Context) {
+	c.Data["Title"] = c.Tr("repo.settings.pages")
+	c.Data["PageIsSettingsPages"] = true
+	c.Data["PagesDomain"] = conf.Pages.Domain
+	c.Data["MaxArtifactSize"] = conf.Pages.MaxArtifactSize
+
+	site, err := db.PagesSites.GetByRepoID(c.Req.Context(), c.Repo.Repository.ID)
+	if err != nil && !db.IsErrPagesSiteNotExist(err) {
+		c.Error(err, "get pages site")
+		return
+	}
+	c.Data["PagesBranch"] = c.Repo.Repository.DefaultBranch
+	if site != nil {
+		c.Data["Site"] = site
+		c.Data["SiteURL"] = pages.SiteURL(c.Repo.Owner.Name, c.Repo.Repository.Name, site)
+		if site.Branch != "" {
+			c.Data["PagesBranch"] = site.Branch
+		}
+	}
+	c.Data["ArtifactAPIURL"] = conf.Server.ExternalURL + "api/v1/repos/" + c.Repo.Owner.Name + "/" + c.Repo.Repository.Name + "/pages/artifact"
+	c.Success(SETTINGS_PAGES)
+}
+
+func SettingsPagesPost(c *context.Context) {
+	redirectTo := c.Repo.RepoLink + "/settings/pages"
+
+	opts := db.SavePagesSiteOptions{
+		Source:       db.PagesSource(c.Query("source")),
+		Branch:       strings.TrimSpace(c.Query("branch")),
+		Directory:    strings.Trim(path.Clean("/"+c.Query("directory")), "/"),
+		CustomDomain: db.NormalizeDomain(c.Query("custom_domain")),
+	}
+	if !opts.Source.IsValid() {
+		c.Flash.Error(c.Tr("repo.settings.pages.invalid_source"))
+		c.Redirect(redirectTo)
+		return
+	}
+
+	if opts.Source == db.PagesSourceBranch {
+		if c.Repo.Repository.IsBare || !c.Repo.GitRepo.HasBranch(opts.Branch) {
+			c.Flash.Error(c.Tr("repo.settings.pages.branch_not_exist", opts.Branch))
+			c.Redirect(redirectTo)
+			return
+		}
+	} else {
+		opts.Branch = ""
+	}
+
+	if opts.CustomDomain != "" {
+		if len(opts.CustomDomain) > 253 ||
+			!pagesDomainPattern.MatchString(opts.CustomDomain) ||
+			opts.CustomDomain == conf.Pages.Domain ||
+			strings.HasSuffix(opts.CustomDomain, "."+conf.Pages.Domain) ||
+			opts.CustomDomain == conf.Server.URL.Hostname() {
+			c.Flash.Error(c.Tr("repo.settings.pages.invalid_domain"))
+			c.Redirect(redirectTo)
+			return
+		}
+	}
+
+	_, err := db.PagesSites.Save(c.Req.Context(), c.Repo.Repository.ID, opts)
+	if err != nil {
+		if db.IsErrPagesDomainAlreadyUsed(err) {
+			c.Flash.Error(c.Tr("repo.settings.pages.domain_already_used", opts.CustomDomain))
+			c.Redirect(redirectTo)
+		} else {
+			c.Error(err, "save pages site")
+		}
+		return
+	}
+
+	log.Trace("Pages site of repository updated [repo_id: %d]: %s", c.Repo.Repository.ID, opts.Source)
+	c.Flash.Success(c.Tr("repo.settings.update_settings_success"))
+	c.Redirect(redirectTo)
+}
+
+func SettingsPagesVerify(c *context.Context) {
+	redirectTo := c.Repo.RepoLink + "/settings/pages"
+
+	site, err := db.PagesSites.GetByRepoID(c.Req.Context(), c.Repo.Repository.ID)
+	if err != nil {
+		c.NotFoundOrError(err, "get pages site")
+		return
+	} else if site.CustomDomain == "" {
+		c.Redirect(redirectTo)
+		return
+	}
+
+	verified := false
+	records, err := net.DefaultResolver.LookupTXT(c.Req.Context(), site.TXTRecordName())
+	if err != nil {
+		log.Trace("Failed to look up TXT records of %q: %v", site.TXTRecordName(), err)
+	}
+	for _, record := range records {
+		if record == site.TXTRecordValue() {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		c.Flash.Error(c.Tr("repo.settings.pages.verify_failed", site.CustomDomain))
+		c.Redirect(redirectTo)
+		return
+	}
+
+	if err = db.PagesSites.MarkDomainVerified(c.Req.Context(), c.Repo.Repository.ID); err != nil {
+		c.Error(err, "mark domain as verified")
+		return
+	}
+
+	log.Trace("Custom domain of pages site verified [repo_id: %d]: %s", c.Repo.Repository.ID, site.CustomDomain)
+	c.Flash.Success(c.Tr("repo.settings.pages.verify_success", site.CustomDomain))
+	c.Redirect(redirectTo)
+}
+
+func SettingsPagesDelete(c *context.Context) {
+	repoID := c.Repo.Repository.ID
+	if err := db.PagesSites.DeleteByRepoID(c.Req.Context(), repoID); err != nil {
+		c.Error(err, "delete pages site")
+		return
+	}
+	if err := os.Remove(db.PagesArtifactPath(repoID)); err != nil && !os.IsNotExist(err) {
+		log.Error("Failed to remove pages artifact [repo_id: %d]: %v", repoID, err)
+	}
+
+	c.Flash.Success(c.Tr("repo.settings.pages.deletion_success"))
+	c.Redirect(c.Repo.RepoLink + "/settings/pages")
+}
+
+// PagesAuth signs the user in to the pages site of the private repository by
+// redirecting to the site with a short-lived token.
+func PagesAuth(c *context.Context) {
+	site, err := db.PagesSites.GetByRepoID(c.Req.Context(), c.Repo.Repository.ID)
+	if err != nil {
+		c.NotFoundOrError(err, "get pages site")
+		return
+	}
+
+	siteURL := pages.SiteURL(c.Repo.Owner.Name, c.Repo.Repository.Name, site)
+	c.Redirect(pages.AuthURL(siteURL, c.Repo.Repository.ID, c.User.ID, c.Query("path")))
+}
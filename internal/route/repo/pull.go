@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/unknwon/com"
 	log "unknwon.dev/clog/v2"
 
@@ -20,6 +21,7 @@ import (
 	"gogs.io/gogs/internal/db"
 	"gogs.io/gogs/internal/form"
 	"gogs.io/gogs/internal/gitutil"
+	"gogs.io/gogs/internal/tool"
 )
 
 const (
@@ -291,6 +293,7 @@ func ViewPullCommits(c *context.Context) {
 		commits = prInfo.Commits
 	}
 
+	mapCommitAuthors(c, commits...)
 	c.Data["Commits"] = db.ValidateCommitsWithEmails(commits)
 	c.Data["CommitsCount"] = len(commits)
 
@@ -418,6 +421,43 @@ func MergePullRequest(c *context.Context) {
 
 	pr.Issue = issue
 	pr.Issue.Repo = c.Repo.Repository
+
+	missing, err := pr.MissingRequiredDeployments()
+	if err != nil {
+		c.Error(err, "get missing required deployments")
+		return
+	} else if len(missing) > 0 {
+		c.Flash.Error(c.Tr("repo.pulls.required_deployments_missing", strings.Join(missing, ", ")))
+		c.Redirect(c.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
+		return
+	}
+
+	checklist, err := pr.MergeChecklist(c.Req.Context())
+	if err != nil {
+		c.Error(err, "get merge checklist")
+		return
+	} else if !db.IsMergeChecklistComplete(checklist) {
+		c.Flash.Error(c.Tr("repo.pulls.merge_checklist_incomplete"))
+		c.Redirect(c.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
+		return
+	}
+
+	if c.Repo.Repository.EnableMergeQueue {
+		if err = db.EnqueuePullRequest(c.User, pr, c.Query("commit_description")); err != nil {
+			if db.IsErrMergeQueueEntryAlreadyExist(err) {
+				c.Flash.Info(c.Tr("repo.pulls.merge_queue.already_queued"))
+			} else {
+				c.Error(err, "enqueue pull request")
+				return
+			}
+		} else {
+			log.Trace("Pull request added to merge queue: %d", pr.ID)
+			c.Flash.Success(c.Tr("repo.pulls.merge_queue.add_success"))
+		}
+		c.Redirect(c.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
+		return
+	}
+
 	if err = pr.Merge(c.User, c.Repo.GitRepo, db.MergeStyle(c.Query("merge_style")), c.Query("commit_description")); err != nil {
 		c.Error(err, "merge")
 		return
@@ -427,6 +467,65 @@ func MergePullRequest(c *context.Context) {
 	c.Redirect(c.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
 }
 
+// canCheckMergeChecklist returns true if the current user can check off items
+// of the merge checklist of the pull request, i.e. the author or a reviewer
+// while the pull request is open.
+func canCheckMergeChecklist(c *context.Context, issue *db.Issue) bool {
+	if !c.IsLogged || issue.IsClosed || issue.PullRequest.HasMerged {
+		return false
+	}
+	return c.Repo.IsWriter() || issue.IsPoster(c.User.ID)
+}
+
+func UpdateMergeChecklist(c *context.Context) {
+	issue := checkPullInfo(c)
+	if c.Written() {
+		return
+	}
+	if !canCheckMergeChecklist(c, issue) {
+		c.NotFound()
+		return
+	}
+
+	items := db.ParseMergeChecklist(c.Repo.Repository.MergeChecklist)
+	i := c.QueryInt("item")
+	if i < 0 || i >= len(items) {
+		c.NotFound()
+		return
+	}
+
+	checked := c.Query("checked") == "on"
+	if err := db.MergeChecklistChecks.Set(c.Req.Context(), issue.PullRequest.ID, items[i], c.User.ID, checked); err != nil {
+		c.Error(err, "set merge checklist check")
+		return
+	}
+
+	log.Trace("Merge checklist item %q of pull request %d set to %v by %q", items[i], issue.PullRequest.ID, checked, c.User.Name)
+	c.Redirect(c.Repo.RepoLink + "/pulls/" + com.ToStr(issue.Index))
+}
+
+func RemoveFromMergeQueue(c *context.Context) {
+	issue := checkPullInfo(c)
+	if c.Written() {
+		return
+	}
+
+	pr, err := db.GetPullRequestByIssueID(issue.ID)
+	if err != nil {
+		c.NotFoundOrError(err, "get pull request by issue ID")
+		return
+	}
+
+	if err = db.DequeuePullRequest(pr); err != nil {
+		c.Error(err, "dequeue pull request")
+		return
+	}
+
+	log.Trace("Pull request removed from merge queue: %d", pr.ID)
+	c.Flash.Success(c.Tr("repo.pulls.merge_queue.remove_success"))
+	c.Redirect(c.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
+}
+
 func ParseCompareInfo(c *context.Context) (*db.User, *db.Repository, *git.Repository, *gitutil.PullRequestMeta, string, string) {
 	baseRepo := c.Repo.Repository
 
@@ -590,6 +689,7 @@ func PrepareCompareDiff(
 		return false
 	}
 
+	mapCommitAuthors(c, meta.Commits...)
 	c.Data["Commits"] = db.ValidateCommitsWithEmails(meta.Commits)
 	c.Data["CommitCount"] = len(meta.Commits)
 	c.Data["Username"] = headUser.Name
@@ -707,6 +807,26 @@ func CompareAndPullRequestPost(c *context.Context, f form.NewIssue) {
 		return
 	}
 
+	reviewers := pullRequestReviewers(c, headGitRepo, meta.MergeBase, headBranch)
+	if c.Written() {
+		return
+	}
+	// The explicitly chosen assignee takes precedence, then the first reviewer
+	// who is able to be assigned.
+	if assigneeID == 0 {
+		for _, u := range reviewers {
+			if db.Perms.Authorize(c.Req.Context(), u.ID, repo.ID, db.AccessModeWrite,
+				db.AccessModeOptions{
+					OwnerID: repo.OwnerID,
+					Private: repo.IsPrivate,
+				},
+			) {
+				assigneeID = u.ID
+				break
+			}
+		}
+	}
+
 	pullIssue := &db.Issue{
 		RepoID:      repo.ID,
 		Index:       repo.NextIssueIndex(),
@@ -718,6 +838,10 @@ func CompareAndPullRequestPost(c *context.Context, f form.NewIssue) {
 		IsPull:      true,
 		Content:     f.Content,
 	}
+	reviewerIDs := make([]int64, len(reviewers))
+	for i := range reviewers {
+		reviewerIDs[i] = reviewers[i].ID
+	}
 	pullRequest := &db.PullRequest{
 		HeadRepoID:   headRepo.ID,
 		BaseRepoID:   repo.ID,
@@ -728,6 +852,7 @@ func CompareAndPullRequestPost(c *context.Context, f form.NewIssue) {
 		BaseRepo:     repo,
 		MergeBase:    meta.MergeBase,
 		Type:         db.PULL_REQUEST_GOGS,
+		ReviewerIDs:  strings.Join(tool.Int64sToStrings(reviewerIDs), ","),
 	}
 	// FIXME: check error in the case two people send pull request at almost same time, give nice error prompt
 	// instead of 500.
@@ -739,6 +864,37 @@ func CompareAndPullRequestPost(c *context.Context, f form.NewIssue) {
 		return
 	}
 
+	if err := pullIssue.RequestReviews(c.User, reviewers); err != nil {
+		log.Error("Failed to request reviews for pull request %d: %v", pullIssue.ID, err)
+	}
+
 	log.Trace("Pull request created: %d/%d", repo.ID, pullIssue.ID)
 	c.Redirect(c.Repo.RepoLink + "/pulls/" + com.ToStr(pullIssue.Index))
 }
+
+// pullRequestReviewers returns reviewers for a new pull request, based on the
+// CODEOWNERS file on the base branch and changed files, or the default reviewers
+// of the repository.
+func pullRequestReviewers(c *context.Context, headGitRepo *git.Repository, mergeBase, headBranch string) []*db.User {
+	var owners []string
+	codeowners, err := c.Repo.Codeowners()
+	if err != nil {
+		if !gitutil.IsErrRevisionNotExist(errors.Cause(err)) {
+			log.Warn("pullRequestReviewers.Codeowners [repo_id: %d]: %v", c.Repo.Repository.ID, err)
+		}
+	} else {
+		files, err := headGitRepo.DiffNameOnly(mergeBase, headBranch)
+		if err != nil {
+			c.Error(err, "get changed files")
+			return nil
+		}
+		owners = codeowners.Owners(files...)
+	}
+
+	reviewers, err := c.Repo.Repository.PullRequestReviewers(owners)
+	if err != nil {
+		c.Error(err, "get pull request reviewers")
+		return nil
+	}
+	return reviewers
+}
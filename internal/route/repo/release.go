@@ -11,6 +11,7 @@ import (
 	"github.com/gogs/git-module"
 	log "unknwon.dev/clog/v2"
 
+	"gogs.io/gogs/internal/antivirus"
 	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/context"
 	"gogs.io/gogs/internal/db"
@@ -22,8 +23,16 @@ import (
 const (
 	RELEASES    = "repo/release/list"
 	RELEASE_NEW = "repo/release/new"
+	RELEASE_TAG = "repo/release/tag"
 )
 
+func MustEnableReleases(c *context.Context) {
+	if !c.Repo.Repository.EnableReleases {
+		c.NotFound()
+		return
+	}
+}
+
 // calReleaseNumCommitsBehind calculates given release has how many commits behind release target.
 func calReleaseNumCommitsBehind(repoCtx *context.Repository, release *db.Release, countCache map[string]int64) error {
 	// Get count if not exists
@@ -91,7 +100,12 @@ func Releases(c *context.Context) {
 
 		// No published release matches this tag
 		if results[i] == nil {
-			commit, err := c.Repo.GitRepo.TagCommit(rawTag)
+			tag, err := c.Repo.GitRepo.Tag(rawTag)
+			if err != nil {
+				c.Error(err, "get tag")
+				return
+			}
+			commit, err := tag.Commit()
 			if err != nil {
 				c.Error(err, "get tag commit")
 				return
@@ -102,6 +116,9 @@ func Releases(c *context.Context) {
 				TagName: rawTag,
 				Sha1:    commit.ID.String(),
 			}
+			if tag.Type() == git.ObjectTag {
+				results[i].TagMessage, _ = gitutil.SplitTagSignature(tag.Message())
+			}
 
 			results[i].NumCommits, err = commit.CommitsCount()
 			if err != nil {
@@ -151,6 +168,41 @@ func Releases(c *context.Context) {
 	c.Success(RELEASES)
 }
 
+// ReleaseTag shows details of a tag, including the message and the signature
+// status for annotated tags.
+func ReleaseTag(c *context.Context) {
+	tagName := c.Params("*")
+	c.Data["Title"] = tagName + " · " + c.Tr("repo.release.releases")
+	c.Data["PageIsViewFiles"] = true
+	c.Data["PageIsReleaseList"] = true
+
+	tag, err := c.Repo.GitRepo.Tag(tagName)
+	if err != nil {
+		c.NotFoundOrError(gitutil.NewError(err), "get tag")
+		return
+	}
+	commit, err := tag.Commit()
+	if err != nil {
+		c.Error(err, "get tag commit")
+		return
+	}
+	mapCommitAuthors(c, commit)
+
+	c.Data["TagName"] = tagName
+	c.Data["Tag"] = tag
+	c.Data["Commit"] = commit
+	c.Data["IsAnnotated"] = tag.Type() == git.ObjectTag
+	if tag.Type() == git.ObjectTag {
+		message, signature := gitutil.SplitTagSignature(tag.Message())
+		c.Data["TagMessage"] = message
+		c.Data["SignatureStatus"] = string(gitutil.VerifyTag(c.Repo.GitRepo.Path(), tagName, signature))
+		if tagger := tag.Tagger(); tagger != nil {
+			c.Data["Tagger"] = db.ValidateCommitWithEmail(&git.Commit{Author: tagger})
+		}
+	}
+	c.Success(RELEASE_TAG)
+}
+
 func renderReleaseAttachmentSettings(c *context.Context) {
 	c.Data["RequireDropzone"] = true
 	c.Data["IsAttachmentEnabled"] = conf.Release.Attachment.Enabled
@@ -275,6 +327,7 @@ func EditReleasePost(c *context.Context, f form.EditRelease) {
 		c.NotFoundOrError(err, "get release")
 		return
 	}
+	c.Data["ID"] = rel.ID
 	c.Data["tag_name"] = rel.TagName
 	c.Data["tag_target"] = rel.Target
 	c.Data["title"] = rel.Title
@@ -310,7 +363,7 @@ func UploadReleaseAttachment(c *context.Context) {
 		c.NotFound()
 		return
 	}
-	uploadAttachment(c, conf.Release.Attachment.AllowedTypes)
+	uploadAttachment(c, antivirus.KindReleaseAttachment, conf.Release.Attachment.AllowedTypes)
 }
 
 func DeleteRelease(c *context.Context) {
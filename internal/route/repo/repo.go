@@ -16,10 +16,13 @@ import (
 
 	"github.com/gogs/git-module"
 
+	"gogs.io/gogs/internal/archiveutil"
 	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/context"
 	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/downloadlimit"
 	"gogs.io/gogs/internal/form"
+	"gogs.io/gogs/internal/osutil"
 	"gogs.io/gogs/internal/tool"
 )
 
@@ -70,22 +73,146 @@ func Create(c *context.Context) {
 	c.RequireAutosize()
 
 	// Give default value for template to render.
-	c.Data["Gitignores"] = db.Gitignores
-	c.Data["Licenses"] = db.Licenses
-	c.Data["Readmes"] = db.Readmes
+	c.Data["Gitignores"] = db.Gitignores()
+	c.Data["Licenses"] = db.Licenses()
+	c.Data["Readmes"] = db.Readmes()
 	c.Data["readme"] = "Default"
 	c.Data["private"] = c.User.LastRepoVisibility
 	c.Data["IsForcedPrivate"] = conf.Repository.ForcePrivate
+	setInitialContentData(c)
 
 	ctxUser := checkContextUser(c, c.QueryInt64("org"))
 	if c.Written() {
 		return
 	}
 	c.Data["ContextUser"] = ctxUser
+	setNamingConventionData(c, ctxUser)
 
 	c.Success(CREATE)
 }
 
+// setNamingConventionData sets data to show naming conventions of new
+// repositories of the owner.
+func setNamingConventionData(c *context.Context, owner *db.User) {
+	c.Data["MaxRepoTopics"] = db.MaxRepoTopics
+	c.Data["RepoNamingConventions"] = db.RepoNamingConventions(owner)
+}
+
+func setInitialContentData(c *context.Context) {
+	c.Data["IsArchiveUploadEnabled"] = conf.Repository.Upload.Enabled
+	c.Data["ArchiveMaxSize"] = conf.Repository.Upload.ArchiveMaxSize
+	c.Data["CanCreateFromLocalPath"] = c.User.CanImportLocal()
+}
+
+// hasFiles returns true if the given directory contains anything other than
+// the ".git" directory.
+func hasFiles(dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+	for _, e := range entries {
+		if e.Name() != ".git" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// prepareInitialContent returns the path of the directory whose files become
+// the initial commit of the new repository, either extracted from the uploaded
+// archive or given as a server path. It returns an empty path when neither is
+// specified. The caller must remove the directory when "cleanup" is true.
+func prepareInitialContent(c *context.Context, f *form.CreateRepo) (dir string, cleanup bool) {
+	hasArchive := f.Archive != nil && f.Archive.Filename != ""
+	localPath := strings.TrimSpace(f.LocalPath)
+	if !hasArchive && localPath == "" {
+		return "", false
+	}
+
+	if f.AutoInit || (hasArchive && localPath != "") {
+		c.RenderWithErr(c.Tr("repo.initial_content_conflict"), CREATE, f)
+		return "", false
+	}
+
+	if localPath != "" {
+		c.Data["Err_LocalPath"] = true
+		if !c.User.CanImportLocal() {
+			c.RenderWithErr(c.Tr("repo.initial_content_local_path_denied"), CREATE, f)
+			return "", false
+		} else if !osutil.IsDir(localPath) {
+			c.RenderWithErr(c.Tr("repo.migrate.invalid_local_path"), CREATE, f)
+			return "", false
+		}
+
+		ok, err := hasFiles(localPath)
+		if err != nil {
+			c.Error(err, "check files of local path")
+			return "", false
+		} else if !ok {
+			c.RenderWithErr(c.Tr("repo.initial_content_empty"), CREATE, f)
+			return "", false
+		}
+		delete(c.Data, "Err_LocalPath")
+		return localPath, false
+	}
+
+	c.Data["Err_Archive"] = true
+	maxSize := conf.Repository.Upload.ArchiveMaxSize
+	if !conf.Repository.Upload.Enabled {
+		c.RenderWithErr(c.Tr("repo.initial_content_archive_disabled"), CREATE, f)
+		return "", false
+	} else if f.Archive.Size > maxSize<<20 {
+		c.RenderWithErr(c.Tr("repo.initial_content_archive_too_large", maxSize), CREATE, f)
+		return "", false
+	} else if !archiveutil.IsSupported(f.Archive.Filename) {
+		c.RenderWithErr(c.Tr("repo.initial_content_archive_unsupported"), CREATE, f)
+		return "", false
+	}
+
+	file, err := f.Archive.Open()
+	if err != nil {
+		c.Error(err, "open archive")
+		return "", false
+	}
+	defer func() { _ = file.Close() }()
+
+	if err = os.MkdirAll(conf.Repository.Upload.TempPath, os.ModePerm); err != nil {
+		c.Error(err, "create temporary directory")
+		return "", false
+	}
+	dir, err = os.MkdirTemp(conf.Repository.Upload.TempPath, "archive-")
+	if err != nil {
+		c.Error(err, "create temporary directory")
+		return "", false
+	}
+
+	err = archiveutil.Extract(f.Archive.Filename, file, f.Archive.Size, dir, maxSize<<20)
+	if err == nil {
+		var ok bool
+		ok, err = hasFiles(dir)
+		if err == nil && !ok {
+			_ = os.RemoveAll(dir)
+			c.RenderWithErr(c.Tr("repo.initial_content_empty"), CREATE, f)
+			return "", false
+		}
+	}
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		switch err {
+		case archiveutil.ErrTooLarge:
+			c.RenderWithErr(c.Tr("repo.initial_content_archive_too_large", maxSize), CREATE, f)
+		case archiveutil.ErrUnsupportedFormat:
+			c.RenderWithErr(c.Tr("repo.initial_content_archive_unsupported"), CREATE, f)
+		default:
+			c.RenderWithErr(c.Tr("repo.initial_content_archive_invalid", err), CREATE, f)
+		}
+		return "", false
+	}
+	delete(c.Data, "Err_Archive")
+	return dir, true
+}
+
 func handleCreateError(c *context.Context, owner *db.User, err error, name, tpl string, form interface{}) {
 	switch {
 	case db.IsErrReachLimitOfRepo(err):
@@ -96,6 +223,11 @@ func handleCreateError(c *context.Context, owner *db.User, err error, name, tpl
 	case db.IsErrNameNotAllowed(err):
 		c.Data["Err_RepoName"] = true
 		c.RenderWithErr(c.Tr("repo.form.name_not_allowed", err.(db.ErrNameNotAllowed).Value()), tpl, form)
+	case db.IsErrRepoTopicInvalid(err):
+		c.Data["Err_Topics"] = true
+		c.RenderWithErr(c.Tr("repo.settings.topics_invalid", db.MaxRepoTopics), tpl, form)
+	case db.IsErrRepoNamingConvention(err):
+		c.RenderWithErr(c.Tr("repo.form.naming_convention_violated", err.(db.ErrRepoNamingConvention).Reason()), tpl, form)
 	default:
 		c.Error(err, name)
 	}
@@ -104,21 +236,31 @@ func handleCreateError(c *context.Context, owner *db.User, err error, name, tpl
 func CreatePost(c *context.Context, f form.CreateRepo) {
 	c.Data["Title"] = c.Tr("new_repo")
 
-	c.Data["Gitignores"] = db.Gitignores
-	c.Data["Licenses"] = db.Licenses
-	c.Data["Readmes"] = db.Readmes
+	c.Data["Gitignores"] = db.Gitignores()
+	c.Data["Licenses"] = db.Licenses()
+	c.Data["Readmes"] = db.Readmes()
+	setInitialContentData(c)
 
 	ctxUser := checkContextUser(c, f.UserID)
 	if c.Written() {
 		return
 	}
 	c.Data["ContextUser"] = ctxUser
+	setNamingConventionData(c, ctxUser)
 
 	if c.HasError() {
 		c.Success(CREATE)
 		return
 	}
 
+	initialContentPath, cleanup := prepareInitialContent(c, &f)
+	if c.Written() {
+		return
+	}
+	if cleanup {
+		defer func() { _ = os.RemoveAll(initialContentPath) }()
+	}
+
 	if f.SaveDefaultRepo {
 		db.UpdateRepositoryDefault(&db.CreateRepoOptionsLegacy{Name: "default",
 			Description: f.Description,
@@ -139,6 +281,10 @@ func CreatePost(c *context.Context, f form.CreateRepo) {
 		IsPrivate:   f.Private || conf.Repository.ForcePrivate,
 		IsUnlisted:  f.Unlisted,
 		AutoInit:    f.AutoInit,
+
+		IsTrackerOnly:      f.TrackerOnly,
+		InitialContentPath: initialContentPath,
+		Topics:             strings.Split(f.Topics, ","),
 	})
 	if err == nil {
 		log.Trace("Repository created [%d]: %s/%s", repo.ID, ctxUser.Name, repo.Name)
@@ -166,6 +312,7 @@ func Migrate(c *context.Context) {
 		return
 	}
 	c.Data["ContextUser"] = ctxUser
+	setNamingConventionData(c, ctxUser)
 
 	c.Success(MIGRATE)
 }
@@ -178,6 +325,7 @@ func MigratePost(c *context.Context, f form.MigrateRepo) {
 		return
 	}
 	c.Data["ContextUser"] = ctxUser
+	setNamingConventionData(c, ctxUser)
 
 	if c.HasError() {
 		c.Success(MIGRATE)
@@ -214,6 +362,7 @@ func MigratePost(c *context.Context, f form.MigrateRepo) {
 		IsUnlisted:  f.Unlisted,
 		IsMirror:    f.Mirror,
 		RemoteAddr:  remoteAddr,
+		Topics:      strings.Split(f.Topics, ","),
 	})
 	if err == nil {
 		log.Trace("Repository migrated [%d]: %s/%s", repo.ID, ctxUser.Name, f.RepoName)
@@ -245,19 +394,19 @@ func Action(c *context.Context) {
 	var err error
 	switch c.Params(":action") {
 	case "watch":
-		err = db.WatchRepo(c.User.ID, c.Repo.Repository.ID, true)
+		err = db.WatchRepository(c.User, c.Repo.Repository, true)
 	case "unwatch":
 		if userID := c.QueryInt64("user_id"); userID != 0 {
 			if c.User.IsAdmin {
 				err = db.WatchRepo(userID, c.Repo.Repository.ID, false)
 			}
 		} else {
-			err = db.WatchRepo(c.User.ID, c.Repo.Repository.ID, false)
+			err = db.WatchRepository(c.User, c.Repo.Repository, false)
 		}
 	case "star":
-		err = db.StarRepo(c.User.ID, c.Repo.Repository.ID, true)
+		err = db.StarRepository(c.User, c.Repo.Repository, true)
 	case "unstar":
-		err = db.StarRepo(c.User.ID, c.Repo.Repository.ID, false)
+		err = db.StarRepository(c.User, c.Repo.Repository, false)
 	case "desc": // FIXME: this is not used
 		if !c.Repo.IsOwner() {
 			c.NotFound()
@@ -344,7 +493,13 @@ func Download(c *context.Context) {
 
 	archivePath = path.Join(archivePath, tool.ShortSHA1(commit.ID.String())+ext)
 	if !com.IsFile(archivePath) {
-		if err := commit.CreateArchive(archiveFormat, archivePath); err != nil {
+		release, ok := downloadlimit.AcquireArchive(c)
+		if !ok {
+			return
+		}
+		err = commit.CreateArchive(archiveFormat, archivePath)
+		release()
+		if err != nil {
 			c.Error(err, "creates archive")
 			return
 		}
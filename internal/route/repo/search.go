@@ -0,0 +1,229 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"html/template"
+	"net/url"
+	"time"
+
+	"github.com/gogs/git-module"
+	"github.com/unknwon/paginater"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/gitutil"
+	"gogs.io/gogs/internal/tool"
+)
+
+const (
+	SEARCH         = "repo/search"
+	SEARCH_COMMITS = "repo/search_commits"
+
+	// searchResultLimit is the maximum number of results in each group.
+	searchResultLimit = 20
+	// searchContentMaxLength is the maximum number of characters of a matched
+	// line of file content to return.
+	searchContentMaxLength = 200
+)
+
+// SearchResults is the results of searching in a repository, grouped by their
+// kinds. Groups that are not available to the repository are nil.
+type SearchResults struct {
+	Keyword string
+	// Branch is the branch that files and commits are searched in.
+	Branch       string
+	FileNames    []string
+	FileContents []*gitutil.GrepResult
+	Issues       []*db.Issue
+	Pulls        []*db.Issue
+	Commits      []*git.Commit
+}
+
+// SearchRepository searches file names, file contents (when enabled), issues,
+// pull requests and commit messages of the current repository with the
+// keyword.
+func SearchRepository(c *context.Context, keyword string) (*SearchResults, error) {
+	repo := c.Repo.Repository
+	results := &SearchResults{
+		Keyword: keyword,
+	}
+
+	var err error
+	if repo.EnableIssues && !repo.EnableExternalTracker {
+		results.Issues, err = db.SearchRepoIssues(repo.ID, keyword, false, searchResultLimit)
+		if err != nil {
+			return nil, fmt.Errorf("search issues: %v", err)
+		}
+	}
+	if repo.AllowsPulls() {
+		results.Pulls, err = db.SearchRepoIssues(repo.ID, keyword, true, searchResultLimit)
+		if err != nil {
+			return nil, fmt.Errorf("search pull requests: %v", err)
+		}
+	}
+
+	if repo.IsBare {
+		return results, nil
+	}
+
+	gitRepo, err := git.Open(repo.RepoPath())
+	if err != nil {
+		return nil, fmt.Errorf("open repository: %v", err)
+	}
+	commit, err := gitRepo.BranchCommit(repo.DefaultBranch)
+	if err != nil {
+		return nil, gitutil.NewError(err)
+	}
+	results.Branch = repo.DefaultBranch
+
+	results.FileNames, err = gitutil.SearchFileNames(gitRepo.Path(), commit.ID.String(), keyword, searchResultLimit)
+	if err != nil {
+		return nil, fmt.Errorf("search file names: %v", err)
+	}
+	if conf.Repository.EnableContentSearch {
+		results.FileContents, err = gitutil.Grep(gitRepo.Path(), commit.ID.String(), keyword, searchResultLimit, time.Minute)
+		if err != nil {
+			return nil, fmt.Errorf("search file contents: %v", err)
+		}
+		for _, result := range results.FileContents {
+			result.Content = tool.TruncateString(result.Content, searchContentMaxLength)
+		}
+	}
+
+	results.Commits, err = commit.SearchCommits(keyword, git.SearchCommitsOptions{MaxCount: searchResultLimit})
+	if err != nil {
+		return nil, fmt.Errorf("search commits: %v", err)
+	}
+	return results, nil
+}
+
+// Search renders results of searching in the current repository with the
+// keyword given by the "q" query parameter.
+func Search(c *context.Context) {
+	c.Data["Title"] = c.Tr("search") + " · " + c.Repo.Repository.FullName()
+	c.Data["PageIsRepoSearch"] = true
+
+	keyword := c.Query("q")
+	c.Data["Keyword"] = keyword
+	c.Data["IsContentSearchEnabled"] = conf.Repository.EnableContentSearch
+	if keyword == "" {
+		c.Success(SEARCH)
+		return
+	}
+
+	results, err := SearchRepository(c, keyword)
+	if err != nil {
+		c.Error(err, "search repository")
+		return
+	}
+	if len(results.Commits) > 0 {
+		mapCommitAuthors(c, results.Commits...)
+	}
+	c.Data["Results"] = results
+	c.Success(SEARCH)
+}
+
+// parseSearchDate parses the date in the format of either "2006-01-02" or RFC
+// 3339. A date without time is the end of the day when endOfDay is true.
+func parseSearchDate(s string, endOfDay bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse("2006-01-02", s)
+	if err == nil {
+		if endOfDay {
+			t = t.Add(24*time.Hour - time.Second)
+		}
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// ParseSearchCommitsOptions returns options of searching indexed commits from
+// the "q", "author", "committer", "since" and "until" query parameters.
+func ParseSearchCommitsOptions(c *context.Context) (db.SearchCommitMessagesOptions, error) {
+	opts := db.SearchCommitMessagesOptions{
+		Keyword:   c.Query("q"),
+		Author:    c.Query("author"),
+		Committer: c.Query("committer"),
+	}
+
+	var err error
+	opts.Since, err = parseSearchDate(c.Query("since"), false)
+	if err != nil {
+		return opts, fmt.Errorf("invalid since date: %v", err)
+	}
+	opts.Until, err = parseSearchDate(c.Query("until"), true)
+	if err != nil {
+		return opts, fmt.Errorf("invalid until date: %v", err)
+	}
+	return opts, nil
+}
+
+// SearchIndexedCommits searches indexed commits of the current repository.
+// Commits of all branches are indexed first if the repository has not been
+// indexed yet.
+func SearchIndexedCommits(c *context.Context, opts db.SearchCommitMessagesOptions) ([]*db.CommitMessage, int64, error) {
+	repo := c.Repo.Repository
+	if !repo.IsBare {
+		count, err := db.CommitMessages.Count(c.Req.Context(), repo.ID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("count indexed commits: %v", err)
+		} else if count == 0 {
+			err = db.IndexCommitMessages(c.Req.Context(), repo.ID, repo.RepoPath(), "", "", "")
+			if err != nil {
+				return nil, 0, fmt.Errorf("index commit messages: %v", err)
+			}
+		}
+	}
+	return db.CommitMessages.Search(c.Req.Context(), repo.ID, opts)
+}
+
+// SearchCommitsPage renders indexed commits of the current repository that match
+// the conditions given by query parameters.
+func SearchCommitsPage(c *context.Context) {
+	c.Data["Title"] = c.Tr("repo.search.commits") + " · " + c.Repo.Repository.FullName()
+	c.Data["PageIsRepoSearch"] = true
+	c.Data["Keyword"] = c.Query("q")
+	c.Data["Author"] = c.Query("author")
+	c.Data["Committer"] = c.Query("committer")
+	c.Data["Since"] = c.Query("since")
+	c.Data["Until"] = c.Query("until")
+
+	opts, err := ParseSearchCommitsOptions(c)
+	if err != nil {
+		c.Flash.Error(c.Tr("repo.search.commits.invalid_date"), true)
+		c.Success(SEARCH_COMMITS)
+		return
+	}
+	opts.Page = c.QueryInt("page")
+	if opts.Page <= 0 {
+		opts.Page = 1
+	}
+	opts.PageSize = conf.UI.User.CommitsPagingNum
+
+	commits, count, err := SearchIndexedCommits(c, opts)
+	if err != nil {
+		c.Error(err, "search indexed commits")
+		return
+	}
+	c.Data["Commits"] = commits
+	c.Data["Total"] = count
+	c.Data["Page"] = paginater.New(int(count), opts.PageSize, opts.Page, 5)
+
+	query := make(url.Values)
+	for _, key := range []string{"q", "author", "committer", "since", "until"} {
+		if v := c.Query(key); v != "" {
+			query.Set(key, v)
+		}
+	}
+	c.Data["SearchQuery"] = template.URL(query.Encode())
+
+	c.Success(SEARCH_COMMITS)
+}
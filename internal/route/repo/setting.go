@@ -33,19 +33,61 @@ const (
 	SETTINGS_GITHOOKS         = "repo/settings/githooks"
 	SETTINGS_GITHOOK_EDIT     = "repo/settings/githook_edit"
 	SETTINGS_DEPLOY_KEYS      = "repo/settings/deploy_keys"
+	SETTINGS_SECURITY         = "repo/settings/security"
+	SETTINGS_TRAFFIC          = "repo/settings/traffic"
 )
 
 func Settings(c *context.Context) {
 	c.Title("repo.settings")
 	c.PageIs("SettingsOptions")
 	c.RequireAutosize()
+	c.Data["EnableTrash"] = conf.Repository.EnableTrash
+	c.Data["MaxRepoTopics"] = db.MaxRepoTopics
+	setMergeMessageTemplateData(c)
+	if !setMirrorDriftData(c) {
+		return
+	}
 	c.Success(SETTINGS_OPTIONS)
 }
 
+func setMergeMessageTemplateData(c *context.Context) {
+	vars := make([]string, len(db.MergeMessageVars))
+	for i := range db.MergeMessageVars {
+		vars[i] = "${" + db.MergeMessageVars[i] + "}"
+	}
+	c.Data["MergeMessageVars"] = strings.Join(vars, " ")
+	c.Data["DefaultMergeMessageTemplate"] = db.DefaultMergeMessageTemplate
+	c.Data["DefaultSquashMessageTemplate"] = db.DefaultSquashMessageTemplate
+}
+
+// setMirrorDriftData sets references of the mirror that have drifted from its
+// upstream as of the last check. It returns false if a response has been
+// written.
+func setMirrorDriftData(c *context.Context) bool {
+	c.Data["EnableMirrorDriftCheck"] = conf.Mirror.EnableDriftCheck
+	if !c.Repo.Repository.IsMirror || !conf.Mirror.EnableDriftCheck {
+		return true
+	}
+
+	drifts, err := db.MirrorRefDrifts.List(c.Req.Context(), c.Repo.Repository.ID)
+	if err != nil {
+		c.Error(err, "list drifted references")
+		return false
+	}
+	c.Data["MirrorRefDrifts"] = drifts
+	return true
+}
+
 func SettingsPost(c *context.Context, f form.RepoSetting) {
 	c.Title("repo.settings")
 	c.PageIs("SettingsOptions")
 	c.RequireAutosize()
+	c.Data["EnableTrash"] = conf.Repository.EnableTrash
+	c.Data["MaxRepoTopics"] = db.MaxRepoTopics
+	setMergeMessageTemplateData(c)
+	if !setMirrorDriftData(c) {
+		return
+	}
 
 	repo := c.Repo.Repository
 
@@ -90,7 +132,15 @@ func SettingsPost(c *context.Context, f form.RepoSetting) {
 			f.Unlisted = repo.BaseRepo.IsUnlisted
 		}
 
+		topics, err := db.NormalizeRepoTopics(strings.Split(f.Topics, ","))
+		if err != nil {
+			c.FormErr("Topics")
+			c.RenderWithErr(c.Tr("repo.settings.topics_invalid", db.MaxRepoTopics), SETTINGS_OPTIONS, &f)
+			return
+		}
+
 		visibilityChanged := repo.IsPrivate != f.Private || repo.IsUnlisted != f.Unlisted
+		privateChanged := repo.IsPrivate != f.Private
 		repo.IsPrivate = f.Private
 		repo.IsUnlisted = f.Unlisted
 		if err := db.UpdateRepository(repo, visibilityChanged); err != nil {
@@ -99,6 +149,14 @@ func SettingsPost(c *context.Context, f form.RepoSetting) {
 		}
 		log.Trace("Repository basic settings updated: %s/%s", c.Repo.Owner.Name, repo.Name)
 
+		if privateChanged {
+			db.PrepareVisibilityWebhook(c.User, repo)
+		}
+		if err = db.SetRepoTopics(c.User, repo, topics); err != nil {
+			c.Error(err, "set repository topics")
+			return
+		}
+
 		if isNameChanged {
 			if err := db.Actions.RenameRepo(c.Req.Context(), c.User, repo.MustOwner(), oldRepoName, repo); err != nil {
 				log.Error("create rename repository action: %v", err)
@@ -141,7 +199,65 @@ func SettingsPost(c *context.Context, f form.RepoSetting) {
 		c.Flash.Info(c.Tr("repo.settings.mirror_sync_in_progress"))
 		c.Redirect(repo.Link() + "/settings")
 
+	case "mirror-drift-check":
+		if !repo.IsMirror || !conf.Mirror.EnableDriftCheck {
+			c.NotFound()
+			return
+		}
+
+		drifts, err := db.CheckMirrorDrift(c.Req.Context(), c.Repo.Mirror)
+		if err != nil {
+			log.Error("Failed to check drift of mirror [repo_id: %d]: %v", repo.ID, err)
+			c.Flash.Error(c.Tr("repo.settings.mirror_drift_check_failed"))
+		} else if len(drifts) > 0 {
+			c.Flash.Warning(c.Tr("repo.settings.mirror_drift_found", len(drifts)))
+		} else {
+			c.Flash.Success(c.Tr("repo.settings.mirror_drift_none"))
+		}
+		c.Redirect(repo.Link() + "/settings")
+
+	case "mirror-drift-resync":
+		if !repo.IsMirror || !conf.Mirror.EnableDriftCheck {
+			c.NotFound()
+			return
+		}
+
+		refs := c.QueryStrings("refs")
+		if len(refs) == 0 {
+			c.Flash.Error(c.Tr("repo.settings.mirror_drift_resync_empty"))
+			c.Redirect(repo.Link() + "/settings")
+			return
+		}
+
+		drifts, err := db.ResyncMirrorRefs(c.Req.Context(), c.Repo.Mirror, refs)
+		if err != nil {
+			if db.IsErrMirrorRefNotDrifted(err) {
+				c.Flash.Error(c.Tr("repo.settings.mirror_drift_resync_outdated"))
+			} else {
+				log.Error("Failed to re-sync references of mirror [repo_id: %d]: %v", repo.ID, err)
+				c.Flash.Error(c.Tr("repo.settings.mirror_drift_resync_failed"))
+			}
+			c.Redirect(repo.Link() + "/settings")
+			return
+		}
+		c.Flash.Success(c.Tr("repo.settings.mirror_drift_resync_success", len(refs), len(drifts)))
+		c.Redirect(repo.Link() + "/settings")
+
 	case "advanced":
+		defaultAssignees := db.ParseParticipants(f.DefaultAssignees)
+		defaultReviewers := db.ParseParticipants(f.DefaultReviewers)
+		for _, names := range [][]string{defaultAssignees, defaultReviewers} {
+			if err := repo.ValidateParticipants(names); err != nil {
+				if db.IsErrParticipantNotExist(err) {
+					c.Flash.Error(c.Tr("repo.settings.participant_not_exist", err.(db.ErrParticipantNotExist).Name()))
+					c.Redirect(c.Repo.RepoLink + "/settings")
+				} else {
+					c.Error(err, "validate participants")
+				}
+				return
+			}
+		}
+
 		repo.EnableWiki = f.EnableWiki
 		repo.AllowPublicWiki = f.AllowPublicWiki
 		repo.EnableExternalWiki = f.EnableExternalWiki
@@ -152,9 +268,28 @@ func SettingsPost(c *context.Context, f form.RepoSetting) {
 		repo.ExternalTrackerURL = f.ExternalTrackerURL
 		repo.ExternalTrackerFormat = f.TrackerURLFormat
 		repo.ExternalTrackerStyle = f.TrackerIssueStyle
-		repo.EnablePulls = f.EnablePulls
+		// Tracker-only repositories have no code to accept pulls or to release.
+		repo.EnablePulls = f.EnablePulls && repo.CanEnablePulls()
 		repo.PullsIgnoreWhitespace = f.PullsIgnoreWhitespace
 		repo.PullsAllowRebase = f.PullsAllowRebase
+		repo.PullsAllowSquash = f.PullsAllowSquash
+		repo.MergeMessageTemplate = strings.TrimSpace(f.MergeMessageTemplate)
+		repo.SquashMessageTemplate = strings.TrimSpace(f.SquashMessageTemplate)
+		repo.DefaultAssignees = strings.Join(defaultAssignees, ", ")
+		repo.DefaultReviewers = strings.Join(defaultReviewers, ", ")
+		repo.EnableMergeQueue = f.EnableMergeQueue
+		repo.MergeQueueRequiredChecks = strings.Join(db.ParseRequiredChecks(f.MergeQueueRequiredChecks), ", ")
+		repo.MergeChecklist = strings.TrimSpace(f.MergeChecklist)
+		repo.EnableReleases = f.EnableReleases && !repo.IsTrackerOnly
+		repo.MaxPushFileSize = f.MaxPushFileSize
+		repo.MaxPushSize = f.MaxPushSize
+		repo.EnableMaintainersFile = f.EnableMaintainersFile
+		if repo.MaxPushFileSize < 0 {
+			repo.MaxPushFileSize = 0
+		}
+		if repo.MaxPushSize < 0 {
+			repo.MaxPushSize = 0
+		}
 
 		if !repo.EnableWiki || repo.EnableExternalWiki {
 			repo.AllowPublicWiki = false
@@ -262,7 +397,7 @@ func SettingsPost(c *context.Context, f form.RepoSetting) {
 			}
 		}
 
-		if err := db.DeleteRepository(c.Repo.Owner.ID, repo.ID); err != nil {
+		if err := db.RemoveRepository(c.User, c.Repo.Owner.ID, repo.ID); err != nil {
 			c.Error(err, "delete repository")
 			return
 		}
@@ -557,6 +692,8 @@ func SettingsProtectedBranchPost(c *context.Context, f form.ProtectBranch) {
 	protectBranch.Protected = f.Protected
 	protectBranch.RequirePullRequest = f.RequirePullRequest
 	protectBranch.EnableWhitelist = f.EnableWhitelist
+	protectBranch.RerequestReviews = f.RerequestReviews
+	protectBranch.RequiredDeployments = f.RequiredDeployments
 	if c.Repo.Owner.IsOrganization() {
 		err = db.UpdateOrgProtectBranch(c.Repo.Repository, protectBranch, f.WhitelistUsers, f.WhitelistTeams)
 	} else {
@@ -689,3 +826,138 @@ func DeleteDeployKey(c *context.Context) {
 		"redirect": c.Repo.RepoLink + "/settings/keys",
 	})
 }
+
+func SettingsSecurity(c *context.Context) {
+	c.Data["Title"] = c.Tr("repo.settings.security")
+	c.Data["PageIsSettingsSecurity"] = true
+
+	alerts, err := db.SecurityAlerts.ListByRepo(c.Req.Context(), c.Repo.Repository.ID)
+	if err != nil {
+		c.Error(err, "list security alerts")
+		return
+	}
+	c.Data["SecurityAlerts"] = alerts
+
+	deps, err := db.RepoDependencies.ListByRepo(c.Req.Context(), c.Repo.Repository.ID)
+	if err != nil {
+		c.Error(err, "list dependencies")
+		return
+	}
+	c.Data["NumDependencies"] = len(deps)
+
+	if c.Repo.Owner.IsOrganization() {
+		features, err := securityFeatureStates(c, c.Repo.Repository)
+		if err != nil {
+			c.Error(err, "get security feature states")
+			return
+		}
+		c.Data["SecurityFeatures"] = features
+	}
+
+	c.Success(SETTINGS_SECURITY)
+}
+
+// SecurityFeatureState is the state of a security feature of a repository
+// owned by an organization.
+type SecurityFeatureState struct {
+	Feature db.SecurityFeature
+	// Enabled indicates the feature is enabled by the security policy of the
+	// organization.
+	Enabled   bool
+	Exception *db.SecurityPolicyException
+}
+
+func securityFeatureStates(c *context.Context, repo *db.Repository) ([]*SecurityFeatureState, error) {
+	policy, err := db.OrgSecurityPolicies.Get(c.Req.Context(), repo.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	exceptions, err := db.SecurityPolicyExceptions.ListByRepo(c.Req.Context(), repo.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]*SecurityFeatureState, 0, len(db.SecurityFeatures))
+	for _, feature := range db.SecurityFeatures {
+		state := &SecurityFeatureState{
+			Feature: feature,
+			Enabled: policy.IsEnabled(feature),
+		}
+		for _, e := range exceptions {
+			// Exceptions of organizations that previously owned the repository
+			// do not count.
+			if e.Feature == feature && e.OrgID == repo.OwnerID {
+				state.Exception = e
+			}
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+func SettingsSecurityExceptionPost(c *context.Context) {
+	repo := c.Repo.Repository
+	if !c.Repo.Owner.IsOrganization() {
+		c.NotFound()
+		return
+	}
+
+	feature := db.SecurityFeature(c.Query("feature"))
+	reason := strings.TrimSpace(c.Query("reason"))
+	if !feature.IsValid() || reason == "" {
+		c.Flash.Error(c.Tr("repo.settings.security_exception_invalid"))
+		c.Redirect(repo.Link() + "/settings/security")
+		return
+	}
+
+	_, err := db.SecurityPolicyExceptions.Create(c.Req.Context(), repo.OwnerID, repo.ID, c.User.ID, feature, reason)
+	if err != nil {
+		if db.IsErrSecurityPolicyExceptionAlreadyExist(err) {
+			c.Flash.Error(c.Tr("repo.settings.security_exception_already_exist"))
+			c.Redirect(repo.Link() + "/settings/security")
+		} else {
+			c.Error(err, "create security policy exception")
+		}
+		return
+	}
+
+	log.Trace("Security policy exception of %q requested for repository %d by %q", feature, repo.ID, c.User.Name)
+	c.Flash.Success(c.Tr("repo.settings.security_exception_requested"))
+	c.Redirect(repo.Link() + "/settings/security")
+}
+
+// trafficDays is the number of days shown on the traffic page.
+const trafficDays = 14
+
+func SettingsTraffic(c *context.Context) {
+	c.Data["Title"] = c.Tr("repo.settings.traffic")
+	c.Data["PageIsSettingsTraffic"] = true
+	c.Data["IsTrafficAnalyticsEnabled"] = conf.Repository.EnableTrafficAnalytics
+	c.Data["IsSavingGitAccessLogs"] = conf.Git.AccessLog.SaveToDatabase
+	c.Data["TrafficDays"] = trafficDays
+
+	now := time.Now()
+	traffics, err := db.RepoTraffics.ListByRepo(c.Req.Context(), c.Repo.Repository.ID,
+		now.UTC().AddDate(0, 0, 1-trafficDays).Format(db.RepoTrafficDateFormat))
+	if err != nil {
+		c.Error(err, "list repository traffic")
+		return
+	}
+	c.Data["RepoTraffic"] = db.FillRepoTraffic(traffics, now, trafficDays)
+
+	since := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1-trafficDays)
+	logs, err := db.GitAccessLogs.ListByRepo(c.Req.Context(), c.Repo.Repository.ID, since)
+	if err != nil {
+		c.Error(err, "list Git access logs")
+		return
+	}
+	c.Data["GitAccessTraffic"] = db.BuildGitAccessTraffic(logs, now, trafficDays)
+
+	const maxRecentLogs = 50
+	if len(logs) > maxRecentLogs {
+		logs = logs[:maxRecentLogs]
+	}
+	c.Data["RecentLogs"] = logs
+
+	c.Success(SETTINGS_TRAFFIC)
+}
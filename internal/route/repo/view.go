@@ -111,6 +111,7 @@ func renderDirectory(c *context.Context, treeLink string) {
 			return
 		}
 	}
+	mapCommitAuthors(c, latestCommit)
 	c.Data["LatestCommit"] = latestCommit
 	c.Data["LatestCommitUser"] = db.ValidateCommitWithEmail(latestCommit)
 
@@ -228,9 +229,34 @@ func setEditorconfigIfExists(c *context.Context) {
 	c.Data["Editorconfig"] = ec
 }
 
+// mapCommitAuthors replaces authors and committers of commits with the
+// canonical ones according to the ".mailmap" file of the repository if exists.
+func mapCommitAuthors(c *context.Context, commits ...*git.Commit) {
+	m, err := c.Repo.Mailmap()
+	if err != nil {
+		if !gitutil.IsErrRevisionNotExist(errors.Cause(err)) {
+			log.Warn("mapCommitAuthors.Mailmap [repo_id: %d]: %v", c.Repo.Repository.ID, err)
+		}
+		return
+	}
+	m.MapCommits(commits...)
+}
+
 func Home(c *context.Context) {
 	c.Data["PageIsViewFiles"] = true
 
+	if c.Repo.Repository.IsTrackerOnly {
+		switch {
+		case c.Repo.Repository.EnableIssues:
+			c.Redirect(c.Repo.RepoLink + "/issues")
+		case c.Repo.Repository.EnableWiki:
+			c.Redirect(c.Repo.RepoLink + "/wiki")
+		default:
+			c.NotFound()
+		}
+		return
+	}
+
 	if c.Repo.Repository.IsBare {
 		c.Success(BARE)
 		return
@@ -246,6 +272,8 @@ func Home(c *context.Context) {
 	}
 	c.Data["RequireHighlightJS"] = true
 
+	db.RecordRepoTraffic(c.Repo.Repository.ID, db.RepoTrafficView, c.User, c.RemoteAddr(), c.Req.UserAgent())
+
 	branchLink := c.Repo.RepoLink + "/src/" + c.Repo.BranchName
 	treeLink := branchLink
 	rawLink := c.Repo.RepoLink + "/raw/" + c.Repo.BranchName
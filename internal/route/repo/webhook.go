@@ -174,6 +174,11 @@ func toHookEvent(f form.Webhook) *db.HookEvent {
 			IssueComment: f.IssueComment,
 			PullRequest:  f.PullRequest,
 			Release:      f.Release,
+			Star:         f.Star,
+			Watch:        f.Watch,
+			Repository:   f.Repository,
+			Deployment:   f.Deployment,
+			Wiki:         f.Wiki,
 		},
 	}
 }
@@ -0,0 +1,88 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package route
+
+import (
+	"crypto/subtle"
+	"html/template"
+	"net/http"
+
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/selfcheck"
+)
+
+const safeModeCookieName = "gogs_safe_mode"
+
+// NOTE: The page is self-contained because neither the database nor the
+// renderer is guaranteed to work in safe mode.
+var safeModeTemplate = template.Must(template.New("safe_mode").Parse(`<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<meta name="robots" content="noindex, nofollow">
+	<title>{{.BrandName}} - Safe Mode</title>
+	<style>
+		body { font-family: sans-serif; max-width: 800px; margin: 40px auto; padding: 0 20px; color: #333; }
+		li { margin-bottom: 12px; }
+		code { background: #f5f5f5; padding: 2px 4px; }
+	</style>
+</head>
+<body>
+	<h1>{{.BrandName}} is in safe mode</h1>
+{{if .Authorized}}
+	<p>Following problems were found by the self-check at boot time. Please fix them and restart the server.</p>
+	<ul>
+	{{range .Problems}}
+		<li><code>{{.Name}}</code>: {{.Detail}}</li>
+	{{end}}
+	</ul>
+{{else}}
+	<p>The site is temporarily unavailable for maintenance, please try again later.</p>
+{{end}}
+</body>
+</html>
+`))
+
+// SafeMode returns a handler that serves the page describing problems found by
+// the self-check while the server is booted in safe mode. Because users can't
+// be authenticated without the database, problems are only shown to visitors
+// who present the given token, which is printed to the log for site admins.
+func SafeMode(problems selfcheck.Problems, token string) http.HandlerFunc {
+	validToken := func(t string) bool {
+		return subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if t := r.URL.Query().Get("token"); t != "" && validToken(t) {
+			http.SetCookie(w, &http.Cookie{
+				Name:     safeModeCookieName,
+				Value:    token,
+				Path:     conf.Server.Subpath + "/",
+				Secure:   conf.Server.URL.Scheme == "https",
+				HttpOnly: true,
+				SameSite: http.SameSiteStrictMode,
+			})
+			http.Redirect(w, r, conf.Server.Subpath+"/", http.StatusFound)
+			return
+		}
+
+		cookie, err := r.Cookie(safeModeCookieName)
+		authorized := err == nil && validToken(cookie.Value)
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		err = safeModeTemplate.Execute(w, map[string]interface{}{
+			"BrandName":  conf.App.BrandName,
+			"Authorized": authorized,
+			"Problems":   problems,
+		})
+		if err != nil {
+			log.Error("Failed to render safe mode page: %v", err)
+		}
+	}
+}
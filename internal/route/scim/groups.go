@@ -0,0 +1,534 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package scim
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"gopkg.in/macaron.v1"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/db"
+)
+
+// groupResource is the SCIM representation of a team, whose display name is of
+// form "<organization>/<team>".
+type groupResource struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	DisplayName string       `json:"displayName"`
+	Members     []multiValue `json:"members,omitempty"`
+	Meta        *meta        `json:"meta,omitempty"`
+}
+
+// splitGroupName splits the display name of a group into names of the
+// organization and the team.
+func splitGroupName(displayName string) (orgName, teamName string, err error) {
+	i := strings.Index(displayName, "/")
+	if i <= 0 || i == len(displayName)-1 {
+		return "", "", invalidError{scimType: "invalidValue", detail: `The displayName must be of form "<organization>/<team>"`}
+	}
+	return displayName[:i], displayName[i+1:], nil
+}
+
+func toGroupResource(org *db.User, t *db.Team, members []*db.User) *groupResource {
+	r := &groupResource{
+		Schemas:     []string{schemaGroup},
+		ID:          strconv.FormatInt(t.ID, 10),
+		DisplayName: org.Name + "/" + t.Name,
+		Meta: &meta{
+			ResourceType: "Group",
+			Location:     location("Groups", t.ID),
+		},
+	}
+	for _, u := range members {
+		r.Members = append(r.Members, multiValue{
+			Value:   strconv.FormatInt(u.ID, 10),
+			Display: u.Name,
+			Ref:     location("Users", u.ID),
+		})
+	}
+	return r
+}
+
+// parseMemberIDs returns user IDs of given members.
+func parseMemberIDs(path string, members []multiValue) ([]int64, error) {
+	ids := make([]int64, len(members))
+	for i := range members {
+		id, err := strconv.ParseInt(members[i].Value, 10, 64)
+		if err != nil {
+			return nil, errInvalidValue(path)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+var memberFilterPattern = regexp.MustCompile(`^members\[value eq "(\d+)"\]$`)
+
+// applyGroupPatch applies PATCH operations to the display name and the set of
+// member IDs of the group, and returns the new display name.
+func applyGroupPatch(displayName string, members map[int64]bool, ops []patchOperation) (string, error) {
+	setAttribute := func(op, path string, value jsoniter.RawMessage) error {
+		switch strings.ToLower(path) {
+		case "displayname":
+			return unmarshalValue(path, value, &displayName)
+
+		case "members":
+			var values []multiValue
+			if op != "remove" || len(value) > 0 {
+				if err := unmarshalValue(path, value, &values); err != nil {
+					return err
+				}
+			}
+			ids, err := parseMemberIDs(path, values)
+			if err != nil {
+				return err
+			}
+
+			switch op {
+			case "replace":
+				for id := range members {
+					delete(members, id)
+				}
+				fallthrough
+			case "add":
+				for _, id := range ids {
+					members[id] = true
+				}
+			case "remove":
+				if len(value) == 0 {
+					// Removes all members when no value is given.
+					for id := range members {
+						delete(members, id)
+					}
+				}
+				for _, id := range ids {
+					delete(members, id)
+				}
+			}
+			return nil
+		}
+
+		m := memberFilterPattern.FindStringSubmatch(path)
+		if m == nil || op != "remove" {
+			return errInvalidPath(path)
+		}
+		id, _ := strconv.ParseInt(m[1], 10, 64)
+		delete(members, id)
+		return nil
+	}
+
+	for _, op := range ops {
+		name := strings.ToLower(op.Op)
+		switch name {
+		case "add", "remove", "replace":
+		default:
+			return "", invalidError{scimType: "invalidValue", detail: "Unsupported operation: " + op.Op}
+		}
+
+		if op.Path != "" {
+			if err := setAttribute(name, op.Path, op.Value); err != nil {
+				return "", err
+			}
+			continue
+		} else if name == "remove" {
+			return "", invalidError{scimType: "noTarget", detail: "The path is required for remove operations"}
+		}
+
+		var attrs map[string]jsoniter.RawMessage
+		if err := unmarshalValue("value", op.Value, &attrs); err != nil {
+			return "", err
+		}
+		for path, value := range attrs {
+			if strings.EqualFold(path, "id") || strings.EqualFold(path, "externalId") {
+				continue
+			}
+			if err := setAttribute(name, path, value); err != nil {
+				return "", err
+			}
+		}
+	}
+	return displayName, nil
+}
+
+// getTeamByParams returns the team with the ID in the URL and its
+// organization, and responds 404 when not found.
+func getTeamByParams(c *macaron.Context) (*db.User, *db.Team) {
+	id, ok := parseID(c)
+	if !ok {
+		notFound(c.Resp)
+		return nil, nil
+	}
+
+	t, err := db.GetTeamByID(id)
+	if err != nil {
+		if db.IsErrTeamNotExist(err) {
+			notFound(c.Resp)
+		} else {
+			internalServerError(c.Resp)
+			log.Error("Failed to get team [id: %d]: %v", id, err)
+		}
+		return nil, nil
+	}
+
+	org, err := db.GetUserByID(t.OrgID)
+	if err != nil {
+		internalServerError(c.Resp)
+		log.Error("Failed to get organization [id: %d]: %v", t.OrgID, err)
+		return nil, nil
+	}
+	return org, t
+}
+
+// respondGroup responds the team with its members.
+func respondGroup(c *macaron.Context, status int, org *db.User, t *db.Team) {
+	members, err := db.GetTeamMembers(t.ID)
+	if err != nil {
+		internalServerError(c.Resp)
+		log.Error("Failed to get members of team [id: %d]: %v", t.ID, err)
+		return
+	}
+
+	r := toGroupResource(org, t, members)
+	if status == http.StatusCreated {
+		c.Header().Set("Location", r.Meta.Location)
+	}
+	responseJSON(c.Resp, status, r)
+}
+
+func listGroups(c *macaron.Context) {
+	var teams []*db.Team
+	var total int64
+	startIndex, count := pagination(c)
+
+	if filter := c.Query("filter"); filter != "" {
+		attr, value, err := parseFilter(filter)
+		if err != nil {
+			responseInvalidOrError(c, err, "parse filter")
+			return
+		} else if attr != "displayname" {
+			responseError(c.Resp, http.StatusBadRequest, "invalidFilter", "Unsupported filter attribute: "+attr)
+			return
+		}
+
+		// A display name which is not of the form can't match any group.
+		orgName, teamName, err := splitGroupName(value)
+		if err == nil {
+			org, err := db.GetOrgByName(orgName)
+			if err == nil {
+				t, err := db.GetTeamOfOrgByName(org.ID, teamName)
+				if err == nil {
+					total = 1
+					if startIndex == 1 && count > 0 {
+						teams = []*db.Team{t}
+					}
+				} else if !db.IsErrTeamNotExist(err) {
+					internalServerError(c.Resp)
+					log.Error("Failed to get team by filter %q: %v", filter, err)
+					return
+				}
+			} else if err != db.ErrOrgNotExist {
+				internalServerError(c.Resp)
+				log.Error("Failed to get organization by filter %q: %v", filter, err)
+				return
+			}
+		}
+	} else {
+		var err error
+		teams, err = db.ListTeamsWithOffset(startIndex-1, count)
+		if err != nil {
+			internalServerError(c.Resp)
+			log.Error("Failed to list teams: %v", err)
+			return
+		}
+		total = db.CountTeams()
+	}
+
+	withMembers := !excludesMembers(c)
+	orgs := make(map[int64]*db.User)
+	resources := make([]*groupResource, len(teams))
+	for i, t := range teams {
+		org, ok := orgs[t.OrgID]
+		if !ok {
+			var err error
+			org, err = db.GetUserByID(t.OrgID)
+			if err != nil {
+				internalServerError(c.Resp)
+				log.Error("Failed to get organization [id: %d]: %v", t.OrgID, err)
+				return
+			}
+			orgs[t.OrgID] = org
+		}
+
+		var members []*db.User
+		if withMembers {
+			var err error
+			members, err = db.GetTeamMembers(t.ID)
+			if err != nil {
+				internalServerError(c.Resp)
+				log.Error("Failed to get members of team [id: %d]: %v", t.ID, err)
+				return
+			}
+		}
+		resources[i] = toGroupResource(org, t, members)
+	}
+	responseJSON(c.Resp, http.StatusOK, listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// checkMembers responds 400 when any of given user IDs does not belong to an
+// individual user.
+func checkMembers(c *macaron.Context, ids []int64) bool {
+	for _, id := range ids {
+		u, err := db.Users.GetByID(c.Req.Context(), id)
+		if err != nil {
+			if db.IsErrUserNotExist(err) {
+				responseError(c.Resp, http.StatusBadRequest, "invalidValue", "User does not exist: "+strconv.FormatInt(id, 10))
+			} else {
+				internalServerError(c.Resp)
+				log.Error("Failed to get user [id: %d]: %v", id, err)
+			}
+			return false
+		} else if u.IsOrganization() {
+			responseError(c.Resp, http.StatusBadRequest, "invalidValue", "Organizations can't be members: "+strconv.FormatInt(id, 10))
+			return false
+		}
+	}
+	return true
+}
+
+func createGroup(c *macaron.Context, doer *db.User) {
+	var r groupResource
+	if !decodeJSON(c, &r) {
+		return
+	}
+
+	orgName, teamName, err := splitGroupName(r.DisplayName)
+	if err != nil {
+		responseInvalidOrError(c, err, "split group name")
+		return
+	}
+	memberIDs, err := parseMemberIDs("members", r.Members)
+	if err != nil {
+		responseInvalidOrError(c, err, "parse member IDs")
+		return
+	} else if !checkMembers(c, memberIDs) {
+		return
+	}
+
+	org, err := db.GetOrgByName(orgName)
+	if err != nil {
+		if err == db.ErrOrgNotExist {
+			responseError(c.Resp, http.StatusBadRequest, "invalidValue", "Organization does not exist: "+orgName)
+		} else {
+			internalServerError(c.Resp)
+			log.Error("Failed to get organization %q: %v", orgName, err)
+		}
+		return
+	}
+
+	t := &db.Team{
+		OrgID:     org.ID,
+		Name:      teamName,
+		Authorize: db.AccessModeRead,
+	}
+	if err = db.NewTeam(t); err != nil {
+		switch {
+		case db.IsErrTeamAlreadyExist(err):
+			responseError(c.Resp, http.StatusConflict, "uniqueness", err.Error())
+		case db.IsErrNameNotAllowed(err):
+			responseError(c.Resp, http.StatusBadRequest, "invalidValue", err.Error())
+		default:
+			internalServerError(c.Resp)
+			log.Error("Failed to create team %q: %v", r.DisplayName, err)
+		}
+		return
+	}
+	log.Trace("[SCIM] Team created by %s: %s", doer.Name, r.DisplayName)
+
+	for _, id := range memberIDs {
+		if err = db.AddTeamMember(org.ID, t.ID, id); err != nil {
+			internalServerError(c.Resp)
+			log.Error("Failed to add member [id: %d] to team %q: %v", id, r.DisplayName, err)
+			return
+		}
+	}
+
+	respondGroup(c, http.StatusCreated, org, t)
+}
+
+func getGroup(c *macaron.Context) {
+	org, t := getTeamByParams(c)
+	if t == nil {
+		return
+	}
+	respondGroup(c, http.StatusOK, org, t)
+}
+
+// updateGroup renames the team and synchronizes its members with given user
+// IDs, and responds the updated team.
+func updateGroup(c *macaron.Context, doer, org *db.User, t *db.Team, displayName string, memberIDs []int64) {
+	if !checkMembers(c, memberIDs) {
+		return
+	}
+
+	if displayName != org.Name+"/"+t.Name {
+		orgName, teamName, err := splitGroupName(displayName)
+		if err != nil {
+			responseInvalidOrError(c, err, "split group name")
+			return
+		} else if !strings.EqualFold(orgName, org.Name) {
+			responseError(c.Resp, http.StatusBadRequest, "mutability", "Teams can't be moved to other organizations")
+			return
+		} else if t.IsOwnerTeam() && teamName != t.Name {
+			responseError(c.Resp, http.StatusBadRequest, "mutability", "The owner team can't be renamed")
+			return
+		}
+
+		if teamName != t.Name {
+			if err = db.IsUsableTeamName(teamName); err != nil {
+				responseError(c.Resp, http.StatusBadRequest, "invalidValue", err.Error())
+				return
+			}
+			t.Name = teamName
+			if err = db.UpdateTeam(t, false); err != nil {
+				if db.IsErrTeamAlreadyExist(err) {
+					responseError(c.Resp, http.StatusConflict, "uniqueness", err.Error())
+				} else {
+					internalServerError(c.Resp)
+					log.Error("Failed to update team [id: %d]: %v", t.ID, err)
+				}
+				return
+			}
+			log.Trace("[SCIM] Team renamed by %s: %s", doer.Name, displayName)
+		}
+	}
+
+	members, err := db.GetTeamMembers(t.ID)
+	if err != nil {
+		internalServerError(c.Resp)
+		log.Error("Failed to get members of team [id: %d]: %v", t.ID, err)
+		return
+	}
+	current := make(map[int64]bool, len(members))
+	for _, u := range members {
+		current[u.ID] = true
+	}
+	wanted := make(map[int64]bool, len(memberIDs))
+	for _, id := range memberIDs {
+		wanted[id] = true
+	}
+
+	for id := range wanted {
+		if current[id] {
+			continue
+		}
+		if err = db.AddTeamMember(org.ID, t.ID, id); err != nil {
+			internalServerError(c.Resp)
+			log.Error("Failed to add member [id: %d] to team [id: %d]: %v", id, t.ID, err)
+			return
+		}
+	}
+	for id := range current {
+		if wanted[id] {
+			continue
+		}
+		if err = db.RemoveTeamMember(org.ID, t.ID, id); err != nil {
+			if db.IsErrLastOrgOwner(err) {
+				responseError(c.Resp, http.StatusConflict, "mutability", "The last member of the owner team can't be removed")
+			} else {
+				internalServerError(c.Resp)
+				log.Error("Failed to remove member [id: %d] from team [id: %d]: %v", id, t.ID, err)
+			}
+			return
+		}
+	}
+
+	respondGroup(c, http.StatusOK, org, t)
+}
+
+func replaceGroup(c *macaron.Context, doer *db.User) {
+	org, t := getTeamByParams(c)
+	if t == nil {
+		return
+	}
+
+	var r groupResource
+	if !decodeJSON(c, &r) {
+		return
+	}
+	memberIDs, err := parseMemberIDs("members", r.Members)
+	if err != nil {
+		responseInvalidOrError(c, err, "parse member IDs")
+		return
+	}
+	updateGroup(c, doer, org, t, r.DisplayName, memberIDs)
+}
+
+func patchGroup(c *macaron.Context, doer *db.User) {
+	org, t := getTeamByParams(c)
+	if t == nil {
+		return
+	}
+
+	ops, ok := decodePatchOperations(c)
+	if !ok {
+		return
+	}
+
+	members, err := db.GetTeamMembers(t.ID)
+	if err != nil {
+		internalServerError(c.Resp)
+		log.Error("Failed to get members of team [id: %d]: %v", t.ID, err)
+		return
+	}
+	memberSet := make(map[int64]bool, len(members))
+	for _, u := range members {
+		memberSet[u.ID] = true
+	}
+
+	displayName, err := applyGroupPatch(org.Name+"/"+t.Name, memberSet, ops)
+	if err != nil {
+		responseInvalidOrError(c, err, "apply patch")
+		return
+	}
+
+	memberIDs := make([]int64, 0, len(memberSet))
+	for id := range memberSet {
+		memberIDs = append(memberIDs, id)
+	}
+	sort.Slice(memberIDs, func(i, j int) bool { return memberIDs[i] < memberIDs[j] })
+	updateGroup(c, doer, org, t, displayName, memberIDs)
+}
+
+func deleteGroup(c *macaron.Context, doer *db.User) {
+	org, t := getTeamByParams(c)
+	if t == nil {
+		return
+	} else if t.IsOwnerTeam() {
+		responseError(c.Resp, http.StatusBadRequest, "mutability", "The owner team can't be deleted")
+		return
+	}
+
+	if err := db.DeleteTeam(t); err != nil {
+		internalServerError(c.Resp)
+		log.Error("Failed to delete team [id: %d]: %v", t.ID, err)
+		return
+	}
+
+	log.Trace("[SCIM] Team deleted by %s: %s/%s", doer.Name, org.Name, t.Name)
+	c.Resp.WriteHeader(http.StatusNoContent)
+}
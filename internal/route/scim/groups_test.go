@@ -0,0 +1,97 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package scim
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_splitGroupName(t *testing.T) {
+	orgName, teamName, err := splitGroupName("acme/dev-ops")
+	require.NoError(t, err)
+	assert.Equal(t, "acme", orgName)
+	assert.Equal(t, "dev-ops", teamName)
+
+	for _, name := range []string{"acme", "/dev-ops", "acme/"} {
+		_, _, err = splitGroupName(name)
+		assert.Error(t, err, name)
+	}
+}
+
+func Test_applyGroupPatch(t *testing.T) {
+	tests := []struct {
+		name            string
+		ops             string
+		wantDisplayName string
+		wantMembers     map[int64]bool
+		wantErr         error
+	}{
+		{
+			name:            "add members",
+			ops:             `[{"op": "add", "path": "members", "value": [{"value": "3"}, {"value": "4"}]}]`,
+			wantDisplayName: "acme/dev",
+			wantMembers:     map[int64]bool{1: true, 2: true, 3: true, 4: true},
+		},
+		{
+			name:            "remove member by filter",
+			ops:             `[{"op": "remove", "path": "members[value eq \"2\"]"}]`,
+			wantDisplayName: "acme/dev",
+			wantMembers:     map[int64]bool{1: true},
+		},
+		{
+			name:            "remove members by value",
+			ops:             `[{"op": "Remove", "path": "members", "value": [{"value": "1"}]}]`,
+			wantDisplayName: "acme/dev",
+			wantMembers:     map[int64]bool{2: true},
+		},
+		{
+			name:            "remove all members",
+			ops:             `[{"op": "remove", "path": "members"}]`,
+			wantDisplayName: "acme/dev",
+			wantMembers:     map[int64]bool{},
+		},
+		{
+			name:            "replace without path",
+			ops:             `[{"op": "replace", "value": {"id": "1", "displayName": "acme/devops", "members": [{"value": "5"}]}}]`,
+			wantDisplayName: "acme/devops",
+			wantMembers:     map[int64]bool{5: true},
+		},
+		{
+			name:    "invalid member ID",
+			ops:     `[{"op": "add", "path": "members", "value": [{"value": "alice"}]}]`,
+			wantErr: invalidError{scimType: "invalidValue", detail: "Invalid value of members"},
+		},
+		{
+			name:    "unsupported path",
+			ops:     `[{"op": "replace", "path": "members[value eq \"1\"]", "value": []}]`,
+			wantErr: invalidError{scimType: "invalidPath", detail: `Unsupported path: members[value eq "1"]`},
+		},
+		{
+			name:    "remove without path",
+			ops:     `[{"op": "remove"}]`,
+			wantErr: invalidError{scimType: "noTarget", detail: "The path is required for remove operations"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var ops []patchOperation
+			err := jsoniter.UnmarshalFromString(test.ops, &ops)
+			require.NoError(t, err)
+
+			members := map[int64]bool{1: true, 2: true}
+			displayName, err := applyGroupPatch("acme/dev", members, ops)
+			assert.Equal(t, test.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, test.wantDisplayName, displayName)
+			assert.Equal(t, test.wantMembers, members)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package scim
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"gopkg.in/macaron.v1"
+	log "unknwon.dev/clog/v2"
+)
+
+// invalidError is an error of the request that should be responded with 400
+// and the SCIM error type.
+type invalidError struct {
+	scimType string
+	detail   string
+}
+
+func (err invalidError) Error() string {
+	return err.detail
+}
+
+// responseInvalidOrError responds 400 when the error is an invalidError, and
+// 500 otherwise.
+func responseInvalidOrError(c *macaron.Context, err error, action string) {
+	if e, ok := err.(invalidError); ok {
+		responseError(c.Resp, http.StatusBadRequest, e.scimType, e.detail)
+		return
+	}
+	internalServerError(c.Resp)
+	log.Error("Failed to %s: %v", action, err)
+}
+
+var filterPattern = regexp.MustCompile(`^\s*([A-Za-z][A-Za-z.]*)\s+(?i:eq)\s+("(?:[^"\\]|\\.)*")\s*$`)
+
+// parseFilter parses the filter of form `<attribute> eq "<value>"`, which is
+// the only form supported, and returns the lower-cased attribute and the
+// value.
+func parseFilter(filter string) (attr, value string, err error) {
+	m := filterPattern.FindStringSubmatch(filter)
+	if m == nil {
+		return "", "", invalidError{scimType: "invalidFilter", detail: "Only filters of form `<attribute> eq \"<value>\"` are supported"}
+	}
+
+	value, err = strconv.Unquote(m[2])
+	if err != nil {
+		return "", "", invalidError{scimType: "invalidFilter", detail: "Invalid filter value: " + m[2]}
+	}
+	return strings.ToLower(m[1]), value, nil
+}
+
+type patchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []patchOperation `json:"Operations"`
+}
+
+type patchOperation struct {
+	Op    string              `json:"op"`
+	Path  string              `json:"path"`
+	Value jsoniter.RawMessage `json:"value"`
+}
+
+// decodePatchOperations decodes operations of the PATCH request, and responds
+// 400 when failed.
+func decodePatchOperations(c *macaron.Context) ([]patchOperation, bool) {
+	var req patchRequest
+	if !decodeJSON(c, &req) {
+		return nil, false
+	}
+	return req.Operations, true
+}
+
+func errInvalidPath(path string) error {
+	return invalidError{scimType: "invalidPath", detail: "Unsupported path: " + path}
+}
+
+func errInvalidValue(path string) error {
+	return invalidError{scimType: "invalidValue", detail: "Invalid value of " + path}
+}
+
+// unmarshalValue unmarshals the value of the attribute at given path.
+func unmarshalValue(path string, data jsoniter.RawMessage, v interface{}) error {
+	if err := jsoniter.Unmarshal(data, v); err != nil {
+		return errInvalidValue(path)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package scim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    string
+		wantAttr  string
+		wantValue string
+		wantErr   error
+	}{
+		{
+			name:      "userName",
+			filter:    `userName eq "alice"`,
+			wantAttr:  "username",
+			wantValue: "alice",
+		},
+		{
+			name:      "case-insensitive operator",
+			filter:    ` displayName EQ "acme/Dev \"Ops\""`,
+			wantAttr:  "displayname",
+			wantValue: `acme/Dev "Ops"`,
+		},
+		{
+			name:      "sub-attribute",
+			filter:    `emails.value eq "alice@example.com"`,
+			wantAttr:  "emails.value",
+			wantValue: "alice@example.com",
+		},
+		{
+			name:    "unsupported operator",
+			filter:  `userName sw "al"`,
+			wantErr: invalidError{scimType: "invalidFilter", detail: "Only filters of form `<attribute> eq \"<value>\"` are supported"},
+		},
+		{
+			name:    "logical operator",
+			filter:  `userName eq "alice" and active eq true`,
+			wantErr: invalidError{scimType: "invalidFilter", detail: "Only filters of form `<attribute> eq \"<value>\"` are supported"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			attr, value, err := parseFilter(test.filter)
+			assert.Equal(t, test.wantErr, err)
+			assert.Equal(t, test.wantAttr, attr)
+			assert.Equal(t, test.wantValue, value)
+		})
+	}
+}
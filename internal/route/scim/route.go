@@ -0,0 +1,285 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package scim implements a subset of SCIM 2.0 (RFC 7643 and RFC 7644) for
+// identity providers to provision users and team memberships.
+package scim
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"gopkg.in/macaron.v1"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/db"
+)
+
+const (
+	contentType = "application/scim+json"
+
+	schemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	schemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	schemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	schemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	schemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	schemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	schemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	// maxResults is the maximum number of resources returned in a list response.
+	maxResults = 100
+)
+
+// RegisterRoutes registers SCIM routes using given router, and inherits all
+// groups and middleware.
+func RegisterRoutes(r *macaron.Router) {
+	r.Group("", func() {
+		r.Get("/ServiceProviderConfig", serviceProviderConfig)
+		r.Get("/ResourceTypes", resourceTypes)
+
+		r.Combo("/Users").
+			Get(listUsers).
+			Post(createUser)
+		r.Combo("/Users/:id").
+			Get(getUser).
+			Put(replaceUser).
+			Patch(patchUser).
+			Delete(deleteUser)
+
+		r.Combo("/Groups").
+			Get(listGroups).
+			Post(createGroup)
+		r.Combo("/Groups/:id").
+			Get(getGroup).
+			Put(replaceGroup).
+			Patch(patchGroup).
+			Delete(deleteGroup)
+	}, authenticate())
+}
+
+// authenticate authenticates identity providers via access tokens of site
+// admins or user admins as bearer tokens.
+func authenticate() macaron.Handler {
+	return func(c *macaron.Context) {
+		fields := strings.Fields(c.Req.Header.Get("Authorization"))
+		if len(fields) != 2 || !strings.EqualFold(fields[0], "bearer") {
+			c.Header().Set("WWW-Authenticate", `Bearer realm="SCIM"`)
+			responseError(c.Resp, http.StatusUnauthorized, "", "Bearer token needed")
+			return
+		}
+
+		token, err := db.AccessTokens.GetBySHA1(c.Req.Context(), fields[1])
+		if err != nil {
+			if db.IsErrAccessTokenNotExist(err) {
+				c.Header().Set("WWW-Authenticate", `Bearer realm="SCIM"`)
+				responseError(c.Resp, http.StatusUnauthorized, "", "Invalid bearer token")
+			} else {
+				internalServerError(c.Resp)
+				log.Error("Failed to get access token: %v", err)
+			}
+			return
+		}
+
+		user, err := db.Users.GetByID(c.Req.Context(), token.UserID)
+		if err != nil {
+			// Once we found the token, we're supposed to find its related user,
+			// thus any error is unexpected.
+			internalServerError(c.Resp)
+			log.Error("Failed to get user [id: %d]: %v", token.UserID, err)
+			return
+		} else if user.ProhibitLogin {
+			responseError(c.Resp, http.StatusForbidden, "", "User is prohibited from signing in")
+			return
+		}
+
+		allowed := user.IsAdmin
+		if !allowed {
+			roles, err := db.AdminRoles.ListByUserID(c.Req.Context(), user.ID)
+			if err != nil {
+				internalServerError(c.Resp)
+				log.Error("Failed to list admin roles of user [id: %d]: %v", user.ID, err)
+				return
+			}
+			for _, role := range roles {
+				allowed = allowed || role == db.AdminRoleUserAdmin
+			}
+		}
+		if !allowed {
+			responseError(c.Resp, http.StatusForbidden, "", "Only site admins and user admins are allowed to use SCIM")
+			return
+		}
+
+		if err = db.AccessTokens.Touch(c.Req.Context(), token.ID); err != nil {
+			log.Error("Failed to touch access token: %v", err)
+		}
+
+		log.Trace("[SCIM] Authenticated user: %s", user.Name)
+
+		c.Map(user)
+	}
+}
+
+type meta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Location     string `json:"location,omitempty"`
+}
+
+// location returns the URL of the resource with given endpoint and ID.
+func location(endpoint string, id int64) string {
+	return conf.Server.ExternalURL + "scim/v2/" + endpoint + "/" + strconv.FormatInt(id, 10)
+}
+
+type listResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int64       `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+// pagination returns the 1-based start index and the number of resources per
+// page requested by query parameters "startIndex" and "count".
+func pagination(c *macaron.Context) (startIndex, count int) {
+	startIndex = c.QueryInt("startIndex")
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	count = maxResults
+	if c.Query("count") != "" {
+		count = c.QueryInt("count")
+	}
+	if count < 0 {
+		count = 0
+	} else if count > maxResults {
+		count = maxResults
+	}
+	return startIndex, count
+}
+
+// excludesMembers returns true if members of groups are excluded from the
+// response by the query parameter "excludedAttributes".
+func excludesMembers(c *macaron.Context) bool {
+	for _, attr := range strings.Split(c.Query("excludedAttributes"), ",") {
+		if strings.EqualFold(strings.TrimSpace(attr), "members") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseID returns the ID of the resource in the URL, and false if the ID is
+// not valid.
+func parseID(c *macaron.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Params(":id"), 10, 64)
+	return id, err == nil && id > 0
+}
+
+func responseJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+
+	err := jsoniter.NewEncoder(w).Encode(v)
+	if err != nil {
+		log.Error("Failed to encode JSON: %v", err)
+		return
+	}
+}
+
+// decodeJSON decodes the request body into v, and responds 400 when failed.
+func decodeJSON(c *macaron.Context, v interface{}) bool {
+	err := jsoniter.NewDecoder(c.Req.Request.Body).Decode(v)
+	if err != nil {
+		responseError(c.Resp, http.StatusBadRequest, "invalidSyntax", "Invalid JSON: "+err.Error())
+		return false
+	}
+	return true
+}
+
+type errorResponse struct {
+	Schemas  []string `json:"schemas"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+	Status   string   `json:"status"`
+}
+
+func responseError(w http.ResponseWriter, status int, scimType, detail string) {
+	responseJSON(w, status, errorResponse{
+		Schemas:  []string{schemaError},
+		ScimType: scimType,
+		Detail:   detail,
+		Status:   strconv.Itoa(status),
+	})
+}
+
+func internalServerError(w http.ResponseWriter) {
+	responseError(w, http.StatusInternalServerError, "", "Internal server error")
+}
+
+func notFound(w http.ResponseWriter) {
+	responseError(w, http.StatusNotFound, "", "Resource not found")
+}
+
+func serviceProviderConfig(c *macaron.Context) {
+	supported := func(ok bool) map[string]bool {
+		return map[string]bool{"supported": ok}
+	}
+	responseJSON(c.Resp, http.StatusOK, map[string]interface{}{
+		"schemas":          []string{schemaServiceProviderConfig},
+		"documentationUri": "https://gogs.io/docs",
+		"patch":            supported(true),
+		"bulk": map[string]interface{}{
+			"supported":      false,
+			"maxOperations":  0,
+			"maxPayloadSize": 0,
+		},
+		"filter": map[string]interface{}{
+			"supported":  true,
+			"maxResults": maxResults,
+		},
+		"changePassword": supported(false),
+		"sort":           supported(false),
+		"etag":           supported(false),
+		"authenticationSchemes": []map[string]interface{}{
+			{
+				"type":        "oauthbearertoken",
+				"name":        "Bearer Token",
+				"description": "Authentication with access tokens of site admins",
+				"primary":     true,
+			},
+		},
+		"meta": meta{ResourceType: "ServiceProviderConfig"},
+	})
+}
+
+func resourceTypes(c *macaron.Context) {
+	resourceType := func(name, endpoint, schema string) map[string]interface{} {
+		return map[string]interface{}{
+			"schemas":  []string{schemaResourceType},
+			"id":       name,
+			"name":     name,
+			"endpoint": "/" + endpoint,
+			"schema":   schema,
+			"meta": meta{
+				ResourceType: "ResourceType",
+				Location:     conf.Server.ExternalURL + "scim/v2/ResourceTypes/" + name,
+			},
+		}
+	}
+	types := []map[string]interface{}{
+		resourceType("User", "Users", schemaUser),
+		resourceType("Group", "Groups", schemaGroup),
+	}
+	responseJSON(c.Resp, http.StatusOK, listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: int64(len(types)),
+		StartIndex:   1,
+		ItemsPerPage: len(types),
+		Resources:    types,
+	})
+}
@@ -0,0 +1,494 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package scim
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"gopkg.in/macaron.v1"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/strutil"
+)
+
+type userName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type multiValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// userResource is the SCIM representation of a user.
+type userResource struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	ExternalID  string       `json:"externalId,omitempty"`
+	UserName    string       `json:"userName"`
+	Name        *userName    `json:"name,omitempty"`
+	DisplayName string       `json:"displayName,omitempty"`
+	Emails      []multiValue `json:"emails,omitempty"`
+	Active      *bool        `json:"active,omitempty"`
+	Password    string       `json:"password,omitempty"`
+	Meta        *meta        `json:"meta,omitempty"`
+}
+
+// fullName returns the full name from the display name or the name of the
+// user.
+func (r *userResource) fullName() string {
+	if r.DisplayName != "" {
+		return r.DisplayName
+	} else if r.Name == nil {
+		return ""
+	} else if r.Name.Formatted != "" {
+		return r.Name.Formatted
+	}
+	return strings.TrimSpace(r.Name.GivenName + " " + r.Name.FamilyName)
+}
+
+// primaryEmail returns the primary email address of the user, or the first one
+// when none is marked as primary.
+func (r *userResource) primaryEmail() string {
+	for _, e := range r.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(r.Emails) > 0 {
+		return r.Emails[0].Value
+	}
+	return ""
+}
+
+// setPrimaryEmail replaces the primary email address of the user.
+func (r *userResource) setPrimaryEmail(email string) {
+	for i := range r.Emails {
+		if r.Emails[i].Primary {
+			r.Emails[i].Value = email
+			return
+		}
+	}
+	if len(r.Emails) > 0 {
+		r.Emails[0].Value = email
+		return
+	}
+	r.Emails = []multiValue{{Value: email, Type: "work", Primary: true}}
+}
+
+func toUserResource(u *db.User) *userResource {
+	active := !u.ProhibitLogin
+	return &userResource{
+		Schemas:     []string{schemaUser},
+		ID:          strconv.FormatInt(u.ID, 10),
+		UserName:    u.Name,
+		Name:        &userName{Formatted: u.FullName},
+		DisplayName: u.FullName,
+		Emails: []multiValue{
+			{Value: u.Email, Type: "work", Primary: true},
+		},
+		Active: &active,
+		Meta: &meta{
+			ResourceType: "User",
+			Created:      time.Unix(u.CreatedUnix, 0).UTC().Format(time.RFC3339),
+			LastModified: time.Unix(u.UpdatedUnix, 0).UTC().Format(time.RFC3339),
+			Location:     location("Users", u.ID),
+		},
+	}
+}
+
+// setUserAttribute sets the value of the attribute at given path, attributes
+// that are not supported are ignored.
+func setUserAttribute(r *userResource, path string, value jsoniter.RawMessage) error {
+	switch strings.ToLower(path) {
+	case "active":
+		// Some identity providers send booleans as strings, e.g. "False".
+		var active bool
+		if err := jsoniter.Unmarshal(value, &active); err != nil {
+			var s string
+			if err = unmarshalValue(path, value, &s); err != nil {
+				return err
+			}
+			active, err = strconv.ParseBool(strings.ToLower(s))
+			if err != nil {
+				return errInvalidValue(path)
+			}
+		}
+		r.Active = &active
+
+	case "username":
+		return unmarshalValue(path, value, &r.UserName)
+
+	case "displayname":
+		return unmarshalValue(path, value, &r.DisplayName)
+
+	case "name":
+		r.Name = new(userName)
+		return unmarshalValue(path, value, r.Name)
+
+	case "name.formatted", "name.givenname", "name.familyname":
+		var s string
+		if err := unmarshalValue(path, value, &s); err != nil {
+			return err
+		}
+		// The display name takes precedence over the name, drop it so that the
+		// new name takes effect.
+		r.DisplayName = ""
+		if r.Name == nil {
+			r.Name = new(userName)
+		}
+		switch strings.ToLower(path) {
+		case "name.formatted":
+			r.Name.Formatted = s
+		case "name.givenname":
+			r.Name.Formatted = ""
+			r.Name.GivenName = s
+		case "name.familyname":
+			r.Name.Formatted = ""
+			r.Name.FamilyName = s
+		}
+
+	case "emails":
+		return unmarshalValue(path, value, &r.Emails)
+
+	case "emails.value", `emails[type eq "work"].value`, `emails[primary eq true].value`:
+		var email string
+		if err := unmarshalValue(path, value, &email); err != nil {
+			return err
+		}
+		r.setPrimaryEmail(email)
+	}
+	return nil
+}
+
+// applyUserPatch applies PATCH operations to the user. Only "add" and "replace"
+// operations are supported since no attribute is allowed to be removed.
+func applyUserPatch(r *userResource, ops []patchOperation) error {
+	for _, op := range ops {
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+		default:
+			return invalidError{scimType: "invalidValue", detail: "Unsupported operation: " + op.Op}
+		}
+
+		if op.Path != "" {
+			if err := setUserAttribute(r, op.Path, op.Value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var attrs map[string]jsoniter.RawMessage
+		if err := unmarshalValue("value", op.Value, &attrs); err != nil {
+			return err
+		}
+		for path, value := range attrs {
+			if err := setUserAttribute(r, path, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getUserByParams returns the user with the ID in the URL, and responds 404
+// when not found.
+func getUserByParams(c *macaron.Context) *db.User {
+	id, ok := parseID(c)
+	if !ok {
+		notFound(c.Resp)
+		return nil
+	}
+
+	u, err := db.Users.GetByID(c.Req.Context(), id)
+	if err != nil {
+		if db.IsErrUserNotExist(err) {
+			notFound(c.Resp)
+		} else {
+			internalServerError(c.Resp)
+			log.Error("Failed to get user [id: %d]: %v", id, err)
+		}
+		return nil
+	} else if u.IsOrganization() {
+		notFound(c.Resp)
+		return nil
+	}
+	return u
+}
+
+// getManagedUserByParams returns the user with the ID in the URL that the doer
+// is allowed to manage. Site admins and users with admin roles can only be
+// managed by site admins.
+func getManagedUserByParams(c *macaron.Context, doer *db.User) *db.User {
+	u := getUserByParams(c)
+	if u == nil || doer.IsAdmin {
+		return u
+	}
+
+	privileged, err := db.HasAdminPrivileges(c.Req.Context(), u)
+	if err != nil {
+		internalServerError(c.Resp)
+		log.Error("Failed to check admin privileges of user %q: %v", u.Name, err)
+		return nil
+	} else if privileged {
+		responseError(c.Resp, http.StatusForbidden, "", "Only site admins are allowed to manage users with admin privileges")
+		return nil
+	}
+	return u
+}
+
+func listUsers(c *macaron.Context) {
+	var users []*db.User
+	var total int64
+	startIndex, count := pagination(c)
+
+	if filter := c.Query("filter"); filter != "" {
+		attr, value, err := parseFilter(filter)
+		if err != nil {
+			responseInvalidOrError(c, err, "parse filter")
+			return
+		}
+
+		var u *db.User
+		switch attr {
+		case "username":
+			u, err = db.Users.GetByUsername(c.Req.Context(), value)
+		case "emails", "emails.value":
+			u, err = db.Users.GetByEmail(c.Req.Context(), value)
+		default:
+			responseError(c.Resp, http.StatusBadRequest, "invalidFilter", "Unsupported filter attribute: "+attr)
+			return
+		}
+		if err != nil && !db.IsErrUserNotExist(err) {
+			internalServerError(c.Resp)
+			log.Error("Failed to get user by filter %q: %v", filter, err)
+			return
+		}
+
+		if u != nil && !u.IsOrganization() {
+			total = 1
+			if startIndex == 1 && count > 0 {
+				users = []*db.User{u}
+			}
+		}
+	} else {
+		var err error
+		users, err = db.ListUsersWithOffset(startIndex-1, count)
+		if err != nil {
+			internalServerError(c.Resp)
+			log.Error("Failed to list users: %v", err)
+			return
+		}
+		total = db.CountUsers()
+	}
+
+	resources := make([]*userResource, len(users))
+	for i := range users {
+		resources[i] = toUserResource(users[i])
+	}
+	responseJSON(c.Resp, http.StatusOK, listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+func createUser(c *macaron.Context, doer *db.User) {
+	var r userResource
+	if !decodeJSON(c, &r) {
+		return
+	}
+
+	email := r.primaryEmail()
+	if r.UserName == "" {
+		responseError(c.Resp, http.StatusBadRequest, "invalidValue", "The userName is required")
+		return
+	} else if email == "" {
+		responseError(c.Resp, http.StatusBadRequest, "invalidValue", "An email address is required")
+		return
+	}
+
+	password := r.Password
+	if password == "" {
+		// Users provisioned without passwords are expected to sign in via the
+		// identity provider, a random password prevents signing in locally.
+		var err error
+		password, err = strutil.RandomChars(32)
+		if err != nil {
+			internalServerError(c.Resp)
+			log.Error("Failed to generate random password: %v", err)
+			return
+		}
+	}
+
+	u, err := db.Users.Create(c.Req.Context(), r.UserName, email, db.CreateUserOptions{
+		FullName:  r.fullName(),
+		Password:  password,
+		Activated: true,
+	})
+	if err != nil {
+		switch {
+		case db.IsErrUserAlreadyExist(err), db.IsErrEmailAlreadyUsed(err):
+			responseError(c.Resp, http.StatusConflict, "uniqueness", err.Error())
+		case db.IsErrNameNotAllowed(err):
+			responseError(c.Resp, http.StatusBadRequest, "invalidValue", err.Error())
+		default:
+			internalServerError(c.Resp)
+			log.Error("Failed to create user %q: %v", r.UserName, err)
+		}
+		return
+	}
+	log.Trace("[SCIM] User created by %s: %s", doer.Name, u.Name)
+
+	if r.Active != nil && !*r.Active {
+		if err = db.DeactivateUser(u); err != nil {
+			internalServerError(c.Resp)
+			log.Error("Failed to deactivate user %q: %v", u.Name, err)
+			return
+		}
+	}
+
+	resource := toUserResource(u)
+	resource.ExternalID = r.ExternalID
+	c.Header().Set("Location", resource.Meta.Location)
+	responseJSON(c.Resp, http.StatusCreated, resource)
+}
+
+func getUser(c *macaron.Context) {
+	u := getUserByParams(c)
+	if u == nil {
+		return
+	}
+	responseJSON(c.Resp, http.StatusOK, toUserResource(u))
+}
+
+// updateUser updates the user with given SCIM representation, and responds
+// the updated user.
+func updateUser(c *macaron.Context, doer, u *db.User, r *userResource) {
+	if r.UserName != "" && r.UserName != u.Name {
+		if !strings.EqualFold(r.UserName, u.Name) {
+			err := db.ChangeUserName(u, r.UserName)
+			if err != nil {
+				switch {
+				case db.IsErrUserAlreadyExist(err):
+					responseError(c.Resp, http.StatusConflict, "uniqueness", err.Error())
+				case db.IsErrNameNotAllowed(err):
+					responseError(c.Resp, http.StatusBadRequest, "invalidValue", err.Error())
+				default:
+					internalServerError(c.Resp)
+					log.Error("Failed to change name of user %q: %v", u.Name, err)
+				}
+				return
+			}
+		}
+		log.Trace("[SCIM] User renamed by %s: %s -> %s", doer.Name, u.Name, r.UserName)
+		u.Name = r.UserName
+	}
+
+	u.FullName = r.fullName()
+	if email := r.primaryEmail(); email != "" {
+		u.Email = email
+	}
+	deactivated := r.Active != nil && !*r.Active && !u.ProhibitLogin
+	if r.Active != nil && *r.Active {
+		u.ProhibitLogin = false
+	}
+
+	err := db.UpdateUser(u)
+	if err != nil {
+		if db.IsErrEmailAlreadyUsed(err) {
+			responseError(c.Resp, http.StatusConflict, "uniqueness", err.Error())
+		} else {
+			internalServerError(c.Resp)
+			log.Error("Failed to update user %q: %v", u.Name, err)
+		}
+		return
+	}
+
+	if deactivated {
+		if err = db.DeactivateUser(u); err != nil {
+			internalServerError(c.Resp)
+			log.Error("Failed to deactivate user %q: %v", u.Name, err)
+			return
+		}
+		log.Trace("[SCIM] User deactivated by %s: %s", doer.Name, u.Name)
+	}
+
+	responseJSON(c.Resp, http.StatusOK, toUserResource(u))
+}
+
+func replaceUser(c *macaron.Context, doer *db.User) {
+	u := getManagedUserByParams(c, doer)
+	if u == nil {
+		return
+	}
+
+	var r userResource
+	if !decodeJSON(c, &r) {
+		return
+	}
+	updateUser(c, doer, u, &r)
+}
+
+func patchUser(c *macaron.Context, doer *db.User) {
+	u := getManagedUserByParams(c, doer)
+	if u == nil {
+		return
+	}
+
+	ops, ok := decodePatchOperations(c)
+	if !ok {
+		return
+	}
+
+	r := toUserResource(u)
+	if err := applyUserPatch(r, ops); err != nil {
+		responseInvalidOrError(c, err, "apply patch")
+		return
+	}
+	updateUser(c, doer, u, r)
+}
+
+func deleteUser(c *macaron.Context, doer *db.User) {
+	u := getManagedUserByParams(c, doer)
+	if u == nil {
+		return
+	}
+
+	err := db.DeleteUser(u)
+	if err != nil {
+		if !db.IsErrUserOwnRepos(err) && !db.IsErrUserHasOrgs(err) {
+			internalServerError(c.Resp)
+			log.Error("Failed to delete user %q: %v", u.Name, err)
+			return
+		}
+
+		// Users who still own repositories or organizations can't be deleted,
+		// but must not be able to access anything anymore.
+		if err = db.DeactivateUser(u); err != nil {
+			internalServerError(c.Resp)
+			log.Error("Failed to deactivate user %q: %v", u.Name, err)
+			return
+		}
+		log.Trace("[SCIM] User deactivated instead of deleted by %s: %s", doer.Name, u.Name)
+		responseError(c.Resp, http.StatusConflict, "mutability", "The user still owns repositories or organizations, thus has been deactivated instead")
+		return
+	}
+
+	log.Trace("[SCIM] User deleted by %s: %s", doer.Name, u.Name)
+	c.Resp.WriteHeader(http.StatusNoContent)
+}
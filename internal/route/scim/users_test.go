@@ -0,0 +1,134 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package scim
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gogs.io/gogs/internal/db"
+)
+
+func Test_userResource_fullName(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource userResource
+		want     string
+	}{
+		{
+			name: "display name",
+			resource: userResource{
+				DisplayName: "Alice",
+				Name:        &userName{Formatted: "Alice Liddell"},
+			},
+			want: "Alice",
+		},
+		{
+			name:     "formatted name",
+			resource: userResource{Name: &userName{Formatted: "Alice Liddell"}},
+			want:     "Alice Liddell",
+		},
+		{
+			name:     "given and family names",
+			resource: userResource{Name: &userName{GivenName: "Alice", FamilyName: "Liddell"}},
+			want:     "Alice Liddell",
+		},
+		{
+			name:     "no name",
+			resource: userResource{},
+			want:     "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.resource.fullName())
+		})
+	}
+}
+
+func Test_applyUserPatch(t *testing.T) {
+	newResource := func() *userResource {
+		return toUserResource(&db.User{
+			ID:       1,
+			Name:     "alice",
+			FullName: "Alice",
+			Email:    "alice@example.com",
+		})
+	}
+
+	tests := []struct {
+		name    string
+		ops     string
+		assert  func(t *testing.T, r *userResource)
+		wantErr error
+	}{
+		{
+			name: "deactivate without path",
+			ops:  `[{"op": "replace", "value": {"active": false}}]`,
+			assert: func(t *testing.T, r *userResource) {
+				assert.False(t, *r.Active)
+			},
+		},
+		{
+			name: "deactivate with boolean as string",
+			ops:  `[{"op": "Replace", "path": "active", "value": "False"}]`,
+			assert: func(t *testing.T, r *userResource) {
+				assert.False(t, *r.Active)
+			},
+		},
+		{
+			name: "rename and change email",
+			ops: `[
+				{"op": "replace", "path": "userName", "value": "alice2"},
+				{"op": "replace", "path": "emails[type eq \"work\"].value", "value": "alice2@example.com"}
+			]`,
+			assert: func(t *testing.T, r *userResource) {
+				assert.Equal(t, "alice2", r.UserName)
+				assert.Equal(t, "alice2@example.com", r.primaryEmail())
+			},
+		},
+		{
+			name: "change given name",
+			ops:  `[{"op": "add", "path": "name.givenName", "value": "Alicia"}]`,
+			assert: func(t *testing.T, r *userResource) {
+				assert.Equal(t, "Alicia", r.fullName())
+			},
+		},
+		{
+			name: "ignore unknown attributes",
+			ops:  `[{"op": "add", "value": {"title": "Engineer", "displayName": "Alice L."}}]`,
+			assert: func(t *testing.T, r *userResource) {
+				assert.Equal(t, "Alice L.", r.fullName())
+			},
+		},
+		{
+			name:    "invalid value",
+			ops:     `[{"op": "replace", "path": "active", "value": "maybe"}]`,
+			wantErr: invalidError{scimType: "invalidValue", detail: "Invalid value of active"},
+		},
+		{
+			name:    "unsupported operation",
+			ops:     `[{"op": "remove", "path": "displayName"}]`,
+			wantErr: invalidError{scimType: "invalidValue", detail: "Unsupported operation: remove"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var ops []patchOperation
+			err := jsoniter.UnmarshalFromString(test.ops, &ops)
+			require.NoError(t, err)
+
+			r := newResource()
+			err = applyUserPatch(r, ops)
+			assert.Equal(t, test.wantErr, err)
+			if test.assert != nil {
+				test.assert(t, r)
+			}
+		})
+	}
+}
@@ -0,0 +1,453 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package snippet
+
+import (
+	"bytes"
+	"fmt"
+	gotemplate "html/template"
+	"net/http"
+	"strings"
+
+	"github.com/unknwon/paginater"
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/form"
+	"gogs.io/gogs/internal/markup"
+	"gogs.io/gogs/internal/template"
+	"gogs.io/gogs/internal/template/highlight"
+	"gogs.io/gogs/internal/tool"
+)
+
+const (
+	LIST      = "snippet/list"
+	NEW       = "snippet/new"
+	VIEW      = "snippet/view"
+	REVISIONS = "snippet/revisions"
+)
+
+// MustEnable responds 404 when snippets are disabled.
+func MustEnable(c *context.Context) {
+	if !conf.Snippet.Enabled {
+		c.NotFound()
+		return
+	}
+}
+
+// MustAllowList responds 404 when tenant isolation is enabled and the user is
+// not an admin, unless listing snippets of the user or an organization the user
+// belongs to.
+func MustAllowList(c *context.Context) {
+	if !conf.Security.EnableTenantIsolation || (c.IsLogged && c.User.IsAdmin) {
+		return
+	}
+
+	owner, err := db.GetUserByName(c.Query("owner"))
+	if err != nil || !db.CanWriteSnippets(owner.ID, c.User) {
+		c.NotFound()
+		return
+	}
+}
+
+// Assignment loads the snippet from the URL and responds 404 when the user is
+// not allowed to view it.
+func Assignment(c *context.Context) {
+	s, err := db.Snippets.GetByName(c.Req.Context(), c.Params(":name"))
+	if err != nil {
+		c.NotFoundOrError(err, "get snippet by name")
+		return
+	} else if !s.IsReadableBy(c.User) {
+		c.NotFound()
+		return
+	}
+
+	owner, err := db.GetUserByID(s.OwnerID)
+	if err != nil {
+		c.NotFoundOrError(err, "get owner")
+		return
+	}
+
+	c.Data["Snippet"] = s
+	c.Data["SnippetOwner"] = owner
+	c.Data["SnippetLink"] = conf.Server.Subpath + "/snippets/" + s.Name
+	c.Data["CanWriteSnippet"] = db.CanWriteSnippets(s.OwnerID, c.User)
+}
+
+// MustBeWritable responds 404 when the user is not allowed to edit the
+// snippet.
+func MustBeWritable(c *context.Context) {
+	if !c.Data["CanWriteSnippet"].(bool) {
+		c.NotFound()
+		return
+	}
+}
+
+func snippet(c *context.Context) *db.Snippet {
+	return c.Data["Snippet"].(*db.Snippet)
+}
+
+// SnippetOwner is a snippet with its owner for listing.
+type SnippetOwner struct {
+	*db.Snippet
+	Owner *db.User
+}
+
+func List(c *context.Context) {
+	c.Data["Title"] = c.Tr("explore")
+	c.Data["PageIsExplore"] = true
+	c.Data["PageIsExploreSnippets"] = true
+
+	page := c.QueryInt("page")
+	if page <= 0 {
+		page = 1
+	}
+
+	opts := db.ListSnippetsOptions{
+		Visibilities: []db.SnippetVisibility{db.SnippetPublic},
+		Page:         page,
+		PageSize:     conf.UI.ExplorePagingNum,
+	}
+	if ownerName := c.Query("owner"); ownerName != "" {
+		owner, err := db.GetUserByName(ownerName)
+		if err != nil {
+			c.NotFoundOrError(err, "get owner")
+			return
+		}
+		opts.OwnerID = owner.ID
+
+		// Secret and private snippets are only listed for those who can write
+		// snippets of the owner.
+		if db.CanWriteSnippets(owner.ID, c.User) {
+			opts.Visibilities = nil
+		}
+		c.Data["Owner"] = owner
+	}
+
+	snippets, count, err := db.Snippets.List(c.Req.Context(), opts)
+	if err != nil {
+		c.Error(err, "list snippets")
+		return
+	}
+
+	owners := make(map[int64]*db.User)
+	results := make([]*SnippetOwner, 0, len(snippets))
+	for _, s := range snippets {
+		owner, ok := owners[s.OwnerID]
+		if !ok {
+			owner, err = db.GetUserByID(s.OwnerID)
+			if err != nil {
+				c.Error(err, "get owner")
+				return
+			}
+			owners[s.OwnerID] = owner
+		}
+		results = append(results, &SnippetOwner{
+			Snippet: s,
+			Owner:   owner,
+		})
+	}
+	c.Data["Snippets"] = results
+	c.Data["Total"] = count
+	c.Data["Page"] = paginater.New(int(count), conf.UI.ExplorePagingNum, page, 5)
+
+	c.Success(LIST)
+}
+
+// prepareForm sets data that are needed by the form of snippets.
+func prepareForm(c *context.Context) {
+	c.Data["Visibilities"] = []db.SnippetVisibility{db.SnippetPublic, db.SnippetSecret, db.SnippetPrivate}
+	c.Data["MaxFiles"] = conf.Snippet.MaxFiles
+	c.Data["MaxFileSize"] = conf.Snippet.MaxFileSize
+}
+
+// prepareOwners sets owners that the user is allowed to create snippets for.
+func prepareOwners(c *context.Context) bool {
+	orgs, err := db.GetOrgsByUserID(c.User.ID, true)
+	if err != nil {
+		c.Error(err, "get organizations by user ID")
+		return false
+	}
+	c.Data["Owners"] = append([]*db.User{c.User}, orgs...)
+	return true
+}
+
+// snippetFiles returns files of the form, skipping entries with neither name
+// nor content.
+func snippetFiles(f form.Snippet) []*db.SnippetFile {
+	files := make([]*db.SnippetFile, 0, len(f.FileName))
+	for i, name := range f.FileName {
+		var content string
+		if i < len(f.Content) {
+			content = f.Content[i]
+		}
+		name = strings.TrimSpace(name)
+		if name == "" && content == "" {
+			continue
+		}
+		files = append(files, &db.SnippetFile{
+			Name:    name,
+			Content: []byte(content),
+		})
+	}
+	return files
+}
+
+func New(c *context.Context) {
+	c.Data["Title"] = c.Tr("snippets.new")
+	c.Data["PageIsSnippetNew"] = true
+	prepareForm(c)
+	c.Data["visibility"] = string(db.SnippetPublic)
+	c.Data["owner_id"] = c.User.ID
+	c.Data["Files"] = []*db.SnippetFile{{}}
+	if !prepareOwners(c) {
+		return
+	}
+
+	c.Success(NEW)
+}
+
+func NewPost(c *context.Context, f form.Snippet) {
+	c.Data["Title"] = c.Tr("snippets.new")
+	c.Data["PageIsSnippetNew"] = true
+	prepareForm(c)
+	if !prepareOwners(c) {
+		return
+	}
+
+	files := snippetFiles(f)
+	c.Data["owner_id"] = f.OwnerID
+	c.Data["Files"] = files
+	if len(files) == 0 {
+		c.Data["Files"] = []*db.SnippetFile{{}}
+	}
+
+	if c.HasError() {
+		c.Success(NEW)
+		return
+	}
+
+	owner, err := db.GetUserByID(f.OwnerID)
+	if err != nil {
+		c.NotFoundOrError(err, "get owner")
+		return
+	} else if !db.CanWriteSnippets(owner.ID, c.User) {
+		c.NotFound()
+		return
+	}
+
+	s, err := db.CreateSnippet(c.Req.Context(), c.User, owner, db.CreateSnippetOptions{
+		Title:      f.Title,
+		Visibility: db.SnippetVisibility(f.Visibility),
+	}, files)
+	if err != nil {
+		if db.IsErrSnippetInvalid(err) {
+			c.RenderWithErr(c.Tr("snippets.invalid_files", conf.Snippet.MaxFiles, conf.Snippet.MaxFileSize), NEW, &f)
+		} else {
+			c.Error(err, "create snippet")
+		}
+		return
+	}
+
+	log.Trace("Snippet created by %q: %s", c.User.Name, s.Name)
+	c.RedirectSubpath("/snippets/" + s.Name)
+}
+
+// FileView is a file of a snippet prepared for rendering.
+type FileView struct {
+	Name           string
+	Size           int64
+	IsTextFile     bool
+	IsFileTooLarge bool
+	IsMarkdown     bool
+	HighlightClass string
+	Content        gotemplate.HTML
+	LineNums       gotemplate.HTML
+}
+
+// renderFile renders the file the same way as a file of a repository.
+func renderFile(c *context.Context, f *db.SnippetFile) *FileView {
+	v := &FileView{
+		Name:           f.Name,
+		Size:           int64(len(f.Content)),
+		IsTextFile:     tool.IsTextFile(f.Content),
+		HighlightClass: highlight.FileNameToHighlightClass(f.Name),
+	}
+	if !v.IsTextFile {
+		return v
+	} else if v.Size >= conf.UI.MaxDisplayFileSize {
+		v.IsFileTooLarge = true
+		return v
+	}
+
+	if markup.Detect(f.Name) == markup.TypeMarkdown {
+		v.IsMarkdown = true
+		v.Content = gotemplate.HTML(markup.Markdown(f.Content, c.Data["SnippetLink"].(string), nil))
+		return v
+	}
+
+	fileContent := string(f.Content)
+	if err, content := template.ToUTF8WithErr(f.Content); err != nil {
+		log.Error("ToUTF8WithErr: %s", err)
+	} else {
+		fileContent = content
+	}
+
+	var output bytes.Buffer
+	lines := strings.Split(fileContent, "\n")
+	// Remove blank line at the end of file
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	for index, line := range lines {
+		output.WriteString(fmt.Sprintf(`<li class="L%d" rel="L%d">%s</li>`, index+1, index+1, gotemplate.HTMLEscapeString(strings.TrimRight(line, "\r"))) + "\n")
+	}
+	v.Content = gotemplate.HTML(output.String())
+
+	output.Reset()
+	for i := 0; i < len(lines); i++ {
+		output.WriteString(fmt.Sprintf(`<span id="L%d">%d</span>`, i+1, i+1))
+	}
+	v.LineNums = gotemplate.HTML(output.String())
+	return v
+}
+
+func View(c *context.Context) {
+	s := snippet(c)
+	c.Data["Title"] = s.Title
+	if s.Title == "" {
+		c.Data["Title"] = s.Name
+	}
+
+	revision := c.Params(":sha")
+	files, err := s.Files(revision)
+	if err != nil {
+		c.NotFoundOrError(err, "get files")
+		return
+	}
+
+	views := make([]*FileView, len(files))
+	for i := range files {
+		views[i] = renderFile(c, files[i])
+	}
+	c.Data["Files"] = views
+	c.Data["Revision"] = revision
+	c.RequireHighlightJS()
+
+	c.Success(VIEW)
+}
+
+func Revisions(c *context.Context) {
+	s := snippet(c)
+	c.Data["Title"] = c.Tr("snippets.revisions")
+	c.Data["PageIsSnippetRevisions"] = true
+
+	commits, err := s.Revisions()
+	if err != nil {
+		c.Error(err, "list revisions")
+		return
+	}
+	c.Data["Commits"] = commits
+
+	c.Success(REVISIONS)
+}
+
+func Raw(c *context.Context) {
+	files, err := snippet(c).Files(c.Query("revision"))
+	if err != nil {
+		c.NotFoundOrError(err, "get files")
+		return
+	}
+
+	name := c.Params(":filename")
+	for _, f := range files {
+		if f.Name != name {
+			continue
+		}
+
+		if tool.IsTextFile(f.Content) {
+			c.Resp.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		} else {
+			c.Resp.Header().Set("Content-Disposition", "attachment; filename=\""+f.Name+"\"")
+			c.Resp.Header().Set("Content-Transfer-Encoding", "binary")
+		}
+		c.Resp.WriteHeader(http.StatusOK)
+		if _, err = c.Resp.Write(f.Content); err != nil {
+			log.Error("Failed to write raw file of snippet: %v", err)
+		}
+		return
+	}
+	c.NotFound()
+}
+
+func Edit(c *context.Context) {
+	s := snippet(c)
+	c.Data["Title"] = c.Tr("snippets.edit")
+	c.Data["PageIsSnippetEdit"] = true
+	prepareForm(c)
+
+	files, err := s.Files("")
+	if err != nil {
+		c.Error(err, "get files")
+		return
+	}
+	if len(files) == 0 {
+		files = []*db.SnippetFile{{}}
+	}
+	c.Data["title"] = s.Title
+	c.Data["visibility"] = string(s.Visibility)
+	c.Data["Files"] = files
+
+	c.Success(NEW)
+}
+
+func EditPost(c *context.Context, f form.Snippet) {
+	s := snippet(c)
+	c.Data["Title"] = c.Tr("snippets.edit")
+	c.Data["PageIsSnippetEdit"] = true
+	prepareForm(c)
+
+	files := snippetFiles(f)
+	c.Data["Files"] = files
+	if len(files) == 0 {
+		c.Data["Files"] = []*db.SnippetFile{{}}
+	}
+
+	if c.HasError() {
+		c.Success(NEW)
+		return
+	}
+
+	err := db.UpdateSnippet(c.Req.Context(), c.User, s, db.UpdateSnippetOptions{
+		Title:      f.Title,
+		Visibility: db.SnippetVisibility(f.Visibility),
+	}, files)
+	if err != nil {
+		if db.IsErrSnippetInvalid(err) {
+			c.RenderWithErr(c.Tr("snippets.invalid_files", conf.Snippet.MaxFiles, conf.Snippet.MaxFileSize), NEW, &f)
+		} else {
+			c.Error(err, "update snippet")
+		}
+		return
+	}
+
+	log.Trace("Snippet updated by %q: %s", c.User.Name, s.Name)
+	c.Flash.Success(c.Tr("snippets.update_success"))
+	c.RedirectSubpath("/snippets/" + s.Name)
+}
+
+func Delete(c *context.Context) {
+	s := snippet(c)
+	if err := db.DeleteSnippet(c.Req.Context(), s); err != nil {
+		c.Error(err, "delete snippet")
+		return
+	}
+
+	log.Trace("Snippet deleted by %q: %s", c.User.Name, s.Name)
+	c.Flash.Success(c.Tr("snippets.deletion_success"))
+	c.RedirectSubpath("/snippets?owner=" + c.Data["SnippetOwner"].(*db.User).Name)
+}
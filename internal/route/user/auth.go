@@ -7,6 +7,7 @@ package user
 import (
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/go-macaron/captcha"
 	"github.com/pkg/errors"
@@ -29,6 +30,7 @@ const (
 	ACTIVATE                 = "user/auth/activate"
 	FORGOT_PASSWORD          = "user/auth/forgot_passwd"
 	RESET_PASSWORD           = "user/auth/reset_passwd"
+	ADMIN_RECOVERY           = "user/auth/admin_recovery"
 )
 
 // AutoLogin reads cookie and try to auto-login.
@@ -67,6 +69,7 @@ func AutoLogin(c *context.Context) (bool, error) {
 	isSucceed = true
 	_ = c.Session.Set("uid", u.ID)
 	_ = c.Session.Set("uname", u.Name)
+	_ = c.Session.Set("session_epoch", u.SessionEpoch)
 	c.SetCookie(conf.Session.CSRFCookieName, "", -1, conf.Server.Subpath)
 	if conf.Security.EnableLoginStatusCookie {
 		c.SetCookie(conf.Security.LoginStatusCookieName, "true", 0, conf.Server.Subpath)
@@ -127,6 +130,7 @@ func afterLogin(c *context.Context, u *db.User, remember bool) {
 
 	_ = c.Session.Set("uid", u.ID)
 	_ = c.Session.Set("uname", u.Name)
+	_ = c.Session.Set("session_epoch", u.SessionEpoch)
 	_ = c.Session.Delete("twoFactorRemember")
 	_ = c.Session.Delete("twoFactorUserID")
 
@@ -426,6 +430,7 @@ func Activate(c *context.Context) {
 
 		_ = c.Session.Set("uid", user.ID)
 		_ = c.Session.Set("uname", user.Name)
+		_ = c.Session.Set("session_epoch", user.SessionEpoch)
 		c.RedirectSubpath("/")
 		return
 	}
@@ -479,7 +484,7 @@ func ForgotPasswdPost(c *context.Context) {
 	u, err := db.GetUserByEmail(emailAddr)
 	if err != nil {
 		if db.IsErrUserNotExist(err) {
-			c.Data["Hours"] = conf.Auth.ActivateCodeLives / 60
+			c.Data["Minutes"] = conf.Auth.ResetPasswordCodeLives
 			c.Data["IsResetSent"] = true
 			c.Success(FORGOT_PASSWORD)
 			return
@@ -501,12 +506,19 @@ func ForgotPasswdPost(c *context.Context) {
 		return
 	}
 
-	email.SendResetPasswordMail(c.Context, db.NewMailerUser(u))
+	ttl := time.Duration(conf.Auth.ResetPasswordCodeLives) * time.Minute
+	token, err := db.PasswordResetTokens.Create(c.Req.Context(), u.ID, c.RemoteAddr(), ttl)
+	if err != nil {
+		c.Error(err, "create password reset token")
+		return
+	}
+	email.SendResetPasswordMail(c.Context, db.NewMailerUser(u), token.Token)
 	if err = c.Cache.Put(u.MailResendCacheKey(), 1, 180); err != nil {
 		log.Error("Failed to put cache key 'mail resend': %v", err)
 	}
+	log.Trace("Password reset requested for %q from %s", u.Name, c.RemoteAddr())
 
-	c.Data["Hours"] = conf.Auth.ActivateCodeLives / 60
+	c.Data["Minutes"] = conf.Auth.ResetPasswordCodeLives
 	c.Data["IsResetSent"] = true
 	c.Success(FORGOT_PASSWORD)
 }
@@ -520,7 +532,17 @@ func ResetPasswd(c *context.Context) {
 		return
 	}
 	c.Data["Code"] = code
-	c.Data["IsResetForm"] = true
+
+	_, err := db.PasswordResetTokens.GetByToken(c.Req.Context(), code)
+	if err != nil {
+		if !db.IsErrPasswordResetTokenNotExist(err) {
+			c.Error(err, "get password reset token")
+			return
+		}
+		c.Data["IsResetFailed"] = true
+	} else {
+		c.Data["IsResetForm"] = true
+	}
 	c.Success(RESET_PASSWORD)
 }
 
@@ -534,37 +556,92 @@ func ResetPasswdPost(c *context.Context) {
 	}
 	c.Data["Code"] = code
 
-	if u := db.VerifyUserActiveCode(code); u != nil {
-		// Validate password length.
-		passwd := c.Query("password")
-		if len(passwd) < 6 {
-			c.Data["IsResetForm"] = true
-			c.Data["Err_Password"] = true
-			c.RenderWithErr(c.Tr("auth.password_too_short"), RESET_PASSWORD, nil)
-			return
-		}
+	// Validate password length before using up the token.
+	passwd := c.Query("password")
+	if len(passwd) < 6 {
+		c.Data["IsResetForm"] = true
+		c.Data["Err_Password"] = true
+		c.RenderWithErr(c.Tr("auth.password_too_short"), RESET_PASSWORD, nil)
+		return
+	}
 
-		u.Passwd = passwd
-		var err error
-		if u.Rands, err = db.GetUserSalt(); err != nil {
-			c.Error(err, "get user salt")
-			return
-		}
-		if u.Salt, err = db.GetUserSalt(); err != nil {
-			c.Error(err, "get user salt")
+	token, err := db.PasswordResetTokens.Use(c.Req.Context(), code, c.RemoteAddr())
+	if err != nil {
+		if !db.IsErrPasswordResetTokenNotExist(err) {
+			c.Error(err, "use password reset token")
 			return
 		}
-		u.EncodePassword()
-		if err := db.UpdateUser(u); err != nil {
-			c.Error(err, "update user")
+		c.Data["IsResetFailed"] = true
+		c.Success(RESET_PASSWORD)
+		return
+	}
+
+	u, err := db.GetUserByID(token.UserID)
+	if err != nil {
+		c.NotFoundOrError(err, "get user by ID")
+		return
+	}
+	if err = db.ResetUserPassword(u, passwd); err != nil {
+		c.Error(err, "reset user password")
+		return
+	}
+
+	log.Trace("User password reset: %s from %s", u.Name, c.RemoteAddr())
+	c.RedirectSubpath("/user/login")
+}
+
+// AdminRecovery shows the page to sign in with an admin recovery URL issued by
+// the "gogs admin recover" command. The token is only used by the POST request,
+// so that it is not used up by previews of the link.
+func AdminRecovery(c *context.Context) {
+	c.Title("auth.admin_recovery")
+
+	code := c.Query("code")
+	if code == "" {
+		c.NotFound()
+		return
+	}
+	c.Data["Code"] = code
+	c.Success(ADMIN_RECOVERY)
+}
+
+// AdminRecoveryPost signs in as the site admin that the admin recovery token
+// was issued to, without password or two-factor authentication.
+func AdminRecoveryPost(c *context.Context) {
+	c.Title("auth.admin_recovery")
+
+	code := c.Query("code")
+	if code == "" {
+		c.NotFound()
+		return
+	}
+	c.Data["Code"] = code
+
+	token, err := db.AdminRecoveryTokens.Use(c.Req.Context(), code, c.RemoteAddr())
+	if err != nil {
+		if !db.IsErrAdminRecoveryTokenNotExist(err) {
+			c.Error(err, "use admin recovery token")
 			return
 		}
+		c.Data["IsRecoveryFailed"] = true
+		c.Success(ADMIN_RECOVERY)
+		return
+	}
 
-		log.Trace("User password reset: %s", u.Name)
-		c.RedirectSubpath("/user/login")
+	u, err := db.Users.GetByID(c.Req.Context(), token.UserID)
+	if err != nil {
+		c.NotFoundOrError(err, "get user by ID")
+		return
+	} else if !u.IsAdmin || !u.IsActive || u.ProhibitLogin {
+		c.Data["IsRecoveryFailed"] = true
+		c.Success(ADMIN_RECOVERY)
 		return
 	}
 
-	c.Data["IsResetFailed"] = true
-	c.Success(RESET_PASSWORD)
+	desc := fmt.Sprintf("Admin recovery sign-in URL for %q was used from %s.", u.Name, c.RemoteAddr())
+	if err = db.CreateNotice(db.NOTICE_SECURITY, db.NoticeLevelWarn, desc); err != nil {
+		log.Error("Failed to create notice: %v", err)
+	}
+	log.Info("Admin %q signed in with recovery URL from %s", u.Name, c.RemoteAddr())
+	afterLogin(c, u, false)
 }
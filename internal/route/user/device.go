@@ -0,0 +1,202 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	log "unknwon.dev/clog/v2"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+const (
+	DEVICE = "user/auth/device"
+)
+
+// deviceCodeGrantType is the grant type of the OAuth 2.0 Device Authorization
+// Grant, see https://datatracker.ietf.org/doc/html/rfc8628#section-3.4.
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// MustEnableDeviceAuthorization responds 404 when the device authorization flow
+// is disabled.
+func MustEnableDeviceAuthorization(c *context.Context) {
+	if !conf.Auth.EnableDeviceAuthorization {
+		c.NotFound()
+		return
+	}
+}
+
+// deviceError responds an error in the format described by
+// https://datatracker.ietf.org/doc/html/rfc6749#section-5.2.
+func deviceError(c *context.Context, status int, code, description string) {
+	c.JSON(status, map[string]string{
+		"error":             code,
+		"error_description": description,
+	})
+}
+
+// DeviceCodePost creates a new device authorization and responds with the codes
+// for the device to display and poll with, see
+// https://datatracker.ietf.org/doc/html/rfc8628#section-3.2.
+func DeviceCodePost(c *context.Context) {
+	clientID := c.Query("client_id")
+	if clientID == "" {
+		deviceError(c, http.StatusBadRequest, "invalid_request", `The "client_id" is required.`)
+		return
+	}
+
+	// Opportunistically clean up expired requests to keep the table small.
+	if err := db.DeviceAuthorizations.DeleteExpired(c.Req.Context()); err != nil {
+		log.Error("Failed to delete expired device authorizations: %v", err)
+	}
+
+	lifetime := time.Duration(conf.Auth.DeviceCodeLives) * time.Minute
+	auth, err := db.DeviceAuthorizations.Create(c.Req.Context(), clientID, lifetime)
+	if err != nil {
+		c.Error(err, "create device authorization")
+		return
+	}
+
+	verificationURI := conf.Server.ExternalURL + "login/device"
+	c.JSONSuccess(map[string]interface{}{
+		"device_code":               auth.DeviceCode,
+		"user_code":                 auth.UserCode,
+		"verification_uri":          verificationURI,
+		"verification_uri_complete": verificationURI + "?user_code=" + auth.UserCode,
+		"expires_in":                int(lifetime.Seconds()),
+		"interval":                  conf.Auth.DeviceCodePollInterval,
+	})
+}
+
+// AccessTokenPost exchanges an approved device code for an access token, see
+// https://datatracker.ietf.org/doc/html/rfc8628#section-3.4.
+func AccessTokenPost(c *context.Context) {
+	if c.Query("grant_type") != deviceCodeGrantType {
+		deviceError(c, http.StatusBadRequest, "unsupported_grant_type", fmt.Sprintf("Only the grant type %q is supported.", deviceCodeGrantType))
+		return
+	}
+
+	auth, err := db.DeviceAuthorizations.GetByDeviceCode(c.Req.Context(), c.Query("device_code"))
+	if err != nil {
+		if db.IsErrDeviceAuthorizationNotExist(err) {
+			deviceError(c, http.StatusBadRequest, "invalid_grant", "The device code is not valid.")
+		} else {
+			c.Error(err, "get device authorization by device code")
+		}
+		return
+	} else if auth.ClientID != c.Query("client_id") {
+		deviceError(c, http.StatusBadRequest, "invalid_grant", "The device code was issued to another client.")
+		return
+	}
+
+	now := time.Now()
+	if auth.IsExpired(now) {
+		deviceError(c, http.StatusBadRequest, "expired_token", "The device code has expired.")
+		return
+	}
+
+	switch auth.Status {
+	case db.DeviceAuthorizationPending:
+		interval := time.Duration(conf.Auth.DeviceCodePollInterval) * time.Second
+		if now.Before(auth.PolledAt.Add(interval)) {
+			deviceError(c, http.StatusBadRequest, "slow_down", "The device is polling too frequently.")
+			return
+		}
+
+		if err = db.DeviceAuthorizations.Touch(c.Req.Context(), auth.ID); err != nil {
+			log.Error("Failed to touch device authorization: %v", err)
+		}
+		deviceError(c, http.StatusBadRequest, "authorization_pending", "The authorization request is still pending.")
+		return
+
+	case db.DeviceAuthorizationDenied:
+		deviceError(c, http.StatusBadRequest, "access_denied", "The authorization request was denied.")
+		return
+	}
+
+	// The device code is single-use, delete it before issuing the token to avoid
+	// issuing multiple tokens for concurrent polling requests. Only the request
+	// that actually deletes it issues the token.
+	if err = db.DeviceAuthorizations.DeleteByID(c.Req.Context(), auth.ID); err != nil {
+		if db.IsErrDeviceAuthorizationNotExist(err) {
+			deviceError(c, http.StatusBadRequest, "invalid_grant", "The device code is not valid.")
+		} else {
+			c.Error(err, "delete device authorization")
+		}
+		return
+	}
+
+	name := fmt.Sprintf("%s (device %s)", auth.ClientID, auth.UserCode)
+	token, err := db.AccessTokens.Create(c.Req.Context(), auth.UserID, name)
+	if err != nil {
+		c.Error(err, "create access token")
+		return
+	}
+
+	c.JSONSuccess(map[string]string{
+		"access_token": token.Sha1,
+		"token_type":   "bearer",
+		"scope":        "",
+	})
+}
+
+// Device shows the page for the user to enter and confirm the user code
+// displayed on a device.
+func Device(c *context.Context) {
+	c.Title("auth.device_authorization")
+
+	userCode := c.Query("user_code")
+	if userCode == "" {
+		c.Success(DEVICE)
+		return
+	}
+
+	auth, err := db.DeviceAuthorizations.GetByUserCode(c.Req.Context(), userCode)
+	if err != nil {
+		if db.IsErrDeviceAuthorizationNotExist(err) {
+			c.Data["user_code"] = userCode
+			c.RenderWithErr(c.Tr("auth.device_invalid_user_code"), DEVICE, nil)
+		} else {
+			c.Error(err, "get device authorization by user code")
+		}
+		return
+	}
+
+	c.Data["DeviceAuthorization"] = auth
+	c.Success(DEVICE)
+}
+
+func DevicePost(c *context.Context) {
+	c.Title("auth.device_authorization")
+
+	userCode := c.Query("user_code")
+	var err error
+	if c.Query("action") == "approve" {
+		err = db.DeviceAuthorizations.Approve(c.Req.Context(), userCode, c.User.ID)
+	} else {
+		err = db.DeviceAuthorizations.Deny(c.Req.Context(), userCode, c.User.ID)
+	}
+	if err != nil {
+		if db.IsErrDeviceAuthorizationNotExist(err) {
+			c.Data["user_code"] = userCode
+			c.RenderWithErr(c.Tr("auth.device_invalid_user_code"), DEVICE, nil)
+		} else {
+			c.Error(err, "decide device authorization")
+		}
+		return
+	}
+
+	if c.Query("action") == "approve" {
+		c.Flash.Success(c.Tr("auth.device_approved"))
+	} else {
+		c.Flash.Info(c.Tr("auth.device_denied"))
+	}
+	c.RedirectSubpath("/login/device")
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"strings"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/db"
+)
+
+const (
+	SETTINGS_NOTIFICATIONS = "user/settings/notifications"
+	DIGEST_UNSUBSCRIBE     = "user/digest_unsubscribe"
+)
+
+func SettingsNotifications(c *context.Context) {
+	c.Title("settings.notifications")
+	c.PageIs("SettingsNotifications")
+
+	subscriptions, err := db.Digests.ListByUser(c.Req.Context(), c.User.ID)
+	if err != nil {
+		c.Error(err, "list digest subscriptions")
+		return
+	}
+	subscribed := make(map[db.DigestFrequency]bool, len(subscriptions))
+	for _, s := range subscriptions {
+		subscribed[s.Frequency] = true
+	}
+	c.Data["DigestFrequencies"] = db.AllDigestFrequencies
+	c.Data["SubscribedDigests"] = subscribed
+	c.Data["CanSendEmail"] = conf.Email.Enabled
+
+	hours := make([]int, 24)
+	for i := range hours {
+		hours[i] = i
+	}
+	c.Data["Hours"] = hours
+
+	c.Success(SETTINGS_NOTIFICATIONS)
+}
+
+func SettingsNotificationsPost(c *context.Context) {
+	for _, frequency := range db.AllDigestFrequencies {
+		var err error
+		if c.Query("digest_"+string(frequency)) == "on" {
+			err = db.Digests.Subscribe(c.Req.Context(), c.User.ID, frequency)
+		} else {
+			err = db.Digests.Unsubscribe(c.Req.Context(), c.User.ID, frequency)
+		}
+		if err != nil {
+			c.Errorf(err, "update %s digest subscription", frequency)
+			return
+		}
+	}
+
+	c.Flash.Success(c.Tr("settings.update_notifications_success"))
+	c.RedirectSubpath("/user/settings/notifications")
+}
+
+// SettingsNotificationSchedulePost updates the time zone and quiet hours that
+// non-urgent email notifications are deferred during.
+func SettingsNotificationSchedulePost(c *context.Context) {
+	err := db.UpdateNotificationSchedule(c.User, db.NotificationScheduleOptions{
+		Timezone:         strings.TrimSpace(c.Query("timezone")),
+		EnableQuietHours: c.Query("enable_quiet_hours") == "on",
+		QuietHoursStart:  c.QueryInt("quiet_hours_start"),
+		QuietHoursEnd:    c.QueryInt("quiet_hours_end"),
+		QuietWeekends:    c.Query("quiet_weekends") == "on",
+	})
+	if err != nil {
+		if db.IsErrNotificationScheduleInvalid(err) {
+			c.Flash.Error(c.Tr("settings.notification_schedule_invalid"))
+			c.RedirectSubpath("/user/settings/notifications")
+		} else {
+			c.Error(err, "update notification schedule")
+		}
+		return
+	}
+
+	c.Flash.Success(c.Tr("settings.update_notifications_success"))
+	c.RedirectSubpath("/user/settings/notifications")
+}
+
+// parseDigestUnsubscribe returns the user ID and the digest frequency of a
+// digest unsubscribe link. It responds 404 when the link is invalid.
+func parseDigestUnsubscribe(c *context.Context) (int64, db.DigestFrequency, bool) {
+	userID := c.QueryInt64("user")
+	frequency := db.DigestFrequency(c.Query("frequency"))
+	if userID <= 0 || !frequency.IsValid() ||
+		!db.VerifyDigestUnsubscribeToken(userID, frequency, c.Query("token")) {
+		c.NotFound()
+		return 0, "", false
+	}
+	return userID, frequency, true
+}
+
+// DigestUnsubscribe shows the confirmation of unsubscribing from a digest via
+// the link in digest emails, which does not require signing in.
+func DigestUnsubscribe(c *context.Context) {
+	_, frequency, ok := parseDigestUnsubscribe(c)
+	if !ok {
+		return
+	}
+
+	c.Title("settings.digest_unsubscribe")
+	c.Data["Frequency"] = string(frequency)
+	c.Data["Token"] = c.Query("token")
+	c.Data["UserID"] = c.QueryInt64("user")
+	c.Success(DIGEST_UNSUBSCRIBE)
+}
+
+func DigestUnsubscribePost(c *context.Context) {
+	userID, frequency, ok := parseDigestUnsubscribe(c)
+	if !ok {
+		return
+	}
+
+	if err := db.Digests.Unsubscribe(c.Req.Context(), userID, frequency); err != nil {
+		c.Error(err, "unsubscribe from digest")
+		return
+	}
+
+	c.Title("settings.digest_unsubscribe")
+	c.Data["Frequency"] = string(frequency)
+	c.Data["IsUnsubscribed"] = true
+	c.Success(DIGEST_UNSUBSCRIBE)
+}
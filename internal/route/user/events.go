@@ -0,0 +1,18 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"gogs.io/gogs/internal/context"
+	"gogs.io/gogs/internal/events"
+)
+
+// Events streams live notifications of the signed in user as server-sent
+// events.
+func Events(c *context.Context) {
+	sub := events.Subscribe(events.UserTopic(c.User.ID))
+	defer sub.Close()
+	events.ServeStream(c.Resp, c.Req.Request, sub, events.DefaultStreamOptions)
+}
@@ -15,6 +15,7 @@ import (
 	"gogs.io/gogs/internal/conf"
 	"gogs.io/gogs/internal/context"
 	"gogs.io/gogs/internal/db"
+	"gogs.io/gogs/internal/markup"
 )
 
 const (
@@ -96,6 +97,34 @@ func retrieveFeeds(c *context.Context, ctxUser *db.User, userID int64, isProfile
 	}
 }
 
+// dashboardAnnouncement is an announcement of an organization with the rendered
+// content.
+type dashboardAnnouncement struct {
+	Org     *db.User
+	Content string
+}
+
+// dashboardAnnouncements returns announcements to show on the dashboard of the
+// context user, which are of the organization itself for an organization, or of
+// all organizations the logged in user belongs to.
+func dashboardAnnouncements(c *context.Context, ctxUser *db.User) []*dashboardAnnouncement {
+	var orgs []*db.User
+	if ctxUser.IsOrganization() {
+		orgs = db.OrgsWithAnnouncement([]*db.User{ctxUser})
+	} else {
+		orgs = db.OrgsWithAnnouncement(c.User.Orgs)
+	}
+
+	announcements := make([]*dashboardAnnouncement, len(orgs))
+	for i, org := range orgs {
+		announcements[i] = &dashboardAnnouncement{
+			Org:     org,
+			Content: string(markup.Markdown(org.Announcement, org.HomeLink(), nil)),
+		}
+	}
+	return announcements
+}
+
 func Dashboard(c *context.Context) {
 	ctxUser := getDashboardContextUser(c)
 	if c.Written() {
@@ -115,6 +144,7 @@ func Dashboard(c *context.Context) {
 	c.Data["Title"] = ctxUser.DisplayName() + " - " + c.Tr("dashboard")
 	c.Data["PageIsDashboard"] = true
 	c.Data["PageIsNews"] = true
+	c.Data["Announcements"] = dashboardAnnouncements(c, ctxUser)
 
 	// Only user can have collaborative repositories.
 	if !ctxUser.IsOrganization() {
@@ -367,6 +397,20 @@ func ShowSSHKeys(c *context.Context, uid int64) {
 	c.PlainText(http.StatusOK, buf.String())
 }
 
+func ShowGPGKeys(c *context.Context, uid int64) {
+	keys, err := db.GPGKeys.List(c.Req.Context(), uid)
+	if err != nil {
+		c.Error(err, "list GPG keys")
+		return
+	}
+
+	var buf bytes.Buffer
+	for i := range keys {
+		buf.WriteString(keys[i].Content)
+	}
+	c.PlainText(http.StatusOK, buf.String())
+}
+
 func showOrgProfile(c *context.Context) {
 	c.SetParams(":org", c.Params(":username"))
 	context.HandleOrgAssignment(c)
@@ -419,6 +463,19 @@ func showOrgProfile(c *context.Context) {
 
 	c.Data["Teams"] = org.Teams
 
+	domains, err := db.OrgDomains.List(c.Req.Context(), org.ID)
+	if err != nil {
+		c.Error(err, "list domains")
+		return
+	}
+	verifiedDomains := make([]string, 0, len(domains))
+	for _, d := range domains {
+		if d.IsVerified {
+			verifiedDomains = append(verifiedDomains, d.Domain)
+		}
+	}
+	c.Data["VerifiedDomains"] = verifiedDomains
+
 	c.Success(ORG_HOME)
 }
 
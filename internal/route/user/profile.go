@@ -6,6 +6,7 @@ package user
 
 import (
 	"strings"
+	"time"
 
 	"github.com/unknwon/paginater"
 
@@ -21,15 +22,31 @@ const (
 	STARS     = "user/meta/stars"
 )
 
-func Profile(c *context.Context, puser *context.ParamsUser) {
-	isShowKeys := false
-	if strings.HasSuffix(c.Params(":username"), ".keys") {
-		isShowKeys = true
+func Profile(c *context.Context) {
+	// Public keys of the user are served at "/<username>.keys" and
+	// "/<username>.gpg", which are reserved from being usernames.
+	name := c.Params(":username")
+	var showKeys func(c *context.Context, uid int64)
+	switch {
+	case strings.HasSuffix(name, ".keys"):
+		name = strings.TrimSuffix(name, ".keys")
+		showKeys = ShowSSHKeys
+	case strings.HasSuffix(name, ".gpg"):
+		name = strings.TrimSuffix(name, ".gpg")
+		showKeys = ShowGPGKeys
 	}
 
-	// Show SSH keys.
-	if isShowKeys {
-		ShowSSHKeys(c, puser.ID)
+	puser, err := db.GetUserByName(name)
+	if err != nil {
+		c.NotFoundOrError(err, "get user by name")
+		return
+	} else if !db.IsTenantVisible(c.User, puser) {
+		c.NotFound()
+		return
+	}
+
+	if showKeys != nil {
+		showKeys(c, puser.ID)
 		return
 	}
 
@@ -50,11 +67,30 @@ func Profile(c *context.Context, puser *context.ParamsUser) {
 
 	c.Data["Orgs"] = orgs
 
+	// Profile fields are only shown to signed in users.
+	if c.IsLogged {
+		c.Data["ProfileFields"], err = db.ListProfileFieldEntries(c.Req.Context(), puser.ID, db.ProfileFieldScopeUser)
+		if err != nil {
+			c.Error(err, "list profile field entries")
+			return
+		}
+	}
+
+	if puser.CanViewContributions(c.User) {
+		now := time.Now()
+		contributions, err := puser.ListContributionsForViewer(c.Req.Context(), c.User, db.ContributionCalendarSince(now))
+		if err != nil {
+			c.Error(err, "list contributions")
+			return
+		}
+		c.Data["ContributionCalendar"] = db.BuildContributionCalendar(contributions, now)
+	}
+
 	tab := c.Query("tab")
 	c.Data["TabName"] = tab
 	switch tab {
 	case "activity":
-		retrieveFeeds(c, puser.User, c.UserID(), true)
+		retrieveFeeds(c, puser, c.UserID(), true)
 		if c.Written() {
 			return
 		}
@@ -26,6 +26,7 @@ import (
 	"gogs.io/gogs/internal/db/errors"
 	"gogs.io/gogs/internal/email"
 	"gogs.io/gogs/internal/form"
+	"gogs.io/gogs/internal/theme"
 	"gogs.io/gogs/internal/tool"
 )
 
@@ -35,6 +36,7 @@ const (
 	SETTINGS_PASSWORD                  = "user/settings/password"
 	SETTINGS_EMAILS                    = "user/settings/email"
 	SETTINGS_SSH_KEYS                  = "user/settings/sshkeys"
+	SETTINGS_GPG_KEYS                  = "user/settings/gpgkeys"
 	SETTINGS_SECURITY                  = "user/settings/security"
 	SETTINGS_TWO_FACTOR_ENABLE         = "user/settings/two_factor_enable"
 	SETTINGS_TWO_FACTOR_RECOVERY_CODES = "user/settings/two_factor_recovery_codes"
@@ -45,15 +47,50 @@ const (
 	NOTIFICATION                       = "user/notification"
 )
 
+// PrepareProfileFields sets profile fields of the scope with values of the
+// user or organization for rendering forms.
+func PrepareProfileFields(c *context.Context, userID int64, scope db.ProfileFieldScope) []*db.ProfileFieldEntry {
+	entries, err := db.ListProfileFieldEntries(c.Req.Context(), userID, scope)
+	if err != nil {
+		c.Error(err, "list profile field entries")
+		return nil
+	}
+	c.Data["ProfileFields"] = entries
+	return entries
+}
+
+// ParseProfileFieldValues returns values of profile fields submitted via the
+// form, keyed by field IDs. Fields that are not editable are skipped unless
+// all is true.
+func ParseProfileFieldValues(c *context.Context, entries []*db.ProfileFieldEntry, all bool) map[int64]string {
+	values := make(map[int64]string, len(entries))
+	for _, e := range entries {
+		if !all && !e.Editable {
+			continue
+		}
+		values[e.ID] = strings.TrimSpace(c.Query(fmt.Sprintf("profile_field_%d", e.ID)))
+	}
+	return values
+}
+
 func Settings(c *context.Context) {
 	c.Title("settings.profile")
 	c.PageIs("SettingsProfile")
+	PrepareProfileFields(c, c.User.ID, db.ProfileFieldScopeUser)
+	if c.Written() {
+		return
+	}
 	c.Data["origin_name"] = c.User.Name
 	c.Data["name"] = c.User.Name
 	c.Data["full_name"] = c.User.FullName
 	c.Data["email"] = c.User.Email
 	c.Data["website"] = c.User.Website
 	c.Data["location"] = c.User.Location
+	c.Data["theme"] = c.User.Theme
+	c.Data["contributions_visibility"] = c.User.ContributionsVisibility
+	if conf.UI.AllowUserThemes {
+		c.Data["Themes"] = theme.List()
+	}
 	c.Success(SETTINGS_PROFILE)
 }
 
@@ -61,6 +98,13 @@ func SettingsPost(c *context.Context, f form.UpdateProfile) {
 	c.Title("settings.profile")
 	c.PageIs("SettingsProfile")
 	c.Data["origin_name"] = c.User.Name
+	profileFields := PrepareProfileFields(c, c.User.ID, db.ProfileFieldScopeUser)
+	if c.Written() {
+		return
+	}
+	if conf.UI.AllowUserThemes {
+		c.Data["Themes"] = theme.List()
+	}
 
 	if c.HasError() {
 		c.Success(SETTINGS_PROFILE)
@@ -100,6 +144,12 @@ func SettingsPost(c *context.Context, f form.UpdateProfile) {
 	c.User.Email = f.Email
 	c.User.Website = f.Website
 	c.User.Location = f.Location
+	if conf.UI.AllowUserThemes && (f.Theme == "" || theme.IsValid(f.Theme)) {
+		c.User.Theme = f.Theme
+	}
+	if db.IsValidContributionsVisibility(f.ContributionsVisibility) {
+		c.User.ContributionsVisibility = f.ContributionsVisibility
+	}
 	if err := db.UpdateUser(c.User); err != nil {
 		if db.IsErrEmailAlreadyUsed(err) {
 			msg := c.Tr("form.email_been_used")
@@ -110,6 +160,12 @@ func SettingsPost(c *context.Context, f form.UpdateProfile) {
 		return
 	}
 
+	err := db.ProfileFields.SetValues(c.Req.Context(), c.User.ID, ParseProfileFieldValues(c, profileFields, false))
+	if err != nil {
+		c.Error(err, "set profile field values")
+		return
+	}
+
 	c.Flash.Success(c.Tr("settings.update_profile_success"))
 	c.RedirectSubpath("/user/settings")
 }
@@ -202,17 +258,12 @@ func SettingsPasswordPost(c *context.Context, f form.ChangePassword) {
 	} else if f.Password != f.Retype {
 		c.Flash.Error(c.Tr("form.password_not_match"))
 	} else {
-		c.User.Passwd = f.Password
-		var err error
-		if c.User.Salt, err = db.GetUserSalt(); err != nil {
-			c.Errorf(err, "get user salt")
-			return
-		}
-		c.User.EncodePassword()
-		if err := db.UpdateUser(c.User); err != nil {
-			c.Errorf(err, "update user")
+		if err := db.ResetUserPassword(c.User, f.Password); err != nil {
+			c.Errorf(err, "reset user password")
 			return
 		}
+		// Other sessions are invalidated, but keep the current one.
+		_ = c.Session.Set("session_epoch", c.User.SessionEpoch)
 		c.Flash.Success(c.Tr("settings.change_password_success"))
 	}
 
@@ -290,6 +341,18 @@ func SettingsEmailPost(c *context.Context, f form.AddEmail) {
 	c.RedirectSubpath("/user/settings/email")
 }
 
+func SettingsEmailPrivacyPost(c *context.Context, f form.UpdateEmailPrivacy) {
+	c.User.KeepEmailPrivate = f.KeepEmailPrivate
+	c.User.BlockEmailExposingPush = f.BlockEmailExposingPush
+	if err := db.UpdateUser(c.User); err != nil {
+		c.Errorf(err, "update user")
+		return
+	}
+
+	c.Flash.Success(c.Tr("settings.update_email_privacy_success"))
+	c.RedirectSubpath("/user/settings/email")
+}
+
 func DeleteEmail(c *context.Context) {
 	if err := db.DeleteEmailAddress(&db.EmailAddress{
 		ID:  c.QueryInt64("id"),
@@ -377,6 +440,68 @@ func DeleteSSHKey(c *context.Context) {
 	})
 }
 
+func SettingsGPGKeys(c *context.Context) {
+	c.Title("settings.gpg_keys")
+	c.PageIs("SettingsGPGKeys")
+
+	keys, err := db.GPGKeys.List(c.Req.Context(), c.User.ID)
+	if err != nil {
+		c.Errorf(err, "list GPG keys")
+		return
+	}
+	c.Data["Keys"] = keys
+
+	c.Success(SETTINGS_GPG_KEYS)
+}
+
+func SettingsGPGKeysPost(c *context.Context, f form.AddGPGKey) {
+	c.Title("settings.gpg_keys")
+	c.PageIs("SettingsGPGKeys")
+
+	keys, err := db.GPGKeys.List(c.Req.Context(), c.User.ID)
+	if err != nil {
+		c.Errorf(err, "list GPG keys")
+		return
+	}
+	c.Data["Keys"] = keys
+
+	if c.HasError() {
+		c.Success(SETTINGS_GPG_KEYS)
+		return
+	}
+
+	key, err := db.GPGKeys.Create(c.Req.Context(), c.User.ID, f.Content)
+	if err != nil {
+		c.Data["HasError"] = true
+		switch {
+		case db.IsErrGPGKeyInvalid(err):
+			c.FormErr("Content")
+			c.RenderWithErr(c.Tr("settings.gpg_key_invalid"), SETTINGS_GPG_KEYS, &f)
+		case db.IsErrGPGKeyAlreadyExist(err):
+			c.FormErr("Content")
+			c.RenderWithErr(c.Tr("settings.gpg_key_been_used"), SETTINGS_GPG_KEYS, &f)
+		default:
+			c.Errorf(err, "create GPG key")
+		}
+		return
+	}
+
+	c.Flash.Success(c.Tr("settings.add_gpg_key_success", key.KeyID))
+	c.RedirectSubpath("/user/settings/gpg")
+}
+
+func DeleteGPGKey(c *context.Context) {
+	if err := db.GPGKeys.DeleteByID(c.Req.Context(), c.User.ID, c.QueryInt64("id")); err != nil {
+		c.Flash.Error("DeleteGPGKey: " + err.Error())
+	} else {
+		c.Flash.Success(c.Tr("settings.gpg_key_deletion_success"))
+	}
+
+	c.JSONSuccess(map[string]interface{}{
+		"redirect": conf.Server.Subpath + "/user/settings/gpg",
+	})
+}
+
 func SettingsSecurity(c *context.Context) {
 	c.Title("settings.security")
 	c.PageIs("SettingsSecurity")
@@ -0,0 +1,135 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package selfcheck checks the integrity of an installation at boot time.
+package selfcheck
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"gogs.io/gogs/internal/conf"
+)
+
+// Problem is a problem found by the self-check.
+type Problem struct {
+	// Name is the name of the failed check, e.g. "secret_key".
+	Name string
+	// Detail describes the problem and how to fix it.
+	Detail string
+}
+
+// Problems is a list of problems found by the self-check. It is returned as an
+// error when any check failed.
+type Problems []Problem
+
+func (ps Problems) Error() string {
+	details := make([]string, len(ps))
+	for i := range ps {
+		details[i] = ps[i].Name + ": " + ps[i].Detail
+	}
+	return "self-check failed: " + strings.Join(details, "; ")
+}
+
+// Run runs all checks that do not require a database connection against the
+// current configuration, and returns problems found.
+func Run() Problems {
+	var problems Problems
+	if p := checkSecretKey(conf.Security.SecretKey); p != nil {
+		problems = append(problems, *p)
+	}
+
+	dirs := []struct {
+		name string
+		path string
+	}{
+		{"repository_root", conf.Repository.Root},
+		{"app_data_path", conf.Server.AppDataPath},
+		{"log_root_path", conf.Log.RootPath},
+	}
+	for i, root := range conf.Repository.ShardRoots {
+		dirs = append(dirs, struct {
+			name string
+			path string
+		}{fmt.Sprintf("repository_shard_root_%d", i+1), root})
+	}
+	for _, dir := range dirs {
+		if p := checkDirectory(dir.name, dir.path); p != nil {
+			problems = append(problems, *p)
+		}
+	}
+
+	if p := checkHookBinary(conf.AppPath()); p != nil {
+		problems = append(problems, *p)
+	}
+	return problems
+}
+
+func checkSecretKey(key string) *Problem {
+	if strings.TrimSpace(key) != "" {
+		return nil
+	}
+	return &Problem{
+		Name:   "secret_key",
+		Detail: `The "[security] SECRET_KEY" is not set, cookies and two-factor authentication secrets can't be encrypted.`,
+	}
+}
+
+// checkDirectory checks the directory exists or can be created, and is
+// writable.
+func checkDirectory(name, path string) *Problem {
+	if path == "" {
+		return &Problem{
+			Name:   name,
+			Detail: "The path of the directory is not set.",
+		}
+	}
+
+	err := os.MkdirAll(path, os.ModePerm)
+	if err != nil {
+		return &Problem{
+			Name:   name,
+			Detail: fmt.Sprintf("The directory %q does not exist and can't be created: %v", path, err),
+		}
+	}
+
+	f, err := os.CreateTemp(path, ".selfcheck-")
+	if err != nil {
+		return &Problem{
+			Name:   name,
+			Detail: fmt.Sprintf("The directory %q is not writable: %v", path, err),
+		}
+	}
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+	return nil
+}
+
+// checkHookBinary checks the binary invoked by Git hooks exists and is
+// executable.
+func checkHookBinary(path string) *Problem {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return &Problem{
+			Name:   "hook_binary",
+			Detail: fmt.Sprintf("The binary %q invoked by Git hooks is not accessible: %v", path, err),
+		}
+	} else if fi.IsDir() {
+		return &Problem{
+			Name:   "hook_binary",
+			Detail: fmt.Sprintf("The binary %q invoked by Git hooks is a directory.", path),
+		}
+	}
+
+	// Windows has no notion of executable bits.
+	if runtime.GOOS != "windows" && fi.Mode().Perm()&0111 == 0 {
+		return &Problem{
+			Name:   "hook_binary",
+			Detail: fmt.Sprintf("The binary %q invoked by Git hooks is not executable.", path),
+		}
+	}
+	return nil
+}
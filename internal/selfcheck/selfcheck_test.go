@@ -0,0 +1,88 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package selfcheck
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblems_Error(t *testing.T) {
+	problems := Problems{
+		{Name: "secret_key", Detail: "Not set."},
+		{Name: "hook_binary", Detail: "Not executable."},
+	}
+	assert.Equal(t, "self-check failed: secret_key: Not set.; hook_binary: Not executable.", problems.Error())
+}
+
+func Test_checkSecretKey(t *testing.T) {
+	assert.Nil(t, checkSecretKey("!#@FDEWREWR&*("))
+
+	for _, key := range []string{"", "  "} {
+		p := checkSecretKey(key)
+		require.NotNil(t, p)
+		assert.Equal(t, "secret_key", p.Name)
+	}
+}
+
+func Test_checkDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("exists", func(t *testing.T) {
+		assert.Nil(t, checkDirectory("data", dir))
+	})
+
+	t.Run("created", func(t *testing.T) {
+		path := filepath.Join(dir, "a", "b")
+		assert.Nil(t, checkDirectory("data", path))
+		assert.DirExists(t, path)
+	})
+
+	t.Run("not set", func(t *testing.T) {
+		p := checkDirectory("data", "")
+		require.NotNil(t, p)
+		assert.Equal(t, "data", p.Name)
+	})
+
+	t.Run("is a file", func(t *testing.T) {
+		path := filepath.Join(dir, "file")
+		err := os.WriteFile(path, nil, 0600)
+		require.NoError(t, err)
+
+		p := checkDirectory("data", path)
+		require.NotNil(t, p)
+		assert.Contains(t, p.Detail, "can't be created")
+	})
+}
+
+func Test_checkHookBinary(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "gogs")
+	err := os.WriteFile(path, nil, 0755)
+	require.NoError(t, err)
+	assert.Nil(t, checkHookBinary(path))
+
+	p := checkHookBinary(filepath.Join(dir, "404"))
+	require.NotNil(t, p)
+	assert.Contains(t, p.Detail, "not accessible")
+
+	p = checkHookBinary(dir)
+	require.NotNil(t, p)
+	assert.Contains(t, p.Detail, "is a directory")
+
+	if runtime.GOOS != "windows" {
+		err = os.Chmod(path, 0644)
+		require.NoError(t, err)
+		p = checkHookBinary(path)
+		require.NotNil(t, p)
+		assert.Contains(t, p.Detail, "not executable")
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/unknwon/com"
 	"golang.org/x/crypto/ssh"
@@ -124,6 +125,26 @@ func handleServerConn(keyID string, chans <-chan ssh.NewChannel) {
 	}
 }
 
+// serveConn performs the handshake on the incoming connection and serves it
+// until the client disconnects.
+func serveConn(config *ssh.ServerConfig, conn net.Conn) {
+	log.Trace("SSH: Handshaking for %s", conn.RemoteAddr())
+	sConn, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		if err == io.EOF {
+			log.Warn("SSH: Handshaking was terminated: %v", err)
+		} else {
+			log.Error("SSH: Error on handshaking: %v", err)
+		}
+		return
+	}
+
+	log.Trace("SSH: Connection from %s (%s)", sConn.RemoteAddr(), sConn.ClientVersion())
+	// The incoming Request channel must be serviced.
+	go ssh.DiscardRequests(reqs)
+	go handleServerConn(sConn.Permissions.Extensions["key-id"], chans)
+}
+
 func listen(config *ssh.ServerConfig, host string, port int) {
 	listener, err := net.Listen("tcp", host+":"+com.ToStr(port))
 	if err != nil {
@@ -141,28 +162,11 @@ func listen(config *ssh.ServerConfig, host string, port int) {
 		// It must be handled in a separate goroutine,
 		// otherwise one user could easily block entire loop.
 		// For example, user could be asked to trust server key fingerprint and hangs.
-		go func() {
-			log.Trace("SSH: Handshaking for %s", conn.RemoteAddr())
-			sConn, chans, reqs, err := ssh.NewServerConn(conn, config)
-			if err != nil {
-				if err == io.EOF {
-					log.Warn("SSH: Handshaking was terminated: %v", err)
-				} else {
-					log.Error("SSH: Error on handshaking: %v", err)
-				}
-				return
-			}
-
-			log.Trace("SSH: Connection from %s (%s)", sConn.RemoteAddr(), sConn.ClientVersion())
-			// The incoming Request channel must be serviced.
-			go ssh.DiscardRequests(reqs)
-			go handleServerConn(sConn.Permissions.Extensions["key-id"], chans)
-		}()
+		go serveConn(config, conn)
 	}
 }
 
-// Listen starts a SSH server listens on given port.
-func Listen(host string, port int, ciphers, macs []string) {
+func newServerConfig(ciphers, macs []string) *ssh.ServerConfig {
 	config := &ssh.ServerConfig{
 		Config: ssh.Config{
 			Ciphers: ciphers,
@@ -199,6 +203,25 @@ func Listen(host string, port int, ciphers, macs []string) {
 		panic("SSH: Failed to parse private key: " + err.Error())
 	}
 	config.AddHostKey(private)
+	return config
+}
+
+// Listen starts a SSH server listens on given port.
+func Listen(host string, port int, ciphers, macs []string) {
+	go listen(newServerConfig(ciphers, macs), host, port)
+}
+
+var (
+	tunnelConfigOnce sync.Once
+	tunnelConfig     *ssh.ServerConfig
+)
 
-	go listen(config, host, port)
+// ServeTunnel serves the SSH connection tunneled through the web port, in the
+// same way as connections to the builtin SSH server. It blocks until the
+// handshake is done.
+func ServeTunnel(conn net.Conn) {
+	tunnelConfigOnce.Do(func() {
+		tunnelConfig = newServerConfig(conf.SSH.ServerCiphers, conf.SSH.ServerMACs)
+	})
+	serveConn(tunnelConfig, conn)
 }
@@ -28,6 +28,7 @@ import (
 	"gogs.io/gogs/internal/gitutil"
 	"gogs.io/gogs/internal/markup"
 	"gogs.io/gogs/internal/strutil"
+	"gogs.io/gogs/internal/theme"
 	"gogs.io/gogs/internal/tool"
 )
 
@@ -118,10 +119,14 @@ func FuncMap() []template.FuncMap {
 			"ThemeColorMetaTag": func() string {
 				return conf.UI.ThemeColorMetaTag
 			},
+			"ThemeStylesheets": theme.Stylesheets,
 			"FilenameIsImage": func(filename string) bool {
 				mimeType := mime.TypeByExtension(filepath.Ext(filename))
 				return strings.HasPrefix(mimeType, "image/")
 			},
+			"FilenameIsPDF": func(filename string) bool {
+				return mime.TypeByExtension(filepath.Ext(filename)) == "application/pdf"
+			},
 			"TabSizeClass": func(ec *editorconfig.Editorconfig, filename string) string {
 				if ec != nil {
 					def, err := ec.GetDefinitionForFilename(filename)
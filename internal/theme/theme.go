@@ -0,0 +1,143 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package theme
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gogs.io/gogs/internal/conf"
+	"gogs.io/gogs/internal/osutil"
+)
+
+// Builtin themes.
+const (
+	Light = "light"
+	Dark  = "dark"
+	// Auto follows the color scheme of the system.
+	Auto = "auto"
+)
+
+// IsBuiltin returns true if the theme is a builtin theme.
+func IsBuiltin(name string) bool {
+	return name == Light || name == Dark || name == Auto
+}
+
+// packsDir returns the directory of theme packs.
+func packsDir() string {
+	return filepath.Join(conf.CustomDir(), "themes")
+}
+
+// isPack returns true if a theme pack with given name exists in the root
+// directory. A theme pack must have a "public/theme.css" file.
+func isPack(root, name string) bool {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return false
+	}
+	return osutil.IsFile(filepath.Join(root, name, "public", "theme.css"))
+}
+
+// List returns names of all available themes, builtin themes come first and
+// then theme packs in alphabetical order.
+func List() []string {
+	return list(packsDir())
+}
+
+func list(root string) []string {
+	names := []string{Light, Dark, Auto}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return names
+	}
+	for _, e := range entries {
+		if e.IsDir() && !IsBuiltin(e.Name()) && isPack(root, e.Name()) {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+// IsValid returns true if the theme is either builtin or an existing theme
+// pack.
+func IsValid(name string) bool {
+	return IsBuiltin(name) || isPack(packsDir(), name)
+}
+
+// Resolve returns the theme to be used for the preferred theme of a user. It
+// falls back to the default theme of the instance when users are not allowed
+// to choose their own themes or the preferred theme is not available.
+func Resolve(preferred string) string {
+	if conf.UI.AllowUserThemes && preferred != "" && IsValid(preferred) {
+		return preferred
+	}
+	if IsValid(conf.UI.DefaultTheme) {
+		return conf.UI.DefaultTheme
+	}
+	return Light
+}
+
+// Stylesheet is a stylesheet to be loaded in addition to the base stylesheets.
+type Stylesheet struct {
+	URL string
+	// Media is the media query that the stylesheet applies to, empty for all.
+	Media string
+}
+
+// Stylesheets returns stylesheets to be loaded for the theme.
+func Stylesheets(name string) []Stylesheet {
+	return stylesheets(packsDir(), name)
+}
+
+func stylesheets(root, name string) []Stylesheet {
+	darkURL := conf.Server.Subpath + "/css/theme-dark.css?v=" + conf.BuildCommit
+	switch name {
+	case Light:
+		return nil
+	case Dark:
+		return []Stylesheet{{URL: darkURL}}
+	case Auto:
+		return []Stylesheet{{URL: darkURL, Media: "(prefers-color-scheme: dark)"}}
+	}
+
+	if !isPack(root, name) {
+		return nil
+	}
+	fi, err := os.Stat(filepath.Join(root, name, "public", "theme.css"))
+	if err != nil {
+		return nil
+	}
+	// The modification time is used as the version to make browsers fetch the
+	// stylesheet again whenever it is changed.
+	return []Stylesheet{{
+		URL: fmt.Sprintf("%s/-/themes/%s/theme.css?v=%x", conf.Server.Subpath, name, fi.ModTime().Unix()),
+	}}
+}
+
+// TemplatesDir returns the directory of templates of the theme pack. It
+// returns an empty string when the theme is not a theme pack.
+func TemplatesDir(name string) string {
+	root := packsDir()
+	if IsBuiltin(name) || !isPack(root, name) {
+		return ""
+	}
+	return filepath.Join(root, name, "templates")
+}
+
+// AssetPath returns the path on disk of the asset of the theme pack, and
+// whether the asset exists.
+func AssetPath(name, asset string) (string, bool) {
+	return assetPath(packsDir(), name, asset)
+}
+
+func assetPath(root, name, asset string) (string, bool) {
+	if IsBuiltin(name) || !isPack(root, name) {
+		return "", false
+	}
+	p := filepath.Join(root, name, "public", filepath.FromSlash(path.Clean("/"+asset)))
+	return p, osutil.IsFile(p)
+}
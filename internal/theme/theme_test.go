@@ -0,0 +1,65 @@
+// Copyright 2026 The Gogs Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPacks(t *testing.T) string {
+	root := t.TempDir()
+	for _, name := range []string{"solarized", "dark"} {
+		dir := filepath.Join(root, name, "public")
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "theme.css"), []byte("body {}"), 0644))
+	}
+	// Directories without stylesheets are not theme packs.
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "incomplete", "templates"), 0755))
+	return root
+}
+
+func TestList(t *testing.T) {
+	root := newTestPacks(t)
+	assert.Equal(t, []string{Light, Dark, Auto, "solarized"}, list(root))
+	assert.Equal(t, []string{Light, Dark, Auto}, list(filepath.Join(root, "404")))
+}
+
+func TestStylesheets(t *testing.T) {
+	root := newTestPacks(t)
+	modTime := time.Unix(1588568886, 0)
+	require.NoError(t, os.Chtimes(filepath.Join(root, "solarized", "public", "theme.css"), modTime, modTime))
+
+	assert.Empty(t, stylesheets(root, Light))
+	assert.Equal(t, []Stylesheet{{URL: "/css/theme-dark.css?v="}}, stylesheets(root, Dark))
+	assert.Equal(t,
+		[]Stylesheet{{URL: "/css/theme-dark.css?v=", Media: "(prefers-color-scheme: dark)"}},
+		stylesheets(root, Auto),
+	)
+	assert.Equal(t, []Stylesheet{{URL: "/-/themes/solarized/theme.css?v=5eafa336"}}, stylesheets(root, "solarized"))
+	assert.Empty(t, stylesheets(root, "incomplete"))
+	assert.Empty(t, stylesheets(root, "../solarized"))
+}
+
+func TestAssetPath(t *testing.T) {
+	root := newTestPacks(t)
+
+	p, ok := assetPath(root, "solarized", "theme.css")
+	assert.True(t, ok)
+	assert.Equal(t, filepath.Join(root, "solarized", "public", "theme.css"), p)
+
+	// Paths cannot escape the public directory.
+	_, ok = assetPath(root, "solarized", "../../dark/public/theme.css")
+	assert.False(t, ok)
+
+	// Builtin themes are not served from theme packs.
+	_, ok = assetPath(root, "dark", "theme.css")
+	assert.False(t, ok)
+}
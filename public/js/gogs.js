@@ -401,6 +401,46 @@ function initRepository() {
     });
   }
 
+  // Suggest duplicates of new issue
+  var $issueDuplicates = $("#issue-duplicates");
+  if ($issueDuplicates.length > 0) {
+    var duplicatesTimer;
+    var lastTitle = "";
+    $("#issue_title").on("input", function() {
+      var title = $.trim($(this).val());
+      clearTimeout(duplicatesTimer);
+      duplicatesTimer = setTimeout(function() {
+        if (title === lastTitle) {
+          return;
+        }
+        lastTitle = title;
+        if (title.length < 3) {
+          $issueDuplicates.addClass("hide");
+          return;
+        }
+
+        $.getJSON($issueDuplicates.data("url"), { title: title }, function(issues) {
+          var $list = $issueDuplicates.find(".list").empty();
+          if (issues.length === 0) {
+            $issueDuplicates.addClass("hide");
+            return;
+          }
+          $.each(issues, function(_, issue) {
+            var $state = $("<span class='ui mini basic label'>")
+              .addClass(issue.is_closed ? "red" : "green")
+              .text($issueDuplicates.data(issue.is_closed ? "closed" : "open"));
+            $("<div class='item'>")
+              .append($state)
+              .append(" ")
+              .append($("<a target='_blank'>").attr("href", issue.url).text("#" + issue.index + " " + issue.title))
+              .appendTo($list);
+          });
+          $issueDuplicates.removeClass("hide");
+        });
+      }, 400);
+    });
+  }
+
   // Milestones
   if ($(".repository.milestones").length > 0) {
   }
@@ -472,6 +512,9 @@ function initRepository() {
       var $editContentZone = $segment.find(".edit-content-zone");
       var $renderContent = $segment.find(".render-content");
       var $rawContent = $segment.find(".raw-content");
+      var $deleteAttachments = $segment
+        .next(".attachments")
+        .find(".delete-attachment");
       var $textarea;
 
       // Setup new form
@@ -502,30 +545,64 @@ function initRepository() {
         $editContentZone.find(".cancel.button").click(function() {
           $renderContent.show();
           $editContentZone.hide();
+          $deleteAttachments.addClass("hide");
         });
         $editContentZone.find(".save.button").click(function() {
           $renderContent.show();
           $editContentZone.hide();
+          $deleteAttachments.addClass("hide");
+
+          var renderContent = function(content) {
+            if (content.length == 0) {
+              $renderContent.html($("#no-content").html());
+            } else {
+              $renderContent.html(content);
+              emojify.run($renderContent[0]);
+              $("pre code", $renderContent[0]).each(function(i, block) {
+                hljs.highlightBlock(block);
+              });
+            }
+          };
 
-          $.post(
-            $editContentZone.data("update-url"),
-            {
-              _csrf: csrf,
-              content: $textarea.val(),
-              context: $editContentZone.data("context")
-            },
-            function(data) {
-              if (data.length == 0) {
-                $renderContent.html($("#no-content").html());
-              } else {
-                $renderContent.html(data.content);
-                emojify.run($renderContent[0]);
-                $("pre code", $renderContent[0]).each(function(i, block) {
-                  hljs.highlightBlock(block);
-                });
+          var content = $textarea.val();
+          $.post($editContentZone.data("update-url"), {
+            _csrf: csrf,
+            content: content,
+            context: $editContentZone.data("context"),
+            revision: $rawContent.data("revision"),
+            original: $rawContent.text()
+          })
+            .done(function(data) {
+              renderContent(data.content || "");
+              if (data.revision) {
+                $rawContent.text(content);
+                $rawContent.data("revision", data.revision);
               }
-            }
-          );
+            })
+            .fail(function(xhr) {
+              // The content has been changed by others since the editing began,
+              // reopen the editor with the merged content to be saved again.
+              if (xhr.status != 409 || !xhr.responseJSON) {
+                return;
+              }
+              var data = xhr.responseJSON;
+              renderContent(data.rendered);
+              $rawContent.text(data.content);
+              $rawContent.data("revision", data.revision);
+              if (data.merged !== undefined) {
+                $textarea.val(data.merged);
+              }
+
+              $renderContent.hide();
+              $editContentZone.show();
+              $deleteAttachments.removeClass("hide");
+              $textarea.focus();
+              alert(
+                data.clean
+                  ? $("#edit-content-form").data("conflict-clean")
+                  : $("#edit-content-form").data("conflict")
+              );
+            });
         });
       } else {
         $textarea = $segment.find("textarea");
@@ -534,6 +611,7 @@ function initRepository() {
       // Show write/preview tab and copy raw content as needed
       $editContentZone.show();
       $renderContent.hide();
+      $deleteAttachments.removeClass("hide");
       if ($textarea.val().length == 0) {
         $textarea.val($rawContent.text());
       }
@@ -554,6 +632,26 @@ function initRepository() {
       return false;
     });
 
+    // Delete attachment
+    $(".delete-attachment").click(function() {
+      var $this = $(this);
+      if (confirm($this.data("locale"))) {
+        $.post($this.data("url"), {
+          _csrf: csrf
+        }).done(function() {
+          var $attachments = $this.closest(".attachments");
+          $this.closest(".attachment").remove();
+          $attachments
+            .find('.attachment-preview[data-uuid="' + $this.data("uuid") + '"]')
+            .remove();
+          if ($attachments.find(".attachment").length == 0) {
+            $attachments.remove();
+          }
+        });
+      }
+      return false;
+    });
+
     // Change status
     var $statusButton = $("#status-button");
     $("#comment-form .edit_area").keyup(function() {
@@ -1133,7 +1231,7 @@ function initAdmin() {
           break;
       }
     });
-    $("#delete-selection").click(function() {
+    $("#delete-selection, #ack-selection").click(function() {
       var $this = $(this);
       $this.addClass("loading disabled");
       var ids = [];
@@ -1411,6 +1509,75 @@ function initWebhookSettings() {
   });
 }
 
+function initSnippetForm() {
+  $("#snippet-add-file").click(function() {
+    var $files = $("#snippet-files");
+    var $file = $files.children(".snippet-file").last().clone();
+    $file.find("input, textarea").val("");
+    $files.append($file);
+  });
+}
+
+function initLiveEvents() {
+  var url = $("meta[name=_events]").attr("content");
+  if (!url || typeof EventSource === "undefined") {
+    return;
+  }
+  var source = new EventSource(url);
+
+  // Notification bell, the count is kept until the bell is clicked.
+  var $bell = $(".notification-bell");
+  var showNotifications = function() {
+    var count = parseInt(sessionStorage.getItem("gogs.notifications.count") || "0", 10);
+    var $count = $bell.find(".notification-count");
+    if (count > 0) {
+      $count.text(count).removeClass("hide");
+      $bell.attr("href", sessionStorage.getItem("gogs.notifications.url"));
+    } else {
+      $count.addClass("hide");
+    }
+  };
+  showNotifications();
+  $bell.click(function() {
+    sessionStorage.removeItem("gogs.notifications.count");
+    sessionStorage.removeItem("gogs.notifications.url");
+  });
+  source.addEventListener("notification", function(e) {
+    var data = JSON.parse(e.data);
+    var count = parseInt(sessionStorage.getItem("gogs.notifications.count") || "0", 10);
+    sessionStorage.setItem("gogs.notifications.count", count + 1);
+    sessionStorage.setItem("gogs.notifications.url", data.url);
+    showNotifications();
+  });
+
+  // Issue and pull request pages reload on new activity of others, unless
+  // the user is in the middle of writing something.
+  var $message = $(".live-update-message");
+  if ($message.length === 0) {
+    return;
+  }
+  var onIssueEvent = function(e) {
+    var data = JSON.parse(e.data);
+    if (data.issue_index !== $message.data("issue-index") || data.actor_id === $message.data("user-id")) {
+      return;
+    }
+
+    var writing = $(".edit-content-zone:visible").length > 0;
+    $(".comment.form textarea").each(function() {
+      if ($(this).val() !== "") {
+        writing = true;
+      }
+    });
+    if (writing) {
+      $message.removeClass("hide");
+    } else {
+      window.location.reload();
+    }
+  };
+  source.addEventListener("issue.comment", onIssueEvent);
+  source.addEventListener("issue.edited", onIssueEvent);
+}
+
 $(document).ready(function() {
   csrf = $("meta[name=_csrf]").attr("content");
   suburl = $("meta[name=_suburl]").attr("content");
@@ -1675,6 +1842,8 @@ $(document).ready(function() {
   initOrganization();
   initAdmin();
   initCodeView();
+  initSnippetForm();
+  initLiveEvents();
 
   // Repo clone url.
   if ($("#repo-clone-url").length > 0) {
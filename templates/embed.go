@@ -56,8 +56,9 @@ func mustNames(fsys fs.FS) []string {
 
 // NewTemplateFileSystem returns a macaron.TemplateFileSystem instance for embedded assets.
 // The argument "dir" can be used to serve subset of embedded assets. Template file
-// found under the "customDir" on disk has higher precedence over embedded assets.
-func NewTemplateFileSystem(dir, customDir string) macaron.TemplateFileSystem {
+// found under the "customDirs" on disk has higher precedence over embedded assets,
+// and later ones have higher precedence over previous ones.
+func NewTemplateFileSystem(dir string, customDirs ...string) macaron.TemplateFileSystem {
 	if dir != "" && !strings.HasSuffix(dir, "/") {
 		dir += "/"
 	}
@@ -71,10 +72,14 @@ func NewTemplateFileSystem(dir, customDir string) macaron.TemplateFileSystem {
 		}
 		// Check if corresponding custom file exists
 		var data []byte
-		fpath := path.Join(customDir, name)
-		if osutil.IsFile(fpath) {
-			data, err = ioutil.ReadFile(fpath)
-		} else {
+		for i := len(customDirs) - 1; i >= 0; i-- {
+			fpath := path.Join(customDirs[i], name)
+			if osutil.IsFile(fpath) {
+				data, err = ioutil.ReadFile(fpath)
+				break
+			}
+		}
+		if data == nil && err == nil {
 			data, err = files.ReadFile(name)
 		}
 		if err != nil {
@@ -87,3 +92,28 @@ func NewTemplateFileSystem(dir, customDir string) macaron.TemplateFileSystem {
 	}
 	return &fileSystem{files: tmplFiles}
 }
+
+// reloadingFileSystem implements the macaron.TemplateFileSystem interface, and
+// reads custom template files from disk again on every listing.
+type reloadingFileSystem struct {
+	dir        string
+	customDirs []string
+}
+
+func (fs *reloadingFileSystem) ListFiles() []macaron.TemplateFile {
+	return NewTemplateFileSystem(fs.dir, fs.customDirs...).ListFiles()
+}
+
+func (fs *reloadingFileSystem) Get(name string) (io.Reader, error) {
+	return NewTemplateFileSystem(fs.dir, fs.customDirs...).Get(name)
+}
+
+// NewReloadingTemplateFileSystem is like NewTemplateFileSystem but changes to
+// custom template files take effect without restarting, as long as templates are
+// compiled again, e.g. on every rendering in development mode.
+func NewReloadingTemplateFileSystem(dir string, customDirs ...string) macaron.TemplateFileSystem {
+	return &reloadingFileSystem{
+		dir:        dir,
+		customDirs: customDirs,
+	}
+}